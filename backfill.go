@@ -0,0 +1,658 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// Backfill data types
+const (
+	BackfillTrades  = "trades"
+	BackfillCandles = "candles"
+)
+
+// Backfill job statuses
+const (
+	BackfillRunning   = "running"
+	BackfillComplete  = "complete"
+	BackfillCancelled = "cancelled"
+	BackfillFailed    = "failed"
+)
+
+const (
+	backfillDir        = "backfill"
+	backfillMaxRetries = 3
+)
+
+// backfillRetryDelay is the delay before retrying a rate limited batch
+var backfillRetryDelay = time.Second * 5
+
+// Backfill errors
+var (
+	ErrBackfillJobNotFound = errors.New("backfill job not found")
+	ErrBackfillJobRunning  = errors.New("backfill job already running")
+)
+
+var (
+	backfillJobs  = make(map[string]*BackfillJob)
+	backfillJobID int
+	backfillMtx   sync.Mutex
+)
+
+// BackfillRequest describes the exchange history to backfill
+type BackfillRequest struct {
+	Exchange  string    `json:"exchange"`
+	Currency  string    `json:"currency"`
+	AssetType string    `json:"assetType"`
+	DataType  string    `json:"dataType"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	// Interval is the candle granularity, for example 1m or 1h
+	Interval string `json:"interval,omitempty"`
+}
+
+// validate checks the request, setting the default asset type, and returns
+// the candle interval
+func (b *BackfillRequest) validate() (time.Duration, error) {
+	if b.Exchange == "" {
+		return 0, errors.New("exchange not set")
+	}
+	if len(b.Currency) < 3 {
+		return 0, fmt.Errorf("invalid currency pair %q", b.Currency)
+	}
+	if b.AssetType == "" {
+		b.AssetType = ticker.Spot
+	}
+	if b.Start.IsZero() || !b.Start.Before(b.End) {
+		return 0, errors.New("start must be set and before end")
+	}
+
+	switch b.DataType {
+	case BackfillTrades:
+		return 0, nil
+	case BackfillCandles:
+		interval, err := time.ParseDuration(b.Interval)
+		if err != nil || interval <= 0 {
+			return 0, fmt.Errorf("invalid candle interval %q", b.Interval)
+		}
+		return interval, nil
+	default:
+		return 0, fmt.Errorf("invalid data type %q, expected %s or %s",
+			b.DataType, BackfillTrades, BackfillCandles)
+	}
+}
+
+// name returns the file name the request is stored under, without extension
+func (b *BackfillRequest) name() string {
+	var currency []rune
+	for _, r := range common.StringToUpper(b.Currency) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			currency = append(currency, r)
+		}
+	}
+	name := fmt.Sprintf("%s_%s_%s", string(currency),
+		common.StringToLower(b.AssetType), b.DataType)
+	if b.DataType == BackfillCandles {
+		name += "_" + b.Interval
+	}
+	return name
+}
+
+// BackfillCursor is the position of the last stored record. The trade ID
+// separates trades sharing a timestamp
+type BackfillCursor struct {
+	Time time.Time `json:"time"`
+	TID  int64     `json:"tid,omitempty"`
+}
+
+// after returns whether a record is newer than the cursor
+func (c *BackfillCursor) after(t time.Time, tid int64) bool {
+	return t.After(c.Time) || (t.Equal(c.Time) && tid > c.TID)
+}
+
+// BackfillProgress reports the progress of a backfill
+type BackfillProgress struct {
+	Batches  int            `json:"batches"`
+	Records  int            `json:"records"`
+	Last     BackfillCursor `json:"last"`
+	Complete bool           `json:"complete"`
+}
+
+// BackfillWriter stores backfilled exchange history. Records are written in
+// ascending time order
+type BackfillWriter interface {
+	// Last returns the cursor of the last stored record, ok is false if
+	// nothing has been stored
+	Last() (cursor BackfillCursor, ok bool, err error)
+	WriteTrades(trades []exchange.TradeHistory) error
+	WriteCandles(candles []exchange.Candle) error
+	// Commit flushes the written records and records the progress
+	Commit(progress BackfillProgress) error
+	Close() error
+}
+
+// Backfill fetches the requested exchange history in batches and writes it to
+// the writer. It starts from the last stored record if that is later than the
+// request start, so an interrupted backfill continues where it stopped. The
+// progress callback is called after each batch and the backfill stops when
+// the context is cancelled
+func Backfill(ctx context.Context, exch exchange.IBotExchange, req *BackfillRequest, w BackfillWriter, progress func(BackfillProgress)) (BackfillProgress, error) {
+	var status BackfillProgress
+	interval, err := req.validate()
+	if err != nil {
+		return status, err
+	}
+
+	cursor, ok, err := w.Last()
+	if err != nil {
+		return status, err
+	}
+
+	start := req.Start
+	if ok && !cursor.Time.Before(req.Start) {
+		status.Last = cursor
+		// Trades are requested from the last stored trade as the following
+		// trades may share its timestamp
+		start = cursor.Time
+		if req.DataType == BackfillCandles {
+			start = cursor.Time.Add(interval)
+		}
+	} else {
+		cursor = BackfillCursor{Time: req.Start.Add(-time.Nanosecond)}
+	}
+
+	step := time.Second
+	if req.DataType == BackfillCandles {
+		step = interval
+	}
+
+	p := pair.NewCurrencyPairFromString(req.Currency)
+	for start.Before(req.End) {
+		if ctx.Err() != nil {
+			return status, ctx.Err()
+		}
+
+		var newest time.Time
+		var written int
+		newest, written, cursor, err = fetchBackfillBatch(ctx, exch, p, req,
+			interval, start, cursor, w)
+		if err != nil {
+			return status, err
+		}
+		if newest.IsZero() {
+			break
+		}
+
+		status.Batches++
+		status.Records += written
+		if written > 0 {
+			status.Last = cursor
+		}
+		err = w.Commit(status)
+		if err != nil {
+			return status, err
+		}
+		if progress != nil {
+			progress(status)
+		}
+		if !newest.Before(req.End) {
+			break
+		}
+
+		switch {
+		case written > 0 && req.DataType == BackfillCandles:
+			start = cursor.Time.Add(interval)
+		case written > 0:
+			start = cursor.Time
+		default:
+			// The batch had nothing newer than the last stored record, skip
+			// ahead so the same batch isn't requested forever
+			start = start.Add(step)
+		}
+	}
+
+	status.Complete = true
+	return status, w.Commit(status)
+}
+
+// fetchBackfillBatch fetches a batch of history from the start time, retrying
+// rate limited requests, and writes the records newer than the cursor and
+// before the request end. It returns the time of the newest record fetched,
+// which is zero if the batch was empty, the number of records written and the
+// cursor of the last written record
+func fetchBackfillBatch(ctx context.Context, exch exchange.IBotExchange, p pair.CurrencyPair, req *BackfillRequest, interval time.Duration, start time.Time, cursor BackfillCursor, w BackfillWriter) (time.Time, int, BackfillCursor, error) {
+	var trades []exchange.TradeHistory
+	var candles []exchange.Candle
+	var err error
+	for retry := 0; ; retry++ {
+		if req.DataType == BackfillCandles {
+			candles, err = exch.GetHistoricCandles(p, req.AssetType, start, interval)
+		} else {
+			trades, err = exch.GetExchangeHistory(p, req.AssetType, start)
+		}
+		if err == nil || !errors.Is(err, exchange.ErrRateLimited) ||
+			retry == backfillMaxRetries {
+			break
+		}
+
+		log.Warnf("%s backfill rate limited, retrying in %v.", exch.GetName(),
+			backfillRetryDelay)
+		t := time.NewTimer(backfillRetryDelay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return time.Time{}, 0, cursor, ctx.Err()
+		case <-t.C:
+		}
+	}
+	if err != nil {
+		return time.Time{}, 0, cursor, err
+	}
+
+	if req.DataType == BackfillCandles {
+		var batch []exchange.Candle
+		var newest time.Time
+		for x := range candles {
+			if candles[x].Time.After(newest) {
+				newest = candles[x].Time
+			}
+			if cursor.after(candles[x].Time, 0) && candles[x].Time.Before(req.End) {
+				batch = append(batch, candles[x])
+			}
+		}
+		if len(batch) > 0 {
+			cursor = BackfillCursor{Time: batch[len(batch)-1].Time}
+		}
+		return newest, len(batch), cursor, w.WriteCandles(batch)
+	}
+
+	var batch []exchange.TradeHistory
+	var newest time.Time
+	for x := range trades {
+		t := common.UnixTimestampToTime(trades[x].Timestamp)
+		if t.After(newest) {
+			newest = t
+		}
+		if cursor.after(t, trades[x].TID) && t.Before(req.End) {
+			batch = append(batch, trades[x])
+		}
+	}
+	if len(batch) > 0 {
+		last := batch[len(batch)-1]
+		cursor = BackfillCursor{
+			Time: common.UnixTimestampToTime(last.Timestamp),
+			TID:  last.TID,
+		}
+	}
+	return newest, len(batch), cursor, w.WriteTrades(batch)
+}
+
+// backfillManifest describes the history stored in a backfill CSV file
+type backfillManifest struct {
+	Request  BackfillRequest `json:"request"`
+	File     string          `json:"file"`
+	Columns  []string        `json:"columns"`
+	Records  int             `json:"records"`
+	Last     BackfillCursor  `json:"last"`
+	Complete bool            `json:"complete"`
+	Updated  time.Time       `json:"updated"`
+}
+
+// csvBackfillWriter stores backfilled history in a CSV file with a JSON
+// manifest alongside it
+type csvBackfillWriter struct {
+	request      BackfillRequest
+	path         string
+	manifestPath string
+	file         *os.File
+	writer       *csv.Writer
+	records      int
+	last         BackfillCursor
+}
+
+// backfillColumns returns the CSV columns for a data type
+func backfillColumns(dataType string) []string {
+	if dataType == BackfillCandles {
+		return []string{"timestamp", "open", "high", "low", "close", "volume"}
+	}
+	return []string{"timestamp", "tid", "price", "amount", "type"}
+}
+
+// newCSVBackfillWriter opens the CSV file for the request under the supplied
+// directory, reading the last stored record so the backfill can be resumed
+func newCSVBackfillWriter(dir string, req *BackfillRequest) (*csvBackfillWriter, error) {
+	_, err := req.validate()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, common.StringToLower(req.Exchange))
+	err = common.CheckDir(dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.name()
+	w := &csvBackfillWriter{
+		request:      *req,
+		path:         filepath.Join(dir, name+".csv"),
+		manifestPath: filepath.Join(dir, name+".json"),
+	}
+
+	w.file, err = os.OpenFile(w.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = w.load()
+	if err != nil {
+		w.file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// load counts the stored records and reads the last one, writing the header if
+// the file is empty. A partially written final line left by an interrupted
+// backfill is truncated
+func (w *csvBackfillWriter) load() error {
+	r := bufio.NewReader(w.file)
+	var offset int64
+	var lines int
+	var lastLine string
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+		lines++
+		lastLine = line
+	}
+
+	err := w.file.Truncate(offset)
+	if err != nil {
+		return err
+	}
+	_, err = w.file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	w.writer = csv.NewWriter(w.file)
+	if lines == 0 {
+		return w.writer.Write(backfillColumns(w.request.DataType))
+	}
+	if lines == 1 {
+		return nil
+	}
+
+	w.records = lines - 1
+	fields, err := csv.NewReader(strings.NewReader(lastLine)).Read()
+	if err != nil {
+		return err
+	}
+	ts, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp in %s: %s", w.path, err)
+	}
+	w.last = BackfillCursor{Time: common.UnixTimestampToTime(ts)}
+	if w.request.DataType == BackfillTrades && len(fields) > 1 {
+		w.last.TID, err = strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid trade ID in %s: %s", w.path, err)
+		}
+	}
+	return nil
+}
+
+// Last returns the cursor of the last stored record
+func (w *csvBackfillWriter) Last() (BackfillCursor, bool, error) {
+	return w.last, w.records > 0, nil
+}
+
+// WriteTrades writes trades to the CSV file
+func (w *csvBackfillWriter) WriteTrades(trades []exchange.TradeHistory) error {
+	for x := range trades {
+		err := w.writer.Write([]string{
+			strconv.FormatInt(trades[x].Timestamp, 10),
+			strconv.FormatInt(trades[x].TID, 10),
+			strconv.FormatFloat(trades[x].Price, 'f', -1, 64),
+			strconv.FormatFloat(trades[x].Amount, 'f', -1, 64),
+			trades[x].Type,
+		})
+		if err != nil {
+			return err
+		}
+		w.records++
+		w.last = BackfillCursor{
+			Time: common.UnixTimestampToTime(trades[x].Timestamp),
+			TID:  trades[x].TID,
+		}
+	}
+	return nil
+}
+
+// WriteCandles writes candles to the CSV file
+func (w *csvBackfillWriter) WriteCandles(candles []exchange.Candle) error {
+	for x := range candles {
+		err := w.writer.Write([]string{
+			strconv.FormatInt(candles[x].Time.Unix(), 10),
+			strconv.FormatFloat(candles[x].Open, 'f', -1, 64),
+			strconv.FormatFloat(candles[x].High, 'f', -1, 64),
+			strconv.FormatFloat(candles[x].Low, 'f', -1, 64),
+			strconv.FormatFloat(candles[x].Close, 'f', -1, 64),
+			strconv.FormatFloat(candles[x].Volume, 'f', -1, 64),
+		})
+		if err != nil {
+			return err
+		}
+		w.records++
+		w.last = BackfillCursor{Time: candles[x].Time}
+	}
+	return nil
+}
+
+// Commit flushes the CSV file and then updates the manifest, so the manifest
+// never describes records which haven't been stored
+func (w *csvBackfillWriter) Commit(progress BackfillProgress) error {
+	w.writer.Flush()
+	err := w.writer.Error()
+	if err != nil {
+		return err
+	}
+	err = w.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(backfillManifest{
+		Request:  w.request,
+		File:     filepath.Base(w.path),
+		Columns:  backfillColumns(w.request.DataType),
+		Records:  w.records,
+		Last:     w.last,
+		Complete: progress.Complete,
+		Updated:  time.Now(),
+	}, "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(w.manifestPath, data)
+}
+
+// Close flushes and closes the CSV file
+func (w *csvBackfillWriter) Close() error {
+	w.writer.Flush()
+	err := w.writer.Error()
+	if err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// BackfillJob is a backfill running in the background
+type BackfillJob struct {
+	ID       string           `json:"id"`
+	Request  BackfillRequest  `json:"request"`
+	Status   string           `json:"status"`
+	Progress BackfillProgress `json:"progress"`
+	Error    string           `json:"error,omitempty"`
+	Started  time.Time        `json:"started"`
+	Finished time.Time        `json:"finished,omitempty"`
+
+	path   string
+	cancel context.CancelFunc
+}
+
+// StartBackfill starts a backfill job writing CSV files to the data directory.
+// Only one job may run at a time for each output file
+func StartBackfill(req BackfillRequest) (BackfillJob, error) {
+	_, err := req.validate()
+	if err != nil {
+		return BackfillJob{}, err
+	}
+
+	exch := GetExchangeByName(req.Exchange)
+	if exch == nil {
+		return BackfillJob{}, ErrExchangeNotFound
+	}
+	if !exch.SupportsAsset(req.AssetType) {
+		return BackfillJob{}, fmt.Errorf("%s asset type %s not supported",
+			exch.GetName(), req.AssetType)
+	}
+	req.Exchange = exch.GetName()
+
+	path := filepath.Join(common.StringToLower(req.Exchange), req.name())
+	backfillMtx.Lock()
+	defer backfillMtx.Unlock()
+	for _, job := range backfillJobs {
+		if job.path == path && job.Status == BackfillRunning {
+			return BackfillJob{}, ErrBackfillJobRunning
+		}
+	}
+
+	w, err := newCSVBackfillWriter(filepath.Join(bot.settings.DataDir, backfillDir), &req)
+	if err != nil {
+		return BackfillJob{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	backfillJobID++
+	job := &BackfillJob{
+		ID:      strconv.Itoa(backfillJobID),
+		Request: req,
+		Status:  BackfillRunning,
+		Started: time.Now(),
+		path:    path,
+		cancel:  cancel,
+	}
+	backfillJobs[job.ID] = job
+
+	wg.Add(1)
+	go runBackfillJob(ctx, job, exch, w)
+	return *job, nil
+}
+
+// runBackfillJob runs a backfill job until it finishes, is cancelled or the bot
+// shuts down
+func runBackfillJob(ctx context.Context, job *BackfillJob, exch exchange.IBotExchange, w BackfillWriter) {
+	defer wg.Done()
+	defer job.cancel()
+
+	go func() {
+		select {
+		case <-shutdowner:
+			job.cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	log.Debugf("Backfill job %s started for %s %s %s %s.", job.ID,
+		job.Request.Exchange, job.Request.Currency, job.Request.AssetType,
+		job.Request.DataType)
+	progress, err := Backfill(ctx, exch, &job.Request, w, func(p BackfillProgress) {
+		backfillMtx.Lock()
+		job.Progress = p
+		backfillMtx.Unlock()
+	})
+	closeErr := w.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	backfillMtx.Lock()
+	defer backfillMtx.Unlock()
+	job.Progress = progress
+	job.Finished = time.Now()
+	switch {
+	case err == nil:
+		job.Status = BackfillComplete
+	case errors.Is(err, context.Canceled):
+		job.Status = BackfillCancelled
+	default:
+		job.Status = BackfillFailed
+		job.Error = err.Error()
+	}
+	log.Debugf("Backfill job %s %s, %d records written.", job.ID, job.Status,
+		progress.Records)
+}
+
+// GetBackfillJobs returns the backfill jobs started since the bot started
+func GetBackfillJobs() []BackfillJob {
+	backfillMtx.Lock()
+	defer backfillMtx.Unlock()
+	var jobs []BackfillJob
+	for _, job := range backfillJobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Started.Before(jobs[j].Started)
+	})
+	return jobs
+}
+
+// GetBackfillJob returns a backfill job by ID
+func GetBackfillJob(id string) (BackfillJob, error) {
+	backfillMtx.Lock()
+	defer backfillMtx.Unlock()
+	job, ok := backfillJobs[id]
+	if !ok {
+		return BackfillJob{}, ErrBackfillJobNotFound
+	}
+	return *job, nil
+}
+
+// CancelBackfill cancels a running backfill job. The stored history is kept
+// so the backfill can be resumed by starting it again
+func CancelBackfill(id string) error {
+	backfillMtx.Lock()
+	defer backfillMtx.Unlock()
+	job, ok := backfillJobs[id]
+	if !ok {
+		return ErrBackfillJobNotFound
+	}
+	job.cancel()
+	return nil
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// newHistoryExchange returns a test exchange serving batches of at most
+// batchSize records from its trades and candles
+func newHistoryExchange(trades []exchange.TradeHistory, candles []exchange.Candle, batchSize int) *testExchange {
+	exch := newTestExchange("Mock")
+	exch.getExchangeHistory = func(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
+		var resp []exchange.TradeHistory
+		for x := range trades {
+			if trades[x].Timestamp >= timestampStart.Unix() && len(resp) < batchSize {
+				resp = append(resp, trades[x])
+			}
+		}
+		return resp, nil
+	}
+	exch.getHistoricCandles = func(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]exchange.Candle, error) {
+		var resp []exchange.Candle
+		for x := range candles {
+			if !candles[x].Time.Before(timestampStart) && len(resp) < batchSize {
+				resp = append(resp, candles[x])
+			}
+		}
+		return resp, nil
+	}
+	return exch
+}
+
+func readBackfillCSV(t *testing.T, dir string, req *BackfillRequest) []string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "mock", req.name()+".csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestBackfillTrades(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gctbackfill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(d time.Duration) { backfillRetryDelay = d }(backfillRetryDelay)
+	backfillRetryDelay = time.Millisecond
+
+	// Trades 3 and 4 share a timestamp across a batch boundary and the last
+	// trade is after the end
+	var trades []exchange.TradeHistory
+	for x, ts := range []int64{1000, 1010, 1020, 1020, 1030, 1040, 1050, 2000} {
+		trades = append(trades, exchange.TradeHistory{
+			Timestamp: ts,
+			TID:       int64(x + 1),
+			Price:     6500,
+			Amount:    0.5,
+			Type:      "buy",
+		})
+	}
+	exch := newHistoryExchange(trades, nil, 2)
+	// The first request is rate limited
+	getExchangeHistory, rateLimited := exch.getExchangeHistory, 1
+	exch.getExchangeHistory = func(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
+		if rateLimited > 0 {
+			rateLimited--
+			return nil, exchange.ErrRateLimited
+		}
+		return getExchangeHistory(p, assetType, timestampStart)
+	}
+
+	req := &BackfillRequest{
+		Exchange: "Mock",
+		Currency: "BTC-USD",
+		DataType: BackfillTrades,
+		Start:    time.Unix(1010, 0),
+		End:      time.Unix(1500, 0),
+	}
+	w, err := newCSVBackfillWriter(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel after the first batch to leave a partial backfill
+	ctx, cancel := context.WithCancel(context.Background())
+	progress, err := Backfill(ctx, exch, req, w, func(BackfillProgress) {
+		cancel()
+	})
+	if err != context.Canceled {
+		t.Fatalf("Test failed. Expected cancellation, got %v", err)
+	}
+	if progress.Batches != 1 || progress.Records != 2 || progress.Last.TID != 3 {
+		t.Errorf("Test failed. Unexpected progress %+v", progress)
+	}
+	if n := exch.callCount("GetExchangeHistory"); n != 2 {
+		t.Errorf("Test failed. Rate limited batch not retried, %d requests", n)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = newCSVBackfillWriter(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, ok, err := w.Last()
+	if err != nil || !ok || cursor.TID != 3 || cursor.Time.Unix() != 1020 {
+		t.Fatalf("Test failed. Unexpected resume cursor %+v", cursor)
+	}
+
+	progress, err = Backfill(context.Background(), exch, req, w, nil)
+	if err != nil {
+		t.Fatalf("Test failed. Backfill error: %s", err)
+	}
+	if !progress.Complete || progress.Records != 4 {
+		t.Errorf("Test failed. Unexpected progress %+v", progress)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readBackfillCSV(t, dir, req)
+	expected := []string{
+		"timestamp,tid,price,amount,type",
+		"1010,2,6500,0.5,buy",
+		"1020,3,6500,0.5,buy",
+		"1020,4,6500,0.5,buy",
+		"1030,5,6500,0.5,buy",
+		"1040,6,6500,0.5,buy",
+		"1050,7,6500,0.5,buy",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Test failed. Unexpected CSV:\n%s", strings.Join(lines, "\n"))
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "mock", req.name()+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest backfillManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifest.Complete || manifest.Records != 6 || manifest.Last.TID != 7 {
+		t.Errorf("Test failed. Unexpected manifest %+v", manifest)
+	}
+}
+
+func TestBackfillCandles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gctbackfill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := time.Unix(3600, 0)
+	var candles []exchange.Candle
+	for x := 0; x < 10; x++ {
+		candles = append(candles, exchange.Candle{
+			Time:  start.Add(time.Duration(x) * time.Hour),
+			Close: float64(x),
+		})
+	}
+	exch := newHistoryExchange(nil, candles, 4)
+
+	req := &BackfillRequest{
+		Exchange: "Mock",
+		Currency: "BTCUSD",
+		DataType: BackfillCandles,
+		Start:    start,
+		End:      start.Add(time.Hour * 8),
+		Interval: "1h",
+	}
+	w, err := newCSVBackfillWriter(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var updates int
+	progress, err := Backfill(context.Background(), exch, req, w, func(BackfillProgress) {
+		updates++
+	})
+	if err != nil {
+		t.Fatalf("Test failed. Backfill error: %s", err)
+	}
+	w.Close()
+
+	if progress.Batches != 2 || updates != 2 || progress.Records != 8 {
+		t.Errorf("Test failed. Unexpected progress %+v", progress)
+	}
+	lines := readBackfillCSV(t, dir, req)
+	if len(lines) != 9 || lines[8] != "28800,0,0,0,7,0" {
+		t.Errorf("Test failed. Unexpected CSV:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// A completed backfill only requests the remaining range
+	exch.resetCalls()
+	w, err = newCSVBackfillWriter(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	progress, err = Backfill(context.Background(), exch, req, w, nil)
+	if err != nil || !progress.Complete || progress.Records != 0 ||
+		exch.callCount("GetHistoricCandles") != 0 {
+		t.Errorf("Test failed. Completed backfill refetched %+v %v", progress, err)
+	}
+}
+
+func TestBackfillRequestValidate(t *testing.T) {
+	req := BackfillRequest{
+		Exchange: "Bitfinex",
+		Currency: "BTCUSD",
+		DataType: BackfillCandles,
+		Start:    time.Unix(1000, 0),
+		End:      time.Unix(2000, 0),
+	}
+	_, err := req.validate()
+	if err == nil {
+		t.Error("Test failed. Candles accepted without an interval")
+	}
+
+	req.Interval = "1h"
+	interval, err := req.validate()
+	if err != nil || interval != time.Hour || req.AssetType != "SPOT" {
+		t.Errorf("Test failed. Unexpected validation result %v %v", interval, err)
+	}
+	if req.name() != "BTCUSD_spot_candles_1h" {
+		t.Errorf("Test failed. Unexpected name %s", req.name())
+	}
+
+	req.End = req.Start
+	_, err = req.validate()
+	if err == nil {
+		t.Error("Test failed. Empty range accepted")
+	}
+}
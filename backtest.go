@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/backtest"
+)
+
+// BacktestSettings holds the command line settings of a backtest
+type BacktestSettings struct {
+	DataPath   string
+	EventsFile string
+	Start      string
+	End        string
+	Speed      float64
+	FeePercent float64
+}
+
+// parseBacktestTime parses an optional RFC3339 backtest start or end time
+func parseBacktestTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid backtest %s time %q, expected RFC3339",
+			name, value)
+	}
+	return t, nil
+}
+
+// loadBacktestHooks sets up the communications enabled by the config and
+// registers the market data hooks the bot registers on startup, so they
+// receive the replayed updates. The events subsystem isn't started as the
+// backtest evaluates its replayed events itself
+func loadBacktestHooks() {
+	setupCommunications()
+	registerDefaultHooks()
+}
+
+// RunBacktest replays the backfilled trades and candles under the data path
+// through the saved events and the registered hooks, routing ORDER event
+// actions to paper trading. The events file defaults to the events saved in
+// the data directory. Hooks receive the replayed updates in order before the
+// next record is replayed
+func RunBacktest(ctx context.Context, settings *BacktestSettings) (*backtest.Report, error) {
+	start, err := parseBacktestTime("start", settings.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseBacktestTime("end", settings.End)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsFile := settings.EventsFile
+	if eventsFile == "" {
+		eventsFile = filepath.Join(bot.settings.DataDir, "events.json")
+	}
+	replayed, err := backtest.LoadEvents(eventsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return backtest.Run(ctx, &backtest.Config{
+		DataPath:       settings.DataPath,
+		Start:          start,
+		End:            end,
+		Speed:          settings.Speed,
+		FeePercent:     settings.FeePercent,
+		Events:         replayed,
+		TickerHooks:    []backtest.TickerHook{hooks.deliverTickerNow},
+		OrderbookHooks: []backtest.OrderbookHook{hooks.deliverOrderbookNow},
+	})
+}
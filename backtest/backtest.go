@@ -0,0 +1,257 @@
+// Package backtest replays backfilled trades and candles through the ticker,
+// orderbook and events packages in simulated time, so event configurations
+// can be validated offline. ORDER event actions are filled by a paper trader
+// against books approximated from the replayed data and summarised in a P&L
+// and trade list report.
+//
+// Run replaces the loaded events and the stored market data of the replayed
+// markets while it runs, so it must not be used alongside a running bot.
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// TickerHook is called with every replayed ticker accepted by the ticker
+// package
+type TickerHook func(exchangeName, assetType string, p pair.CurrencyPair, price ticker.Price)
+
+// OrderbookHook is called with every replayed orderbook
+type OrderbookHook func(exchangeName, assetType string, p pair.CurrencyPair, ob orderbook.Base)
+
+// Config holds the data and events replayed by a backtest
+type Config struct {
+	// DataPath is the directory searched for backfill CSV files and their
+	// manifests
+	DataPath string
+	// Start and End limit the replayed records, a zero start or end leaves
+	// that side of the range open
+	Start time.Time
+	End   time.Time
+	// Speed is the multiple of the recorded pace the data is replayed at,
+	// zero replays as fast as possible
+	Speed float64
+	// FeePercent is the taker fee charged on paper trades
+	FeePercent float64
+	// Events are evaluated against the replayed data, their triggered state
+	// is reset before the replay
+	Events         []*events.Event
+	TickerHooks    []TickerHook
+	OrderbookHooks []OrderbookHook
+}
+
+// EventResult holds whether an event triggered during a backtest
+type EventResult struct {
+	ID          int       `json:"id"`
+	Event       string    `json:"event"`
+	Triggered   bool      `json:"triggered"`
+	TriggeredAt time.Time `json:"triggeredAt,omitempty"`
+}
+
+// Report summarises a backtest. PnL is the total P&L of the paper trading
+// positions by second currency
+type Report struct {
+	Start           time.Time          `json:"start"`
+	End             time.Time          `json:"end"`
+	Records         int                `json:"records"`
+	RejectedTickers int                `json:"rejectedTickers"`
+	Events          []EventResult      `json:"events"`
+	Trades          []Trade            `json:"trades"`
+	Positions       []Position         `json:"positions"`
+	PnL             map[string]float64 `json:"pnl"`
+}
+
+// LoadEvents loads events saved by the events package to replay in a
+// backtest
+func LoadEvents(path string) ([]*events.Event, error) {
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var loaded []*events.Event
+	err = json.Unmarshal(data, &loaded)
+	if err != nil {
+		return nil, fmt.Errorf("events file %s is corrupt: %s", path, err)
+	}
+	var result []*events.Event
+	for x := range loaded {
+		if loaded[x] != nil {
+			result = append(result, loaded[x])
+		}
+	}
+	return result, nil
+}
+
+// validate checks the replay range and speed
+func (c *Config) validate() error {
+	if c.DataPath == "" {
+		return errors.New("data path not set")
+	}
+	if !c.End.IsZero() && !c.Start.Before(c.End) {
+		return errors.New("start must be before end")
+	}
+	if c.Speed < 0 || math.IsNaN(c.Speed) || math.IsInf(c.Speed, 0) {
+		return fmt.Errorf("invalid speed multiplier %v", c.Speed)
+	}
+	if c.FeePercent < 0 || c.FeePercent >= 100 || math.IsNaN(c.FeePercent) {
+		return fmt.Errorf("invalid fee percent %v", c.FeePercent)
+	}
+	return nil
+}
+
+// Run replays the recorded data in timestamp order. Each record updates the
+// approximate book and ticker of its market, which are processed by the
+// orderbook and ticker packages, evaluated by the events and passed to the
+// hooks in that order. Replaying the same data and events always produces the
+// same report. The replay stops when the context is cancelled
+func Run(ctx context.Context, cfg *Config) (*Report, error) {
+	err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	markets, records, err := loadRecords(cfg.DataPath, cfg.Start, cfg.End)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no recorded data in %s within the time range",
+			cfg.DataPath)
+	}
+
+	states := make(map[string]*marketState)
+	for x := range markets {
+		if _, ok := states[markets[x].key()]; !ok {
+			states[markets[x].key()] = newMarketState(markets[x])
+		}
+	}
+	removeMarketData(markets)
+	defer removeMarketData(markets)
+
+	var simulated time.Time
+	now := func() time.Time { return simulated }
+	trader := newPaperTrader(cfg.FeePercent, states, now)
+
+	replayed := make([]*events.Event, len(cfg.Events))
+	for x := range cfg.Events {
+		e := *cfg.Events[x]
+		e.Executed = false
+		e.Inactive = false
+		e.LastTriggered = time.Time{}
+		e.LastEvaluated = time.Time{}
+		e.LastData = time.Time{}
+		replayed[x] = &e
+	}
+	previous := events.Events
+	events.Events = replayed
+	events.SetOrderHandler(trader.placeOrder)
+	events.SetClock(now)
+	defer func() {
+		events.Events = previous
+		events.SetOrderHandler(nil)
+		events.SetClock(nil)
+	}()
+
+	report := &Report{
+		Start:   records[0].time,
+		End:     records[len(records)-1].time,
+		Records: len(records),
+	}
+	for x := range records {
+		if cfg.Speed > 0 && x > 0 {
+			err = wait(ctx, time.Duration(float64(records[x].time.Sub(simulated))/cfg.Speed))
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		simulated = records[x].time
+		if !replay(cfg, states[records[x].market.key()], &records[x]) {
+			report.RejectedTickers++
+		}
+	}
+
+	for _, e := range replayed {
+		report.Events = append(report.Events, EventResult{
+			ID:          e.ID,
+			Event:       e.String(),
+			Triggered:   e.Executed,
+			TriggeredAt: e.LastTriggered,
+		})
+	}
+	report.Trades = trader.trades
+	report.Positions, report.PnL = trader.results()
+	return report, nil
+}
+
+// replay applies a record to its market and processes the resulting book and
+// ticker, returning false if the ticker was rejected
+func replay(cfg *Config, state *marketState, rec *record) bool {
+	m := rec.market
+	if m.dataType == DataCandles {
+		state.applyCandle(rec.time, rec.candle.Open, rec.candle.High, rec.candle.Low,
+			rec.candle.Close, rec.candle.Volume)
+	} else {
+		state.applyTrade(rec.time, rec.trade.Price, rec.trade.Amount, rec.trade.Type)
+	}
+
+	ob := state.book
+	ob.Pair = m.pair
+	ob.AssetType = m.assetType
+	orderbook.ProcessOrderbook(m.exchange, m.pair, ob, m.assetType)
+	events.ProcessOrderbook(m.exchange, m.assetType, m.pair, ob)
+	for x := range cfg.OrderbookHooks {
+		cfg.OrderbookHooks[x](m.exchange, m.assetType, m.pair, ob)
+	}
+
+	price := state.ticker
+	price.Pair = m.pair
+	price.CurrencyPair = m.pair.Pair().String()
+	if ticker.ProcessTicker(m.exchange, m.pair, price, m.assetType) != nil {
+		return false
+	}
+	events.ProcessTicker(m.exchange, m.assetType, m.pair, price)
+	for x := range cfg.TickerHooks {
+		cfg.TickerHooks[x](m.exchange, m.assetType, m.pair, price)
+	}
+	return true
+}
+
+// removeMarketData removes the stored tickers and orderbooks of the replayed
+// markets, so a replay doesn't depend on or leave behind market data
+func removeMarketData(markets []*market) {
+	for x := range markets {
+		ticker.RemoveTicker(markets[x].exchange, markets[x].pair, markets[x].assetType)
+		orderbook.RemoveOrderbook(markets[x].exchange, markets[x].pair,
+			markets[x].assetType)
+	}
+}
+
+// wait paces the replay, returning early with an error if the context is
+// cancelled
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,264 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+const testStart = 1538352000
+
+// writeBackfill writes a backfill CSV file and its manifest
+func writeBackfill(t *testing.T, dir, exchangeName, currency, dataType, interval, name, csv string) {
+	dir = filepath.Join(dir, exchangeName)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name+".csv"), []byte(csv), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := map[string]interface{}{
+		"request": map[string]string{
+			"exchange":  exchangeName,
+			"currency":  currency,
+			"assetType": ticker.Spot,
+			"dataType":  dataType,
+			"interval":  interval,
+		},
+		"file": name + ".csv",
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name+".json"), data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupTestData writes Bitstamp BTCUSD trades and Kraken BTCEUR candles and
+// returns the data path and the events file
+func setupTestData(t *testing.T) (string, string) {
+	dir, err := ioutil.TempDir("", "gctbacktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeBackfill(t, dir, "Bitstamp", "BTCUSD", DataTrades, "", "BTCUSD_spot_trades",
+		"timestamp,tid,price,amount,type\n"+
+			"1538352000,1,100,1,buy\n"+
+			"1538352010,2,99,2,sell\n"+
+			"1538352020,3,95,1,sell\n"+
+			"1538352100,4,108,1,buy\n"+
+			"1538352110,5,109,1,sell\n")
+	writeBackfill(t, dir, "Kraken", "BTCEUR", DataCandles, "1m", "BTCEUR_spot_candles_1m",
+		"timestamp,open,high,low,close,volume\n"+
+			"1538352000,90,92,89,91,6\n"+
+			"1538352060,91,93,90,92,5\n")
+
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	testEvents := []*events.Event{
+		{ID: 1, Exchange: "Bitstamp", Item: "PRICE", Condition: "<,96", Pair: btcusd,
+			Asset: ticker.Spot, Action: "ORDER,BUY,0.5"},
+		{ID: 2, Exchange: "Bitstamp", Item: "PRICE", Condition: ">=,109", Pair: btcusd,
+			Asset: ticker.Spot, Action: "ORDER,SELL,0.5"},
+		{ID: 3, Exchange: "Kraken", Item: "VOLUME", Condition: ">,10",
+			Pair: pair.NewCurrencyPair("BTC", "EUR"), Asset: ticker.Spot,
+			Action: "CONSOLE_PRINT"},
+		// Already triggered events are replayed from their initial state
+		{ID: 4, Exchange: "Bitstamp", Item: "PRICE", Condition: ">,200", Pair: btcusd,
+			Asset: ticker.Spot, Action: "ORDER,BUY,1", Executed: true},
+	}
+	data, err := json.Marshal(testEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventsFile := filepath.Join(dir, "events.json")
+	err = ioutil.WriteFile(eventsFile, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, eventsFile
+}
+
+func TestRun(t *testing.T) {
+	dir, eventsFile := setupTestData(t)
+	defer os.RemoveAll(dir)
+
+	loaded, err := LoadEvents(eventsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tickers, orderbooks int
+	report, err := Run(context.Background(), &Config{
+		DataPath:   dir,
+		FeePercent: 0.1,
+		Events:     loaded,
+		TickerHooks: []TickerHook{func(_, _ string, _ pair.CurrencyPair, _ ticker.Price) {
+			tickers++
+		}},
+		OrderbookHooks: []OrderbookHook{func(_, _ string, _ pair.CurrencyPair, _ orderbook.Base) {
+			orderbooks++
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Records != 7 || tickers != 7 || orderbooks != 7 || report.RejectedTickers != 0 {
+		t.Errorf("Test failed. Unexpected replay counts, records %d tickers %d orderbooks %d rejected %d",
+			report.Records, tickers, orderbooks, report.RejectedTickers)
+	}
+	if !report.Start.Equal(time.Unix(testStart, 0)) ||
+		!report.End.Equal(time.Unix(testStart+120, 0)) {
+		t.Errorf("Test failed. Unexpected replay range %v to %v", report.Start, report.End)
+	}
+
+	triggered := []time.Time{
+		time.Unix(testStart+20, 0),
+		time.Unix(testStart+110, 0),
+		time.Unix(testStart+120, 0),
+		{},
+	}
+	for x := range report.Events {
+		if report.Events[x].Triggered != !triggered[x].IsZero() ||
+			!report.Events[x].TriggeredAt.Equal(triggered[x]) {
+			t.Errorf("Test failed. Event %d expected trigger time %v, got %+v",
+				report.Events[x].ID, triggered[x], report.Events[x])
+		}
+	}
+	if loaded[0].Executed || !loaded[3].Executed {
+		t.Error("Test failed. The supplied events were modified")
+	}
+
+	expected := []Trade{
+		{Time: time.Unix(testStart+20, 0).UTC(), EventID: 1, Exchange: "Bitstamp",
+			Pair: "BTCUSD", AssetType: ticker.Spot, Side: exchange.Buy, Amount: 0.5,
+			Filled: 0.5, Price: 100, Fee: 0.05},
+		{Time: time.Unix(testStart+110, 0).UTC(), EventID: 2, Exchange: "Bitstamp",
+			Pair: "BTCUSD", AssetType: ticker.Spot, Side: exchange.Sell, Amount: 0.5,
+			Filled: 0.5, Price: 109, Fee: 0.0545},
+	}
+	if len(report.Trades) != len(expected) {
+		t.Fatalf("Test failed. Unexpected trades %+v", report.Trades)
+	}
+	for x := range expected {
+		trade := report.Trades[x]
+		if !trade.Time.Equal(expected[x].Time) || trade.EventID != expected[x].EventID ||
+			trade.Side != expected[x].Side || trade.Filled != expected[x].Filled ||
+			trade.Price != expected[x].Price ||
+			math.Abs(trade.Fee-expected[x].Fee) > 1e-9 {
+			t.Errorf("Test failed. Trade %d expected %+v, got %+v", x, expected[x], trade)
+		}
+	}
+
+	if len(report.Positions) != 1 || report.Positions[0].Base != 0 ||
+		report.Positions[0].Trades != 2 || report.Positions[0].LastPrice != 109 {
+		t.Errorf("Test failed. Unexpected positions %+v", report.Positions)
+	}
+	if pnl := report.PnL["USD"]; math.Abs(pnl-4.3955) > 1e-9 {
+		t.Errorf("Test failed. Expected USD P&L 4.3955, got %v", pnl)
+	}
+
+	if len(events.Events) != 0 {
+		t.Error("Test failed. Loaded events not restored after the backtest")
+	}
+	_, err = ticker.GetTicker("Bitstamp", pair.NewCurrencyPair("BTC", "USD"), ticker.Spot)
+	if err == nil {
+		t.Error("Test failed. Replayed tickers not removed after the backtest")
+	}
+}
+
+func TestRunDeterministic(t *testing.T) {
+	dir, eventsFile := setupTestData(t)
+	defer os.RemoveAll(dir)
+
+	loaded, err := LoadEvents(eventsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reports [][]byte
+	for _, speed := range []float64{0, 0, 1e6} {
+		report, err := Run(context.Background(), &Config{
+			DataPath:   dir,
+			Speed:      speed,
+			FeePercent: 0.25,
+			Events:     loaded,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(report)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, data)
+	}
+	for x := 1; x < len(reports); x++ {
+		if !reflect.DeepEqual(reports[0], reports[x]) {
+			t.Errorf("Test failed. Run %d report differs:\n%s\n%s", x, reports[0], reports[x])
+		}
+	}
+}
+
+func TestRunTimeRange(t *testing.T) {
+	dir, eventsFile := setupTestData(t)
+	defer os.RemoveAll(dir)
+
+	loaded, err := LoadEvents(eventsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the dip the buy never triggers, so the sell opens a short
+	report, err := Run(context.Background(), &Config{
+		DataPath: dir,
+		Start:    time.Unix(testStart+30, 0),
+		End:      time.Unix(testStart+120, 0),
+		Events:   loaded,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Records != 3 || len(report.Trades) != 1 ||
+		report.Trades[0].Side != exchange.Sell || report.Positions[0].Base != -0.5 {
+		t.Errorf("Test failed. Unexpected report %+v", report)
+	}
+
+	for _, cfg := range []Config{
+		{},
+		{DataPath: dir, Start: time.Unix(testStart+60, 0), End: time.Unix(testStart, 0)},
+		{DataPath: dir, Speed: -1},
+		{DataPath: dir, FeePercent: 100},
+		{DataPath: dir, Start: time.Unix(testStart+1000, 0)},
+		{DataPath: filepath.Join(dir, "missing")},
+	} {
+		_, err = Run(context.Background(), &cfg)
+		if err == nil {
+			t.Errorf("Test failed. Expected an error for %+v", cfg)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Run(ctx, &Config{DataPath: dir})
+	if err != context.Canceled {
+		t.Errorf("Test failed. Expected %v, got %v", context.Canceled, err)
+	}
+}
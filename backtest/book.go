@@ -0,0 +1,148 @@
+package backtest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+const (
+	// volumeWindow is the period the replayed ticker volume is summed over,
+	// matching the 24 hour volume reported by exchange tickers
+	volumeWindow = time.Hour * 24
+	// depthWindow is the period of trades sizing the approximate orderbook
+	// levels built from trades
+	depthWindow = time.Minute
+)
+
+// volumeEntry is an amount traded at a point in time
+type volumeEntry struct {
+	time   time.Time
+	amount float64
+}
+
+// rollingVolume sums the amounts traded within a trailing window
+type rollingVolume struct {
+	window  time.Duration
+	entries []volumeEntry
+	total   float64
+}
+
+// add adds an amount traded at the time and drops the amounts which fell out
+// of the window
+func (r *rollingVolume) add(t time.Time, amount float64) {
+	r.entries = append(r.entries, volumeEntry{time: t, amount: amount})
+	r.total += amount
+	cutoff := t.Add(-r.window)
+	var expired int
+	for expired < len(r.entries) && !r.entries[expired].time.After(cutoff) {
+		r.total -= r.entries[expired].amount
+		expired++
+	}
+	r.entries = r.entries[expired:]
+	if len(r.entries) == 0 || r.total < 0 {
+		r.total = 0
+	}
+}
+
+// marketState is the replayed market data of an exchange, currency pair and
+// asset type
+type marketState struct {
+	// quote is the second currency of the market pair, which the P&L is in
+	quote  string
+	volume rollingVolume
+	depth  rollingVolume
+	bid    float64
+	ask    float64
+	book   orderbook.Base
+	ticker ticker.Price
+}
+
+func newMarketState(m *market) *marketState {
+	return &marketState{
+		quote:  common.StringToUpper(m.pair.SecondCurrency.String()),
+		volume: rollingVolume{window: volumeWindow},
+		depth:  rollingVolume{window: depthWindow},
+	}
+}
+
+// tradeSide returns whether a recorded trade was taken by a buyer or seller,
+// ok is false when the exchange didn't report it
+func tradeSide(tradeType string) (buy, ok bool) {
+	switch common.StringToLower(strings.TrimSpace(tradeType)) {
+	case "buy", "b", "bid":
+		return true, true
+	case "sell", "s", "ask":
+		return false, true
+	}
+	return false, false
+}
+
+// applyTrade updates the market with a recorded trade. The approximate book
+// has a single level on each side, the ask at the price of the latest buy and
+// the bid at the price of the latest sell, sized by the amount traded over the
+// depth window. A side the trade didn't update is moved to the trade price if
+// it would otherwise cross the book
+func (s *marketState) applyTrade(t time.Time, price, amount float64, tradeType string) {
+	buy, ok := tradeSide(tradeType)
+	switch {
+	case !ok:
+		s.bid, s.ask = price, price
+	case buy:
+		s.ask = price
+		if s.bid == 0 || s.bid > price {
+			s.bid = price
+		}
+	default:
+		s.bid = price
+		if s.ask == 0 || s.ask < price {
+			s.ask = price
+		}
+	}
+
+	s.volume.add(t, amount)
+	s.depth.add(t, amount)
+	size := s.depth.total
+	if size < amount {
+		size = amount
+	}
+	s.setBook(t, s.bid, s.ask, size)
+	s.ticker = ticker.Price{
+		Last:        price,
+		Bid:         s.bid,
+		Ask:         s.ask,
+		Volume:      s.volume.total,
+		LastUpdated: t,
+	}
+}
+
+// applyCandle updates the market with a recorded candle at its close. The
+// approximate book has a single level on each side at the close price sized by
+// the candle volume
+func (s *marketState) applyCandle(t time.Time, open, high, low, closePrice, volume float64) {
+	s.bid, s.ask = closePrice, closePrice
+	s.volume.add(t, volume)
+	s.setBook(t, closePrice, closePrice, volume)
+	s.ticker = ticker.Price{
+		Last:        closePrice,
+		High:        high,
+		Low:         low,
+		Bid:         closePrice,
+		Ask:         closePrice,
+		Volume:      s.volume.total,
+		LastUpdated: t,
+	}
+	s.ticker.SetOpen(open)
+}
+
+// setBook sets the approximate book to a single level on each side
+func (s *marketState) setBook(t time.Time, bid, ask, size float64) {
+	s.book = orderbook.Base{
+		Bids:        []orderbook.Item{{Price: bid, Amount: size}},
+		Asks:        []orderbook.Item{{Price: ask, Amount: size}},
+		LastUpdated: t,
+	}
+}
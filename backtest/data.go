@@ -0,0 +1,256 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// Recorded data types, matching the backfill data types
+const (
+	DataTrades  = "trades"
+	DataCandles = "candles"
+)
+
+// manifest holds the fields of a backfill manifest needed to load the CSV
+// file it describes
+type manifest struct {
+	Request struct {
+		Exchange  string `json:"exchange"`
+		Currency  string `json:"currency"`
+		AssetType string `json:"assetType"`
+		DataType  string `json:"dataType"`
+		Interval  string `json:"interval"`
+	} `json:"request"`
+	File string `json:"file"`
+}
+
+// market is a recorded exchange, currency pair, asset type and data type
+type market struct {
+	exchange  string
+	pair      pair.CurrencyPair
+	assetType string
+	dataType  string
+	interval  time.Duration
+}
+
+// key returns the key of the market's exchange, pair and asset type, shared by
+// the trade and candle data of a market
+func (m *market) key() string {
+	return marketKey(m.exchange, m.pair, m.assetType)
+}
+
+// marketKey returns the key of an exchange, currency pair and asset type
+func marketKey(exchangeName string, p pair.CurrencyPair, assetType string) string {
+	return common.StringToUpper(exchangeName) + " " +
+		common.StringToUpper(p.Pair().String()) + " " +
+		common.StringToUpper(assetType)
+}
+
+// record is a recorded trade or candle, timestamped when it became known. A
+// candle is known at its close
+type record struct {
+	time   time.Time
+	market *market
+	trade  exchange.TradeHistory
+	candle exchange.Candle
+}
+
+// loadRecords loads the backfilled trades and candles under the data path
+// from start up to but excluding end, a zero start or end leaves that side of
+// the range open. Records are returned in timestamp order, records sharing a
+// timestamp are ordered by market and then by their position in the file
+func loadRecords(dataPath string, start, end time.Time) ([]*market, []record, error) {
+	var manifests []string
+	err := filepath.Walk(dataPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".json" {
+			manifests = append(manifests, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var markets []*market
+	var files []string
+	for x := range manifests {
+		m, file, err := loadManifest(manifests[x])
+		if err != nil {
+			return nil, nil, err
+		}
+		if m == nil {
+			continue
+		}
+		markets = append(markets, m)
+		files = append(files, file)
+	}
+	if len(markets) == 0 {
+		return nil, nil, fmt.Errorf("no recorded trades or candles found in %s", dataPath)
+	}
+
+	order := make([]int, len(markets))
+	for x := range order {
+		order[x] = x
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := markets[order[i]], markets[order[j]]
+		if a.key() != b.key() {
+			return a.key() < b.key()
+		}
+		return a.dataType+a.interval.String() < b.dataType+b.interval.String()
+	})
+
+	var records []record
+	sorted := make([]*market, 0, len(markets))
+	for _, x := range order {
+		sorted = append(sorted, markets[x])
+		loaded, err := loadCSV(files[x], markets[x])
+		if err != nil {
+			return nil, nil, err
+		}
+		for y := range loaded {
+			if loaded[y].time.Before(start) ||
+				(!end.IsZero() && !loaded[y].time.Before(end)) {
+				continue
+			}
+			records = append(records, loaded[y])
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].time.Before(records[j].time)
+	})
+	return sorted, records, nil
+}
+
+// loadManifest reads a backfill manifest and returns its market and CSV file.
+// JSON files which aren't backfill manifests are skipped with a nil market
+func loadManifest(path string) (*market, string, error) {
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	var man manifest
+	if json.Unmarshal(data, &man) != nil || man.Request.Exchange == "" ||
+		man.File == "" {
+		return nil, "", nil
+	}
+
+	m := &market{
+		exchange:  man.Request.Exchange,
+		pair:      pair.NewCurrencyPairFromString(man.Request.Currency),
+		assetType: man.Request.AssetType,
+		dataType:  man.Request.DataType,
+	}
+	if m.assetType == "" {
+		m.assetType = ticker.Spot
+	}
+	switch m.dataType {
+	case DataTrades:
+	case DataCandles:
+		m.interval, err = time.ParseDuration(man.Request.Interval)
+		if err != nil || m.interval <= 0 {
+			return nil, "", fmt.Errorf("%s invalid candle interval %q", path,
+				man.Request.Interval)
+		}
+	default:
+		return nil, "", fmt.Errorf("%s invalid data type %q", path, m.dataType)
+	}
+	return m, filepath.Join(filepath.Dir(path), man.File), nil
+}
+
+// loadCSV reads the records of a backfill CSV file, locating the columns by
+// the header
+func loadCSV(path string, m *market) ([]record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	columns := make(map[string]int)
+	for x := range header {
+		columns[header[x]] = x
+	}
+
+	var records []record
+	for line := 2; ; line++ {
+		fields, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		values := make(map[string]float64)
+		integers := make(map[string]int64)
+		for name, x := range columns {
+			if x >= len(fields) {
+				continue
+			}
+			switch name {
+			case "type":
+				continue
+			case "timestamp", "tid":
+				integers[name], err = strconv.ParseInt(fields[x], 10, 64)
+			default:
+				values[name], err = strconv.ParseFloat(fields[x], 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d invalid %s %q", path, line, name,
+					fields[x])
+			}
+		}
+
+		rec := record{market: m}
+		timestamp := integers["timestamp"]
+		if m.dataType == DataCandles {
+			rec.candle = exchange.Candle{
+				Time:   common.UnixTimestampToTime(timestamp).UTC(),
+				Open:   values["open"],
+				High:   values["high"],
+				Low:    values["low"],
+				Close:  values["close"],
+				Volume: values["volume"],
+			}
+			rec.time = rec.candle.Time.Add(m.interval)
+		} else {
+			rec.trade = exchange.TradeHistory{
+				Timestamp: timestamp,
+				TID:       integers["tid"],
+				Price:     values["price"],
+				Amount:    values["amount"],
+				Exchange:  m.exchange,
+			}
+			if x, ok := columns["type"]; ok && x < len(fields) {
+				rec.trade.Type = fields[x]
+			}
+			rec.time = common.UnixTimestampToTime(timestamp).UTC()
+		}
+		records = append(records, rec)
+	}
+}
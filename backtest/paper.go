@@ -0,0 +1,154 @@
+package backtest
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// errNoMarketData is returned when a paper order is placed on a market before
+// any of its data has been replayed
+var errNoMarketData = errors.New("no market data replayed for the order's market")
+
+// Trade is a paper trade placed by an ORDER event action. Price is the volume
+// weighted fill price and the fee is charged in the second currency
+type Trade struct {
+	Time      time.Time          `json:"time"`
+	EventID   int                `json:"eventId"`
+	Exchange  string             `json:"exchange"`
+	Pair      string             `json:"pair"`
+	AssetType string             `json:"assetType"`
+	Side      exchange.OrderSide `json:"side"`
+	Amount    float64            `json:"amount"`
+	Filled    float64            `json:"filled"`
+	Price     float64            `json:"price"`
+	Fee       float64            `json:"fee"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// Position is the paper trading position of a market. Base is the net amount
+// of the first currency bought and Quote the net amount of the second
+// currency received, after fees. PnL marks the position to the last replayed
+// price
+type Position struct {
+	Exchange  string  `json:"exchange"`
+	Pair      string  `json:"pair"`
+	AssetType string  `json:"assetType"`
+	Base      float64 `json:"base"`
+	Quote     float64 `json:"quote"`
+	Fees      float64 `json:"fees"`
+	Trades    int     `json:"trades"`
+	LastPrice float64 `json:"lastPrice"`
+	PnL       float64 `json:"pnl"`
+}
+
+// paperTrader executes market orders against the replayed books. Positions
+// start flat and aren't limited by balances, so selling opens a short position
+type paperTrader struct {
+	feePercent float64
+	now        func() time.Time
+	markets    map[string]*marketState
+	positions  map[string]*Position
+	trades     []Trade
+}
+
+func newPaperTrader(feePercent float64, markets map[string]*marketState, now func() time.Time) *paperTrader {
+	return &paperTrader{
+		feePercent: feePercent,
+		now:        now,
+		markets:    markets,
+		positions:  make(map[string]*Position),
+	}
+}
+
+// placeOrder fills an ORDER event action as a market order against the latest
+// replayed book of the event's market. An order the book can't fully fill is
+// partially filled. It implements events.OrderHandler
+func (p *paperTrader) placeOrder(e *events.Event, side string, amount float64) error {
+	trade := Trade{
+		Time:      p.now(),
+		EventID:   e.ID,
+		Exchange:  e.Exchange,
+		Pair:      e.Pair.Pair().String(),
+		AssetType: e.Asset,
+		Side:      exchange.Buy,
+		Amount:    amount,
+	}
+	if side == "SELL" {
+		trade.Side = exchange.Sell
+	}
+
+	err := p.fill(&trade, marketKey(e.Exchange, e.Pair, e.Asset))
+	if err != nil {
+		trade.Error = err.Error()
+	}
+	p.trades = append(p.trades, trade)
+	return err
+}
+
+// fill fills a trade against the book of the market and updates the position
+func (p *paperTrader) fill(trade *Trade, key string) error {
+	state, ok := p.markets[key]
+	if !ok {
+		return errNoMarketData
+	}
+
+	var err error
+	trade.Price, trade.Filled, err = state.book.WeightedAveragePrice(trade.Amount,
+		trade.Side == exchange.Buy)
+	if err != nil && err != orderbook.ErrInsufficientDepth {
+		return err
+	}
+	if trade.Filled == 0 {
+		return err
+	}
+	cost := trade.Price * trade.Filled
+	trade.Fee = cost * p.feePercent / 100
+
+	pos, ok := p.positions[key]
+	if !ok {
+		pos = &Position{
+			Exchange:  trade.Exchange,
+			Pair:      trade.Pair,
+			AssetType: trade.AssetType,
+		}
+		p.positions[key] = pos
+	}
+	pos.Trades++
+	pos.Fees += trade.Fee
+	if trade.Side == exchange.Buy {
+		pos.Base += trade.Filled
+		pos.Quote -= cost + trade.Fee
+	} else {
+		pos.Base -= trade.Filled
+		pos.Quote += cost - trade.Fee
+	}
+	return err
+}
+
+// results marks the positions to the last replayed prices and returns them
+// ordered by market along with the total P&L by second currency
+func (p *paperTrader) results() ([]Position, map[string]float64) {
+	keys := make([]string, 0, len(p.positions))
+	for key := range p.positions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	positions := make([]Position, 0, len(keys))
+	pnl := make(map[string]float64)
+	for _, key := range keys {
+		pos := *p.positions[key]
+		if state, ok := p.markets[key]; ok {
+			pos.LastPrice = state.ticker.Last
+		}
+		pos.PnL = pos.Quote + pos.Base*pos.LastPrice
+		positions = append(positions, pos)
+		pnl[p.markets[key].quote] += pos.PnL
+	}
+	return positions, pnl
+}
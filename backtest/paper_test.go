@@ -0,0 +1,80 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+func TestPaperTrader(t *testing.T) {
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	m := &market{exchange: "Bitstamp", pair: btcusd, assetType: ticker.Spot,
+		dataType: DataTrades}
+	state := newMarketState(m)
+	now := time.Unix(testStart, 0)
+	state.applyTrade(now, 100, 1, "buy")
+	state.applyTrade(now.Add(time.Second), 98, 1, "sell")
+
+	trader := newPaperTrader(1, map[string]*marketState{m.key(): state},
+		func() time.Time { return now })
+	e := &events.Event{ID: 1, Exchange: "Bitstamp", Pair: btcusd, Asset: ticker.Spot}
+
+	// The book holds the two trades, so a larger order is partially filled
+	err := trader.placeOrder(e, "BUY", 3)
+	if err == nil || trader.trades[0].Filled != 2 || trader.trades[0].Price != 100 ||
+		trader.trades[0].Fee != 2 || trader.trades[0].Error == "" {
+		t.Errorf("Test failed. Unexpected partial fill %+v, error: %v", trader.trades[0], err)
+	}
+
+	err = trader.placeOrder(e, "SELL", 1)
+	if err != nil || trader.trades[1].Side != exchange.Sell || trader.trades[1].Price != 98 {
+		t.Errorf("Test failed. Unexpected sell %+v, error: %v", trader.trades[1], err)
+	}
+
+	err = trader.placeOrder(&events.Event{ID: 2, Exchange: "Kraken", Pair: btcusd,
+		Asset: ticker.Spot}, "BUY", 1)
+	if err != errNoMarketData || trader.trades[2].Filled != 0 {
+		t.Errorf("Test failed. Expected %v, got %v", errNoMarketData, err)
+	}
+
+	positions, pnl := trader.results()
+	// Bought 2 at 100 with a 2 fee, sold 1 at 98 with a 0.98 fee, marked at 98
+	if len(positions) != 1 || positions[0].Base != 1 ||
+		math.Abs(positions[0].Fees-2.98) > 1e-9 ||
+		math.Abs(positions[0].Quote+104.98) > 1e-9 || math.Abs(pnl["USD"]+6.98) > 1e-9 {
+		t.Errorf("Test failed. Unexpected positions %+v P&L %v", positions, pnl)
+	}
+}
+
+func TestApplyTrade(t *testing.T) {
+	state := newMarketState(&market{pair: pair.NewCurrencyPair("BTC", "USD")})
+	now := time.Unix(testStart, 0)
+
+	state.applyTrade(now, 100, 1, "")
+	if state.bid != 100 || state.ask != 100 {
+		t.Errorf("Test failed. Unknown side trade expected a 100 book, got %v/%v",
+			state.bid, state.ask)
+	}
+	state.applyTrade(now.Add(time.Second), 105, 2, "buy")
+	if state.bid != 100 || state.ask != 105 || state.book.Asks[0].Amount != 3 {
+		t.Errorf("Test failed. Unexpected book %+v", state.book)
+	}
+	// A sell above the ask moves the ask so the book isn't crossed
+	state.applyTrade(now.Add(time.Second*2), 107, 1, "Sell")
+	if state.bid != 107 || state.ask != 107 {
+		t.Errorf("Test failed. Expected an uncrossed 107 book, got %v/%v",
+			state.bid, state.ask)
+	}
+
+	// Trades older than the windows no longer size the book or count as volume
+	state.applyTrade(now.Add(time.Hour*25), 110, 0.5, "buy")
+	if state.book.Bids[0].Amount != 0.5 || state.ticker.Volume != 0.5 {
+		t.Errorf("Test failed. Unexpected rolling amounts, depth %v volume %v",
+			state.book.Bids[0].Amount, state.ticker.Volume)
+	}
+}
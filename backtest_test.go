@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/communications"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+func TestRunBacktest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gctbacktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	req := &BackfillRequest{
+		Exchange:  "Bitstamp",
+		Currency:  "BTCUSD",
+		AssetType: ticker.Spot,
+		DataType:  BackfillTrades,
+		Start:     start,
+		End:       start.Add(time.Hour),
+	}
+	w, err := newCSVBackfillWriter(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteTrades([]exchange.TradeHistory{
+		{Timestamp: start.Unix(), TID: 1, Price: 100, Amount: 1, Type: "buy"},
+		{Timestamp: start.Unix() + 60, TID: 2, Price: 90, Amount: 1, Type: "sell"},
+		{Timestamp: start.Unix() + 120, TID: 3, Price: 95, Amount: 1, Type: "buy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Commit(BackfillProgress{Complete: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventsFile := filepath.Join(dir, "events.json")
+	data, err := json.Marshal([]*events.Event{{ID: 1, Exchange: "Bitstamp", Item: "PRICE",
+		Condition: "<,95", Pair: pair.NewCurrencyPair("BTC", "USD"), Asset: ticker.Spot,
+		Action: "ORDER,BUY,1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(eventsFile, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var prices []float64
+	id := RegisterTickerHook(func(_, _ string, _ pair.CurrencyPair, price ticker.Price) {
+		prices = append(prices, price.Last)
+	})
+	defer RemoveHook(id)
+
+	settings := BacktestSettings{
+		DataPath:   dir,
+		EventsFile: eventsFile,
+		Start:      start.Format(time.RFC3339),
+	}
+	report, err := RunBacktest(context.Background(), &settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 3 || prices[0] != 100 || prices[2] != 95 {
+		t.Errorf("Test failed. Hook expected the replayed prices in order, got %v", prices)
+	}
+	if len(report.Trades) != 1 || report.Trades[0].Price != 100 ||
+		report.PnL["USD"] != -5 {
+		t.Errorf("Test failed. Unexpected backtest report %+v", report)
+	}
+
+	settings.End = "yesterday"
+	_, err = RunBacktest(context.Background(), &settings)
+	if err == nil {
+		t.Error("Test failed. Expected an invalid end time error")
+	}
+	settings.End = ""
+	settings.EventsFile = filepath.Join(dir, "missing.json")
+	_, err = RunBacktest(context.Background(), &settings)
+	if err == nil {
+		t.Error("Test failed. Expected a missing events file error")
+	}
+}
+
+func TestRunBacktestCandleHooks(t *testing.T) {
+	SetupTestHelpers(t)
+	dir, err := ioutil.TempDir("", "gctbacktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	req := &BackfillRequest{
+		Exchange:  "Bitstamp",
+		Currency:  "BTCUSD",
+		AssetType: ticker.Spot,
+		DataType:  BackfillCandles,
+		Interval:  "1m",
+		Start:     start,
+		End:       start.Add(time.Hour),
+	}
+	w, err := newCSVBackfillWriter(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var candles []exchange.Candle
+	for x, closePrice := range []float64{100, 98, 94, 96} {
+		candles = append(candles, exchange.Candle{Time: start.Add(time.Duration(x) * time.Minute),
+			Open: closePrice + 1, High: closePrice + 2, Low: closePrice - 2, Close: closePrice,
+			Volume: 10})
+	}
+	err = w.WriteCandles(candles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Commit(BackfillProgress{Complete: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventsFile := filepath.Join(dir, "events.json")
+	data, err := json.Marshal([]*events.Event{{ID: 1, Exchange: "Bitstamp", Item: "PRICE",
+		Condition: "<,95", Pair: pair.NewCurrencyPair("BTC", "USD"), Asset: ticker.Spot,
+		Action: "ORDER,BUY,1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(eventsFile, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The hooks enabled by the config are loaded before the replay, the
+	// communications hook stages the replayed tickers
+	defer func(s Settings, c *communications.Communications, commsRunning, eventsRunning bool) {
+		bot.settings, bot.comms = s, c
+		setSubsystemRunning(SubsystemCommunications, commsRunning)
+		setSubsystemRunning(SubsystemEvents, eventsRunning)
+	}(bot.settings, bot.comms, isSubsystemRunning(SubsystemCommunications),
+		isSubsystemRunning(SubsystemEvents))
+	setSubsystemRunning(SubsystemEvents, false)
+	bot.settings.EnableCommunications = true
+	hooks.m.RLock()
+	firstID := hooks.nextID + 1
+	hooks.m.RUnlock()
+	defer func() {
+		hooks.m.RLock()
+		lastID := hooks.nextID
+		hooks.m.RUnlock()
+		for id := firstID; id <= lastID; id++ {
+			RemoveHook(id)
+		}
+	}()
+	loadBacktestHooks()
+
+	var prices []float64
+	RegisterTickerHook(func(_, _ string, _ pair.CurrencyPair, price ticker.Price) {
+		prices = append(prices, price.Last)
+	})
+
+	report, err := RunBacktest(context.Background(), &BacktestSettings{
+		DataPath:   dir,
+		EventsFile: eventsFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prices, []float64{100, 98, 94, 96}) {
+		t.Errorf("Test failed. Hook expected the replayed candle closes in order, got %v",
+			prices)
+	}
+	if staged := new(base.Base).GetTicker("Bitstamp"); !strings.Contains(staged, "Last: 96.") {
+		t.Errorf("Test failed. Expected the config hooks to stage the last replayed ticker, got %q",
+			staged)
+	}
+	// Candles are replayed at their close time
+	if len(report.Events) != 1 || !report.Events[0].Triggered ||
+		!report.Events[0].TriggeredAt.Equal(start.Add(time.Minute*3)) {
+		t.Errorf("Test failed. Expected the ORDER event to trigger on the third candle, got %+v",
+			report.Events)
+	}
+	if len(report.Trades) != 1 || report.Trades[0].Price != 94 {
+		t.Errorf("Test failed. Expected the ORDER action to buy at 94, got %+v", report.Trades)
+	}
+}
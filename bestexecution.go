@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// bestExecutionMaxDataAge is the age above which an exchange orderbook or
+// ticker is too stale to rank the exchange as an execution venue
+const bestExecutionMaxDataAge = time.Minute
+
+// Execution venue price sources
+const (
+	priceSourceOrderbook = "orderbook"
+	priceSourceTicker    = "ticker"
+)
+
+// ErrNoExecutionVenue is returned when no enabled exchange supports the
+// currency pair and asset type of an order
+var ErrNoExecutionVenue = errors.New("no enabled exchange supports the currency pair and asset type")
+
+// ExecutionVenue holds the effective cost of an order on an exchange. For
+// buys EffectiveCost is the cost plus the taker fee, for sells it is the
+// proceeds less the fee. Venues which were skipped hold the reason in Error
+type ExecutionVenue struct {
+	Exchange       string  `json:"exchange"`
+	PriceSource    string  `json:"priceSource,omitempty"`
+	Price          float64 `json:"price"`
+	Cost           float64 `json:"cost"`
+	Fee            float64 `json:"fee"`
+	EffectiveCost  float64 `json:"effectiveCost"`
+	EffectivePrice float64 `json:"effectivePrice"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// BestExecution holds the execution venues for an order ranked from the best
+// effective cost, and the exchange of the best venue
+type BestExecution struct {
+	Pair         string             `json:"pair"`
+	AssetType    string             `json:"assetType"`
+	Side         exchange.OrderSide `json:"side"`
+	Amount       float64            `json:"amount"`
+	BestExchange string             `json:"bestExchange"`
+	Venues       []ExecutionVenue   `json:"venues"`
+}
+
+// GetBestExecutionVenue ranks the enabled exchanges supporting a currency pair
+// by the effective cost of an order, the cached orderbook fill price, or the
+// ticker ask or bid when there is no orderbook, plus the taker fee. Exchanges
+// with stale data are skipped, as are exchanges without the balance for the
+// order when checkBalance is set. BestExchange is empty if every exchange was
+// skipped
+func GetBestExecutionVenue(p pair.CurrencyPair, assetType string, side exchange.OrderSide, amount float64, checkBalance bool) (BestExecution, error) {
+	err := validateSimulation("", side, amount)
+	if err != nil {
+		return BestExecution{}, err
+	}
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+
+	result := BestExecution{
+		Pair:      p.Pair().String(),
+		AssetType: assetType,
+		Side:      side,
+		Amount:    amount,
+	}
+	for x := range bot.exchanges {
+		exch := bot.exchanges[x]
+		if exch == nil || !exch.IsEnabled() || !exch.SupportsAsset(assetType) {
+			continue
+		}
+		exchPair, ok := enabledPair(exch, p)
+		if !ok {
+			continue
+		}
+
+		venue, err := executionVenue(exch, exchPair, assetType, side, amount)
+		if err == nil && checkBalance {
+			err = checkVenueBalance(exch, exchPair, side, amount, venue.EffectiveCost)
+		}
+		if err != nil {
+			venue = ExecutionVenue{Exchange: exch.GetName(), Error: err.Error()}
+		}
+		result.Venues = append(result.Venues, venue)
+	}
+	if len(result.Venues) == 0 {
+		return BestExecution{}, ErrNoExecutionVenue
+	}
+
+	sort.SliceStable(result.Venues, func(i, j int) bool {
+		iRanked := result.Venues[i].Error == ""
+		jRanked := result.Venues[j].Error == ""
+		if iRanked != jRanked {
+			return iRanked
+		}
+		if side == exchange.Sell {
+			return result.Venues[i].EffectiveCost > result.Venues[j].EffectiveCost
+		}
+		return result.Venues[i].EffectiveCost < result.Venues[j].EffectiveCost
+	})
+	if result.Venues[0].Error == "" {
+		result.BestExchange = result.Venues[0].Exchange
+	}
+	return result, nil
+}
+
+// enabledPair returns the exchange's enabled currency pair matching p
+func enabledPair(exch exchange.IBotExchange, p pair.CurrencyPair) (pair.CurrencyPair, bool) {
+	enabledPairs := exch.GetEnabledCurrencies()
+	for x := range enabledPairs {
+		if enabledPairs[x].Equal(p, true) {
+			return enabledPairs[x], true
+		}
+	}
+	return pair.CurrencyPair{}, false
+}
+
+// executionVenue returns the effective cost of an order on an exchange using
+// its orderbook, falling back to its ticker when the orderbook is missing
+func executionVenue(exch exchange.IBotExchange, p pair.CurrencyPair, assetType string, side exchange.OrderSide, amount float64) (ExecutionVenue, error) {
+	venue := ExecutionVenue{Exchange: exch.GetName()}
+
+	ob, err := exch.GetOrderbookEx(p, assetType)
+	if err == nil {
+		if age := time.Since(ob.LastUpdated); age > bestExecutionMaxDataAge {
+			return venue, fmt.Errorf("orderbook is stale, last updated %v ago",
+				age.Round(time.Second))
+		}
+		venue.Price, _, err = ob.WeightedAveragePrice(amount, side == exchange.Buy)
+		if err == orderbook.ErrInsufficientDepth {
+			return venue, err
+		}
+		venue.PriceSource = priceSourceOrderbook
+	}
+	if err != nil {
+		tick, err := exch.GetTickerPrice(p, assetType)
+		if err != nil {
+			return venue, err
+		}
+		if age := time.Since(tick.LastUpdated); age > bestExecutionMaxDataAge {
+			return venue, fmt.Errorf("ticker is stale, last updated %v ago",
+				age.Round(time.Second))
+		}
+		venue.Price = tick.Ask
+		if side == exchange.Sell {
+			venue.Price = tick.Bid
+		}
+		if venue.Price <= 0 {
+			return venue, orderbook.ErrNoLiquidity
+		}
+		venue.PriceSource = priceSourceTicker
+	}
+
+	venue.Cost = venue.Price * amount
+	venue.Fee, err = exch.GetFeeByType(exchange.FeeBuilder{
+		FeeType:        exchange.CryptocurrencyTradeFee,
+		FirstCurrency:  p.Base().String(),
+		SecondCurrency: p.Quote().String(),
+		Delimiter:      p.Delimiter,
+		PurchasePrice:  venue.Price,
+		Amount:         amount,
+	})
+	if err != nil {
+		return venue, err
+	}
+
+	venue.EffectiveCost = venue.Cost + venue.Fee
+	if side == exchange.Sell {
+		venue.EffectiveCost = venue.Cost - venue.Fee
+	}
+	venue.EffectivePrice = venue.EffectiveCost / amount
+	return venue, nil
+}
+
+// checkVenueBalance returns an error if the exchange account lacks the quote
+// currency to pay for a buy or the base currency to fill a sell
+func checkVenueBalance(exch exchange.IBotExchange, p pair.CurrencyPair, side exchange.OrderSide, amount, effectiveCost float64) error {
+	currency, required := p.Quote().Upper().String(), effectiveCost
+	if side == exchange.Sell {
+		currency, required = p.Base().Upper().String(), amount
+	}
+	available, err := availableBalance(exch, currency)
+	if err != nil {
+		return err
+	}
+	if available < required {
+		return fmt.Errorf("insufficient %s balance, %v available of %v required",
+			currency, available, required)
+	}
+	return nil
+}
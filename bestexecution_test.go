@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// newVenueExchange returns a venue with a fresh single level orderbook
+// updated at the time given and a percentage taker fee
+func newVenueExchange(name string, bid, ask, feeRate float64, updated time.Time) *testExchange {
+	return newSimulateExchange(name, orderbook.Base{
+		Bids:        []orderbook.Item{{Price: bid, Amount: 10}},
+		Asks:        []orderbook.Item{{Price: ask, Amount: 10}},
+		LastUpdated: updated,
+	}, feeRate)
+}
+
+// venueBalance returns an account info hook holding the amount of a currency
+func venueBalance(currency string, amount float64) func() (exchange.AccountInfo, error) {
+	return func() (exchange.AccountInfo, error) {
+		return exchange.AccountInfo{Accounts: []exchange.Account{{
+			Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency, TotalValue: amount},
+			},
+		}}}, nil
+	}
+}
+
+func venueNames(venues []ExecutionVenue) []string {
+	var names []string
+	for x := range venues {
+		names = append(names, venues[x].Exchange)
+	}
+	return names
+}
+
+func TestGetBestExecutionVenue(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	// Headline has the best prices but its 1% fee makes it the worst venue
+	headline := newVenueExchange("Headline", 99, 100, 0.01, time.Now())
+	value := newVenueExchange("Value", 98.8, 100.5, 0.001, time.Now())
+	// TickerOnly is priced from its ticker as it has no orderbook
+	tickerOnly := newVenueExchange("TickerOnly", 0, 0, 0.005, time.Now())
+	tickerOnly.getOrderbookEx = func(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+		return orderbook.Base{}, errors.New("no orderbook")
+	}
+	tickerOnly.getTickerPrice = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		return ticker.Price{Bid: 98.6, Ask: 100.2, LastUpdated: time.Now()}, nil
+	}
+	stale := newVenueExchange("Stale", 110, 90, 0, time.Now().Add(-time.Hour))
+	bot.exchanges = []exchange.IBotExchange{headline, stale, tickerOnly, value}
+	p := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+
+	result, err := GetBestExecutionVenue(p, "", exchange.Buy, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := venueNames(result.Venues)
+	if result.BestExchange != "Value" || len(names) != 4 || names[0] != "Value" ||
+		names[1] != "TickerOnly" || names[2] != "Headline" || names[3] != "Stale" {
+		t.Fatalf("Test failed. Unexpected buy ranking %v", names)
+	}
+	best := result.Venues[0]
+	if best.PriceSource != priceSourceOrderbook || best.Price != 100.5 ||
+		math.Abs(best.Fee-0.1005) > 1e-9 ||
+		math.Abs(best.EffectiveCost-100.6005) > 1e-9 ||
+		math.Abs(best.EffectivePrice-100.6005) > 1e-9 {
+		t.Errorf("Test failed. Unexpected best venue %+v", best)
+	}
+	if result.Venues[1].PriceSource != priceSourceTicker ||
+		result.Venues[1].Price != 100.2 {
+		t.Errorf("Test failed. Expected a ticker priced venue, got %+v",
+			result.Venues[1])
+	}
+	if result.Venues[3].Error == "" {
+		t.Error("Test failed. Expected the stale venue to be skipped")
+	}
+
+	result, err = GetBestExecutionVenue(p, ticker.Spot, exchange.Sell, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names = venueNames(result.Venues)
+	if result.BestExchange != "Value" || names[0] != "Value" ||
+		names[1] != "TickerOnly" || names[2] != "Headline" {
+		t.Errorf("Test failed. Unexpected sell ranking %v", names)
+	}
+
+	// Balance checks skip venues which can't pay for the order
+	headline.getAccountInfo = venueBalance("USD", 1000)
+	value.getAccountInfo = venueBalance("USD", 50)
+	tickerOnly.getAccountInfo = venueBalance("usd", 101)
+	result, err = GetBestExecutionVenue(p, "", exchange.Buy, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names = venueNames(result.Venues)
+	if result.BestExchange != "TickerOnly" || names[1] != "Headline" ||
+		result.Venues[2].Error == "" || result.Venues[3].Error == "" {
+		t.Errorf("Test failed. Unexpected balance checked ranking %+v", result.Venues)
+	}
+
+	headline.getAccountInfo = venueBalance("BTC", 1)
+	value.getAccountInfo = venueBalance("BTC", 0.5)
+	tickerOnly.getAccountInfo = venueBalance("BTC", 0)
+	result, err = GetBestExecutionVenue(p, "", exchange.Sell, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BestExchange != "Headline" {
+		t.Errorf("Test failed. Unexpected balance checked sell ranking %+v",
+			result.Venues)
+	}
+
+	headline.getAccountInfo = venueBalance("BTC", 0)
+	result, err = GetBestExecutionVenue(p, "", exchange.Sell, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BestExchange != "" || len(result.Venues) != 4 {
+		t.Errorf("Test failed. Expected every venue to be skipped, got %+v",
+			result.Venues)
+	}
+
+	_, err = GetBestExecutionVenue(pair.NewCurrencyPair("ETH", "USD"), "",
+		exchange.Buy, 1, false)
+	if !errors.Is(err, ErrNoExecutionVenue) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrNoExecutionVenue, err)
+	}
+	_, err = GetBestExecutionVenue(p, "quarter", exchange.Buy, 1, false)
+	if !errors.Is(err, ErrNoExecutionVenue) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrNoExecutionVenue, err)
+	}
+	_, err = GetBestExecutionVenue(p, "", exchange.Buy, 0, false)
+	if !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrInvalidOrder, err)
+	}
+}
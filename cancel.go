@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+const (
+	// defaultCancelAllOrdersTimeout is the time each exchange is given to
+	// cancel its orders
+	defaultCancelAllOrdersTimeout = time.Second * 30
+	// maxCancelAllOrdersTimeout is the longest time an exchange can be given
+	// to cancel its orders
+	maxCancelAllOrdersTimeout = time.Minute * 2
+)
+
+var (
+	// ErrCancelAllOrdersNotConfirmed is returned when the orders of every
+	// exchange are cancelled without the request being confirmed
+	ErrCancelAllOrdersNotConfirmed = errors.New("cancelling the orders of every exchange must be confirmed")
+	// ErrCancelAllOrdersTimeout is recorded for an exchange which doesn't
+	// cancel its orders within the timeout
+	ErrCancelAllOrdersTimeout = errors.New("cancel all orders timed out")
+)
+
+// CancelAllOrdersRequest cancels the orders of an exchange, or of every
+// enabled exchange with authenticated API support if the exchange is empty.
+// Cancelling the orders of every exchange must be confirmed
+type CancelAllOrdersRequest struct {
+	Exchange  string `json:"exchange,omitempty"`
+	Account   string `json:"account,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+	AssetType string `json:"assetType,omitempty"`
+	Confirm   bool   `json:"confirm,omitempty"`
+}
+
+// CancelAllOrdersSummary holds the outcome of cancelling the orders of an
+// exchange. Wrappers only report the orders which failed to cancel, Errors
+// holds their status by order ID
+type CancelAllOrdersSummary struct {
+	Exchange  string            `json:"exchange"`
+	Cancelled int               `json:"cancelled"`
+	Failed    int               `json:"failed"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Duration  time.Duration     `json:"duration"`
+}
+
+// CancelAllOrdersResult holds the summaries of each exchange whose orders were
+// cancelled and their totals
+type CancelAllOrdersResult struct {
+	Exchanges       []CancelAllOrdersSummary `json:"exchanges"`
+	Cancelled       int                      `json:"cancelled"`
+	Failed          int                      `json:"failed"`
+	FailedExchanges int                      `json:"failedExchanges"`
+}
+
+// CancelAllExchangeOrders cancels the orders of the requested exchange, or
+// concurrently of every exchange returned by GetAuthAPISupportedExchanges. Each
+// exchange is given the supplied timeout, the default timeout is used if zero.
+// A failing exchange doesn't stop the others, its error is recorded in its
+// summary
+func CancelAllExchangeOrders(req CancelAllOrdersRequest, timeout time.Duration) (CancelAllOrdersResult, error) {
+	if timeout <= 0 {
+		timeout = defaultCancelAllOrdersTimeout
+	}
+	if timeout > maxCancelAllOrdersTimeout {
+		return CancelAllOrdersResult{}, fmt.Errorf("timeout must not exceed %v",
+			maxCancelAllOrdersTimeout)
+	}
+
+	var targets []exchange.IBotExchange
+	if req.Exchange != "" {
+		exch := GetExchangeByName(req.Exchange)
+		if exch == nil {
+			return CancelAllOrdersResult{}, ErrExchangeNotFound
+		}
+		targets = append(targets, exch)
+	} else {
+		if !req.Confirm {
+			return CancelAllOrdersResult{}, ErrCancelAllOrdersNotConfirmed
+		}
+		targets = GetAuthAPISupportedExchanges()
+	}
+
+	result := CancelAllOrdersResult{Exchanges: []CancelAllOrdersSummary{}}
+	summaries := make(chan CancelAllOrdersSummary, len(targets))
+	var wg sync.WaitGroup
+	for x := range targets {
+		wg.Add(1)
+		go func(exch exchange.IBotExchange) {
+			defer wg.Done()
+			summaries <- cancelAllExchangeOrders(exch, req, timeout)
+		}(targets[x])
+	}
+	wg.Wait()
+	close(summaries)
+
+	var refresh bool
+	for summary := range summaries {
+		result.Cancelled += summary.Cancelled
+		result.Failed += summary.Failed
+		if summary.Error != "" {
+			result.FailedExchanges++
+			log.Errorf("Failed to cancel %s orders: %s", summary.Exchange,
+				summary.Error)
+		} else {
+			refresh = true
+		}
+		result.Exchanges = append(result.Exchanges, summary)
+	}
+	sort.Slice(result.Exchanges, func(i, j int) bool {
+		return result.Exchanges[i].Exchange < result.Exchanges[j].Exchange
+	})
+
+	if refresh {
+		RequestBalanceRefresh()
+	}
+	return result, nil
+}
+
+// cancelAllExchangeOrders cancels the orders of an exchange, failing if they
+// aren't cancelled within the timeout
+func cancelAllExchangeOrders(exch exchange.IBotExchange, req CancelAllOrdersRequest, timeout time.Duration) (summary CancelAllOrdersSummary) {
+	summary.Exchange = exch.GetName()
+	start := time.Now()
+	defer func() {
+		summary.Duration = time.Since(start)
+	}()
+
+	if !exch.GetAuthenticatedAPISupport() {
+		summary.Error = exchange.ErrAuthenticationNotConfigured.Error()
+		return summary
+	}
+
+	cancellation := exchange.OrderCancellation{AssetType: req.AssetType}
+	if req.Currency != "" {
+		p, err := ParseExchangePair(exch.GetName(), req.Currency)
+		if err != nil {
+			summary.Error = err.Error()
+			return summary
+		}
+		cancellation.CurrencyPair = p
+	}
+
+	type cancelled struct {
+		resp exchange.CancelAllOrdersResponse
+		err  error
+	}
+	done := make(chan cancelled, 1)
+	go func() {
+		var c cancelled
+		var accountExch exchange.IBotExchange
+		accountExch, c.err = GetAccountExchange(exch, req.Account)
+		if c.err == nil {
+			c.resp, c.err = accountExch.CancelAllOrders(cancellation)
+		}
+		done <- c
+	}()
+
+	var c cancelled
+	select {
+	case c = <-done:
+	case <-time.After(timeout):
+		c.err = ErrCancelAllOrdersTimeout
+	}
+
+	for id, status := range c.resp.OrderStatus {
+		if isOrderCancelledStatus(status) {
+			summary.Cancelled++
+			continue
+		}
+		summary.Failed++
+		if summary.Errors == nil {
+			summary.Errors = make(map[string]string)
+		}
+		summary.Errors[id] = status
+	}
+	if c.err != nil {
+		summary.Error = c.err.Error()
+	}
+	return summary
+}
+
+// isOrderCancelledStatus returns whether an order status reported by a
+// CancelAllOrders wrapper is a successful cancellation
+func isOrderCancelledStatus(status string) bool {
+	switch common.StringToLower(status) {
+	case "", "cancelled", "canceled", "success":
+		return true
+	}
+	return false
+}
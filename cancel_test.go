@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// newCancelExchange returns a test exchange cancelling its orders after the
+// delay, reporting the order statuses and error
+func newCancelExchange(name string, delay time.Duration, status map[string]string, err error) *testExchange {
+	exch := newTestExchange(name)
+	exch.availablePairs = nil
+	exch.cancelAllOrders = func(orders exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+		time.Sleep(delay)
+		return exchange.CancelAllOrdersResponse{OrderStatus: status}, err
+	}
+	return exch
+}
+
+func TestCancelAllExchangeOrders(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	ok := newCancelExchange("Ok", 0, map[string]string{
+		"1": "cancelled",
+		"2": "Order could not be cancelled",
+	}, nil)
+	var cancelledPair pair.CurrencyPair
+	cancelAllOrders := ok.cancelAllOrders
+	ok.cancelAllOrders = func(orders exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+		cancelledPair = orders.CurrencyPair
+		return cancelAllOrders(orders)
+	}
+	failed := newCancelExchange("Failed", 0,
+		map[string]string{"3": "Could not cancel order"}, errors.New("invalid nonce"))
+	const slowDelay = time.Millisecond * 200
+	slow := newCancelExchange("Slow", slowDelay, nil, nil)
+	noAuth := newCancelExchange("NoAuth", 0, nil, nil)
+	noAuth.base.AuthenticatedAPISupport = false
+	disabled := newCancelExchange("Disabled", 0, nil, nil)
+	disabled.base.Enabled = false
+	bot.exchanges = []exchange.IBotExchange{ok, failed, slow, noAuth, disabled}
+
+	_, err := CancelAllExchangeOrders(CancelAllOrdersRequest{}, 0)
+	if err != ErrCancelAllOrdersNotConfirmed {
+		t.Errorf("Test failed. Expected an unconfirmed error, got %v", err)
+	}
+	_, err = CancelAllExchangeOrders(CancelAllOrdersRequest{Exchange: "Blah"}, 0)
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected an exchange not found error, got %v", err)
+	}
+	_, err = CancelAllExchangeOrders(CancelAllOrdersRequest{Confirm: true},
+		maxCancelAllOrdersTimeout+time.Second)
+	if err == nil {
+		t.Error("Test failed. Expected an invalid timeout error")
+	}
+
+	start := time.Now()
+	result, err := CancelAllExchangeOrders(CancelAllOrdersRequest{Confirm: true,
+		Currency: "BTCUSD"}, time.Millisecond*50)
+	if err != nil {
+		t.Fatalf("Test failed. CancelAllExchangeOrders error: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed >= slowDelay {
+		t.Errorf("Test failed. Expected the slow exchange to time out, took %v", elapsed)
+	}
+	if len(result.Exchanges) != 3 || result.Cancelled != 1 || result.Failed != 2 ||
+		result.FailedExchanges != 2 {
+		t.Fatalf("Test failed. Unexpected result %+v", result)
+	}
+
+	// Summaries are sorted by exchange name
+	summaries := result.Exchanges
+	if summaries[0].Exchange != "Failed" || summaries[0].Error != "invalid nonce" ||
+		summaries[0].Failed != 1 || summaries[0].Errors["3"] != "Could not cancel order" {
+		t.Errorf("Test failed. Unexpected failed exchange summary %+v", summaries[0])
+	}
+	if summaries[1].Exchange != "Ok" || summaries[1].Error != "" ||
+		summaries[1].Cancelled != 1 || summaries[1].Failed != 1 ||
+		summaries[1].Errors["2"] != "Order could not be cancelled" {
+		t.Errorf("Test failed. Unexpected exchange summary %+v", summaries[1])
+	}
+	if summaries[2].Exchange != "Slow" ||
+		summaries[2].Error != ErrCancelAllOrdersTimeout.Error() {
+		t.Errorf("Test failed. Unexpected timed out exchange summary %+v", summaries[2])
+	}
+	if cancelledPair.Pair() != pair.NewCurrencyPair("BTC", "USD").Pair() {
+		t.Errorf("Test failed. Expected the exchange pair to be cancelled, got %s",
+			cancelledPair.Pair())
+	}
+	if noAuth.callCount("CancelAllOrders") != 0 || disabled.callCount("CancelAllOrders") != 0 {
+		t.Error("Test failed. Expected exchanges without authenticated support to be skipped")
+	}
+
+	result, err = CancelAllExchangeOrders(CancelAllOrdersRequest{Exchange: "noauth"}, 0)
+	if err != nil || len(result.Exchanges) != 1 || result.FailedExchanges != 1 ||
+		result.Exchanges[0].Error != exchange.ErrAuthenticationNotConfigured.Error() {
+		t.Errorf("Test failed. Unexpected result without authenticated support %+v %v",
+			result, err)
+	}
+}
+
+func TestRESTCancelAllOrders(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	exch := newCancelExchange("Ok", 0, map[string]string{"1": "failed"}, nil)
+	bot.exchanges = []exchange.IBotExchange{exch}
+
+	router := NewRouter()
+	send := func(path, body string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := send("/orders/cancelall", `{"confirm":true}`, false)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	for _, test := range []struct {
+		path, body string
+		code       int
+	}{
+		{"/orders/cancelall", `{}`, http.StatusBadRequest},
+		{"/orders/cancelall", `{"confirm":"yes"}`, http.StatusBadRequest},
+		{"/orders/cancelall", `{"exchange":"Blah"}`, http.StatusNotFound},
+		{"/orders/cancelall?timeout=soon", `{"exchange":"ok"}`, http.StatusBadRequest},
+		{"/orders/cancelall?timeout=1h", `{"exchange":"ok"}`, http.StatusBadRequest},
+		{"/orders/cancelall?timeout=1m", `{"exchange":"ok"}`, http.StatusOK},
+	} {
+		w = send(test.path, test.body, true)
+		if w.Code != test.code {
+			t.Errorf("Test failed. %s %s expected status %d, got %d", test.path,
+				test.body, test.code, w.Code)
+		}
+	}
+
+	w = send("/orders/cancelall", `{"confirm":true}`, true)
+	var result CancelAllOrdersResult
+	err := common.JSONDecode(w.Body.Bytes(), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Exchanges) != 1 || result.Failed != 1 || result.FailedExchanges != 0 {
+		t.Errorf("Test failed. Unexpected result %+v", result)
+	}
+}
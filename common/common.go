@@ -1,16 +1,21 @@
 package common
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"hash"
@@ -43,6 +48,9 @@ var (
 	// ErrFunctionNotSupported defines a standardised error for an unsupported
 	// wrapper function by an API
 	ErrFunctionNotSupported = errors.New("Unsupported Wrapper Function")
+
+	// ErrInvalidURL is returned by ValidateURL for a URL which can't be used
+	ErrInvalidURL = errors.New("invalid URL")
 )
 
 // Const declarations for common.go operations
@@ -143,6 +151,44 @@ func GetHMAC(hashType int, input, key []byte) []byte {
 	return hmac.Sum(nil)
 }
 
+// ParsePEMPrivateKey decodes a PEM encoded ECDSA or RSA private key. SEC 1
+// ("EC PRIVATE KEY"), PKCS #1 ("RSA PRIVATE KEY") and PKCS #8 ("PRIVATE KEY")
+// blocks are supported
+func ParsePEMPrivateKey(pemKey string) (crypto.Signer, error) {
+	if pemKey == "" {
+		return nil, errors.New("PEM key not set")
+	}
+
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("unable to decode PEM block")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return k, nil
+	case *rsa.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", key)
+}
+
 // Sha1ToHex takes a string, sha1 hashes it and return a hex string of the
 // result
 func Sha1ToHex(data string) string {
@@ -302,15 +348,16 @@ func IsEnabled(isEnabled bool) string {
 
 // IsValidCryptoAddress validates your cryptocurrency address string using the
 // regexp package // Validation issues occurring because "3" is contained in
-// litecoin and Bitcoin addresses - non-fatal
+// litecoin and Bitcoin addresses - non-fatal. Bech32 BTC and LTC addresses and
+// mixed case checksummed ETH addresses are accepted
 func IsValidCryptoAddress(address, crypto string) (bool, error) {
 	switch StringToLower(crypto) {
 	case "btc":
-		return regexp.MatchString("^[13][a-km-zA-HJ-NP-Z1-9]{25,34}$", address)
+		return regexp.MatchString("^([13][a-km-zA-HJ-NP-Z1-9]{25,34}|bc1[ac-hj-np-z02-9]{39,59})$", address)
 	case "ltc":
-		return regexp.MatchString("^[L3M][a-km-zA-HJ-NP-Z1-9]{25,34}$", address)
+		return regexp.MatchString("^([L3M][a-km-zA-HJ-NP-Z1-9]{25,34}|ltc1[ac-hj-np-z02-9]{39,59})$", address)
 	case "eth":
-		return regexp.MatchString("^0x[a-km-z0-9]{40}$", address)
+		return regexp.MatchString("^0x[a-fA-F0-9]{40}$", address)
 	default:
 		return false, errors.New("Invalid crypto currency")
 	}
@@ -449,6 +496,30 @@ func EncodeURLValues(url string, values url.Values) string {
 	return path
 }
 
+// ValidateURL checks a URL parses, has a host and uses one of the supplied
+// schemes
+func ValidateURL(rawURL string, schemes ...string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, err)
+	}
+	supported := false
+	for x := range schemes {
+		if strings.EqualFold(u.Scheme, schemes[x]) {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%w %q: scheme must be %s", ErrInvalidURL, rawURL,
+			JoinStrings(schemes, " or "))
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w %q: host not set", ErrInvalidURL, rawURL)
+	}
+	return nil
+}
+
 // ExtractHost returns the hostname out of a string
 func ExtractHost(address string) string {
 	host := SplitStrings(address, ":")[0]
@@ -525,6 +596,38 @@ func WriteFile(file string, data []byte) error {
 	return nil
 }
 
+// WriteFileAtomic writes data to a temporary file in the same directory and
+// renames it over the file, so the file is never left partially written. The
+// permissions of an existing file are kept
+func WriteFileAtomic(file string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(file); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
 // RemoveFile removes a file
 func RemoveFile(file string) error {
 	return os.Remove(file)
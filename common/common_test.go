@@ -2,8 +2,18 @@ package common
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -69,6 +79,22 @@ func TestIsValidCryptoAddress(t *testing.T) {
 	if err == nil && b {
 		t.Error("Test Failed - Common IsValidCryptoAddress error")
 	}
+
+	for address, crypto := range map[string]string{
+		"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq":  "btc",
+		"ltc1qg82tf5mlxjghjcwjacxu2mu5r0ppmhwe2el7ln": "ltc",
+		"0x52908400098527886E0F7030069857D2E4169EE7":  "eth",
+	} {
+		b, err = IsValidCryptoAddress(address, crypto)
+		if err != nil || !b {
+			t.Errorf("Test Failed - Common IsValidCryptoAddress rejected %s address %s",
+				crypto, address)
+		}
+	}
+	b, _ = IsValidCryptoAddress("0x52908400098527886E0F7030069857D2E4169EG7", "eth")
+	if b {
+		t.Error("Test Failed - Common IsValidCryptoAddress accepted a non hex address")
+	}
 }
 
 func TestGetRandomSalt(t *testing.T) {
@@ -197,6 +223,51 @@ func TestGetHMAC(t *testing.T) {
 
 }
 
+func TestParsePEMPrivateKey(t *testing.T) {
+	t.Parallel()
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]*pem.Block{
+		"EC":    {Type: "EC PRIVATE KEY", Bytes: ecDER},
+		"PKCS8": {Type: "PRIVATE KEY", Bytes: pkcs8DER},
+		"RSA":   {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+	}
+	for name, block := range valid {
+		key, err := ParsePEMPrivateKey(string(pem.EncodeToMemory(block)))
+		if err != nil || key == nil {
+			t.Errorf("Test failed. ParsePEMPrivateKey %s: %v", name, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"not a pem key",
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ecDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("junk")})),
+	}
+	for x := range invalid {
+		_, err = ParsePEMPrivateKey(invalid[x])
+		if err == nil {
+			t.Errorf("Test failed. ParsePEMPrivateKey expected error for input #%d", x)
+		}
+	}
+}
+
 func TestSha1Tohex(t *testing.T) {
 	t.Parallel()
 	expectedResult := "fcfbfcd7d31d994ef660f6972399ab5d7a890149"
@@ -677,6 +748,23 @@ func TestEncodeURLValues(t *testing.T) {
 	}
 }
 
+func TestValidateURL(t *testing.T) {
+	t.Parallel()
+	for _, rawURL := range []string{"https://www.bitmex.com/api/v1", "HTTP://localhost:8080"} {
+		if err := ValidateURL(rawURL, "http", "https"); err != nil {
+			t.Errorf("Test Failed - common ValidateURL %s error: %s", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{"", "garbage", "wss://www.bitmex.com/realtime",
+		"https://", "https://bitmex.com:port", "https//bitmex.com"} {
+		err := ValidateURL(rawURL, "http", "https")
+		if !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("Test Failed - common ValidateURL %q expected %v, got %v", rawURL,
+				ErrInvalidURL, err)
+		}
+	}
+}
+
 func TestExtractHost(t *testing.T) {
 	t.Parallel()
 	address := "localhost:1337"
@@ -793,6 +881,44 @@ func TestWriteFile(t *testing.T) {
 	}
 }
 
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writefileatomic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(path, []byte("old"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteFileAtomic(path, []byte("new"))
+	if err != nil {
+		t.Fatalf("Test failed. Common WriteFileAtomic error: %s", err)
+	}
+	data, err := ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("Test failed. Common WriteFileAtomic wrote %q: %v", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Test failed. Common WriteFileAtomic changed the file mode: %v %v",
+			info.Mode(), err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil || len(files) != 1 {
+		t.Errorf("Test failed. Common WriteFileAtomic left temporary files: %d %v",
+			len(files), err)
+	}
+
+	err = WriteFileAtomic(filepath.Join(dir, "missing", "config.json"), nil)
+	if err == nil {
+		t.Error("Test failed. Common WriteFileAtomic allowed bad path")
+	}
+}
+
 func TestRemoveFile(t *testing.T) {
 	TestWriteFile(t)
 	path := "../testdata/writefiletest"
@@ -6,6 +6,7 @@ import (
 	"github.com/thrasher-/gocryptotrader/communications/smsglobal"
 	"github.com/thrasher-/gocryptotrader/communications/smtpservice"
 	"github.com/thrasher-/gocryptotrader/communications/telegram"
+	"github.com/thrasher-/gocryptotrader/communications/webhook"
 	"github.com/thrasher-/gocryptotrader/config"
 )
 
@@ -42,6 +43,12 @@ func NewComm(config config.CommunicationsConfig) *Communications {
 		comm.IComm = append(comm.IComm, Slack)
 	}
 
+	if config.WebhookConfig.Enabled {
+		Webhook := new(webhook.Webhook)
+		Webhook.Setup(config)
+		comm.IComm = append(comm.IComm, Webhook)
+	}
+
 	comm.Setup()
 	return &comm
 }
@@ -19,10 +19,11 @@ func TestNewComm(t *testing.T) {
 	config.SMSGlobalConfig.Enabled = true
 	config.SMTPConfig.Enabled = true
 	config.SlackConfig.Enabled = true
+	config.WebhookConfig.Enabled = true
 	communications = NewComm(config)
 
-	if len(communications.IComm) != 4 {
-		t.Errorf("Test failed, communications NewComm, expected len 4, got len %d",
+	if len(communications.IComm) != 5 {
+		t.Errorf("Test failed, communications NewComm, expected len 5, got len %d",
 			len(communications.IComm))
 	}
 }
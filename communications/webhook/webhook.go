@@ -0,0 +1,201 @@
+// Package webhook posts communication events as JSON to user defined HTTP
+// endpoints, for home automation, custom dashboards or alerting receivers
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/config"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+const (
+	// SignatureHeader holds the hex encoded HMAC-SHA256 of the request body,
+	// prefixed with "sha256=", when a signature secret is configured
+	SignatureHeader = "X-GCT-Signature"
+
+	defaultTimeout      = time.Second * 10
+	defaultRetryBackoff = time.Second
+	queueSize           = 100
+)
+
+var errQueueFull = errors.New("webhook delivery queue is full")
+
+// Webhook posts events to its endpoints. Each endpoint is delivered to by its
+// own routine so a slow or failing endpoint never blocks pushing events
+type Webhook struct {
+	base.Base
+	Endpoints       []*Endpoint
+	Timeout         time.Duration
+	Retries         int
+	SignatureSecret string
+	// RetryBackoff is the delay before the first retry of a failed delivery,
+	// it doubles with each further retry
+	RetryBackoff time.Duration
+
+	client *http.Client
+}
+
+// Setup takes in a webhook configuration and sets the endpoints, request
+// timeout, retry count and signature secret
+func (w *Webhook) Setup(config config.CommunicationsConfig) {
+	w.Name = config.WebhookConfig.Name
+	w.Enabled = config.WebhookConfig.Enabled
+	w.Verbose = config.WebhookConfig.Verbose
+	w.Timeout = config.WebhookConfig.Timeout
+	w.Retries = config.WebhookConfig.Retries
+	w.SignatureSecret = config.WebhookConfig.SignatureSecret
+	w.RetryBackoff = defaultRetryBackoff
+
+	w.Endpoints = nil
+	for x := range config.WebhookConfig.Endpoints {
+		w.Endpoints = append(w.Endpoints, &Endpoint{
+			URL:     config.WebhookConfig.Endpoints[x].URL,
+			Headers: config.WebhookConfig.Endpoints[x].Headers,
+			Events:  config.WebhookConfig.Endpoints[x].Events,
+		})
+	}
+}
+
+// Connect starts the delivery routine of each endpoint
+func (w *Webhook) Connect() error {
+	if w.Connected {
+		return nil
+	}
+	if len(w.Endpoints) == 0 {
+		return errors.New("webhook has no endpoints")
+	}
+
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	w.client = &http.Client{Timeout: timeout}
+	for x := range w.Endpoints {
+		w.Endpoints[x].queue = make(chan []byte, queueSize)
+		go w.deliver(w.Endpoints[x])
+	}
+	w.Connected = true
+	return nil
+}
+
+// PushEvent queues an event for delivery to the endpoints accepting its type.
+// An error is returned when an endpoint queue is full and the event was
+// dropped for it
+func (w *Webhook) PushEvent(event base.Event) error {
+	body, err := json.Marshal(Payload{
+		Type:      event.Type,
+		GainLoss:  event.GainLoss,
+		Details:   event.TradeDetails,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var dropped []string
+	for x := range w.Endpoints {
+		if !w.Endpoints[x].Accepts(event.Type) {
+			continue
+		}
+		select {
+		case w.Endpoints[x].queue <- body:
+		default:
+			dropped = append(dropped, w.Endpoints[x].URL)
+		}
+	}
+	if len(dropped) > 0 {
+		return fmt.Errorf("%w, dropped %s event for %s", errQueueFull, event.Type,
+			common.JoinStrings(dropped, ", "))
+	}
+	return nil
+}
+
+// Accepts returns whether the endpoint is delivered events of the type, all
+// event types are delivered when the endpoint has no event filter
+func (e *Endpoint) Accepts(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for x := range e.Events {
+		if common.StringToLower(e.Events[x]) == common.StringToLower(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature header value of a request body
+func (w *Webhook) Sign(body []byte) string {
+	return "sha256=" + common.HexEncodeToString(
+		common.GetHMAC(common.HashSHA256, body, []byte(w.SignatureSecret)))
+}
+
+// deliver posts the queued events of an endpoint
+func (w *Webhook) deliver(e *Endpoint) {
+	for body := range e.queue {
+		err := w.send(e, body)
+		if err != nil {
+			log.Errorf("Communications: %s delivery to %s failed after %d attempts: %s",
+				w.Name, e.URL, w.Retries+1, err)
+		}
+	}
+}
+
+// send posts an event body to an endpoint, retrying failed requests with an
+// increasing backoff
+func (w *Webhook) send(e *Endpoint, body []byte) error {
+	var err error
+	for attempt := 0; attempt <= w.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(w.RetryBackoff << uint(attempt-1))
+		}
+		err = w.post(e, body)
+		if err == nil {
+			if w.Verbose {
+				log.Debugf("Communications: %s delivered event to %s", w.Name, e.URL)
+			}
+			return nil
+		}
+		if w.Verbose {
+			log.Debugf("Communications: %s delivery attempt %d to %s failed: %s",
+				w.Name, attempt+1, e.URL, err)
+		}
+	}
+	return err
+}
+
+// post makes a single delivery request to an endpoint
+func (w *Webhook) post(e *Endpoint, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.SignatureSecret != "" {
+		req.Header.Set(SignatureHeader, w.Sign(body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// request holds a delivery received by the test server
+type request struct {
+	path      string
+	header    http.Header
+	body      []byte
+	signature string
+}
+
+// newTestServer returns a server recording its deliveries and the number of
+// requests made, the first failures requests are answered with an internal
+// server error
+func newTestServer(failures int32) (*httptest.Server, chan request, *int32) {
+	received := make(chan request, 10)
+	attempts := new(int32)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- request{path: r.URL.Path, header: r.Header, body: body,
+			signature: r.Header.Get(SignatureHeader)}
+	}))
+	return srv, received, attempts
+}
+
+func newTestWebhook(endpoints ...config.WebhookEndpoint) *Webhook {
+	var w Webhook
+	w.Setup(config.CommunicationsConfig{WebhookConfig: config.WebhookConfig{
+		Name:      "Webhook",
+		Enabled:   true,
+		Endpoints: endpoints,
+		Timeout:   time.Second,
+		Retries:   2,
+	}})
+	w.RetryBackoff = time.Millisecond
+	return &w
+}
+
+func waitForRequest(t *testing.T, received chan request) request {
+	select {
+	case r := <-received:
+		return r
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. Timed out waiting for a webhook delivery")
+	}
+	return request{}
+}
+
+func TestSetup(t *testing.T) {
+	var w Webhook
+	w.Setup(config.CommunicationsConfig{WebhookConfig: config.WebhookConfig{
+		Name:      "Webhook",
+		Enabled:   true,
+		Endpoints: []config.WebhookEndpoint{{URL: "http://localhost", Events: []string{"deposit"}}},
+		Retries:   3,
+	}})
+	if w.Name != "Webhook" || !w.Enabled || w.Retries != 3 || len(w.Endpoints) != 1 ||
+		w.Endpoints[0].URL != "http://localhost" {
+		t.Errorf("Test failed. Unexpected setup values %+v", w)
+	}
+
+	w.Endpoints = nil
+	if err := w.Connect(); err == nil {
+		t.Error("Test failed. Expected an error connecting without endpoints")
+	}
+}
+
+func TestPushEvent(t *testing.T) {
+	srv, received, _ := newTestServer(0)
+	defer srv.Close()
+
+	w := newTestWebhook(config.WebhookEndpoint{URL: srv.URL + "/events",
+		Headers: map[string]string{"Authorization": "Bearer token"}})
+	err := w.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.PushEvent(base.Event{Type: "order_fill", GainLoss: "1.5",
+		TradeDetails: "Bought 1 BTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := waitForRequest(t, received)
+	if r.path != "/events" || r.header.Get("Authorization") != "Bearer token" ||
+		r.header.Get("Content-Type") != "application/json" || r.signature != "" {
+		t.Errorf("Test failed. Unexpected request %+v", r)
+	}
+	var payload Payload
+	err = common.JSONDecode(r.body, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Type != "order_fill" || payload.GainLoss != "1.5" ||
+		payload.Details != "Bought 1 BTC" || payload.Timestamp.IsZero() {
+		t.Errorf("Test failed. Unexpected payload %+v", payload)
+	}
+}
+
+func TestPushEventRetry(t *testing.T) {
+	srv, received, attempts := newTestServer(2)
+	defer srv.Close()
+
+	w := newTestWebhook(config.WebhookEndpoint{URL: srv.URL})
+	err := w.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.PushEvent(base.Event{Type: "deposit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForRequest(t, received)
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("Test failed. Expected 3 delivery attempts, got %d", n)
+	}
+
+	// The delivery is given up after the retries
+	failing, _, attempts := newTestServer(100)
+	defer failing.Close()
+	err = w.send(&Endpoint{URL: failing.URL}, []byte("{}"))
+	if err == nil {
+		t.Error("Test failed. Expected a failed delivery")
+	}
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("Test failed. Expected 3 delivery attempts, got %d", n)
+	}
+}
+
+func TestPushEventFilter(t *testing.T) {
+	srv, received, _ := newTestServer(0)
+	defer srv.Close()
+
+	w := newTestWebhook(
+		config.WebhookEndpoint{URL: srv.URL + "/fills", Events: []string{"ORDER_FILL"}},
+		config.WebhookEndpoint{URL: srv.URL + "/all"})
+	err := w.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.PushEvent(base.Event{Type: "deposit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := waitForRequest(t, received); r.path != "/all" {
+		t.Errorf("Test failed. Deposit delivered to %s", r.path)
+	}
+	select {
+	case r := <-received:
+		t.Errorf("Test failed. Unexpected deposit delivery to %s", r.path)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	err = w.PushEvent(base.Event{Type: "order_fill"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]bool{waitForRequest(t, received).path: true,
+		waitForRequest(t, received).path: true}
+	if !paths["/fills"] || !paths["/all"] {
+		t.Errorf("Test failed. Unexpected order fill deliveries %v", paths)
+	}
+}
+
+func TestPushEventQueueFull(t *testing.T) {
+	w := newTestWebhook(config.WebhookEndpoint{URL: "http://localhost"})
+	w.Endpoints[0].queue = make(chan []byte)
+	err := w.PushEvent(base.Event{Type: "deposit"})
+	if !errors.Is(err, errQueueFull) {
+		t.Errorf("Test failed. Expected %v, got %v", errQueueFull, err)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	srv, received, _ := newTestServer(0)
+	defer srv.Close()
+
+	w := newTestWebhook(config.WebhookEndpoint{URL: srv.URL})
+	w.SignatureSecret = "secret"
+	expected := "sha256=92fdf1f8136b8f652385aaa6e3fdd4143f78c67efc459a86d85812f91304d8af"
+	if sig := w.Sign([]byte(`{"type":"deposit"}`)); sig != expected {
+		t.Errorf("Test failed. Expected signature %s, got %s", expected, sig)
+	}
+	err := w.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.PushEvent(base.Event{Type: "deposit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := waitForRequest(t, received)
+	expected = "sha256=" + common.HexEncodeToString(
+		common.GetHMAC(common.HashSHA256, r.body, []byte("secret")))
+	if r.signature != expected {
+		t.Errorf("Test failed. Expected signature %s, got %s", expected, r.signature)
+	}
+}
@@ -0,0 +1,21 @@
+package webhook
+
+import "time"
+
+// Payload is the JSON body posted to the webhook endpoints for each event
+type Payload struct {
+	Type      string    `json:"type"`
+	GainLoss  string    `json:"gainLoss,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Endpoint holds a URL events are posted to, the headers sent with each
+// request and the event types delivered to it
+type Endpoint struct {
+	URL     string
+	Headers map[string]string
+	Events  []string
+
+	queue chan []byte
+}
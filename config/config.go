@@ -9,6 +9,7 @@ import (
 	"path"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/thrasher-/gocryptotrader/currency/forexprovider"
 	"github.com/thrasher-/gocryptotrader/currency/forexprovider/base"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	log "github.com/thrasher-/gocryptotrader/logger"
 	"github.com/thrasher-/gocryptotrader/portfolio"
 )
@@ -32,7 +34,27 @@ const (
 	configFileEncryptionDisabled           = -1
 	configPairsLastUpdatedWarningThreshold = 30 // 30 days
 	configDefaultHTTPTimeout               = time.Second * 15
+	configDefaultExchangeStartupTimeout    = time.Second * 30
+	configDefaultPairUpdateInterval        = time.Hour * 24
+	configDefaultBalanceRefreshInterval    = time.Minute * 10
+	configDefaultPortfolioRefreshInterval  = time.Minute * 10
+	configDefaultMaintenanceProbeInterval  = time.Minute * 5
+	configDefaultMarketDataMaxAge          = time.Hour * 24
+	configDefaultLatencyWarningThreshold   = time.Second * 5
+	configDefaultLatencyWarningWindows     = 3
+	configDefaultRequestLogLevel           = "DEBUG"
+	configDefaultSlowRequestThreshold      = time.Second * 5
+	configDefaultWebsocketAuthTimeout      = time.Minute
+	configDefaultWebhookTimeout            = time.Second * 10
+	configDefaultWebhookRetries            = 3
+	configDefaultWebsocketSendQueueSize    = 1024
 	configMaxAuthFailres                   = 3
+	configDefaultWebsocketReconnectInitial = time.Second * 3
+	configDefaultWebsocketReconnectMax     = time.Minute * 5
+	configDefaultWebsocketReconnectStable  = time.Minute
+	configDefaultWebsocketFailureThreshold = 5
+	configDefaultEventsCheckInterval       = time.Second * 10
+	configDefaultEventsMinEvaluation       = time.Millisecond * 250
 )
 
 // Constants here hold some messages
@@ -42,6 +64,7 @@ const (
 	ErrExchangeEnabledPairsEmpty                    = "Exchange %s: Enabled pairs is empty."
 	ErrExchangeBaseCurrenciesEmpty                  = "Exchange %s: Base currencies is empty."
 	ErrExchangeNotFound                             = "Exchange %s: Not found."
+	ErrExchangeAssetTypeNotSupported                = "Exchange %s: Enabled asset type %s is not one of the supported asset types %s."
 	ErrNoEnabledExchanges                           = "No Exchanges enabled."
 	ErrCryptocurrenciesEmpty                        = "Cryptocurrencies variable is empty."
 	ErrFailureOpeningConfig                         = "Fatal error opening %s file. Error: %s"
@@ -54,10 +77,21 @@ const (
 	WarningWebserverListenAddressInvalid            = "WARNING -- Webserver support disabled due to invalid listen address."
 	WarningWebserverRootWebFolderNotFound           = "WARNING -- Webserver support disabled due to missing web folder."
 	WarningExchangeAuthAPIDefaultOrEmptyValues      = "WARNING -- Exchange %s: Authenticated API support disabled due to default/empty APIKey/Secret/ClientID values."
+	WarningExchangeAuthAPIPEMKeyInvalid             = "WARNING -- Exchange %s: Authenticated API support disabled due to missing or invalid PEM key: %s."
 	WarningCurrencyExchangeProvider                 = "WARNING -- Currency exchange provider invalid valid. Reset to Fixer."
 	WarningPairsLastUpdatedThresholdExceeded        = "WARNING -- Exchange %s: Last manual update of available currency pairs has exceeded %d days. Manual update required!"
+	WarningExchangeAccountDefaultOrEmptyValues      = "WARNING -- Exchange %s: Account %q disabled due to default/empty APIKey/Secret values."
+	WarningNoEnabledExchanges                       = "WARNING -- No exchanges enabled, running in portfolio only mode."
+	ErrExchangeAccountLabelInvalid                  = "Exchange %s: Account #%d label %q is empty, duplicated or contains %q."
+	ErrExchangeCurrencyDetailsInvalid               = "Exchange %s: Currency details #%d for %q must have a unique currency and no negative values."
+	ErrExchangePairFormatIndexUnknown               = "Exchange %s: Pair format index %q is not a known currency."
+	ErrExchangePairInvalid                          = "Exchange %s: Pair %q is invalid, %s."
 )
 
+// AccountLabelSeparator separates an exchange name from a sub-account label
+// when the two are namespaced together, e.g. "OKEX:hedge"
+const AccountLabelSeparator = ":"
+
 // Constants here define unset default values displayed in the config.json
 // file
 const (
@@ -76,15 +110,37 @@ var (
 	m              sync.Mutex
 )
 
+// PortfolioOnly allows running without enabled exchanges for this run only,
+// without changing the config file setting
+var PortfolioOnly bool
+
 // WebserverConfig struct holds the prestart variables for the webserver.
 type WebserverConfig struct {
-	Enabled                      bool   `json:"enabled"`
-	AdminUsername                string `json:"adminUsername"`
-	AdminPassword                string `json:"adminPassword"`
-	ListenAddress                string `json:"listenAddress"`
-	WebsocketConnectionLimit     int    `json:"websocketConnectionLimit"`
-	WebsocketMaxAuthFailures     int    `json:"websocketMaxAuthFailures"`
-	WebsocketAllowInsecureOrigin bool   `json:"websocketAllowInsecureOrigin"`
+	Enabled                      bool     `json:"enabled"`
+	AdminUsername                string   `json:"adminUsername"`
+	AdminPassword                string   `json:"adminPassword"`
+	ListenAddress                string   `json:"listenAddress"`
+	WebsocketConnectionLimit     int      `json:"websocketConnectionLimit"`
+	WebsocketMaxAuthFailures     int      `json:"websocketMaxAuthFailures"`
+	WebsocketAllowInsecureOrigin bool     `json:"websocketAllowInsecureOrigin"`
+	TLSEnabled                   bool     `json:"tlsEnabled"`
+	TLSHostnames                 []string `json:"tlsHostnames,omitempty"`
+	// RequestLogLevel is the level requests are logged at, DEBUG, INFO or
+	// WARN
+	RequestLogLevel string `json:"requestLogLevel,omitempty"`
+	// SlowRequestThreshold is the duration after which a request is logged
+	// as slow
+	SlowRequestThreshold time.Duration `json:"slowRequestThreshold,omitempty"`
+	// DebugVarsEnabled exposes the expvar variables, including the request
+	// stats, on /debug/vars
+	DebugVarsEnabled bool `json:"debugVarsEnabled,omitempty"`
+	// WebsocketAuthTimeout is the time a websocket client has to
+	// authenticate before it is disconnected, clients aren't disconnected if
+	// negative
+	WebsocketAuthTimeout time.Duration `json:"websocketAuthTimeout,omitempty"`
+	// WebsocketSendQueueSize is the number of messages queued for a
+	// websocket client, messages are dropped while it is full
+	WebsocketSendQueueSize int `json:"websocketSendQueueSize,omitempty"`
 }
 
 // Post holds the bot configuration data
@@ -98,22 +154,42 @@ type CurrencyPairFormatConfig struct {
 	Delimiter string `json:"delimiter,omitempty"`
 	Separator string `json:"separator,omitempty"`
 	Index     string `json:"index,omitempty"`
+	// QuoteFirst is set if the exchange lists pairs in its requests quote
+	// currency first, e.g. USD-BTC
+	QuoteFirst bool `json:"quoteFirst,omitempty"`
 }
 
 // Config is the overarching object that holds all the information for
 // prestart management of Portfolio, Communications, Webserver and Enabled
 // Exchanges
 type Config struct {
-	Name              string               `json:"name"`
-	EncryptConfig     int                  `json:"encryptConfig"`
-	GlobalHTTPTimeout time.Duration        `json:"globalHTTPTimeout"`
-	Logging           log.Logging          `json:"logging"`
-	Currency          CurrencyConfig       `json:"currencyConfig"`
-	Communications    CommunicationsConfig `json:"communications"`
-	Portfolio         portfolio.Base       `json:"portfolioAddresses"`
-	Webserver         WebserverConfig      `json:"webserver"`
-	Exchanges         []ExchangeConfig     `json:"exchanges"`
-	BankAccounts      []BankAccount        `json:"bankAccounts"`
+	ConfigVersion            int                      `json:"configVersion"`
+	Name                     string                   `json:"name"`
+	EncryptConfig            int                      `json:"encryptConfig"`
+	GlobalHTTPTimeout        time.Duration            `json:"globalHTTPTimeout"`
+	ExchangeStartupTimeout   time.Duration            `json:"exchangeStartupTimeout"`
+	PairUpdateInterval       time.Duration            `json:"pairUpdateInterval"`
+	NotifyNewPairs           bool                     `json:"notifyNewPairs,omitempty"`
+	BalanceRefreshInterval   time.Duration            `json:"balanceRefreshInterval"`
+	BalanceChangeThreshold   float64                  `json:"balanceChangeThreshold,omitempty"`
+	NotifyDeposits           bool                     `json:"notifyDeposits,omitempty"`
+	MaintenanceProbeInterval time.Duration            `json:"maintenanceProbeInterval"`
+	MarketDataMaxAge         time.Duration            `json:"marketDataMaxAge"`
+	LatencyWarningThreshold  time.Duration            `json:"latencyWarningThreshold"`
+	LatencyWarningWindows    int                      `json:"latencyWarningWindows"`
+	TickerPriceJumpLimit     float64                  `json:"tickerPriceJumpLimit,omitempty"`
+	AllowNoExchanges         bool                     `json:"allowNoExchanges,omitempty"`
+	DisableRuntimeSaves      bool                     `json:"disableRuntimeSaves,omitempty"`
+	Logging                  log.Logging              `json:"logging"`
+	Currency                 CurrencyConfig           `json:"currencyConfig"`
+	Communications           CommunicationsConfig     `json:"communications"`
+	Portfolio                portfolio.Base           `json:"portfolioAddresses"`
+	PortfolioWatcher         PortfolioWatcherConfig   `json:"portfolioWatcher"`
+	WebsocketReconnect       WebsocketReconnectConfig `json:"websocketReconnect"`
+	Events                   EventsConfig             `json:"events"`
+	Webserver                WebserverConfig          `json:"webserver"`
+	Exchanges                []ExchangeConfig         `json:"exchanges"`
+	BankAccounts             []BankAccount            `json:"bankAccounts"`
 
 	// Deprecated config settings, will be removed at a future date
 	CurrencyPairFormat  *CurrencyPairFormatConfig `json:"currencyPairFormat,omitempty"`
@@ -132,6 +208,9 @@ type ExchangeConfig struct {
 	RESTPollingDelay          time.Duration             `json:"restPollingDelay"`
 	HTTPTimeout               time.Duration             `json:"httpTimeout"`
 	HTTPUserAgent             string                    `json:"httpUserAgent"`
+	VerboseBodyLimit          int                       `json:"verboseBodyLimit,omitempty"`
+	CaptureFixtures           bool                      `json:"captureFixtures,omitempty"`
+	RateLimitThreshold        int                       `json:"rateLimitThreshold,omitempty"`
 	AuthenticatedAPISupport   bool                      `json:"authenticatedApiSupport"`
 	APIKey                    string                    `json:"apiKey"`
 	APISecret                 string                    `json:"apiSecret"`
@@ -146,11 +225,42 @@ type ExchangeConfig struct {
 	EnabledPairs              string                    `json:"enabledPairs"`
 	BaseCurrencies            string                    `json:"baseCurrencies"`
 	AssetTypes                string                    `json:"assetTypes"`
+	EnabledAssetTypes         string                    `json:"enabledAssetTypes,omitempty"`
 	SupportsAutoPairUpdates   bool                      `json:"supportsAutoPairUpdates"`
 	PairsLastUpdated          int64                     `json:"pairsLastUpdated,omitempty"`
+	PairFormatVersion         int                       `json:"pairFormatVersion,omitempty"`
+	AutoEnableNewPairs        bool                      `json:"autoEnableNewPairs,omitempty"`
+	AutoDisableDelistedPairs  bool                      `json:"autoDisableDelistedPairs,omitempty"`
+	PairRemovalThreshold      float64                   `json:"pairRemovalThreshold,omitempty"`
+	RepairInvalidPairs        bool                      `json:"repairInvalidPairs,omitempty"`
 	ConfigCurrencyPairFormat  *CurrencyPairFormatConfig `json:"configCurrencyPairFormat"`
 	RequestCurrencyPairFormat *CurrencyPairFormatConfig `json:"requestCurrencyPairFormat"`
 	BankAccounts              []BankAccount             `json:"bankAccounts"`
+	Accounts                  []APICredentialsConfig    `json:"accounts,omitempty"`
+	CurrencyDetails           []CurrencyDetailsConfig   `json:"currencyDetails,omitempty"`
+}
+
+// CurrencyDetailsConfig overrides an exchange's built in or fetched details
+// for a currency, so outdated withdrawal fees and minimums can be corrected
+// without a code release. Unset fields keep the exchange value
+type CurrencyDetailsConfig struct {
+	Currency            string   `json:"currency"`
+	WithdrawalFee       *float64 `json:"withdrawalFee,omitempty"`
+	MinWithdrawal       *float64 `json:"minWithdrawal,omitempty"`
+	Confirmations       *int     `json:"confirmations,omitempty"`
+	DepositSuspended    *bool    `json:"depositSuspended,omitempty"`
+	WithdrawalSuspended *bool    `json:"withdrawalSuspended,omitempty"`
+}
+
+// APICredentialsConfig holds a labelled set of API credentials for an exchange
+// sub-account. The top level ExchangeConfig credentials remain the default
+// account
+type APICredentialsConfig struct {
+	Label         string `json:"label"`
+	APIKey        string `json:"apiKey"`
+	APISecret     string `json:"apiSecret"`
+	ClientID      string `json:"clientId,omitempty"`
+	APIAuthPEMKey string `json:"apiAuthPemKey,omitempty"`
 }
 
 // BankAccount holds differing bank account details by supported funding
@@ -182,6 +292,41 @@ type CurrencyConfig struct {
 	Cryptocurrencies       string                    `json:"cryptocurrencies"`
 	CurrencyPairFormat     *CurrencyPairFormatConfig `json:"currencyPairFormat"`
 	FiatDisplayCurrency    string                    `json:"fiatDisplayCurrency"`
+	// CustomCryptocurrencies and CustomFiatCurrencies are comma separated
+	// currencies merged into the default currency lists, a default currency
+	// listed as the other type is reclassified. RemovedCurrencies are dropped
+	// from both lists
+	CustomCryptocurrencies string `json:"customCryptocurrencies,omitempty"`
+	CustomFiatCurrencies   string `json:"customFiatCurrencies,omitempty"`
+	RemovedCurrencies      string `json:"removedCurrencies,omitempty"`
+	// AutoRegisterCryptocurrencies registers the unknown currencies of
+	// exchange pairs as cryptocurrencies, enabled unless set to false
+	AutoRegisterCryptocurrencies *bool `json:"autoRegisterCryptocurrencies,omitempty"`
+}
+
+// PortfolioWatcherConfig holds the settings used to refresh the balances of
+// watched portfolio addresses
+type PortfolioWatcherConfig struct {
+	RefreshInterval time.Duration                `json:"refreshInterval"`
+	Explorers       []portfolio.ExplorerSettings `json:"explorers"`
+}
+
+// WebsocketReconnectConfig holds the exchange websocket reconnection backoff
+// settings
+type WebsocketReconnectConfig struct {
+	InitialInterval  time.Duration `json:"initialInterval"`
+	MaxInterval      time.Duration `json:"maxInterval"`
+	StableDuration   time.Duration `json:"stableDuration"`
+	FailureThreshold int           `json:"failureThreshold"`
+}
+
+// EventsConfig holds the event evaluation settings. Pending events are checked
+// against the stored market data every CheckInterval and as soon as fresh
+// market data for their exchange and pair arrives, but no more often than
+// once every MinEvaluationInterval per event
+type EventsConfig struct {
+	CheckInterval         time.Duration `json:"checkInterval"`
+	MinEvaluationInterval time.Duration `json:"minEvaluationInterval"`
 }
 
 // CryptocurrencyProvider defines coinmarketcap tools
@@ -200,6 +345,7 @@ type CommunicationsConfig struct {
 	SMSGlobalConfig SMSGlobalConfig `json:"smsGlobal"`
 	SMTPConfig      SMTPConfig      `json:"smtp"`
 	TelegramConfig  TelegramConfig  `json:"telegram"`
+	WebhookConfig   WebhookConfig   `json:"webhook"`
 }
 
 // SlackConfig holds all variables to start and run the Slack package
@@ -254,24 +400,52 @@ func (c *Config) GetCurrencyConfig() CurrencyConfig {
 	return c.Currency
 }
 
-// GetExchangeBankAccounts returns banking details associated with an exchange
-// for depositing funds
-func (c *Config) GetExchangeBankAccounts(exchangeName string, depositingCurrency string) (BankAccount, error) {
+// listContains returns whether the comma separated list holds the value,
+// comparing its trimmed entries case insensitively
+func listContains(list, value string) bool {
+	value = strings.TrimSpace(value)
+	entries := common.SplitStrings(list, ",")
+	for x := range entries {
+		if strings.EqualFold(strings.TrimSpace(entries[x]), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindExchangeBankAccounts returns every bank account of an exchange which
+// supports depositing the currency
+func (c *Config) FindExchangeBankAccounts(exchangeName, depositingCurrency string) ([]BankAccount, error) {
 	m.Lock()
 	defer m.Unlock()
 
+	var accounts []BankAccount
 	for _, exch := range c.Exchanges {
-		if exch.Name == exchangeName {
-			for _, account := range exch.BankAccounts {
-				if common.StringContains(account.SupportedCurrencies, depositingCurrency) {
-					return account, nil
-				}
+		if exch.Name != exchangeName {
+			continue
+		}
+		for _, account := range exch.BankAccounts {
+			if listContains(account.SupportedCurrencies, depositingCurrency) {
+				accounts = append(accounts, account)
 			}
 		}
 	}
-	return BankAccount{}, fmt.Errorf("Exchange %s bank details not found for %s",
-		exchangeName,
-		depositingCurrency)
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("Exchange %s bank details not found for %s",
+			exchangeName,
+			depositingCurrency)
+	}
+	return accounts, nil
+}
+
+// GetExchangeBankAccounts returns banking details associated with an exchange
+// for depositing funds, the first matching account if there are several
+func (c *Config) GetExchangeBankAccounts(exchangeName string, depositingCurrency string) (BankAccount, error) {
+	accounts, err := c.FindExchangeBankAccounts(exchangeName, depositingCurrency)
+	if err != nil {
+		return BankAccount{}, err
+	}
+	return accounts[0], nil
 }
 
 // UpdateExchangeBankAccounts updates the configuration for the associated
@@ -290,21 +464,35 @@ func (c *Config) UpdateExchangeBankAccounts(exchangeName string, bankCfg []BankA
 		exchangeName)
 }
 
-// GetClientBankAccounts returns banking details used for a given exchange
-// and currency
-func (c *Config) GetClientBankAccounts(exchangeName string, targetCurrency string) (BankAccount, error) {
+// FindClientBankAccounts returns every client bank account which can be used
+// with the exchange and currency
+func (c *Config) FindClientBankAccounts(exchangeName, targetCurrency string) ([]BankAccount, error) {
 	m.Lock()
 	defer m.Unlock()
 
+	var accounts []BankAccount
 	for _, bank := range c.BankAccounts {
-		if (common.StringContains(bank.SupportedExchanges, exchangeName) || bank.SupportedExchanges == "ALL") && common.StringContains(bank.SupportedCurrencies, targetCurrency) {
-			return bank, nil
-
+		if (bank.SupportedExchanges == "ALL" || listContains(bank.SupportedExchanges, exchangeName)) &&
+			listContains(bank.SupportedCurrencies, targetCurrency) {
+			accounts = append(accounts, bank)
 		}
 	}
-	return BankAccount{}, fmt.Errorf("client banking details not found for %s and currency %s",
-		exchangeName,
-		targetCurrency)
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("client banking details not found for %s and currency %s",
+			exchangeName,
+			targetCurrency)
+	}
+	return accounts, nil
+}
+
+// GetClientBankAccounts returns banking details used for a given exchange
+// and currency, the first matching account if there are several
+func (c *Config) GetClientBankAccounts(exchangeName string, targetCurrency string) (BankAccount, error) {
+	accounts, err := c.FindClientBankAccounts(exchangeName, targetCurrency)
+	if err != nil {
+		return BankAccount{}, err
+	}
+	return accounts[0], nil
 }
 
 // UpdateClientBankAccounts updates the configuration for a bank
@@ -364,10 +552,29 @@ func (c *Config) CheckClientBankAccounts() error {
 				c.BankAccounts[i].SupportedExchanges = "ALL"
 			}
 		}
+
+		currencies := common.SplitStrings(c.BankAccounts[i].SupportedCurrencies, ",")
+		for x := range currencies {
+			code := common.StringToUpper(strings.TrimSpace(currencies[x]))
+			if code != "" && !isKnownCurrencyCode(code) {
+				log.Warnf("Bank account %s supported currency %s is not a known currency code.",
+					c.BankAccounts[i].BankName, code)
+			}
+		}
 	}
 	return nil
 }
 
+// isKnownCurrencyCode returns whether the upper case code is a fiat currency
+// or a configured cryptocurrency
+func isKnownCurrencyCode(code string) bool {
+	if _, err := symbol.GetSymbolByCurrencyName(code); err == nil {
+		return true
+	}
+	return currency.IsDefaultCurrency(code) || currency.IsDefaultCryptocurrency(code) ||
+		currency.IsFiatCurrency(code) || currency.IsCryptocurrency(code)
+}
+
 // GetCommunicationsConfig returns the communications configuration
 func (c *Config) GetCommunicationsConfig() CommunicationsConfig {
 	m.Lock()
@@ -397,6 +604,28 @@ func (c *Config) UpdateCryptocurrencyProviderConfig(config CryptocurrencyProvide
 	m.Unlock()
 }
 
+// WebhookEndpoint holds a URL events are posted to, the headers sent with
+// each request and the event types delivered, all events are delivered when
+// no event types are set
+type WebhookEndpoint struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Events  []string          `json:"events,omitempty"`
+}
+
+// WebhookConfig holds all variables to start and run the Webhook package.
+// Requests are signed with the HMAC-SHA256 of their body when a signature
+// secret is set
+type WebhookConfig struct {
+	Name            string            `json:"name"`
+	Enabled         bool              `json:"enabled"`
+	Verbose         bool              `json:"verbose"`
+	Endpoints       []WebhookEndpoint `json:"endpoints"`
+	Timeout         time.Duration     `json:"timeout"`
+	Retries         int               `json:"retries"`
+	SignatureSecret string            `json:"signatureSecret,omitempty"`
+}
+
 // CheckCommunicationsConfig checks to see if the variables are set correctly
 // from config.json
 func (c *Config) CheckCommunicationsConfig() {
@@ -415,53 +644,18 @@ func (c *Config) CheckCommunicationsConfig() {
 	}
 
 	if c.Communications.SMSGlobalConfig.Name == "" {
-		if c.SMS != nil {
-			if c.SMS.Contacts != nil {
-				c.Communications.SMSGlobalConfig = SMSGlobalConfig{
-					Name:     "SMSGlobal",
-					Enabled:  c.SMS.Enabled,
-					Verbose:  c.SMS.Verbose,
-					Username: c.SMS.Username,
-					Password: c.SMS.Password,
-					Contacts: c.SMS.Contacts,
-				}
-				// flush old SMS config
-				c.SMS = nil
-			} else {
-				c.Communications.SMSGlobalConfig = SMSGlobalConfig{
-					Name:     "SMSGlobal",
-					Username: "main",
-					Password: "test",
-
-					Contacts: []SMSContact{
-						{
-							Name:    "bob",
-							Number:  "1234",
-							Enabled: false,
-						},
-					},
-				}
-			}
-		} else {
-			c.Communications.SMSGlobalConfig = SMSGlobalConfig{
-				Name:     "SMSGlobal",
-				Username: "main",
-				Password: "test",
-
-				Contacts: []SMSContact{
-					{
-						Name:    "bob",
-						Number:  "1234",
-						Enabled: false,
-					},
+		c.Communications.SMSGlobalConfig = SMSGlobalConfig{
+			Name:     "SMSGlobal",
+			Username: "main",
+			Password: "test",
+
+			Contacts: []SMSContact{
+				{
+					Name:    "bob",
+					Number:  "1234",
+					Enabled: false,
 				},
-			}
-		}
-
-	} else {
-		if c.SMS != nil {
-			// flush old SMS config
-			c.SMS = nil
+			},
 		}
 	}
 
@@ -483,10 +677,19 @@ func (c *Config) CheckCommunicationsConfig() {
 		}
 	}
 
+	if c.Communications.WebhookConfig.Name == "" {
+		c.Communications.WebhookConfig = WebhookConfig{
+			Name:    "Webhook",
+			Timeout: configDefaultWebhookTimeout,
+			Retries: configDefaultWebhookRetries,
+		}
+	}
+
 	if c.Communications.SlackConfig.Name != "Slack" ||
 		c.Communications.SMSGlobalConfig.Name != "SMSGlobal" ||
 		c.Communications.SMTPConfig.Name != "SMTP" ||
-		c.Communications.TelegramConfig.Name != "Telegram" {
+		c.Communications.TelegramConfig.Name != "Telegram" ||
+		c.Communications.WebhookConfig.Name != "Webhook" {
 		log.Warn("Communications config name/s not set correctly")
 	}
 	if c.Communications.SlackConfig.Enabled {
@@ -520,6 +723,241 @@ func (c *Config) CheckCommunicationsConfig() {
 			log.Warn("Telegram enabled in config but variable data not set, disabling.")
 		}
 	}
+	if c.Communications.WebhookConfig.Enabled {
+		webhook := &c.Communications.WebhookConfig
+		urlsSet := len(webhook.Endpoints) > 0
+		for x := range webhook.Endpoints {
+			if webhook.Endpoints[x].URL == "" {
+				urlsSet = false
+			}
+		}
+		if !urlsSet {
+			webhook.Enabled = false
+			log.Warn("Webhook enabled in config but endpoint URLs not set, disabling.")
+		}
+		if webhook.Timeout <= 0 {
+			webhook.Timeout = configDefaultWebhookTimeout
+		}
+		if webhook.Retries < 0 {
+			webhook.Retries = 0
+		}
+	}
+}
+
+// CheckPortfolioWatcherConfig sets the default portfolio watcher refresh
+// interval and blockchain explorers if they're not set, and drops explorers
+// without a coin or API URL
+func (c *Config) CheckPortfolioWatcherConfig() {
+	if c.PortfolioWatcher.RefreshInterval <= 0 {
+		log.Warnf("Portfolio watcher refresh interval value not set, defaulting to %v.",
+			configDefaultPortfolioRefreshInterval)
+		c.PortfolioWatcher.RefreshInterval = configDefaultPortfolioRefreshInterval
+	}
+
+	if len(c.PortfolioWatcher.Explorers) == 0 {
+		c.PortfolioWatcher.Explorers = portfolio.DefaultExplorers()
+		return
+	}
+
+	var explorers []portfolio.ExplorerSettings
+	for x := range c.PortfolioWatcher.Explorers {
+		if c.PortfolioWatcher.Explorers[x].Coin == "" ||
+			c.PortfolioWatcher.Explorers[x].APIURL == "" {
+			log.Warnf("Portfolio watcher explorer #%d coin or API URL not set, disabling.", x)
+			continue
+		}
+		explorers = append(explorers, c.PortfolioWatcher.Explorers[x])
+	}
+	c.PortfolioWatcher.Explorers = explorers
+}
+
+// knownCurrencies returns the upper case default fiat and cryptocurrencies,
+// the configured cryptocurrencies and the exchange base currencies
+func (c *Config) knownCurrencies(exch *ExchangeConfig) map[string]bool {
+	known := make(map[string]bool)
+	lists := []string{currency.DefaultCurrencies, currency.DefaultCryptoCurrencies,
+		c.Currency.Cryptocurrencies, c.Cryptocurrencies, exch.BaseCurrencies}
+	for x := range lists {
+		currencies := common.SplitStrings(lists[x], ",")
+		for y := range currencies {
+			if currencies[y] != "" {
+				known[common.StringToUpper(currencies[y])] = true
+			}
+		}
+	}
+	for x := range currency.FiatCurrencies {
+		known[currency.FiatCurrencies[x]] = true
+	}
+	for x := range currency.CryptoCurrencies {
+		known[currency.CryptoCurrencies[x]] = true
+	}
+	return known
+}
+
+// checkCurrencyCode returns why a currency code parsed from a pair is not
+// plausible, or an empty string if it is. Codes made of two known currencies,
+// such as BTCUSD, are the result of a pair split in the wrong place
+func checkCurrencyCode(code string, known map[string]bool) string {
+	if code == "" {
+		return "currency code is empty"
+	}
+	if strings.ContainsAny(code, " \t") {
+		return fmt.Sprintf("currency code %q contains whitespace", code)
+	}
+	code = common.StringToUpper(code)
+	if known[code] {
+		return ""
+	}
+	for x := 2; x <= len(code)-2; x++ {
+		if known[code[:x]] && known[code[x:]] {
+			return fmt.Sprintf("currency code %q is two currencies", code)
+		}
+	}
+	return ""
+}
+
+// checkPairFormat returns why a pair does not parse under the config pair
+// format, or an empty string if it does
+func checkPairFormat(p string, format *CurrencyPairFormatConfig, known map[string]bool) string {
+	var first, second string
+	switch {
+	case format.Delimiter != "":
+		parts := strings.Split(p, format.Delimiter)
+		if len(parts) != 2 {
+			return fmt.Sprintf("expected one delimiter %q", format.Delimiter)
+		}
+		first, second = parts[0], parts[1]
+	case format.Index != "":
+		upper := common.StringToUpper(p)
+		index := common.StringToUpper(format.Index)
+		switch {
+		case strings.HasPrefix(upper, index):
+			first, second = p[:len(index)], p[len(index):]
+		case strings.HasSuffix(upper, index):
+			first, second = p[:len(p)-len(index)], p[len(p)-len(index):]
+		default:
+			return fmt.Sprintf("index %q is not the first or second currency", format.Index)
+		}
+	default:
+		if len(p) <= 3 {
+			return "expected at least four characters"
+		}
+		first, second = p[:3], p[3:]
+	}
+
+	reason := checkCurrencyCode(first, known)
+	if reason == "" {
+		reason = checkCurrencyCode(second, known)
+	}
+	return reason
+}
+
+// CheckPairConfigFormats checks the exchange config pair format index is a
+// known currency and that each available and enabled pair parses into two
+// plausible currency codes under the config pair format. If the exchange
+// repairs invalid pairs, pairs that don't parse are removed instead
+func (c *Config) CheckPairConfigFormats(exchName string) error {
+	for i := range c.Exchanges {
+		if c.Exchanges[i].Name == exchName {
+			return c.checkPairConfigFormats(&c.Exchanges[i])
+		}
+	}
+	return fmt.Errorf(ErrExchangeNotFound, exchName)
+}
+
+func (c *Config) checkPairConfigFormats(exch *ExchangeConfig) error {
+	format := exch.ConfigCurrencyPairFormat
+	if format == nil {
+		return nil
+	}
+
+	known := c.knownCurrencies(exch)
+	if format.Delimiter == "" && format.Index != "" &&
+		!known[common.StringToUpper(format.Index)] {
+		return fmt.Errorf(ErrExchangePairFormatIndexUnknown, exch.Name, format.Index)
+	}
+
+	checkPairs := func(pairs string) (string, error) {
+		var valid []string
+		list := common.SplitStrings(pairs, ",")
+		for x := range list {
+			reason := checkPairFormat(list[x], format, known)
+			if reason == "" {
+				valid = append(valid, list[x])
+				continue
+			}
+			if !exch.RepairInvalidPairs {
+				return "", fmt.Errorf(ErrExchangePairInvalid, exch.Name, list[x], reason)
+			}
+			log.Warnf("Exchange %s: Removing invalid pair %q, %s.", exch.Name, list[x], reason)
+		}
+		return common.JoinStrings(valid, ","), nil
+	}
+
+	availablePairs, err := checkPairs(exch.AvailablePairs)
+	if err != nil {
+		return err
+	}
+	if availablePairs == "" {
+		return fmt.Errorf(ErrExchangeAvailablePairsEmpty, exch.Name)
+	}
+	enabledPairs, err := checkPairs(exch.EnabledPairs)
+	if err != nil {
+		return err
+	}
+	if enabledPairs == "" {
+		return fmt.Errorf(ErrExchangeEnabledPairsEmpty, exch.Name)
+	}
+	exch.AvailablePairs = availablePairs
+	exch.EnabledPairs = enabledPairs
+	return nil
+}
+
+// CheckWebsocketReconnectConfig sets the default websocket reconnection
+// backoff settings if they're not set
+func (c *Config) CheckWebsocketReconnectConfig() {
+	if c.WebsocketReconnect.InitialInterval <= 0 {
+		log.Warnf("Websocket reconnect initial interval value not set, defaulting to %v.",
+			configDefaultWebsocketReconnectInitial)
+		c.WebsocketReconnect.InitialInterval = configDefaultWebsocketReconnectInitial
+	}
+
+	if c.WebsocketReconnect.MaxInterval < c.WebsocketReconnect.InitialInterval {
+		log.Warnf("Websocket reconnect max interval value not set or below the initial interval, defaulting to %v.",
+			configDefaultWebsocketReconnectMax)
+		c.WebsocketReconnect.MaxInterval = configDefaultWebsocketReconnectMax
+		if c.WebsocketReconnect.MaxInterval < c.WebsocketReconnect.InitialInterval {
+			c.WebsocketReconnect.MaxInterval = c.WebsocketReconnect.InitialInterval
+		}
+	}
+
+	if c.WebsocketReconnect.StableDuration <= 0 {
+		log.Warnf("Websocket reconnect stable duration value not set, defaulting to %v.",
+			configDefaultWebsocketReconnectStable)
+		c.WebsocketReconnect.StableDuration = configDefaultWebsocketReconnectStable
+	}
+
+	if c.WebsocketReconnect.FailureThreshold <= 0 {
+		log.Warnf("Websocket reconnect failure threshold value not set, defaulting to %d.",
+			configDefaultWebsocketFailureThreshold)
+		c.WebsocketReconnect.FailureThreshold = configDefaultWebsocketFailureThreshold
+	}
+}
+
+// CheckEventsConfig sets the default event evaluation intervals when they're
+// not set
+func (c *Config) CheckEventsConfig() {
+	if c.Events.CheckInterval <= 0 {
+		log.Warnf("Events check interval value not set, defaulting to %v.",
+			configDefaultEventsCheckInterval)
+		c.Events.CheckInterval = configDefaultEventsCheckInterval
+	}
+
+	if c.Events.MinEvaluationInterval <= 0 {
+		log.Warnf("Events minimum evaluation interval value not set, defaulting to %v.",
+			configDefaultEventsMinEvaluation)
+		c.Events.MinEvaluationInterval = configDefaultEventsMinEvaluation
+	}
 }
 
 // CheckPairConsistency checks to see if the enabled pair exists in the
@@ -721,15 +1159,49 @@ func (c *Config) UpdateExchangeConfig(e ExchangeConfig) error {
 	return fmt.Errorf(ErrExchangeNotFound, e.Name)
 }
 
+// checkEnabledAssetTypes ensures the enabled asset types of an exchange are a
+// subset of its supported asset types
+func checkEnabledAssetTypes(exch ExchangeConfig) error {
+	if exch.EnabledAssetTypes == "" || exch.AssetTypes == "" {
+		return nil
+	}
+
+	supported := common.SplitStrings(common.StringToLower(exch.AssetTypes), ",")
+	for _, a := range common.SplitStrings(exch.EnabledAssetTypes, ",") {
+		if !common.StringDataCompare(supported, common.StringToLower(a)) {
+			return fmt.Errorf(ErrExchangeAssetTypeNotSupported, exch.Name, a,
+				exch.AssetTypes)
+		}
+	}
+	return nil
+}
+
+// checkExchangeURLs validates the REST and websocket URLs an exchange config
+// overrides its default endpoints with
+func checkExchangeURLs(exch ExchangeConfig) error {
+	for _, apiURL := range []string{exch.APIURL, exch.APIURLSecondary} {
+		if apiURL == "" || apiURL == APIURLNonDefaultMessage {
+			continue
+		}
+		err := common.ValidateURL(apiURL, "http", "https")
+		if err != nil {
+			return fmt.Errorf("exchange %s API URL: %s", exch.Name, err)
+		}
+	}
+	if exch.WebsocketURL != "" && exch.WebsocketURL != WebsocketURLNonDefaultMessage {
+		err := common.ValidateURL(exch.WebsocketURL, "ws", "wss")
+		if err != nil {
+			return fmt.Errorf("exchange %s websocket URL: %s", exch.Name, err)
+		}
+	}
+	return nil
+}
+
 // CheckExchangeConfigValues returns configuation values for all enabled
 // exchanges
 func (c *Config) CheckExchangeConfigValues() error {
 	exchanges := 0
 	for i, exch := range c.Exchanges {
-		if exch.Name == "GDAX" {
-			c.Exchanges[i].Name = "CoinbasePro"
-		}
-
 		if exch.WebsocketURL != WebsocketURLNonDefaultMessage {
 			if exch.WebsocketURL == "" {
 				c.Exchanges[i].WebsocketURL = WebsocketURLNonDefaultMessage
@@ -754,6 +1226,8 @@ func (c *Config) CheckExchangeConfigValues() error {
 			if exch.Name == "" {
 				return fmt.Errorf(ErrExchangeNameEmpty, i)
 			}
+			mergeExchangeDefaults(&c.Exchanges[i])
+			exch = c.Exchanges[i]
 			if exch.AvailablePairs == "" {
 				return fmt.Errorf(ErrExchangeAvailablePairsEmpty, exch.Name)
 			}
@@ -775,6 +1249,29 @@ func (c *Config) CheckExchangeConfigValues() error {
 						log.Warn(WarningExchangeAuthAPIDefaultOrEmptyValues, exch.Name)
 					}
 				}
+				if c.Exchanges[i].AuthenticatedAPISupport && exch.APIAuthPEMKeySupport {
+					_, err := common.ParsePEMPrivateKey(exch.APIAuthPEMKey)
+					if err != nil {
+						c.Exchanges[i].AuthenticatedAPISupport = false
+						log.Warnf(WarningExchangeAuthAPIPEMKeyInvalid, exch.Name, err)
+					}
+				}
+			}
+			err := c.checkExchangeAccounts(i)
+			if err != nil {
+				return err
+			}
+			err = checkCurrencyDetails(exch)
+			if err != nil {
+				return err
+			}
+			err = checkEnabledAssetTypes(exch)
+			if err != nil {
+				return err
+			}
+			err = checkExchangeURLs(exch)
+			if err != nil {
+				return err
 			}
 			if !exch.SupportsAutoPairUpdates {
 				lastUpdated := common.UnixTimestampToTime(exch.PairsLastUpdated)
@@ -785,11 +1282,22 @@ func (c *Config) CheckExchangeConfigValues() error {
 			}
 
 			if exch.HTTPTimeout <= 0 {
-				log.Warnf("Exchange %s HTTP Timeout value not set, defaulting to %v.", exch.Name, configDefaultHTTPTimeout)
-				c.Exchanges[i].HTTPTimeout = configDefaultHTTPTimeout
+				timeout := c.GlobalHTTPTimeout
+				if timeout <= 0 {
+					timeout = configDefaultHTTPTimeout
+				}
+				log.Warnf("Exchange %s HTTP Timeout value not set, defaulting to %v.", exch.Name, timeout)
+				c.Exchanges[i].HTTPTimeout = timeout
 			}
 
-			err := c.CheckPairConsistency(exch.Name)
+			err = c.checkPairConfigFormats(&c.Exchanges[i])
+			if err != nil {
+				log.Errorf("%s Disabling exchange.", err)
+				c.Exchanges[i].Enabled = false
+				continue
+			}
+
+			err = c.CheckPairConsistency(exch.Name)
 			if err != nil {
 				log.Errorf("Exchange %s: CheckPairConsistency error: %s", exch.Name, err)
 			}
@@ -826,11 +1334,87 @@ func (c *Config) CheckExchangeConfigValues() error {
 		}
 	}
 	if exchanges == 0 {
+		if c.AllowsNoExchanges() {
+			log.Warn(WarningNoEnabledExchanges)
+			return nil
+		}
 		return errors.New(ErrNoEnabledExchanges)
 	}
 	return nil
 }
 
+// AllowsNoExchanges returns whether the bot can run without enabled exchanges
+// for portfolio tracking, forex rates and the RPC interfaces, either through
+// the config setting or the portfolio only start flag
+func (c *Config) AllowsNoExchanges() bool {
+	return c.AllowNoExchanges || PortfolioOnly
+}
+
+// checkExchangeAccounts validates the labelled sub-account credentials for the
+// exchange at the supplied index, dropping any with default or empty keys
+func (c *Config) checkExchangeAccounts(i int) error {
+	exch := c.Exchanges[i]
+	if len(exch.Accounts) == 0 {
+		return nil
+	}
+
+	var accounts []APICredentialsConfig
+	var labels []string
+	for x := range exch.Accounts {
+		label := exch.Accounts[x].Label
+		if label == "" || common.StringContains(label, AccountLabelSeparator) ||
+			common.StringDataCompareUpper(labels, label) {
+			return fmt.Errorf(ErrExchangeAccountLabelInvalid, exch.Name, x, label,
+				AccountLabelSeparator)
+		}
+		labels = append(labels, label)
+
+		if exch.Accounts[x].APIKey == "" || exch.Accounts[x].APISecret == "" ||
+			exch.Accounts[x].APIKey == DefaultUnsetAPIKey ||
+			exch.Accounts[x].APISecret == DefaultUnsetAPISecret {
+			log.Warnf(WarningExchangeAccountDefaultOrEmptyValues, exch.Name, label)
+			continue
+		}
+		accounts = append(accounts, exch.Accounts[x])
+	}
+	c.Exchanges[i].Accounts = accounts
+	return nil
+}
+
+// checkCurrencyDetails validates the currency details overrides of an
+// exchange
+func checkCurrencyDetails(exch ExchangeConfig) error {
+	var currencies []string
+	for x := range exch.CurrencyDetails {
+		details := exch.CurrencyDetails[x]
+		if details.Currency == "" ||
+			common.StringDataCompareUpper(currencies, details.Currency) ||
+			details.WithdrawalFee != nil && *details.WithdrawalFee < 0 ||
+			details.MinWithdrawal != nil && *details.MinWithdrawal < 0 ||
+			details.Confirmations != nil && *details.Confirmations < 0 {
+			return fmt.Errorf(ErrExchangeCurrencyDetailsInvalid, exch.Name, x,
+				details.Currency)
+		}
+		currencies = append(currencies, details.Currency)
+	}
+	return nil
+}
+
+// GetExchangeAccountLabels returns the configured sub-account labels for an
+// exchange
+func (c *Config) GetExchangeAccountLabels(exchName string) ([]string, error) {
+	exchCfg, err := c.GetExchangeConfig(exchName)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []string
+	for x := range exchCfg.Accounts {
+		labels = append(labels, exchCfg.Accounts[x].Label)
+	}
+	return labels, nil
+}
+
 // CheckWebserverConfigValues checks information before webserver starts and
 // returns an error if values are incorrect.
 func (c *Config) CheckWebserverConfigValues() error {
@@ -860,6 +1444,28 @@ func (c *Config) CheckWebserverConfigValues() error {
 		c.Webserver.WebsocketMaxAuthFailures = 3
 	}
 
+	switch common.StringToUpper(c.Webserver.RequestLogLevel) {
+	case "":
+		c.Webserver.RequestLogLevel = configDefaultRequestLogLevel
+	case "DEBUG", "INFO", "WARN":
+		c.Webserver.RequestLogLevel = common.StringToUpper(c.Webserver.RequestLogLevel)
+	default:
+		return fmt.Errorf("invalid webserver request log level %q, supported values: DEBUG, INFO, WARN",
+			c.Webserver.RequestLogLevel)
+	}
+
+	if c.Webserver.SlowRequestThreshold <= 0 {
+		c.Webserver.SlowRequestThreshold = configDefaultSlowRequestThreshold
+	}
+
+	if c.Webserver.WebsocketAuthTimeout == 0 {
+		c.Webserver.WebsocketAuthTimeout = configDefaultWebsocketAuthTimeout
+	}
+
+	if c.Webserver.WebsocketSendQueueSize <= 0 {
+		c.Webserver.WebsocketSendQueueSize = configDefaultWebsocketSendQueueSize
+	}
+
 	return nil
 }
 
@@ -942,84 +1548,107 @@ func (c *Config) CheckCurrencyConfigValues() error {
 	}
 
 	if len(c.Currency.Cryptocurrencies) == 0 {
-		if len(c.Cryptocurrencies) != 0 {
-			c.Currency.Cryptocurrencies = c.Cryptocurrencies
-			c.Cryptocurrencies = ""
-		} else {
-			c.Currency.Cryptocurrencies = currency.DefaultCryptoCurrencies
-		}
+		c.Currency.Cryptocurrencies = currency.DefaultCryptoCurrencies
 	}
 
 	if c.Currency.CurrencyPairFormat == nil {
-		if c.CurrencyPairFormat != nil {
-			c.Currency.CurrencyPairFormat = c.CurrencyPairFormat
-			c.CurrencyPairFormat = nil
-		} else {
-			c.Currency.CurrencyPairFormat = &CurrencyPairFormatConfig{
-				Delimiter: "-",
-				Uppercase: true,
-			}
+		c.Currency.CurrencyPairFormat = &CurrencyPairFormatConfig{
+			Delimiter: "-",
+			Uppercase: true,
 		}
 	}
 
 	if c.Currency.FiatDisplayCurrency == "" {
-		if c.FiatDisplayCurrency != "" {
-			c.Currency.FiatDisplayCurrency = c.FiatDisplayCurrency
-			c.FiatDisplayCurrency = ""
-		} else {
-			c.Currency.FiatDisplayCurrency = "USD"
+		c.Currency.FiatDisplayCurrency = "USD"
+	}
+
+	cryptos := common.SplitStrings(c.Currency.CustomCryptocurrencies, ",")
+	fiats := common.SplitStrings(c.Currency.CustomFiatCurrencies, ",")
+	removed := common.SplitStrings(c.Currency.RemovedCurrencies, ",")
+	for x := range cryptos {
+		if cryptos[x] != "" && common.StringDataCompareUpper(fiats, cryptos[x]) {
+			return fmt.Errorf("currency %s is both a custom cryptocurrency and fiat currency",
+				cryptos[x])
+		}
+	}
+	for x := range removed {
+		if removed[x] != "" && (common.StringDataCompareUpper(cryptos, removed[x]) ||
+			common.StringDataCompareUpper(fiats, removed[x])) {
+			return fmt.Errorf("removed currency %s is also a custom currency", removed[x])
 		}
 	}
 	return nil
 }
 
+// mergeCurrencies merges the default and configured currencies with the user
+// defined currencies into the currency package lists
+func (c *Config) mergeCurrencies() {
+	currency.SetCustomCurrencies(
+		common.SplitStrings(c.Currency.CustomCryptocurrencies, ","),
+		common.SplitStrings(c.Currency.CustomFiatCurrencies, ","),
+		common.SplitStrings(c.Currency.RemovedCurrencies, ","))
+	currency.Update(common.SplitStrings(currency.DefaultCurrencies, ","), false)
+	currency.Update(common.SplitStrings(c.Currency.Cryptocurrencies, ","), true)
+}
+
+// AutoRegisterCurrencies returns whether the unknown currencies of exchange
+// pairs are registered as cryptocurrencies
+func (c *Config) AutoRegisterCurrencies() bool {
+	return c.Currency.AutoRegisterCryptocurrencies == nil ||
+		*c.Currency.AutoRegisterCryptocurrencies
+}
+
+// RegisterPairCurrencies registers the currencies of the pairs which aren't
+// known fiat currencies or cryptocurrencies as cryptocurrencies if enabled,
+// returning the currencies registered
+func (c *Config) RegisterPairCurrencies(pairs []pair.CurrencyPair) []string {
+	if !c.AutoRegisterCurrencies() {
+		return nil
+	}
+	var currencies []string
+	for x := range pairs {
+		currencies = append(currencies, pairs[x].FirstCurrency.String(),
+			pairs[x].SecondCurrency.String())
+	}
+	return currency.Register(currencies, true)
+}
+
 // RetrieveConfigCurrencyPairs splits, assigns and verifies enabled currency
 // pairs either cryptoCurrencies or fiatCurrencies
 func (c *Config) RetrieveConfigCurrencyPairs(enabledOnly bool) error {
-	cryptoCurrencies := common.SplitStrings(c.Cryptocurrencies, ",")
 	fiatCurrencies := common.SplitStrings(currency.DefaultCurrencies, ",")
-
 	for x := range c.Exchanges {
 		if !c.Exchanges[x].Enabled && enabledOnly {
 			continue
 		}
-
-		baseCurrencies := common.SplitStrings(c.Exchanges[x].BaseCurrencies, ",")
-		for y := range baseCurrencies {
-			if !common.StringDataCompare(fiatCurrencies, common.StringToUpper(baseCurrencies[y])) {
-				fiatCurrencies = append(fiatCurrencies, common.StringToUpper(baseCurrencies[y]))
-			}
-		}
+		fiatCurrencies = append(fiatCurrencies,
+			common.SplitStrings(c.Exchanges[x].BaseCurrencies, ",")...)
 	}
+	currency.Update(fiatCurrencies, false)
+	currency.Update(common.SplitStrings(c.Currency.Cryptocurrencies, ","), true)
+	currency.Update(common.SplitStrings(c.Cryptocurrencies, ","), true)
 
+	var pairs []pair.CurrencyPair
 	for x := range c.Exchanges {
-		var pairs []pair.CurrencyPair
+		var exchPairs []pair.CurrencyPair
 		var err error
 		if !c.Exchanges[x].Enabled && enabledOnly {
-			pairs, err = c.GetEnabledPairs(c.Exchanges[x].Name)
+			exchPairs, err = c.GetEnabledPairs(c.Exchanges[x].Name)
 		} else {
-			pairs, err = c.GetAvailablePairs(c.Exchanges[x].Name)
+			exchPairs, err = c.GetAvailablePairs(c.Exchanges[x].Name)
 		}
 
 		if err != nil {
 			return err
 		}
-
-		for y := range pairs {
-			if !common.StringDataCompare(fiatCurrencies, pairs[y].FirstCurrency.Upper().String()) &&
-				!common.StringDataCompare(cryptoCurrencies, pairs[y].FirstCurrency.Upper().String()) {
-				cryptoCurrencies = append(cryptoCurrencies, pairs[y].FirstCurrency.Upper().String())
-			}
-
-			if !common.StringDataCompare(fiatCurrencies, pairs[y].SecondCurrency.Upper().String()) &&
-				!common.StringDataCompare(cryptoCurrencies, pairs[y].SecondCurrency.Upper().String()) {
-				cryptoCurrencies = append(cryptoCurrencies, pairs[y].SecondCurrency.Upper().String())
-			}
-		}
+		pairs = append(pairs, exchPairs...)
 	}
 
-	currency.Update(fiatCurrencies, false)
-	currency.Update(cryptoCurrencies, true)
+	registered := c.RegisterPairCurrencies(pairs)
+	if len(registered) > 0 {
+		log.Debugf("Registered %d exchange pair currencies as cryptocurrencies: %s",
+			len(registered), common.JoinStrings(registered, ","))
+	}
 	return nil
 }
 
@@ -1050,6 +1679,19 @@ func (c *Config) CheckLoggerConfig() (err error) {
 		log.Logger = &c.Logging
 	}
 
+	for name, level := range c.Logging.Subsystems {
+		if name == "" {
+			log.Warn("Logger subsystem override with no name, removing")
+			delete(c.Logging.Subsystems, name)
+			continue
+		}
+		levelErr := log.ValidateLevels(level)
+		if levelErr != nil {
+			log.Warnf("Logger subsystem %s override invalid, removing. Err: %s", name, levelErr)
+			delete(c.Logging.Subsystems, name)
+		}
+	}
+
 	if len(c.Logging.File) > 0 {
 		logPath := path.Join(common.GetDefaultDataDir(runtime.GOOS), "logs")
 		err = common.CheckDir(logPath, true)
@@ -1148,8 +1790,8 @@ func GetFilePath(file string) (string, error) {
 	return "", errors.New("config default file path error")
 }
 
-// ReadConfig verifies and checks for encryption and verifies the unencrypted
-// file contains JSON.
+// ReadConfig verifies and checks for encryption, verifies the unencrypted
+// file contains JSON and upgrades it to the current config version.
 func (c *Config) ReadConfig(configPath string) error {
 	defaultPath, err := GetFilePath(configPath)
 	if err != nil {
@@ -1161,12 +1803,20 @@ func (c *Config) ReadConfig(configPath string) error {
 		return err
 	}
 
+	// Configs without a version predate versioning and must not inherit the
+	// version of a previously loaded config
+	c.ConfigVersion = 0
 	if !ConfirmECS(file) {
 		err = ConfirmConfigJSON(file, &c)
 		if err != nil {
 			return err
 		}
 
+		err = c.UpgradeConfig()
+		if err != nil {
+			return err
+		}
+
 		if c.EncryptConfig == configFileEncryptionDisabled {
 			return nil
 		}
@@ -1210,19 +1860,29 @@ func (c *Config) ReadConfig(configPath string) error {
 				errCounter++
 				continue
 			}
+
+			err = CacheConfigEncryptionKey(key)
+			if err != nil {
+				return err
+			}
 			break
 		}
+		return c.UpgradeConfig()
 	}
 	return nil
 }
 
-// SaveConfig saves your configuration to your desired path
+// SaveConfig saves your configuration to your desired path. The file is
+// replaced atomically so an interrupted save doesn't corrupt it. Encrypted
+// configs are saved with the key entered on initial setup or cached when the
+// config was read, ErrNoEncryptionKey is returned if there is neither
 func (c *Config) SaveConfig(configPath string) error {
 	defaultPath, err := GetFilePath(configPath)
 	if err != nil {
 		return err
 	}
 
+	c.ConfigVersion = CurrentConfigVersion
 	payload, err := json.MarshalIndent(c, "", " ")
 	if err != nil {
 		return err
@@ -1245,11 +1905,7 @@ func (c *Config) SaveConfig(configPath string) error {
 		}
 	}
 
-	err = common.WriteFile(defaultPath, payload)
-	if err != nil {
-		return err
-	}
-	return nil
+	return common.WriteFileAtomic(defaultPath, payload)
 }
 
 // CheckConfig checks all config settings
@@ -1274,10 +1930,10 @@ func (c *Config) CheckConfig() error {
 		return err
 	}
 
-	if c.GlobalHTTPTimeout <= 0 {
-		log.Warnf("Global HTTP Timeout value not set, defaulting to %v.", configDefaultHTTPTimeout)
-		c.GlobalHTTPTimeout = configDefaultHTTPTimeout
-	}
+	c.checkGlobalValues()
+	c.CheckPortfolioWatcherConfig()
+	c.CheckWebsocketReconnectConfig()
+	c.CheckEventsConfig()
 
 	err = c.CheckClientBankAccounts()
 	if err != nil {
@@ -1287,6 +1943,61 @@ func (c *Config) CheckConfig() error {
 	return nil
 }
 
+// checkGlobalValues sets the defaults of the top level config values which
+// aren't set or are invalid
+func (c *Config) checkGlobalValues() {
+	if c.GlobalHTTPTimeout <= 0 {
+		log.Warnf("Global HTTP Timeout value not set, defaulting to %v.", configDefaultHTTPTimeout)
+		c.GlobalHTTPTimeout = configDefaultHTTPTimeout
+	}
+
+	if c.ExchangeStartupTimeout <= 0 {
+		log.Warnf("Exchange startup timeout value not set, defaulting to %v.", configDefaultExchangeStartupTimeout)
+		c.ExchangeStartupTimeout = configDefaultExchangeStartupTimeout
+	}
+
+	if c.PairUpdateInterval <= 0 {
+		log.Warnf("Pair update interval value not set, defaulting to %v.", configDefaultPairUpdateInterval)
+		c.PairUpdateInterval = configDefaultPairUpdateInterval
+	}
+
+	if c.MarketDataMaxAge <= 0 {
+		log.Warnf("Market data max age value not set, defaulting to %v.", configDefaultMarketDataMaxAge)
+		c.MarketDataMaxAge = configDefaultMarketDataMaxAge
+	}
+
+	if c.BalanceRefreshInterval <= 0 {
+		log.Warnf("Balance refresh interval value not set, defaulting to %v.", configDefaultBalanceRefreshInterval)
+		c.BalanceRefreshInterval = configDefaultBalanceRefreshInterval
+	}
+
+	if c.MaintenanceProbeInterval <= 0 {
+		log.Warnf("Exchange maintenance probe interval value not set, defaulting to %v.", configDefaultMaintenanceProbeInterval)
+		c.MaintenanceProbeInterval = configDefaultMaintenanceProbeInterval
+	}
+
+	if c.LatencyWarningThreshold <= 0 {
+		log.Warnf("Exchange latency warning threshold value not set, defaulting to %v.", configDefaultLatencyWarningThreshold)
+		c.LatencyWarningThreshold = configDefaultLatencyWarningThreshold
+	}
+
+	if c.LatencyWarningWindows <= 0 {
+		log.Warnf("Exchange latency warning windows value not set, defaulting to %d.", configDefaultLatencyWarningWindows)
+		c.LatencyWarningWindows = configDefaultLatencyWarningWindows
+	}
+
+	if c.BalanceChangeThreshold < 0 {
+		log.Warn("Balance change threshold cannot be negative, notifying on all balance changes.")
+		c.BalanceChangeThreshold = 0
+	}
+
+	if c.TickerPriceJumpLimit != 0 && c.TickerPriceJumpLimit <= 1 {
+		log.Warnf("Ticker price jump limit %v must be greater than 1, disabling the check.",
+			c.TickerPriceJumpLimit)
+		c.TickerPriceJumpLimit = 0
+	}
+}
+
 // LoadConfig loads your configuration file into your configuration object
 func (c *Config) LoadConfig(configPath string) error {
 	err := c.ReadConfig(configPath)
@@ -1294,12 +2005,22 @@ func (c *Config) LoadConfig(configPath string) error {
 		return fmt.Errorf(ErrFailureOpeningConfig, configPath, err)
 	}
 
-	return c.CheckConfig()
+	err = c.CheckConfig()
+	if err != nil {
+		return err
+	}
+	c.mergeCurrencies()
+	return nil
 }
 
 // UpdateConfig updates the config with a supplied config file
-func (c *Config) UpdateConfig(configPath string, newCfg Config) error {
-	err := newCfg.CheckConfig()
+func (c *Config) UpdateConfig(configPath string, newCfg *Config) error {
+	err := newCfg.UpgradeConfig()
+	if err != nil {
+		return err
+	}
+
+	err = newCfg.CheckConfig()
 	if err != nil {
 		return err
 	}
@@ -1308,10 +2029,24 @@ func (c *Config) UpdateConfig(configPath string, newCfg Config) error {
 	c.EncryptConfig = newCfg.EncryptConfig
 	c.Currency = newCfg.Currency
 	c.GlobalHTTPTimeout = newCfg.GlobalHTTPTimeout
-	c.Portfolio = newCfg.Portfolio
+	c.ExchangeStartupTimeout = newCfg.ExchangeStartupTimeout
+	c.PairUpdateInterval = newCfg.PairUpdateInterval
+	c.NotifyNewPairs = newCfg.NotifyNewPairs
+	c.BalanceRefreshInterval = newCfg.BalanceRefreshInterval
+	c.BalanceChangeThreshold = newCfg.BalanceChangeThreshold
+	c.NotifyDeposits = newCfg.NotifyDeposits
+	c.MaintenanceProbeInterval = newCfg.MaintenanceProbeInterval
+	c.MarketDataMaxAge = newCfg.MarketDataMaxAge
+	c.LatencyWarningThreshold = newCfg.LatencyWarningThreshold
+	c.LatencyWarningWindows = newCfg.LatencyWarningWindows
+	c.TickerPriceJumpLimit = newCfg.TickerPriceJumpLimit
+	c.Portfolio.Addresses = newCfg.Portfolio.Addresses
+	c.PortfolioWatcher = newCfg.PortfolioWatcher
+	c.Events = newCfg.Events
 	c.Communications = newCfg.Communications
 	c.Webserver = newCfg.Webserver
 	c.Exchanges = newCfg.Exchanges
+	c.mergeCurrencies()
 
 	err = c.SaveConfig(configPath)
 	if err != nil {
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// configDefaultBotName is the bot name of a generated config
+const configDefaultBotName = "Skynet"
+
+// ExchangeDefaults returns the default config of a supported exchange by
+// name. It is set by the bot so sparse exchange configs, which only specify
+// the settings a user changes, are completed on load
+var ExchangeDefaults func(name string) (ExchangeConfig, error)
+
+// mergeExchangeDefaults fills the sections missing from an exchange config
+// with the exchange defaults. Exchanges without defaults are left unchanged so
+// the config checks report the missing sections
+func mergeExchangeDefaults(exch *ExchangeConfig) {
+	if ExchangeDefaults == nil {
+		return
+	}
+	defaults, err := ExchangeDefaults(exch.Name)
+	if err != nil {
+		return
+	}
+
+	var merged []string
+	if exch.AvailablePairs == "" && defaults.AvailablePairs != "" {
+		// Refresh the default available pairs on startup if the exchange
+		// supports it
+		exch.AvailablePairs = defaults.AvailablePairs
+		exch.SupportsAutoPairUpdates = defaults.SupportsAutoPairUpdates
+		merged = append(merged, "availablePairs")
+	}
+	if exch.EnabledPairs == "" && defaults.EnabledPairs != "" {
+		exch.EnabledPairs = defaults.EnabledPairs
+		merged = append(merged, "enabledPairs")
+	}
+	if exch.BaseCurrencies == "" && defaults.BaseCurrencies != "" {
+		exch.BaseCurrencies = defaults.BaseCurrencies
+		merged = append(merged, "baseCurrencies")
+	}
+	if exch.AssetTypes == "" && defaults.AssetTypes != "" {
+		exch.AssetTypes = defaults.AssetTypes
+		merged = append(merged, "assetTypes")
+	}
+	if exch.ConfigCurrencyPairFormat == nil && defaults.ConfigCurrencyPairFormat != nil {
+		format := *defaults.ConfigCurrencyPairFormat
+		exch.ConfigCurrencyPairFormat = &format
+		merged = append(merged, "configCurrencyPairFormat")
+	}
+	if exch.RequestCurrencyPairFormat == nil && defaults.RequestCurrencyPairFormat != nil {
+		format := *defaults.RequestCurrencyPairFormat
+		exch.RequestCurrencyPairFormat = &format
+		merged = append(merged, "requestCurrencyPairFormat")
+	}
+	if exch.RESTPollingDelay <= 0 && defaults.RESTPollingDelay > 0 {
+		exch.RESTPollingDelay = defaults.RESTPollingDelay
+		merged = append(merged, "restPollingDelay")
+	}
+
+	if len(merged) > 0 {
+		log.Debugf("Exchange %s: Using defaults for %s.", exch.Name,
+			common.JoinStrings(merged, ", "))
+	}
+}
+
+// GenerateMinimalConfig returns a config which enables only the supplied
+// exchanges and holds the global settings needed to start the bot, every
+// other setting is filled with its default on load
+func GenerateMinimalConfig(exchanges []string) (*Config, error) {
+	if len(exchanges) == 0 {
+		return nil, errors.New("no exchanges supplied")
+	}
+
+	c := &Config{
+		ConfigVersion:     CurrentConfigVersion,
+		Name:              configDefaultBotName,
+		EncryptConfig:     configFileEncryptionDisabled,
+		GlobalHTTPTimeout: configDefaultHTTPTimeout,
+	}
+	for x := range exchanges {
+		name := common.TrimString(exchanges[x], " ")
+		if name == "" {
+			return nil, fmt.Errorf(ErrExchangeNameEmpty, x)
+		}
+		if ExchangeDefaults != nil {
+			defaults, err := ExchangeDefaults(name)
+			if err != nil {
+				return nil, err
+			}
+			if defaults.EnabledPairs == "" {
+				return nil, fmt.Errorf("exchange %s has no default currency pairs, configure its pairs instead",
+					defaults.Name)
+			}
+			name = defaults.Name
+		}
+		for y := range c.Exchanges {
+			if common.StringToLower(c.Exchanges[y].Name) == common.StringToLower(name) {
+				return nil, fmt.Errorf("exchange %s supplied more than once", name)
+			}
+		}
+		c.Exchanges = append(c.Exchanges, ExchangeConfig{
+			Name:      name,
+			Enabled:   true,
+			APIKey:    DefaultUnsetAPIKey,
+			APISecret: DefaultUnsetAPISecret,
+		})
+	}
+	return c, nil
+}
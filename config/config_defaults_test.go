@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testExchangeDefaults returns defaults for Bitstamp only
+func testExchangeDefaults(name string) (ExchangeConfig, error) {
+	if name != "Bitstamp" {
+		return ExchangeConfig{}, errors.New("exchange not found")
+	}
+	return ExchangeConfig{
+		Name:                      "Bitstamp",
+		RESTPollingDelay:          10,
+		AvailablePairs:            "BTCUSD,BTCEUR",
+		EnabledPairs:              "BTCUSD",
+		BaseCurrencies:            "USD,EUR",
+		AssetTypes:                "SPOT",
+		SupportsAutoPairUpdates:   true,
+		ConfigCurrencyPairFormat:  &CurrencyPairFormatConfig{Uppercase: true},
+		RequestCurrencyPairFormat: &CurrencyPairFormatConfig{Uppercase: false},
+	}, nil
+}
+
+func TestLoadSparseConfig(t *testing.T) {
+	defer func(defaults func(string) (ExchangeConfig, error)) {
+		ExchangeDefaults = defaults
+	}(ExchangeDefaults)
+
+	dir, err := ioutil.TempDir("", "gct-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ConfigFile)
+	err = ioutil.WriteFile(path, []byte(`{"encryptConfig":-1,"exchanges":[`+
+		`{"name":"Bitstamp","enabled":true,"enabledPairs":"BTCEUR"}]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c Config
+	ExchangeDefaults = nil
+	if err = c.LoadConfig(path); err == nil {
+		t.Error("Test failed. Expected a sparse config to fail to load without exchange defaults")
+	}
+
+	ExchangeDefaults = testExchangeDefaults
+	c = Config{}
+	err = c.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Test failed. LoadConfig error: %s", err)
+	}
+
+	exch, err := c.GetExchangeConfig("Bitstamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.AvailablePairs != "BTCUSD,BTCEUR" || exch.EnabledPairs != "BTCEUR" ||
+		exch.BaseCurrencies != "USD,EUR" || exch.AssetTypes != "SPOT" ||
+		!exch.SupportsAutoPairUpdates || exch.RESTPollingDelay != 10 ||
+		exch.ConfigCurrencyPairFormat == nil || exch.RequestCurrencyPairFormat == nil ||
+		exch.HTTPTimeout != configDefaultHTTPTimeout {
+		t.Errorf("Test failed. Unexpected merged exchange config %+v", exch)
+	}
+	if c.GlobalHTTPTimeout != configDefaultHTTPTimeout || c.Currency.FiatDisplayCurrency != "USD" {
+		t.Errorf("Test failed. Expected global defaults, got %v %s", c.GlobalHTTPTimeout,
+			c.Currency.FiatDisplayCurrency)
+	}
+
+	// The merged formats must not be shared with the defaults
+	exch.ConfigCurrencyPairFormat.Delimiter = "-"
+	defaults, _ := testExchangeDefaults("Bitstamp")
+	if defaults.ConfigCurrencyPairFormat.Delimiter != "" {
+		t.Error("Test failed. Merged pair format shares the default")
+	}
+}
+
+func TestGenerateMinimalConfig(t *testing.T) {
+	defer func(defaults func(string) (ExchangeConfig, error)) {
+		ExchangeDefaults = defaults
+	}(ExchangeDefaults)
+	ExchangeDefaults = func(name string) (ExchangeConfig, error) {
+		if name == "bitstamp" {
+			return testExchangeDefaults("Bitstamp")
+		}
+		return testExchangeDefaults(name)
+	}
+
+	for _, exchanges := range [][]string{
+		nil,
+		{"Kraken"},
+		{"Bitstamp", "bitstamp"},
+		{" "},
+	} {
+		if _, err := GenerateMinimalConfig(exchanges); err == nil {
+			t.Errorf("Test failed. Expected an error generating a config for %v", exchanges)
+		}
+	}
+
+	c, err := GenerateMinimalConfig([]string{" bitstamp"})
+	if err != nil {
+		t.Fatalf("Test failed. GenerateMinimalConfig error: %s", err)
+	}
+	if len(c.Exchanges) != 1 || c.Exchanges[0].Name != "Bitstamp" ||
+		!c.Exchanges[0].Enabled || c.EncryptConfig != configFileEncryptionDisabled ||
+		c.ConfigVersion != CurrentConfigVersion {
+		t.Fatalf("Test failed. Unexpected minimal config %+v", c)
+	}
+
+	err = c.CheckConfig()
+	if err != nil {
+		t.Fatalf("Test failed. Minimal config check error: %s", err)
+	}
+	if c.Exchanges[0].EnabledPairs != "BTCUSD" || c.Exchanges[0].HTTPTimeout != time.Second*15 {
+		t.Errorf("Test failed. Expected the minimal config to use defaults, got %+v",
+			c.Exchanges[0])
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	log "github.com/thrasher-/gocryptotrader/logger"
@@ -26,9 +27,27 @@ const (
 	errAESBlockSize = "The config file data is too small for the AES required block size"
 )
 
+// Config encryption key errors
+var (
+	// ErrNoEncryptionKey is returned when an encrypted config is saved without
+	// a key entered or cached for the session
+	ErrNoEncryptionKey = errors.New("config encryption is enabled but no encryption key is available, the config was not saved")
+	// ErrInvalidEncryptionKey is returned when a config encryption key is
+	// empty or doesn't decrypt the config
+	ErrInvalidEncryptionKey = errors.New("invalid config encryption key")
+	// ErrConfigNotEncrypted is returned when changing the encryption key of a
+	// config file which isn't encrypted
+	ErrConfigNotEncrypted = errors.New("config file is not encrypted")
+)
+
+// The session key is the validated config key cached in memory after the
+// config is decrypted or first encrypted, sessionDK is derived from it with
+// storedSalt and is used by every save until the key is cleared on shutdown
 var (
 	storedSalt []byte
 	sessionDK  []byte
+	sessionKey []byte
+	keyMtx     sync.Mutex
 )
 
 // PromptForConfigEncryption asks for encryption key
@@ -94,18 +113,30 @@ func PromptForConfigKey(initialSetup bool) ([]byte, error) {
 }
 
 // EncryptConfigFile encrypts configuration data that is parsed in with a key
-// and returns it as a byte array with an error
+// and returns it as a byte array with an error. The key becomes the session
+// key, an empty key encrypts with the cached session key instead
 func EncryptConfigFile(configData, key []byte) ([]byte, error) {
-	var err error
+	keyMtx.Lock()
+	defer keyMtx.Unlock()
 
-	if len(sessionDK) == 0 {
-		sessionDK, err = makeNewSessionDK(key)
+	if len(key) != 0 && (len(sessionDK) == 0 || !bytes.Equal(key, sessionKey)) {
+		dk, salt, err := deriveSessionDK(key)
 		if err != nil {
 			return nil, err
 		}
+		setSessionKey(key, dk, salt)
+	}
+
+	if len(sessionDK) == 0 {
+		return nil, ErrNoEncryptionKey
 	}
+	return encryptConfigData(configData, sessionDK, storedSalt)
+}
 
-	block, err := aes.NewCipher(sessionDK)
+// encryptConfigData encrypts configuration data with a derived key and
+// prefixes it with the encryption confirmation string and the key salt
+func encryptConfigData(configData, dk, salt []byte) ([]byte, error) {
+	block, err := aes.NewCipher(dk)
 	if err != nil {
 		return nil, err
 	}
@@ -120,16 +151,17 @@ func EncryptConfigFile(configData, key []byte) ([]byte, error) {
 	stream.XORKeyStream(ciphertext[aes.BlockSize:], configData)
 
 	appendedFile := []byte(EncryptConfirmString)
-	appendedFile = append(appendedFile, storedSalt...)
+	appendedFile = append(appendedFile, salt...)
 	appendedFile = append(appendedFile, ciphertext...)
 	return appendedFile, nil
 }
 
 // DecryptConfigFile decrypts configuration data with the supplied key and
-// returns the un-encrypted file as a byte array with an error
+// returns the un-encrypted file as a byte array with an error. The key isn't
+// cached as a wrong key still decrypts, the caller caches it with
+// CacheConfigEncryptionKey once the result is validated
 func DecryptConfigFile(configData, key []byte) ([]byte, error) {
 	configData = RemoveECS(configData)
-	origKey := key
 
 	if ConfirmSalt(configData) {
 		salt := make([]byte, len(SaltPrefix)+SaltRandomLength)
@@ -158,14 +190,98 @@ func DecryptConfigFile(configData, key []byte) ([]byte, error) {
 
 	stream := cipher.NewCFBDecrypter(blockDecrypt, iv)
 	stream.XORKeyStream(configData, configData)
-	result := configData
+	return configData, nil
+}
 
-	sessionDK, err = makeNewSessionDK(origKey)
+// CacheConfigEncryptionKey derives a session key from a validated config key
+// and caches it in memory so later saves are encrypted with it
+func CacheConfigEncryptionKey(key []byte) error {
+	dk, salt, err := deriveSessionDK(key)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return result, nil
+	keyMtx.Lock()
+	setSessionKey(key, dk, salt)
+	keyMtx.Unlock()
+	return nil
+}
+
+// ClearConfigEncryptionKey zeroes and removes the cached session key, saving
+// an encrypted config afterwards requires the key to be entered again
+func ClearConfigEncryptionKey() {
+	keyMtx.Lock()
+	setSessionKey(nil, nil, nil)
+	keyMtx.Unlock()
+}
+
+// setSessionKey replaces the cached session key after zeroing the previous
+// one, keyMtx must be held
+func setSessionKey(key, dk, salt []byte) {
+	zeroBytes(sessionKey)
+	zeroBytes(sessionDK)
+	sessionKey, sessionDK, storedSalt = nil, dk, salt
+	if len(key) != 0 {
+		sessionKey = append([]byte(nil), key...)
+	}
+}
+
+// zeroBytes overwrites a key held in memory
+func zeroBytes(b []byte) {
+	for x := range b {
+		b[x] = 0
+	}
+}
+
+// ChangeConfigEncryptionKey decrypts the encrypted config file at configPath
+// with the old key, re-encrypts it with the new key and atomically replaces
+// the file. The new key becomes the session key used by later saves
+func ChangeConfigEncryptionKey(configPath string, oldKey, newKey []byte) error {
+	if len(newKey) == 0 {
+		return fmt.Errorf("%w, the new key is empty", ErrInvalidEncryptionKey)
+	}
+
+	defaultPath, err := GetFilePath(configPath)
+	if err != nil {
+		return err
+	}
+
+	file, err := common.ReadFile(defaultPath)
+	if err != nil {
+		return err
+	}
+
+	if !ConfirmECS(file) {
+		return ErrConfigNotEncrypted
+	}
+
+	data, err := DecryptConfigFile(file, oldKey)
+	if err == nil {
+		var c Config
+		err = ConfirmConfigJSON(data, &c)
+	}
+	if err != nil {
+		return fmt.Errorf("%w, the old key doesn't decrypt the config", ErrInvalidEncryptionKey)
+	}
+
+	dk, salt, err := deriveSessionDK(newKey)
+	if err != nil {
+		return err
+	}
+
+	payload, err := encryptConfigData(data, dk, salt)
+	if err != nil {
+		return err
+	}
+
+	keyMtx.Lock()
+	defer keyMtx.Unlock()
+	err = common.WriteFileAtomic(defaultPath, payload)
+	if err != nil {
+		return err
+	}
+	setSessionKey(newKey, dk, salt)
+	return nil
 }
 
 // ConfirmConfigJSON confirms JSON in file
@@ -183,9 +299,10 @@ func ConfirmECS(file []byte) bool {
 	return bytes.Contains(file, []byte(EncryptConfirmString))
 }
 
-// RemoveECS removes encryption confirmation string
+// RemoveECS removes the encryption confirmation string prefix, the encrypted
+// data following it is left intact
 func RemoveECS(file []byte) []byte {
-	return bytes.Trim(file, EncryptConfirmString)
+	return bytes.TrimPrefix(file, []byte(EncryptConfirmString))
 }
 
 func getScryptDK(key, salt []byte) ([]byte, error) {
@@ -195,17 +312,17 @@ func getScryptDK(key, salt []byte) ([]byte, error) {
 	return scrypt.Key(key, salt, 32768, 8, 1, 32)
 }
 
-func makeNewSessionDK(key []byte) ([]byte, error) {
-	var err error
-	storedSalt, err = common.GetRandomSalt([]byte(SaltPrefix), SaltRandomLength)
+// deriveSessionDK derives an encryption key from a config key with a new
+// random salt
+func deriveSessionDK(key []byte) ([]byte, []byte, error) {
+	salt, err := common.GetRandomSalt([]byte(SaltPrefix), SaltRandomLength)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	dk, err := getScryptDK(key, storedSalt)
+	dk, err := getScryptDK(key, salt)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return dk, nil
+	return dk, salt, nil
 }
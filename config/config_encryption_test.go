@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/thrasher-/gocryptotrader/common"
@@ -40,10 +44,16 @@ func TestEncryptConfigFile(t *testing.T) {
 		t.Fatal("Test failed. Expected different result")
 	}
 
-	sessionDK, err = makeNewSessionDK([]byte("asdf"))
+	err = CacheConfigEncryptionKey(nil)
+	if err == nil {
+		t.Fatal("Test failed. CacheConfigEncryptionKey passed with nil key")
+	}
+
+	err = CacheConfigEncryptionKey([]byte("asdf"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer ClearConfigEncryptionKey()
 
 	_, err = EncryptConfigFile([]byte("test"), []byte("key"))
 	if err != nil {
@@ -116,13 +126,196 @@ func TestRemoveECS(t *testing.T) {
 	if string(isremoved) != "" {
 		t.Errorf("Test failed. TestConfirmECS: Error ECS not deleted.")
 	}
+	// Encrypted data ending in characters of the confirmation string is kept
+	isremoved = RemoveECS([]byte(EncryptConfirmString + "dataTHOR"))
+	if string(isremoved) != "dataTHOR" {
+		t.Errorf("Test failed. TestRemoveECS: Expected %q, got %q", "dataTHOR", isremoved)
+	}
 }
 
-func TestMakeNewSessionDK(t *testing.T) {
+func TestDeriveSessionDK(t *testing.T) {
 	t.Parallel()
 
-	_, err := makeNewSessionDK(nil)
+	_, _, err := deriveSessionDK(nil)
 	if err == nil {
-		t.Fatal("Test failed. makeNewSessionDK passed with nil key")
+		t.Fatal("Test failed. deriveSessionDK passed with nil key")
+	}
+
+	dk, salt, err := deriveSessionDK([]byte("asdf"))
+	if err != nil || len(dk) != 32 || len(salt) == 0 {
+		t.Fatalf("Test failed. deriveSessionDK unexpected result %v", err)
+	}
+}
+
+// withStdin runs f with the supplied input available on stdin
+func withStdin(t *testing.T, input string, f func()) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.WriteString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		r.Close()
+	}()
+	f()
+}
+
+// decryptTestConfig decrypts the config file at path with a key
+func decryptTestConfig(t *testing.T, path, key string) (*Config, error) {
+	file, err := common.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ConfirmECS(file) {
+		t.Fatal("Test failed. Config file isn't encrypted")
+	}
+	data, err := DecryptConfigFile(file, []byte(key))
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	err = ConfirmConfigJSON(data, &c)
+	return &c, err
+}
+
+func TestSaveConfigAfterLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gctconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer ClearConfigEncryptionKey()
+	defer func(initialSetup bool) { IsInitialSetup = initialSetup }(IsInitialSetup)
+	IsInitialSetup = false
+	path := filepath.Join(dir, "config.json")
+
+	var c Config
+	err = c.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.EncryptConfig = configFileEncryptionEnabled
+
+	// Without a cached key the save fails instead of writing the config
+	ClearConfigEncryptionKey()
+	err = c.SaveConfig(path)
+	if err != ErrNoEncryptionKey {
+		t.Errorf("Test failed. Expected %v, got %v", ErrNoEncryptionKey, err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Test failed. Config saved without an encryption key")
+	}
+
+	err = CacheConfigEncryptionKey([]byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.SaveConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ClearConfigEncryptionKey()
+
+	var loaded Config
+	withStdin(t, "key\n", func() {
+		err = loaded.ReadConfig(path)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded.Name = "Saved after load"
+	err = loaded.SaveConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := decryptTestConfig(t, path, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Name != "Saved after load" {
+		t.Errorf("Test failed. Expected config name %q, got %q", "Saved after load",
+			saved.Name)
+	}
+}
+
+func TestChangeConfigEncryptionKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gctconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer ClearConfigEncryptionKey()
+	path := filepath.Join(dir, "config.json")
+
+	var c Config
+	err = c.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unencrypted config can't have its key changed
+	err = c.SaveConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ChangeConfigEncryptionKey(path, []byte("old"), []byte("new"))
+	if err != ErrConfigNotEncrypted {
+		t.Errorf("Test failed. Expected %v, got %v", ErrConfigNotEncrypted, err)
+	}
+
+	c.EncryptConfig = configFileEncryptionEnabled
+	err = CacheConfigEncryptionKey([]byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.SaveConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ChangeConfigEncryptionKey(path, []byte("wrong"), []byte("new"))
+	if !errors.Is(err, ErrInvalidEncryptionKey) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrInvalidEncryptionKey, err)
+	}
+	err = ChangeConfigEncryptionKey(path, []byte("old"), nil)
+	if !errors.Is(err, ErrInvalidEncryptionKey) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrInvalidEncryptionKey, err)
+	}
+	if _, err = decryptTestConfig(t, path, "old"); err != nil {
+		t.Fatalf("Test failed. Config rewritten by a failed key change: %s", err)
+	}
+
+	err = ChangeConfigEncryptionKey(path, []byte("old"), []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = decryptTestConfig(t, path, "old"); err == nil {
+		t.Error("Test failed. Config still decrypts with the old key")
+	}
+	if _, err = decryptTestConfig(t, path, "new"); err != nil {
+		t.Fatalf("Test failed. Config doesn't decrypt with the new key: %s", err)
+	}
+
+	// Later saves use the new key
+	c.Name = "Rotated"
+	err = c.SaveConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := decryptTestConfig(t, path, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Name != "Rotated" {
+		t.Errorf("Test failed. Expected config name %q, got %q", "Rotated", saved.Name)
 	}
 }
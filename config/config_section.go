@@ -0,0 +1,403 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Config section errors
+var (
+	ErrConfigSectionNotFound = errors.New("config section not found")
+	ErrInvalidConfigSection  = errors.New("invalid config section update")
+	ErrConfigSectionSecret   = errors.New("config section holds credentials")
+)
+
+// sectionMtx serialises section updates so an update can't be lost to a
+// concurrent update of the same section
+var sectionMtx sync.Mutex
+
+// readOnlySections are the top level config sections which can't be updated
+// at runtime, either because they're managed elsewhere or are deprecated
+var readOnlySections = map[string]bool{
+	"configVersion":      true,
+	"encryptConfig":      true,
+	"logging":            true,
+	"portfolioAddresses": true,
+	"currencyPairFormat": true,
+	"fiatDispayCurrency": true,
+	"cryptocurrencies":   true,
+	"smsGlobal":          true,
+}
+
+// sectionChecks validate the config after an update of a top level section,
+// top level values which aren't listed are checked by checkGlobalValues
+var sectionChecks = map[string]func(c *Config) error{
+	"currencyConfig": (*Config).CheckCurrencyConfigValues,
+	"communications": (*Config).checkCommunicationsSection,
+	"portfolioWatcher": func(c *Config) error {
+		c.CheckPortfolioWatcherConfig()
+		return nil
+	},
+	"websocketReconnect": func(c *Config) error {
+		c.CheckWebsocketReconnectConfig()
+		return nil
+	},
+	"events": func(c *Config) error {
+		c.CheckEventsConfig()
+		return nil
+	},
+	"webserver": func(c *Config) error {
+		if !c.Webserver.Enabled {
+			return nil
+		}
+		return c.CheckWebserverConfigValues()
+	},
+	"bankAccounts": (*Config).CheckClientBankAccounts,
+}
+
+// credentialFields are the lower case JSON names of the config values holding
+// credentials, they're never returned by GetSection
+var credentialFields = map[string]bool{
+	"apikey":            true,
+	"apisecret":         true,
+	"apiauthpemkey":     true,
+	"clientid":          true,
+	"adminusername":     true,
+	"adminpassword":     true,
+	"password":          true,
+	"accountpassword":   true,
+	"verificationtoken": true,
+	"signaturesecret":   true,
+	"headers":           true,
+}
+
+// SectionUpdate describes an applied config section update. Root is the JSON
+// name of the updated top level section and Exchange the name of the updated
+// exchange when the root is exchanges
+type SectionUpdate struct {
+	Path     string `json:"path"`
+	Root     string `json:"root"`
+	Exchange string `json:"exchange,omitempty"`
+}
+
+// GetSection returns the config section at a dot separated path such as
+// "exchanges.Bitfinex" or "communications.slack" with its credentials
+// removed. Path segments match JSON or field names ignoring case and slice
+// elements are selected by their name or label
+func (c *Config) GetSection(path string) (interface{}, error) {
+	segments, err := splitSectionPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if credentialFields[strings.ToLower(segments[len(segments)-1])] {
+		return nil, ErrConfigSectionSecret
+	}
+
+	cfg, err := c.copyConfig()
+	if err != nil {
+		return nil, err
+	}
+	target, err := findSection(reflect.ValueOf(cfg).Elem(), segments)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(target.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var section interface{}
+	err = json.Unmarshal(data, &section)
+	if err != nil {
+		return nil, err
+	}
+	return removeCredentials(section), nil
+}
+
+// UpdateSection merges a JSON payload into the config section at a dot
+// separated path, see GetSection. Struct values not set by the payload keep
+// their current values. The update is validated against a copy of the config
+// using the section's check function and only applied if it passes, with any
+// defaults the check sets within the section. Only the section at the path is
+// written to the config, so concurrent writes elsewhere in the config aren't
+// lost. Exchanges are updated individually and can't be renamed
+func (c *Config) UpdateSection(path string, payload []byte) (SectionUpdate, error) {
+	segments, err := splitSectionPath(path)
+	if err != nil {
+		return SectionUpdate{}, err
+	}
+
+	sectionMtx.Lock()
+	defer sectionMtx.Unlock()
+
+	cfg, err := c.copyConfig()
+	if err != nil {
+		return SectionUpdate{}, err
+	}
+	v := reflect.ValueOf(cfg).Elem()
+	if _, ok := sectionField(v, segments[0]); !ok {
+		return SectionUpdate{}, fmt.Errorf("%w: %s", ErrConfigSectionNotFound, segments[0])
+	}
+	update := SectionUpdate{
+		Path: common.JoinStrings(segments, "."),
+		Root: jsonFieldName(v.Type(), segments[0]),
+	}
+	if readOnlySections[update.Root] {
+		return SectionUpdate{}, fmt.Errorf("%w: %s can't be updated at runtime",
+			ErrInvalidConfigSection, update.Root)
+	}
+	if update.Root == "exchanges" {
+		if len(segments) < 2 {
+			return SectionUpdate{}, fmt.Errorf("%w: exchanges must be updated individually",
+				ErrInvalidConfigSection)
+		}
+		update.Exchange = segments[1]
+	}
+
+	target, err := findSection(v, segments)
+	if err != nil {
+		return SectionUpdate{}, err
+	}
+	err = json.Unmarshal(payload, target.Addr().Interface())
+	if err != nil {
+		return SectionUpdate{}, fmt.Errorf("%w: %s", ErrInvalidConfigSection, err)
+	}
+
+	var exch int
+	switch {
+	case update.Root == "exchanges":
+		exch, err = cfg.checkExchangeSection(update.Exchange)
+		if err == nil {
+			update.Exchange = cfg.Exchanges[exch].Name
+		}
+	case sectionChecks[update.Root] != nil:
+		err = sectionChecks[update.Root](cfg)
+	default:
+		err = cfg.checkGlobalSection()
+	}
+	if err != nil {
+		return SectionUpdate{}, fmt.Errorf("%w: %s", ErrInvalidConfigSection, err)
+	}
+
+	// Only the updated path is merged into the live config, the rest of it
+	// may have been written since it was copied
+	target, err = findSection(v, segments)
+	if err != nil {
+		return SectionUpdate{}, err
+	}
+	m.Lock()
+	live, err := findSection(reflect.ValueOf(c).Elem(), segments)
+	if err == nil {
+		live.Set(target)
+	}
+	m.Unlock()
+	if err != nil {
+		return SectionUpdate{}, err
+	}
+
+	if update.Root == "currencyConfig" {
+		c.mergeCurrencies()
+	}
+	return update, nil
+}
+
+// copyConfig returns a deep copy of the config
+func (c *Config) copyConfig() (*Config, error) {
+	m.Lock()
+	data, err := json.Marshal(c)
+	m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	err = json.Unmarshal(data, &cfg)
+	return &cfg, err
+}
+
+// checkExchangeSection validates an updated exchange on its own and returns
+// its index. The update is rejected if it renames the exchange, if the
+// exchange is disabled for invalid settings or if it disables the last
+// enabled exchange when the bot requires one
+func (c *Config) checkExchangeSection(name string) (int, error) {
+	exch := -1
+	for x := range c.Exchanges {
+		if strings.EqualFold(c.Exchanges[x].Name, name) {
+			exch = x
+			break
+		}
+	}
+	if exch == -1 {
+		return 0, fmt.Errorf("exchange %s can't be renamed", name)
+	}
+
+	check, err := c.copyConfig()
+	if err != nil {
+		return 0, err
+	}
+	check.Exchanges = []ExchangeConfig{c.Exchanges[exch]}
+	check.AllowNoExchanges = true
+	err = check.CheckExchangeConfigValues()
+	if err != nil {
+		return 0, err
+	}
+	if c.Exchanges[exch].Enabled && !check.Exchanges[0].Enabled {
+		return 0, fmt.Errorf("exchange %s currency pair formats are invalid", name)
+	}
+	c.Exchanges[exch] = check.Exchanges[0]
+
+	if c.CountEnabledExchanges() == 0 && !c.AllowsNoExchanges() {
+		return 0, errors.New(ErrNoEnabledExchanges)
+	}
+	return exch, nil
+}
+
+// checkCommunicationsSection checks the communications config, rejecting
+// mediums which are enabled without the settings they require instead of
+// disabling them
+func (c *Config) checkCommunicationsSection() error {
+	enabled := communicationsEnabled(&c.Communications)
+	c.CheckCommunicationsConfig()
+	after := communicationsEnabled(&c.Communications)
+	for name := range enabled {
+		if enabled[name] && !after[name] {
+			return fmt.Errorf("%s enabled but its settings are not set", name)
+		}
+	}
+	return nil
+}
+
+// communicationsEnabled returns whether each communication medium is enabled
+func communicationsEnabled(comms *CommunicationsConfig) map[string]bool {
+	return map[string]bool{
+		"Slack":     comms.SlackConfig.Enabled,
+		"SMSGlobal": comms.SMSGlobalConfig.Enabled,
+		"SMTP":      comms.SMTPConfig.Enabled,
+		"Telegram":  comms.TelegramConfig.Enabled,
+		"Webhook":   comms.WebhookConfig.Enabled,
+	}
+}
+
+// checkGlobalSection checks the top level config values
+func (c *Config) checkGlobalSection() error {
+	c.checkGlobalValues()
+	if c.CountEnabledExchanges() == 0 && !c.AllowsNoExchanges() {
+		return errors.New(ErrNoEnabledExchanges)
+	}
+	return nil
+}
+
+// splitSectionPath splits a dot separated config section path
+func splitSectionPath(path string) ([]string, error) {
+	segments := common.SplitStrings(path, ".")
+	for x := range segments {
+		if segments[x] == "" {
+			return nil, fmt.Errorf("%w: invalid path %q", ErrConfigSectionNotFound, path)
+		}
+	}
+	return segments, nil
+}
+
+// findSection returns the value at the path segments below v, allocating nil
+// pointers on the way
+func findSection(v reflect.Value, segments []string) (reflect.Value, error) {
+	for x := range segments {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+
+		var ok bool
+		switch v.Kind() {
+		case reflect.Struct:
+			v, ok = sectionField(v, segments[x])
+		case reflect.Slice:
+			v, ok = sectionElement(v, segments[x])
+		}
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrConfigSectionNotFound,
+				common.JoinStrings(segments[:x+1], "."))
+		}
+	}
+	return v, nil
+}
+
+// sectionField returns the exported field of a struct whose JSON or field
+// name matches name ignoring case
+func sectionField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for x := 0; x < t.NumField(); x++ {
+		f := t.Field(x)
+		if f.PkgPath != "" {
+			continue
+		}
+		if strings.EqualFold(jsonName(f), name) || strings.EqualFold(f.Name, name) {
+			return v.Field(x), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// jsonFieldName returns the JSON name of the struct field matching name
+func jsonFieldName(t reflect.Type, name string) string {
+	for x := 0; x < t.NumField(); x++ {
+		f := t.Field(x)
+		if strings.EqualFold(jsonName(f), name) || strings.EqualFold(f.Name, name) {
+			return jsonName(f)
+		}
+	}
+	return name
+}
+
+// jsonName returns the name a struct field is encoded with
+func jsonName(f reflect.StructField) string {
+	name := common.SplitStrings(f.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// sectionElement returns the element of a slice of structs whose Name or
+// Label field matches name ignoring case
+func sectionElement(v reflect.Value, name string) (reflect.Value, bool) {
+	if v.Type().Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for x := 0; x < v.Len(); x++ {
+		for _, field := range []string{"Name", "Label"} {
+			f := v.Index(x).FieldByName(field)
+			if f.IsValid() && f.Kind() == reflect.String &&
+				strings.EqualFold(f.String(), name) {
+				return v.Index(x), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// removeCredentials removes the credential values from a decoded JSON value
+func removeCredentials(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key := range val {
+			if credentialFields[strings.ToLower(key)] {
+				delete(val, key)
+				continue
+			}
+			val[key] = removeCredentials(val[key])
+		}
+	case []interface{}:
+		for x := range val {
+			val[x] = removeCredentials(val[x])
+		}
+	}
+	return v
+}
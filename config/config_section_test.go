@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateSection(t *testing.T) {
+	var cfg Config
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := cfg.UpdateSection("exchanges.bitfinex.HTTPTimeout", []byte("20000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Root != "exchanges" || update.Exchange != "Bitfinex" {
+		t.Errorf("Test failed. Unexpected section update %+v", update)
+	}
+	exch, err := cfg.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.HTTPTimeout != time.Second*20 {
+		t.Errorf("Test failed. Expected HTTP timeout %v, got %v", time.Second*20,
+			exch.HTTPTimeout)
+	}
+
+	// A partial struct payload keeps the values it doesn't set
+	_, err = cfg.UpdateSection("communications.slack",
+		[]byte(`{"targetChannel":"alerts","verbose":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slack := cfg.GetCommunicationsConfig().SlackConfig
+	if slack.TargetChannel != "alerts" || !slack.Verbose || slack.VerificationToken != "testtest" {
+		t.Errorf("Test failed. Unexpected slack config %+v", slack)
+	}
+
+	update, err = cfg.UpdateSection("globalHTTPTimeout", []byte("-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Root != "globalHTTPTimeout" || cfg.GlobalHTTPTimeout != configDefaultHTTPTimeout {
+		t.Errorf("Test failed. Expected the global HTTP timeout default, got %v",
+			cfg.GlobalHTTPTimeout)
+	}
+}
+
+func TestUpdateSectionInvalid(t *testing.T) {
+	var cfg Config
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		payload string
+		err     error
+	}{
+		{"exchanges.Missing", `{}`, ErrConfigSectionNotFound},
+		{"exchanges..name", `{}`, ErrConfigSectionNotFound},
+		{"communications.irc", `{}`, ErrConfigSectionNotFound},
+		{"exchanges", `[]`, ErrInvalidConfigSection},
+		{"encryptConfig", `1`, ErrInvalidConfigSection},
+		{"exchanges.Bitfinex.httpTimeout", `"20s"`, ErrInvalidConfigSection},
+		{"exchanges.Bitfinex", `{"name":"Bitfinex2"}`, ErrInvalidConfigSection},
+		{"exchanges.Bitfinex.enabledPairs", `""`, ErrInvalidConfigSection},
+		// Slack can't be enabled with the default verification token
+		{"communications.slack.enabled", `true`, ErrInvalidConfigSection},
+		{"webserver", `{"enabled":true,"listenAddress":"9050"}`, ErrInvalidConfigSection},
+	}
+	for _, test := range tests {
+		_, err = cfg.UpdateSection(test.path, []byte(test.payload))
+		if !errors.Is(err, test.err) {
+			t.Errorf("Test failed. Update %s expected %v, got %v", test.path, test.err, err)
+		}
+	}
+
+	exch, err := cfg.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.EnabledPairs == "" || cfg.Communications.SlackConfig.Enabled ||
+		cfg.Webserver.Enabled {
+		t.Error("Test failed. Rejected updates were applied")
+	}
+}
+
+func TestGetSection(t *testing.T) {
+	var cfg Config
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	section, err := cfg.GetSection("exchanges.Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exch, ok := section.(map[string]interface{})
+	if !ok || exch["name"] != "Bitfinex" {
+		t.Fatalf("Test failed. Unexpected section %v", section)
+	}
+	for _, key := range []string{"apiKey", "apiSecret", "clientId"} {
+		if _, ok := exch[key]; ok {
+			t.Errorf("Test failed. Credential %s returned", key)
+		}
+	}
+
+	section, err = cfg.GetSection("webserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := section.(map[string]interface{})["adminPassword"]; ok {
+		t.Error("Test failed. Webserver admin password returned")
+	}
+
+	_, err = cfg.GetSection("communications.smtp.accountPassword")
+	if err != ErrConfigSectionSecret {
+		t.Errorf("Test failed. Expected %v, got %v", ErrConfigSectionSecret, err)
+	}
+}
+
+func TestUpdateSectionConcurrentReads(t *testing.T) {
+	var cfg Config
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for x := 0; x < 10; x++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := cfg.UpdateSection("exchanges.Bitfinex.httpTimeout",
+				[]byte("1000000000"))
+			if err != nil {
+				errs <- err
+			}
+			_, err = cfg.UpdateSection("communications.slack.targetChannel",
+				[]byte(`"general"`))
+			if err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			exch, err := cfg.GetExchangeConfig("Bitfinex")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if exch.HTTPTimeout <= 0 {
+				errs <- errors.New("read an unset HTTP timeout")
+			}
+			_, err = cfg.GetSection("communications")
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error("Test failed.", err)
+	}
+
+	exch, err := cfg.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.HTTPTimeout != time.Second {
+		t.Errorf("Test failed. Expected HTTP timeout %v, got %v", time.Second, exch.HTTPTimeout)
+	}
+}
+
+func TestUpdateSectionConcurrentWrites(t *testing.T) {
+	var cfg Config
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exch, err := cfg.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another exchange value is written until the section updates are done,
+	// the last write must not be lost to an update of a stale copy
+	done := make(chan struct{})
+	written := make(chan time.Duration)
+	go func() {
+		var delay time.Duration
+		for {
+			select {
+			case <-done:
+				written <- delay
+				return
+			default:
+			}
+			delay++
+			exch.RESTPollingDelay = delay
+			err := cfg.UpdateExchangeConfig(exch)
+			if err != nil {
+				t.Error("Test failed.", err)
+			}
+		}
+	}()
+	for x := 0; x < 50; x++ {
+		_, err = cfg.UpdateSection("exchanges.Bitfinex.httpTimeout", []byte("1000000000"))
+		if err != nil {
+			t.Error("Test failed.", err)
+		}
+	}
+	close(done)
+	delay := <-written
+
+	exch, err = cfg.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.RESTPollingDelay != delay {
+		t.Errorf("Test failed. Expected polling delay %v, got %v", delay, exch.RESTPollingDelay)
+	}
+}
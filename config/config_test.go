@@ -2,10 +2,13 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	log "github.com/thrasher-/gocryptotrader/logger"
+	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
 func TestGetCurrencyConfig(t *testing.T) {
@@ -83,6 +86,61 @@ func TestGetClientBankAccounts(t *testing.T) {
 	}
 }
 
+func TestFindBankAccounts(t *testing.T) {
+	c := Config{
+		Exchanges: []ExchangeConfig{{Name: "Bitfinex", BankAccounts: []BankAccount{
+			{AccountNumber: "1", SupportedCurrencies: "USDT,EURT"},
+			{AccountNumber: "2", SupportedCurrencies: "EUR, usd"},
+			{AccountNumber: "3", SupportedCurrencies: "USD"},
+		}}},
+		BankAccounts: []BankAccount{
+			{AccountNumber: "4", SupportedCurrencies: "USDT", SupportedExchanges: "ALL"},
+			{AccountNumber: "5", SupportedCurrencies: "USD", SupportedExchanges: "Krakenx"},
+			{AccountNumber: "6", SupportedCurrencies: "AUD,USD", SupportedExchanges: "ANX, kraken"},
+			{AccountNumber: "7", SupportedCurrencies: "USD", SupportedExchanges: "ALL"},
+		},
+	}
+
+	accounts, err := c.FindExchangeBankAccounts("Bitfinex", "USD")
+	if err != nil || len(accounts) != 2 || accounts[0].AccountNumber != "2" ||
+		accounts[1].AccountNumber != "3" {
+		t.Errorf("Test failed. FindExchangeBankAccounts unexpected accounts %+v %v", accounts, err)
+	}
+	account, err := c.GetExchangeBankAccounts("Bitfinex", "usd")
+	if err != nil || account.AccountNumber != "2" {
+		t.Errorf("Test failed. GetExchangeBankAccounts unexpected account %+v %v", account, err)
+	}
+	if _, err = c.FindExchangeBankAccounts("Bitfinex", "US"); err == nil {
+		t.Error("Test failed. FindExchangeBankAccounts matched a partial currency")
+	}
+
+	accounts, err = c.FindClientBankAccounts("Kraken", "USD")
+	if err != nil || len(accounts) != 2 || accounts[0].AccountNumber != "6" ||
+		accounts[1].AccountNumber != "7" {
+		t.Errorf("Test failed. FindClientBankAccounts unexpected accounts %+v %v", accounts, err)
+	}
+	account, err = c.GetClientBankAccounts("Bitstamp", "USDT")
+	if err != nil || account.AccountNumber != "4" {
+		t.Errorf("Test failed. GetClientBankAccounts unexpected account %+v %v", account, err)
+	}
+	if _, err = c.FindClientBankAccounts("Kraken", "EUR"); err == nil {
+		t.Error("Test failed. FindClientBankAccounts matched EUR to EURT")
+	}
+}
+
+func TestIsKnownCurrencyCode(t *testing.T) {
+	for code, expected := range map[string]bool{
+		"USD": true,
+		"GBP": true,
+		"BTC": true,
+		"XYZ": false,
+	} {
+		if isKnownCurrencyCode(code) != expected {
+			t.Errorf("Test failed. isKnownCurrencyCode(%s) expected %v", code, expected)
+		}
+	}
+}
+
 func TestUpdateClientBankAccounts(t *testing.T) {
 	cfg := GetConfig()
 	err := cfg.LoadConfig(ConfigTestFile)
@@ -206,6 +264,43 @@ func TestUpdateCryptocurrencyProviderConfig(t *testing.T) {
 	cfg.UpdateCryptocurrencyProviderConfig(orig)
 }
 
+func TestCheckPortfolioWatcherConfig(t *testing.T) {
+	var c Config
+	c.CheckPortfolioWatcherConfig()
+	if c.PortfolioWatcher.RefreshInterval != configDefaultPortfolioRefreshInterval {
+		t.Error("Test failed. CheckPortfolioWatcherConfig refresh interval not defaulted")
+	}
+	if len(c.PortfolioWatcher.Explorers) != len(portfolio.DefaultExplorers()) {
+		t.Error("Test failed. CheckPortfolioWatcherConfig explorers not defaulted")
+	}
+
+	c.PortfolioWatcher.Explorers = []portfolio.ExplorerSettings{
+		{Coin: "BTC", Name: portfolio.ExplorerCryptoID},
+		{Coin: "LTC", Name: portfolio.ExplorerCryptoID, APIURL: "https://chainz.cryptoid.info"},
+	}
+	c.CheckPortfolioWatcherConfig()
+	if len(c.PortfolioWatcher.Explorers) != 1 ||
+		c.PortfolioWatcher.Explorers[0].Coin != "LTC" {
+		t.Errorf("Test failed. CheckPortfolioWatcherConfig unexpected explorers %v",
+			c.PortfolioWatcher.Explorers)
+	}
+}
+
+func TestCheckEventsConfig(t *testing.T) {
+	var c Config
+	c.CheckEventsConfig()
+	if c.Events.CheckInterval != configDefaultEventsCheckInterval ||
+		c.Events.MinEvaluationInterval != configDefaultEventsMinEvaluation {
+		t.Errorf("Test failed. CheckEventsConfig intervals not defaulted %+v", c.Events)
+	}
+
+	c.Events = EventsConfig{CheckInterval: time.Second, MinEvaluationInterval: time.Millisecond}
+	c.CheckEventsConfig()
+	if c.Events.CheckInterval != time.Second || c.Events.MinEvaluationInterval != time.Millisecond {
+		t.Errorf("Test failed. CheckEventsConfig changed set intervals %+v", c.Events)
+	}
+}
+
 func TestCheckCommunicationsConfig(t *testing.T) {
 	cfg := GetConfig()
 	err := cfg.LoadConfig(ConfigTestFile)
@@ -223,31 +318,12 @@ func TestCheckCommunicationsConfig(t *testing.T) {
 			cfg.Communications)
 	}
 
-	cfg.SMS = &SMSGlobalConfig{}
 	cfg.Communications.SMSGlobalConfig.Name = ""
 	cfg.CheckCommunicationsConfig()
 	if cfg.Communications.SMSGlobalConfig.Password != "test" {
 		t.Error("Test failed. CheckCommunicationsConfig error:", err)
 	}
 
-	cfg.SMS.Contacts = append(cfg.SMS.Contacts, SMSContact{
-		Name:    "Bobby",
-		Number:  "4321",
-		Enabled: false,
-	})
-	cfg.Communications.SMSGlobalConfig.Name = ""
-	cfg.CheckCommunicationsConfig()
-	if cfg.Communications.SMSGlobalConfig.Contacts[0].Name != "Bobby" {
-		t.Error("Test failed. CheckCommunicationsConfig error:", err)
-	}
-
-	cfg.SMS = &SMSGlobalConfig{}
-	cfg.CheckCommunicationsConfig()
-	if cfg.SMS != nil {
-		t.Error("Test failed. CheckCommunicationsConfig unexpected data:",
-			cfg.SMS)
-	}
-
 	cfg.Communications.SlackConfig.Name = "NOT Slack"
 	cfg.CheckCommunicationsConfig()
 
@@ -281,6 +357,129 @@ func TestCheckCommunicationsConfig(t *testing.T) {
 	if cfg.Communications.TelegramConfig.Enabled {
 		t.Error("Test failed. CheckCommunicationsConfig TelegramConfig is enabled when it shouldn't be.")
 	}
+
+	cfg.Communications.TelegramConfig.Enabled = false
+	if cfg.Communications.WebhookConfig.Name != "Webhook" ||
+		cfg.Communications.WebhookConfig.Timeout <= 0 {
+		t.Error("Test failed. CheckCommunicationsConfig unexpected webhook defaults:",
+			cfg.Communications.WebhookConfig)
+	}
+	cfg.Communications.WebhookConfig.Enabled = true
+	cfg.Communications.WebhookConfig.Endpoints = []WebhookEndpoint{{URL: "https://example.com"}, {}}
+	cfg.CheckCommunicationsConfig()
+	if cfg.Communications.WebhookConfig.Enabled {
+		t.Error("Test failed. CheckCommunicationsConfig WebhookConfig is enabled when it shouldn't be.")
+	}
+	cfg.Communications.WebhookConfig.Enabled = true
+	cfg.Communications.WebhookConfig.Endpoints = cfg.Communications.WebhookConfig.Endpoints[:1]
+	cfg.Communications.WebhookConfig.Timeout = 0
+	cfg.CheckCommunicationsConfig()
+	if !cfg.Communications.WebhookConfig.Enabled ||
+		cfg.Communications.WebhookConfig.Timeout != configDefaultWebhookTimeout {
+		t.Error("Test failed. CheckCommunicationsConfig unexpected webhook config:",
+			cfg.Communications.WebhookConfig)
+	}
+}
+
+func TestCheckPairConfigFormats(t *testing.T) {
+	delimiter := &CurrencyPairFormatConfig{Uppercase: true, Delimiter: "_"}
+	index := &CurrencyPairFormatConfig{Uppercase: true, Index: "KRW"}
+	plain := &CurrencyPairFormatConfig{Uppercase: true}
+
+	tests := []struct {
+		name      string
+		format    *CurrencyPairFormatConfig
+		available string
+		enabled   string
+		repair    bool
+		err       string
+		expected  string
+	}{
+		{name: "delimiter", format: delimiter, available: "BTC_USD,ltc_btc", enabled: "BTC_USD", expected: "BTC_USD"},
+		{name: "missing delimiter", format: delimiter, available: "BTC_USD", enabled: "BTCUSD", err: `"BTCUSD"`},
+		{name: "two delimiters", format: delimiter, available: "BTC_USD_DOGE", enabled: "BTC_USD", err: `"BTC_USD_DOGE"`},
+		{name: "empty currency", format: delimiter, available: "BTC_", enabled: "BTC_", err: "empty"},
+		{name: "whitespace", format: delimiter, available: "BTC_U SD", enabled: "BTC_U SD", err: "whitespace"},
+		{name: "index", format: index, available: "BTCKRW,KRWETH", enabled: "BTCKRW", expected: "BTCKRW"},
+		{name: "unknown index", format: &CurrencyPairFormatConfig{Index: "FOO"}, available: "BTCFOO", enabled: "BTCFOO", err: `index "FOO"`},
+		{name: "index inside pair", format: index, available: "BTKRWC", enabled: "BTCKRW", err: `"BTKRWC"`},
+		{name: "index with two currencies", format: &CurrencyPairFormatConfig{Index: "DOGE"}, available: "BTCUSDDOGE", enabled: "BTCUSDDOGE", err: "two currencies"},
+		{name: "too short", format: plain, available: "BTC", enabled: "BTC", err: `"BTC"`},
+		{name: "plain", format: plain, available: "BTCUSD,XBTZ18", enabled: "XBTZ18", expected: "XBTZ18"},
+		{name: "repair", format: delimiter, available: "BTC_USD,BTCUSD,ETH_BTC", enabled: "BTC_USD,BTCUSD", repair: true, expected: "BTC_USD"},
+		{name: "repair all enabled invalid", format: delimiter, available: "BTC_USD", enabled: "BTCUSD", repair: true, err: "Enabled pairs is empty"},
+	}
+
+	for _, test := range tests {
+		cfg := Config{Exchanges: []ExchangeConfig{{
+			Name:                     "TestExchange",
+			AvailablePairs:           test.available,
+			EnabledPairs:             test.enabled,
+			BaseCurrencies:           "USD,KRW",
+			RepairInvalidPairs:       test.repair,
+			ConfigCurrencyPairFormat: test.format,
+		}}}
+		err := cfg.CheckPairConfigFormats("TestExchange")
+		if test.err != "" {
+			if err == nil || !common.StringContains(err.Error(), test.err) ||
+				!common.StringContains(err.Error(), "TestExchange") {
+				t.Errorf("Test failed. %s: expected error containing %q, got %v",
+					test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test failed. %s: unexpected error %s", test.name, err)
+			continue
+		}
+		if cfg.Exchanges[0].EnabledPairs != test.expected {
+			t.Errorf("Test failed. %s: expected enabled pairs %q, got %q",
+				test.name, test.expected, cfg.Exchanges[0].EnabledPairs)
+		}
+	}
+
+	cfg := Config{Exchanges: []ExchangeConfig{{
+		Name:                     "TestExchange",
+		AvailablePairs:           "BTC_USD,BTCUSD,ETH_BTC",
+		EnabledPairs:             "BTC_USD",
+		RepairInvalidPairs:       true,
+		ConfigCurrencyPairFormat: delimiter,
+	}}}
+	err := cfg.CheckPairConfigFormats("TestExchange")
+	if err != nil || cfg.Exchanges[0].AvailablePairs != "BTC_USD,ETH_BTC" {
+		t.Errorf("Test failed. Invalid available pair not removed %q %v",
+			cfg.Exchanges[0].AvailablePairs, err)
+	}
+	err = cfg.CheckPairConfigFormats("asdf")
+	if err == nil {
+		t.Error("Test failed. CheckPairConfigFormats. Non-existent exchange returned nil error")
+	}
+}
+
+func TestCheckExchangeConfigValuesInvalidPairs(t *testing.T) {
+	cfg := Config{}
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchCfg, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchCfg.EnabledPairs = "BTC_USD,BTCUSD"
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cfg.CheckExchangeConfigValues()
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConfigValues error: %s", err)
+	}
+	exchCfg, _ = cfg.GetExchangeConfig("ANX")
+	if exchCfg.Enabled {
+		t.Error("Test failed. Exchange with an invalid pair not disabled")
+	}
 }
 
 func TestCheckPairConsistency(t *testing.T) {
@@ -655,6 +854,14 @@ func TestCheckExchangeConfigValues(t *testing.T) {
 		t.Fatalf("Test failed. Expected exchange %s to have updated HTTPTimeout value", checkExchangeConfigValues.Exchanges[0].Name)
 	}
 
+	checkExchangeConfigValues.GlobalHTTPTimeout = time.Second * 7
+	checkExchangeConfigValues.Exchanges[0].HTTPTimeout = 0
+	checkExchangeConfigValues.CheckExchangeConfigValues()
+	if checkExchangeConfigValues.Exchanges[0].HTTPTimeout != time.Second*7 {
+		t.Errorf("Test failed. Expected exchange %s HTTPTimeout to default to the global timeout, got %v",
+			checkExchangeConfigValues.Exchanges[0].Name, checkExchangeConfigValues.Exchanges[0].HTTPTimeout)
+	}
+
 	checkExchangeConfigValues.Exchanges[0].APIKey = "Key"
 	checkExchangeConfigValues.Exchanges[0].APISecret = "Secret"
 	checkExchangeConfigValues.Exchanges[0].AuthenticatedAPISupport = true
@@ -665,6 +872,17 @@ func TestCheckExchangeConfigValues(t *testing.T) {
 		)
 	}
 
+	checkExchangeConfigValues.Exchanges[0].APIAuthPEMKeySupport = true
+	checkExchangeConfigValues.Exchanges[0].APIAuthPEMKey = "invalid"
+	err = checkExchangeConfigValues.CheckExchangeConfigValues()
+	if err != nil || checkExchangeConfigValues.Exchanges[0].AuthenticatedAPISupport {
+		t.Error(
+			"Test failed. checkExchangeConfigValues.CheckExchangeConfigValues invalid PEM key not handled",
+		)
+	}
+	checkExchangeConfigValues.Exchanges[0].APIAuthPEMKeySupport = false
+	checkExchangeConfigValues.Exchanges[0].APIAuthPEMKey = ""
+
 	checkExchangeConfigValues.Exchanges[0].AuthenticatedAPISupport = true
 	checkExchangeConfigValues.Exchanges[0].APIKey = "TESTYTEST"
 	checkExchangeConfigValues.Exchanges[0].APISecret = "TESTYTEST"
@@ -726,6 +944,125 @@ func TestCheckExchangeConfigValues(t *testing.T) {
 	}
 }
 
+func TestCheckExchangeConfigValuesNoExchanges(t *testing.T) {
+	c := Config{}
+	err := c.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. LoadConfig: %s", err)
+	}
+	for x := range c.Exchanges {
+		c.Exchanges[x].Enabled = false
+	}
+
+	err = c.CheckExchangeConfigValues()
+	if err == nil || err.Error() != ErrNoEnabledExchanges {
+		t.Errorf("Test failed. Expected %q, got %v", ErrNoEnabledExchanges, err)
+	}
+
+	c.AllowNoExchanges = true
+	err = c.CheckExchangeConfigValues()
+	if err != nil {
+		t.Errorf("Test failed. CheckExchangeConfigValues with AllowNoExchanges: %s", err)
+	}
+
+	c.AllowNoExchanges = false
+	PortfolioOnly = true
+	defer func() { PortfolioOnly = false }()
+	err = c.CheckExchangeConfigValues()
+	if err != nil {
+		t.Errorf("Test failed. CheckExchangeConfigValues with PortfolioOnly: %s", err)
+	}
+}
+
+func TestCheckExchangeAccounts(t *testing.T) {
+	c := Config{}
+	err := c.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. LoadConfig: %s", err)
+	}
+
+	c.Exchanges[0].Accounts = []APICredentialsConfig{
+		{Label: "main", APIKey: "key", APISecret: "secret"},
+		{Label: "unset", APIKey: DefaultUnsetAPIKey, APISecret: DefaultUnsetAPISecret},
+	}
+	err = c.CheckExchangeConfigValues()
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConfigValues: %s", err)
+	}
+
+	labels, err := c.GetExchangeAccountLabels(c.Exchanges[0].Name)
+	if err != nil {
+		t.Fatalf("Test failed. GetExchangeAccountLabels: %s", err)
+	}
+	if len(labels) != 1 || labels[0] != "main" {
+		t.Errorf("Test failed. Expected only the main account to remain, got %v", labels)
+	}
+
+	c.Exchanges[0].Accounts = append(c.Exchanges[0].Accounts,
+		APICredentialsConfig{Label: "MAIN", APIKey: "key2", APISecret: "secret2"})
+	err = c.CheckExchangeConfigValues()
+	if err == nil {
+		t.Error("Test failed. Expected an error for a duplicate account label")
+	}
+
+	c.Exchanges[0].Accounts = []APICredentialsConfig{
+		{Label: "bad" + AccountLabelSeparator + "label", APIKey: "key", APISecret: "secret"},
+	}
+	err = c.CheckExchangeConfigValues()
+	if err == nil {
+		t.Error("Test failed. Expected an error for an account label containing the separator")
+	}
+}
+
+func TestCheckCurrencyDetails(t *testing.T) {
+	fee, negative, confirmations := 0.001, -1.0, 3
+	exch := ExchangeConfig{
+		Name: "Test",
+		CurrencyDetails: []CurrencyDetailsConfig{
+			{Currency: "BTC", WithdrawalFee: &fee, Confirmations: &confirmations},
+			{Currency: "ETH"},
+		},
+	}
+	err := checkCurrencyDetails(exch)
+	if err != nil {
+		t.Fatalf("Test failed. checkCurrencyDetails: %s", err)
+	}
+
+	for _, details := range []CurrencyDetailsConfig{
+		{},
+		{Currency: "btc"},
+		{Currency: "LTC", MinWithdrawal: &negative},
+		{Currency: "LTC", WithdrawalFee: &negative},
+	} {
+		exch.CurrencyDetails = []CurrencyDetailsConfig{{Currency: "BTC"}, details}
+		err = checkCurrencyDetails(exch)
+		if err == nil {
+			t.Errorf("Test failed. Expected an error for %+v", details)
+		}
+	}
+}
+
+func TestCheckEnabledAssetTypes(t *testing.T) {
+	c := Config{}
+	err := c.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. LoadConfig: %s", err)
+	}
+
+	c.Exchanges[0].AssetTypes = "SPOT,this_week"
+	c.Exchanges[0].EnabledAssetTypes = "spot"
+	err = c.CheckExchangeConfigValues()
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConfigValues: %s", err)
+	}
+
+	c.Exchanges[0].EnabledAssetTypes = "SPOT,quarter"
+	err = c.CheckExchangeConfigValues()
+	if err == nil {
+		t.Error("Test failed. Expected an error for an unsupported enabled asset type")
+	}
+}
+
 func TestCheckWebserverConfigValues(t *testing.T) {
 	checkWebserverConfigValues := GetConfig()
 	err := checkWebserverConfigValues.LoadConfig(ConfigTestFile)
@@ -766,6 +1103,41 @@ func TestCheckWebserverConfigValues(t *testing.T) {
 		)
 	}
 
+	checkWebserverConfigValues.Webserver.RequestLogLevel = "info"
+	checkWebserverConfigValues.Webserver.SlowRequestThreshold = 0
+	err = checkWebserverConfigValues.CheckWebserverConfigValues()
+	if err != nil ||
+		checkWebserverConfigValues.Webserver.RequestLogLevel != "INFO" ||
+		checkWebserverConfigValues.Webserver.SlowRequestThreshold != configDefaultSlowRequestThreshold {
+		t.Errorf("Test failed. Unexpected request log settings %s %v, error: %v",
+			checkWebserverConfigValues.Webserver.RequestLogLevel,
+			checkWebserverConfigValues.Webserver.SlowRequestThreshold, err)
+	}
+
+	checkWebserverConfigValues.Webserver.WebsocketAuthTimeout = 0
+	checkWebserverConfigValues.Webserver.WebsocketSendQueueSize = -1
+	err = checkWebserverConfigValues.CheckWebserverConfigValues()
+	if err != nil ||
+		checkWebserverConfigValues.Webserver.WebsocketAuthTimeout != configDefaultWebsocketAuthTimeout ||
+		checkWebserverConfigValues.Webserver.WebsocketSendQueueSize != configDefaultWebsocketSendQueueSize {
+		t.Errorf("Test failed. Unexpected websocket settings %v %d, error: %v",
+			checkWebserverConfigValues.Webserver.WebsocketAuthTimeout,
+			checkWebserverConfigValues.Webserver.WebsocketSendQueueSize, err)
+	}
+
+	checkWebserverConfigValues.Webserver.WebsocketAuthTimeout = -1
+	checkWebserverConfigValues.CheckWebserverConfigValues()
+	if checkWebserverConfigValues.Webserver.WebsocketAuthTimeout != -1 {
+		t.Error("Test failed. Expected a negative websocket auth timeout to be kept")
+	}
+
+	checkWebserverConfigValues.Webserver.RequestLogLevel = "TRACE"
+	err = checkWebserverConfigValues.CheckWebserverConfigValues()
+	if err == nil {
+		t.Error("Test failed. Expected an invalid request log level error")
+	}
+	checkWebserverConfigValues.Webserver.RequestLogLevel = ""
+
 	checkWebserverConfigValues.Webserver.ListenAddress = ":0"
 	err = checkWebserverConfigValues.CheckWebserverConfigValues()
 	if err == nil {
@@ -824,6 +1196,70 @@ func TestRetrieveConfigCurrencyPairs(t *testing.T) {
 	}
 }
 
+func TestCustomCurrencies(t *testing.T) {
+	defer func(cryptos, fiats []string) {
+		currency.SetCustomCurrencies(nil, nil, nil)
+		currency.CryptoCurrencies, currency.FiatCurrencies = cryptos, fiats
+	}(currency.CryptoCurrencies, currency.FiatCurrencies)
+
+	var cfg Config
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. LoadConfig: %s", err)
+	}
+
+	cfg.Currency.CustomCryptocurrencies = "NEWCOIN,usdt"
+	cfg.Currency.CustomFiatCurrencies = "DOGE"
+	cfg.Currency.RemovedCurrencies = "DASH"
+	err = cfg.CheckCurrencyConfigValues()
+	if err != nil {
+		t.Fatalf("Test failed. CheckCurrencyConfigValues: %s", err)
+	}
+	cfg.mergeCurrencies()
+	if !currency.IsCryptocurrency("NEWCOIN") || !currency.IsCryptocurrency("USDT") {
+		t.Error("Test failed. Custom cryptocurrencies not merged")
+	}
+	if currency.IsCryptocurrency("DOGE") || !currency.IsFiatCurrency("DOGE") {
+		t.Error("Test failed. Default cryptocurrency not overridden as fiat")
+	}
+	if currency.IsCryptocurrency("DASH") || !currency.IsCryptocurrency("BTC") {
+		t.Error("Test failed. Unexpected cryptocurrencies after a removal")
+	}
+
+	cfg.Currency.CustomFiatCurrencies = "DOGE,NEWCOIN"
+	if cfg.CheckCurrencyConfigValues() == nil {
+		t.Error("Test failed. Expected an error for a currency of both types")
+	}
+	cfg.Currency.CustomFiatCurrencies = ""
+	cfg.Currency.RemovedCurrencies = "newcoin"
+	if cfg.CheckCurrencyConfigValues() == nil {
+		t.Error("Test failed. Expected an error for a removed custom currency")
+	}
+}
+
+func TestRegisterPairCurrencies(t *testing.T) {
+	defer func(cryptos, fiats []string) {
+		currency.CryptoCurrencies, currency.FiatCurrencies = cryptos, fiats
+	}(currency.CryptoCurrencies, currency.FiatCurrencies)
+	currency.CryptoCurrencies = []string{"BTC"}
+	currency.FiatCurrencies = []string{"USD"}
+
+	pairs := []pair.CurrencyPair{pair.NewCurrencyPair("BTC", "USD"),
+		pair.NewCurrencyPair("NEWCOIN", "BTC")}
+	disabled := false
+	cfg := Config{Currency: CurrencyConfig{AutoRegisterCryptocurrencies: &disabled}}
+	if registered := cfg.RegisterPairCurrencies(pairs); len(registered) != 0 {
+		t.Errorf("Test failed. Currencies registered while disabled: %v", registered)
+	}
+
+	cfg.Currency.AutoRegisterCryptocurrencies = nil
+	registered := cfg.RegisterPairCurrencies(pairs)
+	if len(registered) != 1 || registered[0] != "NEWCOIN" ||
+		!currency.IsCryptocurrency("NEWCOIN") {
+		t.Errorf("Test failed. Expected NEWCOIN to be registered, got %v", registered)
+	}
+}
+
 func TestReadConfig(t *testing.T) {
 	readConfig := GetConfig()
 	err := readConfig.ReadConfig(ConfigTestFile)
@@ -893,6 +1329,23 @@ func TestCheckConfig(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	for _, limit := range []float64{-2, 0.5, 1} {
+		c.TickerPriceJumpLimit = limit
+		err = c.CheckConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.TickerPriceJumpLimit != 0 {
+			t.Errorf("Test failed. Expected invalid ticker price jump limit %v to be disabled",
+				limit)
+		}
+	}
+	c.TickerPriceJumpLimit = 5
+	err = c.CheckConfig()
+	if err != nil || c.TickerPriceJumpLimit != 5 {
+		t.Errorf("Test failed. Expected ticker price jump limit to be kept, error: %v", err)
+	}
 }
 
 func TestUpdateConfig(t *testing.T) {
@@ -902,19 +1355,23 @@ func TestUpdateConfig(t *testing.T) {
 		t.Errorf("Test failed. %s", err)
 	}
 
-	newCfg := c
-	err = c.UpdateConfig(ConfigTestFile, newCfg)
+	var newCfg Config
+	err = newCfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Errorf("Test failed. %s", err)
+	}
+	err = c.UpdateConfig(ConfigTestFile, &newCfg)
 	if err != nil {
 		t.Fatalf("Test failed. %s", err)
 	}
 
-	err = c.UpdateConfig("//non-existantpath\\", newCfg)
+	err = c.UpdateConfig("//non-existantpath\\", &newCfg)
 	if err == nil {
 		t.Fatalf("Test failed. Error should of been thrown for invalid path")
 	}
 
 	newCfg.Currency.Cryptocurrencies = ""
-	err = c.UpdateConfig(ConfigTestFile, newCfg)
+	err = c.UpdateConfig(ConfigTestFile, &newCfg)
 	if err != nil {
 		t.Errorf("Test failed. %s", err)
 	}
@@ -939,4 +1396,20 @@ func TestCheckLoggerConfig(t *testing.T) {
 	if err != nil {
 		t.Errorf("Failed to create logger with user settings: reason: %v", err)
 	}
+
+	c.Logging.Subsystems = map[string]string{
+		"exchange.OKEX":   "DEBUG",
+		"engine.routines": "WARN|ERROR",
+		"exchange.Huobi":  "VERBOSE",
+		"":                "INFO",
+	}
+	err = c.CheckLoggerConfig()
+	if err != nil {
+		t.Errorf("Failed to create logger with subsystem settings: reason: %v", err)
+	}
+	if len(c.Logging.Subsystems) != 2 || c.Logging.Subsystems["exchange.OKEX"] != "DEBUG" ||
+		c.Logging.Subsystems["engine.routines"] != "WARN|ERROR" {
+		t.Errorf("Test failed. Invalid subsystem overrides not removed: %v",
+			c.Logging.Subsystems)
+	}
 }
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// CurrentConfigVersion is the config schema version written by this build.
+// Configs with an older version are upgraded on load, configs with a newer
+// version are rejected
+const CurrentConfigVersion = 3
+
+// ErrConfigVersionUnsupported is returned when a config was written by a newer
+// build than the one loading it
+const ErrConfigVersionUnsupported = "Config version %d is newer than the highest supported version %d, please upgrade GoCryptoTrader or restore a compatible config."
+
+// configUpgrades holds the ordered config schema upgrade steps. The step at
+// index x upgrades a config from version x to version x+1, so there must be
+// exactly CurrentConfigVersion steps
+var configUpgrades = []func(c *Config){
+	upgradeConfigV1,
+	upgradeConfigV2,
+	upgradeConfigV3,
+}
+
+// UpgradeConfig runs each upgrade step needed to bring the config up to the
+// current schema version
+func (c *Config) UpgradeConfig() error {
+	if c.ConfigVersion > CurrentConfigVersion {
+		return fmt.Errorf(ErrConfigVersionUnsupported, c.ConfigVersion,
+			CurrentConfigVersion)
+	}
+
+	if c.ConfigVersion < 0 {
+		log.Warnf("Config version %d invalid, upgrading from version 0.",
+			c.ConfigVersion)
+		c.ConfigVersion = 0
+	}
+
+	for c.ConfigVersion < len(configUpgrades) {
+		configUpgrades[c.ConfigVersion](c)
+		c.ConfigVersion++
+		log.Debugf("Upgraded config to version %d.", c.ConfigVersion)
+	}
+	return nil
+}
+
+// upgradeConfigV1 renames exchanges which have been rebranded
+func upgradeConfigV1(c *Config) {
+	for x := range c.Exchanges {
+		if c.Exchanges[x].Name == "GDAX" {
+			c.Exchanges[x].Name = "CoinbasePro"
+		}
+	}
+}
+
+// upgradeConfigV2 moves the top level currency settings into the currency
+// config. Settings already present in the currency config take precedence
+func upgradeConfigV2(c *Config) {
+	if len(c.Currency.Cryptocurrencies) == 0 && len(c.Cryptocurrencies) != 0 {
+		c.Currency.Cryptocurrencies = c.Cryptocurrencies
+		c.Cryptocurrencies = ""
+	}
+
+	if c.Currency.CurrencyPairFormat == nil && c.CurrencyPairFormat != nil {
+		c.Currency.CurrencyPairFormat = c.CurrencyPairFormat
+		c.CurrencyPairFormat = nil
+	}
+
+	if c.Currency.FiatDisplayCurrency == "" && c.FiatDisplayCurrency != "" {
+		c.Currency.FiatDisplayCurrency = c.FiatDisplayCurrency
+		c.FiatDisplayCurrency = ""
+	}
+}
+
+// upgradeConfigV3 moves the top level SMSGlobal settings into the
+// communications config. Settings without contacts or an already populated
+// SMSGlobal config are dropped
+func upgradeConfigV3(c *Config) {
+	if c.SMS == nil {
+		return
+	}
+
+	if c.Communications.SMSGlobalConfig.Name == "" && c.SMS.Contacts != nil {
+		c.Communications.SMSGlobalConfig = SMSGlobalConfig{
+			Name:     "SMSGlobal",
+			Enabled:  c.SMS.Enabled,
+			Verbose:  c.SMS.Verbose,
+			Username: c.SMS.Username,
+			Password: c.SMS.Password,
+			Contacts: c.SMS.Contacts,
+		}
+	}
+	c.SMS = nil
+}
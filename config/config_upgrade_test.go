@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+func TestUpgradeConfig(t *testing.T) {
+	if len(configUpgrades) != CurrentConfigVersion {
+		t.Fatalf("Test failed. %d upgrade steps for config version %d",
+			len(configUpgrades), CurrentConfigVersion)
+	}
+
+	c := Config{
+		Exchanges:           []ExchangeConfig{{Name: "GDAX"}},
+		FiatDisplayCurrency: "AUD",
+		SMS:                 &SMSGlobalConfig{Contacts: []SMSContact{{Name: "Bobby"}}},
+	}
+	err := c.UpgradeConfig()
+	if err != nil {
+		t.Fatalf("Test failed. UpgradeConfig error: %s", err)
+	}
+	if c.ConfigVersion != CurrentConfigVersion {
+		t.Errorf("Test failed. Expected config version %d, got %d",
+			CurrentConfigVersion, c.ConfigVersion)
+	}
+	if c.Exchanges[0].Name != "CoinbasePro" ||
+		c.Currency.FiatDisplayCurrency != "AUD" ||
+		c.Communications.SMSGlobalConfig.Name != "SMSGlobal" {
+		t.Error("Test failed. UpgradeConfig didn't run all upgrade steps")
+	}
+
+	// An up to date config must not be modified
+	c.Exchanges[0].Name = "GDAX"
+	err = c.UpgradeConfig()
+	if err != nil {
+		t.Fatalf("Test failed. UpgradeConfig error: %s", err)
+	}
+	if c.Exchanges[0].Name != "GDAX" {
+		t.Error("Test failed. UpgradeConfig upgraded a current config")
+	}
+
+	c.ConfigVersion = CurrentConfigVersion + 1
+	err = c.UpgradeConfig()
+	if err == nil {
+		t.Fatal("Test failed. UpgradeConfig accepted a newer config version")
+	}
+	if c.ConfigVersion != CurrentConfigVersion+1 {
+		t.Error("Test failed. UpgradeConfig modified a newer config version")
+	}
+}
+
+func TestUpgradeConfigV1(t *testing.T) {
+	c := Config{Exchanges: []ExchangeConfig{{Name: "GDAX"}, {Name: "Bitfinex"}}}
+	upgradeConfigV1(&c)
+	if c.Exchanges[0].Name != "CoinbasePro" || c.Exchanges[1].Name != "Bitfinex" {
+		t.Errorf("Test failed. Unexpected exchange names %s, %s",
+			c.Exchanges[0].Name, c.Exchanges[1].Name)
+	}
+}
+
+func TestUpgradeConfigV2(t *testing.T) {
+	pairFormat := &CurrencyPairFormatConfig{Delimiter: "_"}
+	c := Config{
+		Cryptocurrencies:    "BTC,LTC",
+		CurrencyPairFormat:  pairFormat,
+		FiatDisplayCurrency: "AUD",
+	}
+	upgradeConfigV2(&c)
+	if c.Currency.Cryptocurrencies != "BTC,LTC" ||
+		c.Currency.CurrencyPairFormat != pairFormat ||
+		c.Currency.FiatDisplayCurrency != "AUD" {
+		t.Errorf("Test failed. Currency settings not moved %v", c.Currency)
+	}
+	if c.Cryptocurrencies != "" || c.CurrencyPairFormat != nil ||
+		c.FiatDisplayCurrency != "" {
+		t.Error("Test failed. Deprecated currency settings not flushed")
+	}
+
+	c = Config{
+		Currency:            CurrencyConfig{FiatDisplayCurrency: "USD"},
+		FiatDisplayCurrency: "AUD",
+	}
+	upgradeConfigV2(&c)
+	if c.Currency.FiatDisplayCurrency != "USD" || c.FiatDisplayCurrency != "AUD" {
+		t.Error("Test failed. Deprecated setting overwrote currency config")
+	}
+}
+
+func TestUpgradeConfigV3(t *testing.T) {
+	c := Config{SMS: &SMSGlobalConfig{}}
+	upgradeConfigV3(&c)
+	if c.SMS != nil || c.Communications.SMSGlobalConfig.Name != "" {
+		t.Error("Test failed. SMS config without contacts not dropped")
+	}
+
+	c.SMS = &SMSGlobalConfig{
+		Enabled:  true,
+		Username: "user",
+		Contacts: []SMSContact{{Name: "Bobby", Number: "4321"}},
+	}
+	upgradeConfigV3(&c)
+	if c.SMS != nil {
+		t.Error("Test failed. SMS config not flushed")
+	}
+	sms := c.Communications.SMSGlobalConfig
+	if sms.Name != "SMSGlobal" || !sms.Enabled || sms.Username != "user" ||
+		sms.Contacts[0].Name != "Bobby" {
+		t.Errorf("Test failed. SMS config not moved %v", sms)
+	}
+
+	c.SMS = &SMSGlobalConfig{Contacts: []SMSContact{{Name: "Alice"}}}
+	upgradeConfigV3(&c)
+	if c.SMS != nil || c.Communications.SMSGlobalConfig.Contacts[0].Name != "Bobby" {
+		t.Error("Test failed. SMS config overwrote communications config")
+	}
+}
+
+func TestReadConfigVersion(t *testing.T) {
+	path := "../testdata/configversiontest.json"
+	defer common.RemoveFile(path)
+
+	var c Config
+	err := c.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. LoadConfig error: %s", err)
+	}
+
+	c.ConfigVersion = 0
+	err = c.SaveConfig(path)
+	if err != nil {
+		t.Fatalf("Test failed. SaveConfig error: %s", err)
+	}
+	if c.ConfigVersion != CurrentConfigVersion {
+		t.Errorf("Test failed. SaveConfig didn't bump config version, got %d",
+			c.ConfigVersion)
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer := strings.Replace(string(data),
+		fmt.Sprintf(`"configVersion": %d`, CurrentConfigVersion),
+		fmt.Sprintf(`"configVersion": %d`, CurrentConfigVersion+1), 1)
+	err = common.WriteFile(path, []byte(newer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n Config
+	err = n.ReadConfig(path)
+	if err == nil {
+		t.Error("Test failed. ReadConfig accepted a newer config version")
+	}
+}
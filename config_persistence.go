@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// configSaveDelay is how long the config persistence routine waits after the
+// last runtime config change before saving, so a burst of changes is saved
+// once
+var configSaveDelay = time.Second * 5
+
+var (
+	configChanged = make(chan struct{}, 1)
+	configDirty   int32
+	configSaveMtx sync.Mutex
+	// saveBotConfig saves the bot config to the config file
+	saveBotConfig = func() error {
+		return bot.config.SaveConfig(bot.settings.ConfigFile)
+	}
+)
+
+// ConfigPersistenceEnabled returns whether runtime config changes are saved to
+// the config file. They aren't saved on dry runs or when disabled in the
+// config
+func ConfigPersistenceEnabled() bool {
+	return !bot.settings.DryRun && bot.config != nil && !bot.config.DisableRuntimeSaves
+}
+
+// MarkConfigDirty records a runtime change to the config. The config
+// persistence routine saves it once no further changes have been marked for
+// the save delay, and any unsaved change is saved on shutdown
+func MarkConfigDirty() {
+	if !ConfigPersistenceEnabled() {
+		return
+	}
+	atomic.StoreInt32(&configDirty, 1)
+	select {
+	case configChanged <- struct{}{}:
+	default:
+	}
+}
+
+// ConfigPersistenceRoutine saves the config after runtime changes, restarting
+// the save delay on every change. The final save is made by Stop
+func ConfigPersistenceRoutine() {
+	routinesLog.Debugf("Starting config persistence routine. Save delay: %v.",
+		configSaveDelay)
+	var t *time.Timer
+	var save <-chan time.Time
+	for {
+		select {
+		case <-shutdowner:
+			if t != nil {
+				t.Stop()
+			}
+			routinesLog.Debugln("Config persistence routine stopped.")
+			return
+		case <-configChanged:
+			if t != nil {
+				t.Stop()
+			}
+			t = time.NewTimer(configSaveDelay)
+			save = t.C
+		case <-save:
+			save = nil
+			err := flushConfig(false)
+			if err != nil {
+				routinesLog.Warnf("Unable to save config: %s.", err)
+			}
+		}
+	}
+}
+
+// flushConfig saves the config if it has unsaved runtime changes, or always
+// when force is set. Saves are serialised and a change marked during a save
+// is kept for the next one. A failed save leaves the config dirty
+func flushConfig(force bool) error {
+	configSaveMtx.Lock()
+	defer configSaveMtx.Unlock()
+
+	if atomic.SwapInt32(&configDirty, 0) == 0 && !force {
+		return nil
+	}
+	syncConfigPortfolio()
+	err := saveBotConfig()
+	if err != nil {
+		atomic.StoreInt32(&configDirty, 1)
+	}
+	return err
+}
+
+// syncConfigPortfolio copies the watched portfolio addresses into the config
+// so they're saved with it
+func syncConfigPortfolio() {
+	if bot.portfolio == nil {
+		return
+	}
+	if addresses := bot.portfolio.GetAddresses(); len(addresses) != 0 {
+		bot.config.Portfolio.Addresses = addresses
+	}
+}
+
+// ChangeConfigEncryptionKey re-encrypts the config file with a new key which
+// later saves use. It is serialised with config saves so a save in progress
+// can't rewrite the file with the old key afterwards
+func ChangeConfigEncryptionKey(oldKey, newKey string) error {
+	if bot.settings.DryRun {
+		return errors.New("the config file isn't modified on dry runs")
+	}
+
+	configSaveMtx.Lock()
+	defer configSaveMtx.Unlock()
+	return config.ChangeConfigEncryptionKey(bot.settings.ConfigFile, []byte(oldKey),
+		[]byte(newKey))
+}
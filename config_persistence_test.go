@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+)
+
+// setupConfigSaves enables runtime config saves with the supplied delay and
+// replaces the config saver with one counting the saves made. The returned
+// function restores the replaced globals
+func setupConfigSaves(t *testing.T, delay time.Duration, disabled bool) (*int32, func()) {
+	SetupTestHelpers(t)
+	cfg, dryRun, saver, saveDelay := bot.config, bot.settings.DryRun, saveBotConfig, configSaveDelay
+
+	var testCfg config.Config
+	err := testCfg.LoadConfig("./testdata/configtest.json")
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config: %s", err)
+	}
+	testCfg.DisableRuntimeSaves = disabled
+	bot.config = &testCfg
+	bot.settings.DryRun = false
+	configSaveDelay = delay
+	atomic.StoreInt32(&configDirty, 0)
+
+	var saves int32
+	saveBotConfig = func() error {
+		atomic.AddInt32(&saves, 1)
+		return nil
+	}
+	return &saves, func() {
+		bot.config, bot.settings.DryRun, saveBotConfig, configSaveDelay = cfg, dryRun, saver, saveDelay
+		atomic.StoreInt32(&configDirty, 0)
+		select {
+		case <-configChanged:
+		default:
+		}
+	}
+}
+
+// runConfigPersistence starts the config persistence routine and returns a
+// function which stops it
+func runConfigPersistence() func() {
+	done := make(chan struct{})
+	go func() {
+		ConfigPersistenceRoutine()
+		close(done)
+	}()
+	return func() {
+		signalShutdown()
+		<-done
+		resetStop()
+	}
+}
+
+func TestConfigPersistenceDebounce(t *testing.T) {
+	saves, restore := setupConfigSaves(t, time.Millisecond*100, false)
+	defer restore()
+	defer runConfigPersistence()()
+
+	for i := 0; i < 5; i++ {
+		MarkConfigDirty()
+		time.Sleep(time.Millisecond * 20)
+	}
+	if n := atomic.LoadInt32(saves); n != 0 {
+		t.Fatalf("Test failed. Expected no saves before the changes settled, got %d", n)
+	}
+
+	time.Sleep(time.Millisecond * 250)
+	if n := atomic.LoadInt32(saves); n != 1 {
+		t.Fatalf("Test failed. Expected 1 save, got %d", n)
+	}
+	if atomic.LoadInt32(&configDirty) != 0 {
+		t.Error("Test failed. Config should be clean after saving")
+	}
+
+	MarkConfigDirty()
+	time.Sleep(time.Millisecond * 250)
+	if n := atomic.LoadInt32(saves); n != 2 {
+		t.Errorf("Test failed. Expected a later change to be saved, got %d saves", n)
+	}
+}
+
+func TestConfigPersistenceConcurrentMarks(t *testing.T) {
+	saves, restore := setupConfigSaves(t, time.Millisecond*50, false)
+	defer restore()
+	defer runConfigPersistence()()
+
+	var marks sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		marks.Add(1)
+		go func() {
+			defer marks.Done()
+			MarkConfigDirty()
+		}()
+	}
+	marks.Wait()
+
+	time.Sleep(time.Millisecond * 200)
+	if n := atomic.LoadInt32(saves); n != 1 {
+		t.Errorf("Test failed. Expected concurrent changes to be saved once, got %d saves", n)
+	}
+}
+
+func TestConfigPersistenceDisabled(t *testing.T) {
+	saves, restore := setupConfigSaves(t, time.Millisecond*10, true)
+	defer restore()
+	defer resetStop()
+
+	stop := runConfigPersistence()
+	MarkConfigDirty()
+	time.Sleep(time.Millisecond * 100)
+	stop()
+	if atomic.LoadInt32(&configDirty) != 0 {
+		t.Error("Test failed. Config shouldn't be marked dirty when runtime saves are disabled")
+	}
+
+	err := Stop(time.Second)
+	if err != nil {
+		t.Fatalf("Test failed. Stop error: %s", err)
+	}
+	if n := atomic.LoadInt32(saves); n != 0 {
+		t.Errorf("Test failed. Expected no saves when runtime saves are disabled, got %d", n)
+	}
+}
+
+func TestConfigPersistenceShutdownFlush(t *testing.T) {
+	saves, restore := setupConfigSaves(t, time.Hour, false)
+	defer restore()
+	defer resetStop()
+
+	MarkConfigDirty()
+	err := Stop(time.Second)
+	if err != nil {
+		t.Fatalf("Test failed. Stop error: %s", err)
+	}
+	if n := atomic.LoadInt32(saves); n != 1 {
+		t.Errorf("Test failed. Expected the pending change to be saved on shutdown, got %d saves", n)
+	}
+}
+
+func TestFlushConfig(t *testing.T) {
+	saves, restore := setupConfigSaves(t, time.Hour, false)
+	defer restore()
+
+	err := flushConfig(false)
+	if err != nil || atomic.LoadInt32(saves) != 0 {
+		t.Fatalf("Test failed. Expected a clean config not to be saved: %v", err)
+	}
+
+	saveBotConfig = func() error {
+		atomic.AddInt32(saves, 1)
+		return errors.New("disk full")
+	}
+	MarkConfigDirty()
+	err = flushConfig(false)
+	if err == nil {
+		t.Fatal("Test failed. Expected the save error to be returned")
+	}
+	if atomic.LoadInt32(&configDirty) != 1 {
+		t.Error("Test failed. Config should stay dirty after a failed save")
+	}
+
+	bot.settings.DryRun = true
+	atomic.StoreInt32(&configDirty, 0)
+	MarkConfigDirty()
+	if atomic.LoadInt32(&configDirty) != 0 {
+		t.Error("Test failed. Config shouldn't be marked dirty on dry runs")
+	}
+}
+
+func TestAddPortfolioAddressSaved(t *testing.T) {
+	saves, restore := setupConfigSaves(t, time.Hour, false)
+	defer restore()
+	defer func(port *portfolio.Base) { bot.portfolio = port }(bot.portfolio)
+	bot.portfolio = &portfolio.Base{}
+
+	address, err := AddPortfolioAddress(PortfolioAddressRequest{
+		Address:  "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy",
+		CoinType: "btc",
+	})
+	if err != nil {
+		t.Fatalf("Test failed. Unable to add portfolio address: %s", err)
+	}
+	if address.CoinType != "BTC" || address.Description != portfolio.PortfolioAddressPersonal {
+		t.Errorf("Test failed. Unexpected address %+v", address)
+	}
+	if atomic.LoadInt32(&configDirty) != 1 {
+		t.Fatal("Test failed. Adding an address should mark the config dirty")
+	}
+
+	err = flushConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(saves); n != 1 {
+		t.Errorf("Test failed. Expected 1 save, got %d", n)
+	}
+	if addresses := bot.config.Portfolio.Addresses; len(addresses) != 1 ||
+		!reflect.DeepEqual(addresses[0], address) {
+		t.Errorf("Test failed. Unexpected saved addresses %+v", addresses)
+	}
+}
+
+func TestRESTChangeConfigEncryptionKey(t *testing.T) {
+	SetupTestHelpers(t)
+	dir, err := ioutil.TempDir("", "gctconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer config.ClearConfigEncryptionKey()
+	defer func(s Settings) { bot.settings = s }(bot.settings)
+	bot.settings.ConfigFile = filepath.Join(dir, "config.json")
+	bot.settings.DryRun = false
+
+	data, err := common.ReadFile("./testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := config.EncryptConfigFile(data, []byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = common.WriteFile(bot.settings.ConfigFile, encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		body   string
+		status int
+	}{
+		{`{"oldKey":"wrong","newKey":"new"}`, http.StatusBadRequest},
+		{`{"oldKey":"old","newKey":""}`, http.StatusBadRequest},
+		{`{"oldKey":"old","newKey":"new"}`, http.StatusOK},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/config/encryptionkey",
+			bytes.NewBufferString(test.body))
+		r.SetBasicAuth(bot.config.Webserver.AdminUsername, bot.config.Webserver.AdminPassword)
+		w := httptest.NewRecorder()
+		NewRouter().ServeHTTP(w, r)
+		if w.Code != test.status {
+			t.Errorf("Test failed. %s expected status %d, got %d", test.body, test.status,
+				w.Code)
+		}
+	}
+
+	file, err := common.ReadFile(bot.settings.ConfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := config.DecryptConfigFile(file, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c config.Config
+	err = config.ConfirmConfigJSON(decrypted, &c)
+	if err != nil {
+		t.Errorf("Test failed. Config doesn't decrypt with the new key: %s", err)
+	}
+}
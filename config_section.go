@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/thrasher-/gocryptotrader/config"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// ConfigSectionUpdate holds an applied config section update and the updated
+// section, which is omitted when it's a credential
+type ConfigSectionUpdate struct {
+	config.SectionUpdate
+	Section interface{} `json:"section,omitempty"`
+}
+
+// GetConfigSection returns the config section at a dot separated path such as
+// "exchanges.Bitfinex" with its credentials removed
+func GetConfigSection(path string) (interface{}, error) {
+	return bot.config.GetSection(path)
+}
+
+// UpdateConfigSection merges a JSON payload into the config section at a dot
+// separated path such as "exchanges.Bitfinex.httpTimeout" or
+// "communications.slack". The update is validated before it's applied to the
+// running config, which is then saved and the affected subsystems reloaded
+func UpdateConfigSection(path string, payload []byte) (ConfigSectionUpdate, error) {
+	update, err := bot.config.UpdateSection(path, payload)
+	if err != nil {
+		return ConfigSectionUpdate{}, err
+	}
+	MarkConfigDirty()
+	reloadConfigSection(update)
+
+	// The section is only missing when it's a credential
+	section, _ := bot.config.GetSection(update.Path)
+	return ConfigSectionUpdate{SectionUpdate: update, Section: section}, nil
+}
+
+// reloadConfigSection applies an updated config section to the running bot
+// and notifies websocket clients. A loaded exchange is reloaded, or unloaded
+// if it was disabled, and an enabled exchange which isn't loaded is loaded.
+// Other sections are read from the config when next used
+func reloadConfigSection(update config.SectionUpdate) {
+	if update.Exchange != "" {
+		exchCfg, err := bot.config.GetExchangeConfig(update.Exchange)
+		if err != nil {
+			log.Errorf("Unable to reload exchange %s: %s", update.Exchange, err)
+			return
+		}
+		loaded := GetExchangeByName(update.Exchange) != nil
+		switch {
+		case loaded && exchCfg.Enabled:
+			err = ReloadExchange(update.Exchange)
+		case loaded:
+			err = UnloadExchange(update.Exchange)
+		case exchCfg.Enabled:
+			err = LoadExchange(update.Exchange, false, nil)
+		}
+		if err != nil {
+			log.Errorf("Unable to reload exchange %s: %s", update.Exchange, err)
+		}
+	}
+
+	log.Debugf("Config section %s updated.", update.Path)
+	relayWebsocketEvent(update, WebsocketEventConfigUpdate, "", update.Exchange)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+func TestRESTConfigSection(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(s Settings, channel string) {
+		bot.settings = s
+		bot.config.Communications.SlackConfig.TargetChannel = channel
+	}(bot.settings, bot.config.Communications.SlackConfig.TargetChannel)
+	bot.settings.DryRun = true
+
+	for _, test := range []struct {
+		path   string
+		body   string
+		auth   bool
+		status int
+	}{
+		{"/config/section/communications.slack", `{"targetChannel":"alerts"}`, false, http.StatusUnauthorized},
+		{"/config/section/communications.slack", `{"targetChannel":"alerts"}`, true, http.StatusOK},
+		{"/config/section/communications.slack.enabled", `true`, true, http.StatusBadRequest},
+		{"/config/section/communications.irc", `{}`, true, http.StatusNotFound},
+		{"/config/section/communications.slack", `{`, true, http.StatusBadRequest},
+	} {
+		r := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.body))
+		if test.auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername, bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		NewRouter().ServeHTTP(w, r)
+		if w.Code != test.status {
+			t.Errorf("Test failed. %s %s expected status %d, got %d", test.path, test.body,
+				test.status, w.Code)
+		}
+	}
+	if bot.config.Communications.SlackConfig.TargetChannel != "alerts" ||
+		bot.config.Communications.SlackConfig.Enabled {
+		t.Errorf("Test failed. Unexpected slack config %+v",
+			bot.config.Communications.SlackConfig)
+	}
+
+	w := httptest.NewRecorder()
+	NewRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/config/section/communications.slack", nil))
+	var slack map[string]interface{}
+	err := common.JSONDecode(w.Body.Bytes(), &slack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := slack["verificationToken"]; ok || slack["targetChannel"] != "alerts" {
+		t.Errorf("Test failed. Unexpected slack section %v", slack)
+	}
+
+	w = httptest.NewRecorder()
+	NewRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/config/section/webserver.adminPassword", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
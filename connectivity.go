@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+const (
+	// defaultConnectivityTimeout is the timeout of each connectivity check
+	defaultConnectivityTimeout = time.Second * 10
+	// maxConnectivityTimeout is the longest timeout a connectivity check can
+	// be given
+	maxConnectivityTimeout = time.Minute
+)
+
+// Connectivity check statuses
+const (
+	ConnectivityPass    = "pass"
+	ConnectivityFail    = "fail"
+	ConnectivitySkipped = "skipped"
+)
+
+// Connectivity check error classes, they tell a credential problem apart from
+// a network or exchange problem
+const (
+	ConnectivityErrorNotConfigured = "not_configured"
+	ConnectivityErrorCredentials   = "credentials"
+	ConnectivityErrorRateLimited   = "rate_limited"
+	ConnectivityErrorUnavailable   = "unavailable"
+	ConnectivityErrorNetwork       = "network"
+	ConnectivityErrorTimeout       = "timeout"
+	ConnectivityErrorNotSupported  = "not_supported"
+	ConnectivityErrorExchange      = "exchange"
+)
+
+// ErrConnectivityTimeout is returned by a connectivity check which doesn't
+// complete within its timeout
+var ErrConnectivityTimeout = errors.New("connectivity check timed out")
+
+var (
+	// connectivityResults holds the last connectivity result of each
+	// exchange by lower case name
+	connectivityResults   = make(map[string]ConnectivityResult)
+	connectivityResultMtx sync.RWMutex
+)
+
+// ConnectivityCheck holds the outcome of a single connectivity check
+type ConnectivityCheck struct {
+	Name       string        `json:"name"`
+	Request    string        `json:"request"`
+	Status     string        `json:"status"`
+	Latency    time.Duration `json:"latency"`
+	Error      string        `json:"error,omitempty"`
+	ErrorClass string        `json:"errorClass,omitempty"`
+}
+
+// ConnectivityResult holds the connectivity checks of an exchange and the API
+// URLs they used
+type ConnectivityResult struct {
+	Exchange string              `json:"exchange"`
+	APIURLs  []string            `json:"apiUrls"`
+	Passed   bool                `json:"passed"`
+	Checks   []ConnectivityCheck `json:"checks"`
+}
+
+// CheckExchangeConnectivity checks an exchange can be reached by fetching a
+// ticker from its public API and, when authenticated API support is
+// configured, the account info from its authenticated API. Each check is
+// given the supplied timeout, the default timeout is used if zero. No orders
+// are placed and no funds are moved. The public check is aborted when ctx is
+// cancelled if the exchange supports it
+func CheckExchangeConnectivity(ctx context.Context, exchName string, timeout time.Duration) (ConnectivityResult, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return ConnectivityResult{}, ErrExchangeNotFound
+	}
+	if timeout <= 0 {
+		timeout = defaultConnectivityTimeout
+	}
+	if timeout > maxConnectivityTimeout {
+		return ConnectivityResult{}, fmt.Errorf("timeout must not exceed %v",
+			maxConnectivityTimeout)
+	}
+
+	result := ConnectivityResult{
+		Exchange: exch.GetName(),
+		APIURLs:  exchangeAPIURLs(exch),
+	}
+	result.Checks = append(result.Checks, checkPublicConnectivity(ctx, exch, timeout))
+
+	if exch.GetAuthenticatedAPISupport() {
+		result.Checks = append(result.Checks, runConnectivityCheck("authenticated",
+			"GetAccountInfo", timeout, func() error {
+				_, err := exch.GetAccountInfo()
+				return err
+			}))
+	} else {
+		result.Checks = append(result.Checks, ConnectivityCheck{
+			Name:       "authenticated",
+			Request:    "GetAccountInfo",
+			Status:     ConnectivitySkipped,
+			Error:      exchange.ErrAuthenticationNotConfigured.Error(),
+			ErrorClass: ConnectivityErrorNotConfigured,
+		})
+	}
+
+	result.Passed = true
+	for x := range result.Checks {
+		if result.Checks[x].Status == ConnectivityFail {
+			result.Passed = false
+		}
+	}
+
+	connectivityResultMtx.Lock()
+	connectivityResults[common.StringToLower(result.Exchange)] = result
+	connectivityResultMtx.Unlock()
+	return result, nil
+}
+
+// authCredentialsRejected returns whether the last connectivity check of an
+// exchange failed its authenticated check due to its credentials. Exchanges
+// which haven't been checked aren't rejected
+func authCredentialsRejected(exchName string) bool {
+	connectivityResultMtx.RLock()
+	defer connectivityResultMtx.RUnlock()
+	result, ok := connectivityResults[common.StringToLower(exchName)]
+	if !ok {
+		return false
+	}
+	for x := range result.Checks {
+		if result.Checks[x].Name == "authenticated" &&
+			result.Checks[x].ErrorClass == ConnectivityErrorCredentials {
+			return true
+		}
+	}
+	return false
+}
+
+// clearConnectivityResult removes the last connectivity result of an
+// exchange, so reloaded credentials aren't judged by an earlier check
+func clearConnectivityResult(exchName string) {
+	connectivityResultMtx.Lock()
+	delete(connectivityResults, common.StringToLower(exchName))
+	connectivityResultMtx.Unlock()
+}
+
+// checkPublicConnectivity fetches the ticker of the first enabled pair and
+// asset type of an exchange from its public API. The request is aborted once
+// the timeout has elapsed if the exchange supports it
+func checkPublicConnectivity(ctx context.Context, exch exchange.IBotExchange, timeout time.Duration) ConnectivityCheck {
+	pairs := exch.GetEnabledCurrencies()
+	assetTypes := exch.GetAssetTypes()
+	if len(pairs) == 0 || len(assetTypes) == 0 {
+		return ConnectivityCheck{
+			Name:       "public",
+			Request:    "UpdateTicker",
+			Status:     ConnectivityFail,
+			Error:      "no enabled currency pairs or asset types",
+			ErrorClass: ConnectivityErrorNotConfigured,
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request := fmt.Sprintf("UpdateTicker %s %s", pairs[0].Pair(), assetTypes[0])
+	return runConnectivityCheck("public", request, timeout, func() error {
+		_, err := updateTickerWithContext(ctx, exch, pairs[0], assetTypes[0])
+		return err
+	})
+}
+
+// runConnectivityCheck runs a check, failing it if it doesn't complete within
+// the timeout
+func runConnectivityCheck(name, request string, timeout time.Duration, check func() error) ConnectivityCheck {
+	result := ConnectivityCheck{Name: name, Request: request}
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		err = ErrConnectivityTimeout
+	}
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		result.Status = ConnectivityFail
+		result.Error = err.Error()
+		result.ErrorClass = connectivityErrorClass(err)
+		return result
+	}
+	result.Status = ConnectivityPass
+	return result
+}
+
+// connectivityErrorClass returns the class of a connectivity check error
+func connectivityErrorClass(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrConnectivityTimeout):
+		return ConnectivityErrorTimeout
+	case errors.Is(err, exchange.ErrAuthenticationNotConfigured):
+		return ConnectivityErrorNotConfigured
+	case errors.Is(err, exchange.ErrCredentialsInvalid):
+		return ConnectivityErrorCredentials
+	case errors.Is(err, exchange.ErrRateLimited):
+		return ConnectivityErrorRateLimited
+	case errors.Is(err, exchange.ErrExchangeUnavailable):
+		return ConnectivityErrorUnavailable
+	case errors.Is(err, common.ErrFunctionNotSupported),
+		errors.Is(err, common.ErrNotYetImplemented):
+		return ConnectivityErrorNotSupported
+	case errors.As(err, &netErr):
+		return ConnectivityErrorNetwork
+	}
+	return ConnectivityErrorExchange
+}
+
+// exchangeAPIURLs returns the API URLs set on an exchange
+func exchangeAPIURLs(exch exchange.IBotExchange) []string {
+	urls := []string{}
+	exchURLs, ok := exch.(interface {
+		GetAPIURL() string
+		GetSecondaryAPIURL() string
+	})
+	if !ok {
+		return urls
+	}
+	for _, url := range []string{exchURLs.GetAPIURL(), exchURLs.GetSecondaryAPIURL()} {
+		if url != "" && !common.StringDataCompare(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// newConnectivityExchange returns a test exchange without credentials whose
+// ticker and account info requests succeed
+func newConnectivityExchange() *testExchange {
+	exch := newTestExchange("Connectivity")
+	exch.base.AuthenticatedAPISupport = false
+	exch.updateTicker = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		return ticker.Price{Pair: p, Last: 1}, nil
+	}
+	exch.getAccountInfo = func() (exchange.AccountInfo, error) {
+		return exchange.AccountInfo{Exchange: exch.GetName()}, nil
+	}
+	return exch
+}
+
+func TestCheckExchangeConnectivity(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	// The ticker and account info requests fail with the set errors after
+	// the set delay
+	var delay time.Duration
+	var tickerErr, accountErr error
+	exch := newConnectivityExchange()
+	exch.base.APIUrl = "https://api.example.com"
+	exch.base.APIUrlSecondary = "https://api.example.com"
+	exch.updateTicker = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		time.Sleep(delay)
+		return ticker.Price{Pair: p, Last: 1}, tickerErr
+	}
+	exch.getAccountInfo = func() (exchange.AccountInfo, error) {
+		return exchange.AccountInfo{Exchange: exch.GetName()}, accountErr
+	}
+	bot.exchanges = []exchange.IBotExchange{exch}
+
+	result, err := CheckExchangeConnectivity(context.Background(), "connectivity", 0)
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConnectivity error: %s", err)
+	}
+	if !result.Passed || len(result.Checks) != 2 ||
+		result.Checks[0].Status != ConnectivityPass ||
+		result.Checks[1].Status != ConnectivitySkipped ||
+		result.Checks[1].ErrorClass != ConnectivityErrorNotConfigured ||
+		exch.callCount("GetAccountInfo") != 0 {
+		t.Errorf("Test failed. Unexpected result without credentials %+v", result)
+	}
+	if len(result.APIURLs) != 1 || result.APIURLs[0] != "https://api.example.com" {
+		t.Errorf("Test failed. Unexpected API URLs %v", result.APIURLs)
+	}
+
+	exch.base.AuthenticatedAPISupport = true
+	accountErr = exchange.WrapError(exchange.ErrCredentialsInvalid, "invalid signature")
+	tickerErr = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	result, err = CheckExchangeConnectivity(context.Background(), "Connectivity", 0)
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConnectivity error: %s", err)
+	}
+	if result.Passed || result.Checks[0].ErrorClass != ConnectivityErrorNetwork ||
+		result.Checks[1].Status != ConnectivityFail ||
+		result.Checks[1].ErrorClass != ConnectivityErrorCredentials ||
+		result.Checks[1].Error != "invalid signature" {
+		t.Errorf("Test failed. Unexpected result with failing checks %+v", result)
+	}
+
+	tickerErr, accountErr = nil, nil
+	delay = time.Millisecond * 100
+	result, err = CheckExchangeConnectivity(context.Background(), "Connectivity", time.Millisecond*10)
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConnectivity error: %s", err)
+	}
+	if result.Passed || result.Checks[0].ErrorClass != ConnectivityErrorTimeout ||
+		result.Checks[0].Latency >= delay || result.Checks[1].Status != ConnectivityPass {
+		t.Errorf("Test failed. Unexpected result with a slow exchange %+v", result)
+	}
+
+	_, err = CheckExchangeConnectivity(context.Background(), "Connectivity", time.Hour)
+	if err == nil {
+		t.Error("Test failed. Expected a timeout error")
+	}
+
+	_, err = CheckExchangeConnectivity(context.Background(), "Missing", 0)
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrExchangeNotFound, err)
+	}
+}
+
+func TestRESTCheckExchangeConnectivity(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	bot.exchanges = []exchange.IBotExchange{newConnectivityExchange()}
+
+	router := NewRouter()
+	for path, code := range map[string]int{
+		"/exchanges/Connectivity/connectivity":              http.StatusOK,
+		"/exchanges/Connectivity/connectivity?timeout=1s":   http.StatusOK,
+		"/exchanges/Connectivity/connectivity?timeout=soon": http.StatusBadRequest,
+		"/exchanges/Connectivity/connectivity?timeout=2h":   http.StatusBadRequest,
+		"/exchanges/Missing/connectivity":                   http.StatusNotFound,
+	} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.SetBasicAuth(bot.config.Webserver.AdminUsername, bot.config.Webserver.AdminPassword)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != code {
+			t.Errorf("Test failed. %s expected status %d, got %d", path, code, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exchanges/Connectivity/connectivity", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+const (
+	// consolidatedOrderbookMaxAge is the age above which a cached exchange
+	// orderbook is too stale to be consolidated
+	consolidatedOrderbookMaxAge = time.Minute
+	// defaultConsolidatedOrderbookDepth is the number of levels returned on
+	// each side of a consolidated orderbook when no depth is supplied
+	defaultConsolidatedOrderbookDepth = 50
+	// maxConsolidatedOrderbookDepth is the largest number of levels which can
+	// be returned on each side of a consolidated orderbook
+	maxConsolidatedOrderbookDepth = 1000
+)
+
+var (
+	// ErrNoOrderbookSource is returned when no enabled exchange lists the
+	// currency pair and asset type of a consolidated orderbook
+	ErrNoOrderbookSource = errors.New("no enabled exchange lists the currency pair and asset type")
+	// ErrInvalidOrderbookDepth is returned when a consolidated orderbook depth
+	// exceeds the maximum
+	ErrInvalidOrderbookDepth = errors.New("invalid orderbook depth")
+)
+
+// ConsolidatedOrderbookItem is a price level of a consolidated orderbook and
+// the exchange it comes from. Levels of different exchanges at the same price
+// are kept separate
+type ConsolidatedOrderbookItem struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`
+	Amount   float64 `json:"amount"`
+}
+
+// ConsolidatedOrderbookSource holds the exchange orderbook used to build a
+// consolidated orderbook. Exchanges whose cached orderbook is missing or stale
+// hold the reason in Error
+type ConsolidatedOrderbookSource struct {
+	Exchange    string    `json:"exchange"`
+	Pair        string    `json:"pair"`
+	Quote       string    `json:"quote"`
+	Bids        int       `json:"bids"`
+	Asks        int       `json:"asks"`
+	LastUpdated time.Time `json:"lastUpdated,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ConsolidatedOrderbook holds the merged price levels of a currency pair
+// across exchanges, bids from the highest price and asks from the lowest,
+// priced in the quote currency of the requested pair
+type ConsolidatedOrderbook struct {
+	Pair      string                        `json:"pair"`
+	AssetType string                        `json:"assetType"`
+	Quote     string                        `json:"quote"`
+	Depth     int                           `json:"depth"`
+	Bids      []ConsolidatedOrderbookItem   `json:"bids"`
+	Asks      []ConsolidatedOrderbookItem   `json:"asks"`
+	Sources   []ConsolidatedOrderbookSource `json:"sources"`
+}
+
+// BuildConsolidatedOrderbook merges the cached orderbooks of every enabled
+// exchange listing the currency pair, matched by its canonical form, into a
+// single orderbook limited to depth levels on each side, the default depth is
+// used if zero. Exchanges quoting the translated quote currency, such as USDT
+// for USD, are included with their prices treated at par. Stale orderbooks are
+// skipped and recorded in the sources
+func BuildConsolidatedOrderbook(p pair.CurrencyPair, assetType string, depth int) (ConsolidatedOrderbook, error) {
+	if depth <= 0 {
+		depth = defaultConsolidatedOrderbookDepth
+	}
+	if depth > maxConsolidatedOrderbookDepth {
+		return ConsolidatedOrderbook{}, fmt.Errorf("%w: depth must not exceed %d",
+			ErrInvalidOrderbookDepth, maxConsolidatedOrderbookDepth)
+	}
+	if assetType == "" {
+		assetType = orderbook.Spot
+	}
+	assetType, err := parseExchangesAssetType(assetType)
+	if err != nil {
+		return ConsolidatedOrderbook{}, err
+	}
+
+	target := newCanonicalPair(p)
+	result := ConsolidatedOrderbook{
+		Pair:      p.Pair().String(),
+		AssetType: assetType,
+		Quote:     target.second.String(),
+		Depth:     depth,
+		Bids:      []ConsolidatedOrderbookItem{},
+		Asks:      []ConsolidatedOrderbookItem{},
+	}
+	for x := range bot.exchanges {
+		exch := bot.exchanges[x]
+		if exch == nil || !exch.IsEnabled() || !exch.SupportsAsset(assetType) {
+			continue
+		}
+		exchPair, ok := consolidatedPair(exch.GetEnabledCurrencies(), target)
+		if !ok {
+			continue
+		}
+
+		source := ConsolidatedOrderbookSource{
+			Exchange: exch.GetName(),
+			Pair:     exchPair.Pair().String(),
+			Quote:    exchPair.Quote().Upper().String(),
+		}
+		ob, err := orderbook.GetOrderbook(exch.GetName(), exchPair, assetType)
+		switch {
+		case err != nil:
+			source.Error = fmt.Sprintf("no cached orderbook: %s", err)
+		case time.Since(ob.LastUpdated) > consolidatedOrderbookMaxAge:
+			source.LastUpdated, source.Source = ob.LastUpdated, ob.Source
+			source.Error = fmt.Sprintf("orderbook is stale, last updated %v ago",
+				time.Since(ob.LastUpdated).Round(time.Second))
+		default:
+			source.LastUpdated, source.Source = ob.LastUpdated, ob.Source
+			bids := consolidateLevels(exch.GetName(), ob.Bids)
+			asks := consolidateLevels(exch.GetName(), ob.Asks)
+			source.Bids, source.Asks = len(bids), len(asks)
+			result.Bids = append(result.Bids, bids...)
+			result.Asks = append(result.Asks, asks...)
+		}
+		result.Sources = append(result.Sources, source)
+	}
+	if len(result.Sources) == 0 {
+		return ConsolidatedOrderbook{}, ErrNoOrderbookSource
+	}
+
+	sortConsolidatedLevels(result.Bids, true)
+	sortConsolidatedLevels(result.Asks, false)
+	if len(result.Bids) > depth {
+		result.Bids = result.Bids[:depth]
+	}
+	if len(result.Asks) > depth {
+		result.Asks = result.Asks[:depth]
+	}
+	return result, nil
+}
+
+// consolidatedPair returns the enabled currency pair matching the canonical
+// pair, preferring the same quote currency over its translation
+func consolidatedPair(enabledPairs []pair.CurrencyPair, target canonicalPair) (pair.CurrencyPair, bool) {
+	translated, err := translation.GetTranslation(target.second)
+	var match pair.CurrencyPair
+	var found bool
+	for x := range enabledPairs {
+		c := newCanonicalPair(enabledPairs[x])
+		if c.first != target.first {
+			continue
+		}
+		if c.second == target.second {
+			return enabledPairs[x], true
+		}
+		if !found && err == nil && c.second == translated {
+			match, found = enabledPairs[x], true
+		}
+	}
+	return match, found
+}
+
+// consolidateLevels returns the price levels of an exchange orderbook side,
+// merging levels at the same price and skipping empty levels
+func consolidateLevels(exchangeName string, items []orderbook.Item) []ConsolidatedOrderbookItem {
+	var levels []ConsolidatedOrderbookItem
+	index := make(map[float64]int)
+	for x := range items {
+		if items[x].Price <= 0 || items[x].Amount <= 0 {
+			continue
+		}
+		if i, ok := index[items[x].Price]; ok {
+			levels[i].Amount += items[x].Amount
+			continue
+		}
+		index[items[x].Price] = len(levels)
+		levels = append(levels, ConsolidatedOrderbookItem{
+			Exchange: exchangeName,
+			Price:    items[x].Price,
+			Amount:   items[x].Amount,
+		})
+	}
+	return levels
+}
+
+// sortConsolidatedLevels sorts bids from the highest price and asks from the
+// lowest, levels at the same price are ordered by exchange name
+func sortConsolidatedLevels(levels []ConsolidatedOrderbookItem, bids bool) {
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].Price != levels[j].Price {
+			if bids {
+				return levels[i].Price > levels[j].Price
+			}
+			return levels[i].Price < levels[j].Price
+		}
+		return levels[i].Exchange < levels[j].Exchange
+	})
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+)
+
+// setupConsolidatedOrderbooks caches fixture orderbooks for three exchanges
+// listing BTC USD, one of them quoting USDT, and exchanges with a stale,
+// missing or unrelated orderbook until the returned func is called
+func setupConsolidatedOrderbooks() func() {
+	exchanges := bot.exchanges
+	usd := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+	xbtusd := pair.NewCurrencyPair("XBT", "USD")
+	usdt := pair.NewCurrencyPair("BTC", "USDT")
+	// The exchanges list a fixed set of spot currency pairs
+	listing := func(name string, pairs ...pair.CurrencyPair) *testExchange {
+		exch := newTestExchange(name)
+		exch.enabledPairs = pairs
+		return exch
+	}
+	disabled := listing("ConsDisabled", usd)
+	disabled.base.Enabled = false
+	bot.exchanges = []exchange.IBotExchange{
+		listing("ConsA", usd),
+		listing("ConsB", pair.NewCurrencyPair("XBT", "USDT"), xbtusd),
+		listing("ConsC", usdt),
+		listing("ConsStale", usd),
+		listing("ConsMissing", usd),
+		listing("ConsOther", pair.NewCurrencyPair("LTC", "USD")),
+		disabled,
+	}
+
+	orderbook.ProcessOrderbook("ConsA", usd, orderbook.Base{
+		Bids: []orderbook.Item{{Price: 100, Amount: 1}, {Price: 99, Amount: 2},
+			{Price: 99, Amount: 1}, {Price: 97, Amount: 0}},
+		Asks: []orderbook.Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}},
+	}, orderbook.Spot)
+	orderbook.ProcessOrderbook("ConsB", xbtusd, orderbook.Base{
+		Bids: []orderbook.Item{{Price: 100, Amount: 0.5}, {Price: 98, Amount: 1}},
+		Asks: []orderbook.Item{{Price: 101, Amount: 2}, {Price: 103, Amount: 1}},
+	}, orderbook.Spot)
+	orderbook.ProcessOrderbook("ConsC", usdt, orderbook.Base{
+		Bids: []orderbook.Item{{Price: 100.5, Amount: 1}},
+		Asks: []orderbook.Item{{Price: 100.8, Amount: 1}},
+	}, orderbook.Spot)
+	orderbook.CreateNewOrderbook("ConsStale", usd, orderbook.Base{
+		Bids:        []orderbook.Item{{Price: 150, Amount: 1}},
+		Asks:        []orderbook.Item{{Price: 50, Amount: 1}},
+		LastUpdated: time.Now().Add(-consolidatedOrderbookMaxAge * 2),
+	}, orderbook.Spot)
+
+	return func() {
+		bot.exchanges = exchanges
+		for _, name := range []string{"ConsA", "ConsB", "ConsC", "ConsStale"} {
+			orderbook.RemoveExchangeOrderbooks(name)
+		}
+	}
+}
+
+func TestBuildConsolidatedOrderbook(t *testing.T) {
+	SetupTestHelpers(t)
+	defer setupConsolidatedOrderbooks()()
+
+	result, err := BuildConsolidatedOrderbook(pair.NewCurrencyPair("btc", "usd"), "", 0)
+	if err != nil {
+		t.Fatalf("Test failed. BuildConsolidatedOrderbook error: %s", err)
+	}
+	if result.AssetType != orderbook.Spot || result.Quote != "USD" ||
+		result.Depth != defaultConsolidatedOrderbookDepth {
+		t.Errorf("Test failed. Unexpected consolidated orderbook %+v", result)
+	}
+
+	// Levels at the same price are merged per exchange but kept separate
+	// across exchanges, the USDT quoted levels of ConsC are treated at par
+	expectedBids := []ConsolidatedOrderbookItem{
+		{"ConsC", 100.5, 1},
+		{"ConsA", 100, 1},
+		{"ConsB", 100, 0.5},
+		{"ConsA", 99, 3},
+		{"ConsB", 98, 1},
+	}
+	expectedAsks := []ConsolidatedOrderbookItem{
+		{"ConsC", 100.8, 1},
+		{"ConsA", 101, 1},
+		{"ConsB", 101, 2},
+		{"ConsA", 102, 2},
+		{"ConsB", 103, 1},
+	}
+	if !reflect.DeepEqual(result.Bids, expectedBids) {
+		t.Errorf("Test failed. Expected bids %v, got %v", expectedBids, result.Bids)
+	}
+	if !reflect.DeepEqual(result.Asks, expectedAsks) {
+		t.Errorf("Test failed. Expected asks %v, got %v", expectedAsks, result.Asks)
+	}
+
+	if len(result.Sources) != 5 {
+		t.Fatalf("Test failed. Expected 5 sources, got %+v", result.Sources)
+	}
+	for x, expected := range []ConsolidatedOrderbookSource{
+		{Exchange: "ConsA", Pair: "BTC_USD", Quote: "USD", Bids: 2, Asks: 2},
+		{Exchange: "ConsB", Pair: "XBTUSD", Quote: "USD", Bids: 2, Asks: 2},
+		{Exchange: "ConsC", Pair: "BTCUSDT", Quote: "USDT", Bids: 1, Asks: 1},
+	} {
+		source := result.Sources[x]
+		if source.Exchange != expected.Exchange || source.Pair != expected.Pair ||
+			source.Quote != expected.Quote || source.Bids != expected.Bids ||
+			source.Asks != expected.Asks || source.Error != "" || source.LastUpdated.IsZero() {
+			t.Errorf("Test failed. Expected source %+v, got %+v", expected, source)
+		}
+	}
+	if result.Sources[3].Exchange != "ConsStale" || result.Sources[3].Error == "" ||
+		result.Sources[4].Exchange != "ConsMissing" || result.Sources[4].Error == "" {
+		t.Errorf("Test failed. Expected stale and missing orderbooks to be skipped, got %+v",
+			result.Sources[3:])
+	}
+
+	result, err = BuildConsolidatedOrderbook(pair.NewCurrencyPair("XBT", "USDT"), "spot", 3)
+	if err != nil {
+		t.Fatalf("Test failed. BuildConsolidatedOrderbook error: %s", err)
+	}
+	if len(result.Bids) != 3 || len(result.Asks) != 3 || result.Quote != "USDT" {
+		t.Errorf("Test failed. Expected 3 levels on each side, got %+v", result)
+	}
+	if result.Sources[1].Pair != "XBTUSDT" {
+		t.Errorf("Test failed. Expected the USDT pair to be preferred, got %s",
+			result.Sources[1].Pair)
+	}
+
+	_, err = BuildConsolidatedOrderbook(pair.NewCurrencyPair("ETH", "USD"), "", 0)
+	if err != ErrNoOrderbookSource {
+		t.Errorf("Test failed. Expected a no orderbook source error, got %v", err)
+	}
+	_, err = BuildConsolidatedOrderbook(pair.NewCurrencyPair("BTC", "USD"), "",
+		maxConsolidatedOrderbookDepth+1)
+	if !errors.Is(err, ErrInvalidOrderbookDepth) {
+		t.Errorf("Test failed. Expected an invalid depth error, got %v", err)
+	}
+	_, err = BuildConsolidatedOrderbook(pair.NewCurrencyPair("BTC", "USD"), "blah", 0)
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Errorf("Test failed. Expected an invalid asset type error, got %v", err)
+	}
+}
+
+func TestRESTGetConsolidatedOrderbook(t *testing.T) {
+	SetupTestHelpers(t)
+	defer setupConsolidatedOrderbooks()()
+
+	router := NewRouter()
+	for _, test := range []struct {
+		path string
+		code int
+	}{
+		{"/orderbook/consolidated/BTC_USD?depth=2", http.StatusOK},
+		{"/orderbook/consolidated/BT", http.StatusBadRequest},
+		{"/orderbook/consolidated/BTC_USD?depth=two", http.StatusBadRequest},
+		{"/orderbook/consolidated/BTC_USD?depth=5000", http.StatusBadRequest},
+		{"/orderbook/consolidated/BTC_USD?assetType=blah", http.StatusBadRequest},
+		{"/orderbook/consolidated/ETH_USD", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if w.Code != test.code {
+			t.Errorf("Test failed. %s expected status %d, got %d", test.path, test.code,
+				w.Code)
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+
+		var result ConsolidatedOrderbook
+		err := common.JSONDecode(w.Body.Bytes(), &result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Bids) != 2 || result.Bids[0].Exchange != "ConsC" ||
+			len(result.Asks) != 2 || len(result.Sources) != 5 {
+			t.Errorf("Test failed. Unexpected consolidated orderbook %+v", result)
+		}
+	}
+}
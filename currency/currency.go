@@ -24,6 +24,10 @@ const (
 // Manager is the overarching type across this package
 var (
 	FXRates map[string]float64
+	// FXRatesProvider is the name of the forex provider which supplied the
+	// last rates and FXRatesUpdated is when they were fetched
+	FXRatesProvider string
+	FXRatesUpdated  time.Time
 
 	FiatCurrencies   []string
 	CryptoCurrencies []string
@@ -60,7 +64,8 @@ func SeedCurrencyData(currencies string) error {
 		FXProviders = forexprovider.NewDefaultFXProvider()
 	}
 
-	newRates, err := FXProviders.GetCurrencyData(BaseCurrency, currencies)
+	newRates, provider, err := FXProviders.GetCurrencyDataWithProvider(BaseCurrency,
+		currencies)
 	if err != nil {
 		return err
 	}
@@ -68,10 +73,46 @@ func SeedCurrencyData(currencies string) error {
 	for key, value := range newRates {
 		FXRates[key] = value
 	}
+	FXRatesProvider = provider
+	FXRatesUpdated = time.Now()
 
 	return nil
 }
 
+// Errors returned when a currency conversion fails
+var (
+	ErrNoForexRates      = errors.New("no forex rates have been fetched")
+	ErrForexRateNotFound = errors.New("forex rate not found")
+)
+
+// ForexRates holds the forex rates against a base currency, keyed by the base
+// currency followed by the quoted currency, e.g. USDAUD
+type ForexRates struct {
+	BaseCurrency string             `json:"baseCurrency"`
+	Provider     string             `json:"provider"`
+	LastUpdated  time.Time          `json:"lastUpdated"`
+	Rates        map[string]float64 `json:"rates"`
+}
+
+// GetForexRates returns the forex rates with their base currency, provider
+// and when they were fetched
+func GetForexRates() ForexRates {
+	baseCurr := extractBaseCurrency()
+	if baseCurr == "" {
+		baseCurr = BaseCurrency
+	}
+	rates := make(map[string]float64, len(FXRates))
+	for k, v := range FXRates {
+		rates[k] = v
+	}
+	return ForexRates{
+		BaseCurrency: baseCurr,
+		Provider:     FXRatesProvider,
+		LastUpdated:  FXRatesUpdated,
+		Rates:        rates,
+	}
+}
+
 // GetExchangeRates returns the currency exchange rates
 func GetExchangeRates() map[string]float64 {
 	return FXRates
@@ -80,58 +121,46 @@ func GetExchangeRates() map[string]float64 {
 // IsDefaultCurrency checks if the currency passed in matches the default fiat
 // currency
 func IsDefaultCurrency(currency string) bool {
-	defaultCurrencies := common.SplitStrings(DefaultCurrencies, ",")
-	return common.StringDataCompare(defaultCurrencies, common.StringToUpper(currency))
+	return defaultFiatSet[common.StringToUpper(currency)]
 }
 
 // IsDefaultCryptocurrency checks if the currency passed in matches the default
 // cryptocurrency
 func IsDefaultCryptocurrency(currency string) bool {
-	cryptoCurrencies := common.SplitStrings(DefaultCryptoCurrencies, ",")
-	return common.StringDataCompare(cryptoCurrencies, common.StringToUpper(currency))
+	return defaultCryptoSet[common.StringToUpper(currency)]
 }
 
 // IsFiatCurrency checks if the currency passed is an enabled fiat currency
 func IsFiatCurrency(currency string) bool {
-	return common.StringDataCompare(FiatCurrencies, common.StringToUpper(currency))
+	return isListed(&fiatIndex, &FiatCurrencies, currency)
 }
 
 // IsCryptocurrency checks if the currency passed is an enabled CRYPTO currency.
 func IsCryptocurrency(currency string) bool {
-	return common.StringDataCompare(CryptoCurrencies, common.StringToUpper(currency))
+	return isListed(&cryptoIndex, &CryptoCurrencies, currency)
 }
 
 // IsCryptoPair checks to see if the pair is a crypto pair e.g. BTCLTC
 func IsCryptoPair(p pair.CurrencyPair) bool {
-	return IsCryptocurrency(p.FirstCurrency.String()) &&
-		IsCryptocurrency(p.SecondCurrency.String())
+	return IsCryptocurrency(p.Base().String()) &&
+		IsCryptocurrency(p.Quote().String())
 }
 
 // IsCryptoFiatPair checks to see if the pair is a crypto fiat pair e.g. BTCUSD
 func IsCryptoFiatPair(p pair.CurrencyPair) bool {
-	return IsCryptocurrency(p.FirstCurrency.String()) && !IsCryptocurrency(p.SecondCurrency.String()) ||
-		!IsCryptocurrency(p.FirstCurrency.String()) && IsCryptocurrency(p.SecondCurrency.String())
+	return IsCryptocurrency(p.Base().String()) && !IsCryptocurrency(p.Quote().String()) ||
+		!IsCryptocurrency(p.Base().String()) && IsCryptocurrency(p.Quote().String())
 }
 
 // IsFiatPair checks to see if the pair is a fiat pair e.g. EURUSD
 func IsFiatPair(p pair.CurrencyPair) bool {
-	return IsFiatCurrency(p.FirstCurrency.String()) &&
-		IsFiatCurrency(p.SecondCurrency.String())
+	return IsFiatCurrency(p.Base().String()) &&
+		IsFiatCurrency(p.Quote().String())
 }
 
 // Update updates the local crypto currency or base currency store
 func Update(input []string, cryptos bool) {
-	for x := range input {
-		if cryptos {
-			if !common.StringDataCompare(CryptoCurrencies, input[x]) {
-				CryptoCurrencies = append(CryptoCurrencies, common.StringToUpper(input[x]))
-			}
-		} else {
-			if !common.StringDataCompare(FiatCurrencies, input[x]) {
-				FiatCurrencies = append(FiatCurrencies, common.StringToUpper(input[x]))
-			}
-		}
-	}
+	Register(input, cryptos)
 }
 
 func extractBaseCurrency() string {
@@ -141,9 +170,32 @@ func extractBaseCurrency() string {
 	return ""
 }
 
+// Conversion holds a currency conversion, the rate applied and the currencies
+// converted through. Currencies are converted through the base currency of the
+// forex rates when neither is the base currency
+type Conversion struct {
+	Amount    float64  `json:"amount"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Converted float64  `json:"converted"`
+	Rate      float64  `json:"rate"`
+	Path      []string `json:"path"`
+}
+
 // ConvertCurrency for example converts $1 USD to the equivalent Japanese Yen
 // or vice versa.
 func ConvertCurrency(amount float64, from, to string) (float64, error) {
+	conversion, err := ConvertCurrencyWithPath(amount, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return conversion.Converted, nil
+}
+
+// ConvertCurrencyWithPath converts an amount between two currencies and
+// returns the conversion with the rate and path used. Rates are fetched if
+// none have been yet
+func ConvertCurrencyWithPath(amount float64, from, to string) (Conversion, error) {
 	if FXProviders == nil {
 		SetDefaults()
 	}
@@ -151,10 +203,6 @@ func ConvertCurrency(amount float64, from, to string) (float64, error) {
 	from = common.StringToUpper(from)
 	to = common.StringToUpper(to)
 
-	if from == to {
-		return amount, nil
-	}
-
 	if from == "RUR" {
 		from = "RUB"
 	}
@@ -163,50 +211,51 @@ func ConvertCurrency(amount float64, from, to string) (float64, error) {
 		to = "RUB"
 	}
 
+	conversion := Conversion{Amount: amount, From: from, To: to}
+	if from == to {
+		conversion.Converted = amount
+		conversion.Rate = 1
+		conversion.Path = []string{from}
+		return conversion, nil
+	}
+
 	if len(FXRates) == 0 {
-		SeedCurrencyData(from + "," + to)
+		err := SeedCurrencyData(from + "," + to)
+		if err != nil || len(FXRates) == 0 {
+			return Conversion{}, fmt.Errorf("currency conversion %s -> %s failed: %w",
+				from, to, ErrNoForexRates)
+		}
 	}
 
 	// Need to extract the base currency to see if we actually got it from the Forex API
 	// Fixer free API sets the base currency to EUR
 	baseCurr := extractBaseCurrency()
 
-	var resultFrom float64
-	var resultTo float64
-
-	// check to see if we're converting from the base currency
-	if to == baseCurr {
+	rateFrom, rateTo := 1.0, 1.0
+	if from != baseCurr {
 		var ok bool
-		resultFrom, ok = FXRates[baseCurr+from]
-		if !ok {
-			return 0, fmt.Errorf("Currency conversion failed. Unable to find %s in currency map [%s -> %s]", from, from, to)
+		rateFrom, ok = FXRates[baseCurr+from]
+		if !ok || rateFrom == 0 {
+			return Conversion{}, fmt.Errorf("currency conversion %s -> %s failed: %w for %s against base currency %s",
+				from, to, ErrForexRateNotFound, from, baseCurr)
 		}
-		return amount / resultFrom, nil
 	}
-
-	// Check to see if we're converting from the base currency
-	if from == baseCurr {
+	if to != baseCurr {
 		var ok bool
-		resultTo, ok = FXRates[baseCurr+to]
+		rateTo, ok = FXRates[baseCurr+to]
 		if !ok {
-			return 0, fmt.Errorf("Currency conversion failed. Unable to find %s in currency map [%s -> %s]", to, from, to)
+			return Conversion{}, fmt.Errorf("currency conversion %s -> %s failed: %w for %s against base currency %s",
+				from, to, ErrForexRateNotFound, to, baseCurr)
 		}
-		return resultTo * amount, nil
-	}
-
-	// Otherwise convert to base currency, then to the target currency
-	resultFrom, ok := FXRates[baseCurr+from]
-	if !ok {
-		return 0, fmt.Errorf("Currency conversion failed. Unable to find %s in currency map [%s -> %s]", from, from, to)
 	}
 
-	converted := amount / resultFrom
-	resultTo, ok = FXRates[baseCurr+to]
-	if !ok {
-		return 0, fmt.Errorf("Currency conversion failed. Unable to find %s in currency map [%s -> %s]", to, from, to)
+	conversion.Path = []string{from, to}
+	if from != baseCurr && to != baseCurr {
+		conversion.Path = []string{from, baseCurr, to}
 	}
-
-	return converted * resultTo, nil
+	conversion.Rate = rateTo / rateFrom
+	conversion.Converted = amount / rateFrom * rateTo
+	return conversion, nil
 }
 
 // Data defines information pertaining to exchange or a cryptocurrency from
@@ -1,8 +1,13 @@
 package currency
 
 import (
+	"errors"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/thrasher-/gocryptotrader/currency/forexprovider"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 )
 
@@ -257,3 +262,66 @@ func TestConvertCurrency(t *testing.T) {
 	}
 
 }
+
+func TestConvertCurrencyWithPath(t *testing.T) {
+	rates, providers := FXRates, FXProviders
+	defer func() { FXRates, FXProviders = rates, providers }()
+
+	FXProviders = new(forexprovider.ForexProviders)
+	FXRates = map[string]float64{"USDAUD": 1.25, "USDEUR": 0.8, "USDRUB": 60}
+
+	tests := []struct {
+		from, to  string
+		converted float64
+		rate      float64
+		path      []string
+	}{
+		{"USD", "AUD", 1234.56 * 1.25, 1.25, []string{"USD", "AUD"}},
+		{"aud", "usd", 1234.56 / 1.25, 0.8, []string{"AUD", "USD"}},
+		{"AUD", "EUR", 1234.56 / 1.25 * 0.8, 0.64, []string{"AUD", "USD", "EUR"}},
+		{"EUR", "eur", 1234.56, 1, []string{"EUR"}},
+		{"RUR", "USD", 1234.56 / 60, 1.0 / 60, []string{"RUB", "USD"}},
+	}
+	for i := range tests {
+		result, err := ConvertCurrencyWithPath(1234.56, tests[i].from, tests[i].to)
+		if err != nil {
+			t.Fatalf("Test failed. Case %d error: %s", i, err)
+		}
+		if math.Abs(result.Converted-tests[i].converted) > 1e-9 ||
+			math.Abs(result.Rate-tests[i].rate) > 1e-9 ||
+			strings.Join(result.Path, ",") != strings.Join(tests[i].path, ",") {
+			t.Errorf("Test failed. Case %d unexpected conversion %+v", i, result)
+		}
+	}
+
+	_, err := ConvertCurrencyWithPath(1, "AUD", "JPY")
+	if !errors.Is(err, ErrForexRateNotFound) || !strings.Contains(err.Error(), "JPY") {
+		t.Errorf("Test failed. Expected %v for JPY, got %v", ErrForexRateNotFound, err)
+	}
+
+	FXRates = make(map[string]float64)
+	_, err = ConvertCurrencyWithPath(1, "AUD", "USD")
+	if !errors.Is(err, ErrNoForexRates) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrNoForexRates, err)
+	}
+}
+
+func TestGetForexRates(t *testing.T) {
+	rates, provider, updated := FXRates, FXRatesProvider, FXRatesUpdated
+	defer func() { FXRates, FXRatesProvider, FXRatesUpdated = rates, provider, updated }()
+
+	FXRates = map[string]float64{"EURUSD": 1.2}
+	FXRatesProvider = "Fixer"
+	FXRatesUpdated = time.Unix(1538740800, 0)
+
+	result := GetForexRates()
+	if result.BaseCurrency != "EUR" || result.Provider != "Fixer" ||
+		!result.LastUpdated.Equal(FXRatesUpdated) || result.Rates["EURUSD"] != 1.2 {
+		t.Errorf("Test failed. Unexpected forex rates %+v", result)
+	}
+
+	result.Rates["EURUSD"] = 2
+	if FXRates["EURUSD"] != 1.2 {
+		t.Error("Test failed. Forex rates weren't copied")
+	}
+}
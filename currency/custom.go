@@ -0,0 +1,146 @@
+package currency
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+var (
+	// listMtx guards updates of FiatCurrencies and CryptoCurrencies and the
+	// user defined overrides applied to them
+	listMtx sync.RWMutex
+	// customCrypto, customFiat and removedCurrencies are the overrides set by
+	// SetCustomCurrencies, currencies are only registered as the type they
+	// are overridden as and removed currencies aren't registered at all
+	customCrypto      = make(map[string]bool)
+	customFiat        = make(map[string]bool)
+	removedCurrencies = make(map[string]bool)
+
+	cryptoIndex currencyIndex
+	fiatIndex   currencyIndex
+
+	defaultFiatSet   = currencySet(common.SplitStrings(DefaultCurrencies, ","))
+	defaultCryptoSet = currencySet(common.SplitStrings(DefaultCryptoCurrencies, ","))
+)
+
+// currencyIndex is a lookup set of a currency list so classification doesn't
+// scan the list. It is rebuilt when the list is replaced or grows
+type currencyIndex struct {
+	mtx    sync.RWMutex
+	source []string
+	set    map[string]bool
+}
+
+// sameList returns whether both slices share the same length and backing
+// array
+func sameList(a, b []string) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// contains returns whether the upper case currency is in the list
+func (i *currencyIndex) contains(list []string, currency string) bool {
+	i.mtx.RLock()
+	if i.set != nil && sameList(i.source, list) {
+		found := i.set[currency]
+		i.mtx.RUnlock()
+		return found
+	}
+	i.mtx.RUnlock()
+
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	if i.set == nil || !sameList(i.source, list) {
+		i.source = list
+		i.set = currencySet(list)
+	}
+	return i.set[currency]
+}
+
+// currencySet returns a lookup set of the upper case currencies
+func currencySet(currencies []string) map[string]bool {
+	set := make(map[string]bool, len(currencies))
+	for x := range currencies {
+		if curr := strings.TrimSpace(currencies[x]); curr != "" {
+			set[common.StringToUpper(curr)] = true
+		}
+	}
+	return set
+}
+
+// isListed returns whether the currency is in the list, which is read under
+// the list lock as it may be replaced by a concurrent update
+func isListed(index *currencyIndex, list *[]string, currency string) bool {
+	listMtx.RLock()
+	l := *list
+	listMtx.RUnlock()
+	return index.contains(l, common.StringToUpper(currency))
+}
+
+// removeCurrencies returns a copy of the list without the currencies in the
+// set
+func removeCurrencies(list []string, set map[string]bool) []string {
+	var result []string
+	for x := range list {
+		if !set[common.StringToUpper(list[x])] {
+			result = append(result, list[x])
+		}
+	}
+	return result
+}
+
+// SetCustomCurrencies sets the user defined cryptocurrencies and fiat
+// currencies and merges them into the currency lists. A currency listed as
+// the other type, such as a default cryptocurrency declared as fiat, is
+// reclassified and removed currencies are dropped from both lists. The
+// overrides replace any previously set and apply to later list updates
+func SetCustomCurrencies(cryptos, fiats, removed []string) {
+	listMtx.Lock()
+	customCrypto = currencySet(cryptos)
+	customFiat = currencySet(fiats)
+	removedCurrencies = currencySet(removed)
+
+	CryptoCurrencies = removeCurrencies(CryptoCurrencies, customFiat)
+	CryptoCurrencies = removeCurrencies(CryptoCurrencies, removedCurrencies)
+	FiatCurrencies = removeCurrencies(FiatCurrencies, customCrypto)
+	FiatCurrencies = removeCurrencies(FiatCurrencies, removedCurrencies)
+	listMtx.Unlock()
+
+	Register(cryptos, true)
+	Register(fiats, false)
+}
+
+// Register adds the currencies to the cryptocurrency or fiat currency list
+// and returns the currencies added. Currencies already listed as either type,
+// removed or overridden as the other type are skipped
+func Register(input []string, cryptos bool) []string {
+	listMtx.Lock()
+	defer listMtx.Unlock()
+
+	listed := currencySet(CryptoCurrencies)
+	for x := range FiatCurrencies {
+		listed[common.StringToUpper(FiatCurrencies[x])] = true
+	}
+	other := customCrypto
+	if cryptos {
+		other = customFiat
+	}
+
+	var added []string
+	for x := range input {
+		curr := common.StringToUpper(strings.TrimSpace(input[x]))
+		if curr == "" || listed[curr] || other[curr] || removedCurrencies[curr] {
+			continue
+		}
+		listed[curr] = true
+		added = append(added, curr)
+	}
+
+	if cryptos {
+		CryptoCurrencies = append(CryptoCurrencies, added...)
+	} else {
+		FiatCurrencies = append(FiatCurrencies, added...)
+	}
+	return added
+}
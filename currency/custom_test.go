@@ -0,0 +1,67 @@
+package currency
+
+import (
+	"testing"
+)
+
+// resetCurrencyLists restores the currency lists and clears the overrides
+func resetCurrencyLists(cryptos, fiats []string) {
+	SetCustomCurrencies(nil, nil, nil)
+	CryptoCurrencies = cryptos
+	FiatCurrencies = fiats
+}
+
+func TestSetCustomCurrencies(t *testing.T) {
+	defer resetCurrencyLists(CryptoCurrencies, FiatCurrencies)
+	CryptoCurrencies = []string{"BTC", "LTC", "XRP"}
+	FiatCurrencies = []string{"USD", "EUR"}
+
+	SetCustomCurrencies([]string{"newcoin", " EURT"}, []string{"XRP", "SGD"}, []string{"LTC"})
+	for curr, expected := range map[string]struct{ crypto, fiat bool }{
+		"BTC":     {true, false},
+		"NEWCOIN": {true, false},
+		"eurt":    {true, false},
+		"XRP":     {false, true},
+		"SGD":     {false, true},
+		"LTC":     {false, false},
+		"USD":     {false, true},
+	} {
+		if IsCryptocurrency(curr) != expected.crypto || IsFiatCurrency(curr) != expected.fiat {
+			t.Errorf("Test failed. %s expected crypto %v fiat %v, got crypto %v fiat %v",
+				curr, expected.crypto, expected.fiat, IsCryptocurrency(curr), IsFiatCurrency(curr))
+		}
+	}
+
+	// Later updates keep the overrides
+	Update([]string{"XRP", "LTC", "ETH"}, true)
+	if IsCryptocurrency("XRP") || IsCryptocurrency("LTC") || !IsCryptocurrency("ETH") {
+		t.Errorf("Test failed. Update ignored the overrides, cryptocurrencies %v",
+			CryptoCurrencies)
+	}
+
+	// Overrides are replaced by the next custom currencies
+	SetCustomCurrencies(nil, nil, nil)
+	Update([]string{"LTC"}, true)
+	if !IsCryptocurrency("LTC") {
+		t.Error("Test failed. Removed currency not registered after the removal was cleared")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer resetCurrencyLists(CryptoCurrencies, FiatCurrencies)
+	resetCurrencyLists([]string{"BTC"}, []string{"USD"})
+
+	added := Register([]string{"btc", "usd", "NEWCOIN", "newcoin", ""}, true)
+	if len(added) != 1 || added[0] != "NEWCOIN" {
+		t.Errorf("Test failed. Expected only NEWCOIN to be registered, got %v", added)
+	}
+	if !IsCryptocurrency("NewCoin") || IsFiatCurrency("NEWCOIN") {
+		t.Error("Test failed. Registered currency not classified as a cryptocurrency")
+	}
+
+	// Replacing a list is picked up by the lookup
+	CryptoCurrencies = []string{"ETH"}
+	if IsCryptocurrency("BTC") || !IsCryptocurrency("ETH") {
+		t.Error("Test failed. Lookup not rebuilt after the list was replaced")
+	}
+}
@@ -21,25 +21,33 @@ type IFXProvider interface {
 
 // GetCurrencyData returns currency data from enabled FX providers
 func (fxp IFXProviders) GetCurrencyData(baseCurrency, symbols string) (map[string]float64, error) {
+	rates, _, err := fxp.GetCurrencyDataWithProvider(baseCurrency, symbols)
+	return rates, err
+}
+
+// GetCurrencyDataWithProvider returns currency data from enabled FX providers
+// and the name of the provider which supplied it. The primary provider is
+// used unless it fails
+func (fxp IFXProviders) GetCurrencyDataWithProvider(baseCurrency, symbols string) (map[string]float64, string, error) {
 	for x := range fxp {
 		if fxp[x].IsPrimaryProvider() && fxp[x].IsEnabled() {
 			rates, err := fxp[x].GetRates(baseCurrency, symbols)
 			if err != nil {
 				log.Error(err)
 				for y := range fxp {
-					if !fxp[y].IsPrimaryProvider() && fxp[x].IsEnabled() {
+					if !fxp[y].IsPrimaryProvider() && fxp[y].IsEnabled() {
 						rates, err = fxp[y].GetRates(baseCurrency, symbols)
 						if err != nil {
 							log.Error(err)
 							continue
 						}
-						return rates, nil
+						return rates, fxp[y].GetName(), nil
 					}
 				}
-				return nil, errors.New("ForexProvider error GetCurrencyData() failed to acquire data")
+				return nil, "", errors.New("ForexProvider error GetCurrencyData() failed to acquire data")
 			}
-			return rates, nil
+			return rates, fxp[x].GetName(), nil
 		}
 	}
-	return nil, errors.New("ForexProvider error GetCurrencyData() no providers enabled")
+	return nil, "", errors.New("ForexProvider error GetCurrencyData() no providers enabled")
 }
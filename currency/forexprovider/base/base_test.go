@@ -1 +1,57 @@
 package base
+
+import (
+	"errors"
+	"testing"
+)
+
+// mockProvider returns its rates or fails if err is set
+type mockProvider struct {
+	Base
+	rates map[string]float64
+	err   error
+}
+
+func (m *mockProvider) Setup(config Settings) {}
+
+func (m *mockProvider) GetRates(baseCurrency, symbols string) (map[string]float64, error) {
+	return m.rates, m.err
+}
+
+func TestGetCurrencyDataWithProvider(t *testing.T) {
+	primary := &mockProvider{
+		Base:  Base{Settings{Name: "Primary", Enabled: true, PrimaryProvider: true}},
+		rates: map[string]float64{"USDAUD": 1.25},
+	}
+	disabled := &mockProvider{
+		Base:  Base{Settings{Name: "Disabled"}},
+		rates: map[string]float64{"USDAUD": 1},
+	}
+	backup := &mockProvider{
+		Base:  Base{Settings{Name: "Backup", Enabled: true}},
+		rates: map[string]float64{"USDAUD": 1.3},
+	}
+	fxp := IFXProviders{primary, disabled, backup}
+
+	rates, provider, err := fxp.GetCurrencyDataWithProvider("USD", "AUD")
+	if err != nil || provider != "Primary" || rates["USDAUD"] != 1.25 {
+		t.Errorf("Test failed. Unexpected rates %v from %s, error: %v", rates, provider, err)
+	}
+
+	primary.err = errors.New("rate limited")
+	rates, provider, err = fxp.GetCurrencyDataWithProvider("USD", "AUD")
+	if err != nil || provider != "Backup" || rates["USDAUD"] != 1.3 {
+		t.Errorf("Test failed. Unexpected rates %v from %s, error: %v", rates, provider, err)
+	}
+
+	backup.err = errors.New("offline")
+	_, _, err = fxp.GetCurrencyDataWithProvider("USD", "AUD")
+	if err == nil {
+		t.Error("Test failed. Expected an error when every provider fails")
+	}
+
+	_, _, err = IFXProviders{}.GetCurrencyDataWithProvider("USD", "AUD")
+	if err == nil {
+		t.Error("Test failed. Expected an error without providers")
+	}
+}
@@ -26,13 +26,27 @@ func (c CurrencyItem) String() string {
 	return string(c)
 }
 
-// CurrencyPair holds currency pair information
+// CurrencyPair holds currency pair information. FirstCurrency is the base
+// currency of the market and SecondCurrency the quote currency, prefer the
+// Base and Quote accessors over the fields
 type CurrencyPair struct {
 	Delimiter      string       `json:"delimiter"`
 	FirstCurrency  CurrencyItem `json:"first_currency"`
 	SecondCurrency CurrencyItem `json:"second_currency"`
 }
 
+// Base returns the base currency of the pair, the currency being bought or
+// sold
+func (c CurrencyPair) Base() CurrencyItem {
+	return c.FirstCurrency
+}
+
+// Quote returns the quote currency of the pair, the currency prices and
+// costs are denominated in
+func (c CurrencyPair) Quote() CurrencyItem {
+	return c.SecondCurrency
+}
+
 // Pair returns a currency pair string
 func (c CurrencyPair) Pair() CurrencyItem {
 	return c.FirstCurrency + CurrencyItem(c.Delimiter) + c.SecondCurrency
@@ -108,6 +122,15 @@ func NewCurrencyPair(firstCurrency, secondCurrency string) CurrencyPair {
 	}
 }
 
+// NewCurrencyPairFromBaseQuote returns a CurrencyPair without a delimiter from
+// its base and quote currencies
+func NewCurrencyPairFromBaseQuote(base, quote string) CurrencyPair {
+	return CurrencyPair{
+		FirstCurrency:  CurrencyItem(base),
+		SecondCurrency: CurrencyItem(quote),
+	}
+}
+
 // NewCurrencyPairFromIndex returns a CurrencyPair via a currency string and
 // specific index
 func NewCurrencyPairFromIndex(currency, index string) CurrencyPair {
@@ -63,6 +63,26 @@ func TestSecondCurrency(t *testing.T) {
 	}
 }
 
+func TestBaseQuote(t *testing.T) {
+	t.Parallel()
+	pair := NewCurrencyPairFromBaseQuote("BTC", "USD")
+	if pair.Base() != "BTC" || pair.Quote() != "USD" {
+		t.Errorf("Test failed. Base() and Quote(): %s and %s, expected BTC and USD",
+			pair.Base(), pair.Quote())
+	}
+	if pair.FirstCurrency != pair.Base() || pair.SecondCurrency != pair.Quote() {
+		t.Error("Test failed. Base() and Quote() don't match the pair fields")
+	}
+	if !pair.Equal(NewCurrencyPair("BTC", "USD"), true) {
+		t.Error("Test failed. NewCurrencyPairFromBaseQuote() differs from NewCurrencyPair()")
+	}
+	swapped := pair.Swap()
+	if swapped.Base() != "USD" || swapped.Quote() != "BTC" {
+		t.Errorf("Test failed. Swap() base and quote: %s and %s",
+			swapped.Base(), swapped.Quote())
+	}
+}
+
 func TestPair(t *testing.T) {
 	t.Parallel()
 	pair := NewCurrencyPair("BTC", "USD")
@@ -13,6 +13,12 @@ var translations = map[pair.CurrencyItem]pair.CurrencyItem{
 	"USD":  "USDT",
 }
 
+// distinctAssets holds translation values which are relatable for pair
+// matching but are separate assets, so must not be merged into their key
+var distinctAssets = map[pair.CurrencyItem]bool{
+	"USDT": true,
+}
+
 // GetTranslation returns similar strings for a particular currency
 func GetTranslation(currency pair.CurrencyItem) (pair.CurrencyItem, error) {
 	for k, v := range translations {
@@ -32,3 +38,19 @@ func HasTranslation(currency pair.CurrencyItem) bool {
 	_, err := GetTranslation(currency)
 	return (err == nil)
 }
+
+// GetCanonicalCurrency returns the upper cased canonical name for a currency,
+// mapping known aliases to their common name (e.g xbt -> BTC)
+func GetCanonicalCurrency(currency pair.CurrencyItem) pair.CurrencyItem {
+	upper := currency.Upper()
+	if distinctAssets[upper] {
+		return upper
+	}
+
+	for k, v := range translations {
+		if v == upper {
+			return k
+		}
+	}
+	return upper
+}
@@ -59,3 +59,23 @@ func TestHasTranslation(t *testing.T) {
 		t.Error("HasTranslation: translation result was different to expected result")
 	}
 }
+
+func TestGetCanonicalCurrency(t *testing.T) {
+	tester := map[pair.CurrencyItem]pair.CurrencyItem{
+		"xbt":  "BTC",
+		"XBT":  "BTC",
+		"btc":  "BTC",
+		"XDG":  "DOGE",
+		"USDT": "USDT",
+		"usd":  "USD",
+		"NEO":  "NEO",
+	}
+
+	for input, expected := range tester {
+		actual := GetCanonicalCurrency(input)
+		if actual != expected {
+			t.Errorf("GetCanonicalCurrency: %s expected %s got %s",
+				input, expected, actual)
+		}
+	}
+}
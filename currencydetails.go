@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// ErrCurrencyDetailsNotFound is returned when an exchange has no details for
+// the requested currency
+var ErrCurrencyDetailsNotFound = errors.New("currency details not found")
+
+// GetCryptocurrencyInfo returns the deposit and withdrawal details of the
+// currencies on an exchange, or of a single currency if one is supplied
+func GetCryptocurrencyInfo(exchName, currency string) ([]exchange.CurrencyDetails, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+
+	details, err := exch.GetCurrencyDetails()
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		return details, nil
+	}
+
+	d, ok := exchange.GetCurrencyDetail(details, currency)
+	if !ok {
+		return nil, ErrCurrencyDetailsNotFound
+	}
+	return []exchange.CurrencyDetails{d}, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+func TestGetCryptocurrencyInfo(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	exch := newTestExchange("Details")
+	exch.getCurrencyDetails = func() ([]exchange.CurrencyDetails, error) {
+		return []exchange.CurrencyDetails{
+			{Currency: "BTC", WithdrawalFee: 0.0005},
+			{Currency: "ETH", WithdrawalSuspended: true},
+		}, nil
+	}
+	bot.exchanges = []exchange.IBotExchange{exch, newTestExchange("NoDetails")}
+
+	details, err := GetCryptocurrencyInfo("details", "")
+	if err != nil || len(details) != 2 {
+		t.Errorf("Test failed. Unexpected currency details %+v, error: %v", details, err)
+	}
+
+	details, err = GetCryptocurrencyInfo("details", "eth")
+	if err != nil || len(details) != 1 || !details[0].WithdrawalSuspended {
+		t.Errorf("Test failed. Unexpected currency details %+v, error: %v", details, err)
+	}
+
+	_, err = GetCryptocurrencyInfo("details", "LTC")
+	if err != ErrCurrencyDetailsNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrCurrencyDetailsNotFound, err)
+	}
+
+	_, err = GetCryptocurrencyInfo("nodetails", "")
+	if err != common.ErrFunctionNotSupported {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+
+	_, err = GetCryptocurrencyInfo("missing", "")
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrExchangeNotFound, err)
+	}
+}
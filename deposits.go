@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// ErrDepositCurrencyRequired is returned when deposit history is requested
+// without a currency
+var ErrDepositCurrencyRequired = errors.New("deposit currency required")
+
+var (
+	notifiedDeposits   = make(map[string]bool)
+	notifiedDepositMtx sync.Mutex
+)
+
+// GetExchangeDepositHistory returns the deposits of a currency on an exchange
+// made between start and end, a zero start or end leaves that side of the
+// range open
+func GetExchangeDepositHistory(exchName, currency string, start, end time.Time) ([]exchange.FundHistory, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	if currency == "" {
+		return nil, ErrDepositCurrencyRequired
+	}
+	return exch.GetDepositHistory(pair.CurrencyItem(currency).Upper(), start,
+		end)
+}
+
+// checkNewDeposits fetches the deposit history of the currencies whose balance
+// increased and returns the deposits made since the bot started which haven't
+// been returned before with their current status, so a pending deposit is
+// returned again once credited. Exchanges without deposit history are skipped
+func checkNewDeposits(changes []BalanceChange) []exchange.FundHistory {
+	notifiedDepositMtx.Lock()
+	defer notifiedDepositMtx.Unlock()
+
+	var deposits []exchange.FundHistory
+	checked := make(map[string]bool)
+	for x := range changes {
+		if changes[x].Change <= 0 {
+			continue
+		}
+		exchName, _ := SplitExchangeAccountName(changes[x].Exchange)
+		key := exchName + changes[x].Currency
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		history, err := GetExchangeDepositHistory(exchName, changes[x].Currency,
+			bot.startTime, time.Time{})
+		if err != nil {
+			if err != common.ErrFunctionNotSupported {
+				routinesLog.Warnf("Unable to fetch %s %s deposit history: %s",
+					exchName, changes[x].Currency, err)
+			}
+			continue
+		}
+
+		for y := range history {
+			id := depositID(history[y]) + "/" + history[y].Status
+			if notifiedDeposits[id] {
+				continue
+			}
+			notifiedDeposits[id] = true
+			deposits = append(deposits, history[y])
+		}
+	}
+	return deposits
+}
+
+// depositID returns a key identifying a deposit, the transaction ID if the
+// exchange supplies one
+func depositID(deposit exchange.FundHistory) string {
+	id := deposit.CryptoTxID
+	if id == "" {
+		id = strconv.FormatInt(deposit.Timestamp, 10) + "/" +
+			strconv.FormatFloat(deposit.Amount, 'f', -1, 64)
+	}
+	return deposit.ExchangeName + "/" + deposit.Currency + "/" + id
+}
+
+// notifyNewDeposits pushes new deposits to the enabled communication mediums
+func notifyNewDeposits(deposits []exchange.FundHistory) {
+	for x := range deposits {
+		details := fmt.Sprintf("%s %s deposit of %f %s",
+			deposits[x].ExchangeName, deposits[x].Currency, deposits[x].Amount,
+			deposits[x].Status)
+		routinesLog.Debugf("%s.", details)
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "deposit",
+			TradeDetails: details,
+		})
+	}
+}
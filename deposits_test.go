@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// newDepositExchange returns a test exchange serving its deposits of the
+// requested currency made in the requested time range
+func newDepositExchange(name string, deposits []exchange.FundHistory) *testExchange {
+	exch := newTestExchange(name)
+	exch.getDepositHistory = func(c pair.CurrencyItem, start, end time.Time) ([]exchange.FundHistory, error) {
+		var resp []exchange.FundHistory
+		for x := range deposits {
+			if deposits[x].Currency == c.String() &&
+				exchange.InTimeRange(deposits[x].Timestamp, start, end) {
+				resp = append(resp, deposits[x])
+			}
+		}
+		return resp, nil
+	}
+	return exch
+}
+
+func TestGetExchangeDepositHistory(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	bot.exchanges = []exchange.IBotExchange{
+		newDepositExchange("Deposits", []exchange.FundHistory{
+			{ExchangeName: "Deposits", Currency: "BTC", Amount: 1, Timestamp: 100},
+			{ExchangeName: "Deposits", Currency: "BTC", Amount: 2, Timestamp: 200},
+			{ExchangeName: "Deposits", Currency: "ETH", Amount: 3, Timestamp: 200},
+		}),
+		newTestExchange("NoDeposits"),
+	}
+
+	deposits, err := GetExchangeDepositHistory("deposits", "btc", time.Unix(150, 0),
+		time.Time{})
+	if err != nil || len(deposits) != 1 || deposits[0].Amount != 2 {
+		t.Errorf("Test failed. Unexpected deposits %+v, error: %v", deposits, err)
+	}
+
+	_, err = GetExchangeDepositHistory("deposits", "", time.Time{}, time.Time{})
+	if err != ErrDepositCurrencyRequired {
+		t.Errorf("Test failed. Expected %v, got %v", ErrDepositCurrencyRequired, err)
+	}
+
+	_, err = GetExchangeDepositHistory("nodeposits", "BTC", time.Time{}, time.Time{})
+	if err != common.ErrFunctionNotSupported {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+
+	_, err = GetExchangeDepositHistory("missing", "BTC", time.Time{}, time.Time{})
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrExchangeNotFound, err)
+	}
+}
+
+func TestRESTGetExchangeDepositHistory(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	bot.exchanges = []exchange.IBotExchange{
+		newDepositExchange("Deposits", []exchange.FundHistory{
+			{ExchangeName: "Deposits", Currency: "BTC", Amount: 1, Timestamp: 100},
+		}),
+	}
+
+	router := NewRouter()
+	send := func(auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/exchanges/Deposits/deposits?currency=BTC", nil)
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := send(false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w := send(true)
+	var result []exchange.FundHistory
+	err := common.JSONDecode(w.Body.Bytes(), &result)
+	if err != nil || len(result) != 1 || result[0].Amount != 1 {
+		t.Errorf("Test failed. Unexpected deposits %+v, error: %v", result, err)
+	}
+}
+
+func TestCheckNewDeposits(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange, c *communications.Communications, start time.Time) {
+		bot.exchanges, bot.comms, bot.startTime = exchanges, c, start
+		notifiedDepositMtx.Lock()
+		notifiedDeposits = make(map[string]bool)
+		notifiedDepositMtx.Unlock()
+	}(bot.exchanges, bot.comms, bot.startTime)
+
+	comm := &mockComm{Base: base.Base{Name: "mock", Enabled: true, Connected: true}}
+	bot.comms = &communications.Communications{IComm: base.IComm{comm}}
+	bot.startTime = time.Unix(1000, 0)
+
+	deposits := []exchange.FundHistory{
+		{ExchangeName: "Deposits", Currency: "BTC", Amount: 5, Timestamp: 500,
+			Status: exchange.DepositCredited, CryptoTxID: "old"},
+		{ExchangeName: "Deposits", Currency: "BTC", Amount: 1, Timestamp: 1500,
+			Status: exchange.DepositPending, CryptoTxID: "new"},
+	}
+	exch := newDepositExchange("Deposits", deposits)
+	bot.exchanges = []exchange.IBotExchange{exch, newTestExchange("NoDeposits")}
+
+	changes := []BalanceChange{
+		{Exchange: "Deposits", Currency: "BTC", Change: 1},
+		{Exchange: FormatExchangeAccountName("Deposits", "sub"), Currency: "ETH", Change: -1},
+		{Exchange: "NoDeposits", Currency: "BTC", Change: 1},
+	}
+	result := checkNewDeposits(changes)
+	if len(result) != 1 || result[0].CryptoTxID != "new" {
+		t.Fatalf("Test failed. Expected the deposit since startup, got %+v", result)
+	}
+	if n := exch.callCount("GetDepositHistory"); n != 1 {
+		t.Errorf("Test failed. Expected deposits to be fetched once for a balance increase, got %d",
+			n)
+	}
+
+	notifyNewDeposits(result)
+	if events := comm.pushed(); len(events) != 1 || events[0].Type != "deposit" {
+		t.Errorf("Test failed. Unexpected events %+v", events)
+	}
+
+	result = checkNewDeposits(changes)
+	if len(result) != 0 {
+		t.Errorf("Test failed. Expected a notified deposit to be skipped, got %+v", result)
+	}
+
+	deposits[1].Status = exchange.DepositCredited
+	result = checkNewDeposits(changes)
+	if len(result) != 1 || result[0].Status != exchange.DepositCredited {
+		t.Errorf("Test failed. Expected the credited deposit to be returned, got %+v", result)
+	}
+}
@@ -1,5 +1,20 @@
 package events
 
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
+)
+
 //
 // import (
 // 	"testing"
@@ -367,3 +382,549 @@ package events
 // 		t.Error("Test Failed. IsValidItem: Error, incorrect return")
 // 	}
 // }
+
+func TestEventsPersistence(t *testing.T) {
+	err := config.GetConfig().LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config %s", err)
+	}
+
+	dir, err := ioutil.TempDir("", "gct-events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.json")
+
+	err = LoadEvents(path)
+	if err != nil || len(Events) != 0 {
+		t.Fatalf("Test failed. LoadEvents: Unexpected result with missing file %v", err)
+	}
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	_, err = AddEvent("ANX", "price", ">,100", ConditionParams{}, p, "SPOT", actionTest)
+	if err != nil {
+		t.Fatalf("Test failed. AddEvent: %s", err)
+	}
+	id, err := AddEvent("ANX", "price", "<,50", ConditionParams{}, p, "SPOT", actionTest)
+	if err != nil {
+		t.Fatalf("Test failed. AddEvent: %s", err)
+	}
+
+	triggered := time.Now().Truncate(time.Second)
+	Events[0].Executed = true
+	Events[0].LastTriggered = triggered
+	err = SaveEvents()
+	if err != nil {
+		t.Fatalf("Test failed. SaveEvents: %s", err)
+	}
+
+	err = LoadEvents(path)
+	if err != nil {
+		t.Fatalf("Test failed. LoadEvents: %s", err)
+	}
+	if len(Events) != 2 {
+		t.Fatalf("Test failed. LoadEvents: Unexpected events length %d", len(Events))
+	}
+	if !Events[0].Executed || !Events[0].LastTriggered.Equal(triggered) {
+		t.Error("Test failed. LoadEvents: Triggered state not restored")
+	}
+	if Events[1].Executed || Events[1].Inactive || Events[1].Condition != "<,50" {
+		t.Error("Test failed. LoadEvents: Unexpected event restored")
+	}
+
+	if !RemoveEvent(id) {
+		t.Fatal("Test failed. RemoveEvent: Failed to remove event")
+	}
+	err = LoadEvents(path)
+	if err != nil || len(Events) != 1 {
+		t.Fatalf("Test failed. LoadEvents: Removal not persisted %v", err)
+	}
+
+	Events = append(Events, &Event{
+		ID:        5,
+		Exchange:  "FAKEEXCHANGE",
+		Item:      itemPrice,
+		Condition: ">,1",
+		Pair:      p,
+		Asset:     "SPOT",
+		Action:    actionTest,
+	})
+	err = SaveEvents()
+	if err != nil {
+		t.Fatalf("Test failed. SaveEvents: %s", err)
+	}
+
+	err = LoadEvents(path)
+	if err != nil || len(Events) != 2 {
+		t.Fatalf("Test failed. LoadEvents: Unexpected result %v", err)
+	}
+	if Events[0].Inactive || !Events[1].Inactive {
+		t.Error("Test failed. LoadEvents: Disabled exchange event not flagged inactive")
+	}
+
+	id, err = AddEvent("ANX", "price", ">,200", ConditionParams{}, p, "SPOT", actionTest)
+	if err != nil || id != 6 {
+		t.Errorf("Test failed. AddEvent: Expected ID 6 after loaded events, got %d %v", id, err)
+	}
+
+	corrupt := []byte(`[{"ID":1,"Exchange":`)
+	err = common.WriteFile(path, corrupt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadEvents(path)
+	if err == nil || len(Events) != 0 {
+		t.Error("Test failed. LoadEvents: Corrupt file not handled")
+	}
+	data, err := common.ReadFile(path + ".corrupt")
+	if err != nil || string(data) != string(corrupt) {
+		t.Errorf("Test failed. LoadEvents: Corrupt file not moved aside %v", err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Test failed. LoadEvents: Corrupt file left in place")
+	}
+
+	eventsFile = ""
+	Events = nil
+}
+
+func TestProcessTicker(t *testing.T) {
+	err := config.GetConfig().LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config %s", err)
+	}
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemPrice, Condition: ">,100", Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 2, Exchange: "ANX", Item: itemPrice, Condition: ">,100", Pair: p, Asset: "FUTURES", Action: actionTest},
+		{ID: 3, Exchange: "Bitfinex", Item: itemPrice, Condition: ">,100", Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 4, Exchange: "ANX", Item: itemPrice, Condition: "<,100", Pair: p, Asset: "SPOT", Action: actionTest},
+	}
+
+	ProcessTicker("anx", "SPOT", pair.NewCurrencyPair("LTC", "USD"), ticker.Price{Last: 150})
+	if Events[0].Executed {
+		t.Error("Test failed. ProcessTicker: Event triggered for a different pair")
+	}
+
+	ProcessTicker("anx", "SPOT", p, ticker.Price{Last: 150})
+	if !Events[0].Executed || Events[0].LastTriggered.IsZero() {
+		t.Error("Test failed. ProcessTicker: Matching event not triggered")
+	}
+	if Events[1].Executed || Events[2].Executed {
+		t.Error("Test failed. ProcessTicker: Event triggered for a different asset or exchange")
+	}
+	if Events[3].Executed {
+		t.Error("Test failed. ProcessTicker: Event triggered with unmet condition")
+	}
+
+	SetExchangeMaintenance("Bitfinex", true)
+	if !IsExchangeInMaintenance("BITFINEX") {
+		t.Error("Test failed. SetExchangeMaintenance: Exchange not in maintenance")
+	}
+	ProcessTicker("Bitfinex", "SPOT", p, ticker.Price{Last: 150})
+	if Events[2].Executed {
+		t.Error("Test failed. ProcessTicker: Event triggered during exchange maintenance")
+	}
+
+	SetExchangeMaintenance("Bitfinex", false)
+	ProcessTicker("Bitfinex", "SPOT", p, ticker.Price{Last: 150})
+	if !Events[2].Executed {
+		t.Error("Test failed. ProcessTicker: Event not triggered after exchange maintenance")
+	}
+
+	Events = nil
+}
+
+func TestSetExchangeMaintenanceConcurrentUpdates(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	Events = nil
+	for x := 0; x < 50; x++ {
+		Events = append(Events, &Event{ID: x, Exchange: "Bitfinex", Item: itemPrice,
+			Condition: ">,100", Pair: p, Asset: "SPOT", Action: actionTest})
+	}
+	defer func() { Events = nil }()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for x := 0; x < 50; x++ {
+			RemoveEvent(x)
+		}
+	}()
+	for x := 0; x < 50; x++ {
+		SetExchangeMaintenance("Bitfinex", x%2 == 0)
+	}
+	<-done
+	SetExchangeMaintenance("Bitfinex", false)
+
+	if len(GetEvents()) != 0 || IsExchangeInMaintenance("Bitfinex") {
+		t.Error("Test failed. SetExchangeMaintenance: Unexpected events or maintenance state")
+	}
+}
+
+func TestProcessTickerRateLimit(t *testing.T) {
+	now := time.Date(2018, 10, 6, 12, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+	SetMinEvaluationInterval(time.Second)
+	defer SetMinEvaluationInterval(0)
+
+	p := pair.NewCurrencyPair("RATE", "USD")
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemPrice, Condition: ">,100", Pair: p, Asset: "SPOT", Action: actionTest},
+	}
+	defer func() { Events = nil }()
+
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 90, LastUpdated: now})
+	if Events[0].Executed || !Events[0].LastEvaluated.Equal(now) || !Events[0].LastData.Equal(now) {
+		t.Errorf("Test failed. ProcessTicker: Unexpected evaluation %+v", Events[0])
+	}
+
+	// Updates within the minimum evaluation interval only record their time
+	evaluated := now
+	now = now.Add(time.Millisecond * 500)
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 110, LastUpdated: now})
+	if Events[0].Executed || !Events[0].LastEvaluated.Equal(evaluated) ||
+		!Events[0].LastData.Equal(now) {
+		t.Errorf("Test failed. ProcessTicker: Rate limited update evaluated %+v", Events[0])
+	}
+
+	now = now.Add(time.Second)
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 110, LastUpdated: now})
+	if !Events[0].Executed || !Events[0].LastEvaluated.Equal(now) {
+		t.Errorf("Test failed. ProcessTicker: Update after the interval not evaluated %+v", Events[0])
+	}
+
+	if e := GetEvents(); len(e) != 1 || e[0].LastEvaluated != Events[0].LastEvaluated {
+		t.Errorf("Test failed. GetEvents: Unexpected events %+v", e)
+	}
+}
+
+func TestCheckEvents(t *testing.T) {
+	SetMinEvaluationInterval(time.Hour)
+	defer SetMinEvaluationInterval(0)
+
+	p := pair.NewCurrencyPair("CHECK", "USD")
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemPrice, Condition: ">,100", Pair: p, Asset: "SPOT", Action: actionTest},
+	}
+	defer func() { Events = nil }()
+
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 90})
+	ticker.ProcessTicker("ANX", p, ticker.Price{Last: 110}, "SPOT")
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 110})
+	if Events[0].Executed {
+		t.Fatal("Test failed. ProcessTicker: Rate limited update evaluated")
+	}
+
+	// The periodic check picks up the stored ticker skipped by the rate limit
+	CheckEvents()
+	if !Events[0].Executed || Events[0].LastData.IsZero() {
+		t.Errorf("Test failed. CheckEvents: Event not triggered from the stored ticker %+v", Events[0])
+	}
+}
+
+func TestConcurrentEventUpdates(t *testing.T) {
+	err := config.GetConfig().LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config %s", err)
+	}
+	dir, err := ioutil.TempDir("", "gct-events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = LoadEvents(filepath.Join(dir, "events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		eventsFile = ""
+		Events = nil
+	}()
+
+	// Evaluate events as the check loop and ticker hook do while events are
+	// added and removed, run with -race to detect unguarded access
+	p := pair.NewCurrencyPair("RACE", "USD")
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			CheckEvents()
+			ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 50})
+		}
+	}()
+
+	for x := 0; x < 50; x++ {
+		id, err := AddEvent("ANX", "price", ">,100", ConditionParams{}, p, "SPOT", actionTest)
+		if err != nil {
+			t.Fatalf("Test failed. AddEvent: %s", err)
+		}
+		GetEventCounter()
+		if x%2 == 0 && !RemoveEvent(id) {
+			t.Errorf("Test failed. RemoveEvent: Event %d not removed", id)
+		}
+		err = SaveEvents()
+		if err != nil {
+			t.Fatalf("Test failed. SaveEvents: %s", err)
+		}
+	}
+	close(done)
+	<-stopped
+
+	total, executed := GetEventCounter()
+	if total != 25 || executed != 0 {
+		t.Errorf("Test failed. Expected 25 pending events, got %d with %d executed",
+			total, executed)
+	}
+}
+
+func TestProcessVolume(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemVolume, Condition: ">,1000", Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 2, Exchange: "ANX", Item: itemVolume, Condition: "<,500", Pair: p, Asset: "SPOT", Action: actionTest},
+	}
+
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 100, Volume: 800})
+	if Events[0].Executed || Events[1].Executed {
+		t.Error("Test failed. ProcessTicker: Volume event triggered before crossing its threshold")
+	}
+
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 100, Volume: 1200})
+	if !Events[0].Executed || Events[1].Executed {
+		t.Error("Test failed. ProcessTicker: Volume rising above the threshold not handled")
+	}
+
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 100, Volume: 400})
+	if !Events[1].Executed {
+		t.Error("Test failed. ProcessTicker: Volume falling below the threshold not handled")
+	}
+
+	Events = nil
+}
+
+func TestProcessOrderbook(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemDepth, Condition: "<,10", Params: ConditionParams{Side: sideBid, Percent: 0.5},
+			Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 2, Exchange: "ANX", Item: itemDepth, Condition: ">=,20", Params: ConditionParams{Side: sideAsk, Percent: 0.5},
+			Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 3, Exchange: "ANX", Item: itemPrice, Condition: ">,1", Pair: p, Asset: "SPOT", Action: actionTest},
+	}
+
+	book := func(bidAmount, askAmount float64) orderbook.Base {
+		return orderbook.Base{
+			Bids: []orderbook.Item{{Price: 99.8, Amount: bidAmount}, {Price: 90, Amount: 100}},
+			Asks: []orderbook.Item{{Price: 100.2, Amount: askAmount}, {Price: 110, Amount: 100}},
+		}
+	}
+
+	ProcessOrderbook("ANX", "SPOT", p, book(15, 5))
+	if Events[0].Executed || Events[1].Executed || Events[2].Executed {
+		t.Error("Test failed. ProcessOrderbook: Event triggered before crossing its threshold")
+	}
+
+	ProcessOrderbook("ANX", "SPOT", p, book(8, 5))
+	if !Events[0].Executed || Events[1].Executed {
+		t.Error("Test failed. ProcessOrderbook: Bid depth falling below the threshold not handled")
+	}
+
+	ProcessOrderbook("ANX", "SPOT", p, book(8, 25))
+	if !Events[1].Executed {
+		t.Error("Test failed. ProcessOrderbook: Ask depth rising above the threshold not handled")
+	}
+	if Events[2].Executed {
+		t.Error("Test failed. ProcessOrderbook: Price event triggered by an orderbook update")
+	}
+
+	Events = nil
+}
+
+func TestAddTradeEvent(t *testing.T) {
+	err := config.GetConfig().LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config %s", err)
+	}
+	defer func() { Events = nil }()
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	_, err = AddEvent("ANX", "trade_rate", ">,10", ConditionParams{Side: sideBid, Window: "5m"},
+		p, "SPOT", actionTest)
+	if err != nil {
+		t.Fatalf("Test failed. AddEvent: %s", err)
+	}
+	_, err = AddEvent("ANX", "trade_imbalance", "<,0", ConditionParams{}, p, "SPOT", actionTest)
+	if err != nil {
+		t.Fatalf("Test failed. AddEvent: %s", err)
+	}
+	if len(Events) != 2 || Events[0].Params != (ConditionParams{Window: "5m"}) ||
+		Events[1].Params.Window != defaultTradeWindow {
+		t.Errorf("Test failed. AddEvent: Unexpected trade event params %+v %+v",
+			Events[0].Params, Events[1].Params)
+	}
+
+	for _, window := range []string{"soon", "-1m", "0s"} {
+		_, err = AddEvent("ANX", "trade_rate", ">,10", ConditionParams{Window: window}, p,
+			"SPOT", actionTest)
+		if err != errInvalidWindow {
+			t.Errorf("Test failed. AddEvent: Window %s expected %v, got %v", window,
+				errInvalidWindow, err)
+		}
+	}
+}
+
+func TestProcessTrade(t *testing.T) {
+	defer trades.SetBufferSize(0)
+	now := time.Date(2018, 10, 6, 12, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+
+	p := pair.NewCurrencyPair("LTC", "USD")
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemTradeRate, Condition: ">=,3",
+			Params: ConditionParams{Window: "1m"}, Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 2, Exchange: "ANX", Item: itemTradeImbalance, Condition: "<,-0.5",
+			Params: ConditionParams{Window: "5m"}, Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 3, Exchange: "ANX", Item: itemPrice, Condition: ">,1", Pair: p, Asset: "SPOT",
+			Action: actionTest},
+	}
+	defer func() { Events = nil }()
+
+	add := func(age time.Duration, amount float64, side string) {
+		trade := trades.Trade{Timestamp: now.Add(-age), Price: 80, Amount: amount, Side: side}
+		trades.Add("ANX", p, "SPOT", trade)
+		ProcessTrade("ANX", "SPOT", p, trade)
+	}
+
+	add(time.Minute*2, 4, "sell")
+	if Events[0].Executed || !Events[1].Executed {
+		t.Error("Test failed. ProcessTrade: Sell imbalance below the threshold not handled")
+	}
+
+	add(time.Second*30, 1, "buy")
+	add(time.Second*20, 1, "buy")
+	if Events[0].Executed {
+		t.Error("Test failed. ProcessTrade: Trade rate event triggered before crossing its threshold")
+	}
+	add(time.Second*10, 1, "buy")
+	if !Events[0].Executed {
+		t.Error("Test failed. ProcessTrade: Trade rate rising above the threshold not handled")
+	}
+	if Events[2].Executed {
+		t.Error("Test failed. ProcessTrade: Price event triggered by a trade")
+	}
+}
+
+func TestCheckConditionWithoutData(t *testing.T) {
+	p := pair.NewCurrencyPair("NODATA", "USD")
+	events := []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemVolume, Condition: "<,10", Pair: p, Asset: "SPOT", Action: actionTest},
+		{ID: 2, Exchange: "ANX", Item: itemDepth, Condition: "<,10", Params: ConditionParams{Side: sideBid, Percent: 1},
+			Pair: p, Asset: "SPOT", Action: actionTest},
+	}
+	for x := range events {
+		if events[x].CheckCondition() {
+			t.Errorf("Test failed. CheckCondition: Event %d triggered without market data", events[x].ID)
+		}
+	}
+
+	ticker.ProcessTicker("ANX", p, ticker.Price{Last: 1, Volume: 5}, "SPOT")
+	if !events[0].CheckCondition() {
+		t.Error("Test failed. CheckCondition: Volume event not triggered from the stored ticker")
+	}
+}
+
+func TestIsValidConditionParams(t *testing.T) {
+	tests := []struct {
+		params   ConditionParams
+		expected bool
+	}{
+		{ConditionParams{Side: "bid", Percent: 0.5}, true},
+		{ConditionParams{Side: sideAsk, Percent: 99}, true},
+		{ConditionParams{Side: "mid", Percent: 0.5}, false},
+		{ConditionParams{Side: sideBid}, false},
+		{ConditionParams{Side: sideBid, Percent: 100}, false},
+	}
+	for x := range tests {
+		if IsValidConditionParams(tests[x].params) != tests[x].expected {
+			t.Errorf("Test failed. IsValidConditionParams: Case %d expected %v", x, tests[x].expected)
+		}
+	}
+}
+
+func TestOrderAction(t *testing.T) {
+	err := config.GetConfig().LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config %s", err)
+	}
+
+	for action, valid := range map[string]bool{
+		"ORDER,BUY,0.5":  true,
+		"order,sell,2":   true,
+		"ORDER,BUY":      false,
+		"ORDER,HOLD,1":   false,
+		"ORDER,SELL,0":   false,
+		"ORDER,SELL,abc": false,
+	} {
+		err = IsValidEvent("ANX", itemPrice, ">,100", action)
+		if (err == nil) != valid {
+			t.Errorf("Test failed. IsValidEvent: %s expected valid %v, got %v", action,
+				valid, err)
+		}
+	}
+
+	simulated := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return simulated })
+	defer SetClock(nil)
+
+	type order struct {
+		id     int
+		side   string
+		amount float64
+	}
+	p := pair.NewCurrencyPair("BTC", "USD")
+	_, err = AddEvent("ANX", itemPrice, ">,100", ConditionParams{}, p, "SPOT", "order,buy,1")
+	if err != errOrderUnsupported {
+		t.Errorf("Test failed. AddEvent: ORDER action accepted without an order handler, got %v", err)
+	}
+
+	var orders []order
+	SetOrderHandler(func(e *Event, side string, amount float64) error {
+		orders = append(orders, order{e.ID, side, amount})
+		return nil
+	})
+	defer SetOrderHandler(nil)
+
+	_, err = AddEvent("ANX", itemPrice, ">,100", ConditionParams{}, p, "SPOT", "ORDER,BUY,1")
+	if err != nil {
+		t.Errorf("Test failed. AddEvent: ORDER action rejected with an order handler, %s", err)
+	}
+	Events = []*Event{
+		{ID: 1, Exchange: "ANX", Item: itemPrice, Condition: ">,100", Pair: p, Asset: "SPOT", Action: "ORDER,SELL,0.5"},
+		{ID: 2, Exchange: "ANX", Item: itemPrice, Condition: "<,100", Pair: p, Asset: "SPOT", Action: "ORDER,BUY,1"},
+	}
+	defer func() { Events = nil }()
+
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 150})
+	ProcessTicker("ANX", "SPOT", p, ticker.Price{Last: 160})
+	if len(orders) != 1 || orders[0] != (order{1, orderSideSell, 0.5}) {
+		t.Errorf("Test failed. ExecuteAction: Unexpected orders %+v", orders)
+	}
+	if !Events[0].LastTriggered.Equal(simulated) {
+		t.Errorf("Test failed. ExecuteAction: Expected trigger time %v, got %v", simulated,
+			Events[0].LastTriggered)
+	}
+	if Events[1].Executed {
+		t.Error("Test failed. ExecuteAction: Order placed with unmet condition")
+	}
+}
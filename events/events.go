@@ -1,21 +1,33 @@
 package events
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/communications"
 	"github.com/thrasher-/gocryptotrader/communications/base"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
 	log "github.com/thrasher-/gocryptotrader/logger"
 )
 
 const (
 	itemPrice          = "PRICE"
+	itemVolume         = "VOLUME"
+	itemDepth          = "DEPTH"
+	itemTradeRate      = "TRADE_RATE"
+	itemTradeImbalance = "TRADE_IMBALANCE"
+	sideBid            = "BID"
+	sideAsk            = "ASK"
 	greaterThan        = ">"
 	greaterThanOrEqual = ">="
 	lessThan           = "<"
@@ -24,34 +36,114 @@ const (
 	actionSMSNotify    = "SMS"
 	actionConsolePrint = "CONSOLE_PRINT"
 	actionTest         = "ACTION_TEST"
+	actionOrder        = "ORDER"
+	orderSideBuy       = "BUY"
+	orderSideSell      = "SELL"
+
+	// defaultTradeWindow is the window trade activity is measured over when a
+	// TRADE_RATE or TRADE_IMBALANCE event doesn't set one
+	defaultTradeWindow = "1m"
 )
 
 var (
 	errInvalidItem      = errors.New("invalid item")
 	errInvalidCondition = errors.New("invalid conditional option")
 	errInvalidAction    = errors.New("invalid action")
+	errInvalidParams    = errors.New("invalid depth condition parameters")
+	errInvalidWindow    = errors.New("invalid trade activity window")
 	errExchangeDisabled = errors.New("desired exchange is disabled")
+	errOrderUnsupported = errors.New("ORDER actions are only supported in backtest mode")
 
 	// NOTE comms is an interim implementation
 	comms *communications.Communications
+
+	eventsFile    string
+	eventsFileMtx sync.Mutex
+
+	// maintenance holds the upper case names of exchanges in maintenance
+	maintenance    = make(map[string]bool)
+	maintenanceMtx sync.RWMutex
+
+	// orderHandler places the orders of ORDER actions, clock returns the
+	// time events are triggered at
+	orderHandler OrderHandler
+	clock        = time.Now
+
+	// minEvaluationInterval is the minimum time between evaluations of an
+	// event triggered by market data updates, zero evaluates every update.
+	// evaluationMtx guards Events and serialises evaluations so an action
+	// can't execute twice
+	minEvaluationInterval time.Duration
+	evaluationMtx         sync.Mutex
 )
 
+// OrderHandler places the market order of an ORDER event action, side is BUY
+// or SELL and amount is in the first currency of the event pair
+type OrderHandler func(e *Event, side string, amount float64) error
+
+// ConditionParams holds the extra parameters of a condition. DEPTH conditions
+// use the orderbook side to measure and how far from the mid price in percent
+// orders are counted, TRADE_RATE and TRADE_IMBALANCE conditions use the
+// duration trade activity is measured over, such as 5m
+type ConditionParams struct {
+	Side    string  `json:",omitempty"`
+	Percent float64 `json:",omitempty"`
+	Window  string  `json:",omitempty"`
+}
+
 // Event struct holds the event variables
 type Event struct {
 	ID        int
 	Exchange  string
 	Item      string
 	Condition string
+	Params    ConditionParams
 	Pair      pair.CurrencyPair
 	Asset     string
 	Action    string
 	Executed  bool
+
+	// LastTriggered is when the event last triggered, Inactive is set for
+	// loaded events whose exchange is no longer enabled
+	LastTriggered time.Time
+	Inactive      bool
+
+	// LastEvaluated is when the event condition was last checked and LastData
+	// when the market data it was last offered was updated
+	LastEvaluated time.Time
+	LastData      time.Time
 }
 
 // Events variable is a pointer array to the event structures that will be
 // appended
 var Events []*Event
 
+// SetOrderHandler sets the handler placing the orders of ORDER event actions,
+// nil removes it and ORDER actions are only logged
+func SetOrderHandler(handler OrderHandler) {
+	orderHandler = handler
+}
+
+// SetClock sets the function returning the time events are triggered at, so
+// events can be evaluated in simulated time. Nil restores the wall clock
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock = now
+}
+
+// SetMinEvaluationInterval sets the minimum time between evaluations of an
+// event triggered by market data updates, so busy websocket feeds don't
+// evaluate an event hundreds of times per second. Updates arriving within the
+// interval only record their time and are picked up by the next CheckEvents
+// pass. Zero evaluates every update
+func SetMinEvaluationInterval(d time.Duration) {
+	evaluationMtx.Lock()
+	minEvaluationInterval = d
+	evaluationMtx.Unlock()
+}
+
 // SetComms is an interim function that will support a median integration. This
 // sets the current comms package.
 func SetComms(commsP *communications.Communications) {
@@ -59,46 +151,105 @@ func SetComms(commsP *communications.Communications) {
 }
 
 // AddEvent adds an event to the Events chain and returns an index/eventID
-// and an error
-func AddEvent(Exchange, Item, Condition string, CurrencyPair pair.CurrencyPair, Asset, Action string) (int, error) {
+// and an error. Params are only used by DEPTH and trade activity events.
+// ORDER actions are rejected unless an order handler is set, which is only
+// the case in backtest mode
+func AddEvent(Exchange, Item, Condition string, Params ConditionParams, CurrencyPair pair.CurrencyPair, Asset, Action string) (int, error) {
 	err := IsValidEvent(Exchange, Item, Condition, Action)
 	if err != nil {
 		return 0, err
 	}
+	if isOrderAction(Action) && orderHandler == nil {
+		return 0, errOrderUnsupported
+	}
 
-	Event := &Event{}
-
-	if len(Events) == 0 {
-		Event.ID = 0
-	} else {
-		Event.ID = len(Events) + 1
+	Item = common.StringToUpper(Item)
+	Params.Side = common.StringToUpper(Params.Side)
+	if Item == itemDepth && !IsValidConditionParams(Params) {
+		return 0, errInvalidParams
 	}
+	if isTradeItem(Item) {
+		if Params.Window == "" {
+			Params.Window = defaultTradeWindow
+		}
+		if _, err = parseTradeWindow(Params.Window); err != nil {
+			return 0, err
+		}
+	}
+
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
+	Event := &Event{}
+	Event.ID = nextEventID()
 
 	Event.Exchange = Exchange
 	Event.Item = Item
 	Event.Condition = Condition
+	switch {
+	case Item == itemDepth:
+		Event.Params = ConditionParams{Side: Params.Side, Percent: Params.Percent}
+	case isTradeItem(Item):
+		Event.Params = ConditionParams{Window: Params.Window}
+	}
 	Event.Pair = CurrencyPair
 	Event.Asset = Asset
 	Event.Action = Action
 	Event.Executed = false
 	Events = append(Events, Event)
+	persistEvents()
 	return Event.ID, nil
 }
 
+// nextEventID returns the ID of a new event, one greater than the highest
+// event ID so loaded and remaining events keep unique IDs
+func nextEventID() int {
+	if len(Events) == 0 {
+		return 0
+	}
+	id := Events[0].ID
+	for _, x := range Events[1:] {
+		if x.ID > id {
+			id = x.ID
+		}
+	}
+	return id + 1
+}
+
 // RemoveEvent deletes and event by its ID
 func RemoveEvent(EventID int) bool {
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
 	for i, x := range Events {
 		if x.ID == EventID {
 			Events = append(Events[:i], Events[i+1:]...)
+			persistEvents()
 			return true
 		}
 	}
 	return false
 }
 
+// GetEvents returns a copy of the events with their evaluation times
+func GetEvents() []Event {
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
+	return copyEvents()
+}
+
+// copyEvents returns a copy of the events, the caller must hold evaluationMtx
+func copyEvents() []Event {
+	result := make([]Event, 0, len(Events))
+	for _, e := range Events {
+		result = append(result, *e)
+	}
+	return result
+}
+
 // GetEventCounter displays the emount of total events on the chain and the
 // events that have been executed.
 func GetEventCounter() (int, int) {
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
 	total := len(Events)
 	executed := 0
 
@@ -114,6 +265,10 @@ func GetEventCounter() (int, int) {
 func (e *Event) ExecuteAction() bool {
 	if common.StringContains(e.Action, ",") {
 		action := common.SplitStrings(e.Action, ",")
+		if common.StringToUpper(action[0]) == actionOrder {
+			e.placeOrder(action)
+			return true
+		}
 		if action[0] == actionSMSNotify {
 			message := fmt.Sprintf("Event triggered: %s", e.String())
 			if action[1] == "ALL" {
@@ -126,60 +281,233 @@ func (e *Event) ExecuteAction() bool {
 	return true
 }
 
+// placeOrder places the order of an ORDER action through the order handler.
+// Failures are logged, the event still counts as triggered so the order isn't
+// placed repeatedly
+func (e *Event) placeOrder(action []string) {
+	side, amount, err := parseOrderAction(action)
+	if err != nil {
+		log.Errorf("Event %d order not placed: %s", e.ID, err)
+		return
+	}
+	if orderHandler == nil {
+		log.Warnf("Event %d triggered, no order handler set to place the %s %v order.",
+			e.ID, side, amount)
+		return
+	}
+	err = orderHandler(e, side, amount)
+	if err != nil {
+		log.Errorf("Event %d %s %v order failed: %s", e.ID, side, amount, err)
+	}
+}
+
+// isOrderAction returns whether an action is an ORDER action
+func isOrderAction(action string) bool {
+	return common.StringToUpper(common.SplitStrings(action, ",")[0]) == actionOrder
+}
+
+// parseOrderAction returns the side and amount of an ORDER,<BUY|SELL>,<amount>
+// action
+func parseOrderAction(action []string) (string, float64, error) {
+	if len(action) != 3 {
+		return "", 0, errInvalidAction
+	}
+	side := common.StringToUpper(action[1])
+	if side != orderSideBuy && side != orderSideSell {
+		return "", 0, errInvalidAction
+	}
+	amount, err := strconv.ParseFloat(action[2], 64)
+	if err != nil || !(amount > 0) {
+		return "", 0, errInvalidAction
+	}
+	return side, amount, nil
+}
+
 // String turns the structure event into a string
 func (e *Event) String() string {
 	condition := common.SplitStrings(e.Condition, ",")
+	item := e.Item
+	switch {
+	case common.StringToUpper(e.Item) == itemDepth:
+		item = fmt.Sprintf("%s %s within %v%% of the mid price", e.Params.Side,
+			e.Item, e.Params.Percent)
+	case isTradeItem(common.StringToUpper(e.Item)):
+		item = fmt.Sprintf("%s over %s", e.Item, e.Params.Window)
+	}
 	return fmt.Sprintf(
 		"If the %s%s [%s] %s on %s is %s then %s.", e.Pair.FirstCurrency.String(),
-		e.Pair.SecondCurrency.String(), e.Asset, e.Item, e.Exchange, condition[0]+" "+condition[1], e.Action,
+		e.Pair.SecondCurrency.String(), e.Asset, item, e.Exchange, condition[0]+" "+condition[1], e.Action,
 	)
 }
 
+// marketData holds the market data an event is evaluated against, a nil
+// field means no data is stored for the event's exchange, pair and asset type.
+// trades is set when the stored trades are read for the event's trade stats
+type marketData struct {
+	ticker    *ticker.Price
+	orderbook *orderbook.Base
+	trades    bool
+}
+
+// updated returns when the market data was updated. Trade data and updates
+// without an update time are taken as updated at the current time
+func (m marketData) updated() time.Time {
+	var updated time.Time
+	switch {
+	case m.ticker != nil:
+		updated = m.ticker.LastUpdated
+	case m.orderbook != nil:
+		updated = m.orderbook.LastUpdated
+	case !m.trades:
+		return updated
+	}
+	if updated.IsZero() {
+		updated = clock()
+	}
+	return updated
+}
+
+// fetchMarketData reads the latest stored ticker or orderbook needed by the
+// event item once, so every check of an event uses the same data
+func (e *Event) fetchMarketData() marketData {
+	var data marketData
+	if isTradeItem(common.StringToUpper(e.Item)) {
+		data.trades = true
+		return data
+	}
+	if common.StringToUpper(e.Item) == itemDepth {
+		ob, err := orderbook.GetOrderbook(e.Exchange, e.Pair, e.Asset)
+		if err == nil {
+			data.orderbook = &ob
+		}
+		return data
+	}
+
+	t, err := ticker.GetTicker(e.Exchange, e.Pair, e.Asset)
+	if err == nil {
+		data.ticker = &t
+	}
+	return data
+}
+
 // CheckCondition will check the event structure to see if there is a condition
 // met
 func (e *Event) CheckCondition() bool {
-	condition := common.SplitStrings(e.Condition, ",")
-	targetPrice, _ := strconv.ParseFloat(condition[1], 64)
+	return e.evaluate(e.fetchMarketData())
+}
 
-	t, err := ticker.GetTicker(e.Exchange, e.Pair, e.Asset)
-	if err != nil {
+// evaluate checks the event condition against the supplied market data and
+// executes the event action if the condition is met. Events without data for
+// their item are skipped
+func (e *Event) evaluate(data marketData) bool {
+	e.LastEvaluated = clock()
+	if updated := data.updated(); updated.After(e.LastData) {
+		e.LastData = updated
+	}
+	value, ok := e.itemValue(data)
+	if !ok {
 		return false
 	}
+	return e.checkValue(value)
+}
+
+// itemValue returns the value of the event item from the market data and
+// whether the data holds it
+func (e *Event) itemValue(data marketData) (float64, bool) {
+	switch common.StringToUpper(e.Item) {
+	case itemPrice:
+		if data.ticker == nil || data.ticker.Last == 0 {
+			return 0, false
+		}
+		return data.ticker.Last, true
+	case itemVolume:
+		if data.ticker == nil {
+			return 0, false
+		}
+		return data.ticker.Volume, true
+	case itemDepth:
+		if data.orderbook == nil {
+			return 0, false
+		}
+		depth, err := data.orderbook.TotalWithinPercent(e.Params.Percent,
+			e.Params.Side == sideBid)
+		if err != nil {
+			return 0, false
+		}
+		return depth, true
+	case itemTradeRate, itemTradeImbalance:
+		if !data.trades {
+			return 0, false
+		}
+		window, err := parseTradeWindow(e.Params.Window)
+		if err != nil {
+			return 0, false
+		}
+		stats, err := trades.GetStats(e.Exchange, e.Pair, e.Asset, window, clock())
+		if err != nil {
+			return 0, false
+		}
+		if common.StringToUpper(e.Item) == itemTradeRate {
+			return stats.TradesPerMinute, true
+		}
+		return stats.Imbalance, true
+	}
+	return 0, false
+}
 
-	lastPrice := t.Last
+// isTradeItem returns whether an upper case event item is measured from the
+// stored trades
+func isTradeItem(item string) bool {
+	return item == itemTradeRate || item == itemTradeImbalance
+}
 
-	if lastPrice == 0 {
-		return false
+// parseTradeWindow parses the window of a trade activity event, an empty
+// window is the default window
+func parseTradeWindow(window string) (time.Duration, error) {
+	if window == "" {
+		window = defaultTradeWindow
 	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return 0, errInvalidWindow
+	}
+	return d, nil
+}
+
+// checkValue checks the event condition against the supplied item value and
+// executes the event action if the condition is met
+func (e *Event) checkValue(value float64) bool {
+	condition := common.SplitStrings(e.Condition, ",")
+	target, _ := strconv.ParseFloat(condition[1], 64)
 
 	switch condition[0] {
 	case greaterThan:
 		{
-			if lastPrice > targetPrice {
+			if value > target {
 				return e.ExecuteAction()
 			}
 		}
 	case greaterThanOrEqual:
 		{
-			if lastPrice >= targetPrice {
+			if value >= target {
 				return e.ExecuteAction()
 			}
 		}
 	case lessThan:
 		{
-			if lastPrice < targetPrice {
+			if value < target {
 				return e.ExecuteAction()
 			}
 		}
 	case lessThanOrEqual:
 		{
-			if lastPrice <= targetPrice {
+			if value <= target {
 				return e.ExecuteAction()
 			}
 		}
 	case isEqual:
 		{
-			if lastPrice == targetPrice {
+			if value == target {
 				return e.ExecuteAction()
 			}
 		}
@@ -214,6 +542,11 @@ func IsValidEvent(Exchange, Item, Condition, Action string) error {
 	if common.StringContains(Action, ",") {
 		action := common.SplitStrings(Action, ",")
 
+		if action[0] == actionOrder {
+			_, _, err := parseOrderAction(action)
+			return err
+		}
+
 		if action[0] != actionSMSNotify {
 			return errInvalidAction
 		}
@@ -229,34 +562,232 @@ func IsValidEvent(Exchange, Item, Condition, Action string) error {
 	return nil
 }
 
-// CheckEvents is the overarching routine that will iterate through the Events
-// chain
+// CheckEvents checks the conditions of all pending events against the latest
+// stored market data once. It is run every events check interval so events
+// are evaluated when market data updates are rate limited or not delivered
 func CheckEvents() {
-	for {
-		total, executed := GetEventCounter()
-		if total > 0 && executed != total {
-			for _, event := range Events {
-				if !event.Executed {
-					success := event.CheckCondition()
-					if success {
-						log.Debugf(
-							"Event %d triggered on %s successfully.\n", event.ID,
-							event.Exchange,
-						)
-						event.Executed = true
-					}
-				}
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
+	for _, event := range Events {
+		if !event.Executed && !event.Inactive &&
+			!IsExchangeInMaintenance(event.Exchange) {
+			if event.CheckCondition() {
+				event.setExecuted()
 			}
 		}
 	}
 }
 
+// ProcessTicker checks the conditions of pending price and volume events
+// matching the exchange, asset type and currency pair of an updated ticker. It
+// is registered as a ticker hook so events are evaluated as market data
+// arrives
+func ProcessTicker(exchangeName, assetType string, p pair.CurrencyPair, price ticker.Price) {
+	processUpdate(exchangeName, assetType, p, marketData{ticker: &price})
+}
+
+// ProcessOrderbook checks the conditions of pending depth events matching the
+// exchange, asset type and currency pair of an updated orderbook. It is
+// registered as an orderbook hook so events are evaluated as market data
+// arrives
+func ProcessOrderbook(exchangeName, assetType string, p pair.CurrencyPair, ob orderbook.Base) {
+	processUpdate(exchangeName, assetType, p, marketData{orderbook: &ob})
+}
+
+// ProcessTrade checks the conditions of pending trade activity events matching
+// the exchange, asset type and currency pair of a streamed trade. It is
+// registered as a trade hook so events are evaluated as trades arrive
+func ProcessTrade(exchangeName, assetType string, p pair.CurrencyPair, _ trades.Trade) {
+	processUpdate(exchangeName, assetType, p, marketData{trades: true})
+}
+
+// processUpdate evaluates the pending events matching a market data update,
+// events whose item isn't held by the update are skipped. Events evaluated
+// within the minimum evaluation interval only record the update time
+func processUpdate(exchangeName, assetType string, p pair.CurrencyPair, data marketData) {
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
+	now := clock()
+	for _, event := range Events {
+		if event.Executed || event.Inactive {
+			continue
+		}
+
+		if common.StringToUpper(event.Exchange) != common.StringToUpper(exchangeName) ||
+			common.StringToUpper(event.Asset) != common.StringToUpper(assetType) ||
+			!event.Pair.Equal(p, false) {
+			continue
+		}
+
+		if IsExchangeInMaintenance(exchangeName) {
+			continue
+		}
+
+		if minEvaluationInterval > 0 && now.Sub(event.LastEvaluated) < minEvaluationInterval {
+			if updated := data.updated(); updated.After(event.LastData) {
+				event.LastData = updated
+			}
+			continue
+		}
+
+		if event.evaluate(data) {
+			event.setExecuted()
+		}
+	}
+}
+
+// SetExchangeMaintenance marks an exchange as in or out of maintenance. Events
+// on an exchange in maintenance are not evaluated, so they can't act on frozen
+// market data
+func SetExchangeMaintenance(exchName string, inMaintenance bool) {
+	name := common.StringToUpper(exchName)
+	maintenanceMtx.Lock()
+	if inMaintenance {
+		maintenance[name] = true
+	} else {
+		delete(maintenance, name)
+	}
+	maintenanceMtx.Unlock()
+
+	var pending int
+	evaluationMtx.Lock()
+	for _, event := range Events {
+		if !event.Executed && !event.Inactive &&
+			common.StringToUpper(event.Exchange) == name {
+			pending++
+		}
+	}
+	evaluationMtx.Unlock()
+	if pending == 0 {
+		return
+	}
+
+	if inMaintenance {
+		log.Warnf("Suppressing %d events on %s while the exchange is in maintenance.\n",
+			pending, exchName)
+		return
+	}
+	log.Debugf("Resuming %d events on %s after exchange maintenance.\n",
+		pending, exchName)
+}
+
+// IsExchangeInMaintenance returns whether events on an exchange are suppressed
+// due to exchange maintenance
+func IsExchangeInMaintenance(exchName string) bool {
+	maintenanceMtx.RLock()
+	defer maintenanceMtx.RUnlock()
+	return maintenance[common.StringToUpper(exchName)]
+}
+
+// setExecuted flags the event as executed and persists the event set, the
+// caller must hold evaluationMtx
+func (e *Event) setExecuted() {
+	log.Debugf("Event %d triggered on %s successfully.\n", e.ID, e.Exchange)
+	e.Executed = true
+	e.LastTriggered = clock()
+	persistEvents()
+}
+
+// LoadEvents sets the file used to persist events and loads any previously
+// saved events from it. A missing file results in an empty event set, a
+// corrupt file is moved aside to the path with a .corrupt suffix so it isn't
+// overwritten by the next save, and also results in an empty event set and
+// returns an error so that startup can continue. Loaded events whose exchange
+// is no longer enabled are flagged as inactive
+func LoadEvents(path string) error {
+	eventsFileMtx.Lock()
+	eventsFile = path
+	eventsFileMtx.Unlock()
+	evaluationMtx.Lock()
+	defer evaluationMtx.Unlock()
+	Events = nil
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var loaded []*Event
+	err = json.Unmarshal(data, &loaded)
+	if err != nil {
+		corrupt := path + ".corrupt"
+		if renameErr := os.Rename(path, corrupt); renameErr != nil {
+			return fmt.Errorf("events file %s is corrupt: %s, failed to move it aside: %s",
+				path, err, renameErr)
+		}
+		return fmt.Errorf("events file %s is corrupt, moved to %s: %s", path, corrupt, err)
+	}
+
+	for _, e := range loaded {
+		if e == nil {
+			continue
+		}
+		e.Inactive = !IsValidExchange(e.Exchange)
+		if e.Inactive {
+			log.Warnf("Event %d exchange %s is not enabled, flagging event as inactive",
+				e.ID, e.Exchange)
+		}
+		Events = append(Events, e)
+	}
+	return nil
+}
+
+// SaveEvents writes the current events to the events file set by LoadEvents.
+// The file is replaced atomically so that a failed write cannot leave a
+// partially written events file
+func SaveEvents() error {
+	evaluationMtx.Lock()
+	saved := copyEvents()
+	evaluationMtx.Unlock()
+	return saveEvents(saved)
+}
+
+// saveEvents writes a copy of the events to the events file
+func saveEvents(saved []Event) error {
+	eventsFileMtx.Lock()
+	defer eventsFileMtx.Unlock()
+	if eventsFile == "" {
+		return errors.New("events file not set")
+	}
+
+	data, err := json.MarshalIndent(saved, "", " ")
+	if err != nil {
+		return err
+	}
+
+	tmp := eventsFile + ".tmp"
+	err = common.WriteFile(tmp, data)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, eventsFile)
+}
+
+// persistEvents saves the events if persistence is enabled, logging any
+// failure. The caller must hold evaluationMtx
+func persistEvents() {
+	eventsFileMtx.Lock()
+	enabled := eventsFile != ""
+	eventsFileMtx.Unlock()
+	if !enabled {
+		return
+	}
+
+	err := saveEvents(copyEvents())
+	if err != nil {
+		log.Errorf("Failed to save events: %s", err)
+	}
+}
+
 // IsValidExchange validates the exchange
 func IsValidExchange(Exchange string) bool {
 	Exchange = common.StringToUpper(Exchange)
 	cfg := config.GetConfig()
 	for _, x := range cfg.Exchanges {
-		if x.Name == Exchange && x.Enabled {
+		if common.StringToUpper(x.Name) == Exchange && x.Enabled {
 			return true
 		}
 	}
@@ -272,11 +803,18 @@ func IsValidCondition(Condition string) bool {
 	return false
 }
 
+// IsValidConditionParams validates the parameters of a DEPTH condition
+func IsValidConditionParams(params ConditionParams) bool {
+	side := common.StringToUpper(params.Side)
+	return (side == sideBid || side == sideAsk) &&
+		params.Percent > 0 && params.Percent < 100
+}
+
 // IsValidAction validates passed in action
 func IsValidAction(Action string) bool {
 	Action = common.StringToUpper(Action)
 	switch Action {
-	case actionSMSNotify, actionConsolePrint, actionTest:
+	case actionSMSNotify, actionConsolePrint, actionTest, actionOrder:
 		return true
 	}
 	return false
@@ -286,7 +824,7 @@ func IsValidAction(Action string) bool {
 func IsValidItem(Item string) bool {
 	Item = common.StringToUpper(Item)
 	switch Item {
-	case itemPrice:
+	case itemPrice, itemVolume, itemDepth, itemTradeRate, itemTradeImbalance:
 		return true
 	}
 	return false
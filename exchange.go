@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/anx"
 	"github.com/thrasher-/gocryptotrader/exchanges/binance"
@@ -31,19 +40,89 @@ import (
 	"github.com/thrasher-/gocryptotrader/exchanges/localbitcoins"
 	"github.com/thrasher-/gocryptotrader/exchanges/okcoin"
 	"github.com/thrasher-/gocryptotrader/exchanges/okex"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/poloniex"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-/gocryptotrader/exchanges/wex"
 	"github.com/thrasher-/gocryptotrader/exchanges/yobit"
 	"github.com/thrasher-/gocryptotrader/exchanges/zb"
 	log "github.com/thrasher-/gocryptotrader/logger"
 )
 
+// exchangeStartupWorkers is the maximum number of exchanges started
+// concurrently
+const exchangeStartupWorkers = 10
+
+const (
+	// fixturesDir is the data directory subdirectory exchange request fixtures
+	// are captured to
+	fixturesDir = "fixtures"
+	// captureFixturesEnv enables fixture capture for a comma separated list of
+	// exchange names, or every exchange if set to all
+	captureFixturesEnv = "GCT_CAPTURE_FIXTURES"
+)
+
+// Exchange health statuses
+const (
+	ExchangeStatusUp       = "up"
+	ExchangeStatusDegraded = "degraded"
+	ExchangeStatusFailed   = "failed"
+)
+
+// ExchangeHealth holds the startup status of an exchange, when its account
+// balances were last refreshed, whether it is in maintenance and the number
+// of tickers and orderbooks stored for it
+type ExchangeHealth struct {
+	Exchange           string        `json:"exchange"`
+	Status             string        `json:"status"`
+	StartupDuration    time.Duration `json:"startupDuration"`
+	Error              string        `json:"error,omitempty"`
+	LastBalanceRefresh time.Time     `json:"lastBalanceRefresh"`
+	Maintenance        bool          `json:"maintenance"`
+	MaintenanceSince   time.Time     `json:"maintenanceSince,omitempty"`
+	TickerCount        int           `json:"tickerCount"`
+	OrderbookCount     int           `json:"orderbookCount"`
+	// RejectedTickers is the number of invalid tickers from the exchange which
+	// weren't stored
+	RejectedTickers int64 `json:"rejectedTickers"`
+	// RateLimit is the latest API rate limit consumption reported by the
+	// exchange, if any
+	RateLimit *request.RateLimitStatus `json:"rateLimit,omitempty"`
+	// Latency holds the REST request latency percentiles of the exchange by
+	// endpoint class, if it has sent any requests
+	Latency request.LatencyStats `json:"latency,omitempty"`
+	// Timeouts are the effective request and websocket dial timeouts of the
+	// exchange
+	Timeouts *exchange.Timeouts `json:"timeouts,omitempty"`
+	// Endpoints are the active REST and websocket endpoints of the exchange and
+	// whether it is in sandbox mode
+	Endpoints *exchange.Endpoints `json:"endpoints,omitempty"`
+	// AuthEnabled is whether the exchange is used by the authenticated API
+	// routines, see GetAuthAPISupportedExchanges
+	AuthEnabled bool `json:"authEnabled"`
+}
+
+var (
+	exchangeHealth    = make(map[string]ExchangeHealth)
+	exchangeHealthMtx sync.RWMutex
+)
+
+// accountExchanges holds the exchange instances set up for sub-accounts keyed
+// by their lowercase exchange account name
+var (
+	accountExchanges    = make(map[string]exchange.IBotExchange)
+	accountExchangesMtx sync.Mutex
+)
+
 // vars related to exchange functions
 var (
 	ErrNoExchangesLoaded     = errors.New("no exchanges have been loaded")
 	ErrExchangeNotFound      = errors.New("exchange not found")
 	ErrExchangeAlreadyLoaded = errors.New("exchange already loaded")
 	ErrExchangeFailedToLoad  = errors.New("exchange failed to load")
+	ErrExchangeHealthUnknown = errors.New("exchange health status unknown")
+	ErrWebsocketNotSupported = errors.New("exchange websocket not supported")
 )
 
 // CheckExchangeExists returns true whether or not an exchange has already
@@ -85,7 +164,14 @@ func ReloadExchange(name string) error {
 	}
 
 	e := GetExchangeByName(nameLower)
+	clearAccountExchanges(name)
 	e.Setup(exchCfg)
+	e.SetAPIAccounts(exchCfg.Accounts)
+	e.SetCurrencyDetailOverrides(exchCfg.CurrencyDetails)
+	e.SetVerboseBodyLimit(exchCfg.VerboseBodyLimit)
+	e.SetFixtureCapture(fixtureCaptureDir(&exchCfg))
+	e.SetRateLimitThreshold(exchCfg.RateLimitThreshold)
+	clearConnectivityResult(name)
 	log.Debugf("%s exchange reloaded successfully.\n", name)
 	return nil
 }
@@ -112,11 +198,15 @@ func UnloadExchange(name string) error {
 	if err != nil {
 		return err
 	}
+	MarkConfigDirty()
 
 	for x := range bot.exchanges {
 		if bot.exchanges[x].GetName() == name {
 			bot.exchanges[x].SetEnabled(false)
 			bot.exchanges = append(bot.exchanges[:x], bot.exchanges[x+1:]...)
+			clearAccountExchanges(name)
+			ticker.RemoveExchangeTickers(name)
+			orderbook.RemoveExchangeOrderbooks(name)
 			return nil
 		}
 	}
@@ -124,17 +214,232 @@ func UnloadExchange(name string) error {
 	return ErrExchangeNotFound
 }
 
+// ExchangeWebsocketState holds the websocket state of an exchange
+type ExchangeWebsocketState struct {
+	Exchange  string `json:"exchange"`
+	Enabled   bool   `json:"enabled"`
+	Connected bool   `json:"connected"`
+}
+
+// SetExchangeWebsocket enables or disables an exchange websocket at runtime,
+// connecting or shutting down its connection and updating the exchange config.
+// Enabling an already connected websocket does nothing. The resulting state is
+// returned along with any connection error
+func SetExchangeWebsocket(name string, enabled bool) (ExchangeWebsocketState, error) {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return ExchangeWebsocketState{}, ErrExchangeNotFound
+	}
+
+	ws, err := exch.GetWebsocket()
+	if err != nil || ws == nil {
+		return ExchangeWebsocketState{}, ErrWebsocketNotSupported
+	}
+
+	switch {
+	case enabled && !ws.IsEnabled():
+		err = ws.SetEnabled(true)
+	case enabled && !ws.IsConnected():
+		err = ws.Connect()
+	case !enabled && ws.IsEnabled():
+		err = ws.SetEnabled(false)
+	}
+
+	state := ExchangeWebsocketState{
+		Exchange:  exch.GetName(),
+		Enabled:   ws.IsEnabled(),
+		Connected: ws.IsConnected(),
+	}
+	log.ExchangeLogger(state.Exchange).Debugf("Websocket enabled: %t connected: %t",
+		state.Enabled, state.Connected)
+
+	exchCfg, cfgErr := bot.config.GetExchangeConfig(state.Exchange)
+	if cfgErr != nil {
+		return state, cfgErr
+	}
+	if exchCfg.Websocket != state.Enabled {
+		exchCfg.Websocket = state.Enabled
+		cfgErr = bot.config.UpdateExchangeConfig(exchCfg)
+		if cfgErr != nil {
+			return state, cfgErr
+		}
+		MarkConfigDirty()
+	}
+	return state, err
+}
+
+// SetExchangeEndpoint overrides the REST and websocket endpoints of a loaded
+// exchange at runtime and saves them to its config, empty URLs are left
+// unchanged. A connected websocket is reconnected when its URL changes. The
+// resulting endpoints are returned
+func SetExchangeEndpoint(name, apiURL, apiURLSecondary, websocketURL string) (exchange.Endpoints, error) {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return exchange.Endpoints{}, ErrExchangeNotFound
+	}
+
+	err := exch.SetEndpoints(apiURL, apiURLSecondary, websocketURL)
+	if err != nil {
+		return exchange.Endpoints{}, err
+	}
+	endpoints := exch.GetEndpoints()
+	log.ExchangeLogger(exch.GetName()).Debugf("Endpoints set to %s %s %s",
+		endpoints.APIURL, endpoints.APIURLSecondary, endpoints.WebsocketURL)
+
+	exchCfg, err := bot.config.GetExchangeConfig(exch.GetName())
+	if err != nil {
+		return endpoints, err
+	}
+	if apiURL != "" {
+		exchCfg.APIURL = apiURL
+	}
+	if apiURLSecondary != "" {
+		exchCfg.APIURLSecondary = apiURLSecondary
+	}
+	if websocketURL != "" {
+		exchCfg.WebsocketURL = websocketURL
+	}
+	err = bot.config.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		return endpoints, err
+	}
+	MarkConfigDirty()
+	return endpoints, nil
+}
+
 // LoadExchange loads an exchange by name
 func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
-	nameLower := common.StringToLower(name)
-	var exch exchange.IBotExchange
+	exch, err := setupExchange(name)
+	if err != nil {
+		return err
+	}
+
+	if useWG {
+		exch.Start(wg)
+	} else {
+		wg := sync.WaitGroup{}
+		exch.Start(&wg)
+		wg.Wait()
+	}
+	return nil
+}
 
+// setupExchange creates, sets up and adds an exchange by name to the bot
+// without starting it
+func setupExchange(name string) (exchange.IBotExchange, error) {
+	nameLower := common.StringToLower(name)
 	if len(bot.exchanges) > 0 {
 		if CheckExchangeExists(nameLower) {
-			return ErrExchangeAlreadyLoaded
+			return nil, ErrExchangeAlreadyLoaded
+		}
+	}
+
+	exch, err := createExchange(nameLower)
+	if err != nil {
+		return nil, err
+	}
+
+	if exch == nil {
+		return nil, ErrExchangeFailedToLoad
+	}
+
+	exch.SetDefaults()
+	bot.exchanges = append(bot.exchanges, exch)
+	exchCfg, err := bot.config.GetExchangeConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	exchCfg.Enabled = true
+	exch.Setup(exchCfg)
+	exch.SetAPIAccounts(exchCfg.Accounts)
+	exch.SetCurrencyDetailOverrides(exchCfg.CurrencyDetails)
+	exch.SetVerboseBodyLimit(exchCfg.VerboseBodyLimit)
+	exch.SetFixtureCapture(fixtureCaptureDir(&exchCfg))
+	exch.SetRateLimitThreshold(exchCfg.RateLimitThreshold)
+	return exch, nil
+}
+
+// GetAccountExchange returns the exchange instance used for the labelled
+// sub-account of an exchange. Each sub-account is set up once as a separate
+// instance holding its own credentials, so concurrent requests for different
+// accounts never share or swap keys. An empty label returns the exchange itself
+func GetAccountExchange(exch exchange.IBotExchange, account string) (exchange.IBotExchange, error) {
+	if account == "" {
+		return exch, nil
+	}
+
+	if !exch.GetAuthenticatedAPISupport() {
+		return nil, exchange.NewAuthenticationNotConfiguredError(exch.GetName())
+	}
+
+	key := common.StringToLower(FormatExchangeAccountName(exch.GetName(), account))
+	accountExchangesMtx.Lock()
+	defer accountExchangesMtx.Unlock()
+
+	if accountExch, ok := accountExchanges[key]; ok {
+		return accountExch, nil
+	}
+
+	exchCfg, err := bot.config.GetExchangeConfig(exch.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	var creds *config.APICredentialsConfig
+	for x := range exchCfg.Accounts {
+		if common.StringToLower(exchCfg.Accounts[x].Label) == common.StringToLower(account) {
+			creds = &exchCfg.Accounts[x]
+			break
 		}
 	}
+	if creds == nil {
+		return nil, fmt.Errorf("%s account %q not found", exch.GetName(), account)
+	}
 
+	accountExch, err := createExchange(common.StringToLower(exch.GetName()))
+	if err != nil {
+		return nil, err
+	}
+
+	exchCfg.APIKey = creds.APIKey
+	exchCfg.APISecret = creds.APISecret
+	if creds.ClientID != "" {
+		exchCfg.ClientID = creds.ClientID
+	}
+	if creds.APIAuthPEMKey != "" {
+		exchCfg.APIAuthPEMKey = creds.APIAuthPEMKey
+	}
+	exchCfg.Accounts = nil
+	exchCfg.Websocket = false
+	exchCfg.Enabled = true
+
+	accountExch.SetDefaults()
+	accountExch.Setup(exchCfg)
+	accountExch.SetCurrencyDetailOverrides(exchCfg.CurrencyDetails)
+	accountExch.SetVerboseBodyLimit(exchCfg.VerboseBodyLimit)
+	accountExch.SetFixtureCapture(fixtureCaptureDir(&exchCfg))
+	accountExch.SetRateLimitThreshold(exchCfg.RateLimitThreshold)
+	accountExchanges[key] = accountExch
+	return accountExch, nil
+}
+
+// clearAccountExchanges removes the sub-account instances of an exchange so
+// they are set up again from its current config
+func clearAccountExchanges(exchName string) {
+	prefix := common.StringToLower(exchName) + config.AccountLabelSeparator
+	accountExchangesMtx.Lock()
+	for key := range accountExchanges {
+		if strings.HasPrefix(key, prefix) {
+			delete(accountExchanges, key)
+		}
+	}
+	accountExchangesMtx.Unlock()
+}
+
+// createExchange returns a new exchange by its lowercase name
+func createExchange(nameLower string) (exchange.IBotExchange, error) {
+	var exch exchange.IBotExchange
 	switch nameLower {
 	case "anx":
 		exch = new(anx.ANX)
@@ -183,9 +488,9 @@ func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
 	case "localbitcoins":
 		exch = new(localbitcoins.LocalBitcoins)
 	case "okcoin china":
-		exch = new(okcoin.OKCoin)
+		exch = &okcoin.OKCoin{Variant: okcoin.China}
 	case "okcoin international":
-		exch = new(okcoin.OKCoin)
+		exch = &okcoin.OKCoin{Variant: okcoin.International}
 	case "okex":
 		exch = new(okex.OKEX)
 	case "poloniex":
@@ -197,36 +502,155 @@ func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
 	case "zb":
 		exch = new(zb.ZB)
 	default:
-		return ErrExchangeNotFound
+		return nil, ErrExchangeNotFound
 	}
 
-	if exch == nil {
-		return ErrExchangeFailedToLoad
+	return exch, nil
+}
+
+// supportedExchanges holds the config names of the exchanges the bot supports
+var supportedExchanges = []string{
+	"ANX",
+	"Binance",
+	"Bitfinex",
+	"Bitflyer",
+	"Bithumb",
+	"Bitmex",
+	"Bitstamp",
+	"Bittrex",
+	"BTCC",
+	"BTC Markets",
+	"COINUT",
+	"EXMO",
+	"CoinbasePro",
+	"GateIO",
+	"Gemini",
+	"HitBTC",
+	"Huobi",
+	"HuobiHadax",
+	"ITBIT",
+	"Kraken",
+	"LakeBTC",
+	"Liqui",
+	"LocalBitcoins",
+	"OKCOIN China",
+	"OKCOIN International",
+	"OKEX",
+	"Poloniex",
+	"WEX",
+	"Yobit",
+	"ZB",
+}
+
+// exchangeDefaultConfig returns the default config of a supported exchange,
+// the name is matched case insensitively and the config name is returned
+func exchangeDefaultConfig(name string) (config.ExchangeConfig, error) {
+	nameLower := common.StringToLower(name)
+	for x := range supportedExchanges {
+		if common.StringToLower(supportedExchanges[x]) != nameLower {
+			continue
+		}
+		exch, err := createExchange(nameLower)
+		if err != nil {
+			return config.ExchangeConfig{}, err
+		}
+		exch.SetDefaults()
+		exchCfg := exch.GetDefaultConfig()
+		exchCfg.Name = supportedExchanges[x]
+		return exchCfg, nil
 	}
+	return config.ExchangeConfig{}, fmt.Errorf("%w: %s", ErrExchangeNotFound, name)
+}
 
-	exch.SetDefaults()
-	bot.exchanges = append(bot.exchanges, exch)
-	exchCfg, err := bot.config.GetExchangeConfig(name)
-	if err != nil {
-		return err
+// unconfiguredExchanges returns the supported exchanges absent from the
+// config, which are treated as disabled
+func unconfiguredExchanges() []string {
+	var result []string
+	for x := range supportedExchanges {
+		if _, err := bot.config.GetExchangeConfig(supportedExchanges[x]); err != nil {
+			result = append(result, supportedExchanges[x])
+		}
 	}
+	return result
+}
 
-	exchCfg.Enabled = true
-	exch.Setup(exchCfg)
+// fixtureCaptureDir returns the directory the requests of an exchange are
+// captured to as test fixtures, or an empty string if capturing isn't enabled
+// for the exchange by its config or the GCT_CAPTURE_FIXTURES environment
+// variable
+func fixtureCaptureDir(exchCfg *config.ExchangeConfig) string {
+	enabled := exchCfg.CaptureFixtures
+	if env := os.Getenv(captureFixturesEnv); env != "" {
+		names := common.SplitStrings(common.StringToLower(env), ",")
+		for x := range names {
+			name := strings.TrimSpace(names[x])
+			if name == "all" || name == common.StringToLower(exchCfg.Name) {
+				enabled = true
+			}
+		}
+	}
+	if !enabled {
+		return ""
+	}
 
-	if useWG {
-		exch.Start(wg)
-	} else {
-		wg := sync.WaitGroup{}
-		exch.Start(&wg)
+	dir := filepath.Join(bot.settings.DataDir, fixturesDir, exchCfg.Name)
+	log.Warnf("%s exchange requests are captured to %s, responses are not redacted and may hold account details.\n",
+		exchCfg.Name, dir)
+	return dir
+}
+
+// contextStarter is implemented by exchanges whose startup requests can be
+// aborted through a context
+type contextStarter interface {
+	StartWithContext(ctx context.Context, wg *sync.WaitGroup)
+}
+
+// startExchange starts an exchange and waits for its startup routine to
+// complete or the supplied timeout to elapse, recording the outcome in the
+// exchange health status. Exchanges supporting it are started with a context
+// which is cancelled on timeout, aborting their startup requests such as the
+// tradable pair update. An exchange whose startup routine completes after
+// the timeout without being aborted is marked as up
+func startExchange(exch exchange.IBotExchange, timeout time.Duration) {
+	start := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	starter, abortable := exch.(contextStarter)
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		if abortable {
+			starter.StartWithContext(ctx, &wg)
+		} else {
+			exch.Start(&wg)
+		}
 		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		setExchangeHealth(exch.GetName(), ExchangeStatusUp, time.Since(start), nil)
+	case <-time.After(timeout):
+		cancel()
+		setExchangeHealth(exch.GetName(), ExchangeStatusDegraded, time.Since(start),
+			fmt.Errorf("startup exceeded timeout of %v", timeout))
+		if !abortable {
+			go func() {
+				<-done
+				setExchangeHealth(exch.GetName(), ExchangeStatusUp, time.Since(start), nil)
+			}()
+		}
 	}
-	return nil
 }
 
-// SetupExchanges sets up the exchanges used by the bot
+// SetupExchanges sets up the exchanges used by the bot. Newly loaded exchanges
+// are started concurrently by a bounded pool of workers, an exchange which
+// fails to start within the configured startup timeout is marked as degraded
+// and startup continues for the remaining exchanges
 func SetupExchanges() {
-	var wg sync.WaitGroup
+	var toStart []exchange.IBotExchange
 	for _, exch := range bot.config.Exchanges {
 		if CheckExchangeExists(exch.Name) {
 			e := GetExchangeByName(exch.Name)
@@ -252,18 +676,256 @@ func SetupExchanges() {
 			log.Debugf("%s: Exchange support: Disabled", exch.Name)
 			continue
 		} else {
-			err := LoadExchange(exch.Name, true, &wg)
+			e, err := setupExchange(exch.Name)
 			if err != nil {
 				log.Errorf("LoadExchange %s failed: %s", exch.Name, err)
+				setExchangeHealth(exch.Name, ExchangeStatusFailed, 0, err)
 				continue
 			}
+			toStart = append(toStart, e)
 		}
 		log.Debugf(
-			"%s: Exchange support: Enabled (Authenticated API support: %s - Verbose mode: %s).\n",
+			"%s: Exchange support: Enabled (Authenticated API support: %s - Verbose mode: %s - Sub-accounts: %d).\n",
 			exch.Name,
 			common.IsEnabled(exch.AuthenticatedAPISupport),
 			common.IsEnabled(exch.Verbose),
+			len(exch.Accounts),
 		)
 	}
+
+	if unconfigured := unconfiguredExchanges(); len(unconfigured) > 0 {
+		log.Debugf("Exchange support: Disabled for exchanges absent from config: %s.\n",
+			common.JoinStrings(unconfigured, ", "))
+	}
+
+	if len(toStart) == 0 {
+		return
+	}
+
+	timeout := bot.config.ExchangeStartupTimeout
+	var wg sync.WaitGroup
+	jobs := make(chan exchange.IBotExchange, len(toStart))
+	workers := exchangeStartupWorkers
+	if len(toStart) < workers {
+		workers = len(toStart)
+	}
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for e := range jobs {
+				startExchange(e, timeout)
+			}
+		}()
+	}
+
+	for x := range toStart {
+		jobs <- toStart[x]
+	}
+	close(jobs)
 	wg.Wait()
+
+	logExchangeStartupSummary(toStart)
+}
+
+// GetAuthAPISupportedExchanges returns the enabled exchanges whose
+// authenticated API can be used: authenticated API support is still set after
+// the config validation, which disables it for exchanges without credentials,
+// and the last connectivity check of the exchange, if any, didn't reject its
+// credentials. Authenticated only routines skip the other exchanges
+func GetAuthAPISupportedExchanges() []exchange.IBotExchange {
+	var result []exchange.IBotExchange
+	for x := range bot.exchanges {
+		if isAuthAPISupported(bot.exchanges[x]) {
+			result = append(result, bot.exchanges[x])
+		}
+	}
+	return result
+}
+
+// isAuthAPISupported returns whether an exchange is used by the authenticated
+// API routines
+func isAuthAPISupported(exch exchange.IBotExchange) bool {
+	return exch != nil && exch.IsEnabled() && exch.GetAuthenticatedAPISupport() &&
+		!authCredentialsRejected(exch.GetName())
+}
+
+// logAuthAPISupportedExchanges logs the exchanges used by the authenticated
+// API routines
+func logAuthAPISupportedExchanges() {
+	var names []string
+	for _, exch := range GetAuthAPISupportedExchanges() {
+		names = append(names, exch.GetName())
+	}
+	if len(names) == 0 {
+		log.Debugln("Authenticated API support: No exchanges.")
+		return
+	}
+	log.Debugf("Authenticated API support: %s.\n", common.JoinStrings(names, ", "))
+}
+
+// logExchangeStartupSummary logs the startup outcome and duration of each
+// supplied exchange
+func logExchangeStartupSummary(exchanges []exchange.IBotExchange) {
+	log.Debugln("Exchange startup summary:")
+	for x := range exchanges {
+		status, err := GetExchangeHealth(exchanges[x].GetName())
+		if err != nil {
+			continue
+		}
+
+		if status.Error != "" {
+			log.Warnf("%s: %s after %v. Error: %s", status.Exchange, status.Status,
+				status.StartupDuration, status.Error)
+			continue
+		}
+		log.Debugf("%s: %s after %v.", status.Exchange, status.Status,
+			status.StartupDuration)
+	}
+}
+
+// setExchangeHealth records the startup status of an exchange
+func setExchangeHealth(name, status string, duration time.Duration, err error) {
+	h := ExchangeHealth{
+		Exchange:        name,
+		Status:          status,
+		StartupDuration: duration,
+	}
+	if err != nil {
+		h.Error = err.Error()
+	}
+
+	exchangeHealthMtx.Lock()
+	exchangeHealth[common.StringToLower(name)] = h
+	exchangeHealthMtx.Unlock()
+}
+
+// setExchangeBalanceRefreshed records the time an exchange's account balances
+// were last successfully refreshed
+func setExchangeBalanceRefreshed(name string, refreshed time.Time) {
+	exchangeHealthMtx.Lock()
+	defer exchangeHealthMtx.Unlock()
+	h, ok := exchangeHealth[common.StringToLower(name)]
+	if !ok {
+		return
+	}
+	h.LastBalanceRefresh = refreshed
+	exchangeHealth[common.StringToLower(name)] = h
+}
+
+// GetExchangeHealth returns the startup status of an exchange by name
+func GetExchangeHealth(name string) (ExchangeHealth, error) {
+	exchangeHealthMtx.RLock()
+	defer exchangeHealthMtx.RUnlock()
+	h, ok := exchangeHealth[common.StringToLower(name)]
+	if !ok {
+		return ExchangeHealth{}, ErrExchangeHealthUnknown
+	}
+	h.TickerCount = ticker.GetTickerCounts()[h.Exchange]
+	h.OrderbookCount = orderbook.GetOrderbookCounts()[h.Exchange]
+	h.RejectedTickers = ticker.GetRejectedCounts()[h.Exchange]
+	h.RateLimit = exchangeRateLimitStatus(h.Exchange)
+	h.Latency = exchangeLatency(h.Exchange)
+	h.Timeouts = exchangeTimeouts(h.Exchange)
+	h.Endpoints = exchangeEndpoints(h.Exchange)
+	h.AuthEnabled = isAuthAPISupported(GetExchangeByName(h.Exchange))
+	return h, nil
+}
+
+// exchangeRateLimitStatus returns the rate limit consumption reported by a
+// loaded exchange, nil if it hasn't reported any
+func exchangeRateLimitStatus(name string) *request.RateLimitStatus {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return nil
+	}
+	status := exch.GetRateLimitStatus()
+	if status.Updated.IsZero() {
+		return nil
+	}
+	return &status
+}
+
+// exchangeTimeouts returns the effective timeouts of a loaded exchange, nil if
+// it isn't loaded
+func exchangeTimeouts(name string) *exchange.Timeouts {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return nil
+	}
+	timeouts := exch.GetTimeouts()
+	return &timeouts
+}
+
+// exchangeEndpoints returns the active endpoints of a loaded exchange, nil if
+// it isn't loaded
+func exchangeEndpoints(name string) *exchange.Endpoints {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return nil
+	}
+	endpoints := exch.GetEndpoints()
+	return &endpoints
+}
+
+// GetAllExchangeHealth returns the startup status of all exchanges which have
+// been started
+func GetAllExchangeHealth() []ExchangeHealth {
+	exchangeHealthMtx.RLock()
+	defer exchangeHealthMtx.RUnlock()
+	tickers := ticker.GetTickerCounts()
+	orderbooks := orderbook.GetOrderbookCounts()
+	rejected := ticker.GetRejectedCounts()
+	result := []ExchangeHealth{}
+	for _, h := range exchangeHealth {
+		h.TickerCount = tickers[h.Exchange]
+		h.OrderbookCount = orderbooks[h.Exchange]
+		h.RejectedTickers = rejected[h.Exchange]
+		h.RateLimit = exchangeRateLimitStatus(h.Exchange)
+		h.Latency = exchangeLatency(h.Exchange)
+		h.Timeouts = exchangeTimeouts(h.Exchange)
+		h.Endpoints = exchangeEndpoints(h.Exchange)
+		h.AuthEnabled = isAuthAPISupported(GetExchangeByName(h.Exchange))
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Exchange < result[j].Exchange
+	})
+	return result
+}
+
+// contextExchange is implemented by exchanges whose ticker, orderbook and
+// tradable pair updates can be aborted through a context
+type contextExchange interface {
+	UpdateTickerWithContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error)
+	UpdateOrderbookWithContext(ctx context.Context, p pair.CurrencyPair, assetType string) (orderbook.Base, error)
+	UpdateTradablePairsWithContext(ctx context.Context, forceUpdate bool) error
+}
+
+// updateTickerWithContext updates the exchange ticker, aborting the request
+// when ctx is cancelled if the exchange supports it
+func updateTickerWithContext(ctx context.Context, exch exchange.IBotExchange, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	if ctxExch, ok := exch.(contextExchange); ok {
+		return ctxExch.UpdateTickerWithContext(ctx, p, assetType)
+	}
+	return exch.UpdateTicker(p, assetType)
+}
+
+// updateOrderbookWithContext updates the exchange orderbook, aborting the
+// request when ctx is cancelled if the exchange supports it
+func updateOrderbookWithContext(ctx context.Context, exch exchange.IBotExchange, p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	if ctxExch, ok := exch.(contextExchange); ok {
+		return ctxExch.UpdateOrderbookWithContext(ctx, p, assetType)
+	}
+	return exch.UpdateOrderbook(p, assetType)
+}
+
+// updateTradablePairsWithContext updates the exchange tradable pairs, aborting
+// the request when ctx is cancelled if the exchange supports it
+func updateTradablePairsWithContext(ctx context.Context, exch exchange.IBotExchange, forceUpdate bool) error {
+	if ctxExch, ok := exch.(contextExchange); ok {
+		return ctxExch.UpdateTradablePairsWithContext(ctx, forceUpdate)
+	}
+	return exch.UpdateTradablePairs(forceUpdate)
 }
@@ -1,9 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/bitfinex"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 var testSetup = false
@@ -116,11 +132,18 @@ func TestUnloadExchange(t *testing.T) {
 			err)
 	}
 
+	p := pair.NewCurrencyPair("BTC", "USD")
+	ticker.ProcessTicker("Bitfinex", p, ticker.Price{Last: 1}, ticker.Spot)
+	orderbook.ProcessOrderbook("Bitfinex", p, orderbook.Base{}, orderbook.Spot)
 	err = UnloadExchange("Bitfinex")
 	if err != nil {
 		t.Errorf("Test failed. TestUnloadExchange: Failed to get exchange. %s",
 			err)
 	}
+	if ticker.GetTickerCounts()["Bitfinex"] != 0 ||
+		orderbook.GetOrderbookCounts()["Bitfinex"] != 0 {
+		t.Error("Test failed. TestUnloadExchange: Market data not removed")
+	}
 
 	err = UnloadExchange("asdf")
 	if err != ErrNoExchangesLoaded {
@@ -131,8 +154,531 @@ func TestUnloadExchange(t *testing.T) {
 	CleanupTest(t)
 }
 
+func TestGetAccountExchange(t *testing.T) {
+	SetupTest(t)
+
+	exchCfg, err := bot.config.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatalf("Test failed. TestGetAccountExchange: %s", err)
+	}
+	origCfg := exchCfg
+	exchCfg.Enabled = true
+	exchCfg.AuthenticatedAPISupport = true
+	exchCfg.APIKey = "RocketMan"
+	exchCfg.APISecret = "Digereedoo"
+	exchCfg.Accounts = []config.APICredentialsConfig{
+		{Label: "hedge", APIKey: "HedgeKey", APISecret: "HedgeSecret"},
+	}
+	err = bot.config.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatalf("Test failed. TestGetAccountExchange: %s", err)
+	}
+	defer func() {
+		origCfg.Enabled = true
+		bot.config.UpdateExchangeConfig(origCfg)
+		ReloadExchange("Bitfinex")
+		CleanupTest(t)
+	}()
+
+	err = ReloadExchange("Bitfinex")
+	if err != nil {
+		t.Fatalf("Test failed. TestGetAccountExchange: %s", err)
+	}
+	exch := GetExchangeByName("Bitfinex")
+
+	accountExch, err := GetAccountExchange(exch, "")
+	if err != nil || accountExch != exch {
+		t.Error("Test failed. TestGetAccountExchange: empty label should return the exchange")
+	}
+
+	accountExch, err = GetAccountExchange(exch, "HEDGE")
+	if err != nil {
+		t.Fatalf("Test failed. TestGetAccountExchange: %s", err)
+	}
+	if accountExch == exch {
+		t.Fatal("Test failed. TestGetAccountExchange: sub-account shares the default instance")
+	}
+	if accountExch.(*bitfinex.Bitfinex).APIKey != "HedgeKey" ||
+		accountExch.(*bitfinex.Bitfinex).APISecret != "HedgeSecret" {
+		t.Error("Test failed. TestGetAccountExchange: sub-account credentials not set")
+	}
+	if exch.(*bitfinex.Bitfinex).APIKey != "RocketMan" {
+		t.Error("Test failed. TestGetAccountExchange: default credentials modified")
+	}
+
+	cached, err := GetAccountExchange(exch, "hedge")
+	if err != nil || cached != accountExch {
+		t.Error("Test failed. TestGetAccountExchange: sub-account instance not reused")
+	}
+
+	_, err = GetAccountExchange(exch, "missing")
+	if err == nil {
+		t.Error("Test failed. TestGetAccountExchange: expected error for unknown account")
+	}
+
+	err = ReloadExchange("Bitfinex")
+	if err != nil {
+		t.Fatalf("Test failed. TestGetAccountExchange: %s", err)
+	}
+	reloaded, err := GetAccountExchange(exch, "hedge")
+	if err != nil || reloaded == accountExch {
+		t.Error("Test failed. TestGetAccountExchange: sub-account instance not cleared on reload")
+	}
+}
+
 func TestSetupExchanges(t *testing.T) {
 	SetupTest(t)
 	SetupExchanges()
 	CleanupTest(t)
 }
+
+// newSlowExchange returns a test exchange taking delay to start
+func newSlowExchange(name string, delay time.Duration) *testExchange {
+	exch := newTestExchange(name)
+	exch.start = func(wg *sync.WaitGroup) {
+		wg.Add(1)
+		go func() {
+			time.Sleep(delay)
+			wg.Done()
+		}()
+	}
+	return exch
+}
+
+func TestStartExchange(t *testing.T) {
+	startExchange(newSlowExchange("FastExchange", 0), time.Second)
+
+	h, err := GetExchangeHealth("fastexchange")
+	if err != nil {
+		t.Fatalf("Test failed. TestStartExchange: %s", err)
+	}
+	if h.Status != ExchangeStatusUp || h.Error != "" {
+		t.Errorf("Test failed. TestStartExchange: Unexpected status %s", h.Status)
+	}
+
+	startExchange(newSlowExchange("SlowExchange", time.Second), time.Millisecond*10)
+
+	h, err = GetExchangeHealth("SlowExchange")
+	if err != nil {
+		t.Fatalf("Test failed. TestStartExchange: %s", err)
+	}
+	if h.Status != ExchangeStatusDegraded || h.Error == "" {
+		t.Errorf("Test failed. TestStartExchange: Unexpected status %s", h.Status)
+	}
+}
+
+func TestStartExchangeLateStartup(t *testing.T) {
+	startExchange(newSlowExchange("LateExchange", time.Millisecond*100), time.Millisecond*10)
+
+	h, err := GetExchangeHealth("LateExchange")
+	if err != nil {
+		t.Fatalf("Test failed. TestStartExchangeLateStartup: %s", err)
+	}
+	if h.Status != ExchangeStatusDegraded {
+		t.Errorf("Test failed. TestStartExchangeLateStartup: Unexpected status %s", h.Status)
+	}
+
+	deadline := time.Now().Add(time.Second * 5)
+	for h.Status != ExchangeStatusUp && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+		h, _ = GetExchangeHealth("LateExchange")
+	}
+	if h.Status != ExchangeStatusUp || h.Error != "" {
+		t.Errorf("Test failed. TestStartExchangeLateStartup: Late startup not marked up, status %s",
+			h.Status)
+	}
+}
+
+// contextStartExchange is a test exchange whose startup requests are aborted
+// through a context
+type contextStartExchange struct {
+	*testExchange
+	startWithContext func(ctx context.Context, wg *sync.WaitGroup)
+}
+
+func (e *contextStartExchange) StartWithContext(ctx context.Context, wg *sync.WaitGroup) {
+	e.startWithContext(ctx, wg)
+}
+
+func TestStartExchangeCancelsRequests(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+
+	// The exchange sends a request to the server on startup and reports its
+	// error, requests sent outside startup share the requester
+	requestErr := make(chan error, 1)
+	exch := &contextStartExchange{testExchange: newTestExchange("RequestingExchange")}
+	exch.base.Requester = request.New(exch.GetName(),
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+	exch.startWithContext = func(ctx context.Context, wg *sync.WaitGroup) {
+		wg.Add(1)
+		go func() {
+			var result interface{}
+			requestErr <- exch.base.SendPayloadWithContext(ctx, http.MethodGet, server.URL,
+				nil, nil, &result, false, false)
+			wg.Done()
+		}()
+	}
+	startExchange(exch, time.Millisecond*50)
+
+	select {
+	case err := <-requestErr:
+		if err != context.Canceled {
+			t.Errorf("Test failed. TestStartExchangeCancelsRequests: expected cancelled request error, got %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. TestStartExchangeCancelsRequests: startup request not cancelled")
+	}
+
+	var result interface{}
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer fast.Close()
+	err := exch.base.SendPayload(http.MethodGet, fast.URL, nil, nil, &result, false, false)
+	if err != nil {
+		t.Errorf("Test failed. TestStartExchangeCancelsRequests: request after startup cancelled %s", err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+	h, err := GetExchangeHealth("RequestingExchange")
+	if err != nil {
+		t.Fatalf("Test failed. TestStartExchangeCancelsRequests: %s", err)
+	}
+	if h.Status != ExchangeStatusDegraded {
+		t.Errorf("Test failed. TestStartExchangeCancelsRequests: Unexpected status %s", h.Status)
+	}
+}
+
+func TestGetExchangeHealth(t *testing.T) {
+	exchangeHealthMtx.Lock()
+	exchangeHealth = make(map[string]ExchangeHealth)
+	exchangeHealthMtx.Unlock()
+
+	_, err := GetExchangeHealth("asdf")
+	if err != ErrExchangeHealthUnknown {
+		t.Errorf("Test failed. TestGetExchangeHealth: Incorrect result: %s", err)
+	}
+
+	setExchangeHealth("Zzz", ExchangeStatusFailed, 0, errors.New("failed"))
+	setExchangeHealth("Aaa", ExchangeStatusUp, time.Second, nil)
+
+	result := GetAllExchangeHealth()
+	if len(result) != 2 {
+		t.Fatalf("Test failed. TestGetExchangeHealth: Unexpected length %d",
+			len(result))
+	}
+	if result[0].Exchange != "Aaa" || result[1].Exchange != "Zzz" {
+		t.Error("Test failed. TestGetExchangeHealth: Results not sorted")
+	}
+	if result[1].Error != "failed" {
+		t.Error("Test failed. TestGetExchangeHealth: Error not recorded")
+	}
+
+	rejected := ticker.GetRejectedCounts()["Aaa"]
+	ticker.ProcessTicker("Aaa", pair.NewCurrencyPair("BTC", "USD"),
+		ticker.Price{Last: 1}, ticker.Spot)
+	ticker.ProcessTicker("Aaa", pair.NewCurrencyPair("LTC", "USD"),
+		ticker.Price{Last: 1}, ticker.Spot)
+	ticker.ProcessTicker("Aaa", pair.NewCurrencyPair("ETH", "USD"),
+		ticker.Price{Last: -1}, ticker.Spot)
+	orderbook.ProcessOrderbook("Aaa", pair.NewCurrencyPair("BTC", "USD"),
+		orderbook.Base{}, orderbook.Spot)
+	defer ticker.RemoveExchangeTickers("Aaa")
+	defer orderbook.RemoveExchangeOrderbooks("Aaa")
+
+	h, err := GetExchangeHealth("aaa")
+	if err != nil {
+		t.Fatalf("Test failed. TestGetExchangeHealth: %s", err)
+	}
+	if h.TickerCount != 2 || h.OrderbookCount != 1 {
+		t.Errorf("Test failed. TestGetExchangeHealth: Unexpected counts %d tickers %d orderbooks",
+			h.TickerCount, h.OrderbookCount)
+	}
+	if h.RejectedTickers != rejected+1 {
+		t.Errorf("Test failed. TestGetExchangeHealth: Expected %d rejected tickers, got %d",
+			rejected+1, h.RejectedTickers)
+	}
+	result = GetAllExchangeHealth()
+	if result[0].TickerCount != 2 || result[1].TickerCount != 0 {
+		t.Error("Test failed. TestGetExchangeHealth: Unexpected ticker counts")
+	}
+	if result[0].RejectedTickers != rejected+1 {
+		t.Error("Test failed. TestGetExchangeHealth: Unexpected rejected ticker count")
+	}
+}
+
+func TestExchangeHealthRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "300")
+		w.Header().Set("X-RateLimit-Remaining", "299")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	exchanges := bot.exchanges
+	defer func() { bot.exchanges = exchanges }()
+	exch := newTestExchange("RateLimitTest")
+	exch.base.SetRateLimitThreshold(-1)
+	bot.exchanges = []exchange.IBotExchange{exch}
+
+	exchangeHealthMtx.Lock()
+	defer func(health map[string]ExchangeHealth) {
+		exchangeHealthMtx.Lock()
+		exchangeHealth = health
+		exchangeHealthMtx.Unlock()
+	}(exchangeHealth)
+	exchangeHealth = make(map[string]ExchangeHealth)
+	exchangeHealthMtx.Unlock()
+	setExchangeHealth("RateLimitTest", ExchangeStatusUp, 0, nil)
+
+	h, err := GetExchangeHealth("RateLimitTest")
+	if err != nil || h.RateLimit != nil || h.Latency != nil {
+		t.Errorf("Test failed. TestExchangeHealthRateLimit: Unexpected rate limit %+v latency %+v %v",
+			h.RateLimit, h.Latency, err)
+	}
+
+	err = exch.base.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := GetAllExchangeHealth()
+	if len(result) != 1 || result[0].RateLimit == nil ||
+		result[0].RateLimit.Limit != 300 || result[0].RateLimit.Remaining != 299 {
+		t.Errorf("Test failed. TestExchangeHealthRateLimit: Unexpected health %+v", result)
+	}
+	if len(result) != 1 || result[0].Latency[request.EndpointPublic].Rolling.Count != 1 {
+		t.Errorf("Test failed. TestExchangeHealthRateLimit: Unexpected latency %+v",
+			result[0].Latency)
+	}
+
+	exch.base.SetHTTPClientTimeout(time.Second * 5)
+	h, err = GetExchangeHealth("RateLimitTest")
+	if err != nil || h.Timeouts == nil || h.Timeouts.HTTP != time.Second*5 ||
+		h.Timeouts.TLSHandshake != time.Second*5 || h.Timeouts.ResponseHeader != time.Second*5 {
+		t.Errorf("Test failed. TestExchangeHealthRateLimit: Unexpected timeouts %+v %v",
+			h.Timeouts, err)
+	}
+}
+
+func TestFixtureCaptureDir(t *testing.T) {
+	defer func(dataDir, env string) {
+		bot.settings.DataDir = dataDir
+		os.Setenv(captureFixturesEnv, env)
+	}(bot.settings.DataDir, os.Getenv(captureFixturesEnv))
+	bot.settings.DataDir = "data"
+	os.Setenv(captureFixturesEnv, "")
+
+	exchCfg := config.ExchangeConfig{Name: "Bitstamp"}
+	if dir := fixtureCaptureDir(&exchCfg); dir != "" {
+		t.Errorf("Test failed. TestFixtureCaptureDir: Capture enabled to %s", dir)
+	}
+
+	expected := filepath.Join("data", fixturesDir, "Bitstamp")
+	exchCfg.CaptureFixtures = true
+	if dir := fixtureCaptureDir(&exchCfg); dir != expected {
+		t.Errorf("Test failed. TestFixtureCaptureDir: Expected %s, got %s", expected, dir)
+	}
+
+	exchCfg.CaptureFixtures = false
+	for env, enabled := range map[string]bool{
+		"bitfinex, bitstamp": true,
+		"ALL":                true,
+		"bitfinex":           false,
+	} {
+		os.Setenv(captureFixturesEnv, env)
+		if dir := fixtureCaptureDir(&exchCfg); (dir != "") != enabled {
+			t.Errorf("Test failed. TestFixtureCaptureDir: %s=%s capture dir %q",
+				captureFixturesEnv, env, dir)
+		}
+	}
+}
+
+func TestExchangeDefaultConfig(t *testing.T) {
+	defer func(defaults func(string) (config.ExchangeConfig, error)) {
+		config.ExchangeDefaults = defaults
+	}(config.ExchangeDefaults)
+	config.ExchangeDefaults = exchangeDefaultConfig
+
+	var withPairs []string
+	for _, name := range supportedExchanges {
+		exchCfg, err := exchangeDefaultConfig(common.StringToUpper(name))
+		if err != nil {
+			t.Errorf("Test failed. TestExchangeDefaultConfig: %s error: %s", name, err)
+			continue
+		}
+		if exchCfg.Name != name || exchCfg.ConfigCurrencyPairFormat == nil ||
+			exchCfg.RequestCurrencyPairFormat == nil || exchCfg.HTTPTimeout <= 0 {
+			t.Errorf("Test failed. TestExchangeDefaultConfig: Unexpected %s defaults %+v",
+				name, exchCfg)
+		}
+		// OKCoin sets its pairs and pair formats up by config name
+		if common.StringContains(name, "OKCOIN") {
+			continue
+		}
+		if exchCfg.EnabledPairs == "" || exchCfg.AvailablePairs == "" ||
+			exchCfg.BaseCurrencies == "" || exchCfg.AssetTypes == "" {
+			t.Errorf("Test failed. TestExchangeDefaultConfig: %s has no default pairs", name)
+		}
+		withPairs = append(withPairs, name)
+	}
+
+	_, err := exchangeDefaultConfig("blah")
+	if !errors.Is(err, ErrExchangeNotFound) {
+		t.Errorf("Test failed. TestExchangeDefaultConfig: Expected an exchange not found error, got %v",
+			err)
+	}
+
+	// A minimal config of every exchange must pass the config checks with its
+	// default pairs
+	cfg, err := config.GenerateMinimalConfig(withPairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.CheckExchangeConfigValues()
+	if err != nil {
+		t.Fatalf("Test failed. TestExchangeDefaultConfig: Config check error: %s", err)
+	}
+	for x := range cfg.Exchanges {
+		if !cfg.Exchanges[x].Enabled {
+			t.Errorf("Test failed. TestExchangeDefaultConfig: %s disabled by the config checks",
+				cfg.Exchanges[x].Name)
+		}
+	}
+}
+
+func TestSparseConfigStartup(t *testing.T) {
+	defer func(exchanges []exchange.IBotExchange,
+		defaults func(string) (config.ExchangeConfig, error)) {
+		config.Cfg = config.Config{}
+		err := config.Cfg.LoadConfig("./testdata/configtest.json")
+		if err != nil {
+			t.Errorf("Test failed. TestSparseConfigStartup: Failed to restore config: %s", err)
+		}
+		bot.exchanges = exchanges
+		config.ExchangeDefaults = defaults
+	}(bot.exchanges, config.ExchangeDefaults)
+
+	dir, err := ioutil.TempDir("", "gct-sparse-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, config.ConfigFile)
+	err = ioutil.WriteFile(path,
+		[]byte(`{"encryptConfig":-1,"exchanges":[{"name":"Bitstamp","enabled":true}]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Cfg = config.Config{}
+	config.ExchangeDefaults = exchangeDefaultConfig
+	bot.config = &config.Cfg
+	bot.exchanges = nil
+	err = bot.config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Test failed. TestSparseConfigStartup: LoadConfig error: %s", err)
+	}
+
+	exch, err := setupExchange("Bitstamp")
+	if err != nil {
+		t.Fatalf("Test failed. TestSparseConfigStartup: setupExchange error: %s", err)
+	}
+	defaults, err := exchangeDefaultConfig("Bitstamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := pair.PairsToStringArray(exch.GetEnabledCurrencies())
+	if !exch.IsEnabled() || common.JoinStrings(enabled, ",") != defaults.EnabledPairs ||
+		len(exch.GetAvailableCurrencies()) != len(enabled) {
+		t.Errorf("Test failed. TestSparseConfigStartup: Unexpected enabled pairs %v", enabled)
+	}
+	if timeouts := exch.GetTimeouts(); timeouts.HTTP != exchange.DefaultHTTPTimeout {
+		t.Errorf("Test failed. TestSparseConfigStartup: Unexpected HTTP timeout %v",
+			timeouts.HTTP)
+	}
+
+	unconfigured := unconfiguredExchanges()
+	if len(unconfigured) != len(supportedExchanges)-1 ||
+		common.StringDataCompare(unconfigured, "Bitstamp") {
+		t.Errorf("Test failed. TestSparseConfigStartup: Unexpected unconfigured exchanges %v",
+			unconfigured)
+	}
+}
+
+func TestGetAuthAPISupportedExchanges(t *testing.T) {
+	exchanges := bot.exchanges
+	defer func() { bot.exchanges = exchanges }()
+
+	newExchange := func(name string, enabled, auth bool) *testExchange {
+		exch := newTestExchange(name)
+		exch.base.Enabled = enabled
+		exch.base.AuthenticatedAPISupport = auth
+		return exch
+	}
+	bot.exchanges = []exchange.IBotExchange{
+		newExchange("Keys", true, true),
+		newExchange("NoKeys", true, false),
+		newExchange("Disabled", false, true),
+		newExchange("Rejected", true, true),
+		nil,
+	}
+	defer clearConnectivityResult("Rejected")
+	connectivityResultMtx.Lock()
+	connectivityResults["rejected"] = ConnectivityResult{Exchange: "Rejected",
+		Checks: []ConnectivityCheck{{Name: "authenticated", Status: ConnectivityFail,
+			ErrorClass: ConnectivityErrorCredentials}}}
+	connectivityResults["keys"] = ConnectivityResult{Exchange: "Keys",
+		Checks: []ConnectivityCheck{{Name: "authenticated", Status: ConnectivityFail,
+			ErrorClass: ConnectivityErrorTimeout}}}
+	connectivityResultMtx.Unlock()
+	defer clearConnectivityResult("Keys")
+
+	// A failed check which isn't due to the credentials keeps the exchange
+	result := GetAuthAPISupportedExchanges()
+	if len(result) != 1 || result[0].GetName() != "Keys" {
+		t.Errorf("Test failed. TestGetAuthAPISupportedExchanges: Unexpected exchanges %v",
+			result)
+	}
+
+	exchangeHealthMtx.Lock()
+	defer func(health map[string]ExchangeHealth) {
+		exchangeHealthMtx.Lock()
+		exchangeHealth = health
+		exchangeHealthMtx.Unlock()
+	}(exchangeHealth)
+	exchangeHealth = make(map[string]ExchangeHealth)
+	exchangeHealthMtx.Unlock()
+	for _, name := range []string{"Keys", "NoKeys", "Rejected"} {
+		setExchangeHealth(name, ExchangeStatusUp, 0, nil)
+	}
+	for _, h := range GetAllExchangeHealth() {
+		if h.AuthEnabled != (h.Exchange == "Keys") {
+			t.Errorf("Test failed. TestGetAuthAPISupportedExchanges: %s unexpected auth enabled %v",
+				h.Exchange, h.AuthEnabled)
+		}
+	}
+
+	// Reloaded credentials aren't judged by the earlier check
+	clearConnectivityResult("Rejected")
+	result = GetAuthAPISupportedExchanges()
+	if len(result) != 2 || result[1].GetName() != "Rejected" {
+		t.Errorf("Test failed. TestGetAuthAPISupportedExchanges: Unexpected exchanges after reload %v",
+			result)
+	}
+	h, err := GetExchangeHealth("rejected")
+	if err != nil || !h.AuthEnabled {
+		t.Errorf("Test failed. TestGetAuthAPISupportedExchanges: Expected auth enabled, got %+v %v",
+			h, err)
+	}
+}
@@ -515,7 +515,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.USD,
 	}
-	response, err := a.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     1,
+		Amount:    1,
+	}
+	response, err := a.SubmitOrder(orderSubmission)
 	if !areTestAPIKeysSet(a) && err == nil {
 		t.Errorf("Expecting an error when no keys are set: %v", err)
 	}
@@ -2,8 +2,8 @@ package alphapoint
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -54,7 +54,10 @@ func (a *Alphapoint) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker
 	tickerPrice.High = tick.High
 	tickerPrice.Volume = tick.Volume
 	tickerPrice.Last = tick.Last
-	ticker.ProcessTicker(a.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(a.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(a.Name, p, assetType)
 }
 
@@ -107,7 +110,7 @@ func (a *Alphapoint) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (a *Alphapoint) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (a *Alphapoint) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
@@ -115,12 +118,16 @@ func (a *Alphapoint) GetExchangeHistory(p pair.CurrencyPair, assetType string) (
 
 // SubmitOrder submits a new order and returns a true value when
 // successfully submitted
-func (a *Alphapoint) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (a *Alphapoint) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(a.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
 
-	response, err := a.CreateOrder(p.Pair().String(), side.ToString(), orderType.ToString(), amount, price)
+	response, err := a.CreateOrder(order.Pair.Pair().String(), order.OrderSide.ToString(), order.OrderType.ToString(), order.Amount, order.Price)
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -154,7 +161,12 @@ func (a *Alphapoint) CancelAllOrders(orderCancellation exchange.OrderCancellatio
 }
 
 // GetOrderInfo returns information on a current open order
-func (a *Alphapoint) GetOrderInfo(orderID int64) (float64, error) {
+func (a *Alphapoint) GetOrderInfo(orderID string) (float64, error) {
+	id, err := exchange.ParseNumericOrderID(orderID)
+	if err != nil {
+		return 0, err
+	}
+
 	orders, err := a.GetOrders()
 	if err != nil {
 		return 0, err
@@ -162,7 +174,7 @@ func (a *Alphapoint) GetOrderInfo(orderID int64) (float64, error) {
 
 	for x := range orders {
 		for y := range orders[x].Openorders {
-			if int64(orders[x].Openorders[y].Serverorderid) == orderID {
+			if int64(orders[x].Openorders[y].Serverorderid) == id {
 				return float64(orders[x].Openorders[y].QtyRemaining), nil
 			}
 		}
@@ -171,18 +183,20 @@ func (a *Alphapoint) GetOrderInfo(orderID int64) (float64, error) {
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (a *Alphapoint) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	addreses, err := a.GetDepositAddresses()
-	if err != nil {
-		return "", err
-	}
+func (a *Alphapoint) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return a.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		addreses, err := a.GetDepositAddresses()
+		if err != nil {
+			return "", err
+		}
 
-	for x := range addreses {
-		if addreses[x].Name == cryptocurrency.String() {
-			return addreses[x].DepositAddress, nil
+		for x := range addreses {
+			if addreses[x].Name == cryptocurrency.String() {
+				return addreses[x].DepositAddress, nil
+			}
 		}
-	}
-	return "", errors.New("associated currency address not found")
+		return "", errors.New("associated currency address not found")
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
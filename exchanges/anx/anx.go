@@ -2,6 +2,7 @@ package anx
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -37,6 +38,10 @@ const (
 	// ANX rate limites for authenticated and unauthenticated requests
 	anxAuthRate   = 0
 	anxUnauthRate = 0
+
+	// anxPairFormatVersion is increased whenever the config pair format
+	// changes
+	anxPairFormatVersion = 1
 )
 
 // ANX is the overarching type across the alphapoint package
@@ -63,6 +68,11 @@ func (a *ANX) SetDefaults() {
 		exchange.WithdrawCryptoWith2FA |
 		exchange.WithdrawFiatViaWebsiteOnly
 	a.AssetTypes = []string{ticker.Spot}
+	a.BaseCurrencies = []string{"USD", "HKD", "EUR", "CAD", "AUD", "SGD", "JPY",
+		"GBP", "NZD"}
+	a.EnabledPairs = []string{"BTC_USD", "BTC_HKD", "BTC_EUR", "BTC_CAD",
+		"BTC_AUD", "BTC_SGD", "BTC_JPY", "BTC_GBP", "BTC_NZD", "LTC_BTC",
+		"STR_BTC", "XRP_BTC"}
 	a.SupportsAutoPairUpdating = true
 	a.SupportsRESTTickerBatching = false
 	a.Requester = request.New(a.Name,
@@ -416,21 +426,23 @@ func (a *ANX) SendAuthenticatedHTTPRequest(path string, params map[string]interf
 		a.Nonce.Inc()
 	}
 
-	request := make(map[string]interface{})
-	request["nonce"] = a.Nonce.String()[0:13]
+	req := make(map[string]interface{})
+	req["nonce"] = a.Nonce.String()[0:13]
 	path = fmt.Sprintf("api/%s/%s", anxAPIVersion, path)
 
 	for key, value := range params {
-		request[key] = value
+		req[key] = value
 	}
 
-	PayloadJSON, err := common.JSONEncode(request)
+	PayloadJSON, err := common.JSONEncode(req)
 	if err != nil {
 		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 	}
 
+	ctx, id := request.WithRequestID(context.Background())
 	if a.Verbose {
-		log.Debugf("Request JSON: %s\n", PayloadJSON)
+		log.Debugf("%s request %s. Request JSON: %s", a.Name, id,
+			request.ScrubBody(string(PayloadJSON)))
 	}
 
 	hmac := common.GetHMAC(common.HashSHA512, []byte(path+string("\x00")+string(PayloadJSON)), []byte(a.APISecret))
@@ -439,7 +451,7 @@ func (a *ANX) SendAuthenticatedHTTPRequest(path string, params map[string]interf
 	headers["Rest-Sign"] = common.Base64Encode(hmac)
 	headers["Content-Type"] = "application/json"
 
-	return a.SendPayload("POST", a.APIUrl+path, headers, bytes.NewBuffer(PayloadJSON), result, true, a.Verbose)
+	return a.SendPayloadWithContext(ctx, "POST", a.APIUrl+path, headers, bytes.NewBuffer(PayloadJSON), result, true, a.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
@@ -1,13 +1,18 @@
 package anx
 
 import (
+	"net/http"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/fixtures"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
 )
 
 // Please supply your own keys here for due diligence testing
@@ -84,6 +89,45 @@ func TestSetup(t *testing.T) {
 	}
 }
 
+func TestMigratePairFormat(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+	exchCfg, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal("Test Failed - ANX GetExchangeConfig() error", err)
+	}
+	exchCfg.AvailablePairs = "BTCUSD,LTCBTC,DOGE-BTC,BTCHKD"
+	exchCfg.EnabledPairs = "BTCUSD,DOGEBTC,BTCHKD"
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var migrate ANX
+	migrate.SetDefaults()
+	migrate.Setup(exchCfg)
+	if migrate.migratePairFormat() {
+		t.Fatal("Test Failed - ANX migratePairFormat() reset the pairs")
+	}
+	if !reflect.DeepEqual(migrate.EnabledPairs, []string{"BTC_USD", "DOGE_BTC", "BTC_HKD"}) {
+		t.Errorf("Test Failed - ANX migratePairFormat() enabled pairs %v",
+			migrate.EnabledPairs)
+	}
+	if !common.StringDataCompare(migrate.AvailablePairs, "DOGE_BTC") {
+		t.Errorf("Test Failed - ANX migratePairFormat() available pairs %v",
+			migrate.AvailablePairs)
+	}
+
+	exchCfg, err = cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchCfg.PairFormatVersion != anxPairFormatVersion {
+		t.Errorf("Test Failed - ANX migratePairFormat() config version %d",
+			exchCfg.PairFormatVersion)
+	}
+}
+
 func TestGetCurrencies(t *testing.T) {
 	_, err := a.GetCurrencies()
 	if err != nil {
@@ -251,7 +295,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.USD,
 	}
-	response, err := a.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     1,
+		Amount:    1,
+	}
+	response, err := a.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -398,14 +449,111 @@ func TestWithdrawInternationalBank(t *testing.T) {
 
 func TestGetDepositAddress(t *testing.T) {
 	if areTestAPIKeysSet() {
-		_, err := a.GetDepositAddress(symbol.BTC, "")
+		_, err := a.GetDepositAddress(symbol.BTC, "", false)
 		if err != nil {
 			t.Error("Test Failed - GetDepositAddress() error", err)
 		}
 	} else {
-		_, err := a.GetDepositAddress(symbol.BTC, "")
+		_, err := a.GetDepositAddress(symbol.BTC, "", false)
 		if err == nil {
 			t.Error("Test Failed - GetDepositAddress() error cannot be nil")
 		}
 	}
 }
+
+// newReplayANX returns an ANX instance whose requests are answered by the
+// captured fixtures in the directory
+func newReplayANX(t *testing.T, dir string) (*ANX, *fixtures.Server) {
+	srv, err := fixtures.NewServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var x ANX
+	x.SetDefaults()
+	x.APIUrl = srv.URL + "/"
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+	return &x, srv
+}
+
+func TestGetOrderDetail(t *testing.T) {
+	x, srv := newReplayANX(t, "testdata/fixtures")
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		orderID  string
+		expected exchange.OrderDetail
+	}{
+		{
+			name:    "filled",
+			orderID: "7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01",
+			expected: exchange.OrderDetail{Exchange: "ANX",
+				ID: "7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01", BaseCurrency: "BTC",
+				QuoteCurrency: "USD", OrderSide: "Buy", OrderType: "LIMIT",
+				CreationTime: 1539077390000, Status: "Filled", Price: 6500, Amount: 1.5,
+				ExecutedAmount: 1.5, AveragePrice: 6498.5},
+		},
+		{
+			name:    "partially filled",
+			orderID: "5d9e1c2a-6f47-4e8b-9b0d-2a7c4e6f8b02",
+			expected: exchange.OrderDetail{Exchange: "ANX",
+				ID: "5d9e1c2a-6f47-4e8b-9b0d-2a7c4e6f8b02", BaseCurrency: "BTC",
+				QuoteCurrency: "USD", OrderSide: "Sell", OrderType: "LIMIT",
+				CreationTime: 1539077390000, Status: "PartiallyFilled", Price: 6500,
+				Amount: 2, OpenVolume: 1.25, ExecutedAmount: 0.75, AveragePrice: 6500},
+		},
+		{
+			name:    "cancelled",
+			orderID: "0c4a8e6b-2d19-4f7a-8e3c-5b1d7f9a0c03",
+			expected: exchange.OrderDetail{Exchange: "ANX",
+				ID: "0c4a8e6b-2d19-4f7a-8e3c-5b1d7f9a0c03", BaseCurrency: "BTC",
+				QuoteCurrency: "USD", OrderSide: "Buy", OrderType: "LIMIT",
+				CreationTime: 1539077390000, Status: "Cancelled", Price: 6500, Amount: 1,
+				OpenVolume: 0.6, ExecutedAmount: 0.4, AveragePrice: 6499},
+		},
+	}
+
+	for _, test := range tests {
+		detail, err := x.GetOrderInfo(test.orderID)
+		if err != nil {
+			t.Fatalf("Test Failed - %s GetOrderInfo() error %v", test.name, err)
+		}
+		// Compare the executed amount separately to allow for float rounding
+		if diff := detail.ExecutedAmount - test.expected.ExecutedAmount; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("Test Failed - %s GetOrderInfo() expected executed amount %v, got %v",
+				test.name, test.expected.ExecutedAmount, detail.ExecutedAmount)
+		}
+		detail.ExecutedAmount = test.expected.ExecutedAmount
+		if detail != test.expected {
+			t.Errorf("Test Failed - %s GetOrderInfo() expected %+v, got %+v",
+				test.name, test.expected, detail)
+		}
+	}
+}
+
+func TestGetActiveOrderDetails(t *testing.T) {
+	x, srv := newReplayANX(t, "testdata/fixtures")
+	defer srv.Close()
+
+	orders, err := x.GetActiveOrderDetails()
+	if err != nil {
+		t.Fatal("Test Failed - GetActiveOrderDetails() error", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("Test Failed - GetActiveOrderDetails() expected 2 orders, got %d", len(orders))
+	}
+	if orders[0].Status != "PartiallyFilled" || orders[0].OpenVolume != 1.25 {
+		t.Errorf("Test Failed - GetActiveOrderDetails() unexpected order %+v", orders[0])
+	}
+	if orders[1].ID != "9e3b7d1f-4c28-4a6e-b5f0-8d2c6a4e1b04" || orders[1].Status != "Active" ||
+		orders[1].OrderSide != "Buy" || orders[1].BaseCurrency != "ETH" ||
+		orders[1].OpenVolume != 10 || orders[1].ExecutedAmount != 0 ||
+		orders[1].AveragePrice != 0 {
+		t.Errorf("Test Failed - GetActiveOrderDetails() unexpected order %+v", orders[1])
+	}
+}
@@ -9,6 +9,16 @@ const (
 	CancelOrderWrongState  string = "ORDER_CANCEL_WRONG_STATE"
 )
 
+// Order statuses returned by the order list and info endpoints
+const (
+	OrderStatusPlacePending  = "PLACE_PENDING"
+	OrderStatusActive        = "ACTIVE"
+	OrderStatusPartialFill   = "PARTIAL_FILL"
+	OrderStatusFullFill      = "FULL_FILL"
+	OrderStatusCancelPending = "CANCEL_PENDING"
+	OrderStatusCancelled     = "CANCELLED"
+)
+
 // Currency holds the currency information
 type Currency struct {
 	Decimals               int     `json:"decimals"`
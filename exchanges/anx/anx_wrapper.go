@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -29,29 +30,34 @@ func (a *ANX) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", a.GetName(), len(a.EnabledPairs), a.EnabledPairs)
 	}
 
-	exchangeProducts, err := a.GetTradablePairs()
+	forceUpgrade := a.migratePairFormat()
+	err := a.UpdateTradablePairs(forceUpgrade)
 	if err != nil {
-		log.Debugf("%s Failed to get available symbols.\n", a.GetName())
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(a.EnabledPairs, "_") || !common.StringDataContains(a.AvailablePairs, "_") {
-			forceUpgrade = true
-		}
+		log.Errorf("%s failed to update tradable pairs. Err: %s", a.Name, err)
+	}
+}
 
-		if forceUpgrade {
-			enabledPairs := []string{"BTC_USD,BTC_HKD,BTC_EUR,BTC_CAD,BTC_AUD,BTC_SGD,BTC_JPY,BTC_GBP,BTC_NZD,LTC_BTC,DOG_EBTC,STR_BTC,XRP_BTC"}
-			log.Warn("Enabled pairs for ANX reset due to config upgrade, please enable the ones you would like again.")
+// migratePairFormat reformats the stored pairs from an older config pair
+// format and returns whether the pairs were reset and need a forced update
+func (a *ANX) migratePairFormat() bool {
+	reset, err := a.MigratePairFormat(anxPairFormatVersion, []string{"BTC_USD",
+		"BTC_HKD", "BTC_EUR", "BTC_CAD", "BTC_AUD", "BTC_SGD", "BTC_JPY",
+		"BTC_GBP", "BTC_NZD", "LTC_BTC", "DOGE_BTC", "STR_BTC", "XRP_BTC"})
+	if err != nil {
+		log.Errorf("%s failed to migrate pair format. Err: %s", a.Name, err)
+	}
+	return reset
+}
 
-			err = a.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to get config.\n", a.GetName())
-			}
-		}
-		err = a.UpdateCurrencies(exchangeProducts, false, forceUpgrade)
-		if err != nil {
-			log.Errorf("%s Failed to get config.\n", a.GetName())
-		}
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (a *ANX) UpdateTradablePairs(forceUpdate bool) error {
+	exchangeProducts, err := a.GetTradablePairs()
+	if err != nil {
+		return err
 	}
+
+	return a.UpdateCurrencies(exchangeProducts, false, forceUpdate)
 }
 
 // GetTradablePairs returns a list of available
@@ -132,7 +138,10 @@ func (a *ANX) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price,
 	} else {
 		tickerPrice.High = 0
 	}
-	ticker.ProcessTicker(a.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(a.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(a.Name, p, assetType)
 }
 
@@ -215,33 +224,37 @@ func (a *ANX) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (a *ANX) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (a *ANX) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (a *ANX) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (a *ANX) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(a.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
 
 	var isBuying bool
 	var limitPriceInSettlementCurrency float64
 
-	if side == exchange.Buy {
+	if order.OrderSide == exchange.Buy {
 		isBuying = true
 	}
 
-	if orderType == exchange.Limit {
-		limitPriceInSettlementCurrency = price
+	if order.OrderType == exchange.Limit {
+		limitPriceInSettlementCurrency = order.Price
 	}
 
-	response, err := a.NewOrder(orderType.ToString(),
+	response, err := a.NewOrder(order.OrderType.ToString(),
 		isBuying,
-		p.FirstCurrency.String(),
-		amount,
-		p.SecondCurrency.String(),
-		amount,
+		order.Pair.FirstCurrency.String(),
+		order.Amount,
+		order.Pair.SecondCurrency.String(),
+		order.Amount,
 		limitPriceInSettlementCurrency,
 		false,
 		"",
@@ -300,15 +313,105 @@ func (a *ANX) CancelAllOrders(orderCancellation exchange.OrderCancellation) (exc
 	return cancelAllOrdersResponse, err
 }
 
-// GetOrderInfo returns information on a current open order
-func (a *ANX) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
-	var orderDetail exchange.OrderDetail
-	return orderDetail, common.ErrNotYetImplemented
+// GetOrderInfo returns information on an order by its UUID
+func (a *ANX) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	order, err := a.OrderInfo(orderID)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+	return a.convertOrder(order)
+}
+
+// GetActiveOrderDetails returns the open orders of the account
+func (a *ANX) GetActiveOrderDetails() ([]exchange.OrderDetail, error) {
+	orders, err := a.GetOrderList(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderDetails []exchange.OrderDetail
+	for x := range orders {
+		detail, err := a.convertOrder(orders[x])
+		if err != nil {
+			return nil, err
+		}
+		orderDetails = append(orderDetails, detail)
+	}
+	return orderDetails, nil
+}
+
+// convertOrder maps an ANX order to an order detail, amounts are in the
+// traded currency and prices in the settlement currency
+func (a *ANX) convertOrder(order OrderResponse) (exchange.OrderDetail, error) {
+	amount, err := parseOrderFloat(order.TradedCurrencyAmount)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+	outstanding, err := parseOrderFloat(order.TradedCurrencyOutstanding)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+	price, err := parseOrderFloat(order.LimitPriceInSettlementCurrency)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+	averagePrice, err := parseOrderFloat(order.ExecutedAverageRate)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	side := exchange.Sell
+	if order.BuyTradedCurrency {
+		side = exchange.Buy
+	}
+
+	return exchange.OrderDetail{
+		Exchange:       a.GetName(),
+		ID:             order.OrderID,
+		BaseCurrency:   order.TradedCurrency,
+		QuoteCurrency:  order.SettlementCurrency,
+		OrderSide:      side.ToString(),
+		OrderType:      order.OrderType,
+		CreationTime:   order.Timestamp,
+		Status:         normaliseOrderStatus(order.OrderStatus),
+		Price:          price,
+		Amount:         amount,
+		OpenVolume:     outstanding,
+		ExecutedAmount: amount - outstanding,
+		AveragePrice:   averagePrice,
+	}, nil
+}
+
+// normaliseOrderStatus maps an ANX order status to the statuses shared across
+// exchanges, unknown statuses are returned unchanged
+func normaliseOrderStatus(status string) string {
+	switch status {
+	case OrderStatusPlacePending, OrderStatusActive, OrderStatusCancelPending:
+		return "Active"
+	case OrderStatusPartialFill:
+		return "PartiallyFilled"
+	case OrderStatusFullFill:
+		return "Filled"
+	case OrderStatusCancelled:
+		return "Cancelled"
+	}
+	return status
+}
+
+// parseOrderFloat parses an order amount or price, ANX omits the values it
+// doesn't report
+func parseOrderFloat(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (a *ANX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	return a.GetDepositAddressByCurrency(cryptocurrency.String(), "", false)
+func (a *ANX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return a.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		return a.GetDepositAddressByCurrency(cryptocurrency.String(), "", false)
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
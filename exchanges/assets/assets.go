@@ -0,0 +1,34 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Asset types used by the exchanges, each exchange declares the asset types
+// it supports
+const (
+	Spot     = "SPOT"
+	ThisWeek = "this_week"
+	NextWeek = "next_week"
+	Quarter  = "quarter"
+)
+
+// ErrInvalidAssetType is returned when an asset type string does not match a
+// supported asset type
+var ErrInvalidAssetType = errors.New("invalid asset type")
+
+// Parse validates a single asset type against a list of asset types, such as
+// those supported by an exchange, matching case insensitively and returns its
+// canonical form from that list
+func Parse(assetType string, assetTypes []string) (string, error) {
+	for x := range assetTypes {
+		if common.StringToLower(assetType) == common.StringToLower(assetTypes[x]) {
+			return assetTypes[x], nil
+		}
+	}
+	return "", fmt.Errorf("%w %q, supported asset types: %s",
+		ErrInvalidAssetType, assetType, common.JoinStrings(assetTypes, ", "))
+}
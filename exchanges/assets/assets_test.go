@@ -0,0 +1,36 @@
+package assets
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, input := range []string{"SPOT", "spot", "Spot"} {
+		a, err := Parse(input, []string{Spot, ThisWeek})
+		if err != nil || a != Spot {
+			t.Errorf("Test failed. Parse(%q) expected %s, got %s %v", input, Spot, a, err)
+		}
+	}
+
+	a, err := Parse("this_WEEK", []string{Spot, ThisWeek})
+	if err != nil || a != ThisWeek {
+		t.Fatalf("Test failed. Expected %s, got %s %v", ThisWeek, a, err)
+	}
+
+	for _, input := range []string{"", "Spot ", "spots", ThisWeek} {
+		_, err = Parse(input, []string{Spot})
+		if !errors.Is(err, ErrInvalidAssetType) {
+			t.Errorf("Test failed. Parse(%q) expected invalid asset type error, got %v",
+				input, err)
+		}
+	}
+
+	_, err = Parse(Quarter, []string{Spot})
+	if !errors.Is(err, ErrInvalidAssetType) {
+		t.Fatalf("Test failed. Expected invalid asset type error, got %v", err)
+	}
+	if err.Error() != `invalid asset type "quarter", supported asset types: SPOT` {
+		t.Fatalf("Test failed. Unexpected error message: %s", err)
+	}
+}
@@ -80,6 +80,9 @@ func (b *Binance) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = "-"
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"USD"}
+	b.EnabledPairs = []string{"BTC-USDT", "ETH-USDT", "LTC-USDT", "ADA-USDT",
+		"XRP-USDT"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = true
 	b.APIWithdrawPermissions = exchange.AutoWithdrawCrypto |
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -129,8 +128,11 @@ func (b *Binance) WSConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var Dialer websocket.Dialer
-	var err error
+	dialer, err := b.Websocket.Dialer()
+	if err != nil {
+		return fmt.Errorf("binance_websocket.go - Unable to connect to parse proxy address. Error: %s",
+			err)
+	}
 
 	ticker := strings.ToLower(
 		strings.Replace(
@@ -155,17 +157,6 @@ func (b *Binance) WSConnect() error {
 		"/" +
 		depth
 
-	if b.Websocket.GetProxyAddress() != "" {
-		var u *url.URL
-		u, err = url.Parse(b.Websocket.GetProxyAddress())
-		if err != nil {
-			return fmt.Errorf("binance_websocket.go - Unable to connect to parse proxy address. Error: %s",
-				err)
-		}
-
-		Dialer.Proxy = http.ProxyURL(u)
-	}
-
 	for _, ePair := range b.GetEnabledCurrencies() {
 		err = b.SeedLocalCache(ePair)
 		if err != nil {
@@ -173,7 +164,7 @@ func (b *Binance) WSConnect() error {
 		}
 	}
 
-	b.WebsocketConn, _, err = Dialer.Dial(wsurl, http.Header{})
+	b.WebsocketConn, _, err = dialer.Dial(wsurl, http.Header{})
 	if err != nil {
 		return fmt.Errorf("binance_websocket.go - Unable to connect to Websocket. Error: %s",
 			err)
@@ -2,9 +2,9 @@ package binance
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -37,30 +37,37 @@ func (b *Binance) Run() {
 			b.EnabledPairs)
 	}
 
-	symbols, err := b.GetExchangeValidCurrencyPairs()
-	if err != nil {
-		log.Errorf("%s Failed to get exchange info.\n", b.GetName())
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(b.EnabledPairs, "-") ||
-			!common.StringDataContains(b.AvailablePairs, "-") {
-			forceUpgrade = true
-		}
+	forceUpgrade := false
+	if !common.StringDataContains(b.EnabledPairs, "-") ||
+		!common.StringDataContains(b.AvailablePairs, "-") {
+		forceUpgrade = true
+	}
 
-		if forceUpgrade {
-			enabledPairs := []string{"BTC-USDT"}
-			log.Warn("Available pairs for Binance reset due to config upgrade, please enable the ones you would like again")
+	if forceUpgrade {
+		enabledPairs := []string{"BTC-USDT"}
+		log.Warn("Available pairs for Binance reset due to config upgrade, please enable the ones you would like again")
 
-			err = b.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to get config.\n", b.GetName())
-			}
-		}
-		err = b.UpdateCurrencies(symbols, false, forceUpgrade)
+		err := b.UpdateCurrencies(enabledPairs, true, true)
 		if err != nil {
-			log.Errorf("%s Failed to get config.\n", b.GetName())
+			log.Errorf("%s failed to update enabled currencies. Err: %s", b.Name, err)
 		}
 	}
+
+	err := b.UpdateTradablePairs(forceUpgrade)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Binance) UpdateTradablePairs(forceUpdate bool) error {
+	symbols, err := b.GetExchangeValidCurrencyPairs()
+	if err != nil {
+		return err
+	}
+
+	return b.UpdateCurrencies(symbols, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -82,7 +89,10 @@ func (b *Binance) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pr
 				tickerPrice.Last = tick[y].LastPrice
 				tickerPrice.Low = tick[y].LowPrice
 				tickerPrice.Volume = tick[y].Volume
-				ticker.ProcessTicker(b.Name, x, tickerPrice, assetType)
+				err = ticker.ProcessTicker(b.Name, x, tickerPrice, assetType)
+				if err != nil {
+					log.Error(err)
+				}
 			}
 		}
 	}
@@ -173,26 +183,30 @@ func (b *Binance) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Binance) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Binance) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *Binance) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *Binance) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
 
 	var sideType RequestParamsSideType
-	if side == exchange.Buy {
+	if order.OrderSide == exchange.Buy {
 		sideType = BinanceRequestParamsSideBuy
 	} else {
 		sideType = BinanceRequestParamsSideSell
 	}
 
 	var requestParamsOrderType RequestParamsOrderType
-	if orderType == exchange.Market {
+	if order.OrderType == exchange.Market {
 		requestParamsOrderType = BinanceRequestParamsOrderMarket
-	} else if orderType == exchange.Limit {
+	} else if order.OrderType == exchange.Limit {
 		requestParamsOrderType = BinanceRequestParamsOrderLimit
 	} else {
 		submitOrderResponse.IsOrderPlaced = false
@@ -200,17 +214,17 @@ func (b *Binance) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orde
 	}
 
 	var orderRequest = NewOrderRequest{
-		Symbol:    p.FirstCurrency.String() + p.SecondCurrency.String(),
+		Symbol:    order.Pair.FirstCurrency.String() + order.Pair.SecondCurrency.String(),
 		Side:      sideType,
-		Price:     price,
-		Quantity:  amount,
+		Price:     order.Price,
+		Quantity:  order.Amount,
 		TradeType: requestParamsOrderType,
 	}
 
 	response, err := b.NewOrder(orderRequest)
 
 	if response.OrderID > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response.OrderID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.OrderID)
 	}
 
 	if err == nil {
@@ -261,14 +275,16 @@ func (b *Binance) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *Binance) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *Binance) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Binance) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	return b.GetDepositAddressForCurrency(cryptocurrency.String())
+func (b *Binance) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return b.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		return b.GetDepositAddressForCurrency(cryptocurrency.String())
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
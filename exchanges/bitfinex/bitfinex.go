@@ -1,6 +1,7 @@
 package bitfinex
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -101,6 +102,8 @@ func (b *Bitfinex) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = ""
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"USD"}
+	b.EnabledPairs = []string{"BTCUSD", "LTCUSD", "LTCBTC", "ETHUSD", "ETHBTC"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = true
 	b.Requester = request.New(b.Name,
@@ -930,21 +933,23 @@ func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[
 		b.Nonce.Inc()
 	}
 
-	request := make(map[string]interface{})
-	request["request"] = fmt.Sprintf("%s%s", bitfinexAPIVersion, path)
-	request["nonce"] = b.Nonce.String()
+	req := make(map[string]interface{})
+	req["request"] = fmt.Sprintf("%s%s", bitfinexAPIVersion, path)
+	req["nonce"] = b.Nonce.String()
 
 	for key, value := range params {
-		request[key] = value
+		req[key] = value
 	}
 
-	PayloadJSON, err := common.JSONEncode(request)
+	PayloadJSON, err := common.JSONEncode(req)
 	if err != nil {
 		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 	}
 
+	ctx, id := request.WithRequestID(context.Background())
 	if b.Verbose {
-		log.Debugf("Request JSON: %s\n", PayloadJSON)
+		log.Debugf("%s request %s. Request JSON: %s", b.Name, id,
+			request.ScrubBody(string(PayloadJSON)))
 	}
 
 	PayloadBase64 := common.Base64Encode(PayloadJSON)
@@ -954,7 +959,7 @@ func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[
 	headers["X-BFX-PAYLOAD"] = PayloadBase64
 	headers["X-BFX-SIGNATURE"] = common.HexEncodeToString(hmac)
 
-	err = b.SendPayload(method, b.APIUrl+bitfinexAPIVersion+path, headers, nil, result, true, b.Verbose)
+	err = b.SendPayloadWithContext(ctx, method, b.APIUrl+bitfinexAPIVersion+path, headers, nil, result, true, b.Verbose)
 	if err != nil {
 		return err
 	}
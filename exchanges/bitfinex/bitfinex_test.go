@@ -730,7 +730,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.LTC,
 		SecondCurrency: symbol.BTC,
 	}
-	response, err := b.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     1,
+		Amount:    1,
+	}
+	response, err := b.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -906,12 +913,12 @@ func TestWithdrawInternationalBank(t *testing.T) {
 
 func TestGetDepositAddress(t *testing.T) {
 	if areTestAPIKeysSet() {
-		_, err := b.GetDepositAddress(symbol.BTC, "deposit")
+		_, err := b.GetDepositAddress(symbol.BTC, "deposit", false)
 		if err != nil {
 			t.Error("Test Failed - GetDepositAddress() error", err)
 		}
 	} else {
-		_, err := b.GetDepositAddress(symbol.BTC, "deposit")
+		_, err := b.GetDepositAddress(symbol.BTC, "deposit", false)
 		if err == nil {
 			t.Error("Test Failed - GetDepositAddress() error cannot be nil")
 		}
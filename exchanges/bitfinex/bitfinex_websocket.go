@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"reflect"
 	"strconv"
 	"time"
@@ -131,19 +130,12 @@ func (b *Bitfinex) WsConnect() error {
 	}
 
 	var channels = []string{"book", "trades", "ticker"}
-	var Dialer websocket.Dialer
-	var err error
-
-	if b.Websocket.GetProxyAddress() != "" {
-		var proxy *url.URL
-		proxy, err = url.Parse(b.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-		Dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := b.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	b.WebsocketConn, _, err = Dialer.Dial(b.Websocket.GetWebsocketURL(), http.Header{})
+	b.WebsocketConn, _, err = dialer.Dial(b.Websocket.GetWebsocketURL(), http.Header{})
 	if err != nil {
 		return fmt.Errorf("Unable to connect to Websocket. Error: %s", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -32,15 +33,21 @@ func (b *Bitfinex) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	err := b.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Bitfinex) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := b.GetSymbols()
 	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", b.GetName())
-	} else {
-		err = b.UpdateCurrencies(exchangeProducts, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available symbols.\n", b.GetName())
-		}
+		return err
 	}
+
+	return b.UpdateCurrencies(exchangeProducts, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -68,7 +75,10 @@ func (b *Bitfinex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.P
 		tick.Last = tickerNew[x].Last
 		tick.Volume = tickerNew[x].Volume
 		tick.High = tickerNew[x].High
-		ticker.ProcessTicker(b.Name, tick.Pair, tick, assetType)
+		err = ticker.ProcessTicker(b.Name, tick.Pair, tick, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(b.Name, p, assetType)
 }
@@ -155,25 +165,30 @@ func (b *Bitfinex) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Bitfinex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Bitfinex) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *Bitfinex) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *Bitfinex) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var isBuying bool
 
-	if side == exchange.Buy {
+	if order.OrderSide == exchange.Buy {
 		isBuying = true
 	}
 
-	response, err := b.NewOrder(p.Pair().String(), amount, price, isBuying, orderType.ToString(), false)
+	response, err := b.NewOrder(order.Pair.Pair().String(), order.Amount, order.Price, isBuying, order.OrderType.ToString(), false)
 
 	if response.OrderID > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response.OrderID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.OrderID)
 	}
 
 	if err == nil {
@@ -209,24 +224,26 @@ func (b *Bitfinex) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *Bitfinex) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *Bitfinex) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Bitfinex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	method, err := b.ConvertSymbolToDepositMethod(cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
+func (b *Bitfinex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return b.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		method, err := b.ConvertSymbolToDepositMethod(cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
 
-	resp, err := b.NewDeposit(method, accountID, 0)
-	if err != nil {
-		return "", err
-	}
+		resp, err := b.NewDeposit(method, accountID, 0)
+		if err != nil {
+			return "", err
+		}
 
-	return resp.Address, nil
+		return resp.Address, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is submitted
@@ -88,6 +88,8 @@ func (b *Bitflyer) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = "_"
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"JPY"}
+	b.EnabledPairs = []string{"BTC_JPY", "ETH_BTC", "BCH_BTC"}
 	b.SupportsAutoPairUpdating = false
 	b.SupportsRESTTickerBatching = false
 	b.Requester = request.New(b.Name,
@@ -285,8 +287,8 @@ func (b *Bitflyer) GetCryptoDepositAddress() {
 	// Needs to be updated
 }
 
-// GetDepositHistory returns a full history of deposits
-func (b *Bitflyer) GetDepositHistory() {
+// GetAccountDepositHistory returns a full history of deposits
+func (b *Bitflyer) GetAccountDepositHistory() {
 	// Needs to be updated
 }
 
@@ -272,7 +272,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.LTC,
 		SecondCurrency: symbol.BTC,
 	}
-	_, err := b.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     1,
+		Amount:    1,
+	}
+	_, err := b.SubmitOrder(orderSubmission)
 	if err != common.ErrNotYetImplemented {
 		t.Errorf("Expected 'Not Yet Implemented', received %v", err)
 	}
@@ -3,6 +3,7 @@ package bitflyer
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -48,6 +49,12 @@ func (b *Bitflyer) Run() {
 	*/
 }
 
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Bitflyer) UpdateTradablePairs(forceUpdate bool) error {
+	return common.ErrFunctionNotSupported
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (b *Bitflyer) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -66,7 +73,10 @@ func (b *Bitflyer) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.P
 	tickerPrice.Last = tickerNew.Last
 	tickerPrice.Volume = tickerNew.Volume
 	// tickerPrice.High
-	ticker.ProcessTicker(b.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(b.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(b.Name, p, assetType)
 }
 
@@ -146,15 +156,19 @@ func (b *Bitflyer) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Bitflyer) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Bitflyer) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *Bitflyer) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *Bitflyer) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
 
 	return submitOrderResponse, common.ErrNotYetImplemented
 }
@@ -178,13 +192,13 @@ func (b *Bitflyer) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *Bitflyer) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *Bitflyer) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Bitflyer) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (b *Bitflyer) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrNotYetImplemented
 }
 
@@ -211,6 +225,11 @@ func (b *Bitflyer) GetWebsocket() (*exchange.Websocket, error) {
 	return nil, common.ErrNotYetImplemented
 }
 
+// GetFeeByType returns an estimate of fee based on type of transaction
+func (b *Bitflyer) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
+	return b.GetFee(feeBuilder)
+}
+
 // GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
 func (b *Bitflyer) GetWithdrawCapabilities() uint32 {
 	return b.GetWithdrawPermissions()
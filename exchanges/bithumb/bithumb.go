@@ -72,6 +72,9 @@ func (b *Bithumb) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.ConfigCurrencyPairFormat.Index = "KRW"
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"KRW"}
+	b.EnabledPairs = []string{"BTCKRW", "ETHKRW", "DASHKRW", "LTCKRW", "ETCKRW",
+		"XRPKRW", "BCHKRW", "XMRKRW", "ZECKRW", "QTUMKRW", "BTGKRW", "EOSKRW"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = true
 	b.Requester = request.New(b.Name,
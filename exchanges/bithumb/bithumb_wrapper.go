@@ -6,6 +6,7 @@ import (
 	"math"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -33,15 +34,21 @@ func (b *Bithumb) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	err := b.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Bithumb) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := b.GetTradingPairs()
 	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", b.GetName())
-	} else {
-		err = b.UpdateCurrencies(exchangeProducts, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available symbols.\n", b.GetName())
-		}
+		return err
 	}
+
+	return b.UpdateCurrencies(exchangeProducts, false, forceUpdate)
 }
 
 // GetTradingPairs gets the available trading currencies
@@ -77,7 +84,10 @@ func (b *Bithumb) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pr
 		tp.Last = tickers[currency].ClosingPrice
 		tp.Volume = tickers[currency].Volume1Day
 		tp.High = tickers[currency].MaxPrice
-		ticker.ProcessTicker(b.Name, x, tp, assetType)
+		err = ticker.ProcessTicker(b.Name, x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(b.Name, p, assetType)
 }
@@ -162,24 +172,28 @@ func (b *Bithumb) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Bithumb) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Bithumb) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *Bithumb) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *Bithumb) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	var err error
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var orderID string
-	if side == exchange.Buy {
+	if order.OrderSide == exchange.Buy {
 		var result MarketBuy
-		result, err = b.MarketBuyOrder(p.FirstCurrency.String(), amount)
+		result, err = b.MarketBuyOrder(order.Pair.FirstCurrency.String(), order.Amount)
 		orderID = result.OrderID
-	} else if side == exchange.Sell {
+	} else if order.OrderSide == exchange.Sell {
 		var result MarketSell
-		result, err = b.MarketSellOrder(p.FirstCurrency.String(), amount)
+		result, err = b.MarketSellOrder(order.Pair.FirstCurrency.String(), order.Amount)
 		orderID = result.OrderID
 	}
 
@@ -242,19 +256,21 @@ func (b *Bithumb) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *Bithumb) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *Bithumb) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Bithumb) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	addr, err := b.GetWalletAddress(cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
+func (b *Bithumb) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return b.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		addr, err := b.GetWalletAddress(cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
 
-	return addr.Data.WalletAddress, nil
+		return addr.Data.WalletAddress, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
@@ -30,6 +30,7 @@ const (
 	bitmexAPItestnetURL = "https://testnet.bitmex.com/api/v1"
 
 	// Public endpoints
+	bitmexEndpointAPIInfo                   = "/"
 	bitmexEndpointAnnouncement              = "/announcement"
 	bitmexEndpointAnnouncementUrgent        = "/announcement/urgent"
 	bitmexEndpointOrderbookL2               = "/orderBook/L2"
@@ -110,6 +111,16 @@ const (
 	ContractUpsideProfit
 )
 
+const (
+	// bitmexWalletHistoryPageSize is the number of wallet history entries
+	// requested per page
+	bitmexWalletHistoryPageSize = 500
+	// bitmexSatoshisPerXBT converts amounts in XBt (satoshi) units to XBT
+	bitmexSatoshisPerXBT = 1e8
+	// bitmexCurrencyXBt is the satoshi denominated currency code
+	bitmexCurrencyXBt = "XBt"
+)
+
 // SetDefaults sets the basic defaults for Bitmex
 func (b *Bitmex) SetDefaults() {
 	b.Name = "Bitmex"
@@ -125,13 +136,22 @@ func (b *Bitmex) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = ""
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"USD"}
+	b.EnabledPairs = []string{"XBTUSD"}
 	b.Requester = request.New(b.Name,
 		request.NewRateLimit(time.Second, bitmexAuthRate),
 		request.NewRateLimit(time.Second, bitmexUnauthRate),
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	b.APIUrlDefault = bitmexAPIURL
 	b.APIUrl = b.APIUrlDefault
+	b.SetSandboxEndpoints(exchange.SandboxEndpoints{
+		APIURL:       bitmexAPItestnetURL,
+		WebsocketURL: bitmexWSTestnetURL,
+	})
 	b.SupportsAutoPairUpdating = true
+	b.SetCurrencyDetails([]exchange.CurrencyDetails{
+		{Currency: bitmexCurrencyXBt, Name: "Bitcoin"},
+	})
 	b.WebsocketInit()
 	b.Websocket.Functionality = exchange.WebsocketTradeDataSupported |
 		exchange.WebsocketOrderbookSupported
@@ -145,6 +165,7 @@ func (b *Bitmex) Setup(exch config.ExchangeConfig) {
 		b.Enabled = true
 		b.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		b.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
+		b.SetHTTPClientTimeout(exch.HTTPTimeout)
 		b.RESTPollingDelay = exch.RESTPollingDelay
 		b.Verbose = exch.Verbose
 		b.Websocket.SetEnabled(exch.Websocket)
@@ -182,6 +203,22 @@ func (b *Bitmex) Setup(exch config.ExchangeConfig) {
 	}
 }
 
+// GetAPIInfo returns the Bitmex API name, version and server timestamp
+func (b *Bitmex) GetAPIInfo() (APIInfo, error) {
+	var info APIInfo
+
+	return info, b.SendHTTPRequest(bitmexEndpointAPIInfo, nil, &info)
+}
+
+// GetServerTime returns the current Bitmex server time
+func (b *Bitmex) GetServerTime() (time.Time, error) {
+	info, err := b.GetAPIInfo()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, info.Timestamp*int64(time.Millisecond)), nil
+}
+
 // GetAnnouncement returns the general announcements from Bitmex
 func (b *Bitmex) GetAnnouncement() ([]Announcement, error) {
 	var announcement []Announcement
@@ -829,6 +866,57 @@ func (b *Bitmex) GetWalletHistory(currency string) ([]TransactionInfo, error) {
 		&info)
 }
 
+// GetAllWalletHistory pages through the user wallet history of a currency,
+// newest first, until an entry older than since is reached or the history is
+// exhausted. A zero since returns the full history. Requests go through the
+// authenticated rate limiter
+func (b *Bitmex) GetAllWalletHistory(currency string, since time.Time) ([]TransactionInfo, error) {
+	var history []TransactionInfo
+	params := WalletHistoryParams{
+		Currency: currency,
+		Count:    bitmexWalletHistoryPageSize,
+	}
+
+	for {
+		var page []TransactionInfo
+		err := b.SendAuthenticatedHTTPRequest("GET",
+			bitmexEndpointUserWalletHistory,
+			params,
+			&page)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page {
+			tm := transactionTime(page[i])
+			if !since.IsZero() && !tm.IsZero() && tm.Before(since) {
+				return history, nil
+			}
+			history = append(history, page[i])
+		}
+
+		if len(page) < bitmexWalletHistoryPageSize {
+			return history, nil
+		}
+		params.Start += bitmexWalletHistoryPageSize
+	}
+}
+
+// transactionTime returns when a wallet transaction happened, falling back to
+// when it was recorded for pending transactions without a transact time
+func transactionTime(t TransactionInfo) time.Time {
+	for _, v := range []string{t.TransactTime, t.Timestamp} {
+		if v == "" {
+			continue
+		}
+		tm, err := time.Parse(time.RFC3339, v)
+		if err == nil {
+			return tm
+		}
+	}
+	return time.Time{}
+}
+
 // GetWalletSummary returns user wallet summary
 func (b *Bitmex) GetWalletSummary(currency string) ([]TransactionInfo, error) {
 	var info []TransactionInfo
@@ -866,11 +954,10 @@ func (b *Bitmex) SendHTTPRequest(path string, params Parameter, result interface
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to bitmex
 func (b *Bitmex) SendAuthenticatedHTTPRequest(verb, path string, params Parameter, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
-		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
-			b.Name)
+		return exchange.NewAuthenticationNotConfiguredError(b.Name)
 	}
 
-	timestamp := time.Now().Add(time.Second * 10).UnixNano()
+	timestamp := b.GetAdjustedTime().Add(time.Second * 10).UnixNano()
 	timestampStr := strconv.FormatInt(timestamp, 10)
 	timestampNew := timestampStr[:13]
 
@@ -892,11 +979,9 @@ func (b *Bitmex) SendAuthenticatedHTTPRequest(verb, path string, params Paramete
 		payload = string(data)
 	}
 
-	hmac := common.GetHMAC(common.HashSHA256,
+	headers["api-signature"] = exchange.SignSHA256HMAC(
 		[]byte(verb+"/api/v1"+path+timestampNew+payload),
-		[]byte(b.APISecret))
-
-	headers["api-signature"] = common.HexEncodeToString(hmac)
+		[]byte(b.APISecret), false)
 
 	var respCheck interface{}
 
@@ -908,7 +993,7 @@ func (b *Bitmex) SendAuthenticatedHTTPRequest(verb, path string, params Paramete
 		true,
 		b.Verbose)
 	if err != nil {
-		return err
+		return exchange.ClassifyRequestError(err)
 	}
 
 	return b.CaptureError(respCheck, result)
@@ -924,7 +1009,7 @@ func (b *Bitmex) CaptureError(resp, reType interface{}) error {
 	}
 
 	err = common.JSONDecode(marshalled, &Error)
-	if err == nil {
+	if err == nil && (Error.Error.Name != "" || Error.Error.Message != "") {
 		return fmt.Errorf("bitmex error %s: %s",
 			Error.Error.Name,
 			Error.Error.Message)
@@ -1037,3 +1037,38 @@ func (p UserRequestWithdrawalParams) ToURLVals(path string) (string, error) {
 func (p UserRequestWithdrawalParams) IsNil() bool {
 	return p == (UserRequestWithdrawalParams{})
 }
+
+// WalletHistoryParams contains all the parameters to send to the API endpoint
+type WalletHistoryParams struct {
+	// Currency - [Optional] currency, defaults to `XBt`
+	Currency string `json:"currency,omitempty"`
+
+	// Count - Number of results to fetch.
+	Count int32 `json:"count,omitempty"`
+
+	// Start - Starting point for results.
+	Start int32 `json:"start,omitempty"`
+}
+
+// VerifyData verifies outgoing data sets
+func (p WalletHistoryParams) VerifyData() error {
+	if p.Count < 0 || p.Start < 0 {
+		return errors.New("verifydata() WalletHistoryParams error - count and start must not be negative")
+	}
+	return nil
+}
+
+// ToURLVals converts struct values to url.values and encodes it on the supplied
+// path
+func (p WalletHistoryParams) ToURLVals(path string) (string, error) {
+	values, err := StructValsToURLVals(&p)
+	if err != nil {
+		return "", err
+	}
+	return common.EncodeURLValues(path, values), nil
+}
+
+// IsNil checks to see if any values has been set for the paramater
+func (p WalletHistoryParams) IsNil() bool {
+	return p == (WalletHistoryParams{})
+}
@@ -1,6 +1,13 @@
 package bitmex
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -10,6 +17,9 @@ import (
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 // Please supply your own keys here for due diligence testing
@@ -40,6 +50,25 @@ func TestSetup(t *testing.T) {
 	b.Setup(bitmexConfig)
 }
 
+func TestSandboxEndpoints(t *testing.T) {
+	var testnet Bitmex
+	testnet.SetDefaults()
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+	bitmexConfig, err := cfg.GetExchangeConfig("Bitmex")
+	if err != nil {
+		t.Fatal("Test Failed - Bitmex Setup() init error")
+	}
+
+	bitmexConfig.UseSandbox = true
+	testnet.Setup(bitmexConfig)
+	endpoints := testnet.GetEndpoints()
+	if !endpoints.Sandbox || endpoints.APIURL != bitmexAPItestnetURL ||
+		endpoints.WebsocketURL != bitmexWSTestnetURL {
+		t.Errorf("Test Failed - Bitmex unexpected sandbox endpoints %+v", endpoints)
+	}
+}
+
 func TestStart(t *testing.T) {
 	var testWg sync.WaitGroup
 	b.Start(&testWg)
@@ -489,7 +518,15 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.XBT,
 		SecondCurrency: symbol.USD,
 	}
-	response, err := b.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     1,
+		Amount:    1,
+		ClientID:  "clientId",
+	}
+	response, err := b.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -575,6 +612,88 @@ func TestGetAccountInfo(t *testing.T) {
 	}
 }
 
+func TestGetAccountInfoMargin(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"account":1234,"currency":"XBt","walletBalance":150000000,
+			"marginBalance":162000000,"availableMargin":100000000,"unrealisedPnl":12000000}]`)
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	info, err := x.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("Test Failed - GetAccountInfo() error: %s", err)
+	}
+	if path != bitmexEndpointUserMargin {
+		t.Errorf("Test Failed - unexpected request path %s", path)
+	}
+	if len(info.Accounts) != 1 || info.Accounts[0].ID != "1234" ||
+		info.Accounts[0].Type != exchange.AccountTypeMargin ||
+		len(info.Accounts[0].Currencies) != 1 {
+		t.Fatalf("Test Failed - unexpected accounts %+v", info.Accounts)
+	}
+	balance := info.Accounts[0].Currencies[0]
+	if balance.CurrencyName != symbol.BTC || balance.TotalValue != 1.5 || balance.Hold != 0 {
+		t.Errorf("Test Failed - unexpected balance %+v", balance)
+	}
+	if balance.AvailableValue == nil || *balance.AvailableValue != 1 ||
+		balance.MarginBalance == nil || *balance.MarginBalance != 1.62 ||
+		balance.UnrealisedPNL == nil || *balance.UnrealisedPNL != 0.12 {
+		t.Errorf("Test Failed - unexpected margin balance %+v", balance)
+	}
+}
+
+func TestGetOpenPositions(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"symbol":"XBTUSD","underlying":"XBT","quoteCurrency":"USD",
+			"currency":"XBt","isOpen":true,"homeNotional":0.5,"foreignNotional":-4000,
+			"unrealisedPnl":2500000},
+			{"symbol":"ETHUSD","underlying":"ETH","quoteCurrency":"USD","currency":"XBt",
+			"isOpen":false}]`)
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	positions, err := x.GetOpenPositions()
+	if err != nil {
+		t.Fatalf("Test Failed - GetOpenPositions() error: %s", err)
+	}
+	if path != bitmexEndpointPosition {
+		t.Errorf("Test Failed - unexpected request path %s", path)
+	}
+	if len(positions) != 1 {
+		t.Fatalf("Test Failed - unexpected positions %+v", positions)
+	}
+	p := positions[0]
+	if p.Pair.Base().String() != "XBT" || p.Pair.Quote().String() != "USD" ||
+		p.BaseAmount != 0.5 || p.QuoteAmount != -4000 ||
+		p.SettlementCurrency != symbol.BTC || p.UnrealisedPNL != 0.025 {
+		t.Errorf("Test Failed - unexpected position %+v", p)
+	}
+}
+
 func TestModifyOrder(t *testing.T) {
 	_, err := b.ModifyOrder(exchange.ModifyOrder{OrderID: "1337"})
 	if err == nil {
@@ -640,14 +759,520 @@ func TestWithdrawInternationalBank(t *testing.T) {
 
 func TestGetDepositAddress(t *testing.T) {
 	if areTestAPIKeysSet() {
-		_, err := b.GetDepositAddress(symbol.BTC, "")
+		_, err := b.GetDepositAddress(symbol.BTC, "", false)
 		if err != nil {
 			t.Error("Test Failed - GetDepositAddress() error", err)
 		}
 	} else {
-		_, err := b.GetDepositAddress(symbol.BTC, "")
+		_, err := b.GetDepositAddress(symbol.BTC, "", false)
 		if err == nil {
 			t.Error("Test Failed - GetDepositAddress() error cannot be nil")
 		}
 	}
 }
+
+func TestAuthenticationNotConfiguredError(t *testing.T) {
+	var x Bitmex
+	x.SetDefaults()
+	x.AuthenticatedAPISupport = false
+	err := x.SendAuthenticatedHTTPRequest("GET", "/apiKey", nil, nil)
+	if !errors.Is(err, exchange.ErrAuthenticationNotConfigured) {
+		t.Errorf("Test failed - expected authentication not configured error, received %v", err)
+	}
+}
+
+func TestNewOrderParams(t *testing.T) {
+	order := &exchange.OrderSubmission{
+		Pair:        pair.NewCurrencyPair(symbol.XBT, symbol.USD),
+		OrderSide:   exchange.Sell,
+		OrderType:   exchange.Limit,
+		Amount:      10,
+		Price:       6500,
+		ClientID:    "abc",
+		TimeInForce: exchange.GTC,
+		PostOnly:    true,
+		ReduceOnly:  true,
+	}
+
+	params := newOrderParams(order, 10, 6500.5)
+	if params.OrdType != "Limit" || params.Side != "Sell" || params.Symbol != "XBTUSD" ||
+		params.OrderQty != 10 || params.Price != 6500.5 || params.ClOrdID != "abc" {
+		t.Errorf("Test failed - unexpected order params %+v", params)
+	}
+	if params.ExecInst != "ParticipateDoNotInitiate,ReduceOnly" {
+		t.Errorf("Test failed - unexpected execInst %s", params.ExecInst)
+	}
+	if params.TimeInForce != "GoodTillCancel" {
+		t.Errorf("Test failed - unexpected timeInForce %s", params.TimeInForce)
+	}
+
+	order.OrderType = exchange.Market
+	order.PostOnly = false
+	order.ReduceOnly = false
+	order.TimeInForce = exchange.IOC
+	params = newOrderParams(order, 10, 6500)
+	if params.Price != 0 || params.ExecInst != "" || params.TimeInForce != "ImmediateOrCancel" {
+		t.Errorf("Test failed - unexpected order params %+v", params)
+	}
+
+	order.TimeInForce = ""
+	params = newOrderParams(order, 10, 6500)
+	if params.TimeInForce != "" {
+		t.Errorf("Test failed - unexpected timeInForce %s", params.TimeInForce)
+	}
+}
+
+func TestNewOrderParamsStops(t *testing.T) {
+	order := &exchange.OrderSubmission{
+		Pair:         pair.NewCurrencyPair(symbol.XBT, symbol.USD),
+		OrderSide:    exchange.Sell,
+		OrderType:    exchange.Stop,
+		Amount:       10,
+		Price:        6400,
+		TriggerPrice: 6450,
+	}
+	params := newOrderParams(order, 10, 6400)
+	if params.OrdType != "Stop" || params.StopPx != 6450 || params.Price != 0 ||
+		params.PegPriceType != "" {
+		t.Errorf("Test failed - unexpected stop order params %+v", params)
+	}
+
+	order.OrderType = exchange.StopLimit
+	params = newOrderParams(order, 10, 6400)
+	if params.OrdType != "StopLimit" || params.StopPx != 6450 || params.Price != 6400 {
+		t.Errorf("Test failed - unexpected stop limit order params %+v", params)
+	}
+
+	order.OrderType = exchange.TrailingStop
+	order.TriggerPrice = 0
+	order.PegOffset = 25
+	params = newOrderParams(order, 10, 6400)
+	if params.OrdType != "Stop" || params.PegPriceType != "TrailingStopPeg" ||
+		params.PegOffsetValue != -25 || params.StopPx != 0 || params.Price != 0 {
+		t.Errorf("Test failed - unexpected trailing stop order params %+v", params)
+	}
+
+	order.OrderSide = exchange.Buy
+	params = newOrderParams(order, 10, 6400)
+	if params.PegOffsetValue != 25 {
+		t.Errorf("Test failed - unexpected buy trailing stop offset %v",
+			params.PegOffsetValue)
+	}
+}
+
+func TestSubmitOrderOptions(t *testing.T) {
+	var x Bitmex
+	x.SetDefaults()
+	order := &exchange.OrderSubmission{
+		Pair:      pair.NewCurrencyPair(symbol.XBT, symbol.USD),
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Amount:    1,
+		PostOnly:  true,
+	}
+	_, err := x.SubmitOrder(order)
+	if err == nil {
+		t.Error("Test failed - expected an error for a post only market order")
+	}
+
+	for _, o := range []exchange.OrderSubmission{
+		{OrderType: exchange.Stop},
+		{OrderType: exchange.StopLimit, Price: 6400},
+		{OrderType: exchange.StopLimit, TriggerPrice: 6450},
+		{OrderType: exchange.TrailingStop},
+	} {
+		o.Pair = pair.NewCurrencyPair(symbol.XBT, symbol.USD)
+		o.OrderSide = exchange.Sell
+		o.Amount = 1
+		_, err = x.SubmitOrder(&o)
+		if err == nil || errors.Is(err, exchange.ErrOrderTypeNotSupported) {
+			t.Errorf("Test failed - expected a missing trigger error for %+v, got %v",
+				o, err)
+		}
+	}
+}
+
+const walletHistoryFixture = `[
+	{"transactID":"b1","account":1,"currency":"XBt","transactType":"Withdrawal","amount":-50000000,"fee":20000,"transactStatus":"Pending","address":"3BMEXwithdraw","tx":"","text":"","transactTime":"","timestamp":"2018-10-06T09:30:00.000Z"},
+	{"transactID":"b2","account":1,"currency":"XBt","transactType":"RealisedPNL","amount":12345,"fee":0,"transactStatus":"Completed","address":"XBTUSD","tx":"","text":"","transactTime":"2018-10-06T00:00:00.000Z","timestamp":"2018-10-06T00:00:00.000Z"},
+	{"transactID":"b3","account":1,"currency":"XBt","transactType":"Deposit","amount":150000000,"fee":0,"transactStatus":"Completed","address":"3BMEXdeposit","tx":"5b1c0fd3a2","text":"","transactTime":"2018-10-05T12:00:00.000Z","timestamp":"2018-10-05T12:01:00.000Z"},
+	{"transactID":"b4","account":1,"currency":"XBt","transactType":"Withdrawal","amount":-1,"fee":1,"transactStatus":"Completed","address":"3BMEXwithdraw","tx":"9a8b7c","text":"dust","transactTime":"2018-10-04T08:00:00.000Z","timestamp":"2018-10-04T08:00:00.000Z"}
+]`
+
+func TestConvertFundingHistory(t *testing.T) {
+	var x Bitmex
+	x.SetDefaults()
+	var history []TransactionInfo
+	err := common.JSONDecode([]byte(walletHistoryFixture), &history)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funding := x.convertFundingHistory(history)
+	expected := []exchange.FundHistory{
+		{ExchangeName: "Bitmex", Status: "Pending", Timestamp: 1538818200,
+			Currency: symbol.BTC, Amount: 0.5, Fee: 0.0002,
+			TransferType: "Withdrawal", CryptoToAddress: "3BMEXwithdraw"},
+		{ExchangeName: "Bitmex", Status: "Completed", Timestamp: 1538740800,
+			Currency: symbol.BTC, Amount: 1.5, TransferType: "Deposit",
+			CryptoFromAddress: "3BMEXdeposit", CryptoTxID: "5b1c0fd3a2"},
+		{ExchangeName: "Bitmex", Status: "Completed", Description: "dust",
+			Timestamp: 1538640000, Currency: symbol.BTC, Amount: 0.00000001,
+			Fee: 0.00000001, TransferType: "Withdrawal",
+			CryptoToAddress: "3BMEXwithdraw", CryptoTxID: "9a8b7c"},
+	}
+	if len(funding) != len(expected) {
+		t.Fatalf("Test failed - expected %d funding entries, received %d",
+			len(expected), len(funding))
+	}
+	for i := range expected {
+		if funding[i] != expected[i] {
+			t.Errorf("Test failed - funding entry %d expected %+v, received %+v",
+				i, expected[i], funding[i])
+		}
+	}
+}
+
+func TestGetAllWalletHistoryPagination(t *testing.T) {
+	const total = 1200
+	newest := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	var starts []int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params WalletHistoryParams
+		err := json.NewDecoder(r.Body).Decode(&params)
+		if err != nil || params.Currency != bitmexCurrencyXBt {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		starts = append(starts, params.Start)
+		var page []TransactionInfo
+		for i := params.Start; i < total && i < params.Start+params.Count; i++ {
+			page = append(page, TransactionInfo{
+				TransactID:   fmt.Sprint(i),
+				TransactType: "Deposit",
+				TransactTime: newest.Add(-time.Hour * time.Duration(i)).Format(time.RFC3339),
+			})
+		}
+		data, _ := common.JSONEncode(page)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	history, err := x.GetAllWalletHistory(bitmexCurrencyXBt, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != total || len(starts) != 3 || starts[2] != 1000 {
+		t.Errorf("Test failed - expected %d entries over 3 pages, received %d over %v",
+			total, len(history), starts)
+	}
+
+	starts = nil
+	history, err = x.GetAllWalletHistory(bitmexCurrencyXBt,
+		newest.Add(-time.Hour*700))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 701 || len(starts) != 2 ||
+		history[700].TransactID != "700" {
+		t.Errorf("Test failed - expected 701 entries over 2 pages, received %d over %v",
+			len(history), starts)
+	}
+}
+
+func TestGetDepositHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(walletHistoryFixture))
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	deposits, err := x.GetDepositHistory(pair.CurrencyItem("btc"), time.Time{},
+		time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 || deposits[0].Status != exchange.DepositCredited ||
+		deposits[0].Amount != 1.5 || deposits[0].CryptoTxID != "5b1c0fd3a2" {
+		t.Errorf("Test failed - unexpected deposits %+v", deposits)
+	}
+
+	deposits, err = x.GetDepositHistory(pair.CurrencyItem(symbol.BTC),
+		time.Unix(1538740801, 0), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 0 {
+		t.Errorf("Test failed - expected deposits before the start to be excluded, received %+v",
+			deposits)
+	}
+
+	_, err = x.GetDepositHistory(pair.CurrencyItem(symbol.ETH), time.Time{},
+		time.Time{})
+	if err == nil {
+		t.Error("Test failed - expected an unsupported currency error")
+	}
+
+	_, err = x.GetDepositHistory(pair.CurrencyItem(symbol.BTC), time.Now(),
+		time.Now().Add(-time.Hour))
+	if err != exchange.ErrInvalidTimeRange {
+		t.Errorf("Test failed - expected %v, received %v",
+			exchange.ErrInvalidTimeRange, err)
+	}
+}
+
+func TestGetServerTime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"BitMEX API","version":"1.2.0","timestamp":1538784000123}`))
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	serverTime, err := x.GetServerTime()
+	if err != nil {
+		t.Fatal("Test failed - GetServerTime() error", err)
+	}
+	expected := time.Date(2018, 10, 6, 0, 0, 0, 123000000, time.UTC)
+	if !serverTime.Equal(expected) {
+		t.Errorf("Test failed - expected %v, received %v", expected, serverTime)
+	}
+}
+
+func TestUpdateTicker(t *testing.T) {
+	var symbol string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol = r.URL.Query().Get("symbol")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"symbol":"XBTUSD","lastPrice":6600,"highPrice":6700,"lowPrice":6400,` +
+			`"bidPrice":6599.5,"askPrice":6600.5,"volume24h":1200000,"prevPrice24h":6000}]`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	tick, err := x.UpdateTicker(pair.NewCurrencyPair("XBT", "USD"), ticker.Spot)
+	if err != nil {
+		t.Fatal("Test failed - UpdateTicker() error", err)
+	}
+	if symbol != "XBTUSD" {
+		t.Errorf("Test failed - expected instrument XBTUSD, received %s", symbol)
+	}
+	if tick.Last != 6600 || tick.Bid != 6599.5 || tick.Ask != 6600.5 ||
+		tick.High != 6700 || tick.Low != 6400 || tick.Volume != 1200000 {
+		t.Errorf("Test failed - unexpected ticker %+v", tick)
+	}
+	if tick.Open != 6000 || tick.Change != 600 || tick.ChangePercent != 10 {
+		t.Errorf("Test failed - unexpected ticker change %+v", tick)
+	}
+}
+
+func TestPairStatusTransitions(t *testing.T) {
+	state := "Closed"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"symbol":"XBTUSD","state":"` + state + `","lotSize":1,"tickSize":0.5},` +
+			`{"symbol":"ETHUSD","state":"Open","lotSize":1,"tickSize":0.05}]`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	xbtusd := pair.NewCurrencyPair("XBT", "USD")
+	order := &exchange.OrderSubmission{
+		Pair:      xbtusd,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Limit,
+		Amount:    1,
+		Price:     6000,
+	}
+	for _, test := range []struct {
+		state    string
+		expected error
+	}{
+		{"Closed", exchange.ErrPairHalted},
+		{"Unlisted", exchange.ErrPairDelisted},
+		{"Open", nil},
+	} {
+		state = test.state
+		_, err := x.updateTradablePairs(false)
+		if err != nil {
+			t.Fatal("Test failed - updateTradablePairs() error", err)
+		}
+		if x.IsPairTradable(xbtusd) != (test.expected == nil) ||
+			!x.IsPairTradable(pair.NewCurrencyPair("ETH", "USD")) {
+			t.Errorf("Test failed - unexpected tradable pairs for state %s: %v",
+				test.state, x.GetPairStatuses())
+		}
+
+		_, err = x.SubmitOrder(order)
+		if test.expected != nil && !errors.Is(err, test.expected) {
+			t.Errorf("Test failed - expected %v for state %s, received %v",
+				test.expected, test.state, err)
+		}
+		if test.expected == nil && (errors.Is(err, exchange.ErrPairHalted) ||
+			errors.Is(err, exchange.ErrPairDelisted)) {
+			t.Errorf("Test failed - expected an open pair order to be sent, received %v", err)
+		}
+	}
+}
+
+func TestSignedRequestServerTimeOffset(t *testing.T) {
+	var expires []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expiry, err := strconv.ParseInt(r.Header.Get("api-expires"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		expires = append(expires, expiry)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	for _, offset := range []time.Duration{0, -time.Hour} {
+		x.SetServerTimeOffset(offset)
+		_, err := x.GetWalletSummary(bitmexCurrencyXBt)
+		if err != nil {
+			t.Fatal("Test failed - GetWalletSummary() error", err)
+		}
+	}
+
+	if len(expires) != 2 {
+		t.Fatalf("Test failed - expected 2 signed requests, received %d", len(expires))
+	}
+	// Expiry timestamps are in milliseconds
+	skew := time.Duration(expires[0]-expires[1]) * time.Millisecond
+	if skew < time.Hour-time.Minute || skew > time.Hour+time.Minute {
+		t.Errorf("Test failed - expected expiry to be moved back an hour, moved %v", skew)
+	}
+}
+
+func TestUpdateOrderbook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"symbol":"XBTUSD","id":1,"side":"Sell","size":200,"price":6600.5},` +
+			`{"symbol":"XBTUSD","id":2,"side":"Buy","size":100,"price":6599.5}]`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	ob, err := x.UpdateOrderbook(pair.NewCurrencyPair("XBT", "USD"), ticker.Spot)
+	if err != nil {
+		t.Fatal("Test failed - UpdateOrderbook() error", err)
+	}
+	if len(ob.Bids) != 1 || len(ob.Asks) != 1 || ob.LastUpdated.IsZero() ||
+		ob.Source != orderbook.SourceREST {
+		t.Errorf("Test failed - unexpected orderbook %+v", ob)
+	}
+}
+
+func TestGetOrderInfo(t *testing.T) {
+	var filter string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params GenericRequestParams
+		data, _ := ioutil.ReadAll(r.Body)
+		common.JSONDecode(data, &params)
+		filter = params.Filter
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"orderID":"7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01","symbol":"XBTUSD",` +
+			`"currency":"USD","side":"Buy","ordType":"Limit","ordStatus":"PartiallyFilled",` +
+			`"price":6500,"orderQty":100,"cumQty":40,"leavesQty":60,"avgPx":6499.5,` +
+			`"timestamp":"2018-10-06T09:30:00.000Z"}]`))
+	}))
+	defer srv.Close()
+
+	var x Bitmex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	detail, err := x.GetOrderInfo("7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01")
+	if err != nil {
+		t.Fatal("Test failed - GetOrderInfo() error", err)
+	}
+	if expected := `{"orderID":"7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01"}`; filter != expected {
+		t.Errorf("Test failed - expected filter %s, received %s", expected, filter)
+	}
+	expected := exchange.OrderDetail{Exchange: "Bitmex",
+		ID: "7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01", BaseCurrency: "XBT",
+		QuoteCurrency: "USD", OrderSide: "Buy", OrderType: "Limit",
+		CreationTime: 1538818200000, Status: "PartiallyFilled", Price: 6500, Amount: 100,
+		OpenVolume: 60, ExecutedAmount: 40, AveragePrice: 6499.5}
+	if detail != expected {
+		t.Errorf("Test failed - GetOrderInfo() expected %+v, received %+v", expected, detail)
+	}
+
+	_, err = x.GetOrderInfo("unknown")
+	if err == nil {
+		t.Error("Test failed - GetOrderInfo() expected an error for an unknown order")
+	}
+}
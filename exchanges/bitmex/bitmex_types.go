@@ -8,6 +8,13 @@ type RequestError struct {
 	} `json:"error"`
 }
 
+// APIInfo holds the API name, version and server timestamp in milliseconds
+type APIInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+}
+
 // Announcement General Announcements
 type Announcement struct {
 	Content string `json:"content"`
@@ -3,12 +3,9 @@ package bitmex
 import (
 	"errors"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
@@ -17,7 +14,8 @@ import (
 )
 
 const (
-	bitmexWSURL = "wss://www.bitmex.com/realtime"
+	bitmexWSURL        = "wss://www.bitmex.com/realtime"
+	bitmexWSTestnetURL = "wss://testnet.bitmex.com/realtime"
 
 	// Public Subscription Channels
 	bitmexWSAnnouncement        = "announcement"
@@ -68,17 +66,9 @@ func (b *Bitmex) WsConnector() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-	var err error
-
-	if b.Websocket.GetProxyAddress() != "" {
-		var proxy *url.URL
-		proxy, err = url.Parse(b.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := b.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
 	b.WebsocketConn, _, err = dialer.Dial(b.Websocket.GetWebsocketURL(), nil)
@@ -431,13 +421,10 @@ func (b *Bitmex) websocketSubscribe() error {
 
 // WebsocketSendAuth sends an authenticated subscription
 func (b *Bitmex) websocketSendAuth() error {
-	timestamp := time.Now().Add(time.Hour * 1).Unix()
+	timestamp := b.GetAdjustedTime().Add(time.Hour * 1).Unix()
 	newTimestamp := strconv.FormatInt(timestamp, 10)
-	hmac := common.GetHMAC(common.HashSHA256,
-		[]byte("GET/realtime"+newTimestamp),
-		[]byte(b.APISecret))
-
-	signature := common.HexEncodeToString(hmac)
+	signature := exchange.SignSHA256HMAC([]byte("GET/realtime"+newTimestamp),
+		[]byte(b.APISecret), false)
 
 	var sendAuth WebsocketRequest
 	sendAuth.Command = "authKeyExpires"
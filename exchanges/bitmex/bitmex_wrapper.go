@@ -2,12 +2,16 @@ package bitmex
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
@@ -31,20 +35,67 @@ func (b *Bitmex) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	diff, err := b.updateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+		return
+	}
+
+	log.Infof("%s tradable pairs updated. New: %v Removed: %v Disabled: %v",
+		b.Name, diff.New, diff.Removed, diff.Disabled)
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Bitmex) UpdateTradablePairs(forceUpdate bool) error {
+	_, err := b.updateTradablePairs(forceUpdate)
+	return err
+}
+
+// updateTradablePairs fetches the tradable currency pairs and returns the
+// changes made to the available pairs
+func (b *Bitmex) updateTradablePairs(forceUpdate bool) (exchange.PairDifference, error) {
 	marketInfo, err := b.GetActiveInstruments(GenericRequestParams{})
 	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", b.GetName())
+		return exchange.PairDifference{}, err
+	}
 
-	} else {
-		var exchangeProducts []string
-		for _, info := range marketInfo {
-			exchangeProducts = append(exchangeProducts, info.Symbol)
+	var exchangeProducts []string
+	var limits []exchange.Limits
+	var statuses []exchange.PairStatus
+	for _, info := range marketInfo {
+		exchangeProducts = append(exchangeProducts, info.Symbol)
+		if len(info.Symbol) <= 3 {
+			continue
 		}
+		p := pair.NewCurrencyPairFromString(info.Symbol)
+		limits = append(limits, exchange.Limits{
+			Pair:       p,
+			MinAmount:  float64(info.LotSize),
+			MaxAmount:  float64(info.MaxOrderQty),
+			AmountStep: float64(info.LotSize),
+			PriceStep:  info.TickSize,
+		})
+		statuses = append(statuses, exchange.PairStatus{
+			Pair:   p,
+			Status: instrumentStatus(info.State),
+		})
+	}
+	b.SetOrderExecutionLimits(limits)
+	b.SetPairStatuses(statuses)
 
-		err = b.UpdateCurrencies(exchangeProducts, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", b.GetName())
-		}
+	return b.UpdatePairs(exchangeProducts, false, forceUpdate)
+}
+
+// instrumentStatus maps an instrument state to a pair trading status
+func instrumentStatus(state string) string {
+	switch state {
+	case "", "Open":
+		return exchange.PairStatusTrading
+	case "Unlisted", "Settled", "Delisted":
+		return exchange.PairStatusDelisted
+	default:
+		return exchange.PairStatusHalted
 	}
 }
 
@@ -53,26 +104,32 @@ func (b *Bitmex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 	var tickerPrice ticker.Price
 	currency := exchange.FormatExchangeCurrency(b.Name, p)
 
-	tick, err := b.GetTrade(GenericRequestParams{
-		Symbol:    currency.String(),
-		StartTime: time.Now().Format(time.RFC3339),
-		Reverse:   true,
-		Count:     1})
+	instruments, err := b.GetInstruments(GenericRequestParams{
+		Symbol: currency.String()})
 	if err != nil {
 		return tickerPrice, err
 	}
 
-	if len(tick) == 0 {
+	if len(instruments) == 0 {
 		return tickerPrice, errors.New("Bitmex REST error: no ticker return")
 	}
 
+	tick := instruments[0]
 	tickerPrice.Pair = p
 	tickerPrice.LastUpdated = time.Now()
-	tickerPrice.CurrencyPair = tick[0].Symbol
-	tickerPrice.Last = tick[0].Price
-	tickerPrice.Volume = float64(tick[0].Size)
-
-	ticker.ProcessTicker(b.Name, p, tickerPrice, assetType)
+	tickerPrice.CurrencyPair = tick.Symbol
+	tickerPrice.Last = tick.LastPrice
+	tickerPrice.High = tick.HighPrice
+	tickerPrice.Low = tick.LowPrice
+	tickerPrice.Bid = tick.BidPrice
+	tickerPrice.Ask = tick.AskPrice
+	tickerPrice.Volume = float64(tick.Volume24h)
+	tickerPrice.SetOpen(tick.PrevPrice24h)
+
+	err = ticker.ProcessTicker(b.Name, p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 
 	return tickerPrice, nil
 }
@@ -118,6 +175,7 @@ func (b *Bitmex) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 			continue
 		}
 	}
+	orderBook.Source = orderbook.SourceREST
 	orderbook.ProcessOrderbook(b.GetName(), p, orderBook, assetType)
 
 	return orderbook.GetOrderbook(b.Name, p, assetType)
@@ -133,58 +191,194 @@ func (b *Bitmex) GetAccountInfo() (exchange.AccountInfo, error) {
 		return info, err
 	}
 
-	// Need to update to add Margin/Liquidity availibilty
-	var balances []exchange.AccountCurrencyInfo
+	info.Exchange = b.GetName()
+	accounts := make(map[int64]int)
 	for _, data := range bal {
-		balances = append(balances, exchange.AccountCurrencyInfo{
-			CurrencyName: data.Currency,
-			TotalValue:   float64(data.WalletBalance),
-		})
+		currency, wallet := convertXBt(data.Currency, data.WalletBalance)
+		_, available := convertXBt(data.Currency, data.AvailableMargin)
+		_, margin := convertXBt(data.Currency, data.MarginBalance)
+		_, unrealisedPNL := convertXBt(data.Currency, data.UnrealisedPnl)
+		balance := exchange.AccountCurrencyInfo{
+			CurrencyName:   currency,
+			TotalValue:     wallet,
+			AvailableValue: &available,
+			MarginBalance:  &margin,
+			UnrealisedPNL:  &unrealisedPNL,
+		}
+
+		x, ok := accounts[data.Account]
+		if !ok {
+			x = len(info.Accounts)
+			accounts[data.Account] = x
+			info.Accounts = append(info.Accounts, exchange.Account{
+				ID:   strconv.FormatInt(data.Account, 10),
+				Type: exchange.AccountTypeMargin,
+			})
+		}
+		info.Accounts[x].Currencies = append(info.Accounts[x].Currencies, balance)
 	}
+	return info, nil
+}
 
-	info.Exchange = b.GetName()
-	info.Accounts = append(info.Accounts, exchange.Account{
-		Currencies: balances,
-	})
+// convertXBt returns the currency and amount of a Bitmex balance, converting
+// XBt amounts to BTC
+func convertXBt(currency string, amount int64) (string, float64) {
+	if currency == bitmexCurrencyXBt {
+		return symbol.BTC, float64(amount) / bitmexSatoshisPerXBT
+	}
+	return common.StringToUpper(currency), float64(amount)
+}
 
-	return info, nil
+// GetOpenPositions returns the open positions of the account, converting XBt
+// unrealised profit and loss to BTC
+func (b *Bitmex) GetOpenPositions() ([]exchange.Position, error) {
+	positions, err := b.GetPositions(PositionGetParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []exchange.Position
+	for x := range positions {
+		if !positions[x].IsOpen {
+			continue
+		}
+		settlement, unrealisedPNL := convertXBt(positions[x].Currency,
+			positions[x].UnrealisedPnl)
+		result = append(result, exchange.Position{
+			Pair: pair.NewCurrencyPair(positions[x].Underlying,
+				positions[x].QuoteCurrency),
+			BaseAmount:         positions[x].HomeNotional,
+			QuoteAmount:        positions[x].ForeignNotional,
+			SettlementCurrency: settlement,
+			UnrealisedPNL:      unrealisedPNL,
+		})
+	}
+	return result, nil
 }
 
 // GetFundingHistory returns funding history, deposits and
 // withdrawals
 func (b *Bitmex) GetFundingHistory() ([]exchange.FundHistory, error) {
+	history, err := b.GetAllWalletHistory(bitmexCurrencyXBt, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return b.convertFundingHistory(history), nil
+}
+
+// GetDepositHistory returns the deposits of a currency made between start and
+// end, newest first. Only BTC can be deposited
+func (b *Bitmex) GetDepositHistory(currency pair.CurrencyItem, start, end time.Time) ([]exchange.FundHistory, error) {
+	err := exchange.CheckTimeRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if currency.Upper().String() != symbol.BTC {
+		return nil, fmt.Errorf("%s deposits of %s are not supported", b.Name,
+			currency.Upper())
+	}
+
+	history, err := b.GetAllWalletHistory(bitmexCurrencyXBt, start)
+	if err != nil {
+		return nil, err
+	}
+
+	var deposits []exchange.FundHistory
+	funding := b.convertFundingHistory(history)
+	for i := range funding {
+		if funding[i].TransferType != "Deposit" ||
+			!exchange.InTimeRange(funding[i].Timestamp, start, end) {
+			continue
+		}
+		funding[i].Status = depositStatus(funding[i].Status)
+		deposits = append(deposits, funding[i])
+	}
+	return deposits, nil
+}
+
+// depositStatus maps a wallet history transaction status to a deposit status
+func depositStatus(status string) string {
+	switch status {
+	case "Completed":
+		return exchange.DepositCredited
+	case "Canceled", "Rejected":
+		return exchange.DepositFailed
+	default:
+		return exchange.DepositPending
+	}
+}
+
+// convertFundingHistory maps the deposits and withdrawals in wallet history to
+// funding history, converting XBt amounts and fees to BTC. Amounts are
+// absolute, the transfer type gives the direction
+func (b *Bitmex) convertFundingHistory(history []TransactionInfo) []exchange.FundHistory {
 	var fundHistory []exchange.FundHistory
-	// b.GetFullFundingHistory()
-	return fundHistory, common.ErrNotYetImplemented
+	for i := range history {
+		if history[i].TransactType != "Deposit" &&
+			history[i].TransactType != "Withdrawal" {
+			continue
+		}
+
+		currency, amount := convertXBt(history[i].Currency, history[i].Amount)
+		_, fee := convertXBt(history[i].Currency, history[i].Fee)
+
+		f := exchange.FundHistory{
+			ExchangeName: b.Name,
+			Status:       history[i].TransactStatus,
+			Description:  history[i].Text,
+			Currency:     currency,
+			Amount:       math.Abs(amount),
+			Fee:          math.Abs(fee),
+			TransferType: history[i].TransactType,
+			CryptoTxID:   history[i].Tx,
+		}
+		if tm := transactionTime(history[i]); !tm.IsZero() {
+			f.Timestamp = tm.Unix()
+		}
+		if history[i].TransactType == "Deposit" {
+			f.CryptoFromAddress = history[i].Address
+		} else {
+			f.CryptoToAddress = history[i].Address
+		}
+		fundHistory = append(fundHistory, f)
+	}
+	return fundHistory
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Bitmex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Bitmex) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
-// SubmitOrder submits a new order
-func (b *Bitmex) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+// SubmitOrder submits a new order. Post only, reduce only and time in force
+// options are sent as Bitmex execution instructions and time in force values.
+// Stop and stop limit orders require a trigger price and trailing stops a peg
+// offset
+func (b *Bitmex) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(b.Name, bitmexOrderOptions)
+	if err != nil {
+		return submitOrderResponse, err
+	}
 
-	if math.Mod(amount, 1) != 0 {
+	if math.Mod(order.Amount, 1) != 0 {
 		return submitOrderResponse,
 			errors.New("contract amount can not have decimals")
 	}
 
-	var orderNewParams = OrderNewParams{
-		OrdType:  side.ToString(),
-		Symbol:   p.Pair().String(),
-		OrderQty: amount,
-		Side:     side.ToString(),
+	err = b.CheckPairTradable(order.Pair)
+	if err != nil {
+		return submitOrderResponse, err
 	}
 
-	if orderType == exchange.Limit {
-		orderNewParams.Price = price
+	amount, price, err := b.CheckOrderExecutionLimits(order.Pair, order.Amount, order.Price, order.OrderType)
+	if err != nil {
+		return submitOrderResponse, err
 	}
 
+	orderNewParams := newOrderParams(order, amount, price)
 	response, err := b.CreateOrder(orderNewParams)
 	if response.OrderID != "" {
 		submitOrderResponse.OrderID = response.OrderID
@@ -197,6 +391,65 @@ func (b *Bitmex) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, order
 	return submitOrderResponse, err
 }
 
+// bitmexOrderOptions are the order execution options supported by Bitmex
+var bitmexOrderOptions = exchange.OrderOptions{
+	TimeInForce:   []exchange.TimeInForce{exchange.GTC, exchange.IOC, exchange.FOK},
+	PostOnly:      true,
+	ReduceOnly:    true,
+	OrderTypes:    []exchange.OrderType{exchange.Stop, exchange.StopLimit, exchange.TrailingStop},
+	ClientOrderID: true,
+}
+
+// bitmexTimeInForce maps time in force values to Bitmex time in force values
+var bitmexTimeInForce = map[exchange.TimeInForce]string{
+	exchange.GTC: "GoodTillCancel",
+	exchange.IOC: "ImmediateOrCancel",
+	exchange.FOK: "FillOrKill",
+}
+
+// newOrderParams converts an order submission with the amount and price
+// adjusted to the order execution limits to Bitmex order parameters
+func newOrderParams(order *exchange.OrderSubmission, amount, price float64) OrderNewParams {
+	params := OrderNewParams{
+		ClOrdID:     order.ClientID,
+		OrdType:     order.OrderType.ToString(),
+		Symbol:      order.Pair.Pair().String(),
+		OrderQty:    amount,
+		Side:        order.OrderSide.ToString(),
+		TimeInForce: bitmexTimeInForce[order.TimeInForce],
+	}
+
+	switch order.OrderType {
+	case exchange.Limit:
+		params.Price = price
+	case exchange.StopLimit:
+		params.Price = price
+		params.StopPx = order.TriggerPrice
+	case exchange.Stop:
+		params.StopPx = order.TriggerPrice
+	case exchange.TrailingStop:
+		// Bitmex trailing stops are stop orders pegged to the market price,
+		// the offset is negative for sells so the stop trails below it
+		params.OrdType = exchange.Stop.ToString()
+		params.PegPriceType = "TrailingStopPeg"
+		params.PegOffsetValue = order.PegOffset
+		if order.OrderSide == exchange.Sell {
+			params.PegOffsetValue = -order.PegOffset
+		}
+		params.StopPx = order.TriggerPrice
+	}
+
+	var execInst []string
+	if order.PostOnly {
+		execInst = append(execInst, "ParticipateDoNotInitiate")
+	}
+	if order.ReduceOnly {
+		execInst = append(execInst, "ReduceOnly")
+	}
+	params.ExecInst = common.JoinStrings(execInst, ",")
+	return params
+}
+
 // ModifyOrder will allow of changing orderbook placement and limit to
 // market conversion
 func (b *Bitmex) ModifyOrder(action exchange.ModifyOrder) (string, error) {
@@ -246,20 +499,74 @@ func (b *Bitmex) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 	return cancelAllOrdersResponse, nil
 }
 
-// GetOrderInfo returns information on a current open order
-func (b *Bitmex) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
-	var orderDetail exchange.OrderDetail
-	return orderDetail, common.ErrNotYetImplemented
+// GetOrderInfo returns information on an order by its UUID
+func (b *Bitmex) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	filter, err := common.JSONEncode(map[string]string{"orderID": orderID})
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	orders, err := b.GetOrders(GenericRequestParams{Filter: string(filter)})
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	for x := range orders {
+		if orders[x].OrderID != orderID {
+			continue
+		}
+
+		var creationTime int64
+		if tm, err := time.Parse(time.RFC3339, orders[x].Timestamp); err == nil {
+			creationTime = tm.UnixNano() / int64(time.Millisecond)
+		}
+
+		status := orders[x].OrdStatus
+		switch status {
+		case "New":
+			status = "Active"
+		case "Canceled":
+			status = "Cancelled"
+		}
+
+		return exchange.OrderDetail{
+			Exchange:       b.Name,
+			ID:             orders[x].OrderID,
+			BaseCurrency:   strings.TrimSuffix(orders[x].Symbol, orders[x].Currency),
+			QuoteCurrency:  orders[x].Currency,
+			OrderSide:      orders[x].Side,
+			OrderType:      orders[x].OrdType,
+			CreationTime:   creationTime,
+			Status:         status,
+			Price:          orders[x].Price,
+			Amount:         float64(orders[x].OrderQty),
+			OpenVolume:     float64(orders[x].LeavesQty),
+			ExecutedAmount: float64(orders[x].CumQty),
+			AveragePrice:   orders[x].AvgPx,
+		}, nil
+	}
+	return exchange.OrderDetail{}, fmt.Errorf("%s order %s not found", b.Name, orderID)
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Bitmex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	return b.GetCryptoDepositAddress(cryptocurrency.String())
+func (b *Bitmex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return b.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		return b.GetCryptoDepositAddress(cryptocurrency.String())
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
 // submitted
 func (b *Bitmex) WithdrawCryptocurrencyFunds(withdrawRequest exchange.WithdrawRequest) (string, error) {
+	details, err := b.GetCurrencyDetails()
+	if err != nil {
+		return "", err
+	}
+	err = exchange.CheckWithdrawal(details, withdrawRequest)
+	if err != nil {
+		return "", err
+	}
+
 	var request = UserRequestWithdrawalParams{
 		Address:  withdrawRequest.Address,
 		Amount:   withdrawRequest.Amount,
@@ -300,6 +607,24 @@ func (b *Bitmex) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return b.GetFee(feeBuilder)
 }
 
+// GetCurrencyDetails returns the currency details. The satoshi denominated
+// withdrawal fee is refreshed from the API when authenticated API support is
+// enabled
+func (b *Bitmex) GetCurrencyDetails() ([]exchange.CurrencyDetails, error) {
+	if b.AuthenticatedAPISupport {
+		fee, err := b.GetMinimumWithdrawalFee(bitmexCurrencyXBt)
+		if err != nil {
+			return nil, err
+		}
+		b.SetCurrencyDetails([]exchange.CurrencyDetails{{
+			Currency:      bitmexCurrencyXBt,
+			Name:          "Bitcoin",
+			WithdrawalFee: float64(fee.Fee),
+		}})
+	}
+	return b.Base.GetCurrencyDetails()
+}
+
 // GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
 func (b *Bitmex) GetWithdrawCapabilities() uint32 {
 	return b.GetWithdrawPermissions()
@@ -77,6 +77,8 @@ func (b *Bitstamp) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = ""
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"USD", "EUR"}
+	b.EnabledPairs = []string{"BTCUSD", "BTCEUR", "EURUSD", "XRPUSD", "XRPEUR"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = false
 	b.Requester = request.New(b.Name,
@@ -2,10 +2,10 @@ package bitstamp
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -32,23 +32,31 @@ func (b *Bitstamp) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	err := b.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Bitstamp) UpdateTradablePairs(forceUpdate bool) error {
 	pairs, err := b.GetTradingPairs()
 	if err != nil {
-		log.Errorf("%s failed to get trading pairs. Err: %s", b.Name, err)
-	} else {
-		var currencies []string
-		for x := range pairs {
-			if pairs[x].Trading != "Enabled" {
-				continue
-			}
-			pair := strings.Split(pairs[x].Name, "/")
-			currencies = append(currencies, pair[0]+pair[1])
-		}
-		err = b.UpdateCurrencies(currencies, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", b.Name)
+		return err
+	}
+
+	var currencies []string
+	for x := range pairs {
+		if pairs[x].Trading != "Enabled" {
+			continue
 		}
+
+		pair := strings.Split(pairs[x].Name, "/")
+		currencies = append(currencies, pair[0]+pair[1])
 	}
+
+	return b.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -66,7 +74,10 @@ func (b *Bitstamp) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.P
 	tickerPrice.Last = tick.Last
 	tickerPrice.Volume = tick.Volume
 	tickerPrice.High = tick.High
-	ticker.ProcessTicker(b.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(b.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(b.Name, p, assetType)
 }
 
@@ -167,21 +178,26 @@ func (b *Bitstamp) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Bitstamp) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Bitstamp) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *Bitstamp) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *Bitstamp) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	buy := side == exchange.Buy
-	market := orderType == exchange.Market
-	response, err := b.PlaceOrder(p.Pair().String(), price, amount, buy, market)
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	buy := order.OrderSide == exchange.Buy
+	market := order.OrderType == exchange.Market
+	response, err := b.PlaceOrder(order.Pair.Pair().String(), order.Price, order.Amount, buy, market)
 
 	if response.ID > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response.ID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.ID)
 	}
 
 	if err == nil {
@@ -220,14 +236,16 @@ func (b *Bitstamp) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *Bitstamp) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *Bitstamp) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Bitstamp) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	return b.GetCryptoDepositAddress(cryptocurrency.String())
+func (b *Bitstamp) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return b.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		return b.GetCryptoDepositAddress(cryptocurrency.String())
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
@@ -75,6 +75,8 @@ func (b *Bittrex) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = "-"
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"USD"}
+	b.EnabledPairs = []string{"USDT-BTC"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = true
 	b.Requester = request.New(b.Name,
@@ -464,9 +466,9 @@ func (b *Bittrex) GetWithdrawalHistory(currency string) (WithdrawalHistory, erro
 	return history, nil
 }
 
-// GetDepositHistory is used to retrieve your deposit history. If currency is
-// is omitted it will return the entire deposit history
-func (b *Bittrex) GetDepositHistory(currency string) (WithdrawalHistory, error) {
+// GetAccountDepositHistory is used to retrieve your deposit history. If
+// currency is is omitted it will return the entire deposit history
+func (b *Bittrex) GetAccountDepositHistory(currency string) (WithdrawalHistory, error) {
 	var history WithdrawalHistory
 	values := url.Values{}
 
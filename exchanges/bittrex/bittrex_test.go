@@ -207,16 +207,16 @@ func TestGetwithdrawalHistory(t *testing.T) {
 	}
 }
 
-func TestGetDepositHistory(t *testing.T) {
+func TestGetAccountDepositHistory(t *testing.T) {
 	t.Parallel()
 
-	_, err := b.GetDepositHistory("")
+	_, err := b.GetAccountDepositHistory("")
 	if err == nil {
-		t.Error("Test Failed - Bittrex - GetDepositHistory() error")
+		t.Error("Test Failed - Bittrex - GetAccountDepositHistory() error")
 	}
-	_, err = b.GetDepositHistory("btc-ltc")
+	_, err = b.GetAccountDepositHistory("btc-ltc")
 	if err == nil {
-		t.Error("Test Failed - Bittrex - GetDepositHistory() error")
+		t.Error("Test Failed - Bittrex - GetAccountDepositHistory() error")
 	}
 }
 
@@ -339,7 +339,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.LTC,
 	}
-	response, err := b.SubmitOrder(p, exchange.Buy, exchange.Limit, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Limit,
+		Price:     1,
+		Amount:    1,
+	}
+	response, err := b.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -475,12 +482,12 @@ func TestWithdrawInternationalBank(t *testing.T) {
 
 func TestGetDepositAddress(t *testing.T) {
 	if areTestAPIKeysSet() {
-		_, err := b.GetDepositAddress(symbol.BTC, "")
+		_, err := b.GetDepositAddress(symbol.BTC, "", false)
 		if err != nil {
 			t.Error("Test Failed - GetDepositAddress() error", err)
 		}
 	} else {
-		_, err := b.GetDepositAddress(symbol.BTC, "")
+		_, err := b.GetDepositAddress(symbol.BTC, "", false)
 		if err == nil {
 			t.Error("Test Failed - GetDepositAddress() error cannot be nil")
 		}
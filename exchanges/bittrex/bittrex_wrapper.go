@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -29,36 +30,44 @@ func (b *Bittrex) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
-	exchangeProducts, err := b.GetMarkets()
-	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", b.GetName())
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(b.EnabledPairs, "-") || !common.StringDataContains(b.AvailablePairs, "-") {
-			forceUpgrade = true
-		}
-		var currencies []string
-		for x := range exchangeProducts.Result {
-			if !exchangeProducts.Result[x].IsActive || exchangeProducts.Result[x].MarketName == "" {
-				continue
-			}
-			currencies = append(currencies, exchangeProducts.Result[x].MarketName)
-		}
+	forceUpgrade := false
+	if !common.StringDataContains(b.EnabledPairs, "-") || !common.StringDataContains(b.AvailablePairs, "-") {
+		forceUpgrade = true
+	}
 
-		if forceUpgrade {
-			enabledPairs := []string{"USDT-BTC"}
-			log.Warn("Available pairs for Bittrex reset due to config upgrade, please enable the ones you would like again")
+	if forceUpgrade {
+		enabledPairs := []string{"USDT-BTC"}
+		log.Warn("Available pairs for Bittrex reset due to config upgrade, please enable the ones you would like again")
 
-			err = b.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to get config.", b.GetName())
-			}
-		}
-		err = b.UpdateCurrencies(currencies, false, forceUpgrade)
+		err := b.UpdateCurrencies(enabledPairs, true, true)
 		if err != nil {
-			log.Errorf("%s Failed to get config.", b.GetName())
+			log.Errorf("%s failed to update enabled currencies. Err: %s", b.Name, err)
 		}
 	}
+
+	err := b.UpdateTradablePairs(forceUpgrade)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *Bittrex) UpdateTradablePairs(forceUpdate bool) error {
+	exchangeProducts, err := b.GetMarkets()
+	if err != nil {
+		return err
+	}
+
+	var currencies []string
+	for x := range exchangeProducts.Result {
+		if !exchangeProducts.Result[x].IsActive || exchangeProducts.Result[x].MarketName == "" {
+			continue
+		}
+		currencies = append(currencies, exchangeProducts.Result[x].MarketName)
+	}
+
+	return b.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // GetAccountInfo Retrieves balances for all enabled currencies for the
@@ -106,7 +115,10 @@ func (b *Bittrex) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pr
 				tickerPrice.Bid = tick.Result[y].Bid
 				tickerPrice.Last = tick.Result[y].Last
 				tickerPrice.Volume = tick.Result[y].Volume
-				ticker.ProcessTicker(b.GetName(), x, tickerPrice, assetType)
+				err = ticker.ProcessTicker(b.GetName(), x, tickerPrice, assetType)
+				if err != nil {
+					log.Error(err)
+				}
 			}
 		}
 	}
@@ -169,27 +181,31 @@ func (b *Bittrex) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *Bittrex) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *Bittrex) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *Bittrex) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *Bittrex) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	buy := side == exchange.Buy
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	buy := order.OrderSide == exchange.Buy
 	var response UUID
-	var err error
 
-	if orderType != exchange.Limit {
+	if order.OrderType != exchange.Limit {
 		return submitOrderResponse, errors.New("not supported on exchange")
 	}
 
 	if buy {
-		response, err = b.PlaceBuyLimit(p.Pair().String(), amount, price)
+		response, err = b.PlaceBuyLimit(order.Pair.Pair().String(), order.Amount, order.Price)
 	} else {
-		response, err = b.PlaceSellLimit(p.Pair().String(), amount, price)
+		response, err = b.PlaceSellLimit(order.Pair.Pair().String(), order.Amount, order.Price)
 	}
 
 	if response.Result.ID != "" {
@@ -237,19 +253,21 @@ func (b *Bittrex) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *Bittrex) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *Bittrex) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *Bittrex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	depositAddr, err := b.GetCryptoDepositAddress(cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
+func (b *Bittrex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return b.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		depositAddr, err := b.GetCryptoDepositAddress(cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
 
-	return depositAddr.Result.Address, nil
+		return depositAddr.Result.Address, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
@@ -38,6 +38,8 @@ func (b *BTCC) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = ""
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"USD"}
+	b.EnabledPairs = []string{"BTCUSD"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = false
 	b.Requester = request.New(b.Name,
@@ -4,12 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"sync"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
@@ -47,16 +45,9 @@ func (b *BTCC) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-	var err error
-
-	if b.Websocket.GetProxyAddress() != "" {
-		var proxy *url.URL
-		proxy, err = url.Parse(b.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := b.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
 	b.Conn, _, err = dialer.Dial(b.Websocket.GetWebsocketURL(), http.Header{})
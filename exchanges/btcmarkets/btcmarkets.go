@@ -2,9 +2,12 @@ package btcmarkets
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
@@ -25,6 +28,7 @@ const (
 	btcMarketsOrderCancel       = "/order/cancel"
 	btcMarketsOrderHistory      = "/order/history"
 	btcMarketsOrderOpen         = "/order/open"
+	btcMarketsOpenOrders        = "/v2/order/open"
 	btcMarketsOrderTradeHistory = "/order/trade/history"
 	btcMarketsOrderDetail       = "/order/detail"
 	btcMarketsWithdrawCrypto    = "/fundtransfer/withdrawCrypto"
@@ -40,8 +44,15 @@ const (
 	orderStatusFullyMatched       = "Fully Matched"
 	orderStatusPartiallyMatched   = "Partially Matched"
 
+	// btcMarketsOpenOrdersPageLimit is the largest page of open orders
+	btcMarketsOpenOrdersPageLimit = 200
+
 	btcmarketsAuthLimit   = 10
 	btcmarketsUnauthLimit = 25
+
+	// btcMarketsPairFormatVersion is increased whenever the config pair
+	// format changes
+	btcMarketsPairFormatVersion = 1
 )
 
 // BTCMarkets is the overarching type across the BTCMarkets package
@@ -65,6 +76,8 @@ func (b *BTCMarkets) SetDefaults() {
 	b.ConfigCurrencyPairFormat.Delimiter = "-"
 	b.ConfigCurrencyPairFormat.Uppercase = true
 	b.AssetTypes = []string{ticker.Spot}
+	b.BaseCurrencies = []string{"AUD"}
+	b.EnabledPairs = []string{"BTC-AUD"}
 	b.SupportsAutoPairUpdating = true
 	b.SupportsRESTTickerBatching = false
 	b.Requester = request.New(b.Name,
@@ -136,7 +149,7 @@ func (b *BTCMarkets) GetMarkets() ([]Market, error) {
 	return resp.Markets, nil
 }
 
-// GetTicker returns a ticker
+// GetTicker returns a ticker, market data values are returned in human units
 // symbol - example "btc" or "ltc"
 func (b *BTCMarkets) GetTicker(firstPair, secondPair string) (Ticker, error) {
 	ticker := Ticker{}
@@ -148,7 +161,8 @@ func (b *BTCMarkets) GetTicker(firstPair, secondPair string) (Ticker, error) {
 	return ticker, b.SendHTTPRequest(path, &ticker)
 }
 
-// GetOrderbook returns current orderbook
+// GetOrderbook returns current orderbook, market data values are returned in
+// human units
 // symbol - example "btc" or "ltc"
 func (b *BTCMarkets) GetOrderbook(firstPair, secondPair string) (Orderbook, error) {
 	orderbook := Orderbook{}
@@ -175,20 +189,17 @@ func (b *BTCMarkets) GetTrades(firstPair, secondPair string, values url.Values)
 // NewOrder requests a new order and returns an ID
 // currency - example "AUD"
 // instrument - example "BTC"
-// price - example 13000000000 (i.e x 100000000)
-// amount - example 100000000 (i.e x 100000000)
+// price - example 130 (converted to API units)
+// amount - example 1 (converted to API units)
 // orderside - example "Bid" or "Ask"
 // orderType - example "limit"
 // clientReq - example "abc-cdf-1000"
 func (b *BTCMarkets) NewOrder(currency, instrument string, price, amount float64, orderSide, orderType, clientReq string) (int64, error) {
-	newPrice := int64(price * float64(common.SatoshisPerBTC))
-	newVolume := int64(amount * float64(common.SatoshisPerBTC))
-
 	order := OrderToGo{
 		Currency:        common.StringToUpper(currency),
 		Instrument:      common.StringToUpper(instrument),
-		Price:           newPrice,
-		Volume:          newVolume,
+		Price:           toAPIUnits(price),
+		Volume:          toAPIUnits(amount),
 		OrderSide:       orderSide,
 		OrderType:       orderType,
 		ClientRequestID: clientReq,
@@ -267,29 +278,45 @@ func (b *BTCMarkets) GetOrders(currency, instrument string, limit, since int64,
 		return nil, errors.New(resp.ErrorMessage)
 	}
 
-	for i := range resp.Orders {
-		resp.Orders[i].Price = resp.Orders[i].Price / common.SatoshisPerBTC
-		resp.Orders[i].OpenVolume = resp.Orders[i].OpenVolume / common.SatoshisPerBTC
-		resp.Orders[i].Volume = resp.Orders[i].Volume / common.SatoshisPerBTC
+	convertOrders(resp.Orders)
+	return resp.Orders, nil
+}
 
-		for x := range resp.Orders[i].Trades {
-			resp.Orders[i].Trades[x].Fee = resp.Orders[i].Trades[x].Fee / common.SatoshisPerBTC
-			resp.Orders[i].Trades[x].Price = resp.Orders[i].Trades[x].Price / common.SatoshisPerBTC
-			resp.Orders[i].Trades[x].Volume = resp.Orders[i].Trades[x].Volume / common.SatoshisPerBTC
+// GetOpenOrders returns all open orders, fetching every page of open orders
+func (b *BTCMarkets) GetOpenOrders() ([]Order, error) {
+	var orders []Order
+	err := exchange.Paginate(0, func(cursor string) (string, error) {
+		page, err := b.getOpenOrdersPage(cursor)
+		if err != nil {
+			return "", err
 		}
+		orders = append(orders, page...)
+		if len(page) < btcMarketsOpenOrdersPageLimit {
+			return "", nil
+		}
+		return page[len(page)-1].ID, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return resp.Orders, nil
+	return orders, nil
 }
 
-// GetOpenOrders returns all open orders
-func (b *BTCMarkets) GetOpenOrders() ([]Order, error) {
+// getOpenOrdersPage returns a page of open orders following the order ID
+// since, the first page if empty
+func (b *BTCMarkets) getOpenOrdersPage(since string) ([]Order, error) {
 	type marketsResp struct {
 		Response
 		Orders []Order `json:"orders"`
 	}
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(btcMarketsOpenOrdersPageLimit))
+	if since != "" {
+		params.Set("since", since)
+	}
 	request := make(map[string]interface{})
 	var resp marketsResp
-	path := fmt.Sprintf("/v2/order/open")
+	path := common.EncodeURLValues(btcMarketsOpenOrders, params)
 
 	err := b.SendAuthenticatedRequest("GET", path, request, &resp)
 	if err != nil {
@@ -300,6 +327,7 @@ func (b *BTCMarkets) GetOpenOrders() ([]Order, error) {
 		return nil, errors.New(resp.ErrorMessage)
 	}
 
+	convertOrders(resp.Orders)
 	return resp.Orders, nil
 }
 
@@ -323,17 +351,7 @@ func (b *BTCMarkets) GetOrderDetail(orderID []int64) ([]Order, error) {
 		return nil, errors.New(resp.ErrorMessage)
 	}
 
-	for i := range resp.Orders {
-		resp.Orders[i].Price = resp.Orders[i].Price / common.SatoshisPerBTC
-		resp.Orders[i].OpenVolume = resp.Orders[i].OpenVolume / common.SatoshisPerBTC
-		resp.Orders[i].Volume = resp.Orders[i].Volume / common.SatoshisPerBTC
-
-		for x := range resp.Orders[i].Trades {
-			resp.Orders[i].Trades[x].Fee = resp.Orders[i].Trades[x].Fee / common.SatoshisPerBTC
-			resp.Orders[i].Trades[x].Price = resp.Orders[i].Trades[x].Price / common.SatoshisPerBTC
-			resp.Orders[i].Trades[x].Volume = resp.Orders[i].Trades[x].Volume / common.SatoshisPerBTC
-		}
-	}
+	convertOrders(resp.Orders)
 	return resp.Orders, nil
 }
 
@@ -346,10 +364,10 @@ func (b *BTCMarkets) GetAccountBalance() ([]AccountBalance, error) {
 		return nil, err
 	}
 
-	// All values are returned in Satoshis, even for fiat currencies.
+	// All values are returned in API units, even for fiat currencies.
 	for i := range balance {
-		balance[i].Balance = balance[i].Balance / common.SatoshisPerBTC
-		balance[i].PendingFunds = balance[i].PendingFunds / common.SatoshisPerBTC
+		balance[i].Balance = fromAPIUnits(int64(balance[i].Balance))
+		balance[i].PendingFunds = fromAPIUnits(int64(balance[i].PendingFunds))
 	}
 	return balance, nil
 }
@@ -363,10 +381,8 @@ func (b *BTCMarkets) GetTradingFee(firstPair, secondPair string) (TradingFee, er
 
 // WithdrawCrypto withdraws cryptocurrency into a designated address
 func (b *BTCMarkets) WithdrawCrypto(amount float64, currency, address string) (string, error) {
-	newAmount := int64(amount * float64(common.SatoshisPerBTC))
-
 	req := WithdrawRequestCrypto{
-		Amount:   newAmount,
+		Amount:   toAPIUnits(amount),
 		Currency: common.StringToUpper(currency),
 		Address:  address,
 	}
@@ -387,14 +403,12 @@ func (b *BTCMarkets) WithdrawCrypto(amount float64, currency, address string) (s
 // WithdrawAUD withdraws AUD into a designated bank address
 // Does not return a TxID!
 func (b *BTCMarkets) WithdrawAUD(accountName, accountNumber, bankName, bsbNumber string, amount float64) (string, error) {
-	newAmount := int64(amount * float64(common.SatoshisPerBTC))
-
 	req := WithdrawRequestAUD{
 		AccountName:   accountName,
 		AccountNumber: accountNumber,
 		BankName:      bankName,
 		BSBNumber:     bsbNumber,
-		Amount:        newAmount,
+		Amount:        toAPIUnits(amount),
 		Currency:      "AUD",
 	}
 
@@ -427,7 +441,7 @@ func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interfa
 	} else {
 		b.Nonce.Inc()
 	}
-	var request string
+	var message string
 	payload := []byte("")
 
 	if data != nil {
@@ -435,15 +449,17 @@ func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interfa
 		if err != nil {
 			return err
 		}
-		request = path + "\n" + b.Nonce.String()[0:13] + "\n" + string(payload)
+		message = path + "\n" + b.Nonce.String()[0:13] + "\n" + string(payload)
 	} else {
-		request = path + "\n" + b.Nonce.String()[0:13] + "\n"
+		message = path + "\n" + b.Nonce.String()[0:13] + "\n"
 	}
 
-	hmac := common.GetHMAC(common.HashSHA512, []byte(request), []byte(b.APISecret))
+	hmac := common.GetHMAC(common.HashSHA512, []byte(message), []byte(b.APISecret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if b.Verbose {
-		log.Debugf("Sending %s request to URL %s with params %s\n", reqType, b.APIUrl+path, request)
+		log.Debugf("%s request %s. Sending %s request to URL %s with params %s",
+			b.Name, id, reqType, b.APIUrl+path, request.ScrubBody(string(payload)))
 	}
 
 	headers := make(map[string]string)
@@ -454,7 +470,7 @@ func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interfa
 	headers["timestamp"] = b.Nonce.String()[0:13]
 	headers["signature"] = common.Base64Encode(hmac)
 
-	return b.SendPayload(reqType, b.APIUrl+path, headers, bytes.NewBuffer(payload), result, true, b.Verbose)
+	return b.SendPayloadWithContext(ctx, reqType, b.APIUrl+path, headers, bytes.NewBuffer(payload), result, true, b.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
@@ -480,7 +496,7 @@ func (b *BTCMarkets) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 }
 
 func calculateTradingFee(tradingFee TradingFee, purchasePrice, amount float64) (fee float64) {
-	fee = tradingFee.TradingFeeRate / 100000000
+	fee = fromAPIUnits(int64(tradingFee.TradingFeeRate))
 	return fee * amount * purchasePrice
 }
 
@@ -496,3 +512,30 @@ func getInternationalBankWithdrawalFee(currency string) float64 {
 	}
 	return fee
 }
+
+// toAPIUnits converts a human unit price, volume or amount to the fixed-point
+// integer representation used by the BTC Markets trading API
+func toAPIUnits(value float64) int64 {
+	return int64(math.Round(value * common.SatoshisPerBTC))
+}
+
+// fromAPIUnits converts a fixed-point integer price, volume or amount returned
+// by the BTC Markets trading API to human units
+func fromAPIUnits(value int64) float64 {
+	return float64(value) / common.SatoshisPerBTC
+}
+
+// convertOrders converts order and trade values from API units to human units
+func convertOrders(orders []Order) {
+	for i := range orders {
+		orders[i].Price = fromAPIUnits(int64(orders[i].Price))
+		orders[i].OpenVolume = fromAPIUnits(int64(orders[i].OpenVolume))
+		orders[i].Volume = fromAPIUnits(int64(orders[i].Volume))
+
+		for x := range orders[i].Trades {
+			orders[i].Trades[x].Fee = fromAPIUnits(int64(orders[i].Trades[x].Fee))
+			orders[i].Trades[x].Price = fromAPIUnits(int64(orders[i].Trades[x].Price))
+			orders[i].Trades[x].Volume = fromAPIUnits(int64(orders[i].Trades[x].Volume))
+		}
+	}
+}
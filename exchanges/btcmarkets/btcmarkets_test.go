@@ -1,14 +1,21 @@
 package btcmarkets
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
 )
 
 var b BTCMarkets
@@ -41,6 +48,45 @@ func TestSetup(t *testing.T) {
 	b.Setup(bConfig)
 }
 
+func TestMigratePairFormat(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+	exchCfg, err := cfg.GetExchangeConfig("BTC Markets")
+	if err != nil {
+		t.Fatal("Test Failed - BTC Markets GetExchangeConfig() error", err)
+	}
+	exchCfg.AvailablePairs = "BTCAUD,LTCAUD,POWR_AUD,ETHBTC"
+	exchCfg.EnabledPairs = "BTCAUD,POWRAUD"
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var migrate BTCMarkets
+	migrate.SetDefaults()
+	migrate.Setup(exchCfg)
+	if migrate.migratePairFormat() {
+		t.Fatal("Test Failed - BTC Markets migratePairFormat() reset the pairs")
+	}
+	if !reflect.DeepEqual(migrate.EnabledPairs, []string{"BTC-AUD", "POWR-AUD"}) {
+		t.Errorf("Test Failed - BTC Markets migratePairFormat() enabled pairs %v",
+			migrate.EnabledPairs)
+	}
+	if !common.StringDataCompare(migrate.AvailablePairs, "ETH-BTC") {
+		t.Errorf("Test Failed - BTC Markets migratePairFormat() available pairs %v",
+			migrate.AvailablePairs)
+	}
+
+	exchCfg, err = cfg.GetExchangeConfig("BTC Markets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchCfg.PairFormatVersion != btcMarketsPairFormatVersion {
+		t.Errorf("Test Failed - BTC Markets migratePairFormat() config version %d",
+			exchCfg.PairFormatVersion)
+	}
+}
+
 func TestGetMarkets(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetMarkets()
@@ -164,7 +210,7 @@ func TestCancelOrder(t *testing.T) {
 }
 
 func TestGetOrderInfo(t *testing.T) {
-	_, err := b.GetOrderInfo(1337)
+	_, err := b.GetOrderInfo("1337")
 	if err == nil {
 		t.Error("Test failed - GetOrderInfo() error", err)
 	}
@@ -300,7 +346,15 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.LTC,
 	}
-	response, err := b.SubmitOrder(p, exchange.Buy, exchange.Limit, 1, 1, "clientId")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Limit,
+		Price:     1,
+		Amount:    1,
+		ClientID:  "clientId",
+	}
+	response, err := b.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -452,8 +506,117 @@ func TestWithdrawInternationalBank(t *testing.T) {
 }
 
 func TestGetDepositAddress(t *testing.T) {
-	_, err := b.GetDepositAddress(symbol.BTC, "")
+	_, err := b.GetDepositAddress(symbol.BTC, "", false)
 	if err == nil {
 		t.Error("Test Failed - GetDepositAddress() error cannot be nil")
 	}
 }
+
+func TestAPIUnits(t *testing.T) {
+	for _, v := range []float64{0.00000001, 12345.6789, 0, 1} {
+		if r := fromAPIUnits(toAPIUnits(v)); r != v {
+			t.Errorf("Test failed - API units round trip expected %v, got %v", v, r)
+		}
+	}
+
+	if r := toAPIUnits(0.00000001); r != 1 {
+		t.Errorf("Test failed - toAPIUnits() expected 1, got %d", r)
+	}
+
+	if r := toAPIUnits(12345.6789); r != 1234567890000 {
+		t.Errorf("Test failed - toAPIUnits() expected 1234567890000, got %d", r)
+	}
+
+	orders := []Order{{
+		Price:      1234567890000,
+		Volume:     1,
+		OpenVolume: 50000000,
+		Trades:     []TradeResponse{{Price: 1234567890000, Volume: 1, Fee: 2500000}},
+	}}
+	convertOrders(orders)
+	if orders[0].Price != 12345.6789 || orders[0].Volume != 0.00000001 ||
+		orders[0].OpenVolume != 0.5 {
+		t.Errorf("Test failed - convertOrders() unexpected order values %+v", orders[0])
+	}
+	if orders[0].Trades[0].Price != 12345.6789 ||
+		orders[0].Trades[0].Volume != 0.00000001 ||
+		orders[0].Trades[0].Fee != 0.025 {
+		t.Errorf("Test failed - convertOrders() unexpected trade values %+v",
+			orders[0].Trades[0])
+	}
+}
+
+func TestCancelAllOrdersPaginated(t *testing.T) {
+	const totalOrders = btcMarketsOpenOrdersPageLimit*2 + 5
+	var cursors []string
+	var cancelled []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case btcMarketsOpenOrders:
+			if r.URL.Query().Get("limit") != strconv.Itoa(btcMarketsOpenOrdersPageLimit) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			since := r.URL.Query().Get("since")
+			cursors = append(cursors, since)
+			first, _ := strconv.Atoi(since)
+			resp := Response{Success: true}
+			for i := first + 1; i <= totalOrders && i <= first+btcMarketsOpenOrdersPageLimit; i++ {
+				resp.Orders = append(resp.Orders, Order{ID: strconv.Itoa(i)})
+			}
+			data, _ := common.JSONEncode(resp)
+			w.Write(data)
+		case btcMarketsOrderCancel:
+			var req struct {
+				OrderIDs []int64 `json:"orderIds"`
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err == nil {
+				err = common.JSONDecode(body, &req)
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			cancelled = append(cancelled, req.OrderIDs...)
+			resp := Response{Success: true}
+			for _, id := range req.OrderIDs {
+				resp.Responses = append(resp.Responses, ResponseDetails{
+					Success:      id != 3,
+					ErrorMessage: "order already filled",
+					ID:           id,
+				})
+			}
+			data, _ := common.JSONEncode(resp)
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var x BTCMarkets
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	resp, err := x.CancelAllOrders(exchange.OrderCancellation{})
+	if err != nil {
+		t.Fatalf("Test failed - CancelAllOrders() error: %s", err)
+	}
+	if expected := []string{"", "200", "400"}; !reflect.DeepEqual(cursors, expected) {
+		t.Errorf("Test failed - expected open order pages %v, received %v", expected, cursors)
+	}
+	if len(cancelled) != totalOrders || cancelled[totalOrders-1] != totalOrders {
+		t.Errorf("Test failed - expected %d orders cancelled, received %d",
+			totalOrders, len(cancelled))
+	}
+	if len(resp.OrderStatus) != 1 || resp.OrderStatus["3"] != "order already filled" {
+		t.Errorf("Test failed - unexpected order statuses %v", resp.OrderStatus)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -31,34 +32,38 @@ func (b *BTCMarkets) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
-	markets, err := b.GetMarkets()
+	forceUpgrade := b.migratePairFormat()
+	err := b.UpdateTradablePairs(forceUpgrade)
 	if err != nil {
-		log.Errorf("%s failed to get active market. Err: %s", b.Name, err)
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(b.EnabledPairs, "-") || !common.StringDataContains(b.AvailablePairs, "-") {
-			forceUpgrade = true
-		}
+		log.Errorf("%s failed to update tradable pairs. Err: %s", b.Name, err)
+	}
+}
 
-		var currencies []string
-		for x := range markets {
-			currencies = append(currencies, markets[x].Instrument+"-"+markets[x].Currency)
-		}
+// migratePairFormat reformats the stored pairs from an older config pair
+// format and returns whether the pairs were reset and need a forced update
+func (b *BTCMarkets) migratePairFormat() bool {
+	reset, err := b.MigratePairFormat(btcMarketsPairFormatVersion,
+		[]string{"BTC-AUD"})
+	if err != nil {
+		log.Errorf("%s failed to migrate pair format. Err: %s", b.Name, err)
+	}
+	return reset
+}
 
-		if forceUpgrade {
-			enabledPairs := []string{"BTC-AUD"}
-			log.Warn("Available pairs for BTC Makrets reset due to config upgrade, please enable the pairs you would like again.")
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (b *BTCMarkets) UpdateTradablePairs(forceUpdate bool) error {
+	markets, err := b.GetMarkets()
+	if err != nil {
+		return err
+	}
 
-			err = b.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s failed to update currencies. Err: %s", b.Name, err)
-			}
-		}
-		err = b.UpdateCurrencies(currencies, false, forceUpgrade)
-		if err != nil {
-			log.Errorf("%s failed to update currencies. Err: %s", b.Name, err)
-		}
+	var currencies []string
+	for x := range markets {
+		currencies = append(currencies, markets[x].Instrument+"-"+markets[x].Currency)
 	}
+
+	return b.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -73,7 +78,10 @@ func (b *BTCMarkets) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker
 	tickerPrice.Ask = tick.BestAsk
 	tickerPrice.Bid = tick.BestBID
 	tickerPrice.Last = tick.LastPrice
-	ticker.ProcessTicker(b.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(b.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(b.Name, p, assetType)
 }
 
@@ -154,19 +162,24 @@ func (b *BTCMarkets) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (b *BTCMarkets) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (b *BTCMarkets) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (b *BTCMarkets) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (b *BTCMarkets) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	response, err := b.NewOrder(p.FirstCurrency.Upper().String(), p.SecondCurrency.Upper().String(), price, amount, side.ToString(), orderType.ToString(), clientID)
+	err := order.CheckOptions(b.Name, exchange.OrderOptions{ClientOrderID: true})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := b.NewOrder(order.Pair.FirstCurrency.Upper().String(), order.Pair.SecondCurrency.Upper().String(), order.Price, order.Amount, order.OrderSide.ToString(), order.OrderType.ToString(), order.ClientID)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -208,6 +221,7 @@ func (b *BTCMarkets) CancelAllOrders(orderCancellation exchange.OrderCancellatio
 		orderIDInt, err := strconv.ParseInt(order.ID, 10, 64)
 		if err != nil {
 			cancelAllOrdersResponse.OrderStatus[order.ID] = err.Error()
+			continue
 		}
 		orderList = append(orderList, orderIDInt)
 	}
@@ -219,8 +233,8 @@ func (b *BTCMarkets) CancelAllOrders(orderCancellation exchange.OrderCancellatio
 		}
 
 		for _, order := range orders {
-			if err != nil {
-				cancelAllOrdersResponse.OrderStatus[strconv.FormatInt(order.ID, 10)] = err.Error()
+			if !order.Success {
+				cancelAllOrdersResponse.OrderStatus[strconv.FormatInt(order.ID, 10)] = order.ErrorMessage
 			}
 		}
 	}
@@ -228,10 +242,15 @@ func (b *BTCMarkets) CancelAllOrders(orderCancellation exchange.OrderCancellatio
 }
 
 // GetOrderInfo returns information on a current open order
-func (b *BTCMarkets) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (b *BTCMarkets) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var OrderDetail exchange.OrderDetail
 
-	orders, err := b.GetOrderDetail([]int64{orderID})
+	id, err := exchange.ParseNumericOrderID(orderID)
+	if err != nil {
+		return OrderDetail, err
+	}
+
+	orders, err := b.GetOrderDetail([]int64{id})
 	if err != nil {
 		return OrderDetail, err
 	}
@@ -262,7 +281,7 @@ func (b *BTCMarkets) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (b *BTCMarkets) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (b *BTCMarkets) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrFunctionNotSupported
 }
 
@@ -2,6 +2,7 @@ package coinbasepro
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -77,6 +78,8 @@ func (c *CoinbasePro) SetDefaults() {
 	c.ConfigCurrencyPairFormat.Delimiter = ""
 	c.ConfigCurrencyPairFormat.Uppercase = true
 	c.AssetTypes = []string{ticker.Spot}
+	c.BaseCurrencies = []string{"USD", "GBP", "EUR"}
+	c.EnabledPairs = []string{"BTCUSD", "BTCGBP", "BTCEUR"}
 	c.SupportsAutoPairUpdating = true
 	c.SupportsRESTTickerBatching = false
 	c.Requester = request.New(c.Name,
@@ -85,6 +88,7 @@ func (c *CoinbasePro) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	c.APIUrlDefault = coinbaseproAPIURL
 	c.APIUrl = c.APIUrlDefault
+	c.SetSandboxEndpoints(exchange.SandboxEndpoints{APIURL: coinbaseproSandboxAPIURL})
 	c.WebsocketInit()
 	c.Websocket.Functionality = exchange.WebsocketTickerSupported |
 		exchange.WebsocketOrderbookSupported
@@ -106,9 +110,6 @@ func (c *CoinbasePro) Setup(exch config.ExchangeConfig) {
 		c.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
 		c.AvailablePairs = common.SplitStrings(exch.AvailablePairs, ",")
 		c.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
-		if exch.UseSandbox {
-			c.APIUrl = coinbaseproSandboxAPIURL
-		}
 		err := c.SetCurrencyPairFormat()
 		if err != nil {
 			log.Fatal(err)
@@ -306,10 +307,14 @@ func (c *CoinbasePro) GetCurrencies() ([]Currency, error) {
 }
 
 // GetServerTime returns the API server time
-func (c *CoinbasePro) GetServerTime() (ServerTime, error) {
+func (c *CoinbasePro) GetServerTime() (time.Time, error) {
 	serverTime := ServerTime{}
 
-	return serverTime, c.SendHTTPRequest(c.APIUrl+coinbaseproTime, &serverTime)
+	err := c.SendHTTPRequest(c.APIUrl+coinbaseproTime, &serverTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(serverTime.Epoch*float64(time.Second))), nil
 }
 
 // GetAccounts returns a list of trading accounts associated with the APIKEYS
@@ -803,6 +808,7 @@ func (c *CoinbasePro) SendAuthenticatedHTTPRequest(method, path string, params m
 
 	payload := []byte("")
 
+	ctx, id := request.WithRequestID(context.Background())
 	if params != nil {
 		payload, err = common.JSONEncode(params)
 		if err != nil {
@@ -810,7 +816,8 @@ func (c *CoinbasePro) SendAuthenticatedHTTPRequest(method, path string, params m
 		}
 
 		if c.Verbose {
-			log.Debugf("Request JSON: %s\n", payload)
+			log.Debugf("%s request %s. Request JSON: %s", c.Name, id,
+				request.ScrubBody(string(payload)))
 		}
 	}
 
@@ -824,7 +831,7 @@ func (c *CoinbasePro) SendAuthenticatedHTTPRequest(method, path string, params m
 	headers["CB-ACCESS-PASSPHRASE"] = c.ClientID
 	headers["Content-Type"] = "application/json"
 
-	return c.SendPayload(method, c.APIUrl+path, headers, bytes.NewBuffer(payload), result, true, c.Verbose)
+	return c.SendPayloadWithContext(ctx, method, c.APIUrl+path, headers, bytes.NewBuffer(payload), result, true, c.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
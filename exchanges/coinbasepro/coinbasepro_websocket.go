@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
@@ -60,19 +59,12 @@ func (c *CoinbasePro) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-
-	if c.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(c.Websocket.GetProxyAddress())
-		if err != nil {
-			return fmt.Errorf("coinbasepro_websocket.go error - proxy address %s",
-				err)
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := c.Websocket.Dialer()
+	if err != nil {
+		return fmt.Errorf("coinbasepro_websocket.go error - proxy address %s",
+			err)
 	}
 
-	var err error
 	c.WebsocketConn, _, err = dialer.Dial(c.Websocket.GetWebsocketURL(),
 		http.Header{})
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -30,21 +31,28 @@ func (c *CoinbasePro) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", c.GetName(), len(c.EnabledPairs), c.EnabledPairs)
 	}
 
+	err := c.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", c.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (c *CoinbasePro) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := c.GetProducts()
 	if err != nil {
-		log.Errorf("%s Failed to get available products.\n", c.GetName())
-	} else {
-		currencies := []string{}
-		for _, x := range exchangeProducts {
-			if x.ID != "BTC" && x.ID != "USD" && x.ID != "GBP" {
-				currencies = append(currencies, x.ID[0:3]+x.ID[4:])
-			}
-		}
-		err = c.UpdateCurrencies(currencies, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", c.GetName())
+		return err
+	}
+
+	currencies := []string{}
+	for _, x := range exchangeProducts {
+		if x.ID != "BTC" && x.ID != "USD" && x.ID != "GBP" {
+			currencies = append(currencies, x.ID[0:3]+x.ID[4:])
 		}
 	}
+
+	return c.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // GetAccountInfo retrieves balances for all enabled currencies for the
@@ -93,7 +101,10 @@ func (c *CoinbasePro) UpdateTicker(p pair.CurrencyPair, assetType string) (ticke
 	tickerPrice.Last = tick.Price
 	tickerPrice.High = stats.High
 	tickerPrice.Low = stats.Low
-	ticker.ProcessTicker(c.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(c.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(c.Name, p, assetType)
 }
 
@@ -145,22 +156,26 @@ func (c *CoinbasePro) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (c *CoinbasePro) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (c *CoinbasePro) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (c *CoinbasePro) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (c *CoinbasePro) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(c.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var response string
-	var err error
-	if orderType == exchange.Market {
-		response, err = c.PlaceMarginOrder("", amount, amount, side.ToString(), p.Pair().String(), "")
+	if order.OrderType == exchange.Market {
+		response, err = c.PlaceMarginOrder("", order.Amount, order.Amount, order.OrderSide.ToString(), order.Pair.Pair().String(), "")
 
-	} else if orderType == exchange.Limit {
-		response, err = c.PlaceLimitOrder("", price, amount, side.ToString(), "", "", p.Pair().String(), "", false)
+	} else if order.OrderType == exchange.Limit {
+		response, err = c.PlaceLimitOrder("", order.Price, order.Amount, order.OrderSide.ToString(), "", "", order.Pair.Pair().String(), "", false)
 	} else {
 		err = errors.New("not supported")
 	}
@@ -195,13 +210,13 @@ func (c *CoinbasePro) CancelAllOrders(orderCancellation exchange.OrderCancellati
 }
 
 // GetOrderInfo returns information on a current open order
-func (c *CoinbasePro) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (c *CoinbasePro) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (c *CoinbasePro) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (c *CoinbasePro) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrFunctionNotSupported
 }
 
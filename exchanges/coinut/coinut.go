@@ -2,6 +2,7 @@ package coinut
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -66,6 +67,8 @@ func (c *COINUT) SetDefaults() {
 	c.ConfigCurrencyPairFormat.Delimiter = ""
 	c.ConfigCurrencyPairFormat.Uppercase = true
 	c.AssetTypes = []string{ticker.Spot}
+	c.BaseCurrencies = []string{"USD"}
+	c.EnabledPairs = []string{"LTCBTC", "ETCBTC", "ETHBTC"}
 	c.SupportsAutoPairUpdating = true
 	c.SupportsRESTTickerBatching = false
 	c.Requester = request.New(c.Name,
@@ -355,8 +358,10 @@ func (c *COINUT) SendHTTPRequest(apiRequest string, params map[string]interface{
 		return errors.New("SenddHTTPRequest: Unable to JSON request")
 	}
 
+	ctx, id := request.WithRequestID(context.Background())
 	if c.Verbose {
-		log.Debugf("Request JSON: %s", payload)
+		log.Debugf("%s request %s. Request JSON: %s", c.Name, id,
+			request.ScrubBody(string(payload)))
 	}
 
 	headers := make(map[string]string)
@@ -368,7 +373,7 @@ func (c *COINUT) SendHTTPRequest(apiRequest string, params map[string]interface{
 	headers["Content-Type"] = "application/json"
 
 	var rawMsg json.RawMessage
-	err = c.SendPayload("POST", c.APIUrl, headers, bytes.NewBuffer(payload), &rawMsg, authenticated, c.Verbose)
+	err = c.SendPayloadWithContext(ctx, "POST", c.APIUrl, headers, bytes.NewBuffer(payload), &rawMsg, authenticated, c.Verbose)
 	if err != nil {
 		return err
 	}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -173,19 +172,12 @@ func (c *COINUT) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var Dialer websocket.Dialer
-
-	if c.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(c.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		Dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := c.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
-	c.WebsocketConn, _, err = Dialer.Dial(c.Websocket.GetWebsocketURL(),
+	c.WebsocketConn, _, err = dialer.Dial(c.Websocket.GetWebsocketURL(),
 		http.Header{})
 
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -32,10 +33,18 @@ func (c *COINUT) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", c.GetName(), len(c.EnabledPairs), c.EnabledPairs)
 	}
 
+	err := c.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", c.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (c *COINUT) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := c.GetInstruments()
 	if err != nil {
-		log.Debugf("%s Failed to get available products.\n", c.GetName())
-		return
+		return err
 	}
 
 	currencies := []string{}
@@ -45,10 +54,7 @@ func (c *COINUT) Run() {
 		currencies = append(currencies, x)
 	}
 
-	err = c.UpdateCurrencies(currencies, false, false)
-	if err != nil {
-		log.Errorf("%s Failed to update available currencies.\n", c.GetName())
-	}
+	return c.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // GetAccountInfo retrieves balances for all enabled currencies for the
@@ -152,7 +158,10 @@ func (c *COINUT) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 	tickerPrice.Last = tick.Last
 	tickerPrice.High = tick.HighestBuy
 	tickerPrice.Low = tick.LowestSell
-	ticker.ProcessTicker(c.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(c.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(c.Name, p, assetType)
 
 }
@@ -204,19 +213,23 @@ func (c *COINUT) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (c *COINUT) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (c *COINUT) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (c *COINUT) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (c *COINUT) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	var err error
+	err := order.CheckOptions(c.Name, exchange.OrderOptions{ClientOrderID: true})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var APIresponse interface{}
-	isBuyOrder := side == exchange.Buy
-	clientIDInt, err := strconv.ParseUint(clientID, 0, 32)
+	isBuyOrder := order.OrderSide == exchange.Buy
+	clientIDInt, err := strconv.ParseUint(order.ClientID, 0, 32)
 	clientIDUint := uint32(clientIDInt)
 
 	if err != nil {
@@ -228,13 +241,13 @@ func (c *COINUT) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, order
 		return submitOrderResponse, err
 	}
 
-	currencyArray := instruments.Instruments[p.Pair().String()]
+	currencyArray := instruments.Instruments[order.Pair.Pair().String()]
 	currencyID := currencyArray[0].InstID
 
-	if orderType == exchange.Limit {
-		APIresponse, err = c.NewOrder(currencyID, amount, price, isBuyOrder, clientIDUint)
-	} else if orderType == exchange.Market {
-		APIresponse, err = c.NewOrder(currencyID, amount, 0, isBuyOrder, clientIDUint)
+	if order.OrderType == exchange.Limit {
+		APIresponse, err = c.NewOrder(currencyID, order.Amount, order.Price, isBuyOrder, clientIDUint)
+	} else if order.OrderType == exchange.Market {
+		APIresponse, err = c.NewOrder(currencyID, order.Amount, 0, isBuyOrder, clientIDUint)
 	} else {
 		return submitOrderResponse, errors.New("unsupported order type")
 	}
@@ -242,13 +255,13 @@ func (c *COINUT) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, order
 	switch apiResp := APIresponse.(type) {
 	case OrdersBase:
 		orderResult := apiResp
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", orderResult.OrderID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(orderResult.OrderID)
 	case OrderFilledResponse:
 		orderResult := apiResp
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", orderResult.Order.OrderID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(orderResult.Order.OrderID)
 	case OrderRejectResponse:
 		orderResult := apiResp
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", orderResult.OrderID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(orderResult.OrderID)
 		err = fmt.Errorf("OrderID: %v was rejected: %v", orderResult.OrderID, orderResult.Reasons)
 	}
 
@@ -340,13 +353,13 @@ func (c *COINUT) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 }
 
 // GetOrderInfo returns information on a current open order
-func (c *COINUT) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (c *COINUT) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (c *COINUT) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (c *COINUT) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrFunctionNotSupported
 }
 
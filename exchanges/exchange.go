@@ -1,10 +1,16 @@
 package exchange
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +18,7 @@ import (
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
 	"github.com/thrasher-/gocryptotrader/exchanges/nonce"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/request"
@@ -21,14 +28,111 @@ import (
 
 const (
 	warningBase64DecryptSecretKeyFailed = "WARNING -- Exchange %s unable to base64 decode secret key.. Disabling Authenticated API support."
+	warningPEMKeyInvalid                = "WARNING -- Exchange %s unable to load PEM key: %s. Disabling Authenticated API support."
 	// WarningAuthenticatedRequestWithoutCredentialsSet error message for authenticated request without credentials set
 	WarningAuthenticatedRequestWithoutCredentialsSet = "WARNING -- Exchange %s authenticated HTTP request called but not supported due to unset/default API keys."
 	// ErrExchangeNotFound is a stand for an error message
 	ErrExchangeNotFound = "Exchange not found in dataset"
 	// DefaultHTTPTimeout is the default HTTP/HTTPS Timeout for exchange requests
 	DefaultHTTPTimeout = time.Second * 15
+	// DefaultPairRemovalThreshold is the default maximum percentage of
+	// available pairs an unforced pair update may remove
+	DefaultPairRemovalThreshold = 50
 )
 
+// Error classes returned by exchange request paths, use errors.Is to check
+// which class an error belongs to
+var (
+	ErrAuthenticationNotConfigured = errors.New("authenticated API support not configured")
+	ErrCredentialsInvalid          = errors.New("API credentials rejected by exchange")
+	ErrRateLimited                 = errors.New("request rate limited by exchange")
+	ErrExchangeUnavailable         = errors.New("exchange unavailable")
+	// ErrExchangeMaintenance is returned during exchange maintenance or a
+	// trading halt, it also matches ErrExchangeUnavailable
+	ErrExchangeMaintenance = WrapError(ErrExchangeUnavailable, "exchange under maintenance")
+)
+
+// maintenanceResponseKeywords are found in the bodies of HTTP 503 responses
+// returned during exchange maintenance
+var maintenanceResponseKeywords = []string{
+	"maintenance",
+	"maintaining",
+	"system upgrade",
+	"trading halt",
+}
+
+// classifiedError holds a descriptive error message for an error class
+type classifiedError struct {
+	class error
+	msg   string
+}
+
+func (c *classifiedError) Error() string {
+	return c.msg
+}
+
+func (c *classifiedError) Unwrap() error {
+	return c.class
+}
+
+// WrapError returns an error with the formatted message which matches the
+// supplied error class with errors.Is
+func WrapError(class error, format string, a ...interface{}) error {
+	return &classifiedError{class: class, msg: fmt.Sprintf(format, a...)}
+}
+
+// NewAuthenticationNotConfiguredError returns the error for an authenticated
+// request made without authenticated API support configured
+func NewAuthenticationNotConfiguredError(exchName string) error {
+	return WrapError(ErrAuthenticationNotConfigured,
+		WarningAuthenticatedRequestWithoutCredentialsSet, exchName)
+}
+
+// ClassifyRequestError wraps a request error in its error class based on the
+// HTTP status code or network failure. Errors which can't be classified are
+// returned unchanged
+func ClassifyRequestError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *request.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusUnauthorized,
+			statusErr.StatusCode == http.StatusForbidden:
+			return WrapError(ErrCredentialsInvalid, "%s", err)
+		case statusErr.StatusCode == http.StatusTooManyRequests,
+			statusErr.StatusCode == http.StatusTeapot:
+			return WrapError(ErrRateLimited, "%s", err)
+		case statusErr.StatusCode == http.StatusServiceUnavailable &&
+			isMaintenanceResponse(statusErr.Body):
+			return WrapError(ErrExchangeMaintenance, "%s", err)
+		case statusErr.StatusCode >= http.StatusInternalServerError:
+			return WrapError(ErrExchangeUnavailable, "%s", err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return WrapError(ErrExchangeUnavailable, "%s", err)
+	}
+	return err
+}
+
+// isMaintenanceResponse returns whether a response body announces exchange
+// maintenance
+func isMaintenanceResponse(body string) bool {
+	body = strings.ToLower(body)
+	for x := range maintenanceResponseKeywords {
+		if strings.Contains(body, maintenanceResponseKeywords[x]) {
+			return true
+		}
+	}
+	return false
+}
+
 // FeeType custom type for calculating fees based on method
 type FeeType string
 
@@ -67,10 +171,203 @@ const (
 	Contact         InternationalBankTransactionType = "contact"
 )
 
-// SubmitOrderResponse is what is returned after submitting an order to an exchange
+// SubmitOrderResponse is what is returned after submitting an order to an exchange.
+// FilledAmount and RemainingAmount are only set by exchanges which report them
+// when the order is placed
 type SubmitOrderResponse struct {
-	IsOrderPlaced bool
-	OrderID       string
+	IsOrderPlaced   bool
+	OrderID         string
+	FilledAmount    *float64 `json:",omitempty"`
+	RemainingAmount *float64 `json:",omitempty"`
+}
+
+// FormatOrderID converts a numeric order ID returned by an exchange to its
+// decimal string representation
+func FormatOrderID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// FormatFloatOrderID converts an order ID an exchange returns as a JSON number
+// decoded into a float64 to its decimal string representation, without the
+// exponent formatting fmt uses for large values
+func FormatFloatOrderID(id float64) string {
+	return strconv.FormatFloat(id, 'f', -1, 64)
+}
+
+// ErrOrderOptionNotSupported is returned when an order submission requests an
+// execution option the exchange does not support
+var ErrOrderOptionNotSupported = errors.New("order option not supported")
+
+// ErrOrderTypeNotSupported is returned when an order submission requests a
+// stop or trailing stop order type the exchange does not support
+var ErrOrderTypeNotSupported = errors.New("order type not supported")
+
+// ErrCancelReplaceRequired is returned when an exchange can only modify an
+// order by cancelling and replacing it and the caller hasn't allowed it
+var ErrCancelReplaceRequired = errors.New("order modification cancels and replaces the order, set CancelReplace to allow it")
+
+// ErrOrderNotOpen is returned when an order can't be modified because it has
+// filled or been cancelled
+var ErrOrderNotOpen = errors.New("order is no longer open")
+
+// ErrLeverageRequired is returned when a futures order is submitted without
+// the leverage the exchange requires
+var ErrLeverageRequired = errors.New("order leverage required")
+
+// Currency pair formatting errors, returned when batched symbol request
+// parameters can't be formatted so malformed requests aren't sent
+var (
+	ErrNoCurrencyPairs      = errors.New("no currency pairs to format")
+	ErrEmptyCurrencyPair    = errors.New("currency pair formats to an empty string")
+	ErrInvalidPairSeparator = errors.New("request currency pair separator is empty or matches the delimiter")
+)
+
+// ErrOrderIDNotNumeric is returned when an exchange using numeric order IDs is
+// given an order ID which isn't a number
+var ErrOrderIDNotNumeric = errors.New("order ID is not numeric")
+
+// ParseNumericOrderID parses the order ID of an exchange using numeric order
+// IDs, a non numeric order ID returns an ErrOrderIDNotNumeric error
+func ParseNumericOrderID(orderID string) (int64, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return 0, WrapError(ErrOrderIDNotNumeric, "order ID %q is not numeric", orderID)
+	}
+	return id, nil
+}
+
+// TimeInForce defines how long an order remains active before it is executed
+// or expires
+type TimeInForce string
+
+// TimeInForce types, an unset TimeInForce defaults to GTC
+const (
+	GTC TimeInForce = "GTC" // good till cancelled
+	IOC TimeInForce = "IOC" // immediate or cancel
+	FOK TimeInForce = "FOK" // fill or kill
+)
+
+// ParseTimeInForce returns the TimeInForce for a case insensitive GTC, IOC or
+// FOK string, an empty string returns the default unset TimeInForce
+func ParseTimeInForce(timeInForce string) (TimeInForce, error) {
+	switch t := TimeInForce(common.StringToUpper(timeInForce)); t {
+	case "", GTC, IOC, FOK:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid time in force %q, supported values: GTC, IOC, FOK",
+		timeInForce)
+}
+
+// OrderSubmission contains the order details and execution options used to
+// submit an order to an exchange
+type OrderSubmission struct {
+	Pair pair.CurrencyPair
+	// AssetType is the market the order is placed on, spot if empty
+	AssetType string
+	OrderSide OrderSide
+	OrderType OrderType
+	Amount    float64
+	Price     float64
+	// TriggerPrice is the price at which stop and stop limit orders are
+	// placed, for trailing stops it optionally sets the initial trigger price
+	TriggerPrice float64
+	// PegOffset is the distance a trailing stop trails the market price by,
+	// it is applied below the price for sells and above it for buys
+	PegOffset float64
+	// ClientID is passed to the exchange as the client order ID, or as the
+	// account ID on exchanges which require one to place orders
+	ClientID string
+
+	TimeInForce TimeInForce
+	PostOnly    bool
+	ReduceOnly  bool
+	// Leverage is the leverage of futures orders, exchanges which require it
+	// reject futures orders without it
+	Leverage float64
+}
+
+// OrderOptions describes the order execution options supported by an
+// exchange
+type OrderOptions struct {
+	TimeInForce []TimeInForce
+	PostOnly    bool
+	ReduceOnly  bool
+	// AssetTypes are the asset types orders can be placed on besides spot
+	AssetTypes []string
+	// OrderTypes are the stop and trailing stop order types supported besides
+	// limit and market orders
+	OrderTypes []OrderType
+	// ClientOrderID is whether orders can be submitted with a ClientID
+	ClientOrderID bool
+}
+
+// CheckOptions returns an error if the order requests an execution option
+// which is not in the supported options of the exchange
+func (o *OrderSubmission) CheckOptions(exchName string, supported OrderOptions) error {
+	if o.AssetType != "" && o.AssetType != ticker.Spot &&
+		!common.StringDataCompare(supported.AssetTypes, o.AssetType) {
+		return fmt.Errorf("%s %w: asset type %s",
+			exchName, ErrOrderOptionNotSupported, o.AssetType)
+	}
+	if o.TimeInForce != "" && o.TimeInForce != GTC {
+		var found bool
+		for x := range supported.TimeInForce {
+			if supported.TimeInForce[x] == o.TimeInForce {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s %w: time in force %s",
+				exchName, ErrOrderOptionNotSupported, o.TimeInForce)
+		}
+	}
+	if o.OrderType.IsStop() {
+		var found bool
+		for x := range supported.OrderTypes {
+			if supported.OrderTypes[x] == o.OrderType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s %w: order type %s",
+				exchName, ErrOrderTypeNotSupported, o.OrderType)
+		}
+	}
+	if o.PostOnly && !supported.PostOnly {
+		return fmt.Errorf("%s %w: post only", exchName, ErrOrderOptionNotSupported)
+	}
+	if o.ReduceOnly && !supported.ReduceOnly {
+		return fmt.Errorf("%s %w: reduce only", exchName, ErrOrderOptionNotSupported)
+	}
+	if o.ClientID != "" && !supported.ClientOrderID {
+		return fmt.Errorf("%s %w: client order ID", exchName, ErrOrderOptionNotSupported)
+	}
+	if o.PostOnly && o.OrderType != "" && o.OrderType != Limit &&
+		o.OrderType != StopLimit {
+		return fmt.Errorf("%s post only orders must be limit orders", exchName)
+	}
+	switch o.OrderType {
+	case Stop, StopLimit:
+		if !(o.TriggerPrice > 0) {
+			return fmt.Errorf("%s %s orders require a trigger price",
+				exchName, o.OrderType)
+		}
+		if o.OrderType == StopLimit && !(o.Price > 0) {
+			return fmt.Errorf("%s stop limit orders require a limit price",
+				exchName)
+		}
+	case TrailingStop:
+		if !(o.PegOffset > 0) {
+			return fmt.Errorf("%s trailing stop orders require a trailing offset",
+				exchName)
+		}
+	}
+	if o.PostOnly && (o.TimeInForce == IOC || o.TimeInForce == FOK) {
+		return fmt.Errorf("%s post only orders cannot be %s", exchName, o.TimeInForce)
+	}
+	return nil
 }
 
 // FeeBuilder is the type which holds all parameters required to calculate a fee for an exchange
@@ -94,6 +391,7 @@ type OrderCancellation struct {
 	AccountID     string
 	OrderID       string
 	CurrencyPair  pair.CurrencyPair
+	AssetType     string
 	WalletAddress string
 	Side          OrderSide
 }
@@ -192,20 +490,55 @@ type AccountInfo struct {
 	Accounts []Account
 }
 
-// Account defines a singular account type with asocciated currencies
+// Account types of exchanges holding balances in separate wallets
+const (
+	AccountTypeSpot    = "spot"
+	AccountTypeMargin  = "margin"
+	AccountTypeFutures = "futures"
+)
+
+// Account defines a singular account type with asocciated currencies. Type is
+// set by exchanges holding balances in separate wallets
 type Account struct {
 	ID         string
+	Type       string `json:",omitempty"`
 	Currencies []AccountCurrencyInfo
 }
 
-// AccountCurrencyInfo is a sub type to store currency name and value
+// AccountCurrencyInfo is a sub type to store currency name and value.
+// AvailableValue, MarginBalance and UnrealisedPNL are only set by exchanges
+// which report them
 type AccountCurrencyInfo struct {
-	CurrencyName string
-	TotalValue   float64
-	Hold         float64
+	CurrencyName   string
+	TotalValue     float64
+	Hold           float64
+	AvailableValue *float64 `json:",omitempty"`
+	MarginBalance  *float64 `json:",omitempty"`
+	UnrealisedPNL  *float64 `json:",omitempty"`
 }
 
-// TradeHistory holds exchange history data
+// Available returns the value available to trade or withdraw, TotalValue less
+// Hold when the exchange doesn't report it
+func (a *AccountCurrencyInfo) Available() float64 {
+	if a.AvailableValue != nil {
+		return *a.AvailableValue
+	}
+	return a.TotalValue - a.Hold
+}
+
+// Position holds an open derivative position. BaseAmount and QuoteAmount are
+// the position's exposure to the pair currencies, the base amount is positive
+// and the quote amount negative when long. UnrealisedPNL is in the settlement
+// currency
+type Position struct {
+	Pair               pair.CurrencyPair
+	BaseAmount         float64
+	QuoteAmount        float64
+	SettlementCurrency string
+	UnrealisedPNL      float64
+}
+
+// TradeHistory holds exchange history data, the timestamp is in unix seconds
 type TradeHistory struct {
 	Timestamp int64
 	TID       int64
@@ -228,6 +561,11 @@ type OrderDetail struct {
 	Price         float64
 	Amount        float64
 	OpenVolume    float64
+	// ExecutedAmount is the amount of the order filled so far
+	ExecutedAmount float64
+	// AveragePrice is the average execution price of the filled amount, zero
+	// if the exchange doesn't report it
+	AveragePrice float64
 }
 
 // FundHistory holds exchange funding history data
@@ -244,6 +582,7 @@ type FundHistory struct {
 	CryptoToAddress   string
 	CryptoFromAddress string
 	CryptoTxID        string
+	Confirmations     int64
 	BankTo            string
 	BankFrom          string
 }
@@ -264,9 +603,11 @@ type Base struct {
 	AvailablePairs                             []string
 	EnabledPairs                               []string
 	AssetTypes                                 []string
+	EnabledAssetTypes                          []string
 	PairsLastUpdated                           int64
 	SupportsAutoPairUpdating                   bool
 	SupportsRESTTickerBatching                 bool
+	MaxSymbolsPerRequest                       int
 	HTTPTimeout                                time.Duration
 	HTTPUserAgent                              string
 	WebsocketURL                               string
@@ -277,7 +618,26 @@ type Base struct {
 	RequestCurrencyPairFormat                  config.CurrencyPairFormatConfig
 	ConfigCurrencyPairFormat                   config.CurrencyPairFormatConfig
 	Websocket                                  *Websocket
+	APIAccounts                                []config.APICredentialsConfig
 	*request.Requester
+
+	// accountMtx guards APIAccounts
+	accountMtx        sync.Mutex
+	orderLimits       map[string]Limits
+	limitsMtx         sync.RWMutex
+	pairStatuses      map[string]PairStatus
+	pairStatusMtx     sync.RWMutex
+	depositAddresses  map[string]string
+	depositAddressMtx sync.RWMutex
+	serverTimeOffset  time.Duration
+	serverTimeMtx     sync.RWMutex
+
+	currencyDetails         map[string]CurrencyDetails
+	currencyDetailOverrides []config.CurrencyDetailsConfig
+	currencyDetailsMtx      sync.RWMutex
+
+	sandboxEndpoints *SandboxEndpoints
+	sandbox          bool
 }
 
 // IBotExchange enforces standard functions for all exchanges supported in
@@ -286,6 +646,7 @@ type IBotExchange interface {
 	Setup(exch config.ExchangeConfig)
 	Start(wg *sync.WaitGroup)
 	SetDefaults()
+	GetDefaultConfig() config.ExchangeConfig
 	GetName() string
 	IsEnabled() bool
 	SetEnabled(bool)
@@ -296,31 +657,65 @@ type IBotExchange interface {
 	GetEnabledCurrencies() []pair.CurrencyPair
 	GetAvailableCurrencies() []pair.CurrencyPair
 	GetAssetTypes() []string
+	GetSupportedAssetTypes() []string
+	SupportsAsset(assetType string) bool
+	SetAssetTypeEnabled(assetType string, enabled bool) error
 	GetAccountInfo() (AccountInfo, error)
 	GetAuthenticatedAPISupport() bool
 	SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error
-	GetExchangeHistory(pair.CurrencyPair, string) ([]TradeHistory, error)
+	GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]TradeHistory, error)
+	GetHistoricCandles(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]Candle, error)
 	SupportsAutoPairUpdates() bool
 	GetLastPairsUpdateTime() int64
+	UpdateTradablePairs(forceUpdate bool) error
 	SupportsRESTTickerBatchUpdates() bool
+	UpdateTickers(assetType string) error
 
 	GetWithdrawPermissions() uint32
 	FormatWithdrawPermissions() string
 	SupportsWithdrawPermissions(permissions uint32) bool
 
 	GetFundingHistory() ([]FundHistory, error)
-	SubmitOrder(p pair.CurrencyPair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (SubmitOrderResponse, error)
+	GetDepositHistory(currency pair.CurrencyItem, start, end time.Time) ([]FundHistory, error)
+	GetFeeByType(feeBuilder FeeBuilder) (float64, error)
+	SubmitOrder(order *OrderSubmission) (SubmitOrderResponse, error)
 	ModifyOrder(action ModifyOrder) (string, error)
 	CancelOrder(order OrderCancellation) error
 	CancelAllOrders(orders OrderCancellation) (CancelAllOrdersResponse, error)
-	GetOrderInfo(orderID int64) (OrderDetail, error)
-	GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error)
+	GetOrderInfo(orderID string) (OrderDetail, error)
+	GetActiveOrderDetails() ([]OrderDetail, error)
+	GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error)
 
 	WithdrawCryptocurrencyFunds(wtihdrawRequest WithdrawRequest) (string, error)
 	WithdrawFiatFunds(wtihdrawRequest WithdrawRequest) (string, error)
 	WithdrawFiatFundsToInternationalBank(wtihdrawRequest WithdrawRequest) (string, error)
 
 	GetWebsocket() (*Websocket, error)
+
+	SetAPIAccounts(accounts []config.APICredentialsConfig)
+	GetAccountLabels() []string
+
+	GetOrderExecutionLimits(p pair.CurrencyPair) (Limits, error)
+	GetAllOrderExecutionLimits() []Limits
+	GetPairStatuses() []PairStatus
+	IsPairTradable(p pair.CurrencyPair) bool
+	CheckPairTradable(p pair.CurrencyPair) error
+
+	GetServerTime() (time.Time, error)
+	SetServerTimeOffset(offset time.Duration)
+	GetServerTimeOffset() time.Duration
+
+	GetCurrencyDetails() ([]CurrencyDetails, error)
+	SetCurrencyDetailOverrides(overrides []config.CurrencyDetailsConfig)
+
+	SetVerboseBodyLimit(limit int)
+	SetFixtureCapture(dir string)
+	SetRateLimitThreshold(threshold int)
+	GetRateLimitStatus() request.RateLimitStatus
+	GetLatencyStats() request.LatencyStats
+	GetTimeouts() Timeouts
+	GetEndpoints() Endpoints
+	SetEndpoints(apiURL, apiURLSecondary, websocketURL string) error
 }
 
 // SupportsRESTTickerBatchUpdates returns whether or not the
@@ -329,6 +724,19 @@ func (e *Base) SupportsRESTTickerBatchUpdates() bool {
 	return e.SupportsRESTTickerBatching
 }
 
+// UpdateTickers refreshes the tickers of all enabled currency pairs for an
+// asset type in a single request and stores them in the ticker cache.
+// Exchanges with a batch ticker endpoint override it
+func (e *Base) UpdateTickers(assetType string) error {
+	return common.ErrFunctionNotSupported
+}
+
+// GetActiveOrderDetails returns the open orders of the account across all
+// currency pairs. Exchanges with an order list endpoint override it
+func (e *Base) GetActiveOrderDetails() ([]OrderDetail, error) {
+	return nil, common.ErrFunctionNotSupported
+}
+
 // SetHTTPClientTimeout sets the timeout value for the exchanges
 // HTTP Client
 func (e *Base) SetHTTPClientTimeout(t time.Duration) {
@@ -338,7 +746,81 @@ func (e *Base) SetHTTPClientTimeout(t time.Duration) {
 			request.NewRateLimit(time.Second, 0),
 			new(http.Client))
 	}
-	e.Requester.HTTPClient.Timeout = t
+	e.HTTPTimeout = t
+	e.Requester.SetTimeout(t)
+	if e.Websocket != nil {
+		e.Websocket.SetDialTimeout(t)
+	}
+}
+
+// Timeouts holds the effective timeouts of an exchange's REST requests and
+// websocket connection
+type Timeouts struct {
+	HTTP           time.Duration `json:"http"`
+	TLSHandshake   time.Duration `json:"tlsHandshake"`
+	ResponseHeader time.Duration `json:"responseHeader"`
+	WebsocketDial  time.Duration `json:"websocketDial,omitempty"`
+}
+
+// GetTimeouts returns the effective timeouts of the exchange's REST requests
+// and, if it supports websockets, its websocket dial timeout
+func (e *Base) GetTimeouts() Timeouts {
+	var t Timeouts
+	t.HTTP, t.TLSHandshake, t.ResponseHeader = e.Requester.GetTimeouts()
+	if e.Websocket != nil {
+		t.WebsocketDial = e.Websocket.GetDialTimeout()
+	}
+	return t
+}
+
+// SetVerboseBodyLimit sets the number of bytes of request and response bodies
+// logged when verbose, the request package default is used if zero and bodies
+// aren't truncated if negative
+func (e *Base) SetVerboseBodyLimit(limit int) {
+	if e.Requester == nil {
+		e.Requester = request.New(e.Name,
+			request.NewRateLimit(time.Second, 0),
+			request.NewRateLimit(time.Second, 0),
+			new(http.Client))
+	}
+	e.Requester.VerboseBodyLimit = limit
+}
+
+// SetFixtureCapture sets the directory each request and its response are
+// captured to as fixtures for offline tests, capturing is disabled if empty
+func (e *Base) SetFixtureCapture(dir string) {
+	if e.Requester == nil {
+		e.Requester = request.New(e.Name,
+			request.NewRateLimit(time.Second, 0),
+			request.NewRateLimit(time.Second, 0),
+			new(http.Client))
+	}
+	e.Requester.FixtureDir = dir
+}
+
+// SetRateLimitThreshold sets the remaining request count reported by the
+// exchange below which requests are slowed down, the request package default
+// is used if zero and requests aren't slowed down if negative
+func (e *Base) SetRateLimitThreshold(threshold int) {
+	if e.Requester == nil {
+		e.Requester = request.New(e.Name,
+			request.NewRateLimit(time.Second, 0),
+			request.NewRateLimit(time.Second, 0),
+			new(http.Client))
+	}
+	e.Requester.RateLimitThreshold = threshold
+}
+
+// GetRateLimitStatus returns the latest API rate limit consumption reported by
+// the exchange
+func (e *Base) GetRateLimitStatus() request.RateLimitStatus {
+	return e.Requester.GetRateLimitStatus()
+}
+
+// GetLatencyStats returns the REST request latency percentiles of the exchange
+// by endpoint class
+func (e *Base) GetLatencyStats() request.LatencyStats {
+	return e.Requester.GetLatencyStats()
 }
 
 // SetHTTPClient sets exchanges HTTP client
@@ -448,7 +930,8 @@ func (e *Base) GetLastPairsUpdateTime() int64 {
 }
 
 // SetAssetTypes checks the exchange asset types (whether it supports SPOT,
-// Binary or Futures) and sets it to a default setting if it doesn't exist
+// Binary or Futures) and stores them in the config. The enabled asset types
+// default to all supported asset types if not set
 func (e *Base) SetAssetTypes() error {
 	cfg := config.GetConfig()
 	exch, err := cfg.GetExchangeConfig(e.Name)
@@ -456,29 +939,95 @@ func (e *Base) SetAssetTypes() error {
 		return err
 	}
 
-	var update bool
-	if exch.AssetTypes == "" {
-		exch.AssetTypes = common.JoinStrings(e.AssetTypes, ",")
-		update = true
-	} else {
-		exch.AssetTypes = common.JoinStrings(e.AssetTypes, ",")
-		update = true
+	exch.AssetTypes = common.JoinStrings(e.AssetTypes, ",")
+	if exch.EnabledAssetTypes == "" {
+		exch.EnabledAssetTypes = exch.AssetTypes
 	}
 
-	if update {
-		return cfg.UpdateExchangeConfig(exch)
+	var enabledAssets []string
+	if exch.EnabledAssetTypes != "" {
+		for _, a := range common.SplitStrings(exch.EnabledAssetTypes, ",") {
+			assetType, err := assets.Parse(a, e.AssetTypes)
+			if err != nil {
+				return fmt.Errorf("%s enabled asset types: %w", e.Name, err)
+			}
+			enabledAssets = append(enabledAssets, assetType)
+		}
 	}
+	e.EnabledAssetTypes = enabledAssets
+	exch.EnabledAssetTypes = common.JoinStrings(enabledAssets, ",")
 
-	return nil
+	return cfg.UpdateExchangeConfig(exch)
 }
 
-// GetAssetTypes returns the available asset types for an individual exchange
+// GetAssetTypes returns the enabled asset types for an individual exchange
 func (e *Base) GetAssetTypes() []string {
+	if e.EnabledAssetTypes == nil {
+		return e.AssetTypes
+	}
+	return e.EnabledAssetTypes
+}
+
+// GetSupportedAssetTypes returns all asset types supported by an individual
+// exchange, whether enabled or not
+func (e *Base) GetSupportedAssetTypes() []string {
 	return e.AssetTypes
 }
 
-// GetExchangeAssetTypes returns the asset types the exchange supports (SPOT,
-// binary, futures)
+// SupportsAsset returns whether or not the exchange supports the asset type
+func (e *Base) SupportsAsset(assetType string) bool {
+	return common.StringDataCompare(e.AssetTypes, assetType)
+}
+
+// SetAssetTypeEnabled enables or disables fetching data for a supported asset
+// type and updates the config. At least one asset type must remain enabled
+func (e *Base) SetAssetTypeEnabled(assetType string, enabled bool) error {
+	assetType, err := assets.Parse(assetType, e.AssetTypes)
+	if err != nil {
+		return fmt.Errorf("%s %w", e.Name, err)
+	}
+
+	current := e.GetAssetTypes()
+	var enabledAssets []string
+	for _, a := range e.AssetTypes {
+		if (a == assetType && enabled) ||
+			(a != assetType && common.StringDataCompare(current, a)) {
+			enabledAssets = append(enabledAssets, a)
+		}
+	}
+	if len(enabledAssets) == 0 {
+		return fmt.Errorf("%s cannot disable %s, at least one asset type must be enabled",
+			e.Name, assetType)
+	}
+
+	cfg := config.GetConfig()
+	exch, err := cfg.GetExchangeConfig(e.Name)
+	if err != nil {
+		return err
+	}
+	exch.EnabledAssetTypes = common.JoinStrings(enabledAssets, ",")
+	err = cfg.UpdateExchangeConfig(exch)
+	if err != nil {
+		return err
+	}
+
+	e.EnabledAssetTypes = enabledAssets
+	return nil
+}
+
+// CheckAssetType returns an error if the asset type is not one of the
+// exchange's supported asset types
+func (e *Base) CheckAssetType(assetType string) error {
+	if !e.SupportsAsset(assetType) {
+		return fmt.Errorf("%s %w %q", e.Name, assets.ErrInvalidAssetType, assetType)
+	}
+	return nil
+}
+
+// GetExchangeAssetTypes returns the asset types enabled for the exchange
+// (SPOT, binary, futures), falling back to the supported asset types if none
+// are set. Enabled asset types are validated against the supported asset
+// types stored in the config by SetAssetTypes
 func GetExchangeAssetTypes(exchName string) ([]string, error) {
 	cfg := config.GetConfig()
 	exch, err := cfg.GetExchangeConfig(exchName)
@@ -486,7 +1035,20 @@ func GetExchangeAssetTypes(exchName string) ([]string, error) {
 		return nil, err
 	}
 
-	return common.SplitStrings(exch.AssetTypes, ","), nil
+	supported := common.SplitStrings(exch.AssetTypes, ",")
+	if exch.EnabledAssetTypes == "" {
+		return supported, nil
+	}
+
+	var assetTypes []string
+	for _, a := range common.SplitStrings(exch.EnabledAssetTypes, ",") {
+		assetType, err := assets.Parse(a, supported)
+		if err != nil {
+			return nil, fmt.Errorf("exchange %s: %w", exchName, err)
+		}
+		assetTypes = append(assetTypes, assetType)
+	}
+	return assetTypes, nil
 }
 
 // GetClientBankAccounts returns banking details associated with
@@ -509,12 +1071,42 @@ func CompareCurrencyPairFormats(pair1 config.CurrencyPairFormatConfig, pair2 *co
 	if pair1.Delimiter != pair2.Delimiter ||
 		pair1.Uppercase != pair2.Uppercase ||
 		pair1.Separator != pair2.Separator ||
-		pair1.Index != pair2.Index {
+		pair1.Index != pair2.Index ||
+		pair1.QuoteFirst != pair2.QuoteFirst {
 		return false
 	}
 	return true
 }
 
+// GetDefaultConfig returns the default config of the exchange, used to fill
+// the sections missing from a sparse exchange config. SetDefaults must be
+// called first
+func (e *Base) GetDefaultConfig() config.ExchangeConfig {
+	availablePairs := e.AvailablePairs
+	if len(availablePairs) == 0 {
+		availablePairs = e.EnabledPairs
+	}
+	requestFormat := e.RequestCurrencyPairFormat
+	configFormat := e.ConfigCurrencyPairFormat
+	return config.ExchangeConfig{
+		Name:                      e.Name,
+		RESTPollingDelay:          e.RESTPollingDelay,
+		HTTPTimeout:               DefaultHTTPTimeout,
+		APIKey:                    config.DefaultUnsetAPIKey,
+		APISecret:                 config.DefaultUnsetAPISecret,
+		APIURL:                    config.APIURLNonDefaultMessage,
+		APIURLSecondary:           config.APIURLNonDefaultMessage,
+		WebsocketURL:              config.WebsocketURLNonDefaultMessage,
+		AvailablePairs:            common.JoinStrings(availablePairs, ","),
+		EnabledPairs:              common.JoinStrings(e.EnabledPairs, ","),
+		BaseCurrencies:            common.JoinStrings(e.BaseCurrencies, ","),
+		AssetTypes:                common.JoinStrings(e.AssetTypes, ","),
+		SupportsAutoPairUpdates:   e.SupportsAutoPairUpdating,
+		RequestCurrencyPairFormat: &requestFormat,
+		ConfigCurrencyPairFormat:  &configFormat,
+	}
+}
+
 // SetCurrencyPairFormat checks the exchange request and config currency pair
 // formats and sets it to a default setting if it doesn't exist
 func (e *Base) SetCurrencyPairFormat() error {
@@ -527,10 +1119,11 @@ func (e *Base) SetCurrencyPairFormat() error {
 	update := false
 	if exch.RequestCurrencyPairFormat == nil {
 		exch.RequestCurrencyPairFormat = &config.CurrencyPairFormatConfig{
-			Delimiter: e.RequestCurrencyPairFormat.Delimiter,
-			Uppercase: e.RequestCurrencyPairFormat.Uppercase,
-			Separator: e.RequestCurrencyPairFormat.Separator,
-			Index:     e.RequestCurrencyPairFormat.Index,
+			Delimiter:  e.RequestCurrencyPairFormat.Delimiter,
+			Uppercase:  e.RequestCurrencyPairFormat.Uppercase,
+			Separator:  e.RequestCurrencyPairFormat.Separator,
+			Index:      e.RequestCurrencyPairFormat.Index,
+			QuoteFirst: e.RequestCurrencyPairFormat.QuoteFirst,
 		}
 		update = true
 	} else {
@@ -545,10 +1138,11 @@ func (e *Base) SetCurrencyPairFormat() error {
 
 	if exch.ConfigCurrencyPairFormat == nil {
 		exch.ConfigCurrencyPairFormat = &config.CurrencyPairFormatConfig{
-			Delimiter: e.ConfigCurrencyPairFormat.Delimiter,
-			Uppercase: e.ConfigCurrencyPairFormat.Uppercase,
-			Separator: e.ConfigCurrencyPairFormat.Separator,
-			Index:     e.ConfigCurrencyPairFormat.Index,
+			Delimiter:  e.ConfigCurrencyPairFormat.Delimiter,
+			Uppercase:  e.ConfigCurrencyPairFormat.Uppercase,
+			Separator:  e.ConfigCurrencyPairFormat.Separator,
+			Index:      e.ConfigCurrencyPairFormat.Index,
+			QuoteFirst: e.ConfigCurrencyPairFormat.QuoteFirst,
 		}
 		update = true
 	} else {
@@ -619,35 +1213,116 @@ func GetExchangeFormatCurrencySeperator(exchName string) bool {
 }
 
 // GetAndFormatExchangeCurrencies returns a pair.CurrencyItem string containing
-// the exchanges formatted currency pairs
+// the exchanges formatted currency pairs joined by its request separator. An
+// error is returned for no pairs, a pair missing a currency or several pairs
+// without a separator distinct from the delimiter
 func GetAndFormatExchangeCurrencies(exchName string, pairs []pair.CurrencyPair) (pair.CurrencyItem, error) {
-	var currencyItems pair.CurrencyItem
+	if len(pairs) == 0 {
+		return "", ErrNoCurrencyPairs
+	}
+
 	cfg := config.GetConfig()
 	exch, err := cfg.GetExchangeConfig(exchName)
 	if err != nil {
-		return currencyItems, err
+		return "", err
+	}
+	var format config.CurrencyPairFormatConfig
+	if exch.RequestCurrencyPairFormat != nil {
+		format = *exch.RequestCurrencyPairFormat
+	}
+	if len(pairs) > 1 && (format.Separator == "" || format.Separator == format.Delimiter) {
+		return "", fmt.Errorf("%s %w: %q", exchName, ErrInvalidPairSeparator,
+			format.Separator)
 	}
 
+	formatted := make([]string, len(pairs))
 	for x := range pairs {
-		currencyItems += FormatExchangeCurrency(exchName, pairs[x])
-		if x == len(pairs)-1 {
-			continue
+		p := pairs[x]
+		if p.FirstCurrency == "" || p.SecondCurrency == "" {
+			return "", fmt.Errorf("%s pair %d %w", exchName, x, ErrEmptyCurrencyPair)
+		}
+		if format.QuoteFirst {
+			p = p.Swap()
+		}
+		formatted[x] = p.Display(format.Delimiter, format.Uppercase).String()
+	}
+	return pair.CurrencyItem(common.JoinStrings(formatted, format.Separator)), nil
+}
+
+// FormatExchangeCurrencies formats the pairs for batched symbol request
+// parameters, see GetAndFormatExchangeCurrencies. Exchanges with URL length
+// limits set MaxSymbolsPerRequest and the pairs are split into chunks of at
+// most that many pairs, one per request
+func (e *Base) FormatExchangeCurrencies(pairs []pair.CurrencyPair) ([]string, error) {
+	if len(pairs) == 0 {
+		return nil, ErrNoCurrencyPairs
+	}
+
+	size := len(pairs)
+	if e.MaxSymbolsPerRequest > 0 && e.MaxSymbolsPerRequest < size {
+		size = e.MaxSymbolsPerRequest
+	}
+	var chunks []string
+	for start := 0; start < len(pairs); start += size {
+		end := start + size
+		if end > len(pairs) {
+			end = len(pairs)
 		}
-		currencyItems += pair.CurrencyItem(exch.RequestCurrencyPairFormat.Separator)
+		chunk, err := GetAndFormatExchangeCurrencies(e.Name, pairs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk.String())
 	}
-	return currencyItems, nil
+	return chunks, nil
 }
 
 // FormatExchangeCurrency is a method that formats and returns a currency pair
-// based on the user currency display preferences
+// based on the user currency display preferences. The quote currency is listed
+// first for exchanges whose request pair format is quote first
 func FormatExchangeCurrency(exchName string, p pair.CurrencyPair) pair.CurrencyItem {
 	cfg := config.GetConfig()
 	exch, _ := cfg.GetExchangeConfig(exchName)
 
+	if exch.RequestCurrencyPairFormat.QuoteFirst {
+		p = p.Swap()
+	}
 	return p.Display(exch.RequestCurrencyPairFormat.Delimiter,
 		exch.RequestCurrencyPairFormat.Uppercase)
 }
 
+// ParseExchangeCurrency parses a currency pair formatted with the request pair
+// format of an exchange, so the base and quote currencies of pairs listed
+// quote first are returned the right way around
+func ParseExchangeCurrency(exchName, currency string) (pair.CurrencyPair, error) {
+	cfg := config.GetConfig()
+	exch, err := cfg.GetExchangeConfig(exchName)
+	if err != nil {
+		return pair.CurrencyPair{}, err
+	}
+	format := config.CurrencyPairFormatConfig{}
+	if exch.RequestCurrencyPairFormat != nil {
+		format = *exch.RequestCurrencyPairFormat
+	}
+
+	if format.Delimiter != "" && !strings.Contains(currency, format.Delimiter) ||
+		format.Index != "" && !strings.Contains(currency, format.Index) ||
+		len(currency) <= 3 {
+		return pair.CurrencyPair{}, fmt.Errorf("invalid %s currency pair %q",
+			exchName, currency)
+	}
+	p := pair.FormatPairs([]string{currency}, format.Delimiter, format.Index)[0]
+	if p.Empty() {
+		return pair.CurrencyPair{}, fmt.Errorf("invalid %s currency pair %q",
+			exchName, currency)
+	}
+	if format.QuoteFirst {
+		p = p.Swap()
+	}
+	return pair.NewCurrencyPairFromBaseQuote(p.Base().Upper().String(),
+		p.Quote().Upper().String()), nil
+}
+
 // FormatCurrency is a method that formats and returns a currency pair
 // based on the user currency display preferences
 func FormatCurrency(p pair.CurrencyPair) pair.CurrencyItem {
@@ -687,6 +1362,74 @@ func (e *Base) SetAPIKeys(APIKey, APISecret, ClientID string, b64Decode bool) {
 	}
 }
 
+// SetPEMKey loads and validates the PEM private key used to sign
+// authenticated requests. Authenticated API support is disabled if the key
+// cannot be parsed
+func (e *Base) SetPEMKey(pemKey string) {
+	if !e.AuthenticatedAPISupport {
+		return
+	}
+
+	_, err := common.ParsePEMPrivateKey(pemKey)
+	if err != nil {
+		e.AuthenticatedAPISupport = false
+		log.Warnf(warningPEMKeyInvalid, e.Name, err)
+		return
+	}
+	e.APIAuthPEMKey = pemKey
+}
+
+// SignWithPEMKey signs the SHA256 digest of payload with the exchange PEM key
+func (e *Base) SignWithPEMKey(payload []byte) ([]byte, error) {
+	return SignPEM(e.APIAuthPEMKey, payload)
+}
+
+// SignPEM signs the SHA256 digest of payload with a PEM encoded private key.
+// ECDSA signatures are returned as the fixed width concatenation of r and s,
+// RSA signatures use PKCS #1 v1.5
+func SignPEM(pemKey string, payload []byte) ([]byte, error) {
+	key, err := common.ParsePEMPrivateKey(pemKey)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := common.GetSHA256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return nil, err
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig := make([]byte, size*2)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(sig[size-len(rBytes):size], rBytes)
+		copy(sig[size*2-len(sBytes):], sBytes)
+		return sig, nil
+	case *rsa.PrivateKey:
+		return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest)
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", key)
+}
+
+// SetAPIAccounts sets the labelled sub-account credentials for the exchange
+func (e *Base) SetAPIAccounts(accounts []config.APICredentialsConfig) {
+	e.accountMtx.Lock()
+	e.APIAccounts = accounts
+	e.accountMtx.Unlock()
+}
+
+// GetAccountLabels returns the labels of the configured sub-accounts
+func (e *Base) GetAccountLabels() []string {
+	e.accountMtx.Lock()
+	defer e.accountMtx.Unlock()
+	var labels []string
+	for x := range e.APIAccounts {
+		labels = append(labels, e.APIAccounts[x].Label)
+	}
+	return labels
+}
+
 // SetCurrencies sets the exchange currency pairs for either enabledPairs or
 // availablePairs
 func (e *Base) SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error {
@@ -709,6 +1452,7 @@ func (e *Base) SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error
 	if enabledPairs {
 		exchCfg.EnabledPairs = common.JoinStrings(pairsStr, ",")
 		e.EnabledPairs = pairsStr
+		defer e.updateWebsocketSubscriptions()
 	} else {
 		exchCfg.AvailablePairs = common.JoinStrings(pairsStr, ",")
 		e.AvailablePairs = pairsStr
@@ -717,11 +1461,31 @@ func (e *Base) SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error
 	return cfg.UpdateExchangeConfig(exchCfg)
 }
 
+// PairDifference holds the currency pairs added and removed by a pair update
+type PairDifference struct {
+	New     []string
+	Removed []string
+	// Disabled holds enabled pairs removed because they are no longer
+	// available
+	Disabled []string
+}
+
 // UpdateCurrencies updates the exchange currency pairs for either enabledPairs or
 // availablePairs
 func (e *Base) UpdateCurrencies(exchangeProducts []string, enabled, force bool) error {
+	_, err := e.UpdatePairs(exchangeProducts, enabled, force)
+	return err
+}
+
+// UpdatePairs updates the exchange currency pairs for either enabledPairs or
+// availablePairs and returns the pairs added and removed. Unless forced, an
+// available pairs update which would remove more than the configured
+// percentage of existing pairs is refused. Enabled pairs which are no longer
+// available are disabled
+func (e *Base) UpdatePairs(exchangeProducts []string, enabled, force bool) (PairDifference, error) {
+	var diff PairDifference
 	if len(exchangeProducts) == 0 {
-		return fmt.Errorf("%s UpdateCurrencies error - exchangeProducts is empty", e.Name)
+		return diff, fmt.Errorf("%s UpdatePairs error - exchangeProducts is empty", e.Name)
 	}
 
 	exchangeProducts = common.SplitStrings(common.StringToUpper(common.JoinStrings(exchangeProducts, ",")), ",")
@@ -734,33 +1498,59 @@ func (e *Base) UpdateCurrencies(exchangeProducts []string, enabled, force bool)
 		products = append(products, exchangeProducts[x])
 	}
 
-	var newPairs, removedPairs []string
-	var updateType string
+	if len(products) == 0 {
+		return diff, fmt.Errorf("%s UpdatePairs error - exchangeProducts is empty", e.Name)
+	}
 
+	var updateType string
 	if enabled {
-		newPairs, removedPairs = pair.FindPairDifferences(e.EnabledPairs, products)
+		diff.New, diff.Removed = pair.FindPairDifferences(e.EnabledPairs, products)
 		updateType = "enabled"
 	} else {
-		newPairs, removedPairs = pair.FindPairDifferences(e.AvailablePairs, products)
+		diff.New, diff.Removed = pair.FindPairDifferences(e.AvailablePairs, products)
 		updateType = "available"
 	}
 
-	if force || len(newPairs) > 0 || len(removedPairs) > 0 {
-		cfg := config.GetConfig()
-		exch, err := cfg.GetExchangeConfig(e.Name)
-		if err != nil {
-			return err
+	changed := force || len(diff.New) > 0 || len(diff.Removed) > 0
+	if !changed && enabled {
+		return diff, nil
+	}
+
+	cfg := config.GetConfig()
+	exch, err := cfg.GetExchangeConfig(e.Name)
+	if err != nil {
+		return diff, err
+	}
+
+	if !enabled && !force && len(e.AvailablePairs) > 0 {
+		threshold := exch.PairRemovalThreshold
+		if threshold <= 0 {
+			threshold = DefaultPairRemovalThreshold
+		}
+
+		removed := float64(len(diff.Removed)) / float64(len(e.AvailablePairs)) * 100
+		if removed > threshold {
+			return diff, fmt.Errorf("%s UpdatePairs error - update would remove %d of %d available pairs, exceeding the %v%% removal threshold",
+				e.Name, len(diff.Removed), len(e.AvailablePairs), threshold)
 		}
+	}
+
+	if !enabled {
+		// Available pairs were just fetched from the exchange, so record the
+		// update time even when the pairs are unchanged
+		exch.PairsLastUpdated = time.Now().Unix()
+		e.PairsLastUpdated = exch.PairsLastUpdated
+	}
 
+	if changed {
 		if force {
-			log.Debugf("%s forced update of %s pairs.", e.Name, updateType)
-		} else {
-			if len(newPairs) > 0 {
-				log.Debugf("%s Updating pairs - New: %s.\n", e.Name, newPairs)
-			}
-			if len(removedPairs) > 0 {
-				log.Debugf("%s Updating pairs - Removed: %s.\n", e.Name, removedPairs)
-			}
+			log.Infof("%s forced update of %s pairs.", e.Name, updateType)
+		}
+		if len(diff.New) > 0 {
+			log.Infof("%s Updating %s pairs - New: %s.\n", e.Name, updateType, diff.New)
+		}
+		if len(diff.Removed) > 0 {
+			log.Infof("%s Updating %s pairs - Removed: %s.\n", e.Name, updateType, diff.Removed)
 		}
 
 		if enabled {
@@ -769,10 +1559,42 @@ func (e *Base) UpdateCurrencies(exchangeProducts []string, enabled, force bool)
 		} else {
 			exch.AvailablePairs = common.JoinStrings(products, ",")
 			e.AvailablePairs = products
+			e.disableUnavailablePairs(&exch, &diff)
+		}
+		if enabled || len(diff.Disabled) > 0 {
+			defer e.updateWebsocketSubscriptions()
 		}
-		return cfg.UpdateExchangeConfig(exch)
 	}
-	return nil
+	return diff, cfg.UpdateExchangeConfig(exch)
+}
+
+// disableUnavailablePairs removes enabled pairs which are no longer in the
+// available pairs list, enabled pairs which are still available are kept. If
+// no enabled pairs would remain they are left unchanged
+func (e *Base) disableUnavailablePairs(exch *config.ExchangeConfig, diff *PairDifference) {
+	var remaining []string
+	for x := range e.EnabledPairs {
+		if common.StringDataCompareUpper(e.AvailablePairs, e.EnabledPairs[x]) {
+			remaining = append(remaining, e.EnabledPairs[x])
+			continue
+		}
+		diff.Disabled = append(diff.Disabled, e.EnabledPairs[x])
+	}
+
+	if len(diff.Disabled) == 0 {
+		return
+	}
+
+	if len(remaining) == 0 {
+		log.Warnf("%s none of the enabled pairs are available, leaving enabled pairs unchanged.\n",
+			e.Name)
+		diff.Disabled = nil
+		return
+	}
+
+	log.Infof("%s Disabling unavailable pairs: %s.\n", e.Name, diff.Disabled)
+	exch.EnabledPairs = common.JoinStrings(remaining, ",")
+	e.EnabledPairs = remaining
 }
 
 // ModifyOrder is a an order modifyer
@@ -790,6 +1612,11 @@ type ModifyOrder struct {
 	HiddenOrder       bool
 	FillOrKill        bool
 	PostOnly          bool
+
+	// CancelReplace allows exchanges without native order amendment to
+	// cancel the order and submit a replacement with a new order ID. This
+	// isn't atomic, the order leaves the book and loses its queue position
+	CancelReplace bool
 }
 
 // ModifyOrderResponse is an order modifying return type
@@ -820,8 +1647,21 @@ const (
 	Limit             OrderType = "Limit"
 	Market            OrderType = "Market"
 	ImmediateOrCancel OrderType = "IMMEDIATE_OR_CANCEL"
+	// Stop is a market order placed once the trigger price is reached
+	Stop OrderType = "Stop"
+	// StopLimit is a limit order placed once the trigger price is reached
+	StopLimit OrderType = "StopLimit"
+	// TrailingStop is a stop order whose trigger price trails the market price
+	// by the peg offset
+	TrailingStop OrderType = "TrailingStop"
 )
 
+// IsStop returns whether the order type is triggered by the market price
+// rather than placed immediately
+func (o OrderType) IsStop() bool {
+	return o == Stop || o == StopLimit || o == TrailingStop
+}
+
 // ToString changes the ordertype to the exchange standard and returns a string
 func (o OrderType) ToString() string {
 	return fmt.Sprintf("%v", o)
@@ -841,11 +1681,33 @@ func (o OrderSide) ToString() string {
 	return fmt.Sprintf("%v", o)
 }
 
-// SetAPIURL sets configuration API URL for an exchange
+// SetAPIURL sets configuration API URL for an exchange. The default URLs are
+// used unless the config overrides them, or the sandbox URLs if sandbox mode
+// is enabled and the exchange declares them. Overriding URLs must be valid
+// HTTP or HTTPS URLs
 func (e *Base) SetAPIURL(ec config.ExchangeConfig) error {
 	if ec.APIURL == "" || ec.APIURLSecondary == "" {
 		return errors.New("SetAPIURL error variable zero value")
 	}
+	for _, apiURL := range []string{ec.APIURL, ec.APIURLSecondary} {
+		if apiURL == config.APIURLNonDefaultMessage {
+			continue
+		}
+		err := common.ValidateURL(apiURL, restURLSchemes...)
+		if err != nil {
+			return fmt.Errorf("%s SetAPIURL error: %w", e.Name, err)
+		}
+	}
+
+	// Reloading an exchange restores the defaults of URLs no longer overridden
+	if e.APIUrlDefault != "" {
+		e.APIUrl = e.APIUrlDefault
+	}
+	if e.APIUrlSecondaryDefault != "" {
+		e.APIUrlSecondary = e.APIUrlSecondaryDefault
+	}
+	e.setSandbox(ec.UseSandbox)
+
 	if ec.APIURL != config.APIURLNonDefaultMessage {
 		e.APIUrl = ec.APIURL
 	}
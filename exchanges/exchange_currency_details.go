@@ -0,0 +1,128 @@
+package exchange
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+// Withdrawal errors returned when a withdrawal breaks the currency details of
+// an exchange
+var (
+	ErrWithdrawalSuspended    = errors.New("withdrawals suspended")
+	ErrWithdrawalBelowMinimum = errors.New("withdrawal amount below minimum")
+)
+
+// CurrencyDetails holds the deposit and withdrawal details of a currency on an
+// exchange. Amounts are in the exchange's units for the currency and zero
+// values mean the detail is unknown
+type CurrencyDetails struct {
+	Currency            string  `json:"currency"`
+	Name                string  `json:"name,omitempty"`
+	WithdrawalFee       float64 `json:"withdrawalFee"`
+	MinWithdrawal       float64 `json:"minWithdrawal"`
+	Confirmations       int     `json:"confirmations"`
+	DepositSuspended    bool    `json:"depositSuspended"`
+	WithdrawalSuspended bool    `json:"withdrawalSuspended"`
+}
+
+// GetCurrencyDetails returns the stored currency details with the config
+// overrides applied. Exchanges exposing currency details through their API
+// override it to refresh the stored details first
+func (e *Base) GetCurrencyDetails() ([]CurrencyDetails, error) {
+	e.currencyDetailsMtx.RLock()
+	defer e.currencyDetailsMtx.RUnlock()
+	if len(e.currencyDetails) == 0 && len(e.currencyDetailOverrides) == 0 {
+		return nil, common.ErrFunctionNotSupported
+	}
+
+	details := make(map[string]CurrencyDetails, len(e.currencyDetails))
+	for k, v := range e.currencyDetails {
+		details[k] = v
+	}
+	for x := range e.currencyDetailOverrides {
+		override := e.currencyDetailOverrides[x]
+		key := common.StringToUpper(override.Currency)
+		d, ok := details[key]
+		if !ok {
+			d.Currency = override.Currency
+		}
+		if override.WithdrawalFee != nil {
+			d.WithdrawalFee = *override.WithdrawalFee
+		}
+		if override.MinWithdrawal != nil {
+			d.MinWithdrawal = *override.MinWithdrawal
+		}
+		if override.Confirmations != nil {
+			d.Confirmations = *override.Confirmations
+		}
+		if override.DepositSuspended != nil {
+			d.DepositSuspended = *override.DepositSuspended
+		}
+		if override.WithdrawalSuspended != nil {
+			d.WithdrawalSuspended = *override.WithdrawalSuspended
+		}
+		details[key] = d
+	}
+
+	result := make([]CurrencyDetails, 0, len(details))
+	for _, d := range details {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Currency < result[j].Currency
+	})
+	return result, nil
+}
+
+// SetCurrencyDetails replaces the stored currency details, either an
+// exchange's static table or details fetched from its API
+func (e *Base) SetCurrencyDetails(details []CurrencyDetails) {
+	e.currencyDetailsMtx.Lock()
+	defer e.currencyDetailsMtx.Unlock()
+	e.currencyDetails = make(map[string]CurrencyDetails, len(details))
+	for x := range details {
+		e.currencyDetails[common.StringToUpper(details[x].Currency)] = details[x]
+	}
+}
+
+// SetCurrencyDetailOverrides sets the config overrides applied on top of the
+// stored currency details
+func (e *Base) SetCurrencyDetailOverrides(overrides []config.CurrencyDetailsConfig) {
+	e.currencyDetailsMtx.Lock()
+	defer e.currencyDetailsMtx.Unlock()
+	e.currencyDetailOverrides = overrides
+}
+
+// GetCurrencyDetail returns the details for a single currency from the
+// supplied currency details
+func GetCurrencyDetail(details []CurrencyDetails, currency string) (CurrencyDetails, bool) {
+	for x := range details {
+		if common.StringToUpper(details[x].Currency) == common.StringToUpper(currency) {
+			return details[x], true
+		}
+	}
+	return CurrencyDetails{}, false
+}
+
+// CheckWithdrawal rejects a cryptocurrency withdrawal when withdrawals of the
+// currency are suspended or the amount is below the exchange minimum.
+// Currencies without details aren't checked
+func CheckWithdrawal(details []CurrencyDetails, request WithdrawRequest) error {
+	d, ok := GetCurrencyDetail(details, request.Currency.String())
+	if !ok {
+		return nil
+	}
+	if d.WithdrawalSuspended {
+		return WrapError(ErrWithdrawalSuspended, "%s withdrawals are suspended",
+			d.Currency)
+	}
+	if d.MinWithdrawal > 0 && request.Amount < d.MinWithdrawal {
+		return WrapError(ErrWithdrawalBelowMinimum,
+			"%s withdrawal amount %v is below the minimum of %v", d.Currency,
+			request.Amount, d.MinWithdrawal)
+	}
+	return nil
+}
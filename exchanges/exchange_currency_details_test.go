@@ -0,0 +1,91 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
+)
+
+func TestGetCurrencyDetails(t *testing.T) {
+	var b Base
+	_, err := b.GetCurrencyDetails()
+	if err != common.ErrFunctionNotSupported {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+
+	b.SetCurrencyDetails([]CurrencyDetails{
+		{Currency: "LTC", WithdrawalFee: 0.001},
+		{Currency: "BTC", WithdrawalFee: 0.0005, MinWithdrawal: 0.001},
+	})
+
+	fee := 0.0004
+	suspended := true
+	b.SetCurrencyDetailOverrides([]config.CurrencyDetailsConfig{
+		{Currency: "btc", WithdrawalFee: &fee},
+		{Currency: "ETH", WithdrawalSuspended: &suspended},
+	})
+
+	details, err := b.GetCurrencyDetails()
+	if err != nil {
+		t.Fatalf("Test failed. GetCurrencyDetails() error: %s", err)
+	}
+	if len(details) != 3 || details[0].Currency != "BTC" ||
+		details[1].Currency != "ETH" || details[2].Currency != "LTC" {
+		t.Fatalf("Test failed. Unexpected currency details %+v", details)
+	}
+	if details[0].WithdrawalFee != fee || details[0].MinWithdrawal != 0.001 {
+		t.Errorf("Test failed. Override not applied to BTC %+v", details[0])
+	}
+	if !details[1].WithdrawalSuspended {
+		t.Errorf("Test failed. Override not applied to ETH %+v", details[1])
+	}
+
+	d, ok := GetCurrencyDetail(details, "ltc")
+	if !ok || d.WithdrawalFee != 0.001 {
+		t.Errorf("Test failed. GetCurrencyDetail() returned %+v, %v", d, ok)
+	}
+	if _, ok = GetCurrencyDetail(details, "XRP"); ok {
+		t.Error("Test failed. GetCurrencyDetail() found a missing currency")
+	}
+}
+
+func TestCheckWithdrawal(t *testing.T) {
+	details := []CurrencyDetails{
+		{Currency: "BTC", MinWithdrawal: 0.001},
+		{Currency: "ETH", WithdrawalSuspended: true},
+	}
+
+	tests := []struct {
+		currency string
+		amount   float64
+		expected error
+	}{
+		{symbol.BTC, 0.01, nil},
+		{symbol.BTC, 0.001, nil},
+		{symbol.BTC, 0.0001, ErrWithdrawalBelowMinimum},
+		{symbol.ETH, 1, ErrWithdrawalSuspended},
+		{symbol.LTC, 0.0001, nil},
+	}
+
+	for _, test := range tests {
+		err := CheckWithdrawal(details, WithdrawRequest{
+			Currency: pair.CurrencyItem(test.currency),
+			Amount:   test.amount,
+		})
+		if test.expected == nil {
+			if err != nil {
+				t.Errorf("Test failed. %s %v unexpected error %s", test.currency,
+					test.amount, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Test failed. %s %v expected %v, got %v", test.currency,
+				test.amount, test.expected, err)
+		}
+	}
+}
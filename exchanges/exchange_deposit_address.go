@@ -0,0 +1,54 @@
+package exchange
+
+import (
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// depositAddressKey returns a case insensitive key for a currency and
+// account ID
+func depositAddressKey(cryptocurrency pair.CurrencyItem, accountID string) string {
+	return cryptocurrency.Upper().String() + ":" + accountID
+}
+
+// LoadDepositAddress returns the cached deposit address for a currency and
+// account ID, if one has been stored
+func (e *Base) LoadDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, bool) {
+	e.depositAddressMtx.RLock()
+	defer e.depositAddressMtx.RUnlock()
+	addr, ok := e.depositAddresses[depositAddressKey(cryptocurrency, accountID)]
+	return addr, ok
+}
+
+// StoreDepositAddress caches the deposit address for a currency and account
+// ID. Empty addresses are not stored
+func (e *Base) StoreDepositAddress(cryptocurrency pair.CurrencyItem, accountID, address string) {
+	if address == "" {
+		return
+	}
+
+	e.depositAddressMtx.Lock()
+	defer e.depositAddressMtx.Unlock()
+	if e.depositAddresses == nil {
+		e.depositAddresses = make(map[string]string)
+	}
+	e.depositAddresses[depositAddressKey(cryptocurrency, accountID)] = address
+}
+
+// GetCachedDepositAddress returns the cached deposit address for a currency
+// and account ID. If none is cached or forceRefresh is set the address is
+// fetched and cached, failed fetches aren't cached
+func (e *Base) GetCachedDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool, fetch func() (string, error)) (string, error) {
+	if !forceRefresh {
+		if addr, ok := e.LoadDepositAddress(cryptocurrency, accountID); ok {
+			return addr, nil
+		}
+	}
+
+	addr, err := fetch()
+	if err != nil {
+		return "", err
+	}
+
+	e.StoreDepositAddress(cryptocurrency, accountID, addr)
+	return addr, nil
+}
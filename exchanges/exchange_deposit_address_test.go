@@ -0,0 +1,65 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestGetCachedDepositAddress(t *testing.T) {
+	var b Base
+	var apiCalls int
+	var fail bool
+	fetch := func(cryptocurrency pair.CurrencyItem, accountID string) func() (string, error) {
+		return func() (string, error) {
+			apiCalls++
+			if fail {
+				return "", errors.New("api error")
+			}
+			return cryptocurrency.Upper().String() + accountID, nil
+		}
+	}
+
+	_, ok := b.LoadDepositAddress("BTC", "")
+	if ok {
+		t.Fatal("Test failed. LoadDepositAddress returned an address from an empty cache")
+	}
+
+	addr, err := b.GetCachedDepositAddress("BTC", "", false, fetch("BTC", ""))
+	if err != nil || addr != "BTC" || apiCalls != 1 {
+		t.Fatalf("Test failed. GetCachedDepositAddress unexpected result %s %v", addr, err)
+	}
+
+	addr, err = b.GetCachedDepositAddress("btc", "", false, fetch("btc", ""))
+	if err != nil || addr != "BTC" {
+		t.Fatalf("Test failed. GetCachedDepositAddress unexpected result %s %v", addr, err)
+	}
+	if apiCalls != 1 {
+		t.Fatal("Test failed. Cached deposit address should not be fetched")
+	}
+
+	addr, err = b.GetCachedDepositAddress("BTC", "sub", false, fetch("BTC", "sub"))
+	if err != nil || addr != "BTCsub" || apiCalls != 2 {
+		t.Fatal("Test failed. Deposit addresses should be cached per account ID")
+	}
+
+	_, err = b.GetCachedDepositAddress("BTC", "", true, fetch("BTC", ""))
+	if err != nil || apiCalls != 3 {
+		t.Fatal("Test failed. Force refresh should fetch the address")
+	}
+
+	fail = true
+	_, err = b.GetCachedDepositAddress("LTC", "", false, fetch("LTC", ""))
+	if err == nil {
+		t.Fatal("Test failed. Expected fetch error")
+	}
+	if _, ok = b.LoadDepositAddress("LTC", ""); ok {
+		t.Fatal("Test failed. Failed fetch should not be cached")
+	}
+
+	b.StoreDepositAddress("ETH", "", "")
+	if _, ok = b.LoadDepositAddress("ETH", ""); ok {
+		t.Fatal("Test failed. Empty addresses should not be cached")
+	}
+}
@@ -0,0 +1,48 @@
+package exchange
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Deposit statuses of the funding history returned by GetDepositHistory
+const (
+	DepositPending  = "pending"
+	DepositCredited = "credited"
+	DepositFailed   = "failed"
+)
+
+// ErrInvalidTimeRange is returned when the end of a time range is before its
+// start
+var ErrInvalidTimeRange = errors.New("end time is before start time")
+
+// GetDepositHistory returns the deposits of a currency made between start and
+// end, newest first. A zero start or end leaves that side of the range open.
+// Exchanges exposing deposit history override it
+func (e *Base) GetDepositHistory(currency pair.CurrencyItem, start, end time.Time) ([]FundHistory, error) {
+	return nil, common.ErrFunctionNotSupported
+}
+
+// CheckTimeRange returns ErrInvalidTimeRange if both ends of a time range are
+// set and the end is before the start
+func CheckTimeRange(start, end time.Time) error {
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
+// InTimeRange returns whether a unix timestamp is between start and end
+// inclusive. Zero bounds are open
+func InTimeRange(timestamp int64, start, end time.Time) bool {
+	if !start.IsZero() && timestamp < start.Unix() {
+		return false
+	}
+	if !end.IsZero() && timestamp > end.Unix() {
+		return false
+	}
+	return true
+}
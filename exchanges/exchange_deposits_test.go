@@ -0,0 +1,57 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
+)
+
+func TestGetDepositHistory(t *testing.T) {
+	var b Base
+	_, err := b.GetDepositHistory(pair.CurrencyItem(symbol.BTC), time.Time{},
+		time.Time{})
+	if err != common.ErrFunctionNotSupported {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+}
+
+func TestCheckTimeRange(t *testing.T) {
+	now := time.Now()
+	if err := CheckTimeRange(time.Time{}, time.Time{}); err != nil {
+		t.Errorf("Test failed. Open range error: %s", err)
+	}
+	if err := CheckTimeRange(now, now.Add(time.Hour)); err != nil {
+		t.Errorf("Test failed. Valid range error: %s", err)
+	}
+	if err := CheckTimeRange(now, time.Time{}); err != nil {
+		t.Errorf("Test failed. Open ended range error: %s", err)
+	}
+	if err := CheckTimeRange(now, now.Add(-time.Hour)); err != ErrInvalidTimeRange {
+		t.Errorf("Test failed. Expected %v, got %v", ErrInvalidTimeRange, err)
+	}
+}
+
+func TestInTimeRange(t *testing.T) {
+	start := time.Unix(1538740800, 0)
+	end := start.Add(time.Hour)
+	tests := []struct {
+		timestamp  int64
+		start, end time.Time
+		expected   bool
+	}{
+		{start.Unix(), start, end, true},
+		{end.Unix(), start, end, true},
+		{start.Unix() - 1, start, end, false},
+		{end.Unix() + 1, start, end, false},
+		{0, time.Time{}, end, true},
+		{end.Unix() + 1, start, time.Time{}, true},
+	}
+	for i := range tests {
+		if InTimeRange(tests[i].timestamp, tests[i].start, tests[i].end) != tests[i].expected {
+			t.Errorf("Test failed. Case %d expected %v", i, tests[i].expected)
+		}
+	}
+}
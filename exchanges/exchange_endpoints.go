@@ -0,0 +1,119 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// URL schemes accepted for REST and websocket endpoint overrides
+var (
+	restURLSchemes      = []string{"http", "https"}
+	websocketURLSchemes = []string{"ws", "wss"}
+)
+
+// Endpoints holds the active REST and websocket endpoints of an exchange and
+// whether its sandbox endpoints are in use
+type Endpoints struct {
+	APIURL          string `json:"apiUrl"`
+	APIURLSecondary string `json:"apiUrlSecondary,omitempty"`
+	WebsocketURL    string `json:"websocketUrl,omitempty"`
+	Sandbox         bool   `json:"sandbox"`
+}
+
+// SandboxEndpoints holds the sandbox or testnet endpoints of an exchange, an
+// empty URL keeps the live endpoint
+type SandboxEndpoints struct {
+	APIURL          string
+	APIURLSecondary string
+	WebsocketURL    string
+}
+
+// SetSandboxEndpoints declares the sandbox endpoints the exchange uses when
+// sandbox mode is enabled in its config
+func (e *Base) SetSandboxEndpoints(s SandboxEndpoints) {
+	e.sandboxEndpoints = &s
+}
+
+// SupportsSandbox returns whether the exchange declares sandbox endpoints
+func (e *Base) SupportsSandbox() bool {
+	return e.sandboxEndpoints != nil
+}
+
+// IsSandbox returns whether the exchange is using its sandbox endpoints
+func (e *Base) IsSandbox() bool {
+	return e.sandbox
+}
+
+// setSandbox switches the REST endpoints to the sandbox endpoints if enabled
+// and declared. The websocket endpoint is switched by WebsocketSetup
+func (e *Base) setSandbox(enabled bool) {
+	e.sandbox = false
+	if !enabled {
+		return
+	}
+	if e.sandboxEndpoints == nil {
+		log.Warnf("%s has no sandbox endpoints, using its live endpoints.", e.Name)
+		return
+	}
+
+	e.sandbox = true
+	if e.sandboxEndpoints.APIURL != "" {
+		e.APIUrl = e.sandboxEndpoints.APIURL
+	}
+	if e.sandboxEndpoints.APIURLSecondary != "" {
+		e.APIUrlSecondary = e.sandboxEndpoints.APIURLSecondary
+	}
+}
+
+// GetEndpoints returns the active REST and websocket endpoints of the exchange
+func (e *Base) GetEndpoints() Endpoints {
+	endpoints := Endpoints{
+		APIURL:          e.APIUrl,
+		APIURLSecondary: e.APIUrlSecondary,
+		Sandbox:         e.sandbox,
+	}
+	if e.Websocket != nil {
+		endpoints.WebsocketURL = e.Websocket.GetWebsocketURL()
+	}
+	return endpoints
+}
+
+// SetEndpoints overrides the REST and websocket endpoints of the exchange at
+// runtime, empty URLs are left unchanged. Every URL is validated before any is
+// applied and a connected websocket is reconnected to a changed websocket URL
+func (e *Base) SetEndpoints(apiURL, apiURLSecondary, websocketURL string) error {
+	for _, u := range []string{apiURL, apiURLSecondary} {
+		if u == "" {
+			continue
+		}
+		err := common.ValidateURL(u, restURLSchemes...)
+		if err != nil {
+			return err
+		}
+	}
+	if websocketURL != "" {
+		if e.Websocket == nil {
+			return fmt.Errorf("%s doesn't support websockets", e.Name)
+		}
+		err := common.ValidateURL(websocketURL, websocketURLSchemes...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if apiURL != "" {
+		e.APIUrl = apiURL
+	}
+	if apiURLSecondary != "" {
+		e.APIUrlSecondary = apiURLSecondary
+	}
+	if websocketURL != "" && websocketURL != e.Websocket.GetWebsocketURL() {
+		err := e.Websocket.ChangeURL(websocketURL)
+		if err != nil {
+			return fmt.Errorf("%s websocket reconnection error: %s", e.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+const (
+	testLiveAPIURL       = "https://api.live.test"
+	testSandboxAPIURL    = "https://api.sandbox.test"
+	testLiveWebsocket    = "wss://ws.live.test"
+	testSandboxWebsocket = "wss://ws.sandbox.test"
+)
+
+// newEndpointsTestConfig returns an exchange config using the default URLs
+func newEndpointsTestConfig(sandbox bool) config.ExchangeConfig {
+	return config.ExchangeConfig{
+		APIURL:          config.APIURLNonDefaultMessage,
+		APIURLSecondary: config.APIURLNonDefaultMessage,
+		WebsocketURL:    config.WebsocketURLNonDefaultMessage,
+		UseSandbox:      sandbox,
+	}
+}
+
+// setupEndpointsTest sets up an exchange with live and sandbox endpoints and a
+// websocket counting its connections
+func setupEndpointsTest(t *testing.T, ec config.ExchangeConfig, connections *int) *Base {
+	b := &Base{Name: "EndpointsTest", APIUrlDefault: testLiveAPIURL}
+	b.SetSandboxEndpoints(SandboxEndpoints{
+		APIURL:       testSandboxAPIURL,
+		WebsocketURL: testSandboxWebsocket,
+	})
+	b.WebsocketInit()
+	err := b.SetAPIURL(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.WebsocketSetup(func() error {
+		*connections++
+		return nil
+	}, b.Name, true, testLiveWebsocket, ec.WebsocketURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSetAPIURLValidation(t *testing.T) {
+	b := Base{Name: "EndpointsTest", APIUrlDefault: testLiveAPIURL}
+	for _, apiURL := range []string{"garbage", "wss://api.live.test", "https://"} {
+		ec := newEndpointsTestConfig(false)
+		ec.APIURLSecondary = apiURL
+		err := b.SetAPIURL(ec)
+		if !errors.Is(err, common.ErrInvalidURL) {
+			t.Errorf("test failed - SetAPIURL %q expected %v, got %v", apiURL,
+				common.ErrInvalidURL, err)
+		}
+	}
+
+	var connections int
+	ec := newEndpointsTestConfig(false)
+	ec.WebsocketURL = "https://ws.live.test"
+	b.WebsocketInit()
+	err := b.WebsocketSetup(func() error {
+		connections++
+		return nil
+	}, b.Name, true, testLiveWebsocket, ec.WebsocketURL)
+	if !errors.Is(err, common.ErrInvalidURL) {
+		t.Errorf("test failed - WebsocketSetup expected %v, got %v", common.ErrInvalidURL, err)
+	}
+}
+
+func TestSandboxEndpoints(t *testing.T) {
+	var connections int
+	b := setupEndpointsTest(t, newEndpointsTestConfig(true), &connections)
+	endpoints := b.GetEndpoints()
+	if !endpoints.Sandbox || endpoints.APIURL != testSandboxAPIURL ||
+		endpoints.WebsocketURL != testSandboxWebsocket {
+		t.Errorf("test failed - unexpected sandbox endpoints %+v", endpoints)
+	}
+
+	// Reloading without sandbox mode restores the live endpoints and
+	// reconnects the websocket
+	done := drainWebsocketEvents(b.Websocket)
+	defer close(done)
+	err := b.Websocket.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Websocket.Shutdown()
+	ec := newEndpointsTestConfig(false)
+	err = b.SetAPIURL(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.WebsocketSetup(nil, b.Name, true, testLiveWebsocket, ec.WebsocketURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints = b.GetEndpoints()
+	if endpoints.Sandbox || endpoints.APIURL != testLiveAPIURL ||
+		endpoints.WebsocketURL != testLiveWebsocket {
+		t.Errorf("test failed - unexpected live endpoints %+v", endpoints)
+	}
+	if connections != 2 || !b.Websocket.IsConnected() {
+		t.Errorf("test failed - expected the websocket to reconnect, connections %d",
+			connections)
+	}
+
+	// Exchanges without sandbox endpoints keep their live endpoints
+	live := Base{Name: "LiveOnly", APIUrlDefault: testLiveAPIURL}
+	err = live.SetAPIURL(newEndpointsTestConfig(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.IsSandbox() || live.SupportsSandbox() || live.GetAPIURL() != testLiveAPIURL {
+		t.Errorf("test failed - unexpected endpoints %+v", live.GetEndpoints())
+	}
+}
+
+func TestSetEndpoints(t *testing.T) {
+	var connections int
+	b := setupEndpointsTest(t, newEndpointsTestConfig(false), &connections)
+
+	err := b.SetEndpoints(testSandboxAPIURL, "", "ftp://ws.sandbox.test")
+	if !errors.Is(err, common.ErrInvalidURL) {
+		t.Errorf("test failed - SetEndpoints expected %v, got %v", common.ErrInvalidURL, err)
+	}
+	if b.GetAPIURL() != testLiveAPIURL {
+		t.Error("test failed - endpoints changed by a rejected override")
+	}
+
+	done := drainWebsocketEvents(b.Websocket)
+	defer close(done)
+	err = b.Websocket.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Websocket.Shutdown()
+	err = b.SetEndpoints(testSandboxAPIURL, "", testSandboxWebsocket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints := b.GetEndpoints()
+	if endpoints.APIURL != testSandboxAPIURL || endpoints.WebsocketURL != testSandboxWebsocket ||
+		endpoints.Sandbox {
+		t.Errorf("test failed - unexpected endpoints %+v", endpoints)
+	}
+	if connections != 2 || !b.Websocket.IsConnected() {
+		t.Errorf("test failed - expected the websocket to reconnect, connections %d",
+			connections)
+	}
+
+	noWebsocket := Base{Name: "NoWebsocket"}
+	err = noWebsocket.SetEndpoints("", "", testSandboxWebsocket)
+	if err == nil {
+		t.Error("test failed - expected an error for an exchange without websockets")
+	}
+}
+
+// drainWebsocketEvents discards the connection events of a websocket, like the
+// connection monitor of a running bot, until the returned channel is closed
+func drainWebsocketEvents(w *Websocket) chan struct{} {
+	done := make(chan struct{})
+	connected, disconnected := w.Connected, w.Disconnected
+	go func() {
+		for {
+			select {
+			case <-connected:
+			case <-disconnected:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return done
+}
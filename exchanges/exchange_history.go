@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Candle holds the OHLCV data for a single candle interval
+type Candle struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+}
+
+// ParseCandles parses kline rows of a millisecond timestamp followed by the
+// open, high, low, close and volume values, sent as numbers or strings
+func ParseCandles(rows [][]interface{}) ([]Candle, error) {
+	candles := make([]Candle, 0, len(rows))
+	for x := range rows {
+		if len(rows[x]) < 6 {
+			return nil, fmt.Errorf("kline row %d has %d values, expected at least 6",
+				x, len(rows[x]))
+		}
+
+		var values [6]float64
+		for y := range values {
+			value, err := parseCandleValue(rows[x][y])
+			if err != nil {
+				return nil, fmt.Errorf("kline row %d value %d: %s", x, y, err)
+			}
+			values[y] = value
+		}
+
+		candles = append(candles, Candle{
+			Time:   time.Unix(0, int64(values[0])*int64(time.Millisecond)),
+			Open:   values[1],
+			High:   values[2],
+			Low:    values[3],
+			Close:  values[4],
+			Volume: values[5],
+		})
+	}
+	return candles, nil
+}
+
+// parseCandleValue parses a kline value sent as a number or a string
+func parseCandleValue(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("unexpected value type %T", value)
+	}
+}
+
+// GetHistoricCandles returns a batch of candles of the supplied interval from
+// the start time onwards. Exchanges supporting candle history override this
+func (e *Base) GetHistoricCandles(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]Candle, error) {
+	return nil, common.ErrNotYetImplemented
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCandles(t *testing.T) {
+	candles, err := ParseCandles([][]interface{}{
+		{1417536000000.0, "370.5", "375", "368.1", "372.2", "1200.5"},
+		{1417536060000.0, 372.2, 373.0, 371.5, 372.9, 300.0, 5.5},
+	})
+	if err != nil {
+		t.Fatalf("Test failed. ParseCandles error: %s", err)
+	}
+	expected := []Candle{
+		{Time: time.Unix(1417536000, 0), Open: 370.5, High: 375, Low: 368.1, Close: 372.2, Volume: 1200.5},
+		{Time: time.Unix(1417536060, 0), Open: 372.2, High: 373, Low: 371.5, Close: 372.9, Volume: 300},
+	}
+	if len(candles) != len(expected) {
+		t.Fatalf("Test failed. ParseCandles expected %d candles, got %d", len(expected), len(candles))
+	}
+	for x := range expected {
+		if !candles[x].Time.Equal(expected[x].Time) || candles[x].Open != expected[x].Open ||
+			candles[x].High != expected[x].High || candles[x].Low != expected[x].Low ||
+			candles[x].Close != expected[x].Close || candles[x].Volume != expected[x].Volume {
+			t.Errorf("Test failed. ParseCandles expected %+v, got %+v", expected[x], candles[x])
+		}
+	}
+
+	tests := []struct {
+		name string
+		rows [][]interface{}
+	}{
+		{"short row", [][]interface{}{{1417536000000.0, "1", "2", "3", "4"}}},
+		{"invalid number", [][]interface{}{{1417536000000.0, "1", "two", "3", "4", "5"}}},
+		{"invalid timestamp", [][]interface{}{{"yesterday", "1", "2", "3", "4", "5"}}},
+		{"unexpected type", [][]interface{}{{1417536000000.0, "1", "2", nil, "4", "5"}}},
+		{"malformed second row", [][]interface{}{
+			{1417536000000.0, "1", "2", "3", "4", "5"},
+			{1417536060000.0, true, "2", "3", "4", "5"},
+		}},
+	}
+	for x := range tests {
+		if _, err := ParseCandles(tests[x].rows); err == nil {
+			t.Errorf("Test failed. ParseCandles %s expected an error", tests[x].name)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package exchange
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Limits holds the order execution limits and precision for a currency pair
+type Limits struct {
+	Pair       pair.CurrencyPair `json:"pair"`
+	MinAmount  float64           `json:"minAmount"`
+	MaxAmount  float64           `json:"maxAmount,omitempty"`
+	AmountStep float64           `json:"amountStep,omitempty"`
+	PriceStep  float64           `json:"priceStep,omitempty"`
+}
+
+// limitsKey returns a delimiter and case insensitive key for a currency pair
+func limitsKey(p pair.CurrencyPair) string {
+	return p.FirstCurrency.Upper().String() + p.SecondCurrency.Upper().String()
+}
+
+// SetOrderExecutionLimits replaces the stored order execution limits
+func (e *Base) SetOrderExecutionLimits(limits []Limits) {
+	l := make(map[string]Limits, len(limits))
+	for x := range limits {
+		l[limitsKey(limits[x].Pair)] = limits[x]
+	}
+
+	e.limitsMtx.Lock()
+	e.orderLimits = l
+	e.limitsMtx.Unlock()
+}
+
+// GetOrderExecutionLimits returns the order execution limits for a currency
+// pair
+func (e *Base) GetOrderExecutionLimits(p pair.CurrencyPair) (Limits, error) {
+	e.limitsMtx.RLock()
+	defer e.limitsMtx.RUnlock()
+	l, ok := e.orderLimits[limitsKey(p)]
+	if !ok {
+		return Limits{}, fmt.Errorf("%s order execution limits for %s not loaded",
+			e.Name, p.Pair().String())
+	}
+	return l, nil
+}
+
+// GetAllOrderExecutionLimits returns the order execution limits for all
+// currency pairs
+func (e *Base) GetAllOrderExecutionLimits() []Limits {
+	e.limitsMtx.RLock()
+	defer e.limitsMtx.RUnlock()
+	var result []Limits
+	for _, l := range e.orderLimits {
+		result = append(result, l)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return limitsKey(result[i].Pair) < limitsKey(result[j].Pair)
+	})
+	return result
+}
+
+// CheckOrderExecutionLimits validates an order amount and price against the
+// stored limits for a currency pair, returning the amount rounded down to the
+// amount step and the price rounded to the price step. Orders for pairs
+// without stored limits are returned unchanged
+func (e *Base) CheckOrderExecutionLimits(p pair.CurrencyPair, amount, price float64, orderType OrderType) (float64, float64, error) {
+	l, err := e.GetOrderExecutionLimits(p)
+	if err != nil {
+		return amount, price, nil
+	}
+
+	if l.AmountStep > 0 {
+		amount = roundToStep(amount, l.AmountStep, true)
+	}
+
+	if amount <= 0 {
+		return amount, price, fmt.Errorf("amount %v below amount step %v %s",
+			amount, l.AmountStep, p.FirstCurrency.Upper())
+	}
+
+	if amount < l.MinAmount {
+		return amount, price, fmt.Errorf("amount %v below minimum %v %s",
+			amount, l.MinAmount, p.FirstCurrency.Upper())
+	}
+
+	if l.MaxAmount > 0 && amount > l.MaxAmount {
+		return amount, price, fmt.Errorf("amount %v above maximum %v %s",
+			amount, l.MaxAmount, p.FirstCurrency.Upper())
+	}
+
+	if (orderType == Limit || orderType == StopLimit) && l.PriceStep > 0 {
+		price = roundToStep(price, l.PriceStep, false)
+		if price <= 0 {
+			return amount, price, fmt.Errorf("price %v below price step %v %s",
+				price, l.PriceStep, p.SecondCurrency.Upper())
+		}
+	}
+	return amount, price, nil
+}
+
+// roundToStep rounds a value to a multiple of step, rounding down if floor is
+// set or to the nearest step otherwise. The result is trimmed to the step's
+// precision to avoid floating point artifacts
+func roundToStep(value, step float64, floor bool) float64 {
+	// Small epsilon so values already on a step aren't floored below it
+	steps := value / step
+	if floor {
+		steps = math.Floor(steps + 1e-9)
+	} else {
+		steps = math.Round(steps)
+	}
+
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step) - 1e-9))
+	}
+	result, _ := strconv.ParseFloat(strconv.FormatFloat(steps*step, 'f', decimals, 64), 64)
+	return result
+}
@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestOrderExecutionLimits(t *testing.T) {
+	var b Base
+	b.Name = "TESTNAME"
+	p := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+
+	_, err := b.GetOrderExecutionLimits(p)
+	if err == nil {
+		t.Fatal("Test failed. TestOrderExecutionLimits expected error for unloaded limits")
+	}
+
+	amount, price, err := b.CheckOrderExecutionLimits(p, 0.00012345, 100.123, Limit)
+	if err != nil || amount != 0.00012345 || price != 100.123 {
+		t.Fatal("Test failed. TestOrderExecutionLimits order modified without limits")
+	}
+
+	b.SetOrderExecutionLimits([]Limits{
+		{
+			Pair:       pair.NewCurrencyPair("btc", "usd"),
+			MinAmount:  0.001,
+			MaxAmount:  100,
+			AmountStep: 0.0001,
+			PriceStep:  0.1,
+		},
+		{
+			Pair:      pair.NewCurrencyPair("ETH", "BTC"),
+			MinAmount: 0.01,
+		},
+	})
+
+	if len(b.GetAllOrderExecutionLimits()) != 2 {
+		t.Fatal("Test failed. TestOrderExecutionLimits unexpected limits length")
+	}
+
+	_, err = b.GetOrderExecutionLimits(pair.NewCurrencyPair("BTC", "USD"))
+	if err != nil {
+		t.Fatalf("Test failed. TestOrderExecutionLimits %s", err)
+	}
+
+	_, _, err = b.CheckOrderExecutionLimits(p, 0.0001, 100, Limit)
+	if err == nil || err.Error() != "amount 0.0001 below minimum 0.001 BTC" {
+		t.Fatalf("Test failed. TestOrderExecutionLimits unexpected error %v", err)
+	}
+
+	_, _, err = b.CheckOrderExecutionLimits(p, 101, 100, Limit)
+	if err == nil {
+		t.Fatal("Test failed. TestOrderExecutionLimits expected error above maximum")
+	}
+
+	amount, price, err = b.CheckOrderExecutionLimits(p, 0.12345678, 6543.27, Limit)
+	if err != nil {
+		t.Fatalf("Test failed. TestOrderExecutionLimits %s", err)
+	}
+	if amount != 0.1234 || price != 6543.3 {
+		t.Fatalf("Test failed. TestOrderExecutionLimits unexpected rounding %v %v",
+			amount, price)
+	}
+
+	amount, price, err = b.CheckOrderExecutionLimits(p, 0.3, 6543.27, Market)
+	if err != nil {
+		t.Fatalf("Test failed. TestOrderExecutionLimits %s", err)
+	}
+	if amount != 0.3 || price != 6543.27 {
+		t.Fatalf("Test failed. TestOrderExecutionLimits unexpected rounding %v %v",
+			amount, price)
+	}
+}
+
+func TestRoundToStep(t *testing.T) {
+	if r := roundToStep(0.3, 0.1, true); r != 0.3 {
+		t.Errorf("Test failed. TestRoundToStep unexpected result %v", r)
+	}
+
+	if r := roundToStep(17, 5, true); r != 15 {
+		t.Errorf("Test failed. TestRoundToStep unexpected result %v", r)
+	}
+
+	if r := roundToStep(1.25, 0.5, false); r != 1.5 {
+		t.Errorf("Test failed. TestRoundToStep unexpected result %v", r)
+	}
+}
@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// DefaultMaxPages is the number of pages fetched before pagination is aborted
+// when no cap is supplied
+const DefaultMaxPages = 50
+
+// ErrMaxPagesExceeded is returned when more pages remain after the page cap
+// has been reached, the records of the fetched pages are kept
+var ErrMaxPagesExceeded = errors.New("maximum number of pages exceeded")
+
+// PageFetcher fetches the page at the cursor, an empty cursor being the first
+// page, and returns the cursor of the next page or an empty cursor if it was
+// the last page. Pages must be requested through the exchange Requester so
+// every page is rate limited
+type PageFetcher func(cursor string) (next string, err error)
+
+// Paginate calls fetch for every page until the last page is reached, up to
+// maxPages pages, DefaultMaxPages being used if zero or negative. An exchange
+// returning the same cursor twice is reported as an error so pagination can't
+// loop forever
+func Paginate(maxPages int, fetch PageFetcher) error {
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	seen := make(map[string]bool)
+	var cursor string
+	for page := 0; page < maxPages; page++ {
+		seen[cursor] = true
+		next, err := fetch(cursor)
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		if seen[next] {
+			return fmt.Errorf("pagination cursor %s returned more than once", next)
+		}
+		cursor = next
+	}
+	return fmt.Errorf("%w: stopped after %d pages", ErrMaxPagesExceeded, maxPages)
+}
+
+// PaginatePages calls fetch for every page number starting from the first page
+// until it reports no more pages remain, up to maxPages pages as per Paginate
+func PaginatePages(maxPages int, fetch func(page int) (more bool, err error)) error {
+	return Paginate(maxPages, func(cursor string) (string, error) {
+		page := 1
+		if cursor != "" {
+			var err error
+			page, err = strconv.Atoi(cursor)
+			if err != nil {
+				return "", err
+			}
+		}
+		more, err := fetch(page)
+		if err != nil || !more {
+			return "", err
+		}
+		return strconv.Itoa(page + 1), nil
+	})
+}
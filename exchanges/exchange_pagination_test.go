@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// testPages serves three pages of records keyed by cursor
+var testPages = map[string]struct {
+	records []int
+	next    string
+}{
+	"":      {[]int{1, 2}, "page2"},
+	"page2": {[]int{3, 4}, "page3"},
+	"page3": {[]int{5}, ""},
+}
+
+func TestPaginate(t *testing.T) {
+	var records []int
+	var cursors []string
+	fetch := func(cursor string) (string, error) {
+		cursors = append(cursors, cursor)
+		page := testPages[cursor]
+		records = append(records, page.records...)
+		return page.next, nil
+	}
+
+	err := Paginate(0, fetch)
+	if err != nil {
+		t.Fatalf("Test failed. Paginate error: %s", err)
+	}
+	if len(records) != 5 || records[4] != 5 || len(cursors) != 3 {
+		t.Errorf("Test failed. Expected 5 records over 3 pages, got %v over %v",
+			records, cursors)
+	}
+
+	records, cursors = nil, nil
+	err = Paginate(2, fetch)
+	if !errors.Is(err, ErrMaxPagesExceeded) {
+		t.Errorf("Test failed. Expected a max pages error, got %v", err)
+	}
+	if len(records) != 4 || len(cursors) != 2 {
+		t.Errorf("Test failed. Expected 4 records over 2 pages, got %v over %v",
+			records, cursors)
+	}
+
+	var calls int
+	err = Paginate(0, func(cursor string) (string, error) {
+		calls++
+		return "page2", nil
+	})
+	if err == nil || errors.Is(err, ErrMaxPagesExceeded) || calls != 2 {
+		t.Errorf("Test failed. Expected a repeated cursor error after 2 pages, got %v after %d",
+			err, calls)
+	}
+
+	fetchErr := errors.New("fetch failed")
+	err = Paginate(0, func(cursor string) (string, error) {
+		return "", fetchErr
+	})
+	if err != fetchErr {
+		t.Errorf("Test failed. Expected the fetch error, got %v", err)
+	}
+}
+
+func TestPaginatePages(t *testing.T) {
+	var pages []int
+	fetch := func(page int) (bool, error) {
+		pages = append(pages, page)
+		return len(testPages["page"+strconv.Itoa(page+1)].records) > 0, nil
+	}
+
+	err := PaginatePages(0, fetch)
+	if err != nil {
+		t.Fatalf("Test failed. PaginatePages error: %s", err)
+	}
+	if len(pages) != 3 || pages[0] != 1 || pages[2] != 3 {
+		t.Errorf("Test failed. Expected pages 1 to 3, got %v", pages)
+	}
+
+	pages = nil
+	err = PaginatePages(1, fetch)
+	if !errors.Is(err, ErrMaxPagesExceeded) || len(pages) != 1 {
+		t.Errorf("Test failed. Expected a max pages error after 1 page, got %v after %v",
+			err, pages)
+	}
+}
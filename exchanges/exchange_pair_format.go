@@ -0,0 +1,119 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// ErrPairFormatUnknown is returned when a stored pair can't be split into its
+// currencies
+var ErrPairFormatUnknown = errors.New("unable to determine the currencies of pair")
+
+// pairDelimiters holds the delimiters used by past config pair formats
+var pairDelimiters = []string{"-", "_", "/", ":"}
+
+// MigratePairFormat reformats the stored enabled and available pairs into the
+// config pair format when the stored pair format version is older than the
+// supplied version, keeping the enabled pair selection, and records the new
+// version. If a pair can't be reformatted the enabled pairs are reset to
+// defaultEnabled and true is returned, so the caller can force update the
+// available pairs
+func (e *Base) MigratePairFormat(version int, defaultEnabled []string) (bool, error) {
+	cfg := config.GetConfig()
+	exch, err := cfg.GetExchangeConfig(e.Name)
+	if err != nil {
+		return false, err
+	}
+
+	if exch.PairFormatVersion >= version {
+		return false, nil
+	}
+
+	known := append(append([]string{}, e.AvailablePairs...), e.EnabledPairs...)
+	available, availableConverted, err := e.reformatPairs(e.AvailablePairs, known)
+	var enabled, enabledConverted []string
+	if err == nil {
+		enabled, enabledConverted, err = e.reformatPairs(e.EnabledPairs, known)
+	}
+
+	reset := err != nil
+	if reset {
+		log.Warnf("%s unable to migrate pairs to format version %d, resetting enabled pairs to %s. Err: %s\n",
+			e.Name, version, defaultEnabled, err)
+		available = e.AvailablePairs
+		enabled = defaultEnabled
+	} else {
+		log.Infof("%s migrated pairs to format version %d - available pairs converted: [%s] enabled pairs converted: [%s].\n",
+			e.Name, version, common.JoinStrings(availableConverted, ", "),
+			common.JoinStrings(enabledConverted, ", "))
+	}
+
+	e.AvailablePairs = available
+	e.EnabledPairs = enabled
+	exch.AvailablePairs = common.JoinStrings(available, ",")
+	exch.EnabledPairs = common.JoinStrings(enabled, ",")
+	exch.PairFormatVersion = version
+	return reset, cfg.UpdateExchangeConfig(exch)
+}
+
+// reformatPairs returns the pairs in the config pair format along with a
+// description of each pair changed. Pairs without a delimiter are split by
+// matching them against the known pairs
+func (e *Base) reformatPairs(pairs, known []string) ([]string, []string, error) {
+	var formatted, converted []string
+	for x := range pairs {
+		first, second, err := splitPair(pairs[x], known)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p := first + e.ConfigCurrencyPairFormat.Delimiter + second
+		if e.ConfigCurrencyPairFormat.Uppercase {
+			p = common.StringToUpper(p)
+		} else {
+			p = common.StringToLower(p)
+		}
+
+		if p != pairs[x] {
+			converted = append(converted, pairs[x]+" -> "+p)
+		}
+		formatted = append(formatted, p)
+	}
+	return formatted, converted, nil
+}
+
+// splitPair splits a stored pair into its currencies. A pair without a
+// delimiter is split at the delimiter of a matching known pair, or as two
+// three letter currencies
+func splitPair(p string, known []string) (string, string, error) {
+	if first, second, ok := splitDelimitedPair(p); ok {
+		return first, second, nil
+	}
+
+	for x := range known {
+		first, second, ok := splitDelimitedPair(known[x])
+		if ok && common.StringToUpper(first+second) == common.StringToUpper(p) {
+			return first, second, nil
+		}
+	}
+
+	if len(p) == 6 {
+		return p[:3], p[3:], nil
+	}
+	return "", "", fmt.Errorf("%s: %v", p, ErrPairFormatUnknown)
+}
+
+// splitDelimitedPair splits a pair at the first known delimiter it contains
+func splitDelimitedPair(p string) (string, string, bool) {
+	for x := range pairDelimiters {
+		split := common.SplitStrings(p, pairDelimiters[x])
+		if len(split) == 2 && split[0] != "" && split[1] != "" {
+			return split[0], split[1], true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,89 @@
+package exchange
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+func TestMigratePairFormat(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatal("Test failed. TestMigratePairFormat failed to load config")
+	}
+
+	b := Base{Name: "ANX"}
+	b.ConfigCurrencyPairFormat.Delimiter = "_"
+	b.ConfigCurrencyPairFormat.Uppercase = true
+	b.AvailablePairs = []string{"BTCUSD", "LTC-BTC", "DOGE_BTC", "btchkd"}
+	b.EnabledPairs = []string{"BTCUSD", "DOGEBTC"}
+
+	reset, err := b.MigratePairFormat(1, []string{"BTC_USD"})
+	if err != nil || reset {
+		t.Fatalf("Test failed. MigratePairFormat reset: %v error: %v", reset, err)
+	}
+	if !reflect.DeepEqual(b.AvailablePairs, []string{"BTC_USD", "LTC_BTC", "DOGE_BTC", "BTC_HKD"}) {
+		t.Errorf("Test failed. Unexpected available pairs %v", b.AvailablePairs)
+	}
+	if !reflect.DeepEqual(b.EnabledPairs, []string{"BTC_USD", "DOGE_BTC"}) {
+		t.Errorf("Test failed. Unexpected enabled pairs %v", b.EnabledPairs)
+	}
+
+	exch, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.PairFormatVersion != 1 || exch.EnabledPairs != "BTC_USD,DOGE_BTC" {
+		t.Errorf("Test failed. Unexpected config version %d and enabled pairs %s",
+			exch.PairFormatVersion, exch.EnabledPairs)
+	}
+
+	// Pairs aren't checked again once the version is current
+	b.EnabledPairs = []string{"BTCUSD"}
+	reset, err = b.MigratePairFormat(1, []string{"BTC_USD"})
+	if err != nil || reset || b.EnabledPairs[0] != "BTCUSD" {
+		t.Errorf("Test failed. Current version migrated, reset: %v error: %v", reset, err)
+	}
+
+	b.AvailablePairs = []string{"BTCUSD"}
+	b.EnabledPairs = []string{"DOGEBTC"}
+	reset, err = b.MigratePairFormat(2, []string{"BTC_USD"})
+	if err != nil || !reset {
+		t.Fatalf("Test failed. Expected unknown pairs to be reset, reset: %v error: %v",
+			reset, err)
+	}
+	if !reflect.DeepEqual(b.EnabledPairs, []string{"BTC_USD"}) {
+		t.Errorf("Test failed. Unexpected reset enabled pairs %v", b.EnabledPairs)
+	}
+
+	b.Name = "Blah"
+	_, err = b.MigratePairFormat(3, nil)
+	if err == nil {
+		t.Error("Test failed. MigratePairFormat succeeded on an exchange which doesn't exist")
+	}
+}
+
+func TestSplitPair(t *testing.T) {
+	tests := []struct {
+		pair          string
+		known         []string
+		first, second string
+		expectErr     bool
+	}{
+		{"BTC-USD", nil, "BTC", "USD", false},
+		{"btc_usd", nil, "btc", "usd", false},
+		{"BTCUSD", nil, "BTC", "USD", false},
+		{"DOGEBTC", []string{"LTC_BTC", "doge-btc"}, "doge", "btc", false},
+		{"DOGEBTC", []string{"LTC_BTC"}, "", "", true},
+		{"-USD", nil, "", "", true},
+	}
+	for i := range tests {
+		first, second, err := splitPair(tests[i].pair, tests[i].known)
+		if (err != nil) != tests[i].expectErr || first != tests[i].first ||
+			second != tests[i].second {
+			t.Errorf("Test failed. Case %d got %s %s error %v", i, first, second, err)
+		}
+	}
+}
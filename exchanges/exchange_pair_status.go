@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// Currency pair trading statuses
+const (
+	PairStatusTrading  = "TRADING"
+	PairStatusHalted   = "HALTED"
+	PairStatusDelisted = "DELISTED"
+)
+
+// Errors returned when an order is placed for a pair which isn't tradable
+var (
+	ErrPairHalted   = errors.New("currency pair trading halted")
+	ErrPairDelisted = errors.New("currency pair delisted")
+)
+
+// PairStatus holds the trading status of a currency pair as reported by the
+// exchange
+type PairStatus struct {
+	Pair   pair.CurrencyPair `json:"pair"`
+	Status string            `json:"status"`
+}
+
+// SetPairStatuses replaces the stored currency pair trading statuses. Pairs
+// without a status are treated as trading
+func (e *Base) SetPairStatuses(statuses []PairStatus) {
+	s := make(map[string]PairStatus, len(statuses))
+	for x := range statuses {
+		s[limitsKey(statuses[x].Pair)] = statuses[x]
+	}
+
+	e.pairStatusMtx.Lock()
+	e.pairStatuses = s
+	e.pairStatusMtx.Unlock()
+}
+
+// GetPairStatus returns the trading status of a currency pair
+func (e *Base) GetPairStatus(p pair.CurrencyPair) string {
+	e.pairStatusMtx.RLock()
+	defer e.pairStatusMtx.RUnlock()
+	s, ok := e.pairStatuses[limitsKey(p)]
+	if !ok || s.Status == "" {
+		return PairStatusTrading
+	}
+	return s.Status
+}
+
+// GetPairStatuses returns the trading statuses reported for all currency
+// pairs
+func (e *Base) GetPairStatuses() []PairStatus {
+	e.pairStatusMtx.RLock()
+	defer e.pairStatusMtx.RUnlock()
+	var result []PairStatus
+	for _, s := range e.pairStatuses {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return limitsKey(result[i].Pair) < limitsKey(result[j].Pair)
+	})
+	return result
+}
+
+// IsPairTradable returns whether orders can be placed for a currency pair
+func (e *Base) IsPairTradable(p pair.CurrencyPair) bool {
+	return e.GetPairStatus(p) == PairStatusTrading
+}
+
+// CheckPairTradable returns an error matching ErrPairHalted or
+// ErrPairDelisted if trading of a currency pair is halted or it is delisted
+func (e *Base) CheckPairTradable(p pair.CurrencyPair) error {
+	switch status := e.GetPairStatus(p); status {
+	case PairStatusTrading:
+		return nil
+	case PairStatusDelisted:
+		return WrapError(ErrPairDelisted, "%s %s is delisted", e.Name,
+			p.Pair().String())
+	default:
+		return WrapError(ErrPairHalted, "%s %s trading is %s", e.Name,
+			p.Pair().String(), common.StringToLower(status))
+	}
+}
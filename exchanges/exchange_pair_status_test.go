@@ -0,0 +1,43 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+func TestPairStatuses(t *testing.T) {
+	var b Base
+	b.Name = "TESTNAME"
+	btcusd := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+	ethusd := pair.NewCurrencyPair("ETH", "USD")
+
+	if !b.IsPairTradable(btcusd) || b.CheckPairTradable(btcusd) != nil {
+		t.Fatal("Test failed. TestPairStatuses expected pairs without a status to be tradable")
+	}
+
+	b.SetPairStatuses([]PairStatus{
+		{Pair: pair.NewCurrencyPair("btc", "usd"), Status: PairStatusHalted},
+		{Pair: ethusd, Status: PairStatusTrading},
+	})
+	if b.IsPairTradable(btcusd) || !b.IsPairTradable(ethusd) {
+		t.Error("Test failed. TestPairStatuses unexpected tradable pairs")
+	}
+	if err := b.CheckPairTradable(btcusd); !errors.Is(err, ErrPairHalted) {
+		t.Errorf("Test failed. TestPairStatuses expected a halted error, got %v", err)
+	}
+	if statuses := b.GetPairStatuses(); len(statuses) != 2 ||
+		statuses[0].Status != PairStatusHalted {
+		t.Errorf("Test failed. TestPairStatuses unexpected statuses %v", statuses)
+	}
+
+	b.SetPairStatuses([]PairStatus{{Pair: btcusd, Status: PairStatusDelisted}})
+	err := b.CheckPairTradable(btcusd)
+	if !errors.Is(err, ErrPairDelisted) || errors.Is(err, ErrPairHalted) {
+		t.Errorf("Test failed. TestPairStatuses expected a delisted error, got %v", err)
+	}
+	if b.GetPairStatus(ethusd) != PairStatusTrading {
+		t.Error("Test failed. TestPairStatuses expected replaced statuses to be trading")
+	}
+}
@@ -0,0 +1,35 @@
+package exchange
+
+import (
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// GetServerTime returns the exchange server time. Exchanges exposing a server
+// time endpoint override it
+func (e *Base) GetServerTime() (time.Time, error) {
+	return time.Time{}, common.ErrFunctionNotSupported
+}
+
+// SetServerTimeOffset sets how far the exchange server clock is ahead of the
+// local clock, a negative offset means it is behind
+func (e *Base) SetServerTimeOffset(offset time.Duration) {
+	e.serverTimeMtx.Lock()
+	e.serverTimeOffset = offset
+	e.serverTimeMtx.Unlock()
+}
+
+// GetServerTimeOffset returns the measured exchange server clock offset
+func (e *Base) GetServerTimeOffset() time.Duration {
+	e.serverTimeMtx.RLock()
+	defer e.serverTimeMtx.RUnlock()
+	return e.serverTimeOffset
+}
+
+// GetAdjustedTime returns the local time adjusted by the server clock offset.
+// Signed requests use it for their timestamps so a drifting local clock
+// doesn't get them rejected
+func (e *Base) GetAdjustedTime() time.Time {
+	return time.Now().Add(e.GetServerTimeOffset())
+}
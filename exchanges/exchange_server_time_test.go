@@ -0,0 +1,33 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+func TestServerTimeOffset(t *testing.T) {
+	var b Base
+	_, err := b.GetServerTime()
+	if err != common.ErrFunctionNotSupported {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+
+	if b.GetServerTimeOffset() != 0 {
+		t.Error("Test failed. Expected no offset before one is set")
+	}
+
+	b.SetServerTimeOffset(-time.Hour)
+	if b.GetServerTimeOffset() != -time.Hour {
+		t.Errorf("Test failed. Expected offset %v, got %v", -time.Hour,
+			b.GetServerTimeOffset())
+	}
+
+	adjusted := b.GetAdjustedTime()
+	expected := time.Now().Add(-time.Hour)
+	if adjusted.After(expected) || expected.Sub(adjusted) > time.Minute {
+		t.Errorf("Test failed. Adjusted time %v not offset from %v", adjusted,
+			expected)
+	}
+}
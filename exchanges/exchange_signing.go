@@ -0,0 +1,36 @@
+package exchange
+
+import (
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// SignSHA256HMAC returns the HMAC-SHA256 of a message keyed with the secret,
+// base64 encoded if b64 is set or lowercase hex encoded otherwise. Secrets are
+// taken as bytes so base64 decoded API secrets can be used directly. Neither
+// the message nor the secret is logged
+func SignSHA256HMAC(message, secret []byte, b64 bool) string {
+	return encodeSignature(common.GetHMAC(common.HashSHA256, message, secret), b64)
+}
+
+// SignSHA512HMAC returns the HMAC-SHA512 of a message keyed with the secret,
+// base64 encoded if b64 is set or lowercase hex encoded otherwise
+func SignSHA512HMAC(message, secret []byte, b64 bool) string {
+	return encodeSignature(common.GetHMAC(common.HashSHA512, message, secret), b64)
+}
+
+// SignMD5Concat returns the MD5 of a message with the secret appended, base64
+// encoded if b64 is set or lowercase hex encoded otherwise
+func SignMD5Concat(message, secret []byte, b64 bool) string {
+	payload := make([]byte, 0, len(message)+len(secret))
+	payload = append(payload, message...)
+	payload = append(payload, secret...)
+	return encodeSignature(common.GetMD5(payload), b64)
+}
+
+// encodeSignature encodes a signature as base64 or hex
+func encodeSignature(signature []byte, b64 bool) string {
+	if b64 {
+		return common.Base64Encode(signature)
+	}
+	return common.HexEncodeToString(signature)
+}
@@ -0,0 +1,71 @@
+package exchange
+
+import (
+	"testing"
+)
+
+func TestSignSHA256HMAC(t *testing.T) {
+	tests := []struct {
+		message, secret []byte
+		b64             bool
+		expected        string
+	}{
+		// RFC 4231 test case 2
+		{[]byte("what do ya want for nothing?"), []byte("Jefe"), false,
+			"5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"},
+		{[]byte("what do ya want for nothing?"), []byte("Jefe"), true,
+			"W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM="},
+		{nil, nil, false,
+			"b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad"},
+		// Base64 decoded secret holding non UTF-8 bytes
+		{[]byte("GET/api/v1/order1518064236"), []byte("secret\x00\xff"), false,
+			"fcdb5656b80756702e01da1afbed434ba24f46bfe0e95369f928c1cd3f116574"},
+	}
+	for i := range tests {
+		result := SignSHA256HMAC(tests[i].message, tests[i].secret, tests[i].b64)
+		if result != tests[i].expected {
+			t.Errorf("Test failed. Case %d expected %s, got %s", i,
+				tests[i].expected, result)
+		}
+	}
+}
+
+func TestSignSHA512HMAC(t *testing.T) {
+	// RFC 4231 test case 2
+	message, secret := []byte("what do ya want for nothing?"), []byte("Jefe")
+	expected := "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737"
+	if result := SignSHA512HMAC(message, secret, false); result != expected {
+		t.Errorf("Test failed. Expected %s, got %s", expected, result)
+	}
+
+	expected = "Fkt6e/z4GeLjlfvnO1bgo4e9ZCIugx/WECcM1+olBVSXWL91wFqZSm0DT2X48Ob9yuqxo01Ka0tjbgcKOLznNw=="
+	if result := SignSHA512HMAC(message, secret, true); result != expected {
+		t.Errorf("Test failed. Expected %s, got %s", expected, result)
+	}
+}
+
+func TestSignMD5Concat(t *testing.T) {
+	tests := []struct {
+		message, secret []byte
+		b64             bool
+		expected        string
+	}{
+		{[]byte("ab"), []byte("c"), false, "900150983cd24fb0d6963f7d28e17f72"},
+		{[]byte("abc"), nil, true, "kAFQmDzST7DWlj99KOF/cg=="},
+		{[]byte("amount=1&api_key=key&secret_key="), []byte("secret"), false,
+			"b855fcf21f7b519dd24826cc4ac432c4"},
+	}
+	for i := range tests {
+		result := SignMD5Concat(tests[i].message, tests[i].secret, tests[i].b64)
+		if result != tests[i].expected {
+			t.Errorf("Test failed. Case %d expected %s, got %s", i,
+				tests[i].expected, result)
+		}
+	}
+
+	message := []byte("ab")
+	SignMD5Concat(message[:1], []byte("c"), false)
+	if string(message) != "ab" {
+		t.Errorf("Test failed. Message modified to %s", message)
+	}
+}
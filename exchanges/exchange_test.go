@@ -1,13 +1,26 @@
 package exchange
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
 	"net/http"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
 	"github.com/thrasher-/gocryptotrader/exchanges/request"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
@@ -244,6 +257,107 @@ func TestSetAssetTypes(t *testing.T) {
 	if !common.StringDataCompare(b.AssetTypes, ticker.Spot) {
 		t.Fatal("Test failed. TestSetAssetTypes assetTypes is not set")
 	}
+
+	exch, err = cfg.GetExchangeConfig(b.Name)
+	if err != nil {
+		t.Fatalf("Test failed. TestSetAssetTypes load config failed. Error %s", err)
+	}
+	if exch.EnabledAssetTypes != ticker.Spot {
+		t.Fatal("Test failed. TestSetAssetTypes enabled asset types did not default to the supported asset types")
+	}
+
+	b.AssetTypes = []string{ticker.Spot, "this_week"}
+	exch.EnabledAssetTypes = "spot"
+	err = cfg.UpdateExchangeConfig(exch)
+	if err != nil {
+		t.Fatalf("Test failed. TestSetAssetTypes update config failed. Error %s", err)
+	}
+	err = b.SetAssetTypes()
+	if err != nil {
+		t.Fatalf("Test failed. TestSetAssetTypes. Error %s", err)
+	}
+	if len(b.GetAssetTypes()) != 1 || b.GetAssetTypes()[0] != ticker.Spot {
+		t.Fatalf("Test failed. TestSetAssetTypes unexpected enabled asset types %v",
+			b.GetAssetTypes())
+	}
+	if len(b.GetSupportedAssetTypes()) != 2 {
+		t.Fatal("Test failed. TestSetAssetTypes supported asset types changed")
+	}
+
+	exch.EnabledAssetTypes = "quarter"
+	err = cfg.UpdateExchangeConfig(exch)
+	if err != nil {
+		t.Fatalf("Test failed. TestSetAssetTypes update config failed. Error %s", err)
+	}
+	defer func() {
+		exch.EnabledAssetTypes = ""
+		cfg.UpdateExchangeConfig(exch)
+	}()
+	err = b.SetAssetTypes()
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Fatalf("Test failed. Expected invalid asset type error, got %v", err)
+	}
+}
+
+func TestSetAssetTypeEnabled(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Test failed. TestSetAssetTypeEnabled failed to load config file. Error: %s", err)
+	}
+
+	b := Base{
+		Name:       "ANX",
+		AssetTypes: []string{ticker.Spot, "this_week", "quarter"},
+	}
+	defer func() {
+		exch, err := cfg.GetExchangeConfig(b.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exch.EnabledAssetTypes = ""
+		cfg.UpdateExchangeConfig(exch)
+	}()
+
+	err = b.SetAssetTypeEnabled("this_week", false)
+	if err != nil {
+		t.Fatalf("Test failed. SetAssetTypeEnabled: %s", err)
+	}
+	if common.StringDataCompare(b.GetAssetTypes(), "this_week") ||
+		len(b.GetAssetTypes()) != 2 {
+		t.Fatalf("Test failed. Unexpected enabled asset types %v", b.GetAssetTypes())
+	}
+	if !b.SupportsAsset("this_week") {
+		t.Fatal("Test failed. Disabled asset type should remain supported")
+	}
+
+	exch, err := cfg.GetExchangeConfig(b.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.EnabledAssetTypes != "SPOT,quarter" {
+		t.Fatalf("Test failed. Unexpected config enabled asset types %s",
+			exch.EnabledAssetTypes)
+	}
+
+	err = b.SetAssetTypeEnabled("THIS_WEEK", true)
+	if err != nil {
+		t.Fatalf("Test failed. SetAssetTypeEnabled: %s", err)
+	}
+	if len(b.GetAssetTypes()) != 3 || b.GetAssetTypes()[1] != "this_week" {
+		t.Fatalf("Test failed. Unexpected enabled asset types %v", b.GetAssetTypes())
+	}
+
+	err = b.SetAssetTypeEnabled("binary", true)
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Fatalf("Test failed. Expected invalid asset type error, got %v", err)
+	}
+
+	b.EnabledAssetTypes = []string{ticker.Spot}
+	err = b.SetAssetTypeEnabled(ticker.Spot, false)
+	if err == nil {
+		t.Fatal("Test failed. Disabled the last enabled asset type")
+	}
 }
 
 func TestGetAssetTypes(t *testing.T) {
@@ -277,6 +391,56 @@ func TestGetExchangeAssetTypes(t *testing.T) {
 	if err == nil {
 		t.Fatal("Test failed. Got asset types for non-existent exchange")
 	}
+
+	exchCfg, err := cfg.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origAssetTypes, origEnabled := exchCfg.AssetTypes, exchCfg.EnabledAssetTypes
+	exchCfg.AssetTypes = "SPOT,quarter"
+	exchCfg.EnabledAssetTypes = "QUARTER"
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		exchCfg.AssetTypes, exchCfg.EnabledAssetTypes = origAssetTypes, origEnabled
+		cfg.UpdateExchangeConfig(exchCfg)
+	}()
+
+	result, err = GetExchangeAssetTypes("Bitfinex")
+	if err != nil || len(result) != 1 || result[0] != "quarter" {
+		t.Fatalf("Test failed. Unexpected enabled asset types %v %v", result, err)
+	}
+
+	exchCfg.AssetTypes = "SPOT"
+	exchCfg.EnabledAssetTypes = "spot,quarter"
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = GetExchangeAssetTypes("Bitfinex")
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Fatalf("Test failed. Expected invalid asset type error, got %v", err)
+	}
+}
+
+func TestCheckAssetType(t *testing.T) {
+	b := Base{
+		Name:       "TESTNAME",
+		AssetTypes: []string{ticker.Spot},
+	}
+
+	err := b.CheckAssetType(ticker.Spot)
+	if err != nil {
+		t.Fatalf("Test failed. CheckAssetType: %s", err)
+	}
+
+	err = b.CheckAssetType("quarter")
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Fatalf("Test failed. Expected invalid asset type error, got %v", err)
+	}
 }
 
 func TestCompareCurrencyPairFormats(t *testing.T) {
@@ -604,6 +768,110 @@ func TestGetAndFormatExchangeCurrencies(t *testing.T) {
 	}
 }
 
+func TestGetAndFormatExchangeCurrenciesValidation(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Failed to load config file. Error: %s", err)
+	}
+
+	btcusd := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+	ltcbtc := pair.NewCurrencyPairDelimiter("LTC_BTC", "_")
+	for _, pairs := range [][]pair.CurrencyPair{nil, {}} {
+		_, err = GetAndFormatExchangeCurrencies("Liqui", pairs)
+		if !errors.Is(err, ErrNoCurrencyPairs) {
+			t.Errorf("Test failed - expected %v, got %v", ErrNoCurrencyPairs, err)
+		}
+	}
+	_, err = GetAndFormatExchangeCurrencies("Liqui", []pair.CurrencyPair{btcusd, {Delimiter: "_"}})
+	if !errors.Is(err, ErrEmptyCurrencyPair) {
+		t.Errorf("Test failed - expected %v, got %v", ErrEmptyCurrencyPair, err)
+	}
+
+	exchCfg, err := cfg.GetExchangeConfig("Liqui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		format   *config.CurrencyPairFormatConfig
+		pairs    []pair.CurrencyPair
+		expected string
+		err      error
+	}{
+		{&config.CurrencyPairFormatConfig{Uppercase: true, Separator: ","},
+			[]pair.CurrencyPair{btcusd, ltcbtc}, "BTCUSD,LTCBTC", nil},
+		{&config.CurrencyPairFormatConfig{Delimiter: "-", Separator: ",", QuoteFirst: true},
+			[]pair.CurrencyPair{btcusd, ltcbtc}, "usd-btc,btc-ltc", nil},
+		{&config.CurrencyPairFormatConfig{Delimiter: "_", Separator: "_"},
+			[]pair.CurrencyPair{btcusd, ltcbtc}, "", ErrInvalidPairSeparator},
+		{&config.CurrencyPairFormatConfig{Delimiter: "_"},
+			[]pair.CurrencyPair{btcusd, ltcbtc}, "", ErrInvalidPairSeparator},
+		// A single pair needs no separator
+		{&config.CurrencyPairFormatConfig{Delimiter: "_"},
+			[]pair.CurrencyPair{btcusd}, "btc_usd", nil},
+		{nil, []pair.CurrencyPair{btcusd}, "btcusd", nil},
+	}
+	for x := range tests {
+		exchCfg.RequestCurrencyPairFormat = tests[x].format
+		err = cfg.UpdateExchangeConfig(exchCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := GetAndFormatExchangeCurrencies("Liqui", tests[x].pairs)
+		if !errors.Is(err, tests[x].err) || actual.String() != tests[x].expected {
+			t.Errorf("Test failed - case %d expected %q %v, got %q %v", x,
+				tests[x].expected, tests[x].err, actual, err)
+		}
+	}
+}
+
+func TestFormatExchangeCurrencies(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Failed to load config file. Error: %s", err)
+	}
+
+	var pairs []pair.CurrencyPair
+	for _, p := range []string{"BTC_USD", "LTC_BTC", "ETH_BTC", "DASH_BTC", "XRP_BTC"} {
+		pairs = append(pairs, pair.NewCurrencyPairDelimiter(p, "_"))
+	}
+	b := Base{Name: "Liqui"}
+	_, err = b.FormatExchangeCurrencies(nil)
+	if !errors.Is(err, ErrNoCurrencyPairs) {
+		t.Errorf("Test failed - expected %v, got %v", ErrNoCurrencyPairs, err)
+	}
+
+	tests := []struct {
+		max      int
+		expected []string
+	}{
+		{0, []string{"btc_usd-ltc_btc-eth_btc-dash_btc-xrp_btc"}},
+		{5, []string{"btc_usd-ltc_btc-eth_btc-dash_btc-xrp_btc"}},
+		{6, []string{"btc_usd-ltc_btc-eth_btc-dash_btc-xrp_btc"}},
+		{4, []string{"btc_usd-ltc_btc-eth_btc-dash_btc", "xrp_btc"}},
+		{2, []string{"btc_usd-ltc_btc", "eth_btc-dash_btc", "xrp_btc"}},
+		{1, []string{"btc_usd", "ltc_btc", "eth_btc", "dash_btc", "xrp_btc"}},
+	}
+	for x := range tests {
+		b.MaxSymbolsPerRequest = tests[x].max
+		chunks, err := b.FormatExchangeCurrencies(pairs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(chunks, tests[x].expected) {
+			t.Errorf("Test failed - max %d expected %v, got %v", tests[x].max,
+				tests[x].expected, chunks)
+		}
+	}
+
+	b.MaxSymbolsPerRequest = 2
+	_, err = b.FormatExchangeCurrencies(append(pairs, pair.CurrencyPair{}))
+	if !errors.Is(err, ErrEmptyCurrencyPair) {
+		t.Errorf("Test failed - expected %v, got %v", ErrEmptyCurrencyPair, err)
+	}
+}
+
 func TestFormatExchangeCurrency(t *testing.T) {
 	cfg := config.GetConfig()
 	err := cfg.LoadConfig(config.ConfigTestFile)
@@ -621,6 +889,70 @@ func TestFormatExchangeCurrency(t *testing.T) {
 	}
 }
 
+func TestQuoteFirstCurrencyFormat(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatalf("Failed to load config file. Error: %s", err)
+	}
+
+	exchCfg, err := cfg.GetExchangeConfig("CoinbasePro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchCfg.RequestCurrencyPairFormat = &config.CurrencyPairFormatConfig{
+		Delimiter:  "-",
+		Uppercase:  true,
+		QuoteFirst: true,
+	}
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	btcusd := pair.NewCurrencyPairFromBaseQuote("BTC", "USD")
+	formatted := FormatExchangeCurrency("CoinbasePro", btcusd)
+	if formatted != "USD-BTC" {
+		t.Errorf("Test failed - Exchange TestQuoteFirstCurrencyFormat %s != USD-BTC",
+			formatted)
+	}
+	parsed, err := ParseExchangeCurrency("CoinbasePro", formatted.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Base() != "BTC" || parsed.Quote() != "USD" {
+		t.Errorf("Test failed - Exchange TestQuoteFirstCurrencyFormat parsed base %s quote %s",
+			parsed.Base(), parsed.Quote())
+	}
+
+	exchCfg.RequestCurrencyPairFormat = &config.CurrencyPairFormatConfig{
+		Uppercase:  false,
+		Index:      "btc",
+		QuoteFirst: true,
+	}
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = ParseExchangeCurrency("CoinbasePro", "usdtbtc")
+	if err != nil || parsed.Base() != "BTC" || parsed.Quote() != "USDT" {
+		t.Errorf("Test failed - Exchange TestQuoteFirstCurrencyFormat indexed pair %+v %v",
+			parsed, err)
+	}
+
+	for _, invalid := range []string{"usdeth", "btc", ""} {
+		_, err = ParseExchangeCurrency("CoinbasePro", invalid)
+		if err == nil {
+			t.Errorf("Test failed - Exchange TestQuoteFirstCurrencyFormat expected an error for %q",
+				invalid)
+		}
+	}
+	_, err = ParseExchangeCurrency("Missing", "BTC-USD")
+	if err == nil {
+		t.Error("Test failed - Exchange TestQuoteFirstCurrencyFormat expected a missing exchange error")
+	}
+}
+
 func TestFormatCurrency(t *testing.T) {
 	cfg := config.GetConfig()
 	err := cfg.LoadConfig(config.ConfigTestFile)
@@ -680,6 +1012,20 @@ func TestSetAPIKeys(t *testing.T) {
 	SetAPIKeys.SetAPIKeys("RocketMan", "Digereedoo", "007", true)
 }
 
+func TestGetAccountLabels(t *testing.T) {
+	b := Base{
+		Name: "TESTNAME",
+	}
+	b.SetAPIAccounts([]config.APICredentialsConfig{
+		{Label: "hedge", APIKey: "HedgeKey", APISecret: "HedgeSecret"},
+	})
+
+	labels := b.GetAccountLabels()
+	if len(labels) != 1 || labels[0] != "hedge" {
+		t.Fatalf("Test failed. GetAccountLabels unexpected result %v", labels)
+	}
+}
+
 func TestSetCurrencies(t *testing.T) {
 	cfg := config.GetConfig()
 	err := cfg.LoadConfig(config.ConfigTestFile)
@@ -773,13 +1119,23 @@ func TestUpdateCurrencies(t *testing.T) {
 		t.Errorf("Test Failed - Exchange UpdateCurrencies() error: %s", err)
 	}
 
-	// Test updating the same new products, diff should be 0
+	// Test updating the same new products, diff should be 0 but the last
+	// updated time should still be recorded
 	UAC.Name = "ANX"
+	UAC.PairsLastUpdated = 0
 	err = UAC.UpdateCurrencies(exchangeProducts, false, false)
 	if err != nil {
 		t.Errorf("Test Failed - Exchange UpdateCurrencies() error: %s", err)
 	}
 
+	exchCfg, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if UAC.PairsLastUpdated == 0 || exchCfg.PairsLastUpdated != UAC.PairsLastUpdated {
+		t.Error("Test Failed - Exchange UpdateCurrencies() did not update pairs last updated time")
+	}
+
 	// Test force updating to only one product
 	exchangeProducts = []string{"btc"}
 	err = UAC.UpdateCurrencies(exchangeProducts, false, true)
@@ -787,9 +1143,15 @@ func TestUpdateCurrencies(t *testing.T) {
 		t.Errorf("Test Failed - Forced Exchange UpdateCurrencies() error: %s", err)
 	}
 
-	// Test update currency pairs with btc excluded
+	// Test update currency pairs with btc excluded, removing all existing
+	// available pairs requires a forced update
 	exchangeProducts = []string{"ltc", "eth"}
 	err = UAC.UpdateCurrencies(exchangeProducts, false, false)
+	if err == nil {
+		t.Error("Test Failed - Exchange UpdateCurrencies() removed all available pairs without force")
+	}
+
+	err = UAC.UpdateCurrencies(exchangeProducts, false, true)
 	if err != nil {
 		t.Errorf("Test Failed - Forced Exchange UpdateCurrencies() error: %s", err)
 	}
@@ -802,6 +1164,96 @@ func TestUpdateCurrencies(t *testing.T) {
 	}
 }
 
+func TestUpdatePairs(t *testing.T) {
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig(config.ConfigTestFile)
+	if err != nil {
+		t.Fatal("Test failed. TestUpdatePairs failed to load config")
+	}
+
+	UAC := Base{Name: "ANX"}
+	available := []string{"BTCUSD", "LTCUSD", "ETHUSD", "XRPUSD"}
+	_, err = UAC.UpdatePairs(available, false, true)
+	if err != nil {
+		t.Fatalf("Test failed. UpdatePairs() error: %s", err)
+	}
+
+	_, err = UAC.UpdatePairs([]string{"BTCUSD", "LTCUSD"}, true, true)
+	if err != nil {
+		t.Fatalf("Test failed. UpdatePairs() error: %s", err)
+	}
+
+	// Diff reporting
+	diff, err := UAC.UpdatePairs([]string{"BTCUSD", "ETHUSD", "XRPUSD", "BCHUSD"}, false, false)
+	if err != nil {
+		t.Fatalf("Test failed. UpdatePairs() error: %s", err)
+	}
+
+	if len(diff.New) != 1 || diff.New[0] != "BCHUSD" {
+		t.Errorf("Test failed. UpdatePairs() unexpected new pairs %v", diff.New)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0] != "LTCUSD" {
+		t.Errorf("Test failed. UpdatePairs() unexpected removed pairs %v", diff.Removed)
+	}
+
+	// Enabled pairs no longer available are disabled, the rest are kept
+	if len(diff.Disabled) != 1 || diff.Disabled[0] != "LTCUSD" {
+		t.Errorf("Test failed. UpdatePairs() unexpected disabled pairs %v", diff.Disabled)
+	}
+
+	if len(UAC.EnabledPairs) != 1 || UAC.EnabledPairs[0] != "BTCUSD" {
+		t.Errorf("Test failed. UpdatePairs() unexpected enabled pairs %v", UAC.EnabledPairs)
+	}
+
+	exchCfg, err := cfg.GetExchangeConfig("ANX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchCfg.EnabledPairs != "BTCUSD" {
+		t.Errorf("Test failed. UpdatePairs() unexpected config enabled pairs %s", exchCfg.EnabledPairs)
+	}
+
+	// Large shrink protection
+	diff, err = UAC.UpdatePairs([]string{"BTCUSD"}, false, false)
+	if err == nil {
+		t.Error("Test failed. UpdatePairs() applied an update exceeding the removal threshold")
+	}
+
+	if len(diff.Removed) != 3 {
+		t.Errorf("Test failed. UpdatePairs() unexpected removed pairs %v", diff.Removed)
+	}
+
+	if len(UAC.AvailablePairs) != 4 {
+		t.Errorf("Test failed. UpdatePairs() refused update changed available pairs %v", UAC.AvailablePairs)
+	}
+
+	exchCfg.PairRemovalThreshold = 80
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = UAC.UpdatePairs([]string{"BTCUSD"}, false, false)
+	if err != nil {
+		t.Errorf("Test failed. UpdatePairs() error with raised removal threshold: %s", err)
+	}
+
+	_, err = UAC.UpdatePairs([]string{"ETHUSD"}, false, true)
+	if err != nil {
+		t.Errorf("Test failed. Forced UpdatePairs() error: %s", err)
+	}
+
+	if len(UAC.EnabledPairs) != 1 || UAC.EnabledPairs[0] != "BTCUSD" {
+		t.Errorf("Test failed. UpdatePairs() should not remove all enabled pairs, got %v", UAC.EnabledPairs)
+	}
+
+	_, err = UAC.UpdatePairs([]string{""}, false, true)
+	if err == nil {
+		t.Error("Test failed. UpdatePairs() accepted empty exchange products")
+	}
+}
+
 func TestAPIURL(t *testing.T) {
 	testURL := "https://api.something.com"
 	testURLSecondary := "https://api.somethingelse.com"
@@ -929,3 +1381,256 @@ func TestOrderTypes(t *testing.T) {
 		t.Errorf("test failed - unexpected string %s", os.ToString())
 	}
 }
+
+func TestClassifyRequestError(t *testing.T) {
+	if ClassifyRequestError(nil) != nil {
+		t.Fatal("Test failed. TestClassifyRequestError unexpected error for nil")
+	}
+
+	err := NewAuthenticationNotConfiguredError("RAWR")
+	if !errors.Is(err, ErrAuthenticationNotConfigured) {
+		t.Error("Test failed. TestClassifyRequestError expected authentication not configured")
+	}
+	if err.Error() != fmt.Sprintf(WarningAuthenticatedRequestWithoutCredentialsSet, "RAWR") {
+		t.Errorf("Test failed. TestClassifyRequestError unexpected message %s", err)
+	}
+
+	tester := []struct {
+		Code  int
+		Class error
+	}{
+		{http.StatusUnauthorized, ErrCredentialsInvalid},
+		{http.StatusForbidden, ErrCredentialsInvalid},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusTeapot, ErrRateLimited},
+		{http.StatusBadGateway, ErrExchangeUnavailable},
+		{http.StatusBadRequest, nil},
+	}
+
+	for _, x := range tester {
+		statusErr := &request.HTTPStatusError{StatusCode: x.Code, Message: "rawr"}
+		err = ClassifyRequestError(statusErr)
+		if x.Class == nil {
+			if err != statusErr {
+				t.Errorf("Test failed. TestClassifyRequestError %d unexpectedly classified", x.Code)
+			}
+			continue
+		}
+		if !errors.Is(err, x.Class) || err.Error() != "rawr" {
+			t.Errorf("Test failed. TestClassifyRequestError %d not classified as %s",
+				x.Code, x.Class)
+		}
+	}
+
+	err = ClassifyRequestError(&net.DNSError{Err: "no such host", IsTimeout: true})
+	if !errors.Is(err, ErrExchangeUnavailable) {
+		t.Error("Test failed. TestClassifyRequestError expected exchange unavailable for network error")
+	}
+
+	err = ClassifyRequestError(&request.HTTPStatusError{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "rawr",
+		Body:       `{"msg":"System Maintenance in progress"}`,
+	})
+	if !errors.Is(err, ErrExchangeMaintenance) || !errors.Is(err, ErrExchangeUnavailable) {
+		t.Errorf("Test failed. TestClassifyRequestError expected maintenance, received %v", err)
+	}
+
+	err = ClassifyRequestError(&request.HTTPStatusError{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "rawr",
+		Body:       "upstream connect error",
+	})
+	if errors.Is(err, ErrExchangeMaintenance) || !errors.Is(err, ErrExchangeUnavailable) {
+		t.Errorf("Test failed. TestClassifyRequestError expected unavailable, received %v", err)
+	}
+
+	err = WrapError(ErrRateLimited, "code %d", 10001)
+	if !errors.Is(err, ErrRateLimited) || errors.Is(err, ErrCredentialsInvalid) ||
+		err.Error() != "code 10001" {
+		t.Error("Test failed. TestClassifyRequestError unexpected wrapped error")
+	}
+}
+
+func TestPEMKeySigning(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+	}))
+
+	b := Base{Name: "TESTNAME", AuthenticatedAPISupport: true}
+	b.SetPEMKey("invalid")
+	if b.AuthenticatedAPISupport || b.APIAuthPEMKey != "" {
+		t.Fatal("Test failed. SetPEMKey should disable authenticated API support for an invalid key")
+	}
+
+	b.AuthenticatedAPISupport = true
+	b.SetPEMKey(ecPEM)
+	if !b.AuthenticatedAPISupport || b.APIAuthPEMKey != ecPEM {
+		t.Fatal("Test failed. SetPEMKey failed to load a valid key")
+	}
+
+	payload := []byte("GET\napi.huobi.pro\n/v1/account/accounts\n")
+	digest := common.GetSHA256(payload)
+	sig, err := b.SignWithPEMKey(payload)
+	if err != nil {
+		t.Fatalf("Test failed. SignWithPEMKey: %s", err)
+	}
+	if len(sig) != 64 {
+		t.Fatalf("Test failed. Expected 64 byte ECDSA signature, got %d", len(sig))
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&ecKey.PublicKey, digest, r, s) {
+		t.Fatal("Test failed. ECDSA signature failed verification")
+	}
+
+	sig, err = SignPEM(rsaPEM, payload)
+	if err != nil {
+		t.Fatalf("Test failed. SignPEM: %s", err)
+	}
+	err = rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest, sig)
+	if err != nil {
+		t.Fatalf("Test failed. RSA signature failed verification: %s", err)
+	}
+
+	_, err = SignPEM("", payload)
+	if err == nil {
+		t.Fatal("Test failed. SignPEM expected error for a missing key")
+	}
+}
+
+func TestParseTimeInForce(t *testing.T) {
+	for input, expected := range map[string]TimeInForce{
+		"":    "",
+		"gtc": GTC,
+		"IOC": IOC,
+		"Fok": FOK,
+	} {
+		result, err := ParseTimeInForce(input)
+		if err != nil || result != expected {
+			t.Errorf("Test failed. ParseTimeInForce(%s) expected %s, got %s %v",
+				input, expected, result, err)
+		}
+	}
+
+	_, err := ParseTimeInForce("Day")
+	if err == nil {
+		t.Error("Test failed. ParseTimeInForce accepted an invalid time in force")
+	}
+}
+
+func TestOrderSubmissionCheckOptions(t *testing.T) {
+	order := OrderSubmission{OrderType: Limit, TimeInForce: GTC}
+	err := order.CheckOptions("TESTNAME", OrderOptions{})
+	if err != nil {
+		t.Fatalf("Test failed. CheckOptions rejected GTC: %s", err)
+	}
+
+	for _, o := range []OrderSubmission{
+		{OrderType: Limit, TimeInForce: IOC},
+		{OrderType: Limit, PostOnly: true},
+		{OrderType: Limit, ReduceOnly: true},
+		{OrderType: Limit, AssetType: "quarter"},
+		{OrderType: Limit, ClientID: "abc"},
+	} {
+		err = o.CheckOptions("TESTNAME", OrderOptions{})
+		if !errors.Is(err, ErrOrderOptionNotSupported) {
+			t.Errorf("Test failed. Expected unsupported option error for %+v, got %v", o, err)
+		}
+	}
+
+	supported := OrderOptions{
+		TimeInForce:   []TimeInForce{GTC, IOC, FOK},
+		PostOnly:      true,
+		ReduceOnly:    true,
+		AssetTypes:    []string{"quarter"},
+		ClientOrderID: true,
+	}
+	order = OrderSubmission{OrderType: Limit, TimeInForce: FOK, ReduceOnly: true,
+		AssetType: "quarter", ClientID: "abc"}
+	err = order.CheckOptions("TESTNAME", supported)
+	if err != nil {
+		t.Fatalf("Test failed. CheckOptions: %s", err)
+	}
+
+	for _, o := range []OrderSubmission{
+		{OrderType: Market, PostOnly: true},
+		{OrderType: Limit, PostOnly: true, TimeInForce: IOC},
+	} {
+		err = o.CheckOptions("TESTNAME", supported)
+		if err == nil {
+			t.Errorf("Test failed. Expected an error for %+v", o)
+		}
+	}
+}
+
+func TestOrderSubmissionCheckOptionsStops(t *testing.T) {
+	for _, o := range []OrderSubmission{
+		{OrderType: Stop, TriggerPrice: 1},
+		{OrderType: StopLimit, Price: 1, TriggerPrice: 1},
+		{OrderType: TrailingStop, PegOffset: 1},
+	} {
+		err := o.CheckOptions("TESTNAME", OrderOptions{})
+		if !errors.Is(err, ErrOrderTypeNotSupported) {
+			t.Errorf("Test failed. Expected unsupported order type error for %+v, got %v",
+				o, err)
+		}
+	}
+
+	supported := OrderOptions{
+		PostOnly:   true,
+		OrderTypes: []OrderType{Stop, StopLimit, TrailingStop},
+	}
+	for _, o := range []OrderSubmission{
+		{OrderType: Stop, TriggerPrice: 1},
+		{OrderType: StopLimit, Price: 1, TriggerPrice: 1, PostOnly: true},
+		{OrderType: TrailingStop, PegOffset: 1},
+	} {
+		err := o.CheckOptions("TESTNAME", supported)
+		if err != nil {
+			t.Errorf("Test failed. CheckOptions rejected %+v: %s", o, err)
+		}
+	}
+
+	for _, o := range []OrderSubmission{
+		{OrderType: Stop},
+		{OrderType: StopLimit, TriggerPrice: 1},
+		{OrderType: StopLimit, Price: 1},
+		{OrderType: TrailingStop, TriggerPrice: 1},
+		{OrderType: TrailingStop, PegOffset: -1},
+		{OrderType: Stop, TriggerPrice: 1, PostOnly: true},
+	} {
+		err := o.CheckOptions("TESTNAME", supported)
+		if err == nil {
+			t.Errorf("Test failed. Expected an error for %+v", o)
+		}
+	}
+}
+
+func TestFormatOrderID(t *testing.T) {
+	if id := FormatOrderID(9223372036854775807); id != "9223372036854775807" {
+		t.Errorf("Test failed. FormatOrderID returned %s", id)
+	}
+	for input, expected := range map[float64]string{
+		123456789012: "123456789012",
+		42:           "42",
+	} {
+		if id := FormatFloatOrderID(input); id != expected {
+			t.Errorf("Test failed. FormatFloatOrderID expected %s, got %s", expected, id)
+		}
+	}
+}
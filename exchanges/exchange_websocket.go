@@ -3,10 +3,15 @@ package exchange
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
@@ -54,13 +59,44 @@ func (e *Base) WebsocketInit() {
 	}
 }
 
-// WebsocketSetup sets main variables for websocket connection
+// WebsocketSetup sets main variables for websocket connection. The default
+// URL is replaced by the sandbox websocket URL in sandbox mode. When an
+// exchange is set up again on reload its websocket keeps its channels, is
+// reconnected if its URL changed and is connected or shut down if it was
+// enabled or disabled
 func (e *Base) WebsocketSetup(connector func() error,
 	exchangeName string,
 	wsEnabled bool,
 	defaultURL,
 	runningURL string) error {
 
+	if runningURL != "" && runningURL != config.WebsocketURLNonDefaultMessage {
+		err := common.ValidateURL(runningURL, websocketURLSchemes...)
+		if err != nil {
+			return fmt.Errorf("%s websocket setup error: %w", exchangeName, err)
+		}
+	}
+	if e.sandbox && e.sandboxEndpoints.WebsocketURL != "" {
+		defaultURL = e.sandboxEndpoints.WebsocketURL
+	}
+
+	if !e.Websocket.init {
+		e.Websocket.SetDefaultURL(defaultURL)
+		if runningURL == "" || runningURL == config.WebsocketURLNonDefaultMessage {
+			runningURL = defaultURL
+		}
+		if runningURL != e.Websocket.GetWebsocketURL() {
+			err := e.Websocket.ChangeURL(runningURL)
+			if err != nil {
+				return err
+			}
+		}
+		if e.Websocket.IsEnabled() != wsEnabled {
+			return e.Websocket.SetEnabled(wsEnabled)
+		}
+		return nil
+	}
+
 	e.Websocket.DataHandler = make(chan interface{}, 1)
 	e.Websocket.Connected = make(chan struct{}, 1)
 	e.Websocket.Disconnected = make(chan struct{}, 1)
@@ -75,6 +111,7 @@ func (e *Base) WebsocketSetup(connector func() error,
 	e.Websocket.SetConnector(connector)
 	e.Websocket.SetWebsocketURL(runningURL)
 	e.Websocket.SetExchangeName(exchangeName)
+	e.Websocket.SetEnabledPairsFunc(e.GetEnabledCurrencies)
 
 	e.Websocket.init = false
 
@@ -85,6 +122,7 @@ func (e *Base) WebsocketSetup(connector func() error,
 // wrapper for routine processing in routines.go
 type Websocket struct {
 	proxyAddr    string
+	dialTimeout  time.Duration
 	defaultURL   string
 	runningURL   string
 	exchangeName string
@@ -94,6 +132,12 @@ type Websocket struct {
 	connector    func() error
 	m            sync.Mutex
 
+	subscriptionMtx sync.Mutex
+	subscribe       func(WebsocketChannelSubscription) error
+	unsubscribe     func(WebsocketChannelSubscription) error
+	enabledPairs    func() []pair.CurrencyPair
+	subscriptions   map[string]WebsocketChannelSubscription
+
 	// Connected denotes a channel switch for diversion of request flow
 	Connected chan struct{}
 
@@ -199,8 +243,16 @@ func (w *Websocket) Connect() error {
 	go w.trafficMonitor(&anotherWG)
 	anotherWG.Wait()
 
+	// Subscriptions belong to the previous connection
+	w.resetSubscriptions()
 	err := w.connector()
+	if err == nil {
+		err = w.ManageSubscriptions()
+	}
 	if err != nil {
+		// Stops the traffic monitor so it can't time out a later connection
+		close(w.ShutdownC)
+		w.Wg.Wait()
 		return fmt.Errorf("exchange_websocket.go connection error %s",
 			err)
 	}
@@ -238,6 +290,7 @@ func (w *Websocket) Shutdown() error {
 	select {
 	case <-c:
 		w.connected = false
+		w.resetSubscriptions()
 		return nil
 	case <-timer.C:
 		return fmt.Errorf("%s - Websocket routines failed to shutdown",
@@ -254,6 +307,23 @@ func (w *Websocket) SetWebsocketURL(URL string) {
 	w.runningURL = URL
 }
 
+// ChangeURL sets the running websocket URL, a connected websocket is shut down
+// and connected to the new URL
+func (w *Websocket) ChangeURL(URL string) error {
+	connected := w.IsConnected()
+	if connected {
+		err := w.Shutdown()
+		if err != nil {
+			return err
+		}
+	}
+	w.SetWebsocketURL(URL)
+	if connected {
+		return w.Connect()
+	}
+	return nil
+}
+
 // GetWebsocketURL returns the running websocket URL
 func (w *Websocket) GetWebsocketURL() string {
 	return w.runningURL
@@ -292,6 +362,11 @@ func (w *Websocket) IsEnabled() bool {
 	return w.enabled
 }
 
+// IsConnected returns whether the websocket connection is established
+func (w *Websocket) IsConnected() bool {
+	return w.connected
+}
+
 // SetProxyAddress sets websocket proxy address
 func (w *Websocket) SetProxyAddress(URL string) error {
 	if w.proxyAddr == URL {
@@ -318,6 +393,40 @@ func (w *Websocket) GetProxyAddress() string {
 	return w.proxyAddr
 }
 
+// SetDialTimeout sets the timeout of dialing the websocket, including the
+// TLS and websocket handshakes
+func (w *Websocket) SetDialTimeout(t time.Duration) {
+	w.dialTimeout = t
+}
+
+// GetDialTimeout returns the timeout of dialing the websocket,
+// DefaultHTTPTimeout is used if none is set
+func (w *Websocket) GetDialTimeout() time.Duration {
+	if w.dialTimeout <= 0 {
+		return DefaultHTTPTimeout
+	}
+	return w.dialTimeout
+}
+
+// Dialer returns a websocket dialer using the proxy address and failing to
+// connect, or to complete the TLS and websocket handshakes, within the dial
+// timeout
+func (w *Websocket) Dialer() (*websocket.Dialer, error) {
+	timeout := w.GetDialTimeout()
+	dialer := &websocket.Dialer{
+		NetDial:          (&net.Dialer{Timeout: timeout}).Dial,
+		HandshakeTimeout: timeout,
+	}
+	if w.proxyAddr != "" {
+		proxy, err := url.Parse(w.proxyAddr)
+		if err != nil {
+			return nil, err
+		}
+		dialer.Proxy = http.ProxyURL(proxy)
+	}
+	return dialer, nil
+}
+
 // SetDefaultURL sets default websocket URL
 func (w *Websocket) SetDefaultURL(defaultURL string) {
 	w.defaultURL = defaultURL
@@ -454,6 +563,8 @@ func (w *WebsocketOrderbookLocal) Update(bidTargets, askTargets []orderbook.Item
 		}()
 	}
 
+	orderbookAddress.LastUpdated = time.Now()
+	orderbookAddress.Source = orderbook.SourceWebsocket
 	orderbook.ProcessOrderbook(exchName, p, *orderbookAddress, assetType)
 	return nil
 }
@@ -466,6 +577,10 @@ func (w *WebsocketOrderbookLocal) LoadSnapshot(newOrderbook orderbook.Base, exch
 		return errors.New("exchange.go websocket orderbook cache LoadSnapshot() error - snapshot ask and bids are nil")
 	}
 
+	if newOrderbook.Source == "" {
+		newOrderbook.Source = orderbook.SourceWebsocket
+	}
+
 	w.m.Lock()
 	defer w.m.Unlock()
 
@@ -565,6 +680,8 @@ func (w *WebsocketOrderbookLocal) UpdateUsingID(bidTargets, askTargets []orderbo
 		orderbookAddress.Asks = append(orderbookAddress.Asks, askTargets...)
 	}
 
+	orderbookAddress.LastUpdated = time.Now()
+	orderbookAddress.Source = orderbook.SourceWebsocket
 	orderbook.ProcessOrderbook(exchName, p, *orderbookAddress, assetType)
 	return nil
 }
@@ -0,0 +1,176 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// Websocket channels subscribed for each enabled pair by the subscription
+// manager, depending on the supported websocket functionality
+const (
+	WebsocketChannelTicker    = "ticker"
+	WebsocketChannelOrderbook = "orderbook"
+	WebsocketChannelTrade     = "trade"
+	WebsocketChannelKline     = "kline"
+)
+
+// websocketPairChannels maps the websocket functionality streamed per pair to
+// its subscription channel
+var websocketPairChannels = []struct {
+	functionality uint32
+	channel       string
+}{
+	{WebsocketTickerSupported, WebsocketChannelTicker},
+	{WebsocketOrderbookSupported, WebsocketChannelOrderbook},
+	{WebsocketTradeDataSupported, WebsocketChannelTrade},
+	{WebsocketKlineSupported, WebsocketChannelKline},
+}
+
+// WebsocketChannelSubscription defines a websocket channel subscription for a
+// currency pair
+type WebsocketChannelSubscription struct {
+	Channel  string
+	Currency pair.CurrencyPair
+}
+
+// key returns the unique key of a subscription
+func (s WebsocketChannelSubscription) key() string {
+	return s.Channel + " " + strings.ToUpper(s.Currency.Pair().String())
+}
+
+// SetSubscriber sets the exchange functions which subscribe to and
+// unsubscribe from a websocket channel on the current connection. Once set,
+// the websocket subscribes to the channels of the enabled pairs when
+// connecting and whenever the enabled pairs change
+func (w *Websocket) SetSubscriber(subscribe, unsubscribe func(WebsocketChannelSubscription) error) {
+	w.subscriptionMtx.Lock()
+	w.subscribe = subscribe
+	w.unsubscribe = unsubscribe
+	w.subscriptionMtx.Unlock()
+}
+
+// SetEnabledPairsFunc sets the function returning the enabled currency pairs
+// to subscribe to
+func (w *Websocket) SetEnabledPairsFunc(enabledPairs func() []pair.CurrencyPair) {
+	w.subscriptionMtx.Lock()
+	w.enabledPairs = enabledPairs
+	w.subscriptionMtx.Unlock()
+}
+
+// GetSubscriptions returns the current channel subscriptions sorted by
+// channel and currency pair
+func (w *Websocket) GetSubscriptions() []WebsocketChannelSubscription {
+	w.subscriptionMtx.Lock()
+	defer w.subscriptionMtx.Unlock()
+	subs := make([]WebsocketChannelSubscription, 0, len(w.subscriptions))
+	for _, sub := range w.subscriptions {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].key() < subs[j].key() })
+	return subs
+}
+
+// desiredSubscriptions returns the channel subscriptions of the enabled pairs
+// for the supported websocket functionality
+func (w *Websocket) desiredSubscriptions() map[string]WebsocketChannelSubscription {
+	desired := make(map[string]WebsocketChannelSubscription)
+	if w.enabledPairs == nil {
+		return desired
+	}
+	pairs := w.enabledPairs()
+	for x := range websocketPairChannels {
+		if !w.SupportsFunctionality(websocketPairChannels[x].functionality) {
+			continue
+		}
+		for y := range pairs {
+			sub := WebsocketChannelSubscription{
+				Channel:  websocketPairChannels[x].channel,
+				Currency: pairs[y],
+			}
+			desired[sub.key()] = sub
+		}
+	}
+	return desired
+}
+
+// sortedKeys returns the keys of the subscriptions in order
+func sortedKeys(subs map[string]WebsocketChannelSubscription) []string {
+	keys := make([]string, 0, len(subs))
+	for key := range subs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ManageSubscriptions diffs the channel subscriptions of the enabled pairs
+// against the current subscriptions, unsubscribing from channels no longer
+// required and subscribing to new ones. Failed changes are retried on the
+// next call
+func (w *Websocket) ManageSubscriptions() error {
+	w.subscriptionMtx.Lock()
+	defer w.subscriptionMtx.Unlock()
+
+	if w.subscribe == nil || w.unsubscribe == nil {
+		return nil
+	}
+	if w.subscriptions == nil {
+		w.subscriptions = make(map[string]WebsocketChannelSubscription)
+	}
+
+	desired := w.desiredSubscriptions()
+	var errs []string
+	for _, key := range sortedKeys(w.subscriptions) {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		err := w.unsubscribe(w.subscriptions[key])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("unsubscribe %s: %s", key, err))
+			continue
+		}
+		delete(w.subscriptions, key)
+	}
+
+	for _, key := range sortedKeys(desired) {
+		if _, ok := w.subscriptions[key]; ok {
+			continue
+		}
+		err := w.subscribe(desired[key])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("subscribe %s: %s", key, err))
+			continue
+		}
+		w.subscriptions[key] = desired[key]
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// resetSubscriptions clears the current subscriptions when a connection is
+// established or closed
+func (w *Websocket) resetSubscriptions() {
+	w.subscriptionMtx.Lock()
+	w.subscriptions = nil
+	w.subscriptionMtx.Unlock()
+}
+
+// updateWebsocketSubscriptions applies an enabled pairs change to the
+// subscriptions of a connected websocket
+func (e *Base) updateWebsocketSubscriptions() {
+	if e.Websocket == nil || !e.Websocket.IsConnected() {
+		return
+	}
+	err := e.Websocket.ManageSubscriptions()
+	if err != nil {
+		log.Errorf("%s websocket subscription update error: %s\n", e.Name, err)
+	}
+}
@@ -1,6 +1,8 @@
 package exchange
 
 import (
+	"errors"
+	"net"
 	"testing"
 	"time"
 
@@ -22,6 +24,47 @@ func TestWebsocketInit(t *testing.T) {
 	}
 }
 
+func TestWebsocketDialer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	w := Websocket{}
+	if w.GetDialTimeout() != DefaultHTTPTimeout {
+		t.Errorf("test failed - expected default dial timeout, got %v", w.GetDialTimeout())
+	}
+	w.SetDialTimeout(time.Millisecond * 100)
+	dialer, err := w.Dialer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, _, err = dialer.Dial("ws://"+l.Addr().String(), nil)
+	if err == nil {
+		t.Fatal("test failed - dialing an unresponsive endpoint succeeded")
+	}
+	if time.Since(start) > time.Second*2 {
+		t.Errorf("test failed - dial took %v to time out", time.Since(start))
+	}
+
+	w.proxyAddr = "://blah"
+	if _, err = w.Dialer(); err == nil {
+		t.Error("test failed - expected an invalid proxy address error")
+	}
+}
+
 func TestWebsocket(t *testing.T) {
 	if err := wsTest.Websocket.SetProxyAddress("testProxy"); err != nil {
 		t.Error("test failed - SetProxyAddress", err)
@@ -31,7 +74,7 @@ func TestWebsocket(t *testing.T) {
 		"testName",
 		true,
 		"testDefaultURL",
-		"testRunningURL")
+		"wss://testRunningURL")
 
 	// Test variable setting and retreival
 	if wsTest.Websocket.GetName() != "testName" {
@@ -50,7 +93,7 @@ func TestWebsocket(t *testing.T) {
 		t.Error("test failed - WebsocketSetup")
 	}
 
-	if wsTest.Websocket.GetWebsocketURL() != "testRunningURL" {
+	if wsTest.Websocket.GetWebsocketURL() != "wss://testRunningURL" {
 		t.Error("test failed - WebsocketSetup")
 	}
 
@@ -334,3 +377,120 @@ func TestFunctionality(t *testing.T) {
 		t.Fatal("Test Failed - SupportsFunctionality error should be true")
 	}
 }
+
+// fakeSubscriptionConn records the channel subscriptions of a websocket
+// connection and fails those set to fail
+type fakeSubscriptionConn struct {
+	channels     map[string]bool
+	fail         map[string]bool
+	subscribed   int
+	unsubscribed int
+}
+
+func (f *fakeSubscriptionConn) subscribe(sub WebsocketChannelSubscription) error {
+	if f.fail[sub.key()] {
+		return errors.New("subscription rejected")
+	}
+	f.subscribed++
+	f.channels[sub.key()] = true
+	return nil
+}
+
+func (f *fakeSubscriptionConn) unsubscribe(sub WebsocketChannelSubscription) error {
+	if f.fail[sub.key()] {
+		return errors.New("unsubscription rejected")
+	}
+	f.unsubscribed++
+	delete(f.channels, sub.key())
+	return nil
+}
+
+func TestManageSubscriptions(t *testing.T) {
+	var b Base
+	b.Name = "SubscriptionTest"
+	b.EnabledPairs = []string{"BTC-USD", "LTC-USD"}
+	b.ConfigCurrencyPairFormat.Delimiter = "-"
+	b.WebsocketInit()
+	b.Websocket.Functionality = WebsocketTickerSupported | WebsocketOrderbookSupported
+	err := b.WebsocketSetup(func() error { return nil }, b.Name, true, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := &fakeSubscriptionConn{channels: make(map[string]bool),
+		fail: make(map[string]bool)}
+	b.Websocket.SetSubscriber(conn.subscribe, conn.unsubscribe)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-b.Websocket.Connected:
+			case <-b.Websocket.Disconnected:
+			case <-done:
+				return
+			}
+		}
+	}()
+	err = b.Websocket.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.subscribed != 4 || len(b.Websocket.GetSubscriptions()) != 4 ||
+		!conn.channels["orderbook LTC-USD"] || !conn.channels["ticker BTC-USD"] {
+		t.Fatalf("test failed - unexpected subscriptions after connecting %v", conn.channels)
+	}
+
+	// Only the changed pairs are subscribed and unsubscribed
+	b.EnabledPairs = []string{"BTC-USD", "ETH-USD"}
+	err = b.Websocket.ManageSubscriptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.subscribed != 6 || conn.unsubscribed != 2 || conn.channels["ticker LTC-USD"] ||
+		!conn.channels["ticker ETH-USD"] || len(conn.channels) != 4 {
+		t.Errorf("test failed - unexpected subscriptions after the pairs changed %v", conn.channels)
+	}
+
+	// Failed changes are reported and retried on the next update
+	b.EnabledPairs = []string{"BTC-USD"}
+	conn.fail["ticker ETH-USD"] = true
+	if err = b.Websocket.ManageSubscriptions(); err == nil {
+		t.Error("test failed - expected the rejected unsubscription to be reported")
+	}
+	if len(b.Websocket.GetSubscriptions()) != 3 || !conn.channels["ticker ETH-USD"] {
+		t.Errorf("test failed - unexpected subscriptions after a failed unsubscription %v",
+			b.Websocket.GetSubscriptions())
+	}
+	delete(conn.fail, "ticker ETH-USD")
+	if err = b.Websocket.ManageSubscriptions(); err != nil {
+		t.Fatal(err)
+	}
+	subs := b.Websocket.GetSubscriptions()
+	if len(subs) != 2 || subs[0].Channel != WebsocketChannelOrderbook ||
+		subs[1].Channel != WebsocketChannelTicker || len(conn.channels) != 2 {
+		t.Errorf("test failed - unexpected subscriptions after the retry %v", subs)
+	}
+
+	// Subscriptions are dropped with the connection and restored on connect
+	err = b.Websocket.Shutdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Websocket.GetSubscriptions()) != 0 {
+		t.Error("test failed - expected no subscriptions after shutting down")
+	}
+	conn.channels = make(map[string]bool)
+	err = b.Websocket.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.channels) != 2 {
+		t.Errorf("test failed - expected the subscriptions to be restored %v", conn.channels)
+	}
+	err = b.Websocket.Shutdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package exmo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -64,6 +65,8 @@ func (e *EXMO) SetDefaults() {
 	e.ConfigCurrencyPairFormat.Delimiter = "_"
 	e.ConfigCurrencyPairFormat.Uppercase = true
 	e.AssetTypes = []string{ticker.Spot}
+	e.BaseCurrencies = []string{"USD", "EUR", "RUB", "PLN", "UAH"}
+	e.EnabledPairs = []string{"BTC_USD", "LTC_USD"}
 	e.SupportsAutoPairUpdating = true
 	e.SupportsRESTTickerBatching = true
 	e.Requester = request.New(e.Name,
@@ -390,8 +393,10 @@ func (e *EXMO) SendAuthenticatedHTTPRequest(method, endpoint string, vals url.Va
 	payload := vals.Encode()
 	hash := common.GetHMAC(common.HashSHA512, []byte(payload), []byte(e.APISecret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if e.Verbose {
-		log.Debugf("Sending %s request to %s with params %s\n", method, endpoint, payload)
+		log.Debugf("%s request %s. Sending %s request to %s with params %s",
+			e.Name, id, method, endpoint, request.ScrubBody(payload))
 	}
 
 	headers := make(map[string]string)
@@ -401,7 +406,7 @@ func (e *EXMO) SendAuthenticatedHTTPRequest(method, endpoint string, vals url.Va
 
 	path := fmt.Sprintf("%s/v%s/%s", e.APIUrl, exmoAPIVersion, endpoint)
 
-	return e.SendPayload(method, path, headers, strings.NewReader(payload), result, true, e.Verbose)
+	return e.SendPayloadWithContext(ctx, method, path, headers, strings.NewReader(payload), result, true, e.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -30,19 +31,26 @@ func (e *EXMO) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", e.GetName(), len(e.EnabledPairs), e.EnabledPairs)
 	}
 
+	err := e.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", e.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (e *EXMO) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := e.GetPairSettings()
 	if err != nil {
-		log.Errorf("%s Failed to get available products.\n", e.GetName())
-	} else {
-		var currencies []string
-		for x := range exchangeProducts {
-			currencies = append(currencies, x)
-		}
-		err = e.UpdateCurrencies(currencies, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", e.GetName())
-		}
+		return err
+	}
+
+	var currencies []string
+	for x := range exchangeProducts {
+		currencies = append(currencies, x)
 	}
+
+	return e.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -69,7 +77,10 @@ func (e *EXMO) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price
 		tickerPrice.Last = result[currency].Last
 		tickerPrice.Low = result[currency].Low
 		tickerPrice.Volume = result[currency].Volume
-		ticker.ProcessTicker(e.Name, x, tickerPrice, assetType)
+		err = ticker.ProcessTicker(e.Name, x, tickerPrice, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(e.Name, p, assetType)
 }
@@ -176,20 +187,25 @@ func (e *EXMO) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (e *EXMO) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (e *EXMO) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (e *EXMO) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (e *EXMO) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(e.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var oT string
-	if orderType == exchange.Limit {
+	if order.OrderType == exchange.Limit {
 		return submitOrderResponse, errors.New("Unsupported order type")
-	} else if orderType == exchange.Market {
-		if side == exchange.Buy {
+	} else if order.OrderType == exchange.Market {
+		if order.OrderSide == exchange.Buy {
 			oT = "market_buy"
 		} else {
 			oT = "market_sell"
@@ -198,10 +214,10 @@ func (e *EXMO) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderTy
 		return submitOrderResponse, errors.New("Unsupported order type")
 	}
 
-	response, err := e.CreateOrder(p.Pair().String(), oT, price, amount)
+	response, err := e.CreateOrder(order.Pair.Pair().String(), oT, order.Price, order.Amount)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -249,24 +265,26 @@ func (e *EXMO) CancelAllOrders(orderCancellation exchange.OrderCancellation) (ex
 }
 
 // GetOrderInfo returns information on a current open order
-func (e *EXMO) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (e *EXMO) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (e *EXMO) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	fullAddr, err := e.GetCryptoDepositAddress()
-	if err != nil {
-		return "", err
-	}
+func (e *EXMO) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return e.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		fullAddr, err := e.GetCryptoDepositAddress()
+		if err != nil {
+			return "", err
+		}
 
-	addr, ok := fullAddr[cryptocurrency.String()]
-	if !ok {
-		return "", fmt.Errorf("currency %s could not be found, please generate via the exmo website", cryptocurrency.String())
-	}
+		addr, ok := fullAddr[cryptocurrency.String()]
+		if !ok {
+			return "", fmt.Errorf("currency %s could not be found, please generate via the exmo website", cryptocurrency.String())
+		}
 
-	return addr, nil
+		return addr, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
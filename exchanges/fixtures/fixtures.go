@@ -0,0 +1,108 @@
+// Package fixtures replays request fixtures captured by the exchange
+// requester so exchange wrappers can be tested without reaching the exchange
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+// Load returns the fixtures in a directory in capture order
+func Load(dir string) ([]request.Fixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	sort.Strings(files)
+
+	fixtures := make([]request.Fixture, 0, len(files))
+	for x := range files {
+		data, err := ioutil.ReadFile(files[x])
+		if err != nil {
+			return nil, err
+		}
+		var f request.Fixture
+		err = json.Unmarshal(data, &f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", files[x], err)
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// Server replays fixtures keyed by request method and path. Fixtures captured
+// for the same method and path are served in capture order, the last one is
+// repeated once they are exhausted
+type Server struct {
+	*httptest.Server
+	fixtures map[string][]request.Fixture
+	served   map[string]int
+	m        sync.Mutex
+}
+
+// NewServer starts a server replaying the fixtures in a directory, it must be
+// closed when no longer needed
+func NewServer(dir string) (*Server, error) {
+	fixtures, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		fixtures: make(map[string][]request.Fixture),
+		served:   make(map[string]int),
+	}
+	for x := range fixtures {
+		key, err := fixtures[x].Key()
+		if err != nil {
+			return nil, err
+		}
+		s.fixtures[key] = append(s.fixtures[key], fixtures[x])
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s, nil
+}
+
+// Served returns how many requests were served for a method and path
+func (s *Server) Served(method, path string) int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.served[method+" "+path]
+}
+
+// serve writes the next fixture response for the request method and path, a
+// request without a fixture receives a not found status
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+	s.m.Lock()
+	fixtures, ok := s.fixtures[key]
+	if !ok {
+		s.m.Unlock()
+		http.Error(w, fmt.Sprintf("no fixture for %s", key), http.StatusNotFound)
+		return
+	}
+	f := fixtures[len(fixtures)-1]
+	if s.served[key] < len(fixtures) {
+		f = fixtures[s.served[key]]
+	}
+	s.served[key]++
+	s.m.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if f.StatusCode != 0 {
+		w.WriteHeader(f.StatusCode)
+	}
+	w.Write([]byte(f.Response))
+}
@@ -0,0 +1,78 @@
+package fixtures
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+func TestNewServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = NewServer(dir)
+	if err == nil {
+		t.Error("Test failed - NewServer() expected an error without fixtures")
+	}
+
+	captured := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	for x, f := range []request.Fixture{
+		{Method: "GET", URL: "https://api.exchange.com/v1/ticker?pair=BTCUSD", StatusCode: http.StatusOK, Response: `{"last":1}`},
+		{Method: "GET", URL: "https://api.exchange.com/v1/ticker?pair=LTCUSD", StatusCode: http.StatusOK, Response: `{"last":2}`},
+		{Method: "POST", URL: "https://api.exchange.com/v1/order", StatusCode: http.StatusBadRequest, Response: `{"error":"funds"}`},
+	} {
+		f.Timestamp = captured.Add(time.Second * time.Duration(x))
+		_, err = request.WriteFixture(dir, &f)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv, err := NewServer(dir)
+	if err != nil {
+		t.Fatalf("Test failed - NewServer() error: %s", err)
+	}
+	defer srv.Close()
+
+	for _, expected := range []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"GET", "/v1/ticker", http.StatusOK, `{"last":1}`},
+		{"GET", "/v1/ticker", http.StatusOK, `{"last":2}`},
+		{"GET", "/v1/ticker", http.StatusOK, `{"last":2}`},
+		{"POST", "/v1/order", http.StatusBadRequest, `{"error":"funds"}`},
+		{"GET", "/v1/order", http.StatusNotFound, "no fixture for GET /v1/order\n"},
+	} {
+		req, err := http.NewRequest(expected.method, srv.URL+expected.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != expected.status || string(body) != expected.body {
+			t.Errorf("Test failed - %s %s expected %d %s, received %d %s",
+				expected.method, expected.path, expected.status, expected.body,
+				resp.StatusCode, body)
+		}
+	}
+
+	if served := srv.Served("GET", "/v1/ticker"); served != 3 {
+		t.Errorf("Test failed - expected 3 ticker requests served, received %d", served)
+	}
+}
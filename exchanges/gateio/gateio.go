@@ -61,6 +61,8 @@ func (g *Gateio) SetDefaults() {
 	g.ConfigCurrencyPairFormat.Delimiter = "_"
 	g.ConfigCurrencyPairFormat.Uppercase = true
 	g.AssetTypes = []string{ticker.Spot}
+	g.BaseCurrencies = []string{"USD"}
+	g.EnabledPairs = []string{"BTC_USDT"}
 	g.SupportsAutoPairUpdating = true
 	g.SupportsRESTTickerBatching = true
 	g.Requester = request.New(g.Name,
@@ -76,6 +78,7 @@ func (g *Gateio) SetDefaults() {
 		exchange.WebsocketTradeDataSupported |
 		exchange.WebsocketOrderbookSupported |
 		exchange.WebsocketKlineSupported
+	g.Websocket.SetSubscriber(g.WsSubscribe, g.WsUnsubscribe)
 }
 
 // Setup sets user configuration
@@ -197,12 +200,13 @@ func (g *Gateio) GetTicker(symbol string) (TickerResponse, error) {
 	return res, nil
 }
 
-// GetTickers returns tickers for all symbols
+// GetTickers returns tickers for all symbols. Responses are briefly cached as
+// the tickers of every pair are returned by a single request
 func (g *Gateio) GetTickers() (map[string]TickerResponse, error) {
 	url := fmt.Sprintf("%s/%s/%s", g.APIUrlSecondary, gateioAPIVersion, gateioTickers)
 
 	resp := make(map[string]TickerResponse)
-	err := g.SendHTTPRequest(url, &resp)
+	err := g.SendCacheablePayload(url, nil, &resp, 0, g.Verbose)
 	if err != nil {
 		return nil, err
 	}
@@ -454,7 +458,7 @@ func (g *Gateio) GetOpenOrders(symbol string) (OpenOrdersResponse, error) {
 // To use this you must setup an APIKey and APISecret from the exchange
 func (g *Gateio) SendAuthenticatedHTTPRequest(method, endpoint, param string, result interface{}) error {
 	if !g.AuthenticatedAPISupport {
-		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, g.Name)
+		return exchange.NewAuthenticationNotConfiguredError(g.Name)
 	}
 
 	headers := make(map[string]string)
@@ -470,7 +474,7 @@ func (g *Gateio) SendAuthenticatedHTTPRequest(method, endpoint, param string, re
 
 	err := g.SendPayload(method, url, headers, strings.NewReader(param), &intermidiary, true, g.Verbose)
 	if err != nil {
-		return err
+		return exchange.ClassifyRequestError(err)
 	}
 
 	errCap := struct {
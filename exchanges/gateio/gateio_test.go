@@ -1,13 +1,22 @@
 package gateio
 
 import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 // Please supply your own APIKEYS here for due diligence testing
@@ -123,6 +132,67 @@ func TestGetTickers(t *testing.T) {
 	}
 }
 
+func TestUpdateTickers(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"btc_usdt":{"result":"true","last":"6500.5","high24hr":"6600","low24hr":"6400","baseVolume":"1200"},` +
+			`"eth_usdt":{"result":"true","last":"220","high24hr":"230","low24hr":"210","baseVolume":"5400",` +
+			`"highestBid":"219.9","lowestAsk":"220.2","percentChange":"10"}}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+
+	var x Gateio
+	x.SetDefaults()
+	x.APIUrlSecondary = srv.URL
+	x.EnabledPairs = []string{"BTC_USDT", "ETH_USDT", "ZZZ_USDT"}
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	err := x.UpdateTickers(ticker.Spot)
+	if err != nil {
+		t.Fatal("Test failed - Gateio UpdateTickers:", err)
+	}
+	if requests != 1 {
+		t.Errorf("Test failed - expected 1 tickers request, received %d", requests)
+	}
+
+	tick, err := ticker.GetTicker(x.Name, pair.NewCurrencyPair("ETH", "USDT"), ticker.Spot)
+	if err != nil || tick.Last != 220 || tick.Volume != 5400 ||
+		tick.Bid != 219.9 || tick.Ask != 220.2 {
+		t.Errorf("Test failed - unexpected cached ticker %+v: %v", tick, err)
+	}
+	if math.Abs(tick.Open-200) > 1e-9 || math.Abs(tick.Change-20) > 1e-9 ||
+		tick.ChangePercent != 10 {
+		t.Errorf("Test failed - unexpected ticker change %+v", tick)
+	}
+	_, err = ticker.GetTicker(x.Name, pair.NewCurrencyPair("ZZZ", "USDT"), ticker.Spot)
+	if err == nil {
+		t.Error("Test failed - expected no ticker for a pair missing from the response")
+	}
+
+	tick, err = x.UpdateTicker(pair.NewCurrencyPair("BTC", "USDT"), ticker.Spot)
+	if err != nil || tick.Last != 6500.5 {
+		t.Errorf("Test failed - unexpected ticker %+v: %v", tick, err)
+	}
+	// A stored ticker is stale once the pair is missing from the response
+	err = ticker.ProcessTicker(x.Name, pair.NewCurrencyPair("ZZZ", "USDT"),
+		ticker.Price{Pair: pair.NewCurrencyPair("ZZZ", "USDT"), Last: 1}, ticker.Spot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = x.UpdateTicker(pair.NewCurrencyPair("ZZZ", "USDT"), ticker.Spot)
+	if err == nil {
+		t.Error("Test failed - expected an error for a pair missing from the response")
+	}
+}
+
 func TestGetOrderbook(t *testing.T) {
 	t.Parallel()
 	_, err := g.GetOrderbook("btc_usdt")
@@ -274,7 +344,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.LTC,
 		SecondCurrency: symbol.BTC,
 	}
-	response, err := g.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 10, "1234234")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     10,
+		Amount:    1,
+	}
+	response, err := g.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -282,6 +359,74 @@ func TestSubmitOrder(t *testing.T) {
 	}
 }
 
+func TestSubmitOrderUnsupportedType(t *testing.T) {
+	var x Gateio
+	x.SetDefaults()
+	for _, orderType := range []exchange.OrderType{exchange.Stop, exchange.StopLimit, exchange.TrailingStop} {
+		_, err := x.SubmitOrder(&exchange.OrderSubmission{
+			Pair:         pair.NewCurrencyPairDelimiter("LTC_BTC", "_"),
+			OrderSide:    exchange.Sell,
+			OrderType:    orderType,
+			Amount:       1,
+			Price:        10,
+			TriggerPrice: 9,
+			PegOffset:    1,
+		})
+		if !errors.Is(err, exchange.ErrOrderTypeNotSupported) {
+			t.Errorf("Test failed - expected unsupported order type error for %s, got %v",
+				orderType, err)
+		}
+	}
+}
+
+func TestSubmitOrderResponse(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":"true","orderNumber":1234567890123,"rate":"10",` +
+			`"leftAmount":"0.4","filledAmount":"0.6","filledRate":"10"}`))
+	}))
+	defer srv.Close()
+
+	var x Gateio
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.AuthenticatedAPISupport = true
+	x.APIKey = "key"
+	x.APISecret = "secret"
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	response, err := x.SubmitOrder(&exchange.OrderSubmission{
+		Pair:      pair.NewCurrencyPairDelimiter("LTC_BTC", "_"),
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Limit,
+		Price:     10,
+		Amount:    1,
+	})
+	if err != nil {
+		t.Fatal("Test failed - Gateio SubmitOrder:", err)
+	}
+	if path != "/"+gateioAPIVersion+"/private/buy" {
+		t.Errorf("Test failed - unexpected request path %s", path)
+	}
+	if !response.IsOrderPlaced || response.OrderID != "1234567890123" {
+		t.Errorf("Test failed - unexpected order ID %q", response.OrderID)
+	}
+	for _, c := range response.OrderID {
+		if c < '0' || c > '9' {
+			t.Fatalf("Test failed - expected a numeric order ID, got %q", response.OrderID)
+		}
+	}
+	if response.FilledAmount == nil || *response.FilledAmount != 0.6 ||
+		response.RemainingAmount == nil || *response.RemainingAmount != 0.4 {
+		t.Errorf("Test failed - unexpected filled and remaining amounts %+v", response)
+	}
+}
+
 func TestCancelExchangeOrder(t *testing.T) {
 	// Arrange
 	g.SetDefaults()
@@ -424,14 +569,119 @@ func TestWithdrawInternationalBank(t *testing.T) {
 
 func TestGetDepositAddress(t *testing.T) {
 	if areTestAPIKeysSet() {
-		_, err := g.GetDepositAddress(symbol.ETC, "")
+		_, err := g.GetDepositAddress(symbol.ETC, "", false)
 		if err != nil {
 			t.Error("Test Fail - GetDepositAddress error", err)
 		}
 	} else {
-		_, err := g.GetDepositAddress(symbol.ETC, "")
+		_, err := g.GetDepositAddress(symbol.ETC, "", false)
 		if err == nil {
 			t.Error("Test Fail - GetDepositAddress error cannot be nil")
 		}
 	}
 }
+
+func TestAuthenticationNotConfiguredError(t *testing.T) {
+	var x Gateio
+	x.SetDefaults()
+	x.AuthenticatedAPISupport = false
+	err := x.SendAuthenticatedHTTPRequest("POST", gateioBalances, "", nil)
+	if !errors.Is(err, exchange.ErrAuthenticationNotConfigured) {
+		t.Errorf("Test failed - expected authentication not configured error, received %v", err)
+	}
+}
+
+func TestWsSubscriptions(t *testing.T) {
+	requests := make(chan WebsocketRequest, 4)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req WebsocketRequest
+			if conn.ReadJSON(&req) != nil {
+				return
+			}
+			requests <- req
+		}
+	}))
+	defer server.Close()
+
+	var x Gateio
+	x.SetDefaults()
+	var err error
+	x.WebsocketConn, _, err = websocket.DefaultDialer.Dial("ws"+server.URL[len("http"):], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer x.WebsocketConn.Close()
+
+	p := pair.NewCurrencyPair("btc", "usdt")
+	for _, test := range []struct {
+		channel     string
+		unsubscribe bool
+		method      string
+		params      int
+	}{
+		{exchange.WebsocketChannelOrderbook, false, "depth.subscribe", 3},
+		{exchange.WebsocketChannelKline, false, "kline.subscribe", 2},
+		{exchange.WebsocketChannelTrade, false, "trades.subscribe", 1},
+		{exchange.WebsocketChannelOrderbook, true, "depth.unsubscribe", 1},
+	} {
+		sub := exchange.WebsocketChannelSubscription{Channel: test.channel, Currency: p}
+		if test.unsubscribe {
+			err = x.WsUnsubscribe(sub)
+		} else {
+			err = x.WsSubscribe(sub)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case req := <-requests:
+			if req.Method != test.method || len(req.Params) != test.params ||
+				req.Params[0] != "BTC_USDT" {
+				t.Errorf("Test failed - Gateio unexpected websocket request %+v", req)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Test failed - Gateio %s request not received", test.method)
+		}
+	}
+
+	if x.WsSubscribe(exchange.WebsocketChannelSubscription{Channel: "blah", Currency: p}) == nil {
+		t.Error("Test failed - Gateio expected an unsupported channel error")
+	}
+}
+
+func TestUpdateOrderbook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":"true","asks":[["6602","1"],["6601","1.5"],["6600.5","2"]],` +
+			`"bids":[["6599.5","1"],["6599","3"]]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+
+	var x Gateio
+	x.SetDefaults()
+	x.APIUrlSecondary = srv.URL
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	ob, err := x.UpdateOrderbook(pair.NewCurrencyPair("BTC", "USDT"), ticker.Spot)
+	if err != nil {
+		t.Fatal("Test failed - Gateio UpdateOrderbook:", err)
+	}
+	if len(ob.Bids) != 2 || len(ob.Asks) == 0 || ob.LastUpdated.IsZero() ||
+		ob.Source != orderbook.SourceREST {
+		t.Errorf("Test failed - unexpected orderbook %+v", ob)
+	}
+}
@@ -84,10 +84,10 @@ type TickerResponse struct {
 	Result        string  `json:"result"`
 	Volume        float64 `json:"baseVolume,string"`    // Trading volume
 	High          float64 `json:"high24hr,string"`      // 24 hour high price
-	Open          float64 `json:"highestBid,string"`    // Openening price
+	HighestBid    float64 `json:"highestBid,string"`    // Best bid price
 	Last          float64 `json:"last,string"`          // Last price
 	Low           float64 `json:"low24hr,string"`       // 24 hour low price
-	Close         float64 `json:"lowestAsk,string"`     // Closing price
+	LowestAsk     float64 `json:"lowestAsk,string"`     // Best ask price
 	PercentChange float64 `json:"percentChange,string"` // Percentage change
 	QuoteVolume   float64 `json:"quoteVolume,string"`   // Quote currency volume
 }
@@ -4,11 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
@@ -26,17 +24,11 @@ func (g *Gateio) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-	if g.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(g.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := g.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	g.WebsocketConn, _, err = dialer.Dial(g.Websocket.GetWebsocketURL(),
 		http.Header{})
 	if err != nil {
@@ -45,60 +37,55 @@ func (g *Gateio) WsConnect() error {
 
 	go g.WsHandleData()
 
-	return g.WsSubscribe()
+	return nil
 }
 
-// WsSubscribe subscribes to the full websocket suite on ZB exchange
-func (g *Gateio) WsSubscribe() error {
-	enabled := g.GetEnabledCurrencies()
-
-	for _, c := range enabled {
-		ticker := WebsocketRequest{
-			ID:     1337,
-			Method: "ticker.subscribe",
-			Params: []interface{}{c.Pair().String()},
-		}
-
-		err := g.WebsocketConn.WriteJSON(ticker)
-		if err != nil {
-			return err
-		}
-
-		trade := WebsocketRequest{
-			ID:     1337,
-			Method: "trades.subscribe",
-			Params: []interface{}{c.Pair().String()},
-		}
-
-		err = g.WebsocketConn.WriteJSON(trade)
-		if err != nil {
-			return err
-		}
-
-		depth := WebsocketRequest{
-			ID:     1337,
-			Method: "depth.subscribe",
-			Params: []interface{}{c.Pair().String(), 30, "0.1"},
-		}
-
-		err = g.WebsocketConn.WriteJSON(depth)
-		if err != nil {
-			return err
+// wsRequest returns the websocket request of a subscription method for the
+// channel of a subscription
+func wsRequest(sub exchange.WebsocketChannelSubscription, method string) (WebsocketRequest, error) {
+	c := sub.Currency.Display("_", true).String()
+	switch sub.Channel {
+	case exchange.WebsocketChannelTicker:
+		return WebsocketRequest{ID: 1337, Method: "ticker." + method,
+			Params: []interface{}{c}}, nil
+	case exchange.WebsocketChannelTrade:
+		return WebsocketRequest{ID: 1337, Method: "trades." + method,
+			Params: []interface{}{c}}, nil
+	case exchange.WebsocketChannelOrderbook:
+		params := []interface{}{c}
+		if method == "subscribe" {
+			params = append(params, 30, "0.1")
 		}
-
-		kline := WebsocketRequest{
-			ID:     1337,
-			Method: "kline.subscribe",
-			Params: []interface{}{c.Pair().String(), 1800},
+		return WebsocketRequest{ID: 1337, Method: "depth." + method,
+			Params: params}, nil
+	case exchange.WebsocketChannelKline:
+		params := []interface{}{c}
+		if method == "subscribe" {
+			params = append(params, 1800)
 		}
+		return WebsocketRequest{ID: 1337, Method: "kline." + method,
+			Params: params}, nil
+	}
+	return WebsocketRequest{}, fmt.Errorf("unsupported websocket channel %s",
+		sub.Channel)
+}
 
-		err = g.WebsocketConn.WriteJSON(kline)
-		if err != nil {
-			return err
-		}
+// WsSubscribe subscribes to a websocket channel
+func (g *Gateio) WsSubscribe(sub exchange.WebsocketChannelSubscription) error {
+	req, err := wsRequest(sub, "subscribe")
+	if err != nil {
+		return err
 	}
+	return g.WebsocketConn.WriteJSON(req)
+}
 
-	return nil
+// WsUnsubscribe unsubscribes from a websocket channel
+func (g *Gateio) WsUnsubscribe(sub exchange.WebsocketChannelSubscription) error {
+	req, err := wsRequest(sub, "unsubscribe")
+	if err != nil {
+		return err
+	}
+	return g.WebsocketConn.WriteJSON(req)
 }
 
 // WsReadData reads from the websocket connection and returns the websocket
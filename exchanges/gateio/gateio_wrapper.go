@@ -32,38 +32,81 @@ func (g *Gateio) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", g.GetName(), len(g.EnabledPairs), g.EnabledPairs)
 	}
 
+	err := g.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", g.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (g *Gateio) UpdateTradablePairs(forceUpdate bool) error {
 	symbols, err := g.GetSymbols()
 	if err != nil {
-		log.Errorf("%s Unable to fetch symbols.\n", g.GetName())
-	} else {
-		err = g.UpdateCurrencies(symbols, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", g.GetName())
-		}
+		return err
 	}
+
+	return g.UpdateCurrencies(symbols, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
 func (g *Gateio) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
-	var tickerPrice ticker.Price
 	result, err := g.GetTickers()
 	if err != nil {
-		return tickerPrice, err
+		return ticker.Price{}, err
+	}
+	g.processTickers(result, assetType)
+
+	// A stored ticker isn't returned for a pair missing from the response as
+	// it would be stale
+	currency := exchange.FormatExchangeCurrency(g.Name, p).String()
+	if _, ok := result[currency]; !ok {
+		return ticker.Price{}, fmt.Errorf("%s ticker for %s not returned by the exchange",
+			g.Name, currency)
+	}
+	return ticker.GetTicker(g.Name, p, assetType)
+}
+
+// UpdateTickers updates the tickers of all enabled currency pairs from a
+// single tickers request
+func (g *Gateio) UpdateTickers(assetType string) error {
+	result, err := g.GetTickers()
+	if err != nil {
+		return err
 	}
+	g.processTickers(result, assetType)
+	return nil
+}
 
+// processTickers stores the tickers of the enabled currency pairs in a tickers
+// response, pairs missing from the response are skipped
+func (g *Gateio) processTickers(result map[string]TickerResponse, assetType string) {
 	for _, x := range g.GetEnabledCurrencies() {
 		currency := exchange.FormatExchangeCurrency(g.Name, x).String()
+		tick, ok := result[currency]
+		if !ok {
+			continue
+		}
 		var tp ticker.Price
 		tp.Pair = x
-		tp.High = result[currency].High
-		tp.Last = result[currency].Last
-		tp.Last = result[currency].Last
-		tp.Low = result[currency].Low
-		tp.Volume = result[currency].Volume
-		ticker.ProcessTicker(g.Name, x, tp, assetType)
+		tp.High = tick.High
+		tp.Last = tick.Last
+		tp.Low = tick.Low
+		tp.Bid = tick.HighestBid
+		tp.Ask = tick.LowestAsk
+		tp.Volume = tick.Volume
+		if tick.Last > 0 && tick.PercentChange > -100 {
+			// Gateio only supplies the percentage change, the open price is
+			// derived from it
+			tp.Open = tick.Last / (1 + tick.PercentChange/100)
+			tp.Change = tick.Last - tp.Open
+			tp.ChangePercent = tick.PercentChange
+		}
+		err := ticker.ProcessTicker(g.Name, x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
-
-	return ticker.GetTicker(g.Name, p, assetType)
 }
 
 // GetTickerPrice returns the ticker for a currency pair
@@ -104,6 +147,7 @@ func (g *Gateio) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbo
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data.Amount, Price: data.Price})
 	}
 
+	orderBook.Source = orderbook.SourceREST
 	orderbook.ProcessOrderbook(g.GetName(), p, orderBook, assetType)
 	return orderbook.GetOrderbook(g.Name, p, assetType)
 }
@@ -180,38 +224,45 @@ func (g *Gateio) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (g *Gateio) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (g *Gateio) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (g *Gateio) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (g *Gateio) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(g.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var orderTypeFormat SpotNewOrderRequestParamsType
 
-	if side == exchange.Buy {
+	if order.OrderSide == exchange.Buy {
 		orderTypeFormat = SpotNewOrderRequestParamsTypeBuy
 	} else {
 		orderTypeFormat = SpotNewOrderRequestParamsTypeSell
 	}
 
 	var spotNewOrderRequestParams = SpotNewOrderRequestParams{
-		Amount: amount,
-		Price:  price,
-		Symbol: p.Pair().String(),
+		Amount: order.Amount,
+		Price:  order.Price,
+		Symbol: order.Pair.Pair().String(),
 		Type:   orderTypeFormat,
 	}
 
 	response, err := g.SpotNewOrder(spotNewOrderRequestParams)
 
 	if response.OrderNumber > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.OrderNumber)
 	}
 
 	if err == nil {
 		submitOrderResponse.IsOrderPlaced = true
+		submitOrderResponse.FilledAmount = &response.FilledAmount
+		submitOrderResponse.RemainingAmount = &response.LeftAmount
 	}
 
 	return submitOrderResponse, err
@@ -261,30 +312,34 @@ func (g *Gateio) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 }
 
 // GetOrderInfo returns information on a current open order
-func (g *Gateio) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (g *Gateio) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (g *Gateio) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	addr, err := g.GetCryptoDepositAddress(cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
+func (g *Gateio) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return g.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		addr, err := g.GetCryptoDepositAddress(cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
 
-	// Waits for new generated address if not created yet, its variable per
-	// currency
-	if addr == gateioGenerateAddress {
-		time.Sleep(10 * time.Second)
-		addr, err = g.GetCryptoDepositAddress(cryptocurrency.String())
+		// Waits for new generated address if not created yet, its variable per
+		// currency
 		if addr == gateioGenerateAddress {
-			return "", errors.New("address not generated in time")
+			time.Sleep(10 * time.Second)
+			addr, err = g.GetCryptoDepositAddress(cryptocurrency.String())
+			if err != nil {
+				return "", err
+			}
+			if addr == gateioGenerateAddress {
+				return "", errors.New("address not generated in time")
+			}
 		}
-		return addr, nil
-	}
 
-	return addr, err
+		return addr, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
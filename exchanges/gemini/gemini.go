@@ -1,6 +1,7 @@
 package gemini
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -112,6 +113,8 @@ func (g *Gemini) SetDefaults() {
 	g.ConfigCurrencyPairFormat.Delimiter = ""
 	g.ConfigCurrencyPairFormat.Uppercase = true
 	g.AssetTypes = []string{ticker.Spot}
+	g.BaseCurrencies = []string{"USD"}
+	g.EnabledPairs = []string{"BTCUSD"}
 	g.SupportsAutoPairUpdating = true
 	g.SupportsRESTTickerBatching = false
 	g.Requester = request.New(g.Name,
@@ -120,6 +123,7 @@ func (g *Gemini) SetDefaults() {
 		common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
 	g.APIUrlDefault = geminiAPIURL
 	g.APIUrl = g.APIUrlDefault
+	g.SetSandboxEndpoints(exchange.SandboxEndpoints{APIURL: geminiSandboxAPIURL})
 	g.WebsocketInit()
 	g.Websocket.Functionality = exchange.WebsocketOrderbookSupported |
 		exchange.WebsocketTradeDataSupported
@@ -157,9 +161,6 @@ func (g *Gemini) Setup(exch config.ExchangeConfig) {
 		if err != nil {
 			log.Fatal(err)
 		}
-		if exch.UseSandbox {
-			g.APIUrl = geminiSandboxAPIURL
-		}
 		err = g.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
 			log.Fatal(err)
@@ -492,21 +493,23 @@ func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[st
 	}
 
 	headers := make(map[string]string)
-	request := make(map[string]interface{})
-	request["request"] = fmt.Sprintf("/v%s/%s", geminiAPIVersion, path)
-	request["nonce"] = g.Nonce.GetValue(g.Name, false)
+	req := make(map[string]interface{})
+	req["request"] = fmt.Sprintf("/v%s/%s", geminiAPIVersion, path)
+	req["nonce"] = g.Nonce.GetValue(g.Name, false)
 
 	for key, value := range params {
-		request[key] = value
+		req[key] = value
 	}
 
-	PayloadJSON, err := common.JSONEncode(request)
+	PayloadJSON, err := common.JSONEncode(req)
 	if err != nil {
 		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 	}
 
+	ctx, id := request.WithRequestID(context.Background())
 	if g.Verbose {
-		log.Debugf("Request JSON: %s", PayloadJSON)
+		log.Debugf("%s request %s. Request JSON: %s", g.Name, id,
+			request.ScrubBody(string(PayloadJSON)))
 	}
 
 	PayloadBase64 := common.Base64Encode(PayloadJSON)
@@ -516,7 +519,7 @@ func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[st
 	headers["X-GEMINI-PAYLOAD"] = PayloadBase64
 	headers["X-GEMINI-SIGNATURE"] = common.HexEncodeToString(hmac)
 
-	return g.SendPayload(method, g.APIUrl+"/v1/"+path, headers, strings.NewReader(""), result, true, g.Verbose)
+	return g.SendPayloadWithContext(ctx, method, g.APIUrl+"/v1/"+path, headers, strings.NewReader(""), result, true, g.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
@@ -31,19 +31,14 @@ func (g *Gemini) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-	if g.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(g.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := g.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
 	go g.WsHandleData()
 
-	return g.WsSubscribe(dialer)
+	return g.WsSubscribe(*dialer)
 }
 
 // WsSubscribe subscribes to the full websocket suite on gemini exchange
@@ -2,10 +2,10 @@ package gemini
 
 import (
 	"errors"
-	"fmt"
 	"net/url"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -31,15 +31,21 @@ func (g *Gemini) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", g.GetName(), len(g.EnabledPairs), g.EnabledPairs)
 	}
 
+	err := g.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", g.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (g *Gemini) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := g.GetSymbols()
 	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", g.GetName())
-	} else {
-		err = g.UpdateCurrencies(exchangeProducts, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", g.GetName())
-		}
+		return err
 	}
+
+	return g.UpdateCurrencies(exchangeProducts, false, forceUpdate)
 }
 
 // GetAccountInfo Retrieves balances for all enabled currencies for the
@@ -80,7 +86,10 @@ func (g *Gemini) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 	tickerPrice.Bid = tick.Bid
 	tickerPrice.Last = tick.Last
 	tickerPrice.Volume = tick.Volume.USD
-	ticker.ProcessTicker(g.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(g.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(g.Name, p, assetType)
 }
 
@@ -130,19 +139,24 @@ func (g *Gemini) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (g *Gemini) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (g *Gemini) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (g *Gemini) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (g *Gemini) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	response, err := g.NewOrder(p.Pair().String(), amount, price, side.ToString(), orderType.ToString())
+	err := order.CheckOptions(g.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := g.NewOrder(order.Pair.Pair().String(), order.Amount, order.Price, order.OrderSide.ToString(), order.OrderType.ToString())
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -187,18 +201,21 @@ func (g *Gemini) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 }
 
 // GetOrderInfo returns information on a current open order
-func (g *Gemini) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (g *Gemini) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (g *Gemini) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	addr, err := g.GetCryptoDepositAddress("", cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
-	return addr.Address, nil
+func (g *Gemini) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return g.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		addr, err := g.GetCryptoDepositAddress("", cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
+
+		return addr.Address, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
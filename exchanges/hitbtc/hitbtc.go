@@ -67,6 +67,8 @@ func (h *HitBTC) SetDefaults() {
 	h.ConfigCurrencyPairFormat.Delimiter = "-"
 	h.ConfigCurrencyPairFormat.Uppercase = true
 	h.AssetTypes = []string{ticker.Spot}
+	h.BaseCurrencies = []string{"USD"}
+	h.EnabledPairs = []string{"BTC-USD"}
 	h.SupportsAutoPairUpdating = true
 	h.SupportsRESTTickerBatching = true
 	h.Requester = request.New(h.Name,
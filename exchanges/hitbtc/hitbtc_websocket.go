@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -25,18 +24,11 @@ func (h *HitBTC) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-
-	if h.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(h.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := h.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	h.WebsocketConn, _, err = dialer.Dial(hitbtcWebsocketAddress, http.Header{})
 	if err != nil {
 		return err
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -31,33 +32,41 @@ func (h *HitBTC) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", h.GetName(), len(h.EnabledPairs), h.EnabledPairs)
 	}
 
-	exchangeProducts, err := h.GetSymbolsDetailed()
-	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", h.GetName())
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(h.EnabledPairs, "-") || !common.StringDataContains(h.AvailablePairs, "-") {
-			forceUpgrade = true
-		}
-		var currencies []string
-		for x := range exchangeProducts {
-			currencies = append(currencies, exchangeProducts[x].BaseCurrency+"-"+exchangeProducts[x].QuoteCurrency)
-		}
+	forceUpgrade := false
+	if !common.StringDataContains(h.EnabledPairs, "-") || !common.StringDataContains(h.AvailablePairs, "-") {
+		forceUpgrade = true
+	}
 
-		if forceUpgrade {
-			enabledPairs := []string{"BTC-USD"}
-			log.Warn("Available pairs for HitBTC reset due to config upgrade, please enable the ones you would like again.")
+	if forceUpgrade {
+		enabledPairs := []string{"BTC-USD"}
+		log.Warn("Available pairs for HitBTC reset due to config upgrade, please enable the ones you would like again.")
 
-			err = h.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to update enabled currencies.\n", h.GetName())
-			}
-		}
-		err = h.UpdateCurrencies(currencies, false, forceUpgrade)
+		err := h.UpdateCurrencies(enabledPairs, true, true)
 		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", h.GetName())
+			log.Errorf("%s failed to update enabled currencies. Err: %s", h.Name, err)
 		}
 	}
+
+	err := h.UpdateTradablePairs(forceUpgrade)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", h.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (h *HitBTC) UpdateTradablePairs(forceUpdate bool) error {
+	exchangeProducts, err := h.GetSymbolsDetailed()
+	if err != nil {
+		return err
+	}
+
+	var currencies []string
+	for x := range exchangeProducts {
+		currencies = append(currencies, exchangeProducts[x].BaseCurrency+"-"+exchangeProducts[x].QuoteCurrency)
+	}
+
+	return h.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -77,7 +86,10 @@ func (h *HitBTC) UpdateTicker(currencyPair pair.CurrencyPair, assetType string)
 		tp.Last = tick[curr].Last
 		tp.Low = tick[curr].Low
 		tp.Volume = tick[curr].Volume
-		ticker.ProcessTicker(h.GetName(), x, tp, assetType)
+		err = ticker.ProcessTicker(h.GetName(), x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(h.Name, currencyPair, assetType)
 }
@@ -156,19 +168,24 @@ func (h *HitBTC) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (h *HitBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (h *HitBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (h *HitBTC) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (h *HitBTC) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	response, err := h.PlaceOrder(p.Pair().String(), price, amount, common.StringToLower(orderType.ToString()), common.StringToLower(side.ToString()))
+	err := order.CheckOptions(h.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := h.PlaceOrder(order.Pair.Pair().String(), order.Price, order.Amount, common.StringToLower(order.OrderType.ToString()), common.StringToLower(order.OrderSide.ToString()))
 
 	if response.OrderNumber > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response.OrderNumber)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.OrderNumber)
 	}
 
 	if err == nil {
@@ -215,19 +232,21 @@ func (h *HitBTC) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 }
 
 // GetOrderInfo returns information on a current open order
-func (h *HitBTC) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (h *HitBTC) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (h *HitBTC) GetDepositAddress(currency pair.CurrencyItem, accountID string) (string, error) {
-	resp, err := h.GetDepositAddresses(currency.String())
-	if err != nil {
-		return "", err
-	}
+func (h *HitBTC) GetDepositAddress(currency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return h.GetCachedDepositAddress(currency, accountID, forceRefresh, func() (string, error) {
+		resp, err := h.GetDepositAddresses(currency.String())
+		if err != nil {
+			return "", err
+		}
 
-	return resp.Address, nil
+		return resp.Address, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
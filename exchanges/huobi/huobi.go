@@ -2,18 +2,12 @@ package huobi
 
 import (
 	"bytes"
-	"crypto/ecdsa"
-	"crypto/rand"
-	"crypto/x509"
 	"encoding/json"
-	"encoding/pem"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -84,6 +78,8 @@ func (h *HUOBI) SetDefaults() {
 	h.ConfigCurrencyPairFormat.Delimiter = "-"
 	h.ConfigCurrencyPairFormat.Uppercase = true
 	h.AssetTypes = []string{ticker.Spot}
+	h.BaseCurrencies = []string{"USD"}
+	h.EnabledPairs = []string{"BTC-USDT"}
 	h.SupportsAutoPairUpdating = true
 	h.SupportsRESTTickerBatching = false
 	h.Requester = request.New(h.Name,
@@ -108,6 +104,9 @@ func (h *HUOBI) Setup(exch config.ExchangeConfig) {
 		h.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		h.APIAuthPEMKeySupport = exch.APIAuthPEMKeySupport
 		h.APIAuthPEMKey = exch.APIAuthPEMKey
+		if h.APIAuthPEMKeySupport {
+			h.SetPEMKey(exch.APIAuthPEMKey)
+		}
 		h.SetHTTPClientTimeout(exch.HTTPTimeout)
 		h.SetHTTPClientUserAgent(exch.HTTPUserAgent)
 		h.RESTPollingDelay = exch.RESTPollingDelay
@@ -864,30 +863,10 @@ func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url
 	values.Set("Signature", signature)
 
 	if h.APIAuthPEMKeySupport {
-		pemKey := strings.NewReader(h.APIAuthPEMKey)
-		pemBytes, err := ioutil.ReadAll(pemKey)
-		if err != nil {
-			return fmt.Errorf("Huobi unable to ioutil.ReadAll PEM key: %s", err)
-		}
-
-		block, _ := pem.Decode(pemBytes)
-		if block == nil {
-			return fmt.Errorf("Huobi block is nil")
-		}
-
-		x509Encoded := block.Bytes
-		privKey, err := x509.ParseECPrivateKey(x509Encoded)
+		privSig, err := h.SignWithPEMKey([]byte(signature))
 		if err != nil {
-			return fmt.Errorf("Huobi unable to ParseECPrivKey: %s", err)
+			return fmt.Errorf("Huobi unable to sign with PEM key: %s", err)
 		}
-
-		r, s, err := ecdsa.Sign(rand.Reader, privKey, common.GetSHA256([]byte(signature)))
-		if err != nil {
-			return fmt.Errorf("Huobi unable to sign: %s", err)
-		}
-
-		privSig := r.Bytes()
-		privSig = append(privSig, s.Bytes()...)
 		values.Set("PrivateSignature", common.Base64Encode(privSig))
 	}
 
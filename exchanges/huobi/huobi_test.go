@@ -430,7 +430,15 @@ func TestSubmitOrder(t *testing.T) {
 		t.Errorf("Failed to get accounts. Err: %s", err)
 	}
 
-	response, err := h.SubmitOrder(p, exchange.Buy, exchange.Limit, 1, 10, strconv.FormatInt(accounts[0].ID, 10))
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Limit,
+		Price:     10,
+		Amount:    1,
+		ClientID:  strconv.FormatInt(accounts[0].ID, 10),
+	}
+	response, err := h.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -576,7 +584,7 @@ func TestWithdrawInternationalBank(t *testing.T) {
 }
 
 func TestGetDepositAddress(t *testing.T) {
-	_, err := h.GetDepositAddress(symbol.BTC, "")
+	_, err := h.GetDepositAddress(symbol.BTC, "", false)
 	if err == nil {
 		t.Error("Test Failed - GetDepositAddress() error cannot be nil")
 	}
@@ -8,7 +8,6 @@ import (
 	"io/ioutil"
 	"math/big"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -32,18 +31,11 @@ func (h *HUOBI) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-
-	if h.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(h.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := h.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	h.WebsocketConn, _, err = dialer.Dial(h.Websocket.GetWebsocketURL(), http.Header{})
 	if err != nil {
 		return err
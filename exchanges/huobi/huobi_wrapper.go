@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
@@ -32,52 +33,59 @@ func (h *HUOBI) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", h.GetName(), len(h.EnabledPairs), h.EnabledPairs)
 	}
 
-	exchangeProducts, err := h.GetSymbols()
-	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", h.GetName())
-	} else {
-		forceUpgrade := false
-		if common.StringDataContains(h.EnabledPairs, "CNY") || common.StringDataContains(h.AvailablePairs, "CNY") {
-			forceUpgrade = true
-		}
-
-		if common.StringDataContains(h.BaseCurrencies, "CNY") {
-			cfg := config.GetConfig()
-			exchCfg, errCNY := cfg.GetExchangeConfig(h.Name)
-			if err != nil {
-				log.Errorf("%s failed to get exchange config. %s\n", h.Name, errCNY)
-				return
-			}
-			exchCfg.BaseCurrencies = "USD"
-			h.BaseCurrencies = []string{"USD"}
+	forceUpgrade := false
+	if common.StringDataContains(h.EnabledPairs, "CNY") || common.StringDataContains(h.AvailablePairs, "CNY") {
+		forceUpgrade = true
+	}
 
-			errCNY = cfg.UpdateExchangeConfig(exchCfg)
-			if errCNY != nil {
-				log.Errorf("%s failed to update config. %s\n", h.Name, errCNY)
-				return
-			}
+	if common.StringDataContains(h.BaseCurrencies, "CNY") {
+		cfg := config.GetConfig()
+		exchCfg, err := cfg.GetExchangeConfig(h.Name)
+		if err != nil {
+			log.Errorf("%s failed to get exchange config. %s\n", h.Name, err)
+			return
 		}
+		exchCfg.BaseCurrencies = "USD"
+		h.BaseCurrencies = []string{"USD"}
 
-		var currencies []string
-		for x := range exchangeProducts {
-			newCurrency := exchangeProducts[x].BaseCurrency + "-" + exchangeProducts[x].QuoteCurrency
-			currencies = append(currencies, newCurrency)
+		err = cfg.UpdateExchangeConfig(exchCfg)
+		if err != nil {
+			log.Errorf("%s failed to update config. %s\n", h.Name, err)
+			return
 		}
+	}
 
-		if forceUpgrade {
-			enabledPairs := []string{"btc-usdt"}
-			log.Warn("Available and enabled pairs for Huobi reset due to config upgrade, please enable the ones you would like again")
+	if forceUpgrade {
+		enabledPairs := []string{"btc-usdt"}
+		log.Warn("Available and enabled pairs for Huobi reset due to config upgrade, please enable the ones you would like again")
 
-			err = h.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to update enabled currencies.\n", h.GetName())
-			}
-		}
-		err = h.UpdateCurrencies(currencies, false, forceUpgrade)
+		err := h.UpdateCurrencies(enabledPairs, true, true)
 		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", h.GetName())
+			log.Errorf("%s failed to update enabled currencies. Err: %s", h.Name, err)
 		}
 	}
+
+	err := h.UpdateTradablePairs(forceUpgrade)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", h.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (h *HUOBI) UpdateTradablePairs(forceUpdate bool) error {
+	exchangeProducts, err := h.GetSymbols()
+	if err != nil {
+		return err
+	}
+
+	var currencies []string
+	for x := range exchangeProducts {
+		newCurrency := exchangeProducts[x].BaseCurrency + "-" + exchangeProducts[x].QuoteCurrency
+		currencies = append(currencies, newCurrency)
+	}
+
+	return h.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -102,7 +110,10 @@ func (h *HUOBI) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pric
 		tickerPrice.Bid = tick.Bid[0]
 	}
 
-	ticker.ProcessTicker(h.GetName(), p, tickerPrice, assetType)
+	err = ticker.ProcessTicker(h.GetName(), p, tickerPrice, assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
 	return ticker.GetTicker(h.Name, p, assetType)
 }
 
@@ -237,38 +248,43 @@ func (h *HUOBI) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (h *HUOBI) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (h *HUOBI) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (h *HUOBI) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (h *HUOBI) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	accountID, err := strconv.ParseInt(clientID, 10, 64)
+	err := order.CheckOptions(h.Name, exchange.OrderOptions{ClientOrderID: true})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	accountID, err := strconv.ParseInt(order.ClientID, 10, 64)
 	if err != nil {
 		return submitOrderResponse, err
 	}
 
 	var formattedType SpotNewOrderRequestParamsType
 	var params = SpotNewOrderRequestParams{
-		Amount:    amount,
+		Amount:    order.Amount,
 		Source:    "api",
-		Symbol:    common.StringToLower(p.Pair().String()),
+		Symbol:    common.StringToLower(order.Pair.Pair().String()),
 		AccountID: int(accountID),
 	}
 
-	if side == exchange.Buy && orderType == exchange.Market {
+	if order.OrderSide == exchange.Buy && order.OrderType == exchange.Market {
 		formattedType = SpotNewOrderRequestTypeBuyMarket
-	} else if side == exchange.Sell && orderType == exchange.Market {
+	} else if order.OrderSide == exchange.Sell && order.OrderType == exchange.Market {
 		formattedType = SpotNewOrderRequestTypeSellMarket
-	} else if side == exchange.Buy && orderType == exchange.Limit {
+	} else if order.OrderSide == exchange.Buy && order.OrderType == exchange.Limit {
 		formattedType = SpotNewOrderRequestTypeBuyLimit
-		params.Price = price
-	} else if side == exchange.Sell && orderType == exchange.Limit {
+		params.Price = order.Price
+	} else if order.OrderSide == exchange.Sell && order.OrderType == exchange.Limit {
 		formattedType = SpotNewOrderRequestTypeSellLimit
-		params.Price = price
+		params.Price = order.Price
 	} else {
 		return submitOrderResponse, errors.New("Unsupported order type")
 	}
@@ -278,7 +294,7 @@ func (h *HUOBI) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderT
 	response, err := h.SpotNewOrder(params)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -331,13 +347,13 @@ func (h *HUOBI) CancelAllOrders(orderCancellation exchange.OrderCancellation) (e
 }
 
 // GetOrderInfo returns information on a current open order
-func (h *HUOBI) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (h *HUOBI) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (h *HUOBI) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (h *HUOBI) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrFunctionNotSupported
 }
 
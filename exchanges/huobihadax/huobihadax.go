@@ -78,6 +78,8 @@ func (h *HUOBIHADAX) SetDefaults() {
 	h.ConfigCurrencyPairFormat.Delimiter = "-"
 	h.ConfigCurrencyPairFormat.Uppercase = true
 	h.AssetTypes = []string{ticker.Spot}
+	h.BaseCurrencies = []string{"USD"}
+	h.EnabledPairs = []string{"NCC-BTC"}
 	h.SupportsAutoPairUpdating = true
 	h.SupportsRESTTickerBatching = false
 	h.Requester = request.New(h.Name,
@@ -102,6 +104,9 @@ func (h *HUOBIHADAX) Setup(exch config.ExchangeConfig) {
 		h.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		h.APIAuthPEMKeySupport = exch.APIAuthPEMKeySupport
 		h.APIAuthPEMKey = exch.APIAuthPEMKey
+		if h.APIAuthPEMKeySupport {
+			h.SetPEMKey(exch.APIAuthPEMKey)
+		}
 		h.SetHTTPClientTimeout(exch.HTTPTimeout)
 		h.SetHTTPClientUserAgent(exch.HTTPUserAgent)
 		h.RESTPollingDelay = exch.RESTPollingDelay
@@ -874,7 +879,16 @@ func (h *HUOBIHADAX) SendAuthenticatedHTTPRequest(method, endpoint string, value
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
 	hmac := common.GetHMAC(common.HashSHA256, []byte(payload), []byte(h.APISecret))
-	values.Set("Signature", common.Base64Encode(hmac))
+	signature := common.Base64Encode(hmac)
+	values.Set("Signature", signature)
+
+	if h.APIAuthPEMKeySupport {
+		privSig, err := h.SignWithPEMKey([]byte(signature))
+		if err != nil {
+			return fmt.Errorf("HuobiHadax unable to sign with PEM key: %s", err)
+		}
+		values.Set("PrivateSignature", common.Base64Encode(privSig))
+	}
 
 	url := fmt.Sprintf("%s%s", h.APIUrl, endpoint)
 	url = common.EncodeURLValues(url, values)
@@ -2,6 +2,7 @@ package itbit
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -56,6 +57,8 @@ func (i *ItBit) SetDefaults() {
 	i.ConfigCurrencyPairFormat.Delimiter = ""
 	i.ConfigCurrencyPairFormat.Uppercase = true
 	i.AssetTypes = []string{ticker.Spot}
+	i.BaseCurrencies = []string{"USD", "SGD"}
+	i.EnabledPairs = []string{"XBTUSD", "XBTSGD"}
 	i.SupportsAutoPairUpdating = false
 	i.SupportsRESTTickerBatching = false
 	i.Requester = request.New(i.Name,
@@ -360,24 +363,26 @@ func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params
 		return errors.New("client ID not set")
 	}
 
-	request := make(map[string]interface{})
+	req := make(map[string]interface{})
 	url := i.APIUrl + path
 
 	for key, value := range params {
-		request[key] = value
+		req[key] = value
 	}
 
 	PayloadJSON := []byte("")
 	var err error
 
+	ctx, id := request.WithRequestID(context.Background())
 	if params != nil {
-		PayloadJSON, err = common.JSONEncode(request)
+		PayloadJSON, err = common.JSONEncode(req)
 		if err != nil {
 			return err
 		}
 
 		if i.Verbose {
-			log.Debugf("Request JSON: %s\n", PayloadJSON)
+			log.Debugf("%s request %s. Request JSON: %s", i.Name, id,
+				request.ScrubBody(string(PayloadJSON)))
 		}
 	}
 
@@ -407,7 +412,7 @@ func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params
 		RequestID   string `json:"requestId"`
 	}{}
 
-	err = i.SendPayload(method, url, headers, bytes.NewBuffer(PayloadJSON), &intermediary, true, i.Verbose)
+	err = i.SendPayloadWithContext(ctx, method, url, headers, bytes.NewBuffer(PayloadJSON), &intermediary, true, i.Verbose)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package kraken
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -75,6 +76,8 @@ func (k *Kraken) SetDefaults() {
 	k.ConfigCurrencyPairFormat.Delimiter = "-"
 	k.ConfigCurrencyPairFormat.Uppercase = true
 	k.AssetTypes = []string{ticker.Spot}
+	k.BaseCurrencies = []string{"EUR", "USD", "CAD", "GBP", "JPY"}
+	k.EnabledPairs = []string{"XBT-USD"}
 	k.SupportsAutoPairUpdating = true
 	k.SupportsRESTTickerBatching = true
 	k.Requester = request.New(k.Name,
@@ -125,7 +128,7 @@ func (k *Kraken) Setup(exch config.ExchangeConfig) {
 }
 
 // GetServerTime returns current server time
-func (k *Kraken) GetServerTime() (TimeResponse, error) {
+func (k *Kraken) GetServerTime() (time.Time, error) {
 	path := fmt.Sprintf("%s/%s/public/%s", k.APIUrl, krakenAPIVersion, krakenServerTime)
 
 	var response struct {
@@ -134,10 +137,13 @@ func (k *Kraken) GetServerTime() (TimeResponse, error) {
 	}
 
 	if err := k.SendHTTPRequest(path, &response); err != nil {
-		return response.Result, err
+		return time.Time{}, err
 	}
 
-	return response.Result, GetError(response.Error)
+	if err := GetError(response.Error); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(response.Result.Unixtime, 0), nil
 }
 
 // GetAssets returns a full asset list
@@ -938,15 +944,17 @@ func (k *Kraken) SendAuthenticatedHTTPRequest(method string, params url.Values,
 	shasum := common.GetSHA256([]byte(params.Get("nonce") + encoded))
 	signature := common.Base64Encode(common.GetHMAC(common.HashSHA512, append([]byte(path), shasum...), secret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if k.Verbose {
-		log.Debugf("Sending POST request to %s, path: %s, params: %s", k.APIUrl, path, encoded)
+		log.Debugf("%s request %s. Sending POST request to %s, path: %s, params: %s",
+			k.Name, id, k.APIUrl, path, request.ScrubBody(encoded))
 	}
 
 	headers := make(map[string]string)
 	headers["API-Key"] = k.APIKey
 	headers["API-Sign"] = signature
 
-	return k.SendPayload("POST", k.APIUrl+path, headers, strings.NewReader(encoded), result, true, k.Verbose)
+	return k.SendPayloadWithContext(ctx, "POST", k.APIUrl+path, headers, strings.NewReader(encoded), result, true, k.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
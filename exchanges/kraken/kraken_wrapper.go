@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -29,45 +30,66 @@ func (k *Kraken) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", k.GetName(), len(k.EnabledPairs), k.EnabledPairs)
 	}
 
-	assetPairs, err := k.GetAssetPairs()
+	exchangeProducts, err := k.fetchTradablePairs()
 	if err != nil {
 		log.Errorf("%s Failed to get available symbols.\n", k.GetName())
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(k.EnabledPairs, "-") || !common.StringDataContains(k.AvailablePairs, "-") {
-			forceUpgrade = true
-		}
+		return
+	}
 
-		var exchangeProducts []string
-		for _, v := range assetPairs {
-			if common.StringContains(v.Altname, ".d") {
-				continue
-			}
-			if v.Base[0] == 'X' {
-				if len(v.Base) > 3 {
-					v.Base = v.Base[1:]
-				}
-			}
-			if v.Quote[0] == 'Z' || v.Quote[0] == 'X' {
-				v.Quote = v.Quote[1:]
-			}
-			exchangeProducts = append(exchangeProducts, v.Base+"-"+v.Quote)
+	forceUpgrade := false
+	if !common.StringDataContains(k.EnabledPairs, "-") || !common.StringDataContains(k.AvailablePairs, "-") {
+		forceUpgrade = true
+	}
+
+	if forceUpgrade {
+		enabledPairs := []string{"XBT-USD"}
+		log.Warn("Available pairs for Kraken reset due to config upgrade, please enable the ones you would like again")
+
+		err = k.UpdateCurrencies(enabledPairs, true, true)
+		if err != nil {
+			log.Errorf("%s failed to update enabled currencies. Err: %s", k.Name, err)
 		}
+	}
 
-		if forceUpgrade {
-			enabledPairs := []string{"XBT-USD"}
-			log.Warn("Available pairs for Kraken reset due to config upgrade, please enable the ones you would like again")
+	err = k.UpdateCurrencies(exchangeProducts, false, forceUpgrade)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", k.Name, err)
+	}
+}
 
-			err = k.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to get config.\n", k.GetName())
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (k *Kraken) UpdateTradablePairs(forceUpdate bool) error {
+	exchangeProducts, err := k.fetchTradablePairs()
+	if err != nil {
+		return err
+	}
+	return k.UpdateCurrencies(exchangeProducts, false, forceUpdate)
+}
+
+// fetchTradablePairs returns the exchange's tradable currency pairs
+func (k *Kraken) fetchTradablePairs() ([]string, error) {
+	assetPairs, err := k.GetAssetPairs()
+	if err != nil {
+		return nil, err
+	}
+
+	var exchangeProducts []string
+	for _, v := range assetPairs {
+		if common.StringContains(v.Altname, ".d") {
+			continue
+		}
+		if v.Base[0] == 'X' {
+			if len(v.Base) > 3 {
+				v.Base = v.Base[1:]
 			}
 		}
-		err = k.UpdateCurrencies(exchangeProducts, false, forceUpgrade)
-		if err != nil {
-			log.Errorf("%s Failed to get config.\n", k.GetName())
+		if v.Quote[0] == 'Z' || v.Quote[0] == 'X' {
+			v.Quote = v.Quote[1:]
 		}
+		exchangeProducts = append(exchangeProducts, v.Base+"-"+v.Quote)
 	}
+	return exchangeProducts, nil
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -94,7 +116,10 @@ func (k *Kraken) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 				tp.High = z.High
 				tp.Low = z.Low
 				tp.Volume = z.Volume
-				ticker.ProcessTicker(k.GetName(), x, tp, assetType)
+				err = ticker.ProcessTicker(k.GetName(), x, tp, assetType)
+				if err != nil {
+					log.Error(err)
+				}
 			}
 		}
 	}
@@ -173,18 +198,23 @@ func (k *Kraken) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (k *Kraken) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (k *Kraken) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (k *Kraken) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (k *Kraken) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(k.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var args = AddOrderOptions{}
 
-	response, err := k.AddOrder(p.Pair().String(), side.ToString(), orderType.ToString(), amount, price, 0, 0, args)
+	response, err := k.AddOrder(order.Pair.Pair().String(), order.OrderSide.ToString(), order.OrderType.ToString(), order.Amount, order.Price, 0, 0, args)
 
 	if len(response.TransactionIds) > 0 {
 		submitOrderResponse.OrderID = strings.Join(response.TransactionIds, ", ")
@@ -234,28 +264,35 @@ func (k *Kraken) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 }
 
 // GetOrderInfo returns information on a current open order
-func (k *Kraken) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (k *Kraken) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (k *Kraken) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	methods, err := k.GetDepositMethods(cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
+func (k *Kraken) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return k.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		methods, err := k.GetDepositMethods(cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
 
-	var method string
-	for _, m := range methods {
-		method = m.Method
-	}
+		var method string
+		for _, m := range methods {
+			method = m.Method
+		}
 
-	if method == "" {
-		return "", errors.New("method not found")
-	}
+		if method == "" {
+			return "", errors.New("method not found")
+		}
 
-	return k.GetCryptoDepositAddress(method, cryptocurrency.String())
+		addr, err := k.GetCryptoDepositAddress(method, cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
+
+		return addr, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal
@@ -1,6 +1,7 @@
 package lakebtc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -56,6 +57,9 @@ func (l *LakeBTC) SetDefaults() {
 	l.ConfigCurrencyPairFormat.Delimiter = ""
 	l.ConfigCurrencyPairFormat.Uppercase = true
 	l.AssetTypes = []string{ticker.Spot}
+	l.BaseCurrencies = []string{"USD", "EUR", "HKD", "AUD", "GBP", "NZD", "JPY",
+		"SGD", "NGN", "CHF", "CAD"}
+	l.EnabledPairs = []string{"BTCUSD", "BTCAUD"}
 	l.SupportsAutoPairUpdating = true
 	l.SupportsRESTTickerBatching = true
 	l.Requester = request.New(l.Name,
@@ -353,8 +357,10 @@ func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result int
 	req := fmt.Sprintf("tonce=%s&accesskey=%s&requestmethod=post&id=1&method=%s&params=%s", l.Nonce.String(), l.APIKey, method, params)
 	hmac := common.GetHMAC(common.HashSHA1, []byte(req), []byte(l.APISecret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if l.Verbose {
-		log.Debugf("Sending POST request to %s calling method %s with params %s\n", l.APIUrl, method, req)
+		log.Debugf("%s request %s. Sending POST request to %s calling method %s with params %s",
+			l.Name, id, l.APIUrl, method, request.ScrubBody(req))
 	}
 
 	postData := make(map[string]interface{})
@@ -372,7 +378,7 @@ func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result int
 	headers["Authorization"] = "Basic " + common.Base64Encode([]byte(l.APIKey+":"+common.HexEncodeToString(hmac)))
 	headers["Content-Type"] = "application/json-rpc"
 
-	return l.SendPayload("POST", l.APIUrl, headers, strings.NewReader(string(data)), result, true, l.Verbose)
+	return l.SendPayloadWithContext(ctx, "POST", l.APIUrl, headers, strings.NewReader(string(data)), result, true, l.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
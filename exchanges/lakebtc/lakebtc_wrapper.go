@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -32,15 +33,21 @@ func (l *LakeBTC) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", l.GetName(), len(l.EnabledPairs), l.EnabledPairs)
 	}
 
+	err := l.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", l.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (l *LakeBTC) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeProducts, err := l.GetTradablePairs()
 	if err != nil {
-		log.Errorf("%s Failed to get available products.\n", l.GetName())
-	} else {
-		err = l.UpdateCurrencies(exchangeProducts, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", l.GetName())
-		}
+		return err
 	}
+
+	return l.UpdateCurrencies(exchangeProducts, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -60,7 +67,10 @@ func (l *LakeBTC) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pr
 		tickerPrice.High = tick[currency].High
 		tickerPrice.Low = tick[currency].Low
 		tickerPrice.Last = tick[currency].Last
-		ticker.ProcessTicker(l.GetName(), x, tickerPrice, assetType)
+		err = ticker.ProcessTicker(l.GetName(), x, tickerPrice, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(l.Name, p, assetType)
 }
@@ -141,20 +151,25 @@ func (l *LakeBTC) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (l *LakeBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (l *LakeBTC) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (l *LakeBTC) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (l *LakeBTC) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	isBuyOrder := side == exchange.Buy
-	response, err := l.Trade(isBuyOrder, amount, price, common.StringToLower(p.Pair().String()))
+	err := order.CheckOptions(l.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	isBuyOrder := order.OrderSide == exchange.Buy
+	response, err := l.Trade(isBuyOrder, order.Amount, order.Price, common.StringToLower(order.Pair.Pair().String()))
 
 	if response.ID > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response.ID)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.ID)
 	}
 
 	if err == nil {
@@ -202,24 +217,26 @@ func (l *LakeBTC) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (l *LakeBTC) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (l *LakeBTC) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (l *LakeBTC) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	if !strings.EqualFold(cryptocurrency.String(), symbol.BTC) {
-		return "", fmt.Errorf("unsupported currency %s deposit address can only be BTC, manual deposit is required for other currencies",
-			cryptocurrency.String())
-	}
+func (l *LakeBTC) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return l.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		if !strings.EqualFold(cryptocurrency.String(), symbol.BTC) {
+			return "", fmt.Errorf("unsupported currency %s deposit address can only be BTC, manual deposit is required for other currencies",
+				cryptocurrency.String())
+		}
 
-	info, err := l.GetAccountInformation()
-	if err != nil {
-		return "", err
-	}
+		info, err := l.GetAccountInformation()
+		if err != nil {
+			return "", err
+		}
 
-	return info.Profile.BTCDepositAddress, nil
+		return info.Profile.BTCDepositAddress, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
@@ -1,6 +1,7 @@
 package liqui
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -60,6 +61,8 @@ func (l *Liqui) SetDefaults() {
 	l.ConfigCurrencyPairFormat.Delimiter = "_"
 	l.ConfigCurrencyPairFormat.Uppercase = true
 	l.AssetTypes = []string{ticker.Spot}
+	l.BaseCurrencies = []string{"USD"}
+	l.EnabledPairs = []string{"ETH_BTC", "LTC_BTC", "DASH_BTC"}
 	l.SupportsAutoPairUpdating = true
 	l.SupportsRESTTickerBatching = true
 	l.Requester = request.New(l.Name,
@@ -299,9 +302,10 @@ func (l *Liqui) SendAuthenticatedHTTPRequest(method string, values url.Values, r
 	encoded := values.Encode()
 	hmac := common.GetHMAC(common.HashSHA512, []byte(encoded), []byte(l.APISecret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if l.Verbose {
-		log.Debugf("Sending POST request to %s calling method %s with params %s\n",
-			l.APIUrlSecondary, method, encoded)
+		log.Debugf("%s request %s. Sending POST request to %s calling method %s with params %s",
+			l.Name, id, l.APIUrlSecondary, method, request.ScrubBody(encoded))
 	}
 
 	headers := make(map[string]string)
@@ -309,7 +313,7 @@ func (l *Liqui) SendAuthenticatedHTTPRequest(method string, values url.Values, r
 	headers["Sign"] = common.HexEncodeToString(hmac)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	return l.SendPayload("POST",
+	return l.SendPayloadWithContext(ctx, "POST",
 		l.APIUrlSecondary, headers,
 		strings.NewReader(encoded),
 		result,
@@ -95,7 +95,7 @@ func TestAuthRequests(t *testing.T) {
 			t.Error("Test Failed - liqui GetActiveOrders() error", err)
 		}
 
-		_, err = l.GetOrderInfo(1337)
+		_, err = l.GetOrderInfo("1337")
 		if err == nil {
 			t.Error("Test Failed - liqui GetOrderInfo() error", err)
 		}
@@ -256,7 +256,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.EUR,
 	}
-	response, err := l.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 10, "hi")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     10,
+		Amount:    1,
+	}
+	response, err := l.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -29,19 +30,25 @@ func (l *Liqui) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", l.GetName(), len(l.EnabledPairs), l.EnabledPairs)
 	}
 
-	var err error
-	l.Info, err = l.GetInfo()
+	err := l.UpdateTradablePairs(false)
 	if err != nil {
-		log.Errorf("%s Unable to fetch info.\n", l.GetName())
-	} else {
-		exchangeProducts := l.GetAvailablePairs(true)
-		err = l.UpdateCurrencies(exchangeProducts, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to get config.\n", l.GetName())
-		}
+		log.Errorf("%s failed to update tradable pairs. Err: %s", l.Name, err)
 	}
 }
 
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (l *Liqui) UpdateTradablePairs(forceUpdate bool) error {
+	info, err := l.GetInfo()
+	if err != nil {
+		return err
+	}
+	l.Info = info
+
+	exchangeProducts := l.GetAvailablePairs(true)
+	return l.UpdateCurrencies(exchangeProducts, false, forceUpdate)
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (l *Liqui) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
@@ -67,7 +74,10 @@ func (l *Liqui) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pric
 		tp.Last = result[currency].Last
 		tp.Low = result[currency].Low
 		tp.Volume = result[currency].Vol
-		ticker.ProcessTicker(l.Name, x, tp, assetType)
+		err = ticker.ProcessTicker(l.Name, x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 
 	return ticker.GetTicker(l.Name, p, assetType)
@@ -147,19 +157,24 @@ func (l *Liqui) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (l *Liqui) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (l *Liqui) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (l *Liqui) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (l *Liqui) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	response, err := l.Trade(p.Pair().String(), orderType.ToString(), amount, price)
+	err := order.CheckOptions(l.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := l.Trade(order.Pair.Pair().String(), order.OrderType.ToString(), order.Amount, order.Price)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatFloatOrderID(response)
 	}
 
 	if err == nil {
@@ -213,13 +228,13 @@ func (l *Liqui) CancelAllOrders(orderCancellation exchange.OrderCancellation) (e
 }
 
 // GetOrderInfo returns information on a current open order
-func (l *Liqui) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (l *Liqui) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (l *Liqui) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (l *Liqui) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrFunctionNotSupported
 }
 
@@ -1,6 +1,7 @@
 package localbitcoins
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	"github.com/thrasher-/gocryptotrader/config"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	log "github.com/thrasher-/gocryptotrader/logger"
 )
 
@@ -123,6 +125,11 @@ func (l *LocalBitcoins) SetDefaults() {
 	l.RequestCurrencyPairFormat.Uppercase = true
 	l.ConfigCurrencyPairFormat.Delimiter = ""
 	l.ConfigCurrencyPairFormat.Uppercase = true
+	l.AssetTypes = []string{ticker.Spot}
+	l.BaseCurrencies = []string{"ARS", "AUD", "BRL", "CAD", "CHF", "CZK", "DKK",
+		"EUR", "GBP", "HKD", "ILS", "INR", "MXN", "NOK", "NZD", "PLN", "RUB",
+		"SEK", "SGD", "THB", "USD", "ZAR"}
+	l.EnabledPairs = []string{"BTCAUD", "BTCUSD"}
 	l.SupportsAutoPairUpdating = true
 	l.SupportsRESTTickerBatching = true
 	l.Requester = request.New(l.Name,
@@ -734,15 +741,17 @@ func (l *LocalBitcoins) SendAuthenticatedHTTPRequest(method, path string, params
 	headers["Apiauth-Signature"] = common.StringToUpper(common.HexEncodeToString(hmac))
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
+	ctx, id := request.WithRequestID(context.Background())
 	if l.Verbose {
-		log.Debugf("Sending POST request to `%s`, path: `%s`, params: `%s`.", l.APIUrl, path, encoded)
+		log.Debugf("%s request %s. Sending %s request to `%s`, path: `%s`, params: `%s`.",
+			l.Name, id, method, l.APIUrl, path, request.ScrubBody(encoded))
 	}
 
 	if method == "GET" && len(encoded) > 0 {
 		path += "?" + encoded
 	}
 
-	return l.SendPayload(method, l.APIUrl+path, headers, strings.NewReader(encoded), result, true, l.Verbose)
+	return l.SendPayloadWithContext(ctx, method, l.APIUrl+path, headers, strings.NewReader(encoded), result, true, l.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
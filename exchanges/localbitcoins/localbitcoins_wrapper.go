@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 
@@ -34,10 +35,18 @@ func (l *LocalBitcoins) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", l.GetName(), len(l.EnabledPairs), l.EnabledPairs)
 	}
 
+	err := l.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", l.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (l *LocalBitcoins) UpdateTradablePairs(forceUpdate bool) error {
 	currencies, err := l.GetTradableCurrencies()
 	if err != nil {
-		log.Errorf("%s failed to obtain available tradable currencies. Err: %s", l.Name, err)
-		return
+		return err
 	}
 
 	var pairs []string
@@ -45,11 +54,7 @@ func (l *LocalBitcoins) Run() {
 		pairs = append(pairs, "BTC"+currencies[x])
 	}
 
-	err = l.UpdateCurrencies(pairs, false, false)
-	if err != nil {
-		log.Errorf("%s failed to update available currencies. Err %s", l.Name, err)
-	}
-
+	return l.UpdateCurrencies(pairs, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -66,7 +71,10 @@ func (l *LocalBitcoins) UpdateTicker(p pair.CurrencyPair, assetType string) (tic
 		tp.Pair = x
 		tp.Last = tick[currency].Avg24h
 		tp.Volume = tick[currency].VolumeBTC
-		ticker.ProcessTicker(l.GetName(), x, tp, assetType)
+		err = ticker.ProcessTicker(l.GetName(), x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 
 	return ticker.GetTicker(l.GetName(), p, assetType)
@@ -139,15 +147,20 @@ func (l *LocalBitcoins) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (l *LocalBitcoins) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (l *LocalBitcoins) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (l *LocalBitcoins) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (l *LocalBitcoins) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(l.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	// These are placeholder details
 	// TODO store a user's localbitcoin details to use here
 	var params = AdCreate{
@@ -157,21 +170,21 @@ func (l *LocalBitcoins) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide
 		City:                       "City",
 		Location:                   "Location",
 		CountryCode:                "US",
-		Currency:                   p.SecondCurrency.String(),
+		Currency:                   order.Pair.SecondCurrency.String(),
 		AccountInfo:                "-",
 		BankName:                   "Bank",
-		MSG:                        side.ToString(),
+		MSG:                        order.OrderSide.ToString(),
 		SMSVerficationRequired:     true,
 		TrackMaxAmount:             true,
 		RequireTrustedByAdvertiser: true,
 		RequireIdentification:      true,
 		OnlineProvider:             "",
 		TradeType:                  "",
-		MinAmount:                  int(math.Round(amount)),
+		MinAmount:                  int(math.Round(order.Amount)),
 	}
 
 	// Does not return any orderID, so create the add, then get the order
-	err := l.CreateAd(params)
+	err = l.CreateAd(params)
 	if err != nil {
 		return submitOrderResponse, err
 	}
@@ -245,19 +258,26 @@ func (l *LocalBitcoins) CancelAllOrders(orderCancellation exchange.OrderCancella
 }
 
 // GetOrderInfo returns information on a current open order
-func (l *LocalBitcoins) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (l *LocalBitcoins) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (l *LocalBitcoins) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	if !strings.EqualFold(symbol.BTC, cryptocurrency.String()) {
-		return "", fmt.Errorf("Localbitcoins do not have support for currency %s just bitcoin",
-			cryptocurrency.String())
-	}
+func (l *LocalBitcoins) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return l.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		if !strings.EqualFold(symbol.BTC, cryptocurrency.String()) {
+			return "", fmt.Errorf("Localbitcoins do not have support for currency %s just bitcoin",
+				cryptocurrency.String())
+		}
 
-	return l.GetWalletAddress()
+		addr, err := l.GetWalletAddress()
+		if err != nil {
+			return "", err
+		}
+
+		return addr, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
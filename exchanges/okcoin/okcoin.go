@@ -1,6 +1,8 @@
 package okcoin
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -21,12 +23,13 @@ import (
 
 const (
 	okcoinAPIURL                = "https://www.okcoin.com/api/v1/"
-	okcoinAPIURLChina           = "https://www.okcoin.com/api/v1/"
+	okcoinAPIURLChina           = "https://www.okcoin.cn/api/v1/"
 	okcoinAPIURLBase            = "https://www.okcoin.com/api/"
 	okcoinAPIVersion            = "1"
 	okcoinWebsocketURL          = "wss://real.okcoin.com:10440/websocket/okcoinapi"
 	okcoinWebsocketURLChina     = "wss://real.okcoin.cn:10440/websocket/okcoinapi"
 	okcoinInstruments           = "instruments"
+	okcoinServerTime            = "general/v3/time"
 	okcoinTicker                = "ticker.do"
 	okcoinDepth                 = "depth.do"
 	okcoinTrades                = "trades.do"
@@ -74,23 +77,126 @@ const (
 
 	okcoinAuthRate   = 0
 	okcoinUnauthRate = 0
+
+	okcoinRecordTypeDeposit = 0
+	okcoinRecordsPageLength = 50
+	// okcoinOrderHistoryPageLength is the largest page of order history
+	okcoinOrderHistoryPageLength = 200
+	// okcoinOrderStatusUnfilled is the order history status of open orders
+	okcoinOrderStatusUnfilled = "0"
+	// okcoinCancelOrderBatchSize is the number of orders a cancel request can
+	// hold
+	okcoinCancelOrderBatchSize = 3
+	// okcoinKlineBatchSize is the number of candles requested per kline batch
+	okcoinKlineBatchSize = 500
+
+	// okcoinPairFormatVersion is increased whenever the OKCoin International
+	// config pair format changes
+	okcoinPairFormatVersion = 1
+)
+
+// Order info statuses
+const (
+	okcoinOrderCancelled       = -1
+	okcoinOrderUnfilled        = 0
+	okcoinOrderPartiallyFilled = 1
+	okcoinOrderFilled          = 2
+	okcoinOrderCancelling      = 4
+
+	// okcoinErrorOrderNotFound is the error code of an order info request for
+	// an order which doesn't exist on the currency pair
+	okcoinErrorOrderNotFound = 10009
+)
+
+// OKCoin variants, named after the exchange names they are registered under
+const (
+	International = "OKCOIN International"
+	China         = "OKCOIN China"
+)
+
+// ErrUnknownVariant is returned when an OKCoin variant is neither
+// International nor China
+var ErrUnknownVariant = errors.New("unknown OKCoin variant")
+
+// variantDefaults holds the endpoints and currency pair delimiters of an
+// OKCoin variant. Pairs are uppercase in the config and lowercase in requests
+// for both variants
+type variantDefaults struct {
+	apiURL           string
+	websocketURL     string
+	configDelimiter  string
+	requestDelimiter string
+	// international variants support futures and updating their pairs
+	international bool
+}
+
+var variants = map[string]variantDefaults{
+	International: {
+		apiURL:           okcoinAPIURL,
+		websocketURL:     okcoinWebsocketURL,
+		configDelimiter:  "_",
+		requestDelimiter: "_",
+		international:    true,
+	},
+	China: {
+		apiURL:       okcoinAPIURLChina,
+		websocketURL: okcoinWebsocketURLChina,
+	},
+}
+
+// Deposit record statuses
+const (
+	okcoinDepositFailed     = -1
+	okcoinDepositConfirming = 0
+	okcoinDepositSucceeded  = 1
 )
 
 // OKCoin is the overarching type across this package
 type OKCoin struct {
 	exchange.Base
+	// Variant is International or China and selects the endpoints and pair
+	// formats. It is set before SetDefaults and defaults to International
+	Variant         string
 	RESTErrors      map[string]string
 	WebsocketErrors map[string]string
 	FuturesValues   []string
 	WebsocketConn   *websocket.Conn
 }
 
-// setCurrencyPairFormats sets currency pair formatting for this package
-func (o *OKCoin) setCurrencyPairFormats() {
-	o.RequestCurrencyPairFormat.Delimiter = "_"
-	o.RequestCurrencyPairFormat.Uppercase = false
-	o.ConfigCurrencyPairFormat.Delimiter = ""
-	o.ConfigCurrencyPairFormat.Uppercase = true
+// SetVariant sets the variant, matched case insensitively, and applies its
+// name, endpoints and currency pair formats
+func (o *OKCoin) SetVariant(variant string) error {
+	for name, defaults := range variants {
+		if !strings.EqualFold(name, variant) {
+			continue
+		}
+		o.Variant = name
+		o.Name = name
+		o.APIUrlDefault = defaults.apiURL
+		o.APIUrl = o.APIUrlDefault
+		o.WebsocketURL = defaults.websocketURL
+		o.ConfigCurrencyPairFormat.Delimiter = defaults.configDelimiter
+		o.ConfigCurrencyPairFormat.Uppercase = true
+		o.RequestCurrencyPairFormat.Delimiter = defaults.requestDelimiter
+		o.RequestCurrencyPairFormat.Uppercase = false
+		o.SupportsAutoPairUpdating = defaults.international
+		o.AssetTypes = []string{ticker.Spot}
+		if defaults.international {
+			o.AssetTypes = append(o.AssetTypes, o.FuturesValues...)
+		}
+		o.Requester = request.New(o.Name,
+			request.NewRateLimit(time.Second, okcoinAuthRate),
+			request.NewRateLimit(time.Second, okcoinUnauthRate),
+			common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
+		return nil
+	}
+	return fmt.Errorf("%w %q, expected %s or %s", ErrUnknownVariant, variant,
+		International, China)
+}
+
+// isInternational returns whether the International variant is set
+func (o *OKCoin) isInternational() bool {
+	return variants[o.Variant].international
 }
 
 // SetDefaults sets current default values for this package
@@ -100,11 +206,19 @@ func (o *OKCoin) SetDefaults() {
 	o.Enabled = false
 	o.Verbose = false
 	o.RESTPollingDelay = 10
-	o.AssetTypes = []string{ticker.Spot}
 	o.APIWithdrawPermissions = exchange.AutoWithdrawCrypto |
 		exchange.WithdrawFiatViaWebsiteOnly
-	o.SupportsAutoPairUpdating = false
 	o.SupportsRESTTickerBatching = false
+	variant := o.Variant
+	if variant == "" {
+		variant = International
+	}
+	err := o.SetVariant(variant)
+	if err != nil {
+		log.Errorf("%s, defaulting to %s", err, International)
+		o.SetVariant(International)
+	}
+	o.SetCurrencyDetails(staticCurrencyDetails())
 	o.WebsocketInit()
 	o.Websocket.Functionality = exchange.WebsocketTickerSupported |
 		exchange.WebsocketOrderbookSupported |
@@ -116,36 +230,10 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 	if !exch.Enabled {
 		o.SetEnabled(false)
 	} else {
-		if exch.Name == "OKCOIN International" {
-			o.AssetTypes = append(o.AssetTypes, o.FuturesValues...)
-			o.APIUrlDefault = okcoinAPIURL
-			o.APIUrl = o.APIUrlDefault
-			o.Name = "OKCOIN International"
-			o.WebsocketURL = okcoinWebsocketURL
-			o.setCurrencyPairFormats()
-			o.Requester = request.New(o.Name,
-				request.NewRateLimit(time.Second, okcoinAuthRate),
-				request.NewRateLimit(time.Second, okcoinUnauthRate),
-				common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
-			o.ConfigCurrencyPairFormat.Delimiter = "_"
-			o.ConfigCurrencyPairFormat.Uppercase = true
-			o.RequestCurrencyPairFormat.Uppercase = false
-			o.RequestCurrencyPairFormat.Delimiter = "_"
-			o.SupportsAutoPairUpdating = true
-		} else {
-			o.APIUrlDefault = okcoinAPIURLChina
-			o.APIUrl = o.APIUrlDefault
-			o.Name = "OKCOIN China"
-			o.WebsocketURL = okcoinWebsocketURLChina
-			o.setCurrencyPairFormats()
-			o.Requester = request.New(o.Name,
-				request.NewRateLimit(time.Second, okcoinAuthRate),
-				request.NewRateLimit(time.Second, okcoinUnauthRate),
-				common.NewHTTPClientWithTimeout(exchange.DefaultHTTPTimeout))
-			o.ConfigCurrencyPairFormat.Delimiter = ""
-			o.ConfigCurrencyPairFormat.Uppercase = true
-			o.RequestCurrencyPairFormat.Uppercase = false
-			o.RequestCurrencyPairFormat.Delimiter = ""
+		// The variant is selected by the config name
+		err := o.SetVariant(exch.Name)
+		if err != nil {
+			log.Fatal(err)
 		}
 
 		o.Enabled = true
@@ -159,7 +247,7 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 		o.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
 		o.AvailablePairs = common.SplitStrings(exch.AvailablePairs, ",")
 		o.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
-		err := o.SetCurrencyPairFormat()
+		err = o.SetCurrencyPairFormat()
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -182,20 +270,21 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 		err = o.WebsocketSetup(o.WsConnect,
 			exch.Name,
 			exch.Websocket,
-			okcoinWebsocketURL,
-			o.WebsocketURL)
+			o.WebsocketURL,
+			exch.WebsocketURL)
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 }
 
-// GetSpotInstruments returns a list of tradable spot instruments and their properties
-func (o *OKCoin) GetSpotInstruments() ([]SpotInstrument, error) {
+// GetSpotInstruments returns a list of tradable spot instruments and their properties.
+// Responses are briefly cached
+func (o *OKCoin) GetSpotInstruments(ctx context.Context) ([]SpotInstrument, error) {
 	var resp []SpotInstrument
 
 	path := fmt.Sprintf("%sspot/v3/%s", okcoinAPIURLBase, okcoinInstruments)
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendCacheablePayloadWithContext(ctx, path, nil, &resp, 0, o.Verbose)
 
 	if err != nil {
 		return nil, err
@@ -204,18 +293,28 @@ func (o *OKCoin) GetSpotInstruments() ([]SpotInstrument, error) {
 	return resp, nil
 }
 
+// GetServerTime returns the exchange server time
+func (o *OKCoin) GetServerTime() (time.Time, error) {
+	var resp ServerTime
+	err := o.SendHTTPRequest(context.Background(), okcoinAPIURLBase+okcoinServerTime, &resp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, resp.ISO)
+}
+
 // GetTicker returns the current ticker
-func (o *OKCoin) GetTicker(symbol string) (Ticker, error) {
+func (o *OKCoin) GetTicker(ctx context.Context, symbol string) (Ticker, error) {
 	resp := TickerResponse{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	path := common.EncodeURLValues(o.APIUrl+okcoinTicker, vals)
 
-	return resp.Ticker, o.SendHTTPRequest(path, &resp)
+	return resp.Ticker, o.SendHTTPRequest(ctx, path, &resp)
 }
 
 // GetOrderBook returns the current order book by size
-func (o *OKCoin) GetOrderBook(symbol string, size int64, merge bool) (Orderbook, error) {
+func (o *OKCoin) GetOrderBook(ctx context.Context, symbol string, size int64, merge bool) (Orderbook, error) {
 	resp := Orderbook{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
@@ -227,11 +326,11 @@ func (o *OKCoin) GetOrderBook(symbol string, size int64, merge bool) (Orderbook,
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinDepth, vals)
-	return resp, o.SendHTTPRequest(path, &resp)
+	return resp, o.SendHTTPRequest(ctx, path, &resp)
 }
 
 // GetTrades returns historic trades since a timestamp
-func (o *OKCoin) GetTrades(symbol string, since int64) ([]Trades, error) {
+func (o *OKCoin) GetTrades(ctx context.Context, symbol string, since int64) ([]Trades, error) {
 	result := []Trades{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
@@ -240,15 +339,20 @@ func (o *OKCoin) GetTrades(symbol string, since int64) ([]Trades, error) {
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinTrades, vals)
-	return result, o.SendHTTPRequest(path, &result)
+	return result, o.SendHTTPRequest(ctx, path, &result)
 }
 
 // GetKline returns kline data
-func (o *OKCoin) GetKline(symbol, klineType string, size, since int64) ([]interface{}, error) {
-	resp := []interface{}{}
+func (o *OKCoin) GetKline(ctx context.Context, symbol string, interval TimeInterval, size, since int64) ([]exchange.Candle, error) {
+	err := CheckTimeInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp [][]interface{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
-	vals.Set("type", klineType)
+	vals.Set("type", string(interval))
 
 	if size != 0 {
 		vals.Set("size", strconv.FormatInt(size, 10))
@@ -259,22 +363,26 @@ func (o *OKCoin) GetKline(symbol, klineType string, size, since int64) ([]interf
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinKline, vals)
-	return resp, o.SendHTTPRequest(path, &resp)
+	err = o.SendHTTPRequest(ctx, path, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return exchange.ParseCandles(resp)
 }
 
 // GetFuturesTicker returns a current ticker for the futures market
-func (o *OKCoin) GetFuturesTicker(symbol, contractType string) (FuturesTicker, error) {
+func (o *OKCoin) GetFuturesTicker(ctx context.Context, symbol, contractType string) (FuturesTicker, error) {
 	resp := FuturesTickerResponse{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	vals.Set("contract_type", contractType)
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesTicker, vals)
 
-	return resp.Ticker, o.SendHTTPRequest(path, &resp)
+	return resp.Ticker, o.SendHTTPRequest(ctx, path, &resp)
 }
 
 // GetFuturesDepth returns current depth for the futures market
-func (o *OKCoin) GetFuturesDepth(symbol, contractType string, size int64, merge bool) (Orderbook, error) {
+func (o *OKCoin) GetFuturesDepth(ctx context.Context, symbol, contractType string, size int64, merge bool) (Orderbook, error) {
 	result := Orderbook{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
@@ -288,22 +396,22 @@ func (o *OKCoin) GetFuturesDepth(symbol, contractType string, size int64, merge
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesDepth, vals)
-	return result, o.SendHTTPRequest(path, &result)
+	return result, o.SendHTTPRequest(ctx, path, &result)
 }
 
 // GetFuturesTrades returns historic trades for the futures market
-func (o *OKCoin) GetFuturesTrades(symbol, contractType string) ([]FuturesTrades, error) {
+func (o *OKCoin) GetFuturesTrades(ctx context.Context, symbol, contractType string) ([]FuturesTrades, error) {
 	result := []FuturesTrades{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	vals.Set("contract_type", contractType)
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesTrades, vals)
-	return result, o.SendHTTPRequest(path, &result)
+	return result, o.SendHTTPRequest(ctx, path, &result)
 }
 
 // GetFuturesIndex returns an index for the futures market
-func (o *OKCoin) GetFuturesIndex(symbol string) (float64, error) {
+func (o *OKCoin) GetFuturesIndex(ctx context.Context, symbol string) (float64, error) {
 	type Response struct {
 		Index float64 `json:"future_index"`
 	}
@@ -313,22 +421,22 @@ func (o *OKCoin) GetFuturesIndex(symbol string) (float64, error) {
 	vals.Set("symbol", symbol)
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesIndex, vals)
-	return result.Index, o.SendHTTPRequest(path, &result)
+	return result.Index, o.SendHTTPRequest(ctx, path, &result)
 }
 
 // GetFuturesExchangeRate returns the exchange rate for the futures market
-func (o *OKCoin) GetFuturesExchangeRate() (float64, error) {
+func (o *OKCoin) GetFuturesExchangeRate(ctx context.Context) (float64, error) {
 	type Response struct {
 		Rate float64 `json:"rate"`
 	}
 
 	result := Response{}
-	return result.Rate, o.SendHTTPRequest(o.APIUrl+okcoinExchangeRate, &result)
+	return result.Rate, o.SendHTTPRequest(ctx, o.APIUrl+okcoinExchangeRate, &result)
 }
 
 // GetFuturesEstimatedPrice returns a current estimated futures price for a
 // currency
-func (o *OKCoin) GetFuturesEstimatedPrice(symbol string) (float64, error) {
+func (o *OKCoin) GetFuturesEstimatedPrice(ctx context.Context, symbol string) (float64, error) {
 	type Response struct {
 		Price float64 `json:"forecast_price"`
 	}
@@ -338,16 +446,21 @@ func (o *OKCoin) GetFuturesEstimatedPrice(symbol string) (float64, error) {
 	vals.Set("symbol", symbol)
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesEstimatedPrice, vals)
 
-	return result.Price, o.SendHTTPRequest(path, &result)
+	return result.Price, o.SendHTTPRequest(ctx, path, &result)
 }
 
 // GetFuturesKline returns kline data for a specific currency on the futures
 // market
-func (o *OKCoin) GetFuturesKline(symbol, klineType, contractType string, size, since int64) ([]interface{}, error) {
-	resp := []interface{}{}
+func (o *OKCoin) GetFuturesKline(ctx context.Context, symbol string, interval TimeInterval, contractType string, size, since int64) ([]exchange.Candle, error) {
+	err := CheckTimeInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp [][]interface{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
-	vals.Set("type", klineType)
+	vals.Set("type", string(interval))
 	vals.Set("contract_type", contractType)
 
 	if size != 0 {
@@ -358,22 +471,45 @@ func (o *OKCoin) GetFuturesKline(symbol, klineType, contractType string, size, s
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesKline, vals)
-	return resp, o.SendHTTPRequest(path, &resp)
+	err = o.SendHTTPRequest(ctx, path, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return exchange.ParseCandles(resp)
+}
+
+// CheckTimeInterval returns an error if the kline interval isn't supported
+func CheckTimeInterval(interval TimeInterval) error {
+	for _, supported := range timeIntervals {
+		if interval == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported kline interval %q", interval)
+}
+
+// IntervalToTimeInterval returns the kline interval of a candle duration
+func IntervalToTimeInterval(interval time.Duration) (TimeInterval, error) {
+	timeInterval, ok := timeIntervals[interval]
+	if !ok {
+		return "", fmt.Errorf("unsupported kline interval %s", interval)
+	}
+	return timeInterval, nil
 }
 
 // GetFuturesHoldAmount returns the hold amount for a futures trade
-func (o *OKCoin) GetFuturesHoldAmount(symbol, contractType string) ([]FuturesHoldAmount, error) {
+func (o *OKCoin) GetFuturesHoldAmount(ctx context.Context, symbol, contractType string) ([]FuturesHoldAmount, error) {
 	resp := []FuturesHoldAmount{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	vals.Set("contract_type", contractType)
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesHoldAmount, vals)
-	return resp, o.SendHTTPRequest(path, &resp)
+	return resp, o.SendHTTPRequest(ctx, path, &resp)
 }
 
 // GetFuturesExplosive returns the explosive for a futures contract
-func (o *OKCoin) GetFuturesExplosive(symbol, contractType string, status, currentPage, pageLength int64) ([]FuturesExplosive, error) {
+func (o *OKCoin) GetFuturesExplosive(ctx context.Context, symbol, contractType string, status, currentPage, pageLength int64) ([]FuturesExplosive, error) {
 	type Response struct {
 		Data []FuturesExplosive `json:"data"`
 	}
@@ -387,19 +523,19 @@ func (o *OKCoin) GetFuturesExplosive(symbol, contractType string, status, curren
 
 	path := common.EncodeURLValues(o.APIUrl+okcoinFuturesExplosive, vals)
 
-	return resp.Data, o.SendHTTPRequest(path, &resp)
+	return resp.Data, o.SendHTTPRequest(ctx, path, &resp)
 }
 
 // GetUserInfo returns user information associated with the calling APIkeys
-func (o *OKCoin) GetUserInfo() (UserInfo, error) {
+func (o *OKCoin) GetUserInfo(ctx context.Context) (UserInfo, error) {
 	result := UserInfo{}
 
 	return result,
-		o.SendAuthenticatedHTTPRequest(okcoinUserInfo, url.Values{}, &result)
+		o.SendAuthenticatedHTTPRequest(ctx, okcoinUserInfo, url.Values{}, &result)
 }
 
 // Trade initiates a new trade
-func (o *OKCoin) Trade(amount, price float64, symbol, orderType string) (int64, error) {
+func (o *OKCoin) Trade(ctx context.Context, amount, price float64, symbol, orderType string) (int64, error) {
 	type Response struct {
 		Result  bool  `json:"result"`
 		OrderID int64 `json:"order_id"`
@@ -412,7 +548,7 @@ func (o *OKCoin) Trade(amount, price float64, symbol, orderType string) (int64,
 
 	result := Response{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinTrade, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinTrade, v, &result)
 
 	if err != nil {
 		return 0, err
@@ -426,13 +562,13 @@ func (o *OKCoin) Trade(amount, price float64, symbol, orderType string) (int64,
 }
 
 // GetTradeHistory returns client trade history
-func (o *OKCoin) GetTradeHistory(symbol string, TradeID int64) ([]Trades, error) {
+func (o *OKCoin) GetTradeHistory(ctx context.Context, symbol string, TradeID int64) ([]Trades, error) {
 	result := []Trades{}
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("since", strconv.FormatInt(TradeID, 10))
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinTradeHistory, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinTradeHistory, v, &result)
 
 	if err != nil {
 		return nil, err
@@ -442,14 +578,14 @@ func (o *OKCoin) GetTradeHistory(symbol string, TradeID int64) ([]Trades, error)
 }
 
 // BatchTrade initiates a trade by batch order
-func (o *OKCoin) BatchTrade(orderData string, symbol, orderType string) (BatchTrade, error) {
+func (o *OKCoin) BatchTrade(ctx context.Context, orderData string, symbol, orderType string) (BatchTrade, error) {
 	v := url.Values{}
 	v.Set("orders_data", orderData)
 	v.Set("symbol", symbol)
 	v.Set("type", orderType)
 	result := BatchTrade{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinTradeBatch, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinTradeBatch, v, &result)
 
 	if err != nil {
 		return result, err
@@ -459,7 +595,7 @@ func (o *OKCoin) BatchTrade(orderData string, symbol, orderType string) (BatchTr
 }
 
 // CancelExistingOrder cancels a specific order or list of orders by orderID
-func (o *OKCoin) CancelExistingOrder(orderID []int64, symbol string) (CancelOrderResponse, error) {
+func (o *OKCoin) CancelExistingOrder(ctx context.Context, orderID []int64, symbol string) (CancelOrderResponse, error) {
 	v := url.Values{}
 	orders := []string{}
 	result := CancelOrderResponse{}
@@ -476,11 +612,11 @@ func (o *OKCoin) CancelExistingOrder(orderID []int64, symbol string) (CancelOrde
 	v.Set("order_id", orderStr)
 	v.Set("symbol", symbol)
 
-	return result, o.SendAuthenticatedHTTPRequest(okcoinOrderCancel, v, &result)
+	return result, o.SendAuthenticatedHTTPRequest(ctx, okcoinOrderCancel, v, &result)
 }
 
 // GetOrderInformation returns order information by orderID
-func (o *OKCoin) GetOrderInformation(orderID int64, symbol string) ([]OrderInfo, error) {
+func (o *OKCoin) GetOrderInformation(ctx context.Context, orderID int64, symbol string) ([]OrderInfo, error) {
 	type Response struct {
 		Result bool        `json:"result"`
 		Orders []OrderInfo `json:"orders"`
@@ -490,7 +626,7 @@ func (o *OKCoin) GetOrderInformation(orderID int64, symbol string) ([]OrderInfo,
 	v.Set("order_id", strconv.FormatInt(orderID, 10))
 	result := Response{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinOrderInfo, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinOrderInfo, v, &result)
 
 	if err != nil {
 		return nil, err
@@ -504,7 +640,7 @@ func (o *OKCoin) GetOrderInformation(orderID int64, symbol string) ([]OrderInfo,
 }
 
 // GetOrderInfoBatch returns order info on a batch of orders
-func (o *OKCoin) GetOrderInfoBatch(orderID []int64, symbol string) ([]OrderInfo, error) {
+func (o *OKCoin) GetOrderInfoBatch(ctx context.Context, orderID []int64, symbol string) ([]OrderInfo, error) {
 	type Response struct {
 		Result bool        `json:"result"`
 		Orders []OrderInfo `json:"orders"`
@@ -520,7 +656,7 @@ func (o *OKCoin) GetOrderInfoBatch(orderID []int64, symbol string) ([]OrderInfo,
 	v.Set("order_id", common.JoinStrings(orders, ","))
 	result := Response{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinOrderInfo, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinOrderInfo, v, &result)
 
 	if err != nil {
 		return nil, err
@@ -534,7 +670,7 @@ func (o *OKCoin) GetOrderInfoBatch(orderID []int64, symbol string) ([]OrderInfo,
 }
 
 // GetOrderHistory returns a history of orders
-func (o *OKCoin) GetOrderHistory(pageLength, currentPage int64, status, symbol string) (OrderHistory, error) {
+func (o *OKCoin) GetOrderHistory(ctx context.Context, pageLength, currentPage int64, status, symbol string) (OrderHistory, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("status", status)
@@ -542,7 +678,7 @@ func (o *OKCoin) GetOrderHistory(pageLength, currentPage int64, status, symbol s
 	v.Set("page_length", strconv.FormatInt(pageLength, 10))
 	result := OrderHistory{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinOrderHistory, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinOrderHistory, v, &result)
 
 	if err != nil {
 		return result, err
@@ -551,8 +687,26 @@ func (o *OKCoin) GetOrderHistory(pageLength, currentPage int64, status, symbol s
 	return result, nil
 }
 
+// GetAllOrderHistory returns every order of a symbol with the status, 0 for
+// unfilled orders and 1 for filled orders, fetching every page of the order
+// history
+func (o *OKCoin) GetAllOrderHistory(ctx context.Context, status, symbol string) ([]OrderInfo, error) {
+	var orders []OrderInfo
+	err := exchange.PaginatePages(0, func(page int) (bool, error) {
+		history, err := o.GetOrderHistory(ctx, okcoinOrderHistoryPageLength,
+			int64(page), status, symbol)
+		if err != nil {
+			return false, err
+		}
+		orders = append(orders, history.Orders...)
+		return len(history.Orders) == okcoinOrderHistoryPageLength &&
+			len(orders) < history.Total, nil
+	})
+	return orders, err
+}
+
 // Withdrawal withdraws a cryptocurrency to a supplied address
-func (o *OKCoin) Withdrawal(symbol string, fee float64, tradePWD, address string, amount float64) (int, error) {
+func (o *OKCoin) Withdrawal(ctx context.Context, symbol string, fee float64, tradePWD, address string, amount float64) (int, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 
@@ -565,7 +719,7 @@ func (o *OKCoin) Withdrawal(symbol string, fee float64, tradePWD, address string
 	v.Set("target", "address")
 	result := WithdrawalResponse{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinWithdraw, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinWithdraw, v, &result)
 	if err != nil {
 		return 0, err
 	}
@@ -578,13 +732,13 @@ func (o *OKCoin) Withdrawal(symbol string, fee float64, tradePWD, address string
 }
 
 // CancelWithdrawal cancels a withdrawal
-func (o *OKCoin) CancelWithdrawal(symbol string, withdrawalID int64) (int, error) {
+func (o *OKCoin) CancelWithdrawal(ctx context.Context, symbol string, withdrawalID int64) (int, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("withdrawal_id", strconv.FormatInt(withdrawalID, 10))
 	result := WithdrawalResponse{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinWithdrawCancel, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinWithdrawCancel, v, &result)
 
 	if err != nil {
 		return 0, err
@@ -598,7 +752,7 @@ func (o *OKCoin) CancelWithdrawal(symbol string, withdrawalID int64) (int, error
 }
 
 // GetWithdrawalInfo returns withdrawal information
-func (o *OKCoin) GetWithdrawalInfo(symbol string, withdrawalID int64) ([]WithdrawInfo, error) {
+func (o *OKCoin) GetWithdrawalInfo(ctx context.Context, symbol string, withdrawalID int64) ([]WithdrawInfo, error) {
 	type Response struct {
 		Result   bool
 		Withdraw []WithdrawInfo `json:"withdraw"`
@@ -608,7 +762,7 @@ func (o *OKCoin) GetWithdrawalInfo(symbol string, withdrawalID int64) ([]Withdra
 	v.Set("withdrawal_id", strconv.FormatInt(withdrawalID, 10))
 	result := Response{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinWithdrawInfo, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinWithdrawInfo, v, &result)
 
 	if err != nil {
 		return nil, err
@@ -622,7 +776,7 @@ func (o *OKCoin) GetWithdrawalInfo(symbol string, withdrawalID int64) ([]Withdra
 }
 
 // GetOrderFeeInfo returns order fee information
-func (o *OKCoin) GetOrderFeeInfo(symbol string, orderID int64) (OrderFeeInfo, error) {
+func (o *OKCoin) GetOrderFeeInfo(ctx context.Context, symbol string, orderID int64) (OrderFeeInfo, error) {
 	type Response struct {
 		Data   OrderFeeInfo `json:"data"`
 		Result bool         `json:"result"`
@@ -633,7 +787,7 @@ func (o *OKCoin) GetOrderFeeInfo(symbol string, orderID int64) (OrderFeeInfo, er
 	v.Set("order_id", strconv.FormatInt(orderID, 10))
 	result := Response{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinOrderFee, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinOrderFee, v, &result)
 
 	if err != nil {
 		return result.Data, err
@@ -647,7 +801,7 @@ func (o *OKCoin) GetOrderFeeInfo(symbol string, orderID int64) (OrderFeeInfo, er
 }
 
 // GetLendDepth returns the depth of lends
-func (o *OKCoin) GetLendDepth(symbol string) ([]LendDepth, error) {
+func (o *OKCoin) GetLendDepth(ctx context.Context, symbol string) ([]LendDepth, error) {
 	type Response struct {
 		LendDepth []LendDepth `json:"lend_depth"`
 	}
@@ -656,7 +810,7 @@ func (o *OKCoin) GetLendDepth(symbol string) ([]LendDepth, error) {
 	v.Set("symbol", symbol)
 	result := Response{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinLendDepth, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinLendDepth, v, &result)
 
 	if err != nil {
 		return nil, err
@@ -666,12 +820,12 @@ func (o *OKCoin) GetLendDepth(symbol string) ([]LendDepth, error) {
 }
 
 // GetBorrowInfo returns borrow information
-func (o *OKCoin) GetBorrowInfo(symbol string) (BorrowInfo, error) {
+func (o *OKCoin) GetBorrowInfo(ctx context.Context, symbol string) (BorrowInfo, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	result := BorrowInfo{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinBorrowsInfo, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinBorrowsInfo, v, &result)
 
 	if err != nil {
 		return result, nil
@@ -681,7 +835,7 @@ func (o *OKCoin) GetBorrowInfo(symbol string) (BorrowInfo, error) {
 }
 
 // Borrow initiates a borrow request
-func (o *OKCoin) Borrow(symbol, days string, amount, rate float64) (int, error) {
+func (o *OKCoin) Borrow(ctx context.Context, symbol, days string, amount, rate float64) (int, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("days", days)
@@ -689,7 +843,7 @@ func (o *OKCoin) Borrow(symbol, days string, amount, rate float64) (int, error)
 	v.Set("rate", strconv.FormatFloat(rate, 'f', -1, 64))
 	result := BorrowResponse{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinBorrowMoney, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinBorrowMoney, v, &result)
 
 	if err != nil {
 		return 0, err
@@ -703,13 +857,13 @@ func (o *OKCoin) Borrow(symbol, days string, amount, rate float64) (int, error)
 }
 
 // CancelBorrow cancels a borrow request
-func (o *OKCoin) CancelBorrow(symbol string, borrowID int64) (bool, error) {
+func (o *OKCoin) CancelBorrow(ctx context.Context, symbol string, borrowID int64) (bool, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("borrow_id", strconv.FormatInt(borrowID, 10))
 	result := BorrowResponse{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinBorrowCancel, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinBorrowCancel, v, &result)
 
 	if err != nil {
 		return false, err
@@ -723,7 +877,7 @@ func (o *OKCoin) CancelBorrow(symbol string, borrowID int64) (bool, error) {
 }
 
 // GetBorrowOrderInfo returns information about a borrow order
-func (o *OKCoin) GetBorrowOrderInfo(borrowID int64) (BorrowInfo, error) {
+func (o *OKCoin) GetBorrowOrderInfo(ctx context.Context, borrowID int64) (BorrowInfo, error) {
 	type Response struct {
 		Result      bool       `json:"result"`
 		BorrowOrder BorrowInfo `json:"borrow_order"`
@@ -732,7 +886,7 @@ func (o *OKCoin) GetBorrowOrderInfo(borrowID int64) (BorrowInfo, error) {
 	v := url.Values{}
 	v.Set("borrow_id", strconv.FormatInt(borrowID, 10))
 	result := Response{}
-	err := o.SendAuthenticatedHTTPRequest(okcoinBorrowOrderInfo, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinBorrowOrderInfo, v, &result)
 
 	if err != nil {
 		return result.BorrowOrder, err
@@ -746,12 +900,12 @@ func (o *OKCoin) GetBorrowOrderInfo(borrowID int64) (BorrowInfo, error) {
 }
 
 // GetRepaymentInfo returns information on a repayment
-func (o *OKCoin) GetRepaymentInfo(borrowID int64) (bool, error) {
+func (o *OKCoin) GetRepaymentInfo(ctx context.Context, borrowID int64) (bool, error) {
 	v := url.Values{}
 	v.Set("borrow_id", strconv.FormatInt(borrowID, 10))
 	result := BorrowResponse{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinRepayment, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinRepayment, v, &result)
 
 	if err != nil {
 		return false, err
@@ -765,7 +919,7 @@ func (o *OKCoin) GetRepaymentInfo(borrowID int64) (bool, error) {
 }
 
 // GetUnrepaymentsInfo returns information on an unrepayment
-func (o *OKCoin) GetUnrepaymentsInfo(symbol string, currentPage, pageLength int) ([]BorrowOrder, error) {
+func (o *OKCoin) GetUnrepaymentsInfo(ctx context.Context, symbol string, currentPage, pageLength int) ([]BorrowOrder, error) {
 	type Response struct {
 		Unrepayments []BorrowOrder `json:"unrepayments"`
 		Result       bool          `json:"result"`
@@ -775,7 +929,7 @@ func (o *OKCoin) GetUnrepaymentsInfo(symbol string, currentPage, pageLength int)
 	v.Set("current_page", strconv.Itoa(currentPage))
 	v.Set("page_length", strconv.Itoa(pageLength))
 	result := Response{}
-	err := o.SendAuthenticatedHTTPRequest(okcoinUnrepaymentsInfo, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinUnrepaymentsInfo, v, &result)
 
 	if err != nil {
 		return nil, err
@@ -788,31 +942,28 @@ func (o *OKCoin) GetUnrepaymentsInfo(symbol string, currentPage, pageLength int)
 	return result.Unrepayments, nil
 }
 
-// GetAccountRecords returns account records
-func (o *OKCoin) GetAccountRecords(symbol string, recType, currentPage, pageLength int) ([]AccountRecords, error) {
-	type Response struct {
-		Records []AccountRecords `json:"records"`
-		Symbol  string           `json:"symbol"`
-	}
+// GetAccountRecords returns a page of the deposit (recType 0) or withdrawal
+// (recType 1) records of a symbol, newest first
+func (o *OKCoin) GetAccountRecords(ctx context.Context, symbol string, recType, currentPage, pageLength int) (AccountRecords, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("type", strconv.Itoa(recType))
 	v.Set("current_page", strconv.Itoa(currentPage))
 	v.Set("page_length", strconv.Itoa(pageLength))
-	result := Response{}
+	result := AccountRecords{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinAccountRecords, v, &result)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinAccountRecords, v, &result)
 
 	if err != nil {
-		return nil, err
+		return result, err
 	}
 
-	return result.Records, nil
+	return result, nil
 }
 
 // GetFuturesUserInfo returns information on a users futures
-func (o *OKCoin) GetFuturesUserInfo() {
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesUserInfo, url.Values{}, nil)
+func (o *OKCoin) GetFuturesUserInfo(ctx context.Context) {
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesUserInfo, url.Values{}, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -820,11 +971,11 @@ func (o *OKCoin) GetFuturesUserInfo() {
 }
 
 // GetFuturesPosition returns position on a futures contract
-func (o *OKCoin) GetFuturesPosition(symbol, contractType string) {
+func (o *OKCoin) GetFuturesPosition(ctx context.Context, symbol, contractType string) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("contract_type", contractType)
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesPosition, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesPosition, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -832,7 +983,7 @@ func (o *OKCoin) GetFuturesPosition(symbol, contractType string) {
 }
 
 // FuturesTrade initiates a new futures trade
-func (o *OKCoin) FuturesTrade(amount, price float64, matchPrice, leverage int64, symbol, contractType, orderType string) {
+func (o *OKCoin) FuturesTrade(ctx context.Context, amount, price float64, matchPrice, leverage int64, symbol, contractType, orderType string) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("contract_type", contractType)
@@ -842,7 +993,7 @@ func (o *OKCoin) FuturesTrade(amount, price float64, matchPrice, leverage int64,
 	v.Set("match_price", strconv.FormatInt(matchPrice, 10))
 	v.Set("lever_rate", strconv.FormatInt(leverage, 10))
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesTrade, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesTrade, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -850,14 +1001,14 @@ func (o *OKCoin) FuturesTrade(amount, price float64, matchPrice, leverage int64,
 }
 
 // FuturesBatchTrade initiates a batch of futures contract trades
-func (o *OKCoin) FuturesBatchTrade(orderData, symbol, contractType string, leverage int64, orderType string) {
+func (o *OKCoin) FuturesBatchTrade(ctx context.Context, orderData, symbol, contractType string, leverage int64, orderType string) {
 	v := url.Values{} //to-do batch trade support for orders_data)
 	v.Set("symbol", symbol)
 	v.Set("contract_type", contractType)
 	v.Set("orders_data", orderData)
 	v.Set("lever_rate", strconv.FormatInt(leverage, 10))
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesTradeBatch, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesTradeBatch, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -865,13 +1016,13 @@ func (o *OKCoin) FuturesBatchTrade(orderData, symbol, contractType string, lever
 }
 
 // CancelFuturesOrder cancels a futures contract order
-func (o *OKCoin) CancelFuturesOrder(orderID int64, symbol, contractType string) {
+func (o *OKCoin) CancelFuturesOrder(ctx context.Context, orderID int64, symbol, contractType string) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("contract_type", contractType)
 	v.Set("order_id", strconv.FormatInt(orderID, 10))
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesCancel, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesCancel, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -879,7 +1030,7 @@ func (o *OKCoin) CancelFuturesOrder(orderID int64, symbol, contractType string)
 }
 
 // GetFuturesOrderInfo returns information on a specific futures contract order
-func (o *OKCoin) GetFuturesOrderInfo(orderID, status, currentPage, pageLength int64, symbol, contractType string) {
+func (o *OKCoin) GetFuturesOrderInfo(ctx context.Context, orderID, status, currentPage, pageLength int64, symbol, contractType string) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("contract_type", contractType)
@@ -888,7 +1039,7 @@ func (o *OKCoin) GetFuturesOrderInfo(orderID, status, currentPage, pageLength in
 	v.Set("current_page", strconv.FormatInt(currentPage, 10))
 	v.Set("page_length", strconv.FormatInt(pageLength, 10))
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesOrderInfo, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesOrderInfo, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -896,13 +1047,13 @@ func (o *OKCoin) GetFuturesOrderInfo(orderID, status, currentPage, pageLength in
 }
 
 // GetFutureOrdersInfo returns information on a range of futures orders
-func (o *OKCoin) GetFutureOrdersInfo(orderID int64, contractType, symbol string) {
+func (o *OKCoin) GetFutureOrdersInfo(ctx context.Context, orderID int64, contractType, symbol string) {
 	v := url.Values{}
 	v.Set("order_id", strconv.FormatInt(orderID, 10))
 	v.Set("contract_type", contractType)
 	v.Set("symbol", symbol)
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesOrdersInfo, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesOrdersInfo, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -910,10 +1061,10 @@ func (o *OKCoin) GetFutureOrdersInfo(orderID int64, contractType, symbol string)
 }
 
 // GetFuturesUserInfo4Fix returns futures user info fix rate
-func (o *OKCoin) GetFuturesUserInfo4Fix() {
+func (o *OKCoin) GetFuturesUserInfo4Fix(ctx context.Context) {
 	v := url.Values{}
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesUserInfo4Fix, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesUserInfo4Fix, v, nil)
 
 	if err != nil {
 		log.Error(err)
@@ -921,45 +1072,100 @@ func (o *OKCoin) GetFuturesUserInfo4Fix() {
 }
 
 // GetFuturesUserPosition4Fix returns futures user info on a fixed position
-func (o *OKCoin) GetFuturesUserPosition4Fix(symbol, contractType string) {
+func (o *OKCoin) GetFuturesUserPosition4Fix(ctx context.Context, symbol, contractType string) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("contract_type", contractType)
 	v.Set("type", strconv.FormatInt(1, 10))
 
-	err := o.SendAuthenticatedHTTPRequest(okcoinFuturesUserInfo4Fix, v, nil)
+	err := o.SendAuthenticatedHTTPRequest(ctx, okcoinFuturesUserInfo4Fix, v, nil)
 
 	if err != nil {
 		log.Error(err)
 	}
 }
 
-// SendHTTPRequest sends an unauthenticated HTTP request
-func (o *OKCoin) SendHTTPRequest(path string, result interface{}) error {
-	return o.SendPayload("GET", path, nil, nil, result, false, o.Verbose)
+// SendHTTPRequest sends an unauthenticated HTTP request which is aborted when
+// ctx is cancelled
+func (o *OKCoin) SendHTTPRequest(ctx context.Context, path string, result interface{}) error {
+	var intermediary json.RawMessage
+	err := o.SendPayloadWithContext(ctx, "GET", path, nil, nil, &intermediary, false, o.Verbose)
+	if err != nil {
+		return exchange.ClassifyRequestError(err)
+	}
+	return o.decodeResponse(intermediary, result)
 }
 
-// SendAuthenticatedHTTPRequest sends an authenticated HTTP request
-func (o *OKCoin) SendAuthenticatedHTTPRequest(method string, v url.Values, result interface{}) (err error) {
+// SendAuthenticatedHTTPRequest sends an authenticated HTTP request which is
+// aborted when ctx is cancelled
+func (o *OKCoin) SendAuthenticatedHTTPRequest(ctx context.Context, method string, v url.Values, result interface{}) (err error) {
 	if !o.AuthenticatedAPISupport {
-		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, o.Name)
+		return exchange.NewAuthenticationNotConfiguredError(o.Name)
 	}
 
 	v.Set("api_key", o.APIKey)
-	hasher := common.GetMD5([]byte(v.Encode() + "&secret_key=" + o.APISecret))
-	v.Set("sign", strings.ToUpper(common.HexEncodeToString(hasher)))
+	v.Set("sign", strings.ToUpper(exchange.SignMD5Concat(
+		[]byte(v.Encode()+"&secret_key="), []byte(o.APISecret), false)))
 
 	encoded := v.Encode()
 	path := o.APIUrl + method
 
+	ctx, id := request.WithRequestID(ctx)
 	if o.Verbose {
-		log.Debugf("Sending POST request to %s with params %s\n", path, encoded)
+		log.Debugf("%s request %s. Sending POST request to %s with params %s",
+			o.Name, id, path, request.ScrubBody(encoded))
 	}
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	return o.SendPayload("POST", path, headers, strings.NewReader(encoded), result, true, o.Verbose)
+	var intermediary json.RawMessage
+	err = o.SendPayloadWithContext(ctx, "POST", path, headers, strings.NewReader(encoded), &intermediary, true, o.Verbose)
+	if err != nil {
+		return exchange.ClassifyRequestError(err)
+	}
+	return o.decodeResponse(intermediary, result)
+}
+
+// decodeResponse returns an APIError if the response holds an unsuccessful
+// result with a numeric error code, otherwise the response is decoded into
+// result
+func (o *OKCoin) decodeResponse(resp json.RawMessage, result interface{}) error {
+	var envelope struct {
+		Result    *bool           `json:"result"`
+		ErrorCode json.RawMessage `json:"error_code"`
+	}
+
+	err := common.JSONDecode(resp, &envelope)
+	if err == nil && len(envelope.ErrorCode) > 0 &&
+		(envelope.Result == nil || !*envelope.Result) {
+		// String error codes hold order IDs for batch requests
+		code, err := strconv.ParseInt(string(envelope.ErrorCode), 10, 64)
+		if err == nil && code != 0 {
+			codeStr := strconv.FormatInt(code, 10)
+			msg, ok := o.RESTErrors[codeStr]
+			if !ok {
+				msg = codeStr
+			}
+			return &APIError{Code: code, Message: msg}
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+	return common.JSONDecode(resp, result)
+}
+
+// errorCodeClasses maps API error codes to their exchange error class
+var errorCodeClasses = map[int64]error{
+	10001: exchange.ErrRateLimited,
+	10002: exchange.ErrExchangeMaintenance,
+	10005: exchange.ErrCredentialsInvalid,
+	10006: exchange.ErrCredentialsInvalid,
+	10007: exchange.ErrCredentialsInvalid,
+	10017: exchange.ErrCredentialsInvalid,
+	20014: exchange.ErrExchangeMaintenance,
 }
 
 // SetErrorDefaults sets default error map
@@ -1049,7 +1255,7 @@ func (o *OKCoin) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	case exchange.InternationalBankWithdrawalFee:
 		fee = calculateInternationalBankWithdrawalFee(feeBuilder.CurrencyItem, feeBuilder.PurchasePrice, feeBuilder.Amount)
 	case exchange.CryptocurrencyWithdrawalFee:
-		fee = getWithdrawalFee(feeBuilder.FirstCurrency)
+		fee = o.getWithdrawalFee(feeBuilder.FirstCurrency)
 	}
 	if fee < 0 {
 		fee = 0
@@ -1079,6 +1285,26 @@ func calculateInternationalBankWithdrawalFee(currency string, purchasePrice, amo
 	return fee
 }
 
-func getWithdrawalFee(currency string) float64 {
-	return WithdrawalFees[currency]
+// getWithdrawalFee returns the withdrawal fee of a currency from its currency
+// details, which include any config overrides
+func (o *OKCoin) getWithdrawalFee(currency string) float64 {
+	details, err := o.GetCurrencyDetails()
+	if err != nil {
+		return 0
+	}
+	d, _ := exchange.GetCurrencyDetail(details, currency)
+	return d.WithdrawalFee
+}
+
+// staticCurrencyDetails returns the currency details built from the
+// predefined withdrawal fees, OKCoin doesn't expose them through its API
+func staticCurrencyDetails() []exchange.CurrencyDetails {
+	details := make([]exchange.CurrencyDetails, 0, len(WithdrawalFees))
+	for currency, fee := range WithdrawalFees {
+		details = append(details, exchange.CurrencyDetails{
+			Currency:      currency,
+			WithdrawalFee: fee,
+		})
+	}
+	return details
 }
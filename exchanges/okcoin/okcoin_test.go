@@ -1,13 +1,25 @@
 package okcoin
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/fixtures"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 var o OKCoin
@@ -39,6 +51,103 @@ func TestSetup(t *testing.T) {
 	o.Setup(okcoinConfig)
 }
 
+// roundTripFunc answers HTTP requests without a server
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestVariants(t *testing.T) {
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	for _, tc := range []struct {
+		variant, name, tickerURL, websocketURL string
+		configPair, requestPair                string
+		futures                                bool
+	}{
+		{International, International,
+			"https://www.okcoin.com/api/v1/ticker.do?symbol=btc_usd",
+			okcoinWebsocketURL, "BTC_USD", "btc_usd", true},
+		{"okcoin china", China,
+			"https://www.okcoin.cn/api/v1/ticker.do?symbol=btcusd",
+			okcoinWebsocketURLChina, "BTCUSD", "btcusd", false},
+	} {
+		x := OKCoin{Variant: tc.variant}
+		x.SetDefaults()
+		if x.Name != tc.name || x.Variant != tc.name || x.WebsocketURL != tc.websocketURL ||
+			x.isInternational() != tc.futures {
+			t.Errorf("Test Failed - OKCoin %s unexpected name %s variant %s websocket %s",
+				tc.variant, x.Name, x.Variant, x.WebsocketURL)
+		}
+
+		configPair := btcusd.Display(x.ConfigCurrencyPairFormat.Delimiter,
+			x.ConfigCurrencyPairFormat.Uppercase).String()
+		requestPair := btcusd.Display(x.RequestCurrencyPairFormat.Delimiter,
+			x.RequestCurrencyPairFormat.Uppercase).String()
+		if configPair != tc.configPair || requestPair != tc.requestPair {
+			t.Errorf("Test Failed - OKCoin %s expected pair formats %s and %s, got %s and %s",
+				tc.variant, tc.configPair, tc.requestPair, configPair, requestPair)
+		}
+
+		var requested string
+		x.Requester.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requested = r.URL.String()
+			return nil, errors.New("no connection")
+		})
+		x.GetTicker(context.Background(), requestPair)
+		if requested != tc.tickerURL {
+			t.Errorf("Test Failed - OKCoin %s expected request URL %s, got %s",
+				tc.variant, tc.tickerURL, requested)
+		}
+	}
+
+	var x OKCoin
+	err := x.SetVariant("OKCOIN Korea")
+	if !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("Test Failed - OKCoin SetVariant() expected %v, got %v",
+			ErrUnknownVariant, err)
+	}
+}
+
+func TestMigratePairFormat(t *testing.T) {
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+	exchCfg, err := cfg.GetExchangeConfig("OKCOIN International")
+	if err != nil {
+		t.Fatal("Test Failed - OKCoin GetExchangeConfig() error", err)
+	}
+	exchCfg.AvailablePairs = "btcusd,ltcusd,tusd_usd,ethbtc"
+	exchCfg.EnabledPairs = "btcusd,tusdusd,ethbtc"
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var migrate OKCoin
+	migrate.SetDefaults()
+	migrate.Setup(exchCfg)
+	if migrate.migratePairFormat() {
+		t.Fatal("Test Failed - OKCoin migratePairFormat() reset the pairs")
+	}
+	if !reflect.DeepEqual(migrate.EnabledPairs, []string{"BTC_USD", "TUSD_USD", "ETH_BTC"}) {
+		t.Errorf("Test Failed - OKCoin migratePairFormat() enabled pairs %v",
+			migrate.EnabledPairs)
+	}
+	if !common.StringDataCompare(migrate.AvailablePairs, "TUSD_USD") {
+		t.Errorf("Test Failed - OKCoin migratePairFormat() available pairs %v",
+			migrate.AvailablePairs)
+	}
+
+	exchCfg, err = cfg.GetExchangeConfig("OKCOIN International")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchCfg.PairFormatVersion != okcoinPairFormatVersion {
+		t.Errorf("Test Failed - OKCoin migratePairFormat() config version %d",
+			exchCfg.PairFormatVersion)
+	}
+}
+
 func setFeeBuilder() exchange.FeeBuilder {
 	return exchange.FeeBuilder{
 		Amount:              1,
@@ -53,9 +162,17 @@ func setFeeBuilder() exchange.FeeBuilder {
 	}
 }
 
+func TestGetServerTime(t *testing.T) {
+	t.Parallel()
+	_, err := o.GetServerTime()
+	if err != nil {
+		t.Errorf("Test failed - okcoin GetServerTime() failed: %s", err)
+	}
+}
+
 func TestGetSpotInstruments(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetSpotInstruments()
+	_, err := o.GetSpotInstruments(context.Background())
 	if err != nil {
 		t.Errorf("Test failed - okcoin GetSpotInstruments() failed: %s", err)
 	}
@@ -172,7 +289,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.EUR,
 	}
-	response, err := o.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 10, "hi")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     10,
+		Amount:    1,
+	}
+	response, err := o.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -305,3 +429,488 @@ func TestWithdrawInternationalBank(t *testing.T) {
 		t.Errorf("Expected '%v', received: '%v'", common.ErrFunctionNotSupported, err)
 	}
 }
+
+func TestAuthenticationNotConfiguredError(t *testing.T) {
+	var x OKCoin
+	x.SetDefaults()
+	x.AuthenticatedAPISupport = false
+	err := x.SendAuthenticatedHTTPRequest(context.Background(), okcoinUserInfo, url.Values{}, nil)
+	if !errors.Is(err, exchange.ErrAuthenticationNotConfigured) {
+		t.Errorf("Test failed - expected authentication not configured error, received %v", err)
+	}
+}
+
+// newReplayOKCoin returns an OKCoin instance whose requests are answered by
+// the captured fixtures in the directory
+func newReplayOKCoin(t *testing.T, dir string) (*OKCoin, *fixtures.Server) {
+	srv, err := fixtures.NewServer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var x OKCoin
+	x.SetDefaults()
+	x.Name = "OKCOIN International"
+	x.APIUrl = srv.URL + "/api/v1/"
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+	return &x, srv
+}
+
+func TestAPIErrorTranslation(t *testing.T) {
+	x, srv := newReplayOKCoin(t, "testdata/fixtures")
+	defer srv.Close()
+
+	_, err := x.Trade(context.Background(), 1, 1, "btc_usd", "buy")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 10010 ||
+		apiErr.Message != "Insufficient funds" {
+		t.Errorf("Test failed - Trade() unexpected error %v", err)
+	}
+
+	_, err = x.Withdrawal(context.Background(), "btc_usd", 0, "pwd", "address", 1)
+	if !errors.As(err, &apiErr) || apiErr.Code != 10016 ||
+		apiErr.Message != "Insufficient coins balance" {
+		t.Errorf("Test failed - Withdrawal() unexpected error %v", err)
+	}
+
+	_, err = x.CancelExistingOrder(context.Background(), []int64{1}, "btc_usd")
+	if !errors.As(err, &apiErr) || apiErr.Code != 10009 {
+		t.Errorf("Test failed - CancelExistingOrder() unexpected error %v", err)
+	}
+
+	_, err = x.GetUserInfo(context.Background())
+	if !errors.Is(err, exchange.ErrRateLimited) {
+		t.Errorf("Test failed - GetUserInfo() expected rate limited error %v", err)
+	}
+
+	_, err = x.GetTicker(context.Background(), "btc_usd")
+	if !errors.Is(err, exchange.ErrExchangeMaintenance) ||
+		!errors.Is(err, exchange.ErrExchangeUnavailable) {
+		t.Errorf("Test failed - GetTicker() expected maintenance error %v", err)
+	}
+
+	_, err = x.GetTicker(context.Background(), "btc_usd")
+	if !errors.As(err, &apiErr) || apiErr.Message != "99999" {
+		t.Errorf("Test failed - GetTicker() unexpected error %v", err)
+	}
+
+	resp, err := x.CancelExistingOrder(context.Background(), []int64{1, 2, 3}, "btc_usd")
+	if err != nil || resp.ErrorCode != "2,3" {
+		t.Errorf("Test failed - CancelExistingOrder() batch response not decoded %v", err)
+	}
+
+	orderID, err := x.Trade(context.Background(), 1, 1, "btc_usd", "buy")
+	if err != nil || orderID != 1337 {
+		t.Errorf("Test failed - Trade() unexpected result %d %v", orderID, err)
+	}
+
+	if served := srv.Served("POST", "/api/v1/trade.do"); served != 2 {
+		t.Errorf("Test failed - expected 2 trade requests, received %d", served)
+	}
+}
+
+func TestCurrencyDetailOverrides(t *testing.T) {
+	var x OKCoin
+	x.SetDefaults()
+
+	fee, minWithdrawal := 0.1, 1.0
+	suspended := true
+	x.SetCurrencyDetailOverrides([]config.CurrencyDetailsConfig{
+		{Currency: symbol.LTC, WithdrawalFee: &fee, MinWithdrawal: &minWithdrawal},
+		{Currency: symbol.ETH, WithdrawalSuspended: &suspended},
+	})
+
+	feeBuilder := setFeeBuilder()
+	feeBuilder.FeeType = exchange.CryptocurrencyWithdrawalFee
+	if resp, err := x.GetFee(feeBuilder); resp != fee || err != nil {
+		t.Errorf("Test Failed - GetFee() expected overridden fee %v, received %v %v",
+			fee, resp, err)
+	}
+
+	_, err := x.WithdrawCryptocurrencyFunds(exchange.WithdrawRequest{
+		Currency: symbol.LTC,
+		Amount:   0.5,
+		Address:  "address",
+	})
+	if !errors.Is(err, exchange.ErrWithdrawalBelowMinimum) {
+		t.Errorf("Test Failed - expected below minimum error, received %v", err)
+	}
+
+	_, err = x.WithdrawCryptocurrencyFunds(exchange.WithdrawRequest{
+		Currency: symbol.ETH,
+		Amount:   5,
+		Address:  "address",
+	})
+	if !errors.Is(err, exchange.ErrWithdrawalSuspended) {
+		t.Errorf("Test Failed - expected withdrawal suspended error, received %v", err)
+	}
+}
+
+func TestGetDepositHistory(t *testing.T) {
+	newest := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil || r.PostForm.Get("symbol") != "btc_usd" ||
+			r.PostForm.Get("type") != "0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		pages = append(pages, r.PostForm.Get("current_page"))
+		page, _ := strconv.Atoi(r.PostForm.Get("current_page"))
+		records := AccountRecords{Symbol: "btc_usd"}
+		for i := (page - 1) * okcoinRecordsPageLength; i < 60 && i < page*okcoinRecordsPageLength; i++ {
+			records.Records = append(records.Records, Record{
+				Address: "1OKCoinDeposit",
+				Amount:  float64(i + 1),
+				Date:    float64(newest.Add(-time.Hour*time.Duration(i)).Unix() * 1000),
+				Status:  int64(i%3 - 1),
+			})
+		}
+		data, _ := common.JSONEncode(records)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	var x OKCoin
+	x.SetDefaults()
+	x.Name = "OKCOIN International"
+	x.APIUrl = srv.URL + "/"
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	deposits, err := x.GetDepositHistory(pair.CurrencyItem(symbol.BTC),
+		time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 60 || len(pages) != 2 {
+		t.Fatalf("Test failed - expected 60 deposits over 2 pages, received %d over %v",
+			len(deposits), pages)
+	}
+	if deposits[0].Status != exchange.DepositFailed ||
+		deposits[1].Status != exchange.DepositPending ||
+		deposits[2].Status != exchange.DepositCredited ||
+		deposits[0].Timestamp != newest.Unix() ||
+		deposits[0].CryptoFromAddress != "1OKCoinDeposit" ||
+		deposits[0].Currency != symbol.BTC {
+		t.Errorf("Test failed - unexpected deposits %+v", deposits[:3])
+	}
+
+	pages = nil
+	deposits, err = x.GetDepositHistory(pair.CurrencyItem(symbol.BTC),
+		newest.Add(-time.Hour*20), newest.Add(-time.Hour*10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 11 || len(pages) != 1 || deposits[0].Amount != 11 {
+		t.Errorf("Test failed - expected 11 deposits from 1 page, received %d over %v",
+			len(deposits), pages)
+	}
+}
+
+func TestCancelAllOrdersPaginated(t *testing.T) {
+	const totalOrders = okcoinOrderHistoryPageLength*2 + 5
+	var pages []string
+	var cancelled []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil || r.PostForm.Get("symbol") != "btc_usd" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/" + okcoinOrderHistory:
+			if r.PostForm.Get("status") != okcoinOrderStatusUnfilled {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			pages = append(pages, r.PostForm.Get("current_page"))
+			page, _ := strconv.Atoi(r.PostForm.Get("current_page"))
+			history := OrderHistory{Result: true, Total: totalOrders}
+			for i := (page - 1) * okcoinOrderHistoryPageLength; i < totalOrders && i < page*okcoinOrderHistoryPageLength; i++ {
+				history.Orders = append(history.Orders, OrderInfo{OrderID: int64(i + 1)})
+			}
+			data, _ := common.JSONEncode(history)
+			w.Write(data)
+		case "/" + okcoinOrderCancel:
+			ids := common.SplitStrings(r.PostForm.Get("order_id"), ",")
+			if len(ids) > okcoinCancelOrderBatchSize {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			cancelled = append(cancelled, ids...)
+			if ids[0] == "1" {
+				w.Write([]byte(`{"success":"2,3","error_code":"1"}`))
+				return
+			}
+			w.Write([]byte(`{"result":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var x OKCoin
+	x.SetDefaults()
+	x.Name = "OKCOIN International"
+	x.APIUrl = srv.URL + "/"
+	x.AuthenticatedAPISupport = true
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	resp, err := x.CancelAllOrders(exchange.OrderCancellation{
+		CurrencyPair: pair.NewCurrencyPair(symbol.BTC, symbol.USD),
+	})
+	if err != nil {
+		t.Fatalf("Test failed - CancelAllOrders() error: %s", err)
+	}
+	if expected := []string{"1", "2", "3"}; !reflect.DeepEqual(pages, expected) {
+		t.Errorf("Test failed - expected order history pages %v, received %v", expected, pages)
+	}
+	if len(cancelled) != totalOrders || cancelled[totalOrders-1] != strconv.Itoa(totalOrders) {
+		t.Errorf("Test failed - expected %d orders cancelled, received %d",
+			totalOrders, len(cancelled))
+	}
+	if len(resp.OrderStatus) != 1 || resp.OrderStatus["1"] == "" {
+		t.Errorf("Test failed - unexpected order statuses %v", resp.OrderStatus)
+	}
+}
+
+func TestSendAuthenticatedHTTPRequestSignature(t *testing.T) {
+	var sign string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sign = r.PostForm.Get("sign")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	var x OKCoin
+	x.SetDefaults()
+	x.Name = "OKCOIN International"
+	x.APIUrl = srv.URL + "/"
+	x.AuthenticatedAPISupport = true
+	x.APIKey = "key"
+	x.APISecret = "secret"
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	err := x.SendAuthenticatedHTTPRequest(context.Background(), okcoinUserInfo, url.Values{"amount": {"1"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "B855FCF21F7B519DD24826CC4AC432C4"; sign != expected {
+		t.Errorf("Test failed - expected signature %s, received %s", expected, sign)
+	}
+}
+
+func TestGetHistoricCandles(t *testing.T) {
+	params := make(map[string]url.Values)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params[r.URL.Path] = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[[1538784000000,"6500","6600","6400","6550","12.5"],
+			[1538787600000,6550,6700,6500,6650,20]]`))
+	}))
+	defer srv.Close()
+
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x := OKCoin{FuturesValues: []string{"quarter"}}
+	x.SetDefaults()
+	x.APIUrl = srv.URL + "/"
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	start := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	candles, err := x.GetHistoricCandles(p, ticker.Spot, start, time.Hour)
+	if err != nil {
+		t.Fatalf("Test Failed - GetHistoricCandles() error: %s", err)
+	}
+	if len(candles) != 2 || !candles[0].Time.Equal(start) || candles[1].Close != 6650 {
+		t.Errorf("Test Failed - GetHistoricCandles() unexpected candles %+v", candles)
+	}
+	spot := params["/"+okcoinKline]
+	if spot.Get("symbol") != "btc_usd" || spot.Get("type") != string(TimeIntervalHour) ||
+		spot.Get("since") != "1538784000000" || spot.Get("size") != "500" {
+		t.Errorf("Test Failed - GetHistoricCandles() unexpected params %v", spot)
+	}
+
+	_, err = x.GetHistoricCandles(p, "quarter", start, time.Hour)
+	if err != nil {
+		t.Fatalf("Test Failed - GetHistoricCandles() error: %s", err)
+	}
+	if futures := params["/"+okcoinFuturesKline]; futures.Get("contract_type") != "quarter" {
+		t.Errorf("Test Failed - GetHistoricCandles() unexpected params %v", futures)
+	}
+
+	_, err = x.GetHistoricCandles(p, ticker.Spot, start, time.Minute*2)
+	if err == nil {
+		t.Error("Test Failed - GetHistoricCandles() expected an unsupported interval error")
+	}
+}
+
+func TestIntervalToTimeInterval(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		expected TimeInterval
+		valid    bool
+	}{
+		{time.Minute, TimeIntervalMinute, true},
+		{time.Hour * 2, TimeIntervalTwoHours, true},
+		{time.Hour * 24 * 7, TimeIntervalWeek, true},
+		{time.Minute * 2, "", false},
+		{time.Hour * 8, "", false},
+		{0, "", false},
+	}
+	for x := range tests {
+		result, err := IntervalToTimeInterval(tests[x].interval)
+		if (err == nil) != tests[x].valid || result != tests[x].expected {
+			t.Errorf("Test Failed - IntervalToTimeInterval(%s) unexpected result %q, error: %v",
+				tests[x].interval, result, err)
+		}
+	}
+}
+
+func TestCheckTimeInterval(t *testing.T) {
+	tests := []struct {
+		interval TimeInterval
+		valid    bool
+	}{
+		{TimeIntervalMinute, true},
+		{TimeIntervalTwelveHours, true},
+		{"1 min", false},
+		{"1MIN", false},
+		{"2min", false},
+		{"", false},
+	}
+	for x := range tests {
+		if err := CheckTimeInterval(tests[x].interval); (err == nil) != tests[x].valid {
+			t.Errorf("Test Failed - CheckTimeInterval(%q) unexpected error: %v",
+				tests[x].interval, err)
+		}
+	}
+
+	_, err := o.GetKline(context.Background(), "btc_usd", "1 min", 10, 0)
+	if err == nil {
+		t.Error("Test Failed - GetKline() expected an unsupported interval error")
+	}
+	_, err = o.GetFuturesKline(context.Background(), "btc_usd", "1hr", "this_week", 10, 0)
+	if err == nil {
+		t.Error("Test Failed - GetFuturesKline() expected an unsupported interval error")
+	}
+}
+
+func TestUpdateOrderbook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"asks":[[6601,1.5],[6600.5,2]],"bids":[[6599.5,1],[6599,3]],` +
+			`"timestamp":1541030400000}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	cfg.LoadConfig("../../testdata/configtest.json")
+
+	var x OKCoin
+	x.SetDefaults()
+	x.APIUrl = srv.URL + "/"
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	ob, err := x.UpdateOrderbook(pair.NewCurrencyPair("BTC", "USD"), ticker.Spot)
+	if err != nil {
+		t.Fatal("Test failed - UpdateOrderbook() error", err)
+	}
+	if len(ob.Bids) != 2 || len(ob.Asks) != 2 || ob.LastUpdated.IsZero() ||
+		ob.Source != orderbook.SourceREST {
+		t.Errorf("Test failed - unexpected orderbook %+v", ob)
+	}
+	if !ob.ExchangeTimestamp.Equal(time.Unix(1541030400, 0)) {
+		t.Errorf("Test failed - unexpected exchange timestamp %v", ob.ExchangeTimestamp)
+	}
+}
+
+func TestGetOrderInfo(t *testing.T) {
+	var symbols []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		symbols = append(symbols, r.PostForm.Get("symbol"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("symbol") != "ltc_usd" {
+			w.Write([]byte(`{"result":false,"error_code":10009}`))
+			return
+		}
+		w.Write([]byte(`{"result":true,"orders":[{"amount":2,"avg_price":80.5,` +
+			`"create_date":1539077390000,"deal_amount":0.5,"order_id":1337,` +
+			`"orders_id":1337,"price":81,"status":1,"symbol":"ltc_usd","type":"sell"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.GetConfig()
+	err := cfg.LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var x OKCoin
+	x.SetDefaults()
+	x.Name = "OKCOIN International"
+	x.APIUrl = srv.URL + "/"
+	x.AuthenticatedAPISupport = true
+	x.EnabledPairs = []string{"BTC_USD", "LTC_USD"}
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+
+	_, err = x.GetOrderInfo("7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01")
+	if !errors.Is(err, exchange.ErrOrderIDNotNumeric) {
+		t.Errorf("Test failed - GetOrderInfo() expected %v, received %v",
+			exchange.ErrOrderIDNotNumeric, err)
+	}
+	if len(symbols) != 0 {
+		t.Errorf("Test failed - non numeric order ID requested %v", symbols)
+	}
+
+	detail, err := x.GetOrderInfo("1337")
+	if err != nil {
+		t.Fatal("Test failed - GetOrderInfo() error", err)
+	}
+	if expected := []string{"btc_usd", "ltc_usd"}; !reflect.DeepEqual(symbols, expected) {
+		t.Errorf("Test failed - expected order info requests for %v, received %v",
+			expected, symbols)
+	}
+	expected := exchange.OrderDetail{Exchange: "OKCOIN International", ID: "1337",
+		BaseCurrency: "LTC", QuoteCurrency: "USD", OrderSide: "Sell", OrderType: "Limit",
+		CreationTime: 1539077390000, Status: "PartiallyFilled", Price: 81, Amount: 2,
+		OpenVolume: 1.5, ExecutedAmount: 0.5, AveragePrice: 80.5}
+	if detail != expected {
+		t.Errorf("Test failed - GetOrderInfo() expected %+v, received %+v", expected, detail)
+	}
+}
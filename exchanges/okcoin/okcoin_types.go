@@ -1,6 +1,11 @@
 package okcoin
 
-import "github.com/thrasher-/gocryptotrader/currency/symbol"
+import (
+	"fmt"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
+)
 
 // SpotInstrument stores the spot instrument info
 type SpotInstrument struct {
@@ -16,6 +21,12 @@ type SpotInstrument struct {
 	TickSize       float64 `json:"tick_size,string"`
 }
 
+// ServerTime holds the exchange server time
+type ServerTime struct {
+	ISO   string `json:"iso"`
+	Epoch string `json:"epoch"`
+}
+
 // Ticker holds ticker data
 type Ticker struct {
 	Buy  float64 `json:",string"`
@@ -44,10 +55,11 @@ type FuturesTicker struct {
 	UnitAmount float64
 }
 
-// Orderbook holds orderbook data
+// Orderbook holds orderbook data, the timestamp is in milliseconds
 type Orderbook struct {
-	Asks [][]float64 `json:"asks"`
-	Bids [][]float64 `json:"bids"`
+	Asks      [][]float64 `json:"asks"`
+	Bids      [][]float64 `json:"bids"`
+	Timestamp int64       `json:"timestamp"`
 }
 
 // FuturesTickerResponse is a response type
@@ -92,6 +104,7 @@ type Record struct {
 	TransactionValue   float64 `json:"transaction_value"`
 	Fee                float64 `json:"fee"`
 	Date               float64 `json:"date"`
+	Status             int64   `json:"status"`
 }
 
 // AccountRecords holds account record data
@@ -195,6 +208,22 @@ type BatchTrade struct {
 	Result bool `json:"result"`
 }
 
+// APIError holds an error code and its message returned by the API
+type APIError struct {
+	Code    int64
+	Message string
+}
+
+// Error returns the error message
+func (a *APIError) Error() string {
+	return fmt.Sprintf("OKCoin API error %d: %s", a.Code, a.Message)
+}
+
+// Unwrap returns the exchange error class of the error code, if any
+func (a *APIError) Unwrap() error {
+	return errorCodeClasses[a.Code]
+}
+
 // CancelOrderResponse is a response type for a cancelled order
 type CancelOrderResponse struct {
 	Success   string
@@ -433,6 +462,43 @@ type WebsocketTradeOrderResponse struct {
 	Result  bool  `json:"result,string"`
 }
 
+// TimeInterval is a kline interval accepted by the kline endpoints
+type TimeInterval string
+
+// Kline intervals
+const (
+	TimeIntervalMinute         = TimeInterval("1min")
+	TimeIntervalThreeMinutes   = TimeInterval("3min")
+	TimeIntervalFiveMinutes    = TimeInterval("5min")
+	TimeIntervalFifteenMinutes = TimeInterval("15min")
+	TimeIntervalThirtyMinutes  = TimeInterval("30min")
+	TimeIntervalHour           = TimeInterval("1hour")
+	TimeIntervalTwoHours       = TimeInterval("2hour")
+	TimeIntervalFourHours      = TimeInterval("4hour")
+	TimeIntervalSixHours       = TimeInterval("6hour")
+	TimeIntervalTwelveHours    = TimeInterval("12hour")
+	TimeIntervalDay            = TimeInterval("1day")
+	TimeIntervalThreeDays      = TimeInterval("3day")
+	TimeIntervalWeek           = TimeInterval("1week")
+)
+
+// timeIntervals maps the supported candle durations to their kline intervals
+var timeIntervals = map[time.Duration]TimeInterval{
+	time.Minute:        TimeIntervalMinute,
+	time.Minute * 3:    TimeIntervalThreeMinutes,
+	time.Minute * 5:    TimeIntervalFiveMinutes,
+	time.Minute * 15:   TimeIntervalFifteenMinutes,
+	time.Minute * 30:   TimeIntervalThirtyMinutes,
+	time.Hour:          TimeIntervalHour,
+	time.Hour * 2:      TimeIntervalTwoHours,
+	time.Hour * 4:      TimeIntervalFourHours,
+	time.Hour * 6:      TimeIntervalSixHours,
+	time.Hour * 12:     TimeIntervalTwelveHours,
+	time.Hour * 24:     TimeIntervalDay,
+	time.Hour * 24 * 3: TimeIntervalThreeDays,
+	time.Hour * 24 * 7: TimeIntervalWeek,
+}
+
 // WithdrawalFees the large list of predefined withdrawal fees
 // Prone to change, using highest value
 var WithdrawalFees = map[string]float64{
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
@@ -51,18 +50,11 @@ func (o *OKCoin) WsConnect() error {
 	klineValues := []string{"1min", "3min", "5min", "15min", "30min", "1hour",
 		"2hour", "4hour", "6hour", "12hour", "day", "3day", "week"}
 
-	var dialer websocket.Dialer
-
-	if o.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(o.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := o.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	o.WebsocketConn, _, err = dialer.Dial(o.Websocket.GetWebsocketURL(),
 		http.Header{})
 	if err != nil {
@@ -1,13 +1,16 @@
 package okcoin
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
@@ -16,62 +19,134 @@ import (
 
 // Start starts the OKCoin go routine
 func (o *OKCoin) Start(wg *sync.WaitGroup) {
+	o.StartWithContext(context.Background(), wg)
+}
+
+// StartWithContext starts the OKCoin go routine, the startup requests are
+// aborted when ctx is cancelled
+func (o *OKCoin) StartWithContext(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		o.Run()
+		o.run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the OKCoin wrapper
 func (o *OKCoin) Run() {
+	o.run(context.Background())
+}
+
+// run fetches the tradable pairs, aborting the request when ctx is cancelled
+func (o *OKCoin) run(ctx context.Context) {
+	logger := log.ExchangeLogger(o.GetName())
 	if o.Verbose {
-		log.Debugf("%s Websocket: %s. (url: %s).\n", o.GetName(), common.IsEnabled(o.Websocket.IsEnabled()), o.WebsocketURL)
-		log.Debugf("%s polling delay: %ds.\n", o.GetName(), o.RESTPollingDelay)
-		log.Debugf("%s %d currencies enabled: %s.\n", o.GetName(), len(o.EnabledPairs), o.EnabledPairs)
+		logger.Debugf("Websocket: %s. (url: %s).", common.IsEnabled(o.Websocket.IsEnabled()), o.WebsocketURL)
+		logger.Debugf("Polling delay: %ds.", o.RESTPollingDelay)
+		logger.Debugf("%d currencies enabled: %s.", len(o.EnabledPairs), o.EnabledPairs)
 	}
 
-	if o.APIUrl == okcoinAPIURL {
-		// OKCoin International
-		forceUpgrade := false
-		if !common.StringDataContains(o.EnabledPairs, "_") || !common.StringDataContains(o.AvailablePairs, "_") {
-			forceUpgrade = true
-		}
-
-		prods, err := o.GetSpotInstruments()
+	if o.isInternational() {
+		forceUpgrade := o.migratePairFormat()
+		diff, err := o.updateTradablePairs(ctx, forceUpgrade)
 		if err != nil {
-			log.Errorf("OKEX failed to obtain available spot instruments. Err: %d", err)
+			logger.Errorf("Failed to update tradable pairs. Err: %s", err)
 		} else {
-			var pairs []string
-			for x := range prods {
-				pairs = append(pairs, prods[x].BaseCurrency+"_"+prods[x].QuoteCurrency)
-			}
-
-			err = o.UpdateCurrencies(pairs, false, forceUpgrade)
-			if err != nil {
-				log.Errorf("OKEX failed to update available currencies. Err: %s", err)
-			}
+			logger.Infof("Tradable pairs updated. New: %v Removed: %v Disabled: %v",
+				diff.New, diff.Removed, diff.Disabled)
 		}
+	}
+}
+
+// migratePairFormat reformats the stored pairs from an older config pair
+// format and returns whether the pairs were reset and need a forced update
+func (o *OKCoin) migratePairFormat() bool {
+	reset, err := o.MigratePairFormat(okcoinPairFormatVersion,
+		[]string{"BTC_USD"})
+	if err != nil {
+		log.ExchangeLogger(o.GetName()).Errorf("Failed to migrate pair format. Err: %s", err)
+	}
+	return reset
+}
 
-		if forceUpgrade {
-			enabledPairs := []string{"btc_usd"}
-			log.Warn("Available pairs for OKCoin International reset due to config upgrade, please enable the pairs you would like again.")
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs, only OKCoin International is supported
+func (o *OKCoin) UpdateTradablePairs(forceUpdate bool) error {
+	return o.UpdateTradablePairsWithContext(context.Background(), forceUpdate)
+}
 
-			err := o.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s failed to update currencies. Err: %s", o.Name, err)
-			}
+// UpdateTradablePairsWithContext fetches the exchange's tradable currency
+// pairs and updates the stored available pairs, the request is aborted when ctx
+// is cancelled
+func (o *OKCoin) UpdateTradablePairsWithContext(ctx context.Context, forceUpdate bool) error {
+	_, err := o.updateTradablePairs(ctx, forceUpdate)
+	return err
+}
+
+// updateTradablePairs fetches the tradable currency pairs and returns the
+// changes made to the available pairs
+func (o *OKCoin) updateTradablePairs(ctx context.Context, forceUpdate bool) (exchange.PairDifference, error) {
+	if !o.isInternational() {
+		return exchange.PairDifference{}, common.ErrFunctionNotSupported
+	}
+
+	prods, err := o.GetSpotInstruments(ctx)
+	if err != nil {
+		return exchange.PairDifference{}, err
+	}
+
+	var pairs []string
+	for x := range prods {
+		pairs = append(pairs, prods[x].BaseCurrency+"_"+prods[x].QuoteCurrency)
+	}
+	o.setOrderExecutionLimits(prods)
+
+	return o.UpdatePairs(pairs, false, forceUpdate)
+}
+
+// setOrderExecutionLimits stores the order execution limits supplied by the
+// spot instruments endpoint
+func (o *OKCoin) setOrderExecutionLimits(prods []SpotInstrument) {
+	var limits []exchange.Limits
+	for x := range prods {
+		l := exchange.Limits{
+			Pair:       pair.NewCurrencyPair(prods[x].BaseCurrency, prods[x].QuoteCurrency),
+			MinAmount:  prods[x].MinSize,
+			AmountStep: prods[x].SizeIncrement,
+			PriceStep:  prods[x].TickSize,
+		}
+		if l.MinAmount == 0 {
+			l.MinAmount = prods[x].BaseMinSize
+		}
+		if l.AmountStep == 0 {
+			l.AmountStep = prods[x].BaseIncrement
+		}
+		if l.PriceStep == 0 {
+			l.PriceStep = prods[x].QuoteIncrement
 		}
+		limits = append(limits, l)
 	}
+	o.SetOrderExecutionLimits(limits)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
 func (o *OKCoin) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	return o.UpdateTickerWithContext(context.Background(), p, assetType)
+}
+
+// UpdateTickerWithContext updates and returns the ticker for a currency pair,
+// the request is aborted when ctx is cancelled
+func (o *OKCoin) UpdateTickerWithContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	currency := exchange.FormatExchangeCurrency(o.Name, p).String()
 	var tickerPrice ticker.Price
 
-	if assetType != ticker.Spot && o.APIUrl == okcoinAPIURL {
-		tick, err := o.GetFuturesTicker(currency, assetType)
+	err := o.CheckAssetType(assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
+
+	if assetType != ticker.Spot && o.isInternational() {
+		tick, err := o.GetFuturesTicker(ctx, currency, assetType)
 		if err != nil {
 			return tickerPrice, err
 		}
@@ -82,9 +157,12 @@ func (o *OKCoin) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 		tickerPrice.Last = tick.Last
 		tickerPrice.Volume = tick.Vol
 		tickerPrice.High = tick.High
-		ticker.ProcessTicker(o.GetName(), p, tickerPrice, assetType)
+		err = ticker.ProcessTicker(o.GetName(), p, tickerPrice, assetType)
+		if err != nil {
+			return tickerPrice, err
+		}
 	} else {
-		tick, err := o.GetTicker(currency)
+		tick, err := o.GetTicker(ctx, currency)
 		if err != nil {
 			return tickerPrice, err
 		}
@@ -95,7 +173,10 @@ func (o *OKCoin) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pri
 		tickerPrice.Last = tick.Last
 		tickerPrice.Volume = tick.Vol
 		tickerPrice.High = tick.High
-		ticker.ProcessTicker(o.GetName(), p, tickerPrice, ticker.Spot)
+		err = ticker.ProcessTicker(o.GetName(), p, tickerPrice, ticker.Spot)
+		if err != nil {
+			return tickerPrice, err
+		}
 
 	}
 	return ticker.GetTicker(o.Name, p, assetType)
@@ -121,8 +202,19 @@ func (o *OKCoin) GetOrderbookEx(currency pair.CurrencyPair, assetType string) (o
 
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (o *OKCoin) UpdateOrderbook(currency pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	return o.UpdateOrderbookWithContext(context.Background(), currency, assetType)
+}
+
+// UpdateOrderbookWithContext updates and returns the orderbook for a currency
+// pair, the request is aborted when ctx is cancelled
+func (o *OKCoin) UpdateOrderbookWithContext(ctx context.Context, currency pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
-	orderbookNew, err := o.GetOrderBook(exchange.FormatExchangeCurrency(o.Name, currency).String(), 200, false)
+	err := o.CheckAssetType(assetType)
+	if err != nil {
+		return orderBook, err
+	}
+
+	orderbookNew, err := o.GetOrderBook(ctx, exchange.FormatExchangeCurrency(o.Name, currency).String(), 200, false)
 	if err != nil {
 		return orderBook, err
 	}
@@ -137,6 +229,10 @@ func (o *OKCoin) UpdateOrderbook(currency pair.CurrencyPair, assetType string) (
 		orderBook.Asks = append(orderBook.Asks, orderbook.Item{Amount: data[1], Price: data[0]})
 	}
 
+	if orderbookNew.Timestamp != 0 {
+		orderBook.ExchangeTimestamp = time.Unix(0, orderbookNew.Timestamp*int64(time.Millisecond))
+	}
+	orderBook.Source = orderbook.SourceREST
 	orderbook.ProcessOrderbook(o.GetName(), currency, orderBook, assetType)
 	return orderbook.GetOrderbook(o.Name, currency, assetType)
 }
@@ -146,7 +242,7 @@ func (o *OKCoin) UpdateOrderbook(currency pair.CurrencyPair, assetType string) (
 func (o *OKCoin) GetAccountInfo() (exchange.AccountInfo, error) {
 	var response exchange.AccountInfo
 	response.Exchange = o.GetName()
-	assets, err := o.GetUserInfo()
+	assets, err := o.GetUserInfo(context.Background())
 	if err != nil {
 		return response, err
 	}
@@ -191,25 +287,111 @@ func (o *OKCoin) GetFundingHistory() ([]exchange.FundHistory, error) {
 	return fundHistory, common.ErrFunctionNotSupported
 }
 
+// GetDepositHistory returns the deposits of a currency made between start and
+// end, newest first
+func (o *OKCoin) GetDepositHistory(currency pair.CurrencyItem, start, end time.Time) ([]exchange.FundHistory, error) {
+	err := exchange.CheckTimeRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSymbol := common.StringToLower(currency.String())
+	if currency.Upper().String() != symbol.USD {
+		recordSymbol += "_usd"
+	}
+
+	var deposits []exchange.FundHistory
+	err = exchange.PaginatePages(0, func(page int) (bool, error) {
+		records, err := o.GetAccountRecords(context.Background(), recordSymbol, okcoinRecordTypeDeposit,
+			page, okcoinRecordsPageLength)
+		if err != nil {
+			return false, err
+		}
+
+		for i := range records.Records {
+			timestamp := int64(records.Records[i].Date) / 1000
+			if !start.IsZero() && timestamp < start.Unix() {
+				return false, nil
+			}
+			if !exchange.InTimeRange(timestamp, start, end) {
+				continue
+			}
+			deposits = append(deposits, exchange.FundHistory{
+				ExchangeName:      o.Name,
+				Status:            depositStatus(records.Records[i].Status),
+				Timestamp:         timestamp,
+				Currency:          currency.Upper().String(),
+				Amount:            records.Records[i].Amount,
+				Fee:               records.Records[i].Fee,
+				TransferType:      "Deposit",
+				CryptoFromAddress: records.Records[i].Address,
+			})
+		}
+		return len(records.Records) == okcoinRecordsPageLength, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deposits, nil
+}
+
+// depositStatus maps a deposit record status to a deposit status
+func depositStatus(status int64) string {
+	switch status {
+	case okcoinDepositSucceeded:
+		return exchange.DepositCredited
+	case okcoinDepositFailed:
+		return exchange.DepositFailed
+	default:
+		return exchange.DepositPending
+	}
+}
+
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (o *OKCoin) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (o *OKCoin) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
+// GetHistoricCandles returns a batch of candles of the supplied interval from
+// the start time onwards
+func (o *OKCoin) GetHistoricCandles(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]exchange.Candle, error) {
+	err := o.CheckAssetType(assetType)
+	if err != nil {
+		return nil, err
+	}
+	timeInterval, err := IntervalToTimeInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := exchange.FormatExchangeCurrency(o.Name, p).String()
+	since := timestampStart.UnixNano() / int64(time.Millisecond)
+	if assetType != ticker.Spot && o.isInternational() {
+		return o.GetFuturesKline(context.Background(), currency, timeInterval, assetType,
+			okcoinKlineBatchSize, since)
+	}
+	return o.GetKline(context.Background(), currency, timeInterval, okcoinKlineBatchSize, since)
+}
+
 // SubmitOrder submits a new order
-func (o *OKCoin) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (o *OKCoin) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(o.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var oT string
-	if orderType == exchange.Limit {
-		if side == exchange.Buy {
+	if order.OrderType == exchange.Limit {
+		if order.OrderSide == exchange.Buy {
 			oT = "buy"
 		} else {
 			oT = "sell"
 		}
-	} else if orderType == exchange.Market {
-		if side == exchange.Buy {
+	} else if order.OrderType == exchange.Market {
+		if order.OrderSide == exchange.Buy {
 			oT = "buy_market"
 		} else {
 			oT = "sell_market"
@@ -218,10 +400,15 @@ func (o *OKCoin) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, order
 		return submitOrderResponse, errors.New("Unsupported order type")
 	}
 
-	response, err := o.Trade(amount, price, p.Pair().String(), oT)
+	amount, price, err := o.CheckOrderExecutionLimits(order.Pair, order.Amount, order.Price, order.OrderType)
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := o.Trade(context.Background(), amount, price, order.Pair.Pair().String(), oT)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -246,7 +433,7 @@ func (o *OKCoin) CancelOrder(order exchange.OrderCancellation) error {
 		return err
 	}
 
-	resp, err := o.CancelExistingOrder(orders, exchange.FormatExchangeCurrency(o.Name, order.CurrencyPair).String())
+	resp, err := o.CancelExistingOrder(context.Background(), orders, exchange.FormatExchangeCurrency(o.Name, order.CurrencyPair).String())
 	if !resp.Result {
 		return errors.New(resp.ErrorCode)
 	}
@@ -258,24 +445,30 @@ func (o *OKCoin) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 	cancelAllOrdersResponse := exchange.CancelAllOrdersResponse{
 		OrderStatus: make(map[string]string),
 	}
-	orderInfo, err := o.GetOrderInformation(-1, exchange.FormatExchangeCurrency(o.Name, orderCancellation.CurrencyPair).String())
+	orderSymbol := exchange.FormatExchangeCurrency(o.Name, orderCancellation.CurrencyPair).String()
+	openOrders, err := o.GetAllOrderHistory(context.Background(), okcoinOrderStatusUnfilled, orderSymbol)
 	if err != nil {
 		return cancelAllOrdersResponse, err
 	}
 
-	var ordersToCancel []int64
-	for _, order := range orderInfo {
-		ordersToCancel = append(ordersToCancel, order.OrderID)
-	}
+	// Cancel requests are limited in size so the open orders are cancelled in
+	// batches
+	for x := 0; x < len(openOrders); x += okcoinCancelOrderBatchSize {
+		var batch []int64
+		for y := x; y < len(openOrders) && y < x+okcoinCancelOrderBatchSize; y++ {
+			batch = append(batch, openOrders[y].OrderID)
+		}
 
-	if len(ordersToCancel) > 0 {
-		resp, err := o.CancelExistingOrder(ordersToCancel, exchange.FormatExchangeCurrency(o.Name, orderCancellation.CurrencyPair).String())
+		resp, err := o.CancelExistingOrder(context.Background(), batch, orderSymbol)
 		if err != nil {
-			return cancelAllOrdersResponse, err
+			for y := range batch {
+				cancelAllOrdersResponse.OrderStatus[strconv.FormatInt(batch[y], 10)] = err.Error()
+			}
+			continue
 		}
 
-		for _, order := range common.SplitStrings(resp.ErrorCode, ",") {
-			if err != nil {
+		if resp.ErrorCode != "" {
+			for _, order := range common.SplitStrings(resp.ErrorCode, ",") {
 				cancelAllOrdersResponse.OrderStatus[order] = "Order could not be cancelled"
 			}
 		}
@@ -284,14 +477,77 @@ func (o *OKCoin) CancelAllOrders(orderCancellation exchange.OrderCancellation) (
 	return cancelAllOrdersResponse, nil
 }
 
-// GetOrderInfo returns information on a current open order
-func (o *OKCoin) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
-	var orderDetail exchange.OrderDetail
-	return orderDetail, common.ErrNotYetImplemented
+// GetOrderInfo returns information on an order. Order info requests need the
+// currency pair of the order so each enabled pair is queried until the order
+// is found
+func (o *OKCoin) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	id, err := exchange.ParseNumericOrderID(orderID)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	for _, p := range o.GetEnabledCurrencies() {
+		orders, err := o.GetOrderInformation(context.Background(), id,
+			exchange.FormatExchangeCurrency(o.Name, p).String())
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.Code == okcoinErrorOrderNotFound {
+				continue
+			}
+			return exchange.OrderDetail{}, err
+		}
+
+		for x := range orders {
+			if orders[x].OrderID == id {
+				return o.convertOrder(orders[x], p), nil
+			}
+		}
+	}
+	return exchange.OrderDetail{}, fmt.Errorf("%s order %s not found", o.Name, orderID)
+}
+
+// convertOrder maps an order info result to an order detail
+func (o *OKCoin) convertOrder(order OrderInfo, p pair.CurrencyPair) exchange.OrderDetail {
+	side := exchange.Buy
+	if common.StringContains(order.Type, "sell") {
+		side = exchange.Sell
+	}
+	orderType := exchange.Limit
+	if common.StringContains(order.Type, "market") {
+		orderType = exchange.Market
+	}
+
+	status := strconv.Itoa(order.Status)
+	switch order.Status {
+	case okcoinOrderUnfilled, okcoinOrderCancelling:
+		status = "Active"
+	case okcoinOrderPartiallyFilled:
+		status = "PartiallyFilled"
+	case okcoinOrderFilled:
+		status = "Filled"
+	case okcoinOrderCancelled:
+		status = "Cancelled"
+	}
+
+	return exchange.OrderDetail{
+		Exchange:       o.Name,
+		ID:             strconv.FormatInt(order.OrderID, 10),
+		BaseCurrency:   p.FirstCurrency.String(),
+		QuoteCurrency:  p.SecondCurrency.String(),
+		OrderSide:      side.ToString(),
+		OrderType:      orderType.ToString(),
+		CreationTime:   order.Created,
+		Status:         status,
+		Price:          order.Price,
+		Amount:         order.Amount,
+		OpenVolume:     order.Amount - order.DealAmount,
+		ExecutedAmount: order.DealAmount,
+		AveragePrice:   order.AvgPrice,
+	}
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (o *OKCoin) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (o *OKCoin) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	// NOTE needs API version update to access
 	return "", common.ErrNotYetImplemented
 }
@@ -299,7 +555,16 @@ func (o *OKCoin) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID s
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
 // submitted
 func (o *OKCoin) WithdrawCryptocurrencyFunds(withdrawRequest exchange.WithdrawRequest) (string, error) {
-	resp, err := o.Withdrawal(withdrawRequest.Currency.String(), withdrawRequest.FeeAmount, withdrawRequest.TradePassword, withdrawRequest.Address, withdrawRequest.Amount)
+	details, err := o.GetCurrencyDetails()
+	if err != nil {
+		return "", err
+	}
+	err = exchange.CheckWithdrawal(details, withdrawRequest)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.Withdrawal(context.Background(), withdrawRequest.Currency.String(), withdrawRequest.FeeAmount, withdrawRequest.TradePassword, withdrawRequest.Address, withdrawRequest.Amount)
 	return fmt.Sprintf("%v", resp), err
 }
 
@@ -1,6 +1,7 @@
 package okex
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -58,6 +59,7 @@ const (
 	spotTrades  = "trades"
 	spotKline   = "kline"
 	instruments = "instruments"
+	serverTime  = "general/v3/time"
 
 	// Authenticated
 	spotUserInfo       = "userinfo"
@@ -73,11 +75,18 @@ const (
 
 	myWalletInfo = "wallet_info.do"
 
+	// Authenticated v3 account requests
+	accountCurrencies     = "account/v3/currencies"
+	accountWithdrawalFees = "account/v3/withdrawal/fee"
+
 	// just your average return type from okex
 	returnTypeOne = "map[string]interface {}"
 
 	okexAuthRate   = 0
 	okexUnauthRate = 0
+
+	// okexKlineBatchSize is the number of candles requested per kline batch
+	okexKlineBatchSize = 500
 )
 
 var errMissValue = errors.New("warning - resp value is missing from exchange")
@@ -114,6 +123,7 @@ func (o *OKEX) SetDefaults() {
 	o.ConfigCurrencyPairFormat.Uppercase = true
 	o.SupportsAutoPairUpdating = true
 	o.SupportsRESTTickerBatching = false
+	o.SetCurrencyDetails(staticCurrencyDetails())
 	o.Requester = request.New(o.Name,
 		request.NewRateLimit(time.Second, okexAuthRate),
 		request.NewRateLimit(time.Second, okexUnauthRate),
@@ -121,11 +131,14 @@ func (o *OKEX) SetDefaults() {
 	o.APIUrlDefault = apiURL
 	o.APIUrl = o.APIUrlDefault
 	o.AssetTypes = []string{ticker.Spot}
+	o.BaseCurrencies = []string{"USD"}
+	o.EnabledPairs = []string{"eos_usdt"}
 	o.WebsocketInit()
 	o.Websocket.Functionality = exchange.WebsocketTickerSupported |
 		exchange.WebsocketTradeDataSupported |
 		exchange.WebsocketKlineSupported |
 		exchange.WebsocketOrderbookSupported
+	o.Websocket.SetSubscriber(o.WsSubscribe, o.WsUnsubscribe)
 }
 
 // Setup method sets current configuration details if enabled
@@ -175,12 +188,13 @@ func (o *OKEX) Setup(exch config.ExchangeConfig) {
 	}
 }
 
-// GetSpotInstruments returns a list of tradable spot instruments and their properties
-func (o *OKEX) GetSpotInstruments() ([]SpotInstrument, error) {
+// GetSpotInstruments returns a list of tradable spot instruments and their properties.
+// Responses are briefly cached
+func (o *OKEX) GetSpotInstruments(ctx context.Context) ([]SpotInstrument, error) {
 	var resp []SpotInstrument
 
 	path := fmt.Sprintf("%sspot/v3/%s", o.APIUrl, instruments)
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendCacheablePayloadWithContext(ctx, path, nil, &resp, 0, o.Verbose)
 
 	if err != nil {
 		return nil, err
@@ -189,11 +203,31 @@ func (o *OKEX) GetSpotInstruments() ([]SpotInstrument, error) {
 	return resp, nil
 }
 
+// GetSpotInstrumentTicker returns the v3 ticker of a spot instrument
+//
+// instrumentID e.g. "BTC-USDT"
+func (o *OKEX) GetSpotInstrumentTicker(ctx context.Context, instrumentID string) (SpotTicker, error) {
+	var resp SpotTicker
+
+	path := fmt.Sprintf("%sspot/v3/%s/%s/%s", o.APIUrl, instruments, instrumentID, spotPrice)
+	return resp, o.SendHTTPRequest(ctx, path, &resp)
+}
+
+// GetServerTime returns the exchange server time
+func (o *OKEX) GetServerTime() (time.Time, error) {
+	var resp ServerTime
+	err := o.SendHTTPRequest(context.Background(), o.APIUrl+serverTime, &resp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, resp.ISO)
+}
+
 // GetContractPrice returns current contract prices
 //
 // symbol e.g. "btc_usd"
 // contractType e.g. "this_week" "next_week" "quarter"
-func (o *OKEX) GetContractPrice(symbol, contractType string) (ContractPrice, error) {
+func (o *OKEX) GetContractPrice(ctx context.Context, symbol, contractType string) (ContractPrice, error) {
 	resp := ContractPrice{}
 
 	if err := o.CheckContractType(contractType); err != nil {
@@ -209,7 +243,7 @@ func (o *OKEX) GetContractPrice(symbol, contractType string) (ContractPrice, err
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractPrice, values.Encode())
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return resp, err
 	}
@@ -226,7 +260,7 @@ func (o *OKEX) GetContractPrice(symbol, contractType string) (ContractPrice, err
 //
 // symbol e.g. "btc_usd"
 // contractType e.g. "this_week" "next_week" "quarter"
-func (o *OKEX) GetContractMarketDepth(symbol, contractType string) (ActualContractDepth, error) {
+func (o *OKEX) GetContractMarketDepth(ctx context.Context, symbol, contractType string) (ActualContractDepth, error) {
 	resp := ContractDepth{}
 	fullDepth := ActualContractDepth{}
 
@@ -243,7 +277,7 @@ func (o *OKEX) GetContractMarketDepth(symbol, contractType string) (ActualContra
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractFutureDepth, values.Encode())
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return fullDepth, err
 	}
@@ -290,7 +324,7 @@ func (o *OKEX) GetContractMarketDepth(symbol, contractType string) (ActualContra
 }
 
 // GetContractTradeHistory returns trade history for the contract market
-func (o *OKEX) GetContractTradeHistory(symbol, contractType string) ([]ActualContractTradeHistory, error) {
+func (o *OKEX) GetContractTradeHistory(ctx context.Context, symbol, contractType string) ([]ActualContractTradeHistory, error) {
 	actualTradeHistory := []ActualContractTradeHistory{}
 	var resp interface{}
 
@@ -307,7 +341,7 @@ func (o *OKEX) GetContractTradeHistory(symbol, contractType string) ([]ActualCon
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractTradeHistory, values.Encode())
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return actualTradeHistory, err
 	}
@@ -334,7 +368,7 @@ func (o *OKEX) GetContractTradeHistory(symbol, contractType string) ([]ActualCon
 // GetContractIndexPrice returns the current index price
 //
 // symbol e.g. btc_usd
-func (o *OKEX) GetContractIndexPrice(symbol string) (float64, error) {
+func (o *OKEX) GetContractIndexPrice(ctx context.Context, symbol string) (float64, error) {
 	if err := o.CheckSymbol(symbol); err != nil {
 		return 0, err
 	}
@@ -344,7 +378,7 @@ func (o *OKEX) GetContractIndexPrice(symbol string) (float64, error) {
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractFutureIndex, values.Encode())
 	var resp interface{}
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return 0, err
 	}
@@ -363,11 +397,11 @@ func (o *OKEX) GetContractIndexPrice(symbol string) (float64, error) {
 // GetContractExchangeRate returns the current exchange rate for the currency
 // pair
 // USD-CNY exchange rate used by OKEX, updated weekly
-func (o *OKEX) GetContractExchangeRate() (float64, error) {
+func (o *OKEX) GetContractExchangeRate(ctx context.Context) (float64, error) {
 	path := fmt.Sprintf("%s%s%s.do?", o.APIUrl, apiVersion, contractExchangeRate)
 	var resp interface{}
 
-	if err := o.SendHTTPRequest(path, &resp); err != nil {
+	if err := o.SendHTTPRequest(ctx, path, &resp); err != nil {
 		return 0, err
 	}
 
@@ -385,7 +419,7 @@ func (o *OKEX) GetContractExchangeRate() (float64, error) {
 // GetContractFutureEstimatedPrice returns futures estimated price
 //
 // symbol e.g btc_usd
-func (o *OKEX) GetContractFutureEstimatedPrice(symbol string) (float64, error) {
+func (o *OKEX) GetContractFutureEstimatedPrice(ctx context.Context, symbol string) (float64, error) {
 	if err := o.CheckSymbol(symbol); err != nil {
 		return 0, err
 	}
@@ -395,7 +429,7 @@ func (o *OKEX) GetContractFutureEstimatedPrice(symbol string) (float64, error) {
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractFutureIndex, values.Encode())
 	var resp interface{}
 
-	if err := o.SendHTTPRequest(path, &resp); err != nil {
+	if err := o.SendHTTPRequest(ctx, path, &resp); err != nil {
 		return 0, err
 	}
 
@@ -410,75 +444,37 @@ func (o *OKEX) GetContractFutureEstimatedPrice(symbol string) (float64, error) {
 	return 0, errMissValue
 }
 
-// GetContractCandlestickData returns CandleStickData
+// GetContractCandlestickData returns candlestick data
 //
 // symbol e.g. btc_usd
-// type e.g. 1min or 1 minute candlestick data
+// interval e.g. 1min or 1 minute candlestick data
 // contract_type e.g. this_week
 // size: specify data size to be acquired
 // since: timestamp(eg:1417536000000). data after the timestamp will be returned
-func (o *OKEX) GetContractCandlestickData(symbol, typeInput, contractType string, size, since int) ([]CandleStickData, error) {
-	var candleData []CandleStickData
+func (o *OKEX) GetContractCandlestickData(ctx context.Context, symbol string, interval TimeInterval, contractType string, size, since int) ([]exchange.Candle, error) {
 	if err := o.CheckSymbol(symbol); err != nil {
-		return candleData, err
+		return nil, err
 	}
 	if err := o.CheckContractType(contractType); err != nil {
-		return candleData, err
+		return nil, err
 	}
-	if err := o.CheckType(typeInput); err != nil {
-		return candleData, err
+	if err := o.CheckType(string(interval)); err != nil {
+		return nil, err
 	}
 
 	values := url.Values{}
 	values.Set("symbol", symbol)
-	values.Set("type", typeInput)
+	values.Set("type", string(interval))
 	values.Set("contract_type", contractType)
 	values.Set("size", strconv.FormatInt(int64(size), 10))
 	values.Set("since", strconv.FormatInt(int64(since), 10))
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractCandleStick, values.Encode())
-	var resp interface{}
-
-	if err := o.SendHTTPRequest(path, &resp); err != nil {
-		return candleData, err
-	}
-
-	if reflect.TypeOf(resp).String() == returnTypeOne {
-		errorMap := resp.(map[string]interface{})
-		return candleData, o.GetErrorCode(errorMap["error_code"].(float64))
-	}
-
-	for _, candleStickData := range resp.([]interface{}) {
-		var quickCandle CandleStickData
-
-		for i, datum := range candleStickData.([]interface{}) {
-			switch i {
-			case 0:
-				quickCandle.Timestamp = datum.(float64)
-			case 1:
-				quickCandle.Open = datum.(float64)
-			case 2:
-				quickCandle.High = datum.(float64)
-			case 3:
-				quickCandle.Low = datum.(float64)
-			case 4:
-				quickCandle.Close = datum.(float64)
-			case 5:
-				quickCandle.Volume = datum.(float64)
-			case 6:
-				quickCandle.Amount = datum.(float64)
-			default:
-				return candleData, errors.New("incoming data out of range")
-			}
-		}
-		candleData = append(candleData, quickCandle)
-	}
-
-	return candleData, nil
+	return o.getCandles(ctx, path)
 }
 
 // GetContractHoldingsNumber returns current number of holdings
-func (o *OKEX) GetContractHoldingsNumber(symbol, contractType string) (number float64, contract string, err error) {
+func (o *OKEX) GetContractHoldingsNumber(ctx context.Context, symbol, contractType string) (number float64, contract string, err error) {
 	if err = o.CheckSymbol(symbol); err != nil {
 		return number, contract, err
 	}
@@ -493,7 +489,7 @@ func (o *OKEX) GetContractHoldingsNumber(symbol, contractType string) (number fl
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractFutureHoldAmount, values.Encode())
 	var resp interface{}
 
-	if err = o.SendHTTPRequest(path, &resp); err != nil {
+	if err = o.SendHTTPRequest(ctx, path, &resp); err != nil {
 		return number, contract, err
 	}
 
@@ -513,7 +509,7 @@ func (o *OKEX) GetContractHoldingsNumber(symbol, contractType string) (number fl
 }
 
 // GetContractlimit returns upper and lower price limit
-func (o *OKEX) GetContractlimit(symbol, contractType string) (map[string]float64, error) {
+func (o *OKEX) GetContractlimit(ctx context.Context, symbol, contractType string) (map[string]float64, error) {
 	contractLimits := make(map[string]float64)
 	if err := o.CheckSymbol(symbol); err != nil {
 		return contractLimits, err
@@ -529,7 +525,7 @@ func (o *OKEX) GetContractlimit(symbol, contractType string) (map[string]float64
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, contractFutureLimits, values.Encode())
 	var resp interface{}
 
-	if err := o.SendHTTPRequest(path, &resp); err != nil {
+	if err := o.SendHTTPRequest(ctx, path, &resp); err != nil {
 		return contractLimits, err
 	}
 
@@ -545,21 +541,14 @@ func (o *OKEX) GetContractlimit(symbol, contractType string) (map[string]float64
 }
 
 // GetContractUserInfo returns OKEX Contract Account Info（Cross-Margin Mode）
-func (o *OKEX) GetContractUserInfo() error {
-	var resp interface{}
-	if err := o.SendAuthenticatedHTTPRequest(contractFutureUserInfo, url.Values{}, &resp); err != nil {
-		return err
-	}
-
-	userInfoMap := resp.(map[string]interface{})
-	if code, ok := userInfoMap["error_code"]; ok {
-		return o.GetErrorCode(code)
-	}
-	return nil
+func (o *OKEX) GetContractUserInfo(ctx context.Context) (ContractUserInfo, error) {
+	var resp ContractUserInfo
+	err := o.SendAuthenticatedHTTPRequest(ctx, contractFutureUserInfo, url.Values{}, &resp)
+	return resp, err
 }
 
 // GetContractPosition returns User Contract Positions （Cross-Margin Mode）
-func (o *OKEX) GetContractPosition(symbol, contractType string) error {
+func (o *OKEX) GetContractPosition(ctx context.Context, symbol, contractType string) error {
 	var resp interface{}
 
 	if err := o.CheckSymbol(symbol); err != nil {
@@ -573,7 +562,7 @@ func (o *OKEX) GetContractPosition(symbol, contractType string) error {
 	values.Set("symbol", symbol)
 	values.Set("contract_type", contractType)
 
-	if err := o.SendAuthenticatedHTTPRequest(contractFuturePosition, values, &resp); err != nil {
+	if err := o.SendAuthenticatedHTTPRequest(ctx, contractFuturePosition, values, &resp); err != nil {
 		return err
 	}
 
@@ -585,7 +574,7 @@ func (o *OKEX) GetContractPosition(symbol, contractType string) error {
 }
 
 // PlaceContractOrders places orders
-func (o *OKEX) PlaceContractOrders(symbol, contractType, position string, leverageRate int, price, amount float64, matchPrice bool) (float64, error) {
+func (o *OKEX) PlaceContractOrders(ctx context.Context, symbol, contractType, position string, leverageRate int, price, amount float64, matchPrice bool) (float64, error) {
 	var resp interface{}
 
 	if err := o.CheckSymbol(symbol); err != nil {
@@ -615,7 +604,7 @@ func (o *OKEX) PlaceContractOrders(symbol, contractType, position string, levera
 	}
 	values.Set("lever_rate", strconv.FormatInt(int64(leverageRate), 10))
 
-	if err := o.SendAuthenticatedHTTPRequest(contractFutureTrade, values, &resp); err != nil {
+	if err := o.SendAuthenticatedHTTPRequest(ctx, contractFutureTrade+".do", values, &resp); err != nil {
 		return 0, err
 	}
 
@@ -631,8 +620,29 @@ func (o *OKEX) PlaceContractOrders(symbol, contractType, position string, levera
 	return 0, errors.New("orderID returned nil")
 }
 
+// CancelContractOrder cancels a contract order
+//
+// symbol e.g. "btc_usd"
+// contractType e.g. "this_week" "next_week" "quarter"
+func (o *OKEX) CancelContractOrder(ctx context.Context, symbol, contractType string, orderID int64) error {
+	if err := o.CheckSymbol(symbol); err != nil {
+		return err
+	}
+	if err := o.CheckContractType(contractType); err != nil {
+		return err
+	}
+
+	values := url.Values{}
+	values.Set("symbol", symbol)
+	values.Set("order_id", strconv.FormatInt(orderID, 10))
+	values.Set("contract_type", contractType)
+
+	var resp interface{}
+	return o.SendAuthenticatedHTTPRequest(ctx, contractFutureCancel+".do", values, &resp)
+}
+
 // GetContractFuturesTradeHistory returns OKEX Contract Trade History (Not for Personal)
-func (o *OKEX) GetContractFuturesTradeHistory(symbol, date string, since int) error {
+func (o *OKEX) GetContractFuturesTradeHistory(ctx context.Context, symbol, date string, since int) error {
 	var resp interface{}
 
 	if err := o.CheckSymbol(symbol); err != nil {
@@ -644,7 +654,7 @@ func (o *OKEX) GetContractFuturesTradeHistory(symbol, date string, since int) er
 	values.Set("date", date)
 	values.Set("since", strconv.FormatInt(int64(since), 10))
 
-	if err := o.SendAuthenticatedHTTPRequest(contractFutureTradeHistory, values, &resp); err != nil {
+	if err := o.SendAuthenticatedHTTPRequest(ctx, contractFutureTradeHistory, values, &resp); err != nil {
 		return err
 	}
 
@@ -656,23 +666,44 @@ func (o *OKEX) GetContractFuturesTradeHistory(symbol, date string, since int) er
 }
 
 // GetTokenOrders returns details for a single orderID or all open orders when orderID == -1
-func (o *OKEX) GetTokenOrders(symbol string, orderID int64) (TokenOrdersResponse, error) {
+func (o *OKEX) GetTokenOrders(ctx context.Context, symbol string, orderID int64) (TokenOrdersResponse, error) {
 	var resp TokenOrdersResponse
 	values := url.Values{}
 	values.Set("symbol", symbol)
 	values.Set("order_id", strconv.FormatInt(orderID, 10))
 
-	if err := o.SendAuthenticatedHTTPRequest(contractFutureTradeHistory, values, &resp); err != nil {
+	if err := o.SendAuthenticatedHTTPRequest(ctx, contractFutureTradeHistory, values, &resp); err != nil {
 		return resp, err
 	}
 
 	return resp, nil
 }
 
+// GetSpotOrder returns the details of a spot order
+// symbol such as ltc_btc
+func (o *OKEX) GetSpotOrder(ctx context.Context, symbol string, orderID int64) (SpotOrder, error) {
+	var resp SpotOrdersResponse
+	values := url.Values{}
+	values.Set("symbol", symbol)
+	values.Set("order_id", strconv.FormatInt(orderID, 10))
+
+	err := o.SendAuthenticatedHTTPRequest(ctx, spotOrderInfo+".do", values, &resp)
+	if err != nil {
+		return SpotOrder{}, err
+	}
+
+	for x := range resp.Orders {
+		if resp.Orders[x].OrderID == orderID {
+			return resp.Orders[x], nil
+		}
+	}
+	return SpotOrder{}, fmt.Errorf("order %d not found", orderID)
+}
+
 // GetUserInfo returns the user info
-func (o *OKEX) GetUserInfo() (SpotUserInfo, error) {
+func (o *OKEX) GetUserInfo(ctx context.Context) (SpotUserInfo, error) {
 	var resp SpotUserInfo
-	err := o.SendAuthenticatedHTTPRequest(spotUserInfo, url.Values{}, &resp)
+	err := o.SendAuthenticatedHTTPRequest(ctx, spotUserInfo, url.Values{}, &resp)
 	if err != nil {
 		return resp, err
 	}
@@ -680,7 +711,7 @@ func (o *OKEX) GetUserInfo() (SpotUserInfo, error) {
 }
 
 // SpotNewOrder creates a new spot order
-func (o *OKEX) SpotNewOrder(arg SpotNewOrderRequestParams) (int64, error) {
+func (o *OKEX) SpotNewOrder(ctx context.Context, arg SpotNewOrderRequestParams) (int64, error) {
 	type response struct {
 		Result  bool  `json:"result"`
 		OrderID int64 `json:"order_id"`
@@ -693,7 +724,7 @@ func (o *OKEX) SpotNewOrder(arg SpotNewOrderRequestParams) (int64, error) {
 	params.Set("price", strconv.FormatFloat(arg.Price, 'f', -1, 64))
 	params.Set("amount", strconv.FormatFloat(arg.Amount, 'f', -1, 64))
 
-	err := o.SendAuthenticatedHTTPRequest(spotTrade, params, &res)
+	err := o.SendAuthenticatedHTTPRequest(ctx, spotTrade, params, &res)
 	if err != nil {
 		return res.OrderID, err
 	}
@@ -705,7 +736,7 @@ func (o *OKEX) SpotNewOrder(arg SpotNewOrderRequestParams) (int64, error) {
 // symbol such as ltc_btc
 // orderID orderID
 // returns orderID or an error
-func (o *OKEX) SpotCancelOrder(symbol string, argOrderID int64) (int64, error) {
+func (o *OKEX) SpotCancelOrder(ctx context.Context, symbol string, argOrderID int64) (int64, error) {
 	var res = struct {
 		Result    bool   `json:"result"`
 		OrderID   string `json:"order_id"`
@@ -717,7 +748,7 @@ func (o *OKEX) SpotCancelOrder(symbol string, argOrderID int64) (int64, error) {
 	params.Set("order_id", strconv.FormatInt(argOrderID, 10))
 	var returnOrderID int64
 
-	err := o.SendAuthenticatedHTTPRequest(spotCancelTrade+".do", params, &res)
+	err := o.SendAuthenticatedHTTPRequest(ctx, spotCancelTrade+".do", params, &res)
 	if err != nil {
 		return returnOrderID, err
 	}
@@ -733,8 +764,8 @@ func (o *OKEX) SpotCancelOrder(symbol string, argOrderID int64) (int64, error) {
 // GetLatestSpotPrice returns latest spot price of symbol
 //
 // symbol: string of currency pair
-func (o *OKEX) GetLatestSpotPrice(symbol string) (float64, error) {
-	spotPrice, err := o.GetSpotTicker(symbol)
+func (o *OKEX) GetLatestSpotPrice(ctx context.Context, symbol string) (float64, error) {
+	spotPrice, err := o.GetSpotTicker(ctx, symbol)
 
 	if err != nil {
 		return 0, err
@@ -744,14 +775,14 @@ func (o *OKEX) GetLatestSpotPrice(symbol string) (float64, error) {
 }
 
 // GetSpotTicker returns Price Ticker
-func (o *OKEX) GetSpotTicker(symbol string) (SpotPrice, error) {
+func (o *OKEX) GetSpotTicker(ctx context.Context, symbol string) (SpotPrice, error) {
 	var resp SpotPrice
 
 	values := url.Values{}
 	values.Set("symbol", symbol)
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, "ticker", values.Encode())
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return resp, err
 	}
@@ -763,7 +794,7 @@ func (o *OKEX) GetSpotTicker(symbol string) (SpotPrice, error) {
 }
 
 //GetSpotMarketDepth returns Market Depth
-func (o *OKEX) GetSpotMarketDepth(asd ActualSpotDepthRequestParams) (ActualSpotDepth, error) {
+func (o *OKEX) GetSpotMarketDepth(ctx context.Context, asd ActualSpotDepthRequestParams) (ActualSpotDepth, error) {
 	resp := SpotDepth{}
 	fullDepth := ActualSpotDepth{}
 
@@ -773,7 +804,7 @@ func (o *OKEX) GetSpotMarketDepth(asd ActualSpotDepthRequestParams) (ActualSpotD
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, "depth", values.Encode())
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return fullDepth, err
 	}
@@ -820,7 +851,7 @@ func (o *OKEX) GetSpotMarketDepth(asd ActualSpotDepthRequestParams) (ActualSpotD
 }
 
 // GetSpotRecentTrades returns recent trades
-func (o *OKEX) GetSpotRecentTrades(ast ActualSpotTradeHistoryRequestParams) ([]ActualSpotTradeHistory, error) {
+func (o *OKEX) GetSpotRecentTrades(ctx context.Context, ast ActualSpotTradeHistoryRequestParams) ([]ActualSpotTradeHistory, error) {
 	actualTradeHistory := []ActualSpotTradeHistory{}
 	var resp interface{}
 
@@ -830,7 +861,7 @@ func (o *OKEX) GetSpotRecentTrades(ast ActualSpotTradeHistoryRequestParams) ([]A
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, "trades", values.Encode())
 
-	err := o.SendHTTPRequest(path, &resp)
+	err := o.SendHTTPRequest(ctx, path, &resp)
 	if err != nil {
 		return actualTradeHistory, err
 	}
@@ -855,8 +886,10 @@ func (o *OKEX) GetSpotRecentTrades(ast ActualSpotTradeHistoryRequestParams) ([]A
 }
 
 // GetSpotKline returns candlestick data
-func (o *OKEX) GetSpotKline(arg KlinesRequestParams) ([]CandleStickData, error) {
-	var candleData []CandleStickData
+func (o *OKEX) GetSpotKline(ctx context.Context, arg KlinesRequestParams) ([]exchange.Candle, error) {
+	if err := o.CheckType(string(arg.Type)); err != nil {
+		return nil, err
+	}
 
 	values := url.Values{}
 	values.Set("symbol", arg.Symbol)
@@ -869,44 +902,35 @@ func (o *OKEX) GetSpotKline(arg KlinesRequestParams) ([]CandleStickData, error)
 	}
 
 	path := fmt.Sprintf("%s%s%s.do?%s", o.APIUrl, apiVersion, spotKline, values.Encode())
-	var resp interface{}
+	return o.getCandles(ctx, path)
+}
 
-	if err := o.SendHTTPRequest(path, &resp); err != nil {
-		return candleData, err
+// getCandles requests kline rows and parses them into candles
+func (o *OKEX) getCandles(ctx context.Context, path string) ([]exchange.Candle, error) {
+	var resp interface{}
+	if err := o.SendHTTPRequest(ctx, path, &resp); err != nil {
+		return nil, err
 	}
 
-	if reflect.TypeOf(resp).String() == returnTypeOne {
-		errorMap := resp.(map[string]interface{})
-		return candleData, o.GetErrorCode(errorMap["error_code"].(float64))
-	}
-
-	for _, candleStickData := range resp.([]interface{}) {
-		var quickCandle CandleStickData
-
-		for i, datum := range candleStickData.([]interface{}) {
-			switch i {
-			case 0:
-				quickCandle.Timestamp = datum.(float64)
-			case 1:
-				quickCandle.Open, _ = strconv.ParseFloat(datum.(string), 64)
-			case 2:
-				quickCandle.High, _ = strconv.ParseFloat(datum.(string), 64)
-			case 3:
-				quickCandle.Low, _ = strconv.ParseFloat(datum.(string), 64)
-			case 4:
-				quickCandle.Close, _ = strconv.ParseFloat(datum.(string), 64)
-			case 5:
-				quickCandle.Volume, _ = strconv.ParseFloat(datum.(string), 64)
-			case 6:
-				quickCandle.Amount, _ = strconv.ParseFloat(datum.(string), 64)
-			default:
-				return candleData, errors.New("incoming data out of range")
-			}
+	if errorMap, ok := resp.(map[string]interface{}); ok {
+		code, ok := errorMap["error_code"]
+		if !ok {
+			return nil, errors.New("unexpected kline response without data")
 		}
-		candleData = append(candleData, quickCandle)
+		return nil, o.GetErrorCode(code)
 	}
 
-	return candleData, nil
+	rawRows, ok := resp.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected kline response type %T", resp)
+	}
+	rows := make([][]interface{}, len(rawRows))
+	for x := range rawRows {
+		if rows[x], ok = rawRows[x].([]interface{}); !ok {
+			return nil, fmt.Errorf("kline row %d is %T, expected an array", x, rawRows[x])
+		}
+	}
+	return exchange.ParseCandles(rows)
 }
 
 // GetErrorCode finds the associated error code and returns its corresponding
@@ -929,27 +953,53 @@ func (o *OKEX) GetErrorCode(code interface{}) error {
 	return errors.New("unable to find SPOT error code")
 }
 
-// SendHTTPRequest sends an unauthenticated HTTP request
-func (o *OKEX) SendHTTPRequest(path string, result interface{}) error {
-	return o.SendPayload("GET", path, nil, nil, result, false, o.Verbose)
+// SendHTTPRequest sends an unauthenticated HTTP request which is aborted when
+// ctx is cancelled
+func (o *OKEX) SendHTTPRequest(ctx context.Context, path string, result interface{}) error {
+	return o.SendPayloadWithContext(ctx, "GET", path, nil, nil, result, false, o.Verbose)
+}
+
+// SendAuthenticatedHTTPRequestV3 sends an authenticated request to a v3 API
+// endpoint. Requests are signed with the API secret, the passphrase is set as
+// the client ID and the timestamp is adjusted by the server clock offset. The
+// request is aborted when ctx is cancelled
+func (o *OKEX) SendAuthenticatedHTTPRequestV3(ctx context.Context, method, requestPath string, result interface{}) error {
+	if !o.AuthenticatedAPISupport {
+		return exchange.NewAuthenticationNotConfiguredError(o.Name)
+	}
+
+	timestamp := o.GetAdjustedTime().UTC().Format("2006-01-02T15:04:05.000Z")
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["OK-ACCESS-KEY"] = o.APIKey
+	headers["OK-ACCESS-SIGN"] = exchange.SignSHA256HMAC(
+		[]byte(timestamp+method+"/api/"+requestPath), []byte(o.APISecret), true)
+	headers["OK-ACCESS-TIMESTAMP"] = timestamp
+	headers["OK-ACCESS-PASSPHRASE"] = o.ClientID
+
+	err := o.SendPayloadWithContext(ctx, method, o.APIUrl+requestPath, headers, nil, result, true, o.Verbose)
+	return exchange.ClassifyRequestError(err)
 }
 
 // SendAuthenticatedHTTPRequest sends an authenticated http request to a desired
-// path
-func (o *OKEX) SendAuthenticatedHTTPRequest(method string, values url.Values, result interface{}) (err error) {
+// path, the request is aborted when ctx is cancelled
+func (o *OKEX) SendAuthenticatedHTTPRequest(ctx context.Context, method string, values url.Values, result interface{}) (err error) {
 	if !o.AuthenticatedAPISupport {
-		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, o.Name)
+		return exchange.NewAuthenticationNotConfiguredError(o.Name)
 	}
 
 	values.Set("api_key", o.APIKey)
-	hasher := common.GetMD5([]byte(values.Encode() + "&secret_key=" + o.APISecret))
-	values.Set("sign", strings.ToUpper(common.HexEncodeToString(hasher)))
+	values.Set("sign", strings.ToUpper(exchange.SignMD5Concat(
+		[]byte(values.Encode()+"&secret_key="), []byte(o.APISecret), false)))
 
 	encoded := values.Encode()
 	path := o.APIUrl + apiVersion + method
 
+	ctx, id := request.WithRequestID(ctx)
 	if o.Verbose {
-		log.Debugf("Sending POST request to %s with params %s\n", path, encoded)
+		log.Debugf("%s request %s. Sending POST request to %s with params %s",
+			o.Name, id, path, request.ScrubBody(encoded))
 	}
 
 	headers := make(map[string]string)
@@ -962,22 +1012,37 @@ func (o *OKEX) SendAuthenticatedHTTPRequest(method string, values url.Values, re
 		Error  int64 `json:"error_code"`
 	}{}
 
-	err = o.SendPayload("POST", path, headers, strings.NewReader(encoded), &intermediary, true, o.Verbose)
+	err = o.SendPayloadWithContext(ctx, "POST", path, headers, strings.NewReader(encoded), &intermediary, true, o.Verbose)
 	if err != nil {
-		return err
+		return exchange.ClassifyRequestError(err)
 	}
 
 	err = common.JSONDecode(intermediary, &errCap)
 	if err == nil {
 		if !errCap.Result {
-			return fmt.Errorf("SendAuthenticatedHTTPRequest error - %s",
+			msg := fmt.Sprintf("SendAuthenticatedHTTPRequest error - %s",
 				o.ErrorCodes[strconv.FormatInt(errCap.Error, 10)])
+			if class, ok := errorCodeClasses[errCap.Error]; ok {
+				return exchange.WrapError(class, "%s", msg)
+			}
+			return errors.New(msg)
 		}
 	}
 
 	return common.JSONDecode(intermediary, result)
 }
 
+// errorCodeClasses maps API error codes to their exchange error class
+var errorCodeClasses = map[int64]error{
+	10001: exchange.ErrRateLimited,
+	10002: exchange.ErrExchangeMaintenance,
+	10004: exchange.ErrCredentialsInvalid,
+	10005: exchange.ErrCredentialsInvalid,
+	10006: exchange.ErrCredentialsInvalid,
+	10007: exchange.ErrCredentialsInvalid,
+	20014: exchange.ErrExchangeMaintenance,
+}
+
 // SetErrorDefaults sets the full error default list
 func (o *OKEX) SetErrorDefaults() {
 	o.ErrorCodes = map[string]error{
@@ -1126,7 +1191,7 @@ func (o *OKEX) SetErrorDefaults() {
 // api does not return an error if there are misspellings in strings. So better
 // to check on this, this end.
 func (o *OKEX) SetCheckVarDefaults() {
-	o.ContractTypes = []string{"this_week", "next_week", "quarter"}
+	o.ContractTypes = []string{ContractThisWeek, ContractNextWeek, ContractQuarter}
 	o.CurrencyPairs = []string{"btc_usd", "ltc_usd", "eth_usd", "etc_usd", "bch_usd"}
 	o.Types = []string{"1min", "3min", "5min", "15min", "30min", "1day", "3day",
 		"1week", "1hour", "2hour", "4hour", "6hour", "12hour"}
@@ -1165,6 +1230,15 @@ func (o *OKEX) CheckType(typeInput string) error {
 	return nil
 }
 
+// IntervalToTimeInterval returns the kline interval of a candle duration
+func IntervalToTimeInterval(interval time.Duration) (TimeInterval, error) {
+	timeInterval, ok := timeIntervals[interval]
+	if !ok {
+		return "", fmt.Errorf("unsupported kline interval %s", interval)
+	}
+	return timeInterval, nil
+}
+
 // GetFee returns an estimate of fee based on type of transaction
 func (o *OKEX) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	var fee float64
@@ -1172,7 +1246,7 @@ func (o *OKEX) GetFee(feeBuilder exchange.FeeBuilder) (float64, error) {
 	case exchange.CryptocurrencyTradeFee:
 		fee = calculateTradingFee(feeBuilder.PurchasePrice, feeBuilder.Amount, feeBuilder.IsMaker)
 	case exchange.CryptocurrencyWithdrawalFee:
-		fee = getWithdrawalFee(feeBuilder.FirstCurrency)
+		fee = o.getWithdrawalFee(feeBuilder.FirstCurrency)
 	}
 	if fee < 0 {
 		fee = 0
@@ -1191,16 +1265,82 @@ func calculateTradingFee(purchasePrice, amount float64, isMaker bool) (fee float
 	return fee * amount * purchasePrice
 }
 
-func getWithdrawalFee(currency string) float64 {
-	return WithdrawalFees[currency]
+// getWithdrawalFee returns the withdrawal fee of a currency from its currency
+// details, which include any config overrides
+func (o *OKEX) getWithdrawalFee(currency string) float64 {
+	details, err := o.Base.GetCurrencyDetails()
+	if err != nil {
+		return 0
+	}
+	d, _ := exchange.GetCurrencyDetail(details, currency)
+	return d.WithdrawalFee
+}
+
+// staticCurrencyDetails returns the currency details built from the
+// predefined withdrawal fees, used until they are fetched from the API
+func staticCurrencyDetails() []exchange.CurrencyDetails {
+	details := make([]exchange.CurrencyDetails, 0, len(WithdrawalFees))
+	for currency, fee := range WithdrawalFees {
+		details = append(details, exchange.CurrencyDetails{
+			Currency:      currency,
+			WithdrawalFee: fee,
+		})
+	}
+	return details
+}
+
+// GetCurrencies returns the deposit and withdrawal status and minimum
+// withdrawal of every currency
+func (o *OKEX) GetCurrencies(ctx context.Context) ([]CurrencyResponse, error) {
+	var resp []CurrencyResponse
+	return resp, o.SendAuthenticatedHTTPRequestV3(ctx, "GET", accountCurrencies, &resp)
+}
+
+// GetWithdrawalFees returns the withdrawal fee range of every currency
+func (o *OKEX) GetWithdrawalFees(ctx context.Context) ([]WithdrawalFeeResponse, error) {
+	var resp []WithdrawalFeeResponse
+	return resp, o.SendAuthenticatedHTTPRequestV3(ctx, "GET", accountWithdrawalFees, &resp)
+}
+
+// FetchCurrencyDetails fetches the currency details from the currencies and
+// withdrawal fee endpoints. The maximum withdrawal fee is used, matching the
+// predefined withdrawal fees
+func (o *OKEX) FetchCurrencyDetails(ctx context.Context) ([]exchange.CurrencyDetails, error) {
+	currencies, err := o.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := o.GetWithdrawalFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFees := make(map[string]float64, len(fees))
+	for x := range fees {
+		maxFees[common.StringToUpper(fees[x].Currency)], _ = strconv.ParseFloat(fees[x].MaxFee, 64)
+	}
+
+	details := make([]exchange.CurrencyDetails, 0, len(currencies))
+	for x := range currencies {
+		minWithdrawal, _ := strconv.ParseFloat(currencies[x].MinWithdrawal, 64)
+		details = append(details, exchange.CurrencyDetails{
+			Currency:            common.StringToUpper(currencies[x].Currency),
+			Name:                currencies[x].Name,
+			WithdrawalFee:       maxFees[common.StringToUpper(currencies[x].Currency)],
+			MinWithdrawal:       minWithdrawal,
+			DepositSuspended:    currencies[x].CanDeposit != "1",
+			WithdrawalSuspended: currencies[x].CanWithdraw != "1",
+		})
+	}
+	return details, nil
 }
 
 // GetBalance returns the full balance across all wallets
-func (o *OKEX) GetBalance() ([]FullBalance, error) {
+func (o *OKEX) GetBalance(ctx context.Context) ([]FullBalance, error) {
 	var resp Balance
 	var balances []FullBalance
 
-	err := o.SendAuthenticatedHTTPRequest(myWalletInfo, url.Values{}, &resp)
+	err := o.SendAuthenticatedHTTPRequest(ctx, myWalletInfo, url.Values{}, &resp)
 	if err != nil {
 		return balances, err
 	}
@@ -1232,7 +1372,7 @@ func (o *OKEX) GetBalance() ([]FullBalance, error) {
 }
 
 // Withdrawal withdraws a cryptocurrency to a supplied address
-func (o *OKEX) Withdrawal(symbol string, fee float64, tradePWD, address string, amount float64) (int, error) {
+func (o *OKEX) Withdrawal(ctx context.Context, symbol string, fee float64, tradePWD, address string, amount float64) (int, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 
@@ -1245,7 +1385,7 @@ func (o *OKEX) Withdrawal(symbol string, fee float64, tradePWD, address string,
 	v.Set("target", "address")
 	resp := WithdrawalResponse{}
 
-	err := o.SendAuthenticatedHTTPRequest(spotWithdraw, v, &resp)
+	err := o.SendAuthenticatedHTTPRequest(ctx, spotWithdraw, v, &resp)
 	if err != nil {
 		return 0, err
 	}
@@ -1,13 +1,23 @@
 package okex
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 )
 
 var o OKEX
@@ -41,9 +51,17 @@ func TestSetup(t *testing.T) {
 	o.Setup(okexConfig)
 }
 
+func TestGetServerTime(t *testing.T) {
+	t.Parallel()
+	_, err := o.GetServerTime()
+	if err != nil {
+		t.Errorf("Test failed - okex GetServerTime() failed: %s", err)
+	}
+}
+
 func TestGetSpotInstruments(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetSpotInstruments()
+	_, err := o.GetSpotInstruments(context.Background())
 	if err != nil {
 		t.Errorf("Test failed - okex GetSpotInstruments() failed: %s", err)
 	}
@@ -51,15 +69,15 @@ func TestGetSpotInstruments(t *testing.T) {
 
 func TestGetContractPrice(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractPrice("btc_usd", "this_week")
+	_, err := o.GetContractPrice(context.Background(), "btc_usd", "this_week")
 	if err != nil {
 		t.Error("Test failed - okex GetContractPrice() error", err)
 	}
-	_, err = o.GetContractPrice("btc_bla", "123525")
+	_, err = o.GetContractPrice(context.Background(), "btc_bla", "123525")
 	if err == nil {
 		t.Error("Test failed - okex GetContractPrice() error", err)
 	}
-	_, err = o.GetContractPrice("btc_bla", "this_week")
+	_, err = o.GetContractPrice(context.Background(), "btc_bla", "this_week")
 	if err == nil {
 		t.Error("Test failed - okex GetContractPrice() error", err)
 	}
@@ -67,15 +85,15 @@ func TestGetContractPrice(t *testing.T) {
 
 func TestGetContractMarketDepth(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractMarketDepth("btc_usd", "this_week")
+	_, err := o.GetContractMarketDepth(context.Background(), "btc_usd", "this_week")
 	if err != nil {
 		t.Error("Test failed - okex GetContractMarketDepth() error", err)
 	}
-	_, err = o.GetContractMarketDepth("btc_bla", "123525")
+	_, err = o.GetContractMarketDepth(context.Background(), "btc_bla", "123525")
 	if err == nil {
 		t.Error("Test failed - okex GetContractMarketDepth() error", err)
 	}
-	_, err = o.GetContractMarketDepth("btc_bla", "this_week")
+	_, err = o.GetContractMarketDepth(context.Background(), "btc_bla", "this_week")
 	if err == nil {
 		t.Error("Test failed - okex GetContractMarketDepth() error", err)
 	}
@@ -83,15 +101,15 @@ func TestGetContractMarketDepth(t *testing.T) {
 
 func TestGetContractTradeHistory(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractTradeHistory("btc_usd", "this_week")
+	_, err := o.GetContractTradeHistory(context.Background(), "btc_usd", "this_week")
 	if err != nil {
 		t.Error("Test failed - okex GetContractTradeHistory() error", err)
 	}
-	_, err = o.GetContractTradeHistory("btc_bla", "123525")
+	_, err = o.GetContractTradeHistory(context.Background(), "btc_bla", "123525")
 	if err == nil {
 		t.Error("Test failed - okex GetContractTradeHistory() error", err)
 	}
-	_, err = o.GetContractTradeHistory("btc_bla", "this_week")
+	_, err = o.GetContractTradeHistory(context.Background(), "btc_bla", "this_week")
 	if err == nil {
 		t.Error("Test failed - okex GetContractTradeHistory() error", err)
 	}
@@ -99,11 +117,11 @@ func TestGetContractTradeHistory(t *testing.T) {
 
 func TestGetContractIndexPrice(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractIndexPrice("btc_usd")
+	_, err := o.GetContractIndexPrice(context.Background(), "btc_usd")
 	if err != nil {
 		t.Error("Test failed - okex GetContractIndexPrice() error", err)
 	}
-	_, err = o.GetContractIndexPrice("lol123")
+	_, err = o.GetContractIndexPrice(context.Background(), "lol123")
 	if err == nil {
 		t.Error("Test failed - okex GetContractTradeHistory() error", err)
 	}
@@ -111,7 +129,7 @@ func TestGetContractIndexPrice(t *testing.T) {
 
 func TestGetContractExchangeRate(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractExchangeRate()
+	_, err := o.GetContractExchangeRate(context.Background())
 	if err != nil {
 		t.Error("Test failed - okex GetContractExchangeRate() error", err)
 	}
@@ -119,19 +137,19 @@ func TestGetContractExchangeRate(t *testing.T) {
 
 func TestGetContractCandlestickData(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractCandlestickData("btc_usd", "1min", "this_week", 1, 2)
+	_, err := o.GetContractCandlestickData(context.Background(), "btc_usd", "1min", "this_week", 1, 2)
 	if err != nil {
 		t.Error("Test failed - okex GetContractCandlestickData() error", err)
 	}
-	_, err = o.GetContractCandlestickData("btc_bla", "1min", "this_week", 1, 2)
+	_, err = o.GetContractCandlestickData(context.Background(), "btc_bla", "1min", "this_week", 1, 2)
 	if err == nil {
 		t.Error("Test failed - okex GetContractCandlestickData() error", err)
 	}
-	_, err = o.GetContractCandlestickData("btc_usd", "min", "this_week", 1, 2)
+	_, err = o.GetContractCandlestickData(context.Background(), "btc_usd", "min", "this_week", 1, 2)
 	if err == nil {
 		t.Error("Test failed - okex GetContractCandlestickData() error", err)
 	}
-	_, err = o.GetContractCandlestickData("btc_usd", "1min", "this_wok", 1, 2)
+	_, err = o.GetContractCandlestickData(context.Background(), "btc_usd", "1min", "this_wok", 1, 2)
 	if err == nil {
 		t.Error("Test failed - okex GetContractCandlestickData() error", err)
 	}
@@ -139,15 +157,15 @@ func TestGetContractCandlestickData(t *testing.T) {
 
 func TestGetContractHoldingsNumber(t *testing.T) {
 	t.Parallel()
-	_, _, err := o.GetContractHoldingsNumber("btc_usd", "this_week")
+	_, _, err := o.GetContractHoldingsNumber(context.Background(), "btc_usd", "this_week")
 	if err != nil {
 		t.Error("Test failed - okex GetContractHoldingsNumber() error", err)
 	}
-	_, _, err = o.GetContractHoldingsNumber("btc_bla", "this_week")
+	_, _, err = o.GetContractHoldingsNumber(context.Background(), "btc_bla", "this_week")
 	if err == nil {
 		t.Error("Test failed - okex GetContractHoldingsNumber() error", err)
 	}
-	_, _, err = o.GetContractHoldingsNumber("btc_usd", "this_bla")
+	_, _, err = o.GetContractHoldingsNumber(context.Background(), "btc_usd", "this_bla")
 	if err == nil {
 		t.Error("Test failed - okex GetContractHoldingsNumber() error", err)
 	}
@@ -155,15 +173,15 @@ func TestGetContractHoldingsNumber(t *testing.T) {
 
 func TestGetContractlimit(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetContractlimit("btc_usd", "this_week")
+	_, err := o.GetContractlimit(context.Background(), "btc_usd", "this_week")
 	if err != nil {
 		t.Error("Test failed - okex GetContractlimit() error", err)
 	}
-	_, err = o.GetContractlimit("btc_bla", "this_week")
+	_, err = o.GetContractlimit(context.Background(), "btc_bla", "this_week")
 	if err == nil {
 		t.Error("Test failed - okex GetContractlimit() error", err)
 	}
-	_, err = o.GetContractlimit("btc_usd", "this_bla")
+	_, err = o.GetContractlimit(context.Background(), "btc_usd", "this_bla")
 	if err == nil {
 		t.Error("Test failed - okex GetContractlimit() error", err)
 	}
@@ -171,7 +189,7 @@ func TestGetContractlimit(t *testing.T) {
 
 func TestGetContractUserInfo(t *testing.T) {
 	t.Parallel()
-	err := o.GetContractUserInfo()
+	_, err := o.GetContractUserInfo(context.Background())
 	if err == nil {
 		t.Error("Test failed - okex GetContractUserInfo() error", err)
 	}
@@ -179,7 +197,7 @@ func TestGetContractUserInfo(t *testing.T) {
 
 func TestGetContractPosition(t *testing.T) {
 	t.Parallel()
-	err := o.GetContractPosition("btc_usd", "this_week")
+	err := o.GetContractPosition(context.Background(), "btc_usd", "this_week")
 	if err == nil {
 		t.Error("Test failed - okex GetContractPosition() error", err)
 	}
@@ -187,7 +205,7 @@ func TestGetContractPosition(t *testing.T) {
 
 func TestPlaceContractOrders(t *testing.T) {
 	t.Parallel()
-	_, err := o.PlaceContractOrders("btc_usd", "this_week", "1", 10, 1, 1, true)
+	_, err := o.PlaceContractOrders(context.Background(), "btc_usd", "this_week", "1", 10, 1, 1, true)
 	if err == nil {
 		t.Error("Test failed - okex PlaceContractOrders() error", err)
 	}
@@ -195,7 +213,7 @@ func TestPlaceContractOrders(t *testing.T) {
 
 func TestGetContractFuturesTradeHistory(t *testing.T) {
 	t.Parallel()
-	err := o.GetContractFuturesTradeHistory("btc_usd", "1972-01-01", 0)
+	err := o.GetContractFuturesTradeHistory(context.Background(), "btc_usd", "1972-01-01", 0)
 	if err == nil {
 		t.Error("Test failed - okex GetContractTradeHistory() error", err)
 	}
@@ -203,7 +221,7 @@ func TestGetContractFuturesTradeHistory(t *testing.T) {
 
 func TestGetLatestSpotPrice(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetLatestSpotPrice("ltc_btc")
+	_, err := o.GetLatestSpotPrice(context.Background(), "ltc_btc")
 	if err != nil {
 		t.Error("Test failed - okex GetLatestSpotPrice() error", err)
 	}
@@ -211,7 +229,7 @@ func TestGetLatestSpotPrice(t *testing.T) {
 
 func TestGetSpotTicker(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetSpotTicker("ltc_btc")
+	_, err := o.GetSpotTicker(context.Background(), "ltc_btc")
 	if err != nil {
 		t.Error("Test failed - okex GetSpotTicker() error", err)
 	}
@@ -219,7 +237,7 @@ func TestGetSpotTicker(t *testing.T) {
 
 func TestGetSpotMarketDepth(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetSpotMarketDepth(ActualSpotDepthRequestParams{
+	_, err := o.GetSpotMarketDepth(context.Background(), ActualSpotDepthRequestParams{
 		Symbol: "eth_btc",
 		Size:   2,
 	})
@@ -230,7 +248,7 @@ func TestGetSpotMarketDepth(t *testing.T) {
 
 func TestGetSpotRecentTrades(t *testing.T) {
 	t.Parallel()
-	_, err := o.GetSpotRecentTrades(ActualSpotTradeHistoryRequestParams{
+	_, err := o.GetSpotRecentTrades(context.Background(), ActualSpotTradeHistoryRequestParams{
 		Symbol: "ltc_btc",
 		Since:  0,
 	})
@@ -246,7 +264,7 @@ func TestGetSpotKline(t *testing.T) {
 		Type:   TimeIntervalFiveMinutes,
 		Size:   100,
 	}
-	_, err := o.GetSpotKline(arg)
+	_, err := o.GetSpotKline(context.Background(), arg)
 	if err != nil {
 		t.Error("Test failed - okex GetSpotCandleStick() error", err)
 	}
@@ -259,7 +277,7 @@ func TestSpotNewOrder(t *testing.T) {
 		t.Skip()
 	}
 
-	_, err := o.SpotNewOrder(SpotNewOrderRequestParams{
+	_, err := o.SpotNewOrder(context.Background(), SpotNewOrderRequestParams{
 		Symbol: "ltc_btc",
 		Amount: 1.1,
 		Price:  10.1,
@@ -277,7 +295,7 @@ func TestSpotCancelOrder(t *testing.T) {
 		t.Skip()
 	}
 
-	_, err := o.SpotCancelOrder("ltc_btc", 519158961)
+	_, err := o.SpotCancelOrder(context.Background(), "ltc_btc", 519158961)
 	if err != nil {
 		t.Error("Test failed - okex SpotCancelOrder() error", err)
 	}
@@ -290,7 +308,7 @@ func TestGetUserInfo(t *testing.T) {
 		t.Skip()
 	}
 
-	_, err := o.GetUserInfo()
+	_, err := o.GetUserInfo(context.Background())
 	if err != nil {
 		t.Error("Test failed - okex GetUserInfo() error", err)
 	}
@@ -421,7 +439,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.EUR,
 	}
-	response, err := o.SubmitOrder(p, exchange.Buy, exchange.Market, 1, 10, "hi")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      p,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     10,
+		Amount:    1,
+	}
+	response, err := o.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -507,6 +532,96 @@ func TestGetAccountInfo(t *testing.T) {
 	}
 }
 
+func TestGetContractPriceCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		cancel()
+	}()
+
+	_, err := f.GetContractPrice(ctx, "btc_usd", "this_week")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Test Failed - GetContractPrice() should be aborted by the context, got %v", err)
+	}
+}
+
+func TestGetAccountInfoWallets(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/" + apiVersion + myWalletInfo:
+			fmt.Fprint(w, `{"info":{"funds":{"free":{"btc":"1.5"},"holds":{"btc":"0.5"}}},"result":true}`)
+		case "/" + apiVersion + contractFutureUserInfo:
+			fmt.Fprint(w, `{"info":{"ltc":{"account_rights":10,"keep_deposit":4,"profit_real":1,
+				"profit_unreal":-0.5,"risk_rate":2.5},"btc":{"account_rights":2,"keep_deposit":0.5,
+				"profit_real":0,"profit_unreal":0.25,"risk_rate":4}},"result":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+	f.AuthenticatedAPISupport = true
+	f.APIKey = "key"
+	f.APISecret = "secret"
+
+	info, err := f.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("Test Failed - GetAccountInfo() error: %s", err)
+	}
+	if len(requests) != 1 || len(info.Accounts) != 1 {
+		t.Fatalf("Test Failed - expected only the spot wallet without futures enabled, got %v %+v",
+			requests, info.Accounts)
+	}
+	spot := info.Accounts[0]
+	if spot.Type != exchange.AccountTypeSpot || len(spot.Currencies) != 1 {
+		t.Fatalf("Test Failed - unexpected spot account %+v", spot)
+	}
+	if b := spot.Currencies[0]; b.CurrencyName != "btc" || b.TotalValue != 2 || b.Hold != 0.5 ||
+		b.AvailableValue == nil || *b.AvailableValue != 1.5 || b.MarginBalance != nil {
+		t.Errorf("Test Failed - unexpected spot balance %+v", b)
+	}
+
+	f.EnabledAssetTypes = []string{ticker.Spot, ContractQuarter}
+	info, err = f.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("Test Failed - GetAccountInfo() error: %s", err)
+	}
+	if len(info.Accounts) != 2 || info.Accounts[1].Type != exchange.AccountTypeFutures ||
+		len(info.Accounts[1].Currencies) != 2 {
+		t.Fatalf("Test Failed - unexpected accounts %+v", info.Accounts)
+	}
+	for x, expected := range []struct {
+		currency                                   string
+		total, hold, available, margin, unrealised float64
+	}{
+		{"btc", 2, 0.5, 1.5, 2, 0.25},
+		{"ltc", 10, 4, 6, 10, -0.5},
+	} {
+		b := info.Accounts[1].Currencies[x]
+		if b.CurrencyName != expected.currency || b.TotalValue != expected.total ||
+			b.Hold != expected.hold || b.AvailableValue == nil ||
+			*b.AvailableValue != expected.available || b.MarginBalance == nil ||
+			*b.MarginBalance != expected.margin || b.UnrealisedPNL == nil ||
+			*b.UnrealisedPNL != expected.unrealised {
+			t.Errorf("Test Failed - unexpected futures balance %+v", b)
+		}
+	}
+}
+
 func TestModifyOrder(t *testing.T) {
 	_, err := o.ModifyOrder(exchange.ModifyOrder{})
 	if err == nil {
@@ -570,3 +685,476 @@ func TestWithdrawInternationalBank(t *testing.T) {
 		t.Errorf("Expected '%v', received: '%v'", common.ErrFunctionNotSupported, err)
 	}
 }
+
+func TestAuthenticationNotConfiguredError(t *testing.T) {
+	var x OKEX
+	x.SetDefaults()
+	x.AuthenticatedAPISupport = false
+	err := x.SendAuthenticatedHTTPRequest(context.Background(), "userinfo.do", url.Values{}, nil)
+	if !errors.Is(err, exchange.ErrAuthenticationNotConfigured) {
+		t.Errorf("Test failed - expected authentication not configured error, received %v", err)
+	}
+}
+
+func TestFuturesContractRequests(t *testing.T) {
+	params := make(map[string]url.Values)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		params[path.Base(r.URL.Path)] = r.Form
+		switch path.Base(r.URL.Path) {
+		case "future_ticker.do":
+			fmt.Fprint(w, `{"ticker":{"buy":6500,"sell":6501,"last":6500.5},"result":true}`)
+		case "future_depth.do":
+			fmt.Fprint(w, `{"asks":[[6501,10]],"bids":[[6500,5]],"result":true}`)
+		case "future_trade.do":
+			fmt.Fprint(w, `{"order_id":12345,"result":true}`)
+		default:
+			fmt.Fprint(w, `{"order_id":"12345","result":true}`)
+		}
+	}))
+	defer server.Close()
+
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+	f.AuthenticatedAPISupport = true
+	f.APIKey = "key"
+	f.APISecret = "secret"
+
+	p := pair.NewCurrencyPairDelimiter("BTC_USDT", "_")
+	expected := map[string]string{"symbol": "btc_usd", "contract_type": ContractQuarter}
+	checkParams := func(endpoint string) {
+		for k, v := range expected {
+			if params[endpoint].Get(k) != v {
+				t.Errorf("Test Failed - %s %s expected %s got %q", endpoint, k, v,
+					params[endpoint].Get(k))
+			}
+		}
+	}
+
+	tick, err := f.UpdateTicker(p, ContractQuarter)
+	if err != nil {
+		t.Fatalf("Test Failed - UpdateTicker() error: %s", err)
+	}
+	if tick.Last != 6500.5 {
+		t.Errorf("Test Failed - unexpected ticker %+v", tick)
+	}
+	checkParams("future_ticker.do")
+
+	ob, err := f.UpdateOrderbook(p, ContractQuarter)
+	if err != nil {
+		t.Fatalf("Test Failed - UpdateOrderbook() error: %s", err)
+	}
+	if len(ob.Asks) != 1 || len(ob.Bids) != 1 {
+		t.Errorf("Test Failed - unexpected orderbook %+v", ob)
+	}
+	checkParams("future_depth.do")
+
+	order := &exchange.OrderSubmission{
+		Pair:       p,
+		AssetType:  ContractQuarter,
+		OrderSide:  exchange.Sell,
+		OrderType:  exchange.Limit,
+		Amount:     2,
+		Price:      6600,
+		ReduceOnly: true,
+	}
+	_, err = f.SubmitOrder(order)
+	if !errors.Is(err, exchange.ErrLeverageRequired) {
+		t.Errorf("Test Failed - SubmitOrder() expected leverage required error, received %v", err)
+	}
+	order.Leverage = 5
+	_, err = f.SubmitOrder(order)
+	if !errors.Is(err, exchange.ErrOrderOptionNotSupported) {
+		t.Errorf("Test Failed - SubmitOrder() expected unsupported leverage error, received %v", err)
+	}
+	if _, ok := params["future_trade.do"]; ok {
+		t.Error("Test Failed - SubmitOrder() placed an order without a valid leverage")
+	}
+
+	order.Leverage = 20
+	resp, err := f.SubmitOrder(order)
+	if err != nil || resp.OrderID != "12345" {
+		t.Fatalf("Test Failed - SubmitOrder() %+v error: %v", resp, err)
+	}
+	checkParams("future_trade.do")
+	// Reduce only sells close a long position
+	if params["future_trade.do"].Get("type") != "3" ||
+		params["future_trade.do"].Get("amount") != "2" ||
+		params["future_trade.do"].Get("lever_rate") != "20" ||
+		params["future_trade.do"].Get("match_price") != "0" {
+		t.Errorf("Test Failed - unexpected order params %v", params["future_trade.do"])
+	}
+
+	err = f.CancelOrder(exchange.OrderCancellation{
+		OrderID:      "12345",
+		CurrencyPair: p,
+		AssetType:    ContractQuarter,
+	})
+	if err != nil {
+		t.Fatalf("Test Failed - CancelOrder() error: %s", err)
+	}
+	checkParams("future_cancel.do")
+	if params["future_cancel.do"].Get("order_id") != "12345" {
+		t.Errorf("Test Failed - unexpected cancel params %v", params["future_cancel.do"])
+	}
+
+	_, err = f.UpdateTicker(pair.NewCurrencyPairDelimiter("BTC_ETH", "_"), ContractQuarter)
+	if err == nil {
+		t.Error("Test Failed - UpdateTicker() accepted a pair without a futures contract")
+	}
+}
+
+// spotOrderInfoResponse returns an order info response for order 100 with the
+// supplied status and filled amount
+func spotOrderInfoResponse(status int, filled float64) string {
+	return fmt.Sprintf(`{"result":true,"orders":[{"amount":1,"deal_amount":%v,"order_id":100,`+
+		`"price":6500,"status":%d,"symbol":"btc_usdt","type":"buy"}]}`, filled, status)
+}
+
+func TestModifyOrderCancelReplace(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderInfo  []string
+		cancel     string
+		action     exchange.ModifyOrder
+		newOrderID string
+		placed     url.Values
+		err        error
+	}{
+		{
+			name:      "partially filled before cancel",
+			orderInfo: []string{spotOrderInfoResponse(SpotOrderPartiallyFilled, 0.2), spotOrderInfoResponse(SpotOrderCancelled, 0.3)},
+			cancel:    `{"result":true,"order_id":"100"}`,
+			action:    exchange.ModifyOrder{OrderID: "100", Price: 6400, Amount: 2, CancelReplace: true},
+			placed: url.Values{"symbol": {"btc_usdt"}, "type": {"buy"}, "price": {"6400"},
+				"amount": {"1.7"}},
+			newOrderID: "200",
+		},
+		{
+			name:      "price only",
+			orderInfo: []string{spotOrderInfoResponse(SpotOrderUnfilled, 0), spotOrderInfoResponse(SpotOrderCancelled, 0)},
+			cancel:    `{"result":true,"order_id":"100"}`,
+			action:    exchange.ModifyOrder{OrderID: "100", Price: 6450, CancelReplace: true},
+			placed: url.Values{"symbol": {"btc_usdt"}, "type": {"buy"}, "price": {"6450"},
+				"amount": {"1"}},
+			newOrderID: "200",
+		},
+		{
+			name:      "filled before cancel",
+			orderInfo: []string{spotOrderInfoResponse(SpotOrderPartiallyFilled, 0.5), spotOrderInfoResponse(SpotOrderFilled, 1)},
+			cancel:    `{"result":true,"order_id":"100"}`,
+			action:    exchange.ModifyOrder{OrderID: "100", Price: 6400, CancelReplace: true},
+			err:       exchange.ErrOrderNotOpen,
+		},
+		{
+			name:      "already filled",
+			orderInfo: []string{spotOrderInfoResponse(SpotOrderFilled, 1)},
+			action:    exchange.ModifyOrder{OrderID: "100", Price: 6400, CancelReplace: true},
+			err:       exchange.ErrOrderNotOpen,
+		},
+		{
+			name:      "cancel failed",
+			orderInfo: []string{spotOrderInfoResponse(SpotOrderUnfilled, 0)},
+			cancel:    `{"result":false,"error_code":1009}`,
+			action:    exchange.ModifyOrder{OrderID: "100", Price: 6400, CancelReplace: true},
+		},
+		{
+			name:   "cancel replace not allowed",
+			action: exchange.ModifyOrder{OrderID: "100", Price: 6400},
+			err:    exchange.ErrCancelReplaceRequired,
+		},
+	}
+
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []string
+			var placed url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				endpoint := path.Base(r.URL.Path)
+				requests = append(requests, endpoint)
+				switch endpoint {
+				case "order_info.do":
+					if len(test.orderInfo) == 0 {
+						t.Error("Test Failed - unexpected order info request")
+						return
+					}
+					fmt.Fprint(w, test.orderInfo[0])
+					test.orderInfo = test.orderInfo[1:]
+				case "cancel_order.do":
+					fmt.Fprint(w, test.cancel)
+				case spotTrade:
+					placed = r.Form
+					fmt.Fprint(w, `{"result":true,"order_id":200}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			var f OKEX
+			f.SetDefaults()
+			f.APIUrl = server.URL + "/"
+			f.AuthenticatedAPISupport = true
+			f.APIKey = "key"
+			f.APISecret = "secret"
+
+			test.action.Currency = pair.NewCurrencyPairDelimiter("BTC_USDT", "_")
+			orderID, err := f.ModifyOrder(test.action)
+			if test.newOrderID != "" {
+				if err != nil || orderID != test.newOrderID {
+					t.Fatalf("Test Failed - ModifyOrder() returned %q error: %v", orderID, err)
+				}
+				for k := range test.placed {
+					if placed.Get(k) != test.placed.Get(k) {
+						t.Errorf("Test Failed - replacement %s expected %s got %q", k,
+							test.placed.Get(k), placed.Get(k))
+					}
+				}
+				return
+			}
+
+			if err == nil || (test.err != nil && !errors.Is(err, test.err)) {
+				t.Errorf("Test Failed - expected %v, received %v", test.err, err)
+			}
+			if placed != nil {
+				t.Errorf("Test Failed - replacement submitted after %v: %v", requests, placed)
+			}
+		})
+	}
+}
+
+func TestUpdateSpotTicker(t *testing.T) {
+	var requestPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		fmt.Fprint(w, `{"instrument_id":"BTC-USDT","last":"6600","best_bid":"6599.5","best_bid_size":"0.25",
+			"best_ask":"6600.5","best_ask_size":"1.5","open_24h":"6000","high_24h":"6700","low_24h":"6400",
+			"base_volume_24h":"1200","timestamp":"2018-10-06T00:00:00.000Z"}`)
+	}))
+	defer server.Close()
+
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+
+	tick, err := f.UpdateTicker(pair.NewCurrencyPairDelimiter("BTC_USDT", "_"), ticker.Spot)
+	if err != nil {
+		t.Fatalf("Test Failed - UpdateTicker() error: %s", err)
+	}
+	if requestPath != "/spot/v3/instruments/BTC-USDT/ticker" {
+		t.Errorf("Test Failed - unexpected request path %s", requestPath)
+	}
+	if tick.Last != 6600 || tick.Bid != 6599.5 || tick.BidSize != 0.25 ||
+		tick.Ask != 6600.5 || tick.AskSize != 1.5 || tick.Volume != 1200 {
+		t.Errorf("Test Failed - unexpected ticker %+v", tick)
+	}
+	if tick.Open != 6000 || tick.Change != 600 || tick.ChangePercent != 10 {
+		t.Errorf("Test Failed - unexpected ticker change %+v", tick)
+	}
+}
+
+func TestFetchCurrencyDetails(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		switch r.URL.Path {
+		case "/" + accountCurrencies:
+			fmt.Fprint(w, `[{"currency":"btc","name":"Bitcoin","can_deposit":"1","can_withdraw":"1","min_withdrawal":"0.01"},
+				{"currency":"ETH","name":"Ethereum","can_deposit":"1","can_withdraw":"0","min_withdrawal":"0.1"}]`)
+		case "/" + accountWithdrawalFees:
+			fmt.Fprint(w, `[{"currency":"BTC","min_fee":"0.0002","max_fee":"0.0005"},
+				{"currency":"ETH","min_fee":"0.001","max_fee":"0.01"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+	f.AuthenticatedAPISupport = true
+	f.APIKey = "key"
+	f.APISecret = "secret"
+	f.ClientID = "passphrase"
+
+	details, err := f.GetCurrencyDetails()
+	if err != nil {
+		t.Fatalf("Test Failed - GetCurrencyDetails() error: %s", err)
+	}
+	if headers.Get("OK-ACCESS-KEY") != "key" ||
+		headers.Get("OK-ACCESS-PASSPHRASE") != "passphrase" ||
+		headers.Get("OK-ACCESS-SIGN") == "" ||
+		headers.Get("OK-ACCESS-TIMESTAMP") == "" {
+		t.Errorf("Test Failed - unexpected request headers %v", headers)
+	}
+	if len(details) != 2 {
+		t.Fatalf("Test Failed - unexpected currency details %+v", details)
+	}
+	if details[0] != (exchange.CurrencyDetails{
+		Currency:      "BTC",
+		Name:          "Bitcoin",
+		WithdrawalFee: 0.0005,
+		MinWithdrawal: 0.01,
+	}) {
+		t.Errorf("Test Failed - unexpected BTC details %+v", details[0])
+	}
+	if !details[1].WithdrawalSuspended || details[1].DepositSuspended {
+		t.Errorf("Test Failed - unexpected ETH details %+v", details[1])
+	}
+
+	_, err = f.WithdrawCryptocurrencyFunds(exchange.WithdrawRequest{
+		Currency: symbol.BTC,
+		Amount:   0.001,
+		Address:  "address",
+	})
+	if !errors.Is(err, exchange.ErrWithdrawalBelowMinimum) {
+		t.Errorf("Test Failed - expected below minimum error, received %v", err)
+	}
+}
+
+func TestGetHistoricCandles(t *testing.T) {
+	params := make(map[string]url.Values)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		params[path.Base(r.URL.Path)] = r.Form
+		fmt.Fprint(w, `[[1538784000000,"6500","6600","6400","6550","12.5"],
+			[1538787600000,6550,6700,6500,6650,20]]`)
+	}))
+	defer server.Close()
+
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+
+	p := pair.NewCurrencyPairDelimiter("BTC_USDT", "_")
+	start := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	candles, err := f.GetHistoricCandles(p, ticker.Spot, start, time.Hour)
+	if err != nil {
+		t.Fatalf("Test Failed - GetHistoricCandles() error: %s", err)
+	}
+	if len(candles) != 2 || !candles[0].Time.Equal(start) || candles[1].Close != 6650 {
+		t.Errorf("Test Failed - GetHistoricCandles() unexpected candles %+v", candles)
+	}
+	if params["kline.do"].Get("type") != string(TimeIntervalHour) ||
+		params["kline.do"].Get("since") != "1538784000000" ||
+		params["kline.do"].Get("size") != "500" {
+		t.Errorf("Test Failed - GetHistoricCandles() unexpected params %v",
+			params["kline.do"])
+	}
+
+	_, err = f.GetHistoricCandles(p, ContractQuarter, start, time.Hour)
+	if err != nil {
+		t.Fatalf("Test Failed - GetHistoricCandles() error: %s", err)
+	}
+	if params["future_kline.do"].Get("symbol") != "btc_usd" ||
+		params["future_kline.do"].Get("contract_type") != ContractQuarter {
+		t.Errorf("Test Failed - GetHistoricCandles() unexpected params %v",
+			params["future_kline.do"])
+	}
+
+	_, err = f.GetHistoricCandles(p, ticker.Spot, start, time.Hour*8)
+	if err == nil {
+		t.Error("Test Failed - GetHistoricCandles() expected an unsupported interval error")
+	}
+}
+
+func TestIntervalToTimeInterval(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		expected TimeInterval
+		valid    bool
+	}{
+		{time.Minute, TimeIntervalMinute, true},
+		{time.Hour * 2, TimeIntervalTwoHours, true},
+		{time.Hour * 24 * 7, TimeIntervalWeek, true},
+		{time.Minute * 2, "", false},
+		{time.Hour * 8, "", false},
+		{0, "", false},
+	}
+	for x := range tests {
+		result, err := IntervalToTimeInterval(tests[x].interval)
+		if (err == nil) != tests[x].valid || result != tests[x].expected {
+			t.Errorf("Test failed - okex IntervalToTimeInterval(%s) unexpected result %q, error: %v",
+				tests[x].interval, result, err)
+		}
+	}
+}
+
+func TestGetSpotKlineUnsupportedInterval(t *testing.T) {
+	_, err := o.GetSpotKline(context.Background(), KlinesRequestParams{Symbol: "ltc_btc", Type: "1 min"})
+	if err == nil {
+		t.Error("Test failed - okex GetSpotKline() expected an unsupported interval error")
+	}
+}
+
+func TestWsChannel(t *testing.T) {
+	for _, test := range []struct {
+		channel  string
+		currency pair.CurrencyPair
+		expected string
+	}{
+		{exchange.WebsocketChannelTicker, pair.NewCurrencyPair("EOS", "USDT"), "ok_sub_spot_eos_usdt_ticker"},
+		{exchange.WebsocketChannelOrderbook, pair.NewCurrencyPair("LTC", "BTC"), "ok_sub_spot_ltc_btc_depth"},
+		{exchange.WebsocketChannelTrade, pair.NewCurrencyPair("BTC", "USD"), "ok_sub_spot_btc_usdt_deals"},
+		{exchange.WebsocketChannelKline, pair.NewCurrencyPair("ETH", "BTC"), "ok_sub_spot_eth_btc_kline_1min"},
+	} {
+		channel, err := wsChannel(exchange.WebsocketChannelSubscription{
+			Channel: test.channel, Currency: test.currency})
+		if err != nil || channel != test.expected {
+			t.Errorf("Test failed - okex wsChannel expected %s, received %s error: %v",
+				test.expected, channel, err)
+		}
+	}
+
+	if _, err := wsChannel(exchange.WebsocketChannelSubscription{Channel: "blah"}); err == nil {
+		t.Error("Test failed - okex wsChannel expected an unsupported channel error")
+	}
+}
+
+func TestUpdateOrderbook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"asks":[[6601,1.5],[6600.5,2]],"bids":[[6599.5,1],[6599,3]],"result":true}`)
+	}))
+	defer server.Close()
+
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f OKEX
+	f.SetDefaults()
+	f.APIUrl = server.URL + "/"
+
+	ob, err := f.UpdateOrderbook(pair.NewCurrencyPair("BTC", "USDT"), ticker.Spot)
+	if err != nil {
+		t.Fatalf("Test Failed - UpdateOrderbook() error: %s", err)
+	}
+	if len(ob.Bids) != 2 || len(ob.Asks) != 2 || ob.LastUpdated.IsZero() ||
+		ob.Source != orderbook.SourceREST {
+		t.Errorf("Test Failed - unexpected orderbook %+v", ob)
+	}
+}
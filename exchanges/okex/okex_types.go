@@ -1,8 +1,24 @@
 package okex
 
 import "encoding/json"
+import "time"
 import "github.com/thrasher-/gocryptotrader/currency/symbol"
 
+// Contract types, futures are requested using the contract type as the asset
+// type
+const (
+	ContractThisWeek = "this_week"
+	ContractNextWeek = "next_week"
+	ContractQuarter  = "quarter"
+)
+
+// FuturesContract identifies a futures instrument by its contract symbol and
+// contract type
+type FuturesContract struct {
+	Symbol       string
+	ContractType string
+}
+
 // SpotInstrument stores the spot instrument info
 type SpotInstrument struct {
 	BaseCurrency   string  `json:"base_currency"`
@@ -14,9 +30,16 @@ type SpotInstrument struct {
 	QuoteCurrency  string  `json:"quote_currency"`
 	QuoteIncrement float64 `json:"quote_increment,string"`
 	SizeIncrement  float64 `json:"size_increment,string"`
+	Status         string  `json:"status"`
 	TickSize       float64 `json:"tick_size,string"`
 }
 
+// ServerTime holds the exchange server time
+type ServerTime struct {
+	ISO   string `json:"iso"`
+	Epoch string `json:"epoch"`
+}
+
 // ContractPrice holds date and ticker price price for contracts.
 type ContractPrice struct {
 	Date   string `json:"date"`
@@ -58,6 +81,34 @@ type TokenOrder struct {
 	Type       string  `json:"type"`
 }
 
+// SpotOrdersResponse holds the spot orders returned by an order info request
+type SpotOrdersResponse struct {
+	Result bool        `json:"result"`
+	Orders []SpotOrder `json:"orders"`
+}
+
+// SpotOrder holds the details of a spot order
+type SpotOrder struct {
+	Amount     float64 `json:"amount"`
+	AvgPrice   float64 `json:"avg_price"`
+	CreateDate int64   `json:"create_date"`
+	DealAmount float64 `json:"deal_amount"`
+	OrderID    int64   `json:"order_id"`
+	Price      float64 `json:"price"`
+	Status     int64   `json:"status"`
+	Symbol     string  `json:"symbol"`
+	Type       string  `json:"type"`
+}
+
+// Spot order statuses
+const (
+	SpotOrderCancelled       = -1
+	SpotOrderUnfilled        = 0
+	SpotOrderPartiallyFilled = 1
+	SpotOrderFilled          = 2
+	SpotOrderCancelling      = 3
+)
+
 // TickerStreamData contains ticker stream data from okex
 type TickerStreamData struct {
 	Buy       string  `json:"buy"`
@@ -115,17 +166,6 @@ type ActualContractTradeHistory struct {
 	Type     string  `json:"buy"`
 }
 
-// CandleStickData holds candlestick data
-type CandleStickData struct {
-	Timestamp float64 `json:"timestamp"`
-	Open      float64 `json:"open"`
-	High      float64 `json:"high"`
-	Low       float64 `json:"low"`
-	Close     float64 `json:"close"`
-	Volume    float64 `json:"volume"`
-	Amount    float64 `json:"amount"`
-}
-
 // Info holds individual information
 type Info struct {
 	AccountRights float64 `json:"account_rights"`
@@ -178,6 +218,22 @@ type FutureTradeHistory struct {
 	Type   string  `json:"type"`
 }
 
+// SpotTicker holds the v3 ticker of a spot instrument, including the best bid
+// and ask sizes and the 24 hour open
+type SpotTicker struct {
+	InstrumentID  string  `json:"instrument_id"`
+	Last          float64 `json:"last,string"`
+	BestBid       float64 `json:"best_bid,string"`
+	BestBidSize   float64 `json:"best_bid_size,string"`
+	BestAsk       float64 `json:"best_ask,string"`
+	BestAskSize   float64 `json:"best_ask_size,string"`
+	Open24h       float64 `json:"open_24h,string"`
+	High24h       float64 `json:"high_24h,string"`
+	Low24h        float64 `json:"low_24h,string"`
+	BaseVolume24h float64 `json:"base_volume_24h,string"`
+	Timestamp     string  `json:"timestamp"`
+}
+
 // SpotPrice holds date and ticker price price for contracts.
 type SpotPrice struct {
 	Date   string `json:"date"`
@@ -287,6 +343,7 @@ var (
 	TimeIntervalFifteenMinutes = TimeInterval("15min")
 	TimeIntervalThirtyMinutes  = TimeInterval("30min")
 	TimeIntervalHour           = TimeInterval("1hour")
+	TimeIntervalTwoHours       = TimeInterval("2hour")
 	TimeIntervalFourHours      = TimeInterval("4hour")
 	TimeIntervalSixHours       = TimeInterval("6hour")
 	TimeIntervalTwelveHours    = TimeInterval("12hour")
@@ -295,6 +352,40 @@ var (
 	TimeIntervalWeek           = TimeInterval("1week")
 )
 
+// timeIntervals maps the supported candle durations to their kline intervals
+var timeIntervals = map[time.Duration]TimeInterval{
+	time.Minute:        TimeIntervalMinute,
+	time.Minute * 3:    TimeIntervalThreeMinutes,
+	time.Minute * 5:    TimeIntervalFiveMinutes,
+	time.Minute * 15:   TimeIntervalFifteenMinutes,
+	time.Minute * 30:   TimeIntervalThirtyMinutes,
+	time.Hour:          TimeIntervalHour,
+	time.Hour * 2:      TimeIntervalTwoHours,
+	time.Hour * 4:      TimeIntervalFourHours,
+	time.Hour * 6:      TimeIntervalSixHours,
+	time.Hour * 12:     TimeIntervalTwelveHours,
+	time.Hour * 24:     TimeIntervalDay,
+	time.Hour * 24 * 3: TimeIntervalThreeDays,
+	time.Hour * 24 * 7: TimeIntervalWeek,
+}
+
+// CurrencyResponse holds a currency from the v3 account currencies endpoint
+type CurrencyResponse struct {
+	Currency      string `json:"currency"`
+	Name          string `json:"name"`
+	CanDeposit    string `json:"can_deposit"`
+	CanWithdraw   string `json:"can_withdraw"`
+	MinWithdrawal string `json:"min_withdrawal"`
+}
+
+// WithdrawalFeeResponse holds the withdrawal fee range of a currency from the
+// v3 account withdrawal fee endpoint
+type WithdrawalFeeResponse struct {
+	Currency string `json:"currency"`
+	MinFee   string `json:"min_fee"`
+	MaxFee   string `json:"max_fee"`
+}
+
 // WithdrawalFees the large list of predefined withdrawal fees
 // Prone to change, using highest value
 var WithdrawalFees = map[string]float64{
@@ -454,6 +545,22 @@ type FullBalance struct {
 	Hold      float64
 }
 
+// ContractUserInfo holds the cross-margin futures account of each currency
+type ContractUserInfo struct {
+	Info   map[string]ContractAccount `json:"info"`
+	Result bool                       `json:"result"`
+}
+
+// ContractAccount is a sub type for ContractUserInfo, AccountRights is the
+// account equity and KeepDeposit the margin held by positions and orders
+type ContractAccount struct {
+	AccountRights float64 `json:"account_rights"`
+	KeepDeposit   float64 `json:"keep_deposit"`
+	ProfitReal    float64 `json:"profit_real"`
+	ProfitUnreal  float64 `json:"profit_unreal"`
+	RiskRate      float64 `json:"risk_rate"`
+}
+
 // Balance defines returned balance data
 type Balance struct {
 	Info struct {
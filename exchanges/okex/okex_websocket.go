@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -36,18 +35,11 @@ func (o *OKEX) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-
-	if o.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(o.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := o.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	o.WebsocketConn, _, err = dialer.Dial(o.Websocket.GetWebsocketURL(),
 		http.Header{})
 	if err != nil {
@@ -59,60 +51,56 @@ func (o *OKEX) WsConnect() error {
 	go o.WsHandleData()
 	go o.wsPingHandler()
 
-	err = o.WsSubscribe()
-	if err != nil {
-		return fmt.Errorf("Error: Could not subscribe to the OKEX websocket %s",
-			err)
-	}
-
 	return nil
 }
 
-// WsSubscribe subscribes to the websocket channels
-func (o *OKEX) WsSubscribe() error {
-	myEnabledSubscriptionChannels := []string{}
-
-	for _, pair := range o.EnabledPairs {
-
-		// ----------- deprecate when usd pairs are upgraded to usdt ----------
-		checkSymbol := common.SplitStrings(pair, "_")
-		for i := range checkSymbol {
-			if common.StringContains(checkSymbol[i], "usdt") {
-				break
-			}
-			if common.StringContains(checkSymbol[i], "usd") {
-				checkSymbol[i] = "usdt"
-			}
+// wsChannel returns the OKEX websocket channel name of a subscription
+func wsChannel(sub exchange.WebsocketChannelSubscription) (string, error) {
+	// ----------- deprecate when usd pairs are upgraded to usdt ----------
+	checkSymbol := common.SplitStrings(sub.Currency.Display("_", false).String(), "_")
+	for i := range checkSymbol {
+		if common.StringContains(checkSymbol[i], "usdt") {
+			break
 		}
+		if common.StringContains(checkSymbol[i], "usd") {
+			checkSymbol[i] = "usdt"
+		}
+	}
 
-		symbolRedone := common.JoinStrings(checkSymbol, "_")
-		// ----------- deprecate when usd pairs are upgraded to usdt ----------
-
-		myEnabledSubscriptionChannels = append(myEnabledSubscriptionChannels,
-			fmt.Sprintf("{'event':'addChannel','channel':'ok_sub_spot_%s_ticker'}",
-				symbolRedone))
-
-		myEnabledSubscriptionChannels = append(myEnabledSubscriptionChannels,
-			fmt.Sprintf("{'event':'addChannel','channel':'ok_sub_spot_%s_depth'}",
-				symbolRedone))
-
-		myEnabledSubscriptionChannels = append(myEnabledSubscriptionChannels,
-			fmt.Sprintf("{'event':'addChannel','channel':'ok_sub_spot_%s_deals'}",
-				symbolRedone))
-
-		myEnabledSubscriptionChannels = append(myEnabledSubscriptionChannels,
-			fmt.Sprintf("{'event':'addChannel','channel':'ok_sub_spot_%s_kline_1min'}",
-				symbolRedone))
+	symbolRedone := common.JoinStrings(checkSymbol, "_")
+	// ----------- deprecate when usd pairs are upgraded to usdt ----------
+
+	switch sub.Channel {
+	case exchange.WebsocketChannelTicker:
+		return fmt.Sprintf("ok_sub_spot_%s_ticker", symbolRedone), nil
+	case exchange.WebsocketChannelOrderbook:
+		return fmt.Sprintf("ok_sub_spot_%s_depth", symbolRedone), nil
+	case exchange.WebsocketChannelTrade:
+		return fmt.Sprintf("ok_sub_spot_%s_deals", symbolRedone), nil
+	case exchange.WebsocketChannelKline:
+		return fmt.Sprintf("ok_sub_spot_%s_kline_1min", symbolRedone), nil
 	}
+	return "", fmt.Errorf("unsupported websocket channel %s", sub.Channel)
+}
 
-	for _, outgoing := range myEnabledSubscriptionChannels {
-		err := o.writeToWebsocket(outgoing)
-		if err != nil {
-			return err
-		}
+// WsSubscribe subscribes to a websocket channel
+func (o *OKEX) WsSubscribe(sub exchange.WebsocketChannelSubscription) error {
+	channel, err := wsChannel(sub)
+	if err != nil {
+		return err
 	}
+	return o.writeToWebsocket(fmt.Sprintf("{'event':'addChannel','channel':'%s'}",
+		channel))
+}
 
-	return nil
+// WsUnsubscribe unsubscribes from a websocket channel
+func (o *OKEX) WsUnsubscribe(sub exchange.WebsocketChannelSubscription) error {
+	channel, err := wsChannel(sub)
+	if err != nil {
+		return err
+	}
+	return o.writeToWebsocket(fmt.Sprintf("{'event':'removeChannel','channel':'%s'}",
+		channel))
 }
 
 // WsReadData reads data from the websocket connection
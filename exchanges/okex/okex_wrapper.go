@@ -1,14 +1,18 @@
 package okex
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	log "github.com/thrasher-/gocryptotrader/logger"
@@ -16,46 +20,183 @@ import (
 
 // Start starts the OKEX go routine
 func (o *OKEX) Start(wg *sync.WaitGroup) {
+	o.StartWithContext(context.Background(), wg)
+}
+
+// StartWithContext starts the OKEX go routine, the startup requests are
+// aborted when ctx is cancelled
+func (o *OKEX) StartWithContext(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
-		o.Run()
+		o.run(ctx)
 		wg.Done()
 	}()
 }
 
 // Run implements the OKEX wrapper
 func (o *OKEX) Run() {
+	o.run(context.Background())
+}
+
+// run fetches the tradable pairs, aborting the request when ctx is cancelled
+func (o *OKEX) run(ctx context.Context) {
+	logger := log.ExchangeLogger(o.GetName())
 	if o.Verbose {
-		log.Debugf("%s Websocket: %s. (url: %s).\n", o.GetName(), common.IsEnabled(o.Websocket.IsEnabled()), o.WebsocketURL)
-		log.Debugf("%s polling delay: %ds.\n", o.GetName(), o.RESTPollingDelay)
-		log.Debugf("%s %d currencies enabled: %s.\n", o.GetName(), len(o.EnabledPairs), o.EnabledPairs)
+		logger.Debugf("Websocket: %s. (url: %s).", common.IsEnabled(o.Websocket.IsEnabled()), o.WebsocketURL)
+		logger.Debugf("Polling delay: %ds.", o.RESTPollingDelay)
+		logger.Debugf("%d currencies enabled: %s.", len(o.EnabledPairs), o.EnabledPairs)
 	}
 
-	prods, err := o.GetSpotInstruments()
+	diff, err := o.updateTradablePairs(ctx, false)
 	if err != nil {
-		log.Errorf("OKEX failed to obtain available spot instruments. Err: %d", err)
+		logger.Errorf("Failed to update tradable pairs. Err: %s", err)
 		return
 	}
 
+	logger.Infof("Tradable pairs updated. New: %v Removed: %v Disabled: %v",
+		diff.New, diff.Removed, diff.Disabled)
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (o *OKEX) UpdateTradablePairs(forceUpdate bool) error {
+	return o.UpdateTradablePairsWithContext(context.Background(), forceUpdate)
+}
+
+// UpdateTradablePairsWithContext fetches the exchange's tradable currency
+// pairs and updates the stored available pairs, the request is aborted when ctx
+// is cancelled
+func (o *OKEX) UpdateTradablePairsWithContext(ctx context.Context, forceUpdate bool) error {
+	_, err := o.updateTradablePairs(ctx, forceUpdate)
+	return err
+}
+
+// updateTradablePairs fetches the tradable currency pairs and returns the
+// changes made to the available pairs
+func (o *OKEX) updateTradablePairs(ctx context.Context, forceUpdate bool) (exchange.PairDifference, error) {
+	prods, err := o.GetSpotInstruments(ctx)
+	if err != nil {
+		return exchange.PairDifference{}, err
+	}
+
 	var pairs []string
 	for x := range prods {
 		pairs = append(pairs, prods[x].BaseCurrency+"_"+prods[x].QuoteCurrency)
 	}
+	o.setOrderExecutionLimits(prods)
+	o.setPairStatuses(prods)
+
+	return o.UpdatePairs(pairs, false, forceUpdate)
+}
+
+// setOrderExecutionLimits stores the order execution limits supplied by the
+// spot instruments endpoint
+func (o *OKEX) setOrderExecutionLimits(prods []SpotInstrument) {
+	var limits []exchange.Limits
+	for x := range prods {
+		l := exchange.Limits{
+			Pair:       pair.NewCurrencyPair(prods[x].BaseCurrency, prods[x].QuoteCurrency),
+			MinAmount:  prods[x].MinSize,
+			AmountStep: prods[x].SizeIncrement,
+			PriceStep:  prods[x].TickSize,
+		}
+		if l.MinAmount == 0 {
+			l.MinAmount = prods[x].BaseMinSize
+		}
+		if l.AmountStep == 0 {
+			l.AmountStep = prods[x].BaseIncrement
+		}
+		if l.PriceStep == 0 {
+			l.PriceStep = prods[x].QuoteIncrement
+		}
+		limits = append(limits, l)
+	}
+	o.SetOrderExecutionLimits(limits)
+}
+
+// setPairStatuses stores the trading statuses supplied by the spot instruments
+// endpoint
+func (o *OKEX) setPairStatuses(prods []SpotInstrument) {
+	var statuses []exchange.PairStatus
+	for x := range prods {
+		statuses = append(statuses, exchange.PairStatus{
+			Pair:   pair.NewCurrencyPair(prods[x].BaseCurrency, prods[x].QuoteCurrency),
+			Status: spotInstrumentStatus(prods[x].Status),
+		})
+	}
+	o.SetPairStatuses(statuses)
+}
+
+// spotInstrumentStatus maps a spot instrument status to a pair trading
+// status, instruments without a status are trading
+func spotInstrumentStatus(status string) string {
+	switch common.StringToLower(status) {
+	case "suspend", "halt":
+		return exchange.PairStatusHalted
+	case "delisted", "offline":
+		return exchange.PairStatusDelisted
+	default:
+		return exchange.PairStatusTrading
+	}
+}
 
-	err = o.UpdateCurrencies(pairs, false, false)
+// checkAssetType returns an error unless the asset type is spot or a
+// supported contract type
+func (o *OKEX) checkAssetType(assetType string) error {
+	if assetType == ticker.Spot || common.StringDataCompare(o.ContractTypes, assetType) {
+		return nil
+	}
+	return fmt.Errorf("%s %w %q", o.Name, assets.ErrInvalidAssetType, assetType)
+}
+
+// getFuturesContract returns the futures contract of a currency pair for a
+// contract type asset. Contracts are USD denominated, so pairs quoted in USD
+// or USDT map to the USD contract of their base currency
+func (o *OKEX) getFuturesContract(p pair.CurrencyPair, assetType string) (FuturesContract, error) {
+	contract := FuturesContract{
+		Symbol:       p.FirstCurrency.Lower().String() + "_usd",
+		ContractType: assetType,
+	}
+	err := o.CheckContractType(contract.ContractType)
 	if err != nil {
-		log.Errorf("OKEX failed to update available currencies. Err: %s", err)
-		return
+		return contract, fmt.Errorf("%s %w %q", o.Name, assets.ErrInvalidAssetType, assetType)
 	}
+
+	quote := p.SecondCurrency.Upper().String()
+	if (quote != "USD" && quote != "USDT") || o.CheckSymbol(contract.Symbol) != nil {
+		return contract, fmt.Errorf("%s has no %s futures contract for %s",
+			o.Name, assetType, p.Pair())
+	}
+	return contract, nil
+}
+
+// SupportsAsset returns whether the asset type is spot or a contract type
+func (o *OKEX) SupportsAsset(assetType string) bool {
+	return o.checkAssetType(assetType) == nil
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
 func (o *OKEX) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	return o.UpdateTickerWithContext(context.Background(), p, assetType)
+}
+
+// UpdateTickerWithContext updates and returns the ticker for a currency pair,
+// the request is aborted when ctx is cancelled
+func (o *OKEX) UpdateTickerWithContext(ctx context.Context, p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	currency := exchange.FormatExchangeCurrency(o.Name, p).String()
 	var tickerPrice ticker.Price
 
+	err := o.checkAssetType(assetType)
+	if err != nil {
+		return tickerPrice, err
+	}
+
 	if assetType != ticker.Spot {
-		tick, err := o.GetContractPrice(currency, assetType)
+		contract, err := o.getFuturesContract(p, assetType)
+		if err != nil {
+			return tickerPrice, err
+		}
+		tick, err := o.GetContractPrice(ctx, contract.Symbol, contract.ContractType)
 		if err != nil {
 			return tickerPrice, err
 		}
@@ -67,20 +208,31 @@ func (o *OKEX) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price
 		tickerPrice.Last = tick.Ticker.Last
 		tickerPrice.Volume = tick.Ticker.Vol
 		tickerPrice.High = tick.Ticker.High
-		ticker.ProcessTicker(o.GetName(), p, tickerPrice, assetType)
+		err = ticker.ProcessTicker(o.GetName(), p, tickerPrice, assetType)
+		if err != nil {
+			return tickerPrice, err
+		}
 	} else {
-		tick, err := o.GetSpotTicker(currency)
+		tick, err := o.GetSpotInstrumentTicker(ctx, p.FirstCurrency.Upper().String()+
+			"-"+p.SecondCurrency.Upper().String())
 		if err != nil {
 			return tickerPrice, err
 		}
 		tickerPrice.Pair = p
-		tickerPrice.Ask = tick.Ticker.Sell
-		tickerPrice.Bid = tick.Ticker.Buy
-		tickerPrice.Low = tick.Ticker.Low
-		tickerPrice.Last = tick.Ticker.Last
-		tickerPrice.Volume = tick.Ticker.Vol
-		tickerPrice.High = tick.Ticker.High
-		ticker.ProcessTicker(o.GetName(), p, tickerPrice, ticker.Spot)
+		tickerPrice.CurrencyPair = currency
+		tickerPrice.Ask = tick.BestAsk
+		tickerPrice.AskSize = tick.BestAskSize
+		tickerPrice.Bid = tick.BestBid
+		tickerPrice.BidSize = tick.BestBidSize
+		tickerPrice.Low = tick.Low24h
+		tickerPrice.Last = tick.Last
+		tickerPrice.Volume = tick.BaseVolume24h
+		tickerPrice.High = tick.High24h
+		tickerPrice.SetOpen(tick.Open24h)
+		err = ticker.ProcessTicker(o.GetName(), p, tickerPrice, ticker.Spot)
+		if err != nil {
+			return tickerPrice, err
+		}
 
 	}
 	return ticker.GetTicker(o.Name, p, assetType)
@@ -106,11 +258,27 @@ func (o *OKEX) GetOrderbookEx(currency pair.CurrencyPair, assetType string) (ord
 
 // UpdateOrderbook updates and returns the orderbook for a currency pair
 func (o *OKEX) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	return o.UpdateOrderbookWithContext(context.Background(), p, assetType)
+}
+
+// UpdateOrderbookWithContext updates and returns the orderbook for a currency
+// pair, the request is aborted when ctx is cancelled
+func (o *OKEX) UpdateOrderbookWithContext(ctx context.Context, p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
 	var orderBook orderbook.Base
 	currency := exchange.FormatExchangeCurrency(o.Name, p).String()
 
+	err := o.checkAssetType(assetType)
+	if err != nil {
+		return orderBook, err
+	}
+
 	if assetType != ticker.Spot {
-		orderbookNew, err := o.GetContractMarketDepth(currency, assetType)
+		contract, err := o.getFuturesContract(p, assetType)
+		if err != nil {
+			return orderBook, err
+		}
+		orderbookNew, err := o.GetContractMarketDepth(ctx, contract.Symbol,
+			contract.ContractType)
 		if err != nil {
 			return orderBook, err
 		}
@@ -126,7 +294,7 @@ func (o *OKEX) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook
 		}
 
 	} else {
-		orderbookNew, err := o.GetSpotMarketDepth(ActualSpotDepthRequestParams{
+		orderbookNew, err := o.GetSpotMarketDepth(ctx, ActualSpotDepthRequestParams{
 			Symbol: currency,
 			Size:   200,
 		})
@@ -145,6 +313,7 @@ func (o *OKEX) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook
 		}
 	}
 
+	orderBook.Source = orderbook.SourceREST
 	orderbook.ProcessOrderbook(o.GetName(), p, orderBook, assetType)
 	return orderbook.GetOrderbook(o.Name, p, assetType)
 }
@@ -153,28 +322,73 @@ func (o *OKEX) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook
 // OKEX exchange
 func (o *OKEX) GetAccountInfo() (exchange.AccountInfo, error) {
 	var info exchange.AccountInfo
-	bal, err := o.GetBalance()
+	bal, err := o.GetBalance(context.Background())
 	if err != nil {
 		return info, err
 	}
 
 	var balances []exchange.AccountCurrencyInfo
 	for _, data := range bal {
+		available := data.Available
 		balances = append(balances, exchange.AccountCurrencyInfo{
-			CurrencyName: data.Currency,
-			TotalValue:   data.Available + data.Hold,
-			Hold:         data.Hold,
+			CurrencyName:   data.Currency,
+			TotalValue:     data.Available + data.Hold,
+			Hold:           data.Hold,
+			AvailableValue: &available,
 		})
 	}
 
 	info.Exchange = o.GetName()
 	info.Accounts = append(info.Accounts, exchange.Account{
+		Type:       exchange.AccountTypeSpot,
 		Currencies: balances,
 	})
 
+	if !o.futuresEnabled() {
+		return info, nil
+	}
+	futures, err := o.GetContractUserInfo(context.Background())
+	if err != nil {
+		return info, err
+	}
+	info.Accounts = append(info.Accounts, convertContractUserInfo(futures))
 	return info, nil
 }
 
+// futuresEnabled returns whether a futures contract asset type is enabled
+func (o *OKEX) futuresEnabled() bool {
+	assetTypes := o.GetAssetTypes()
+	for x := range assetTypes {
+		if common.StringDataCompare(o.ContractTypes, assetTypes[x]) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertContractUserInfo returns the futures account of the cross-margin
+// futures balances, the value held as margin is reported as on hold
+func convertContractUserInfo(info ContractUserInfo) exchange.Account {
+	account := exchange.Account{Type: exchange.AccountTypeFutures}
+	for currency, data := range info.Info {
+		available := data.AccountRights - data.KeepDeposit
+		margin := data.AccountRights
+		unrealisedPNL := data.ProfitUnreal
+		account.Currencies = append(account.Currencies, exchange.AccountCurrencyInfo{
+			CurrencyName:   currency,
+			TotalValue:     data.AccountRights,
+			Hold:           data.KeepDeposit,
+			AvailableValue: &available,
+			MarginBalance:  &margin,
+			UnrealisedPNL:  &unrealisedPNL,
+		})
+	}
+	sort.Slice(account.Currencies, func(i, j int) bool {
+		return account.Currencies[i].CurrencyName < account.Currencies[j].CurrencyName
+	})
+	return account
+}
+
 // GetFundingHistory returns funding history, deposits and
 // withdrawals
 func (o *OKEX) GetFundingHistory() ([]exchange.FundHistory, error) {
@@ -183,25 +397,67 @@ func (o *OKEX) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (o *OKEX) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (o *OKEX) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
-// SubmitOrder submits a new order
-func (o *OKEX) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+// GetHistoricCandles returns a batch of candles of the supplied interval from
+// the start time onwards
+func (o *OKEX) GetHistoricCandles(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]exchange.Candle, error) {
+	err := o.checkAssetType(assetType)
+	if err != nil {
+		return nil, err
+	}
+	timeInterval, err := IntervalToTimeInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	since := timestampStart.UnixNano() / int64(time.Millisecond)
+	if assetType != ticker.Spot {
+		contract, err := o.getFuturesContract(p, assetType)
+		if err != nil {
+			return nil, err
+		}
+		return o.GetContractCandlestickData(context.Background(), contract.Symbol, timeInterval,
+			contract.ContractType, okexKlineBatchSize, int(since))
+	}
+	return o.GetSpotKline(context.Background(), KlinesRequestParams{
+		Symbol: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		Type:   timeInterval,
+		Size:   okexKlineBatchSize,
+		Since:  since,
+	})
+}
+
+// SubmitOrder submits a new order, orders with a contract type asset type are
+// placed on the futures contract of the currency pair
+func (o *OKEX) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(o.Name, exchange.OrderOptions{
+		ReduceOnly: order.AssetType != "" && order.AssetType != ticker.Spot,
+		AssetTypes: o.ContractTypes,
+	})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	if order.AssetType != "" && order.AssetType != ticker.Spot {
+		return o.submitFuturesOrder(order)
+	}
+
 	var oT SpotNewOrderRequestType
 
-	if orderType == exchange.Limit {
-		if side == exchange.Buy {
+	if order.OrderType == exchange.Limit {
+		if order.OrderSide == exchange.Buy {
 			oT = SpotNewOrderRequestTypeBuy
 		} else {
 			oT = SpotNewOrderRequestTypeSell
 		}
-	} else if orderType == exchange.Market {
-		if side == exchange.Buy {
+	} else if order.OrderType == exchange.Market {
+		if order.OrderSide == exchange.Buy {
 			oT = SpotNewOrderRequestTypeBuyMarket
 		} else {
 			oT = SpotNewOrderRequestTypeSellMarket
@@ -210,17 +466,27 @@ func (o *OKEX) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderTy
 		return submitOrderResponse, errors.New("Unsupported order type")
 	}
 
+	err = o.CheckPairTradable(order.Pair)
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	amount, price, err := o.CheckOrderExecutionLimits(order.Pair, order.Amount, order.Price, order.OrderType)
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var params = SpotNewOrderRequestParams{
 		Amount: amount,
 		Price:  price,
-		Symbol: p.Pair().String(),
+		Symbol: order.Pair.Pair().String(),
 		Type:   oT,
 	}
 
-	response, err := o.SpotNewOrder(params)
+	response, err := o.SpotNewOrder(context.Background(), params)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -230,10 +496,142 @@ func (o *OKEX) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderTy
 	return submitOrderResponse, err
 }
 
-// ModifyOrder will allow of changing orderbook placement and limit to
-// market conversion
+// submitFuturesOrder places an order on a futures contract. The amount is the
+// number of contracts, buy orders open long positions and sell orders open
+// short positions unless the order is reduce only, which closes the opposite
+// position instead. Market orders are placed at the best price. The order
+// leverage must be set to 10 or 20
+func (o *OKEX) submitFuturesOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
+	var submitOrderResponse exchange.SubmitOrderResponse
+	contract, err := o.getFuturesContract(order.Pair, order.AssetType)
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	if order.Leverage == 0 {
+		return submitOrderResponse, exchange.ErrLeverageRequired
+	}
+	if order.Leverage != 10 && order.Leverage != 20 {
+		return submitOrderResponse, fmt.Errorf("%w: leverage can only be 10 or 20",
+			exchange.ErrOrderOptionNotSupported)
+	}
+
+	if order.OrderType != exchange.Limit && order.OrderType != exchange.Market {
+		return submitOrderResponse, errors.New("Unsupported order type")
+	}
+
+	// 1 opens a long, 2 opens a short, 3 closes a long and 4 closes a short
+	var position string
+	switch {
+	case order.OrderSide == exchange.Buy && !order.ReduceOnly:
+		position = "1"
+	case order.OrderSide == exchange.Sell && !order.ReduceOnly:
+		position = "2"
+	case order.OrderSide == exchange.Sell:
+		position = "3"
+	default:
+		position = "4"
+	}
+
+	response, err := o.PlaceContractOrders(context.Background(), contract.Symbol, contract.ContractType,
+		position, int(order.Leverage), order.Price, order.Amount,
+		order.OrderType == exchange.Market)
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	submitOrderResponse.OrderID = exchange.FormatFloatOrderID(response)
+	submitOrderResponse.IsOrderPlaced = true
+	return submitOrderResponse, nil
+}
+
+// ModifyOrder changes the price or amount of an open spot limit order and
+// returns the ID of the replacement order. OKEX can't amend orders, so the
+// order is cancelled and replaced, which must be allowed with CancelReplace.
+// Zero prices and amounts keep the order's values and the amount includes any
+// filled quantity, the replacement is placed for the unfilled remainder. No
+// replacement is submitted if the cancel fails or the order fills first
 func (o *OKEX) ModifyOrder(action exchange.ModifyOrder) (string, error) {
-	return "", common.ErrFunctionNotSupported
+	if !action.CancelReplace {
+		return "", exchange.WrapError(exchange.ErrCancelReplaceRequired,
+			"%s order modification cancels and replaces the order, set CancelReplace to allow it",
+			o.Name)
+	}
+
+	orderID, err := strconv.ParseInt(action.OrderID, 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	symbol := exchange.FormatExchangeCurrency(o.Name, action.Currency).String()
+	order, err := o.GetSpotOrder(context.Background(), symbol, orderID)
+	if err != nil {
+		return "", err
+	}
+	if order.Status != SpotOrderUnfilled && order.Status != SpotOrderPartiallyFilled {
+		return "", exchange.WrapError(exchange.ErrOrderNotOpen,
+			"%s order %s is no longer open", o.Name, action.OrderID)
+	}
+
+	orderType := SpotNewOrderRequestType(order.Type)
+	if orderType != SpotNewOrderRequestTypeBuy && orderType != SpotNewOrderRequestTypeSell {
+		return "", fmt.Errorf("%s order %s is a %s order, only limit orders can be modified",
+			o.Name, action.OrderID, order.Type)
+	}
+
+	price, amount := order.Price, order.Amount
+	if action.Price > 0 {
+		price = action.Price
+	}
+	if action.Amount > 0 {
+		amount = action.Amount
+	}
+	if amount <= order.DealAmount {
+		return "", fmt.Errorf("%s order %s amount %v must exceed the filled amount %v",
+			o.Name, action.OrderID, amount, order.DealAmount)
+	}
+
+	_, err = o.SpotCancelOrder(context.Background(), symbol, orderID)
+	if err != nil {
+		return "", fmt.Errorf("%s order %s left unchanged, unable to cancel it: %w",
+			o.Name, action.OrderID, err)
+	}
+
+	// The order can fill between being fetched and cancelled, so the
+	// replacement is sized from its filled amount once cancelled
+	order, err = o.GetSpotOrder(context.Background(), symbol, orderID)
+	if err != nil {
+		return "", fmt.Errorf("%s order %s cancelled but not replaced, unable to confirm its filled amount: %w",
+			o.Name, action.OrderID, err)
+	}
+	if order.Status == SpotOrderFilled || amount <= order.DealAmount {
+		return "", exchange.WrapError(exchange.ErrOrderNotOpen,
+			"%s order %s filled before it was cancelled, no replacement submitted",
+			o.Name, action.OrderID)
+	}
+	if order.Status != SpotOrderCancelled {
+		return "", fmt.Errorf("%s order %s not replaced, its cancellation is unconfirmed with status %d",
+			o.Name, action.OrderID, order.Status)
+	}
+
+	remaining, price, err := o.CheckOrderExecutionLimits(action.Currency,
+		amount-order.DealAmount, price, exchange.Limit)
+	if err != nil {
+		return "", fmt.Errorf("%s order %s cancelled but not replaced: %w",
+			o.Name, action.OrderID, err)
+	}
+
+	replacementID, err := o.SpotNewOrder(context.Background(), SpotNewOrderRequestParams{
+		Amount: remaining,
+		Price:  price,
+		Symbol: symbol,
+		Type:   orderType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s order %s cancelled but its replacement failed: %w",
+			o.Name, action.OrderID, err)
+	}
+	return strconv.FormatInt(replacementID, 10), nil
 }
 
 // CancelOrder cancels an order by its corresponding ID number
@@ -243,7 +641,16 @@ func (o *OKEX) CancelOrder(order exchange.OrderCancellation) error {
 		return err
 	}
 
-	_, err = o.SpotCancelOrder(exchange.FormatExchangeCurrency(o.Name, order.CurrencyPair).String(), orderIDInt)
+	if order.AssetType != "" && order.AssetType != ticker.Spot {
+		contract, err := o.getFuturesContract(order.CurrencyPair, order.AssetType)
+		if err != nil {
+			return err
+		}
+		return o.CancelContractOrder(context.Background(), contract.Symbol, contract.ContractType,
+			orderIDInt)
+	}
+
+	_, err = o.SpotCancelOrder(context.Background(), exchange.FormatExchangeCurrency(o.Name, order.CurrencyPair).String(), orderIDInt)
 	return err
 }
 
@@ -255,7 +662,7 @@ func (o *OKEX) CancelAllOrders(orderCancellation exchange.OrderCancellation) (ex
 	var allOpenOrders []TokenOrder
 	for _, currency := range o.GetEnabledCurrencies() {
 		formattedCurrency := exchange.FormatExchangeCurrency(o.Name, currency).String()
-		openOrders, err := o.GetTokenOrders(formattedCurrency, -1)
+		openOrders, err := o.GetTokenOrders(context.Background(), formattedCurrency, -1)
 		if err != nil {
 			return cancelAllOrdersResponse, err
 		}
@@ -268,7 +675,7 @@ func (o *OKEX) CancelAllOrders(orderCancellation exchange.OrderCancellation) (ex
 	}
 
 	for _, openOrder := range allOpenOrders {
-		_, err := o.SpotCancelOrder(openOrder.Symbol, openOrder.OrderID)
+		_, err := o.SpotCancelOrder(context.Background(), openOrder.Symbol, openOrder.OrderID)
 		if err != nil {
 			cancelAllOrdersResponse.OrderStatus[strconv.FormatInt(openOrder.OrderID, 10)] = err.Error()
 		}
@@ -278,13 +685,13 @@ func (o *OKEX) CancelAllOrders(orderCancellation exchange.OrderCancellation) (ex
 }
 
 // GetOrderInfo returns information on a current open order
-func (o *OKEX) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (o *OKEX) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (o *OKEX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (o *OKEX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	// NOTE needs API version update to access
 	return "", common.ErrNotYetImplemented
 }
@@ -292,7 +699,16 @@ func (o *OKEX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID str
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
 // submitted
 func (o *OKEX) WithdrawCryptocurrencyFunds(withdrawRequest exchange.WithdrawRequest) (string, error) {
-	resp, err := o.Withdrawal(withdrawRequest.Currency.String(), withdrawRequest.FeeAmount, withdrawRequest.TradePassword, withdrawRequest.Address, withdrawRequest.Amount)
+	details, err := o.GetCurrencyDetails()
+	if err != nil {
+		return "", err
+	}
+	err = exchange.CheckWithdrawal(details, withdrawRequest)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.Withdrawal(context.Background(), withdrawRequest.Currency.String(), withdrawRequest.FeeAmount, withdrawRequest.TradePassword, withdrawRequest.Address, withdrawRequest.Amount)
 	return fmt.Sprintf("%v", resp), err
 }
 
@@ -318,6 +734,20 @@ func (o *OKEX) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
 	return o.GetFee(feeBuilder)
 }
 
+// GetCurrencyDetails returns the currency details, refreshed from the API when
+// authenticated API support is enabled and otherwise built from the
+// predefined withdrawal fees
+func (o *OKEX) GetCurrencyDetails() ([]exchange.CurrencyDetails, error) {
+	if o.AuthenticatedAPISupport {
+		details, err := o.FetchCurrencyDetails(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		o.SetCurrencyDetails(details)
+	}
+	return o.Base.GetCurrencyDetails()
+}
+
 // GetWithdrawCapabilities returns the types of withdrawal methods permitted by the exchange
 func (o *OKEX) GetWithdrawCapabilities() uint32 {
 	return o.GetWithdrawPermissions()
@@ -2,6 +2,7 @@ package orderbook
 
 import (
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,14 +14,27 @@ const (
 	ErrOrderbookForExchangeNotFound = "Ticker for exchange does not exist."
 	ErrPrimaryCurrencyNotFound      = "Error primary currency for orderbook not found."
 	ErrSecondaryCurrencyNotFound    = "Error secondary currency for orderbook not found."
+	ErrAssetTypeNotFound            = "Error asset type for orderbook not found."
 
 	Spot = "SPOT"
 )
 
+// Orderbook sources
+const (
+	SourceREST      = "rest"
+	SourceWebsocket = "websocket"
+)
+
 // Vars for the orderbook package
 var (
 	Orderbooks []Orderbook
 	m          sync.Mutex
+
+	// ErrInsufficientDepth is returned when the orderbook side can't fill the
+	// requested amount
+	ErrInsufficientDepth = errors.New("insufficient orderbook depth")
+	// ErrNoLiquidity is returned when an orderbook side has no orders
+	ErrNoLiquidity = errors.New("orderbook has no bids or asks")
 )
 
 // Item stores the amount and price values
@@ -30,14 +44,18 @@ type Item struct {
 	ID     int64
 }
 
-// Base holds the fields for the orderbook base
+// Base holds the fields for the orderbook base. LastUpdated is when the
+// orderbook was received, ExchangeTimestamp is the time reported by the
+// exchange when it supplies one and Source is how the orderbook was fetched
 type Base struct {
-	Pair         pair.CurrencyPair `json:"pair"`
-	CurrencyPair string            `json:"CurrencyPair"`
-	Bids         []Item            `json:"bids"`
-	Asks         []Item            `json:"asks"`
-	LastUpdated  time.Time         `json:"last_updated"`
-	AssetType    string
+	Pair              pair.CurrencyPair `json:"pair"`
+	CurrencyPair      string            `json:"CurrencyPair"`
+	Bids              []Item            `json:"bids"`
+	Asks              []Item            `json:"asks"`
+	LastUpdated       time.Time         `json:"last_updated"`
+	ExchangeTimestamp time.Time         `json:"exchange_timestamp,omitempty"`
+	Source            string            `json:"source,omitempty"`
+	AssetType         string
 }
 
 // Orderbook holds the orderbook information for a currency pair and type
@@ -70,6 +88,100 @@ func (o *Base) CalculateTotalAsks() (float64, float64) {
 	return amountCollated, total
 }
 
+// MidPrice returns the price halfway between the best bid and the best ask
+func (o *Base) MidPrice() (float64, error) {
+	bids := sortedItems(o.Bids, true)
+	asks := sortedItems(o.Asks, false)
+	if len(bids) == 0 || len(asks) == 0 {
+		return 0, ErrNoLiquidity
+	}
+	return (bids[0].Price + asks[0].Price) / 2, nil
+}
+
+// TotalWithinPercent returns the total amount of the bids, or the asks, priced
+// within the supplied percentage of the mid price
+func (o *Base) TotalWithinPercent(percent float64, bids bool) (float64, error) {
+	if !(percent > 0) {
+		return 0, errors.New("percent must be greater than zero")
+	}
+
+	mid, err := o.MidPrice()
+	if err != nil {
+		return 0, err
+	}
+
+	items := sortedItems(o.Bids, true)
+	limit := mid * (1 - percent/100)
+	if !bids {
+		items = sortedItems(o.Asks, false)
+		limit = mid * (1 + percent/100)
+	}
+
+	var total float64
+	for x := range items {
+		if (bids && items[x].Price < limit) || (!bids && items[x].Price > limit) {
+			break
+		}
+		total += items[x].Amount
+	}
+	return total, nil
+}
+
+// WeightedAveragePrice walks the asks for a buy, or the bids for a sell, from
+// the best price and returns the volume weighted average price and amount
+// filled for the supplied amount. When the book can't fill the whole amount
+// the average price and amount of the partial fill are returned along with
+// ErrInsufficientDepth
+func (o *Base) WeightedAveragePrice(amount float64, buy bool) (float64, float64, error) {
+	if !(amount > 0) {
+		return 0, 0, errors.New("amount must be greater than zero")
+	}
+
+	items := sortedItems(o.Bids, true)
+	if buy {
+		items = sortedItems(o.Asks, false)
+	}
+	if len(items) == 0 {
+		return 0, 0, ErrNoLiquidity
+	}
+
+	var filled, total float64
+	for x := range items {
+		fill := items[x].Amount
+		if remaining := amount - filled; fill > remaining {
+			fill = remaining
+		}
+		filled += fill
+		total += fill * items[x].Price
+		if filled >= amount {
+			return total / filled, filled, nil
+		}
+	}
+
+	if filled == 0 {
+		return 0, 0, ErrInsufficientDepth
+	}
+	return total / filled, filled, ErrInsufficientDepth
+}
+
+// sortedItems returns a copy of items ordered from the best price, highest
+// first for bids and lowest first for asks. Empty levels are dropped
+func sortedItems(items []Item, bids bool) []Item {
+	sorted := make([]Item, 0, len(items))
+	for x := range items {
+		if items[x].Amount > 0 && items[x].Price > 0 {
+			sorted = append(sorted, items[x])
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if bids {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+	return sorted
+}
+
 // Update updates the bids and asks
 func (o *Base) Update(Bids, Asks []Item) {
 	o.Bids = Bids
@@ -93,7 +205,13 @@ func GetOrderbook(exchange string, p pair.CurrencyPair, orderbookType string) (B
 		return Base{}, errors.New(ErrSecondaryCurrencyNotFound)
 	}
 
-	return orderbook.Orderbook[p.FirstCurrency][p.SecondCurrency][orderbookType], nil
+	m.Lock()
+	defer m.Unlock()
+	base, ok := orderbook.Orderbook[p.FirstCurrency][p.SecondCurrency][orderbookType]
+	if !ok {
+		return Base{}, errors.New(ErrAssetTypeNotFound)
+	}
+	return base, nil
 }
 
 // GetOrderbookByExchange returns an exchange orderbook
@@ -144,6 +262,12 @@ func SecondCurrencyExists(exchange string, p pair.CurrencyPair) bool {
 func CreateNewOrderbook(exchangeName string, p pair.CurrencyPair, orderbookNew Base, orderbookType string) Orderbook {
 	m.Lock()
 	defer m.Unlock()
+	return createNewOrderbook(exchangeName, p, orderbookNew, orderbookType)
+}
+
+// createNewOrderbook creates a new orderbook and appends it to the Orderbooks
+// list, the caller must hold the orderbook lock
+func createNewOrderbook(exchangeName string, p pair.CurrencyPair, orderbookNew Base, orderbookType string) Orderbook {
 	orderbook := Orderbook{}
 	orderbook.ExchangeName = exchangeName
 	orderbook.Orderbook = make(map[pair.CurrencyItem]map[pair.CurrencyItem]map[string]Base)
@@ -157,35 +281,137 @@ func CreateNewOrderbook(exchangeName string, p pair.CurrencyPair, orderbookNew B
 }
 
 // ProcessOrderbook processes incoming orderbooks, creating or updating the
-// Orderbook list
+// Orderbook list. LastUpdated is set to the current time unless the caller
+// provided one. The update is made under a single lock so it can't race with
+// the removal of the orderbook
 func ProcessOrderbook(exchangeName string, p pair.CurrencyPair, orderbookNew Base, orderbookType string) {
 	if orderbookNew.Pair.Pair() == "" {
 		// set Pair if not set
 		orderbookNew.Pair = p
 	}
 	orderbookNew.CurrencyPair = p.Pair().String()
-	orderbookNew.LastUpdated = time.Now()
-
-	orderbook, err := GetOrderbookByExchange(exchangeName)
-	if err != nil {
-		CreateNewOrderbook(exchangeName, p, orderbookNew, orderbookType)
-		return
+	if orderbookNew.LastUpdated.IsZero() {
+		orderbookNew.LastUpdated = time.Now()
 	}
 
-	if FirstCurrencyExists(exchangeName, p.FirstCurrency) {
-		m.Lock()
-		a := make(map[string]Base)
+	m.Lock()
+	defer m.Unlock()
+	for x := range Orderbooks {
+		if Orderbooks[x].ExchangeName != exchangeName {
+			continue
+		}
+		second, ok := Orderbooks[x].Orderbook[p.FirstCurrency]
+		if !ok {
+			second = make(map[pair.CurrencyItem]map[string]Base)
+			Orderbooks[x].Orderbook[p.FirstCurrency] = second
+		}
+		a, ok := second[p.SecondCurrency]
+		if !ok {
+			a = make(map[string]Base)
+			second[p.SecondCurrency] = a
+		}
 		a[orderbookType] = orderbookNew
-		orderbook.Orderbook[p.FirstCurrency][p.SecondCurrency] = a
-		m.Unlock()
 		return
 	}
+	createNewOrderbook(exchangeName, p, orderbookNew, orderbookType)
+}
 
+// RemoveOrderbook removes the stored orderbook of an exchange currency pair
+// and asset type and returns whether it existed. Exchanges left without
+// orderbooks are removed from the Orderbooks list
+func RemoveOrderbook(exchangeName string, p pair.CurrencyPair, orderbookType string) bool {
 	m.Lock()
-	a := make(map[pair.CurrencyItem]map[string]Base)
-	b := make(map[string]Base)
-	b[orderbookType] = orderbookNew
-	a[p.SecondCurrency] = b
-	orderbook.Orderbook[p.FirstCurrency] = a
-	m.Unlock()
+	defer m.Unlock()
+	for x := range Orderbooks {
+		if Orderbooks[x].ExchangeName != exchangeName {
+			continue
+		}
+		second := Orderbooks[x].Orderbook[p.FirstCurrency]
+		if _, ok := second[p.SecondCurrency][orderbookType]; !ok {
+			return false
+		}
+		delete(second[p.SecondCurrency], orderbookType)
+		if len(second[p.SecondCurrency]) == 0 {
+			delete(second, p.SecondCurrency)
+		}
+		if len(second) == 0 {
+			delete(Orderbooks[x].Orderbook, p.FirstCurrency)
+		}
+		if len(Orderbooks[x].Orderbook) == 0 {
+			Orderbooks = append(Orderbooks[:x], Orderbooks[x+1:]...)
+		}
+		return true
+	}
+	return false
+}
+
+// RemoveExchangeOrderbooks removes all stored orderbooks of an exchange and
+// returns the number removed
+func RemoveExchangeOrderbooks(exchangeName string) int {
+	m.Lock()
+	defer m.Unlock()
+	for x := range Orderbooks {
+		if Orderbooks[x].ExchangeName == exchangeName {
+			removed := countBases(Orderbooks[x])
+			Orderbooks = append(Orderbooks[:x], Orderbooks[x+1:]...)
+			return removed
+		}
+	}
+	return 0
+}
+
+// RemoveStaleOrderbooks removes the stored orderbooks which haven't been
+// updated within the max age and returns the number removed
+func RemoveStaleOrderbooks(maxAge time.Duration) int {
+	cutoff := time.Now().Add(-maxAge)
+	m.Lock()
+	defer m.Unlock()
+
+	var removed int
+	kept := Orderbooks[:0]
+	for x := range Orderbooks {
+		for first, second := range Orderbooks[x].Orderbook {
+			for secondCurrency, assets := range second {
+				for orderbookType, base := range assets {
+					if base.LastUpdated.Before(cutoff) {
+						delete(assets, orderbookType)
+						removed++
+					}
+				}
+				if len(assets) == 0 {
+					delete(second, secondCurrency)
+				}
+			}
+			if len(second) == 0 {
+				delete(Orderbooks[x].Orderbook, first)
+			}
+		}
+		if len(Orderbooks[x].Orderbook) > 0 {
+			kept = append(kept, Orderbooks[x])
+		}
+	}
+	Orderbooks = kept
+	return removed
+}
+
+// GetOrderbookCounts returns the number of stored orderbooks of each exchange
+func GetOrderbookCounts() map[string]int {
+	m.Lock()
+	defer m.Unlock()
+	counts := make(map[string]int, len(Orderbooks))
+	for x := range Orderbooks {
+		counts[Orderbooks[x].ExchangeName] = countBases(Orderbooks[x])
+	}
+	return counts
+}
+
+// countBases returns the number of orderbooks stored in an Orderbook
+func countBases(o Orderbook) int {
+	var count int
+	for _, second := range o.Orderbook {
+		for _, assets := range second {
+			count += len(assets)
+		}
+	}
+	return count
 }
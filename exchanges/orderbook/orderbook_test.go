@@ -1,6 +1,7 @@
 package orderbook
 
 import (
+	"math"
 	"math/rand"
 	"strconv"
 	"sync"
@@ -42,6 +43,96 @@ func TestCalculateTotaAsks(t *testing.T) {
 	}
 }
 
+func TestMidPrice(t *testing.T) {
+	t.Parallel()
+	base := Base{
+		Bids: []Item{{Price: 99, Amount: 1}, {Price: 100, Amount: 1}},
+		Asks: []Item{{Price: 103, Amount: 1}, {Price: 102, Amount: 1}},
+	}
+
+	mid, err := base.MidPrice()
+	if err != nil || mid != 101 {
+		t.Fatalf("Test failed. TestMidPrice expected 101, got %v %v", mid, err)
+	}
+
+	base.Asks = nil
+	_, err = base.MidPrice()
+	if err != ErrNoLiquidity {
+		t.Fatalf("Test failed. TestMidPrice expected %v, got %v", ErrNoLiquidity, err)
+	}
+}
+
+func TestTotalWithinPercent(t *testing.T) {
+	t.Parallel()
+	base := Base{
+		Bids: []Item{{Price: 99, Amount: 5}, {Price: 99.6, Amount: 2}, {Price: 99.7, Amount: 3}},
+		Asks: []Item{{Price: 100.6, Amount: 4}, {Price: 100.3, Amount: 1}, {Price: 100.4, Amount: 2}},
+	}
+
+	total, err := base.TotalWithinPercent(0.5, true)
+	if err != nil || total != 5 {
+		t.Errorf("Test failed. TestTotalWithinPercent expected bids 5, got %v %v", total, err)
+	}
+	total, err = base.TotalWithinPercent(0.5, false)
+	if err != nil || total != 3 {
+		t.Errorf("Test failed. TestTotalWithinPercent expected asks 3, got %v %v", total, err)
+	}
+	total, err = base.TotalWithinPercent(2, true)
+	if err != nil || total != 10 {
+		t.Errorf("Test failed. TestTotalWithinPercent expected bids 10, got %v %v", total, err)
+	}
+
+	if _, err = base.TotalWithinPercent(0, true); err == nil {
+		t.Error("Test failed. TestTotalWithinPercent accepted a zero percent")
+	}
+
+	base.Asks = nil
+	if _, err = base.TotalWithinPercent(0.5, true); err != ErrNoLiquidity {
+		t.Errorf("Test failed. TestTotalWithinPercent expected %v, got %v", ErrNoLiquidity, err)
+	}
+}
+
+func TestWeightedAveragePrice(t *testing.T) {
+	t.Parallel()
+	base := Base{
+		Bids: []Item{{Price: 98, Amount: 2}, {Price: 99, Amount: 1}},
+		Asks: []Item{{Price: 102, Amount: 2}, {Price: 101, Amount: 1}},
+	}
+
+	tests := []struct {
+		amount float64
+		buy    bool
+		price  float64
+		filled float64
+		err    error
+	}{
+		{1, true, 101, 1, nil},
+		{2, true, 101.5, 2, nil},
+		{3, false, 295.0 / 3, 3, nil},
+		{4, true, 305.0 / 3, 3, ErrInsufficientDepth},
+		{0, true, 0, 0, nil},
+	}
+	for x := range tests {
+		price, filled, err := base.WeightedAveragePrice(tests[x].amount, tests[x].buy)
+		if tests[x].amount == 0 {
+			if err == nil {
+				t.Error("Test failed. TestWeightedAveragePrice expected error for zero amount")
+			}
+			continue
+		}
+		if err != tests[x].err || filled != tests[x].filled ||
+			math.Abs(price-tests[x].price) > 1e-9 {
+			t.Errorf("Test failed. TestWeightedAveragePrice %d expected %v %v %v, got %v %v %v",
+				x, tests[x].price, tests[x].filled, tests[x].err, price, filled, err)
+		}
+	}
+
+	_, _, err := (&Base{}).WeightedAveragePrice(1, false)
+	if err != ErrNoLiquidity {
+		t.Errorf("Test failed. TestWeightedAveragePrice expected %v, got %v", ErrNoLiquidity, err)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	t.Parallel()
 	currency := pair.NewCurrencyPair("BTC", "USD")
@@ -111,6 +202,19 @@ func TestGetOrderbook(t *testing.T) {
 	if err == nil {
 		t.Fatal("Test failed. TestGetOrderbook retrieved non-existent orderbook using invalid second currency")
 	}
+
+	currency = pair.NewCurrencyPair("BTC", "USD")
+	ProcessOrderbook("Exchange", currency, Base{Asks: []Item{{Price: 1, Amount: 1}}},
+		"futures_3m")
+	result, err = GetOrderbook("Exchange", currency, Spot)
+	if err != nil || len(result.Asks) != 1 || result.Asks[0].Price != 100 {
+		t.Fatal("Test failed. TestGetOrderbook orderbook of another asset type replaced the spot orderbook")
+	}
+
+	_, err = GetOrderbook("Exchange", currency, "futures_1w")
+	if err == nil {
+		t.Fatal("Test failed. TestGetOrderbook retrieved non-existent orderbook using invalid asset type")
+	}
 }
 
 func TestGetOrderbookByExchange(t *testing.T) {
@@ -328,3 +432,152 @@ func TestProcessOrderbook(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestProcessOrderbookLastUpdated(t *testing.T) {
+	Orderbooks = []Orderbook{}
+	currency := pair.NewCurrencyPair("BTC", "USD")
+	ProcessOrderbook("Exchange", currency, Base{Source: SourceREST}, Spot)
+	result, err := GetOrderbook("Exchange", currency, Spot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.LastUpdated.IsZero() || time.Since(result.LastUpdated) > time.Minute ||
+		result.Source != SourceREST {
+		t.Errorf("Test failed. Expected a stamped rest orderbook, got %v %s",
+			result.LastUpdated, result.Source)
+	}
+
+	received := time.Now().Add(-time.Second)
+	ProcessOrderbook("Exchange", currency, Base{LastUpdated: received}, Spot)
+	result, err = GetOrderbook("Exchange", currency, Spot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.LastUpdated.Equal(received) {
+		t.Errorf("Test failed. Expected the provided LastUpdated %v, got %v", received,
+			result.LastUpdated)
+	}
+}
+
+func TestRemoveOrderbook(t *testing.T) {
+	Orderbooks = []Orderbook{}
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	btcaud := pair.NewCurrencyPair("BTC", "AUD")
+	ProcessOrderbook("btcc", btcusd, Base{}, Spot)
+	ProcessOrderbook("btcc", btcaud, Base{}, Spot)
+
+	if RemoveOrderbook("btcc", btcusd, "futures") {
+		t.Error("Test failed. RemoveOrderbook removed a missing asset type")
+	}
+	if !RemoveOrderbook("btcc", btcusd, Spot) {
+		t.Error("Test failed. RemoveOrderbook failed to remove a orderbook")
+	}
+	if SecondCurrencyExists("btcc", btcusd) {
+		t.Error("Test failed. RemoveOrderbook left the removed pair")
+	}
+	if _, err := GetOrderbook("btcc", btcaud, Spot); err != nil {
+		t.Errorf("Test failed. RemoveOrderbook removed another pair: %s", err)
+	}
+
+	if !RemoveOrderbook("btcc", btcaud, Spot) {
+		t.Error("Test failed. RemoveOrderbook failed to remove a orderbook")
+	}
+	if len(Orderbooks) != 0 {
+		t.Errorf("Test failed. Expected the empty exchange to be removed, got %d", len(Orderbooks))
+	}
+	if RemoveOrderbook("btcc", btcaud, Spot) {
+		t.Error("Test failed. RemoveOrderbook removed a missing exchange orderbook")
+	}
+}
+
+func TestRemoveExchangeOrderbooks(t *testing.T) {
+	Orderbooks = []Orderbook{}
+	ProcessOrderbook("btcc", pair.NewCurrencyPair("BTC", "USD"), Base{}, Spot)
+	ProcessOrderbook("btcc", pair.NewCurrencyPair("LTC", "USD"), Base{}, Spot)
+	ProcessOrderbook("anx", pair.NewCurrencyPair("BTC", "USD"), Base{}, Spot)
+
+	if removed := RemoveExchangeOrderbooks("btcc"); removed != 2 {
+		t.Errorf("Test failed. Expected 2 orderbooks removed, got %d", removed)
+	}
+	if removed := RemoveExchangeOrderbooks("btcc"); removed != 0 {
+		t.Errorf("Test failed. Expected no orderbooks removed, got %d", removed)
+	}
+	counts := GetOrderbookCounts()
+	if len(counts) != 1 || counts["anx"] != 1 {
+		t.Errorf("Test failed. Unexpected orderbook counts %v", counts)
+	}
+}
+
+func TestRemoveStaleOrderbooks(t *testing.T) {
+	Orderbooks = []Orderbook{}
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ltcusd := pair.NewCurrencyPair("LTC", "USD")
+	ProcessOrderbook("btcc", btcusd, Base{}, Spot)
+	ProcessOrderbook("btcc", ltcusd, Base{}, Spot)
+	ProcessOrderbook("anx", btcusd, Base{}, Spot)
+
+	m.Lock()
+	for x := range Orderbooks {
+		if Orderbooks[x].ExchangeName == "anx" {
+			base := Orderbooks[x].Orderbook[btcusd.FirstCurrency][btcusd.SecondCurrency][Spot]
+			base.LastUpdated = time.Now().Add(-time.Hour)
+			Orderbooks[x].Orderbook[btcusd.FirstCurrency][btcusd.SecondCurrency][Spot] = base
+		}
+	}
+	m.Unlock()
+
+	if removed := RemoveStaleOrderbooks(time.Minute); removed != 1 {
+		t.Errorf("Test failed. Expected 1 stale orderbook removed, got %d", removed)
+	}
+	counts := GetOrderbookCounts()
+	if len(counts) != 1 || counts["btcc"] != 2 {
+		t.Errorf("Test failed. Unexpected orderbook counts %v", counts)
+	}
+}
+
+func TestRemoveOrderbookRace(t *testing.T) {
+	Orderbooks = []Orderbook{}
+	pairs := []pair.CurrencyPair{
+		pair.NewCurrencyPair("BTC", "USD"),
+		pair.NewCurrencyPair("BTC", "AUD"),
+		pair.NewCurrencyPair("LTC", "USD"),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(4)
+		p := pairs[i%len(pairs)]
+		evictAll := i%10 == 0
+		go func() {
+			ProcessOrderbook("btcc", p, Base{}, Spot)
+			wg.Done()
+		}()
+		go func() {
+			RemoveOrderbook("btcc", p, Spot)
+			wg.Done()
+		}()
+		go func() {
+			if evictAll {
+				RemoveExchangeOrderbooks("btcc")
+			} else {
+				RemoveStaleOrderbooks(0)
+			}
+			wg.Done()
+		}()
+		go func() {
+			GetOrderbook("btcc", p, Spot)
+			GetOrderbookCounts()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	RemoveExchangeOrderbooks("btcc")
+	for x := range pairs {
+		ProcessOrderbook("btcc", pairs[x], Base{}, Spot)
+	}
+	if counts := GetOrderbookCounts(); counts["btcc"] != len(pairs) {
+		t.Errorf("Test failed. Expected %d orderbooks after eviction, got %d",
+			len(pairs), counts["btcc"])
+	}
+}
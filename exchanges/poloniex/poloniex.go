@@ -51,6 +51,13 @@ const (
 
 	poloniexAuthRate   = 6
 	poloniexUnauthRate = 6
+
+	// poloniexTradeHistoryLimit is the most trades returned for a time range,
+	// the newest trades of the range are returned when it is exceeded
+	poloniexTradeHistoryLimit  = 1000
+	poloniexTradeHistoryWindow = time.Hour
+	poloniexTradeHistoryMaxGap = time.Hour * 24 * 30
+	poloniexTradeTimeLayout    = "2006-01-02 15:04:05"
 )
 
 // Poloniex is the overarching type across the poloniex package
@@ -73,6 +80,9 @@ func (p *Poloniex) SetDefaults() {
 	p.ConfigCurrencyPairFormat.Delimiter = "_"
 	p.ConfigCurrencyPairFormat.Uppercase = true
 	p.AssetTypes = []string{ticker.Spot}
+	p.BaseCurrencies = []string{"USD"}
+	p.EnabledPairs = []string{"BTC_LTC", "BTC_ETH", "BTC_DOGE", "BTC_DASH",
+		"BTC_XRP"}
 	p.SupportsAutoPairUpdating = true
 	p.SupportsRESTTickerBatching = true
 	p.Requester = request.New(p.Name,
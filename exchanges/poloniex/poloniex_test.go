@@ -1,13 +1,18 @@
 package poloniex
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
 	"github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
 )
 
 var p Poloniex
@@ -67,6 +72,81 @@ func TestGetTradeHistory(t *testing.T) {
 	}
 }
 
+func TestGetExchangeHistory(t *testing.T) {
+	p.SetDefaults()
+	TestSetup(t)
+	base := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	var trades []time.Time
+	for x := 0; x < 1500; x++ {
+		trades = append(trades, base.Add(time.Second*2*time.Duration(x)))
+	}
+	trades = append(trades, base.Add(time.Hour*24*10))
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		if r.URL.Query().Get("command") != "returnTradeHistory" ||
+			r.URL.Query().Get("currencyPair") != "BTC_LTC" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var resp []TradeHistory
+		for x := len(trades) - 1; x >= 0 && len(resp) < poloniexTradeHistoryLimit; x-- {
+			if trades[x].Unix() < start || trades[x].Unix() > end {
+				continue
+			}
+			resp = append(resp, TradeHistory{
+				TradeID: int64(x + 1),
+				Date:    trades[x].Format(poloniexTradeTimeLayout),
+				Type:    "buy",
+				Rate:    0.01,
+				Amount:  1,
+			})
+		}
+		data, _ := common.JSONEncode(resp)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	var x Poloniex
+	x.SetDefaults()
+	x.APIUrl = srv.URL
+	x.Requester = request.New(x.Name,
+		request.NewRateLimit(time.Second, 0),
+		request.NewRateLimit(time.Second, 0),
+		new(http.Client))
+	currencyPair := pair.NewCurrencyPairDelimiter("BTC_LTC", "_")
+
+	// A full range is narrowed so the batch starts at the start time
+	history, err := x.GetExchangeHistory(currencyPair, "SPOT", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 901 || history[0].Timestamp != base.Unix() ||
+		history[0].TID != 1 || history[900].TID != 901 || requests != 2 {
+		t.Fatalf("Test Failed - GetExchangeHistory() unexpected batch of %d trades over %d requests",
+			len(history), requests)
+	}
+
+	// Quiet periods are skipped
+	history, err = x.GetExchangeHistory(currencyPair, "SPOT", trades[1499].Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Timestamp != trades[1500].Unix() {
+		t.Errorf("Test Failed - GetExchangeHistory() unexpected trades %v", history)
+	}
+
+	history, err = x.GetExchangeHistory(currencyPair, "SPOT", time.Now().Add(time.Minute))
+	if err != nil || len(history) != 0 {
+		t.Errorf("Test Failed - GetExchangeHistory() unexpected trades %v, error: %v",
+			history, err)
+	}
+}
+
 func TestGetChartData(t *testing.T) {
 	_, err := p.GetChartData("BTC_XMR", "1405699200", "1405699400", "300")
 	if err != nil {
@@ -217,7 +297,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.LTC,
 	}
-	response, err := p.SubmitOrder(pair, exchange.Buy, exchange.Market, 1, 10, "hi")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      pair,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     10,
+		Amount:    1,
+	}
+	response, err := p.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -353,12 +440,12 @@ func TestWithdrawInternationalBank(t *testing.T) {
 
 func TestGetDepositAddress(t *testing.T) {
 	if areTestAPIKeysSet() {
-		_, err := p.GetDepositAddress(symbol.DASH, "")
+		_, err := p.GetDepositAddress(symbol.DASH, "", false)
 		if err != nil {
 			t.Error("Test Failed - GetDepositAddress()", err)
 		}
 	} else {
-		_, err := p.GetDepositAddress(symbol.DASH, "")
+		_, err := p.GetDepositAddress(symbol.DASH, "", false)
 		if err == nil {
 			t.Error("Test Failed - GetDepositAddress()")
 		}
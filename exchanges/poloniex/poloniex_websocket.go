@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
@@ -35,17 +34,11 @@ func (p *Poloniex) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-	if p.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(p.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := p.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	p.WebsocketConn, _, err = dialer.Dial(p.Websocket.GetWebsocketURL(),
 		http.Header{})
 	if err != nil {
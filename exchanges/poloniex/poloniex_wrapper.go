@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -30,21 +31,28 @@ func (p *Poloniex) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", p.GetName(), len(p.EnabledPairs), p.EnabledPairs)
 	}
 
+	forceUpdate := false
+	if common.StringDataCompare(p.AvailablePairs, "BTC_USDT") {
+		log.Warnf("%s contains invalid pair, forcing upgrade of available currencies.\n",
+			p.GetName())
+		forceUpdate = true
+	}
+
+	err := p.UpdateTradablePairs(forceUpdate)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", p.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (p *Poloniex) UpdateTradablePairs(forceUpdate bool) error {
 	exchangeCurrencies, err := p.GetExchangeCurrencies()
 	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", p.GetName())
-	} else {
-		forceUpdate := false
-		if common.StringDataCompare(p.AvailablePairs, "BTC_USDT") {
-			log.Warnf("%s contains invalid pair, forcing upgrade of available currencies.\n",
-				p.GetName())
-			forceUpdate = true
-		}
-		err = p.UpdateCurrencies(exchangeCurrencies, false, forceUpdate)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies %s.\n", p.GetName(), err)
-		}
+		return err
 	}
+
+	return p.UpdateCurrencies(exchangeCurrencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -65,7 +73,10 @@ func (p *Poloniex) UpdateTicker(currencyPair pair.CurrencyPair, assetType string
 		tp.Last = tick[curr].Last
 		tp.Low = tick[curr].Low24Hr
 		tp.Volume = tick[curr].BaseVolume
-		ticker.ProcessTicker(p.GetName(), x, tp, assetType)
+		err = ticker.ProcessTicker(p.GetName(), x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(p.Name, currencyPair, assetType)
 }
@@ -154,22 +165,70 @@ func (p *Poloniex) GetFundingHistory() ([]exchange.FundHistory, error) {
 	return fundHistory, common.ErrFunctionNotSupported
 }
 
-// GetExchangeHistory returns historic trade data since exchange opening.
-func (p *Poloniex) GetExchangeHistory(currencyPair pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
-	var resp []exchange.TradeHistory
+// GetExchangeHistory returns a batch of historic trade data from the start
+// time onwards
+func (p *Poloniex) GetExchangeHistory(currencyPair pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
+	symbol := exchange.FormatExchangeCurrency(p.Name, currencyPair).String()
+	start := timestampStart
+	window := poloniexTradeHistoryWindow
+	for start.Before(time.Now()) {
+		trades, err := p.GetTradeHistory(symbol,
+			strconv.FormatInt(start.Unix(), 10),
+			strconv.FormatInt(start.Add(window).Unix(), 10))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(trades) >= poloniexTradeHistoryLimit && window > time.Second:
+			// Only the newest trades of a full range are returned, narrow the
+			// range so the batch follows on from the start time
+			window /= 2
+			continue
+		case len(trades) == 0:
+			// Skip quiet periods with a widening range
+			start = start.Add(window)
+			if window < poloniexTradeHistoryMaxGap {
+				window *= 2
+			}
+			continue
+		}
 
-	return resp, common.ErrNotYetImplemented
+		resp := make([]exchange.TradeHistory, 0, len(trades))
+		// Trades are returned newest first
+		for x := len(trades) - 1; x >= 0; x-- {
+			tradeTime, err := time.Parse(poloniexTradeTimeLayout, trades[x].Date)
+			if err != nil {
+				return nil, err
+			}
+			resp = append(resp, exchange.TradeHistory{
+				Timestamp: tradeTime.Unix(),
+				TID:       trades[x].TradeID,
+				Price:     trades[x].Rate,
+				Amount:    trades[x].Amount,
+				Exchange:  p.Name,
+				Type:      trades[x].Type,
+			})
+		}
+		return resp, nil
+	}
+	return nil, nil
 }
 
 // SubmitOrder submits a new order
-func (p *Poloniex) SubmitOrder(currencyPair pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (p *Poloniex) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	fillOrKill := orderType == exchange.Market
-	isBuyOrder := side == exchange.Buy
-	response, err := p.PlaceOrder(currencyPair.Pair().String(), price, amount, false, fillOrKill, isBuyOrder)
+	err := order.CheckOptions(p.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	fillOrKill := order.OrderType == exchange.Market
+	isBuyOrder := order.OrderSide == exchange.Buy
+	response, err := p.PlaceOrder(order.Pair.Pair().String(), order.Price, order.Amount, false, fillOrKill, isBuyOrder)
 
 	if response.OrderNumber > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response.OrderNumber)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response.OrderNumber)
 	}
 
 	if err == nil {
@@ -235,25 +294,27 @@ func (p *Poloniex) CancelAllOrders(orderCancellation exchange.OrderCancellation)
 }
 
 // GetOrderInfo returns information on a current open order
-func (p *Poloniex) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (p *Poloniex) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (p *Poloniex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	a, err := p.GetDepositAddresses()
-	if err != nil {
-		return "", err
-	}
+func (p *Poloniex) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return p.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		a, err := p.GetDepositAddresses()
+		if err != nil {
+			return "", err
+		}
 
-	address, ok := a.Addresses[cryptocurrency.Upper().String()]
-	if !ok {
-		return "", fmt.Errorf("Cannot find deposit address for %s",
-			cryptocurrency)
-	}
+		address, ok := a.Addresses[cryptocurrency.Upper().String()]
+		if !ok {
+			return "", fmt.Errorf("Cannot find deposit address for %s",
+				cryptocurrency)
+		}
 
-	return address, nil
+		return address, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
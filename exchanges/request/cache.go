@@ -0,0 +1,81 @@
+package request
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cacheable response is served from the cache
+// when no TTL is supplied
+const DefaultCacheTTL = time.Second * 2
+
+// maxCacheEntries bounds the number of responses cached per Requester
+const maxCacheEntries = 100
+
+// cacheEntry holds a cached response body and when it expires
+type cacheEntry struct {
+	contents []byte
+	expires  time.Time
+}
+
+// responseCache holds recent response bodies of cacheable requests keyed by
+// method and URL
+type responseCache struct {
+	entries map[string]cacheEntry
+	m       sync.Mutex
+}
+
+// get returns the cached response body for a key if it hasn't expired
+func (c *responseCache) get(key string) ([]byte, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.contents, true
+}
+
+// set caches a response body for the supplied TTL. Expired entries are purged
+// when the cache is full and if it is still full the entry closest to expiry
+// is evicted
+func (c *responseCache) set(key string, contents []byte, ttl time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]cacheEntry)
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		now := time.Now()
+		var oldest string
+		for k, v := range c.entries {
+			if now.After(v.expires) {
+				delete(c.entries, k)
+				continue
+			}
+			if oldest == "" || v.expires.Before(c.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			delete(c.entries, oldest)
+		}
+	}
+
+	c.entries[key] = cacheEntry{
+		contents: contents,
+		expires:  time.Now().Add(ttl),
+	}
+}
+
+// len returns the number of cached entries
+func (c *responseCache) len() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,136 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+const (
+	// fixtureTimeFormat sorts fixture file names in capture order
+	fixtureTimeFormat = "20060102T150405.000000000"
+	// maxFixturePathLength bounds the request path part of a fixture file name
+	maxFixturePathLength = 100
+)
+
+// fixturePathChars matches the characters replaced in the request path part of
+// a fixture file name
+var fixturePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Fixture holds a captured request and its response. Credentials are redacted
+// from the URL, headers and request body
+type Fixture struct {
+	ID         string            `json:"id,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	StatusCode int               `json:"statusCode"`
+	Response   string            `json:"response"`
+}
+
+// Key returns the method and path a fixture is replayed for
+func (f *Fixture) Key() (string, error) {
+	return FixtureKey(f.Method, f.URL)
+}
+
+// FixtureKey returns the key of a request from its method and URL, the host
+// and query are ignored
+func FixtureKey(method, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	return method + " " + p, nil
+}
+
+// WriteFixture writes a fixture to a timestamped file in the directory,
+// creating it if needed, and returns the file path
+func WriteFixture(dir string, f *Fixture) (string, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	name := f.Timestamp.UTC().Format(fixtureTimeFormat)
+	if f.ID != "" {
+		name += "-" + f.ID
+	}
+	name += "-" + f.Method
+	if u, err := url.Parse(f.URL); err == nil {
+		p := fixturePathChars.ReplaceAllString(u.Path, "_")
+		if len(p) > maxFixturePathLength {
+			p = p[len(p)-maxFixturePathLength:]
+		}
+		name += "-" + p
+	}
+
+	// HTML escaping is disabled so captured URLs and bodies stay readable
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	err = encoder.Encode(f)
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, name+".json")
+	return file, ioutil.WriteFile(file, data.Bytes(), 0600)
+}
+
+// captureFixture writes a request and its response to the fixture directory,
+// a failure is logged as capturing must not fail the request
+func (r *Requester) captureFixture(id string, req *http.Request, path string, headers map[string]string, status int, contents []byte) {
+	fixture := &Fixture{
+		ID:         id,
+		Timestamp:  time.Now(),
+		Method:     req.Method,
+		URL:        ScrubURL(path),
+		Headers:    ScrubHeaders(headers),
+		Body:       ScrubBody(requestBody(req)),
+		StatusCode: status,
+		Response:   string(contents),
+	}
+	if len(fixture.Headers) == 0 {
+		fixture.Headers = nil
+	}
+
+	file, err := WriteFixture(r.FixtureDir, fixture)
+	if err != nil {
+		log.Errorf("%s exchange request %s failed to capture fixture: %s",
+			r.Name, id, err)
+		return
+	}
+	log.Debugf("%s exchange request %s captured to %s", r.Name, id, file)
+}
+
+// requestBody returns the body of a request read from a copy so the request
+// body isn't consumed
+func requestBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	contents, err := ioutil.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	return string(contents)
+}
@@ -0,0 +1,94 @@
+package request
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFixtureKey(t *testing.T) {
+	key, err := FixtureKey("GET", "https://www.okcoin.com/api/v1/ticker.do?symbol=btc_usd")
+	if err != nil || key != "GET /api/v1/ticker.do" {
+		t.Errorf("test failed - unexpected key %q %v", key, err)
+	}
+
+	key, err = FixtureKey("POST", "http://127.0.0.1:8080")
+	if err != nil || key != "POST /" {
+		t.Errorf("test failed - unexpected key %q %v", key, err)
+	}
+
+	_, err = FixtureKey("GET", "http://[::1")
+	if err == nil {
+		t.Error("test failed - expected an invalid URL error")
+	}
+}
+
+func TestCaptureFixture(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0),
+		new(http.Client))
+	r.FixtureDir = filepath.Join(dir, "test")
+
+	err = r.SendPayload("POST", srv.URL+"/api/v1/trade.do?api_key=key",
+		map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		strings.NewReader("amount=1&api_key=key&sign=ABCDEF"), nil, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.SendPayload("GET", srv.URL+"/fail", nil, nil, nil, false, false)
+	if err == nil {
+		t.Fatal("test failed - expected an unsuccessful HTTP status error")
+	}
+
+	files, err := filepath.Glob(filepath.Join(r.FixtureDir, "*.json"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("test failed - expected 2 fixtures, found %v %v", files, err)
+	}
+	if !strings.HasSuffix(files[0], "-POST-_api_v1_trade.do.json") {
+		t.Errorf("test failed - unexpected fixture file %s", files[0])
+	}
+
+	var fixtures []Fixture
+	for x := range files {
+		data, err := ioutil.ReadFile(files[x])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var f Fixture
+		err = json.Unmarshal(data, &f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	if fixtures[0].URL != srv.URL+"/api/v1/trade.do?api_key="+RedactedValue ||
+		fixtures[0].Body != "amount=1&api_key="+RedactedValue+"&sign="+RedactedValue ||
+		fixtures[0].StatusCode != http.StatusOK ||
+		fixtures[0].Response != `{"result":true}` {
+		t.Errorf("test failed - unexpected fixture %+v", fixtures[0])
+	}
+	if fixtures[1].Method != "GET" || fixtures[1].StatusCode != http.StatusBadRequest ||
+		fixtures[1].Body != "" || fixtures[1].Headers != nil {
+		t.Errorf("test failed - unexpected fixture %+v", fixtures[1])
+	}
+}
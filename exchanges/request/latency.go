@@ -0,0 +1,214 @@
+package request
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLatencyWindow is the duration of a request latency window
+	DefaultLatencyWindow = time.Minute
+	// latencyReservoirSize bounds the latency samples kept per window, larger
+	// windows are sampled uniformly
+	latencyReservoirSize = 1024
+	// latencyHistorySize is the number of completed windows kept per endpoint
+	// class
+	latencyHistorySize = 10
+)
+
+// Endpoint classes request latency is tracked by
+const (
+	EndpointPublic = "public"
+	EndpointAuth   = "auth"
+)
+
+// LatencyWindow holds the latency percentiles of the requests sent within a
+// window
+type LatencyWindow struct {
+	Start time.Time     `json:"start"`
+	End   time.Time     `json:"end"`
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// EndpointLatency holds the request latency statistics of an endpoint class
+type EndpointLatency struct {
+	// Rolling holds the percentiles over the current and previous windows
+	Rolling LatencyWindow `json:"rolling"`
+	// Windows are the completed windows with requests, oldest first
+	Windows []LatencyWindow `json:"windows"`
+}
+
+// LatencyStats holds the request latency statistics of an exchange keyed by
+// endpoint class
+type LatencyStats map[string]EndpointLatency
+
+// latencyReservoir holds a fixed size uniform sample of the request latencies
+// of a window
+type latencyReservoir struct {
+	start   time.Time
+	count   int
+	max     time.Duration
+	samples []time.Duration
+}
+
+// add records a latency, replacing a random sample once the reservoir is full
+func (l *latencyReservoir) add(d time.Duration, rnd *rand.Rand) {
+	l.count++
+	if d > l.max {
+		l.max = d
+	}
+	if len(l.samples) < latencyReservoirSize {
+		l.samples = append(l.samples, d)
+		return
+	}
+	if i := rnd.Intn(l.count); i < latencyReservoirSize {
+		l.samples[i] = d
+	}
+}
+
+// endpointLatency tracks the latency windows of an endpoint class
+type endpointLatency struct {
+	current  latencyReservoir
+	previous latencyReservoir
+	history  []LatencyWindow
+}
+
+// roll completes the current window once it has elapsed. Windows stay aligned
+// to the first window so consecutive windows are contiguous, elapsed windows
+// without requests aren't kept
+func (e *endpointLatency) roll(now time.Time, window time.Duration) {
+	if e.current.start.IsZero() || now.Sub(e.current.start) < window {
+		return
+	}
+
+	elapsed := now.Sub(e.current.start) / window
+	if e.current.count > 0 {
+		e.history = append(e.history, latencyStats(e.current.samples,
+			e.current.start, e.current.start.Add(window), e.current.count,
+			e.current.max))
+		if len(e.history) > latencyHistorySize {
+			e.history = e.history[len(e.history)-latencyHistorySize:]
+		}
+	}
+
+	e.previous = latencyReservoir{}
+	if elapsed == 1 {
+		e.previous = e.current
+	}
+	e.current = latencyReservoir{start: e.current.start.Add(elapsed * window)}
+}
+
+// percentile returns the nearest rank percentile of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// latencyStats returns the percentiles of the latency samples
+func latencyStats(samples []time.Duration, start, end time.Time, count int, max time.Duration) LatencyWindow {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return LatencyWindow{
+		Start: start,
+		End:   end,
+		Count: count,
+		P50:   percentile(sorted, 50),
+		P95:   percentile(sorted, 95),
+		P99:   percentile(sorted, 99),
+		Max:   max,
+	}
+}
+
+// latencyTracker tracks request latencies by endpoint class over windows of
+// a fixed duration, its zero value uses DefaultLatencyWindow
+type latencyTracker struct {
+	mtx       sync.Mutex
+	window    time.Duration
+	endpoints map[string]*endpointLatency
+	rnd       *rand.Rand
+}
+
+func (t *latencyTracker) windowDuration() time.Duration {
+	if t.window <= 0 {
+		return DefaultLatencyWindow
+	}
+	return t.window
+}
+
+// record adds the latency of a request to the window of its endpoint class
+func (t *latencyTracker) record(class string, d time.Duration, now time.Time) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.endpoints == nil {
+		t.endpoints = make(map[string]*endpointLatency)
+		t.rnd = rand.New(rand.NewSource(now.UnixNano()))
+	}
+	e, ok := t.endpoints[class]
+	if !ok {
+		e = &endpointLatency{current: latencyReservoir{start: now}}
+		t.endpoints[class] = e
+	}
+	e.roll(now, t.windowDuration())
+	e.current.add(d, t.rnd)
+}
+
+// stats returns the latency statistics of every endpoint class, completing
+// elapsed windows first
+func (t *latencyTracker) stats(now time.Time) LatencyStats {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	result := make(LatencyStats, len(t.endpoints))
+	window := t.windowDuration()
+	for class, e := range t.endpoints {
+		e.roll(now, window)
+
+		start := e.current.start
+		max := e.current.max
+		samples := append([]time.Duration(nil), e.current.samples...)
+		if e.previous.count > 0 {
+			start = e.previous.start
+			samples = append(samples, e.previous.samples...)
+			if e.previous.max > max {
+				max = e.previous.max
+			}
+		}
+		result[class] = EndpointLatency{
+			Rolling: latencyStats(samples, start, now,
+				e.current.count+e.previous.count, max),
+			Windows: append([]LatencyWindow(nil), e.history...),
+		}
+	}
+	return result
+}
+
+// recordLatency records the latency of a request by its endpoint class
+func (r *Requester) recordLatency(authRequest bool, d time.Duration) {
+	class := EndpointPublic
+	if authRequest {
+		class = EndpointAuth
+	}
+	r.latency.record(class, d, time.Now())
+}
+
+// GetLatencyStats returns the request latency percentiles of the exchange by
+// endpoint class, over the current and previous windows and for each recent
+// completed window
+func (r *Requester) GetLatencyStats() LatencyStats {
+	if r == nil {
+		return LatencyStats{}
+	}
+	return r.latency.stats(time.Now())
+}
@@ -0,0 +1,121 @@
+package request
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for x := 1; x <= 100; x++ {
+		sorted = append(sorted, time.Duration(x)*time.Millisecond)
+	}
+	for p, expected := range map[float64]time.Duration{
+		0:    time.Millisecond,
+		50:   50 * time.Millisecond,
+		95:   95 * time.Millisecond,
+		99:   99 * time.Millisecond,
+		99.5: 100 * time.Millisecond,
+		100:  100 * time.Millisecond,
+	} {
+		if result := percentile(sorted, p); result != expected {
+			t.Errorf("test failed - p%v expected %v, received %v", p, expected, result)
+		}
+	}
+
+	if percentile(nil, 50) != 0 {
+		t.Error("test failed - expected a zero percentile without samples")
+	}
+	single := []time.Duration{time.Second}
+	if percentile(single, 1) != time.Second || percentile(single, 99) != time.Second {
+		t.Error("test failed - expected every percentile of one sample to be the sample")
+	}
+
+	stats := latencyStats([]time.Duration{4, 1, 3, 2}, time.Time{}, time.Time{}, 4, 4)
+	if stats.P50 != 2 || stats.P95 != 4 || stats.P99 != 4 || stats.Max != 4 || stats.Count != 4 {
+		t.Errorf("test failed - unexpected stats of unsorted samples %+v", stats)
+	}
+}
+
+func TestLatencyReservoir(t *testing.T) {
+	var r latencyReservoir
+	rnd := rand.New(rand.NewSource(1))
+	for x := 1; x <= latencyReservoirSize*5; x++ {
+		r.add(time.Duration(x), rnd)
+	}
+	if len(r.samples) != latencyReservoirSize || r.count != latencyReservoirSize*5 ||
+		r.max != time.Duration(latencyReservoirSize*5) {
+		t.Fatalf("test failed - unexpected reservoir of %d samples, count %d, max %v",
+			len(r.samples), r.count, r.max)
+	}
+
+	// The samples are uniform over every latency added, not only the first
+	var later int
+	for x := range r.samples {
+		if r.samples[x] > latencyReservoirSize {
+			later++
+		}
+	}
+	if later < latencyReservoirSize/2 {
+		t.Errorf("test failed - expected most samples after the reservoir filled, received %d",
+			later)
+	}
+}
+
+func TestLatencyWindowRollover(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := latencyTracker{window: time.Minute}
+	tracker.record(EndpointPublic, 100*time.Millisecond, start)
+	tracker.record(EndpointPublic, 300*time.Millisecond, start.Add(30*time.Second))
+	tracker.record(EndpointAuth, time.Second, start.Add(30*time.Second))
+
+	stats := tracker.stats(start.Add(59 * time.Second))
+	public := stats[EndpointPublic]
+	if len(public.Windows) != 0 || public.Rolling.Count != 2 ||
+		public.Rolling.P50 != 100*time.Millisecond || public.Rolling.Max != 300*time.Millisecond {
+		t.Errorf("test failed - unexpected stats before the rollover %+v", public)
+	}
+
+	// The first window completes and the rolling stats include both windows
+	tracker.record(EndpointPublic, 500*time.Millisecond, start.Add(61*time.Second))
+	public = tracker.stats(start.Add(90 * time.Second))[EndpointPublic]
+	if len(public.Windows) != 1 || public.Windows[0].Count != 2 ||
+		!public.Windows[0].Start.Equal(start) ||
+		!public.Windows[0].End.Equal(start.Add(time.Minute)) ||
+		public.Windows[0].P95 != 300*time.Millisecond {
+		t.Errorf("test failed - unexpected completed window %+v", public.Windows)
+	}
+	if public.Rolling.Count != 3 || public.Rolling.Max != 500*time.Millisecond ||
+		!public.Rolling.Start.Equal(start) {
+		t.Errorf("test failed - unexpected rolling stats %+v", public.Rolling)
+	}
+
+	// Idle windows aren't kept and the next window stays aligned
+	tracker.record(EndpointPublic, 200*time.Millisecond, start.Add(5*time.Minute+10*time.Second))
+	public = tracker.stats(start.Add(5*time.Minute + 20*time.Second))[EndpointPublic]
+	if len(public.Windows) != 2 || !public.Windows[1].Start.Equal(start.Add(time.Minute)) ||
+		public.Rolling.Count != 1 || !public.Rolling.Start.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("test failed - unexpected stats after idle windows %+v", public)
+	}
+
+	// Reading the stats completes elapsed windows without new requests
+	public = tracker.stats(start.Add(6*time.Minute + time.Second))[EndpointPublic]
+	if len(public.Windows) != 3 || public.Rolling.Count != 1 {
+		t.Errorf("test failed - expected the window to complete on read %+v", public)
+	}
+
+	for x := 0; x < latencyHistorySize*2; x++ {
+		tracker.record(EndpointPublic, time.Millisecond,
+			start.Add(time.Duration(10+x)*time.Minute))
+	}
+	public = tracker.stats(start.Add(time.Hour))[EndpointPublic]
+	if len(public.Windows) != latencyHistorySize {
+		t.Errorf("test failed - expected %d windows, received %d", latencyHistorySize,
+			len(public.Windows))
+	}
+	if auth := tracker.stats(start.Add(time.Hour))[EndpointAuth]; len(auth.Windows) != 1 ||
+		auth.Windows[0].P99 != time.Second {
+		t.Errorf("test failed - unexpected auth stats %+v", auth)
+	}
+}
@@ -0,0 +1,206 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+const (
+	// DefaultRateLimitThreshold is the remaining request count reported by an
+	// exchange below which requests are slowed down
+	DefaultRateLimitThreshold = 5
+	// defaultRateLimitBackoff is waited after a rate limited response without
+	// a Retry-After or reset header
+	defaultRateLimitBackoff = time.Second
+	// maxRateLimitDelay bounds the slow down of a single request
+	maxRateLimitDelay = time.Minute
+	// unixResetThreshold separates reset headers holding the seconds until the
+	// reset from those holding the reset unix time
+	unixResetThreshold = 1000000000
+	// unixMilliResetThreshold separates reset headers holding the reset unix
+	// time in seconds from those holding it in milliseconds
+	unixMilliResetThreshold = 100000000000
+)
+
+// Rate limit response headers, matched case insensitively
+var (
+	rateLimitHeaders = []string{"X-RateLimit-Limit", "X-Rate-Limit-Limit",
+		"RateLimit-Limit"}
+	rateLimitRemainingHeaders = []string{"X-RateLimit-Remaining",
+		"X-Rate-Limit-Remaining", "RateLimit-Remaining"}
+	rateLimitResetHeaders = []string{"X-RateLimit-Reset", "X-Rate-Limit-Reset",
+		"RateLimit-Reset"}
+)
+
+// RateLimitStatus holds the latest API rate limit consumption reported by an
+// exchange in its response headers, or inferred from rate limited responses
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed per rate limit window, zero
+	// if unknown
+	Limit int `json:"limit"`
+	// Remaining is the number of requests left in the window, negative if
+	// unknown
+	Remaining int `json:"remaining"`
+	// Reset is when the window resets, zero if unknown
+	Reset time.Time `json:"reset,omitempty"`
+	// RateLimited counts the responses rate limited by the exchange
+	RateLimited int `json:"rateLimited"`
+	// Updated is when the status was last updated, zero if the exchange
+	// hasn't reported it
+	Updated time.Time `json:"updated,omitempty"`
+}
+
+// GetRateLimitStatus returns the latest rate limit consumption reported by the
+// exchange
+func (r *Requester) GetRateLimitStatus() RateLimitStatus {
+	if r == nil {
+		return RateLimitStatus{Remaining: -1}
+	}
+	r.rateLimitMtx.Lock()
+	defer r.rateLimitMtx.Unlock()
+	if r.rateLimitStatus.Updated.IsZero() {
+		return RateLimitStatus{Remaining: -1}
+	}
+	return r.rateLimitStatus
+}
+
+// updateRateLimitStatus records the rate limit headers of a response. A rate
+// limited response exhausts the remaining requests until its Retry-After or
+// reset time
+func (r *Requester) updateRateLimitStatus(resp *http.Response) {
+	limit, hasLimit := headerInt(resp.Header, rateLimitHeaders)
+	remaining, hasRemaining := headerInt(resp.Header, rateLimitRemainingHeaders)
+	reset, hasReset := headerReset(resp.Header, rateLimitResetHeaders)
+	limited := resp.StatusCode == http.StatusTooManyRequests
+	if !hasLimit && !hasRemaining && !hasReset && !limited {
+		return
+	}
+
+	r.rateLimitMtx.Lock()
+	defer r.rateLimitMtx.Unlock()
+	status := &r.rateLimitStatus
+	if status.Updated.IsZero() {
+		status.Remaining = -1
+	}
+	status.Updated = time.Now()
+	if hasLimit {
+		status.Limit = limit
+	}
+	if hasRemaining {
+		status.Remaining = remaining
+	}
+	if hasReset {
+		status.Reset = reset
+	}
+	if !limited {
+		return
+	}
+
+	status.RateLimited++
+	status.Remaining = 0
+	if retryAfter, ok := headerReset(resp.Header, []string{"Retry-After"}); ok {
+		status.Reset = retryAfter
+	} else if !hasReset || !status.Reset.After(status.Updated) {
+		status.Reset = status.Updated.Add(defaultRateLimitBackoff)
+	}
+}
+
+// rateLimitDelay returns how long to wait before the next request. Once the
+// remaining requests drop below the threshold the rest are spread evenly
+// until the window resets
+func (r *Requester) rateLimitDelay() time.Duration {
+	threshold := r.RateLimitThreshold
+	if threshold == 0 {
+		threshold = DefaultRateLimitThreshold
+	}
+	if threshold < 0 {
+		return 0
+	}
+
+	r.rateLimitMtx.Lock()
+	defer r.rateLimitMtx.Unlock()
+	status := r.rateLimitStatus
+	if status.Remaining < 0 || status.Remaining >= threshold ||
+		status.Updated.IsZero() {
+		return 0
+	}
+	untilReset := time.Until(status.Reset)
+	if untilReset <= 0 {
+		return 0
+	}
+	delay := untilReset / time.Duration(status.Remaining+1)
+	if delay > maxRateLimitDelay {
+		delay = maxRateLimitDelay
+	}
+	return delay
+}
+
+// waitForRateLimit slows down a request when the exchange reported few
+// remaining requests, returning early if the context is cancelled
+func (r *Requester) waitForRateLimit(ctx context.Context, id string, verbose bool) error {
+	delay := r.rateLimitDelay()
+	if delay <= 0 {
+		return nil
+	}
+	if verbose {
+		log.Debugf("%s exchange request %s. Few requests remaining, slowing down for %v",
+			r.Name, id, delay)
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// headerInt returns the first of the headers holding an integer
+func headerInt(h http.Header, names []string) (int, bool) {
+	for x := range names {
+		v := h.Get(names[x])
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// headerReset returns the reset time of the first of the headers holding the
+// seconds until the reset, the reset unix time in seconds or milliseconds, or
+// a HTTP date
+func headerReset(h http.Header, names []string) (time.Time, bool) {
+	for x := range names {
+		v := h.Get(names[x])
+		if v == "" {
+			continue
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return t, true
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			continue
+		}
+		switch {
+		case f < unixResetThreshold:
+			return time.Now().Add(time.Duration(f * float64(time.Second))), true
+		case f < unixMilliResetThreshold:
+			return time.Unix(0, int64(f*float64(time.Second))), true
+		default:
+			return time.Unix(0, int64(f*float64(time.Millisecond))), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,165 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHeaderReset(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2", now.Add(2 * time.Second)},
+		{"1539820800", time.Unix(1539820800, 0)},
+		{"1539820800500", time.Unix(1539820800, 500000000)},
+		{"Thu, 18 Oct 2018 00:00:00 GMT", time.Date(2018, 10, 18, 0, 0, 0, 0, time.UTC)},
+	} {
+		h := http.Header{}
+		h.Set("X-RateLimit-Reset", test.value)
+		reset, ok := headerReset(h, rateLimitResetHeaders)
+		if !ok || reset.Sub(test.expected) > time.Second ||
+			test.expected.Sub(reset) > time.Second {
+			t.Errorf("test failed - %s expected reset %v, received %v %v",
+				test.value, test.expected, reset, ok)
+		}
+	}
+
+	h := http.Header{}
+	h.Set("X-RateLimit-Reset", "soon")
+	if _, ok := headerReset(h, rateLimitResetHeaders); ok {
+		t.Error("test failed - expected an invalid reset header to be ignored")
+	}
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	var m sync.Mutex
+	headers := map[string]string{}
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	respond := func(code int, h map[string]string) {
+		m.Lock()
+		status, headers = code, h
+		m.Unlock()
+	}
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0),
+		new(http.Client))
+	if s := r.GetRateLimitStatus(); !s.Updated.IsZero() || s.Remaining != -1 {
+		t.Errorf("test failed - expected an unknown rate limit status, received %+v", s)
+	}
+
+	err := r.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := r.GetRateLimitStatus(); !s.Updated.IsZero() {
+		t.Errorf("test failed - expected no status without rate limit headers, received %+v", s)
+	}
+
+	reset := time.Now().Add(time.Minute).Unix()
+	respond(http.StatusOK, map[string]string{
+		"x-ratelimit-limit":     "300",
+		"x-ratelimit-remaining": "250",
+		"x-ratelimit-reset":     strconv.FormatInt(reset, 10),
+	})
+	err = r.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := r.GetRateLimitStatus()
+	if s.Limit != 300 || s.Remaining != 250 || s.Reset.Unix() != reset ||
+		s.RateLimited != 0 || s.Updated.IsZero() {
+		t.Errorf("test failed - unexpected rate limit status %+v", s)
+	}
+	if delay := r.rateLimitDelay(); delay != 0 {
+		t.Errorf("test failed - expected no slow down above the threshold, received %v", delay)
+	}
+
+	respond(http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+	err = r.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err == nil {
+		t.Fatal("test failed - expected a rate limited error")
+	}
+	s = r.GetRateLimitStatus()
+	if s.Limit != 300 || s.Remaining != 0 || s.RateLimited != 1 ||
+		time.Until(s.Reset) > time.Second {
+		t.Errorf("test failed - unexpected rate limited status %+v", s)
+	}
+}
+
+func TestRateLimitSlowdown(t *testing.T) {
+	var m sync.Mutex
+	remaining := 2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		m.Unlock()
+		w.Header().Set("X-RateLimit-Reset", "0.3")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0),
+		new(http.Client))
+	err := r.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two remaining requests are spread over the 300ms until the reset
+	delay := r.rateLimitDelay()
+	if delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+		t.Errorf("test failed - expected a slow down of about 100ms, received %v", delay)
+	}
+	start := time.Now()
+	err = r.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("test failed - expected the request to be slowed down, took %v", elapsed)
+	}
+
+	r.RateLimitThreshold = 2
+	if delay = r.rateLimitDelay(); delay != 0 {
+		t.Errorf("test failed - expected no slow down at the threshold, received %v", delay)
+	}
+	r.RateLimitThreshold = -1
+	m.Lock()
+	remaining = 0
+	m.Unlock()
+	err = r.SendPayload("GET", srv.URL, nil, nil, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay = r.rateLimitDelay(); delay != 0 {
+		t.Errorf("test failed - expected no slow down when disabled, received %v", delay)
+	}
+
+	// An exhausted window waits until the reset unless the request is cancelled
+	r.RateLimitThreshold = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = r.SendPayloadWithContext(ctx, "GET", srv.URL, nil, nil, nil, false, false)
+	if err != context.DeadlineExceeded {
+		t.Errorf("test failed - expected the slowed down request to be cancelled, received %v", err)
+	}
+}
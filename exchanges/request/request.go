@@ -2,6 +2,8 @@ package request
 
 import (
 	"compress/gzip"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -36,6 +38,36 @@ type Requester struct {
 	m                    sync.Mutex
 	Jobs                 chan Job
 	WorkerStarted        bool
+	cache                responseCache
+	// VerboseBodyLimit limits the bytes of a request or response body logged
+	// verbosely. DefaultVerboseBodyLimit is used if zero, bodies aren't
+	// truncated if negative
+	VerboseBodyLimit int
+	// FixtureDir is the directory each request and its response are captured
+	// to as a Fixture, requests aren't captured if empty
+	FixtureDir string
+	// RateLimitThreshold is the remaining request count reported by the
+	// exchange below which requests are slowed down.
+	// DefaultRateLimitThreshold is used if zero, requests aren't slowed down
+	// if negative
+	RateLimitThreshold int
+	rateLimitStatus    RateLimitStatus
+	rateLimitMtx       sync.Mutex
+	latency            latencyTracker
+}
+
+// HTTPStatusError is returned when a request receives an unsuccessful HTTP
+// status code
+type HTTPStatusError struct {
+	StatusCode int
+	Message    string
+	// Body holds the raw response body for classifying the failure
+	Body string
+}
+
+// Error returns the error message
+func (e *HTTPStatusError) Error() string {
+	return e.Message
 }
 
 // RateLimit struct
@@ -54,6 +86,7 @@ type JobResult struct {
 
 // Job holds a request job
 type Job struct {
+	Context     context.Context
 	Request     *http.Request
 	Method      string
 	Path        string
@@ -238,11 +271,12 @@ func (r *Requester) IsValidCycle(auth bool) bool {
 	return false
 }
 
-func (r *Requester) checkRequest(method, path string, body io.Reader, headers map[string]string) (*http.Request, error) {
+func (r *Requester) checkRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Request, error) {
 	req, err := http.NewRequest(method, path, body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	for k, v := range headers {
 		req.Header.Add(k, v)
@@ -257,18 +291,31 @@ func (r *Requester) checkRequest(method, path string, body io.Reader, headers ma
 
 // DoRequest performs a HTTP/HTTPS request with the supplied params
 func (r *Requester) DoRequest(req *http.Request, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
+	id := RequestID(req.Context())
 	if verbose {
-		log.Debugf("%s exchange request path: %s requires rate limiter: %v", r.Name, path, r.RequiresRateLimiter())
-		for k, d := range headers {
-			log.Debugf("%s exchange request header [%s]: %s", r.Name, k, d)
+		r.logVerboseRequest(id, req, path, headers)
+	}
+
+	err := r.waitForRateLimit(req.Context(), id, verbose)
+	if err != nil {
+		if r.RequiresRateLimiter() {
+			r.DecrementRequests(authRequest)
 		}
-		log.Debug(body)
+		return err
 	}
 
 	var timeoutError error
 	for i := 0; i < r.timeoutRetryAttempts+1; i++ {
+		start := time.Now()
 		resp, err := r.HTTPClient.Do(req)
 		if err != nil {
+			if req.Context().Err() != nil {
+				if r.RequiresRateLimiter() {
+					r.DecrementRequests(authRequest)
+				}
+				return req.Context().Err()
+			}
+
 			if timeoutErr, ok := err.(net.Error); ok && timeoutErr.Timeout() {
 				if verbose {
 					log.Errorf("%s request has timed-out retrying request, count %d",
@@ -290,6 +337,7 @@ func (r *Requester) DoRequest(req *http.Request, method, path string, headers ma
 			}
 			return errors.New("resp is nil")
 		}
+		r.updateRateLimitStatus(resp)
 
 		var reader io.ReadCloser
 		switch resp.Header.Get("Content-Encoding") {
@@ -319,22 +367,34 @@ func (r *Requester) DoRequest(req *http.Request, method, path string, headers ma
 		if err != nil {
 			return err
 		}
+		r.recordLatency(authRequest, time.Since(start))
+
+		if r.FixtureDir != "" {
+			r.captureFixture(id, req, path, headers, resp.StatusCode, contents)
+		}
 
 		if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 202 {
 			err = fmt.Errorf("unsuccessful HTTP status code: %d", resp.StatusCode)
 
 			if verbose {
 				err = fmt.Errorf("%s\n%s", err.Error(),
-					fmt.Sprintf("%s exchange raw response: %s", r.Name, string(contents)))
+					fmt.Sprintf("%s exchange request %s raw response: %s", r.Name, id,
+						r.truncateVerboseBody(string(contents))))
 			}
 
-			return err
+			return &HTTPStatusError{
+				StatusCode: resp.StatusCode,
+				Message:    err.Error(),
+				Body:       string(contents),
+			}
 		}
 
 		resp.Body.Close()
 		if verbose {
-			log.Debugf("HTTP status: %s, Code: %v", resp.Status, resp.StatusCode)
-			log.Debugf("%s exchange raw response: %s", r.Name, string(contents))
+			log.Debugf("%s exchange request %s HTTP status: %s, Code: %v", r.Name,
+				id, resp.Status, resp.StatusCode)
+			log.Debugf("%s exchange request %s raw response: %s", r.Name, id,
+				r.truncateVerboseBody(string(contents)))
 		}
 
 		if result != nil {
@@ -343,13 +403,47 @@ func (r *Requester) DoRequest(req *http.Request, method, path string, headers ma
 
 		return nil
 	}
-	return fmt.Errorf("request.go error - failed to retry request %s",
+	return fmt.Errorf("request.go error - failed to retry request %w",
 		timeoutError)
 }
 
+// logVerboseRequest logs the path, headers and body of a request with
+// credentials redacted
+func (r *Requester) logVerboseRequest(id string, req *http.Request, path string, headers map[string]string) {
+	log.Debugf("%s exchange request %s path: %s requires rate limiter: %v", r.Name,
+		id, ScrubURL(path), r.RequiresRateLimiter())
+	for k, d := range ScrubHeaders(headers) {
+		log.Debugf("%s exchange request %s header [%s]: %s", r.Name, id, k, d)
+	}
+
+	body := requestBody(req)
+	if body == "" {
+		return
+	}
+	log.Debugf("%s exchange request %s body: %s", r.Name, id,
+		r.truncateVerboseBody(ScrubBody(body)))
+}
+
+// truncateVerboseBody truncates a body to the verbose body limit
+func (r *Requester) truncateVerboseBody(body string) string {
+	limit := r.VerboseBodyLimit
+	if limit == 0 {
+		limit = DefaultVerboseBodyLimit
+	}
+	return TruncateBody(body, limit)
+}
+
 func (r *Requester) worker() {
 	for {
 		for x := range r.Jobs {
+			if x.Context.Err() != nil {
+				x.JobResult <- &JobResult{
+					Error:  x.Context.Err(),
+					Result: x.Result,
+				}
+				continue
+			}
+
 			if !r.IsRateLimited(x.AuthRequest) {
 				r.IncrementRequests(x.AuthRequest)
 
@@ -364,9 +458,29 @@ func (r *Requester) worker() {
 				if x.Verbose {
 					log.Debugf("%s request. Rate limited! Sleeping for %v", r.Name, diff)
 				}
-				time.Sleep(diff)
+
+				select {
+				case <-time.After(diff):
+				case <-x.Context.Done():
+					if x.Verbose {
+						log.Debugf("%s request. Cancelled while rate limited", r.Name)
+					}
+					x.JobResult <- &JobResult{
+						Error:  x.Context.Err(),
+						Result: x.Result,
+					}
+					continue
+				}
 
 				for {
+					if x.Context.Err() != nil {
+						x.JobResult <- &JobResult{
+							Error:  x.Context.Err(),
+							Result: x.Result,
+						}
+						break
+					}
+
 					if !r.IsRateLimited(x.AuthRequest) {
 						r.IncrementRequests(x.AuthRequest)
 
@@ -389,6 +503,13 @@ func (r *Requester) worker() {
 
 // SendPayload handles sending HTTP/HTTPS requests
 func (r *Requester) SendPayload(method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
+	return r.SendPayloadWithContext(context.Background(), method, path, headers, body, result, authRequest, verbose)
+}
+
+// SendPayloadWithContext handles sending HTTP/HTTPS requests, the request is
+// aborted if the supplied context is cancelled whilst it is queued behind the
+// rate limiter or in-flight
+func (r *Requester) SendPayloadWithContext(ctx context.Context, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, verbose bool) error {
 	if r == nil || r.Name == "" {
 		return errors.New("not initiliased, SetDefaults() called before making request?")
 	}
@@ -401,7 +522,16 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		return errors.New("invalid path")
 	}
 
-	req, err := r.checkRequest(method, path, body, headers)
+	if ctx == nil {
+		return errors.New("nil context supplied")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, id := WithRequestID(ctx)
+	req, err := r.checkRequest(ctx, method, path, body, headers)
 	if err != nil {
 		return err
 	}
@@ -422,9 +552,11 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 	}
 	r.m.Unlock()
 
-	jobResult := make(chan *JobResult)
+	// Buffered so the worker never blocks on a caller which has given up
+	jobResult := make(chan *JobResult, 1)
 
 	newJob := Job{
+		Context:     ctx,
 		Request:     req,
 		Method:      method,
 		Path:        path,
@@ -437,19 +569,73 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 	}
 
 	if verbose {
-		log.Debugf("%s request. Attaching new job.", r.Name)
+		log.Debugf("%s request %s. Attaching new job.", r.Name, id)
+	}
+	select {
+	case r.Jobs <- newJob:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	r.Jobs <- newJob
 
 	if verbose {
-		log.Debugf("%s request. Waiting for job to complete.", r.Name)
+		log.Debugf("%s request %s. Waiting for job to complete.", r.Name, id)
 	}
-	resp := <-newJob.JobResult
 
-	if verbose {
-		log.Debugf("%s request. Job complete.", r.Name)
+	select {
+	case resp := <-newJob.JobResult:
+		if verbose {
+			log.Debugf("%s request %s. Job complete.", r.Name, id)
+		}
+		return resp.Error
+	case <-ctx.Done():
+		if verbose {
+			log.Debugf("%s request %s. Job cancelled.", r.Name, id)
+		}
+		return ctx.Err()
 	}
-	return resp.Error
+}
+
+// SendCacheablePayload sends an unauthenticated GET request whose response is
+// cached for the supplied TTL, or DefaultCacheTTL if the TTL is not set.
+// Duplicate requests within the TTL are served from the cache without
+// consuming the rate limit. Only idempotent public endpoints should be cached
+func (r *Requester) SendCacheablePayload(path string, headers map[string]string, result interface{}, ttl time.Duration, verbose bool) error {
+	return r.SendCacheablePayloadWithContext(context.Background(), path, headers, result, ttl, verbose)
+}
+
+// SendCacheablePayloadWithContext sends a cacheable request as
+// SendCacheablePayload does, a request missing the cache is aborted if the
+// supplied context is cancelled
+func (r *Requester) SendCacheablePayloadWithContext(ctx context.Context, path string, headers map[string]string, result interface{}, ttl time.Duration, verbose bool) error {
+	if r == nil || r.Name == "" {
+		return errors.New("not initiliased, SetDefaults() called before making request?")
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	key := "GET " + path
+	contents, ok := r.cache.get(key)
+	if ok {
+		if verbose {
+			log.Debugf("%s request. Serving cached response for %s", r.Name,
+				ScrubURL(path))
+		}
+	} else {
+		var raw json.RawMessage
+		err := r.SendPayloadWithContext(ctx, "GET", path, headers, nil, &raw, false, verbose)
+		if err != nil {
+			return err
+		}
+		contents = raw
+		r.cache.set(key, contents, ttl)
+	}
+
+	if result != nil {
+		return common.JSONDecode(contents, result)
+	}
+	return nil
 }
 
 // SetProxy sets a proxy address to the client transport
@@ -458,9 +644,60 @@ func (r *Requester) SetProxy(p *url.URL) error {
 		return errors.New("No proxy URL supplied")
 	}
 
-	r.HTTPClient.Transport = &http.Transport{
-		Proxy:               http.ProxyURL(p),
-		TLSHandshakeTimeout: proxyTLSTimeout,
+	transport, ok := r.transport()
+	if !ok {
+		transport = new(http.Transport)
+	}
+	transport.Proxy = http.ProxyURL(p)
+	if transport.TLSHandshakeTimeout <= 0 {
+		transport.TLSHandshakeTimeout = proxyTLSTimeout
 	}
+	r.HTTPClient.Transport = transport
 	return nil
 }
+
+// SetTimeout sets the timeout of each request attempt, which also bounds
+// dialing, the TLS handshake and waiting for the response headers so an
+// unresponsive endpoint fails at each stage within the timeout
+func (r *Requester) SetTimeout(t time.Duration) {
+	r.HTTPClient.Timeout = t
+	transport, ok := r.transport()
+	if !ok {
+		return
+	}
+	transport.DialContext = (&net.Dialer{
+		Timeout:   t,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = t
+	transport.ResponseHeaderTimeout = t
+	r.HTTPClient.Transport = transport
+}
+
+// GetTimeouts returns the timeout of each request attempt and the TLS
+// handshake and response header timeouts of the client transport
+func (r *Requester) GetTimeouts() (request, tlsHandshake, responseHeader time.Duration) {
+	if r == nil || r.HTTPClient == nil {
+		return 0, 0, 0
+	}
+	if transport, ok := r.HTTPClient.Transport.(*http.Transport); ok && transport != nil {
+		tlsHandshake, responseHeader = transport.TLSHandshakeTimeout,
+			transport.ResponseHeaderTimeout
+	}
+	return r.HTTPClient.Timeout, tlsHandshake, responseHeader
+}
+
+// transport returns a copy of the client transport to modify, or false if
+// the client uses a custom round tripper
+func (r *Requester) transport() (*http.Transport, bool) {
+	switch transport := r.HTTPClient.Transport.(type) {
+	case nil:
+	case *http.Transport:
+		if transport != nil {
+			return transport.Clone(), true
+		}
+	default:
+		return nil, false
+	}
+	return http.DefaultTransport.(*http.Transport).Clone(), true
+}
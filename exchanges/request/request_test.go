@@ -1,8 +1,13 @@
 package request
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -192,7 +197,7 @@ func TestIsValidCycle(t *testing.T) {
 
 func TestCheckRequest(t *testing.T) {
 	r := New("", NewRateLimit(time.Second*10, 5), NewRateLimit(time.Second*20, 100), new(http.Client))
-	_, err := r.checkRequest("bad method, bad", "http://www.google.com", nil, nil)
+	_, err := r.checkRequest(context.Background(), "bad method, bad", "http://www.google.com", nil, nil)
 	if err == nil {
 		t.Fatal("unexpected values")
 	}
@@ -322,3 +327,199 @@ func TestDoRequest(t *testing.T) {
 		t.Error("failed to set proxy")
 	}
 }
+
+func TestSendPayloadWithContextInFlight(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(time.Second * 10):
+		}
+	}))
+	defer srv.Close()
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	err := r.SendPayloadWithContext(nil, "GET", srv.URL, nil, nil, nil, false, false)
+	if err == nil {
+		t.Fatal("test failed - nil context accepted")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*50, cancel)
+
+	start := time.Now()
+	err = r.SendPayloadWithContext(ctx, "GET", srv.URL, nil, nil, nil, false, false)
+	if err != context.Canceled {
+		t.Fatalf("test failed - unexpected error %v", err)
+	}
+
+	if time.Since(start) > time.Second*2 {
+		t.Fatal("test failed - in-flight request not aborted promptly")
+	}
+}
+
+func TestSendPayloadWithContextQueued(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer srv.Close()
+
+	r := New("test", NewRateLimit(time.Second*10, 1), NewRateLimit(time.Second*10, 1), new(http.Client))
+	r.m.Lock()
+	r.StartCycle()
+	r.WorkerStarted = true
+	go r.worker()
+	r.m.Unlock()
+	r.UnauthLimit.SetRequests(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	start := time.Now()
+	err := r.SendPayloadWithContext(ctx, "GET", srv.URL, nil, nil, nil, false, false)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("test failed - unexpected error %v", err)
+	}
+
+	if time.Since(start) > time.Second*2 {
+		t.Fatal("test failed - queued request not aborted promptly")
+	}
+
+	err = r.SendPayloadWithContext(ctx, "GET", srv.URL, nil, nil, nil, false, false)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("test failed - expired context accepted %v", err)
+	}
+}
+
+func TestSendCacheablePayload(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"price":1337}`)
+	}))
+	defer srv.Close()
+
+	var resp struct {
+		Price float64 `json:"price"`
+	}
+
+	r := New("test", NewRateLimit(time.Second*10, 100), NewRateLimit(time.Second*10, 100), new(http.Client))
+	for i := 0; i < 2; i++ {
+		err := r.SendCacheablePayload(srv.URL, nil, &resp, time.Second, false)
+		if err != nil {
+			t.Fatalf("test failed - SendCacheablePayload error %v", err)
+		}
+		if resp.Price != 1337 {
+			t.Fatalf("test failed - unexpected response %v", resp)
+		}
+	}
+
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Fatalf("test failed - cached request hit the transport %d times", hits)
+	}
+
+	if r.UnauthLimit.GetRequests() != 1 {
+		t.Fatal("test failed - cached request consumed the rate limit")
+	}
+
+	err := r.SendCacheablePayload(srv.URL+"/other", nil, &resp, time.Millisecond, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 10)
+	err = r.SendCacheablePayload(srv.URL+"/other", nil, &resp, time.Millisecond, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&hits) != 3 {
+		t.Fatal("test failed - expired response served from the cache")
+	}
+
+	err = r.SendPayload("GET", srv.URL, nil, nil, &resp, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&hits) != 4 {
+		t.Fatal("test failed - non-cacheable request served from the cache")
+	}
+}
+
+func TestResponseCacheSize(t *testing.T) {
+	var c responseCache
+	for i := 0; i < maxCacheEntries+10; i++ {
+		c.set(fmt.Sprintf("GET %d", i), []byte("{}"), time.Minute)
+	}
+
+	if c.len() != maxCacheEntries {
+		t.Fatalf("test failed - cache exceeded size bound %d", c.len())
+	}
+
+	_, ok := c.get("GET 0")
+	if ok {
+		t.Fatal("test failed - oldest entry not evicted")
+	}
+
+	_, ok = c.get(fmt.Sprintf("GET %d", maxCacheEntries+9))
+	if !ok {
+		t.Fatal("test failed - newest entry evicted")
+	}
+}
+
+// blackHoleListener accepts connections which are never written to until the
+// returned func is called
+func blackHoleListener(t *testing.T) (string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	return l.Addr().String(), func() { l.Close() }
+}
+
+func TestSetTimeout(t *testing.T) {
+	addr, closeListener := blackHoleListener(t)
+	defer closeListener()
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	r.SetTimeout(time.Millisecond * 100)
+	if err := r.SetTimeoutRetryAttempts(0); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout, tlsHandshake, responseHeader := r.GetTimeouts()
+	if timeout != time.Millisecond*100 || tlsHandshake != timeout || responseHeader != timeout {
+		t.Fatalf("test failed - unexpected timeouts %v %v %v", timeout, tlsHandshake,
+			responseHeader)
+	}
+
+	for _, path := range []string{"http://" + addr, "https://" + addr} {
+		start := time.Now()
+		err := r.SendPayload("GET", path, nil, nil, nil, false, false)
+		if err == nil {
+			t.Fatalf("test failed - %s request to an unresponsive endpoint succeeded", path)
+		}
+		if time.Since(start) > time.Second*2 {
+			t.Errorf("test failed - %s request took %v to time out", path, time.Since(start))
+		}
+	}
+
+	proxy, err := url.Parse("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.SetProxy(proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, tlsHandshake, responseHeader = r.GetTimeouts(); tlsHandshake != timeout ||
+		responseHeader != timeout {
+		t.Errorf("test failed - proxy reset the timeouts to %v %v", tlsHandshake,
+			responseHeader)
+	}
+}
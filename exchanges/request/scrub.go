@@ -0,0 +1,170 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// RedactedValue replaces credential values in verbose logs
+	RedactedValue = "[REDACTED]"
+	// DefaultVerboseBodyLimit is the number of bytes of a request or response
+	// body logged verbosely when the requester has no limit set
+	DefaultVerboseBodyLimit = 4096
+)
+
+// sensitiveNameSuffixes holds the normalised parameter and header name
+// suffixes whose values are credentials, e.g. api_key, OK-ACCESS-SIGN and
+// otpToken
+var sensitiveNameSuffixes = []string{
+	"apikey",
+	"accesskey",
+	"secret",
+	"secretkey",
+	"sign",
+	"signature",
+	"passphrase",
+	"password",
+	"pwd",
+	"otp",
+	"otptoken",
+	"authorization",
+}
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// requestIDCounter holds the last request ID assigned
+var requestIDCounter uint64
+
+// WithRequestID returns a context carrying a request ID used to correlate the
+// verbose request and response lines of a request, and the ID. The existing
+// ID is returned if the context already carries one
+func WithRequestID(ctx context.Context) (context.Context, string) {
+	if id := RequestID(ctx); id != "" {
+		return ctx, id
+	}
+	id := strconv.FormatUint(atomic.AddUint64(&requestIDCounter, 1), 10)
+	return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+// RequestID returns the request ID carried by the context, or an empty string
+// if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// IsSensitiveName returns whether a parameter or header name holds a
+// credential. Names are compared case insensitively ignoring dashes and
+// underscores
+func IsSensitiveName(name string) bool {
+	normalised := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for x := range sensitiveNameSuffixes {
+		if strings.HasSuffix(normalised, sensitiveNameSuffixes[x]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrubHeaders returns a copy of the headers with credential values redacted
+func ScrubHeaders(headers map[string]string) map[string]string {
+	scrubbed := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if IsSensitiveName(k) {
+			v = RedactedValue
+		}
+		scrubbed[k] = v
+	}
+	return scrubbed
+}
+
+// ScrubURL returns the URL with credential query parameters redacted
+func ScrubURL(rawURL string) string {
+	i := strings.IndexByte(rawURL, '?')
+	if i < 0 {
+		return rawURL
+	}
+	return rawURL[:i+1] + scrubForm(rawURL[i+1:])
+}
+
+// ScrubBody returns a JSON or URL encoded request body with credential values
+// redacted. Other bodies are returned unchanged
+func ScrubBody(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return body
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var data interface{}
+		if decoder.Decode(&data) == nil {
+			scrubbed, err := json.Marshal(scrubJSON(data))
+			if err == nil {
+				return string(scrubbed)
+			}
+		}
+	}
+
+	if strings.Contains(body, "=") {
+		return scrubForm(body)
+	}
+	return body
+}
+
+// TruncateBody shortens a body to the limit, noting how many bytes were
+// removed. Bodies aren't truncated if the limit isn't positive
+func TruncateBody(body string, limit int) string {
+	if limit <= 0 || len(body) <= limit {
+		return body
+	}
+	return fmt.Sprintf("%s... [%d bytes truncated]", body[:limit],
+		len(body)-limit)
+}
+
+// scrubForm redacts the credential values of URL encoded parameters keeping
+// their order
+func scrubForm(form string) string {
+	params := strings.Split(form, "&")
+	for x := range params {
+		kv := strings.SplitN(params[x], "=", 2)
+		key, err := url.QueryUnescape(kv[0])
+		if err != nil {
+			key = kv[0]
+		}
+		if len(kv) == 2 && IsSensitiveName(key) {
+			params[x] = kv[0] + "=" + RedactedValue
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// scrubJSON redacts the credential values of decoded JSON objects
+func scrubJSON(data interface{}) interface{} {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		for k, v := range d {
+			if IsSensitiveName(k) {
+				d[k] = RedactedValue
+				continue
+			}
+			d[k] = scrubJSON(v)
+		}
+	case []interface{}:
+		for x := range d {
+			d[x] = scrubJSON(d[x])
+		}
+	}
+	return data
+}
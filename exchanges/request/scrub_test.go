@@ -0,0 +1,153 @@
+package request
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsSensitiveName(t *testing.T) {
+	sensitive := []string{"api_key", "apiKey", "sign", "secret_key", "Authorization",
+		"OK-ACCESS-KEY", "OK-ACCESS-SIGN", "OK-ACCESS-PASSPHRASE", "api-signature",
+		"otpToken", "otp", "trade_pwd", "password"}
+	for x := range sensitive {
+		if !IsSensitiveName(sensitive[x]) {
+			t.Errorf("test failed - %s not treated as sensitive", sensitive[x])
+		}
+	}
+
+	public := []string{"symbol", "amount", "api-expires", "OK-ACCESS-TIMESTAMP",
+		"Content-Type", "currency"}
+	for x := range public {
+		if IsSensitiveName(public[x]) {
+			t.Errorf("test failed - %s treated as sensitive", public[x])
+		}
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	tests := map[string]string{
+		// URL encoded, order is kept
+		"amount=1&api_key=abc&sign=DEF&symbol=btc_usd": "amount=1&api_key=[REDACTED]&sign=[REDACTED]&symbol=btc_usd",
+		"api%5Fkey=abc&trade_pwd=hunter2":              "api%5Fkey=[REDACTED]&trade_pwd=[REDACTED]",
+		"symbol=btc_usd&flag":                          "symbol=btc_usd&flag",
+		// JSON, nested objects and arrays are scrubbed
+		`{"symbol":"XBTUSD","otpToken":"123456","orderQty":1.5}`:         `{"orderQty":1.5,"otpToken":"[REDACTED]","symbol":"XBTUSD"}`,
+		`[{"auth":{"secret":"s","passphrase":"p"},"id":12345678901234}]`: `[{"auth":{"passphrase":"[REDACTED]","secret":"[REDACTED]"},"id":12345678901234}]`,
+		// Other bodies are untouched
+		"":            "",
+		"plain text":  "plain text",
+		`{"broken":1`: `{"broken":1`,
+	}
+	for body, expected := range tests {
+		if result := ScrubBody(body); result != expected {
+			t.Errorf("test failed - ScrubBody(%q) expected %q got %q", body,
+				expected, result)
+		}
+	}
+}
+
+func TestScrubURL(t *testing.T) {
+	tests := map[string]string{
+		"https://www.okex.com/api/v1/ticker.do":                         "https://www.okex.com/api/v1/ticker.do",
+		"https://www.okex.com/api/v1/userinfo.do?api_key=abc&sign=DEF":  "https://www.okex.com/api/v1/userinfo.do?api_key=[REDACTED]&sign=[REDACTED]",
+		"https://api.example.com/private?nonce=1&apikey=abc&symbol=BTC": "https://api.example.com/private?nonce=1&apikey=[REDACTED]&symbol=BTC",
+	}
+	for u, expected := range tests {
+		if result := ScrubURL(u); result != expected {
+			t.Errorf("test failed - ScrubURL(%q) expected %q got %q", u, expected,
+				result)
+		}
+	}
+}
+
+func TestScrubHeaders(t *testing.T) {
+	headers := map[string]string{
+		"api-key":       "abc",
+		"api-signature": "def",
+		"api-expires":   "1546300800",
+		"Authorization": "Bearer token",
+		"Content-Type":  "application/json",
+	}
+	scrubbed := ScrubHeaders(headers)
+	if scrubbed["api-key"] != RedactedValue || scrubbed["api-signature"] != RedactedValue ||
+		scrubbed["Authorization"] != RedactedValue {
+		t.Errorf("test failed - credentials not redacted %v", scrubbed)
+	}
+	if scrubbed["api-expires"] != "1546300800" || scrubbed["Content-Type"] != "application/json" {
+		t.Errorf("test failed - public headers redacted %v", scrubbed)
+	}
+	if headers["api-key"] != "abc" {
+		t.Error("test failed - ScrubHeaders() modified the supplied headers")
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	if result := TruncateBody("abcdef", 4); result != "abcd... [2 bytes truncated]" {
+		t.Errorf("test failed - unexpected truncated body %q", result)
+	}
+	if result := TruncateBody("abcdef", 6); result != "abcdef" {
+		t.Errorf("test failed - body at the limit truncated %q", result)
+	}
+	if result := TruncateBody("abcdef", -1); result != "abcdef" {
+		t.Errorf("test failed - body truncated without a limit %q", result)
+	}
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	long := strings.Repeat("a", DefaultVerboseBodyLimit+1)
+	if !strings.HasSuffix(r.truncateVerboseBody(long), "[1 bytes truncated]") {
+		t.Error("test failed - default verbose body limit not applied")
+	}
+	r.VerboseBodyLimit = -1
+	if r.truncateVerboseBody(long) != long {
+		t.Error("test failed - negative verbose body limit truncated the body")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	if RequestID(context.Background()) != "" {
+		t.Error("test failed - request ID found on an empty context")
+	}
+
+	ctx, id := WithRequestID(context.Background())
+	if id == "" || RequestID(ctx) != id {
+		t.Fatalf("test failed - request ID %q not carried by the context", id)
+	}
+
+	same, sameID := WithRequestID(ctx)
+	if same != ctx || sameID != id {
+		t.Error("test failed - existing request ID replaced")
+	}
+
+	_, other := WithRequestID(context.Background())
+	if other == id {
+		t.Error("test failed - request IDs not unique")
+	}
+}
+
+func TestVerboseRequestBody(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		received = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	r := New("test", NewRateLimit(time.Second, 0), NewRateLimit(time.Second, 0), new(http.Client))
+	body := "api_key=abc&sign=DEF&symbol=btc_usd"
+	err := r.SendPayload("POST", srv.URL, map[string]string{"api-key": "abc"},
+		strings.NewReader(body), nil, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Logging the body must neither consume nor scrub the body sent
+	if received != body {
+		t.Errorf("test failed - expected body %q sent, got %q", body, received)
+	}
+}
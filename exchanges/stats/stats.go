@@ -2,17 +2,19 @@ package stats
 
 import (
 	"sort"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 )
 
 // Item holds various fields for storing currency pair stats
 type Item struct {
-	Exchange  string
-	Pair      pair.CurrencyPair
-	AssetType string
-	Price     float64
-	Volume    float64
+	Exchange    string
+	Pair        pair.CurrencyPair
+	AssetType   string
+	Price       float64
+	Volume      float64
+	LastUpdated time.Time
 }
 
 // Items var array
@@ -75,11 +77,12 @@ func Append(exchange string, p pair.CurrencyPair, assetType string, price, volum
 	}
 
 	i := Item{
-		Exchange:  exchange,
-		Pair:      p,
-		AssetType: assetType,
-		Price:     price,
-		Volume:    volume,
+		Exchange:    exchange,
+		Pair:        p,
+		AssetType:   assetType,
+		Price:       price,
+		Volume:      volume,
+		LastUpdated: time.Now(),
 	}
 
 	Items = append(Items, i)
@@ -91,6 +94,7 @@ func AlreadyExists(exchange string, p pair.CurrencyPair, assetType string, price
 	for i := range Items {
 		if Items[i].Exchange == exchange && Items[i].Pair.Equal(p, false) && Items[i].AssetType == assetType {
 			Items[i].Price, Items[i].Volume = price, volume
+			Items[i].LastUpdated = time.Now()
 			return true
 		}
 	}
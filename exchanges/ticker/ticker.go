@@ -2,6 +2,8 @@ package ticker
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"strconv"
 	"sync"
 	"time"
@@ -15,28 +17,78 @@ const (
 	ErrTickerForExchangeNotFound = "Ticker for exchange does not exist."
 	ErrPrimaryCurrencyNotFound   = "Error primary currency for ticker not found."
 	ErrSecondaryCurrencyNotFound = "Error secondary currency for ticker not found."
+	ErrAssetTypeNotFound         = "Error asset type for ticker not found."
 
 	Spot = "SPOT"
+
+	// MaxCrossedSpread is the fraction the bid can exceed the ask by before a
+	// ticker is rejected as crossed
+	MaxCrossedSpread = 0.1
+
+	// PriceJumpConfirmations is the number of consecutive tickers which must
+	// agree on a last price beyond the price jump limit before it's accepted
+	PriceJumpConfirmations = 3
 )
 
+// ErrInvalidTicker is matched by ticker validation errors using errors.Is
+var ErrInvalidTicker = errors.New("invalid ticker")
+
 // Vars for the ticker package
 var (
 	Tickers []Ticker
 	m       sync.Mutex
+
+	// priceJumpLimit is the multiple the last price can move from the stored
+	// last price before a ticker is rejected, zero disables the check
+	priceJumpLimit float64
+	pendingJumps   = make(map[priceJumpKey]priceJump)
+	rejected       = make(map[string]int64)
 )
 
-// Price struct stores the currency pair and pricing information
+// priceJumpKey identifies the stored ticker a price jump is pending for
+type priceJumpKey struct {
+	exchangeName string
+	first        pair.CurrencyItem
+	second       pair.CurrencyItem
+	tickerType   string
+}
+
+// priceJump is a last price beyond the price jump limit and the number of
+// consecutive tickers which have agreed on it
+type priceJump struct {
+	last          float64
+	confirmations int
+}
+
+// Price struct stores the currency pair and pricing information. The bid and
+// ask sizes and 24 hour open and change values are zero when the exchange
+// doesn't supply them
 type Price struct {
-	Pair         pair.CurrencyPair `json:"Pair"`
-	LastUpdated  time.Time         `json:"LastUpdated"`
-	CurrencyPair string            `json:"CurrencyPair"`
-	Last         float64           `json:"Last"`
-	High         float64           `json:"High"`
-	Low          float64           `json:"Low"`
-	Bid          float64           `json:"Bid"`
-	Ask          float64           `json:"Ask"`
-	Volume       float64           `json:"Volume"`
-	PriceATH     float64           `json:"PriceATH"`
+	Pair          pair.CurrencyPair `json:"Pair"`
+	LastUpdated   time.Time         `json:"LastUpdated"`
+	CurrencyPair  string            `json:"CurrencyPair"`
+	Last          float64           `json:"Last"`
+	High          float64           `json:"High"`
+	Low           float64           `json:"Low"`
+	Bid           float64           `json:"Bid"`
+	BidSize       float64           `json:"BidSize"`
+	Ask           float64           `json:"Ask"`
+	AskSize       float64           `json:"AskSize"`
+	Volume        float64           `json:"Volume"`
+	Open          float64           `json:"Open"`
+	Change        float64           `json:"Change"`
+	ChangePercent float64           `json:"ChangePercent"`
+	PriceATH      float64           `json:"PriceATH"`
+}
+
+// SetOpen sets the 24 hour open price and the change of the last price from
+// it. The change is left unset if either price is unknown
+func (p *Price) SetOpen(open float64) {
+	p.Open = open
+	if open > 0 && p.Last > 0 {
+		p.Change = p.Last - open
+		p.ChangePercent = p.Change / open * 100
+	}
 }
 
 // Ticker struct holds the ticker information for a currency pair and type
@@ -60,8 +112,18 @@ func (t *Ticker) PriceToString(p pair.CurrencyPair, priceType, tickerType string
 		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].Bid, 'f', -1, 64)
 	case "ask":
 		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].Ask, 'f', -1, 64)
+	case "bidsize":
+		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].BidSize, 'f', -1, 64)
+	case "asksize":
+		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].AskSize, 'f', -1, 64)
 	case "volume":
 		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].Volume, 'f', -1, 64)
+	case "open":
+		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].Open, 'f', -1, 64)
+	case "change":
+		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].Change, 'f', -1, 64)
+	case "changepercent":
+		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].ChangePercent, 'f', -1, 64)
 	case "ath":
 		return strconv.FormatFloat(t.Price[p.FirstCurrency][p.SecondCurrency][tickerType].PriceATH, 'f', -1, 64)
 	default:
@@ -84,7 +146,13 @@ func GetTicker(exchange string, p pair.CurrencyPair, tickerType string) (Price,
 		return Price{}, errors.New(ErrSecondaryCurrencyNotFound)
 	}
 
-	return ticker.Price[p.FirstCurrency][p.SecondCurrency][tickerType], nil
+	m.Lock()
+	defer m.Unlock()
+	price, ok := ticker.Price[p.FirstCurrency][p.SecondCurrency][tickerType]
+	if !ok {
+		return Price{}, errors.New(ErrAssetTypeNotFound)
+	}
+	return price, nil
 }
 
 // GetTickerByExchange returns an exchange Ticker
@@ -135,6 +203,12 @@ func SecondCurrencyExists(exchange string, p pair.CurrencyPair) bool {
 func CreateNewTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price, tickerType string) Ticker {
 	m.Lock()
 	defer m.Unlock()
+	return createNewTicker(exchangeName, p, tickerNew, tickerType)
+}
+
+// createNewTicker creates a new Ticker and appends it to the Tickers list,
+// the caller must hold the ticker lock
+func createNewTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price, tickerType string) Ticker {
 	ticker := Ticker{}
 	ticker.ExchangeName = exchangeName
 	ticker.Price = make(map[pair.CurrencyItem]map[pair.CurrencyItem]map[string]Price)
@@ -147,9 +221,113 @@ func CreateNewTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price,
 	return ticker
 }
 
-// ProcessTicker processes incoming tickers, creating or updating the Tickers
-// list
-func ProcessTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price, tickerType string) {
+// SetPriceJumpLimit sets the multiple the last price of a ticker can move from
+// the stored last price by before the ticker is rejected, zero disables the
+// check
+func SetPriceJumpLimit(multiple float64) {
+	m.Lock()
+	priceJumpLimit = multiple
+	pendingJumps = make(map[priceJumpKey]priceJump)
+	m.Unlock()
+}
+
+// GetRejectedCounts returns the number of tickers rejected by ProcessTicker
+// for each exchange
+func GetRejectedCounts() map[string]int64 {
+	m.Lock()
+	defer m.Unlock()
+	counts := make(map[string]int64, len(rejected))
+	for exchangeName, count := range rejected {
+		counts[exchangeName] = count
+	}
+	return counts
+}
+
+// validatePrice checks a ticker. Prices and volumes must be finite and
+// non-negative, a last, bid or ask price must be set and the bid can't exceed
+// the ask by more than MaxCrossedSpread
+func validatePrice(tickerNew Price) error {
+	for _, v := range []struct {
+		name  string
+		value float64
+	}{
+		{"last", tickerNew.Last},
+		{"high", tickerNew.High},
+		{"low", tickerNew.Low},
+		{"bid", tickerNew.Bid},
+		{"bid size", tickerNew.BidSize},
+		{"ask", tickerNew.Ask},
+		{"ask size", tickerNew.AskSize},
+		{"volume", tickerNew.Volume},
+		{"open", tickerNew.Open},
+		{"all time high", tickerNew.PriceATH},
+	} {
+		if v.value < 0 || math.IsNaN(v.value) || math.IsInf(v.value, 0) {
+			return fmt.Errorf("%s %v must be a non-negative number", v.name, v.value)
+		}
+	}
+
+	if tickerNew.Last == 0 && tickerNew.Bid == 0 && tickerNew.Ask == 0 {
+		return errors.New("last, bid and ask prices not set")
+	}
+
+	if tickerNew.Bid > 0 && tickerNew.Ask > 0 &&
+		tickerNew.Bid > tickerNew.Ask*(1+MaxCrossedSpread) {
+		return fmt.Errorf("bid %v exceeds ask %v", tickerNew.Bid, tickerNew.Ask)
+	}
+	return nil
+}
+
+// withinPriceJumpLimit returns whether the last price is within the price jump
+// limit of the reference price
+func withinPriceJumpLimit(last, reference float64) bool {
+	return last <= reference*priceJumpLimit && last >= reference/priceJumpLimit
+}
+
+// checkPriceJump rejects a last price which moved from the stored last price
+// by more than the price jump limit, so a single bad price can't replace it.
+// A genuine move is accepted once PriceJumpConfirmations consecutive tickers
+// agree on it, each within the limit of the first, otherwise the stored price
+// would never catch up and every later ticker would be rejected. A price
+// within the limit of the stored price discards the pending jump. It must be
+// called with the ticker lock held
+func checkPriceJump(key priceJumpKey, last, previous float64) error {
+	if priceJumpLimit <= 0 || previous <= 0 || last <= 0 ||
+		withinPriceJumpLimit(last, previous) {
+		delete(pendingJumps, key)
+		return nil
+	}
+
+	jump, ok := pendingJumps[key]
+	if !ok || !withinPriceJumpLimit(last, jump.last) {
+		jump = priceJump{last: last}
+	}
+	jump.confirmations++
+	if jump.confirmations >= PriceJumpConfirmations {
+		delete(pendingJumps, key)
+		return nil
+	}
+	pendingJumps[key] = jump
+	return fmt.Errorf("last price %v moved more than %vx from %v, %d of %d confirmations",
+		last, priceJumpLimit, previous, jump.confirmations, PriceJumpConfirmations)
+}
+
+// ProcessTicker validates incoming tickers and creates or updates the Tickers
+// list. Invalid tickers aren't stored, the previous price is kept and the
+// rejection is counted for the exchange. The update is made under a single
+// lock so it can't race with the removal of the ticker
+func ProcessTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price, tickerType string) error {
+	if exchangeName == "" {
+		return fmt.Errorf("%w: exchange name not set", ErrInvalidTicker)
+	}
+	if p.Pair() == "" {
+		m.Lock()
+		rejected[exchangeName]++
+		m.Unlock()
+		return fmt.Errorf("%w: %s currency pair not set", ErrInvalidTicker,
+			exchangeName)
+	}
+
 	if tickerNew.Pair.Pair() == "" {
 		// set Pair if not set
 		tickerNew.Pair = p
@@ -158,26 +336,148 @@ func ProcessTicker(exchangeName string, p pair.CurrencyPair, tickerNew Price, ti
 	tickerNew.CurrencyPair = p.Pair().String()
 	tickerNew.LastUpdated = time.Now()
 
-	ticker, err := GetTickerByExchange(exchangeName)
+	m.Lock()
+	defer m.Unlock()
+	var previous Price
+	var stored bool
+	for x := range Tickers {
+		if Tickers[x].ExchangeName == exchangeName {
+			previous, stored = Tickers[x].Price[p.FirstCurrency][p.SecondCurrency][tickerType]
+			break
+		}
+	}
+	err := validatePrice(tickerNew)
+	if err == nil {
+		key := priceJumpKey{exchangeName, p.FirstCurrency, p.SecondCurrency, tickerType}
+		if stored {
+			err = checkPriceJump(key, tickerNew.Last, previous.Last)
+		} else {
+			delete(pendingJumps, key)
+		}
+	}
 	if err != nil {
-		CreateNewTicker(exchangeName, p, tickerNew, tickerType)
-		return
+		rejected[exchangeName]++
+		return fmt.Errorf("%w: %s %s %s %s", ErrInvalidTicker, exchangeName,
+			p.Pair(), tickerType, err)
 	}
 
-	if FirstCurrencyExists(exchangeName, p.FirstCurrency) {
-		m.Lock()
-		a := make(map[string]Price)
+	for x := range Tickers {
+		if Tickers[x].ExchangeName != exchangeName {
+			continue
+		}
+		second, ok := Tickers[x].Price[p.FirstCurrency]
+		if !ok {
+			second = make(map[pair.CurrencyItem]map[string]Price)
+			Tickers[x].Price[p.FirstCurrency] = second
+		}
+		a, ok := second[p.SecondCurrency]
+		if !ok {
+			a = make(map[string]Price)
+			second[p.SecondCurrency] = a
+		}
 		a[tickerType] = tickerNew
-		ticker.Price[p.FirstCurrency][p.SecondCurrency] = a
-		m.Unlock()
-		return
+		return nil
 	}
+	createNewTicker(exchangeName, p, tickerNew, tickerType)
+	return nil
+}
 
+// RemoveTicker removes the stored ticker of an exchange currency pair and
+// asset type and returns whether it existed. Exchanges left without tickers
+// are removed from the Tickers list
+func RemoveTicker(exchangeName string, p pair.CurrencyPair, tickerType string) bool {
 	m.Lock()
-	a := make(map[pair.CurrencyItem]map[string]Price)
-	b := make(map[string]Price)
-	b[tickerType] = tickerNew
-	a[p.SecondCurrency] = b
-	ticker.Price[p.FirstCurrency] = a
-	m.Unlock()
+	defer m.Unlock()
+	for x := range Tickers {
+		if Tickers[x].ExchangeName != exchangeName {
+			continue
+		}
+		second := Tickers[x].Price[p.FirstCurrency]
+		if _, ok := second[p.SecondCurrency][tickerType]; !ok {
+			return false
+		}
+		delete(second[p.SecondCurrency], tickerType)
+		if len(second[p.SecondCurrency]) == 0 {
+			delete(second, p.SecondCurrency)
+		}
+		if len(second) == 0 {
+			delete(Tickers[x].Price, p.FirstCurrency)
+		}
+		if len(Tickers[x].Price) == 0 {
+			Tickers = append(Tickers[:x], Tickers[x+1:]...)
+		}
+		return true
+	}
+	return false
+}
+
+// RemoveExchangeTickers removes all stored tickers of an exchange and returns
+// the number removed
+func RemoveExchangeTickers(exchangeName string) int {
+	m.Lock()
+	defer m.Unlock()
+	for x := range Tickers {
+		if Tickers[x].ExchangeName == exchangeName {
+			removed := countPrices(Tickers[x])
+			Tickers = append(Tickers[:x], Tickers[x+1:]...)
+			return removed
+		}
+	}
+	return 0
+}
+
+// RemoveStaleTickers removes the stored tickers which haven't been updated
+// within the max age and returns the number removed
+func RemoveStaleTickers(maxAge time.Duration) int {
+	cutoff := time.Now().Add(-maxAge)
+	m.Lock()
+	defer m.Unlock()
+
+	var removed int
+	kept := Tickers[:0]
+	for x := range Tickers {
+		for first, second := range Tickers[x].Price {
+			for secondCurrency, assets := range second {
+				for tickerType, price := range assets {
+					if price.LastUpdated.Before(cutoff) {
+						delete(assets, tickerType)
+						removed++
+					}
+				}
+				if len(assets) == 0 {
+					delete(second, secondCurrency)
+				}
+			}
+			if len(second) == 0 {
+				delete(Tickers[x].Price, first)
+			}
+		}
+		if len(Tickers[x].Price) > 0 {
+			kept = append(kept, Tickers[x])
+		}
+	}
+	Tickers = kept
+	return removed
+}
+
+// GetTickerCounts returns the number of stored tickers of each exchange
+func GetTickerCounts() map[string]int {
+	m.Lock()
+	defer m.Unlock()
+	counts := make(map[string]int, len(Tickers))
+	for x := range Tickers {
+		counts[Tickers[x].ExchangeName] = countPrices(Tickers[x])
+	}
+	return counts
+}
+
+// countPrices returns the number of prices stored in a Ticker
+func countPrices(t Ticker) int {
+	var count int
+	for _, second := range t.Price {
+		for _, assets := range second {
+			count += len(assets)
+		}
+	}
+	return count
 }
@@ -1,6 +1,8 @@
 package ticker
 
 import (
+	"errors"
+	"math"
 	"math/rand"
 	"reflect"
 	"strconv"
@@ -20,10 +22,13 @@ func TestPriceToString(t *testing.T) {
 		High:         1298,
 		Low:          1148,
 		Bid:          1195,
+		BidSize:      0.5,
 		Ask:          1220,
+		AskSize:      1.5,
 		Volume:       5,
 		PriceATH:     1337,
 	}
+	priceStruct.SetOpen(1000)
 
 	newTicker := CreateNewTicker("ANX", newPair, priceStruct, Spot)
 
@@ -45,6 +50,21 @@ func TestPriceToString(t *testing.T) {
 	if newTicker.PriceToString(newPair, "volume", Spot) != "5" {
 		t.Error("Test Failed - ticker PriceToString volume value is incorrect")
 	}
+	if newTicker.PriceToString(newPair, "bidsize", Spot) != "0.5" {
+		t.Error("Test Failed - ticker PriceToString bidsize value is incorrect")
+	}
+	if newTicker.PriceToString(newPair, "asksize", Spot) != "1.5" {
+		t.Error("Test Failed - ticker PriceToString asksize value is incorrect")
+	}
+	if newTicker.PriceToString(newPair, "open", Spot) != "1000" {
+		t.Error("Test Failed - ticker PriceToString open value is incorrect")
+	}
+	if newTicker.PriceToString(newPair, "change", Spot) != "200" {
+		t.Error("Test Failed - ticker PriceToString change value is incorrect")
+	}
+	if newTicker.PriceToString(newPair, "changepercent", Spot) != "20" {
+		t.Error("Test Failed - ticker PriceToString changepercent value is incorrect")
+	}
 	if newTicker.PriceToString(newPair, "ath", Spot) != "1337" {
 		t.Error("Test Failed - ticker PriceToString ath value is incorrect")
 	}
@@ -53,6 +73,26 @@ func TestPriceToString(t *testing.T) {
 	}
 }
 
+func TestSetOpen(t *testing.T) {
+	p := Price{Last: 90}
+	p.SetOpen(100)
+	if p.Open != 100 || p.Change != -10 || p.ChangePercent != -10 {
+		t.Errorf("Test Failed - unexpected change %+v", p)
+	}
+
+	p = Price{Last: 90}
+	p.SetOpen(0)
+	if p.Change != 0 || p.ChangePercent != 0 {
+		t.Errorf("Test Failed - change set without an open price %+v", p)
+	}
+
+	p = Price{}
+	p.SetOpen(100)
+	if p.Open != 100 || p.Change != 0 || p.ChangePercent != 0 {
+		t.Errorf("Test Failed - change set without a last price %+v", p)
+	}
+}
+
 func TestGetTicker(t *testing.T) {
 	newPair := pair.NewCurrencyPair("BTC", "USD")
 	priceStruct := Price{
@@ -103,6 +143,18 @@ func TestGetTicker(t *testing.T) {
 	if tickerPrice.PriceATH != 9001 {
 		t.Error("Test Failed - ticker tickerPrice.PriceATH value is incorrect")
 	}
+
+	newPair.FirstCurrency = "BTC"
+	ProcessTicker("bitfinex", newPair, priceStruct, "futures_3m")
+	tickerPrice, err = GetTicker("bitfinex", newPair, Spot)
+	if err != nil || tickerPrice.PriceATH != 1337 {
+		t.Error("Test Failed - ticker of another asset type replaced the spot ticker")
+	}
+
+	_, err = GetTicker("bitfinex", newPair, "futures_1w")
+	if err == nil {
+		t.Error("Test Failed. TestGetTicker returned ticker for invalid asset type")
+	}
 }
 
 func TestGetTickerByExchange(t *testing.T) {
@@ -333,3 +385,226 @@ func TestProcessTicker(t *testing.T) { //non-appending function to tickers
 	wg.Wait()
 
 }
+
+func TestRemoveTicker(t *testing.T) {
+	Tickers = []Ticker{}
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	btcaud := pair.NewCurrencyPair("BTC", "AUD")
+	ProcessTicker("btcc", btcusd, Price{Last: 1}, Spot)
+	ProcessTicker("btcc", btcaud, Price{Last: 2}, Spot)
+
+	if RemoveTicker("btcc", btcusd, "futures") {
+		t.Error("Test failed. RemoveTicker removed a missing asset type")
+	}
+	if !RemoveTicker("btcc", btcusd, Spot) {
+		t.Error("Test failed. RemoveTicker failed to remove a ticker")
+	}
+	if SecondCurrencyExists("btcc", btcusd) {
+		t.Error("Test failed. RemoveTicker left the removed pair")
+	}
+	if _, err := GetTicker("btcc", btcaud, Spot); err != nil {
+		t.Errorf("Test failed. RemoveTicker removed another pair: %s", err)
+	}
+
+	if !RemoveTicker("btcc", btcaud, Spot) {
+		t.Error("Test failed. RemoveTicker failed to remove a ticker")
+	}
+	if len(Tickers) != 0 {
+		t.Errorf("Test failed. Expected the empty exchange to be removed, got %d", len(Tickers))
+	}
+	if RemoveTicker("btcc", btcaud, Spot) {
+		t.Error("Test failed. RemoveTicker removed a missing exchange ticker")
+	}
+}
+
+func TestRemoveExchangeTickers(t *testing.T) {
+	Tickers = []Ticker{}
+	ProcessTicker("btcc", pair.NewCurrencyPair("BTC", "USD"), Price{Last: 1}, Spot)
+	ProcessTicker("btcc", pair.NewCurrencyPair("LTC", "USD"), Price{Last: 1}, Spot)
+	ProcessTicker("anx", pair.NewCurrencyPair("BTC", "USD"), Price{Last: 1}, Spot)
+
+	if removed := RemoveExchangeTickers("btcc"); removed != 2 {
+		t.Errorf("Test failed. Expected 2 tickers removed, got %d", removed)
+	}
+	if removed := RemoveExchangeTickers("btcc"); removed != 0 {
+		t.Errorf("Test failed. Expected no tickers removed, got %d", removed)
+	}
+	counts := GetTickerCounts()
+	if len(counts) != 1 || counts["anx"] != 1 {
+		t.Errorf("Test failed. Unexpected ticker counts %v", counts)
+	}
+}
+
+func TestRemoveStaleTickers(t *testing.T) {
+	Tickers = []Ticker{}
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ltcusd := pair.NewCurrencyPair("LTC", "USD")
+	ProcessTicker("btcc", btcusd, Price{Last: 1}, Spot)
+	ProcessTicker("btcc", ltcusd, Price{Last: 1}, Spot)
+	ProcessTicker("anx", btcusd, Price{Last: 1}, Spot)
+
+	m.Lock()
+	for x := range Tickers {
+		if Tickers[x].ExchangeName == "anx" {
+			price := Tickers[x].Price[btcusd.FirstCurrency][btcusd.SecondCurrency][Spot]
+			price.LastUpdated = time.Now().Add(-time.Hour)
+			Tickers[x].Price[btcusd.FirstCurrency][btcusd.SecondCurrency][Spot] = price
+		}
+	}
+	m.Unlock()
+
+	if removed := RemoveStaleTickers(time.Minute); removed != 1 {
+		t.Errorf("Test failed. Expected 1 stale ticker removed, got %d", removed)
+	}
+	counts := GetTickerCounts()
+	if len(counts) != 1 || counts["btcc"] != 2 {
+		t.Errorf("Test failed. Unexpected ticker counts %v", counts)
+	}
+}
+
+func TestRemoveTickerRace(t *testing.T) {
+	Tickers = []Ticker{}
+	pairs := []pair.CurrencyPair{
+		pair.NewCurrencyPair("BTC", "USD"),
+		pair.NewCurrencyPair("BTC", "AUD"),
+		pair.NewCurrencyPair("LTC", "USD"),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(4)
+		p := pairs[i%len(pairs)]
+		evictAll := i%10 == 0
+		go func() {
+			ProcessTicker("btcc", p, Price{Last: 1}, Spot)
+			wg.Done()
+		}()
+		go func() {
+			RemoveTicker("btcc", p, Spot)
+			wg.Done()
+		}()
+		go func() {
+			if evictAll {
+				RemoveExchangeTickers("btcc")
+			} else {
+				RemoveStaleTickers(0)
+			}
+			wg.Done()
+		}()
+		go func() {
+			GetTicker("btcc", p, Spot)
+			GetTickerCounts()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	RemoveExchangeTickers("btcc")
+	for x := range pairs {
+		ProcessTicker("btcc", pairs[x], Price{Last: 1}, Spot)
+	}
+	if counts := GetTickerCounts(); counts["btcc"] != len(pairs) {
+		t.Errorf("Test failed. Expected %d tickers after eviction, got %d",
+			len(pairs), counts["btcc"])
+	}
+}
+
+func TestProcessTickerValidation(t *testing.T) {
+	defer SetPriceJumpLimit(0)
+	p := pair.NewCurrencyPair("XRP", "USD")
+	good := Price{Last: 100, Bid: 99, Ask: 101, Volume: 10}
+	if err := ProcessTicker("validation", p, good, Spot); err != nil {
+		t.Fatal(err)
+	}
+	rejectedBefore := GetRejectedCounts()["validation"]
+
+	SetPriceJumpLimit(5)
+	for name, test := range map[string]struct {
+		exchange string
+		pair     pair.CurrencyPair
+		price    Price
+	}{
+		"no exchange":     {"", p, good},
+		"no pair":         {"validation", pair.CurrencyPair{}, good},
+		"no price":        {"validation", p, Price{Volume: 10}},
+		"negative last":   {"validation", p, Price{Last: -1}},
+		"negative volume": {"validation", p, Price{Last: 100, Volume: -1}},
+		"negative ask":    {"validation", p, Price{Last: 100, Ask: -1}},
+		"NaN high":        {"validation", p, Price{Last: 100, High: math.NaN()}},
+		"crossed":         {"validation", p, Price{Last: 100, Bid: 150, Ask: 100}},
+		"jumped up":       {"validation", p, Price{Last: 501}},
+		"jumped down":     {"validation", p, Price{Last: 19}},
+	} {
+		err := ProcessTicker(test.exchange, test.pair, test.price, Spot)
+		if !errors.Is(err, ErrInvalidTicker) {
+			t.Errorf("Test failed - %s ticker expected %v, received %v", name,
+				ErrInvalidTicker, err)
+		}
+	}
+
+	// The last good price remains stored
+	result, err := GetTicker("validation", p, Spot)
+	if err != nil || result.Last != good.Last || result.Bid != good.Bid {
+		t.Errorf("Test failed - expected the previous ticker to be kept, received %+v error: %v",
+			result, err)
+	}
+	if rejected := GetRejectedCounts()["validation"] - rejectedBefore; rejected != 9 {
+		t.Errorf("Test failed - expected 9 rejected tickers, received %d", rejected)
+	}
+
+	// A slightly crossed book and moves within the limit are accepted
+	for _, price := range []Price{{Last: 450, Bid: 105, Ask: 100}, {Last: 91}} {
+		if err = ProcessTicker("validation", p, price, Spot); err != nil {
+			t.Errorf("Test failed - expected %+v to be accepted: %v", price, err)
+		}
+	}
+
+	// Without a limit any move is accepted
+	SetPriceJumpLimit(0)
+	if err = ProcessTicker("validation", p, Price{Last: 100000}, Spot); err != nil {
+		t.Errorf("Test failed - expected the jump to be accepted without a limit: %v", err)
+	}
+}
+
+func TestProcessTickerPriceJumpConfirmation(t *testing.T) {
+	defer SetPriceJumpLimit(0)
+	defer RemoveExchangeTickers("jumps")
+	p := pair.NewCurrencyPair("XRP", "USD")
+	SetPriceJumpLimit(5)
+	if err := ProcessTicker("jumps", p, Price{Last: 100}, Spot); err != nil {
+		t.Fatal(err)
+	}
+
+	process := func(last float64, accepted bool) {
+		err := ProcessTicker("jumps", p, Price{Last: last}, Spot)
+		if accepted && err != nil {
+			t.Errorf("Test failed - expected %v to be accepted: %v", last, err)
+		}
+		if !accepted && !errors.Is(err, ErrInvalidTicker) {
+			t.Errorf("Test failed - expected %v to be rejected, received %v", last, err)
+		}
+	}
+
+	// A one off bad price is discarded by the next price within the limit
+	process(1000, false)
+	process(101, true)
+	process(1000, false)
+	process(1000, false)
+	process(102, true)
+
+	// Inconsistent jumps restart the confirmations
+	process(1000, false)
+	process(10000, false)
+	process(1000, false)
+
+	// A genuine move is accepted once confirmed by consecutive tickers and
+	// becomes the reference for later tickers
+	process(900, false)
+	process(950, true)
+	process(960, true)
+	result, err := GetTicker("jumps", p, Spot)
+	if err != nil || result.Last != 960 {
+		t.Errorf("Test failed - expected the confirmed move to be stored, received %+v error: %v",
+			result, err)
+	}
+}
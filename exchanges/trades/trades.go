@@ -0,0 +1,209 @@
+// Package trades keeps a bounded buffer of the most recent trades of each
+// exchange, currency pair and asset type and computes rolling trade activity
+// statistics from them
+package trades
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+// DefaultBufferSize is the number of recent trades kept per exchange,
+// currency pair and asset type
+const DefaultBufferSize = 1000
+
+// Trade sides, trades whose side the exchange doesn't report have no side
+const (
+	Buy  = "BUY"
+	Sell = "SELL"
+)
+
+// ErrNoTrades is returned when no trades are stored for an exchange, currency
+// pair and asset type
+var ErrNoTrades = errors.New("no trades stored")
+
+// Vars for the trades package
+var (
+	buffers    = make(map[string]*buffer)
+	bufferSize = DefaultBufferSize
+	m          sync.RWMutex
+)
+
+// Trade holds a single executed trade
+type Trade struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+	Amount    float64   `json:"amount"`
+	Side      string    `json:"side,omitempty"`
+}
+
+// Stats holds the trade activity of an exchange, currency pair and asset type
+// over a window ending at the time the stats were computed. Imbalance is the
+// buy volume less the sell volume divided by their sum, ranging from -1 when
+// only sells traded to 1 when only buys traded
+type Stats struct {
+	Exchange        string    `json:"exchange"`
+	Pair            string    `json:"pair"`
+	AssetType       string    `json:"assetType"`
+	Window          string    `json:"window"`
+	Trades          int       `json:"trades"`
+	TradesPerMinute float64   `json:"tradesPerMinute"`
+	Volume          float64   `json:"volume"`
+	BuyVolume       float64   `json:"buyVolume"`
+	SellVolume      float64   `json:"sellVolume"`
+	Imbalance       float64   `json:"imbalance"`
+	LastPrice       float64   `json:"lastPrice"`
+	LastAmount      float64   `json:"lastAmount"`
+	LastSide        string    `json:"lastSide,omitempty"`
+	LastTrade       time.Time `json:"lastTrade"`
+}
+
+// buffer is a fixed size ring of the most recent trades, the oldest trade is
+// overwritten once it is full
+type buffer struct {
+	trades []Trade
+	next   int
+	count  int
+}
+
+// add stores a trade, overwriting the oldest one if the buffer is full
+func (b *buffer) add(t Trade) {
+	b.trades[b.next] = t
+	b.next = (b.next + 1) % len(b.trades)
+	if b.count < len(b.trades) {
+		b.count++
+	}
+}
+
+// newest returns the trade stored x trades before the most recent one
+func (b *buffer) newest(x int) Trade {
+	return b.trades[(b.next-1-x+len(b.trades))%len(b.trades)]
+}
+
+// SetBufferSize sets the number of trades kept per exchange, currency pair and
+// asset type and clears the stored trades. A size below one restores the
+// default
+func SetBufferSize(size int) {
+	if size < 1 {
+		size = DefaultBufferSize
+	}
+	m.Lock()
+	bufferSize = size
+	buffers = make(map[string]*buffer)
+	m.Unlock()
+}
+
+// NormaliseSide maps the trade side reported by an exchange to Buy or Sell,
+// unknown sides return an empty string
+func NormaliseSide(side string) string {
+	switch common.StringToUpper(side) {
+	case "BUY", "BID", "B":
+		return Buy
+	case "SELL", "ASK", "S":
+		return Sell
+	}
+	return ""
+}
+
+// Add stores a trade for an exchange, currency pair and asset type. A trade
+// without a timestamp is stamped with the current time
+func Add(exchangeName string, p pair.CurrencyPair, assetType string, t Trade) {
+	if t.Timestamp.IsZero() {
+		t.Timestamp = time.Now()
+	}
+	t.Side = NormaliseSide(t.Side)
+
+	key := getKey(exchangeName, p, assetType)
+	m.Lock()
+	b, ok := buffers[key]
+	if !ok {
+		b = &buffer{trades: make([]Trade, bufferSize)}
+		buffers[key] = b
+	}
+	b.add(t)
+	m.Unlock()
+}
+
+// GetRecentTrades returns up to limit of the most recent trades of an
+// exchange, currency pair and asset type, newest first. A limit below one
+// returns every stored trade
+func GetRecentTrades(exchangeName string, p pair.CurrencyPair, assetType string, limit int) ([]Trade, error) {
+	m.RLock()
+	defer m.RUnlock()
+	b, ok := buffers[getKey(exchangeName, p, assetType)]
+	if !ok || b.count == 0 {
+		return nil, ErrNoTrades
+	}
+
+	if limit < 1 || limit > b.count {
+		limit = b.count
+	}
+	recent := make([]Trade, limit)
+	for x := range recent {
+		recent[x] = b.newest(x)
+	}
+	return recent, nil
+}
+
+// GetStats returns the trade activity of an exchange, currency pair and asset
+// type over the window ending at the supplied time. The last trade is reported
+// even if it traded before the window
+func GetStats(exchangeName string, p pair.CurrencyPair, assetType string, window time.Duration, at time.Time) (Stats, error) {
+	if window <= 0 {
+		return Stats{}, errors.New("trade stats window must be positive")
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+	b, ok := buffers[getKey(exchangeName, p, assetType)]
+	if !ok || b.count == 0 {
+		return Stats{}, ErrNoTrades
+	}
+
+	last := b.newest(0)
+	stats := Stats{
+		Exchange:   exchangeName,
+		Pair:       p.Pair().String(),
+		AssetType:  assetType,
+		Window:     window.String(),
+		LastPrice:  last.Price,
+		LastAmount: last.Amount,
+		LastSide:   last.Side,
+		LastTrade:  last.Timestamp,
+	}
+
+	start := at.Add(-window)
+	for x := 0; x < b.count; x++ {
+		t := b.newest(x)
+		// Trades are stored in arrival order which may differ slightly from
+		// their timestamps, so every trade is checked against the window
+		if t.Timestamp.Before(start) || t.Timestamp.After(at) {
+			continue
+		}
+		stats.Trades++
+		stats.Volume += t.Amount
+		switch t.Side {
+		case Buy:
+			stats.BuyVolume += t.Amount
+		case Sell:
+			stats.SellVolume += t.Amount
+		}
+	}
+
+	stats.TradesPerMinute = float64(stats.Trades) / window.Minutes()
+	if sided := stats.BuyVolume + stats.SellVolume; sided > 0 {
+		stats.Imbalance = (stats.BuyVolume - stats.SellVolume) / sided
+	}
+	return stats, nil
+}
+
+// getKey returns the buffer key of an exchange, currency pair and asset type
+func getKey(exchangeName string, p pair.CurrencyPair, assetType string) string {
+	return common.StringToUpper(exchangeName) + "/" +
+		common.StringToUpper(p.FirstCurrency.String()+p.SecondCurrency.String()) + "/" +
+		common.StringToUpper(assetType)
+}
@@ -0,0 +1,139 @@
+package trades
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+)
+
+const testAsset = "SPOT"
+
+var testPair = pair.NewCurrencyPair("BTC", "USD")
+
+func TestGetRecentTrades(t *testing.T) {
+	SetBufferSize(3)
+	defer SetBufferSize(0)
+
+	_, err := GetRecentTrades("Bitstamp", testPair, testAsset, 0)
+	if err != ErrNoTrades {
+		t.Errorf("Test failed. Expected %v, got %v", ErrNoTrades, err)
+	}
+
+	start := time.Date(2018, 10, 6, 0, 0, 0, 0, time.UTC)
+	for x := 1; x <= 5; x++ {
+		Add("Bitstamp", testPair, testAsset, Trade{
+			Timestamp: start.Add(time.Second * time.Duration(x)),
+			Price:     float64(x),
+			Amount:    1,
+			Side:      "buy",
+		})
+	}
+
+	// The buffer is bounded so only the 3 most recent trades are kept
+	recent, err := GetRecentTrades("bitstamp", pair.NewCurrencyPair("btc", "usd"), "spot", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 3 || recent[0].Price != 5 || recent[1].Price != 4 ||
+		recent[2].Price != 3 || recent[0].Side != Buy {
+		t.Errorf("Test failed. Unexpected recent trades %+v", recent)
+	}
+
+	recent, err = GetRecentTrades("Bitstamp", testPair, testAsset, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].Price != 5 || recent[1].Price != 4 {
+		t.Errorf("Test failed. Unexpected limited recent trades %+v", recent)
+	}
+}
+
+func TestGetStatsWindowExpiry(t *testing.T) {
+	SetBufferSize(0)
+	defer SetBufferSize(0)
+
+	now := time.Date(2018, 10, 6, 12, 0, 0, 0, time.UTC)
+	Add("Gemini", testPair, testAsset, Trade{Timestamp: now.Add(-time.Minute * 10),
+		Price: 6400, Amount: 5, Side: "sell"})
+	Add("Gemini", testPair, testAsset, Trade{Timestamp: now.Add(-time.Minute * 4),
+		Price: 6450, Amount: 1, Side: "buy"})
+	Add("Gemini", testPair, testAsset, Trade{Timestamp: now.Add(-time.Second * 30),
+		Price: 6500, Amount: 2, Side: "buy"})
+
+	stats, err := GetStats("Gemini", testPair, testAsset, time.Minute*5, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Trades != 2 || stats.TradesPerMinute != 0.4 || stats.Volume != 3 ||
+		stats.BuyVolume != 3 || stats.SellVolume != 0 || stats.Imbalance != 1 {
+		t.Errorf("Test failed. Unexpected 5 minute stats %+v", stats)
+	}
+	if stats.LastPrice != 6500 || stats.LastAmount != 2 || stats.LastSide != Buy ||
+		!stats.LastTrade.Equal(now.Add(-time.Second*30)) || stats.Window != "5m0s" ||
+		stats.Pair != "BTCUSD" {
+		t.Errorf("Test failed. Unexpected last trade stats %+v", stats)
+	}
+
+	// Five minutes later every trade has expired from the window but the last
+	// trade is still reported
+	stats, err = GetStats("Gemini", testPair, testAsset, time.Minute*5,
+		now.Add(time.Minute*5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Trades != 0 || stats.TradesPerMinute != 0 || stats.Volume != 0 ||
+		stats.Imbalance != 0 || stats.LastPrice != 6500 {
+		t.Errorf("Test failed. Unexpected expired stats %+v", stats)
+	}
+
+	_, err = GetStats("Gemini", testPair, testAsset, 0, now)
+	if err == nil {
+		t.Error("Test failed. Expected an error for a zero window")
+	}
+	_, err = GetStats("Gemini", pair.NewCurrencyPair("ETH", "USD"), testAsset,
+		time.Minute, now)
+	if err != ErrNoTrades {
+		t.Errorf("Test failed. Expected %v, got %v", ErrNoTrades, err)
+	}
+}
+
+func TestGetStatsImbalance(t *testing.T) {
+	SetBufferSize(0)
+	defer SetBufferSize(0)
+
+	now := time.Date(2018, 10, 6, 12, 0, 0, 0, time.UTC)
+	tests := []Trade{
+		{Timestamp: now.Add(-time.Second * 50), Price: 100, Amount: 3, Side: "BID"},
+		{Timestamp: now.Add(-time.Second * 40), Price: 100, Amount: 1, Side: "ask"},
+		{Timestamp: now.Add(-time.Second * 30), Price: 100, Amount: 0.5, Side: "Sell"},
+		{Timestamp: now.Add(-time.Second * 20), Price: 100, Amount: 4},
+	}
+	for x := range tests {
+		Add("Bitfinex", testPair, testAsset, tests[x])
+	}
+
+	stats, err := GetStats("Bitfinex", testPair, testAsset, time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Trades without a side count towards the volume but not the imbalance
+	if stats.Trades != 4 || stats.TradesPerMinute != 4 || stats.Volume != 8.5 ||
+		stats.BuyVolume != 3 || stats.SellVolume != 1.5 || stats.Imbalance != 1.5/4.5 ||
+		stats.LastSide != "" {
+		t.Errorf("Test failed. Unexpected stats %+v", stats)
+	}
+}
+
+func TestNormaliseSide(t *testing.T) {
+	tests := map[string]string{
+		"buy": Buy, "Bid": Buy, "b": Buy, "SELL": Sell, "ask": Sell, "s": Sell, "": "",
+		"unknown": "",
+	}
+	for side, expected := range tests {
+		if result := NormaliseSide(side); result != expected {
+			t.Errorf("Test failed. NormaliseSide(%q) expected %q, got %q", side, expected,
+				result)
+		}
+	}
+}
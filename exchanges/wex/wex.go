@@ -1,6 +1,7 @@
 package wex
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -64,6 +65,8 @@ func (w *WEX) SetDefaults() {
 	w.ConfigCurrencyPairFormat.Delimiter = "_"
 	w.ConfigCurrencyPairFormat.Uppercase = true
 	w.AssetTypes = []string{ticker.Spot}
+	w.BaseCurrencies = []string{"USD", "RUR", "EUR"}
+	w.EnabledPairs = []string{"BTC_USD", "LTC_USD", "LTC_BTC", "ETH_USD"}
 	w.SupportsAutoPairUpdating = true
 	w.SupportsRESTTickerBatching = true
 	w.Requester = request.New(w.Name,
@@ -377,11 +380,14 @@ func (w *WEX) SendAuthenticatedHTTPRequest(method string, values url.Values, res
 	encoded := values.Encode()
 	hmac := common.GetHMAC(common.HashSHA512, []byte(encoded), []byte(w.APISecret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if w.Verbose {
-		log.Debugf("Sending POST request to %s calling method %s with params %s\n",
+		log.Debugf("%s request %s. Sending POST request to %s calling method %s with params %s",
+			w.Name,
+			id,
 			w.APIUrlSecondary,
 			method,
-			encoded)
+			request.ScrubBody(encoded))
 	}
 
 	headers := make(map[string]string)
@@ -389,7 +395,7 @@ func (w *WEX) SendAuthenticatedHTTPRequest(method string, values url.Values, res
 	headers["Sign"] = common.HexEncodeToString(hmac)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	return w.SendPayload("POST",
+	return w.SendPayloadWithContext(ctx, "POST",
 		w.APIUrlSecondary,
 		headers,
 		strings.NewReader(encoded),
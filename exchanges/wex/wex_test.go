@@ -120,7 +120,7 @@ func TestGetOrderInfo(t *testing.T) {
 		t.Skip()
 	}
 	t.Parallel()
-	_, err := w.GetOrderInfo(6196974)
+	_, err := w.GetOrderInfo("6196974")
 	if err == nil {
 		t.Error("Test Failed - GetOrderInfo() error", err)
 	}
@@ -350,7 +350,14 @@ func TestSubmitOrder(t *testing.T) {
 		FirstCurrency:  symbol.BTC,
 		SecondCurrency: symbol.USD,
 	}
-	response, err := w.SubmitOrder(pair, exchange.Buy, exchange.Market, 1, 10, "hi")
+	var orderSubmission = &exchange.OrderSubmission{
+		Pair:      pair,
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Market,
+		Price:     10,
+		Amount:    1,
+	}
+	response, err := w.SubmitOrder(orderSubmission)
 	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
 		t.Errorf("Order failed to be placed: %v", err)
 	} else if !areTestAPIKeysSet() && err == nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -30,29 +31,36 @@ func (w *WEX) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", w.GetName(), len(w.EnabledPairs), w.EnabledPairs)
 	}
 
-	exchangeProducts, err := w.GetTradablePairs()
-	if err != nil {
-		log.Errorf("%s Failed to get available symbols.\n", w.GetName())
-	} else {
-		forceUpgrade := false
-		if !common.StringDataContains(w.EnabledPairs, "_") || !common.StringDataContains(w.AvailablePairs, "_") {
-			forceUpgrade = true
-		}
+	forceUpgrade := false
+	if !common.StringDataContains(w.EnabledPairs, "_") || !common.StringDataContains(w.AvailablePairs, "_") {
+		forceUpgrade = true
+	}
 
-		if forceUpgrade {
-			enabledPairs := []string{"BTC_USD", "LTC_USD", "LTC_BTC", "ETH_USD"}
-			log.Warn("Enabled pairs for WEX reset due to config upgrade, please enable the ones you would like again.")
+	if forceUpgrade {
+		enabledPairs := []string{"BTC_USD", "LTC_USD", "LTC_BTC", "ETH_USD"}
+		log.Warn("Enabled pairs for WEX reset due to config upgrade, please enable the ones you would like again.")
 
-			err = w.UpdateCurrencies(enabledPairs, true, true)
-			if err != nil {
-				log.Errorf("%s Failed to get config.\n", w.GetName())
-			}
-		}
-		err = w.UpdateCurrencies(exchangeProducts, false, forceUpgrade)
+		err := w.UpdateCurrencies(enabledPairs, true, true)
 		if err != nil {
-			log.Errorf("%s Failed to get config.\n", w.GetName())
+			log.Errorf("%s failed to update enabled currencies. Err: %s", w.Name, err)
 		}
 	}
+
+	err := w.UpdateTradablePairs(forceUpgrade)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", w.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (w *WEX) UpdateTradablePairs(forceUpdate bool) error {
+	exchangeProducts, err := w.GetTradablePairs()
+	if err != nil {
+		return err
+	}
+
+	return w.UpdateCurrencies(exchangeProducts, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -78,7 +86,10 @@ func (w *WEX) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price,
 		tp.Last = result[currency].Last
 		tp.Low = result[currency].Low
 		tp.Volume = result[currency].VolumeCurrent
-		ticker.ProcessTicker(w.Name, x, tp, assetType)
+		err = ticker.ProcessTicker(w.Name, x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(w.Name, p, assetType)
 }
@@ -157,19 +168,24 @@ func (w *WEX) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (w *WEX) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (w *WEX) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (w *WEX) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (w *WEX) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	response, err := w.Trade(common.StringToLower(p.Pair().String()), common.StringToLower(side.ToString()), amount, price)
+	err := order.CheckOptions(w.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := w.Trade(common.StringToLower(order.Pair.Pair().String()), common.StringToLower(order.OrderSide.ToString()), order.Amount, order.Price)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -231,13 +247,13 @@ func (w *WEX) CancelAllOrders(orderCancellation exchange.OrderCancellation) (exc
 }
 
 // GetOrderInfo returns information on a current open order
-func (w *WEX) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (w *WEX) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (w *WEX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
+func (w *WEX) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
 	return "", common.ErrNotYetImplemented
 }
 
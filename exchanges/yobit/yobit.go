@@ -1,6 +1,7 @@
 package yobit
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -63,8 +64,13 @@ func (y *Yobit) SetDefaults() {
 	y.ConfigCurrencyPairFormat.Delimiter = "_"
 	y.ConfigCurrencyPairFormat.Uppercase = true
 	y.AssetTypes = []string{ticker.Spot}
+	y.BaseCurrencies = []string{"USD", "RUR"}
+	y.EnabledPairs = []string{"LTC_BTC", "ETH_BTC", "BTC_USD", "DASH_BTC"}
 	y.SupportsAutoPairUpdating = false
 	y.SupportsRESTTickerBatching = true
+	// Ticker requests list the pairs in the URL path, long pair lists are
+	// split across requests to keep the URL short
+	y.MaxSymbolsPerRequest = 50
 	y.Requester = request.New(y.Name,
 		request.NewRateLimit(time.Second, yobitAuthRate),
 		request.NewRateLimit(time.Second, yobitUnauthRate),
@@ -345,8 +351,10 @@ func (y *Yobit) SendAuthenticatedHTTPRequest(path string, params url.Values, res
 	encoded := params.Encode()
 	hmac := common.GetHMAC(common.HashSHA512, []byte(encoded), []byte(y.APISecret))
 
+	ctx, id := request.WithRequestID(context.Background())
 	if y.Verbose {
-		log.Debugf("Sending POST request to %s calling path %s with params %s\n", apiPrivateURL, path, encoded)
+		log.Debugf("%s request %s. Sending POST request to %s calling path %s with params %s",
+			y.Name, id, apiPrivateURL, path, request.ScrubBody(encoded))
 	}
 
 	headers := make(map[string]string)
@@ -354,7 +362,7 @@ func (y *Yobit) SendAuthenticatedHTTPRequest(path string, params url.Values, res
 	headers["Sign"] = common.HexEncodeToString(hmac)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	return y.SendPayload("POST", apiPrivateURL, headers, strings.NewReader(encoded), result, true, y.Verbose)
+	return y.SendPayloadWithContext(ctx, "POST", apiPrivateURL, headers, strings.NewReader(encoded), result, true, y.Verbose)
 }
 
 // GetFee returns an estimate of fee based on type of transaction
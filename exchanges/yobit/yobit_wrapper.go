@@ -2,9 +2,9 @@ package yobit
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -32,17 +32,29 @@ func (y *Yobit) Run() {
 	}
 }
 
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (y *Yobit) UpdateTradablePairs(forceUpdate bool) error {
+	return common.ErrFunctionNotSupported
+}
+
 // UpdateTicker updates and returns the ticker for a currency pair
 func (y *Yobit) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
 	var tickerPrice ticker.Price
-	pairsCollated, err := exchange.GetAndFormatExchangeCurrencies(y.Name, y.GetEnabledCurrencies())
+	chunks, err := y.FormatExchangeCurrencies(y.GetEnabledCurrencies())
 	if err != nil {
 		return tickerPrice, err
 	}
 
-	result, err := y.GetTicker(pairsCollated.String())
-	if err != nil {
-		return tickerPrice, err
+	result := make(map[string]Ticker)
+	for x := range chunks {
+		tickers, err := y.GetTicker(chunks[x])
+		if err != nil {
+			return tickerPrice, err
+		}
+		for currency, tick := range tickers {
+			result[currency] = tick
+		}
 	}
 
 	for _, x := range y.GetEnabledCurrencies() {
@@ -55,7 +67,10 @@ func (y *Yobit) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Pric
 		tickerPrice.Last = result[currency].Last
 		tickerPrice.Low = result[currency].Low
 		tickerPrice.Volume = result[currency].VolumeCurrent
-		ticker.ProcessTicker(y.Name, x, tickerPrice, assetType)
+		err = ticker.ProcessTicker(y.Name, x, tickerPrice, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	return ticker.GetTicker(y.Name, p, assetType)
 }
@@ -140,19 +155,24 @@ func (y *Yobit) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (y *Yobit) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (y *Yobit) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (y *Yobit) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (y *Yobit) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	response, err := y.Trade(p.Pair().String(), orderType.ToString(), amount, price)
+	err := order.CheckOptions(y.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
+	response, err := y.Trade(order.Pair.Pair().String(), order.OrderType.ToString(), order.Amount, order.Price)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -213,19 +233,21 @@ func (y *Yobit) CancelAllOrders(orderCancellation exchange.OrderCancellation) (e
 }
 
 // GetOrderInfo returns information on a current open order
-func (y *Yobit) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (y *Yobit) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (y *Yobit) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	a, err := y.GetCryptoDepositAddress(cryptocurrency.String())
-	if err != nil {
-		return "", err
-	}
+func (y *Yobit) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return y.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		a, err := y.GetCryptoDepositAddress(cryptocurrency.String())
+		if err != nil {
+			return "", err
+		}
 
-	return a.Return.Address, nil
+		return a.Return.Address, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
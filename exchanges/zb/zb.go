@@ -62,6 +62,8 @@ func (z *ZB) SetDefaults() {
 	z.ConfigCurrencyPairFormat.Delimiter = "_"
 	z.ConfigCurrencyPairFormat.Uppercase = true
 	z.AssetTypes = []string{ticker.Spot}
+	z.BaseCurrencies = []string{"USD"}
+	z.EnabledPairs = []string{"BTC_USDT", "ETH_USDT"}
 	z.SupportsAutoPairUpdating = true
 	z.SupportsRESTTickerBatching = true
 	z.Requester = request.New(z.Name,
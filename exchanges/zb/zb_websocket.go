@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -24,17 +23,11 @@ func (z *ZB) WsConnect() error {
 		return errors.New(exchange.WebsocketNotEnabled)
 	}
 
-	var dialer websocket.Dialer
-	if z.Websocket.GetProxyAddress() != "" {
-		proxy, err := url.Parse(z.Websocket.GetProxyAddress())
-		if err != nil {
-			return err
-		}
-
-		dialer.Proxy = http.ProxyURL(proxy)
+	dialer, err := z.Websocket.Dialer()
+	if err != nil {
+		return err
 	}
 
-	var err error
 	z.WebsocketConn, _, err = dialer.Dial(z.Websocket.GetWebsocketURL(),
 		http.Header{})
 	if err != nil {
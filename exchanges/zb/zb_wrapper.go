@@ -1,9 +1,9 @@
 package zb
 
 import (
-	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
@@ -30,20 +30,26 @@ func (z *ZB) Run() {
 		log.Debugf("%s %d currencies enabled: %s.\n", z.GetName(), len(z.EnabledPairs), z.EnabledPairs)
 	}
 
+	err := z.UpdateTradablePairs(false)
+	if err != nil {
+		log.Errorf("%s failed to update tradable pairs. Err: %s", z.Name, err)
+	}
+}
+
+// UpdateTradablePairs fetches the exchange's tradable currency pairs and
+// updates the stored available pairs
+func (z *ZB) UpdateTradablePairs(forceUpdate bool) error {
 	markets, err := z.GetMarkets()
 	if err != nil {
-		log.Errorf("%s Unable to fetch symbols.\n", z.GetName())
-	} else {
-		var currencies []string
-		for x := range markets {
-			currencies = append(currencies, x)
-		}
+		return err
+	}
 
-		err = z.UpdateCurrencies(currencies, false, false)
-		if err != nil {
-			log.Errorf("%s Failed to update available currencies.\n", z.GetName())
-		}
+	var currencies []string
+	for x := range markets {
+		currencies = append(currencies, x)
 	}
+
+	return z.UpdateCurrencies(currencies, false, forceUpdate)
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -67,7 +73,10 @@ func (z *ZB) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price,
 		tp.Last = result[currency].Last
 		tp.Low = result[currency].Low
 		tp.Volume = result[currency].Vol
-		ticker.ProcessTicker(z.Name, x, tp, assetType)
+		err = ticker.ProcessTicker(z.Name, x, tp, assetType)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 
 	return ticker.GetTicker(z.Name, p, assetType)
@@ -159,33 +168,38 @@ func (z *ZB) GetFundingHistory() ([]exchange.FundHistory, error) {
 }
 
 // GetExchangeHistory returns historic trade data since exchange opening.
-func (z *ZB) GetExchangeHistory(p pair.CurrencyPair, assetType string) ([]exchange.TradeHistory, error) {
+func (z *ZB) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
 	var resp []exchange.TradeHistory
 
 	return resp, common.ErrNotYetImplemented
 }
 
 // SubmitOrder submits a new order
-func (z *ZB) SubmitOrder(p pair.CurrencyPair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+func (z *ZB) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
+	err := order.CheckOptions(z.Name, exchange.OrderOptions{})
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	var oT SpotNewOrderRequestParamsType
 
-	if side == exchange.Buy {
+	if order.OrderSide == exchange.Buy {
 		oT = SpotNewOrderRequestParamsTypeBuy
 	} else {
 		oT = SpotNewOrderRequestParamsTypeSell
 	}
 
 	var params = SpotNewOrderRequestParams{
-		Amount: amount,
-		Price:  price,
-		Symbol: common.StringToLower(p.Pair().String()),
+		Amount: order.Amount,
+		Price:  order.Price,
+		Symbol: common.StringToLower(order.Pair.Pair().String()),
 		Type:   oT,
 	}
 	response, err := z.SpotNewOrder(params)
 
 	if response > 0 {
-		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
+		submitOrderResponse.OrderID = exchange.FormatOrderID(response)
 	}
 
 	if err == nil {
@@ -238,19 +252,21 @@ func (z *ZB) CancelAllOrders(orderCancellation exchange.OrderCancellation) (exch
 }
 
 // GetOrderInfo returns information on a current open order
-func (z *ZB) GetOrderInfo(orderID int64) (exchange.OrderDetail, error) {
+func (z *ZB) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
 	return orderDetail, common.ErrNotYetImplemented
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
-func (z *ZB) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string) (string, error) {
-	address, err := z.GetCryptoAddress(cryptocurrency)
-	if err != nil {
-		return "", err
-	}
+func (z *ZB) GetDepositAddress(cryptocurrency pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	return z.GetCachedDepositAddress(cryptocurrency, accountID, forceRefresh, func() (string, error) {
+		address, err := z.GetCryptoAddress(cryptocurrency)
+		if err != nil {
+			return "", err
+		}
 
-	return address.Message.Data.Key, nil
+		return address.Message.Data.Key, nil
+	})
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
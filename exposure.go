@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/currency/translation"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// Exposure report data sources
+const (
+	ExposureSourceBalances  = "balances"
+	ExposureSourceOrders    = "orders"
+	ExposureSourcePositions = "positions"
+)
+
+// Exposure report data source statuses
+const (
+	ExposureStatusOK          = "ok"
+	ExposureStatusStale       = "stale"
+	ExposureStatusUnavailable = "unavailable"
+)
+
+// errNoFiatTicker is returned when a cryptocurrency can't be valued as no
+// loaded exchange has a ticker pricing it in a fiat currency
+var errNoFiatTicker = errors.New("no ticker pricing it in a fiat currency")
+
+// positionsExchange is implemented by exchanges which report their open
+// derivative positions
+type positionsExchange interface {
+	GetOpenPositions() ([]exchange.Position, error)
+}
+
+// ExposureSource holds the state of the data an exposure report uses from an
+// exchange account, Updated is when the data was last refreshed
+type ExposureSource struct {
+	Exchange string    `json:"exchange"`
+	Source   string    `json:"source"`
+	Status   string    `json:"status"`
+	Updated  time.Time `json:"updated,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// ExchangeExposure holds the exposure of an exchange account to a currency.
+// Balance is its balance, Orders the part of the balance locked in open orders
+// and Positions the exposure of its open derivative positions including their
+// unrealised profit and loss. Total is the balance plus the positions
+type ExchangeExposure struct {
+	Exchange  string  `json:"exchange"`
+	Balance   float64 `json:"balance"`
+	Orders    float64 `json:"orders"`
+	Positions float64 `json:"positions"`
+	Total     float64 `json:"total"`
+}
+
+// CurrencyExposure holds the exposure to a currency across exchange accounts,
+// see ExchangeExposure. Value is the total in the display currency converted
+// at Rate along Path, it is omitted and the reason set in ValuationError when
+// the currency can't be converted
+type CurrencyExposure struct {
+	Currency       string             `json:"currency"`
+	Balance        float64            `json:"balance"`
+	Orders         float64            `json:"orders"`
+	Positions      float64            `json:"positions"`
+	Total          float64            `json:"total"`
+	Value          *float64           `json:"value,omitempty"`
+	Rate           float64            `json:"rate,omitempty"`
+	Path           []string           `json:"path,omitempty"`
+	ValuationError string             `json:"valuationError,omitempty"`
+	Exchanges      []ExchangeExposure `json:"exchanges"`
+}
+
+// ExposureReport holds the exposure to each currency and the state of the
+// data sources it was calculated from. TotalValue is the sum of the currency
+// values in the display currency
+type ExposureReport struct {
+	DisplayCurrency string             `json:"displayCurrency"`
+	TotalValue      float64            `json:"totalValue"`
+	Currencies      []CurrencyExposure `json:"currencies"`
+	Sources         []ExposureSource   `json:"sources"`
+	Timestamp       time.Time          `json:"timestamp"`
+}
+
+// exposureRateFunc returns the rate converting a currency into the display
+// currency and the currencies it is converted through
+type exposureRateFunc func(currencyName string) (float64, []string, error)
+
+// GetExposureReport returns the exposure to each currency across the exchange
+// accounts, from the balances stored by the balance refresher, the open orders
+// submitted through the bot and the open positions of exchanges reporting
+// them, valued in the fiat display currency. Balances not refreshed within two
+// refresh intervals and orders not polled within two poll intervals are
+// flagged as stale, exchanges whose balances or positions can't be retrieved
+// as unavailable
+func GetExposureReport() ExposureReport {
+	now := time.Now()
+	report := ExposureReport{
+		DisplayCurrency: bot.config.Currency.FiatDisplayCurrency,
+		Timestamp:       now,
+	}
+
+	balances := make(map[string]map[string]float64)
+	balanceMtx.Lock()
+	for account, snapshot := range balanceSnapshots {
+		balances[account] = snapshot
+		report.Sources = append(report.Sources, exposureSource(account,
+			ExposureSourceBalances, balanceSnapshotTimes[account], now,
+			2*bot.config.BalanceRefreshInterval))
+	}
+	balanceMtx.Unlock()
+
+	orders := getTrackedOrders()
+	ordersUpdated := make(map[string]time.Time)
+	for x := range orders {
+		account := FormatExchangeAccountName(orders[x].exchange, orders[x].account)
+		updated, ok := ordersUpdated[account]
+		if !ok || orders[x].updated.Before(updated) {
+			ordersUpdated[account] = orders[x].updated
+		}
+	}
+	for account, updated := range ordersUpdated {
+		report.Sources = append(report.Sources, exposureSource(account,
+			ExposureSourceOrders, updated, now, 2*orderStatusPollInterval))
+	}
+
+	positions := make(map[string][]exchange.Position)
+	for _, exch := range GetAuthAPISupportedExchanges() {
+		name := exch.GetName()
+		if _, ok := balances[name]; !ok {
+			report.Sources = append(report.Sources, ExposureSource{
+				Exchange: name,
+				Source:   ExposureSourceBalances,
+				Status:   ExposureStatusUnavailable,
+				Error:    "balances not retrieved",
+			})
+		}
+
+		positionsExch, ok := exch.(positionsExchange)
+		if !ok {
+			continue
+		}
+		source := ExposureSource{
+			Exchange: name,
+			Source:   ExposureSourcePositions,
+			Status:   ExposureStatusUnavailable,
+		}
+		if IsExchangeInMaintenance(name) {
+			source.Error = "exchange in maintenance"
+		} else if result, err := positionsExch.GetOpenPositions(); err != nil {
+			source.Error = err.Error()
+		} else {
+			positions[name] = result
+			source.Status = ExposureStatusOK
+			source.Updated = now
+		}
+		report.Sources = append(report.Sources, source)
+	}
+	sort.Slice(report.Sources, func(i, j int) bool {
+		if report.Sources[i].Exchange != report.Sources[j].Exchange {
+			return report.Sources[i].Exchange < report.Sources[j].Exchange
+		}
+		return report.Sources[i].Source < report.Sources[j].Source
+	})
+
+	report.Currencies, report.TotalValue = calculateExposure(balances, orders,
+		positions, func(currencyName string) (float64, []string, error) {
+			return displayCurrencyRate(currencyName, report.DisplayCurrency)
+		})
+	return report
+}
+
+// exposureSource returns the state of an exchange account's data source
+// updated at the time, which is stale when older than maxAge
+func exposureSource(account, source string, updated, now time.Time, maxAge time.Duration) ExposureSource {
+	result := ExposureSource{
+		Exchange: account,
+		Source:   source,
+		Status:   ExposureStatusOK,
+		Updated:  updated,
+	}
+	if now.Sub(updated) > maxAge {
+		result.Status = ExposureStatusStale
+	}
+	return result
+}
+
+// calculateExposure aggregates the balances of each exchange account, the
+// amounts locked in open orders and the open positions of each exchange by
+// currency and values the totals using rate. Open buy orders lock their
+// remaining amount times their price in the quote currency and sell orders
+// their remaining amount in the base currency, market buy orders without a
+// price are left out. Positions add their base and quote amounts and their
+// unrealised profit and loss in the settlement currency. Currency names are
+// mapped to their canonical names. The currencies are returned sorted by name
+// with the sum of their values
+func calculateExposure(balances map[string]map[string]float64, orders []trackedOrder, positions map[string][]exchange.Position, rate exposureRateFunc) ([]CurrencyExposure, float64) {
+	exposures := make(map[string]map[string]*ExchangeExposure)
+	get := func(currencyName, account string) *ExchangeExposure {
+		currencyName = translation.GetCanonicalCurrency(pair.CurrencyItem(currencyName)).String()
+		accounts, ok := exposures[currencyName]
+		if !ok {
+			accounts = make(map[string]*ExchangeExposure)
+			exposures[currencyName] = accounts
+		}
+		e, ok := accounts[account]
+		if !ok {
+			e = &ExchangeExposure{Exchange: account}
+			accounts[account] = e
+		}
+		return e
+	}
+
+	for account, snapshot := range balances {
+		for currencyName, balance := range snapshot {
+			get(currencyName, account).Balance += balance
+		}
+	}
+
+	for x := range orders {
+		remaining := orders[x].amount - orders[x].filled
+		if remaining <= 0 {
+			continue
+		}
+		account := FormatExchangeAccountName(orders[x].exchange, orders[x].account)
+		if common.StringToLower(orders[x].side) == common.StringToLower(string(exchange.Buy)) {
+			if orders[x].price <= 0 {
+				continue
+			}
+			get(orders[x].pair.Quote().String(), account).Orders += remaining * orders[x].price
+			continue
+		}
+		get(orders[x].pair.Base().String(), account).Orders += remaining
+	}
+
+	for exchName, exchPositions := range positions {
+		for x := range exchPositions {
+			p := &exchPositions[x]
+			get(p.Pair.Base().String(), exchName).Positions += p.BaseAmount
+			get(p.Pair.Quote().String(), exchName).Positions += p.QuoteAmount
+			if p.SettlementCurrency != "" && p.UnrealisedPNL != 0 {
+				get(p.SettlementCurrency, exchName).Positions += p.UnrealisedPNL
+			}
+		}
+	}
+
+	var result []CurrencyExposure
+	var totalValue float64
+	for currencyName, accounts := range exposures {
+		c := CurrencyExposure{Currency: currencyName}
+		for _, e := range accounts {
+			e.Total = e.Balance + e.Positions
+			c.Balance += e.Balance
+			c.Orders += e.Orders
+			c.Positions += e.Positions
+			c.Total += e.Total
+			c.Exchanges = append(c.Exchanges, *e)
+		}
+		sort.Slice(c.Exchanges, func(i, j int) bool {
+			return c.Exchanges[i].Exchange < c.Exchanges[j].Exchange
+		})
+
+		r, path, err := rate(currencyName)
+		if err != nil {
+			c.ValuationError = err.Error()
+		} else {
+			value := c.Total * r
+			c.Value = &value
+			c.Rate = r
+			c.Path = path
+			totalValue += value
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Currency < result[j].Currency
+	})
+	return result, totalValue
+}
+
+// displayCurrencyRate returns the rate converting a currency into the display
+// currency and the currencies it is converted through. Fiat currencies are
+// converted with the forex rates, other currencies are priced with the last
+// price of a stored ticker quoting them in a fiat currency, preferring the
+// display currency, which is then converted
+func displayCurrencyRate(currencyName, displayCurrency string) (float64, []string, error) {
+	if currency.IsFiatCurrency(currencyName) {
+		conversion, err := currency.ConvertCurrencyWithPath(1, currencyName, displayCurrency)
+		if err != nil {
+			return 0, nil, err
+		}
+		return conversion.Rate, conversion.Path, nil
+	}
+
+	var quote string
+	var price float64
+	for _, exch := range bot.exchanges {
+		for _, p := range exch.GetEnabledCurrencies() {
+			base := translation.GetCanonicalCurrency(p.Base()).String()
+			pairQuote := p.Quote().Upper().String()
+			if base != currencyName || quote == displayCurrency ||
+				!currency.IsFiatCurrency(pairQuote) {
+				continue
+			}
+			t, err := ticker.GetTicker(exch.GetName(), p, ticker.Spot)
+			if err != nil || t.Last <= 0 {
+				continue
+			}
+			if quote == "" || pairQuote == displayCurrency {
+				quote, price = pairQuote, t.Last
+			}
+		}
+	}
+	if quote == "" {
+		return 0, nil, fmt.Errorf("%s %w", currencyName, errNoFiatTicker)
+	}
+
+	conversion, err := currency.ConvertCurrencyWithPath(price, quote, displayCurrency)
+	if err != nil {
+		return 0, nil, err
+	}
+	return conversion.Converted, append([]string{currencyName}, conversion.Path...), nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// testExposureRate values BTC at 10000 USD and USD at 1, other currencies
+// can't be valued
+func testExposureRate(currencyName string) (float64, []string, error) {
+	switch currencyName {
+	case "BTC":
+		return 10000, []string{"BTC", "USD"}, nil
+	case "USD":
+		return 1, []string{"USD"}, nil
+	}
+	return 0, nil, errNoFiatTicker
+}
+
+func TestCalculateExposure(t *testing.T) {
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	balances := map[string]map[string]float64{
+		"Bitstamp":     {"BTC": 2, "USD": 1000},
+		"Bitstamp:sub": {"BTC": 1, "LTC": 5},
+		"Bitmex":       {"BTC": 0.5},
+	}
+	orders := []trackedOrder{
+		// A partially filled buy locks its remaining amount in USD
+		{exchange: "Bitstamp", pair: btcusd, side: "Buy", amount: 2, filled: 1, price: 100},
+		{exchange: "Bitstamp", account: "sub", pair: btcusd, side: "Sell", amount: 0.5},
+		// A market buy without a price locks an unknown amount
+		{exchange: "Bitstamp", pair: btcusd, side: "Buy", amount: 1},
+	}
+	positions := map[string][]exchange.Position{
+		"Bitmex": {{
+			Pair:               pair.NewCurrencyPair("XBT", "USD"),
+			BaseAmount:         0.5,
+			QuoteAmount:        -4000,
+			SettlementCurrency: "BTC",
+			UnrealisedPNL:      0.025,
+		}},
+	}
+
+	result, totalValue := calculateExposure(balances, orders, positions, testExposureRate)
+	if len(result) != 3 || result[0].Currency != "BTC" || result[1].Currency != "LTC" ||
+		result[2].Currency != "USD" {
+		t.Fatalf("Test failed. Unexpected currencies %+v", result)
+	}
+
+	btc := result[0]
+	if math.Abs(btc.Balance-3.5) > 1e-9 || btc.Orders != 0.5 ||
+		math.Abs(btc.Positions-0.525) > 1e-9 || math.Abs(btc.Total-4.025) > 1e-9 ||
+		btc.Value == nil || math.Abs(*btc.Value-40250) > 1e-6 || btc.Rate != 10000 ||
+		!reflect.DeepEqual(btc.Path, []string{"BTC", "USD"}) {
+		t.Errorf("Test failed. Unexpected BTC exposure %+v", btc)
+	}
+	if len(btc.Exchanges) != 3 || btc.Exchanges[0].Exchange != "Bitmex" ||
+		math.Abs(btc.Exchanges[0].Total-1.025) > 1e-9 ||
+		btc.Exchanges[2].Exchange != "Bitstamp:sub" || btc.Exchanges[2].Orders != 0.5 {
+		t.Errorf("Test failed. Unexpected BTC exchange breakdown %+v", btc.Exchanges)
+	}
+
+	ltc := result[1]
+	if ltc.Total != 5 || ltc.Value != nil || ltc.ValuationError == "" {
+		t.Errorf("Test failed. Unexpected unvalued LTC exposure %+v", ltc)
+	}
+
+	usd := result[2]
+	if usd.Balance != 1000 || usd.Orders != 100 || usd.Positions != -4000 ||
+		usd.Total != -3000 || usd.Value == nil || *usd.Value != -3000 {
+		t.Errorf("Test failed. Unexpected USD exposure %+v", usd)
+	}
+	if math.Abs(totalValue-37250) > 1e-6 {
+		t.Errorf("Test failed. Expected total value 37250, got %f", totalValue)
+	}
+}
+
+func TestGetExposureReport(t *testing.T) {
+	SetupTestHelpers(t)
+	exchanges := bot.exchanges
+	refreshInterval := bot.config.BalanceRefreshInterval
+	balanceMtx.Lock()
+	snapshots, snapshotTimes := balanceSnapshots, balanceSnapshotTimes
+	balanceSnapshots = map[string]map[string]float64{
+		"Bitmex":   {"USD": 10},
+		"Bitstamp": {"USD": 20},
+	}
+	balanceSnapshotTimes = map[string]time.Time{
+		"Bitmex":   time.Now(),
+		"Bitstamp": time.Now().Add(-time.Hour),
+	}
+	balanceMtx.Unlock()
+	trackedOrderMtx.Lock()
+	orders := trackedOrders
+	trackedOrders = map[string]*trackedOrder{
+		"Bitmex/1": {exchange: "Bitmex", id: "1", pair: pair.NewCurrencyPair("XBT", "USD"),
+			side: "Buy", amount: 1, price: 5, updated: time.Now()},
+	}
+	trackedOrderMtx.Unlock()
+	defer func() {
+		bot.exchanges = exchanges
+		bot.config.BalanceRefreshInterval = refreshInterval
+		balanceMtx.Lock()
+		balanceSnapshots, balanceSnapshotTimes = snapshots, snapshotTimes
+		balanceMtx.Unlock()
+		trackedOrderMtx.Lock()
+		trackedOrders = orders
+		trackedOrderMtx.Unlock()
+	}()
+
+	bot.config.BalanceRefreshInterval = time.Minute
+	// Bitmex reports an open position and Kraken fails to report its
+	// positions
+	bitmex := newBalanceExchange("Bitmex", nil, nil)
+	bitmex.enabledPairs = nil
+	bitmex.getOpenPositions = func() ([]exchange.Position, error) {
+		return []exchange.Position{
+			{Pair: pair.NewCurrencyPair("XBT", "USD"), BaseAmount: 1, QuoteAmount: -100},
+		}, nil
+	}
+	kraken := newBalanceExchange("Kraken", nil, nil)
+	kraken.enabledPairs = nil
+	kraken.getOpenPositions = func() ([]exchange.Position, error) {
+		return nil, errors.New("positions unavailable")
+	}
+	bot.exchanges = []exchange.IBotExchange{bitmex, kraken}
+
+	report := GetExposureReport()
+	statuses := make(map[string]string)
+	for _, source := range report.Sources {
+		statuses[source.Exchange+"/"+source.Source] = source.Status
+	}
+	expected := map[string]string{
+		"Bitmex/balances":   ExposureStatusOK,
+		"Bitmex/orders":     ExposureStatusOK,
+		"Bitmex/positions":  ExposureStatusOK,
+		"Bitstamp/balances": ExposureStatusStale,
+		"Kraken/balances":   ExposureStatusUnavailable,
+		"Kraken/positions":  ExposureStatusUnavailable,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Test failed. Unexpected sources %+v", report.Sources)
+	}
+
+	var usd CurrencyExposure
+	for _, c := range report.Currencies {
+		if c.Currency == "USD" {
+			usd = c
+		}
+	}
+	if usd.Balance != 30 || usd.Orders != 5 || usd.Positions != -100 || usd.Total != -70 ||
+		len(usd.Exchanges) != 2 {
+		t.Errorf("Test failed. Unexpected USD exposure %+v", usd)
+	}
+}
@@ -1,5 +1,7 @@
 module github.com/thrasher-/gocryptotrader
 
+go 1.13
+
 require (
 	github.com/gorilla/context v0.0.0-20160226214623-1ea25387ff6f // indirect
 	github.com/gorilla/mux v1.6.1
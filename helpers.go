@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sync"
+	"time"
 
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/translation"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
@@ -15,10 +22,85 @@ import (
 	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
+// DaemonInfo holds the version, uptime and subsystem status of the daemon
+type DaemonInfo struct {
+	Version                 string        `json:"version"`
+	BuildCommit             string        `json:"buildCommit,omitempty"`
+	BuildTime               string        `json:"buildTime,omitempty"`
+	StartTime               time.Time     `json:"startTime"`
+	Uptime                  time.Duration `json:"uptime"`
+	EnabledExchanges        int           `json:"enabledExchanges"`
+	LoadedExchanges         int           `json:"loadedExchanges"`
+	WebserverEnabled        bool          `json:"webserverEnabled"`
+	WebsocketServerRunning  bool          `json:"websocketServerRunning"`
+	PortfolioManagerRunning bool          `json:"portfolioManagerRunning"`
+	DryRun                  bool          `json:"dryRun"`
+	DataDir                 string        `json:"dataDir"`
+}
+
+// GetDaemonInfo returns the version, uptime and subsystem status of the daemon
+func GetDaemonInfo() DaemonInfo {
+	info := DaemonInfo{
+		Version:                 common.TrimString(BuildVersion(true), " .\n"),
+		BuildCommit:             BuildCommit,
+		BuildTime:               BuildTime,
+		StartTime:               bot.startTime,
+		LoadedExchanges:         len(bot.exchanges),
+		WebsocketServerRunning:  wsHubStarted,
+		PortfolioManagerRunning: isSubsystemRunning(SubsystemPortfolioManager),
+		DryRun:                  bot.settings.DryRun,
+		DataDir:                 bot.settings.DataDir,
+	}
+
+	if !bot.startTime.IsZero() {
+		info.Uptime = time.Since(bot.startTime)
+	}
+
+	if bot.config != nil {
+		info.EnabledExchanges = bot.config.CountEnabledExchanges()
+		info.WebserverEnabled = bot.config.Webserver.Enabled
+	}
+	return info
+}
+
+// canonicalPair is the upper cased, alias translated form of a currency pair,
+// independent of its delimiter, used to deduplicate pairs across exchanges
+type canonicalPair struct {
+	first, second pair.CurrencyItem
+}
+
+// newCanonicalPair returns the canonical form of a currency pair
+func newCanonicalPair(p pair.CurrencyPair) canonicalPair {
+	return canonicalPair{
+		first:  translation.GetCanonicalCurrency(p.FirstCurrency),
+		second: translation.GetCanonicalCurrency(p.SecondCurrency),
+	}
+}
+
+// pairSet holds the canonical pairs already seen when deduplicating currency
+// pairs. Reciprocal pairs are treated as duplicates, matching pair.Contains
+type pairSet map[canonicalPair]struct{}
+
+// add adds a currency pair to the set and returns whether it wasn't already
+// present
+func (s pairSet) add(p pair.CurrencyPair) bool {
+	c := newCanonicalPair(p)
+	if _, ok := s[c]; ok {
+		return false
+	}
+	if _, ok := s[canonicalPair{first: c.second, second: c.first}]; ok {
+		return false
+	}
+	s[c] = struct{}{}
+	return true
+}
+
 // GetAllAvailablePairs returns a list of all available pairs on either enabled
-// or disabled exchanges
+// or disabled exchanges. Pairs are deduplicated by their canonical form and
+// returned in config order, keeping the first occurrence
 func GetAllAvailablePairs(enabledExchangesOnly bool) []pair.CurrencyPair {
 	var pairList []pair.CurrencyPair
+	seen := make(pairSet)
 	for x := range bot.config.Exchanges {
 		if enabledExchangesOnly && !bot.config.Exchanges[x].Enabled {
 			continue
@@ -31,7 +113,7 @@ func GetAllAvailablePairs(enabledExchangesOnly bool) []pair.CurrencyPair {
 		}
 
 		for y := range pairs {
-			if pair.Contains(pairList, pairs[y], false) {
+			if !seen.add(pairs[y]) {
 				continue
 			}
 			pairList = append(pairList, pairs[y])
@@ -44,6 +126,7 @@ func GetAllAvailablePairs(enabledExchangesOnly bool) []pair.CurrencyPair {
 // parameters
 func GetSpecificAvailablePairs(enabledExchangesOnly, fiatPairs, includeUSDT, cryptoPairs bool) []pair.CurrencyPair {
 	var pairList []pair.CurrencyPair
+	seen := make(pairSet)
 	supportedPairs := GetAllAvailablePairs(enabledExchangesOnly)
 
 	for x := range supportedPairs {
@@ -51,7 +134,7 @@ func GetSpecificAvailablePairs(enabledExchangesOnly, fiatPairs, includeUSDT, cry
 			if currency.IsCryptoFiatPair(supportedPairs[x]) &&
 				!pair.ContainsCurrency(supportedPairs[x], "USDT") ||
 				(includeUSDT && pair.ContainsCurrency(supportedPairs[x], "USDT") && currency.IsCryptoPair(supportedPairs[x])) {
-				if pair.Contains(pairList, supportedPairs[x], false) {
+				if !seen.add(supportedPairs[x]) {
 					continue
 				}
 				pairList = append(pairList, supportedPairs[x])
@@ -59,7 +142,7 @@ func GetSpecificAvailablePairs(enabledExchangesOnly, fiatPairs, includeUSDT, cry
 		}
 		if cryptoPairs {
 			if currency.IsCryptoPair(supportedPairs[x]) {
-				if pair.Contains(pairList, supportedPairs[x], false) {
+				if !seen.add(supportedPairs[x]) {
 					continue
 				}
 				pairList = append(pairList, supportedPairs[x])
@@ -78,7 +161,7 @@ func IsRelatablePairs(p1, p2 pair.CurrencyPair, includeUSDT bool) bool {
 	var relatablePairs = GetRelatableCurrencies(p1, true, includeUSDT)
 	if currency.IsCryptoFiatPair(p1) {
 		for x := range relatablePairs {
-			relatablePairs = append(relatablePairs, GetRelatableFiatCurrencies(relatablePairs[x])...)
+			relatablePairs = append(relatablePairs, GetRelatableFiatCurrencies(relatablePairs[x], false)...)
 		}
 	}
 	return pair.Contains(relatablePairs, p2, false)
@@ -88,29 +171,19 @@ func IsRelatablePairs(p1, p2 pair.CurrencyPair, includeUSDT bool) bool {
 // exchange
 func MapCurrenciesByExchange(p []pair.CurrencyPair, enabledExchangesOnly bool) map[string][]pair.CurrencyPair {
 	currencyExchange := make(map[string][]pair.CurrencyPair)
-	for x := range p {
-		for y := range bot.config.Exchanges {
-			if enabledExchangesOnly && !bot.config.Exchanges[y].Enabled {
-				continue
-			}
-			exchName := bot.config.Exchanges[y].Name
-			success, err := bot.config.SupportsPair(exchName, p[x])
-			if err != nil || !success {
-				continue
-			}
+	for x := range bot.config.Exchanges {
+		pairs, enabled := getExchangePairs(&bot.config.Exchanges[x])
+		if enabledExchangesOnly && !enabled {
+			continue
+		}
 
-			result, ok := currencyExchange[exchName]
-			if !ok {
-				var pairs []pair.CurrencyPair
-				pairs = append(pairs, p[x])
-				currencyExchange[exchName] = pairs
-			} else {
-				if pair.Contains(result, p[x], false) {
-					continue
-				}
-				result = append(result, p[x])
-				currencyExchange[exchName] = result
+		exchName := bot.config.Exchanges[x].Name
+		for y := range p {
+			if !pair.Contains(pairs, p[y], false) ||
+				pair.Contains(currencyExchange[exchName], p[y], false) {
+				continue
 			}
+			currencyExchange[exchName] = append(currencyExchange[exchName], p[y])
 		}
 	}
 	return currencyExchange
@@ -121,33 +194,65 @@ func MapCurrenciesByExchange(p []pair.CurrencyPair, enabledExchangesOnly bool) m
 func GetExchangeNamesByCurrency(p pair.CurrencyPair, enabled bool) []string {
 	var exchanges []string
 	for x := range bot.config.Exchanges {
-		if enabled != bot.config.Exchanges[x].Enabled {
-			continue
-		}
-
-		exchName := bot.config.Exchanges[x].Name
-		success, err := bot.config.SupportsPair(exchName, p)
-		if err != nil {
+		pairs, exchEnabled := getExchangePairs(&bot.config.Exchanges[x])
+		if enabled != exchEnabled {
 			continue
 		}
 
-		if success {
-			exchanges = append(exchanges, exchName)
+		if pair.Contains(pairs, p, false) {
+			exchanges = append(exchanges, bot.config.Exchanges[x].Name)
 		}
 	}
 	return exchanges
 }
 
+// getExchangePairs returns the available currency pairs of an exchange and
+// whether it is enabled. Loaded exchanges are queried for their live pairs,
+// which include pairs found by auto pair updates, and the config is only used
+// for exchanges which aren't loaded
+func getExchangePairs(exchCfg *config.ExchangeConfig) ([]pair.CurrencyPair, bool) {
+	exch := GetExchangeByName(exchCfg.Name)
+	if exch != nil {
+		return exch.GetAvailableCurrencies(), exch.IsEnabled()
+	}
+
+	pairs, err := bot.config.GetAvailablePairs(exchCfg.Name)
+	if err != nil {
+		return nil, exchCfg.Enabled
+	}
+	return pairs, exchCfg.Enabled
+}
+
 // GetRelatableCryptocurrencies returns a list of currency pairs if it can find
-// any relatable currencies (e.g ETHBTC -> ETHLTC -> ETHUSDT -> ETHREP)
-// incOrig includes the supplied pair if desired
-func GetRelatableCryptocurrencies(p pair.CurrencyPair) []pair.CurrencyPair {
-	var pairs []pair.CurrencyPair
-	cryptocurrencies := currency.CryptoCurrencies
+// any relatable currencies (e.g ETHBTC -> ETHLTC -> ETHUSDT -> ETHREP).
+// availableOnly only returns pairs available on at least one enabled exchange
+func GetRelatableCryptocurrencies(p pair.CurrencyPair, availableOnly bool) []pair.CurrencyPair {
+	return getRelatablePairs(p, currency.CryptoCurrencies, availableOnly)
+}
+
+// GetRelatableFiatCurrencies returns a list of currency pairs if it can find
+// any relatable currencies (e.g ETHUSD -> ETHAUD -> ETHGBP -> ETHJPY).
+// availableOnly only returns pairs available on at least one enabled exchange
+func GetRelatableFiatCurrencies(p pair.CurrencyPair, availableOnly bool) []pair.CurrencyPair {
+	return getRelatablePairs(p, currency.FiatCurrencies, availableOnly)
+}
+
+// getRelatablePairs pairs the first currency of p with each of the supplied
+// currencies, skipping self-pairs and duplicates which only differ in case
+func getRelatablePairs(p pair.CurrencyPair, currencies []string, availableOnly bool) []pair.CurrencyPair {
+	var available []pair.CurrencyPair
+	if availableOnly {
+		available = getEnabledAvailablePairs()
+	}
 
-	for x := range cryptocurrencies {
-		newPair := pair.NewCurrencyPair(p.FirstCurrency.String(), cryptocurrencies[x])
-		if pair.Contains(pairs, newPair, false) {
+	var pairs []pair.CurrencyPair
+	for x := range currencies {
+		newPair := pair.NewCurrencyPair(p.FirstCurrency.String(), currencies[x])
+		if newPair.FirstCurrency.Upper() == newPair.SecondCurrency.Upper() ||
+			pair.Contains(pairs, newPair, true) {
+			continue
+		}
+		if availableOnly && !pair.Contains(available, newPair, false) {
 			continue
 		}
 		pairs = append(pairs, newPair)
@@ -155,21 +260,18 @@ func GetRelatableCryptocurrencies(p pair.CurrencyPair) []pair.CurrencyPair {
 	return pairs
 }
 
-// GetRelatableFiatCurrencies returns a list of currency pairs if it can find
-// any relatable currencies (e.g ETHUSD -> ETHAUD -> ETHGBP -> ETHJPY)
-// incOrig includes the supplied pair if desired
-func GetRelatableFiatCurrencies(p pair.CurrencyPair) []pair.CurrencyPair {
-	var pairs []pair.CurrencyPair
-	fiatCurrencies := currency.FiatCurrencies
-
-	for x := range fiatCurrencies {
-		newPair := pair.NewCurrencyPair(p.FirstCurrency.String(), fiatCurrencies[x])
-		if pair.Contains(pairs, newPair, false) {
+// getEnabledAvailablePairs returns the available pairs of all enabled
+// exchanges
+func getEnabledAvailablePairs() []pair.CurrencyPair {
+	var available []pair.CurrencyPair
+	for x := range bot.config.Exchanges {
+		pairs, enabled := getExchangePairs(&bot.config.Exchanges[x])
+		if !enabled {
 			continue
 		}
-		pairs = append(pairs, newPair)
+		available = append(available, pairs...)
 	}
-	return pairs
+	return available
 }
 
 // GetRelatableCurrencies returns a list of currency pairs if it can find
@@ -220,71 +322,574 @@ func GetRelatableCurrencies(p pair.CurrencyPair, incOrig, incUSDT bool) []pair.C
 	return pairs
 }
 
-// GetSpecificOrderbook returns a specific orderbook given the currency,
+// tickerRefreshes and orderbookRefreshes deduplicate concurrent on demand
+// ticker and orderbook refreshes for the same exchange, pair and asset type
+var (
+	tickerRefreshes    refreshGroup
+	orderbookRefreshes refreshGroup
+)
+
+// getExchangeAssetType returns the named exchange and the asset type parsed
+// against its enabled asset types
+func getExchangeAssetType(exchangeName, assetType string) (exchange.IBotExchange, string, error) {
+	exch := GetExchangeByName(exchangeName)
+	if exch == nil {
+		return nil, "", ErrExchangeNotFound
+	}
+	assetType, err := assets.Parse(assetType, exch.GetAssetTypes())
+	if err != nil {
+		return nil, "", err
+	}
+	return exch, assetType, nil
+}
+
+// RefreshSpecificOrderbook fetches a fresh orderbook from the exchange given
+// the currency pair, exchangeName and assetType, bypassing the cached
+// orderbook. Concurrent refreshes of the same orderbook share a single
+// exchange request, which is aborted when ctx is cancelled if the exchange
+// supports it
+func RefreshSpecificOrderbook(ctx context.Context, p pair.CurrencyPair, exchangeName, assetType string) (orderbook.Base, error) {
+	exch, assetType, err := getExchangeAssetType(exchangeName, assetType)
+	if err != nil {
+		return orderbook.Base{}, err
+	}
+
+	p = exchangePair(exch, p)
+	key := exch.GetName() + "-" + p.Pair().String() + "-" + assetType
+	result, err := orderbookRefreshes.Do(key, func() (interface{}, error) {
+		return updateOrderbookWithContext(ctx, exch, p, assetType)
+	})
+	if err != nil {
+		return orderbook.Base{}, err
+	}
+	return result.(orderbook.Base), nil
+}
+
+// RefreshSpecificTicker fetches a fresh ticker from the exchange given the
+// currency pair, exchangeName and assetType, bypassing the cached ticker.
+// Concurrent refreshes of the same ticker share a single exchange request,
+// which is aborted when ctx is cancelled if the exchange supports it
+func RefreshSpecificTicker(ctx context.Context, p pair.CurrencyPair, exchangeName, assetType string) (ticker.Price, error) {
+	exch, assetType, err := getExchangeAssetType(exchangeName, assetType)
+	if err != nil {
+		return ticker.Price{}, err
+	}
+
+	p = exchangePair(exch, p)
+	key := exch.GetName() + "-" + p.Pair().String() + "-" + assetType
+	result, err := tickerRefreshes.Do(key, func() (interface{}, error) {
+		return updateTickerWithContext(ctx, exch, p, assetType)
+	})
+	if err != nil {
+		return ticker.Price{}, err
+	}
+	return result.(ticker.Price), nil
+}
+
+// GetSpecificOrderbook returns a specific orderbook given the currency pair,
 // exchangeName and assetType
-func GetSpecificOrderbook(currency, exchangeName, assetType string) (orderbook.Base, error) {
-	var specificOrderbook orderbook.Base
-	var err error
-	for x := range bot.exchanges {
-		if bot.exchanges[x] != nil {
-			if bot.exchanges[x].GetName() == exchangeName {
-				specificOrderbook, err = bot.exchanges[x].GetOrderbookEx(
-					pair.NewCurrencyPairFromString(currency),
-					assetType,
-				)
-				break
-			}
-		}
+func GetSpecificOrderbook(p pair.CurrencyPair, exchangeName, assetType string) (orderbook.Base, error) {
+	exch, assetType, err := getExchangeAssetType(exchangeName, assetType)
+	if err != nil {
+		return orderbook.Base{}, err
 	}
-	return specificOrderbook, err
+	return exch.GetOrderbookEx(exchangePair(exch, p), assetType)
 }
 
-// GetSpecificTicker returns a specific ticker given the currency,
+// GetSpecificTicker returns a specific ticker given the currency pair,
 // exchangeName and assetType
-func GetSpecificTicker(currency, exchangeName, assetType string) (ticker.Price, error) {
-	var specificTicker ticker.Price
-	var err error
-	for x := range bot.exchanges {
-		if bot.exchanges[x] != nil {
-			if bot.exchanges[x].GetName() == exchangeName {
-				specificTicker, err = bot.exchanges[x].GetTickerPrice(
-					pair.NewCurrencyPairFromString(currency),
-					assetType,
-				)
-				break
+func GetSpecificTicker(p pair.CurrencyPair, exchangeName, assetType string) (ticker.Price, error) {
+	exch, assetType, err := getExchangeAssetType(exchangeName, assetType)
+	if err != nil {
+		return ticker.Price{}, err
+	}
+	return exch.GetTickerPrice(exchangePair(exch, p), assetType)
+}
+
+// exchangePair returns the exchange's enabled or available currency pair
+// matching p, keeping the exchange's pair format, or p when the exchange
+// doesn't list the pair
+func exchangePair(exch exchange.IBotExchange, p pair.CurrencyPair) pair.CurrencyPair {
+	if exchPair, ok := enabledPair(exch, p); ok {
+		return exchPair
+	}
+	if exchPair := pair.CopyPairFormat(p, exch.GetAvailableCurrencies(), true); !exchPair.Empty() {
+		return exchPair
+	}
+	return p
+}
+
+// ParseExchangePair parses a currency pair supplied by a client for an
+// exchange. Pairs with a delimiter are split on it. Pairs without one are
+// matched against the exchange's currency pairs, so currencies which aren't
+// three characters long such as DOGEUSDT are split correctly, and are
+// otherwise split after the third character
+func ParseExchangePair(exchangeName, currency string) (pair.CurrencyPair, error) {
+	if len(currency) < 3 {
+		return pair.CurrencyPair{}, fmt.Errorf("invalid currency pair %q", currency)
+	}
+	p := pair.NewCurrencyPairFromString(currency)
+	if exch := GetExchangeByName(exchangeName); exch != nil && p.Delimiter == "" {
+		pairs := append(exch.GetEnabledCurrencies(), exch.GetAvailableCurrencies()...)
+		for x := range pairs {
+			if common.StringToUpper(pairs[x].FirstCurrency.String()+pairs[x].SecondCurrency.String()) ==
+				common.StringToUpper(currency) {
+				return pairs[x], nil
 			}
 		}
 	}
-	return specificTicker, err
+	if p.Empty() {
+		return pair.CurrencyPair{}, fmt.Errorf("invalid currency pair %q", currency)
+	}
+	return p, nil
+}
+
+// Pair display cases supported by PairDisplayFormat
+const (
+	PairCaseUpper = "upper"
+	PairCaseLower = "lower"
+)
+
+// PairDisplayFormat holds a client's overrides of the configured currency pair
+// display format, empty fields keep the configured format
+type PairDisplayFormat struct {
+	Delimiter string `json:"pairDelimiter,omitempty"`
+	Case      string `json:"pairCase,omitempty"`
+}
+
+// Validate returns an error if the case override isn't supported
+func (f PairDisplayFormat) Validate() error {
+	switch common.StringToLower(f.Case) {
+	case "", PairCaseUpper, PairCaseLower:
+		return nil
+	}
+	return fmt.Errorf("invalid pair case %q, supported values: %s, %s", f.Case,
+		PairCaseUpper, PairCaseLower)
+}
+
+// FormatPairForDisplay returns a currency pair in the configured display
+// format with the supplied overrides applied. The pair's own delimiter and
+// case are ignored, so pairs from the exchange APIs and websockets display the
+// same way
+func FormatPairForDisplay(p pair.CurrencyPair, format PairDisplayFormat) string {
+	delimiter, uppercase := "-", true
+	if bot.config != nil {
+		if cfg := bot.config.GetCurrencyPairDisplayConfig(); cfg != nil {
+			delimiter, uppercase = cfg.Delimiter, cfg.Uppercase
+		}
+	}
+
+	if format.Delimiter != "" {
+		delimiter = format.Delimiter
+	}
+	switch common.StringToLower(format.Case) {
+	case PairCaseUpper:
+		uppercase = true
+	case PairCaseLower:
+		uppercase = false
+	}
+	return p.Display(delimiter, uppercase).String()
+}
+
+// formatTickerPair returns the ticker with its pair string in the display
+// format
+func formatTickerPair(t ticker.Price, format PairDisplayFormat) ticker.Price {
+	t.CurrencyPair = FormatPairForDisplay(t.Pair, format)
+	return t
+}
+
+// formatOrderbookPair returns the orderbook with its pair string in the
+// display format
+func formatOrderbookPair(o orderbook.Base, format PairDisplayFormat) orderbook.Base {
+	o.CurrencyPair = FormatPairForDisplay(o.Pair, format)
+	return o
+}
+
+// ConvertTickerToDisplayCurrency converts the last, bid and ask values of a
+// ticker into the configured fiat display currency. The original ticker values
+// are left untouched, if the quote currency cannot be converted the
+// conversion is left empty
+func ConvertTickerToDisplayCurrency(t ticker.Price) DisplayTicker {
+	result := DisplayTicker{Price: t}
+	displayCurrency := bot.config.Currency.FiatDisplayCurrency
+	quote := t.Pair.Quote().Upper().String()
+	if displayCurrency == "" || !currency.IsFiatCurrency(quote) {
+		return result
+	}
+
+	rate, err := currency.ConvertCurrency(1, quote, displayCurrency)
+	if err != nil {
+		log.Debugf("Unable to convert %s to display currency %s: %s", quote,
+			displayCurrency, err)
+		return result
+	}
+
+	var converted [3]float64
+	for i, v := range []float64{t.Last, t.Bid, t.Ask} {
+		converted[i], err = currency.ConvertCurrency(v, quote, displayCurrency)
+		if err != nil {
+			log.Debugf("Unable to convert %s to display currency %s: %s", quote,
+				displayCurrency, err)
+			return result
+		}
+	}
+
+	result.Conversion = TickerConversion{
+		DisplayCurrency: displayCurrency,
+		Rate:            rate,
+		Last:            converted[0],
+		Bid:             converted[1],
+		Ask:             converted[2],
+	}
+	return result
+}
+
+// ConvertTickersToDisplayCurrency converts each enabled exchange ticker into
+// the configured fiat display currency
+func ConvertTickersToDisplayCurrency(data []EnabledExchangeCurrencies) []EnabledExchangeDisplayTickers {
+	result := []EnabledExchangeDisplayTickers{}
+	for x := range data {
+		exch := EnabledExchangeDisplayTickers{ExchangeName: data[x].ExchangeName}
+		for y := range data[x].ExchangeValues {
+			exch.ExchangeValues = append(exch.ExchangeValues,
+				ConvertTickerToDisplayCurrency(data[x].ExchangeValues[y]))
+		}
+		result = append(result, exch)
+	}
+	return result
+}
+
+// normaliseAccountCurrency returns the canonical currency name for an account
+// currency and records any normalisation applied
+func normaliseAccountCurrency(currencyName string, normalised map[string]string) string {
+	canonical := translation.GetCanonicalCurrency(pair.CurrencyItem(currencyName)).String()
+	if canonical != currencyName {
+		normalised[currencyName] = canonical
+	}
+	return canonical
 }
 
 // GetCollatedExchangeAccountInfoByCoin collates individual exchange account
 // information and turns into into a map string of
-// exchange.AccountCurrencyInfo
-func GetCollatedExchangeAccountInfoByCoin(exchAccounts []exchange.AccountInfo) map[string]exchange.AccountCurrencyInfo {
+// exchange.AccountCurrencyInfo. Currency names are upper cased and known
+// aliases mapped to their canonical name before merging, the normalisations
+// applied are returned as a map of original to canonical currency names.
+// The optional values are only set when an exchange reports them, the
+// available value then includes the available value of exchanges which don't
+func GetCollatedExchangeAccountInfoByCoin(exchAccounts []exchange.AccountInfo) (map[string]exchange.AccountCurrencyInfo, map[string]string) {
 	result := make(map[string]exchange.AccountCurrencyInfo)
+	available := make(map[string]float64)
+	normalised := make(map[string]string)
 	for _, accounts := range exchAccounts {
 		for _, account := range accounts.Accounts {
-			for _, accountCurrencyInfo := range account.Currencies {
-				currencyName := accountCurrencyInfo.CurrencyName
-				avail := accountCurrencyInfo.TotalValue
-				onHold := accountCurrencyInfo.Hold
+			for x := range account.Currencies {
+				accountCurrencyInfo := &account.Currencies[x]
+				currencyName := normaliseAccountCurrency(accountCurrencyInfo.CurrencyName, normalised)
 
 				info, ok := result[currencyName]
 				if !ok {
-					accountInfo := exchange.AccountCurrencyInfo{CurrencyName: currencyName, Hold: onHold, TotalValue: avail}
-					result[currencyName] = accountInfo
-				} else {
-					info.Hold += onHold
-					info.TotalValue += avail
-					result[currencyName] = info
+					info.CurrencyName = currencyName
 				}
+				info.Hold += accountCurrencyInfo.Hold
+				info.TotalValue += accountCurrencyInfo.TotalValue
+				available[currencyName] += accountCurrencyInfo.Available()
+				if accountCurrencyInfo.AvailableValue != nil {
+					info.AvailableValue = new(float64)
+				}
+				info.MarginBalance = addAccountValue(info.MarginBalance,
+					accountCurrencyInfo.MarginBalance)
+				info.UnrealisedPNL = addAccountValue(info.UnrealisedPNL,
+					accountCurrencyInfo.UnrealisedPNL)
+				result[currencyName] = info
 			}
 		}
 	}
+
+	for currencyName, info := range result {
+		if info.AvailableValue != nil {
+			*info.AvailableValue = available[currencyName]
+		}
+	}
+	return result, normalised
+}
+
+// addAccountValue returns the sum of two optional account values, nil if
+// neither is set
+func addAccountValue(total, value *float64) *float64 {
+	if value == nil {
+		return total
+	}
+	sum := *value
+	if total != nil {
+		sum += *total
+	}
+	return &sum
+}
+
+// FormatExchangeAccountName namespaces an exchange name with a sub-account
+// label (e.g "OKEX:hedge"). An empty label returns the exchange name
+func FormatExchangeAccountName(exchName, account string) string {
+	if account == "" {
+		return exchName
+	}
+	return exchName + config.AccountLabelSeparator + account
+}
+
+// SplitExchangeAccountName splits a namespaced exchange account name into the
+// exchange name and sub-account label
+func SplitExchangeAccountName(name string) (exchName, account string) {
+	split := common.SplitStrings(name, config.AccountLabelSeparator)
+	if len(split) < 2 {
+		return name, ""
+	}
+	return split[0], common.JoinStrings(split[1:], config.AccountLabelSeparator)
+}
+
+// GetExchangeAccountInfo returns the account info for an exchange using either
+// the default credentials or the supplied sub-account label. Sub-account
+// results are namespaced with the account label
+func GetExchangeAccountInfo(exchName, account string) (exchange.AccountInfo, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return exchange.AccountInfo{}, ErrExchangeNotFound
+	}
+
+	accountExch, err := GetAccountExchange(exch, account)
+	if err != nil {
+		return exchange.AccountInfo{}, err
+	}
+
+	result, err := accountExch.GetAccountInfo()
+	if err != nil {
+		return result, err
+	}
+
+	if account != "" {
+		result.Exchange = FormatExchangeAccountName(exch.GetName(), account)
+		for x := range result.Accounts {
+			if result.Accounts[x].ID == "" {
+				result.Accounts[x].ID = account
+			}
+		}
+	}
+	return result, nil
+}
+
+// SubmitExchangeOrder submits an order to an exchange using either the default
+// credentials or the supplied sub-account label. Orders for pairs whose
+// trading is halted or which are delisted are rejected
+func SubmitExchangeOrder(exchName, account string, order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return exchange.SubmitOrderResponse{}, ErrExchangeNotFound
+	}
+
+	err := ValidateOrder(exch, order)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+
+	err = exch.CheckPairTradable(order.Pair)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+
+	accountExch, err := GetAccountExchange(exch, account)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+
+	result, err := accountExch.SubmitOrder(order)
+	if err == nil && result.IsOrderPlaced {
+		RequestBalanceRefresh()
+		trackOrder(exch.GetName(), account, order, result.OrderID)
+	}
+	return result, err
+}
+
+// ErrInvalidOrder is matched by order validation errors using errors.Is
+var ErrInvalidOrder = errors.New("invalid order")
+
+// OrderValidationError lists every rule an order violated
+type OrderValidationError struct {
+	Exchange   string
+	Violations []string
+}
+
+func (o *OrderValidationError) Error() string {
+	if o.Exchange == "" {
+		return fmt.Sprintf("%s: %s", ErrInvalidOrder,
+			common.JoinStrings(o.Violations, ", "))
+	}
+	return fmt.Sprintf("%s %s: %s", o.Exchange, ErrInvalidOrder,
+		common.JoinStrings(o.Violations, ", "))
+}
+
+// Is matches ErrInvalidOrder
+func (o *OrderValidationError) Is(target error) bool {
+	return target == ErrInvalidOrder
+}
+
+// ValidateOrder checks an order before it is submitted to an exchange. The
+// side and type must be set, the amount and limit price must be positive, the
+// pair must be enabled on the exchange for a supported asset type and spot
+// amounts must be within the exchange's order execution limits when known.
+// The order is not modified
+func ValidateOrder(exch exchange.IBotExchange, order *exchange.OrderSubmission) error {
+	verr := &OrderValidationError{Exchange: exch.GetName()}
+	if order == nil {
+		verr.Violations = append(verr.Violations, "order not set")
+		return verr
+	}
+
+	if order.OrderSide == "" {
+		verr.Violations = append(verr.Violations, "order side not set")
+	}
+	if order.OrderType == "" {
+		verr.Violations = append(verr.Violations, "order type not set")
+	}
+	if !(order.Amount > 0) || math.IsInf(order.Amount, 1) {
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("amount %v must be greater than zero", order.Amount))
+	}
+	switch {
+	case (order.OrderType == exchange.Limit || order.OrderType == exchange.StopLimit) &&
+		!(order.Price > 0):
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("limit price %v must be greater than zero", order.Price))
+	case order.Price < 0 || math.IsNaN(order.Price):
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("price %v must not be negative", order.Price))
+	}
+	switch order.OrderType {
+	case exchange.Stop, exchange.StopLimit:
+		if !(order.TriggerPrice > 0) {
+			verr.Violations = append(verr.Violations,
+				fmt.Sprintf("trigger price %v must be greater than zero",
+					order.TriggerPrice))
+		}
+	case exchange.TrailingStop:
+		if !(order.PegOffset > 0) {
+			verr.Violations = append(verr.Violations,
+				fmt.Sprintf("trailing offset %v must be greater than zero",
+					order.PegOffset))
+		}
+	}
+
+	assetType := order.AssetType
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+	if !exch.SupportsAsset(assetType) {
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("asset type %s not supported", assetType))
+	}
+
+	if order.Pair.Pair().String() == "" {
+		verr.Violations = append(verr.Violations, "currency pair not set")
+	} else if !pair.Contains(exch.GetEnabledCurrencies(), order.Pair, true) {
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("currency pair %s not enabled", order.Pair.Pair()))
+	} else if assetType == ticker.Spot && order.Amount > 0 {
+		limits, err := exch.GetOrderExecutionLimits(order.Pair)
+		if err == nil {
+			if order.Amount < limits.MinAmount {
+				verr.Violations = append(verr.Violations,
+					fmt.Sprintf("amount %v below minimum %v", order.Amount,
+						limits.MinAmount))
+			}
+			if limits.MaxAmount > 0 && order.Amount > limits.MaxAmount {
+				verr.Violations = append(verr.Violations,
+					fmt.Sprintf("amount %v above maximum %v", order.Amount,
+						limits.MaxAmount))
+			}
+		}
+	}
+
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// GetCryptocurrenciesByExchange returns the unique cryptocurrencies in an
+// exchange's enabled currency pairs
+func GetCryptocurrenciesByExchange(exch exchange.IBotExchange) []pair.CurrencyItem {
+	var result []pair.CurrencyItem
+	seen := make(map[string]bool)
+	for _, p := range exch.GetEnabledCurrencies() {
+		for _, c := range []pair.CurrencyItem{p.FirstCurrency, p.SecondCurrency} {
+			name := c.Upper().String()
+			if seen[name] || !currency.IsCryptocurrency(name) {
+				continue
+			}
+			seen[name] = true
+			result = append(result, c.Upper())
+		}
+	}
 	return result
 }
 
+// GetExchangeCryptocurrencyDepositAddresses returns the deposit addresses of
+// the enabled cryptocurrencies for all exchanges returned by
+// GetAuthAPISupportedExchanges, keyed by exchange name and currency. Cached
+// addresses are used unless forceRefresh is set
+func GetExchangeCryptocurrencyDepositAddresses(forceRefresh bool) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, exch := range GetAuthAPISupportedExchanges() {
+		result[exch.GetName()] = getCryptocurrencyDepositAddresses(exch, forceRefresh)
+	}
+	return result
+}
+
+// getCryptocurrencyDepositAddresses returns the deposit addresses of the
+// enabled cryptocurrencies of an exchange keyed by currency, fetching stops
+// early if the bot shuts down
+func getCryptocurrencyDepositAddresses(exch exchange.IBotExchange, forceRefresh bool) map[string]string {
+	addresses := make(map[string]string)
+	for _, c := range GetCryptocurrenciesByExchange(exch) {
+		select {
+		case <-shutdowner:
+			return addresses
+		default:
+		}
+
+		addr, err := exch.GetDepositAddress(c, "", forceRefresh)
+		if err != nil {
+			log.Debugf("%s failed to get %s deposit address. Error: %s",
+				exch.GetName(), c, err)
+			continue
+		}
+		addresses[c.String()] = addr
+	}
+	return addresses
+}
+
+// warmDepositAddressCache loads the deposit addresses of the exchanges
+// returned by GetAuthAPISupportedExchanges into their caches in the
+// background, one routine per exchange so an exchange which is slow to
+// generate addresses doesn't hold up startup or the other exchanges. The
+// returned channel is closed once every exchange is done
+func warmDepositAddressCache() <-chan struct{} {
+	done := make(chan struct{})
+	var warmWG sync.WaitGroup
+	for _, exch := range GetAuthAPISupportedExchanges() {
+		warmWG.Add(1)
+		go func(exch exchange.IBotExchange) {
+			defer warmWG.Done()
+			addresses := getCryptocurrencyDepositAddresses(exch, false)
+			log.Debugf("%s deposit addresses loaded for %d currencies.\n",
+				exch.GetName(), len(addresses))
+		}(exch)
+	}
+
+	go func() {
+		warmWG.Wait()
+		close(done)
+	}()
+	return done
+}
+
 // GetAccountCurrencyInfoByExchangeName returns info for an exchange
 func GetAccountCurrencyInfoByExchangeName(accounts []exchange.AccountInfo, exchangeName string) (exchange.AccountInfo, error) {
 	for i := 0; i < len(accounts); i++ {
@@ -317,10 +922,123 @@ func GetExchangeLowestPriceByCurrencyPair(p pair.CurrencyPair, assetType string)
 	return result[0].Exchange, nil
 }
 
-// SeedExchangeAccountInfo seeds account info
-func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
+// ErrNoStats is returned when no exchange stats have been collected for a
+// currency pair and asset type
+var ErrNoStats = errors.New("no stats collected yet for supplied currency pair and asset type")
+
+// ExchangeStats holds the exchanges ranked by price or volume for a currency
+// pair and asset type
+type ExchangeStats struct {
+	Pair                 string       `json:"pair"`
+	AssetType            string       `json:"assetType"`
+	SortedByVolume       bool         `json:"sortedByVolume"`
+	HighestPriceExchange string       `json:"highestPriceExchange"`
+	LowestPriceExchange  string       `json:"lowestPriceExchange"`
+	Exchanges            []stats.Item `json:"exchanges"`
+}
+
+// parseExchangesAssetType validates an asset type against the asset types
+// supported by the enabled exchanges and returns its canonical form
+func parseExchangesAssetType(assetType string) (string, error) {
+	var supported []string
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil || !bot.exchanges[x].IsEnabled() {
+			continue
+		}
+		for _, a := range bot.exchanges[x].GetSupportedAssetTypes() {
+			if !common.StringDataCompare(supported, a) {
+				supported = append(supported, a)
+			}
+		}
+	}
+	return assets.Parse(assetType, supported)
+}
+
+// GetExchangeStats returns the exchanges ranked from highest to lowest price,
+// or volume if byVolume is set, for a currency pair and asset type
+func GetExchangeStats(p pair.CurrencyPair, assetType string, byVolume bool) (ExchangeStats, error) {
+	assetType, err := parseExchangesAssetType(assetType)
+	if err != nil {
+		return ExchangeStats{}, err
+	}
+
+	result := ExchangeStats{
+		Pair:           p.Pair().String(),
+		AssetType:      assetType,
+		SortedByVolume: byVolume,
+	}
+
+	if byVolume {
+		result.Exchanges = stats.SortExchangesByVolume(p, assetType, true)
+	} else {
+		result.Exchanges = stats.SortExchangesByPrice(p, assetType, true)
+	}
+
+	if len(result.Exchanges) == 0 {
+		return result, ErrNoStats
+	}
+
+	result.HighestPriceExchange, err = GetExchangeHighestPriceByCurrencyPair(p, assetType)
+	if err != nil {
+		return result, err
+	}
+
+	result.LowestPriceExchange, err = GetExchangeLowestPriceByCurrencyPair(p, assetType)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// ErrUnknownCoin is returned when registering a portfolio address for a coin or
+// token which isn't a known cryptocurrency
+var ErrUnknownCoin = errors.New("unknown coin type")
+
+// PortfolioAddressRequest holds a watched portfolio address to register.
+// Tokens are only supported by coins whose tokens share their address format,
+// such as ERC-20 tokens on an ETH address
+type PortfolioAddressRequest struct {
+	Address          string   `json:"address"`
+	CoinType         string   `json:"coinType"`
+	Description      string   `json:"description,omitempty"`
+	Balance          float64  `json:"balance,omitempty"`
+	Tokens           []string `json:"tokens,omitempty"`
+	AllowUnknownCoin bool     `json:"allowUnknownCoin,omitempty"`
+}
+
+// AddPortfolioAddress validates and registers a watched portfolio address,
+// returning the registered address. The coin type and tokens must be known
+// cryptocurrencies unless unknown coins are allowed
+func AddPortfolioAddress(request PortfolioAddressRequest) (portfolio.Address, error) {
+	if !request.AllowUnknownCoin {
+		coins := append([]string{request.CoinType}, request.Tokens...)
+		for x := range coins {
+			if !currency.IsCryptocurrency(coins[x]) &&
+				!currency.IsDefaultCryptocurrency(coins[x]) {
+				return portfolio.Address{}, fmt.Errorf("%w %q", ErrUnknownCoin,
+					coins[x])
+			}
+		}
+	}
+
+	address, err := bot.portfolio.RegisterAddress(request.Address,
+		request.CoinType, request.Description, request.Balance, request.Tokens)
+	if err != nil {
+		return portfolio.Address{}, err
+	}
+	log.Debugf("Portfolio: Added %s address %s.\n", address.CoinType,
+		address.Address)
+	MarkConfigDirty()
+	return address, nil
+}
+
+// SeedExchangeAccountInfo seeds account info, normalising currency names in
+// the same way as GetCollatedExchangeAccountInfoByCoin. The normalisations
+// applied are returned as a map of original to canonical currency names
+func SeedExchangeAccountInfo(data []exchange.AccountInfo) map[string]string {
+	normalised := make(map[string]string)
 	if len(data) == 0 {
-		return
+		return normalised
 	}
 
 	port := portfolio.GetPortfolio()
@@ -331,6 +1049,7 @@ func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
 		var currencies []exchange.AccountCurrencyInfo
 		for _, account := range exchangeData.Accounts {
 			for _, info := range account.Currencies {
+				info.CurrencyName = normaliseAccountCurrency(info.CurrencyName, normalised)
 
 				var update bool
 				for i := range currencies {
@@ -368,12 +1087,7 @@ func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
 					total,
 					portfolio.PortfolioAddressExchange)
 
-				port.Addresses = append(
-					port.Addresses,
-					portfolio.Address{Address: exchangeName,
-						CoinType:    currencyName,
-						Balance:     total,
-						Description: portfolio.PortfolioAddressExchange})
+				port.AddExchangeAddress(exchangeName, currencyName, total)
 
 			} else {
 				if total <= 0 {
@@ -405,4 +1119,50 @@ func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
 			}
 		}
 	}
+	return normalised
+}
+
+// ExchangeAssetTypes holds the supported and enabled asset types of an
+// exchange
+type ExchangeAssetTypes struct {
+	Exchange  string   `json:"exchange"`
+	Supported []string `json:"supported"`
+	Enabled   []string `json:"enabled"`
+}
+
+// GetExchangeAssetTypeInfo returns the supported and enabled asset types of an
+// exchange
+func GetExchangeAssetTypeInfo(exchName string) (ExchangeAssetTypes, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return ExchangeAssetTypes{}, ErrExchangeNotFound
+	}
+	return ExchangeAssetTypes{
+		Exchange:  exch.GetName(),
+		Supported: exch.GetSupportedAssetTypes(),
+		Enabled:   exch.GetAssetTypes(),
+	}, nil
+}
+
+// SetExchangeAssetTypeEnabled enables or disables an asset type for an
+// exchange at runtime. Disabled asset types are skipped by the ticker and
+// orderbook updater routines and their stored tickers and orderbooks are
+// removed
+func SetExchangeAssetTypeEnabled(exchName, assetType string, enabled bool) error {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return ErrExchangeNotFound
+	}
+	err := exch.SetAssetTypeEnabled(assetType, enabled)
+	if err != nil {
+		return err
+	}
+	MarkConfigDirty()
+	if !enabled {
+		removePairMarketData(exch.GetName(), exch.GetEnabledCurrencies(),
+			[]string{assetType})
+	}
+	log.Debugf("%s asset types enabled: %s", exch.GetName(),
+		common.JoinStrings(exch.GetAssetTypes(), ", "))
+	return nil
 }
@@ -1,17 +1,22 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
 const (
@@ -40,6 +45,129 @@ func SetupTestHelpers(t *testing.T) {
 	}
 }
 
+// newPairsConfig returns a config with an exchange per supplied available
+// pairs list, exchanges are enabled unless listed in disabled
+func newPairsConfig(disabled map[int]bool, availablePairs ...string) *config.Config {
+	cfg := &config.Config{}
+	for x := range availablePairs {
+		cfg.Exchanges = append(cfg.Exchanges, config.ExchangeConfig{
+			Name:           fmt.Sprintf("Exchange%d", x),
+			Enabled:        !disabled[x],
+			AvailablePairs: availablePairs[x],
+			ConfigCurrencyPairFormat: &config.CurrencyPairFormatConfig{
+				Delimiter: "-",
+				Uppercase: true,
+			},
+		})
+	}
+	return cfg
+}
+
+func pairStrings(pairs []pair.CurrencyPair) string {
+	var s []string
+	for x := range pairs {
+		s = append(s, pairs[x].Pair().String())
+	}
+	return common.JoinStrings(s, ",")
+}
+
+func TestGetAllAvailablePairs(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(cfg *config.Config) { bot.config = cfg }(bot.config)
+
+	// Aliases, case and reciprocal pairs are duplicates, USDT isn't an alias
+	// of USD
+	bot.config = newPairsConfig(map[int]bool{2: true},
+		"BTC-USD,LTC-BTC,BTC-USDT",
+		"XBT-USD,usd-btc,ETH-EUR,ltc-btc,LTC-USDT",
+		"DOGE-BTC,XDG-BTC")
+
+	expected := "BTC-USD,LTC-BTC,BTC-USDT,ETH-EUR,LTC-USDT"
+	if result := pairStrings(GetAllAvailablePairs(true)); result != expected {
+		t.Errorf("Test failed. Expected %s, got %s", expected, result)
+	}
+
+	expected += ",DOGE-BTC"
+	if result := pairStrings(GetAllAvailablePairs(false)); result != expected {
+		t.Errorf("Test failed. Expected %s, got %s", expected, result)
+	}
+}
+
+func TestGetSpecificAvailablePairsFilters(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(cfg *config.Config) { bot.config = cfg }(bot.config)
+
+	bot.config = newPairsConfig(nil,
+		"BTC-USD,LTC-BTC,BTC-USDT",
+		"XBT-USD,ETH-EUR,LTC-USDT,ltc-btc")
+
+	tests := []struct {
+		fiatPairs, includeUSDT, cryptoPairs bool
+		expected                            string
+	}{
+		{true, true, false, "BTC-USD,BTC-USDT,ETH-EUR,LTC-USDT"},
+		{true, false, false, "BTC-USD,ETH-EUR"},
+		{false, true, false, ""},
+		{false, false, true, "LTC-BTC,BTC-USDT,LTC-USDT"},
+		{true, false, true, "BTC-USD,LTC-BTC,BTC-USDT,ETH-EUR,LTC-USDT"},
+		{true, true, true, "BTC-USD,LTC-BTC,BTC-USDT,ETH-EUR,LTC-USDT"},
+	}
+
+	for _, test := range tests {
+		result := pairStrings(GetSpecificAvailablePairs(true, test.fiatPairs,
+			test.includeUSDT, test.cryptoPairs))
+		if result != test.expected {
+			t.Errorf("Test failed. fiat %v USDT %v crypto %v expected %s, got %s",
+				test.fiatPairs, test.includeUSDT, test.cryptoPairs, test.expected,
+				result)
+		}
+	}
+}
+
+// newBenchmarkPairsConfig returns a config of 10 exchanges sharing the same
+// 2000 synthetic pairs
+func newBenchmarkPairsConfig() *config.Config {
+	var pairs []string
+	for x := 0; x < 2000; x++ {
+		pairs = append(pairs, fmt.Sprintf("C%d-BTC", x))
+	}
+	var available []string
+	for x := 0; x < 10; x++ {
+		available = append(available, common.JoinStrings(pairs, ","))
+	}
+	return newPairsConfig(nil, available...)
+}
+
+// BenchmarkGetAllAvailablePairs benchmarks the canonical pair deduplication
+func BenchmarkGetAllAvailablePairs(b *testing.B) {
+	defer func(cfg *config.Config) { bot.config = cfg }(bot.config)
+	bot.config = newBenchmarkPairsConfig()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		GetAllAvailablePairs(true)
+	}
+}
+
+// BenchmarkGetAllAvailablePairsLinear benchmarks the previous linear scan
+// deduplication for comparison
+func BenchmarkGetAllAvailablePairsLinear(b *testing.B) {
+	defer func(cfg *config.Config) { bot.config = cfg }(bot.config)
+	bot.config = newBenchmarkPairsConfig()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var pairList []pair.CurrencyPair
+		for x := range bot.config.Exchanges {
+			pairs, _ := bot.config.GetAvailablePairs(bot.config.Exchanges[x].Name)
+			for y := range pairs {
+				if pair.Contains(pairList, pairs[y], false) {
+					continue
+				}
+				pairList = append(pairList, pairs[y])
+			}
+		}
+	}
+}
+
 func TestGetSpecificAvailablePairs(t *testing.T) {
 	SetupTestHelpers(t)
 	result := GetSpecificAvailablePairs(true, true, true, false)
@@ -154,25 +282,50 @@ func TestIsRelatablePairs(t *testing.T) {
 
 func TestGetRelatableCryptocurrencies(t *testing.T) {
 	SetupTestHelpers(t)
-	p := GetRelatableCryptocurrencies(pair.NewCurrencyPair("BTC", "LTC"))
+	p := GetRelatableCryptocurrencies(pair.NewCurrencyPair("BTC", "LTC"), false)
 	if !pair.Contains(p, pair.NewCurrencyPair("BTC", "ETH"), true) {
 		t.Fatal("Unexpected result")
 	}
 
 	backup := currency.CryptoCurrencies
-	currency.CryptoCurrencies = append(currency.CryptoCurrencies, "BTC")
+	currency.CryptoCurrencies = append(currency.CryptoCurrencies, "BTC", "btc", "eth", "ZZZ")
 
-	p = GetRelatableCryptocurrencies(pair.NewCurrencyPair("BTC", "LTC"))
+	p = GetRelatableCryptocurrencies(pair.NewCurrencyPair("BTC", "LTC"), false)
 	if !pair.Contains(p, pair.NewCurrencyPair("BTC", "ETH"), true) {
 		t.Fatal("Unexpected result")
 	}
+	if pair.Contains(p, pair.NewCurrencyPair("BTC", "BTC"), true) {
+		t.Error("Test failed. GetRelatableCryptocurrencies returned a self-pair")
+	}
+	var eth int
+	for x := range p {
+		if p[x].Equal(pair.NewCurrencyPair("BTC", "ETH"), true) {
+			eth++
+		}
+	}
+	if eth != 1 {
+		t.Errorf("Test failed. GetRelatableCryptocurrencies returned BTCETH %d times", eth)
+	}
+	if !pair.Contains(p, pair.NewCurrencyPair("BTC", "ZZZ"), true) {
+		t.Error("Test failed. GetRelatableCryptocurrencies unexpectedly filtered BTCZZZ")
+	}
+
+	available := GetRelatableCryptocurrencies(pair.NewCurrencyPair("BTC", "LTC"), true)
+	if !pair.Contains(available, pair.NewCurrencyPair("BTC", "LTC"), true) ||
+		!pair.Contains(available, pair.NewCurrencyPair("BTC", "ETH"), true) {
+		t.Error("Test failed. GetRelatableCryptocurrencies filtered an available pair")
+	}
+	if pair.Contains(available, pair.NewCurrencyPair("BTC", "ZZZ"), true) ||
+		len(available) >= len(p) {
+		t.Error("Test failed. GetRelatableCryptocurrencies returned an unavailable pair")
+	}
 
 	currency.CryptoCurrencies = backup
 }
 
 func TestGetRelatableFiatCurrencies(t *testing.T) {
 	SetupTestHelpers(t)
-	p := GetRelatableFiatCurrencies(pair.NewCurrencyPair("BTC", "USD"))
+	p := GetRelatableFiatCurrencies(pair.NewCurrencyPair("BTC", "USD"), false)
 	if !pair.Contains(p, pair.NewCurrencyPair("BTC", "EUR"), true) {
 		t.Fatal("Unexpected result")
 	}
@@ -180,11 +333,16 @@ func TestGetRelatableFiatCurrencies(t *testing.T) {
 	backup := currency.FiatCurrencies
 	currency.FiatCurrencies = append(currency.FiatCurrencies, "USD")
 
-	p = GetRelatableFiatCurrencies(pair.NewCurrencyPair("BTC", "USD"))
+	p = GetRelatableFiatCurrencies(pair.NewCurrencyPair("BTC", "USD"), false)
 	if !pair.Contains(p, pair.NewCurrencyPair("BTC", "ZAR"), true) {
 		t.Fatal("Unexpected result")
 	}
 
+	p = GetRelatableFiatCurrencies(pair.NewCurrencyPair("USD", "BTC"), false)
+	if pair.Contains(p, pair.NewCurrencyPair("USD", "USD"), true) {
+		t.Error("Test failed. GetRelatableFiatCurrencies returned a self-pair")
+	}
+
 	currency.FiatCurrencies = backup
 }
 
@@ -226,6 +384,48 @@ func TestGetExchangeNamesByCurrency(t *testing.T) {
 	}
 }
 
+func TestExchangePairHelpersLivePairs(t *testing.T) {
+	SetupTestHelpers(t)
+
+	exchanges := bot.exchanges
+	defer func() { bot.exchanges = exchanges }()
+	// BTCUSD has been delisted and BTCNZD auto discovered since the config
+	// was saved
+	live := newTestExchange("Bitstamp")
+	live.availablePairs = []pair.CurrencyPair{pair.NewCurrencyPair("BTC", "EUR"),
+		pair.NewCurrencyPair("BTC", "NZD")}
+	bot.exchanges = []exchange.IBotExchange{live}
+
+	pairs := []pair.CurrencyPair{pair.NewCurrencyPair("BTC", "USD"),
+		pair.NewCurrencyPair("BTC", "NZD"), pair.NewCurrencyPair("BTC", "JPY")}
+	result := MapCurrenciesByExchange(pairs, true)
+	if len(result["Bitstamp"]) != 1 || result["Bitstamp"][0].Pair().String() != "BTCNZD" {
+		t.Errorf("Test failed. Live pairs not used %v", result["Bitstamp"])
+	}
+	// Bitflyer isn't loaded so its config pairs are used
+	if len(result["Bitflyer"]) != 1 || result["Bitflyer"][0].Pair().String() != "BTCJPY" {
+		t.Errorf("Test failed. Config pairs not used %v", result["Bitflyer"])
+	}
+
+	names := GetExchangeNamesByCurrency(pair.NewCurrencyPair("BTC", "NZD"), true)
+	if !common.StringDataCompare(names, "Bitstamp") {
+		t.Errorf("Test failed. Auto discovered pair not found %v", names)
+	}
+	names = GetExchangeNamesByCurrency(pair.NewCurrencyPair("BTC", "USD"), true)
+	if common.StringDataCompare(names, "Bitstamp") {
+		t.Errorf("Test failed. Delisted pair found %v", names)
+	}
+
+	live.base.Enabled = false
+	names = GetExchangeNamesByCurrency(pair.NewCurrencyPair("BTC", "NZD"), false)
+	if !common.StringDataCompare(names, "Bitstamp") {
+		t.Errorf("Test failed. Live enabled state not used %v", names)
+	}
+	if _, ok := MapCurrenciesByExchange(pairs, true)["Bitstamp"]; ok {
+		t.Error("Test failed. Disabled exchange mapped")
+	}
+}
+
 func TestGetSpecificOrderbook(t *testing.T) {
 	SetupTestHelpers(t)
 
@@ -235,7 +435,7 @@ func TestGetSpecificOrderbook(t *testing.T) {
 	bids = append(bids, orderbook.Item{Price: 1000, Amount: 1})
 
 	orderbook.ProcessOrderbook("Bitstamp", p, orderbook.Base{Pair: p, Bids: bids}, ticker.Spot)
-	ob, err := GetSpecificOrderbook("BTCUSD", "Bitstamp", ticker.Spot)
+	ob, err := GetSpecificOrderbook(p, "Bitstamp", ticker.Spot)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -244,7 +444,7 @@ func TestGetSpecificOrderbook(t *testing.T) {
 		t.Fatal("Unexpected result")
 	}
 
-	ob, err = GetSpecificOrderbook("ETHLTC", "Bitstamp", ticker.Spot)
+	ob, err = GetSpecificOrderbook(pair.NewCurrencyPair("ETH", "LTC"), "Bitstamp", ticker.Spot)
 	if err == nil {
 		t.Fatal("Unexpected result")
 	}
@@ -259,7 +459,7 @@ func TestGetSpecificTicker(t *testing.T) {
 	p := pair.NewCurrencyPair("BTC", "USD")
 	ticker.ProcessTicker("Bitstamp", p, ticker.Price{Last: 1000}, ticker.Spot)
 
-	tick, err := GetSpecificTicker("BTCUSD", "Bitstamp", ticker.Spot)
+	tick, err := GetSpecificTicker(p, "Bitstamp", ticker.Spot)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -268,14 +468,127 @@ func TestGetSpecificTicker(t *testing.T) {
 		t.Fatal("Unexpected result")
 	}
 
-	tick, err = GetSpecificTicker("ETHLTC", "Bitstamp", ticker.Spot)
+	tick, err = GetSpecificTicker(pair.NewCurrencyPair("ETH", "LTC"), "Bitstamp", ticker.Spot)
 	if err == nil {
 		t.Fatal("Unexpected result")
 	}
 
+	tick, err = GetSpecificTicker(p, "Bitstamp", "spot")
+	if err != nil || tick.Last != 1000 {
+		t.Fatal("Unexpected result for case insensitive asset type")
+	}
+
+	_, err = GetSpecificTicker(p, "Bitstamp", "future")
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Fatalf("Expected invalid asset type error, got %v", err)
+	}
+
 	UnloadExchange("Bitstamp")
 }
 
+// newPairLookupExchange returns a test exchange named Pairs listing the
+// pairs, recording the pair its ticker and orderbook are requested for
+func newPairLookupExchange(pairs []pair.CurrencyPair, requested *pair.CurrencyPair) *testExchange {
+	exch := newTestExchange("Pairs")
+	exch.enabledPairs = pairs
+	exch.availablePairs = pairs
+	exch.getTickerPrice = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		*requested = p
+		return ticker.Price{Pair: p, Last: 0.25}, nil
+	}
+	exch.getOrderbookEx = func(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+		*requested = p
+		return orderbook.Base{Pair: p}, nil
+	}
+	return exch
+}
+
+func TestGetSpecificTickerNoDelimiterPair(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	doge := pair.NewCurrencyPairDelimiter("DOGE-USDT", "-")
+	var requested pair.CurrencyPair
+	bot.exchanges = []exchange.IBotExchange{
+		newPairLookupExchange([]pair.CurrencyPair{doge}, &requested)}
+
+	// Splitting the bare string after three characters misparses the pair
+	if old := pair.NewCurrencyPairFromString("DOGEUSDT"); old.FirstCurrency == "DOGE" {
+		t.Fatalf("Test failed. Expected DOGEUSDT to be misparsed, got %v", old)
+	}
+
+	p, err := ParseExchangePair("Pairs", "DOGEUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.FirstCurrency != "DOGE" || p.SecondCurrency != "USDT" {
+		t.Fatalf("Test failed. Expected DOGE USDT, got %s %s", p.FirstCurrency,
+			p.SecondCurrency)
+	}
+
+	tick, err := GetSpecificTicker(pair.NewCurrencyPair("doge", "usdt"), "Pairs", ticker.Spot)
+	if err != nil || tick.Last != 0.25 {
+		t.Fatalf("Test failed. Unexpected ticker %+v: %v", tick, err)
+	}
+	if requested != doge {
+		t.Errorf("Test failed. Expected the exchange pair %v, got %v", doge, requested)
+	}
+
+	_, err = GetSpecificOrderbook(p, "Pairs", ticker.Spot)
+	if err != nil || requested != doge {
+		t.Errorf("Test failed. Expected the exchange pair %v, got %v: %v", doge,
+			requested, err)
+	}
+
+	_, err = GetSpecificTicker(p, "Missing", ticker.Spot)
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %s, got %v", ErrExchangeNotFound, err)
+	}
+}
+
+func TestParseExchangePair(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	var requested pair.CurrencyPair
+	bot.exchanges = []exchange.IBotExchange{newPairLookupExchange(
+		[]pair.CurrencyPair{pair.NewCurrencyPairDelimiter("DOGE-USDT", "-")},
+		&requested)}
+
+	tests := []struct {
+		exchange, currency string
+		first, second      string
+		err                bool
+	}{
+		{"Pairs", "DOGEUSDT", "DOGE", "USDT", false},
+		{"Pairs", "dogeusdt", "DOGE", "USDT", false},
+		{"Pairs", "BTCUSD", "BTC", "USD", false},
+		{"Missing", "DOGE_USDT", "DOGE", "USDT", false},
+		{"Missing", "DOGE-USDT", "DOGE", "USDT", false},
+		{"Missing", "BTCUSD", "BTC", "USD", false},
+		{"Pairs", "BTC", "", "", true},
+		{"Pairs", "BTC-", "", "", true},
+		{"Pairs", "", "", "", true},
+	}
+	for _, test := range tests {
+		p, err := ParseExchangePair(test.exchange, test.currency)
+		if test.err {
+			if err == nil {
+				t.Errorf("Test failed. Expected %q to be invalid, got %v", test.currency, p)
+			}
+			continue
+		}
+		if err != nil || p.FirstCurrency.String() != test.first ||
+			p.SecondCurrency.String() != test.second {
+			t.Errorf("Test failed. %q parsed as %s %s: %v", test.currency,
+				p.FirstCurrency, p.SecondCurrency, err)
+		}
+	}
+}
+
 func TestGetCollatedExchangeAccountInfoByCoin(t *testing.T) {
 	SetupTestHelpers(t)
 
@@ -310,7 +623,7 @@ func TestGetCollatedExchangeAccountInfoByCoin(t *testing.T) {
 
 	exchangeInfo = append(exchangeInfo, info)
 
-	result := GetCollatedExchangeAccountInfoByCoin(exchangeInfo)
+	result, _ := GetCollatedExchangeAccountInfoByCoin(exchangeInfo)
 	if len(result) == 0 {
 		t.Fatal("Unexpected result")
 	}
@@ -330,6 +643,229 @@ func TestGetCollatedExchangeAccountInfoByCoin(t *testing.T) {
 	}
 }
 
+func TestGetCollatedExchangeAccountInfoByCoinNormalisation(t *testing.T) {
+	exchangeInfo := []exchange.AccountInfo{
+		{
+			Exchange: "Bitfinex",
+			Accounts: []exchange.Account{
+				{Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "btc", TotalValue: 1, Hold: 0.5},
+					{CurrencyName: "usdt", TotalValue: 10},
+				}},
+			},
+		},
+		{
+			Exchange: "Bitmex",
+			Accounts: []exchange.Account{
+				{Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "XBT", TotalValue: 2, Hold: 1},
+				}},
+			},
+		},
+		{
+			Exchange: "Kraken",
+			Accounts: []exchange.Account{
+				{Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "BTC", TotalValue: 3},
+					{CurrencyName: "USD", TotalValue: 20},
+				}},
+			},
+		},
+	}
+
+	result, normalised := GetCollatedExchangeAccountInfoByCoin(exchangeInfo)
+	if len(result) != 3 {
+		t.Fatalf("Unexpected result length %d", len(result))
+	}
+
+	btc, ok := result["BTC"]
+	if !ok {
+		t.Fatal("Expected currency was not found in result map")
+	}
+	if btc.TotalValue != 6 || btc.Hold != 1.5 {
+		t.Fatalf("Unexpected BTC totals %f %f", btc.TotalValue, btc.Hold)
+	}
+
+	if result["USDT"].TotalValue != 10 || result["USD"].TotalValue != 20 {
+		t.Fatal("USD and USDT balances should not be merged")
+	}
+
+	if normalised["XBT"] != "BTC" || normalised["btc"] != "BTC" {
+		t.Fatalf("Unexpected normalisation result %v", normalised)
+	}
+
+	if _, ok = normalised["BTC"]; ok {
+		t.Fatal("Already canonical currencies should not be reported")
+	}
+}
+
+func TestGetCollatedExchangeAccountInfoByCoinOptionalValues(t *testing.T) {
+	value := func(f float64) *float64 { return &f }
+	exchangeInfo := []exchange.AccountInfo{
+		{
+			Exchange: "Bitstamp",
+			Accounts: []exchange.Account{
+				{Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "BTC", TotalValue: 3, Hold: 1},
+					{CurrencyName: "LTC", TotalValue: 5},
+				}},
+			},
+		},
+		{
+			Exchange: "Bitmex",
+			Accounts: []exchange.Account{
+				{Type: exchange.AccountTypeMargin, Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "BTC", TotalValue: 2, AvailableValue: value(0),
+						MarginBalance: value(2.5), UnrealisedPNL: value(0.5)},
+				}},
+			},
+		},
+		{
+			Exchange: "OKEX",
+			Accounts: []exchange.Account{
+				{Type: exchange.AccountTypeSpot, Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "btc", TotalValue: 1, Hold: 0.5, AvailableValue: value(0.5)},
+				}},
+				{Type: exchange.AccountTypeFutures, Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "btc", TotalValue: 4, Hold: 1, AvailableValue: value(3),
+						MarginBalance: value(4), UnrealisedPNL: value(-0.25)},
+				}},
+			},
+		},
+	}
+
+	result, _ := GetCollatedExchangeAccountInfoByCoin(exchangeInfo)
+	btc := result["BTC"]
+	if btc.TotalValue != 10 || btc.Hold != 2.5 {
+		t.Fatalf("Test failed. Unexpected BTC totals %f %f", btc.TotalValue, btc.Hold)
+	}
+	// Bitstamp doesn't report an available value, its total less hold is used
+	if btc.AvailableValue == nil || *btc.AvailableValue != 5.5 {
+		t.Errorf("Test failed. Expected an available BTC value of 5.5, got %v",
+			btc.AvailableValue)
+	}
+	if btc.MarginBalance == nil || *btc.MarginBalance != 6.5 ||
+		btc.UnrealisedPNL == nil || *btc.UnrealisedPNL != 0.25 {
+		t.Errorf("Test failed. Unexpected BTC margin values %+v", btc)
+	}
+	if btc.Available() != 5.5 {
+		t.Errorf("Test failed. Expected 5.5 BTC available, got %f", btc.Available())
+	}
+
+	ltc := result["LTC"]
+	if ltc.AvailableValue != nil || ltc.MarginBalance != nil || ltc.UnrealisedPNL != nil {
+		t.Errorf("Test failed. Expected no optional LTC values, got %+v", ltc)
+	}
+	if ltc.Available() != 5 {
+		t.Errorf("Test failed. Expected 5 LTC available, got %f", ltc.Available())
+	}
+}
+
+func TestSeedExchangeAccountInfoNormalisation(t *testing.T) {
+	port := portfolio.GetPortfolio()
+	normalised := SeedExchangeAccountInfo([]exchange.AccountInfo{
+		{
+			Exchange: "NormalisationTest",
+			Accounts: []exchange.Account{
+				{Currencies: []exchange.AccountCurrencyInfo{
+					{CurrencyName: "xbt", TotalValue: 1},
+					{CurrencyName: "BTC", TotalValue: 2},
+				}},
+			},
+		},
+	})
+
+	if normalised["xbt"] != "BTC" {
+		t.Fatalf("Unexpected normalisation result %v", normalised)
+	}
+
+	var entries int
+	for x := range port.Addresses {
+		if port.Addresses[x].Address == "NormalisationTest" {
+			entries++
+			if port.Addresses[x].CoinType != "BTC" || port.Addresses[x].Balance != 3 {
+				t.Fatalf("Unexpected portfolio entry %v", port.Addresses[x])
+			}
+		}
+	}
+
+	if entries != 1 {
+		t.Fatalf("Expected a single portfolio entry, got %d", entries)
+	}
+	port.RemoveExchangeAddress("NormalisationTest", "BTC")
+}
+
+func TestExchangeAccountName(t *testing.T) {
+	name := FormatExchangeAccountName("OKEX", "hedge")
+	if name != "OKEX:hedge" {
+		t.Fatalf("Unexpected result %s", name)
+	}
+
+	if FormatExchangeAccountName("OKEX", "") != "OKEX" {
+		t.Fatal("Unexpected result")
+	}
+
+	exchName, account := SplitExchangeAccountName(name)
+	if exchName != "OKEX" || account != "hedge" {
+		t.Fatalf("Unexpected result %s %s", exchName, account)
+	}
+
+	exchName, account = SplitExchangeAccountName("Bitmex")
+	if exchName != "Bitmex" || account != "" {
+		t.Fatalf("Unexpected result %s %s", exchName, account)
+	}
+}
+
+// newAddressExchange returns a test exchange returning deposit addresses
+// once release is closed
+func newAddressExchange(name string, release chan struct{}) *testExchange {
+	exch := newTestExchange(name)
+	exch.getDepositAddress = func(c pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+		<-release
+		return "addr" + c.String(), nil
+	}
+	return exch
+}
+
+func TestWarmDepositAddressCache(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	fastRelease, slowRelease := make(chan struct{}), make(chan struct{})
+	fast := newAddressExchange("Fast", fastRelease)
+	slow := newAddressExchange("Slow", slowRelease)
+	close(fastRelease)
+	bot.exchanges = []exchange.IBotExchange{slow, fast}
+
+	done := warmDepositAddressCache()
+
+	deadline := time.Now().Add(time.Second * 5)
+	for fast.callCount("GetDepositAddress") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Test failed. Deposit addresses blocked by a slow exchange")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("Test failed. Cache warming finished before the slow exchange")
+	default:
+	}
+
+	close(slowRelease)
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. Cache warming did not finish")
+	}
+	if n := slow.callCount("GetDepositAddress"); n != 1 {
+		t.Errorf("Test failed. Expected 1 slow deposit address, got %d", n)
+	}
+}
+
 func TestGetAccountCurrencyInfoByExchangeName(t *testing.T) {
 	SetupTestHelpers(t)
 
@@ -405,3 +941,240 @@ func TestGetExchangeLowestPriceByCurrencyPair(t *testing.T) {
 		t.Error("Unexpected reuslt")
 	}
 }
+
+func TestGetExchangeStats(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	bot.exchanges = []exchange.IBotExchange{newTestExchange("Bitfinex")}
+
+	p := pair.NewCurrencyPair("LTC", "EUR")
+	_, err := GetExchangeStats(p, ticker.Spot, false)
+	if err != ErrNoStats {
+		t.Errorf("Unexpected result %v", err)
+	}
+
+	_, err = GetExchangeStats(p, "quarter", false)
+	if !errors.Is(err, assets.ErrInvalidAssetType) {
+		t.Errorf("Expected invalid asset type error for an unsupported asset type, got %v", err)
+	}
+
+	stats.Add("Bitfinex", p, ticker.Spot, 50, 200)
+	stats.Add("Bitstamp", p, ticker.Spot, 55, 100)
+	stats.Add("Kraken", p, ticker.Spot, 52, 300)
+
+	result, err := GetExchangeStats(p, ticker.Spot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Exchanges) != 3 || result.Exchanges[0].Exchange != "Bitstamp" ||
+		result.Exchanges[2].Exchange != "Bitfinex" {
+		t.Error("Unexpected price ranking")
+	}
+
+	if result.HighestPriceExchange != "Bitstamp" || result.LowestPriceExchange != "Bitfinex" {
+		t.Error("Unexpected highest/lowest exchange")
+	}
+
+	if result.Exchanges[0].LastUpdated.IsZero() {
+		t.Error("Sample timestamp not set")
+	}
+
+	result, err = GetExchangeStats(p, ticker.Spot, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Exchanges[0].Exchange != "Kraken" || result.Exchanges[2].Exchange != "Bitstamp" {
+		t.Error("Unexpected volume ranking")
+	}
+}
+
+func TestConvertTickerToDisplayCurrency(t *testing.T) {
+	SetupTestHelpers(t)
+
+	oldFiat := currency.FiatCurrencies
+	currency.FiatCurrencies = []string{"USD"}
+	defer func() { currency.FiatCurrencies = oldFiat }()
+
+	tick := ticker.Price{
+		Pair: pair.NewCurrencyPair("BTC", "USD"),
+		Last: 1000,
+		Bid:  999,
+		Ask:  1001,
+	}
+
+	result := ConvertTickerToDisplayCurrency(tick)
+	if result.Last != 1000 || result.Bid != 999 || result.Ask != 1001 {
+		t.Fatal("Test failed. TestConvertTickerToDisplayCurrency: Original values modified")
+	}
+
+	if result.Conversion.DisplayCurrency != "USD" || result.Conversion.Rate != 1 ||
+		result.Conversion.Last != 1000 || result.Conversion.Bid != 999 ||
+		result.Conversion.Ask != 1001 {
+		t.Fatalf("Test failed. TestConvertTickerToDisplayCurrency: Unexpected conversion %v",
+			result.Conversion)
+	}
+
+	tick.Pair = pair.NewCurrencyPair("ETH", "BTC")
+	result = ConvertTickerToDisplayCurrency(tick)
+	if result.Conversion != (TickerConversion{}) {
+		t.Fatal("Test failed. TestConvertTickerToDisplayCurrency: Unexpected conversion for non-fiat quote")
+	}
+	if result.Last != 1000 {
+		t.Fatal("Test failed. TestConvertTickerToDisplayCurrency: Original values modified")
+	}
+
+	data := ConvertTickersToDisplayCurrency([]EnabledExchangeCurrencies{
+		{ExchangeName: "Bitfinex", ExchangeValues: []ticker.Price{tick, tick}},
+	})
+	if len(data) != 1 || len(data[0].ExchangeValues) != 2 {
+		t.Fatal("Test failed. TestConvertTickerToDisplayCurrency: Unexpected result length")
+	}
+}
+
+func TestGetDaemonInfo(t *testing.T) {
+	SetupTestHelpers(t)
+
+	bot.startTime = time.Now().Add(-time.Minute)
+	info := GetDaemonInfo()
+	if !common.StringContains(info.Version, "v"+MajorVersion+"."+MinorVersion) {
+		t.Errorf("Unexpected version %s", info.Version)
+	}
+
+	if info.Uptime < time.Minute {
+		t.Errorf("Unexpected uptime %v", info.Uptime)
+	}
+
+	if info.EnabledExchanges != bot.config.CountEnabledExchanges() {
+		t.Error("Unexpected enabled exchanges count")
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	exch := newOrderExchange(nil)
+	valid := exchange.OrderSubmission{
+		Pair:      pair.NewCurrencyPair("XBT", "USD"),
+		OrderSide: exchange.Buy,
+		OrderType: exchange.Limit,
+		Amount:    2,
+		Price:     6500,
+	}
+	order := valid
+	err := ValidateOrder(exch, &order)
+	if err != nil {
+		t.Fatalf("Test failed. ValidateOrder rejected a valid order: %s", err)
+	}
+	if order != valid {
+		t.Errorf("Test failed. ValidateOrder modified the order %+v", order)
+	}
+
+	order.OrderType = exchange.Market
+	order.Price = 0
+	err = ValidateOrder(exch, &order)
+	if err != nil {
+		t.Errorf("Test failed. ValidateOrder rejected a market order: %s", err)
+	}
+
+	for violation, modify := range map[string]func(o *exchange.OrderSubmission){
+		"order side not set": func(o *exchange.OrderSubmission) { o.OrderSide = "" },
+		"order type not set": func(o *exchange.OrderSubmission) { o.OrderType = "" },
+		"amount 0 must be greater than zero": func(o *exchange.OrderSubmission) {
+			o.Amount = 0
+		},
+		"amount -1 must be greater than zero": func(o *exchange.OrderSubmission) {
+			o.Amount = -1
+		},
+		"limit price -5 must be greater than zero": func(o *exchange.OrderSubmission) {
+			o.Price = -5
+		},
+		"price -5 must not be negative": func(o *exchange.OrderSubmission) {
+			o.OrderType = exchange.Market
+			o.Price = -5
+		},
+		"trigger price 0 must be greater than zero": func(o *exchange.OrderSubmission) {
+			o.OrderType = exchange.StopLimit
+		},
+		"trailing offset 0 must be greater than zero": func(o *exchange.OrderSubmission) {
+			o.OrderType = exchange.TrailingStop
+		},
+		"asset type quarter not supported": func(o *exchange.OrderSubmission) {
+			o.AssetType = "quarter"
+		},
+		"currency pair not set": func(o *exchange.OrderSubmission) {
+			o.Pair = pair.CurrencyPair{}
+		},
+		"currency pair ETHUSD not enabled": func(o *exchange.OrderSubmission) {
+			o.Pair = pair.NewCurrencyPair("ETH", "USD")
+		},
+		// The reciprocal of an enabled pair is a different market
+		"currency pair USDXBT not enabled": func(o *exchange.OrderSubmission) {
+			o.Pair = pair.NewCurrencyPair("USD", "XBT")
+		},
+		"amount 0.5 below minimum 1": func(o *exchange.OrderSubmission) {
+			o.Amount = 0.5
+		},
+	} {
+		order = valid
+		modify(&order)
+		err = ValidateOrder(exch, &order)
+		var verr *OrderValidationError
+		if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidOrder) {
+			t.Errorf("Test failed. Expected a validation error for %q, got %v",
+				violation, err)
+			continue
+		}
+		if len(verr.Violations) != 1 || verr.Violations[0] != violation {
+			t.Errorf("Test failed. Expected violation %q, got %v", violation,
+				verr.Violations)
+		}
+	}
+
+	// Every violated rule is reported
+	err = ValidateOrder(exch, &exchange.OrderSubmission{
+		Pair:      pair.NewCurrencyPair("ETH", "USD"),
+		OrderType: exchange.Limit,
+	})
+	var verr *OrderValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 4 {
+		t.Errorf("Test failed. Expected 4 violations, got %v", err)
+	}
+}
+
+func TestFormatPairForDisplay(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(format config.CurrencyPairFormatConfig) {
+		*bot.config.Currency.CurrencyPairFormat = format
+	}(*bot.config.Currency.CurrencyPairFormat)
+	bot.config.Currency.CurrencyPairFormat.Delimiter = "-"
+	bot.config.Currency.CurrencyPairFormat.Uppercase = true
+
+	p := pair.NewCurrencyPairDelimiter("doge_usdt", "_")
+	tests := []struct {
+		format   PairDisplayFormat
+		expected string
+	}{
+		{PairDisplayFormat{}, "DOGE-USDT"},
+		{PairDisplayFormat{Case: "LOWER"}, "doge-usdt"},
+		{PairDisplayFormat{Delimiter: "/"}, "DOGE/USDT"},
+		{PairDisplayFormat{Delimiter: "/", Case: PairCaseLower}, "doge/usdt"},
+	}
+	for x := range tests {
+		if result := FormatPairForDisplay(p, tests[x].format); result != tests[x].expected {
+			t.Errorf("Test failed. %+v expected %s, got %s", tests[x].format,
+				tests[x].expected, result)
+		}
+	}
+
+	bot.config.Currency.CurrencyPairFormat.Delimiter = ""
+	bot.config.Currency.CurrencyPairFormat.Uppercase = false
+	if result := FormatPairForDisplay(p, PairDisplayFormat{}); result != "dogeusdt" {
+		t.Errorf("Test failed. Expected the config format dogeusdt, got %s", result)
+	}
+
+	if err := (PairDisplayFormat{Case: "title"}).Validate(); err == nil {
+		t.Error("Test failed. Expected an invalid pair case error")
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// defaultHookQueueSize is the number of updates buffered per hook before the
+// oldest pending update is dropped
+const defaultHookQueueSize = 100
+
+// ErrHookNotFound is returned when a hook ID is not registered
+var ErrHookNotFound = errors.New("hook not found")
+
+// TickerHook is called with every successfully processed ticker update
+type TickerHook func(exchangeName, assetType string, p pair.CurrencyPair, price ticker.Price)
+
+// OrderbookHook is called with every successfully processed orderbook update
+type OrderbookHook func(exchangeName, assetType string, p pair.CurrencyPair, ob orderbook.Base)
+
+// TradeHook is called with every trade streamed by an exchange websocket
+type TradeHook func(exchangeName, assetType string, p pair.CurrencyPair, trade trades.Trade)
+
+// hookUpdate holds a single market data update queued for a hook
+type hookUpdate struct {
+	exchangeName string
+	assetType    string
+	pair         pair.CurrencyPair
+	ticker       ticker.Price
+	orderbook    orderbook.Base
+	trade        trades.Trade
+}
+
+// hook is a registered consumer with its own bounded update queue, delivered
+// in order by a dedicated routine so a slow consumer never blocks the
+// updater routines
+type hook struct {
+	dropped uint64
+	id      int
+	queue   chan hookUpdate
+	quit    chan struct{}
+	deliver func(hookUpdate)
+	m       sync.Mutex
+}
+
+// hookManager stores the registered ticker, orderbook and trade hooks
+type hookManager struct {
+	queueSize  int
+	nextID     int
+	tickers    map[int]*hook
+	orderbooks map[int]*hook
+	trades     map[int]*hook
+	m          sync.RWMutex
+}
+
+var hooks = newHookManager(defaultHookQueueSize)
+
+func newHookManager(queueSize int) *hookManager {
+	return &hookManager{
+		queueSize:  queueSize,
+		tickers:    make(map[int]*hook),
+		orderbooks: make(map[int]*hook),
+		trades:     make(map[int]*hook),
+	}
+}
+
+// enqueue adds an update to the hook queue, dropping the oldest pending update
+// if the queue is full
+func (h *hook) enqueue(u hookUpdate) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	for {
+		select {
+		case h.queue <- u:
+			return
+		default:
+		}
+
+		select {
+		case <-h.queue:
+			atomic.AddUint64(&h.dropped, 1)
+		default:
+		}
+	}
+}
+
+// run delivers queued updates until the hook is removed
+func (h *hook) run() {
+	for {
+		select {
+		case <-h.quit:
+			return
+		case u := <-h.queue:
+			h.call(u)
+		}
+	}
+}
+
+// call delivers an update, recovering from a panicking consumer so the hook
+// keeps receiving subsequent updates
+func (h *hook) call(u hookUpdate) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Hook %d panicked processing %s %s %s update: %v",
+				h.id, u.exchangeName, u.pair.Pair().String(), u.assetType, r)
+		}
+	}()
+	h.deliver(u)
+}
+
+func (hm *hookManager) register(store map[int]*hook, deliver func(hookUpdate)) int {
+	hm.m.Lock()
+	defer hm.m.Unlock()
+	hm.nextID++
+	h := &hook{
+		id:      hm.nextID,
+		queue:   make(chan hookUpdate, hm.queueSize),
+		quit:    make(chan struct{}),
+		deliver: deliver,
+	}
+	store[h.id] = h
+	go h.run()
+	return h.id
+}
+
+// registerTicker registers a ticker hook and returns its ID
+func (hm *hookManager) registerTicker(fn TickerHook) int {
+	return hm.register(hm.tickers, func(u hookUpdate) {
+		fn(u.exchangeName, u.assetType, u.pair, u.ticker)
+	})
+}
+
+// registerOrderbook registers an orderbook hook and returns its ID
+func (hm *hookManager) registerOrderbook(fn OrderbookHook) int {
+	return hm.register(hm.orderbooks, func(u hookUpdate) {
+		fn(u.exchangeName, u.assetType, u.pair, u.orderbook)
+	})
+}
+
+// registerTrade registers a trade hook and returns its ID
+func (hm *hookManager) registerTrade(fn TradeHook) int {
+	return hm.register(hm.trades, func(u hookUpdate) {
+		fn(u.exchangeName, u.assetType, u.pair, u.trade)
+	})
+}
+
+// remove stops and removes a hook, discarding any pending updates
+func (hm *hookManager) remove(id int) error {
+	hm.m.Lock()
+	defer hm.m.Unlock()
+	for _, store := range []map[int]*hook{hm.tickers, hm.orderbooks, hm.trades} {
+		if h, ok := store[id]; ok {
+			close(h.quit)
+			delete(store, id)
+			return nil
+		}
+	}
+	return ErrHookNotFound
+}
+
+// dropCount returns the number of updates dropped for a hook
+func (hm *hookManager) dropCount(id int) (uint64, error) {
+	hm.m.RLock()
+	defer hm.m.RUnlock()
+	for _, store := range []map[int]*hook{hm.tickers, hm.orderbooks, hm.trades} {
+		if h, ok := store[id]; ok {
+			return atomic.LoadUint64(&h.dropped), nil
+		}
+	}
+	return 0, ErrHookNotFound
+}
+
+func (hm *hookManager) dispatch(store map[int]*hook, u hookUpdate) {
+	hm.m.RLock()
+	defer hm.m.RUnlock()
+	for _, h := range store {
+		h.enqueue(u)
+	}
+}
+
+// deliverNow delivers an update to every hook of the store in registration
+// order on the calling routine, bypassing the queues so updates are consumed
+// deterministically
+func (hm *hookManager) deliverNow(store map[int]*hook, u hookUpdate) {
+	hm.m.RLock()
+	registered := make([]*hook, 0, len(store))
+	for _, h := range store {
+		registered = append(registered, h)
+	}
+	hm.m.RUnlock()
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].id < registered[j].id
+	})
+	for x := range registered {
+		registered[x].call(u)
+	}
+}
+
+// deliverTickerNow delivers a ticker update to all registered ticker hooks
+// before returning
+func (hm *hookManager) deliverTickerNow(exchangeName, assetType string, p pair.CurrencyPair, price ticker.Price) {
+	hm.deliverNow(hm.tickers, hookUpdate{
+		exchangeName: exchangeName,
+		assetType:    assetType,
+		pair:         p,
+		ticker:       price,
+	})
+}
+
+// deliverOrderbookNow delivers an orderbook update to all registered orderbook
+// hooks before returning
+func (hm *hookManager) deliverOrderbookNow(exchangeName, assetType string, p pair.CurrencyPair, ob orderbook.Base) {
+	hm.deliverNow(hm.orderbooks, hookUpdate{
+		exchangeName: exchangeName,
+		assetType:    assetType,
+		pair:         p,
+		orderbook:    ob,
+	})
+}
+
+// dispatchTicker queues a ticker update for all registered ticker hooks
+func (hm *hookManager) dispatchTicker(exchangeName, assetType string, p pair.CurrencyPair, price ticker.Price) {
+	hm.dispatch(hm.tickers, hookUpdate{
+		exchangeName: exchangeName,
+		assetType:    assetType,
+		pair:         p,
+		ticker:       price,
+	})
+}
+
+// dispatchOrderbook queues an orderbook update for all registered orderbook
+// hooks
+func (hm *hookManager) dispatchOrderbook(exchangeName, assetType string, p pair.CurrencyPair, ob orderbook.Base) {
+	hm.dispatch(hm.orderbooks, hookUpdate{
+		exchangeName: exchangeName,
+		assetType:    assetType,
+		pair:         p,
+		orderbook:    ob,
+	})
+}
+
+// dispatchTrade queues a trade for all registered trade hooks
+func (hm *hookManager) dispatchTrade(exchangeName, assetType string, p pair.CurrencyPair, trade trades.Trade) {
+	hm.dispatch(hm.trades, hookUpdate{
+		exchangeName: exchangeName,
+		assetType:    assetType,
+		pair:         p,
+		trade:        trade,
+	})
+}
+
+// RegisterTickerHook registers a function to be called with every processed
+// ticker update and returns the hook ID used to remove it
+func RegisterTickerHook(fn TickerHook) int {
+	return hooks.registerTicker(fn)
+}
+
+// RegisterOrderbookHook registers a function to be called with every
+// processed orderbook update and returns the hook ID used to remove it
+func RegisterOrderbookHook(fn OrderbookHook) int {
+	return hooks.registerOrderbook(fn)
+}
+
+// RegisterTradeHook registers a function to be called with every trade
+// streamed by an exchange websocket and returns the hook ID used to remove it
+func RegisterTradeHook(fn TradeHook) int {
+	return hooks.registerTrade(fn)
+}
+
+// RemoveHook removes a ticker, orderbook or trade hook by its ID
+func RemoveHook(id int) error {
+	return hooks.remove(id)
+}
+
+// GetHookDropCount returns the number of updates dropped for a hook because
+// its queue was full
+func GetHookDropCount(id int) (uint64, error) {
+	return hooks.dropCount(id)
+}
+
+// registerDefaultHooks registers the communications and events packages as
+// market data consumers when their subsystems are running
+func registerDefaultHooks() {
+	if isSubsystemRunning(SubsystemCommunications) {
+		RegisterTickerHook(func(exchangeName, assetType string, _ pair.CurrencyPair, price ticker.Price) {
+			bot.comms.StageTickerData(exchangeName, assetType, price)
+		})
+		RegisterOrderbookHook(func(exchangeName, assetType string, _ pair.CurrencyPair, ob orderbook.Base) {
+			bot.comms.StageOrderbookData(exchangeName, assetType, ob)
+		})
+	}
+	if isSubsystemRunning(SubsystemEvents) {
+		RegisterTickerHook(events.ProcessTicker)
+		RegisterOrderbookHook(events.ProcessOrderbook)
+		RegisterTradeHook(events.ProcessTrade)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+func waitForUpdates(t *testing.T, c chan float64, n int) []float64 {
+	var result []float64
+	timer := time.NewTimer(time.Second * 5)
+	defer timer.Stop()
+	for len(result) < n {
+		select {
+		case v := <-c:
+			result = append(result, v)
+		case <-timer.C:
+			t.Fatalf("Test failed. Received %d of %d hook updates", len(result), n)
+		}
+	}
+	return result
+}
+
+func TestHookRegistration(t *testing.T) {
+	hm := newHookManager(10)
+	p := pair.NewCurrencyPair("BTC", "USD")
+
+	tickers := make(chan float64, 10)
+	tickerID := hm.registerTicker(func(exchangeName, assetType string, c pair.CurrencyPair, price ticker.Price) {
+		if exchangeName != "Bitfinex" || assetType != ticker.Spot || !c.Equal(p, true) {
+			t.Errorf("Test failed. Unexpected ticker hook args %s %s %s",
+				exchangeName, assetType, c.Pair())
+		}
+		tickers <- price.Last
+	})
+
+	orderbooks := make(chan float64, 10)
+	orderbookID := hm.registerOrderbook(func(_, _ string, _ pair.CurrencyPair, ob orderbook.Base) {
+		orderbooks <- ob.Bids[0].Price
+	})
+
+	if tickerID == orderbookID {
+		t.Fatal("Test failed. Hook IDs should be unique")
+	}
+
+	hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{Last: 1000})
+	hm.dispatchOrderbook("Bitfinex", ticker.Spot, p, orderbook.Base{
+		Bids: []orderbook.Item{{Price: 999, Amount: 1}},
+	})
+
+	if r := waitForUpdates(t, tickers, 1); r[0] != 1000 {
+		t.Fatalf("Test failed. Expected ticker last 1000, got %f", r[0])
+	}
+	if r := waitForUpdates(t, orderbooks, 1); r[0] != 999 {
+		t.Fatalf("Test failed. Expected orderbook bid 999, got %f", r[0])
+	}
+
+	err := hm.remove(tickerID)
+	if err != nil {
+		t.Fatalf("Test failed. Unable to remove hook: %s", err)
+	}
+
+	err = hm.remove(tickerID)
+	if err != ErrHookNotFound {
+		t.Fatalf("Test failed. Expected %s, got %v", ErrHookNotFound, err)
+	}
+
+	_, err = hm.dropCount(tickerID)
+	if err != ErrHookNotFound {
+		t.Fatalf("Test failed. Expected %s, got %v", ErrHookNotFound, err)
+	}
+
+	hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{Last: 1001})
+	select {
+	case <-tickers:
+		t.Fatal("Test failed. Removed hook received an update")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestHookDeliveryOrdering(t *testing.T) {
+	hm := newHookManager(100)
+	p := pair.NewCurrencyPair("BTC", "USD")
+
+	received := make(chan float64, 100)
+	panicked := false
+	hm.registerTicker(func(_, _ string, _ pair.CurrencyPair, price ticker.Price) {
+		if price.Last == 5 && !panicked {
+			panicked = true
+			panic("consumer failure")
+		}
+		received <- price.Last
+	})
+
+	for x := 1; x <= 50; x++ {
+		hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{Last: float64(x)})
+	}
+
+	result := waitForUpdates(t, received, 49)
+	expected := 1.0
+	for x := range result {
+		if expected == 5 {
+			expected++
+		}
+		if result[x] != expected {
+			t.Fatalf("Test failed. Expected update %f, got %f", expected, result[x])
+		}
+		expected++
+	}
+}
+
+func TestHookBackpressure(t *testing.T) {
+	const queueSize = 10
+	hm := newHookManager(queueSize)
+	p := pair.NewCurrencyPair("BTC", "USD")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	received := make(chan float64, queueSize*2)
+	id := hm.registerTicker(func(_, _ string, _ pair.CurrencyPair, price ticker.Price) {
+		if price.Last == 0 {
+			close(started)
+			<-release
+			return
+		}
+		received <- price.Last
+	})
+
+	hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{})
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		for x := 1; x <= queueSize+5; x++ {
+			hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{Last: float64(x)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. Dispatch blocked on a slow hook")
+	}
+
+	dropped, err := hm.dropCount(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 5 {
+		t.Fatalf("Test failed. Expected 5 dropped updates, got %d", dropped)
+	}
+
+	close(release)
+	result := waitForUpdates(t, received, queueSize)
+	for x := range result {
+		if result[x] != float64(x+6) {
+			t.Fatalf("Test failed. Expected oldest updates to be dropped, got %v",
+				result)
+		}
+	}
+}
+
+func TestEventsTickerHook(t *testing.T) {
+	hm := newHookManager(10)
+	p := pair.NewCurrencyPair("BTC", "USD")
+	defer func(e []*events.Event) { events.Events = e }(events.Events)
+	events.Events = []*events.Event{
+		{ID: 1, Exchange: "Bitfinex", Item: "PRICE", Condition: ">,100", Pair: p,
+			Asset: ticker.Spot, Action: "ACTION_TEST"},
+	}
+	events.SetMinEvaluationInterval(time.Millisecond * 10)
+	defer events.SetMinEvaluationInterval(0)
+	hm.registerTicker(events.ProcessTicker)
+
+	hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{Last: 90, LastUpdated: time.Now()})
+	time.Sleep(time.Millisecond * 20)
+	sent := time.Now()
+	hm.dispatchTicker("Bitfinex", ticker.Spot, p, ticker.Price{Last: 110, LastUpdated: sent})
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		e := events.GetEvents()[0]
+		if e.Executed {
+			if !e.LastData.Equal(sent) || e.LastEvaluated.Before(sent) {
+				t.Errorf("Test failed. Unexpected evaluation times %+v", e)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Test failed. Event not triggered by the hook delivered ticker")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
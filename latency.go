@@ -0,0 +1,110 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+// latencyStreak counts the consecutive completed latency windows of an
+// exchange endpoint class with a p95 latency above the warning threshold
+type latencyStreak struct {
+	lastWindow  time.Time
+	consecutive int
+}
+
+var (
+	latencyStreaks   = make(map[string]latencyStreak)
+	latencyStreakMtx sync.Mutex
+)
+
+// GetExchangeLatency returns the REST request latency statistics of an
+// exchange by endpoint class
+func GetExchangeLatency(name string) (request.LatencyStats, error) {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return exch.GetLatencyStats(), nil
+}
+
+// exchangeLatency returns the request latency statistics of a loaded
+// exchange, nil if it isn't loaded or hasn't sent any requests
+func exchangeLatency(name string) request.LatencyStats {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return nil
+	}
+	stats := exch.GetLatencyStats()
+	if len(stats) == 0 {
+		return nil
+	}
+	return stats
+}
+
+// checkExchangeLatency evaluates the latency windows of an exchange completed
+// since the last check, logging a warning once the p95 latency of an endpoint
+// class exceeds the threshold for the number of consecutive windows. It
+// returns the endpoint classes warned about
+func checkExchangeLatency(exch exchange.IBotExchange, threshold time.Duration, windows int) []string {
+	if threshold <= 0 || windows <= 0 {
+		return nil
+	}
+
+	exchName := exch.GetName()
+	stats := exch.GetLatencyStats()
+	classes := make([]string, 0, len(stats))
+	for class := range stats {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	latencyStreakMtx.Lock()
+	defer latencyStreakMtx.Unlock()
+	var warned []string
+	for _, class := range classes {
+		key := exchName + " " + class
+		streak := latencyStreaks[key]
+		completed := stats[class].Windows
+		for x := range completed {
+			if !completed[x].End.After(streak.lastWindow) {
+				continue
+			}
+			streak.lastWindow = completed[x].End
+			if completed[x].P95 <= threshold {
+				if streak.consecutive >= windows {
+					routinesLog.With("exchange", exchName).Infof(
+						"%s request p95 latency %v back under the %v threshold.",
+						class, completed[x].P95, threshold)
+				}
+				streak.consecutive = 0
+				continue
+			}
+			streak.consecutive++
+			if streak.consecutive == windows {
+				routinesLog.With("exchange", exchName).Warnf(
+					"%s request p95 latency %v exceeded the %v threshold for %d consecutive %v windows.",
+					class, completed[x].P95, threshold, windows,
+					completed[x].End.Sub(completed[x].Start))
+				warned = append(warned, class)
+			}
+		}
+		latencyStreaks[key] = streak
+	}
+	return warned
+}
+
+// checkExchangeLatencies checks the request latency of every loaded exchange
+// against the configured warning threshold
+func checkExchangeLatencies() {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		checkExchangeLatency(bot.exchanges[x], bot.config.LatencyWarningThreshold,
+			bot.config.LatencyWarningWindows)
+	}
+}
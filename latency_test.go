@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+func TestCheckExchangeLatency(t *testing.T) {
+	defer func() {
+		latencyStreakMtx.Lock()
+		latencyStreaks = make(map[string]latencyStreak)
+		latencyStreakMtx.Unlock()
+	}()
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	var windows []request.LatencyWindow
+	exch := newTestExchange("LatencyTest")
+	addWindow := func(p95 time.Duration) {
+		windowStart := start.Add(time.Duration(len(windows)) * time.Minute)
+		windows = append(windows, request.LatencyWindow{Start: windowStart,
+			End: windowStart.Add(time.Minute), Count: 10, P95: p95})
+		stats := request.LatencyStats{request.EndpointPublic: {Windows: windows}}
+		exch.getLatencyStats = func() request.LatencyStats { return stats }
+	}
+
+	for x, test := range []struct {
+		p95    time.Duration
+		warned bool
+	}{
+		{time.Second * 6, false},
+		{time.Second * 7, false},
+		{time.Second, false},
+		{time.Second * 6, false},
+		{time.Second * 6, false},
+		{time.Second * 6, true},
+		{time.Second * 6, false},
+	} {
+		addWindow(test.p95)
+		warned := checkExchangeLatency(exch, time.Second*5, 3)
+		if (len(warned) == 1) != test.warned {
+			t.Errorf("Test failed. Window %d expected warned %v, got %v", x,
+				test.warned, warned)
+		}
+		// Windows already evaluated aren't counted again
+		if len(checkExchangeLatency(exch, time.Second*5, 3)) != 0 {
+			t.Errorf("Test failed. Window %d warned about twice", x)
+		}
+	}
+
+	latencyStreakMtx.Lock()
+	streak := latencyStreaks["LatencyTest "+request.EndpointPublic]
+	latencyStreakMtx.Unlock()
+	if streak.consecutive != 4 || !streak.lastWindow.Equal(windows[len(windows)-1].End) {
+		t.Errorf("Test failed. Unexpected latency streak %+v", streak)
+	}
+
+	if checkExchangeLatency(exch, 0, 3) != nil {
+		t.Error("Test failed. Expected no warnings without a threshold")
+	}
+}
+
+func TestRESTGetExchangeLatency(t *testing.T) {
+	SetupTestHelpers(t)
+	exch := newTestExchange("LatencyTest")
+	exch.getLatencyStats = func() request.LatencyStats {
+		return request.LatencyStats{request.EndpointAuth: {
+			Rolling: request.LatencyWindow{Count: 2, P95: time.Second}}}
+	}
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{exch}
+	defer func() { bot.exchanges = exchanges }()
+
+	router := NewRouter()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exchanges/Blah/latency", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exchanges/LatencyTest/latency", nil))
+	var stats request.LatencyStats
+	err := common.JSONDecode(w.Body.Bytes(), &stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats[request.EndpointAuth].Rolling.P95 != time.Second {
+		t.Errorf("Test failed. Unexpected latency stats %+v", stats)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 func init() {
 	setDefaultOutputs()
+	subsystemOutputs = newSubsystemOutputs(os.Stdout)
 }
 
 // SetupLogger configure logger instance with user provided settings
@@ -29,6 +30,7 @@ func SetupLogger() (err error) {
 	} else {
 		clearAllLoggers()
 	}
+	setupSubsystems()
 	return
 }
 
@@ -1,10 +1,36 @@
 package logger
 
 import (
+	"fmt"
 	"log"
 	"strings"
 )
 
+// level is a set of enabled log levels
+type level uint8
+
+const (
+	debugLevel level = 1 << iota
+	infoLevel
+	warnLevel
+	errorLevel
+	fatalLevel
+
+	allLevels = debugLevel | infoLevel | warnLevel | errorLevel | fatalLevel
+)
+
+// levelNames are the log level names in order of severity
+var levelNames = []struct {
+	name  string
+	level level
+}{
+	{"DEBUG", debugLevel},
+	{"INFO", infoLevel},
+	{"WARN", warnLevel},
+	{"ERROR", errorLevel},
+	{"FATAL", fatalLevel},
+}
+
 func logLevel() {
 	clearAllLoggers()
 	enabledLevels := strings.Split(Logger.Level, "|")
@@ -31,3 +57,37 @@ func logLevel() {
 		}
 	}
 }
+
+// parseLevels parses a "|" separated list of levels. A single level enables
+// that level and every level above it, so "WARN" also enables ERROR and FATAL
+func parseLevels(value string) (level, error) {
+	names := strings.Split(strings.ToUpper(strings.TrimSpace(value)), "|")
+	var result level
+	for x := range names {
+		found := false
+		for y := range levelNames {
+			if strings.TrimSpace(names[x]) != levelNames[y].name {
+				continue
+			}
+			found = true
+			if len(names) == 1 {
+				for z := y; z < len(levelNames); z++ {
+					result |= levelNames[z].level
+				}
+			} else {
+				result |= levelNames[y].level
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid log level %q", names[x])
+		}
+	}
+	return result, nil
+}
+
+// ValidateLevels checks a subsystem level value, either a single level or a
+// "|" separated list of levels
+func ValidateLevels(value string) error {
+	_, err := parseLevels(value)
+	return err
+}
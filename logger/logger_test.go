@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"bytes"
 	"os"
 	"path"
+	"strings"
 	"testing"
 )
 
@@ -63,6 +65,84 @@ func TestSetupOutputsInValidPath(t *testing.T) {
 	}
 }
 
+func TestSubLoggerLevels(t *testing.T) {
+	Logger = &Logging{
+		Enabled: trueptr,
+		Level:   "INFO|WARN|ERROR|FATAL",
+		Subsystems: map[string]string{
+			"exchange.okex":   "DEBUG",
+			"exchange":        "ERROR",
+			"engine.routines": "WARN",
+		},
+	}
+	err := SetupLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	subsystemOutputs = newSubsystemOutputs(&buf)
+	defer func() { subsystemOutputs = newSubsystemOutputs(os.Stdout) }()
+
+	ExchangeLogger("OKEX").With("pair", "BTC USD").Debugf("okex %s", "debug")
+	ExchangeLogger("Bitfinex").Warn("bitfinex warn")
+	ExchangeLogger("Bitfinex").Error("bitfinex error")
+	NewSubLogger("engine.routines").Info("routines info")
+	NewSubLogger("engine.routines").Warnf("routines warn")
+	NewSubLogger("engine.other").Debug("other debug")
+	NewSubLogger("engine.other").Infoln("other info")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		`[exchange.OKEX] okex debug exchange=OKEX pair="BTC USD"`,
+		"[exchange.Bitfinex] bitfinex error exchange=Bitfinex",
+		"[engine.routines] routines warn",
+		"[engine.other] other info",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Test Failed - SubLogger unexpected output:\n%s", buf.String())
+	}
+	for x := range expected {
+		if !strings.HasSuffix(lines[x], expected[x]) {
+			t.Errorf("Test Failed - SubLogger line %d expected %q got %q",
+				x, expected[x], lines[x])
+		}
+	}
+
+	Logger.Enabled = falseptr
+	err = SetupLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	ExchangeLogger("OKEX").Error("disabled")
+	if buf.Len() != 0 {
+		t.Errorf("Test Failed - SubLogger wrote while disabled: %s", buf.String())
+	}
+}
+
+func TestValidateLevels(t *testing.T) {
+	for _, value := range []string{"DEBUG", "warn", "INFO|ERROR"} {
+		err := ValidateLevels(value)
+		if err != nil {
+			t.Errorf("Test Failed - ValidateLevels(%q) error: %s", value, err)
+		}
+	}
+	for _, value := range []string{"", "VERBOSE", "INFO|"} {
+		if ValidateLevels(value) == nil {
+			t.Errorf("Test Failed - ValidateLevels(%q) accepted", value)
+		}
+	}
+
+	l, _ := parseLevels("WARN")
+	if l != warnLevel|errorLevel|fatalLevel {
+		t.Errorf("Test Failed - parseLevels(WARN) unexpected levels %b", l)
+	}
+	l, _ = parseLevels("DEBUG|ERROR")
+	if l != debugLevel|errorLevel {
+		t.Errorf("Test Failed - parseLevels(DEBUG|ERROR) unexpected levels %b", l)
+	}
+}
+
 func BenchmarkDebugf(b *testing.B) {
 	Logger = &Logging{
 		Enabled:      trueptr,
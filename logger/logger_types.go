@@ -14,6 +14,11 @@ type Logging struct {
 	ColourOutputOverride bool   `json:"colourOverride,omitempty"`
 	Level                string `json:"level"`
 	Rotate               bool   `json:"rotate"`
+
+	// Subsystems overrides the level for sub-loggers by subsystem name, for
+	// example "exchange.OKEX" or "engine.routines". A name also applies to
+	// every subsystem below it, so "exchange" covers all exchanges
+	Subsystems map[string]string `json:"subsystems,omitempty"`
 }
 
 var (
@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SubsystemExchange is the subsystem prefix of exchange sub-loggers
+const SubsystemExchange = "exchange"
+
+var (
+	subsystemMtx sync.RWMutex
+	// globalLevels are the levels enabled by the logging level setting
+	globalLevels = allLevels
+	// subsystemLevels are the configured subsystem overrides by lower case
+	// subsystem name
+	subsystemLevels = make(map[string]level)
+	// subsystemResolved caches the levels resolved for each subsystem
+	subsystemResolved = make(map[string]level)
+	// subsystemOutputs write sub-logger lines regardless of the global level,
+	// as subsystems can enable levels it does not
+	subsystemOutputs map[level]*log.Logger
+)
+
+// SubLogger writes log lines tagged with a subsystem name and any fields
+// attached to it, at the levels configured for that subsystem
+type SubLogger struct {
+	name   string
+	fields string
+}
+
+// NewSubLogger returns a sub-logger for the named subsystem, for example
+// "engine.routines"
+func NewSubLogger(name string) *SubLogger {
+	return &SubLogger{name: name}
+}
+
+// ExchangeLogger returns the sub-logger for an exchange, tagged with the
+// exchange field
+func ExchangeLogger(exchName string) *SubLogger {
+	return NewSubLogger(SubsystemExchange+"."+exchName).With("exchange", exchName)
+}
+
+// With returns a copy of the sub-logger which appends key=value to every line
+func (s *SubLogger) With(key string, value interface{}) *SubLogger {
+	v := fmt.Sprint(value)
+	if v == "" || strings.ContainsAny(v, " =\"") {
+		v = strconv.Quote(v)
+	}
+	return &SubLogger{name: s.name, fields: s.fields + " " + key + "=" + v}
+}
+
+// Name returns the sub-logger's subsystem name
+func (s *SubLogger) Name() string {
+	return s.name
+}
+
+// Debug handler takes any input returns unformatted output to the debug writer
+func (s *SubLogger) Debug(v ...interface{}) {
+	s.output(debugLevel, fmt.Sprint(v...))
+}
+
+// Debugf handler takes any input returns formatted output to the debug writer
+func (s *SubLogger) Debugf(data string, v ...interface{}) {
+	s.output(debugLevel, fmt.Sprintf(data, v...))
+}
+
+// Debugln handler takes any input returns formatted output to the debug writer
+func (s *SubLogger) Debugln(v ...interface{}) {
+	s.output(debugLevel, sprintln(v...))
+}
+
+// Info handler takes any input returns unformatted output to the info writer
+func (s *SubLogger) Info(v ...interface{}) {
+	s.output(infoLevel, fmt.Sprint(v...))
+}
+
+// Infof handler takes any input returns formatted output to the info writer
+func (s *SubLogger) Infof(data string, v ...interface{}) {
+	s.output(infoLevel, fmt.Sprintf(data, v...))
+}
+
+// Infoln handler takes any input returns formatted output to the info writer
+func (s *SubLogger) Infoln(v ...interface{}) {
+	s.output(infoLevel, sprintln(v...))
+}
+
+// Warn handler takes any input returns unformatted output to the warn writer
+func (s *SubLogger) Warn(v ...interface{}) {
+	s.output(warnLevel, fmt.Sprint(v...))
+}
+
+// Warnf handler takes any input returns formatted output to the warn writer
+func (s *SubLogger) Warnf(data string, v ...interface{}) {
+	s.output(warnLevel, fmt.Sprintf(data, v...))
+}
+
+// Error handler takes any input returns unformatted output to the error writer
+func (s *SubLogger) Error(v ...interface{}) {
+	s.output(errorLevel, fmt.Sprint(v...))
+}
+
+// Errorf handler takes any input returns formatted output to the error writer
+func (s *SubLogger) Errorf(data string, v ...interface{}) {
+	s.output(errorLevel, fmt.Sprintf(data, v...))
+}
+
+// output writes the line when the level is enabled for the subsystem
+func (s *SubLogger) output(l level, msg string) {
+	out := subsystemOutput(s.name, l)
+	if out == nil {
+		return
+	}
+	out.Output(3, "["+s.name+"] "+strings.TrimRight(msg, "\n")+s.fields)
+}
+
+// sprintln formats like fmt.Sprintln without the trailing newline, so fields
+// stay on the same line
+func sprintln(v ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
+
+// subsystemOutput returns the writer for a level of the subsystem, or nil if
+// the level is not enabled for it
+func subsystemOutput(name string, l level) *log.Logger {
+	subsystemMtx.RLock()
+	levels, ok := subsystemResolved[name]
+	out := subsystemOutputs[l]
+	subsystemMtx.RUnlock()
+	if !ok {
+		levels = resolveSubsystem(name)
+	}
+	if levels&l == 0 {
+		return nil
+	}
+	return out
+}
+
+// resolveSubsystem finds the levels of the closest configured subsystem,
+// walking up the dot separated name before falling back to the global levels
+func resolveSubsystem(name string) level {
+	subsystemMtx.Lock()
+	defer subsystemMtx.Unlock()
+
+	levels := globalLevels
+	for n := strings.ToLower(name); n != ""; {
+		if l, ok := subsystemLevels[n]; ok {
+			levels = l
+			break
+		}
+		i := strings.LastIndex(n, ".")
+		if i < 0 {
+			break
+		}
+		n = n[:i]
+	}
+	subsystemResolved[name] = levels
+	return levels
+}
+
+// newSubsystemOutputs returns the sub-logger writers for each level, using the
+// prefixes of the level loggers
+func newSubsystemOutputs(w io.Writer) map[level]*log.Logger {
+	flags := log.Ldate | log.Ltime
+	return map[level]*log.Logger{
+		debugLevel: log.New(w, debugLogger.Prefix(), flags),
+		infoLevel:  log.New(w, infoLogger.Prefix(), flags),
+		warnLevel:  log.New(w, warnLogger.Prefix(), flags),
+		errorLevel: log.New(w, errorLogger.Prefix(), flags),
+	}
+}
+
+// setupSubsystems applies the global level and subsystem overrides to
+// sub-loggers. Invalid overrides are skipped, CheckLoggerConfig reports them
+func setupSubsystems() {
+	subsystemMtx.Lock()
+	defer subsystemMtx.Unlock()
+
+	globalLevels = 0
+	subsystemLevels = make(map[string]level)
+	subsystemResolved = make(map[string]level)
+	if Logger.Enabled == nil || !*Logger.Enabled {
+		return
+	}
+
+	enabledLevels := strings.Split(Logger.Level, "|")
+	for x := range enabledLevels {
+		for y := range levelNames {
+			if enabledLevels[x] == levelNames[y].name {
+				globalLevels |= levelNames[y].level
+			}
+		}
+	}
+	for name, value := range Logger.Subsystems {
+		l, err := parseLevels(value)
+		if err != nil {
+			continue
+		}
+		subsystemLevels[strings.ToLower(name)] = l
+	}
+	subsystemOutputs = newSubsystemOutputs(logOutput)
+}
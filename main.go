@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/communications"
@@ -16,7 +22,9 @@ import (
 	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/coinmarketcap"
 	"github.com/thrasher-/gocryptotrader/currency/forexprovider"
+	"github.com/thrasher-/gocryptotrader/events"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	log "github.com/thrasher-/gocryptotrader/logger"
 	"github.com/thrasher-/gocryptotrader/portfolio"
 )
@@ -24,16 +32,25 @@ import (
 // Bot contains configuration, portfolio, exchange & ticker data and is the
 // overarching type across this code base.
 type Bot struct {
-	config     *config.Config
-	portfolio  *portfolio.Base
-	exchanges  []exchange.IBotExchange
-	comms      *communications.Communications
-	shutdown   chan bool
-	dryRun     bool
-	configFile string
-	dataDir    string
+	config    *config.Config
+	portfolio *portfolio.Base
+	exchanges []exchange.IBotExchange
+	comms     *communications.Communications
+	shutdown  chan bool
+	settings  Settings
+	startTime time.Time
+	webserver *http.Server
+	stopping  int32
+	// stopped is closed once all routines have stopped after Stop is called
+	stopped chan struct{}
 }
 
+// shutdownTimeout is the maximum time to wait for bot routines to stop
+const shutdownTimeout = time.Second * 10
+
+// errShutdownTimeout is returned when bot routines fail to stop in time
+var errShutdownTimeout = errors.New("timed out waiting for routines to stop")
+
 const banner = `
    ______        ______                     __        ______                  __
   / ____/____   / ____/_____ __  __ ____   / /_ ____ /_  __/_____ ______ ____/ /___   _____
@@ -46,6 +63,7 @@ const banner = `
 var bot Bot
 
 func main() {
+	bot.startTime = time.Now()
 	bot.shutdown = make(chan bool)
 	HandleInterrupt()
 
@@ -55,38 +73,53 @@ func main() {
 	}
 
 	//Handle flags
-	flag.StringVar(&bot.configFile, "config", defaultPath, "config file to load")
-	flag.StringVar(&bot.dataDir, "datadir", common.GetDefaultDataDir(runtime.GOOS), "default data directory for GoCryptoTrader files")
-	dryrun := flag.Bool("dryrun", false, "dry runs bot, doesn't save config file")
+	registerSettingsFlags(flag.CommandLine, &bot.settings, defaultPath)
 	version := flag.Bool("version", false, "retrieves current GoCryptoTrader version")
-	verbosity := flag.Bool("verbose", false, "increases logging verbosity for GoCryptoTrader")
+	generateConfig := flag.String("generateconfig", "", "prints a minimal config enabling the supplied comma separated exchanges, e.g. kraken,bitmex")
+	var backtestSettings BacktestSettings
+	flag.StringVar(&backtestSettings.DataPath, "backtest", "", "replays the backfilled trades and candles under the supplied path through the saved events, prints a paper trading report and exits")
+	flag.StringVar(&backtestSettings.EventsFile, "backtestevents", "", "events file replayed by -backtest, defaults to events.json in the data directory")
+	flag.StringVar(&backtestSettings.Start, "backteststart", "", "RFC3339 time the -backtest replay starts from")
+	flag.StringVar(&backtestSettings.End, "backtestend", "", "RFC3339 time the -backtest replay ends before")
+	flag.Float64Var(&backtestSettings.Speed, "backtestspeed", 0, "multiple of the recorded pace -backtest replays at, 0 replays as fast as possible")
+	flag.Float64Var(&backtestSettings.FeePercent, "backtestfee", 0, "taker fee percent charged on -backtest paper trades")
 
 	flag.Parse()
+	config.PortfolioOnly = bot.settings.PortfolioOnly
 
 	if *version {
 		fmt.Printf(BuildVersion(true))
 		os.Exit(0)
 	}
 
-	if *dryrun {
-		bot.dryRun = true
+	config.ExchangeDefaults = exchangeDefaultConfig
+	if *generateConfig != "" {
+		payload, err := minimalConfig(*generateConfig)
+		if err != nil {
+			log.Fatalf("Failed to generate config. Err: %s", err)
+		}
+		fmt.Println(string(payload))
+		os.Exit(0)
 	}
 
-	fmt.Println(banner)
-	fmt.Println(BuildVersion(false))
+	// -backtest prints its report instead of the banner
+	if backtestSettings.DataPath == "" {
+		fmt.Println(banner)
+		fmt.Println(BuildVersion(false))
+	}
 
 	bot.config = &config.Cfg
-	log.Debugf("Loading config file %s..\n", bot.configFile)
-	err = bot.config.LoadConfig(bot.configFile)
+	log.Debugf("Loading config file %s..\n", bot.settings.ConfigFile)
+	err = bot.config.LoadConfig(bot.settings.ConfigFile)
 	if err != nil {
 		log.Fatalf("Failed to load config. Err: %s", err)
 	}
 
-	err = common.CheckDir(bot.dataDir, true)
+	err = common.CheckDir(bot.settings.DataDir, true)
 	if err != nil {
-		log.Fatalf("Failed to open/create data directory: %s. Err: %s", bot.dataDir, err)
+		log.Fatalf("Failed to open/create data directory: %s. Err: %s", bot.settings.DataDir, err)
 	}
-	log.Debugf("Using data directory: %s.\n", bot.dataDir)
+	log.Debugf("Using data directory: %s.\n", bot.settings.DataDir)
 
 	err = bot.config.CheckLoggerConfig()
 	if err != nil {
@@ -100,7 +133,7 @@ func main() {
 
 	AdjustGoMaxProcs()
 	log.Debugf("Bot '%s' started.\n", bot.config.Name)
-	log.Debugf("Bot dry run mode: %v.\n", common.IsEnabled(bot.dryRun))
+	log.Debugf("Bot dry run mode: %v.\n", common.IsEnabled(bot.settings.DryRun))
 
 	log.Debugf("Available Exchanges: %d. Enabled Exchanges: %d.\n",
 		len(bot.config.Exchanges),
@@ -109,14 +142,41 @@ func main() {
 	common.HTTPClient = common.NewHTTPClientWithTimeout(bot.config.GlobalHTTPTimeout)
 	log.Debugf("Global HTTP request timeout: %v.\n", common.HTTPClient.Timeout)
 
+	ticker.SetPriceJumpLimit(bot.config.TickerPriceJumpLimit)
+
+	if backtestSettings.DataPath != "" {
+		loadBacktestHooks()
+		report, err := RunBacktest(context.Background(), &backtestSettings)
+		if err != nil {
+			log.Fatalf("Backtest failed. Err: %s", err)
+		}
+		payload, err := json.MarshalIndent(report, "", " ")
+		if err != nil {
+			log.Fatalf("Failed to encode the backtest report. Err: %s", err)
+		}
+		fmt.Println(string(payload))
+		os.Exit(0)
+	}
+
 	SetupExchanges()
 	if len(bot.exchanges) == 0 {
-		log.Fatalf("No exchanges were able to be loaded. Exiting")
+		if !bot.config.AllowsNoExchanges() {
+			log.Fatalf("No exchanges were able to be loaded. Exiting")
+		}
+		log.Warnf("No exchanges loaded, running in portfolio only mode.")
 	}
+	logAuthAPISupportedExchanges()
 
-	log.Debugf("Starting communication mediums..")
-	bot.comms = communications.NewComm(bot.config.GetCommunicationsConfig())
-	bot.comms.GetEnabledCommunicationMediums()
+	setupCommunications()
+	setupEvents()
+	registerDefaultHooks()
+
+	fillsPath := filepath.Join(bot.settings.DataDir, fillsLedgerFile)
+	err = LoadFillsLedger(fillsPath)
+	if err != nil {
+		log.Errorf("Failed to load the fills ledger from %s: %s", fillsPath, err)
+	}
+	withdrawals.path = filepath.Join(bot.settings.DataDir, withdrawalJournalFile)
 
 	if bot.config.GetCryptocurrencyProviderConfig().Enabled {
 		log.Debug("Seeding full market data...")
@@ -124,7 +184,7 @@ func main() {
 		if err != nil {
 			log.Warnf("Failure seeding cryptocurrency market data %s", err)
 		} else {
-			if *verbosity {
+			if bot.settings.Verbose {
 				log.Debugf("Total market cryptocurrencies: %d",
 					len(currency.GetTotalMarketCryptocurrencies()))
 			}
@@ -134,7 +194,7 @@ func main() {
 		if err != nil {
 			log.Warnf("Failure seeding exchange market data %s", err)
 		} else {
-			if *verbosity {
+			if bot.settings.Verbose {
 				log.Debugf("Total market exchanges: %d",
 					len(currency.GetTotalMarketExchanges()))
 			}
@@ -159,39 +219,152 @@ func main() {
 	}
 
 	bot.portfolio = &portfolio.Portfolio
-	bot.portfolio.SeedPortfolio(bot.config.Portfolio)
-	SeedExchangeAccountInfo(GetAllEnabledExchangeAccountInfo().Data)
-
-	if bot.config.Webserver.Enabled {
-		listenAddr := bot.config.Webserver.ListenAddress
-		log.Debugf(
-			"HTTP Webserver support enabled. Listen URL: http://%s:%d/\n",
-			common.ExtractHost(listenAddr), common.ExtractPort(listenAddr),
-		)
-
-		router := NewRouter()
-		go func() {
-			err = http.ListenAndServe(listenAddr, router)
-			if err != nil {
-				log.Fatal(err)
-			}
-		}()
+	bot.portfolio.SeedPortfolio(&bot.config.Portfolio)
+	accounts := GetAllEnabledExchangeAccountInfo().Data
+	normalised := SeedExchangeAccountInfo(accounts)
+	for orig, canonical := range normalised {
+		log.Debugf("Portfolio: Normalised exchange account currency %s to %s.\n",
+			orig, canonical)
+	}
+	updateBalanceSnapshots(accounts)
 
-		log.Debugln("HTTP Webserver started successfully.")
-		log.Debugln("Starting websocket handler.")
-		StartWebsocketHandler()
+	warmDepositAddressCache()
+
+	startSubsystems()
+	startRoutine(ConfigPersistenceRoutine)
+
+	<-bot.shutdown
+	Shutdown()
+}
+
+// setupCommunications starts the communication mediums enabled in the config.
+// Events are pushed nowhere when communications are disabled
+func setupCommunications() {
+	if !bot.settings.EnableCommunications {
+		log.Debugln("Communications disabled.")
+		bot.comms = &communications.Communications{}
+		return
+	}
+	log.Debugf("Starting communication mediums..")
+	bot.comms = communications.NewComm(bot.config.GetCommunicationsConfig())
+	bot.comms.GetEnabledCommunicationMediums()
+	setSubsystemRunning(SubsystemCommunications, true)
+}
+
+// setupEvents loads the saved events from the data directory
+func setupEvents() {
+	if !bot.settings.EnableEvents {
+		log.Debugln("Events disabled.")
+		return
+	}
+	eventsPath := filepath.Join(bot.settings.DataDir, "events.json")
+	err := events.LoadEvents(eventsPath)
+	if err != nil {
+		log.Errorf("Failed to load events, continuing with no events: %s", err)
+	} else {
+		log.Debugf("Loaded %d events from %s.\n", len(events.Events), eventsPath)
+	}
+	setSubsystemRunning(SubsystemEvents, true)
+}
+
+// startSubsystems starts the webserver, websocket RPC handler, portfolio
+// manager, updater routines and exchange websockets which are enabled
+func startSubsystems() {
+	if bot.config.Webserver.Enabled && bot.settings.EnableWebserver {
+		startWebserver()
 	} else {
 		log.Debugln("HTTP RESTful Webserver support disabled.")
 	}
 
-	go portfolio.StartPortfolioWatcher()
+	if bot.settings.EnablePortfolioManager {
+		startRoutine(PortfolioWatcherRoutine)
+		setSubsystemRunning(SubsystemPortfolioManager, true)
+	} else {
+		log.Debugln("Portfolio manager disabled.")
+	}
+
+	if bot.settings.EnableUpdaterRoutines {
+		startRoutine(TickerUpdaterRoutine)
+		startRoutine(OrderbookUpdaterRoutine)
+		startRoutine(PairUpdaterRoutine)
+		startRoutine(MarketDataSweeperRoutine)
+		startRoutine(BalanceRefresherRoutine)
+		go OrderFillRoutine()
+		go ServerTimeSyncRoutine()
+		setSubsystemRunning(SubsystemUpdaterRoutines, true)
+	} else {
+		log.Debugln("Updater routines disabled.")
+	}
 
-	go TickerUpdaterRoutine()
-	go OrderbookUpdaterRoutine()
-	go WebsocketRoutine(*verbosity)
+	if isSubsystemRunning(SubsystemEvents) {
+		startRoutine(EventsCheckerRoutine)
+	}
 
-	<-bot.shutdown
-	Shutdown()
+	if bot.settings.EnableExchangeWebsockets {
+		go WebsocketRoutine(bot.settings.Verbose)
+		setSubsystemRunning(SubsystemExchangeWebsockets, true)
+	} else {
+		log.Debugln("Exchange websockets disabled.")
+	}
+}
+
+// startWebserver starts the REST webserver and, when enabled, its websocket
+// RPC handler
+func startWebserver() {
+	listenAddr := bot.config.Webserver.ListenAddress
+	scheme := "http"
+	if bot.config.Webserver.TLSEnabled {
+		scheme = "https"
+	}
+	log.Debugf(
+		"HTTP Webserver support enabled. Listen URL: %s://%s:%d/\n",
+		scheme, common.ExtractHost(listenAddr), common.ExtractPort(listenAddr),
+	)
+
+	bot.webserver = &http.Server{
+		Addr:    listenAddr,
+		Handler: NewRouter(),
+	}
+	if bot.config.Webserver.TLSEnabled {
+		var err error
+		bot.webserver.TLSConfig, err = newWebserverTLSConfig(
+			filepath.Join(bot.settings.DataDir, tlsDir), &bot.config.Webserver)
+		if err != nil {
+			log.Fatalf("Failed to set up webserver TLS. Err: %s", err)
+		}
+	}
+	go func() {
+		var err error
+		if bot.webserver.TLSConfig != nil {
+			err = bot.webserver.ListenAndServeTLS("", "")
+		} else {
+			err = bot.webserver.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	setSubsystemRunning(SubsystemWebserver, true)
+	log.Debugln("HTTP Webserver started successfully.")
+
+	if !bot.settings.EnableWebsocketRPC {
+		wsRPCDisabled = true
+		log.Debugln("Websocket RPC handler disabled.")
+		return
+	}
+	log.Debugln("Starting websocket handler.")
+	StartWebsocketHandler()
+	setSubsystemRunning(SubsystemWebsocketRPC, true)
+}
+
+// minimalConfig returns the JSON of a minimal config enabling the supplied
+// comma separated exchanges
+func minimalConfig(exchanges string) ([]byte, error) {
+	cfg, err := config.GenerateMinimalConfig(common.SplitStrings(exchanges, ","))
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(cfg, "", " ")
 }
 
 // AdjustGoMaxProcs adjusts the maximum processes that the CPU can handle.
@@ -228,16 +401,53 @@ func HandleInterrupt() {
 	}()
 }
 
-// Shutdown correctly shuts down bot saving configuration files
-func Shutdown() {
-	log.Debugln("Bot shutting down..")
+// RequestShutdown asks the main routine to shut down the bot
+func RequestShutdown() {
+	go func() {
+		bot.shutdown <- true
+	}()
+}
 
-	if len(portfolio.Portfolio.Addresses) != 0 {
-		bot.config.Portfolio = portfolio.Portfolio
+// IsStopping returns whether the bot is shutting down and no longer accepting
+// new requests
+func IsStopping() bool {
+	return atomic.LoadInt32(&bot.stopping) == 1
+}
+
+// Stop stops accepting new requests, signals the updater routines and
+// websocket handlers to stop and waits for them up to the supplied timeout.
+// The portfolio and config are then saved, unless runtime config saves are
+// disabled, and the webserver is stopped. A timeout is returned as an error
+// after the remaining steps have completed
+func Stop(timeout time.Duration) error {
+	if !atomic.CompareAndSwapInt32(&bot.stopping, 0, 1) {
+		return errors.New("bot is already stopping")
+	}
+
+	log.Debugln("Stopping bot routines..")
+	signalShutdown()
+	cancelShutdownCtx()
+
+	bot.stopped = make(chan struct{})
+	go func(done chan struct{}) {
+		stopExchangeWebsockets()
+		wg.Wait()
+		close(done)
+	}(bot.stopped)
+
+	var result error
+	select {
+	case <-bot.stopped:
+		log.Debugln("Bot routines stopped.")
+	case <-time.After(timeout):
+		log.Warnf("Bot routines failed to stop within %v.", timeout)
+		result = errShutdownTimeout
 	}
 
-	if !bot.dryRun {
-		err := bot.config.SaveConfig(bot.configFile)
+	syncConfigPortfolio()
+
+	if ConfigPersistenceEnabled() {
+		err := flushConfig(true)
 
 		if err != nil {
 			log.Warn("Unable to save config.")
@@ -246,6 +456,47 @@ func Shutdown() {
 		}
 	}
 
+	if bot.webserver != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err := bot.webserver.Shutdown(ctx)
+		if err != nil {
+			log.Warnf("Unable to stop webserver. Err: %s", err)
+		}
+	}
+	return result
+}
+
+// stopExchangeWebsockets shuts down all enabled exchange websocket
+// connections
+func stopExchangeWebsockets() {
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+
+		ws, err := bot.exchanges[x].GetWebsocket()
+		if err != nil || ws == nil || !ws.IsEnabled() {
+			continue
+		}
+
+		err = ws.Shutdown()
+		if err != nil {
+			log.Debugf("%s websocket shutdown: %s", bot.exchanges[x].GetName(), err)
+		}
+	}
+}
+
+// Shutdown correctly shuts down bot saving configuration files
+func Shutdown() {
+	log.Debugln("Bot shutting down..")
+
+	err := Stop(shutdownTimeout)
+	if err != nil {
+		log.Warnf("Bot shutdown incomplete. Err: %s", err)
+	}
+	config.ClearConfigEncryptionKey()
+
 	log.Debugln("Exiting.")
 
 	log.CloseLogFile()
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/config"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+)
+
+func resetStop() {
+	atomic.StoreInt32(&bot.stopping, 0)
+	shutdowner = make(chan struct{}, 1)
+	shutdownOnce = sync.Once{}
+	shutdownCtx, cancelShutdownCtx = context.WithCancel(context.Background())
+}
+
+func TestStop(t *testing.T) {
+	SetupTestHelpers(t)
+	dryRun := bot.settings.DryRun
+	bot.settings.DryRun = true
+	defer func() {
+		bot.settings.DryRun = dryRun
+		resetStop()
+	}()
+
+	// Simulate a hung subsystem which never returns
+	wg.Add(1)
+	defer func() {
+		wg.Done()
+		<-bot.stopped
+	}()
+
+	start := time.Now()
+	err := Stop(time.Millisecond * 100)
+	if err != errShutdownTimeout {
+		t.Fatalf("Test failed. Expected %s, got %v", errShutdownTimeout, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second*2 {
+		t.Fatalf("Test failed. Stop returned after %v", elapsed)
+	}
+
+	select {
+	case <-shutdowner:
+	default:
+		t.Fatal("Test failed. Shutdown was not signalled")
+	}
+
+	if !IsStopping() {
+		t.Fatal("Test failed. Bot should be stopping")
+	}
+
+	if Stop(time.Millisecond*100) == nil {
+		t.Fatal("Test failed. Stop should return an error when already stopping")
+	}
+
+	w := httptest.NewRecorder()
+	handler := RESTRejectWhileStopping(http.HandlerFunc(getIndex))
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Test failed. Expected status %d while stopping, got %d",
+			http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestStopRoutines(t *testing.T) {
+	SetupTestHelpers(t)
+	dryRun := bot.settings.DryRun
+	bot.settings.DryRun = true
+	defer func() {
+		bot.settings.DryRun = dryRun
+		resetStop()
+	}()
+
+	exchanges := bot.exchanges
+	bot.exchanges = nil
+	defer func() { bot.exchanges = exchanges }()
+
+	startRoutine(TickerUpdaterRoutine)
+	startRoutine(OrderbookUpdaterRoutine)
+	time.Sleep(time.Millisecond * 50)
+
+	err := Stop(time.Second * 5)
+	if err != nil {
+		t.Fatalf("Test failed. Stop error: %s", err)
+	}
+	if shutdownCtx.Err() != context.Canceled {
+		t.Error("Test failed. Stop should cancel in flight routine requests")
+	}
+}
+
+func TestPortfolioOnlyMode(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(cfg *config.Config, exchanges []exchange.IBotExchange, port *portfolio.Base) {
+		bot.config = cfg
+		bot.exchanges = exchanges
+		bot.portfolio = port
+	}(bot.config, bot.exchanges, bot.portfolio)
+
+	var cfg config.Config
+	err := cfg.LoadConfig("./testdata/configtest.json")
+	if err != nil {
+		t.Fatalf("Test failed. Failed to load config: %s", err)
+	}
+	for x := range cfg.Exchanges {
+		cfg.Exchanges[x].Enabled = false
+	}
+	cfg.AllowNoExchanges = true
+	cfg.Portfolio = portfolio.Base{Addresses: []portfolio.Address{
+		{Address: "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy", CoinType: "BTC", Balance: 1.5,
+			Description: portfolio.PortfolioAddressPersonal},
+	}}
+	err = cfg.CheckExchangeConfigValues()
+	if err != nil {
+		t.Fatalf("Test failed. CheckExchangeConfigValues: %s", err)
+	}
+	bot.config = &cfg
+	bot.exchanges = nil
+
+	// Boot the exchange dependent subsystems the same way main does
+	SetupExchanges()
+	if len(bot.exchanges) != 0 {
+		t.Fatalf("Test failed. Expected no exchanges, loaded %d", len(bot.exchanges))
+	}
+	bot.portfolio = &portfolio.Base{}
+	bot.portfolio.SeedPortfolio(&cfg.Portfolio)
+	accounts := GetAllEnabledExchangeAccountInfo().Data
+	SeedExchangeAccountInfo(accounts)
+	updateBalanceSnapshots(accounts)
+	if addresses := GetExchangeCryptocurrencyDepositAddresses(false); len(addresses) != 0 {
+		t.Errorf("Test failed. Expected no deposit addresses, got %v", addresses)
+	}
+	WebsocketRoutine(false)
+
+	router := NewRouter()
+	for path, expected := range map[string]string{
+		"/exchanges/enabled/latest/all":                               `{"data":[]}`,
+		"/exchanges/enabled/latest/all?convertToDisplayCurrency=true": `{"data":[]}`,
+		"/exchanges/enabled/accounts/all":                             `{"data":[]}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != expected {
+			t.Errorf("Test failed. %s expected %s, got %d %s", path, expected,
+				w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/portfolio/all", nil))
+	var summary portfolio.Summary
+	err = json.Unmarshal(w.Body.Bytes(), &summary)
+	if err != nil || w.Code != http.StatusOK || len(summary.Totals) != 1 ||
+		summary.Totals[0].Coin != "BTC" || summary.Totals[0].Balance != 1.5 {
+		t.Errorf("Test failed. Unexpected portfolio response %d %s", w.Code,
+			w.Body.String())
+	}
+
+	info := GetDaemonInfo()
+	if info.LoadedExchanges != 0 || info.EnabledExchanges != 0 {
+		t.Errorf("Test failed. Unexpected daemon info %+v", info)
+	}
+}
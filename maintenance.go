@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+var (
+	maintenanceLog = log.NewSubLogger("engine.maintenance")
+	// maintenanceProbes tracks the running maintenance probe routines
+	maintenanceProbes sync.WaitGroup
+)
+
+// ExchangeMaintenanceChange holds an exchange entering or leaving maintenance
+type ExchangeMaintenanceChange struct {
+	Exchange    string    `json:"exchange"`
+	Maintenance bool      `json:"maintenance"`
+	Time        time.Time `json:"time"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// exchangeMaintenanceProbe checks whether an exchange in maintenance has
+// recovered by updating the ticker of its first enabled pair
+func exchangeMaintenanceProbe(exch exchange.IBotExchange) error {
+	pairs := exch.GetEnabledCurrencies()
+	assetTypes := exch.GetAssetTypes()
+	if len(pairs) == 0 || len(assetTypes) == 0 {
+		return nil
+	}
+	_, err := updateTickerWithContext(shutdownCtx, exch, pairs[0], assetTypes[0])
+	return err
+}
+
+// IsExchangeInMaintenance returns whether an exchange is marked as in
+// maintenance. Updater routines skip exchanges in maintenance
+func IsExchangeInMaintenance(name string) bool {
+	exchangeHealthMtx.RLock()
+	defer exchangeHealthMtx.RUnlock()
+	return exchangeHealth[common.StringToLower(name)].Maintenance
+}
+
+// setExchangeMaintenance marks an exchange as in or out of maintenance and
+// returns whether its state changed
+func setExchangeMaintenance(name string, inMaintenance bool) bool {
+	exchangeHealthMtx.Lock()
+	h, ok := exchangeHealth[common.StringToLower(name)]
+	if !ok {
+		h.Exchange = name
+	}
+	if h.Maintenance == inMaintenance {
+		exchangeHealthMtx.Unlock()
+		return false
+	}
+	h.Maintenance = inMaintenance
+	h.MaintenanceSince = time.Time{}
+	if inMaintenance {
+		h.MaintenanceSince = time.Now()
+	}
+	exchangeHealth[common.StringToLower(name)] = h
+	exchangeHealthMtx.Unlock()
+
+	events.SetExchangeMaintenance(name, inMaintenance)
+	return true
+}
+
+// checkExchangeMaintenance marks an exchange as in maintenance when err is a
+// maintenance error and starts probing it for recovery. It returns whether err
+// is a maintenance error
+func checkExchangeMaintenance(exch exchange.IBotExchange, err error) bool {
+	if !errors.Is(err, exchange.ErrExchangeMaintenance) {
+		return false
+	}
+
+	if setExchangeMaintenance(exch.GetName(), true) {
+		notifyExchangeMaintenance(ExchangeMaintenanceChange{
+			Exchange:    exch.GetName(),
+			Maintenance: true,
+			Time:        time.Now(),
+			Error:       err.Error(),
+		})
+		maintenanceProbes.Add(1)
+		startRoutine(func() { probeExchangeMaintenance(exch) })
+	}
+	return true
+}
+
+// probeExchangeMaintenance probes an exchange in maintenance on each
+// maintenance probe interval until a probe succeeds, then marks the exchange
+// as recovered. Probing stops if the exchange is disabled
+func probeExchangeMaintenance(exch exchange.IBotExchange) {
+	defer maintenanceProbes.Done()
+
+	name := exch.GetName()
+	logger := maintenanceLog.With("exchange", name)
+	for {
+		if !waitOrShutdown(bot.config.MaintenanceProbeInterval) {
+			return
+		}
+
+		if !exch.IsEnabled() {
+			logger.Debugln("Exchange disabled, stopping maintenance probe.")
+			setExchangeMaintenance(name, false)
+			return
+		}
+
+		err := exchangeMaintenanceProbe(exch)
+		if err == nil {
+			if setExchangeMaintenance(name, false) {
+				notifyExchangeMaintenance(ExchangeMaintenanceChange{
+					Exchange: name,
+					Time:     time.Now(),
+				})
+			}
+			return
+		}
+
+		if !errors.Is(err, exchange.ErrExchangeMaintenance) {
+			logger.Debugf("Maintenance probe failed. Error: %s", err)
+		}
+	}
+}
+
+// notifyExchangeMaintenance pushes an exchange entering or leaving maintenance
+// to the enabled communication mediums and websocket clients
+func notifyExchangeMaintenance(change ExchangeMaintenanceChange) {
+	logger := maintenanceLog.With("exchange", change.Exchange)
+	var details string
+	if change.Maintenance {
+		details = fmt.Sprintf("%s entered maintenance, suspending updates. Error: %s",
+			change.Exchange, change.Error)
+		logger.Warnf("%s.", details)
+	} else {
+		details = fmt.Sprintf("%s recovered from maintenance, resuming updates",
+			change.Exchange)
+		logger.Infof("%s.", details)
+	}
+
+	bot.comms.PushEvent(base.Event{
+		Type:         "exchange_maintenance",
+		TradeDetails: details,
+	})
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(change, WebsocketEventExchangeMaintenance, "", change.Exchange)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/communications"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+func TestExchangeMaintenance(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(c *communications.Communications, interval time.Duration, webserver bool) {
+		bot.comms = c
+		bot.config.MaintenanceProbeInterval = interval
+		bot.config.Webserver.Enabled = webserver
+	}(bot.comms, bot.config.MaintenanceProbeInterval, bot.config.Webserver.Enabled)
+	defer func() {
+		exchangeHealthMtx.Lock()
+		delete(exchangeHealth, "maintenancetest")
+		exchangeHealthMtx.Unlock()
+	}()
+
+	comm := &mockComm{Base: base.Base{Name: "mock", Enabled: true, Connected: true}}
+	bot.comms = &communications.Communications{IComm: base.IComm{comm}}
+	bot.config.Webserver.Enabled = false
+	bot.config.MaintenanceProbeInterval = time.Millisecond
+
+	maintenanceErr := exchange.WrapError(exchange.ErrExchangeMaintenance, "code 20014")
+	// Ticker updates fail with the queued errors, succeeding once they run
+	// out. Holding probeMtx blocks the probes
+	var probeMtx sync.Mutex
+	errs := []error{
+		maintenanceErr,
+		exchange.WrapError(exchange.ErrExchangeUnavailable, "timeout"),
+		maintenanceErr,
+	}
+	exch := newTestExchange("MaintenanceTest")
+	exch.updateTicker = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		probeMtx.Lock()
+		defer probeMtx.Unlock()
+		if len(errs) == 0 {
+			return ticker.Price{Last: 1}, nil
+		}
+		err := errs[0]
+		errs = errs[1:]
+		return ticker.Price{}, err
+	}
+
+	// Other errors don't mark the exchange
+	if checkExchangeMaintenance(exch, nil) ||
+		checkExchangeMaintenance(exch, errors.New("rawr")) ||
+		checkExchangeMaintenance(exch, exchange.ErrExchangeUnavailable) {
+		t.Error("Test failed. Non maintenance error detected as maintenance")
+	}
+	if IsExchangeInMaintenance(exch.GetName()) {
+		t.Fatal("Test failed. Exchange marked as in maintenance")
+	}
+
+	probeMtx.Lock()
+	if !checkExchangeMaintenance(exch, maintenanceErr) ||
+		!checkExchangeMaintenance(exch, maintenanceErr) {
+		t.Error("Test failed. Maintenance error not detected")
+	}
+	if !IsExchangeInMaintenance(exch.GetName()) ||
+		!events.IsExchangeInMaintenance(exch.GetName()) {
+		t.Error("Test failed. Exchange not marked as in maintenance")
+	}
+	if n := len(comm.pushed()); n != 1 {
+		t.Errorf("Test failed. Expected one notification on entering maintenance, got %d", n)
+	}
+	probeMtx.Unlock()
+
+	// Recovers on the first successful probe, maintenance and other errors
+	// keep the exchange in maintenance
+	maintenanceProbes.Wait()
+	if IsExchangeInMaintenance(exch.GetName()) {
+		t.Error("Test failed. Exchange still in maintenance after a successful probe")
+	}
+	if n := exch.callCount("UpdateTicker"); n != 4 {
+		t.Errorf("Test failed. Expected 4 probes, got %d", n)
+	}
+	if events.IsExchangeInMaintenance(exch.GetName()) {
+		t.Error("Test failed. Events still suppressed after maintenance")
+	}
+	pushed := comm.pushed()
+	if len(pushed) != 2 || pushed[0].Type != "exchange_maintenance" ||
+		pushed[1].Type != "exchange_maintenance" {
+		t.Errorf("Test failed. Expected entering and leaving notifications, got %v", pushed)
+	}
+
+	// A disabled exchange stops being probed
+	exch.base.Enabled = false
+	exch.resetCalls()
+	checkExchangeMaintenance(exch, maintenanceErr)
+	maintenanceProbes.Wait()
+	if IsExchangeInMaintenance(exch.GetName()) {
+		t.Error("Test failed. Disabled exchange still in maintenance")
+	}
+	if n := exch.callCount("UpdateTicker"); n != 0 {
+		t.Errorf("Test failed. Disabled exchange probed %d times", n)
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+const (
+	// orderStatusPollInterval is how often the status of the orders submitted
+	// through the bot is polled for fills
+	orderStatusPollInterval = time.Second * 15
+	// fillsLedgerFile is the journal of order fills in the data directory
+	fillsLedgerFile = "fills.log"
+	// fillTolerance ignores filled amount changes from float rounding
+	fillTolerance = 1e-12
+)
+
+// Order statuses fill notifications are sent for, exchange statuses are
+// normalised to these
+const (
+	OrderStatusFilled          = "Filled"
+	OrderStatusPartiallyFilled = "PartiallyFilled"
+	OrderStatusCancelled       = "Cancelled"
+)
+
+// OrderFill holds an order execution or status change observed between two
+// polls of an order's status
+type OrderFill struct {
+	Exchange string            `json:"exchange"`
+	Pair     pair.CurrencyPair `json:"pair"`
+	OrderID  string            `json:"order_id"`
+	Side     string            `json:"side"`
+	Status   string            `json:"status"`
+	// Filled is the amount filled since the previous poll
+	Filled float64 `json:"filled"`
+	// AveragePrice is the average execution price of the order, zero if the
+	// exchange doesn't report it
+	AveragePrice float64   `json:"average_price,omitempty"`
+	Remaining    float64   `json:"remaining"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// trackedOrder holds the last observed state of an order whose status is
+// polled for fills, updated is when its status was last observed
+type trackedOrder struct {
+	exchange string
+	account  string
+	id       string
+	pair     pair.CurrencyPair
+	side     string
+	amount   float64
+	price    float64
+	filled   float64
+	status   string
+	updated  time.Time
+}
+
+var (
+	trackedOrders   = make(map[string]*trackedOrder)
+	trackedOrderMtx sync.Mutex
+)
+
+// fillsLedger journals the order fills observed by the bot, each fill is
+// appended to the journal file as a JSON line
+type fillsLedger struct {
+	mtx   sync.Mutex
+	path  string
+	fills []OrderFill
+}
+
+var fills fillsLedger
+
+// trackOrder polls the status of a submitted order for fills until it is
+// filled or cancelled. Orders submitted without an ID are not tracked
+func trackOrder(exchName, account string, order *exchange.OrderSubmission, orderID string) {
+	if orderID == "" {
+		routinesLog.With("exchange", exchName).Debug(
+			"Order submitted without an ID, fills won't be tracked.")
+		return
+	}
+
+	trackedOrderMtx.Lock()
+	trackedOrders[exchName+"/"+orderID] = &trackedOrder{
+		exchange: exchName,
+		account:  account,
+		id:       orderID,
+		pair:     order.Pair,
+		side:     string(order.OrderSide),
+		amount:   order.Amount,
+		price:    order.Price,
+		updated:  time.Now(),
+	}
+	trackedOrderMtx.Unlock()
+}
+
+// normaliseOrderStatus maps an exchange order status to the filled, partially
+// filled and cancelled statuses, other statuses are returned unchanged
+func normaliseOrderStatus(status string) string {
+	switch strings.NewReplacer(" ", "", "_", "", "-", "").Replace(common.StringToLower(status)) {
+	case "filled", "closed", "done", "executed":
+		return OrderStatusFilled
+	case "partiallyfilled", "partial", "partfilled":
+		return OrderStatusPartiallyFilled
+	case "cancelled", "canceled":
+		return OrderStatusCancelled
+	}
+	return status
+}
+
+// isNotifiedOrderStatus returns whether a transition to the status is
+// notified
+func isNotifiedOrderStatus(status string) bool {
+	return status == OrderStatusFilled || status == OrderStatusPartiallyFilled ||
+		status == OrderStatusCancelled
+}
+
+// update applies a polled order status and returns the fill to notify, if
+// the filled amount increased or the status changed to a notified status, and
+// whether the order is complete. Polling an unchanged order returns no fill
+func (t *trackedOrder) update(detail exchange.OrderDetail, now time.Time) (*OrderFill, bool) {
+	status := normaliseOrderStatus(detail.Status)
+	filled := t.filled
+	remaining := t.amount - t.filled
+	if detail.Amount > 0 {
+		filled = detail.Amount - detail.OpenVolume
+		remaining = detail.OpenVolume
+	}
+
+	delta := filled - t.filled
+	if delta < fillTolerance {
+		delta = 0
+	}
+	statusChanged := status != t.status && isNotifiedOrderStatus(status)
+	t.status = status
+	t.updated = now
+	complete := status == OrderStatusFilled || status == OrderStatusCancelled
+	if delta == 0 && !statusChanged {
+		return nil, complete
+	}
+
+	t.filled = filled
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &OrderFill{
+		Exchange:     t.exchange,
+		Pair:         t.pair,
+		OrderID:      t.id,
+		Side:         t.side,
+		Status:       status,
+		Filled:       delta,
+		AveragePrice: detail.AveragePrice,
+		Remaining:    remaining,
+		Timestamp:    now,
+	}, complete
+}
+
+// pollTrackedOrders polls the status of every tracked order and returns the
+// fills observed since the previous poll. Orders which are complete, whose
+// exchange is no longer loaded or which the exchange can't query are no longer
+// tracked. Orders of exchanges without working authenticated API support are
+// skipped until it is restored
+func pollTrackedOrders() []OrderFill {
+	trackedOrderMtx.Lock()
+	orders := make(map[string]*trackedOrder, len(trackedOrders))
+	for key, order := range trackedOrders {
+		orders[key] = order
+	}
+	trackedOrderMtx.Unlock()
+
+	var result []OrderFill
+	for key, order := range orders {
+		logger := routinesLog.With("exchange", order.exchange)
+		exch := GetExchangeByName(order.exchange)
+		if exch == nil {
+			untrackOrder(key)
+			continue
+		}
+		if !isAuthAPISupported(exch) {
+			continue
+		}
+
+		accountExch, err := GetAccountExchange(exch, order.account)
+		if err != nil {
+			logger.Warnf("Unable to fetch order %s status: %s", order.id, err)
+			continue
+		}
+
+		detail, err := accountExch.GetOrderInfo(order.id)
+		if err != nil {
+			if err == common.ErrNotYetImplemented || err == common.ErrFunctionNotSupported {
+				logger.Debugf("Order status not supported, order %s fills won't be tracked.",
+					order.id)
+				untrackOrder(key)
+				continue
+			}
+			logger.Warnf("Unable to fetch order %s status: %s", order.id, err)
+			continue
+		}
+
+		trackedOrderMtx.Lock()
+		fill, complete := order.update(detail, time.Now())
+		trackedOrderMtx.Unlock()
+		if fill != nil {
+			result = append(result, *fill)
+		}
+		if complete {
+			untrackOrder(key)
+		}
+	}
+	return result
+}
+
+// getTrackedOrders returns a copy of the orders whose status is polled, which
+// are the open orders submitted through the bot
+func getTrackedOrders() []trackedOrder {
+	trackedOrderMtx.Lock()
+	defer trackedOrderMtx.Unlock()
+	result := make([]trackedOrder, 0, len(trackedOrders))
+	for _, order := range trackedOrders {
+		result = append(result, *order)
+	}
+	return result
+}
+
+// untrackOrder stops polling the status of an order
+func untrackOrder(key string) {
+	trackedOrderMtx.Lock()
+	delete(trackedOrders, key)
+	trackedOrderMtx.Unlock()
+}
+
+// notifyOrderFills records executions in the fills ledger and pushes the
+// fills to the enabled communication mediums and websocket clients
+func notifyOrderFills(orderFills []OrderFill) {
+	for x := range orderFills {
+		if orderFills[x].Filled > 0 {
+			err := fills.record(orderFills[x])
+			if err != nil {
+				routinesLog.Errorf("Unable to record order fill: %s", err)
+			}
+		}
+
+		details := fmt.Sprintf("%s %s %s order %s %s, filled %f remaining %f",
+			orderFills[x].Exchange, orderFills[x].Pair.Pair(), orderFills[x].Side,
+			orderFills[x].OrderID, orderFills[x].Status, orderFills[x].Filled,
+			orderFills[x].Remaining)
+		if orderFills[x].AveragePrice > 0 {
+			details += fmt.Sprintf(" at average price %f", orderFills[x].AveragePrice)
+		}
+		routinesLog.Infof("%s.", details)
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "order_fill",
+			TradeDetails: details,
+		})
+		if bot.config.Webserver.Enabled {
+			relayWebsocketEvent(orderFills[x], WebsocketEventOrderFill, "",
+				orderFills[x].Exchange)
+		}
+	}
+}
+
+// OrderFillRoutine polls the status of the orders submitted through the bot
+// and notifies their fills
+func OrderFillRoutine() {
+	wg.Add(1)
+	defer wg.Done()
+
+	routinesLog.Debugf("Starting order fill routine. Poll interval: %v.",
+		orderStatusPollInterval)
+	for {
+		if !waitOrShutdown(orderStatusPollInterval) {
+			routinesLog.Debugln("Order fill routine stopped.")
+			return
+		}
+		notifyOrderFills(pollTrackedOrders())
+	}
+}
+
+// LoadFillsLedger loads the order fills journaled to the file and appends
+// later fills to it. A partially written last entry is skipped
+func LoadFillsLedger(path string) error {
+	fills.mtx.Lock()
+	defer fills.mtx.Unlock()
+
+	fills.path = path
+	fills.fills = nil
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var fill OrderFill
+		err = json.Unmarshal(scanner.Bytes(), &fill)
+		if err != nil {
+			routinesLog.Warnf("Skipping invalid fills ledger entry on line %d: %s",
+				line, err)
+			continue
+		}
+		fills.fills = append(fills.fills, fill)
+	}
+	return scanner.Err()
+}
+
+// record adds a fill to the ledger and appends it to the journal file
+func (l *fillsLedger) record(fill OrderFill) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.fills = append(l.fills, fill)
+	if l.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(fill)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// GetFills returns the recorded order fills, optionally filtered by exchange,
+// currency pair and a start and end time. A zero start or end leaves that side
+// of the range open
+func GetFills(exchName, currency string, start, end time.Time) ([]OrderFill, error) {
+	if !start.IsZero() && !end.IsZero() && start.After(end) {
+		return nil, exchange.ErrInvalidTimeRange
+	}
+
+	filter := TickerFilter{}
+	if currency != "" {
+		filter.Pairs = []string{currency}
+	}
+
+	fills.mtx.Lock()
+	defer fills.mtx.Unlock()
+	result := []OrderFill{}
+	for x := range fills.fills {
+		fill := fills.fills[x]
+		if exchName != "" && !strings.EqualFold(fill.Exchange, exchName) {
+			continue
+		}
+		if !filter.matchPair(fill.Pair) {
+			continue
+		}
+		if (!start.IsZero() && fill.Timestamp.Before(start)) ||
+			(!end.IsZero() && fill.Timestamp.After(end)) {
+			continue
+		}
+		result = append(result, fill)
+	}
+	return result, nil
+}
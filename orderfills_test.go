@@ -0,0 +1,301 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// newFillExchange returns a test exchange returning the next order status of
+// the sequence on each order status request, or failing with err if it's set
+func newFillExchange(name string, statuses []exchange.OrderDetail, err error) *testExchange {
+	exch := newTestExchange(name)
+	exch.getOrderInfo = func(orderID string) (exchange.OrderDetail, error) {
+		if err != nil {
+			return exchange.OrderDetail{}, err
+		}
+		detail := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		return detail, nil
+	}
+	return exch
+}
+
+// resetOrderFills clears the tracked orders and the fills ledger
+func resetOrderFills() {
+	trackedOrderMtx.Lock()
+	trackedOrders = make(map[string]*trackedOrder)
+	trackedOrderMtx.Unlock()
+	fills.mtx.Lock()
+	fills.path, fills.fills = "", nil
+	fills.mtx.Unlock()
+}
+
+func TestOrderFillNotifications(t *testing.T) {
+	SetupTestHelpers(t)
+	resetOrderFills()
+	defer func(exchanges []exchange.IBotExchange, c *communications.Communications, ws bool) {
+		bot.exchanges, bot.comms, bot.config.Webserver.Enabled = exchanges, c, ws
+		resetOrderFills()
+	}(bot.exchanges, bot.comms, bot.config.Webserver.Enabled)
+
+	dir, err := ioutil.TempDir("", "gctfills")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ledgerPath := filepath.Join(dir, fillsLedgerFile)
+	err = LoadFillsLedger(ledgerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comm := &mockComm{Base: base.Base{Name: "mock", Enabled: true, Connected: true}}
+	bot.comms = &communications.Communications{IComm: base.IComm{comm}}
+	bot.config.Webserver.Enabled = false
+
+	open := exchange.OrderDetail{Status: "open", Amount: 2, OpenVolume: 2}
+	partial := exchange.OrderDetail{Status: "partially_filled", Amount: 2,
+		OpenVolume: 1.5, AveragePrice: 100}
+	exch := newFillExchange("Fills", []exchange.OrderDetail{
+		open,
+		open,
+		partial,
+		partial,
+		{Status: "partially filled", Amount: 2, OpenVolume: 0.5, AveragePrice: 101},
+		{Status: "FILLED", Amount: 2, AveragePrice: 102},
+	}, nil)
+	cancelled := newFillExchange("Cancels", []exchange.OrderDetail{
+		{Status: "new", Amount: 1, OpenVolume: 1},
+		{Status: "canceled", Amount: 1, OpenVolume: 1},
+	}, nil)
+	unsupported := newFillExchange("NoStatus", nil, common.ErrNotYetImplemented)
+	uuid := newFillExchange("UUID", []exchange.OrderDetail{
+		{Status: "Cancelled", Amount: 1, OpenVolume: 1},
+	}, nil)
+	bot.exchanges = []exchange.IBotExchange{exch, cancelled, unsupported, uuid}
+
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	trackOrder("Fills", "", &exchange.OrderSubmission{Pair: btcusd,
+		OrderSide: exchange.Buy, Amount: 2}, "1")
+	trackOrder("Cancels", "", &exchange.OrderSubmission{Pair: btcusd,
+		OrderSide: exchange.Sell, Amount: 1}, "2")
+	trackOrder("NoStatus", "", &exchange.OrderSubmission{Pair: btcusd, Amount: 1}, "3")
+	trackOrder("Fills", "", &exchange.OrderSubmission{Pair: btcusd, Amount: 1}, "")
+	trackOrder("UUID", "", &exchange.OrderSubmission{Pair: btcusd, Amount: 1},
+		"7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01")
+
+	var notified []OrderFill
+	for x := 0; x < 8; x++ {
+		orderFills := pollTrackedOrders()
+		notifyOrderFills(orderFills)
+		notified = append(notified, orderFills...)
+	}
+
+	var filled []OrderFill
+	var cancels, uuidCancels int
+	for x := range notified {
+		switch notified[x].Exchange {
+		case "Fills":
+			filled = append(filled, notified[x])
+		case "Cancels":
+			cancels++
+			if notified[x].Status != OrderStatusCancelled || notified[x].Filled != 0 ||
+				notified[x].Remaining != 1 {
+				t.Errorf("Test failed. Unexpected cancellation %+v", notified[x])
+			}
+		case "UUID":
+			uuidCancels++
+			if notified[x].OrderID != "7b0f2f5c-8a34-4b43-a1a4-3c5e9d1f0a01" ||
+				notified[x].Status != OrderStatusCancelled {
+				t.Errorf("Test failed. Unexpected cancellation %+v", notified[x])
+			}
+		default:
+			t.Errorf("Test failed. Unexpected notification %+v", notified[x])
+		}
+	}
+	if cancels != 1 || uuidCancels != 1 {
+		t.Errorf("Test failed. Expected one cancellation per exchange, got %d and %d",
+			cancels, uuidCancels)
+	}
+
+	expected := []struct {
+		status    string
+		filled    float64
+		remaining float64
+		price     float64
+	}{
+		{OrderStatusPartiallyFilled, 0.5, 1.5, 100},
+		{OrderStatusPartiallyFilled, 1, 0.5, 101},
+		{OrderStatusFilled, 0.5, 0, 102},
+	}
+	if len(filled) != len(expected) {
+		t.Fatalf("Test failed. Expected %d fill notifications, got %+v", len(expected), filled)
+	}
+	for x := range expected {
+		if filled[x].Status != expected[x].status || filled[x].Filled != expected[x].filled ||
+			filled[x].Remaining != expected[x].remaining ||
+			filled[x].AveragePrice != expected[x].price || filled[x].OrderID != "1" ||
+			filled[x].Side != string(exchange.Buy) {
+			t.Errorf("Test failed. Fill %d expected %+v, got %+v", x, expected[x], filled[x])
+		}
+	}
+
+	trackedOrderMtx.Lock()
+	remaining := len(trackedOrders)
+	trackedOrderMtx.Unlock()
+	if remaining != 0 {
+		t.Errorf("Test failed. Expected completed orders to no longer be tracked, %d remain",
+			remaining)
+	}
+	if events := comm.pushed(); len(events) != 5 || events[0].Type != "order_fill" {
+		t.Errorf("Test failed. Unexpected events %+v", events)
+	}
+
+	// Only executions are journaled and they are reloaded from disk
+	err = LoadFillsLedger(ledgerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger, err := GetFills("", "", time.Time{}, time.Time{})
+	if err != nil || len(ledger) != 3 || ledger[2].Status != OrderStatusFilled ||
+		!ledger[0].Pair.Equal(btcusd, false) {
+		t.Errorf("Test failed. Unexpected fills ledger %+v, error: %v", ledger, err)
+	}
+}
+
+func TestGetFills(t *testing.T) {
+	resetOrderFills()
+	defer resetOrderFills()
+
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, fill := range []OrderFill{
+		{Exchange: "Bitstamp", Pair: pair.NewCurrencyPair("BTC", "USD"), Filled: 1, Timestamp: start},
+		{Exchange: "Bitstamp", Pair: pair.NewCurrencyPair("ETH", "USD"), Filled: 2, Timestamp: start.Add(time.Hour)},
+		{Exchange: "Kraken", Pair: pair.NewCurrencyPair("BTC", "USD"), Filled: 3, Timestamp: start.Add(time.Hour * 2)},
+	} {
+		err := fills.record(fill)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		exchange, currency string
+		start, end         time.Time
+		expected           []float64
+	}{
+		{"", "", time.Time{}, time.Time{}, []float64{1, 2, 3}},
+		{"bitstamp", "", time.Time{}, time.Time{}, []float64{1, 2}},
+		{"", "btc-usd", time.Time{}, time.Time{}, []float64{1, 3}},
+		{"", "", start.Add(time.Minute), time.Time{}, []float64{2, 3}},
+		{"Bitstamp", "BTCUSD", time.Time{}, start.Add(time.Minute), []float64{1}},
+		{"Bitfinex", "", time.Time{}, time.Time{}, []float64{}},
+	} {
+		result, err := GetFills(test.exchange, test.currency, test.start, test.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != len(test.expected) {
+			t.Errorf("Test failed. %s %s expected %d fills, got %d", test.exchange,
+				test.currency, len(test.expected), len(result))
+			continue
+		}
+		for x := range result {
+			if result[x].Filled != test.expected[x] {
+				t.Errorf("Test failed. %s %s unexpected fills %+v", test.exchange,
+					test.currency, result)
+			}
+		}
+	}
+
+	_, err := GetFills("", "", start.Add(time.Hour), start)
+	if err != exchange.ErrInvalidTimeRange {
+		t.Errorf("Test failed. Expected %v, got %v", exchange.ErrInvalidTimeRange, err)
+	}
+}
+
+func TestLoadFillsLedger(t *testing.T) {
+	resetOrderFills()
+	defer resetOrderFills()
+
+	dir, err := ioutil.TempDir("", "gctfills")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, fillsLedgerFile)
+
+	err = LoadFillsLedger(path)
+	if err != nil {
+		t.Fatalf("Test failed. Expected a missing ledger to load empty: %s", err)
+	}
+
+	// A partially written last entry is skipped
+	err = ioutil.WriteFile(path, []byte(`{"exchange":"Bitstamp","filled":1}`+"\n"+`{"exchange":"Bits`),
+		0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadFillsLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := GetFills("", "", time.Time{}, time.Time{})
+	if len(result) != 1 || result[0].Exchange != "Bitstamp" {
+		t.Errorf("Test failed. Unexpected fills %+v", result)
+	}
+}
+
+func TestRESTGetFills(t *testing.T) {
+	SetupTestHelpers(t)
+	resetOrderFills()
+	defer resetOrderFills()
+	err := fills.record(OrderFill{Exchange: "Bitstamp", Pair: pair.NewCurrencyPair("BTC", "USD"),
+		Filled: 1, Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	send := func(path string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := send("/fills", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	for _, path := range []string{"/fills?start=yesterday",
+		"/fills?start=2018-10-02T00:00:00Z&end=2018-10-01T00:00:00Z"} {
+		if w := send(path, true); w.Code != http.StatusBadRequest {
+			t.Errorf("Test failed. %s expected status %d, got %d", path,
+				http.StatusBadRequest, w.Code)
+		}
+	}
+
+	w := send("/fills?exchange=Bitstamp&pair=BTCUSD", true)
+	var result []OrderFill
+	err = common.JSONDecode(w.Body.Bytes(), &result)
+	if err != nil || len(result) != 1 || result[0].Filled != 1 {
+		t.Errorf("Test failed. Unexpected fills %+v, error: %v", result, err)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// haltedPairPollInterval is how often the tickers and orderbooks of pairs
+// which aren't tradable are updated, instead of on every updater run
+const haltedPairPollInterval = time.Minute * 5
+
+var (
+	haltedPairPolls   = make(map[string]time.Time)
+	haltedPairPollMtx sync.Mutex
+)
+
+// PairStatusChange holds a change in the trading status of a currency pair
+type PairStatusChange struct {
+	Exchange string            `json:"exchange"`
+	Pair     pair.CurrencyPair `json:"pair"`
+	Previous string            `json:"previous"`
+	Current  string            `json:"current"`
+}
+
+// pollablePairs returns the pairs due a market data update of the data type.
+// Pairs which aren't tradable, such as halted pairs, are only updated once
+// every haltedPairPollInterval
+func pollablePairs(exch exchange.IBotExchange, pairs []pair.CurrencyPair, dataType string) []pair.CurrencyPair {
+	haltedPairPollMtx.Lock()
+	defer haltedPairPollMtx.Unlock()
+
+	var result []pair.CurrencyPair
+	for x := range pairs {
+		key := exch.GetName() + " " + dataType + " " + pairs[x].Pair().String()
+		if exch.IsPairTradable(pairs[x]) {
+			delete(haltedPairPolls, key)
+			result = append(result, pairs[x])
+			continue
+		}
+		if time.Since(haltedPairPolls[key]) < haltedPairPollInterval {
+			continue
+		}
+		haltedPairPolls[key] = time.Now()
+		result = append(result, pairs[x])
+	}
+	return result
+}
+
+// diffPairStatuses returns the pairs whose trading status changed, pairs
+// without a previous status were trading
+func diffPairStatuses(exchName string, previous, current []exchange.PairStatus) []PairStatusChange {
+	var changes []PairStatusChange
+	for x := range current {
+		previousStatus := exchange.PairStatusTrading
+		for y := range previous {
+			if previous[y].Pair.Equal(current[x].Pair, false) {
+				previousStatus = previous[y].Status
+				break
+			}
+		}
+		if previousStatus == current[x].Status {
+			continue
+		}
+		changes = append(changes, PairStatusChange{
+			Exchange: exchName,
+			Pair:     current[x].Pair,
+			Previous: previousStatus,
+			Current:  current[x].Status,
+		})
+	}
+	return changes
+}
+
+// updatePairStatuses logs the trading status changes of an exchange's pairs
+// since the previous statuses. Enabled pairs which are delisted are notified
+// and disabled if the exchange config requests it
+func updatePairStatuses(exch exchange.IBotExchange, previous []exchange.PairStatus) error {
+	exchName := exch.GetName()
+	logger := routinesLog.With("exchange", exchName)
+	changes := diffPairStatuses(exchName, previous, exch.GetPairStatuses())
+
+	enabled := exch.GetEnabledCurrencies()
+	var delisted []pair.CurrencyPair
+	for x := range changes {
+		logger.Infof("Pair %s trading status changed from %s to %s.",
+			changes[x].Pair.Pair(), changes[x].Previous, changes[x].Current)
+		if changes[x].Current != exchange.PairStatusDelisted {
+			continue
+		}
+		for y := range enabled {
+			if enabled[y].Equal(changes[x].Pair, false) {
+				delisted = append(delisted, enabled[y])
+			}
+		}
+	}
+	if len(delisted) == 0 {
+		return nil
+	}
+
+	exchCfg, err := bot.config.GetExchangeConfig(exchName)
+	if err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("%s enabled pairs delisted: %s", exchName,
+		common.JoinStrings(pair.PairsToStringArray(delisted), ","))
+	if exchCfg.AutoDisableDelistedPairs {
+		var remaining []pair.CurrencyPair
+		for x := range enabled {
+			if !pair.Contains(delisted, enabled[x], true) {
+				remaining = append(remaining, enabled[x])
+			}
+		}
+		if len(remaining) == 0 {
+			logger.Warnf("Pair update - every enabled pair is delisted, leaving them enabled.")
+		} else {
+			err = exch.SetCurrencies(remaining, true)
+			if err != nil {
+				return err
+			}
+			MarkConfigDirty()
+			details += ", pairs disabled"
+		}
+	}
+
+	logger.Warnf("%s.", details)
+	bot.comms.PushEvent(base.Event{
+		Type:         "pair_delisted",
+		TradeDetails: details,
+	})
+	return nil
+}
+
+// GetExchangePairStatuses returns the currency pair trading statuses reported
+// by an exchange
+func GetExchangePairStatuses(name string) ([]exchange.PairStatus, error) {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return exch.GetPairStatuses(), nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+func setAutoDisableDelistedPairs(t *testing.T, exchName string, enabled bool) {
+	exchCfg, err := bot.config.GetExchangeConfig(exchName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchCfg.AutoDisableDelistedPairs = enabled
+	err = bot.config.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdatePairStatuses(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(c *communications.Communications) { bot.comms = c }(bot.comms)
+	comm := &mockComm{Base: base.Base{Name: "mock", Enabled: true, Connected: true}}
+	bot.comms = &communications.Communications{IComm: base.IComm{comm}}
+
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ltcusd := pair.NewCurrencyPair("LTC", "USD")
+	exch := newTestExchange("Bitfinex")
+	exch.enabledPairs = []pair.CurrencyPair{btcusd, ltcusd}
+	exch.availablePairs = exch.enabledPairs
+	ticker.ProcessTicker(exch.GetName(), ltcusd, ticker.Price{Last: 1}, ticker.Spot)
+	defer ticker.RemoveExchangeTickers(exch.GetName())
+
+	setAutoDisableDelistedPairs(t, exch.GetName(), false)
+	defer setAutoDisableDelistedPairs(t, exch.GetName(), false)
+	for x, test := range []struct {
+		status     string
+		autoDelist bool
+		events     int
+		enabled    bool
+	}{
+		{exchange.PairStatusHalted, false, 0, true},
+		{exchange.PairStatusDelisted, false, 1, true},
+		{exchange.PairStatusTrading, false, 1, true},
+		{exchange.PairStatusDelisted, true, 2, false},
+	} {
+		setAutoDisableDelistedPairs(t, exch.GetName(), test.autoDelist)
+		listPairs(exch, []pair.CurrencyPair{btcusd, ltcusd},
+			[]exchange.PairStatus{{Pair: ltcusd, Status: test.status}})
+		err := updateExchangePairs(exch)
+		if err != nil {
+			t.Fatalf("Test failed. updateExchangePairs error: %s", err)
+		}
+		if len(comm.pushed()) != test.events {
+			t.Errorf("Test failed. Test %d expected %d events, got %v", x, test.events,
+				comm.pushed())
+		}
+		if pair.Contains(exch.enabledPairs, ltcusd, true) != test.enabled ||
+			!pair.Contains(exch.enabledPairs, btcusd, true) {
+			t.Errorf("Test failed. Test %d unexpected enabled pairs %v", x,
+				pair.PairsToStringArray(exch.enabledPairs))
+		}
+	}
+	if events := comm.pushed(); events[0].Type != "pair_delisted" ||
+		!common.StringContains(events[1].TradeDetails, "disabled") {
+		t.Errorf("Test failed. Unexpected delisting events %+v", events)
+	}
+	if _, err := ticker.GetTicker(exch.GetName(), ltcusd, ticker.Spot); err == nil {
+		t.Error("Test failed. Ticker of the delisted pair not removed")
+	}
+}
+
+func TestPollablePairs(t *testing.T) {
+	defer func() {
+		haltedPairPollMtx.Lock()
+		haltedPairPolls = make(map[string]time.Time)
+		haltedPairPollMtx.Unlock()
+	}()
+
+	ethusd := pair.NewCurrencyPair("ETH", "USD")
+	exch := newTickerExchange("PollTest", false, nil)
+	exch.base.SetPairStatuses([]exchange.PairStatus{
+		{Pair: ethusd, Status: exchange.PairStatusHalted}})
+	for x, expected := range []int{3, 2, 2} {
+		exch.resetCalls()
+		updateExchangeTickers(exch)
+		if n := exch.callCount("UpdateTicker"); n != expected {
+			t.Errorf("Test failed. Update %d expected %d ticker requests, got %d", x,
+				expected, n)
+		}
+	}
+
+	// Halted pairs are polled again after the halted pair poll interval
+	haltedPairPollMtx.Lock()
+	for key := range haltedPairPolls {
+		haltedPairPolls[key] = time.Now().Add(-haltedPairPollInterval)
+	}
+	haltedPairPollMtx.Unlock()
+	exch.resetCalls()
+	updateExchangeTickers(exch)
+	if n := exch.callCount("UpdateTicker"); n != 3 {
+		t.Errorf("Test failed. Expected the halted pair to be polled, got %d requests",
+			n)
+	}
+
+	if pairs := pollablePairs(exch, exch.GetEnabledCurrencies(), "orderbook"); len(pairs) != 3 {
+		t.Errorf("Test failed. Expected halted pairs to be polled per data type, got %v",
+			pair.PairsToStringArray(pairs))
+	}
+}
+
+func TestRESTGetExchangePairStatuses(t *testing.T) {
+	SetupTestHelpers(t)
+	exch := newOrderExchange(nil)
+	exch.base.SetPairStatuses([]exchange.PairStatus{
+		{Pair: pair.NewCurrencyPair("XBT", "USD"), Status: exchange.PairStatusHalted}})
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{exch}
+	defer func() { bot.exchanges = exchanges }()
+
+	router := NewRouter()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exchanges/Blah/pairstatus", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exchanges/Bitmex/pairstatus", nil))
+	var statuses []exchange.PairStatus
+	err := common.JSONDecode(w.Body.Bytes(), &statuses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].Status != exchange.PairStatusHalted {
+		t.Errorf("Test failed. Unexpected pair statuses %+v", statuses)
+	}
+}
@@ -0,0 +1,189 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+)
+
+// Supported blockchain explorer names
+const (
+	ExplorerCryptoID  = "CryptoID"
+	ExplorerEthplorer = "Ethplorer"
+
+	defaultExplorerRateLimit = 1
+	defaultExplorerTimeout   = time.Second * 15
+)
+
+// ErrCoinNotSupported is returned when no balance provider is configured for
+// a coin
+var ErrCoinNotSupported = errors.New("coin not supported by balance provider")
+
+// ErrTokensNotSupported is returned when token balances can't be fetched for
+// a coin
+var ErrTokensNotSupported = errors.New("coin tokens not supported by balance provider")
+
+// BalanceProvider returns the balance of a blockchain address and the
+// balances of the tokens it holds, keyed by token symbol
+type BalanceProvider interface {
+	GetAddressBalance(coinType, address string) (float64, error)
+	GetTokenBalances(coinType, address string) (map[string]float64, error)
+}
+
+// explorer holds a blockchain explorer and its rate limited requester
+type explorer struct {
+	ExplorerSettings
+	requester *request.Requester
+}
+
+// ExplorerProvider is a BalanceProvider which fetches address balances from
+// public blockchain explorers
+type ExplorerProvider struct {
+	explorers map[string]*explorer
+}
+
+// DefaultExplorers returns the default blockchain explorers for BTC, LTC and
+// ETH
+func DefaultExplorers() []ExplorerSettings {
+	return []ExplorerSettings{
+		{Coin: "BTC", Name: ExplorerCryptoID, APIURL: cryptoIDAPIURL, RateLimit: defaultExplorerRateLimit},
+		{Coin: "LTC", Name: ExplorerCryptoID, APIURL: cryptoIDAPIURL, RateLimit: defaultExplorerRateLimit},
+		{Coin: "ETH", Name: ExplorerEthplorer, APIURL: ethplorerAPIURL, APIKey: "freekey", RateLimit: defaultExplorerRateLimit},
+	}
+}
+
+// NewExplorerProvider returns a balance provider using the supplied explorers.
+// Requests to each explorer are rate limited to its requests per second
+func NewExplorerProvider(settings []ExplorerSettings) (*ExplorerProvider, error) {
+	p := &ExplorerProvider{explorers: make(map[string]*explorer)}
+	for x := range settings {
+		coin := common.StringToUpper(settings[x].Coin)
+		if settings[x].Name != ExplorerCryptoID &&
+			settings[x].Name != ExplorerEthplorer {
+			return nil, fmt.Errorf("%s explorer %q not supported", coin,
+				settings[x].Name)
+		}
+
+		rate := settings[x].RateLimit
+		if rate <= 0 {
+			rate = defaultExplorerRateLimit
+		}
+		p.explorers[coin] = &explorer{
+			ExplorerSettings: settings[x],
+			requester: request.New(settings[x].Name+" "+coin,
+				request.NewRateLimit(time.Second, 0),
+				request.NewRateLimit(time.Second, rate),
+				common.NewHTTPClientWithTimeout(defaultExplorerTimeout)),
+		}
+	}
+	return p, nil
+}
+
+// GetAddressBalance returns the balance of an address from the explorer
+// configured for the coin
+func (p *ExplorerProvider) GetAddressBalance(coinType, address string) (float64, error) {
+	e, ok := p.explorers[common.StringToUpper(coinType)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrCoinNotSupported, coinType)
+	}
+
+	valid, _ := common.IsValidCryptoAddress(address, coinType)
+	if !valid {
+		return 0, fmt.Errorf("invalid %s address %s", coinType, address)
+	}
+
+	switch e.Name {
+	case ExplorerEthplorer:
+		result, err := e.getEthplorerAddressInfo(address)
+		if err != nil {
+			return 0, err
+		}
+		return result.ETH.Balance, nil
+	default:
+		url := fmt.Sprintf("%s/%s/api.dws?q=getbalance&a=%s", e.APIURL,
+			common.StringToLower(coinType), address)
+		if e.APIKey != "" {
+			url += "&key=" + e.APIKey
+		}
+		var result float64
+		err := e.requester.SendPayload("GET", url, nil, nil, &result, false, false)
+		if err != nil {
+			return 0, err
+		}
+		return result, nil
+	}
+}
+
+// GetTokenBalances returns the balances of the tokens held by an address from
+// the explorer configured for the coin, keyed by upper case token symbol.
+// Only Ethplorer supports token balances
+func (p *ExplorerProvider) GetTokenBalances(coinType, address string) (map[string]float64, error) {
+	e, ok := p.explorers[common.StringToUpper(coinType)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCoinNotSupported, coinType)
+	}
+	if e.Name != ExplorerEthplorer {
+		return nil, fmt.Errorf("%w: %s", ErrTokensNotSupported, coinType)
+	}
+
+	valid, _ := common.IsValidCryptoAddress(address, coinType)
+	if !valid {
+		return nil, fmt.Errorf("invalid %s address %s", coinType, address)
+	}
+
+	result, err := e.getEthplorerAddressInfo(address)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]float64)
+	for x := range result.Tokens {
+		symbol := common.StringToUpper(result.Tokens[x].TokenInfo.Symbol)
+		if symbol == "" {
+			continue
+		}
+		decimals, err := tokenDecimals(result.Tokens[x].TokenInfo.Decimals)
+		if err != nil {
+			return nil, fmt.Errorf("%s token %s: %s", coinType, symbol, err)
+		}
+		balances[symbol] += result.Tokens[x].Balance / math.Pow10(decimals)
+	}
+	return balances, nil
+}
+
+// getEthplorerAddressInfo fetches the ETH and token balances of an address
+func (e *explorer) getEthplorerAddressInfo(address string) (EthplorerResponse, error) {
+	url := fmt.Sprintf("%s/%s/%s?apiKey=%s", e.APIURL, ethplorerAddressInfo,
+		address, e.APIKey)
+	var result EthplorerResponse
+	err := e.requester.SendPayload("GET", url, nil, nil, &result, false, false)
+	if err != nil {
+		return result, err
+	}
+	if result.Error.Message != "" {
+		return result, errors.New(result.Error.Message)
+	}
+	return result, nil
+}
+
+// tokenDecimals returns the token decimals Ethplorer returned as a string or a
+// number
+func tokenDecimals(decimals interface{}) (int, error) {
+	switch d := decimals.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int(d), nil
+	case string:
+		if d == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(d)
+	}
+	return 0, fmt.Errorf("unexpected decimals type %T", decimals)
+}
@@ -0,0 +1,110 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewExplorerProvider(t *testing.T) {
+	_, err := NewExplorerProvider(DefaultExplorers())
+	if err != nil {
+		t.Fatalf("Test Failed - NewExplorerProvider() Error: %s", err)
+	}
+
+	_, err = NewExplorerProvider([]ExplorerSettings{{Coin: "BTC", Name: "Blah"}})
+	if err == nil {
+		t.Error("Test Failed - NewExplorerProvider() accepted an unsupported explorer")
+	}
+}
+
+func TestExplorerProviderGetAddressBalance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ltc/api.dws":
+			if r.URL.Query().Get("key") != "ltckey" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `13.37`)
+		case "/getAddressInfo/0xb794f5ea0ba39494ce839613fffba74279579268":
+			fmt.Fprint(w, `{"ETH":{"balance":1.5}}`)
+		default:
+			fmt.Fprint(w, `{"error":{"code":104,"message":"Invalid address format"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := NewExplorerProvider([]ExplorerSettings{
+		{Coin: "ltc", Name: ExplorerCryptoID, APIURL: srv.URL, APIKey: "ltckey"},
+		{Coin: "ETH", Name: ExplorerEthplorer, APIURL: srv.URL, RateLimit: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balance, err := p.GetAddressBalance("LTC", "LX2LMYXtuv5tiYEMztSSoEZcafFPYJFRK1")
+	if err != nil || balance != 13.37 {
+		t.Errorf("Test Failed - GetAddressBalance() LTC returned %f, %v", balance, err)
+	}
+
+	balance, err = p.GetAddressBalance("ETH", "0xb794f5ea0ba39494ce839613fffba74279579268")
+	if err != nil || balance != 1.5 {
+		t.Errorf("Test Failed - GetAddressBalance() ETH returned %f, %v", balance, err)
+	}
+
+	_, err = p.GetAddressBalance("ETH", "0xb794f5ea0ba39494ce839613fffba74279579269")
+	if err == nil {
+		t.Error("Test Failed - GetAddressBalance() ignored an explorer error")
+	}
+
+	_, err = p.GetAddressBalance("ETH", "Testy")
+	if err == nil {
+		t.Error("Test Failed - GetAddressBalance() accepted an invalid address")
+	}
+
+	_, err = p.GetAddressBalance("BTC", "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy")
+	if !errors.Is(err, ErrCoinNotSupported) {
+		t.Errorf("Test Failed - GetAddressBalance() unexpected error %v", err)
+	}
+}
+
+func TestExplorerProviderGetTokenBalances(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ETH":{"balance":1.5},"tokens":[`+
+			`{"tokenInfo":{"symbol":"usdt","decimals":"6"},"balance":2500000},`+
+			`{"tokenInfo":{"symbol":"OMG","decimals":18},"balance":3e18},`+
+			`{"tokenInfo":{"symbol":""},"balance":1}]}`)
+	}))
+	defer srv.Close()
+
+	p, err := NewExplorerProvider([]ExplorerSettings{
+		{Coin: "ETH", Name: ExplorerEthplorer, APIURL: srv.URL, RateLimit: 10},
+		{Coin: "LTC", Name: ExplorerCryptoID, APIURL: srv.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balances, err := p.GetTokenBalances("eth", "0xb794f5ea0ba39494ce839613fffba74279579268")
+	if err != nil {
+		t.Fatalf("Test Failed - GetTokenBalances() Error: %s", err)
+	}
+	if len(balances) != 2 || balances["USDT"] != 2.5 || balances["OMG"] != 3 {
+		t.Errorf("Test Failed - GetTokenBalances() unexpected balances %v", balances)
+	}
+
+	_, err = p.GetTokenBalances("LTC", "LX2LMYXtuv5tiYEMztSSoEZcafFPYJFRK1")
+	if !errors.Is(err, ErrTokensNotSupported) {
+		t.Errorf("Test Failed - GetTokenBalances() unexpected error %v", err)
+	}
+
+	_, err = p.GetTokenBalances("BTC", "1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy")
+	if !errors.Is(err, ErrCoinNotSupported) {
+		t.Errorf("Test Failed - GetTokenBalances() unexpected error %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package portfolio
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thrasher-/gocryptotrader/common"
@@ -24,6 +25,46 @@ const (
 // Portfolio is variable store holding an array of portfolioAddress
 var Portfolio Base
 
+var (
+	// ErrAddressExists is returned when registering an address which is already
+	// watched for the coin
+	ErrAddressExists = errors.New("address already exists in portfolio")
+	// ErrInvalidAddress is returned when an address doesn't match the address
+	// format of its coin
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+// tokenCoinTypes are the coins whose addresses can hold tokens sharing the
+// coin address format
+var tokenCoinTypes = []string{"ETH"}
+
+// addressFormatCoinTypes are the coins whose address format is checked
+var addressFormatCoinTypes = []string{"BTC", "LTC", "ETH"}
+
+// SupportsTokens returns whether addresses of a coin can hold tokens, such as
+// ERC-20 tokens on an ETH address
+func SupportsTokens(coinType string) bool {
+	return common.StringDataCompare(tokenCoinTypes, common.StringToUpper(coinType))
+}
+
+// ValidateAddress sanity checks the format of an address for a coin. The
+// prefix and length are checked for BTC, LTC and ETH addresses, addresses of
+// other coins only need to be non empty without whitespace
+func ValidateAddress(address, coinType string) error {
+	if address == "" || strings.ContainsAny(address, " \t\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidAddress, address)
+	}
+	if !common.StringDataCompare(addressFormatCoinTypes, common.StringToUpper(coinType)) {
+		return nil
+	}
+	valid, _ := common.IsValidCryptoAddress(address, coinType)
+	if !valid {
+		return fmt.Errorf("%w: %s address %s", ErrInvalidAddress,
+			common.StringToUpper(coinType), address)
+	}
+	return nil
+}
+
 // GetEthereumBalance single or multiple address information as
 // EtherchainBalanceResponse
 func GetEthereumBalance(address string) (EthplorerResponse, error) {
@@ -63,6 +104,8 @@ func GetCryptoIDAddress(address string, coinType string) (float64, error) {
 // GetAddressBalance acceses the portfolio base and returns the balance by passed
 // in address, coin type and description
 func (p *Base) GetAddressBalance(address, coinType, description string) (float64, bool) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == address &&
 			p.Addresses[x].Description == description &&
@@ -75,6 +118,8 @@ func (p *Base) GetAddressBalance(address, coinType, description string) (float64
 
 // ExchangeExists checks to see if an exchange exists in the portfolio base
 func (p *Base) ExchangeExists(exchangeName string) bool {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == exchangeName {
 			return true
@@ -86,6 +131,14 @@ func (p *Base) ExchangeExists(exchangeName string) bool {
 // AddressExists checks to see if there is an address associated with the
 // portfolio base
 func (p *Base) AddressExists(address string) bool {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.addressExists(address)
+}
+
+// addressExists checks to see if there is an address associated with the
+// portfolio base, the caller must hold the lock
+func (p *Base) addressExists(address string) bool {
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == address {
 			return true
@@ -97,6 +150,14 @@ func (p *Base) AddressExists(address string) bool {
 // ExchangeAddressExists checks to see if there is an exchange address
 // associated with the portfolio base
 func (p *Base) ExchangeAddressExists(exchangeName, coinType string) bool {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.exchangeAddressExists(exchangeName, coinType)
+}
+
+// exchangeAddressExists checks to see if there is an exchange address
+// associated with the portfolio base, the caller must hold the lock
+func (p *Base) exchangeAddressExists(exchangeName, coinType string) bool {
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == exchangeName && p.Addresses[x].CoinType == coinType {
 			return true
@@ -107,8 +168,16 @@ func (p *Base) ExchangeAddressExists(exchangeName, coinType string) bool {
 
 // AddExchangeAddress adds an exchange address to the portfolio base
 func (p *Base) AddExchangeAddress(exchangeName, coinType string, balance float64) {
-	if p.ExchangeAddressExists(exchangeName, coinType) {
-		p.UpdateExchangeAddressBalance(exchangeName, coinType, balance)
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.addExchangeAddress(exchangeName, coinType, balance)
+}
+
+// addExchangeAddress adds an exchange address to the portfolio base, the
+// caller must hold the lock
+func (p *Base) addExchangeAddress(exchangeName, coinType string, balance float64) {
+	if p.exchangeAddressExists(exchangeName, coinType) {
+		p.updateExchangeAddressBalance(exchangeName, coinType, balance)
 	} else {
 		p.Addresses = append(
 			p.Addresses, Address{Address: exchangeName, CoinType: coinType,
@@ -119,6 +188,14 @@ func (p *Base) AddExchangeAddress(exchangeName, coinType string, balance float64
 
 // UpdateAddressBalance updates the portfolio base balance
 func (p *Base) UpdateAddressBalance(address string, amount float64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.updateAddressBalance(address, amount)
+}
+
+// updateAddressBalance updates the portfolio base balance, the caller must
+// hold the lock
+func (p *Base) updateAddressBalance(address string, amount float64) {
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == address {
 			p.Addresses[x].Balance = amount
@@ -126,8 +203,27 @@ func (p *Base) UpdateAddressBalance(address string, amount float64) {
 	}
 }
 
+// SetAddressBalance sets the balance of an address matching the coin type and
+// description, returning whether the address was found. Unlike AddAddress a
+// zero balance doesn't remove the address
+func (p *Base) SetAddressBalance(address, coinType, description string, balance float64) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for x := range p.Addresses {
+		if p.Addresses[x].Address == address &&
+			p.Addresses[x].CoinType == coinType &&
+			p.Addresses[x].Description == description {
+			p.Addresses[x].Balance = balance
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveExchangeAddress removes an exchange address from the portfolio.
 func (p *Base) RemoveExchangeAddress(exchangeName, coinType string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == exchangeName && p.Addresses[x].CoinType == coinType {
 			p.Addresses = append(p.Addresses[:x], p.Addresses[x+1:]...)
@@ -139,6 +235,14 @@ func (p *Base) RemoveExchangeAddress(exchangeName, coinType string) {
 // UpdateExchangeAddressBalance updates the portfolio balance when checked
 // against correct exchangeName and coinType.
 func (p *Base) UpdateExchangeAddressBalance(exchangeName, coinType string, balance float64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.updateExchangeAddressBalance(exchangeName, coinType, balance)
+}
+
+// updateExchangeAddressBalance updates the portfolio balance when checked
+// against correct exchangeName and coinType, the caller must hold the lock
+func (p *Base) updateExchangeAddressBalance(exchangeName, coinType string, balance float64) {
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == exchangeName && p.Addresses[x].CoinType == coinType {
 			p.Addresses[x].Balance = balance
@@ -148,27 +252,131 @@ func (p *Base) UpdateExchangeAddressBalance(exchangeName, coinType string, balan
 
 // AddAddress adds an address to the portfolio base
 func (p *Base) AddAddress(address, coinType, description string, balance float64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
 	if description == PortfolioAddressExchange {
-		p.AddExchangeAddress(address, coinType, balance)
+		p.addExchangeAddress(address, coinType, balance)
 		return
 	}
-	if !p.AddressExists(address) {
+	if !p.addressExists(address) {
 		p.Addresses = append(
 			p.Addresses, Address{Address: address, CoinType: coinType,
 				Balance: balance, Description: description},
 		)
 	} else {
 		if balance <= 0 {
-			p.RemoveAddress(address, coinType, description)
+			p.removeAddress(address, coinType, description)
 		} else {
-			p.UpdateAddressBalance(address, balance)
+			p.updateAddressBalance(address, balance)
+		}
+	}
+}
+
+// RegisterAddress validates and adds a watched address to the portfolio base.
+// An address can only be registered once per coin, the tokens it holds are
+// tracked along with it if the coin supports tokens. The description defaults
+// to a personal address, exchange addresses are added from exchange account
+// info instead. The registered address is returned
+func (p *Base) RegisterAddress(address, coinType, description string, balance float64, tokens []string) (Address, error) {
+	coinType = common.StringToUpper(coinType)
+	if description == "" {
+		description = PortfolioAddressPersonal
+	}
+	if description == PortfolioAddressExchange {
+		return Address{}, errors.New("exchange addresses can't be registered")
+	}
+	if balance < 0 {
+		return Address{}, errors.New("balance must not be negative")
+	}
+
+	err := ValidateAddress(address, coinType)
+	if err != nil {
+		return Address{}, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for x := range p.Addresses {
+		if p.Addresses[x].Description != PortfolioAddressExchange &&
+			p.Addresses[x].CoinType == coinType &&
+			sameAddress(p.Addresses[x].Address, address, coinType) {
+			return Address{}, fmt.Errorf("%w: %s address %s", ErrAddressExists, coinType,
+				address)
 		}
 	}
+
+	if len(tokens) != 0 && !SupportsTokens(coinType) {
+		return Address{}, fmt.Errorf("%s addresses can't hold tokens", coinType)
+	}
+	var tracked []Token
+	for x := range tokens {
+		symbol := common.StringToUpper(strings.TrimSpace(tokens[x]))
+		if symbol == "" || symbol == coinType {
+			return Address{}, fmt.Errorf("invalid %s token %q", coinType, tokens[x])
+		}
+		duplicate := false
+		for y := range tracked {
+			if tracked[y].Symbol == symbol {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			tracked = append(tracked, Token{Symbol: symbol})
+		}
+	}
+
+	registered := Address{
+		Address:     address,
+		CoinType:    coinType,
+		Balance:     balance,
+		Description: description,
+		Tokens:      tracked,
+	}
+	p.Addresses = append(p.Addresses, registered)
+	registered.Tokens = append([]Token(nil), tracked...)
+	return registered, nil
+}
+
+// sameAddress compares addresses, ETH addresses are compared case
+// insensitively as their case is only a checksum
+func sameAddress(a, b, coinType string) bool {
+	if common.StringToUpper(coinType) == "ETH" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// SetTokenBalance sets the balance of a token tracked on an address matching
+// the coin type, returning whether the token was found
+func (p *Base) SetTokenBalance(address, coinType, symbol string, balance float64) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for x := range p.Addresses {
+		if p.Addresses[x].Address != address ||
+			p.Addresses[x].CoinType != coinType {
+			continue
+		}
+		for y := range p.Addresses[x].Tokens {
+			if p.Addresses[x].Tokens[y].Symbol == symbol {
+				p.Addresses[x].Tokens[y].Balance = balance
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // RemoveAddress removes an address when checked against the correct address and
 // coinType
 func (p *Base) RemoveAddress(address, coinType, description string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.removeAddress(address, coinType, description)
+}
+
+// removeAddress removes an address when checked against the correct address
+// and coinType, the caller must hold the lock
+func (p *Base) removeAddress(address, coinType, description string) {
 	for x := range p.Addresses {
 		if p.Addresses[x].Address == address && p.Addresses[x].CoinType == coinType && p.Addresses[x].Description == description {
 			p.Addresses = append(p.Addresses[:x], p.Addresses[x+1:]...)
@@ -212,6 +420,14 @@ func (p *Base) UpdatePortfolio(addresses []string, coinType string) bool {
 
 // GetPortfolioByExchange returns currency portfolio amount by exchange
 func (p *Base) GetPortfolioByExchange(exchangeName string) map[string]float64 {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.getPortfolioByExchange(exchangeName)
+}
+
+// getPortfolioByExchange returns currency portfolio amount by exchange, the
+// caller must hold the lock
+func (p *Base) getPortfolioByExchange(exchangeName string) map[string]float64 {
 	result := make(map[string]float64)
 	for x := range p.Addresses {
 		if common.StringContains(p.Addresses[x].Address, exchangeName) {
@@ -223,6 +439,14 @@ func (p *Base) GetPortfolioByExchange(exchangeName string) map[string]float64 {
 
 // GetExchangePortfolio returns current portfolio base information
 func (p *Base) GetExchangePortfolio() map[string]float64 {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.getExchangePortfolio()
+}
+
+// getExchangePortfolio returns current portfolio base information, the caller
+// must hold the lock
+func (p *Base) getExchangePortfolio() map[string]float64 {
 	result := make(map[string]float64)
 	for _, x := range p.Addresses {
 		if x.Description != PortfolioAddressExchange {
@@ -238,8 +462,17 @@ func (p *Base) GetExchangePortfolio() map[string]float64 {
 	return result
 }
 
-// GetPersonalPortfolio returns current portfolio base information
+// GetPersonalPortfolio returns current portfolio base information, the
+// balances of tracked tokens are totalled by token symbol
 func (p *Base) GetPersonalPortfolio() map[string]float64 {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.getPersonalPortfolio()
+}
+
+// getPersonalPortfolio returns current portfolio base information, the caller
+// must hold the lock
+func (p *Base) getPersonalPortfolio() map[string]float64 {
 	result := make(map[string]float64)
 	for _, x := range p.Addresses {
 		if x.Description == PortfolioAddressExchange {
@@ -251,6 +484,9 @@ func (p *Base) GetPersonalPortfolio() map[string]float64 {
 		} else {
 			result[x.CoinType] = x.Balance + balance
 		}
+		for y := range x.Tokens {
+			result[x.Tokens[y].Symbol] += x.Tokens[y].Balance
+		}
 	}
 	return result
 }
@@ -260,6 +496,9 @@ func (p *Base) GetPersonalPortfolio() map[string]float64 {
 func getPercentage(input map[string]float64, target string, totals map[string]float64) float64 {
 	subtotal := input[target]
 	total := totals[target]
+	if total == 0 {
+		return 0
+	}
 	percentage := (subtotal / total) * 100 / 1
 	return percentage
 }
@@ -268,6 +507,9 @@ func getPercentage(input map[string]float64, target string, totals map[string]fl
 // against the total coin amount.
 func getPercentageSpecific(input float64, target string, totals map[string]float64) float64 {
 	total := totals[target]
+	if total == 0 {
+		return 0
+	}
 	percentage := (input / total) * 100 / 1
 	return percentage
 }
@@ -275,8 +517,10 @@ func getPercentageSpecific(input float64, target string, totals map[string]float
 // GetPortfolioSummary returns the complete portfolio summary, showing
 // coin totals, offline and online summaries with their relative percentages.
 func (p *Base) GetPortfolioSummary() Summary {
-	personalHoldings := p.GetPersonalPortfolio()
-	exchangeHoldings := p.GetExchangePortfolio()
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	personalHoldings := p.getPersonalPortfolio()
+	exchangeHoldings := p.getExchangePortfolio()
 	totalCoins := make(map[string]float64)
 
 	for x, y := range personalHoldings {
@@ -328,7 +572,7 @@ func (p *Base) GetPortfolioSummary() Summary {
 	exchangeSummary := make(map[string]map[string]OnlineCoinSummary)
 	for x := range portfolioExchanges {
 		exchgName := portfolioExchanges[x]
-		result := p.GetPortfolioByExchange(exchgName)
+		result := p.getPortfolioByExchange(exchgName)
 
 		coinSummary := make(map[string]OnlineCoinSummary)
 		for y, z := range result {
@@ -352,6 +596,14 @@ func (p *Base) GetPortfolioSummary() Summary {
 				Percentage: getPercentageSpecific(x.Balance, x.CoinType,
 					totalCoins),
 			}
+			for y := range x.Tokens {
+				coinSummary.Tokens = append(coinSummary.Tokens, Coin{
+					Coin:    x.Tokens[y].Symbol,
+					Balance: x.Tokens[y].Balance,
+					Percentage: getPercentageSpecific(x.Tokens[y].Balance,
+						x.Tokens[y].Symbol, totalCoins),
+				})
+			}
 			result, ok := offlineSummary[x.CoinType]
 			if !ok {
 				offlineSummary[x.CoinType] = append(offlineSummary[x.CoinType],
@@ -368,6 +620,8 @@ func (p *Base) GetPortfolioSummary() Summary {
 
 // GetPortfolioGroupedCoin returns portfolio base information grouped by coin
 func (p *Base) GetPortfolioGroupedCoin() map[string][]string {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
 	result := make(map[string][]string)
 	for _, x := range p.Addresses {
 		if common.StringContains(x.Description, PortfolioAddressExchange) {
@@ -378,15 +632,34 @@ func (p *Base) GetPortfolioGroupedCoin() map[string][]string {
 	return result
 }
 
+// GetAddresses returns a copy of the portfolio base addresses along with the
+// tokens tracked on them
+func (p *Base) GetAddresses() []Address {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	if p.Addresses == nil {
+		return nil
+	}
+	addresses := make([]Address, len(p.Addresses))
+	for x := range p.Addresses {
+		addresses[x] = p.Addresses[x]
+		addresses[x].Tokens = append([]Token(nil), p.Addresses[x].Tokens...)
+	}
+	return addresses
+}
+
 // SeedPortfolio appends a portfolio base object with another base portfolio
 // addresses
-func (p *Base) SeedPortfolio(port Base) {
-	p.Addresses = port.Addresses
+func (p *Base) SeedPortfolio(port *Base) {
+	addresses := port.GetAddresses()
+	p.mtx.Lock()
+	p.Addresses = addresses
+	p.mtx.Unlock()
 }
 
 // StartPortfolioWatcher observes the portfolio object
 func StartPortfolioWatcher() {
-	addrCount := len(Portfolio.Addresses)
+	addrCount := len(Portfolio.GetAddresses())
 	log.Debugf(
 		"PortfolioWatcher started: Have %d entries in portfolio.\n", addrCount,
 	)
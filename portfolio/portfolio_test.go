@@ -1,7 +1,9 @@
 package portfolio
 
 import (
+	"errors"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
@@ -57,6 +59,158 @@ func TestGetAddressBalance(t *testing.T) {
 	}
 }
 
+func TestSetAddressBalance(t *testing.T) {
+	ltcAddress := "LdP8Qox1VAhCzLJNqrr74YovaWYyNBUWvL"
+	portfolio := Base{}
+	portfolio.AddAddress(ltcAddress, "LTC", PortfolioAddressPersonal, 1000)
+
+	if !portfolio.SetAddressBalance(ltcAddress, "LTC", PortfolioAddressPersonal, 0) {
+		t.Fatal("Test Failed - Portfolio SetAddressBalance() Error: Address not found")
+	}
+
+	balance, found := portfolio.GetAddressBalance(ltcAddress, "LTC", PortfolioAddressPersonal)
+	if !found || balance != 0 {
+		t.Error("Test Failed - Portfolio SetAddressBalance() Error: Incorrect value")
+	}
+
+	if portfolio.SetAddressBalance(ltcAddress, "BTC", PortfolioAddressPersonal, 1) {
+		t.Error("Test Failed - Portfolio SetAddressBalance() Error: Incorrect coin type matched")
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	for address, coinType := range map[string]string{
+		"1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy":         "BTC",
+		"LX2LMYXtuv5tiYEMztSSoEZcafFPYJFRK1":         "ltc",
+		"0xb794f5ea0ba39494ce839613fffba74279579268": "ETH",
+		"DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L":         "DOGE",
+	} {
+		if err := ValidateAddress(address, coinType); err != nil {
+			t.Errorf("Test Failed - ValidateAddress() Error: %s", err)
+		}
+	}
+
+	for address, coinType := range map[string]string{
+		"":                                          "DOGE",
+		"D H5yaieqoZN36fDVciNyRueRGvGLR3mr7":        "DOGE",
+		"LX2LMYXtuv5tiYEMztSSoEZcafFPYJFRK1":        "BTC",
+		"1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy":        "ETH",
+		"0xb794f5ea0ba39494ce839613fffba7427957926": "ETH",
+	} {
+		err := ValidateAddress(address, coinType)
+		if !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("Test Failed - ValidateAddress() %s address %q unexpected error %v",
+				coinType, address, err)
+		}
+	}
+}
+
+func TestRegisterAddress(t *testing.T) {
+	const ethAddress = "0xb794f5ea0ba39494ce839613fffba74279579268"
+	newBase := Base{}
+	registered, err := newBase.RegisterAddress(ethAddress, "eth", "", 1, []string{"usdt", "OMG", "USDT"})
+	if err != nil {
+		t.Fatalf("Test Failed - RegisterAddress() Error: %s", err)
+	}
+	address := newBase.Addresses[0]
+	if !reflect.DeepEqual(registered, address) {
+		t.Errorf("Test Failed - RegisterAddress() returned %+v, stored %+v", registered, address)
+	}
+	registered.Tokens[0].Balance = 5
+	if newBase.Addresses[0].Tokens[0].Balance != 0 {
+		t.Error("Test Failed - RegisterAddress() returned the stored tokens")
+	}
+	if address.CoinType != "ETH" || address.Description != PortfolioAddressPersonal ||
+		len(address.Tokens) != 2 || address.Tokens[0].Symbol != "USDT" ||
+		address.Tokens[1].Symbol != "OMG" {
+		t.Errorf("Test Failed - RegisterAddress() unexpected address %+v", address)
+	}
+
+	_, err = newBase.RegisterAddress("0xB794F5EA0BA39494CE839613FFFBA74279579268", "ETH",
+		"", 0, []string{"DAI"})
+	if !errors.Is(err, ErrAddressExists) {
+		t.Errorf("Test Failed - RegisterAddress() duplicate unexpected error %v", err)
+	}
+
+	newBase.AddExchangeAddress("Bitfinex", "BTC", 1)
+	_, err = newBase.RegisterAddress("1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy", "BTC", "", 1, nil)
+	if err != nil {
+		t.Errorf("Test Failed - RegisterAddress() Error: %s", err)
+	}
+
+	for _, args := range []struct {
+		address, coinType, description string
+		tokens                         []string
+	}{
+		{"Testy", "BTC", "", nil},
+		{"1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy", "BTC", "", nil},
+		{"1Mz7153HMuxXTuR2R1t78mGSdzaAtNbBWX", "BTC", "", []string{"USDT"}},
+		{"0x52908400098527886E0F7030069857D2E4169EE7", "ETH", "", []string{"ETH"}},
+		{"0x52908400098527886E0F7030069857D2E4169EE7", "ETH", "", []string{" "}},
+		{"Bitstamp", "BTC", PortfolioAddressExchange, nil},
+	} {
+		_, err = newBase.RegisterAddress(args.address, args.coinType, args.description, 0,
+			args.tokens)
+		if err == nil {
+			t.Errorf("Test Failed - RegisterAddress() %+v expected an error", args)
+		}
+	}
+	if len(newBase.Addresses) != 3 {
+		t.Errorf("Test Failed - RegisterAddress() unexpected addresses %v", newBase.Addresses)
+	}
+
+	if !newBase.SetTokenBalance(ethAddress, "ETH", "OMG", 5) ||
+		newBase.Addresses[0].Tokens[1].Balance != 5 {
+		t.Error("Test Failed - SetTokenBalance() balance not set")
+	}
+	if newBase.SetTokenBalance(ethAddress, "ETH", "DAI", 5) {
+		t.Error("Test Failed - SetTokenBalance() untracked token set")
+	}
+}
+
+func TestGetPortfolioSummaryTokens(t *testing.T) {
+	newBase := Base{}
+	for address, tokens := range map[string][]Token{
+		"0xb794f5ea0ba39494ce839613fffba74279579268": {{Symbol: "USDT", Balance: 30}, {Symbol: "OMG", Balance: 0}},
+		"0x52908400098527886E0F7030069857D2E4169EE7": {{Symbol: "USDT", Balance: 10}},
+	} {
+		newBase.Addresses = append(newBase.Addresses, Address{Address: address,
+			CoinType: "ETH", Balance: 1, Description: PortfolioAddressPersonal,
+			Tokens: tokens})
+	}
+	newBase.AddExchangeAddress("Bitfinex", "USDT", 60)
+
+	summary := newBase.GetPortfolioSummary()
+	totals := make(map[string]float64)
+	for x := range summary.Totals {
+		totals[summary.Totals[x].Coin] = summary.Totals[x].Balance
+	}
+	if totals["ETH"] != 2 || totals["USDT"] != 100 || totals["OMG"] != 0 {
+		t.Errorf("Test Failed - GetPortfolioSummary() unexpected totals %v", totals)
+	}
+
+	offline := summary.OfflineSummary["ETH"]
+	if len(offline) != 2 || len(summary.OfflineSummary) != 1 {
+		t.Fatalf("Test Failed - GetPortfolioSummary() unexpected offline summary %v",
+			summary.OfflineSummary)
+	}
+	for x := range offline {
+		if offline[x].Percentage != 50 {
+			t.Errorf("Test Failed - GetPortfolioSummary() unexpected ETH percentage %f",
+				offline[x].Percentage)
+		}
+		if offline[x].Address != "0xb794f5ea0ba39494ce839613fffba74279579268" {
+			continue
+		}
+		if len(offline[x].Tokens) != 2 || offline[x].Tokens[0].Coin != "USDT" ||
+			offline[x].Tokens[0].Balance != 30 || offline[x].Tokens[0].Percentage != 30 ||
+			offline[x].Tokens[1].Percentage != 0 {
+			t.Errorf("Test Failed - GetPortfolioSummary() unexpected tokens %v",
+				offline[x].Tokens)
+		}
+	}
+}
+
 func TestExchangeExists(t *testing.T) {
 	newBase := Base{}
 	newBase.AddAddress("someaddress", "LTC", "LTCWALLETTEST", 0.02)
@@ -147,7 +301,7 @@ func TestUpdateExchangeAddressBalance(t *testing.T) {
 	newbase := Base{}
 	newbase.AddExchangeAddress("someaddress", "LTC", 0.02)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	portfolio.UpdateExchangeAddressBalance("someaddress", "LTC", 0.04)
 
 	value := portfolio.GetPortfolioSummary()
@@ -160,7 +314,7 @@ func TestAddAddress(t *testing.T) {
 	newbase := Base{}
 	newbase.AddAddress("Gibson", "LTC", "LTCWALLETTEST", 0.02)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	if !portfolio.AddressExists("Gibson") {
 		t.Error("Test Failed - portfolio_test.go - AddAddress error")
 	}
@@ -177,7 +331,7 @@ func TestUpdatePortfolio(t *testing.T) {
 	newbase := Base{}
 	newbase.AddAddress("someaddress", "LTC", "LTCWALLETTEST", 0.02)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 
 	value := portfolio.UpdatePortfolio(
 		[]string{"LdP8Qox1VAhCzLJNqrr74YovaWYyNBUWvL"}, "LTC",
@@ -232,7 +386,7 @@ func TestGetPortfolioByExchange(t *testing.T) {
 	newbase.AddExchangeAddress("Bitfinex", "LTC", 0.05)
 	newbase.AddAddress("someaddress", "LTC", PortfolioAddressPersonal, 0.03)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	value := portfolio.GetPortfolioByExchange("ANX")
 	result, ok := value["LTC"]
 	if !ok {
@@ -260,7 +414,7 @@ func TestGetExchangePortfolio(t *testing.T) {
 	newbase.AddAddress("Bitfinex", "LTC", PortfolioAddressExchange, 0.05)
 	newbase.AddAddress("someaddress", "LTC", PortfolioAddressPersonal, 0.03)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	value := portfolio.GetExchangePortfolio()
 
 	result, ok := value["LTC"]
@@ -279,7 +433,7 @@ func TestGetPersonalPortfolio(t *testing.T) {
 	newbase.AddAddress("anotheraddress", "LTC", "LTCWALLETTEST", 0.03)
 	newbase.AddAddress("Exchange", "LTC", PortfolioAddressExchange, 0.01)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	value := portfolio.GetPersonalPortfolio()
 	result, ok := value["LTC"]
 	if !ok {
@@ -308,7 +462,7 @@ func TestGetPortfolioSummary(t *testing.T) {
 	newbase.AddExchangeAddress("ANX", "ETH", 42)
 
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	value := portfolio.GetPortfolioSummary()
 
 	getTotalsVal := func(s string) Coin {
@@ -342,7 +496,7 @@ func TestGetPortfolioGroupedCoin(t *testing.T) {
 	newbase.AddAddress("someaddress", "LTC", "LTCWALLETTEST", 0.02)
 	newbase.AddAddress("Exchange", "LTC", PortfolioAddressExchange, 0.05)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 	value := portfolio.GetPortfolioGroupedCoin()
 	if value["LTC"][0] != "someaddress" && len(value["LTC"][0]) != 1 {
 		t.Error("Test Failed - portfolio_test.go - GetPortfolioGroupedCoin error")
@@ -353,19 +507,64 @@ func TestSeedPortfolio(t *testing.T) {
 	newbase := Base{}
 	newbase.AddAddress("someaddress", "LTC", "LTCWALLETTEST", 0.02)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newbase)
+	portfolio.SeedPortfolio(&newbase)
 
 	if !portfolio.AddressExists("someaddress") {
 		t.Error("Test Failed - portfolio_test.go - SeedPortfolio error")
 	}
 }
 
+func TestGetAddresses(t *testing.T) {
+	var newBase Base
+	_, err := newBase.RegisterAddress("0xb794f5ea0ba39494ce839613fffba74279579268",
+		"ETH", "", 1, []string{"OMG"})
+	if err != nil {
+		t.Fatalf("Test Failed - RegisterAddress() error: %s", err)
+	}
+
+	addresses := newBase.GetAddresses()
+	addresses[0].Balance = 5
+	addresses[0].Tokens[0].Balance = 5
+	if balance, _ := newBase.GetAddressBalance(addresses[0].Address, "ETH",
+		PortfolioAddressPersonal); balance != 1 ||
+		newBase.Addresses[0].Tokens[0].Balance != 0 {
+		t.Error("Test Failed - GetAddresses() returned the stored addresses")
+	}
+}
+
+func TestBaseConcurrentAccess(t *testing.T) {
+	var newBase Base
+	var wg sync.WaitGroup
+	for x := 0; x < 10; x++ {
+		wg.Add(2)
+		go func(x int) {
+			defer wg.Done()
+			newBase.AddExchangeAddress("Bitfinex", "BTC", float64(x+1))
+			newBase.SetAddressBalance("Bitfinex", "BTC", PortfolioAddressExchange,
+				float64(x))
+			newBase.RemoveExchangeAddress("Bitfinex", "LTC")
+		}(x)
+		go func() {
+			defer wg.Done()
+			newBase.GetPortfolioSummary()
+			newBase.GetPortfolioGroupedCoin()
+			newBase.GetAddresses()
+		}()
+	}
+	wg.Wait()
+
+	if !newBase.ExchangeAddressExists("Bitfinex", "BTC") ||
+		len(newBase.GetAddresses()) != 1 {
+		t.Errorf("Test Failed - unexpected addresses %v", newBase.GetAddresses())
+	}
+}
+
 func TestStartPortfolioWatcher(t *testing.T) {
 	newBase := Base{}
 	newBase.AddAddress("LX2LMYXtuv5tiYEMztSSoEZcafFPYJFRK1", "LTC", PortfolioAddressPersonal, 0.02)
 	newBase.AddAddress("Testy", "LTC", PortfolioAddressPersonal, 0.02)
 	portfolio := GetPortfolio()
-	portfolio.SeedPortfolio(newBase)
+	portfolio.SeedPortfolio(&newBase)
 
 	if !portfolio.AddressExists("LX2LMYXtuv5tiYEMztSSoEZcafFPYJFRK1") {
 		t.Error("Test Failed - portfolio_test.go - TestStartPortfolioWatcher")
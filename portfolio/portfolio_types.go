@@ -1,8 +1,11 @@
 package portfolio
 
+import "sync"
+
 // Base holds the portfolio base addresses
 type Base struct {
 	Addresses []Address
+	mtx       sync.RWMutex
 }
 
 // Address sub type holding address information for portfolio
@@ -11,6 +14,16 @@ type Address struct {
 	CoinType    string
 	Balance     float64
 	Description string
+	// Tokens holds the balances of the tokens tracked on an address of a coin
+	// which shares its address format with its tokens, such as ERC-20 tokens
+	// on an ETH address
+	Tokens []Token `json:",omitempty"`
+}
+
+// Token holds the balance of a token tracked on a portfolio address
+type Token struct {
+	Symbol  string
+	Balance float64
 }
 
 // EtherchainBalanceResponse holds JSON incoming and outgoing data for
@@ -63,12 +76,25 @@ type EthplorerResponse struct {
 			Currency string `json:"currency"`
 		} `json:"price"`
 	} `json:"tokenInfo"`
-	Error struct {
+	Tokens []EthplorerToken `json:"tokens"`
+	Error  struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 	} `json:"error"`
 }
 
+// EthplorerToken holds the raw balance of a token held by an address, the
+// balance is scaled by the token decimals which Ethplorer returns as either a
+// string or a number
+type EthplorerToken struct {
+	TokenInfo struct {
+		Address  string      `json:"address"`
+		Symbol   string      `json:"symbol"`
+		Decimals interface{} `json:"decimals"`
+	} `json:"tokenInfo"`
+	Balance float64 `json:"balance"`
+}
+
 // ExchangeAccountInfo : Generic type to hold each exchange's holdings in all
 // enabled currencies
 type ExchangeAccountInfo struct {
@@ -98,6 +124,9 @@ type OfflineCoinSummary struct {
 	Address    string  `json:"address"`
 	Balance    float64 `json:"balance"`
 	Percentage float64 `json:"percentage,omitempty"`
+	// Tokens holds the tracked token balances of the address, each with its
+	// percentage relative to the total amount of the token
+	Tokens []Coin `json:"tokens,omitempty"`
 }
 
 // OnlineCoinSummary stores a coin types balance and percentage relative to the
@@ -115,3 +144,13 @@ type Summary struct {
 	Online         []Coin                                  `json:"coins_online"`
 	OnlineSummary  map[string]map[string]OnlineCoinSummary `json:"online_summary"`
 }
+
+// ExplorerSettings holds the public blockchain explorer used to refresh the
+// balance of watched addresses for a coin
+type ExplorerSettings struct {
+	Coin      string `json:"coin"`
+	Name      string `json:"name"`
+	APIURL    string `json:"apiURL"`
+	APIKey    string `json:"apiKey,omitempty"`
+	RateLimit int    `json:"rateLimit"`
+}
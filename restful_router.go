@@ -1,28 +1,45 @@
 package main
 
 import (
+	"expvar"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
-	log "github.com/thrasher-/gocryptotrader/logger"
 )
 
-// RESTLogger logs the requests internally
+// RESTLogger logs the requests internally and records their stats, requests
+// slower than the configured threshold are logged as warnings
 func RESTLogger(inner http.Handler, name string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
 
-		inner.ServeHTTP(w, r)
+		inner.ServeHTTP(recorder, r)
+
+		duration := time.Since(start)
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		_, slowThreshold := restRequestSettings()
+		slow := slowThreshold > 0 && duration > slowThreshold
 
-		log.Debugf(
-			"%s\t%s\t%s\t%s",
-			r.Method,
-			r.RequestURI,
-			name,
-			time.Since(start),
-		)
+		recordRESTRequest(name, status, duration, slow)
+		logRESTRequest(r, name, status, duration, slow)
+	})
+}
+
+// RESTRejectWhileStopping rejects requests once the bot has started shutting
+// down
+func RESTRejectWhileStopping(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsStopping() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		inner.ServeHTTP(w, r)
 	})
 }
 
@@ -51,6 +68,18 @@ func NewRouter() *mux.Router {
 			"/",
 			getIndex,
 		},
+		Route{
+			"GetInfo",
+			"GET",
+			"/info",
+			RESTGetInfo,
+		},
+		Route{
+			"GetSettings",
+			"GET",
+			"/settings",
+			RESTGetSettings,
+		},
 		Route{
 			"GetAllSettings",
 			"GET",
@@ -63,12 +92,138 @@ func NewRouter() *mux.Router {
 			"/config/all/save",
 			RESTSaveAllSettings,
 		},
+		Route{
+			"ChangeConfigEncryptionKey",
+			"POST",
+			"/config/encryptionkey",
+			RESTChangeConfigEncryptionKey,
+		},
+		Route{
+			"GetConfigSection",
+			"GET",
+			"/config/section/{section}",
+			RESTGetConfigSection,
+		},
+		Route{
+			"UpdateConfigSection",
+			"POST",
+			"/config/section/{section}",
+			RESTUpdateConfigSection,
+		},
 		Route{
 			"AllEnabledAccountInfo",
 			"GET",
 			"/exchanges/enabled/accounts/all",
 			RESTGetAllEnabledAccountInfo,
 		},
+		Route{
+			"IndividualExchangeAccountInfo",
+			"GET",
+			"/exchanges/{exchangeName}/accounts",
+			RESTGetExchangeAccountInfo,
+		},
+		Route{
+			"IndividualExchangeOrderLimits",
+			"GET",
+			"/exchanges/{exchangeName}/limits",
+			RESTGetExchangeOrderExecutionLimits,
+		},
+		Route{
+			"IndividualExchangePairStatuses",
+			"GET",
+			"/exchanges/{exchangeName}/pairstatus",
+			RESTGetExchangePairStatuses,
+		},
+		Route{
+			"IndividualExchangeLatency",
+			"GET",
+			"/exchanges/{exchangeName}/latency",
+			RESTGetExchangeLatency,
+		},
+		Route{
+			"IndividualExchangeServerTime",
+			"GET",
+			"/exchanges/{exchangeName}/servertime",
+			RESTGetExchangeServerTime,
+		},
+		Route{
+			"IndividualExchangeCurrencies",
+			"GET",
+			"/exchanges/{exchangeName}/currencies",
+			RESTGetCryptocurrencyInfo,
+		},
+		Route{
+			"IndividualExchangeDepositHistory",
+			"GET",
+			"/exchanges/{exchangeName}/deposits",
+			RESTGetExchangeDepositHistory,
+		},
+		Route{
+			"TestExchangeConnectivity",
+			"GET",
+			"/exchanges/{exchangeName}/connectivity",
+			RESTCheckExchangeConnectivity,
+		},
+		Route{
+			"SubmitOrder",
+			"POST",
+			"/exchanges/{exchangeName}/orders",
+			RESTSubmitOrder,
+		},
+		Route{
+			"GetFills",
+			"GET",
+			"/fills",
+			RESTGetFills,
+		},
+		Route{
+			"GetExposureReport",
+			"GET",
+			"/exposure",
+			RESTGetExposureReport,
+		},
+		Route{
+			"CancelAllOrders",
+			"POST",
+			"/orders/cancelall",
+			RESTCancelAllOrders,
+		},
+		Route{
+			"TransferBetweenExchanges",
+			"POST",
+			"/transfers",
+			RESTTransferBetweenExchanges,
+		},
+		Route{
+			"IndividualExchangeAssetTypes",
+			"GET",
+			"/exchanges/{exchangeName}/assets",
+			RESTGetExchangeAssetTypes,
+		},
+		Route{
+			"SetExchangeAssetType",
+			"POST",
+			"/exchanges/{exchangeName}/assets/{assetType}/{action:enable|disable}",
+			RESTSetExchangeAssetType,
+		},
+		Route{
+			"SetExchangeWebsocket",
+			"POST",
+			"/exchanges/{exchangeName}/websocket/{action:enable|disable}",
+			RESTSetExchangeWebsocket,
+		},
+		Route{
+			"SetExchangeEndpoint",
+			"POST",
+			"/exchanges/{exchangeName}/endpoints",
+			RESTSetExchangeEndpoint,
+		},
+		Route{
+			"ExchangeHealth",
+			"GET",
+			"/exchanges/health",
+			RESTGetExchangeHealth,
+		},
 		Route{
 			"AllActiveExchangesAndCurrencies",
 			"GET",
@@ -81,12 +236,54 @@ func NewRouter() *mux.Router {
 			"/exchanges/{exchangeName}/latest/{currency}",
 			RESTGetTicker,
 		},
+		Route{
+			"GetStats",
+			"GET",
+			"/stats/{currency}",
+			RESTGetStats,
+		},
+		Route{
+			"SimulateOrder",
+			"GET",
+			"/simulate/{currency}",
+			RESTSimulateOrder,
+		},
+		Route{
+			"BestExecutionVenue",
+			"GET",
+			"/bestexecution/{currency}",
+			RESTGetBestExecutionVenue,
+		},
+		Route{
+			"GetConsolidatedOrderbook",
+			"GET",
+			"/orderbook/consolidated/{currency}",
+			RESTGetConsolidatedOrderbook,
+		},
 		Route{
 			"GetPortfolio",
 			"GET",
 			"/portfolio/all",
 			RESTGetPortfolio,
 		},
+		Route{
+			"AddPortfolioAddress",
+			"POST",
+			"/portfolio/addresses",
+			RESTAddPortfolioAddress,
+		},
+		Route{
+			"GetForexRates",
+			"GET",
+			"/forex/rates",
+			RESTGetForexRates,
+		},
+		Route{
+			"ConvertCurrency",
+			"GET",
+			"/forex/convert",
+			RESTConvertCurrency,
+		},
 		Route{
 			"AllActiveExchangesAndOrderbooks",
 			"GET",
@@ -99,6 +296,66 @@ func NewRouter() *mux.Router {
 			"/exchanges/{exchangeName}/orderbook/latest/{currency}",
 			RESTGetOrderbook,
 		},
+		Route{
+			"IndividualExchangeRecentTrades",
+			"GET",
+			"/exchanges/{exchangeName}/trades/recent/{currency}",
+			RESTGetRecentTrades,
+		},
+		Route{
+			"IndividualExchangeTradeStats",
+			"GET",
+			"/exchanges/{exchangeName}/trades/stats/{currency}",
+			RESTGetTradeStats,
+		},
+		Route{
+			"Events",
+			"GET",
+			"/events",
+			RESTGetEvents,
+		},
+		Route{
+			"AddEvent",
+			"POST",
+			"/events",
+			RESTAddEvent,
+		},
+		Route{
+			"StartBackfill",
+			"POST",
+			"/backfill",
+			RESTStartBackfill,
+		},
+		Route{
+			"BackfillJobs",
+			"GET",
+			"/backfill",
+			RESTGetBackfillJobs,
+		},
+		Route{
+			"IndividualBackfillJob",
+			"GET",
+			"/backfill/{id}",
+			RESTGetBackfillJob,
+		},
+		Route{
+			"CancelBackfill",
+			"POST",
+			"/backfill/{id}/cancel",
+			RESTCancelBackfill,
+		},
+		Route{
+			"GetRequestStats",
+			"GET",
+			"/requeststats",
+			RESTGetRequestStats,
+		},
+		Route{
+			"Shutdown",
+			"POST",
+			"/shutdown",
+			RESTShutdown,
+		},
 		Route{
 			"ws",
 			"GET",
@@ -107,12 +364,22 @@ func NewRouter() *mux.Router {
 		},
 	}
 
+	if bot.config != nil && bot.config.Webserver.DebugVarsEnabled {
+		publishRESTStatsVar()
+		routes = append(routes, Route{
+			"DebugVars",
+			"GET",
+			"/debug/vars",
+			expvar.Handler().ServeHTTP,
+		})
+	}
+
 	for _, route := range routes {
 		router.
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			Handler(RESTLogger(route.HandlerFunc, route.Name))
+			Handler(RESTRejectWhileStopping(RESTLogger(route.HandlerFunc, route.Name)))
 	}
 	return router
 }
@@ -1,15 +1,32 @@
 package main
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/gorilla/mux"
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/assets"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
 	log "github.com/thrasher-/gocryptotrader/logger"
+	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
 // AllEnabledExchangeOrderbooks holds the enabled exchange orderbooks
@@ -26,7 +43,86 @@ type EnabledExchangeOrderbooks struct {
 
 // AllEnabledExchangeCurrencies holds the enabled exchange currencies
 type AllEnabledExchangeCurrencies struct {
-	Data []EnabledExchangeCurrencies `json:"data"`
+	Data          []EnabledExchangeCurrencies `json:"data"`
+	NextPageToken string                      `json:"nextPageToken,omitempty"`
+}
+
+const (
+	// defaultTickersPageLimit is the number of tickers returned per page when
+	// no limit is requested
+	defaultTickersPageLimit = 1000
+	// maxTickersPageLimit is the largest number of tickers returned per page
+	maxTickersPageLimit = 5000
+)
+
+var errInvalidTickersPageToken = errors.New("invalid page token")
+
+// TickerFilter selects tickers by exchange, pair and asset type, an empty
+// field matches every ticker. Exchange names and asset types are matched case
+// insensitively and pairs are matched by their currencies regardless of
+// delimiter and case
+type TickerFilter struct {
+	Exchanges []string `json:"exchanges,omitempty"`
+	Pairs     []string `json:"pairs,omitempty"`
+	AssetType string   `json:"assetType,omitempty"`
+}
+
+// Validate checks the filter pairs have currencies
+func (f *TickerFilter) Validate() error {
+	for x := range f.Pairs {
+		if len(pairSymbol(f.Pairs[x])) < 2 {
+			return fmt.Errorf("invalid currency pair %q", f.Pairs[x])
+		}
+	}
+	return nil
+}
+
+// matchExchange returns whether the filter matches an exchange name
+func (f *TickerFilter) matchExchange(name string) bool {
+	if len(f.Exchanges) == 0 {
+		return true
+	}
+	for x := range f.Exchanges {
+		if strings.EqualFold(f.Exchanges[x], name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPair returns whether the filter matches a currency pair either as
+// listed by the exchange or in its canonical form, so XBTUSD also matches
+// BTCUSD
+func (f *TickerFilter) matchPair(p pair.CurrencyPair) bool {
+	if len(f.Pairs) == 0 {
+		return true
+	}
+	c := newCanonicalPair(p)
+	listed := pairSymbol(p.FirstCurrency.String() + p.SecondCurrency.String())
+	canonical := pairSymbol(c.first.String() + c.second.String())
+	for x := range f.Pairs {
+		symbol := pairSymbol(f.Pairs[x])
+		if symbol == listed || symbol == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAssetType returns whether the filter matches an asset type
+func (f *TickerFilter) matchAssetType(assetType string) bool {
+	return f.AssetType == "" || strings.EqualFold(f.AssetType, assetType)
+}
+
+// pairSymbol returns a currency pair string upper cased without delimiters,
+// so BTC-USD, btc_usd and BTCUSD match
+func pairSymbol(p string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, p)
 }
 
 // EnabledExchangeCurrencies is a sub type for singular exchanges and respective
@@ -36,6 +132,57 @@ type EnabledExchangeCurrencies struct {
 	ExchangeValues []ticker.Price `json:"exchangeValues"`
 }
 
+// TickerConversion holds ticker values converted into the fiat display
+// currency
+type TickerConversion struct {
+	DisplayCurrency string  `json:"displayCurrency,omitempty"`
+	Rate            float64 `json:"rate,omitempty"`
+	Last            float64 `json:"convertedLast,omitempty"`
+	Bid             float64 `json:"convertedBid,omitempty"`
+	Ask             float64 `json:"convertedAsk,omitempty"`
+}
+
+// DisplayTicker holds a ticker price and its fiat display currency conversion
+type DisplayTicker struct {
+	ticker.Price
+	Conversion TickerConversion `json:"conversion"`
+}
+
+// AllEnabledExchangeDisplayTickers holds the enabled exchange tickers converted
+// into the fiat display currency
+type AllEnabledExchangeDisplayTickers struct {
+	Data          []EnabledExchangeDisplayTickers `json:"data"`
+	NextPageToken string                          `json:"nextPageToken,omitempty"`
+}
+
+// EnabledExchangeDisplayTickers is a sub type for singular exchanges and
+// respective converted tickers
+type EnabledExchangeDisplayTickers struct {
+	ExchangeName   string          `json:"exchangeName"`
+	ExchangeValues []DisplayTicker `json:"exchangeValues"`
+}
+
+// TickerResponse holds a ticker and whether it was served from the cache
+// rather than fetched from the exchange
+type TickerResponse struct {
+	ticker.Price
+	FromCache bool `json:"fromCache"`
+}
+
+// DisplayTickerResponse holds a converted ticker and whether it was served
+// from the cache rather than fetched from the exchange
+type DisplayTickerResponse struct {
+	DisplayTicker
+	FromCache bool `json:"fromCache"`
+}
+
+// OrderbookResponse holds an orderbook and whether it was served from the
+// cache rather than fetched from the exchange
+type OrderbookResponse struct {
+	orderbook.Base
+	FromCache bool `json:"fromCache"`
+}
+
 // AllEnabledExchangeAccounts holds all enabled accounts info
 type AllEnabledExchangeAccounts struct {
 	Data []exchange.AccountInfo `json:"data"`
@@ -48,12 +195,65 @@ func RESTfulJSONResponse(w http.ResponseWriter, response interface{}) error {
 	return json.NewEncoder(w).Encode(response)
 }
 
+// RESTfulErrorResponse replies with the supplied status code and the error
+func RESTfulErrorResponse(w http.ResponseWriter, statusCode int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// RESTfulInvalidArgument replies with a bad request status and the error for
+// requests with invalid arguments
+func RESTfulInvalidArgument(w http.ResponseWriter, err error) {
+	RESTfulErrorResponse(w, http.StatusBadRequest, err)
+}
+
 // RESTfulError prints the REST method and error
 func RESTfulError(method string, err error) {
 	log.Errorf("RESTful %s: server failed to send JSON response. Error %s",
 		method, err)
 }
 
+// refreshRequested returns whether the request asks for fresh data to be
+// fetched from the exchange instead of the cache
+func refreshRequested(r *http.Request) bool {
+	refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh"))
+	return refresh
+}
+
+// pairDisplayFormat returns the pair display format overrides of a request
+func pairDisplayFormat(r *http.Request) (PairDisplayFormat, error) {
+	format := PairDisplayFormat{
+		Delimiter: r.URL.Query().Get("pairDelimiter"),
+		Case:      r.URL.Query().Get("pairCase"),
+	}
+	return format, format.Validate()
+}
+
+// convertToDisplayCurrency returns whether the request asks for ticker values
+// to be converted into the fiat display currency
+func convertToDisplayCurrency(r *http.Request) bool {
+	convert, _ := strconv.ParseBool(r.URL.Query().Get("convertToDisplayCurrency"))
+	return convert
+}
+
+// RESTGetInfo replies with the daemon version, uptime and subsystem status
+func RESTGetInfo(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetDaemonInfo())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSettings replies with the startup settings of the daemon, its
+// resolved config file and data directory and the running subsystems
+func RESTGetSettings(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetActiveSettings())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
 // RESTGetAllSettings replies to a request with an encoded JSON response about the
 // trading bots configuration.
 func RESTGetAllSettings(w http.ResponseWriter, r *http.Request) {
@@ -74,7 +274,7 @@ func RESTSaveAllSettings(w http.ResponseWriter, r *http.Request) {
 		RESTfulError(r.Method, err)
 	}
 	//Save change the settings
-	err = bot.config.UpdateConfig(bot.configFile, responseData.Data)
+	err = bot.config.UpdateConfig(bot.settings.ConfigFile, &responseData.Data)
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
@@ -87,6 +287,100 @@ func RESTSaveAllSettings(w http.ResponseWriter, r *http.Request) {
 	SetupExchanges()
 }
 
+// ConfigEncryptionKeyRequest holds the current and new config encryption keys
+type ConfigEncryptionKeyRequest struct {
+	OldKey string `json:"oldKey"`
+	NewKey string `json:"newKey"`
+}
+
+// RESTChangeConfigEncryptionKey re-encrypts the config file with a new key,
+// the request must supply the webserver admin credentials using basic
+// authentication
+func RESTChangeConfigEncryptionKey(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request ConfigEncryptionKeyRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	err = ChangeConfigEncryptionKey(request.OldKey, request.NewKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, config.ErrInvalidEncryptionKey),
+			errors.Is(err, config.ErrConfigNotEncrypted):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to change config encryption key: %s", err)
+			RESTfulErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, map[string]string{"status": "config encryption key changed"})
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetConfigSection returns the config section at a dot separated path
+// with its credentials removed
+func RESTGetConfigSection(w http.ResponseWriter, r *http.Request) {
+	section, err := GetConfigSection(mux.Vars(r)["section"])
+	if err != nil {
+		restfulConfigSectionError(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, section)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTUpdateConfigSection merges the JSON request body into the config section
+// at a dot separated path and returns the updated section, the request must
+// supply the webserver admin credentials using basic authentication
+func RESTUpdateConfigSection(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var payload json.RawMessage
+	err := json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	update, err := UpdateConfigSection(mux.Vars(r)["section"], payload)
+	if err != nil {
+		restfulConfigSectionError(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, update)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// restfulConfigSectionError replies with the status of a config section error
+func restfulConfigSectionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, config.ErrConfigSectionNotFound):
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+	case errors.Is(err, config.ErrConfigSectionSecret):
+		RESTfulErrorResponse(w, http.StatusForbidden, err)
+	default:
+		RESTfulInvalidArgument(w, err)
+	}
+}
+
 // RESTGetOrderbook returns orderbook info for a given currency, exchange and
 // asset type
 func RESTGetOrderbook(w http.ResponseWriter, r *http.Request) {
@@ -94,27 +388,54 @@ func RESTGetOrderbook(w http.ResponseWriter, r *http.Request) {
 	currency := vars["currency"]
 	exchange := vars["exchangeName"]
 	assetType := vars["assetType"]
+	if assetType == "" {
+		assetType = r.URL.Query().Get("assetType")
+	}
 
 	if assetType == "" {
 		assetType = orderbook.Spot
 	}
 
-	response, err := GetSpecificOrderbook(currency, exchange, assetType)
+	format, err := pairDisplayFormat(r)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	refresh := refreshRequested(r)
+	var response orderbook.Base
+	p, err := ParseExchangePair(exchange, currency)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+	if refresh {
+		response, err = RefreshSpecificOrderbook(r.Context(), p, exchange, assetType)
+	} else {
+		response, err = GetSpecificOrderbook(p, exchange, assetType)
+	}
 	if err != nil {
+		if errors.Is(err, assets.ErrInvalidAssetType) {
+			RESTfulInvalidArgument(w, err)
+			return
+		}
 		log.Errorf("Failed to fetch orderbook for %s currency: %s\n", exchange,
 			currency)
 		return
 	}
 
-	err = RESTfulJSONResponse(w, response)
+	err = RESTfulJSONResponse(w, OrderbookResponse{
+		formatOrderbookPair(response, format), !refresh})
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
 }
 
-// GetAllActiveOrderbooks returns all enabled exchanges orderbooks
-func GetAllActiveOrderbooks() []EnabledExchangeOrderbooks {
-	var orderbookData []EnabledExchangeOrderbooks
+// GetAllActiveOrderbooks returns the stored orderbooks of the enabled pairs
+// and asset types of all enabled exchanges with their pairs in the display
+// format, an empty list if no exchanges are enabled
+func GetAllActiveOrderbooks(format PairDisplayFormat) []EnabledExchangeOrderbooks {
+	orderbookData := []EnabledExchangeOrderbooks{}
 
 	for _, individualBot := range bot.exchanges {
 		if individualBot != nil && individualBot.IsEnabled() {
@@ -122,37 +443,23 @@ func GetAllActiveOrderbooks() []EnabledExchangeOrderbooks {
 			exchangeName := individualBot.GetName()
 			individualExchange.ExchangeName = exchangeName
 			currencies := individualBot.GetEnabledCurrencies()
-			assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
-			if err != nil {
-				log.Errorf("failed to get %s exchange asset types. Error: %s",
-					exchangeName, err)
-				continue
-			}
-			for _, x := range currencies {
-				currency := x
-
-				var ob orderbook.Base
-				if len(assetTypes) > 1 {
-					for y := range assetTypes {
-						ob, err = individualBot.GetOrderbookEx(currency,
-							assetTypes[y])
+			assetTypes := individualBot.GetAssetTypes()
+			for _, currency := range currencies {
+				for y := range assetTypes {
+					ob, err := orderbook.GetOrderbook(exchangeName, currency,
+						assetTypes[y])
+					if err != nil {
+						log.Debugf("%s %s %s orderbook not stored. Error: %s",
+							exchangeName, currency.Pair().String(), assetTypes[y],
+							err)
+						continue
 					}
-				} else {
-					ob, err = individualBot.GetOrderbookEx(currency,
-						assetTypes[0])
-				}
 
-				if err != nil {
-					log.Errorf("failed to get %s %s orderbook. Error: %s",
-						currency.Pair().String(),
-						exchangeName,
-						err)
-					continue
+					individualExchange.ExchangeValues = append(
+						individualExchange.ExchangeValues,
+						formatOrderbookPair(ob, format),
+					)
 				}
-
-				individualExchange.ExchangeValues = append(
-					individualExchange.ExchangeValues, ob,
-				)
 			}
 			orderbookData = append(orderbookData, individualExchange)
 		}
@@ -162,10 +469,16 @@ func GetAllActiveOrderbooks() []EnabledExchangeOrderbooks {
 
 // RESTGetAllActiveOrderbooks returns all enabled exchange orderbooks
 func RESTGetAllActiveOrderbooks(w http.ResponseWriter, r *http.Request) {
+	format, err := pairDisplayFormat(r)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
 	var response AllEnabledExchangeOrderbooks
-	response.Data = GetAllActiveOrderbooks()
+	response.Data = GetAllActiveOrderbooks(format)
 
-	err := RESTfulJSONResponse(w, response)
+	err = RESTfulJSONResponse(w, response)
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
@@ -180,6 +493,49 @@ func RESTGetPortfolio(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RESTGetForexRates returns the forex rates with their base currency, provider
+// and when they were fetched
+func RESTGetForexRates(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, currency.GetForexRates())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTConvertCurrency converts the amount query parameter from one currency to
+// another using the forex rates and returns the rate and path used
+func RESTConvertCurrency(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	amount, err := strconv.ParseFloat(query.Get("amount"), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		RESTfulInvalidArgument(w, fmt.Errorf("invalid amount %q", query.Get("amount")))
+		return
+	}
+	if query.Get("from") == "" || query.Get("to") == "" {
+		RESTfulInvalidArgument(w, errors.New("from and to currencies required"))
+		return
+	}
+
+	response, err := currency.ConvertCurrencyWithPath(amount, query.Get("from"),
+		query.Get("to"))
+	if err != nil {
+		switch {
+		case errors.Is(err, currency.ErrForexRateNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, currency.ErrNoForexRates):
+			RESTfulErrorResponse(w, http.StatusServiceUnavailable, err)
+		default:
+			RESTfulInvalidArgument(w, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
 // RESTGetTicker returns ticker info for a given currency, exchange and
 // asset type
 func RESTGetTicker(w http.ResponseWriter, r *http.Request) {
@@ -187,75 +543,258 @@ func RESTGetTicker(w http.ResponseWriter, r *http.Request) {
 	currency := vars["currency"]
 	exchange := vars["exchangeName"]
 	assetType := vars["assetType"]
+	if assetType == "" {
+		assetType = r.URL.Query().Get("assetType")
+	}
 
 	if assetType == "" {
 		assetType = ticker.Spot
 	}
-	response, err := GetSpecificTicker(currency, exchange, assetType)
+	format, err := pairDisplayFormat(r)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	refresh := refreshRequested(r)
+	var response ticker.Price
+	p, err := ParseExchangePair(exchange, currency)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+	if refresh {
+		response, err = RefreshSpecificTicker(r.Context(), p, exchange, assetType)
+	} else {
+		response, err = GetSpecificTicker(p, exchange, assetType)
+	}
 	if err != nil {
+		if errors.Is(err, assets.ErrInvalidAssetType) {
+			RESTfulInvalidArgument(w, err)
+			return
+		}
 		log.Errorf("Failed to fetch ticker for %s currency: %s\n", exchange,
 			currency)
 		return
 	}
-	err = RESTfulJSONResponse(w, response)
+
+	response = formatTickerPair(response, format)
+	if convertToDisplayCurrency(r) {
+		err = RESTfulJSONResponse(w, DisplayTickerResponse{
+			ConvertTickerToDisplayCurrency(response), !refresh})
+	} else {
+		err = RESTfulJSONResponse(w, TickerResponse{response, !refresh})
+	}
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
 }
 
-// GetAllActiveTickers returns all enabled exchange tickers
-func GetAllActiveTickers() []EnabledExchangeCurrencies {
-	var tickerData []EnabledExchangeCurrencies
+// GetAllActiveTickers returns the stored tickers of the enabled pairs and
+// asset types of all enabled exchanges with their pairs in the display format,
+// an empty list if no exchanges are enabled
+func GetAllActiveTickers(format PairDisplayFormat) []EnabledExchangeCurrencies {
+	tickerData, _ := getFilteredTickers(TickerFilter{}, format)
+	return tickerData
+}
+
+// getFilteredTickers returns the stored tickers of the enabled exchanges,
+// pairs and asset types matching the filter. Only the tickers matching the
+// filter are looked up
+func getFilteredTickers(filter TickerFilter, format PairDisplayFormat) ([]EnabledExchangeCurrencies, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	for x := range filter.Exchanges {
+		exch := GetExchangeByName(filter.Exchanges[x])
+		if exch == nil || !exch.IsEnabled() {
+			return nil, fmt.Errorf("%w: %s", ErrExchangeNotFound, filter.Exchanges[x])
+		}
+	}
 
+	tickerData := []EnabledExchangeCurrencies{}
 	for _, individualBot := range bot.exchanges {
-		if individualBot != nil && individualBot.IsEnabled() {
-			var individualExchange EnabledExchangeCurrencies
-			exchangeName := individualBot.GetName()
-			individualExchange.ExchangeName = exchangeName
-			currencies := individualBot.GetEnabledCurrencies()
-			for _, x := range currencies {
-				currency := x
-				assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
-				if err != nil {
-					log.Errorf("failed to get %s exchange asset types. Error: %s",
-						exchangeName, err)
+		if individualBot == nil || !individualBot.IsEnabled() ||
+			!filter.matchExchange(individualBot.GetName()) {
+			continue
+		}
+
+		var individualExchange EnabledExchangeCurrencies
+		exchangeName := individualBot.GetName()
+		individualExchange.ExchangeName = exchangeName
+		currencies := individualBot.GetEnabledCurrencies()
+		assetTypes := individualBot.GetAssetTypes()
+		for _, currency := range currencies {
+			if !filter.matchPair(currency) {
+				continue
+			}
+			for y := range assetTypes {
+				if !filter.matchAssetType(assetTypes[y]) {
 					continue
 				}
-				var tickerPrice ticker.Price
-				if len(assetTypes) > 1 {
-					for y := range assetTypes {
-						tickerPrice, err = individualBot.GetTickerPrice(currency,
-							assetTypes[y])
-					}
-				} else {
-					tickerPrice, err = individualBot.GetTickerPrice(currency,
-						assetTypes[0])
-				}
-
+				tickerPrice, err := ticker.GetTicker(exchangeName, currency,
+					assetTypes[y])
 				if err != nil {
-					log.Errorf("failed to get %s %s ticker. Error: %s",
-						currency.Pair().String(),
-						exchangeName,
+					log.Debugf("%s %s %s ticker not stored. Error: %s",
+						exchangeName, currency.Pair().String(), assetTypes[y],
 						err)
 					continue
 				}
 
 				individualExchange.ExchangeValues = append(
-					individualExchange.ExchangeValues, tickerPrice,
+					individualExchange.ExchangeValues,
+					formatTickerPair(tickerPrice, format),
 				)
 			}
-			tickerData = append(tickerData, individualExchange)
 		}
+		tickerData = append(tickerData, individualExchange)
+	}
+	return tickerData, nil
+}
+
+// GetActiveTickers returns a page of the stored tickers matching the filter,
+// grouped by exchange, and the token of the next page or an empty token if
+// there are no more tickers. A page holds up to the limit of tickers, or
+// defaultTickersPageLimit if zero. Exchanges without tickers are only listed
+// on the first page
+func GetActiveTickers(filter TickerFilter, format PairDisplayFormat, pageToken string, limit int) ([]EnabledExchangeCurrencies, string, error) {
+	if limit == 0 {
+		limit = defaultTickersPageLimit
+	}
+	if limit < 0 || limit > maxTickersPageLimit {
+		return nil, "", fmt.Errorf("limit must be between 1 and %d",
+			maxTickersPageLimit)
+	}
+	offset, err := decodeTickersPageToken(pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tickerData, err := getFilteredTickers(filter, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := []EnabledExchangeCurrencies{}
+	var position int
+	for x := range tickerData {
+		values := tickerData[x].ExchangeValues
+		start, end := offset-position, offset+limit-position
+		position += len(values)
+		if len(values) == 0 {
+			if offset == 0 {
+				page = append(page, tickerData[x])
+			}
+			continue
+		}
+		if start < 0 {
+			start = 0
+		}
+		if end > len(values) {
+			end = len(values)
+		}
+		if start >= end {
+			continue
+		}
+		page = append(page, EnabledExchangeCurrencies{
+			ExchangeName:   tickerData[x].ExchangeName,
+			ExchangeValues: values[start:end],
+		})
+	}
+
+	if offset+limit >= position {
+		return page, "", nil
+	}
+	return page, encodeTickersPageToken(offset + limit), nil
+}
+
+// encodeTickersPageToken returns the opaque page token of a ticker offset
+func encodeTickersPageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeTickersPageToken returns the ticker offset of a page token, zero if
+// the token is empty
+func decodeTickersPageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidTickersPageToken
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, errInvalidTickersPageToken
+	}
+	return offset, nil
+}
+
+// tickerFilterFromQuery returns the ticker filter of a request from its
+// comma separated or repeated exchanges and pairs and its asset type query
+// parameters
+func tickerFilterFromQuery(values url.Values) TickerFilter {
+	split := func(params []string) []string {
+		var result []string
+		for x := range params {
+			for _, v := range common.SplitStrings(params[x], ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					result = append(result, v)
+				}
+			}
+		}
+		return result
+	}
+	return TickerFilter{
+		Exchanges: split(values["exchanges"]),
+		Pairs:     split(values["pairs"]),
+		AssetType: values.Get("assetType"),
 	}
-	return tickerData
 }
 
-// RESTGetAllActiveTickers returns all active tickers
+// RESTGetAllActiveTickers returns a page of the active tickers, optionally
+// filtered by the exchanges, pairs and assetType query parameters. The page
+// size is set by the limit query parameter and the following page is
+// requested with the returned nextPageToken as the pageToken query parameter
 func RESTGetAllActiveTickers(w http.ResponseWriter, r *http.Request) {
-	var response AllEnabledExchangeCurrencies
-	response.Data = GetAllActiveTickers()
+	format, err := pairDisplayFormat(r)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
 
-	err := RESTfulJSONResponse(w, response)
+	var limit int
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+	}
+
+	tickers, nextPageToken, err := GetActiveTickers(tickerFilterFromQuery(r.URL.Query()),
+		format, r.URL.Query().Get("pageToken"), limit)
+	if err != nil {
+		if errors.Is(err, ErrExchangeNotFound) {
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	if convertToDisplayCurrency(r) {
+		var response AllEnabledExchangeDisplayTickers
+		response.Data = ConvertTickersToDisplayCurrency(tickers)
+		response.NextPageToken = nextPageToken
+		err = RESTfulJSONResponse(w, response)
+	} else {
+		var response AllEnabledExchangeCurrencies
+		response.Data = tickers
+		response.NextPageToken = nextPageToken
+		err = RESTfulJSONResponse(w, response)
+	}
 	if err != nil {
 		RESTfulError(r.Method, err)
 	}
@@ -263,25 +802,53 @@ func RESTGetAllActiveTickers(w http.ResponseWriter, r *http.Request) {
 
 // GetAllEnabledExchangeAccountInfo returns all the current enabled exchanges
 func GetAllEnabledExchangeAccountInfo() AllEnabledExchangeAccounts {
-	var response AllEnabledExchangeAccounts
-	for _, individualBot := range bot.exchanges {
-		if individualBot != nil && individualBot.IsEnabled() {
-			if !individualBot.GetAuthenticatedAPISupport() {
-				log.Warnf("GetAllEnabledExchangeAccountInfo: Skippping %s due to disabled authenticated API support.", individualBot.GetName())
-				continue
-			}
-			individualExchange, err := individualBot.GetAccountInfo()
-			if err != nil {
-				log.Errorf("Error encountered retrieving exchange account info for %s. Error %s",
-					individualBot.GetName(), err)
-				continue
-			}
-			response.Data = append(response.Data, individualExchange)
-		}
+	response := AllEnabledExchangeAccounts{Data: []exchange.AccountInfo{}}
+	for _, individualBot := range GetAuthAPISupportedExchanges() {
+		response.Data = append(response.Data, getExchangeAccounts(individualBot)...)
 	}
 	return response
 }
 
+// getExchangeAccounts returns the account info for an exchange's default
+// credentials followed by each of its sub-accounts. Nothing is returned if the
+// default account info can't be retrieved
+func getExchangeAccounts(exch exchange.IBotExchange) []exchange.AccountInfo {
+	individualExchange, err := exch.GetAccountInfo()
+	if err != nil {
+		checkExchangeMaintenance(exch, err)
+		logAccountInfoError(exch.GetName(), err)
+		return nil
+	}
+	accounts := []exchange.AccountInfo{individualExchange}
+
+	for _, label := range exch.GetAccountLabels() {
+		subAccount, err := GetExchangeAccountInfo(exch.GetName(), label)
+		if err != nil {
+			logAccountInfoError(FormatExchangeAccountName(exch.GetName(), label), err)
+			continue
+		}
+		accounts = append(accounts, subAccount)
+	}
+	return accounts
+}
+
+// logAccountInfoError logs an account info retrieval failure at a level
+// suited to its error class
+func logAccountInfoError(name string, err error) {
+	switch {
+	case errors.Is(err, exchange.ErrAuthenticationNotConfigured):
+		log.Warnf("GetAllEnabledExchangeAccountInfo: Skipping %s due to disabled authenticated API support.", name)
+	case errors.Is(err, exchange.ErrRateLimited),
+		errors.Is(err, exchange.ErrExchangeUnavailable):
+		log.Warnf("Exchange account info for %s temporarily unavailable. Error %s", name, err)
+	case errors.Is(err, exchange.ErrCredentialsInvalid):
+		log.Errorf("Exchange %s rejected the configured API credentials. Error %s", name, err)
+	default:
+		log.Errorf("Error encountered retrieving exchange account info for %s. Error %s",
+			name, err)
+	}
+}
+
 // RESTGetAllEnabledAccountInfo via get request returns JSON response of account
 // info
 func RESTGetAllEnabledAccountInfo(w http.ResponseWriter, r *http.Request) {
@@ -291,3 +858,1073 @@ func RESTGetAllEnabledAccountInfo(w http.ResponseWriter, r *http.Request) {
 		RESTfulError(r.Method, err)
 	}
 }
+
+// RESTGetExchangeOrderExecutionLimits returns the order execution limits and
+// precision for each currency pair of an exchange
+func RESTGetExchangeOrderExecutionLimits(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		log.Errorf("Failed to fetch order execution limits for %s: %s",
+			exchName, ErrExchangeNotFound)
+		return
+	}
+
+	err := RESTfulJSONResponse(w, exch.GetAllOrderExecutionLimits())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExchangePairStatuses returns the currency pair trading statuses
+// reported by an exchange
+func RESTGetExchangePairStatuses(w http.ResponseWriter, r *http.Request) {
+	statuses, err := GetExchangePairStatuses(mux.Vars(r)["exchangeName"])
+	if err != nil {
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, statuses)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExchangeLatency returns the REST request latency statistics of an
+// exchange by endpoint class
+func RESTGetExchangeLatency(w http.ResponseWriter, r *http.Request) {
+	stats, err := GetExchangeLatency(mux.Vars(r)["exchangeName"])
+	if err != nil {
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, stats)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExchangeServerTime returns the server time of an exchange and its
+// offset from the local clock
+func RESTGetExchangeServerTime(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	response, err := GetExchangeServerTime(exchName)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, common.ErrFunctionNotSupported):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to fetch server time for %s: %s", exchName, err)
+			RESTfulErrorResponse(w, http.StatusBadGateway, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetCryptocurrencyInfo returns the deposit and withdrawal details of the
+// currencies on an exchange, filtered by the currency query parameter if set
+func RESTGetCryptocurrencyInfo(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	response, err := GetCryptocurrencyInfo(exchName, r.URL.Query().Get("currency"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound),
+			errors.Is(err, ErrCurrencyDetailsNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, common.ErrFunctionNotSupported):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to fetch currency details for %s: %s", exchName, err)
+			RESTfulErrorResponse(w, http.StatusBadGateway, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExchangeDepositHistory returns the deposits of the currency query
+// parameter on an exchange, optionally between the RFC3339 start and end query
+// parameters. The request must supply the webserver admin credentials using
+// basic authentication
+func RESTGetExchangeDepositHistory(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	exchName := mux.Vars(r)["exchangeName"]
+	query := r.URL.Query()
+
+	var start, end time.Time
+	var err error
+	if v := query.Get("start"); v != "" {
+		start, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid start time %q", v))
+			return
+		}
+	}
+	if v := query.Get("end"); v != "" {
+		end, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid end time %q", v))
+			return
+		}
+	}
+
+	response, err := GetExchangeDepositHistory(exchName, query.Get("currency"),
+		start, end)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrDepositCurrencyRequired),
+			errors.Is(err, exchange.ErrInvalidTimeRange),
+			errors.Is(err, common.ErrFunctionNotSupported):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to fetch deposit history for %s: %s", exchName, err)
+			RESTfulErrorResponse(w, http.StatusBadGateway, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetStats returns the exchanges ranked by price, or by volume if the
+// sortBy query parameter is set to volume, for a currency pair
+func RESTGetStats(w http.ResponseWriter, r *http.Request) {
+	currency := mux.Vars(r)["currency"]
+	assetType := r.URL.Query().Get("assetType")
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+	byVolume := r.URL.Query().Get("sortBy") == "volume"
+	if len(currency) < 3 {
+		log.Errorf("Failed to fetch stats for %s: invalid currency pair", currency)
+		return
+	}
+
+	response, err := GetExchangeStats(pair.NewCurrencyPairFromString(currency),
+		assetType, byVolume)
+	if err != nil {
+		if errors.Is(err, assets.ErrInvalidAssetType) {
+			RESTfulInvalidArgument(w, err)
+			return
+		}
+		log.Errorf("Failed to fetch stats for %s %s: %s", currency, assetType, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRecentTrades returns the most recent trades streamed by an exchange
+// for a currency pair, newest first. The optional limit query parameter caps
+// the number of trades returned
+func RESTGetRecentTrades(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	exchName := vars["exchangeName"]
+	query := r.URL.Query()
+	p, err := ParseExchangePair(exchName, vars["currency"])
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	var limit int
+	if l := query.Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid limit %q", l))
+			return
+		}
+	}
+
+	response, err := GetRecentTrades(exchName, p, query.Get("assetType"), limit)
+	if err != nil {
+		restfulTradesError(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetTradeStats returns the trade activity of an exchange currency pair
+// over the window query parameter, one minute if it isn't set
+func RESTGetTradeStats(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	exchName := vars["exchangeName"]
+	query := r.URL.Query()
+	p, err := ParseExchangePair(exchName, vars["currency"])
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	window, err := parseTradeStatsWindow(query.Get("window"))
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	response, err := GetTradeStats(exchName, p, query.Get("assetType"), window)
+	if err != nil {
+		restfulTradesError(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// restfulTradesError writes the error response of a recent trades or trade
+// stats request
+func restfulTradesError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrExchangeNotFound), errors.Is(err, trades.ErrNoTrades):
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+	default:
+		RESTfulInvalidArgument(w, err)
+	}
+}
+
+// RESTGetExchangeHealth returns the startup status of all started exchanges
+func RESTGetExchangeHealth(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetAllExchangeHealth())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExchangeAccountInfo returns account info for an exchange and the
+// optional sub-account supplied by the account query parameter. The request
+// must supply the webserver admin credentials using basic authentication
+func RESTGetExchangeAccountInfo(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	exchName := vars["exchangeName"]
+	account := r.URL.Query().Get("account")
+
+	response, err := GetExchangeAccountInfo(exchName, account)
+	if err != nil {
+		log.Errorf("Failed to fetch account info for %s: %s",
+			FormatExchangeAccountName(exchName, account), err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// checkRESTAdminAuth checks the request supplies the webserver admin
+// credentials using basic authentication and replies with an unauthorised
+// status if not
+func checkRESTAdminAuth(w http.ResponseWriter, r *http.Request) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok ||
+		subtle.ConstantTimeCompare([]byte(username), []byte(bot.config.Webserver.AdminUsername)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(password), []byte(bot.config.Webserver.AdminPassword)) != 1 {
+		log.Warnf("RESTful %s request rejected due to invalid credentials", r.URL.Path)
+		RESTfulErrorResponse(w, http.StatusUnauthorized, errors.New("invalid username/password"))
+		return false
+	}
+	return true
+}
+
+// RESTGetExchangeAssetTypes returns the supported and enabled asset types of
+// an exchange
+func RESTGetExchangeAssetTypes(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	response, err := GetExchangeAssetTypeInfo(exchName)
+	if err != nil {
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSetExchangeAssetType enables or disables an exchange asset type, the
+// request must supply the webserver admin credentials using basic
+// authentication
+func RESTSetExchangeAssetType(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	exchName := vars["exchangeName"]
+	err := SetExchangeAssetTypeEnabled(exchName, vars["assetType"],
+		vars["action"] == "enable")
+	if err != nil {
+		if errors.Is(err, ErrExchangeNotFound) {
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	response, err := GetExchangeAssetTypeInfo(exchName)
+	if err != nil {
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSetExchangeWebsocket enables or disables an exchange websocket and
+// returns its resulting state, the request must supply the webserver admin
+// credentials using basic authentication
+func RESTSetExchangeWebsocket(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	response, err := SetExchangeWebsocket(vars["exchangeName"],
+		vars["action"] == "enable")
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrWebsocketNotSupported):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to %s %s websocket: %s", vars["action"],
+				vars["exchangeName"], err)
+			RESTfulErrorResponse(w, http.StatusBadGateway, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSetExchangeEndpoint overrides the REST and websocket endpoints of an
+// exchange with the apiUrl, apiUrlSecondary and websocketUrl of the request
+// body and returns the resulting endpoints, the request must supply the
+// webserver admin credentials using basic authentication
+func RESTSetExchangeEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request exchange.Endpoints
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	response, err := SetExchangeEndpoint(vars["exchangeName"], request.APIURL,
+		request.APIURLSecondary, request.WebsocketURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, common.ErrInvalidURL):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to set %s endpoints: %s", vars["exchangeName"], err)
+			RESTfulErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSimulateOrder quotes the expected fill price, slippage and fee of a
+// market order without placing it. The side and amount query parameters are
+// required. The order is simulated on the exchange query parameter, or on
+// every enabled exchange with the pair enabled ranked by total cost when it
+// isn't set
+func RESTSimulateOrder(w http.ResponseWriter, r *http.Request) {
+	currency := mux.Vars(r)["currency"]
+	query := r.URL.Query()
+	if len(currency) < 3 {
+		RESTfulInvalidArgument(w, fmt.Errorf("invalid currency pair %q", currency))
+		return
+	}
+
+	side, amount, err := parseOrderQuery(query)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	p := pair.NewCurrencyPairFromString(currency)
+	assetType := query.Get("assetType")
+	var response interface{}
+	if exchName := query.Get("exchange"); exchName != "" {
+		response, err = SimulateOrder(r.Context(), exchName, p, assetType, side, amount,
+			refreshRequested(r))
+	} else {
+		response, err = SimulateOrderAllExchanges(r.Context(), p, assetType, side, amount,
+			refreshRequested(r))
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrInvalidOrder):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to simulate %s order for %s: %s", side, currency, err)
+			RESTfulErrorResponse(w, http.StatusBadGateway, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// parseOrderQuery returns the order side and amount query values of an order
+// simulation or best execution request
+func parseOrderQuery(query url.Values) (exchange.OrderSide, float64, error) {
+	var side exchange.OrderSide
+	switch common.StringToLower(query.Get("side")) {
+	case "buy":
+		side = exchange.Buy
+	case "sell":
+		side = exchange.Sell
+	default:
+		return "", 0, fmt.Errorf("invalid order side %q, supported values: buy, sell",
+			query.Get("side"))
+	}
+
+	amount, err := strconv.ParseFloat(query.Get("amount"), 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid amount %q", query.Get("amount"))
+	}
+	return side, amount, nil
+}
+
+// RESTGetBestExecutionVenue ranks the enabled exchanges by the effective cost
+// of an order including taker fees. Checking account balances requires the
+// webserver admin credentials using basic authentication
+func RESTGetBestExecutionVenue(w http.ResponseWriter, r *http.Request) {
+	currency := mux.Vars(r)["currency"]
+	query := r.URL.Query()
+	if len(currency) < 3 {
+		RESTfulInvalidArgument(w, fmt.Errorf("invalid currency pair %q", currency))
+		return
+	}
+
+	side, amount, err := parseOrderQuery(query)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	checkBalance, _ := strconv.ParseBool(query.Get("checkBalance"))
+	if checkBalance && !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	response, err := GetBestExecutionVenue(pair.NewCurrencyPairFromString(currency),
+		query.Get("assetType"), side, amount, checkBalance)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNoExecutionVenue):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrInvalidOrder):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to get best %s execution venue for %s: %s", side, currency, err)
+			RESTfulErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetConsolidatedOrderbook returns the cached orderbooks of every enabled
+// exchange listing a currency pair merged into a single orderbook
+func RESTGetConsolidatedOrderbook(w http.ResponseWriter, r *http.Request) {
+	currency := mux.Vars(r)["currency"]
+	query := r.URL.Query()
+	if len(currency) < 3 {
+		RESTfulInvalidArgument(w, fmt.Errorf("invalid currency pair %q", currency))
+		return
+	}
+
+	var depth int
+	if query.Get("depth") != "" {
+		var err error
+		depth, err = strconv.Atoi(query.Get("depth"))
+		if err != nil || depth < 0 {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid depth %q", query.Get("depth")))
+			return
+		}
+	}
+
+	response, err := BuildConsolidatedOrderbook(pair.NewCurrencyPairFromString(currency),
+		query.Get("assetType"), depth)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNoOrderbookSource):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrInvalidOrderbookDepth), errors.Is(err, assets.ErrInvalidAssetType):
+			RESTfulInvalidArgument(w, err)
+		default:
+			log.Errorf("Failed to build consolidated orderbook for %s: %s", currency, err)
+			RESTfulErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// SubmitOrderRequest holds the details of an order submitted through the
+// RESTful interface
+type SubmitOrderRequest struct {
+	Account      string  `json:"account,omitempty"`
+	Currency     string  `json:"currency"`
+	AssetType    string  `json:"assetType,omitempty"`
+	Side         string  `json:"side"`
+	OrderType    string  `json:"orderType"`
+	Amount       float64 `json:"amount"`
+	Price        float64 `json:"price"`
+	TriggerPrice float64 `json:"triggerPrice,omitempty"`
+	PegOffset    float64 `json:"pegOffset,omitempty"`
+	ClientID     string  `json:"clientId,omitempty"`
+	TimeInForce  string  `json:"timeInForce,omitempty"`
+	PostOnly     bool    `json:"postOnly,omitempty"`
+	ReduceOnly   bool    `json:"reduceOnly,omitempty"`
+	Leverage     float64 `json:"leverage,omitempty"`
+}
+
+// toOrderSubmission validates the request and converts it to an exchange
+// order submission
+func (s *SubmitOrderRequest) toOrderSubmission() (*exchange.OrderSubmission, error) {
+	if len(s.Currency) < 3 {
+		return nil, fmt.Errorf("invalid currency pair %q", s.Currency)
+	}
+	if s.Amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	order := &exchange.OrderSubmission{
+		Pair:         pair.NewCurrencyPairFromString(s.Currency),
+		AssetType:    s.AssetType,
+		Amount:       s.Amount,
+		Price:        s.Price,
+		TriggerPrice: s.TriggerPrice,
+		PegOffset:    s.PegOffset,
+		ClientID:     s.ClientID,
+		PostOnly:     s.PostOnly,
+		ReduceOnly:   s.ReduceOnly,
+		Leverage:     s.Leverage,
+	}
+
+	switch common.StringToLower(s.Side) {
+	case "buy":
+		order.OrderSide = exchange.Buy
+	case "sell":
+		order.OrderSide = exchange.Sell
+	default:
+		return nil, fmt.Errorf("invalid order side %q, supported values: buy, sell", s.Side)
+	}
+
+	switch common.StringToLower(s.OrderType) {
+	case "limit":
+		order.OrderType = exchange.Limit
+		if s.Price <= 0 {
+			return nil, errors.New("limit order price must be greater than zero")
+		}
+	case "market":
+		order.OrderType = exchange.Market
+	case "stop":
+		order.OrderType = exchange.Stop
+		if s.TriggerPrice <= 0 {
+			return nil, errors.New("stop order trigger price must be greater than zero")
+		}
+	case "stoplimit":
+		order.OrderType = exchange.StopLimit
+		if s.Price <= 0 || s.TriggerPrice <= 0 {
+			return nil, errors.New("stop limit order price and trigger price must be greater than zero")
+		}
+	case "trailingstop":
+		order.OrderType = exchange.TrailingStop
+		if s.PegOffset <= 0 {
+			return nil, errors.New("trailing stop order peg offset must be greater than zero")
+		}
+	default:
+		return nil, fmt.Errorf("invalid order type %q, supported values: limit, market, stop, stoplimit, trailingstop", s.OrderType)
+	}
+
+	var err error
+	order.TimeInForce, err = exchange.ParseTimeInForce(s.TimeInForce)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// RESTSubmitOrder submits an order to an exchange, the request must supply the
+// webserver admin credentials using basic authentication
+func RESTSubmitOrder(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	exchName := mux.Vars(r)["exchangeName"]
+	var request SubmitOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	order, err := request.toOrderSubmission()
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	response, err := SubmitExchangeOrder(exchName, request.Account, order)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrInvalidOrder),
+			errors.Is(err, exchange.ErrOrderOptionNotSupported),
+			errors.Is(err, exchange.ErrOrderTypeNotSupported),
+			errors.Is(err, exchange.ErrLeverageRequired):
+			RESTfulInvalidArgument(w, err)
+		case errors.Is(err, exchange.ErrPairHalted),
+			errors.Is(err, exchange.ErrPairDelisted):
+			RESTfulErrorResponse(w, http.StatusConflict, err)
+		default:
+			log.Errorf("Failed to submit %s order: %s", exchName, err)
+			RESTfulErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetExposureReport returns the exposure to each currency across the
+// exchanges from their balances, open orders and positions, the request must
+// supply the webserver admin credentials using basic authentication
+func RESTGetExposureReport(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	err := RESTfulJSONResponse(w, GetExposureReport())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetFills returns the recorded order fills, optionally filtered by the
+// exchange and pair query parameters and between the RFC3339 start and end
+// query parameters. The request must supply the webserver admin credentials
+// using basic authentication
+func RESTGetFills(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	var start, end time.Time
+	var err error
+	if v := query.Get("start"); v != "" {
+		start, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid start time %q", v))
+			return
+		}
+	}
+	if v := query.Get("end"); v != "" {
+		end, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid end time %q", v))
+			return
+		}
+	}
+
+	response, err := GetFills(query.Get("exchange"), query.Get("pair"), start, end)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelAllOrders cancels the orders of the exchange in the request, or of
+// every enabled exchange with authenticated API support if it is empty, which
+// must be confirmed. The optional timeout query parameter sets the time each
+// exchange is given. The request must supply the webserver admin credentials
+// using basic authentication
+func RESTCancelAllOrders(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request CancelAllOrdersRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	var timeout time.Duration
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		timeout, err = time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid timeout %q", value))
+			return
+		}
+	}
+
+	response, err := CancelAllExchangeOrders(request, timeout)
+	if err != nil {
+		if errors.Is(err, ErrExchangeNotFound) {
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTTransferBetweenExchanges plans moving a cryptocurrency between two
+// exchanges and withdraws it from the source exchange if the request is
+// confirmed. The request must supply the webserver admin credentials using
+// basic authentication
+func RESTTransferBetweenExchanges(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request TransferRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	response, err := TransferBetweenExchanges(request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrWithdrawalFailed):
+			RESTfulErrorResponse(w, http.StatusBadGateway, err)
+		default:
+			RESTfulInvalidArgument(w, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCheckExchangeConnectivity checks an exchange's public and authenticated
+// API can be reached, the optional timeout query parameter sets the timeout of
+// each check. The request must supply the webserver admin credentials using
+// basic authentication as the configured API credentials are used
+func RESTCheckExchangeConnectivity(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var timeout time.Duration
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			RESTfulInvalidArgument(w, fmt.Errorf("invalid timeout %q", value))
+			return
+		}
+	}
+
+	response, err := CheckExchangeConnectivity(r.Context(), mux.Vars(r)["exchangeName"], timeout)
+	if err != nil {
+		if errors.Is(err, ErrExchangeNotFound) {
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, response)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRequestStats returns the call counts, errors and durations of every
+// RESTful route called since startup
+func RESTGetRequestStats(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetRESTStats())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAddPortfolioAddress registers a watched portfolio address, the request
+// must supply the webserver admin credentials using basic authentication
+func RESTAddPortfolioAddress(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request PortfolioAddressRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	address, err := AddPortfolioAddress(request)
+	if err != nil {
+		if errors.Is(err, portfolio.ErrAddressExists) {
+			RESTfulErrorResponse(w, http.StatusConflict, err)
+			return
+		}
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, address)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// AddEventRequest holds the details of an event added through the RESTful
+// interface. Side and Percent are only used by DEPTH events, Window is only
+// used by TRADE_RATE and TRADE_IMBALANCE events
+type AddEventRequest struct {
+	Exchange  string  `json:"exchange"`
+	Item      string  `json:"item"`
+	Condition string  `json:"condition"`
+	Currency  string  `json:"currency"`
+	AssetType string  `json:"assetType,omitempty"`
+	Action    string  `json:"action,omitempty"`
+	Side      string  `json:"side,omitempty"`
+	Percent   float64 `json:"percent,omitempty"`
+	Window    string  `json:"window,omitempty"`
+}
+
+// RESTAddEvent adds an event, the request must supply the webserver admin
+// credentials using basic authentication
+func RESTAddEvent(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request AddEventRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+	if len(request.Currency) < 3 {
+		RESTfulInvalidArgument(w, fmt.Errorf("invalid currency pair %q", request.Currency))
+		return
+	}
+	if request.AssetType == "" {
+		request.AssetType = ticker.Spot
+	}
+	if request.Action == "" {
+		request.Action = "CONSOLE_PRINT"
+	}
+
+	id, err := events.AddEvent(request.Exchange, request.Item, request.Condition,
+		events.ConditionParams{Side: request.Side, Percent: request.Percent,
+			Window: request.Window},
+		pair.NewCurrencyPairFromString(request.Currency), request.AssetType,
+		request.Action)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, map[string]int{"id": id})
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetEvents returns the events with when they were last evaluated and
+// when the market data they were last offered was updated, the request must
+// supply the webserver admin credentials using basic authentication
+func RESTGetEvents(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	err := RESTfulJSONResponse(w, events.GetEvents())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTStartBackfill starts a backfill of exchange history to the data
+// directory, the request must supply the webserver admin credentials using
+// basic authentication
+func RESTStartBackfill(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	var request BackfillRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulInvalidArgument(w, err)
+		return
+	}
+
+	job, err := StartBackfill(request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrExchangeNotFound):
+			RESTfulErrorResponse(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrBackfillJobRunning):
+			RESTfulErrorResponse(w, http.StatusConflict, err)
+		default:
+			RESTfulInvalidArgument(w, err)
+		}
+		return
+	}
+
+	err = RESTfulJSONResponse(w, job)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetBackfillJobs returns the backfill jobs
+func RESTGetBackfillJobs(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetBackfillJobs())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetBackfillJob returns the progress of a backfill job
+func RESTGetBackfillJob(w http.ResponseWriter, r *http.Request) {
+	job, err := GetBackfillJob(mux.Vars(r)["id"])
+	if err != nil {
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, job)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelBackfill cancels a backfill job, the request must supply the
+// webserver admin credentials using basic authentication
+func RESTCancelBackfill(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	err := CancelBackfill(id)
+	if err != nil {
+		RESTfulErrorResponse(w, http.StatusNotFound, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, map[string]string{"id": id, "status": "cancelling"})
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTShutdown shuts down the bot, the request must supply the webserver admin
+// credentials using basic authentication
+func RESTShutdown(w http.ResponseWriter, r *http.Request) {
+	if !checkRESTAdminAuth(w, r) {
+		return
+	}
+
+	log.Debugln("RESTful shutdown requested.")
+	err := RESTfulJSONResponse(w, map[string]string{"status": "shutting down"})
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+	RequestShutdown()
+}
@@ -2,14 +2,26 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/forexprovider"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/events"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
 func loadConfig(t *testing.T) *config.Config {
@@ -45,7 +57,1005 @@ func TestConfigAllJsonResponse(t *testing.T) {
 		t.Error("Test failed. Response not parseable as json", err)
 	}
 
-	if reflect.DeepEqual(responseConfig, cfg) {
+	if !reflect.DeepEqual(&responseConfig, cfg) {
 		t.Error("Test failed. Json not equal to config")
 	}
 }
+
+func TestRESTInvalidAssetType(t *testing.T) {
+	SetupTestHelpers(t)
+	LoadExchange("Bitstamp", false, nil)
+	defer UnloadExchange("Bitstamp")
+
+	router := mux.NewRouter()
+	router.HandleFunc("/exchanges/{exchangeName}/latest/{currency}", RESTGetTicker)
+	router.HandleFunc("/exchanges/{exchangeName}/orderbook/latest/{currency}", RESTGetOrderbook)
+	router.HandleFunc("/stats/{currency}", RESTGetStats)
+
+	for _, url := range []string{
+		"/exchanges/Bitstamp/latest/BTCUSD?assetType=future",
+		"/exchanges/Bitstamp/orderbook/latest/BTCUSD?assetType=Spot%20",
+		"/stats/BTCUSD?assetType=futures",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Test failed. %s expected status %d, got %d",
+				url, http.StatusBadRequest, w.Code)
+		}
+
+		var resp map[string]string
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		if err != nil || !strings.Contains(resp["error"], "invalid asset type") {
+			t.Errorf("Test failed. %s unexpected error response %s", url, w.Body.String())
+		}
+	}
+}
+
+func TestRESTShutdown(t *testing.T) {
+	SetupTestHelpers(t)
+	shutdown := bot.shutdown
+	bot.shutdown = make(chan bool, 1)
+	defer func() { bot.shutdown = shutdown }()
+
+	r := httptest.NewRequest(http.MethodPost, "/shutdown", nil)
+	r.SetBasicAuth(bot.config.Webserver.AdminUsername, "wrong")
+	w := httptest.NewRecorder()
+	RESTShutdown(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	select {
+	case <-bot.shutdown:
+		t.Fatal("Test failed. Shutdown requested with invalid credentials")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/shutdown", nil)
+	r.SetBasicAuth(bot.config.Webserver.AdminUsername, bot.config.Webserver.AdminPassword)
+	w = httptest.NewRecorder()
+	RESTShutdown(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	select {
+	case <-bot.shutdown:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. Shutdown was not requested")
+	}
+}
+
+// newAssetExchange returns a test exchange with the asset types enabled,
+// listing the pairs if they're set
+func newAssetExchange(name string, assetTypes []string, pairs ...pair.CurrencyPair) *testExchange {
+	exch := newTestExchange(name)
+	exch.base.AssetTypes = assetTypes
+	if pairs != nil {
+		exch.enabledPairs = pairs
+	}
+	return exch
+}
+
+func TestRESTSetExchangeAssetType(t *testing.T) {
+	SetupTestHelpers(t)
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{
+		newAssetExchange("ANX", []string{"SPOT", "this_week"})}
+	defer func() {
+		bot.exchanges = exchanges
+		exchCfg, err := bot.config.GetExchangeConfig("ANX")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exchCfg.EnabledAssetTypes = ""
+		bot.config.UpdateExchangeConfig(exchCfg)
+	}()
+
+	router := NewRouter()
+	send := func(path string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := send("/exchanges/ANX/assets/this_week/disable", false)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ticker.ProcessTicker("ANX", btcusd, ticker.Price{Last: 1}, "SPOT")
+	ticker.ProcessTicker("ANX", btcusd, ticker.Price{Last: 1}, "this_week")
+	orderbook.ProcessOrderbook("ANX", btcusd, orderbook.Base{}, "this_week")
+	defer ticker.RemoveExchangeTickers("ANX")
+
+	w = send("/exchanges/ANX/assets/this_week/disable", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp ExchangeAssetTypes
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Enabled, []string{"SPOT"}) ||
+		len(resp.Supported) != 2 {
+		t.Fatalf("Test failed. Unexpected asset types %+v", resp)
+	}
+	if ticker.GetTickerCounts()["ANX"] != 1 ||
+		orderbook.GetOrderbookCounts()["ANX"] != 0 {
+		t.Error("Test failed. Market data of the disabled asset type not removed")
+	}
+
+	w = send("/exchanges/ANX/assets/quarter/enable", true)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = send("/exchanges/Blah/assets/spot/enable", true)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = send("/exchanges/ANX/assets/this_week/enable", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if len(bot.exchanges[0].GetAssetTypes()) != 2 {
+		t.Fatalf("Test failed. Unexpected enabled asset types %v",
+			bot.exchanges[0].GetAssetTypes())
+	}
+}
+
+func TestGetAllActiveTickersAndOrderbooks(t *testing.T) {
+	SetupTestHelpers(t)
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{newAssetExchange("ActiveTest", []string{"SPOT"})}
+	defer func() {
+		bot.exchanges = exchanges
+		ticker.RemoveExchangeTickers("ActiveTest")
+		orderbook.RemoveExchangeOrderbooks("ActiveTest")
+	}()
+
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ltcusd := pair.NewCurrencyPair("LTC", "USD")
+	for _, p := range []pair.CurrencyPair{btcusd, ltcusd} {
+		for _, assetType := range []string{"SPOT", "this_week"} {
+			ticker.ProcessTicker("ActiveTest", p, ticker.Price{Last: 1}, assetType)
+			orderbook.ProcessOrderbook("ActiveTest", p, orderbook.Base{}, assetType)
+		}
+	}
+
+	tickers := GetAllActiveTickers(PairDisplayFormat{})
+	if len(tickers) != 1 || len(tickers[0].ExchangeValues) != 1 ||
+		tickers[0].ExchangeValues[0].Pair.Pair() != btcusd.Pair() {
+		t.Errorf("Test failed. Expected only the enabled pair and asset type ticker, got %+v",
+			tickers)
+	}
+
+	orderbooks := GetAllActiveOrderbooks(PairDisplayFormat{})
+	if len(orderbooks) != 1 || len(orderbooks[0].ExchangeValues) != 1 ||
+		orderbooks[0].ExchangeValues[0].Pair.Pair() != btcusd.Pair() {
+		t.Errorf("Test failed. Expected only the enabled pair and asset type orderbook, got %+v",
+			orderbooks)
+	}
+}
+
+func TestGetAllActiveTickersPairDisplayFormat(t *testing.T) {
+	SetupTestHelpers(t)
+	restPair := pair.NewCurrencyPairDelimiter("btc_usd", "_")
+	wsPair := pair.NewCurrencyPair("BTC", "USD")
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{
+		newAssetExchange("RESTSource", []string{"SPOT"}, restPair),
+		newAssetExchange("WSSource", []string{"SPOT"}, wsPair),
+	}
+	defer func() {
+		bot.exchanges = exchanges
+		for _, name := range []string{"RESTSource", "WSSource"} {
+			ticker.RemoveExchangeTickers(name)
+			orderbook.RemoveExchangeOrderbooks(name)
+		}
+	}()
+
+	ticker.ProcessTicker("RESTSource", restPair, ticker.Price{Last: 1}, "SPOT")
+	orderbook.ProcessOrderbook("RESTSource", restPair, orderbook.Base{}, "SPOT")
+	// Websocket tickers are built from the stream's pair string
+	ticker.ProcessTicker("WSSource", wsPair, ticker.Price{Pair: wsPair, CurrencyPair: "BTCUSD",
+		Last: 1}, "SPOT")
+	orderbook.ProcessOrderbook("WSSource", wsPair, orderbook.Base{}, "SPOT")
+
+	for format, expected := range map[PairDisplayFormat]string{
+		{}:                                    "BTC-USD",
+		{Case: PairCaseLower}:                 "btc-usd",
+		{Delimiter: "/", Case: PairCaseLower}: "btc/usd",
+		{Delimiter: "/", Case: PairCaseUpper}: "BTC/USD",
+		{Delimiter: "_"}:                      "BTC_USD",
+	} {
+		tickers := GetAllActiveTickers(format)
+		orderbooks := GetAllActiveOrderbooks(format)
+		if len(tickers) != 2 || len(orderbooks) != 2 {
+			t.Fatalf("Test failed. Expected tickers and orderbooks of both exchanges, got %d and %d",
+				len(tickers), len(orderbooks))
+		}
+		for x := range tickers {
+			if len(tickers[x].ExchangeValues) != 1 ||
+				tickers[x].ExchangeValues[0].CurrencyPair != expected {
+				t.Errorf("Test failed. %+v expected %s ticker pair %s, got %+v", format,
+					tickers[x].ExchangeName, expected, tickers[x].ExchangeValues)
+			}
+			if len(orderbooks[x].ExchangeValues) != 1 ||
+				orderbooks[x].ExchangeValues[0].CurrencyPair != expected {
+				t.Errorf("Test failed. %+v expected %s orderbook pair %s, got %+v", format,
+					orderbooks[x].ExchangeName, expected, orderbooks[x].ExchangeValues)
+			}
+		}
+	}
+
+	router := NewRouter()
+	for path, code := range map[string]int{
+		"/exchanges/enabled/latest/all?pairCase=lower&pairDelimiter=/": http.StatusOK,
+		"/exchanges/enabled/latest/all?pairCase=title":                 http.StatusBadRequest,
+		"/exchanges/orderbook/latest/all?pairCase=title":               http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code {
+			t.Errorf("Test failed. %s expected status %d, got %d", path, code, w.Code)
+		}
+	}
+}
+
+func TestGetActiveTickersFilter(t *testing.T) {
+	SetupTestHelpers(t)
+	btcusd := pair.NewCurrencyPairDelimiter("btc_usd", "_")
+	ltcusd := pair.NewCurrencyPairDelimiter("ltc_usd", "_")
+	ethbtc := pair.NewCurrencyPair("ETH", "BTC")
+	disabled := newAssetExchange("FilterDisabled", []string{"SPOT"}, ethbtc)
+	disabled.base.Enabled = false
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{
+		newAssetExchange("FilterA", []string{"SPOT", "this_week"}, btcusd, ltcusd),
+		newAssetExchange("FilterB", []string{"SPOT"}, ethbtc,
+			pair.NewCurrencyPair("BTC", "USD")),
+		disabled,
+	}
+	defer func() {
+		bot.exchanges = exchanges
+		for _, name := range []string{"FilterA", "FilterB", "FilterDisabled"} {
+			ticker.RemoveExchangeTickers(name)
+		}
+	}()
+
+	for _, assetType := range []string{"SPOT", "this_week"} {
+		ticker.ProcessTicker("FilterA", btcusd, ticker.Price{Last: 1}, assetType)
+		ticker.ProcessTicker("FilterA", ltcusd, ticker.Price{Last: 2}, assetType)
+	}
+	ticker.ProcessTicker("FilterB", ethbtc, ticker.Price{Last: 3}, "SPOT")
+	ticker.ProcessTicker("FilterB", pair.NewCurrencyPair("BTC", "USD"),
+		ticker.Price{Last: 4}, "SPOT")
+	ticker.ProcessTicker("FilterDisabled", ethbtc, ticker.Price{Last: 5}, "SPOT")
+
+	count := func(tickers []EnabledExchangeCurrencies) map[string]int {
+		result := make(map[string]int)
+		for x := range tickers {
+			result[tickers[x].ExchangeName] += len(tickers[x].ExchangeValues)
+		}
+		return result
+	}
+
+	for _, test := range []struct {
+		filter   TickerFilter
+		expected map[string]int
+	}{
+		{TickerFilter{}, map[string]int{"FilterA": 4, "FilterB": 2}},
+		{TickerFilter{Exchanges: []string{"filtera"}}, map[string]int{"FilterA": 4}},
+		{TickerFilter{Pairs: []string{"BTC-USD"}}, map[string]int{"FilterA": 2, "FilterB": 1}},
+		{TickerFilter{Pairs: []string{"btcusd", "eth/btc"}, AssetType: "spot"},
+			map[string]int{"FilterA": 1, "FilterB": 2}},
+		{TickerFilter{Exchanges: []string{"FILTERA", "filterb"}, Pairs: []string{"ltc_usd"},
+			AssetType: "THIS_WEEK"}, map[string]int{"FilterA": 1, "FilterB": 0}},
+	} {
+		tickers, next, err := GetActiveTickers(test.filter, PairDisplayFormat{}, "", 0)
+		if err != nil || next != "" {
+			t.Errorf("Test failed. %+v unexpected error %v or next page %q",
+				test.filter, err, next)
+			continue
+		}
+		received := count(tickers)
+		if len(received) != len(test.expected) {
+			t.Errorf("Test failed. %+v expected %v, got %v", test.filter,
+				test.expected, received)
+			continue
+		}
+		for name, expected := range test.expected {
+			if v, ok := received[name]; !ok || v != expected {
+				t.Errorf("Test failed. %+v expected %v, got %v", test.filter,
+					test.expected, received)
+				break
+			}
+		}
+	}
+
+	_, _, err := GetActiveTickers(TickerFilter{Exchanges: []string{"FilterDisabled"}},
+		PairDisplayFormat{}, "", 0)
+	if !errors.Is(err, ErrExchangeNotFound) {
+		t.Errorf("Test failed. Expected an exchange not found error, got %v", err)
+	}
+	_, _, err = GetActiveTickers(TickerFilter{Pairs: []string{"-"}}, PairDisplayFormat{}, "", 0)
+	if err == nil {
+		t.Error("Test failed. Expected an invalid pair error")
+	}
+	for _, limit := range []int{-1, maxTickersPageLimit + 1} {
+		_, _, err = GetActiveTickers(TickerFilter{}, PairDisplayFormat{}, "", limit)
+		if err == nil {
+			t.Errorf("Test failed. Expected an invalid limit %d error", limit)
+		}
+	}
+	_, _, err = GetActiveTickers(TickerFilter{}, PairDisplayFormat{}, "!", 1)
+	if err == nil {
+		t.Error("Test failed. Expected an invalid page token error")
+	}
+
+	// Pages hold the limit of tickers until all are returned
+	var pages []map[string]int
+	var token string
+	for {
+		var tickers []EnabledExchangeCurrencies
+		tickers, token, err = GetActiveTickers(TickerFilter{}, PairDisplayFormat{}, token, 4)
+		if err != nil {
+			t.Fatalf("Test failed. GetActiveTickers error: %s", err)
+		}
+		pages = append(pages, count(tickers))
+		if token == "" || len(pages) > 3 {
+			break
+		}
+	}
+	if len(pages) != 2 || pages[0]["FilterA"] != 4 || len(pages[0]) != 1 ||
+		pages[1]["FilterB"] != 2 || len(pages[1]) != 1 {
+		t.Errorf("Test failed. Unexpected ticker pages %v", pages)
+	}
+
+	router := NewRouter()
+	for path, code := range map[string]int{
+		"/exchanges/enabled/latest/all?exchanges=filtera,FilterB&pairs=BTC-USD&assetType=spot": http.StatusOK,
+		"/exchanges/enabled/latest/all?limit=1&pageToken=" + encodeTickersPageToken(1):         http.StatusOK,
+		"/exchanges/enabled/latest/all?exchanges=unknown":                                      http.StatusNotFound,
+		"/exchanges/enabled/latest/all?pairs=-":                                                http.StatusBadRequest,
+		"/exchanges/enabled/latest/all?limit=0":                                                http.StatusBadRequest,
+		"/exchanges/enabled/latest/all?pageToken=invalid":                                      http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code {
+			t.Errorf("Test failed. %s expected status %d, got %d", path, code, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/exchanges/enabled/latest/all?exchanges=filtera,FilterB&pairs=BTC-USD&assetType=spot&limit=1", nil))
+	var response AllEnabledExchangeCurrencies
+	err = common.JSONDecode(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 1 || response.Data[0].ExchangeName != "FilterA" ||
+		len(response.Data[0].ExchangeValues) != 1 || response.NextPageToken == "" {
+		t.Errorf("Test failed. Unexpected filtered tickers response %+v", response)
+	}
+}
+
+func TestRESTSetExchangeWebsocket(t *testing.T) {
+	SetupTestHelpers(t)
+	var connects int
+	var connectErr error
+	exch := newTestExchange("Bitfinex")
+	exch.base.WebsocketInit()
+	err := exch.base.WebsocketSetup(func() error {
+		connects++
+		return connectErr
+	}, "Bitfinex", false, "wss://localhost", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := exch.base.Websocket
+
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{exch, newTestExchange("ANX")}
+	defer func() {
+		bot.exchanges = exchanges
+		if ws.IsConnected() {
+			ws.Shutdown()
+		}
+		exchCfg, err := bot.config.GetExchangeConfig("Bitfinex")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exchCfg.Websocket = false
+		bot.config.UpdateExchangeConfig(exchCfg)
+	}()
+
+	router := NewRouter()
+	send := func(path string) (*httptest.ResponseRecorder, ExchangeWebsocketState) {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+			bot.config.Webserver.AdminPassword)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		var state ExchangeWebsocketState
+		if w.Code == http.StatusOK {
+			err := json.Unmarshal(w.Body.Bytes(), &state)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w, state
+	}
+	websocketConfig := func() bool {
+		exchCfg, err := bot.config.GetExchangeConfig("Bitfinex")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return exchCfg.Websocket
+	}
+
+	for x := 0; x < 2; x++ {
+		w, state := send("/exchanges/Bitfinex/websocket/enable")
+		if w.Code != http.StatusOK || !state.Enabled || !state.Connected {
+			t.Fatalf("Test failed. Unexpected enable response %d %+v", w.Code, state)
+		}
+	}
+	if connects != 1 || !websocketConfig() {
+		t.Errorf("Test failed. Enable not idempotent, %d connects, config %t",
+			connects, websocketConfig())
+	}
+	<-ws.Connected
+
+	w, state := send("/exchanges/Bitfinex/websocket/disable")
+	if w.Code != http.StatusOK || state.Enabled || state.Connected || websocketConfig() {
+		t.Fatalf("Test failed. Unexpected disable response %d %+v", w.Code, state)
+	}
+	<-ws.Disconnected
+
+	w, _ = send("/exchanges/ANX/websocket/enable")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	w, _ = send("/exchanges/Blah/websocket/enable")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	connectErr = errors.New("connection refused")
+	w, _ = send("/exchanges/Bitfinex/websocket/enable")
+	if w.Code != http.StatusBadGateway || ws.IsConnected() || !websocketConfig() {
+		t.Errorf("Test failed. Unexpected failed connection response %d", w.Code)
+	}
+	ws.SetEnabled(false)
+}
+
+func TestRESTSetExchangeEndpoint(t *testing.T) {
+	SetupTestHelpers(t)
+	exch := newTestExchange("Bitfinex")
+	exch.base.APIUrl = "https://api.bitfinex.com"
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{exch}
+	exchCfg, err := bot.config.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(s Settings) {
+		bot.exchanges = exchanges
+		bot.settings = s
+		bot.config.UpdateExchangeConfig(exchCfg)
+	}(bot.settings)
+	bot.settings.DryRun = true
+
+	router := NewRouter()
+	for _, test := range []struct {
+		exchange string
+		body     string
+		auth     bool
+		status   int
+	}{
+		{"Bitfinex", `{"apiUrl":"https://sandbox.bitfinex.com"}`, false, http.StatusUnauthorized},
+		{"Bitfinex", `{"apiUrl":"wss://sandbox.bitfinex.com"}`, true, http.StatusBadRequest},
+		{"Bitfinex", `{"apiUrl":"sandbox"}`, true, http.StatusBadRequest},
+		{"Blah", `{"apiUrl":"https://sandbox.bitfinex.com"}`, true, http.StatusNotFound},
+		{"Bitfinex", `{"apiUrl":"https://sandbox.bitfinex.com"}`, true, http.StatusOK},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/exchanges/"+test.exchange+"/endpoints",
+			strings.NewReader(test.body))
+		if test.auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != test.status {
+			t.Errorf("Test failed. %s %s expected status %d, got %d", test.exchange,
+				test.body, test.status, w.Code)
+		}
+	}
+
+	updated, err := bot.config.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exch.base.GetAPIURL() != "https://sandbox.bitfinex.com" ||
+		updated.APIURL != "https://sandbox.bitfinex.com" ||
+		updated.APIURLSecondary != exchCfg.APIURLSecondary {
+		t.Errorf("Test failed. Unexpected endpoints %+v, config %s",
+			exch.base.GetEndpoints(), updated.APIURL)
+	}
+}
+
+func TestRESTRefresh(t *testing.T) {
+	SetupTestHelpers(t)
+	// Cached tickers and orderbooks are priced at 1000 and fresh ones at 1001
+	exch := newTestExchange("Bitstamp")
+	exch.getTickerPrice = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		return ticker.Price{Pair: p, Last: 1000}, nil
+	}
+	exch.updateTicker = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		return ticker.Price{Pair: p, Last: 1001, LastUpdated: time.Now()}, nil
+	}
+	exch.getOrderbookEx = func(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+		return orderbook.Base{Pair: p, Bids: []orderbook.Item{{Price: 1000}}}, nil
+	}
+	exch.updateOrderbook = func(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+		return orderbook.Base{Pair: p, Bids: []orderbook.Item{{Price: 1001}}}, nil
+	}
+	updates := func() int {
+		return exch.callCount("UpdateTicker") + exch.callCount("UpdateOrderbook")
+	}
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{exch}
+	defer func() { bot.exchanges = exchanges }()
+
+	router := NewRouter()
+	get := func(path string, response interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Test failed. %s expected status %d, got %d",
+				path, http.StatusOK, w.Code)
+		}
+		err := json.Unmarshal(w.Body.Bytes(), response)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tick TickerResponse
+	get("/exchanges/Bitstamp/latest/BTCUSD", &tick)
+	if !tick.FromCache || tick.Last != 1000 || updates() != 0 {
+		t.Errorf("Test failed. Unexpected cached ticker %+v", tick)
+	}
+
+	tick = TickerResponse{}
+	get("/exchanges/Bitstamp/latest/BTCUSD?refresh=true", &tick)
+	if tick.FromCache || tick.Last != 1001 || tick.LastUpdated.IsZero() ||
+		updates() != 1 {
+		t.Errorf("Test failed. Unexpected refreshed ticker %+v", tick)
+	}
+
+	var ob OrderbookResponse
+	get("/exchanges/Bitstamp/orderbook/latest/BTCUSD", &ob)
+	if !ob.FromCache || len(ob.Bids) != 1 || ob.Bids[0].Price != 1000 {
+		t.Errorf("Test failed. Unexpected cached orderbook %+v", ob)
+	}
+
+	ob = OrderbookResponse{}
+	get("/exchanges/Bitstamp/orderbook/latest/BTCUSD?refresh=1", &ob)
+	if ob.FromCache || len(ob.Bids) != 1 || ob.Bids[0].Price != 1001 ||
+		updates() != 2 {
+		t.Errorf("Test failed. Unexpected refreshed orderbook %+v", ob)
+	}
+}
+
+// newOrderExchange returns a test exchange named Bitmex listing XBTUSD with a
+// minimum order amount of 1. It supports post only orders, recording the
+// submitted orders in orders if it's set
+func newOrderExchange(orders *[]*exchange.OrderSubmission) *testExchange {
+	exch := newTestExchange("Bitmex")
+	exch.enabledPairs = []pair.CurrencyPair{pair.NewCurrencyPair("XBT", "USD")}
+	exch.availablePairs = exch.enabledPairs
+	exch.getOrderExecutionLimits = func(p pair.CurrencyPair) (exchange.Limits, error) {
+		return exchange.Limits{Pair: p, MinAmount: 1}, nil
+	}
+	exch.submitOrder = func(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
+		err := order.CheckOptions(exch.GetName(), exchange.OrderOptions{PostOnly: true})
+		if err != nil {
+			return exchange.SubmitOrderResponse{}, err
+		}
+		if orders != nil {
+			*orders = append(*orders, order)
+		}
+		return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: "1"}, nil
+	}
+	return exch
+}
+
+func TestRESTSubmitOrder(t *testing.T) {
+	SetupTestHelpers(t)
+	var orders []*exchange.OrderSubmission
+	exch := newOrderExchange(&orders)
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{exch}
+	defer func() { bot.exchanges = exchanges }()
+
+	router := NewRouter()
+	send := func(path, body string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	const order = `{"currency":"XBTUSD","side":"buy","orderType":"limit","amount":1,"price":6500,"postOnly":true}`
+	w := send("/exchanges/Bitmex/orders", order, false)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	for body, code := range map[string]int{
+		`{"currency":"XBTUSD","side":"hold","orderType":"limit","amount":1,"price":1}`:          http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"buy","orderType":"limit","amount":1}`:                     http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"buy","orderType":"market","amount":1,"timeInForce":"x"}`:  http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"buy","orderType":"market","amount":1,"reduceOnly":true}`:  http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"buy","orderType":"market","amount":0.5}`:                  http.StatusBadRequest,
+		`{"currency":"ETHUSD","side":"buy","orderType":"market","amount":1}`:                    http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"sell","orderType":"stop","amount":1}`:                     http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"sell","orderType":"stoplimit","amount":1,"price":1}`:      http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"sell","orderType":"trailingstop","amount":1}`:             http.StatusBadRequest,
+		`{"currency":"XBTUSD","side":"sell","orderType":"stop","amount":1,"triggerPrice":6450}`: http.StatusBadRequest,
+	} {
+		w = send("/exchanges/Bitmex/orders", body, true)
+		if w.Code != code {
+			t.Errorf("Test failed. %s expected status %d, got %d", body, code, w.Code)
+		}
+	}
+
+	w = send("/exchanges/Blah/orders", order, true)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	xbtusd := pair.NewCurrencyPair("XBT", "USD")
+	for _, status := range []string{exchange.PairStatusHalted, exchange.PairStatusDelisted} {
+		exch.base.SetPairStatuses([]exchange.PairStatus{{Pair: xbtusd, Status: status}})
+		w = send("/exchanges/Bitmex/orders", order, true)
+		if w.Code != http.StatusConflict || len(orders) != 0 {
+			t.Fatalf("Test failed. Expected a %s pair order to be rejected with status %d, got %d",
+				status, http.StatusConflict, w.Code)
+		}
+	}
+	exch.base.SetPairStatuses([]exchange.PairStatus{{Pair: xbtusd, Status: exchange.PairStatusTrading}})
+
+	w = send("/exchanges/Bitmex/orders", order, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if len(orders) != 1 || !orders[0].PostOnly ||
+		orders[0].OrderType != exchange.Limit || orders[0].Price != 6500 {
+		t.Fatalf("Test failed. Unexpected submitted orders %+v", orders)
+	}
+}
+
+func TestRESTAddPortfolioAddress(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(port *portfolio.Base, cryptos []string) {
+		bot.portfolio = port
+		currency.CryptoCurrencies = cryptos
+	}(bot.portfolio, currency.CryptoCurrencies)
+	bot.portfolio = &portfolio.Base{}
+	currency.CryptoCurrencies = append([]string{"USDT"}, currency.CryptoCurrencies...)
+
+	router := NewRouter()
+	send := func(body string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/portfolio/addresses", strings.NewReader(body))
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	const ethAddress = `{"address":"0xb794f5ea0ba39494ce839613fffba74279579268","coinType":"eth","tokens":["USDT"]}`
+	w := send(ethAddress, false)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w = send(ethAddress, true)
+	var address portfolio.Address
+	err := json.NewDecoder(w.Body).Decode(&address)
+	if err != nil {
+		t.Fatalf("Test failed. Unable to decode address: %s", err)
+	}
+	if w.Code != http.StatusOK || address.CoinType != "ETH" || len(address.Tokens) != 1 {
+		t.Errorf("Test failed. Unexpected status %d and address %+v", w.Code, address)
+	}
+
+	w = send(ethAddress, true)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	for _, body := range []string{
+		`{"address":"0xb794f5ea0ba39494ce839613fffba7427957926","coinType":"ETH"}`,
+		`{"address":"1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy","coinType":"BTC","tokens":["USDT"]}`,
+		`{"address":"0x52908400098527886E0F7030069857D2E4169EE7","coinType":"ETH","tokens":["NOTATOKEN"]}`,
+		`{"address":"Fabc123","coinType":"NOTACOIN"}`,
+		`{"address":`,
+	} {
+		w = send(body, true)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Test failed. %s expected status %d, got %d", body,
+				http.StatusBadRequest, w.Code)
+		}
+	}
+
+	w = send(`{"address":"Fabc123","coinType":"NOTACOIN","allowUnknownCoin":true}`, true)
+	if w.Code != http.StatusOK || len(bot.portfolio.Addresses) != 2 {
+		t.Errorf("Test failed. Expected the unknown coin to be allowed, got status %d", w.Code)
+	}
+}
+
+func TestRESTGetExchangeAccountInfo(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	exch := newTestExchange("Accounts")
+	exch.getAccountInfo = venueBalance("BTC", 2)
+	bot.exchanges = []exchange.IBotExchange{exch}
+
+	router := NewRouter()
+	send := func(auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/exchanges/Accounts/accounts", nil)
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := send(false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if exch.callCount("GetAccountInfo") != 0 {
+		t.Error("Test failed. Account info fetched without admin credentials")
+	}
+
+	w := send(true)
+	var result exchange.AccountInfo
+	err := common.JSONDecode(w.Body.Bytes(), &result)
+	if err != nil || len(result.Accounts) != 1 ||
+		result.Accounts[0].Currencies[0].TotalValue != 2 {
+		t.Errorf("Test failed. Unexpected account info %+v, error: %v", result, err)
+	}
+}
+
+func TestRESTAddEvent(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(e []*events.Event) { events.Events = e }(events.Events)
+	events.Events = nil
+
+	cfg := config.GetConfig()
+	exchCfg, err := cfg.GetExchangeConfig("Bitstamp")
+	if err != nil {
+		t.Fatalf("Test failed. GetExchangeConfig error: %s", err)
+	}
+	defer func(enabled bool) {
+		exchCfg.Enabled = enabled
+		cfg.UpdateExchangeConfig(exchCfg)
+	}(exchCfg.Enabled)
+	exchCfg.Enabled = true
+	err = cfg.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatalf("Test failed. UpdateExchangeConfig error: %s", err)
+	}
+
+	router := NewRouter()
+	send := func(body string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	const event = `{"exchange":"Bitstamp","item":"depth","condition":"<,10","currency":"BTCUSD","side":"bid","percent":0.5}`
+	w := send(event, false)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	for _, body := range []string{
+		`{"exchange":"Bitstamp","item":"depth","condition":"<,10","currency":"BTCUSD","side":"mid","percent":0.5}`,
+		`{"exchange":"Bitstamp","item":"depth","condition":"<,10","currency":"BTCUSD","side":"bid"}`,
+		`{"exchange":"Bitstamp","item":"spread","condition":"<,10","currency":"BTCUSD"}`,
+		`{"exchange":"Bitstamp","item":"volume","condition":"10","currency":"BTCUSD"}`,
+		`{"exchange":"Blah","item":"volume","condition":">,10","currency":"BTCUSD"}`,
+		`{"exchange":"Bitstamp","item":"volume","condition":">,10","currency":"B"}`,
+		`{"exchange":"Bitstamp","item":"trade_rate","condition":">,10","currency":"BTCUSD","window":"0s"}`,
+		`{"exchange":"Bitstamp","item":"price","condition":">,10","currency":"BTCUSD","action":"ORDER,BUY,1"}`,
+	} {
+		w = send(body, true)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Test failed. %s expected status %d, got %d", body,
+				http.StatusBadRequest, w.Code)
+		}
+	}
+
+	w = send(event, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if len(events.Events) != 1 || events.Events[0].Item != "DEPTH" ||
+		events.Events[0].Params.Side != "BID" || events.Events[0].Params.Percent != 0.5 ||
+		events.Events[0].Asset != ticker.Spot {
+		t.Errorf("Test failed. Unexpected events %+v", events.Events)
+	}
+
+	w = send(`{"exchange":"Bitstamp","item":"trade_imbalance","condition":">,0.5","currency":"BTCUSD","window":"5m"}`, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if len(events.Events) != 2 || events.Events[1].Item != "TRADE_IMBALANCE" ||
+		events.Events[1].Params.Window != "5m" {
+		t.Errorf("Test failed. Unexpected events %+v", events.Events)
+	}
+}
+
+func TestRESTSimulateOrder(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	bot.exchanges = []exchange.IBotExchange{newSimulateExchange("Simulate",
+		orderbook.Base{
+			Bids: []orderbook.Item{{Price: 99, Amount: 1}},
+			Asks: []orderbook.Item{{Price: 101, Amount: 1}},
+		}, 0)}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/simulate/{currency}", RESTSimulateOrder)
+
+	for url, status := range map[string]int{
+		"/simulate/BTC_USD?side=buy&amount=1&exchange=simulate": http.StatusOK,
+		"/simulate/BTC_USD?side=sell&amount=1":                  http.StatusOK,
+		"/simulate/BTC_USD?side=hold&amount=1":                  http.StatusBadRequest,
+		"/simulate/BTC_USD?side=buy&amount=one":                 http.StatusBadRequest,
+		"/simulate/BTC_USD?side=buy&amount=-1":                  http.StatusBadRequest,
+		"/simulate/BTC_USD?side=buy&amount=1&exchange=404":      http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != status {
+			t.Errorf("Test failed. %s expected status %d, got %d: %s",
+				url, status, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/simulate/BTC_USD?side=buy&amount=2&exchange=simulate", nil))
+	var result OrderSimulation
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	if err != nil || result.SufficientDepth || result.FilledAmount != 1 {
+		t.Errorf("Test failed. Expected insufficient depth, got %s", w.Body.String())
+	}
+}
+
+func TestRESTConvertCurrency(t *testing.T) {
+	defer func(rates map[string]float64, providers *forexprovider.ForexProviders) {
+		currency.FXRates, currency.FXProviders = rates, providers
+	}(currency.FXRates, currency.FXProviders)
+	currency.FXProviders = new(forexprovider.ForexProviders)
+	currency.FXRates = map[string]float64{"USDAUD": 1.25, "USDEUR": 0.8}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/forex/convert", RESTConvertCurrency)
+
+	for url, status := range map[string]int{
+		"/forex/convert?amount=1234.56&from=AUD&to=EUR": http.StatusOK,
+		"/forex/convert?amount=one&from=AUD&to=EUR":     http.StatusBadRequest,
+		"/forex/convert?amount=1&from=AUD":              http.StatusBadRequest,
+		"/forex/convert?amount=1&from=AUD&to=JPY":       http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != status {
+			t.Errorf("Test failed. %s expected status %d, got %d: %s",
+				url, status, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/forex/convert?amount=1234.56&from=aud&to=eur", nil))
+	var result currency.Conversion
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	if err != nil || result.Rate != 0.64 ||
+		!reflect.DeepEqual(result.Path, []string{"AUD", "USD", "EUR"}) {
+		t.Errorf("Test failed. Unexpected conversion %s", w.Body.String())
+	}
+
+	currency.FXRates = make(map[string]float64)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/forex/convert?amount=1&from=AUD&to=USD", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Test failed. Expected status %d without rates, got %d",
+			http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestRESTGetBestExecutionVenue(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	venue := newVenueExchange("Venue", 99, 101, 0.001, time.Now())
+	venue.getAccountInfo = venueBalance("USD", 1000)
+	bot.exchanges = []exchange.IBotExchange{venue}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/bestexecution/{currency}", RESTGetBestExecutionVenue)
+
+	send := func(url string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	for url, status := range map[string]int{
+		"/bestexecution/BTC_USD?side=buy&amount=1":                   http.StatusOK,
+		"/bestexecution/BTC_USD?side=hold&amount=1":                  http.StatusBadRequest,
+		"/bestexecution/BTC_USD?side=buy&amount=-1":                  http.StatusBadRequest,
+		"/bestexecution/ETH_USD?side=buy&amount=1":                   http.StatusNotFound,
+		"/bestexecution/BTC_USD?side=buy&amount=1&checkBalance=true": http.StatusUnauthorized,
+	} {
+		w := send(url, false)
+		if w.Code != status {
+			t.Errorf("Test failed. %s expected status %d, got %d: %s",
+				url, status, w.Code, w.Body.String())
+		}
+	}
+
+	w := send("/bestexecution/BTC_USD?side=buy&amount=1&checkBalance=true", true)
+	var result BestExecution
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	if err != nil || w.Code != http.StatusOK || result.BestExchange != "Venue" {
+		t.Errorf("Test failed. Unexpected best execution response %d %s", w.Code,
+			w.Body.String())
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// restDurationBuckets are the upper bounds of the request duration histogram,
+// requests slower than the last bound are counted in a final overflow bucket
+var restDurationBuckets = []time.Duration{
+	time.Millisecond * 10,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 250,
+	time.Millisecond * 500,
+	time.Second,
+	time.Second * 5,
+}
+
+var (
+	restLog = log.NewSubLogger("engine.restful")
+
+	restStats      = make(map[string]*RESTRouteStats)
+	restStatsStart = time.Now()
+	restStatsMtx   sync.Mutex
+
+	publishRESTStats sync.Once
+)
+
+// RESTRouteStats holds the aggregated requests of a RESTful route
+type RESTRouteStats struct {
+	Calls         int64         `json:"calls"`
+	Errors        int64         `json:"errors"`
+	SlowCalls     int64         `json:"slowCalls"`
+	TotalDuration time.Duration `json:"totalDuration"`
+	MaxDuration   time.Duration `json:"maxDuration"`
+	StatusCodes   map[int]int64 `json:"statusCodes"`
+	// Histogram counts the requests per duration bucket of RESTStats
+	Histogram []int64 `json:"histogram"`
+}
+
+// RESTStats holds the request stats of every RESTful route called since
+// startup
+type RESTStats struct {
+	Since   time.Time                 `json:"since"`
+	Buckets []string                  `json:"buckets"`
+	Routes  map[string]RESTRouteStats `json:"routes"`
+}
+
+// GetRESTStats returns a copy of the RESTful request stats
+func GetRESTStats() RESTStats {
+	buckets := make([]string, 0, len(restDurationBuckets)+1)
+	for x := range restDurationBuckets {
+		buckets = append(buckets, "<="+restDurationBuckets[x].String())
+	}
+	buckets = append(buckets, ">"+restDurationBuckets[len(restDurationBuckets)-1].String())
+
+	restStatsMtx.Lock()
+	defer restStatsMtx.Unlock()
+	stats := RESTStats{
+		Since:   restStatsStart,
+		Buckets: buckets,
+		Routes:  make(map[string]RESTRouteStats, len(restStats)),
+	}
+	for name, route := range restStats {
+		routeCopy := *route
+		routeCopy.StatusCodes = make(map[int]int64, len(route.StatusCodes))
+		for code, count := range route.StatusCodes {
+			routeCopy.StatusCodes[code] = count
+		}
+		routeCopy.Histogram = append([]int64(nil), route.Histogram...)
+		stats.Routes[name] = routeCopy
+	}
+	return stats
+}
+
+// recordRESTRequest adds a request to the stats of its route
+func recordRESTRequest(name string, status int, duration time.Duration, slow bool) {
+	restStatsMtx.Lock()
+	defer restStatsMtx.Unlock()
+	route, ok := restStats[name]
+	if !ok {
+		route = &RESTRouteStats{
+			StatusCodes: make(map[int]int64),
+			Histogram:   make([]int64, len(restDurationBuckets)+1),
+		}
+		restStats[name] = route
+	}
+
+	route.Calls++
+	if status >= http.StatusBadRequest {
+		route.Errors++
+	}
+	if slow {
+		route.SlowCalls++
+	}
+	route.TotalDuration += duration
+	if duration > route.MaxDuration {
+		route.MaxDuration = duration
+	}
+	route.StatusCodes[status]++
+
+	bucket := len(restDurationBuckets)
+	for x := range restDurationBuckets {
+		if duration <= restDurationBuckets[x] {
+			bucket = x
+			break
+		}
+	}
+	route.Histogram[bucket]++
+}
+
+// restRequestSettings returns the request log level and slow request threshold
+func restRequestSettings() (string, time.Duration) {
+	if bot.config == nil {
+		return "", 0
+	}
+	return bot.config.Webserver.RequestLogLevel,
+		bot.config.Webserver.SlowRequestThreshold
+}
+
+// restCaller describes the caller of a request by its remote address and the
+// basic authentication username if supplied, the password is never included
+func restCaller(r *http.Request) string {
+	if username, _, ok := r.BasicAuth(); ok && username != "" {
+		return username + "@" + r.RemoteAddr
+	}
+	return r.RemoteAddr
+}
+
+// logRESTRequest logs a request at the configured level, a slow request is
+// logged as a warning. Only the path is logged as query parameters and
+// payloads can hold sensitive values such as withdrawal addresses
+func logRESTRequest(r *http.Request, name string, status int, duration time.Duration, slow bool) {
+	level, _ := restRequestSettings()
+	logger := restLog.With("route", name).
+		With("caller", restCaller(r)).
+		With("status", status)
+
+	if slow {
+		logger.Warnf("Slow request %s %s took %s", r.Method, r.URL.Path, duration)
+		return
+	}
+
+	switch level {
+	case "INFO":
+		logger.Infof("%s %s %s", r.Method, r.URL.Path, duration)
+	case "WARN":
+		if status >= http.StatusInternalServerError {
+			logger.Warnf("%s %s %s", r.Method, r.URL.Path, duration)
+		}
+	default:
+		logger.Debugf("%s %s %s", r.Method, r.URL.Path, duration)
+	}
+}
+
+// publishRESTStatsVar publishes the RESTful request stats as an expvar
+// variable, it is safe to call more than once
+func publishRESTStatsVar() {
+	publishRESTStats.Do(func() {
+		expvar.Publish("restStats", expvar.Func(func() interface{} {
+			return GetRESTStats()
+		}))
+	})
+}
+
+// statusRecorder records the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write records an implicit OK status before writing the body
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Hijack hijacks the underlying connection, for websocket upgrades
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	if s.status == 0 {
+		s.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// Flush flushes buffered data to the client if supported
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
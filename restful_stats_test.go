@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetRESTStats() {
+	restStatsMtx.Lock()
+	restStats = make(map[string]*RESTRouteStats)
+	restStatsMtx.Unlock()
+}
+
+func TestRESTLogger(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(threshold time.Duration) {
+		bot.config.Webserver.SlowRequestThreshold = threshold
+		resetRESTStats()
+	}(bot.config.Webserver.SlowRequestThreshold)
+	resetRESTStats()
+	bot.config.Webserver.SlowRequestThreshold = time.Millisecond * 20
+
+	handler := RESTLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "/slow":
+			time.Sleep(time.Millisecond * 30)
+		}
+		w.Write([]byte("ok"))
+	}), "Stub")
+
+	for _, path := range []string{"/ok", "/ok?address=secret", "/fail", "/slow"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	stats, ok := GetRESTStats().Routes["Stub"]
+	if !ok {
+		t.Fatal("Test failed. Expected stats for the Stub route")
+	}
+	if stats.Calls != 4 || stats.Errors != 1 || stats.SlowCalls != 1 {
+		t.Errorf("Test failed. Unexpected calls %d, errors %d, slow calls %d",
+			stats.Calls, stats.Errors, stats.SlowCalls)
+	}
+	if stats.StatusCodes[http.StatusOK] != 3 || stats.StatusCodes[http.StatusInternalServerError] != 1 {
+		t.Errorf("Test failed. Unexpected status codes %v", stats.StatusCodes)
+	}
+	if stats.MaxDuration < time.Millisecond*30 || stats.TotalDuration < stats.MaxDuration {
+		t.Errorf("Test failed. Unexpected durations, max %s total %s", stats.MaxDuration,
+			stats.TotalDuration)
+	}
+	var histogramCalls int64
+	for x := range stats.Histogram {
+		histogramCalls += stats.Histogram[x]
+	}
+	if histogramCalls != stats.Calls || len(stats.Histogram) != len(GetRESTStats().Buckets) {
+		t.Errorf("Test failed. Unexpected histogram %v", stats.Histogram)
+	}
+
+	bot.config.Webserver.SlowRequestThreshold = 0
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if stats = GetRESTStats().Routes["Stub"]; stats.Calls != 5 || stats.SlowCalls != 1 {
+		t.Errorf("Test failed. Expected no slow call warning without a threshold, got %d slow calls",
+			stats.SlowCalls)
+	}
+}
+
+func TestRESTCaller(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/exchanges/Bitmex/orders", nil)
+	if caller := restCaller(r); caller != r.RemoteAddr {
+		t.Errorf("Test failed. Expected %s, got %s", r.RemoteAddr, caller)
+	}
+
+	r.SetBasicAuth("admin", "secret")
+	if caller := restCaller(r); caller != "admin@"+r.RemoteAddr {
+		t.Errorf("Test failed. Expected the username and address, got %s", caller)
+	}
+}
+
+func TestRESTGetRequestStats(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(enabled bool) {
+		bot.config.Webserver.DebugVarsEnabled = enabled
+		resetRESTStats()
+	}(bot.config.Webserver.DebugVarsEnabled)
+	resetRESTStats()
+
+	bot.config.Webserver.DebugVarsEnabled = false
+	router := NewRouter()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Test failed. Expected status %d while disabled, got %d", http.StatusNotFound,
+			w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/requeststats", nil))
+	var stats RESTStats
+	err := json.NewDecoder(w.Body).Decode(&stats)
+	if err != nil {
+		t.Fatalf("Test failed. Unable to decode request stats: %s", err)
+	}
+	if stats.Routes["GetRequestStats"].Calls != 0 {
+		t.Error("Test failed. Expected the stats to be read before the request is recorded")
+	}
+	if GetRESTStats().Routes["GetRequestStats"].Calls != 1 {
+		t.Error("Test failed. Expected the request stats call to be recorded")
+	}
+
+	bot.config.Webserver.DebugVarsEnabled = true
+	router = NewRouter()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "restStats") {
+		t.Errorf("Test failed. Expected the request stats in the debug vars, got %d", w.Code)
+	}
+}
@@ -1,26 +1,38 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
 	"github.com/thrasher-/gocryptotrader/currency/pair"
 	"github.com/thrasher-/gocryptotrader/currency/symbol"
+	"github.com/thrasher-/gocryptotrader/events"
 	exchange "github.com/thrasher-/gocryptotrader/exchanges"
 	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
 	log "github.com/thrasher-/gocryptotrader/logger"
+	"github.com/thrasher-/gocryptotrader/portfolio"
 )
 
 func printCurrencyFormat(price float64) string {
 	displaySymbol, err := symbol.GetSymbolByCurrencyName(bot.config.Currency.FiatDisplayCurrency)
 	if err != nil {
-		log.Errorf("Failed to get display symbol: %s", err)
+		routinesLog.Errorf("Failed to get display symbol: %s", err)
 	}
 
 	return fmt.Sprintf("%s%.8f", displaySymbol, price)
@@ -30,17 +42,17 @@ func printConvertCurrencyFormat(origCurrency string, origPrice float64) string {
 	displayCurrency := bot.config.Currency.FiatDisplayCurrency
 	conv, err := currency.ConvertCurrency(origPrice, origCurrency, displayCurrency)
 	if err != nil {
-		log.Errorf("Failed to convert currency: %s", err)
+		routinesLog.Errorf("Failed to convert currency: %s", err)
 	}
 
 	displaySymbol, err := symbol.GetSymbolByCurrencyName(displayCurrency)
 	if err != nil {
-		log.Errorf("Failed to get display symbol: %s", err)
+		routinesLog.Errorf("Failed to get display symbol: %s", err)
 	}
 
 	origSymbol, err := symbol.GetSymbolByCurrencyName(origCurrency)
 	if err != nil {
-		log.Errorf("Failed to get original currency symbol: %s", err)
+		routinesLog.Errorf("Failed to get original currency symbol: %s", err)
 	}
 
 	return fmt.Sprintf("%s%.2f %s (%s%.2f %s)",
@@ -53,109 +65,105 @@ func printConvertCurrencyFormat(origCurrency string, origPrice float64) string {
 	)
 }
 
+// routinesLog is the sub-logger for the engine's updater routines
+var routinesLog = log.NewSubLogger("engine.routines")
+
+// pairLogger returns the routines sub-logger tagged with a currency pair's
+// exchange, pair and asset type
+func pairLogger(exchangeName string, p pair.CurrencyPair, assetType string) *log.SubLogger {
+	return routinesLog.With("exchange", exchangeName).
+		With("pair", p.Pair().String()).
+		With("asset", assetType)
+}
+
 func printTickerSummary(result ticker.Price, p pair.CurrencyPair, assetType, exchangeName string, err error) {
+	logger := pairLogger(exchangeName, p, assetType)
 	if err != nil {
-		log.Errorf("Failed to get %s %s ticker. Error: %s",
-			p.Pair().String(),
-			exchangeName,
-			err)
+		logger.Errorf("Failed to get ticker. Error: %s", err)
 		return
 	}
 
 	stats.Add(exchangeName, p, assetType, result.Last, result.Volume)
-	if currency.IsFiatCurrency(p.SecondCurrency.String()) && p.SecondCurrency.String() != bot.config.Currency.FiatDisplayCurrency {
-		origCurrency := p.SecondCurrency.Upper().String()
-		log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %.8f",
-			exchangeName,
-			exchange.FormatCurrency(p).String(),
-			assetType,
-			printConvertCurrencyFormat(origCurrency, result.Last),
-			printConvertCurrencyFormat(origCurrency, result.Ask),
-			printConvertCurrencyFormat(origCurrency, result.Bid),
-			printConvertCurrencyFormat(origCurrency, result.High),
-			printConvertCurrencyFormat(origCurrency, result.Low),
-			result.Volume)
+	var change string
+	if result.ChangePercent != 0 {
+		change = fmt.Sprintf(" Change %.2f%%", result.ChangePercent)
+	}
+	quote := p.Quote().Upper().String()
+	if currency.IsFiatCurrency(quote) && quote != bot.config.Currency.FiatDisplayCurrency {
+		logger.Infof("TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %.8f%s",
+			printConvertCurrencyFormat(quote, result.Last),
+			printConvertCurrencyFormat(quote, result.Ask),
+			printConvertCurrencyFormat(quote, result.Bid),
+			printConvertCurrencyFormat(quote, result.High),
+			printConvertCurrencyFormat(quote, result.Low),
+			result.Volume,
+			change)
 	} else {
-		if currency.IsFiatCurrency(p.SecondCurrency.String()) && p.SecondCurrency.Upper().String() == bot.config.Currency.FiatDisplayCurrency {
-			log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %.8f",
-				exchangeName,
-				exchange.FormatCurrency(p).String(),
-				assetType,
+		if currency.IsFiatCurrency(quote) && quote == bot.config.Currency.FiatDisplayCurrency {
+			logger.Infof("TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %.8f%s",
 				printCurrencyFormat(result.Last),
 				printCurrencyFormat(result.Ask),
 				printCurrencyFormat(result.Bid),
 				printCurrencyFormat(result.High),
 				printCurrencyFormat(result.Low),
-				result.Volume)
+				result.Volume,
+				change)
 		} else {
-			log.Infof("%s %s %s: TICKER: Last %.8f Ask %.8f Bid %.8f High %.8f Low %.8f Volume %.8f",
-				exchangeName,
-				exchange.FormatCurrency(p).String(),
-				assetType,
+			logger.Infof("TICKER: Last %.8f Ask %.8f Bid %.8f High %.8f Low %.8f Volume %.8f%s",
 				result.Last,
 				result.Ask,
 				result.Bid,
 				result.High,
 				result.Low,
-				result.Volume)
+				result.Volume,
+				change)
 		}
 	}
 }
 
 func printOrderbookSummary(result orderbook.Base, p pair.CurrencyPair, assetType, exchangeName string, err error) {
+	logger := pairLogger(exchangeName, p, assetType)
 	if err != nil {
-		log.Errorf("Failed to get %s %s orderbook. Error: %s",
-			p.Pair().String(),
-			exchangeName,
-			err)
+		logger.Errorf("Failed to get orderbook. Error: %s", err)
 		return
 	}
 
 	bidsAmount, bidsValue := result.CalculateTotalBids()
 	asksAmount, asksValue := result.CalculateTotalAsks()
 
-	if currency.IsFiatCurrency(p.SecondCurrency.String()) && p.SecondCurrency.String() != bot.config.Currency.FiatDisplayCurrency {
-		origCurrency := p.SecondCurrency.Upper().String()
-		log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %s Asks len: %d Amount: %f %s. Total value: %s",
-			exchangeName,
-			exchange.FormatCurrency(p).String(),
-			assetType,
+	quote := p.Quote().Upper().String()
+	if currency.IsFiatCurrency(quote) && quote != bot.config.Currency.FiatDisplayCurrency {
+		logger.Infof("ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %s Asks len: %d Amount: %f %s. Total value: %s",
 			len(result.Bids),
 			bidsAmount,
-			p.FirstCurrency.String(),
-			printConvertCurrencyFormat(origCurrency, bidsValue),
+			p.Base().String(),
+			printConvertCurrencyFormat(quote, bidsValue),
 			len(result.Asks),
 			asksAmount,
-			p.FirstCurrency.String(),
-			printConvertCurrencyFormat(origCurrency, asksValue),
+			p.Base().String(),
+			printConvertCurrencyFormat(quote, asksValue),
 		)
 	} else {
-		if currency.IsFiatCurrency(p.SecondCurrency.String()) && p.SecondCurrency.Upper().String() == bot.config.Currency.FiatDisplayCurrency {
-			log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %s Asks len: %d Amount: %f %s. Total value: %s",
-				exchangeName,
-				exchange.FormatCurrency(p).String(),
-				assetType,
+		if currency.IsFiatCurrency(quote) && quote == bot.config.Currency.FiatDisplayCurrency {
+			logger.Infof("ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %s Asks len: %d Amount: %f %s. Total value: %s",
 				len(result.Bids),
 				bidsAmount,
-				p.FirstCurrency.String(),
+				p.Base().String(),
 				printCurrencyFormat(bidsValue),
 				len(result.Asks),
 				asksAmount,
-				p.FirstCurrency.String(),
+				p.Base().String(),
 				printCurrencyFormat(asksValue),
 			)
 		} else {
-			log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %f Asks len: %d Amount: %f %s. Total value: %f",
-				exchangeName,
-				exchange.FormatCurrency(p).String(),
-				assetType,
+			logger.Infof("ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %f Asks len: %d Amount: %f %s. Total value: %f",
 				len(result.Bids),
 				bidsAmount,
-				p.FirstCurrency.String(),
+				p.Base().String(),
 				bidsValue,
 				len(result.Asks),
 				asksAmount,
-				p.FirstCurrency.String(),
+				p.Base().String(),
 				asksValue,
 			)
 		}
@@ -171,7 +179,7 @@ func relayWebsocketEvent(result interface{}, event, assetType, exchangeName stri
 	}
 	err := BroadcastWebsocketMessage(evt)
 	if err != nil {
-		log.Errorf("Failed to broadcast websocket event. Error: %s",
+		routinesLog.Errorf("Failed to broadcast websocket event. Error: %s",
 			err)
 	}
 }
@@ -179,67 +187,96 @@ func relayWebsocketEvent(result interface{}, event, assetType, exchangeName stri
 // TickerUpdaterRoutine fetches and updates the ticker for all enabled
 // currency pairs and exchanges
 func TickerUpdaterRoutine() {
-	log.Debugf("Starting ticker updater routine.")
-	var wg sync.WaitGroup
+	routinesLog.Debugf("Starting ticker updater routine.")
+	var updateWg sync.WaitGroup
 	for {
-		wg.Add(len(bot.exchanges))
+		updateWg.Add(len(bot.exchanges))
 		for x := range bot.exchanges {
 			go func(x int, wg *sync.WaitGroup) {
 				defer wg.Done()
 				if bot.exchanges[x] == nil {
 					return
 				}
-				exchangeName := bot.exchanges[x].GetName()
-				enabledCurrencies := bot.exchanges[x].GetEnabledCurrencies()
-				supportsBatching := bot.exchanges[x].SupportsRESTTickerBatchUpdates()
-				assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
-				if err != nil {
-					log.Debugf("failed to get %s exchange asset types. Error: %s",
-						exchangeName, err)
-					return
-				}
+				updateExchangeTickers(bot.exchanges[x])
+			}(x, &updateWg)
+		}
+		updateWg.Wait()
+		routinesLog.Debugln("All enabled currency tickers fetched.")
+		checkExchangeLatencies()
+		if !waitOrShutdown(time.Second * 10) {
+			routinesLog.Debugln("Ticker updater routine stopped.")
+			return
+		}
+	}
+}
+
+// updateExchangeTickers updates the tickers of all enabled currency pairs and
+// asset types of an exchange. Exchanges supporting batch ticker updates
+// refresh every pair with one UpdateTickers call per asset type and the
+// tickers are then read from the cache
+func updateExchangeTickers(exch exchange.IBotExchange) {
+	exchangeName := exch.GetName()
+	if IsExchangeInMaintenance(exchangeName) {
+		return
+	}
+	enabledCurrencies := pollablePairs(exch, exch.GetEnabledCurrencies(), "ticker")
+	supportsBatching := exch.SupportsRESTTickerBatchUpdates()
+	assetTypes := exch.GetAssetTypes()
 
-				processTicker := func(exch exchange.IBotExchange, update bool, c pair.CurrencyPair, assetType string) {
+	processTicker := func(result ticker.Price, c pair.CurrencyPair, assetType string, err error) {
+		printTickerSummary(result, c, assetType, exchangeName, err)
+		checkExchangeMaintenance(exch, err)
+		if err == nil {
+			hooks.dispatchTicker(exchangeName, assetType, c, result)
+			if bot.config.Webserver.Enabled {
+				relayWebsocketEvent(result, WebsocketEventTickerUpdate, assetType, exchangeName)
+			}
+		}
+	}
+
+	for y := range assetTypes {
+		if IsExchangeInMaintenance(exchangeName) {
+			return
+		}
+		if supportsBatching {
+			err := exch.UpdateTickers(assetTypes[y])
+			if err != common.ErrFunctionNotSupported {
+				for z := range enabledCurrencies {
 					var result ticker.Price
-					var err error
-					if update {
-						result, err = exch.UpdateTicker(c, assetType)
-					} else {
-						result, err = exch.GetTickerPrice(c, assetType)
-					}
-					printTickerSummary(result, c, assetType, exchangeName, err)
+					tickerErr := err
 					if err == nil {
-						bot.comms.StageTickerData(exchangeName, assetType, result)
-						if bot.config.Webserver.Enabled {
-							relayWebsocketEvent(result, "ticker_update", assetType, exchangeName)
-						}
+						result, tickerErr = ticker.GetTicker(exchangeName,
+							enabledCurrencies[z], assetTypes[y])
 					}
+					processTicker(result, enabledCurrencies[z], assetTypes[y], tickerErr)
 				}
+				continue
+			}
+		}
 
-				for y := range assetTypes {
-					for z := range enabledCurrencies {
-						if supportsBatching && z > 0 {
-							processTicker(bot.exchanges[x], false, enabledCurrencies[z], assetTypes[y])
-							continue
-						}
-						processTicker(bot.exchanges[x], true, enabledCurrencies[z], assetTypes[y])
-					}
-				}
-			}(x, &wg)
+		for z := range enabledCurrencies {
+			if IsExchangeInMaintenance(exchangeName) {
+				return
+			}
+			var result ticker.Price
+			var err error
+			if supportsBatching && z > 0 {
+				result, err = exch.GetTickerPrice(enabledCurrencies[z], assetTypes[y])
+			} else {
+				result, err = updateTickerWithContext(shutdownCtx, exch, enabledCurrencies[z], assetTypes[y])
+			}
+			processTicker(result, enabledCurrencies[z], assetTypes[y], err)
 		}
-		wg.Wait()
-		log.Debugln("All enabled currency tickers fetched.")
-		time.Sleep(time.Second * 10)
 	}
 }
 
 // OrderbookUpdaterRoutine fetches and updates the orderbooks for all enabled
 // currency pairs and exchanges
 func OrderbookUpdaterRoutine() {
-	log.Debugln("Starting orderbook updater routine.")
-	var wg sync.WaitGroup
+	routinesLog.Debugln("Starting orderbook updater routine.")
+	var updateWg sync.WaitGroup
 	for {
-		wg.Add(len(bot.exchanges))
+		updateWg.Add(len(bot.exchanges))
 		for x := range bot.exchanges {
 			go func(x int, wg *sync.WaitGroup) {
 				defer wg.Done()
@@ -248,66 +285,600 @@ func OrderbookUpdaterRoutine() {
 					return
 				}
 				exchangeName := bot.exchanges[x].GetName()
-				enabledCurrencies := bot.exchanges[x].GetEnabledCurrencies()
-				assetTypes, err := exchange.GetExchangeAssetTypes(exchangeName)
-				if err != nil {
-					log.Errorf("failed to get %s exchange asset types. Error: %s",
-						exchangeName, err)
+				if IsExchangeInMaintenance(exchangeName) {
 					return
 				}
+				enabledCurrencies := pollablePairs(bot.exchanges[x],
+					bot.exchanges[x].GetEnabledCurrencies(), "orderbook")
+				assetTypes := bot.exchanges[x].GetAssetTypes()
 
 				processOrderbook := func(exch exchange.IBotExchange, c pair.CurrencyPair, assetType string) {
-					result, err := exch.UpdateOrderbook(c, assetType)
+					result, err := updateOrderbookWithContext(shutdownCtx, exch, c, assetType)
 					printOrderbookSummary(result, c, assetType, exchangeName, err)
+					checkExchangeMaintenance(exch, err)
 					if err == nil {
-						bot.comms.StageOrderbookData(exchangeName, assetType, result)
+						hooks.dispatchOrderbook(exchangeName, assetType, c, result)
 						if bot.config.Webserver.Enabled {
-							relayWebsocketEvent(result, "orderbook_update", assetType, exchangeName)
+							relayWebsocketEvent(result, WebsocketEventOrderbookUpdate, assetType, exchangeName)
 						}
 					}
 				}
 
 				for y := range assetTypes {
 					for z := range enabledCurrencies {
+						if IsExchangeInMaintenance(exchangeName) {
+							return
+						}
 						processOrderbook(bot.exchanges[x], enabledCurrencies[z], assetTypes[y])
 					}
 				}
-			}(x, &wg)
+			}(x, &updateWg)
+		}
+		updateWg.Wait()
+		routinesLog.Debugln("All enabled currency orderbooks fetched.")
+		if !waitOrShutdown(time.Second * 10) {
+			routinesLog.Debugln("Orderbook updater routine stopped.")
+			return
+		}
+	}
+}
+
+// marketDataSweepInterval is how often stale tickers and orderbooks are
+// removed
+const marketDataSweepInterval = time.Minute * 5
+
+// MarketDataSweeperRoutine periodically removes the stored tickers and
+// orderbooks which haven't been updated within the configured max age, so
+// pairs which are no longer fetched don't stay in memory
+func MarketDataSweeperRoutine() {
+	routinesLog.Debugf("Starting market data sweeper routine. Max age: %v.",
+		bot.config.MarketDataMaxAge)
+	for {
+		if !waitOrShutdown(marketDataSweepInterval) {
+			routinesLog.Debugln("Market data sweeper routine stopped.")
+			return
+		}
+		sweepStaleMarketData(bot.config.MarketDataMaxAge)
+	}
+}
+
+// EventsCheckerRoutine checks the pending events against the stored market
+// data every configured events check interval, catching conditions met by
+// market data updates skipped by the per event evaluation rate limit. The
+// intervals are read every pass so config updates apply without a restart
+func EventsCheckerRoutine() {
+	routinesLog.Debugf("Starting events checker routine. Check interval: %v.",
+		bot.config.Events.CheckInterval)
+	for {
+		events.SetMinEvaluationInterval(bot.config.Events.MinEvaluationInterval)
+		if !waitOrShutdown(bot.config.Events.CheckInterval) {
+			routinesLog.Debugln("Events checker routine stopped.")
+			return
+		}
+		events.CheckEvents()
+	}
+}
+
+// sweepStaleMarketData removes the tickers and orderbooks which haven't been
+// updated within the max age
+func sweepStaleMarketData(maxAge time.Duration) {
+	tickers := ticker.RemoveStaleTickers(maxAge)
+	orderbooks := orderbook.RemoveStaleOrderbooks(maxAge)
+	if tickers > 0 || orderbooks > 0 {
+		routinesLog.Debugf("Removed %d stale tickers and %d stale orderbooks.",
+			tickers, orderbooks)
+	}
+}
+
+// removePairMarketData removes the stored tickers and orderbooks of exchange
+// currency pairs for the supplied asset types
+func removePairMarketData(exchName string, pairs []pair.CurrencyPair, assetTypes []string) {
+	for x := range pairs {
+		for y := range assetTypes {
+			ticker.RemoveTicker(exchName, pairs[x], assetTypes[y])
+			orderbook.RemoveOrderbook(exchName, pairs[x], assetTypes[y])
+		}
+	}
+}
+
+// PairUpdaterRoutine periodically refreshes the available currency pairs for
+// all enabled exchanges which support automatic pair updates. Exchanges fetch
+// their pairs on startup, so the first update happens after one interval
+func PairUpdaterRoutine() {
+	routinesLog.Debugf("Starting pair updater routine. Update interval: %v.",
+		bot.config.PairUpdateInterval)
+	for {
+		if !waitOrShutdown(bot.config.PairUpdateInterval) {
+			routinesLog.Debugln("Pair updater routine stopped.")
+			return
+		}
+		var updateWg sync.WaitGroup
+		for x := range bot.exchanges {
+			if bot.exchanges[x] == nil || !bot.exchanges[x].IsEnabled() ||
+				!bot.exchanges[x].SupportsAutoPairUpdates() ||
+				IsExchangeInMaintenance(bot.exchanges[x].GetName()) {
+				continue
+			}
+			updateWg.Add(1)
+			go func(exch exchange.IBotExchange) {
+				defer updateWg.Done()
+				err := updateExchangePairs(exch)
+				if err != nil && !checkExchangeMaintenance(exch, err) {
+					routinesLog.With("exchange", exch.GetName()).Errorf("Failed to update tradable pairs. Err: %s", err)
+				}
+			}(bot.exchanges[x])
+		}
+		updateWg.Wait()
+		routinesLog.Debugln("All exchange tradable pairs updated.")
+	}
+}
+
+// updateExchangePairs fetches the tradable pairs for an exchange, logs any
+// added or removed pairs and trading status changes, and handles newly listed
+// and delisted pairs. New pairs are only enabled, and delisted pairs disabled,
+// if the exchange config requests it
+func updateExchangePairs(exch exchange.IBotExchange) error {
+	exchName := exch.GetName()
+	logger := routinesLog.With("exchange", exchName)
+	oldPairs := exch.GetAvailableCurrencies()
+	oldEnabled := exch.GetEnabledCurrencies()
+	oldStatuses := exch.GetPairStatuses()
+
+	err := updateTradablePairsWithContext(shutdownCtx, exch, false)
+	if err != nil {
+		return err
+	}
+
+	err = updatePairStatuses(exch, oldStatuses)
+	if err != nil {
+		return err
+	}
+
+	newEnabled := exch.GetEnabledCurrencies()
+	var disabled []pair.CurrencyPair
+	for x := range oldEnabled {
+		if !pair.Contains(newEnabled, oldEnabled[x], true) {
+			disabled = append(disabled, oldEnabled[x])
+		}
+	}
+	if len(disabled) > 0 {
+		removePairMarketData(exchName, disabled, exch.GetSupportedAssetTypes())
+	}
+
+	newPairs := exch.GetAvailableCurrencies()
+	var added, removed []pair.CurrencyPair
+	for x := range newPairs {
+		if !pair.Contains(oldPairs, newPairs[x], true) {
+			added = append(added, newPairs[x])
+		}
+	}
+	for x := range oldPairs {
+		if !pair.Contains(newPairs, oldPairs[x], true) {
+			removed = append(removed, oldPairs[x])
+		}
+	}
+
+	if len(removed) > 0 {
+		logger.Debugf("Pair update - removed pairs: %s.",
+			pair.PairsToStringArray(removed))
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		MarkConfigDirty()
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	logger.Debugf("Pair update - added pairs: %s.",
+		pair.PairsToStringArray(added))
+
+	registered := bot.config.RegisterPairCurrencies(added)
+	if len(registered) > 0 {
+		logger.Infof("Pair update - registered new currencies as cryptocurrencies: %s.",
+			common.JoinStrings(registered, ","))
+	}
+
+	if bot.config.NotifyNewPairs {
+		bot.comms.PushEvent(base.Event{
+			Type: "new_pairs",
+			TradeDetails: fmt.Sprintf("%s new markets available: %s",
+				exchName, common.JoinStrings(pair.PairsToStringArray(added), ",")),
+		})
+	}
+
+	exchCfg, err := bot.config.GetExchangeConfig(exchName)
+	if err != nil {
+		return err
+	}
+
+	if !exchCfg.AutoEnableNewPairs {
+		return nil
+	}
+
+	enabled := exch.GetEnabledCurrencies()
+	for x := range added {
+		if !pair.Contains(enabled, added[x], true) {
+			enabled = append(enabled, added[x])
+		}
+	}
+
+	logger.Debugln("Pair update - enabling added pairs.")
+	return exch.SetCurrencies(enabled, true)
+}
+
+// BalanceChange holds a change in an exchange account's currency balance
+type BalanceChange struct {
+	Exchange string  `json:"exchange"`
+	Currency string  `json:"currency"`
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+var (
+	balanceRefresh   = make(chan struct{}, 1)
+	balanceSnapshots = make(map[string]map[string]float64)
+	// balanceSnapshotTimes holds when each exchange account's snapshot was
+	// last stored
+	balanceSnapshotTimes = make(map[string]time.Time)
+	balanceMtx           sync.Mutex
+)
+
+// RequestBalanceRefresh asks the balance refresher routine to refresh the
+// exchange account balances without waiting for the next interval. Requests
+// made while a refresh is already pending are merged
+func RequestBalanceRefresh() {
+	select {
+	case balanceRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// BalanceRefresherRoutine periodically refreshes the account balances of all
+// enabled authenticated exchanges, updates the portfolio and notifies of any
+// balance changes, and of new deposits if enabled. Balances are seeded on
+// startup, so the first refresh happens after one interval unless one is
+// requested sooner
+func BalanceRefresherRoutine() {
+	routinesLog.Debugf("Starting balance refresher routine. Refresh interval: %v.",
+		bot.config.BalanceRefreshInterval)
+	for {
+		t := time.NewTimer(bot.config.BalanceRefreshInterval)
+		select {
+		case <-shutdowner:
+			t.Stop()
+			routinesLog.Debugln("Balance refresher routine stopped.")
+			return
+		case <-t.C:
+		case <-balanceRefresh:
+			t.Stop()
+		}
+		changes := refreshExchangeBalances()
+		notifyBalanceChanges(changes)
+		if bot.config.NotifyDeposits {
+			notifyNewDeposits(checkNewDeposits(changes))
+		}
+	}
+}
+
+// refreshExchangeBalances fetches the account info of the exchanges returned
+// by GetAuthAPISupportedExchanges concurrently, so a failing exchange only
+// skips its own balances. The portfolio is updated with the results and the
+// balance changes since the last refresh are returned
+func refreshExchangeBalances() []BalanceChange {
+	var accounts []exchange.AccountInfo
+	var accountsMtx sync.Mutex
+	var refreshWg sync.WaitGroup
+	for _, exch := range GetAuthAPISupportedExchanges() {
+		if IsExchangeInMaintenance(exch.GetName()) {
+			continue
+		}
+		refreshWg.Add(1)
+		go func(exch exchange.IBotExchange) {
+			defer refreshWg.Done()
+			result := getExchangeAccounts(exch)
+			if len(result) == 0 {
+				return
+			}
+			setExchangeBalanceRefreshed(exch.GetName(), time.Now())
+			accountsMtx.Lock()
+			accounts = append(accounts, result...)
+			accountsMtx.Unlock()
+		}(exch)
+	}
+	refreshWg.Wait()
+
+	SeedExchangeAccountInfo(accounts)
+	return updateBalanceSnapshots(accounts)
+}
+
+// updateBalanceSnapshots stores the collated balances of each exchange account
+// and returns the changes from its previous snapshot. Accounts without a
+// previous snapshot are stored without reporting any changes
+func updateBalanceSnapshots(accounts []exchange.AccountInfo) []BalanceChange {
+	balanceMtx.Lock()
+	defer balanceMtx.Unlock()
+
+	var changes []BalanceChange
+	for x := range accounts {
+		collated, _ := GetCollatedExchangeAccountInfoByCoin(accounts[x : x+1])
+		current := make(map[string]float64)
+		for currencyName, info := range collated {
+			current[currencyName] = info.TotalValue
+		}
+
+		previous, ok := balanceSnapshots[accounts[x].Exchange]
+		balanceSnapshots[accounts[x].Exchange] = current
+		balanceSnapshotTimes[accounts[x].Exchange] = time.Now()
+		if !ok {
+			continue
+		}
+		changes = append(changes, diffBalances(accounts[x].Exchange, previous,
+			current, bot.config.BalanceChangeThreshold)...)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Exchange != changes[j].Exchange {
+			return changes[i].Exchange < changes[j].Exchange
+		}
+		return changes[i].Currency < changes[j].Currency
+	})
+	return changes
+}
+
+// diffBalances returns the currency balance changes between two snapshots of
+// an exchange account. Changes smaller than the threshold percentage of the
+// previous balance are ignored, a threshold of 0 reports every change
+func diffBalances(exchName string, previous, current map[string]float64, threshold float64) []BalanceChange {
+	currencies := make(map[string]bool)
+	for currencyName := range previous {
+		currencies[currencyName] = true
+	}
+	for currencyName := range current {
+		currencies[currencyName] = true
+	}
+
+	var changes []BalanceChange
+	for currencyName := range currencies {
+		prev, curr := previous[currencyName], current[currencyName]
+		if !balanceChangeExceedsThreshold(prev, curr, threshold) {
+			continue
+		}
+		changes = append(changes, BalanceChange{
+			Exchange: exchName,
+			Currency: currencyName,
+			Previous: prev,
+			Current:  curr,
+			Change:   curr - prev,
+		})
+	}
+	return changes
+}
+
+// balanceChangeExceedsThreshold returns whether a balance changed by at least
+// the threshold percentage of the previous balance. Any change from a zero
+// balance exceeds the threshold, as does any change if the threshold is 0
+func balanceChangeExceedsThreshold(previous, current, threshold float64) bool {
+	change := current - previous
+	if change == 0 {
+		return false
+	}
+	if threshold > 0 && previous != 0 &&
+		math.Abs(change)/math.Abs(previous)*100 < threshold {
+		return false
+	}
+	return true
+}
+
+// notifyBalanceChanges pushes balance changes to the enabled communication
+// mediums and websocket clients
+func notifyBalanceChanges(changes []BalanceChange) {
+	for x := range changes {
+		details := fmt.Sprintf("%s %s balance changed from %f to %f",
+			changes[x].Exchange, changes[x].Currency, changes[x].Previous,
+			changes[x].Current)
+		routinesLog.Debugf("%s.", details)
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "balance_change",
+			TradeDetails: details,
+		})
+
+		if bot.config.Webserver.Enabled {
+			exchName, _ := SplitExchangeAccountName(changes[x].Exchange)
+			relayWebsocketEvent(changes[x], WebsocketEventBalanceUpdate, "", exchName)
+		}
+	}
+}
+
+// AddressBalanceChange holds a change in the balance of a watched portfolio
+// address
+type AddressBalanceChange struct {
+	Address  string `json:"address"`
+	CoinType string `json:"coinType"`
+	// Token is set when the change is in the balance of a token tracked on the
+	// address
+	Token    string  `json:"token,omitempty"`
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+// PortfolioWatcherRoutine refreshes the balances of the watched portfolio
+// addresses from the configured blockchain explorers on startup and then on
+// each refresh interval
+func PortfolioWatcherRoutine() {
+	provider, err := portfolio.NewExplorerProvider(bot.config.PortfolioWatcher.Explorers)
+	if err != nil {
+		routinesLog.Errorf("Portfolio watcher routine not started. Error: %s", err)
+		return
+	}
+
+	routinesLog.Debugf("Starting portfolio watcher routine. Refresh interval: %v.",
+		bot.config.PortfolioWatcher.RefreshInterval)
+	for {
+		notifyAddressBalanceChanges(refreshPortfolioAddresses(bot.portfolio,
+			provider))
+		if !waitOrShutdown(bot.config.PortfolioWatcher.RefreshInterval) {
+			routinesLog.Debugln("Portfolio watcher routine stopped.")
+			return
+		}
+	}
+}
+
+// refreshPortfolioAddresses fetches the balance of each watched portfolio
+// address and of the tokens tracked on it and updates the portfolio,
+// returning the changes which exceed the balance change threshold. Addresses
+// for unsupported coins are skipped and failed lookups keep their previously
+// known balance
+func refreshPortfolioAddresses(port *portfolio.Base, provider portfolio.BalanceProvider) []AddressBalanceChange {
+	var watched []portfolio.Address
+	addresses := port.GetAddresses()
+	for x := range addresses {
+		if addresses[x].Description != portfolio.PortfolioAddressExchange {
+			watched = append(watched, addresses[x])
+		}
+	}
+
+	var changes []AddressBalanceChange
+	unsupported := make(map[string]bool)
+	for x := range watched {
+		if unsupported[watched[x].CoinType] {
+			continue
+		}
+
+		balance, err := provider.GetAddressBalance(watched[x].CoinType,
+			watched[x].Address)
+		if err != nil {
+			if errors.Is(err, portfolio.ErrCoinNotSupported) {
+				routinesLog.Debugf("Portfolio watcher: Skipping %s addresses. Error: %s",
+					watched[x].CoinType, err)
+				unsupported[watched[x].CoinType] = true
+				continue
+			}
+			routinesLog.Warnf("Portfolio watcher: Failed to refresh %s address %s balance. Error: %s",
+				watched[x].CoinType, watched[x].Address, err)
+			continue
+		}
+
+		if balance != watched[x].Balance {
+			if !port.SetAddressBalance(watched[x].Address, watched[x].CoinType,
+				watched[x].Description, balance) {
+				// The address was removed while its balance was fetched
+				continue
+			}
+			if balanceChangeExceedsThreshold(watched[x].Balance, balance,
+				bot.config.BalanceChangeThreshold) {
+				changes = append(changes, AddressBalanceChange{
+					Address:  watched[x].Address,
+					CoinType: watched[x].CoinType,
+					Previous: watched[x].Balance,
+					Current:  balance,
+					Change:   balance - watched[x].Balance,
+				})
+			}
+		}
+
+		if len(watched[x].Tokens) != 0 {
+			changes = append(changes, refreshPortfolioTokens(port, provider,
+				watched[x])...)
+		}
+	}
+	return changes
+}
+
+// refreshPortfolioTokens fetches the token balances of a watched address and
+// updates the balances of its tracked tokens, a tracked token which is no
+// longer held has a zero balance
+func refreshPortfolioTokens(port *portfolio.Base, provider portfolio.BalanceProvider, address portfolio.Address) []AddressBalanceChange {
+	balances, err := provider.GetTokenBalances(address.CoinType, address.Address)
+	if err != nil {
+		routinesLog.Warnf("Portfolio watcher: Failed to refresh %s address %s token balances. Error: %s",
+			address.CoinType, address.Address, err)
+		return nil
+	}
+
+	var changes []AddressBalanceChange
+	for x := range address.Tokens {
+		previous := address.Tokens[x].Balance
+		balance := balances[address.Tokens[x].Symbol]
+		if balance == previous {
+			continue
+		}
+		if !port.SetTokenBalance(address.Address, address.CoinType,
+			address.Tokens[x].Symbol, balance) {
+			continue
+		}
+
+		if !balanceChangeExceedsThreshold(previous, balance,
+			bot.config.BalanceChangeThreshold) {
+			continue
+		}
+		changes = append(changes, AddressBalanceChange{
+			Address:  address.Address,
+			CoinType: address.CoinType,
+			Token:    address.Tokens[x].Symbol,
+			Previous: previous,
+			Current:  balance,
+			Change:   balance - previous,
+		})
+	}
+	return changes
+}
+
+// notifyAddressBalanceChanges pushes watched address balance changes to the
+// enabled communication mediums and websocket clients
+func notifyAddressBalanceChanges(changes []AddressBalanceChange) {
+	for x := range changes {
+		details := fmt.Sprintf("%s address %s balance changed from %f to %f",
+			changes[x].CoinType, changes[x].Address, changes[x].Previous,
+			changes[x].Current)
+		if changes[x].Token != "" {
+			details = fmt.Sprintf("%s address %s %s token balance changed from %f to %f",
+				changes[x].CoinType, changes[x].Address, changes[x].Token,
+				changes[x].Previous, changes[x].Current)
+		}
+		routinesLog.Debugf("%s.", details)
+
+		bot.comms.PushEvent(base.Event{
+			Type:         "address_balance_change",
+			TradeDetails: details,
+		})
+
+		if bot.config.Webserver.Enabled {
+			relayWebsocketEvent(changes[x], WebsocketEventPortfolioUpdate, "", "")
 		}
-		wg.Wait()
-		log.Debugln("All enabled currency orderbooks fetched.")
-		time.Sleep(time.Second * 10)
 	}
 }
 
 // WebsocketRoutine Initial routine management system for websocket
 func WebsocketRoutine(verbose bool) {
-	log.Debugln("Connecting exchange websocket services...")
+	routinesLog.Debugln("Connecting exchange websocket services...")
 
 	for i := range bot.exchanges {
 		go func(i int) {
+			logger := routinesLog.With("exchange", bot.exchanges[i].GetName())
 			if verbose {
-				log.Debugf("Establishing websocket connection for %s",
-					bot.exchanges[i].GetName())
+				logger.Debugln("Establishing websocket connection")
 			}
 
 			ws, err := bot.exchanges[i].GetWebsocket()
 			if err != nil {
-				log.Debugf("Websocket not enabled for %s",
-					bot.exchanges[i].GetName())
+				logger.Debugln("Websocket not enabled")
 				return
 			}
 
 			// Data handler routine
-			go WebsocketDataHandler(ws, verbose)
+			startRoutine(func() { WebsocketDataHandler(ws, verbose) })
 
 			err = ws.Connect()
 			if err != nil {
 				switch err.Error() {
 				case exchange.WebsocketNotEnabled:
-					log.Warnf("%s - websocket disabled", bot.exchanges[i].GetName())
+					logger.Warn("Websocket disabled")
 				default:
-					log.Error(err)
+					logger.Error(err)
 				}
 			}
 		}(i)
@@ -315,21 +886,58 @@ func WebsocketRoutine(verbose bool) {
 }
 
 var shutdowner = make(chan struct{}, 1)
+var shutdownOnce sync.Once
+
+// shutdownCtx is cancelled when the bot stops, aborting in flight exchange
+// requests made by the updater routines and websocket handlers
+var shutdownCtx, cancelShutdownCtx = context.WithCancel(context.Background())
 var wg sync.WaitGroup
 
+// startRoutine runs fn in a new goroutine tracked by the shared wait group.
+// The routine is added to the wait group before it is started so Stop can't
+// miss a routine which hasn't been scheduled yet
+func startRoutine(fn func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fn()
+	}()
+}
+
+// signalShutdown closes the shared shutdown channel, signalling the updater
+// routines and websocket handlers to return
+func signalShutdown() {
+	shutdownOnce.Do(func() {
+		close(shutdowner)
+	})
+}
+
+// waitOrShutdown waits for the supplied duration and returns false if shutdown
+// was signalled first
+func waitOrShutdown(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-shutdowner:
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
 // Websocketshutdown shuts down the exchange routines and then shuts down
 // governing routines
 func Websocketshutdown(ws *exchange.Websocket) error {
 	err := ws.Shutdown() // shutdown routines on the exchange
 	if err != nil {
-		log.Errorf("routines.go error - failed to shutodwn %s", err)
+		routinesLog.With("exchange", ws.GetName()).Errorf("Failed to shutdown websocket. Error: %s", err)
 	}
 
 	timer := time.NewTimer(5 * time.Second)
 	c := make(chan struct{}, 1)
 
 	go func(c chan struct{}) {
-		close(shutdowner)
+		signalShutdown()
 		wg.Wait()
 		c <- struct{}{}
 	}(c)
@@ -346,9 +954,7 @@ func Websocketshutdown(ws *exchange.Websocket) error {
 // streamDiversion is a diversion switch from websocket to REST or other
 // alternative feed
 func streamDiversion(ws *exchange.Websocket, verbose bool) {
-	wg.Add(1)
-	defer wg.Done()
-
+	logger := routinesLog.With("exchange", ws.GetName())
 	for {
 		select {
 		case <-shutdowner:
@@ -356,13 +962,12 @@ func streamDiversion(ws *exchange.Websocket, verbose bool) {
 
 		case <-ws.Connected:
 			if verbose {
-				log.Debugf("exchange %s websocket feed connected", ws.GetName())
+				logger.Debugln("Websocket feed connected")
 			}
 
 		case <-ws.Disconnected:
 			if verbose {
-				log.Debugf("exchange %s websocket feed disconnected, switching to REST functionality",
-					ws.GetName())
+				logger.Debugln("Websocket feed disconnected, switching to REST functionality")
 			}
 		}
 	}
@@ -371,11 +976,9 @@ func streamDiversion(ws *exchange.Websocket, verbose bool) {
 // WebsocketDataHandler handles websocket data coming from a websocket feed
 // associated with an exchange
 func WebsocketDataHandler(ws *exchange.Websocket, verbose bool) {
-	wg.Add(1)
-	defer wg.Done()
-
-	go streamDiversion(ws, verbose)
+	startRoutine(func() { streamDiversion(ws, verbose) })
 
+	logger := routinesLog.With("exchange", ws.GetName())
 	for {
 		select {
 		case <-shutdowner:
@@ -387,79 +990,238 @@ func WebsocketDataHandler(ws *exchange.Websocket, verbose bool) {
 				switch d {
 				case exchange.WebsocketNotEnabled:
 					if verbose {
-						log.Warnf("routines.go warning - exchange %s weboscket not enabled",
-							ws.GetName())
+						logger.Warn("Websocket not enabled")
 					}
 
 				default:
-					log.Infof(d)
+					logger.Info(d)
 				}
 
 			case error:
-				switch {
-				case common.StringContains(d.Error(), "close 1006"):
-					go WebsocketReconnect(ws, verbose)
+				if isWebsocketDisconnect(d) {
+					logger.Warnf("Websocket connection lost, reconnecting. Error: %s", d)
+					startRoutine(func() { WebsocketReconnect(ws, verbose) })
 					continue
-				default:
-					log.Errorf("routines.go exchange %s websocket error - %s", ws.GetName(), data)
 				}
+				logger.Errorf("Websocket error - %s", d)
 
 			case exchange.TradeData:
 				// Trade Data
 				if verbose {
-					log.Infoln("Websocket trades Updated:   ", d)
+					logger.Infoln("Websocket trades Updated:   ", d)
 				}
+				assetType := d.AssetType
+				if assetType == "" {
+					assetType = ticker.Spot
+				}
+				trade := trades.Trade{
+					Timestamp: d.Timestamp,
+					Price:     d.Price,
+					Amount:    d.Amount,
+					Side:      d.Side,
+				}
+				trades.Add(d.Exchange, d.CurrencyPair, assetType, trade)
+				hooks.dispatchTrade(d.Exchange, assetType, d.CurrencyPair, trade)
 
 			case exchange.TickerData:
 				// Ticker data
 				if verbose {
-					log.Infoln("Websocket Ticker Updated:   ", d)
+					logger.With("pair", d.Pair.Pair().String()).With("asset", d.AssetType).Infoln("Websocket Ticker Updated:   ", d)
 				}
+				price := ticker.Price{
+					Pair:         d.Pair,
+					CurrencyPair: d.Pair.Pair().String(),
+					LastUpdated:  d.Timestamp,
+					Last:         d.ClosePrice,
+					High:         d.HighPrice,
+					Low:          d.LowPrice,
+					Volume:       d.Quantity,
+				}
+				err := ticker.ProcessTicker(d.Exchange, d.Pair, price, d.AssetType)
+				if err != nil {
+					logger.Warnf("Websocket ticker rejected: %s", err)
+					continue
+				}
+				hooks.dispatchTicker(d.Exchange, d.AssetType, d.Pair, price)
 			case exchange.KlineData:
 				// Kline data
 				if verbose {
-					log.Infoln("Websocket Kline Updated:    ", d)
+					logger.Infoln("Websocket Kline Updated:    ", d)
 				}
 			case exchange.WebsocketOrderbookUpdate:
 				// Orderbook data
 				if verbose {
-					log.Infoln("Websocket Orderbook Updated:", d)
+					logger.With("pair", d.Pair.Pair().String()).With("asset", d.Asset).Infoln("Websocket Orderbook Updated:", d)
+				}
+				ob, err := orderbook.GetOrderbook(d.Exchange, d.Pair, d.Asset)
+				if err == nil {
+					hooks.dispatchOrderbook(d.Exchange, d.Asset, d.Pair, ob)
 				}
 			default:
 				if verbose {
-					log.Warnf("Websocket Unknown type:     %s", d)
+					logger.Warnf("Websocket Unknown type:     %s", d)
 				}
 			}
 		}
 	}
 }
 
-// WebsocketReconnect tries to reconnect to a websocket stream
-func WebsocketReconnect(ws *exchange.Websocket, verbose bool) {
-	if verbose {
-		log.Debugf("Websocket reconnection requested for %s", ws.GetName())
+// isWebsocketDisconnect returns whether an exchange websocket error means the
+// connection was lost, either by an abnormal closure or a failed read
+func isWebsocketDisconnect(err error) bool {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		return closeErr.Code != websocket.CloseNormalClosure
 	}
 
-	err := ws.Shutdown()
-	if err != nil {
-		log.Error(err)
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, websocket.ErrCloseSent) {
+		return true
+	}
+
+	// Exchange packages may only pass on the read error text
+	msg := err.Error()
+	if common.StringContains(msg, "websocket: close ") {
+		return !common.StringContains(msg, fmt.Sprintf("close %d ", websocket.CloseNormalClosure))
+	}
+	return common.StringContains(msg, "use of closed network connection") ||
+		common.StringContains(msg, "unexpected EOF")
+}
+
+// websocketReconnectState tracks the reconnection of an exchange websocket
+type websocketReconnectState struct {
+	running   bool
+	failures  int
+	connected time.Time
+}
+
+var (
+	websocketReconnects    = make(map[string]*websocketReconnectState)
+	websocketReconnectsMtx sync.Mutex
+
+	// websocketReconnectJitter returns the random part of a reconnect delay d,
+	// up to half of it
+	websocketReconnectJitter = func(d time.Duration) time.Duration {
+		return time.Duration(rand.Int63n(int64(d)/2 + 1))
+	}
+)
+
+// websocketReconnectDelay returns the delay before the next reconnect attempt
+// after a number of consecutive failures. The delay doubles with each failure
+// up to the max interval and half of it is random jitter
+func websocketReconnectDelay(failures int, cfg config.WebsocketReconnectConfig) time.Duration {
+	delay := cfg.InitialInterval
+	for x := 0; x < failures && delay < cfg.MaxInterval; x++ {
+		delay *= 2
+	}
+	if delay > cfg.MaxInterval {
+		delay = cfg.MaxInterval
+	}
+	return delay - delay/2 + websocketReconnectJitter(delay)
+}
+
+// WebsocketReconnectFailure holds an exchange websocket's consecutive failed
+// reconnect attempts
+type WebsocketReconnectFailure struct {
+	Exchange string `json:"exchange"`
+	Failures int    `json:"failures"`
+	Error    string `json:"error"`
+}
+
+// notifyWebsocketReconnectFailure pushes a websocket reconnect failure to the
+// enabled communication mediums and websocket clients
+func notifyWebsocketReconnectFailure(failure WebsocketReconnectFailure) {
+	details := fmt.Sprintf("%s websocket failed to reconnect %d consecutive times. Error: %s",
+		failure.Exchange, failure.Failures, failure.Error)
+	routinesLog.With("exchange", failure.Exchange).Errorf("%s.", details)
+
+	bot.comms.PushEvent(base.Event{
+		Type:         "websocket_reconnect_failure",
+		TradeDetails: details,
+	})
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(failure, WebsocketEventReconnectFailure, "", failure.Exchange)
+	}
+}
+
+// WebsocketReconnect reconnects an exchange websocket, backing off
+// exponentially between failed attempts. Consecutive failures are kept until a
+// connection stays up for the stable duration, so a flapping connection keeps
+// backing off. Only one reconnection runs per exchange
+func WebsocketReconnect(ws *exchange.Websocket, verbose bool) {
+	name := ws.GetName()
+	logger := routinesLog.With("exchange", name)
+	cfg := bot.config.WebsocketReconnect
+
+	websocketReconnectsMtx.Lock()
+	state, ok := websocketReconnects[name]
+	if !ok {
+		state = &websocketReconnectState{}
+		websocketReconnects[name] = state
+	}
+	if state.running {
+		websocketReconnectsMtx.Unlock()
 		return
 	}
+	state.running = true
+	if !state.connected.IsZero() {
+		if time.Since(state.connected) >= cfg.StableDuration {
+			state.failures = 0
+		} else {
+			state.failures++
+		}
+	}
+	failures := state.failures
+	websocketReconnectsMtx.Unlock()
 
-	wg.Add(1)
-	defer wg.Done()
+	defer func() {
+		websocketReconnectsMtx.Lock()
+		state.running = false
+		websocketReconnectsMtx.Unlock()
+	}()
+
+	if verbose {
+		logger.Debugln("Websocket reconnection requested")
+	}
+
+	if ws.IsConnected() {
+		err := ws.Shutdown()
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+	}
 
-	ticker := time.NewTicker(3 * time.Second)
 	for {
-		select {
-		case <-shutdowner:
+		delay := websocketReconnectDelay(failures, cfg)
+		if verbose {
+			logger.Debugf("Websocket reconnecting in %v", delay)
+		}
+		if !waitOrShutdown(delay) || !ws.IsEnabled() {
 			return
+		}
 
-		case <-ticker.C:
-			err = ws.Connect()
-			if err == nil {
-				return
-			}
+		err := ws.Connect()
+		websocketReconnectsMtx.Lock()
+		if err == nil {
+			state.connected = time.Now()
+			websocketReconnectsMtx.Unlock()
+			logger.Infof("Websocket reconnected after %d consecutive failures", failures)
+			return
+		}
+		state.failures++
+		failures = state.failures
+		websocketReconnectsMtx.Unlock()
+
+		logger.Warnf("Websocket reconnect attempt failed. Error: %s", err)
+		if failures == cfg.FailureThreshold {
+			notifyWebsocketReconnectFailure(WebsocketReconnectFailure{
+				Exchange: name,
+				Failures: failures,
+				Error:    err.Error(),
+			})
 		}
 	}
 }
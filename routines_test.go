@@ -0,0 +1,652 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications"
+	"github.com/thrasher-/gocryptotrader/communications/base"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/portfolio"
+)
+
+// listPairs makes the exchange list the pairs with the trading statuses on
+// its tradable pair updates, replacing its available pairs and disabling
+// enabled pairs which are no longer listed
+func listPairs(exch *testExchange, listed []pair.CurrencyPair, statuses []exchange.PairStatus) {
+	exch.updateTradablePairs = func(forceUpdate bool) error {
+		exch.availablePairs = listed
+		exch.base.SetPairStatuses(statuses)
+		var enabled []pair.CurrencyPair
+		for x := range exch.enabledPairs {
+			if pair.Contains(listed, exch.enabledPairs[x], true) {
+				enabled = append(enabled, exch.enabledPairs[x])
+			}
+		}
+		exch.enabledPairs = enabled
+		return nil
+	}
+}
+
+func setAutoEnableNewPairs(t *testing.T, exchName string, enabled bool) {
+	exchCfg, err := bot.config.GetExchangeConfig(exchName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchCfg.AutoEnableNewPairs = enabled
+	err = bot.config.UpdateExchangeConfig(exchCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartRoutine(t *testing.T) {
+	release := make(chan struct{})
+	startRoutine(func() { <-release })
+
+	waited := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+		t.Fatal("Test failed. Routine not tracked by the wait group")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+	select {
+	case <-waited:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. Routine not removed from the wait group")
+	}
+}
+
+func TestUpdateExchangePairs(t *testing.T) {
+	SetupTestHelpers(t)
+
+	btcusd := pair.NewCurrencyPair("BTC", "USD")
+	ltcusd := pair.NewCurrencyPair("LTC", "USD")
+	ethusd := pair.NewCurrencyPair("ETH", "USD")
+
+	exch := newTestExchange("Bitfinex")
+	exch.availablePairs = []pair.CurrencyPair{btcusd, ltcusd}
+	listPairs(exch, []pair.CurrencyPair{btcusd, ethusd}, nil)
+
+	setAutoEnableNewPairs(t, exch.GetName(), false)
+	err := updateExchangePairs(exch)
+	if err != nil {
+		t.Fatalf("Test failed. updateExchangePairs error: %s", err)
+	}
+
+	if !pair.Contains(exch.availablePairs, ethusd, true) ||
+		pair.Contains(exch.availablePairs, ltcusd, true) {
+		t.Fatalf("Test failed. Unexpected available pairs %v",
+			pair.PairsToStringArray(exch.availablePairs))
+	}
+
+	if pair.Contains(exch.enabledPairs, ethusd, true) {
+		t.Fatal("Test failed. New pair enabled without autoEnableNewPairs set")
+	}
+
+	listPairs(exch, []pair.CurrencyPair{btcusd, ethusd, ltcusd}, nil)
+	setAutoEnableNewPairs(t, exch.GetName(), true)
+	defer setAutoEnableNewPairs(t, exch.GetName(), false)
+	err = updateExchangePairs(exch)
+	if err != nil {
+		t.Fatalf("Test failed. updateExchangePairs error: %s", err)
+	}
+
+	if !pair.Contains(exch.enabledPairs, ltcusd, true) ||
+		!pair.Contains(exch.enabledPairs, btcusd, true) ||
+		pair.Contains(exch.enabledPairs, ethusd, true) {
+		t.Fatalf("Test failed. Unexpected enabled pairs %v",
+			pair.PairsToStringArray(exch.enabledPairs))
+	}
+
+	ticker.ProcessTicker(exch.GetName(), btcusd, ticker.Price{Last: 1}, ticker.Spot)
+	ticker.ProcessTicker(exch.GetName(), ltcusd, ticker.Price{Last: 1}, ticker.Spot)
+	defer ticker.RemoveExchangeTickers(exch.GetName())
+	listPairs(exch, []pair.CurrencyPair{ethusd, ltcusd}, nil)
+	err = updateExchangePairs(exch)
+	if err != nil {
+		t.Fatalf("Test failed. updateExchangePairs error: %s", err)
+	}
+
+	if _, err = ticker.GetTicker(exch.GetName(), btcusd, ticker.Spot); err == nil {
+		t.Error("Test failed. Ticker of the disabled pair not removed")
+	}
+	if _, err = ticker.GetTicker(exch.GetName(), ltcusd, ticker.Spot); err != nil {
+		t.Errorf("Test failed. Ticker of an enabled pair removed: %s", err)
+	}
+
+	// Unknown currencies of new pairs are registered as cryptocurrencies
+	defer func(cryptos []string) { currency.CryptoCurrencies = cryptos }(currency.CryptoCurrencies)
+	newcoin := pair.NewCurrencyPair("NEWCOIN", "USD")
+	listPairs(exch, []pair.CurrencyPair{ethusd, ltcusd, newcoin}, nil)
+	err = updateExchangePairs(exch)
+	if err != nil {
+		t.Fatalf("Test failed. updateExchangePairs error: %s", err)
+	}
+	if !currency.IsCryptocurrency("NEWCOIN") || currency.IsCryptocurrency("USD") {
+		t.Error("Test failed. New pair currency not registered as a cryptocurrency")
+	}
+}
+
+// newBalanceExchange returns a test exchange returning the balances on each
+// account info request or failing with err if it's set
+func newBalanceExchange(name string, balances map[string]float64, err error) *testExchange {
+	exch := newTestExchange(name)
+	exch.getAccountInfo = func() (exchange.AccountInfo, error) {
+		if err != nil {
+			return exchange.AccountInfo{}, err
+		}
+		var account exchange.Account
+		for currencyName, balance := range balances {
+			account.Currencies = append(account.Currencies,
+				exchange.AccountCurrencyInfo{CurrencyName: currencyName, TotalValue: balance})
+		}
+		return exchange.AccountInfo{Exchange: name, Accounts: []exchange.Account{account}}, nil
+	}
+	return exch
+}
+
+// newTickerExchange returns a test exchange with three enabled pairs whose
+// ticker requests succeed. Batch updates store every enabled pair in the
+// ticker cache with a single request, or fail with batchErr if it's set
+func newTickerExchange(name string, batching bool, batchErr error) *testExchange {
+	exch := newTestExchange(name)
+	exch.base.SupportsRESTTickerBatching = batching
+	exch.enabledPairs = []pair.CurrencyPair{
+		pair.NewCurrencyPair("BTC", "USD"),
+		pair.NewCurrencyPair("ETH", "USD"),
+		pair.NewCurrencyPair("LTC", "USD"),
+	}
+	exch.updateTickers = func(assetType string) error {
+		if batchErr != nil {
+			return batchErr
+		}
+		for _, p := range exch.GetEnabledCurrencies() {
+			ticker.ProcessTicker(name, p, ticker.Price{Pair: p, Last: 1}, assetType)
+		}
+		return nil
+	}
+	exch.updateTicker = func(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+		return ticker.Price{Pair: p, Last: 1}, nil
+	}
+	exch.getTickerPrice = exch.updateTicker
+	return exch
+}
+
+func TestUpdateExchangeTickers(t *testing.T) {
+	SetupTestHelpers(t)
+
+	tests := []struct {
+		exch     *testExchange
+		batches  int
+		requests int
+		cached   int
+	}{
+		{newTickerExchange("TickerTest", true, nil), 1, 0, 0},
+		{newTickerExchange("TickerTest", true, common.ErrFunctionNotSupported), 1, 1, 2},
+		{newTickerExchange("TickerTest", false, nil), 0, 3, 0},
+	}
+	for x := range tests {
+		updateExchangeTickers(tests[x].exch)
+		batches := tests[x].exch.callCount("UpdateTickers")
+		requests := tests[x].exch.callCount("UpdateTicker")
+		cached := tests[x].exch.callCount("GetTickerPrice")
+		if batches != tests[x].batches || requests != tests[x].requests ||
+			cached != tests[x].cached {
+			t.Errorf("Test failed. Test %d expected %d batches, %d requests and %d cached reads, got %d, %d and %d",
+				x, tests[x].batches, tests[x].requests, tests[x].cached, batches,
+				requests, cached)
+		}
+	}
+
+	// A failed batch update is reported without falling back to per pair
+	// requests
+	exch := newTickerExchange("TickerTest", true, errors.New("rawr"))
+	updateExchangeTickers(exch)
+	if n, cached := exch.callCount("UpdateTicker"), exch.callCount("GetTickerPrice"); n != 0 || cached != 0 {
+		t.Errorf("Test failed. Failed batch update made %d requests and %d cached reads",
+			n, cached)
+	}
+}
+
+func TestSweepStaleMarketData(t *testing.T) {
+	p := pair.NewCurrencyPair("BTC", "USD")
+	ticker.ProcessTicker("SweepTest", p, ticker.Price{Last: 1}, ticker.Spot)
+	orderbook.ProcessOrderbook("SweepTest", p, orderbook.Base{}, orderbook.Spot)
+
+	sweepStaleMarketData(time.Hour)
+	if ticker.GetTickerCounts()["SweepTest"] != 1 ||
+		orderbook.GetOrderbookCounts()["SweepTest"] != 1 {
+		t.Fatal("Test failed. Market data removed before the max age")
+	}
+
+	time.Sleep(time.Millisecond * 10)
+	sweepStaleMarketData(time.Millisecond)
+	if ticker.GetTickerCounts()["SweepTest"] != 0 ||
+		orderbook.GetOrderbookCounts()["SweepTest"] != 0 {
+		t.Error("Test failed. Stale market data not removed")
+	}
+}
+
+func TestDiffBalances(t *testing.T) {
+	previous := map[string]float64{"BTC": 1, "LTC": 100, "ETH": 5}
+	current := map[string]float64{"BTC": 1.5, "LTC": 101, "XRP": 10}
+
+	changes := diffBalances("Bitfinex", previous, current, 0)
+	if len(changes) != 4 {
+		t.Fatalf("Test failed. Expected 4 changes, got %v", changes)
+	}
+
+	changes = diffBalances("Bitfinex", previous, current, 5)
+	result := make(map[string]BalanceChange)
+	for x := range changes {
+		result[changes[x].Currency] = changes[x]
+	}
+	if len(result) != 3 {
+		t.Fatalf("Test failed. Expected 3 changes above threshold, got %v", changes)
+	}
+	if _, ok := result["LTC"]; ok {
+		t.Error("Test failed. Change below threshold reported")
+	}
+	if result["BTC"].Change != 0.5 || result["BTC"].Previous != 1 ||
+		result["BTC"].Current != 1.5 {
+		t.Errorf("Test failed. Unexpected BTC change %v", result["BTC"])
+	}
+	if result["ETH"].Current != 0 || result["XRP"].Previous != 0 {
+		t.Errorf("Test failed. Removed or added currencies not reported %v",
+			changes)
+	}
+}
+
+func TestRefreshExchangeBalances(t *testing.T) {
+	SetupTestHelpers(t)
+
+	exchangeHealthMtx.Lock()
+	exchangeHealth = make(map[string]ExchangeHealth)
+	exchangeHealthMtx.Unlock()
+	setExchangeHealth("BalanceUp", ExchangeStatusUp, 0, nil)
+	setExchangeHealth("BalanceDown", ExchangeStatusUp, 0, nil)
+
+	balances := map[string]float64{"BTC": 1}
+	up := newBalanceExchange("BalanceUp", balances, nil)
+	down := newBalanceExchange("BalanceDown", nil, exchange.ErrExchangeUnavailable)
+
+	oldExchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{down, up}
+	defer func() { bot.exchanges = oldExchanges }()
+
+	changes := refreshExchangeBalances()
+	if len(changes) != 0 {
+		t.Fatalf("Test failed. Initial snapshot reported changes %v", changes)
+	}
+
+	h, err := GetExchangeHealth("BalanceUp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(h.LastBalanceRefresh) > time.Minute {
+		t.Errorf("Test failed. Last balance refresh not updated %v",
+			h.LastBalanceRefresh)
+	}
+
+	h, err = GetExchangeHealth("BalanceDown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.LastBalanceRefresh.IsZero() {
+		t.Error("Test failed. Last balance refresh updated for failing exchange")
+	}
+
+	balances["BTC"] = 2
+	changes = refreshExchangeBalances()
+	if len(changes) != 1 || changes[0].Exchange != "BalanceUp" ||
+		changes[0].Currency != "BTC" || changes[0].Change != 1 {
+		t.Fatalf("Test failed. Unexpected balance changes %v", changes)
+	}
+
+	balance, ok := portfolio.GetPortfolio().GetAddressBalance("BalanceUp", "BTC",
+		portfolio.PortfolioAddressExchange)
+	if !ok || balance != 2 {
+		t.Errorf("Test failed. Portfolio balance not updated, got %f", balance)
+	}
+}
+
+func TestRequestBalanceRefresh(t *testing.T) {
+	RequestBalanceRefresh()
+	RequestBalanceRefresh()
+
+	select {
+	case <-balanceRefresh:
+	default:
+		t.Fatal("Test failed. Balance refresh not requested")
+	}
+
+	select {
+	case <-balanceRefresh:
+		t.Error("Test failed. Duplicate balance refresh requests not merged")
+	default:
+	}
+}
+
+// mockBalanceProvider returns the configured address and token balances,
+// failing for addresses without a balance and coins other than BTC and ETH.
+// fetched is called before an address balance is returned
+type mockBalanceProvider struct {
+	balances map[string]float64
+	tokens   map[string]map[string]float64
+	fetched  func(address string)
+}
+
+func (m *mockBalanceProvider) GetAddressBalance(coinType, address string) (float64, error) {
+	if coinType != "BTC" && coinType != "ETH" {
+		return 0, portfolio.ErrCoinNotSupported
+	}
+	if m.fetched != nil {
+		m.fetched(address)
+	}
+	balance, ok := m.balances[address]
+	if !ok {
+		return 0, exchange.ErrExchangeUnavailable
+	}
+	return balance, nil
+}
+
+func (m *mockBalanceProvider) GetTokenBalances(coinType, address string) (map[string]float64, error) {
+	tokens, ok := m.tokens[address]
+	if !ok {
+		return nil, exchange.ErrExchangeUnavailable
+	}
+	return tokens, nil
+}
+
+func TestRefreshPortfolioAddresses(t *testing.T) {
+	SetupTestHelpers(t)
+
+	port := &portfolio.Base{Addresses: []portfolio.Address{
+		{Address: "changed", CoinType: "BTC", Balance: 1, Description: portfolio.PortfolioAddressPersonal},
+		{Address: "emptied", CoinType: "BTC", Balance: 1, Description: portfolio.PortfolioAddressPersonal},
+		{Address: "failing", CoinType: "BTC", Balance: 2},
+		{Address: "unsupported", CoinType: "DOGE", Balance: 3},
+		{Address: "Bitfinex", CoinType: "BTC", Balance: 4, Description: portfolio.PortfolioAddressExchange},
+	}}
+	provider := &mockBalanceProvider{balances: map[string]float64{
+		"changed":  1.5,
+		"emptied":  0,
+		"Bitfinex": 10,
+	}}
+
+	changes := refreshPortfolioAddresses(port, provider)
+	if len(changes) != 2 || changes[0].Address != "changed" ||
+		changes[0].Change != 0.5 || changes[1].Address != "emptied" {
+		t.Fatalf("Test failed. Unexpected address balance changes %v", changes)
+	}
+
+	expected := map[string]float64{
+		"changed":     1.5,
+		"emptied":     0,
+		"failing":     2,
+		"unsupported": 3,
+		"Bitfinex":    4,
+	}
+	if len(port.Addresses) != len(expected) {
+		t.Fatalf("Test failed. Portfolio addresses removed %v", port.Addresses)
+	}
+	for x := range port.Addresses {
+		if port.Addresses[x].Balance != expected[port.Addresses[x].Address] {
+			t.Errorf("Test failed. %s balance expected %f got %f",
+				port.Addresses[x].Address, expected[port.Addresses[x].Address],
+				port.Addresses[x].Balance)
+		}
+	}
+
+	threshold := bot.config.BalanceChangeThreshold
+	bot.config.BalanceChangeThreshold = 60
+	defer func() { bot.config.BalanceChangeThreshold = threshold }()
+	provider.balances["changed"] = 2
+	changes = refreshPortfolioAddresses(port, provider)
+	if len(changes) != 0 {
+		t.Errorf("Test failed. Change below threshold reported %v", changes)
+	}
+	balance, _ := port.GetAddressBalance("changed", "BTC", portfolio.PortfolioAddressPersonal)
+	if balance != 2 {
+		t.Error("Test failed. Balance below threshold not updated")
+	}
+}
+
+func TestRefreshPortfolioAddressTokens(t *testing.T) {
+	SetupTestHelpers(t)
+
+	port := &portfolio.Base{}
+	_, err := port.RegisterAddress("0xb794f5ea0ba39494ce839613fffba74279579268", "ETH",
+		"", 1, []string{"USDT", "OMG"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	port.SetTokenBalance(port.Addresses[0].Address, "ETH", "OMG", 5)
+
+	provider := &mockBalanceProvider{
+		balances: map[string]float64{port.Addresses[0].Address: 1},
+		tokens: map[string]map[string]float64{
+			port.Addresses[0].Address: {"USDT": 100, "DAI": 3},
+		},
+	}
+	changes := refreshPortfolioAddresses(port, provider)
+	if len(changes) != 2 || changes[0].Token != "USDT" || changes[0].Current != 100 ||
+		changes[1].Token != "OMG" || changes[1].Current != 0 || changes[1].Change != -5 {
+		t.Fatalf("Test failed. Unexpected token balance changes %v", changes)
+	}
+
+	tokens := port.Addresses[0].Tokens
+	if len(tokens) != 2 || tokens[0].Balance != 100 || tokens[1].Balance != 0 {
+		t.Errorf("Test failed. Unexpected tracked tokens %v", tokens)
+	}
+
+	delete(provider.tokens, port.Addresses[0].Address)
+	provider.balances[port.Addresses[0].Address] = 2
+	changes = refreshPortfolioAddresses(port, provider)
+	if len(changes) != 1 || changes[0].Token != "" ||
+		port.Addresses[0].Tokens[0].Balance != 100 {
+		t.Errorf("Test failed. Failed token refresh changed balances %v", changes)
+	}
+}
+
+func TestRefreshPortfolioAddressesRemoved(t *testing.T) {
+	SetupTestHelpers(t)
+
+	port := &portfolio.Base{}
+	port.AddAddress("removed", "BTC", portfolio.PortfolioAddressPersonal, 1)
+	port.AddAddress("kept", "BTC", portfolio.PortfolioAddressPersonal, 1)
+
+	// The address is removed while its balance is being fetched
+	provider := &mockBalanceProvider{
+		balances: map[string]float64{"removed": 5, "kept": 5},
+		fetched: func(address string) {
+			if address == "removed" {
+				port.RemoveAddress(address, "BTC", portfolio.PortfolioAddressPersonal)
+			}
+		},
+	}
+
+	changes := refreshPortfolioAddresses(port, provider)
+	if len(changes) != 1 || changes[0].Address != "kept" {
+		t.Errorf("Test failed. Unexpected address balance changes %v", changes)
+	}
+	addresses := port.GetAddresses()
+	if len(addresses) != 1 || addresses[0].Balance != 5 {
+		t.Errorf("Test failed. Unexpected portfolio addresses %v", addresses)
+	}
+}
+
+// mockComm records the events pushed to it
+type mockComm struct {
+	base.Base
+	events []base.Event
+	m      sync.Mutex
+}
+
+func (m *mockComm) Setup(cfg config.CommunicationsConfig) {}
+
+func (m *mockComm) Connect() error {
+	return nil
+}
+
+func (m *mockComm) PushEvent(event base.Event) error {
+	m.m.Lock()
+	m.events = append(m.events, event)
+	m.m.Unlock()
+	return nil
+}
+
+// pushed returns the events pushed so far
+func (m *mockComm) pushed() []base.Event {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return append([]base.Event(nil), m.events...)
+}
+
+func TestWebsocketReconnectDelay(t *testing.T) {
+	defer func(j func(time.Duration) time.Duration) { websocketReconnectJitter = j }(websocketReconnectJitter)
+	websocketReconnectJitter = func(d time.Duration) time.Duration { return d / 2 }
+
+	cfg := config.WebsocketReconnectConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Second * 10,
+	}
+	expected := []time.Duration{time.Second, time.Second * 2, time.Second * 4,
+		time.Second * 8, time.Second * 10, time.Second * 10}
+	for x := range expected {
+		delay := websocketReconnectDelay(x, cfg)
+		if delay != expected[x] {
+			t.Errorf("Test failed. Failure %d expected delay %v, got %v", x, expected[x], delay)
+		}
+	}
+
+	websocketReconnectJitter = func(d time.Duration) time.Duration { return 0 }
+	if delay := websocketReconnectDelay(2, cfg); delay != time.Second*2 {
+		t.Errorf("Test failed. Expected minimum delay %v, got %v", time.Second*2, delay)
+	}
+}
+
+func TestIsWebsocketDisconnect(t *testing.T) {
+	tests := []struct {
+		err        error
+		disconnect bool
+	}{
+		{&websocket.CloseError{Code: websocket.CloseAbnormalClosure}, true},
+		{&websocket.CloseError{Code: websocket.CloseGoingAway}, true},
+		{&websocket.CloseError{Code: websocket.CloseNormalClosure}, false},
+		{io.ErrUnexpectedEOF, true},
+		{&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, true},
+		{errors.New("bitfinex_websocket.go - websocket: close 1006 (abnormal closure): unexpected EOF"), true},
+		{errors.New("websocket: close 1000 (normal)"), false},
+		{errors.New("could not find currency pair in map"), false},
+	}
+	for x := range tests {
+		if isWebsocketDisconnect(tests[x].err) != tests[x].disconnect {
+			t.Errorf("Test failed. %v expected disconnect %t", tests[x].err, tests[x].disconnect)
+		}
+	}
+}
+
+func TestWebsocketReconnect(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(c *communications.Communications, cfg config.WebsocketReconnectConfig, webserver bool) {
+		bot.comms = c
+		bot.config.WebsocketReconnect = cfg
+		bot.config.Webserver.Enabled = webserver
+	}(bot.comms, bot.config.WebsocketReconnect, bot.config.Webserver.Enabled)
+	defer func(j func(time.Duration) time.Duration) { websocketReconnectJitter = j }(websocketReconnectJitter)
+
+	comm := &mockComm{Base: base.Base{Name: "mock", Enabled: true, Connected: true}}
+	bot.comms = &communications.Communications{IComm: base.IComm{comm}}
+	bot.config.Webserver.Enabled = false
+	bot.config.WebsocketReconnect = config.WebsocketReconnectConfig{
+		InitialInterval:  time.Millisecond,
+		MaxInterval:      time.Millisecond * 4,
+		StableDuration:   time.Hour,
+		FailureThreshold: 3,
+	}
+
+	var delays []time.Duration
+	websocketReconnectJitter = func(d time.Duration) time.Duration {
+		delays = append(delays, d)
+		return d / 2
+	}
+
+	var connects, failures int
+	var e exchange.Base
+	e.WebsocketInit()
+	err := e.WebsocketSetup(func() error {
+		connects++
+		if connects <= failures {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, "ReconnectTest", true, "wss://localhost", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := e.Websocket
+	defer ws.Shutdown()
+	defer func() {
+		websocketReconnectsMtx.Lock()
+		delete(websocketReconnects, "ReconnectTest")
+		websocketReconnectsMtx.Unlock()
+	}()
+
+	// Fails 4 times, notifying once the threshold is reached
+	failures = 4
+	WebsocketReconnect(ws, false)
+	<-ws.Connected
+	expected := []time.Duration{time.Millisecond, time.Millisecond * 2,
+		time.Millisecond * 4, time.Millisecond * 4, time.Millisecond * 4}
+	if connects != 5 || !ws.IsConnected() {
+		t.Fatalf("Test failed. Expected 5 connection attempts, got %d", connects)
+	}
+	if !reflect.DeepEqual(delays, expected) {
+		t.Errorf("Test failed. Expected backoff %v, got %v", expected, delays)
+	}
+	if len(comm.events) != 1 || comm.events[0].Type != "websocket_reconnect_failure" {
+		t.Errorf("Test failed. Expected one failure notification, got %v", comm.events)
+	}
+
+	// Dropping before the stable duration keeps backing off
+	connects, failures, delays = 0, 0, nil
+	WebsocketReconnect(ws, false)
+	<-ws.Disconnected
+	<-ws.Connected
+	if connects != 1 || !reflect.DeepEqual(delays, []time.Duration{time.Millisecond * 4}) {
+		t.Errorf("Test failed. Unstable connection backoff reset %d %v", connects, delays)
+	}
+
+	// A stable connection resets the backoff
+	websocketReconnectsMtx.Lock()
+	websocketReconnects["ReconnectTest"].connected = time.Now().Add(-time.Hour)
+	websocketReconnectsMtx.Unlock()
+	connects, delays = 0, nil
+	WebsocketReconnect(ws, false)
+	<-ws.Disconnected
+	<-ws.Connected
+	if connects != 1 || !reflect.DeepEqual(delays, []time.Duration{time.Millisecond}) {
+		t.Errorf("Test failed. Stable connection backoff not reset %d %v", connects, delays)
+	}
+}
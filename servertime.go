@@ -0,0 +1,117 @@
+package main
+
+import (
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+const (
+	// serverTimeSyncInterval is how often exchange server time offsets are
+	// remeasured
+	serverTimeSyncInterval = time.Minute * 30
+	// serverTimeSkewWarning is the clock skew above which a warning is logged
+	serverTimeSkewWarning = time.Second * 5
+)
+
+var serverTimeLog = log.NewSubLogger("engine.servertime")
+
+// ServerTime holds an exchange server time compared to the local clock
+type ServerTime struct {
+	Exchange   string        `json:"exchange"`
+	ServerTime time.Time     `json:"serverTime"`
+	LocalTime  time.Time     `json:"localTime"`
+	Offset     time.Duration `json:"offset"`
+}
+
+// measureServerTimeOffset returns the offset between an exchange server
+// clock and the local clock. The server time is compared against the
+// midpoint of the request round trip
+func measureServerTimeOffset(exch exchange.IBotExchange) (ServerTime, error) {
+	start := time.Now()
+	serverTime, err := exch.GetServerTime()
+	if err != nil {
+		return ServerTime{}, err
+	}
+	local := start.Add(time.Since(start) / 2)
+	return ServerTime{
+		Exchange:   exch.GetName(),
+		ServerTime: serverTime,
+		LocalTime:  local,
+		Offset:     serverTime.Sub(local),
+	}, nil
+}
+
+// syncServerTimeOffsets measures the server time offset of every enabled
+// exchange supporting it and applies it to their signed requests. Offsets
+// larger than serverTimeSkewWarning are logged as warnings
+func syncServerTimeOffsets() {
+	for x := range bot.exchanges {
+		exch := bot.exchanges[x]
+		if exch == nil || !exch.IsEnabled() ||
+			IsExchangeInMaintenance(exch.GetName()) {
+			continue
+		}
+
+		logger := serverTimeLog.With("exchange", exch.GetName())
+		result, err := measureServerTimeOffset(exch)
+		if err != nil {
+			if err != common.ErrFunctionNotSupported &&
+				!checkExchangeMaintenance(exch, err) {
+				logger.Errorf("Failed to get server time. Error: %s", err)
+			}
+			continue
+		}
+
+		exch.SetServerTimeOffset(result.Offset)
+		if absDuration(result.Offset) > serverTimeSkewWarning {
+			logger.Warnf("Local clock is %v off the exchange server time, signed request timestamps will be adjusted.",
+				result.Offset)
+			continue
+		}
+		logger.Debugf("Server time offset: %v.", result.Offset)
+	}
+}
+
+// absDuration returns the absolute value of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// GetExchangeServerTime measures the server time offset of an exchange and
+// applies it to its signed requests
+func GetExchangeServerTime(name string) (ServerTime, error) {
+	exch := GetExchangeByName(name)
+	if exch == nil {
+		return ServerTime{}, ErrExchangeNotFound
+	}
+
+	result, err := measureServerTimeOffset(exch)
+	if err != nil {
+		return ServerTime{}, err
+	}
+	exch.SetServerTimeOffset(result.Offset)
+	return result, nil
+}
+
+// ServerTimeSyncRoutine measures the exchange server time offsets on startup
+// and then on every serverTimeSyncInterval
+func ServerTimeSyncRoutine() {
+	wg.Add(1)
+	defer wg.Done()
+
+	serverTimeLog.Debugf("Starting server time sync routine. Sync interval: %v.",
+		serverTimeSyncInterval)
+	for {
+		syncServerTimeOffsets()
+		if !waitOrShutdown(serverTimeSyncInterval) {
+			serverTimeLog.Debugln("Server time sync routine stopped.")
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// newServerTimeExchange returns a test exchange whose server clock is skewed
+// from the local clock, failing with err if it's set
+func newServerTimeExchange(name string, skew time.Duration, err error) *testExchange {
+	exch := newTestExchange(name)
+	exch.getServerTime = func() (time.Time, error) {
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Now().Add(skew), nil
+	}
+	return exch
+}
+
+func TestSyncServerTimeOffsets(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	ahead := newServerTimeExchange("Ahead", time.Minute, nil)
+	behind := newServerTimeExchange("Behind", -time.Second, nil)
+	unsupported := newServerTimeExchange("Unsupported", 0, common.ErrFunctionNotSupported)
+	unsupported.SetServerTimeOffset(time.Hour)
+	failing := newServerTimeExchange("Failing", 0, errors.New("rawr"))
+	failing.SetServerTimeOffset(time.Hour)
+	bot.exchanges = []exchange.IBotExchange{ahead, behind, unsupported,
+		failing}
+
+	syncServerTimeOffsets()
+	if offset := ahead.base.GetServerTimeOffset(); absDuration(offset-time.Minute) > time.Second {
+		t.Errorf("Test failed. Expected offset of about %v, got %v",
+			time.Minute, offset)
+	}
+	if offset := behind.base.GetServerTimeOffset(); absDuration(offset+time.Second) > time.Second {
+		t.Errorf("Test failed. Expected offset of about %v, got %v",
+			-time.Second, offset)
+	}
+	if unsupported.base.GetServerTimeOffset() != time.Hour ||
+		failing.base.GetServerTimeOffset() != time.Hour {
+		t.Error("Test failed. Offset changed for exchanges without server time")
+	}
+
+	result, err := GetExchangeServerTime("ahead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Exchange != "Ahead" || result.Offset != ahead.base.GetServerTimeOffset() ||
+		result.ServerTime.Sub(result.LocalTime) != result.Offset {
+		t.Errorf("Test failed. Unexpected server time result %+v", result)
+	}
+
+	_, err = GetExchangeServerTime("unsupported")
+	if err != common.ErrFunctionNotSupported {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+	_, err = GetExchangeServerTime("404")
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrExchangeNotFound, err)
+	}
+}
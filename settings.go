@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Optional subsystem names
+const (
+	SubsystemCommunications     = "communications"
+	SubsystemEvents             = "events"
+	SubsystemPortfolioManager   = "portfolio_manager"
+	SubsystemWebserver          = "webserver"
+	SubsystemWebsocketRPC       = "websocket_rpc"
+	SubsystemUpdaterRoutines    = "updater_routines"
+	SubsystemExchangeWebsockets = "exchange_websockets"
+)
+
+// Settings holds the startup flags of the bot. Each optional subsystem is
+// started only when its flag is set, the webserver additionally has to be
+// enabled in the config
+type Settings struct {
+	ConfigFile    string `json:"configFile"`
+	DataDir       string `json:"dataDir"`
+	DryRun        bool   `json:"dryRun"`
+	Verbose       bool   `json:"verbose"`
+	PortfolioOnly bool   `json:"portfolioOnly"`
+
+	EnableWebserver          bool `json:"enableWebserver"`
+	EnableWebsocketRPC       bool `json:"enableWebsocketRPC"`
+	EnablePortfolioManager   bool `json:"enablePortfolioManager"`
+	EnableUpdaterRoutines    bool `json:"enableUpdaterRoutines"`
+	EnableExchangeWebsockets bool `json:"enableExchangeWebsockets"`
+	EnableEvents             bool `json:"enableEvents"`
+	EnableCommunications     bool `json:"enableCommunications"`
+}
+
+// ActiveSettings holds the settings the bot was started with, the resolved
+// config file and data directory paths and the subsystems running
+type ActiveSettings struct {
+	Settings
+	ResolvedConfigFile string   `json:"resolvedConfigFile"`
+	ResolvedDataDir    string   `json:"resolvedDataDir"`
+	RunningSubsystems  []string `json:"runningSubsystems"`
+}
+
+var (
+	subsystemsMtx     sync.Mutex
+	runningSubsystems = make(map[string]bool)
+)
+
+// registerSettingsFlags registers the startup settings flags on a flag set,
+// subsystems default to enabled
+func registerSettingsFlags(fs *flag.FlagSet, s *Settings, defaultConfigPath string) {
+	fs.StringVar(&s.ConfigFile, "config", defaultConfigPath, "config file to load")
+	fs.StringVar(&s.DataDir, "datadir", common.GetDefaultDataDir(runtime.GOOS), "default data directory for GoCryptoTrader files")
+	fs.BoolVar(&s.DryRun, "dryrun", false, "dry runs bot, doesn't save config file")
+	fs.BoolVar(&s.Verbose, "verbose", false, "increases logging verbosity for GoCryptoTrader")
+	fs.BoolVar(&s.PortfolioOnly, "portfolioonly", false, "allows running with no enabled exchanges for portfolio tracking and forex rates only")
+	fs.BoolVar(&s.EnableWebserver, "webserver", true, "starts the REST webserver when it is enabled in the config")
+	fs.BoolVar(&s.EnableWebsocketRPC, "websocketrpc", true, "starts the websocket RPC handler of the webserver")
+	fs.BoolVar(&s.EnablePortfolioManager, "portfoliomanager", true, "starts the portfolio address watcher")
+	fs.BoolVar(&s.EnableUpdaterRoutines, "updaterroutines", true, "starts the ticker, orderbook, pair, market data sweeper, balance refresher, order fill and server time routines")
+	fs.BoolVar(&s.EnableExchangeWebsockets, "exchangewebsockets", true, "connects the exchange websocket services")
+	fs.BoolVar(&s.EnableEvents, "events", true, "loads the saved events and checks their conditions")
+	fs.BoolVar(&s.EnableCommunications, "communications", true, "starts the communication mediums enabled in the config")
+}
+
+// setSubsystemRunning records whether an optional subsystem is running
+func setSubsystemRunning(name string, running bool) {
+	subsystemsMtx.Lock()
+	defer subsystemsMtx.Unlock()
+	if running {
+		runningSubsystems[name] = true
+		return
+	}
+	delete(runningSubsystems, name)
+}
+
+// isSubsystemRunning returns whether an optional subsystem is running
+func isSubsystemRunning(name string) bool {
+	subsystemsMtx.Lock()
+	defer subsystemsMtx.Unlock()
+	return runningSubsystems[name]
+}
+
+// GetActiveSettings returns the settings the bot was started with, its
+// resolved config file and data directory paths and the running subsystems
+func GetActiveSettings() ActiveSettings {
+	active := ActiveSettings{
+		Settings:           bot.settings,
+		ResolvedConfigFile: resolvePath(bot.settings.ConfigFile),
+		ResolvedDataDir:    resolvePath(bot.settings.DataDir),
+		RunningSubsystems:  []string{},
+	}
+
+	subsystemsMtx.Lock()
+	for name := range runningSubsystems {
+		active.RunningSubsystems = append(active.RunningSubsystems, name)
+	}
+	subsystemsMtx.Unlock()
+	sort.Strings(active.RunningSubsystems)
+	return active
+}
+
+// resolvePath returns the absolute form of a path, or the path when it can't
+// be resolved
+func resolvePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
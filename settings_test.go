@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/communications"
+)
+
+// resetSubsystems clears the running subsystems and returns a function
+// restoring them
+func resetSubsystems() func() {
+	subsystemsMtx.Lock()
+	saved := runningSubsystems
+	runningSubsystems = make(map[string]bool)
+	subsystemsMtx.Unlock()
+	return func() {
+		subsystemsMtx.Lock()
+		runningSubsystems = saved
+		subsystemsMtx.Unlock()
+	}
+}
+
+func TestRegisterSettingsFlags(t *testing.T) {
+	var s Settings
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerSettingsFlags(fs, &s, "config.json")
+	err := fs.Parse([]string{"-datadir", "data", "-webserver=false", "-events=false", "-verbose"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ConfigFile != "config.json" || s.DataDir != "data" || !s.Verbose || s.DryRun ||
+		s.EnableWebserver || s.EnableEvents {
+		t.Errorf("Test failed. Unexpected settings %+v", s)
+	}
+	if !s.EnableWebsocketRPC || !s.EnablePortfolioManager || !s.EnableUpdaterRoutines ||
+		!s.EnableExchangeWebsockets || !s.EnableCommunications {
+		t.Errorf("Test failed. Expected subsystems enabled by default, got %+v", s)
+	}
+}
+
+func TestStartSubsystemsDisabled(t *testing.T) {
+	SetupTestHelpers(t)
+	defer resetSubsystems()()
+	defer func(s Settings, c *communications.Communications, server *http.Server, enabled, telegram bool) {
+		bot.settings, bot.comms, bot.webserver = s, c, server
+		bot.config.Webserver.Enabled = enabled
+		bot.config.Communications.TelegramConfig.Enabled = telegram
+	}(bot.settings, bot.comms, bot.webserver, bot.config.Webserver.Enabled,
+		bot.config.Communications.TelegramConfig.Enabled)
+
+	// The config enables the webserver and a communication medium but every
+	// subsystem flag is disabled
+	bot.settings = Settings{ConfigFile: "config.json", DataDir: "data"}
+	bot.config.Webserver.Enabled = true
+	bot.config.Communications.TelegramConfig.Enabled = true
+	bot.webserver = nil
+
+	setupCommunications()
+	setupEvents()
+	startSubsystems()
+
+	if bot.webserver != nil {
+		t.Error("Test failed. Webserver started while disabled")
+	}
+	if bot.comms == nil || len(bot.comms.IComm) != 0 {
+		t.Error("Test failed. Communication mediums started while disabled")
+	}
+	active := GetActiveSettings()
+	if len(active.RunningSubsystems) != 0 {
+		t.Errorf("Test failed. Expected no running subsystems, got %v",
+			active.RunningSubsystems)
+	}
+	if GetDaemonInfo().PortfolioManagerRunning {
+		t.Error("Test failed. Portfolio manager reported running while disabled")
+	}
+	if active.ConfigFile != "config.json" || !filepath.IsAbs(active.ResolvedConfigFile) ||
+		filepath.Base(active.ResolvedDataDir) != "data" ||
+		!filepath.IsAbs(active.ResolvedDataDir) {
+		t.Errorf("Test failed. Unexpected active settings %+v", active)
+	}
+}
+
+func TestWebsocketRPCDisabled(t *testing.T) {
+	defer func(disabled bool) { wsRPCDisabled = disabled }(wsRPCDisabled)
+	wsRPCDisabled = true
+
+	w := httptest.NewRecorder()
+	WebsocketClientHandler(w, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusServiceUnavailable,
+			w.Code)
+	}
+}
+
+func TestRESTGetSettings(t *testing.T) {
+	SetupTestHelpers(t)
+	defer resetSubsystems()()
+	defer func(s Settings) { bot.settings = s }(bot.settings)
+	bot.settings = Settings{ConfigFile: "config.json", DataDir: "data",
+		EnableEvents: true, EnableCommunications: true}
+	setSubsystemRunning(SubsystemEvents, true)
+	setSubsystemRunning(SubsystemCommunications, true)
+
+	w := httptest.NewRecorder()
+	NewRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/settings", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var active ActiveSettings
+	err := common.JSONDecode(w.Body.Bytes(), &active)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active.EnableEvents || active.EnableWebserver || len(active.RunningSubsystems) != 2 ||
+		active.RunningSubsystems[0] != SubsystemCommunications ||
+		active.RunningSubsystems[1] != SubsystemEvents {
+		t.Errorf("Test failed. Unexpected settings %+v", active)
+	}
+}
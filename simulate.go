@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// OrderSimulation holds the expected execution of an order walked against an
+// exchange orderbook. For buys TotalCost is the cost plus the fee, for sells
+// it is the proceeds less the fee
+type OrderSimulation struct {
+	Exchange        string             `json:"exchange"`
+	Pair            string             `json:"pair"`
+	AssetType       string             `json:"assetType"`
+	Side            exchange.OrderSide `json:"side"`
+	Amount          float64            `json:"amount"`
+	FilledAmount    float64            `json:"filledAmount"`
+	AveragePrice    float64            `json:"averagePrice"`
+	MidPrice        float64            `json:"midPrice"`
+	SlippagePercent float64            `json:"slippagePercent"`
+	Cost            float64            `json:"cost"`
+	Fee             float64            `json:"fee"`
+	TotalCost       float64            `json:"totalCost"`
+	SufficientDepth bool               `json:"sufficientDepth"`
+	Error           string             `json:"error,omitempty"`
+}
+
+// SimulateOrder walks the orderbook of an exchange for a market order of the
+// supplied side and amount and returns its volume weighted fill price,
+// slippage versus the mid price and taker fee. The cached orderbook is used
+// unless refresh is set. An orderbook too shallow to fill the amount is
+// reported through SufficientDepth with the partial fill, it isn't
+// extrapolated. A refresh is aborted when ctx is cancelled if the exchange
+// supports it
+func SimulateOrder(ctx context.Context, exchName string, p pair.CurrencyPair, assetType string, side exchange.OrderSide, amount float64, refresh bool) (OrderSimulation, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return OrderSimulation{}, ErrExchangeNotFound
+	}
+	return simulateExchangeOrder(ctx, exch, p, assetType, side, amount, refresh)
+}
+
+// SimulateOrderAllExchanges simulates an order on every enabled exchange with
+// the currency pair enabled and ranks the results by total cost, cheapest
+// first for buys and highest proceeds first for sells. Exchanges without
+// enough depth or which failed are ranked last
+func SimulateOrderAllExchanges(ctx context.Context, p pair.CurrencyPair, assetType string, side exchange.OrderSide, amount float64, refresh bool) ([]OrderSimulation, error) {
+	err := validateSimulation("", side, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []OrderSimulation
+	for x := range bot.exchanges {
+		exch := bot.exchanges[x]
+		if exch == nil || !exch.IsEnabled() ||
+			!pair.Contains(exch.GetEnabledCurrencies(), p, true) {
+			continue
+		}
+
+		result, err := simulateExchangeOrder(ctx, exch, p, assetType, side, amount, refresh)
+		if err != nil {
+			result = OrderSimulation{
+				Exchange:  exch.GetName(),
+				Pair:      p.Pair().String(),
+				AssetType: assetType,
+				Side:      side,
+				Amount:    amount,
+				Error:     err.Error(),
+			}
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		iRanked := results[i].SufficientDepth && results[i].Error == ""
+		jRanked := results[j].SufficientDepth && results[j].Error == ""
+		if iRanked != jRanked {
+			return iRanked
+		}
+		if side == exchange.Sell {
+			return results[i].TotalCost > results[j].TotalCost
+		}
+		return results[i].TotalCost < results[j].TotalCost
+	})
+	return results, nil
+}
+
+// validateSimulation checks the side and amount of an order simulation
+func validateSimulation(exchName string, side exchange.OrderSide, amount float64) error {
+	verr := &OrderValidationError{Exchange: exchName}
+	if side != exchange.Buy && side != exchange.Sell {
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("order side %q must be %s or %s", side, exchange.Buy,
+				exchange.Sell))
+	}
+	if !(amount > 0) || math.IsInf(amount, 1) {
+		verr.Violations = append(verr.Violations,
+			fmt.Sprintf("amount %v must be greater than zero", amount))
+	}
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// simulateExchangeOrder walks the orderbook of an exchange for an order and
+// estimates its taker fee
+func simulateExchangeOrder(ctx context.Context, exch exchange.IBotExchange, p pair.CurrencyPair, assetType string, side exchange.OrderSide, amount float64, refresh bool) (OrderSimulation, error) {
+	err := validateSimulation(exch.GetName(), side, amount)
+	if err != nil {
+		return OrderSimulation{}, err
+	}
+
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+	if !exch.SupportsAsset(assetType) {
+		return OrderSimulation{}, &OrderValidationError{
+			Exchange:   exch.GetName(),
+			Violations: []string{fmt.Sprintf("asset type %s not supported", assetType)},
+		}
+	}
+
+	exchPair, enabled := enabledPair(exch, p)
+	if !enabled {
+		return OrderSimulation{}, &OrderValidationError{
+			Exchange:   exch.GetName(),
+			Violations: []string{fmt.Sprintf("currency pair %s not enabled", p.Pair())},
+		}
+	}
+
+	var ob orderbook.Base
+	if refresh {
+		ob, err = updateOrderbookWithContext(ctx, exch, exchPair, assetType)
+	} else {
+		ob, err = exch.GetOrderbookEx(exchPair, assetType)
+	}
+	if err != nil {
+		return OrderSimulation{}, err
+	}
+
+	result := OrderSimulation{
+		Exchange:        exch.GetName(),
+		Pair:            exchPair.Pair().String(),
+		AssetType:       assetType,
+		Side:            side,
+		Amount:          amount,
+		SufficientDepth: true,
+	}
+
+	result.MidPrice, err = ob.MidPrice()
+	if err != nil {
+		return OrderSimulation{}, err
+	}
+
+	result.AveragePrice, result.FilledAmount, err = ob.WeightedAveragePrice(amount,
+		side == exchange.Buy)
+	switch {
+	case err == orderbook.ErrInsufficientDepth:
+		result.SufficientDepth = false
+		result.Error = fmt.Sprintf("%s, only %v of %v can be filled", err,
+			result.FilledAmount, amount)
+	case err != nil:
+		return OrderSimulation{}, err
+	}
+	if result.FilledAmount == 0 {
+		return result, nil
+	}
+
+	result.SlippagePercent = (result.AveragePrice - result.MidPrice) /
+		result.MidPrice * 100
+	if side == exchange.Sell {
+		result.SlippagePercent = -result.SlippagePercent
+	}
+	result.Cost = result.AveragePrice * result.FilledAmount
+
+	result.Fee, err = exch.GetFeeByType(exchange.FeeBuilder{
+		FeeType:        exchange.CryptocurrencyTradeFee,
+		FirstCurrency:  exchPair.Base().String(),
+		SecondCurrency: exchPair.Quote().String(),
+		Delimiter:      exchPair.Delimiter,
+		PurchasePrice:  result.AveragePrice,
+		Amount:         result.FilledAmount,
+	})
+	if err != nil {
+		return OrderSimulation{}, err
+	}
+
+	result.TotalCost = result.Cost + result.Fee
+	if side == exchange.Sell {
+		result.TotalCost = result.Cost - result.Fee
+	}
+	return result, nil
+}
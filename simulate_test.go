@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// newSimulateExchange returns a test exchange serving a fixed orderbook for
+// BTC_USD and charging a percentage taker fee on the order value
+func newSimulateExchange(name string, book orderbook.Base, feeRate float64) *testExchange {
+	exch := newTestExchange(name)
+	exch.enabledPairs = []pair.CurrencyPair{pair.NewCurrencyPairDelimiter("BTC_USD", "_")}
+	exch.getOrderbookEx = func(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+		return book, nil
+	}
+	exch.updateOrderbook = exch.getOrderbookEx
+	exch.getFeeByType = func(feeBuilder exchange.FeeBuilder) (float64, error) {
+		return feeBuilder.PurchasePrice * feeBuilder.Amount * feeRate, nil
+	}
+	return exch
+}
+
+func TestSimulateOrder(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	book := orderbook.Base{
+		Bids: []orderbook.Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 5}},
+		Asks: []orderbook.Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 5}},
+	}
+	deep := newSimulateExchange("Deep", book, 0.002)
+	cheap := newSimulateExchange("Cheap", book, 0.001)
+	shallow := newSimulateExchange("Shallow", orderbook.Base{
+		Bids: []orderbook.Item{{Price: 99.5, Amount: 1}},
+		Asks: []orderbook.Item{{Price: 100.5, Amount: 1}},
+	}, 0)
+	bot.exchanges = []exchange.IBotExchange{shallow, deep, cheap}
+	p := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+
+	result, err := SimulateOrder(context.Background(), "deep", p, "", exchange.Buy, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AveragePrice != 101.5 || result.FilledAmount != 2 ||
+		result.MidPrice != 100 || !result.SufficientDepth ||
+		math.Abs(result.SlippagePercent-1.5) > 1e-9 || result.Cost != 203 ||
+		math.Abs(result.Fee-0.406) > 1e-9 ||
+		math.Abs(result.TotalCost-203.406) > 1e-9 || deep.callCount("UpdateOrderbook") != 1 {
+		t.Errorf("Test failed. Unexpected buy simulation %+v", result)
+	}
+
+	result, err = SimulateOrder(context.Background(), "shallow", p, ticker.Spot, exchange.Sell, 3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SufficientDepth || result.FilledAmount != 1 ||
+		result.AveragePrice != 99.5 || result.Error == "" {
+		t.Errorf("Test failed. Insufficient depth not reported %+v", result)
+	}
+
+	for _, test := range []struct {
+		exch   string
+		asset  string
+		side   exchange.OrderSide
+		amount float64
+		err    error
+	}{
+		{"404", "", exchange.Buy, 1, ErrExchangeNotFound},
+		{"deep", "", "Hold", 1, ErrInvalidOrder},
+		{"deep", "", exchange.Buy, 0, ErrInvalidOrder},
+		{"deep", "quarter", exchange.Buy, 1, ErrInvalidOrder},
+	} {
+		_, err = SimulateOrder(context.Background(), test.exch, p, test.asset, test.side, test.amount, false)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Test failed. Expected %v, got %v", test.err, err)
+		}
+	}
+	_, err = SimulateOrder(context.Background(), "deep", pair.NewCurrencyPair("ETH", "USD"), "",
+		exchange.Buy, 1, false)
+	if !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("Test failed. Expected %v for a disabled pair, got %v",
+			ErrInvalidOrder, err)
+	}
+
+	results, err := SimulateOrderAllExchanges(context.Background(), p, "", exchange.Buy, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Exchange != "Cheap" ||
+		results[1].Exchange != "Deep" || results[2].Exchange != "Shallow" {
+		t.Errorf("Test failed. Unexpected buy ranking %+v", results)
+	}
+
+	results, err = SimulateOrderAllExchanges(context.Background(), p, "", exchange.Sell, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Exchange != "Shallow" ||
+		results[1].Exchange != "Cheap" || results[2].Exchange != "Deep" {
+		t.Errorf("Test failed. Unexpected sell ranking %+v", results)
+	}
+
+	_, err = SimulateOrderAllExchanges(context.Background(), p, "", exchange.Buy, -1, false)
+	if !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrInvalidOrder, err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"sync"
+)
+
+// errRefreshPanicked is returned to callers waiting on a refreshGroup call
+// which panicked
+var errRefreshPanicked = errors.New("refresh call panicked")
+
+// refreshCall is an in-flight or completed refreshGroup call
+type refreshCall struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// refreshGroup deduplicates concurrent calls sharing the same key so that
+// simultaneous refresh requests trigger a single exchange request
+type refreshGroup struct {
+	mtx   sync.Mutex
+	calls map[string]*refreshCall
+}
+
+// Do executes fn for the key, unless a call for the key is already in flight
+// in which case it waits for and returns that call's result. If fn panics the
+// panic is propagated to the caller and waiting callers are released with
+// errRefreshPanicked
+func (g *refreshGroup) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mtx.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*refreshCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mtx.Unlock()
+		c.wg.Wait()
+		return c.val, c.err
+	}
+	c := &refreshCall{err: errRefreshPanicked}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mtx.Unlock()
+
+	defer func() {
+		g.mtx.Lock()
+		delete(g.calls, key)
+		g.mtx.Unlock()
+		c.wg.Done()
+	}()
+
+	c.val, c.err = fn()
+	return c.val, c.err
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForCall waits until a call for the key is in flight
+func waitForCall(t *testing.T, g *refreshGroup, key string) {
+	deadline := time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		g.mtx.Lock()
+		_, ok := g.calls[key]
+		g.mtx.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("Test failed. Timed out waiting for the call")
+}
+
+func TestRefreshGroupDo(t *testing.T) {
+	const key = "Bitfinex-BTCUSD-SPOT"
+	var g refreshGroup
+	var calls int32
+	release := make(chan struct{})
+
+	var wg, started sync.WaitGroup
+	results := make(chan interface{}, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			v, err := g.Do(key, func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return 1000.0, nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			results <- v
+		}()
+	}
+
+	waitForCall(t, &g, key)
+	started.Wait()
+	// Give the duplicate callers time to join the in-flight call
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for v := range results {
+		if v.(float64) != 1000 {
+			t.Fatalf("Test failed. Unexpected result %v", v)
+		}
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Fatalf("Test failed. Expected 1 call, got %d", c)
+	}
+
+	errTest := errors.New("test error")
+	_, err := g.Do(key, func() (interface{}, error) {
+		return nil, errTest
+	})
+	if err != errTest {
+		t.Fatalf("Test failed. Expected %v, got %v", errTest, err)
+	}
+	if len(g.calls) != 0 {
+		t.Fatal("Test failed. Completed call was not removed")
+	}
+}
+
+func TestRefreshGroupDoPanic(t *testing.T) {
+	const key = "Bitfinex-BTCUSD-SPOT"
+	var g refreshGroup
+	release := make(chan struct{})
+
+	panicked := make(chan interface{})
+	go func() {
+		defer func() {
+			panicked <- recover()
+		}()
+		g.Do(key, func() (interface{}, error) {
+			<-release
+			panic("refresh failed")
+		})
+	}()
+
+	waitForCall(t, &g, key)
+	waiter := make(chan error)
+	go func() {
+		_, err := g.Do(key, func() (interface{}, error) {
+			return 1000.0, nil
+		})
+		waiter <- err
+	}()
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+
+	if r := <-panicked; r == nil {
+		t.Fatal("Test failed. Panic not propagated to the caller")
+	}
+
+	select {
+	case err := <-waiter:
+		if err != errRefreshPanicked && err != nil {
+			t.Errorf("Test failed. Unexpected waiter error %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. Waiting caller not released after a panic")
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if len(g.calls) != 0 {
+		t.Fatal("Test failed. Panicked call was not removed")
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/request"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// testExchange is the exchange the main package tests load in place of a real
+// one. Its name, state, asset types, endpoints, websocket and pair statuses
+// are held by an exchange base. Exchange API calls are counted and answered by
+// the matching hook, returning common.ErrFunctionNotSupported if it isn't set.
+// Calls to methods it doesn't implement panic through the nil embedded
+// interface
+type testExchange struct {
+	exchange.IBotExchange
+	base           exchange.Base
+	enabledPairs   []pair.CurrencyPair
+	availablePairs []pair.CurrencyPair
+
+	start                       func(wg *sync.WaitGroup)
+	getLatencyStats             func() request.LatencyStats
+	updateTradablePairs         func(forceUpdate bool) error
+	updateTicker                func(p pair.CurrencyPair, assetType string) (ticker.Price, error)
+	updateTickers               func(assetType string) error
+	getTickerPrice              func(p pair.CurrencyPair, assetType string) (ticker.Price, error)
+	updateOrderbook             func(p pair.CurrencyPair, assetType string) (orderbook.Base, error)
+	getOrderbookEx              func(p pair.CurrencyPair, assetType string) (orderbook.Base, error)
+	getExchangeHistory          func(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error)
+	getHistoricCandles          func(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]exchange.Candle, error)
+	getServerTime               func() (time.Time, error)
+	getAccountInfo              func() (exchange.AccountInfo, error)
+	getOpenPositions            func() ([]exchange.Position, error)
+	getCurrencyDetails          func() ([]exchange.CurrencyDetails, error)
+	getFeeByType                func(feeBuilder exchange.FeeBuilder) (float64, error)
+	getOrderExecutionLimits     func(p pair.CurrencyPair) (exchange.Limits, error)
+	submitOrder                 func(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error)
+	getOrderInfo                func(orderID string) (exchange.OrderDetail, error)
+	cancelAllOrders             func(orders exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error)
+	getDepositAddress           func(c pair.CurrencyItem, accountID string, forceRefresh bool) (string, error)
+	getDepositHistory           func(c pair.CurrencyItem, start, end time.Time) ([]exchange.FundHistory, error)
+	withdrawCryptocurrencyFunds func(req exchange.WithdrawRequest) (string, error)
+
+	callsMtx sync.Mutex
+	calls    map[string]int
+}
+
+// newTestExchange returns an enabled test exchange supporting authenticated
+// requests and the spot asset type, with BTCUSD enabled and available
+func newTestExchange(name string) *testExchange {
+	pairs := []pair.CurrencyPair{pair.NewCurrencyPair("BTC", "USD")}
+	return &testExchange{
+		base: exchange.Base{
+			Name:                    name,
+			Enabled:                 true,
+			AuthenticatedAPISupport: true,
+			AssetTypes:              []string{ticker.Spot},
+		},
+		enabledPairs:   pairs,
+		availablePairs: pairs,
+	}
+}
+
+// called counts a call to an exchange API method
+func (e *testExchange) called(method string) {
+	e.callsMtx.Lock()
+	defer e.callsMtx.Unlock()
+	if e.calls == nil {
+		e.calls = make(map[string]int)
+	}
+	e.calls[method]++
+}
+
+// callCount returns the number of calls made to an exchange API method
+func (e *testExchange) callCount(method string) int {
+	e.callsMtx.Lock()
+	defer e.callsMtx.Unlock()
+	return e.calls[method]
+}
+
+// resetCalls clears the exchange API call counts
+func (e *testExchange) resetCalls() {
+	e.callsMtx.Lock()
+	defer e.callsMtx.Unlock()
+	e.calls = nil
+}
+
+func (e *testExchange) GetName() string {
+	return e.base.GetName()
+}
+
+func (e *testExchange) IsEnabled() bool {
+	return e.base.IsEnabled()
+}
+
+func (e *testExchange) GetAuthenticatedAPISupport() bool {
+	return e.base.GetAuthenticatedAPISupport()
+}
+
+func (e *testExchange) GetWithdrawPermissions() uint32 {
+	return e.base.GetWithdrawPermissions()
+}
+
+func (e *testExchange) GetAccountLabels() []string {
+	return e.base.GetAccountLabels()
+}
+
+func (e *testExchange) GetEnabledCurrencies() []pair.CurrencyPair {
+	return e.enabledPairs
+}
+
+func (e *testExchange) GetAvailableCurrencies() []pair.CurrencyPair {
+	return e.availablePairs
+}
+
+func (e *testExchange) SetCurrencies(pairs []pair.CurrencyPair, enabledPairs bool) error {
+	if enabledPairs {
+		e.enabledPairs = pairs
+	} else {
+		e.availablePairs = pairs
+	}
+	return nil
+}
+
+func (e *testExchange) GetAssetTypes() []string {
+	return e.base.GetAssetTypes()
+}
+
+func (e *testExchange) GetSupportedAssetTypes() []string {
+	return e.base.GetSupportedAssetTypes()
+}
+
+func (e *testExchange) SupportsAsset(assetType string) bool {
+	return e.base.SupportsAsset(assetType)
+}
+
+func (e *testExchange) SetAssetTypeEnabled(assetType string, enabled bool) error {
+	return e.base.SetAssetTypeEnabled(assetType, enabled)
+}
+
+func (e *testExchange) GetPairStatuses() []exchange.PairStatus {
+	return e.base.GetPairStatuses()
+}
+
+func (e *testExchange) IsPairTradable(p pair.CurrencyPair) bool {
+	return e.base.IsPairTradable(p)
+}
+
+func (e *testExchange) CheckPairTradable(p pair.CurrencyPair) error {
+	return e.base.CheckPairTradable(p)
+}
+
+func (e *testExchange) SupportsRESTTickerBatchUpdates() bool {
+	return e.base.SupportsRESTTickerBatchUpdates()
+}
+
+func (e *testExchange) GetAPIURL() string {
+	return e.base.GetAPIURL()
+}
+
+func (e *testExchange) GetSecondaryAPIURL() string {
+	return e.base.GetSecondaryAPIURL()
+}
+
+func (e *testExchange) GetEndpoints() exchange.Endpoints {
+	return e.base.GetEndpoints()
+}
+
+func (e *testExchange) SetEndpoints(apiURL, apiURLSecondary, websocketURL string) error {
+	return e.base.SetEndpoints(apiURL, apiURLSecondary, websocketURL)
+}
+
+func (e *testExchange) GetWebsocket() (*exchange.Websocket, error) {
+	if e.base.Websocket == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return e.base.Websocket, nil
+}
+
+func (e *testExchange) GetRateLimitStatus() request.RateLimitStatus {
+	return e.base.GetRateLimitStatus()
+}
+
+func (e *testExchange) GetTimeouts() exchange.Timeouts {
+	return e.base.GetTimeouts()
+}
+
+func (e *testExchange) SetServerTimeOffset(offset time.Duration) {
+	e.base.SetServerTimeOffset(offset)
+}
+
+func (e *testExchange) Start(wg *sync.WaitGroup) {
+	e.called("Start")
+	if e.start != nil {
+		e.start(wg)
+	}
+}
+
+func (e *testExchange) GetLatencyStats() request.LatencyStats {
+	if e.getLatencyStats == nil {
+		return e.base.GetLatencyStats()
+	}
+	return e.getLatencyStats()
+}
+
+func (e *testExchange) UpdateTradablePairs(forceUpdate bool) error {
+	e.called("UpdateTradablePairs")
+	if e.updateTradablePairs == nil {
+		return common.ErrFunctionNotSupported
+	}
+	return e.updateTradablePairs(forceUpdate)
+}
+
+func (e *testExchange) UpdateTicker(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	e.called("UpdateTicker")
+	if e.updateTicker == nil {
+		return ticker.Price{}, common.ErrFunctionNotSupported
+	}
+	return e.updateTicker(p, assetType)
+}
+
+func (e *testExchange) UpdateTickers(assetType string) error {
+	e.called("UpdateTickers")
+	if e.updateTickers == nil {
+		return common.ErrFunctionNotSupported
+	}
+	return e.updateTickers(assetType)
+}
+
+func (e *testExchange) GetTickerPrice(p pair.CurrencyPair, assetType string) (ticker.Price, error) {
+	e.called("GetTickerPrice")
+	if e.getTickerPrice == nil {
+		return ticker.Price{}, common.ErrFunctionNotSupported
+	}
+	return e.getTickerPrice(p, assetType)
+}
+
+func (e *testExchange) UpdateOrderbook(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	e.called("UpdateOrderbook")
+	if e.updateOrderbook == nil {
+		return orderbook.Base{}, common.ErrFunctionNotSupported
+	}
+	return e.updateOrderbook(p, assetType)
+}
+
+func (e *testExchange) GetOrderbookEx(p pair.CurrencyPair, assetType string) (orderbook.Base, error) {
+	e.called("GetOrderbookEx")
+	if e.getOrderbookEx == nil {
+		return orderbook.Base{}, common.ErrFunctionNotSupported
+	}
+	return e.getOrderbookEx(p, assetType)
+}
+
+func (e *testExchange) GetExchangeHistory(p pair.CurrencyPair, assetType string, timestampStart time.Time) ([]exchange.TradeHistory, error) {
+	e.called("GetExchangeHistory")
+	if e.getExchangeHistory == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return e.getExchangeHistory(p, assetType, timestampStart)
+}
+
+func (e *testExchange) GetHistoricCandles(p pair.CurrencyPair, assetType string, timestampStart time.Time, interval time.Duration) ([]exchange.Candle, error) {
+	e.called("GetHistoricCandles")
+	if e.getHistoricCandles == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return e.getHistoricCandles(p, assetType, timestampStart, interval)
+}
+
+func (e *testExchange) GetServerTime() (time.Time, error) {
+	e.called("GetServerTime")
+	if e.getServerTime == nil {
+		return time.Time{}, common.ErrFunctionNotSupported
+	}
+	return e.getServerTime()
+}
+
+func (e *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	e.called("GetAccountInfo")
+	if e.getAccountInfo == nil {
+		return exchange.AccountInfo{}, common.ErrFunctionNotSupported
+	}
+	return e.getAccountInfo()
+}
+
+func (e *testExchange) GetOpenPositions() ([]exchange.Position, error) {
+	e.called("GetOpenPositions")
+	if e.getOpenPositions == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return e.getOpenPositions()
+}
+
+func (e *testExchange) GetCurrencyDetails() ([]exchange.CurrencyDetails, error) {
+	e.called("GetCurrencyDetails")
+	if e.getCurrencyDetails == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return e.getCurrencyDetails()
+}
+
+func (e *testExchange) GetFeeByType(feeBuilder exchange.FeeBuilder) (float64, error) {
+	e.called("GetFeeByType")
+	if e.getFeeByType == nil {
+		return 0, common.ErrFunctionNotSupported
+	}
+	return e.getFeeByType(feeBuilder)
+}
+
+func (e *testExchange) GetOrderExecutionLimits(p pair.CurrencyPair) (exchange.Limits, error) {
+	e.called("GetOrderExecutionLimits")
+	if e.getOrderExecutionLimits == nil {
+		return exchange.Limits{}, common.ErrFunctionNotSupported
+	}
+	return e.getOrderExecutionLimits(p)
+}
+
+func (e *testExchange) SubmitOrder(order *exchange.OrderSubmission) (exchange.SubmitOrderResponse, error) {
+	e.called("SubmitOrder")
+	if e.submitOrder == nil {
+		return exchange.SubmitOrderResponse{}, common.ErrFunctionNotSupported
+	}
+	return e.submitOrder(order)
+}
+
+func (e *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	e.called("GetOrderInfo")
+	if e.getOrderInfo == nil {
+		return exchange.OrderDetail{}, common.ErrFunctionNotSupported
+	}
+	return e.getOrderInfo(orderID)
+}
+
+func (e *testExchange) CancelAllOrders(orders exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	e.called("CancelAllOrders")
+	if e.cancelAllOrders == nil {
+		return exchange.CancelAllOrdersResponse{}, common.ErrFunctionNotSupported
+	}
+	return e.cancelAllOrders(orders)
+}
+
+func (e *testExchange) GetDepositAddress(c pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+	e.called("GetDepositAddress")
+	if e.getDepositAddress == nil {
+		return "", common.ErrFunctionNotSupported
+	}
+	return e.getDepositAddress(c, accountID, forceRefresh)
+}
+
+func (e *testExchange) GetDepositHistory(c pair.CurrencyItem, start, end time.Time) ([]exchange.FundHistory, error) {
+	e.called("GetDepositHistory")
+	if e.getDepositHistory == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return e.getDepositHistory(c, start, end)
+}
+
+func (e *testExchange) WithdrawCryptocurrencyFunds(req exchange.WithdrawRequest) (string, error) {
+	e.called("WithdrawCryptocurrencyFunds")
+	if e.withdrawCryptocurrencyFunds == nil {
+		return "", common.ErrFunctionNotSupported
+	}
+	return e.withdrawCryptocurrencyFunds(req)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+const (
+	tlsDir              = "tls"
+	tlsCertFile         = "cert.pem"
+	tlsKeyFile          = "key.pem"
+	tlsCertOrganisation = "gocryptotrader"
+	tlsCertValidity     = time.Hour * 24 * 365
+)
+
+// tlsHostnames returns the hostnames and IP addresses the webserver
+// certificate must be valid for
+func tlsHostnames(cfg *config.WebserverConfig) []string {
+	names := []string{"localhost", "127.0.0.1", "::1"}
+	host, _, err := net.SplitHostPort(cfg.ListenAddress)
+	if err == nil && host != "" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsUnspecified() {
+			names = append(names, host)
+		}
+	}
+	names = append(names, cfg.TLSHostnames...)
+
+	var result []string
+	for x := range names {
+		if names[x] != "" && !common.StringDataCompare(result, names[x]) {
+			result = append(result, names[x])
+		}
+	}
+	return result
+}
+
+// checkCerts ensures a valid webserver certificate exists in the supplied
+// directory. A certificate is generated if there isn't one, and generated
+// certificates are regenerated once they expire or are missing any of the
+// hostnames. User supplied certificates are never replaced
+func checkCerts(dir string, hostnames []string) error {
+	err := common.CheckDir(dir, true)
+	if err != nil {
+		return err
+	}
+
+	certPath := filepath.Join(dir, tlsCertFile)
+	keyPath := filepath.Join(dir, tlsKeyFile)
+	keyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		log.Warnf("Unable to load TLS certificate, generating a new one. Error: %s", err)
+		return genCert(dir, hostnames)
+	}
+
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	generated := common.StringDataCompare(cert.Subject.Organization,
+		tlsCertOrganisation)
+	if time.Now().After(cert.NotAfter) {
+		if !generated {
+			return fmt.Errorf("TLS certificate %s expired at %v", certPath,
+				cert.NotAfter)
+		}
+		log.Warnf("TLS certificate expired at %v, generating a new one.",
+			cert.NotAfter)
+		return genCert(dir, hostnames)
+	}
+
+	var missing []string
+	for x := range hostnames {
+		if cert.VerifyHostname(hostnames[x]) != nil {
+			missing = append(missing, hostnames[x])
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if !generated {
+		log.Warnf("TLS certificate %s isn't valid for %s.", certPath,
+			common.JoinStrings(missing, ", "))
+		return nil
+	}
+	log.Warnf("TLS certificate isn't valid for %s, generating a new one.",
+		common.JoinStrings(missing, ", "))
+	return genCert(dir, hostnames)
+}
+
+// genCert generates a self signed certificate and key valid for the supplied
+// hostnames and IP addresses. The certificate can be used as the root
+// certificate by clients
+func genCert(dir string, hostnames []string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{tlsCertOrganisation},
+			CommonName:   "localhost",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(tlsCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for x := range hostnames {
+		if ip := net.ParseIP(hostnames[x]); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, hostnames[x])
+		}
+	}
+
+	certData, err := x509.CreateCertificate(rand.Reader, &template, &template,
+		&key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	keyData, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	// Write the key first so a certificate reload never pairs the new
+	// certificate with the old key
+	err = ioutil.WriteFile(filepath.Join(dir, tlsKeyFile),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyData}),
+		0600)
+	if err != nil {
+		return err
+	}
+
+	err = common.WriteFile(filepath.Join(dir, tlsCertFile),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certData}))
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Generated TLS certificate valid for %s.",
+		common.JoinStrings(hostnames, ", "))
+	return nil
+}
+
+// certReloader serves the webserver certificate, reloading it when the
+// certificate or key file changes so certificates can be replaced without a
+// restart
+type certReloader struct {
+	certPath    string
+	keyPath     string
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	m           sync.RWMutex
+}
+
+// newCertReloader loads the certificate and key at the supplied paths
+func newCertReloader(certPath, keyPath string) (*certReloader, error) {
+	c := &certReloader{certPath: certPath, keyPath: keyPath}
+	err := c.reload()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCertificate returns the current certificate, it is used as the webserver
+// TLS config GetCertificate callback
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if c.modified() {
+		err := c.reload()
+		if err != nil {
+			log.Errorf("Failed to reload TLS certificate, using the previous certificate. Error: %s", err)
+		}
+	}
+
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.cert, nil
+}
+
+// modified returns whether the certificate or key file has changed since it
+// was last loaded
+func (c *certReloader) modified() bool {
+	certInfo, err := os.Stat(c.certPath)
+	if err != nil {
+		return false
+	}
+	keyInfo, err := os.Stat(c.keyPath)
+	if err != nil {
+		return false
+	}
+
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return !certInfo.ModTime().Equal(c.certModTime) ||
+		!keyInfo.ModTime().Equal(c.keyModTime)
+}
+
+// reload loads the certificate and key files. The file modification times are
+// recorded even if loading fails so a bad pair is only reported once per
+// change
+func (c *certReloader) reload() error {
+	certInfo, err := os.Stat(c.certPath)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(c.keyPath)
+	if err != nil {
+		return err
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.certModTime = certInfo.ModTime()
+	c.keyModTime = keyInfo.ModTime()
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return err
+	}
+	if c.cert != nil {
+		log.Debugln("Reloaded TLS certificate.")
+	}
+	c.cert = &cert
+	return nil
+}
+
+// newWebserverTLSConfig checks the webserver certificate and returns a TLS
+// config which reloads it when changed
+func newWebserverTLSConfig(dir string, cfg *config.WebserverConfig) (*tls.Config, error) {
+	if dir == "" {
+		return nil, errors.New("TLS directory not set")
+	}
+
+	err := checkCerts(dir, tlsHostnames(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	reloader, err := newCertReloader(filepath.Join(dir, tlsCertFile),
+		filepath.Join(dir, tlsKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}, nil
+}
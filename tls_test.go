@@ -0,0 +1,177 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+)
+
+func loadTestCert(t *testing.T, dir string) *x509.Certificate {
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(dir, tlsCertFile),
+		filepath.Join(dir, tlsKeyFile))
+	if err != nil {
+		t.Fatalf("Test failed. Unable to load certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// testHandshake dials the listener using the server name and trusting only
+// the certificate
+func testHandshake(addr, serverName string, cert *x509.Certificate) error {
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		ServerName: serverName,
+		RootCAs:    pool,
+	})
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func TestTLSHostnames(t *testing.T) {
+	cfg := config.WebserverConfig{
+		ListenAddress: "gct.local:9050",
+		TLSHostnames:  []string{"10.0.0.2", "localhost", "gct.example.com"},
+	}
+	expected := []string{"localhost", "127.0.0.1", "::1", "gct.local", "10.0.0.2",
+		"gct.example.com"}
+	result := tlsHostnames(&cfg)
+	if common.JoinStrings(result, ",") != common.JoinStrings(expected, ",") {
+		t.Errorf("Test failed. Unexpected hostnames %v", result)
+	}
+
+	cfg = config.WebserverConfig{ListenAddress: "0.0.0.0:9050"}
+	result = tlsHostnames(&cfg)
+	if len(result) != 3 {
+		t.Errorf("Test failed. Unspecified listen address included %v", result)
+	}
+}
+
+func TestCheckCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gcttls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hostnames := tlsHostnames(&config.WebserverConfig{
+		ListenAddress: "10.0.0.2:9050",
+		TLSHostnames:  []string{"gct.example.com"},
+	})
+	err = checkCerts(dir, hostnames)
+	if err != nil {
+		t.Fatalf("Test failed. checkCerts error: %s", err)
+	}
+
+	cert := loadTestCert(t, dir)
+	if common.JoinStrings(cert.DNSNames, ",") != "localhost,gct.example.com" {
+		t.Errorf("Test failed. Unexpected DNS SANs %v", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) != 3 ||
+		!cert.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")) ||
+		!cert.IPAddresses[1].Equal(net.ParseIP("::1")) ||
+		!cert.IPAddresses[2].Equal(net.ParseIP("10.0.0.2")) {
+		t.Errorf("Test failed. Unexpected IP SANs %v", cert.IPAddresses)
+	}
+
+	// A valid certificate must not be regenerated
+	err = checkCerts(dir, hostnames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadTestCert(t, dir).SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		t.Error("Test failed. Valid certificate regenerated")
+	}
+
+	err = checkCerts(dir, append(hostnames, "gct.example.org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadTestCert(t, dir).VerifyHostname("gct.example.org") != nil {
+		t.Error("Test failed. Certificate not regenerated for a new hostname")
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gcttls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hostnames := []string{"localhost", "127.0.0.1", "::1", "gct.example.com"}
+	err = genCert(dir, hostnames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig, err := newWebserverTLSConfig(dir, &config.WebserverConfig{
+		ListenAddress: "localhost:9050",
+		TLSHostnames:  []string{"gct.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Test failed. newWebserverTLSConfig error: %s", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}(conn)
+		}
+	}()
+
+	addr := listener.Addr().String()
+	cert := loadTestCert(t, dir)
+	for x := range hostnames {
+		err = testHandshake(addr, hostnames[x], cert)
+		if err != nil {
+			t.Errorf("Test failed. Handshake for %s failed: %s", hostnames[x], err)
+		}
+	}
+
+	err = testHandshake(addr, "gct.example.org", cert)
+	if err == nil {
+		t.Error("Test failed. Handshake succeeded for an unknown hostname")
+	}
+
+	err = genCert(dir, []string{"gct.example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ensure the reload is detected on filesystems with coarse timestamps
+	future := time.Now().Add(time.Minute)
+	err = os.Chtimes(filepath.Join(dir, tlsCertFile), future, future)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = testHandshake(addr, "gct.example.org", loadTestCert(t, dir))
+	if err != nil {
+		t.Errorf("Test failed. Certificate not reloaded: %s", err)
+	}
+}
@@ -33,10 +33,12 @@ const (
 	currencyTranslationPath         = "..%s..%scurrency%stranslation%s"
 	eventsPath                      = "..%s..%sevents%s"
 	exchangesPath                   = "..%s..%sexchanges%s"
+	exchangesAssetsPath             = "..%s..%sexchanges%sassets%s"
 	exchangesNoncePath              = "..%s..%sexchanges%snonce%s"
 	exchangesOrderbookPath          = "..%s..%sexchanges%sorderbook%s"
 	exchangesStatsPath              = "..%s..%sexchanges%sstats%s"
 	exchangesTickerPath             = "..%s..%sexchanges%sticker%s"
+	exchangesTradesPath             = "..%s..%sexchanges%strades%s"
 	exchangesOrdersPath             = "..%s..%sexchanges%sorders%s"
 	exchangesRequestPath            = "..%s..%sexchanges%srequest%s"
 	portfolioPath                   = "..%s..%sportfolio%s"
@@ -214,10 +216,12 @@ func addPaths() {
 	codebasePaths["root"] = fmt.Sprintf(rootPath, path, path)
 
 	codebasePaths["exchanges"] = fmt.Sprintf(exchangesPath, path, path, path)
+	codebasePaths["exchanges assets"] = fmt.Sprintf(exchangesAssetsPath, path, path, path, path)
 	codebasePaths["exchanges nonce"] = fmt.Sprintf(exchangesNoncePath, path, path, path, path)
 	codebasePaths["exchanges orderbook"] = fmt.Sprintf(exchangesOrderbookPath, path, path, path, path)
 	codebasePaths["exchanges stats"] = fmt.Sprintf(exchangesStatsPath, path, path, path, path)
 	codebasePaths["exchanges ticker"] = fmt.Sprintf(exchangesTickerPath, path, path, path, path)
+	codebasePaths["exchanges trades"] = fmt.Sprintf(exchangesTradesPath, path, path, path, path)
 	codebasePaths["exchanges orders"] = fmt.Sprintf(exchangesOrdersPath, path, path, path, path)
 	codebasePaths["exchanges request"] = fmt.Sprintf(exchangesRequestPath, path, path, path, path)
 
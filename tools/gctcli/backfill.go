@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// backfillPollInterval is how often a waited on backfill job is polled
+const backfillPollInterval = time.Second * 2
+
+// backfillRequest is the backfill submitted to the GoCryptoTrader webserver
+type backfillRequest struct {
+	Exchange  string    `json:"exchange"`
+	Currency  string    `json:"currency"`
+	AssetType string    `json:"assetType,omitempty"`
+	DataType  string    `json:"dataType"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Interval  string    `json:"interval,omitempty"`
+}
+
+// backfillJob is the backfill job status returned by the webserver
+type backfillJob struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
+	Progress struct {
+		Batches int `json:"batches"`
+		Records int `json:"records"`
+		Last    struct {
+			Time time.Time `json:"time"`
+		} `json:"last"`
+	} `json:"progress"`
+}
+
+// parseBackfillTime parses an RFC3339 time or a date in UTC
+func parseBackfillTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+	t, err = time.Parse("2006-01-02", value)
+	if err != nil {
+		return t, fmt.Errorf("invalid time %q, expected RFC3339 or YYYY-MM-DD", value)
+	}
+	return t, nil
+}
+
+// parseBackfill parses the backfill positional arguments followed by its
+// optional flags and returns the request and whether to wait for the job
+func parseBackfill(args []string) (backfillRequest, bool, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	var request backfillRequest
+	if len(positional) != 5 {
+		return request, false, errors.New("expected <exchange> <currency> <trades|candles> <start> <end>")
+	}
+
+	var wait bool
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fs.StringVar(&request.AssetType, "asset", "", "asset type, defaults to SPOT")
+	fs.StringVar(&request.Interval, "interval", "", "candle interval, for example 1m or 1h")
+	fs.BoolVar(&wait, "wait", false, "waits for the backfill, printing its progress")
+	err := fs.Parse(flags)
+	if err != nil {
+		return request, false, err
+	}
+	if fs.NArg() > 0 {
+		return request, false, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	request.Exchange = positional[0]
+	request.Currency = positional[1]
+	request.DataType = positional[2]
+	request.Start, err = parseBackfillTime(positional[3])
+	if err != nil {
+		return request, false, err
+	}
+	request.End, err = parseBackfillTime(positional[4])
+	if err != nil {
+		return request, false, err
+	}
+	if request.DataType == "candles" && request.Interval == "" {
+		return request, false, errors.New("candles require an -interval")
+	}
+	return request, wait, nil
+}
+
+// waitBackfill polls a backfill job, printing its progress until it finishes.
+// An interrupt cancels the job
+func waitBackfill(host, id string) error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(backfillPollInterval)
+	defer ticker.Stop()
+	for {
+		body, err := sendRequest(host, "/backfill/"+id, requestTimeout)
+		if err != nil {
+			return err
+		}
+		var job backfillJob
+		err = json.Unmarshal(body, &job)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Backfill %s %s: %d batches, %d records", job.ID, job.Status,
+			job.Progress.Batches, job.Progress.Records)
+		if !job.Progress.Last.Time.IsZero() {
+			fmt.Printf(", last record %s", job.Progress.Last.Time.UTC().Format(time.RFC3339))
+		}
+		fmt.Println()
+
+		switch job.Status {
+		case "running":
+		case "failed":
+			return errors.New(job.Error)
+		default:
+			return nil
+		}
+
+		select {
+		case <-interrupt:
+			fmt.Println("Cancelling backfill, run it again to resume.")
+			_, err = sendAuthRequest(host, "/backfill/"+id+"/cancel", nil, requestTimeout)
+			if err != nil {
+				return err
+			}
+		case <-ticker.C:
+		}
+	}
+}
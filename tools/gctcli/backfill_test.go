@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBackfill(t *testing.T) {
+	request, wait, err := parseBackfill([]string{"Bitfinex", "BTCUSD", "candles",
+		"2018-01-01", "2018-02-01T12:00:00Z", "-interval", "1h", "-asset", "SPOT", "-wait"})
+	if err != nil {
+		t.Fatalf("Test failed - parseBackfill() error: %s", err)
+	}
+	if !wait || request.Interval != "1h" || request.AssetType != "SPOT" ||
+		!request.Start.Equal(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!request.End.Equal(time.Date(2018, 2, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Test failed - unexpected request %+v", request)
+	}
+
+	_, _, err = parseBackfill([]string{"Bitfinex", "BTCUSD", "candles",
+		"2018-01-01", "2018-02-01"})
+	if err == nil {
+		t.Error("Test failed - parseBackfill() accepted candles without an interval")
+	}
+
+	_, _, err = parseBackfill([]string{"Bitfinex", "BTCUSD", "trades",
+		"yesterday", "2018-02-01"})
+	if err == nil {
+		t.Error("Test failed - parseBackfill() accepted an invalid time")
+	}
+}
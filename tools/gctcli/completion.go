@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	// completeExchangesCommand is a hidden command used by the completion
+	// scripts to fetch the loaded exchange names from the daemon, it is passed
+	// the command line being completed
+	completeExchangesCommand = "__exchanges"
+	completionTimeout        = time.Second * 2
+)
+
+const bashCompletionScript = `_gctcli_complete() {
+	local cur cmd
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	COMPREPLY=()
+
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=($(compgen -W "%s" -- "$cur"))
+		return 0
+	fi
+
+	cmd="${COMP_WORDS[1]}"
+	case "$cmd" in
+	%s)
+		if [ "$COMP_CWORD" -eq 2 ]; then
+			COMPREPLY=($(compgen -W "$(gctcli %s "$COMP_LINE" 2>/dev/null)" -- "$cur"))
+		fi
+		;;
+	completion)
+		if [ "$COMP_CWORD" -eq 2 ]; then
+			COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
+		fi
+		;;
+	esac
+	return 0
+}
+complete -F _gctcli_complete gctcli
+`
+
+const zshCompletionHeader = `#compdef gctcli
+autoload -U +X bashcompinit && bashcompinit
+`
+
+// completionScript returns the completion script for a shell
+func completionScript(shell string) (string, error) {
+	var names, exchangeCommands []string
+	for x := range commands {
+		names = append(names, commands[x].Name)
+		names = append(names, commands[x].Aliases...)
+		if commands[x].ExchangeArg {
+			exchangeCommands = append(exchangeCommands, commands[x].Name)
+			exchangeCommands = append(exchangeCommands, commands[x].Aliases...)
+		}
+	}
+
+	script := fmt.Sprintf(bashCompletionScript,
+		common.JoinStrings(names, " "),
+		common.JoinStrings(exchangeCommands, "|"),
+		completeExchangesCommand)
+
+	switch shell {
+	case "bash":
+		return script, nil
+	case "zsh":
+		return zshCompletionHeader + script, nil
+	}
+	return "", fmt.Errorf("unsupported shell %q, supported shells: bash, zsh", shell)
+}
+
+// parseCompletionLine parses the global flags, such as -host and the TLS
+// settings, from the command line being completed so exchange names are
+// fetched from the same daemon the command will use. The webserver address is
+// returned
+func parseCompletionLine(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		fields = fields[1:]
+	}
+
+	fs := flag.NewFlagSet("gctcli", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	host := registerGlobalFlags(fs)
+	fs.Parse(fields)
+	return *host
+}
+
+// printExchangeNames prints the loaded exchange names for completion. Errors
+// are ignored so completion falls back silently when the daemon is down
+func printExchangeNames(host string) {
+	body, err := sendRequest(host, "/exchanges/health", completionTimeout)
+	if err != nil {
+		return
+	}
+
+	var exchanges []struct {
+		Exchange string `json:"exchange"`
+	}
+	err = json.Unmarshal(body, &exchanges)
+	if err != nil {
+		return
+	}
+
+	for x := range exchanges {
+		fmt.Println(exchanges[x].Exchange)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+func TestCompletionScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Fatalf("Test failed - completionScript(%s) error: %s", shell, err)
+		}
+
+		for x := range commands {
+			names := append([]string{commands[x].Name}, commands[x].Aliases...)
+			for y := range names {
+				if !common.StringContains(script, names[y]) {
+					t.Errorf("Test failed - %s completion missing command %s",
+						shell, names[y])
+				}
+			}
+		}
+
+		if !common.StringContains(script, "complete -F _gctcli_complete gctcli") {
+			t.Errorf("Test failed - %s completion not registered", shell)
+		}
+	}
+
+	script, err := completionScript("zsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !common.StringContains(script, "bashcompinit") {
+		t.Error("Test failed - zsh completion should load bashcompinit")
+	}
+
+	_, err = completionScript("fish")
+	if err == nil {
+		t.Error("Test failed - completionScript() accepted an unsupported shell")
+	}
+}
+
+func TestParseCompletionLine(t *testing.T) {
+	defer func() {
+		useTLS, tlsSkipVerify, tlsServerName, tlsCert = false, false, "", ""
+	}()
+
+	host := parseCompletionLine("gctcli -host 10.0.0.1:9051 -tls -tlsskipverify getticker Bit")
+	if host != "10.0.0.1:9051" {
+		t.Errorf("Test failed - parseCompletionLine() expected host 10.0.0.1:9051, got %s", host)
+	}
+	if !useTLS || !tlsSkipVerify {
+		t.Error("Test failed - parseCompletionLine() did not parse the TLS flags")
+	}
+
+	host = parseCompletionLine("gctcli --host=example.com:9050 t ")
+	if host != "example.com:9050" {
+		t.Errorf("Test failed - parseCompletionLine() expected host example.com:9050, got %s", host)
+	}
+
+	host = parseCompletionLine("gctcli getticker ")
+	if host != defaultHost || useTLS {
+		t.Errorf("Test failed - parseCompletionLine() expected default settings, got %s", host)
+	}
+
+	script, err := completionScript("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !common.StringContains(script, `"$COMP_LINE"`) {
+		t.Error("Test failed - completion script does not pass the command line")
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	aliases := map[string]string{
+		"t":            "getticker",
+		"ob":           "getorderbook",
+		"getticker":    "getticker",
+		"getorderbook": "getorderbook",
+	}
+	for alias, name := range aliases {
+		cmd, ok := findCommand(alias)
+		if !ok || cmd.Name != name {
+			t.Errorf("Test failed - findCommand(%s) expected %s", alias, name)
+		}
+	}
+
+	_, ok := findCommand("blah")
+	if ok {
+		t.Error("Test failed - findCommand() found a non-existent command")
+	}
+}
+
+func TestPrintExchangeNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"exchange":"Bitfinex","status":"running"}]`)
+	}))
+	defer server.Close()
+
+	body, err := sendRequest(server.Listener.Addr().String(), "/exchanges/health",
+		completionTimeout)
+	if err != nil {
+		t.Fatalf("Test failed - sendRequest() error: %s", err)
+	}
+	if !common.StringContains(string(body), "Bitfinex") {
+		t.Errorf("Test failed - unexpected response %s", body)
+	}
+
+	// The daemon being down must not panic or error
+	printExchangeNames("127.0.0.1:1")
+}
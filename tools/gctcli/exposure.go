@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// exposureSource holds the state of a data source of an exposure report
+type exposureSource struct {
+	Exchange string    `json:"exchange"`
+	Source   string    `json:"source"`
+	Status   string    `json:"status"`
+	Updated  time.Time `json:"updated"`
+	Error    string    `json:"error"`
+}
+
+// exchangeExposure holds an exchange account's exposure to a currency
+type exchangeExposure struct {
+	Exchange  string  `json:"exchange"`
+	Balance   float64 `json:"balance"`
+	Orders    float64 `json:"orders"`
+	Positions float64 `json:"positions"`
+	Total     float64 `json:"total"`
+}
+
+// currencyExposure holds the exposure to a currency across exchange accounts
+type currencyExposure struct {
+	exchangeExposure
+	Currency       string             `json:"currency"`
+	Value          *float64           `json:"value"`
+	ValuationError string             `json:"valuationError"`
+	Exchanges      []exchangeExposure `json:"exchanges"`
+}
+
+// exposureReport holds the exposure to each currency returned by the
+// webserver
+type exposureReport struct {
+	DisplayCurrency string             `json:"displayCurrency"`
+	TotalValue      float64            `json:"totalValue"`
+	Currencies      []currencyExposure `json:"currencies"`
+	Sources         []exposureSource   `json:"sources"`
+	Timestamp       time.Time          `json:"timestamp"`
+}
+
+// printExposure fetches the exposure report and prints it as a table
+func printExposure(host string) error {
+	body, err := sendAuthGetRequest(host, "/exposure", requestTimeout)
+	if err != nil {
+		return err
+	}
+	var report exposureReport
+	err = json.Unmarshal(body, &report)
+	if err != nil {
+		return err
+	}
+	for _, line := range formatExposureReport(report) {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// formatExposureReport formats the exposure to each currency followed by its
+// exchange breakdown, then the data sources which are stale or unavailable
+func formatExposureReport(report exposureReport) []string {
+	row := "%-10s %-20s %-16s %-16s %-16s %-16s %s"
+	lines := []string{
+		fmt.Sprintf("Exposure at %s, total value %s %s",
+			report.Timestamp.UTC().Format(time.RFC3339), formatNumber(report.TotalValue),
+			report.DisplayCurrency),
+		fmt.Sprintf(row, "CURRENCY", "EXCHANGE", "BALANCE", "ORDERS", "POSITIONS",
+			"TOTAL", "VALUE"),
+	}
+	for _, c := range report.Currencies {
+		value := "n/a"
+		if c.Value != nil {
+			value = formatNumber(*c.Value)
+		} else if c.ValuationError != "" {
+			value += " (" + c.ValuationError + ")"
+		}
+		lines = append(lines, fmt.Sprintf(row, c.Currency, "", formatNumber(c.Balance),
+			formatNumber(c.Orders), formatNumber(c.Positions), formatNumber(c.Total), value))
+		for _, e := range c.Exchanges {
+			lines = append(lines, strings.TrimRight(fmt.Sprintf(row, "", e.Exchange,
+				formatNumber(e.Balance), formatNumber(e.Orders), formatNumber(e.Positions),
+				formatNumber(e.Total), ""), " "))
+		}
+	}
+
+	for _, s := range report.Sources {
+		if s.Status == "ok" {
+			continue
+		}
+		line := fmt.Sprintf("Warning: %s %s %s", s.Exchange, s.Source, s.Status)
+		if !s.Updated.IsZero() {
+			line += ", updated " + s.Updated.UTC().Format(time.RFC3339)
+		}
+		if s.Error != "" {
+			line += ": " + s.Error
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatExposureReport(t *testing.T) {
+	value := 40250.0
+	report := exposureReport{
+		DisplayCurrency: "USD",
+		TotalValue:      40250,
+		Timestamp:       time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+		Currencies: []currencyExposure{
+			{
+				exchangeExposure: exchangeExposure{Balance: 3.5, Orders: 0.5,
+					Positions: 0.525, Total: 4.025},
+				Currency: "BTC",
+				Value:    &value,
+				Exchanges: []exchangeExposure{
+					{Exchange: "Bitmex", Balance: 0.5, Positions: 0.525, Total: 1.025},
+				},
+			},
+			{
+				exchangeExposure: exchangeExposure{Balance: 5, Total: 5},
+				Currency:         "LTC",
+				ValuationError:   "no ticker",
+			},
+		},
+		Sources: []exposureSource{
+			{Exchange: "Bitmex", Source: "balances", Status: "ok"},
+			{Exchange: "Kraken", Source: "positions", Status: "unavailable", Error: "timeout"},
+		},
+	}
+
+	lines := formatExposureReport(report)
+	if len(lines) != 6 {
+		t.Fatalf("Test failed - unexpected lines %q", lines)
+	}
+	if lines[0] != "Exposure at 2018-01-02T03:04:05Z, total value 40250 USD" {
+		t.Errorf("Test failed - unexpected title %q", lines[0])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "BTC 3.5 0.5 0.525 4.025 40250" {
+		t.Errorf("Test failed - unexpected currency line %q", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); strings.Join(fields, " ") != "Bitmex 0.5 0 0.525 1.025" {
+		t.Errorf("Test failed - unexpected exchange line %q", lines[3])
+	}
+	if !strings.HasSuffix(lines[4], "n/a (no ticker)") {
+		t.Errorf("Test failed - unexpected unvalued line %q", lines[4])
+	}
+	if lines[5] != "Warning: Kraken positions unavailable: timeout" {
+		t.Errorf("Test failed - unexpected source line %q", lines[5])
+	}
+}
@@ -0,0 +1,1666 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	defaultHost    = "localhost:9050"
+	requestTimeout = time.Second * 15
+)
+
+// command is a gctcli command mapped to a GoCryptoTrader webserver endpoint
+type command struct {
+	Name        string
+	Aliases     []string
+	Usage       string
+	Description string
+	// ExchangeArg is set when the first argument is an exchange name, which
+	// enables exchange name completion for the command
+	ExchangeArg bool
+	// Mutating is set when the command changes the daemon state, such
+	// commands can't be watched from the shell
+	Mutating bool
+	MinArgs  int
+	Action   func(host string, args []string) error
+}
+
+var (
+	commands []command
+
+	username  string
+	password  string
+	assumeYes bool
+
+	useTLS        bool
+	tlsSkipVerify bool
+	tlsServerName string
+	tlsCert       string
+
+	// stdin is read by confirmation prompts, the shell replaces it so prompts
+	// share its input
+	stdin io.Reader = os.Stdin
+	// sharedClient is reused by every request when set, keeping a single
+	// connection to the webserver open for the shell
+	sharedClient *http.Client
+)
+
+func init() {
+	commands = []command{
+		{
+			Name:        "getinfo",
+			Description: "gets GoCryptoTrader info",
+			Action: func(host string, _ []string) error {
+				return printRequest(host, "/info")
+			},
+		},
+		{
+			Name:        "getsettings",
+			Aliases:     []string{"settings"},
+			Description: "gets the settings GoCryptoTrader was started with, its resolved config file and data directory and the running subsystems",
+			Action: func(host string, _ []string) error {
+				return printRequest(host, "/settings")
+			},
+		},
+		{
+			Name:        "getexchanges",
+			Description: "gets the loaded exchanges and their health",
+			Action: func(host string, _ []string) error {
+				return printRequest(host, "/exchanges/health")
+			},
+		},
+		{
+			Name:        "getticker",
+			Aliases:     []string{"t"},
+			Usage:       "<exchange> <currency> [assetType] [--refresh] [--delimiter /] [--case upper|lower]",
+			Description: "gets the ticker for an exchange currency pair, --refresh fetches it from the exchange instead of the cache, --delimiter and --case override the pair display format",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				return printRequest(host, specificDataPath(
+					fmt.Sprintf("/exchanges/%s/latest/%s", args[0], args[1]), args[2:]))
+			},
+		},
+		{
+			Name:        "gettickers",
+			Usage:       "[--exchanges a,b] [--pairs BTC-USD,ETH-BTC] [--asset SPOT] [--limit n] [--pagetoken token] [--delimiter /] [--case upper|lower]",
+			Description: "gets the tickers for all enabled exchanges, filtered by exchange, pair and asset type. --limit sets the page size and --pagetoken requests the page following a response nextPageToken, --delimiter and --case override the pair display format",
+			Action: func(host string, args []string) error {
+				path, err := parseGetTickers(args)
+				if err != nil {
+					return err
+				}
+				return printRequest(host, path)
+			},
+		},
+		{
+			Name:        "getorderbook",
+			Aliases:     []string{"ob"},
+			Usage:       "<exchange> <currency> [assetType] [--refresh] [--delimiter /] [--case upper|lower]",
+			Description: "gets the orderbook for an exchange currency pair, --refresh fetches it from the exchange instead of the cache, --delimiter and --case override the pair display format",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				return printRequest(host, specificDataPath(
+					fmt.Sprintf("/exchanges/%s/orderbook/latest/%s", args[0], args[1]), args[2:]))
+			},
+		},
+		{
+			Name:        "watchticker",
+			Usage:       "<exchange> <currency> [currency...] [--asset SPOT]",
+			Description: "streams the tickers of exchange currency pairs, redrawing them in place with up and down moves highlighted until interrupted",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action:      watchTicker,
+		},
+		{
+			Name:        "watchorderbook",
+			Usage:       "<exchange> <currency> [--depth 10] [--asset SPOT]",
+			Description: "streams the orderbook of an exchange currency pair, redrawing the best --depth levels of each side with the spread and totals until interrupted",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action:      watchOrderbook,
+		},
+		{
+			Name:        "getorderbooks",
+			Usage:       "[--delimiter /] [--case upper|lower]",
+			Description: "gets the orderbooks for all enabled exchanges, --delimiter and --case override the pair display format",
+			Action: func(host string, args []string) error {
+				return printRequest(host, specificDataPath("/exchanges/orderbook/latest/all", args))
+			},
+		},
+		{
+			Name:        "getaccountinfo",
+			Aliases:     []string{"a"},
+			Usage:       "<exchange> [account]",
+			Description: "gets the account info for an exchange (requires admin credentials)",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				path := fmt.Sprintf("/exchanges/%s/accounts", args[0])
+				if len(args) > 1 {
+					path += "?account=" + url.QueryEscape(args[1])
+				}
+				body, err := sendAuthGetRequest(host, path, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getorderlimits",
+			Usage:       "<exchange>",
+			Description: "gets the order execution limits for an exchange",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, fmt.Sprintf("/exchanges/%s/limits", args[0]))
+			},
+		},
+		{
+			Name:        "getpairstatus",
+			Usage:       "<exchange>",
+			Description: "gets the trading status of the currency pairs of an exchange",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, fmt.Sprintf("/exchanges/%s/pairstatus", args[0]))
+			},
+		},
+		{
+			Name:        "getlatency",
+			Usage:       "<exchange>",
+			Description: "gets the REST request latency percentiles of an exchange by public and authenticated endpoints",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, fmt.Sprintf("/exchanges/%s/latency", args[0]))
+			},
+		},
+		{
+			Name:        "getservertime",
+			Usage:       "<exchange>",
+			Description: "gets the server time of an exchange and its offset from the local clock",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, fmt.Sprintf("/exchanges/%s/servertime", args[0]))
+			},
+		},
+		{
+			Name:        "getcryptocurrencyinfo",
+			Usage:       "<exchange> [currency]",
+			Description: "gets the withdrawal fees, minimums, confirmations and suspensions of an exchange's currencies",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				path := fmt.Sprintf("/exchanges/%s/currencies", args[0])
+				if len(args) > 1 {
+					path += "?currency=" + url.QueryEscape(args[1])
+				}
+				return printRequest(host, path)
+			},
+		},
+		{
+			Name:        "getdeposithistory",
+			Usage:       "<exchange> <currency> [-start time] [-end time]",
+			Description: "gets the deposits of a currency on an exchange with their status, optionally between RFC3339 or YYYY-MM-DD start and end times (requires admin credentials)",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				path, err := parseDepositHistory(args)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthGetRequest(host, path, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getassettypes",
+			Usage:       "<exchange>",
+			Description: "gets the supported and enabled asset types for an exchange",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, fmt.Sprintf("/exchanges/%s/assets", args[0]))
+			},
+		},
+		{
+			Name:        "enableasset",
+			Usage:       "<exchange> <assetType>",
+			Description: "enables fetching data for an exchange asset type (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				return setAssetType(host, args[0], args[1], true)
+			},
+		},
+		{
+			Name:        "disableasset",
+			Usage:       "<exchange> <assetType>",
+			Description: "disables fetching data for an exchange asset type (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				return setAssetType(host, args[0], args[1], false)
+			},
+		},
+		{
+			Name:        "enablewebsocket",
+			Usage:       "<exchange>",
+			Description: "enables and connects an exchange websocket (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return setWebsocket(host, args[0], true)
+			},
+		},
+		{
+			Name:        "disablewebsocket",
+			Usage:       "<exchange>",
+			Description: "disconnects and disables an exchange websocket (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return setWebsocket(host, args[0], false)
+			},
+		},
+		{
+			Name:        "setexchangeendpoint",
+			Usage:       "<exchange> [-api url] [-apisecondary url] [-websocket url]",
+			Description: "overrides the REST and websocket URLs of an exchange, reconnecting its websocket (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     3,
+			Action: func(host string, args []string) error {
+				endpoints, err := parseExchangeEndpoint(args[1:])
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host, fmt.Sprintf("/exchanges/%s/endpoints",
+					args[0]), endpoints, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getportfolio",
+			Aliases:     []string{"p"},
+			Description: "gets the portfolio summary",
+			Action: func(host string, _ []string) error {
+				return printRequest(host, "/portfolio/all")
+			},
+		},
+		{
+			Name:        "exposure",
+			Description: "gets the exposure to each currency across the exchanges from balances, open orders and positions (requires admin credentials)",
+			Action: func(host string, _ []string) error {
+				return printExposure(host)
+			},
+		},
+		{
+			Name:        "addportfolioaddress",
+			Usage:       "<address> <coin> [-description description] [-balance balance] [-tokens USDT,OMG] [-allowunknown]",
+			Description: "adds a watched portfolio address, ETH addresses can track ERC-20 token balances (requires admin credentials)",
+			Mutating:    true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				request, err := parseAddPortfolioAddress(args)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host, "/portfolio/addresses", request, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getforexrates",
+			Description: "gets the forex rates with their base currency, provider and when they were fetched",
+			Action: func(host string, _ []string) error {
+				return printRequest(host, "/forex/rates")
+			},
+		},
+		{
+			Name:        "convert",
+			Usage:       "<amount> <from> <to>",
+			Description: "converts an amount between currencies using the forex rates, showing the rate and the currencies converted through",
+			MinArgs:     3,
+			Action: func(host string, args []string) error {
+				path, err := parseConvert(args)
+				if err != nil {
+					return err
+				}
+				return printRequest(host, path)
+			},
+		},
+		{
+			Name:        "getstats",
+			Usage:       "<currency>",
+			Description: "gets the exchange stats for a currency pair",
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, fmt.Sprintf("/stats/%s", args[0]))
+			},
+		},
+		{
+			Name:        "getrecenttrades",
+			Usage:       "<exchange> <currency> [assetType] [--limit N]",
+			Description: "gets the most recent trades streamed by an exchange for a currency pair, newest first",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				return printRequest(host, specificDataPath(
+					fmt.Sprintf("/exchanges/%s/trades/recent/%s", args[0], args[1]), args[2:]))
+			},
+		},
+		{
+			Name:        "gettradestats",
+			Usage:       "<exchange> <currency> [assetType] [--window 1m]",
+			Description: "gets the trade count, trades per minute, volume and buy/sell imbalance of an exchange currency pair over the --window duration",
+			ExchangeArg: true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				return printRequest(host, specificDataPath(
+					fmt.Sprintf("/exchanges/%s/trades/stats/%s", args[0], args[1]), args[2:]))
+			},
+		},
+		{
+			Name:        "getrequeststats",
+			Description: "gets the call counts, errors and durations of the webserver routes",
+			Action: func(host string, args []string) error {
+				return printRequest(host, "/requeststats")
+			},
+		},
+		{
+			Name:        "simulateorder",
+			Usage:       "<currency> <buy|sell> <amount> [-exchange name] [-asset type] [-refresh]",
+			Description: "quotes the expected fill price, slippage and fee of a market order without placing it, ranking all enabled exchanges when no exchange is set",
+			MinArgs:     3,
+			Action: func(host string, args []string) error {
+				path, err := parseSimulateOrder(args)
+				if err != nil {
+					return err
+				}
+				return printRequest(host, path)
+			},
+		},
+		{
+			Name:        "getbestexecutionvenue",
+			Usage:       "<currency> <buy|sell> <amount> [-asset type] [-checkbalance]",
+			Description: "ranks the enabled exchanges by the effective cost of a market order including taker fees, -checkbalance skips exchanges without the balance for the order (requires admin credentials)",
+			MinArgs:     3,
+			Action: func(host string, args []string) error {
+				path, checkBalance, err := parseBestExecutionVenue(args)
+				if err != nil {
+					return err
+				}
+				if !checkBalance {
+					return printRequest(host, path)
+				}
+				body, err := sendAuthGetRequest(host, path, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getconsolidatedorderbook",
+			Usage:       "<currency> [-asset type] [-depth levels]",
+			Description: "merges the cached orderbooks of every enabled exchange listing a currency pair, annotating each level with its exchange",
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				path, err := parseConsolidatedOrderbook(args)
+				if err != nil {
+					return err
+				}
+				return printRequest(host, path)
+			},
+		},
+		{
+			Name:        "testexchange",
+			Usage:       "<exchange> [timeout]",
+			Description: "checks an exchange's public API and, when credentials are configured, its authenticated API can be reached without placing orders (requires admin credentials)",
+			ExchangeArg: true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				path := fmt.Sprintf("/exchanges/%s/connectivity", args[0])
+				timeout := requestTimeout
+				if len(args) > 1 {
+					checkTimeout, err := time.ParseDuration(args[1])
+					if err != nil {
+						return fmt.Errorf("invalid timeout %q", args[1])
+					}
+					path += "?timeout=" + url.QueryEscape(args[1])
+					// The public and authenticated checks each get the timeout
+					timeout = checkTimeout*2 + requestTimeout
+				}
+				body, err := sendAuthGetRequest(host, path, timeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "submitorder",
+			Usage:       "<exchange> <currency> <buy|sell> <limit|market|stop|stoplimit|trailingstop> <amount> [price] [-trigger price] [-trailoffset offset] [-tif GTC|IOC|FOK] [-postonly] [-reduceonly] [-leverage rate] [-clientid id] [-account label] [-asset type]",
+			Description: "submits an order to an exchange (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     5,
+			Action: func(host string, args []string) error {
+				order, err := parseSubmitOrder(args)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host,
+					fmt.Sprintf("/exchanges/%s/orders", args[0]), order, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getfills",
+			Usage:       "[exchange] [-pair currency] [-start time] [-end time]",
+			Description: "gets the order fills recorded by the bot, optionally for an exchange and pair between RFC3339 or YYYY-MM-DD start and end times (requires admin credentials)",
+			ExchangeArg: true,
+			Action: func(host string, args []string) error {
+				path, err := parseGetFills(args)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthGetRequest(host, path, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "cancelallorders",
+			Usage:       "[exchange] [-currency pair] [-asset type] [-account label] [-timeout 30s] [-yes]",
+			Description: "cancels the orders of an exchange, or of every enabled exchange with authenticated API support if none is given which must be confirmed (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			Action: func(host string, args []string) error {
+				request, timeout, err := parseCancelAllOrders(args)
+				if err != nil {
+					return err
+				}
+				if request.Exchange == "" && !request.Confirm {
+					if !assumeYes && !confirm(stdin, "Cancel the orders of every enabled exchange at "+host+"?") {
+						return errors.New("cancel all orders cancelled")
+					}
+					request.Confirm = true
+				}
+
+				path := "/orders/cancelall"
+				if timeout > 0 {
+					path += "?timeout=" + url.QueryEscape(timeout.String())
+				} else {
+					timeout = defaultCancelAllOrdersTimeout
+				}
+				// Exchanges are cancelled concurrently, each given the timeout
+				body, err := sendAuthRequest(host, path, request, timeout+requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getevents",
+			Description: "gets the events with when they were last evaluated and when their market data was last updated (requires admin credentials)",
+			Action: func(host string, _ []string) error {
+				body, err := sendAuthGetRequest(host, "/events", requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "addevent",
+			Usage:       "<exchange> <currency> <price|volume|depth|trade_rate|trade_imbalance> <over|under|>|>=|<|<=|==> <threshold> [-asset type] [-action action] [-side bid|ask] [-percent percent] [-window 1m]",
+			Description: "adds an event triggered when a price, volume, orderbook depth within a percentage of the mid price, or the trade rate or buy/sell imbalance over a window crosses a threshold (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     5,
+			Action: func(host string, args []string) error {
+				request, err := parseAddEvent(args)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host, "/events", request, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "backfill",
+			Usage:       "<exchange> <currency> <trades|candles> <start> <end> [-asset type] [-interval 1h] [-wait]",
+			Description: "backfills exchange history to CSV files in the data directory, an existing backfill is resumed (requires admin credentials)",
+			ExchangeArg: true,
+			Mutating:    true,
+			MinArgs:     5,
+			Action: func(host string, args []string) error {
+				request, wait, err := parseBackfill(args)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host, "/backfill", request, requestTimeout)
+				if err != nil {
+					return err
+				}
+				if !wait {
+					return printJSON(body)
+				}
+				var job backfillJob
+				err = json.Unmarshal(body, &job)
+				if err != nil {
+					return err
+				}
+				return waitBackfill(host, job.ID)
+			},
+		},
+		{
+			Name:        "getbackfill",
+			Usage:       "[id]",
+			Description: "gets the progress of all backfill jobs or a single job",
+			Action: func(host string, args []string) error {
+				if len(args) == 0 {
+					return printRequest(host, "/backfill")
+				}
+				return printRequest(host, "/backfill/"+url.PathEscape(args[0]))
+			},
+		},
+		{
+			Name:        "cancelbackfill",
+			Usage:       "<id>",
+			Description: "cancels a backfill job (requires admin credentials)",
+			Mutating:    true,
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				body, err := sendAuthRequest(host,
+					"/backfill/"+url.PathEscape(args[0])+"/cancel", nil, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "changeconfigkey",
+			Description: "re-encrypts the config file with a new key, prompting for the current and new keys (requires admin credentials)",
+			Mutating:    true,
+			Action: func(host string, _ []string) error {
+				request, err := promptConfigEncryptionKeys(stdin)
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host, "/config/encryptionkey", request, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "getconfigsection",
+			Usage:       "<section>",
+			Description: "gets a config section such as exchanges.Bitfinex or communications.slack, credentials are never returned",
+			MinArgs:     1,
+			Action: func(host string, args []string) error {
+				return printRequest(host, "/config/section/"+url.PathEscape(args[0]))
+			},
+		},
+		{
+			Name:        "updateconfigsection",
+			Usage:       "<section> <json>",
+			Description: "merges a JSON value into a config section such as exchanges.Bitfinex.httpTimeout or communications.slack, the update is validated before it's applied and saved (requires admin credentials)",
+			Mutating:    true,
+			MinArgs:     2,
+			Action: func(host string, args []string) error {
+				payload, err := parseConfigSectionPayload(args[1:])
+				if err != nil {
+					return err
+				}
+				body, err := sendAuthRequest(host, "/config/section/"+url.PathEscape(args[0]),
+					payload, requestTimeout)
+				if err != nil {
+					return err
+				}
+				return printJSON(body)
+			},
+		},
+		{
+			Name:        "shutdown",
+			Description: "gracefully shuts down GoCryptoTrader (requires admin credentials)",
+			Mutating:    true,
+			Action: func(host string, _ []string) error {
+				if !assumeYes && !confirm(stdin, "Shut down GoCryptoTrader at "+host+"?") {
+					return errors.New("shutdown cancelled")
+				}
+				body, err := sendAuthRequest(host, "/shutdown", nil, requestTimeout)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(body))
+				return nil
+			},
+		},
+		{
+			Name:        "shell",
+			Description: "starts an interactive shell running commands over a single webserver connection",
+			Action: func(host string, _ []string) error {
+				return runShell(host, os.Stdin, os.Stdout)
+			},
+		},
+		{
+			Name:        "completion",
+			Usage:       "<bash|zsh>",
+			Description: "prints the shell completion script",
+			MinArgs:     1,
+			Action: func(_ string, args []string) error {
+				script, err := completionScript(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Print(script)
+				return nil
+			},
+		},
+	}
+}
+
+// findCommand returns a command by its name or alias
+func findCommand(name string) (command, bool) {
+	for x := range commands {
+		if commands[x].Name == name || common.StringDataCompare(commands[x].Aliases, name) {
+			return commands[x], true
+		}
+	}
+	return command{}, false
+}
+
+// submitOrderRequest is the order submitted to the GoCryptoTrader webserver
+type submitOrderRequest struct {
+	Account      string  `json:"account,omitempty"`
+	Currency     string  `json:"currency"`
+	AssetType    string  `json:"assetType,omitempty"`
+	Side         string  `json:"side"`
+	OrderType    string  `json:"orderType"`
+	Amount       float64 `json:"amount"`
+	Price        float64 `json:"price"`
+	TriggerPrice float64 `json:"triggerPrice,omitempty"`
+	PegOffset    float64 `json:"pegOffset,omitempty"`
+	ClientID     string  `json:"clientId,omitempty"`
+	TimeInForce  string  `json:"timeInForce,omitempty"`
+	PostOnly     bool    `json:"postOnly,omitempty"`
+	ReduceOnly   bool    `json:"reduceOnly,omitempty"`
+	Leverage     float64 `json:"leverage,omitempty"`
+}
+
+// parseSubmitOrder parses the submitorder positional arguments followed by
+// its optional flags
+func parseSubmitOrder(args []string) (submitOrderRequest, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	var order submitOrderRequest
+	if len(positional) < 5 || len(positional) > 6 {
+		return order, errors.New("expected <exchange> <currency> <buy|sell> <limit|market|stop|stoplimit|trailingstop> <amount> [price]")
+	}
+
+	fs := flag.NewFlagSet("submitorder", flag.ContinueOnError)
+	fs.Float64Var(&order.TriggerPrice, "trigger", 0, "trigger price of stop and stop limit orders")
+	fs.Float64Var(&order.PegOffset, "trailoffset", 0, "distance a trailing stop trails the market price by")
+	fs.StringVar(&order.TimeInForce, "tif", "", "time in force: GTC, IOC or FOK")
+	fs.BoolVar(&order.PostOnly, "postonly", false, "only add liquidity to the orderbook")
+	fs.BoolVar(&order.ReduceOnly, "reduceonly", false, "only reduce an open position")
+	fs.Float64Var(&order.Leverage, "leverage", 0, "leverage of futures orders")
+	fs.StringVar(&order.ClientID, "clientid", "", "client order ID")
+	fs.StringVar(&order.Account, "account", "", "exchange sub-account label")
+	fs.StringVar(&order.AssetType, "asset", "", "asset type, defaults to SPOT")
+	err := fs.Parse(flags)
+	if err != nil {
+		return order, err
+	}
+	if fs.NArg() > 0 {
+		return order, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	order.Currency = positional[1]
+	order.Side = positional[2]
+	order.OrderType = positional[3]
+	order.Amount, err = strconv.ParseFloat(positional[4], 64)
+	if err != nil {
+		return order, fmt.Errorf("invalid amount %q", positional[4])
+	}
+	if len(positional) == 6 {
+		order.Price, err = strconv.ParseFloat(positional[5], 64)
+		if err != nil {
+			return order, fmt.Errorf("invalid price %q", positional[5])
+		}
+	}
+	return order, nil
+}
+
+// cancelAllOrdersRequest cancels the orders of an exchange, or of every
+// enabled exchange if empty
+type cancelAllOrdersRequest struct {
+	Exchange  string `json:"exchange,omitempty"`
+	Account   string `json:"account,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+	AssetType string `json:"assetType,omitempty"`
+	Confirm   bool   `json:"confirm,omitempty"`
+}
+
+// defaultCancelAllOrdersTimeout is the time the daemon gives each exchange to
+// cancel its orders when no timeout is supplied
+const defaultCancelAllOrdersTimeout = 30 * time.Second
+
+// parseCancelAllOrders parses the cancelallorders optional exchange argument
+// followed by its optional flags, returning the request and the timeout each
+// exchange is given or zero for the daemon default
+func parseCancelAllOrders(args []string) (cancelAllOrdersRequest, time.Duration, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	var request cancelAllOrdersRequest
+	if len(positional) > 1 {
+		return request, 0, errors.New("expected [exchange]")
+	}
+
+	var timeout time.Duration
+	fs := flag.NewFlagSet("cancelallorders", flag.ContinueOnError)
+	fs.StringVar(&request.Currency, "currency", "", "currency pair of the orders")
+	fs.StringVar(&request.AssetType, "asset", "", "asset type of the orders")
+	fs.StringVar(&request.Account, "account", "", "exchange sub-account label")
+	fs.DurationVar(&timeout, "timeout", 0, "time each exchange is given to cancel its orders")
+	fs.BoolVar(&request.Confirm, "yes", false, "confirms cancelling the orders of every exchange")
+	err := fs.Parse(flags)
+	if err != nil {
+		return request, 0, err
+	}
+	if fs.NArg() > 0 {
+		return request, 0, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if timeout < 0 {
+		return request, 0, fmt.Errorf("invalid timeout %v", timeout)
+	}
+
+	if len(positional) == 1 {
+		request.Exchange = positional[0]
+	}
+	return request, timeout, nil
+}
+
+// addEventRequest holds the details of an event sent to the webserver
+type addEventRequest struct {
+	Exchange  string  `json:"exchange"`
+	Item      string  `json:"item"`
+	Condition string  `json:"condition"`
+	Currency  string  `json:"currency"`
+	AssetType string  `json:"assetType,omitempty"`
+	Action    string  `json:"action,omitempty"`
+	Side      string  `json:"side,omitempty"`
+	Percent   float64 `json:"percent,omitempty"`
+	Window    string  `json:"window,omitempty"`
+}
+
+// parseAddEvent parses the addevent positional arguments followed by its
+// optional flags
+func parseAddEvent(args []string) (addEventRequest, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	var request addEventRequest
+	if len(positional) != 5 {
+		return request, errors.New("expected <exchange> <currency> <price|volume|depth|trade_rate|trade_imbalance> <over|under|>|>=|<|<=|==> <threshold>")
+	}
+
+	fs := flag.NewFlagSet("addevent", flag.ContinueOnError)
+	fs.StringVar(&request.AssetType, "asset", "", "asset type, defaults to SPOT")
+	fs.StringVar(&request.Action, "action", "", "action run when triggered, defaults to CONSOLE_PRINT")
+	fs.StringVar(&request.Side, "side", "", "orderbook side of depth events: bid or ask")
+	fs.Float64Var(&request.Percent, "percent", 0, "distance from the mid price of depth events in percent")
+	fs.StringVar(&request.Window, "window", "", "window trade_rate and trade_imbalance events are measured over, defaults to 1m")
+	err := fs.Parse(flags)
+	if err != nil {
+		return request, err
+	}
+	if fs.NArg() > 0 {
+		return request, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	operator := positional[3]
+	switch common.StringToLower(operator) {
+	case "over":
+		operator = ">"
+	case "under":
+		operator = "<"
+	case ">", ">=", "<", "<=", "==":
+	default:
+		return request, fmt.Errorf("invalid condition %q", positional[3])
+	}
+	_, err = strconv.ParseFloat(positional[4], 64)
+	if err != nil {
+		return request, fmt.Errorf("invalid threshold %q", positional[4])
+	}
+
+	request.Item = common.StringToLower(positional[2])
+	if request.Item != "depth" && (request.Side != "" || request.Percent != 0) {
+		return request, errors.New("-side and -percent are only supported by depth events")
+	}
+	if request.Window != "" && request.Item != "trade_rate" && request.Item != "trade_imbalance" {
+		return request, errors.New("-window is only supported by trade_rate and trade_imbalance events")
+	}
+
+	request.Exchange = positional[0]
+	request.Currency = positional[1]
+	request.Condition = operator + "," + positional[4]
+	return request, nil
+}
+
+// configEncryptionKeyRequest holds the current and new config encryption keys
+// sent to the webserver
+type configEncryptionKeyRequest struct {
+	OldKey string `json:"oldKey"`
+	NewKey string `json:"newKey"`
+}
+
+// promptConfigEncryptionKeys reads the current config encryption key and the
+// new key, entered twice, so the keys aren't kept in the shell history
+func promptConfigEncryptionKeys(in io.Reader) (configEncryptionKeyRequest, error) {
+	reader := bufio.NewReader(in)
+	readKey := func(prompt string) (string, error) {
+		fmt.Printf("%s: ", prompt)
+		key, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return common.TrimString(key, "\r\n"), nil
+	}
+
+	var request configEncryptionKeyRequest
+	var err error
+	request.OldKey, err = readKey("Current config encryption key")
+	if err != nil {
+		return request, err
+	}
+	request.NewKey, err = readKey("New config encryption key")
+	if err != nil {
+		return request, err
+	}
+	confirmation, err := readKey("Re-enter the new config encryption key")
+	if err != nil {
+		return request, err
+	}
+
+	if request.OldKey == "" || request.NewKey == "" {
+		return request, errors.New("config encryption keys can't be empty")
+	}
+	if request.NewKey != confirmation {
+		return request, errors.New("new config encryption keys don't match")
+	}
+	return request, nil
+}
+
+// parseConfigSectionPayload joins the updateconfigsection JSON arguments, which
+// the shell may have split on spaces, and checks they're valid JSON
+func parseConfigSectionPayload(args []string) (json.RawMessage, error) {
+	payload := json.RawMessage(common.JoinStrings(args, " "))
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("invalid JSON config section value %s", payload)
+	}
+	return payload, nil
+}
+
+type portfolioAddressRequest struct {
+	Address          string   `json:"address"`
+	CoinType         string   `json:"coinType"`
+	Description      string   `json:"description,omitempty"`
+	Balance          float64  `json:"balance,omitempty"`
+	Tokens           []string `json:"tokens,omitempty"`
+	AllowUnknownCoin bool     `json:"allowUnknownCoin,omitempty"`
+}
+
+// parseAddPortfolioAddress parses the addportfolioaddress positional arguments
+// followed by its optional flags
+func parseAddPortfolioAddress(args []string) (portfolioAddressRequest, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	var request portfolioAddressRequest
+	if len(positional) != 2 {
+		return request, errors.New("expected <address> <coin>")
+	}
+
+	var tokens string
+	fs := flag.NewFlagSet("addportfolioaddress", flag.ContinueOnError)
+	fs.StringVar(&request.Description, "description", "", "address description, defaults to Personal")
+	fs.Float64Var(&request.Balance, "balance", 0, "initial balance, refreshed by the portfolio watcher")
+	fs.StringVar(&tokens, "tokens", "", "comma separated tokens tracked on the address")
+	fs.BoolVar(&request.AllowUnknownCoin, "allowunknown", false, "allow coins and tokens which aren't known cryptocurrencies")
+	err := fs.Parse(flags)
+	if err != nil {
+		return request, err
+	}
+	if fs.NArg() > 0 {
+		return request, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if request.Balance < 0 {
+		return request, fmt.Errorf("invalid balance %v", request.Balance)
+	}
+	if tokens != "" {
+		request.Tokens = common.SplitStrings(tokens, ",")
+	}
+
+	request.Address = positional[0]
+	request.CoinType = common.StringToUpper(positional[1])
+	return request, nil
+}
+
+// parseConvert parses the convert command arguments into the currency
+// conversion request path
+func parseConvert(args []string) (string, error) {
+	if len(args) != 3 {
+		return "", errors.New("expected <amount> <from> <to>")
+	}
+	_, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid amount %q", args[0])
+	}
+
+	values := url.Values{}
+	values.Set("amount", args[0])
+	values.Set("from", args[1])
+	values.Set("to", args[2])
+	return "/forex/convert?" + values.Encode(), nil
+}
+
+// parseSimulateOrder parses the simulateorder command arguments into the
+// order simulation request path
+func parseSimulateOrder(args []string) (string, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	if len(positional) != 3 {
+		return "", errors.New("expected <currency> <buy|sell> <amount>")
+	}
+
+	var exchName, assetType string
+	var refresh bool
+	fs := flag.NewFlagSet("simulateorder", flag.ContinueOnError)
+	fs.StringVar(&exchName, "exchange", "", "exchange to simulate the order on, defaults to all enabled exchanges")
+	fs.StringVar(&assetType, "asset", "", "asset type, defaults to SPOT")
+	fs.BoolVar(&refresh, "refresh", false, "fetch a fresh orderbook instead of the cached one")
+	err := fs.Parse(flags)
+	if err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	_, err = strconv.ParseFloat(positional[2], 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid amount %q", positional[2])
+	}
+
+	values := url.Values{}
+	values.Set("side", positional[1])
+	values.Set("amount", positional[2])
+	if exchName != "" {
+		values.Set("exchange", exchName)
+	}
+	if assetType != "" {
+		values.Set("assetType", assetType)
+	}
+	if refresh {
+		values.Set("refresh", "true")
+	}
+	return fmt.Sprintf("/simulate/%s?%s", url.PathEscape(positional[0]),
+		values.Encode()), nil
+}
+
+// parseDepositHistory parses the getdeposithistory command arguments into the
+// deposit history request path
+func parseDepositHistory(args []string) (string, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	if len(positional) != 2 {
+		return "", errors.New("expected <exchange> <currency>")
+	}
+
+	var start, end string
+	fs := flag.NewFlagSet("getdeposithistory", flag.ContinueOnError)
+	fs.StringVar(&start, "start", "", "earliest deposit time")
+	fs.StringVar(&end, "end", "", "latest deposit time")
+	err := fs.Parse(flags)
+	if err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	values := url.Values{}
+	values.Set("currency", positional[1])
+	for name, value := range map[string]string{"start": start, "end": end} {
+		if value == "" {
+			continue
+		}
+		t, err := parseBackfillTime(value)
+		if err != nil {
+			return "", err
+		}
+		values.Set(name, t.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("/exchanges/%s/deposits?%s", positional[0],
+		values.Encode()), nil
+}
+
+// parseGetFills parses the getfills command arguments into the fills request
+// path
+func parseGetFills(args []string) (string, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	if len(positional) > 1 {
+		return "", errors.New("expected [exchange]")
+	}
+
+	var currency, start, end string
+	fs := flag.NewFlagSet("getfills", flag.ContinueOnError)
+	fs.StringVar(&currency, "pair", "", "currency pair of the fills")
+	fs.StringVar(&start, "start", "", "earliest fill time")
+	fs.StringVar(&end, "end", "", "latest fill time")
+	err := fs.Parse(flags)
+	if err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	values := url.Values{}
+	if len(positional) == 1 {
+		values.Set("exchange", positional[0])
+	}
+	if currency != "" {
+		values.Set("pair", currency)
+	}
+	for name, value := range map[string]string{"start": start, "end": end} {
+		if value == "" {
+			continue
+		}
+		t, err := parseBackfillTime(value)
+		if err != nil {
+			return "", err
+		}
+		values.Set(name, t.Format(time.RFC3339))
+	}
+	if len(values) == 0 {
+		return "/fills", nil
+	}
+	return "/fills?" + values.Encode(), nil
+}
+
+// parseBestExecutionVenue parses the getbestexecutionvenue command arguments
+// into the best execution request path and whether balances are checked
+func parseBestExecutionVenue(args []string) (string, bool, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	if len(positional) != 3 {
+		return "", false, errors.New("expected <currency> <buy|sell> <amount>")
+	}
+
+	var assetType string
+	var checkBalance bool
+	fs := flag.NewFlagSet("getbestexecutionvenue", flag.ContinueOnError)
+	fs.StringVar(&assetType, "asset", "", "asset type, defaults to SPOT")
+	fs.BoolVar(&checkBalance, "checkbalance", false, "skip exchanges without the balance for the order")
+	err := fs.Parse(flags)
+	if err != nil {
+		return "", false, err
+	}
+	if fs.NArg() > 0 {
+		return "", false, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	_, err = strconv.ParseFloat(positional[2], 64)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid amount %q", positional[2])
+	}
+
+	values := url.Values{}
+	values.Set("side", positional[1])
+	values.Set("amount", positional[2])
+	if assetType != "" {
+		values.Set("assetType", assetType)
+	}
+	if checkBalance {
+		values.Set("checkBalance", "true")
+	}
+	return fmt.Sprintf("/bestexecution/%s?%s", url.PathEscape(positional[0]),
+		values.Encode()), checkBalance, nil
+}
+
+// parseConsolidatedOrderbook parses the getconsolidatedorderbook command
+// arguments into the consolidated orderbook request path
+func parseConsolidatedOrderbook(args []string) (string, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	if len(positional) != 1 {
+		return "", errors.New("expected <currency>")
+	}
+
+	var assetType string
+	var depth int
+	fs := flag.NewFlagSet("getconsolidatedorderbook", flag.ContinueOnError)
+	fs.StringVar(&assetType, "asset", "", "asset type, defaults to SPOT")
+	fs.IntVar(&depth, "depth", 0, "number of levels on each side, defaults to 50")
+	err := fs.Parse(flags)
+	if err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if depth < 0 {
+		return "", fmt.Errorf("invalid depth %d", depth)
+	}
+
+	values := url.Values{}
+	if assetType != "" {
+		values.Set("assetType", assetType)
+	}
+	if depth > 0 {
+		values.Set("depth", strconv.Itoa(depth))
+	}
+	path := "/orderbook/consolidated/" + url.PathEscape(positional[0])
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	return path, nil
+}
+
+// parseGetTickers parses the gettickers command flags into the tickers
+// request path
+func parseGetTickers(args []string) (string, error) {
+	var exchanges, pairs, assetType, pageToken, delimiter, pairCase string
+	var limit int
+	fs := flag.NewFlagSet("gettickers", flag.ContinueOnError)
+	fs.StringVar(&exchanges, "exchanges", "", "comma separated exchanges")
+	fs.StringVar(&pairs, "pairs", "", "comma separated currency pairs")
+	fs.StringVar(&assetType, "asset", "", "asset type")
+	fs.IntVar(&limit, "limit", 0, "maximum number of tickers returned")
+	fs.StringVar(&pageToken, "pagetoken", "", "next page token of a previous response")
+	fs.StringVar(&delimiter, "delimiter", "", "pair display delimiter")
+	fs.StringVar(&pairCase, "case", "", "pair display case, upper or lower")
+	err := fs.Parse(args)
+	if err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if limit < 0 {
+		return "", fmt.Errorf("invalid limit %d", limit)
+	}
+
+	values := url.Values{}
+	for name, value := range map[string]string{
+		"exchanges":     exchanges,
+		"pairs":         pairs,
+		"assetType":     assetType,
+		"pageToken":     pageToken,
+		"pairDelimiter": delimiter,
+		"pairCase":      pairCase,
+	} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	if limit > 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	if len(values) == 0 {
+		return "/exchanges/enabled/latest/all", nil
+	}
+	return "/exchanges/enabled/latest/all?" + values.Encode(), nil
+}
+
+// specificDataPath appends the optional asset type argument and --refresh
+// flag of the ticker and orderbook commands to a request path
+func specificDataPath(path string, args []string) string {
+	values := url.Values{}
+	for x := 0; x < len(args); x++ {
+		switch args[x] {
+		case "--refresh", "-refresh":
+			values.Set("refresh", "true")
+		case "--delimiter", "-delimiter", "--case", "-case":
+			if x+1 < len(args) {
+				name := "pairDelimiter"
+				if args[x] == "--case" || args[x] == "-case" {
+					name = "pairCase"
+				}
+				values.Set(name, args[x+1])
+				x++
+			}
+		case "--limit", "-limit", "--window", "-window":
+			if x+1 < len(args) {
+				values.Set(strings.TrimLeft(args[x], "-"), args[x+1])
+				x++
+			}
+		default:
+			values.Set("assetType", args[x])
+		}
+	}
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+// sendRequest sends a GET request to the GoCryptoTrader webserver
+func sendRequest(host, path string, timeout time.Duration) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, baseURL(host)+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRequest(req, path, timeout)
+}
+
+// sendAuthGetRequest sends a GET request authenticated with the admin
+// credentials to the GoCryptoTrader webserver
+func sendAuthGetRequest(host, path string, timeout time.Duration) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, baseURL(host)+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(username, password)
+	return doRequest(req, path, timeout)
+}
+
+// sendAuthRequest sends a POST request authenticated with the admin
+// credentials to the GoCryptoTrader webserver. The optional payload is sent
+// as the JSON request body
+func sendAuthRequest(host, path string, payload interface{}, timeout time.Duration) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, baseURL(host)+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(username, password)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return doRequest(req, path, timeout)
+}
+
+// baseURL returns the webserver URL for the host using HTTPS if TLS is
+// enabled
+func baseURL(host string) string {
+	if useTLS {
+		return "https://" + host
+	}
+	return "http://" + host
+}
+
+// newHTTPClient returns a client which trusts the webserver certificate when
+// TLS is enabled. The server name override allows connecting by an address the
+// certificate isn't valid for
+func newHTTPClient(timeout time.Duration) (*http.Client, error) {
+	client := &http.Client{Timeout: timeout}
+	if !useTLS {
+		return client, nil
+	}
+
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	return client, nil
+}
+
+// newTLSConfig returns the TLS config used to connect to the webserver,
+// trusting the -tlscert certificate if set
+func newTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         tlsServerName,
+		InsecureSkipVerify: tlsSkipVerify,
+	}
+	if !tlsSkipVerify && tlsCert != "" {
+		data, err := ioutil.ReadFile(tlsCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", tlsCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// statusError is returned when the webserver responds with an unsuccessful
+// status code
+type statusError struct {
+	Path       string
+	StatusCode int
+	Body       []byte
+}
+
+func (s *statusError) Error() string {
+	return fmt.Sprintf("request %s failed with status %d: %s", s.Path,
+		s.StatusCode, s.Body)
+}
+
+func doRequest(req *http.Request, path string, timeout time.Duration) ([]byte, error) {
+	client := sharedClient
+	if client == nil {
+		var err error
+		client, err = newHTTPClient(timeout)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{Path: path, StatusCode: resp.StatusCode, Body: body}
+	}
+	return body, nil
+}
+
+// confirm prompts the user and returns true if they answer yes
+func confirm(in io.Reader, prompt string) bool {
+	fmt.Printf("%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+	answer = common.StringToLower(common.TrimString(answer, " \r\n"))
+	return answer == "y" || answer == "yes"
+}
+
+// printRequest sends a GET request and prints the indented JSON response
+func printRequest(host, path string) error {
+	body, err := sendRequest(host, path, requestTimeout)
+	if err != nil {
+		return err
+	}
+	return printJSON(body)
+}
+
+// printJSON prints an indented JSON response
+func printJSON(body []byte) error {
+	if len(body) == 0 {
+		return errors.New("empty response received")
+	}
+
+	var out bytes.Buffer
+	err := json.Indent(&out, body, "", " ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(out.String())
+	return nil
+}
+
+// setAssetType enables or disables an exchange asset type and prints the
+// resulting asset types
+func setAssetType(host, exchName, assetType string, enable bool) error {
+	action := "disable"
+	if enable {
+		action = "enable"
+	}
+	body, err := sendAuthRequest(host, fmt.Sprintf("/exchanges/%s/assets/%s/%s",
+		exchName, url.PathEscape(assetType), action), nil, requestTimeout)
+	if err != nil {
+		return err
+	}
+	return printJSON(body)
+}
+
+// setWebsocket enables or disables an exchange websocket and prints its
+// resulting connection state
+func setWebsocket(host, exchName string, enable bool) error {
+	action := "disable"
+	if enable {
+		action = "enable"
+	}
+	body, err := sendAuthRequest(host, fmt.Sprintf("/exchanges/%s/websocket/%s",
+		exchName, action), nil, requestTimeout)
+	if err != nil {
+		return err
+	}
+	return printJSON(body)
+}
+
+// exchangeEndpointRequest holds the endpoint URLs of an exchange to override,
+// empty URLs are left unchanged
+type exchangeEndpointRequest struct {
+	APIURL          string `json:"apiUrl,omitempty"`
+	APIURLSecondary string `json:"apiUrlSecondary,omitempty"`
+	WebsocketURL    string `json:"websocketUrl,omitempty"`
+}
+
+// parseExchangeEndpoint parses the setexchangeendpoint flags, at least one
+// URL is required
+func parseExchangeEndpoint(args []string) (exchangeEndpointRequest, error) {
+	var request exchangeEndpointRequest
+	fs := flag.NewFlagSet("setexchangeendpoint", flag.ContinueOnError)
+	fs.StringVar(&request.APIURL, "api", "", "REST API URL")
+	fs.StringVar(&request.APIURLSecondary, "apisecondary", "", "secondary REST API URL")
+	fs.StringVar(&request.WebsocketURL, "websocket", "", "websocket URL")
+	err := fs.Parse(args)
+	if err != nil {
+		return request, err
+	}
+	if fs.NArg() > 0 {
+		return request, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if request == (exchangeEndpointRequest{}) {
+		return request, errors.New("expected at least one of -api, -apisecondary or -websocket")
+	}
+	return request, nil
+}
+
+// printCommands prints the commands with their aliases and arguments
+func printCommands(w io.Writer) {
+	for x := range commands {
+		name := commands[x].Name
+		if len(commands[x].Aliases) > 0 {
+			name += ", " + common.JoinStrings(commands[x].Aliases, ", ")
+		}
+		fmt.Fprintf(w, "  %-18s %s\n", name, commands[x].Description)
+		if commands[x].Usage != "" {
+			fmt.Fprintf(w, "  %-18s arguments: %s\n", "", commands[x].Usage)
+		}
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: gctcli [flags] <command> [arguments]\n\nCommands:\n")
+	printCommands(os.Stderr)
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// registerGlobalFlags registers the flags preceding the command and returns
+// the webserver address flag
+func registerGlobalFlags(fs *flag.FlagSet) *string {
+	host := fs.String("host", defaultHost, "GoCryptoTrader webserver address")
+	fs.StringVar(&username, "username", "admin", "GoCryptoTrader admin username")
+	fs.StringVar(&password, "password", "", "GoCryptoTrader admin password")
+	fs.BoolVar(&assumeYes, "y", false, "skips confirmation prompts")
+	fs.BoolVar(&useTLS, "tls", false, "connects to the webserver using HTTPS")
+	fs.BoolVar(&tlsSkipVerify, "tlsskipverify", false, "skips verification of the webserver certificate, for lab setups only")
+	fs.StringVar(&tlsServerName, "tlsservername", "", "overrides the server name used to verify the webserver certificate")
+	fs.StringVar(&tlsCert, "tlscert", filepath.Join(common.GetDefaultDataDir(runtime.GOOS), "tls", "cert.pem"),
+		"webserver certificate to trust, the system roots are used if empty")
+	return host
+}
+
+func main() {
+	host := registerGlobalFlags(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(1)
+	}
+
+	name := flag.Arg(0)
+	args := flag.Args()[1:]
+
+	if name == completeExchangesCommand {
+		if len(args) > 0 {
+			*host = parseCompletionLine(args[0])
+		}
+		printExchangeNames(*host)
+		return
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+		usage()
+		os.Exit(1)
+	}
+
+	if len(args) < cmd.MinArgs {
+		log.Fatalf("Usage: gctcli %s %s", cmd.Name, cmd.Usage)
+	}
+
+	err := cmd.Action(*host, args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
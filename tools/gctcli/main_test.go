@@ -0,0 +1,539 @@
+package main
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfirm(t *testing.T) {
+	answers := map[string]bool{
+		"y\n":   true,
+		"YES\n": true,
+		" y ":   true,
+		"n\n":   false,
+		"\n":    false,
+		"":      false,
+	}
+	for answer, expected := range answers {
+		if confirm(strings.NewReader(answer), "Continue?") != expected {
+			t.Errorf("Test failed - confirm(%q) expected %v", answer, expected)
+		}
+	}
+}
+
+func TestSendAuthRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if r.Method != http.MethodPost || !ok || user != "admin" || pass != "Password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"status":"shutting down"}`)
+	}))
+	defer server.Close()
+
+	host := server.Listener.Addr().String()
+	username, password = "admin", "wrong"
+	_, err := sendAuthRequest(host, "/shutdown", nil, requestTimeout)
+	if err == nil {
+		t.Error("Test failed - sendAuthRequest() accepted invalid credentials")
+	}
+
+	password = "Password"
+	body, err := sendAuthRequest(host, "/shutdown", nil, requestTimeout)
+	if err != nil {
+		t.Fatalf("Test failed - sendAuthRequest() error: %s", err)
+	}
+	if string(body) != `{"status":"shutting down"}` {
+		t.Errorf("Test failed - unexpected response %s", body)
+	}
+}
+
+func TestSendRequestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	certFile, err := ioutil.TempFile("", "gctclicert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(certFile.Name())
+	err = pem.Encode(certFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile.Close()
+
+	defer func() {
+		useTLS, tlsSkipVerify, tlsServerName, tlsCert = false, false, "", ""
+	}()
+	host := server.Listener.Addr().String()
+	useTLS = true
+	_, err = sendRequest(host, "/exchanges/health", requestTimeout)
+	if err == nil {
+		t.Error("Test failed - sendRequest() trusted an unknown certificate")
+	}
+
+	tlsCert = certFile.Name()
+	_, err = sendRequest(host, "/exchanges/health", requestTimeout)
+	if err != nil {
+		t.Errorf("Test failed - sendRequest() error: %s", err)
+	}
+
+	tlsServerName = "gct.example.org"
+	_, err = sendRequest(host, "/exchanges/health", requestTimeout)
+	if err == nil {
+		t.Error("Test failed - sendRequest() ignored the server name override")
+	}
+
+	tlsServerName = "example.com"
+	_, err = sendRequest(host, "/exchanges/health", requestTimeout)
+	if err != nil {
+		t.Errorf("Test failed - sendRequest() server name override error: %s", err)
+	}
+
+	tlsServerName, tlsCert, tlsSkipVerify = "gct.example.org", "", true
+	_, err = sendRequest(host, "/exchanges/health", requestTimeout)
+	if err != nil {
+		t.Errorf("Test failed - sendRequest() tlsskipverify error: %s", err)
+	}
+}
+
+func TestSpecificDataPath(t *testing.T) {
+	paths := map[string][]string{
+		"/exchanges/Bitfinex/latest/BTCUSD":                             nil,
+		"/exchanges/Bitfinex/latest/BTCUSD?assetType=SPOT":              {"SPOT"},
+		"/exchanges/Bitfinex/latest/BTCUSD?refresh=true":                {"--refresh"},
+		"/exchanges/Bitfinex/latest/BTCUSD?assetType=SPOT&refresh=true": {"--refresh", "SPOT"},
+		"/exchanges/Bitfinex/latest/BTCUSD?pairCase=lower&pairDelimiter=%2F": {"--delimiter", "/",
+			"-case", "lower"},
+		"/exchanges/Bitfinex/latest/BTCUSD?assetType=SPOT&limit=5": {"SPOT", "--limit", "5"},
+		"/exchanges/Bitfinex/latest/BTCUSD?window=5m":              {"-window", "5m"},
+	}
+	for expected, args := range paths {
+		path := specificDataPath("/exchanges/Bitfinex/latest/BTCUSD", args)
+		if path != expected {
+			t.Errorf("Test failed - specificDataPath(%v) expected %s, got %s",
+				args, expected, path)
+		}
+	}
+}
+
+func TestParseSubmitOrder(t *testing.T) {
+	order, err := parseSubmitOrder([]string{"Bitmex", "XBTUSD", "buy", "limit", "10", "6500",
+		"-tif", "GTC", "-postonly", "-clientid", "abc"})
+	if err != nil {
+		t.Fatalf("Test failed - parseSubmitOrder() error: %s", err)
+	}
+	if order.Currency != "XBTUSD" || order.Side != "buy" || order.OrderType != "limit" ||
+		order.Amount != 10 || order.Price != 6500 || order.TimeInForce != "GTC" ||
+		!order.PostOnly || order.ReduceOnly || order.ClientID != "abc" {
+		t.Errorf("Test failed - unexpected order %+v", order)
+	}
+
+	order, err = parseSubmitOrder([]string{"OKEX", "BTC_USD", "sell", "market", "10", "-reduceonly",
+		"-asset", "quarter", "-leverage", "20"})
+	if err != nil {
+		t.Fatalf("Test failed - parseSubmitOrder() error: %s", err)
+	}
+	if order.Price != 0 || !order.ReduceOnly || order.AssetType != "quarter" ||
+		order.Leverage != 20 {
+		t.Errorf("Test failed - unexpected order %+v", order)
+	}
+
+	order, err = parseSubmitOrder([]string{"Bitmex", "XBTUSD", "sell", "trailingstop", "10",
+		"-trigger", "6450", "-trailoffset", "25.5"})
+	if err != nil {
+		t.Fatalf("Test failed - parseSubmitOrder() error: %s", err)
+	}
+	if order.OrderType != "trailingstop" || order.TriggerPrice != 6450 ||
+		order.PegOffset != 25.5 {
+		t.Errorf("Test failed - unexpected order %+v", order)
+	}
+
+	for _, args := range [][]string{
+		{"Bitmex", "XBTUSD", "buy", "stop", "10", "-trigger", "high"},
+		{"Bitmex", "XBTUSD", "buy", "limit"},
+		{"Bitmex", "XBTUSD", "buy", "limit", "ten"},
+		{"Bitmex", "XBTUSD", "buy", "limit", "10", "6500", "-blah"},
+	} {
+		_, err = parseSubmitOrder(args)
+		if err == nil {
+			t.Errorf("Test failed - parseSubmitOrder(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseAddEvent(t *testing.T) {
+	request, err := parseAddEvent([]string{"Bitstamp", "BTCUSD", "depth", "under", "10",
+		"-side", "bid", "-percent", "0.5"})
+	if err != nil {
+		t.Fatalf("Test failed - parseAddEvent() error: %s", err)
+	}
+	if request.Exchange != "Bitstamp" || request.Currency != "BTCUSD" ||
+		request.Item != "depth" || request.Condition != "<,10" ||
+		request.Side != "bid" || request.Percent != 0.5 {
+		t.Errorf("Test failed - unexpected event %+v", request)
+	}
+
+	request, err = parseAddEvent([]string{"Bitstamp", "BTCUSD", "VOLUME", ">=", "1000",
+		"-asset", "SPOT"})
+	if err != nil {
+		t.Fatalf("Test failed - parseAddEvent() error: %s", err)
+	}
+	if request.Item != "volume" || request.Condition != ">=,1000" || request.AssetType != "SPOT" {
+		t.Errorf("Test failed - unexpected event %+v", request)
+	}
+
+	request, err = parseAddEvent([]string{"Bitstamp", "BTCUSD", "TRADE_RATE", "over", "30",
+		"-window", "5m"})
+	if err != nil {
+		t.Fatalf("Test failed - parseAddEvent() error: %s", err)
+	}
+	if request.Item != "trade_rate" || request.Condition != ">,30" || request.Window != "5m" {
+		t.Errorf("Test failed - unexpected event %+v", request)
+	}
+
+	for _, args := range [][]string{
+		{"Bitstamp", "BTCUSD", "price", "over"},
+		{"Bitstamp", "BTCUSD", "price", "above", "100"},
+		{"Bitstamp", "BTCUSD", "price", "over", "high"},
+		{"Bitstamp", "BTCUSD", "volume", "over", "100", "-side", "bid"},
+		{"Bitstamp", "BTCUSD", "depth", "over", "100", "-percent", "half"},
+		{"Bitstamp", "BTCUSD", "price", "over", "100", "-window", "5m"},
+	} {
+		_, err = parseAddEvent(args)
+		if err == nil {
+			t.Errorf("Test failed - parseAddEvent(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseAddPortfolioAddress(t *testing.T) {
+	request, err := parseAddPortfolioAddress([]string{"0xb794f5ea0ba39494ce839613fffba74279579268",
+		"eth", "-tokens", "USDT,OMG", "-balance", "1.5"})
+	if err != nil {
+		t.Fatalf("Test failed - parseAddPortfolioAddress() error: %s", err)
+	}
+	if request.CoinType != "ETH" || request.Balance != 1.5 || len(request.Tokens) != 2 ||
+		request.Tokens[1] != "OMG" || request.AllowUnknownCoin {
+		t.Errorf("Test failed - unexpected address %+v", request)
+	}
+
+	for _, args := range [][]string{
+		{"0xb794f5ea0ba39494ce839613fffba74279579268"},
+		{"0xb794f5ea0ba39494ce839613fffba74279579268", "ETH", "OMG"},
+		{"0xb794f5ea0ba39494ce839613fffba74279579268", "ETH", "-balance", "-1"},
+	} {
+		_, err = parseAddPortfolioAddress(args)
+		if err == nil {
+			t.Errorf("Test failed - parseAddPortfolioAddress(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseGetTickers(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "/exchanges/enabled/latest/all"},
+		{[]string{"--case", "lower"}, "/exchanges/enabled/latest/all?pairCase=lower"},
+		{[]string{"-exchanges", "Bitstamp,okcoin", "--pairs", "BTC-USD", "--asset", "SPOT",
+			"--limit", "10", "--pagetoken", "MTA"},
+			"/exchanges/enabled/latest/all?assetType=SPOT&exchanges=Bitstamp%2Cokcoin&limit=10&pageToken=MTA&pairs=BTC-USD"},
+	} {
+		path, err := parseGetTickers(test.args)
+		if err != nil {
+			t.Fatalf("Test failed - parseGetTickers(%v) error: %s", test.args, err)
+		}
+		if path != test.expected {
+			t.Errorf("Test failed - expected %s, got %s", test.expected, path)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"SPOT"},
+		{"--limit", "-1"},
+		{"--limit", "ten"},
+	} {
+		_, err := parseGetTickers(args)
+		if err == nil {
+			t.Errorf("Test failed - parseGetTickers(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseCancelAllOrders(t *testing.T) {
+	request, timeout, err := parseCancelAllOrders(nil)
+	if err != nil {
+		t.Fatalf("Test failed - parseCancelAllOrders() error: %s", err)
+	}
+	if request.Exchange != "" || request.Confirm || timeout != 0 {
+		t.Errorf("Test failed - unexpected request %+v %v", request, timeout)
+	}
+
+	request, timeout, err = parseCancelAllOrders([]string{"Bitstamp", "-currency", "BTCUSD",
+		"-account", "sub", "-timeout", "1m"})
+	if err != nil {
+		t.Fatalf("Test failed - parseCancelAllOrders() error: %s", err)
+	}
+	if request.Exchange != "Bitstamp" || request.Currency != "BTCUSD" ||
+		request.Account != "sub" || request.Confirm || timeout != time.Minute {
+		t.Errorf("Test failed - unexpected request %+v %v", request, timeout)
+	}
+
+	request, _, err = parseCancelAllOrders([]string{"-yes"})
+	if err != nil || request.Exchange != "" || !request.Confirm {
+		t.Errorf("Test failed - unexpected confirmed request %+v %v", request, err)
+	}
+
+	for _, args := range [][]string{
+		{"Bitstamp", "OKCoin"},
+		{"-timeout", "soon"},
+		{"-timeout", "-1s"},
+	} {
+		_, _, err = parseCancelAllOrders(args)
+		if err == nil {
+			t.Errorf("Test failed - parseCancelAllOrders(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseExchangeEndpoint(t *testing.T) {
+	request, err := parseExchangeEndpoint([]string{"-api", "https://testnet.bitmex.com/api/v1",
+		"-websocket", "wss://testnet.bitmex.com/realtime"})
+	if err != nil {
+		t.Fatalf("Test failed - parseExchangeEndpoint() error: %s", err)
+	}
+	if request.APIURL != "https://testnet.bitmex.com/api/v1" || request.APIURLSecondary != "" ||
+		request.WebsocketURL != "wss://testnet.bitmex.com/realtime" {
+		t.Errorf("Test failed - unexpected request %+v", request)
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"-api"},
+		{"-api", "https://api.test", "extra"},
+		{"-rest", "https://api.test"},
+	} {
+		_, err = parseExchangeEndpoint(args)
+		if err == nil {
+			t.Errorf("Test failed - parseExchangeEndpoint(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseBestExecutionVenue(t *testing.T) {
+	path, checkBalance, err := parseBestExecutionVenue([]string{"BTC_USD", "buy", "1.5"})
+	if err != nil {
+		t.Fatalf("Test failed - parseBestExecutionVenue() error: %s", err)
+	}
+	if expected := "/bestexecution/BTC_USD?amount=1.5&side=buy"; path != expected || checkBalance {
+		t.Errorf("Test failed - expected %s, got %s %v", expected, path, checkBalance)
+	}
+
+	path, checkBalance, err = parseBestExecutionVenue([]string{"BTC_USD", "sell", "2",
+		"-asset", "SPOT", "-checkbalance"})
+	if err != nil {
+		t.Fatalf("Test failed - parseBestExecutionVenue() error: %s", err)
+	}
+	if expected := "/bestexecution/BTC_USD?amount=2&assetType=SPOT&checkBalance=true&side=sell"; path != expected || !checkBalance {
+		t.Errorf("Test failed - expected %s, got %s %v", expected, path, checkBalance)
+	}
+
+	for _, args := range [][]string{
+		{"BTC_USD", "buy"},
+		{"BTC_USD", "buy", "one"},
+		{"BTC_USD", "buy", "1", "-exchange", "OKEX"},
+	} {
+		_, _, err = parseBestExecutionVenue(args)
+		if err == nil {
+			t.Errorf("Test failed - parseBestExecutionVenue(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseConsolidatedOrderbook(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"BTC_USD"}, "/orderbook/consolidated/BTC_USD"},
+		{[]string{"BTC_USD", "-asset", "SPOT", "-depth", "10"},
+			"/orderbook/consolidated/BTC_USD?assetType=SPOT&depth=10"},
+	} {
+		path, err := parseConsolidatedOrderbook(test.args)
+		if err != nil {
+			t.Fatalf("Test failed - parseConsolidatedOrderbook() error: %s", err)
+		}
+		if path != test.expected {
+			t.Errorf("Test failed - expected %s, got %s", test.expected, path)
+		}
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"BTC_USD", "ETH_USD"},
+		{"BTC_USD", "-depth", "-1"},
+		{"BTC_USD", "-depth", "ten"},
+		{"BTC_USD", "-exchange", "OKEX"},
+	} {
+		_, err := parseConsolidatedOrderbook(args)
+		if err == nil {
+			t.Errorf("Test failed - parseConsolidatedOrderbook(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseConvert(t *testing.T) {
+	path, err := parseConvert([]string{"1234.56", "AUD", "USD"})
+	if err != nil {
+		t.Fatalf("Test failed - parseConvert() error: %s", err)
+	}
+	if expected := "/forex/convert?amount=1234.56&from=AUD&to=USD"; path != expected {
+		t.Errorf("Test failed - expected %s, got %s", expected, path)
+	}
+
+	for _, args := range [][]string{
+		{"1234.56", "AUD"},
+		{"lots", "AUD", "USD"},
+	} {
+		_, err = parseConvert(args)
+		if err == nil {
+			t.Errorf("Test failed - parseConvert(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseSimulateOrder(t *testing.T) {
+	path, err := parseSimulateOrder([]string{"BTC_USD", "buy", "1.5"})
+	if err != nil {
+		t.Fatalf("Test failed - parseSimulateOrder() error: %s", err)
+	}
+	if expected := "/simulate/BTC_USD?amount=1.5&side=buy"; path != expected {
+		t.Errorf("Test failed - expected %s, got %s", expected, path)
+	}
+
+	path, err = parseSimulateOrder([]string{"BTC_USD", "sell", "2", "-exchange", "OKEX",
+		"-asset", "SPOT", "-refresh"})
+	if err != nil {
+		t.Fatalf("Test failed - parseSimulateOrder() error: %s", err)
+	}
+	if expected := "/simulate/BTC_USD?amount=2&assetType=SPOT&exchange=OKEX&refresh=true&side=sell"; path != expected {
+		t.Errorf("Test failed - expected %s, got %s", expected, path)
+	}
+
+	for _, args := range [][]string{
+		{"BTC_USD", "buy"},
+		{"BTC_USD", "buy", "one"},
+		{"BTC_USD", "buy", "1", "-blah"},
+	} {
+		_, err = parseSimulateOrder(args)
+		if err == nil {
+			t.Errorf("Test failed - parseSimulateOrder(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseDepositHistory(t *testing.T) {
+	path, err := parseDepositHistory([]string{"Bitmex", "BTC"})
+	if err != nil {
+		t.Fatalf("Test failed - parseDepositHistory() error: %s", err)
+	}
+	if expected := "/exchanges/Bitmex/deposits?currency=BTC"; path != expected {
+		t.Errorf("Test failed - expected %s, got %s", expected, path)
+	}
+
+	path, err = parseDepositHistory([]string{"Bitmex", "BTC", "-start", "2018-10-01",
+		"-end", "2018-10-06T12:00:00Z"})
+	if err != nil {
+		t.Fatalf("Test failed - parseDepositHistory() error: %s", err)
+	}
+	if expected := "/exchanges/Bitmex/deposits?currency=BTC&end=2018-10-06T12%3A00%3A00Z&start=2018-10-01T00%3A00%3A00Z"; path != expected {
+		t.Errorf("Test failed - expected %s, got %s", expected, path)
+	}
+
+	for _, args := range [][]string{
+		{"Bitmex"},
+		{"Bitmex", "BTC", "ETH"},
+		{"Bitmex", "BTC", "-start", "yesterday"},
+		{"Bitmex", "BTC", "-blah"},
+	} {
+		_, err = parseDepositHistory(args)
+		if err == nil {
+			t.Errorf("Test failed - parseDepositHistory(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseGetFills(t *testing.T) {
+	for args, expected := range map[string]string{
+		"":                                      "/fills",
+		"Bitmex":                                "/fills?exchange=Bitmex",
+		"Bitmex -pair XBTUSD -start 2018-10-01": "/fills?exchange=Bitmex&pair=XBTUSD&start=2018-10-01T00%3A00%3A00Z",
+		"-end 2018-10-06T12:00:00Z":             "/fills?end=2018-10-06T12%3A00%3A00Z",
+	} {
+		path, err := parseGetFills(strings.Fields(args))
+		if err != nil {
+			t.Fatalf("Test failed - parseGetFills(%s) error: %s", args, err)
+		}
+		if path != expected {
+			t.Errorf("Test failed - expected %s, got %s", expected, path)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"Bitmex", "Bitstamp"},
+		{"-start", "yesterday"},
+		{"-blah"},
+	} {
+		_, err := parseGetFills(args)
+		if err == nil {
+			t.Errorf("Test failed - parseGetFills(%v) expected an error", args)
+		}
+	}
+}
+
+func TestPromptConfigEncryptionKeys(t *testing.T) {
+	request, err := promptConfigEncryptionKeys(strings.NewReader("old\nnew\r\nnew\n"))
+	if err != nil {
+		t.Fatalf("Test failed - promptConfigEncryptionKeys() error: %s", err)
+	}
+	if request.OldKey != "old" || request.NewKey != "new" {
+		t.Errorf("Test failed - unexpected request %+v", request)
+	}
+
+	_, err = promptConfigEncryptionKeys(strings.NewReader("old\nnew\nother\n"))
+	if err == nil {
+		t.Error("Test failed - expected an error for mismatched new keys")
+	}
+	_, err = promptConfigEncryptionKeys(strings.NewReader("old\n\n\n"))
+	if err == nil {
+		t.Error("Test failed - expected an error for an empty new key")
+	}
+}
+
+func TestParseConfigSectionPayload(t *testing.T) {
+	payload, err := parseConfigSectionPayload([]string{`{"enabled":`, `true}`})
+	if err != nil {
+		t.Fatalf("Test failed - parseConfigSectionPayload() error: %s", err)
+	}
+	if string(payload) != `{"enabled": true}` {
+		t.Errorf("Test failed - unexpected payload %s", payload)
+	}
+
+	_, err = parseConfigSectionPayload([]string{"20s"})
+	if err == nil {
+		t.Error("Test failed - expected an error for an invalid JSON value")
+	}
+}
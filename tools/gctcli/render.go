@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// ANSI escape codes used when rendering to a terminal
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	// ansiCursorUp moves the cursor up the formatted number of lines
+	ansiCursorUp = "\033[%dA"
+	// ansiClearDown clears the terminal from the cursor to the end of the
+	// screen
+	ansiClearDown = "\033[J"
+)
+
+// isTerminal returns whether the file is a terminal, output redirected to a
+// file or pipe is rendered without escape codes
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// liveView renders a block of lines which is redrawn in place on a terminal,
+// otherwise every render is appended to the output
+type liveView struct {
+	out   io.Writer
+	tty   bool
+	drawn int
+}
+
+// newLiveView returns a view rendering to the file, redrawing in place if it
+// is a terminal
+func newLiveView(f *os.File) *liveView {
+	return &liveView{out: f, tty: isTerminal(f)}
+}
+
+// Render draws the lines over the previously rendered block on a terminal,
+// otherwise they are appended
+func (v *liveView) Render(lines []string) {
+	if v.tty && v.drawn > 0 {
+		fmt.Fprintf(v.out, ansiCursorUp, v.drawn)
+		fmt.Fprint(v.out, ansiClearDown)
+	}
+	for x := range lines {
+		fmt.Fprintln(v.out, lines[x])
+	}
+	v.drawn = len(lines)
+}
+
+// Println prints a message below the rendered block, the next render starts
+// a new block below the message
+func (v *liveView) Println(message string) {
+	fmt.Fprintln(v.out, message)
+	v.drawn = 0
+}
+
+// colorize colors the text green for a positive change and red for a
+// negative change
+func colorize(text string, change float64) string {
+	switch {
+	case change > 0:
+		return ansiGreen + text + ansiReset
+	case change < 0:
+		return ansiRed + text + ansiReset
+	}
+	return text
+}
+
+// formatNumber formats a price or amount without trailing zeros
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
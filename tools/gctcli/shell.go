@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+const (
+	shellPrompt = "gctcli> "
+	// minWatchInterval is the shortest interval a command can be watched at
+	minWatchInterval = time.Second
+	// clearScreen moves the cursor home and clears the terminal
+	clearScreen = "\033[H\033[2J"
+)
+
+// shell is an interactive gctcli session. Every command run in the shell
+// shares one webserver connection
+type shell struct {
+	host      string
+	lines     <-chan string
+	out       io.Writer
+	interrupt <-chan os.Signal
+	history   []string
+}
+
+// lineReader reads whole lines from the shell input, letting confirmation
+// prompts read from the same input as the shell
+type lineReader struct {
+	lines <-chan string
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	line, ok := <-l.lines
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(p, line+"\n"), nil
+}
+
+// readLines sends each line of in to the returned channel, closing it once in
+// is exhausted
+func readLines(in io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// runShell runs an interactive shell on the supplied input until it is exited,
+// its input ends or it is interrupted at the prompt. The webserver connection
+// is closed on exit
+func runShell(host string, in io.Reader, out io.Writer) error {
+	if sharedClient != nil {
+		return errors.New("already running a shell")
+	}
+
+	client, err := newHTTPClient(0)
+	if err != nil {
+		return err
+	}
+	sharedClient = client
+	lines := readLines(in)
+	stdin = &lineReader{lines: lines}
+	defer func() {
+		client.CloseIdleConnections()
+		sharedClient = nil
+		stdin = os.Stdin
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	s := &shell{
+		host:      host,
+		lines:     lines,
+		out:       out,
+		interrupt: interrupt,
+	}
+	fmt.Fprintf(out, "Connected to %s, type help for commands or exit to quit.\n",
+		host)
+	s.run()
+	return nil
+}
+
+// run reads and runs lines until the shell is exited
+func (s *shell) run() {
+	for {
+		fmt.Fprint(s.out, shellPrompt)
+		var line string
+		var ok bool
+		select {
+		case line, ok = <-s.lines:
+			if !ok {
+				fmt.Fprintln(s.out)
+				return
+			}
+		case <-s.interrupt:
+			fmt.Fprintln(s.out)
+			return
+		}
+
+		if s.runLine(line) {
+			return
+		}
+		s.drainInterrupts()
+	}
+}
+
+// runLine runs a shell line and returns whether the shell should exit
+func (s *shell) runLine(line string) bool {
+	line, err := expandHistory(line, s.history)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %s\n", err)
+		return false
+	}
+
+	args, err := splitShellLine(line)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %s\n", err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+	s.history = append(s.history, line)
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		fmt.Fprintln(s.out, "Commands:")
+		printCommands(s.out)
+		fmt.Fprintf(s.out, "\nShell commands:\n")
+		fmt.Fprintf(s.out, "  %-18s %s\n", "watch", "reruns a read only command on an interval until interrupted")
+		fmt.Fprintf(s.out, "  %-18s arguments: %s\n", "", "<command> [arguments] <interval>")
+		fmt.Fprintf(s.out, "  %-18s %s\n", "history", "lists the previous commands, rerun them with !! or !<number>")
+		fmt.Fprintf(s.out, "  %-18s %s\n", "exit, quit", "closes the connection and exits the shell")
+		return false
+	case "history":
+		for x := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", x+1, s.history[x])
+		}
+		return false
+	case "watch":
+		cmd, cmdArgs, interval, err := parseWatch(args[1:])
+		if err != nil {
+			fmt.Fprintf(s.out, "Error: %s\n", err)
+			return false
+		}
+		s.watch(cmd, cmdArgs, interval)
+		return false
+	}
+
+	cmd, cmdArgs, err := parseShellCommand(args)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %s\n", err)
+		return false
+	}
+	s.runCommand(cmd, cmdArgs)
+	return false
+}
+
+// runCommand runs a command, reporting its error without exiting the shell
+func (s *shell) runCommand(cmd command, args []string) {
+	err := cmd.Action(s.host, args)
+	if err == nil {
+		return
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+		fmt.Fprintln(s.out, "Authentication failed, restart the shell with the correct -username and -password.")
+		return
+	}
+	fmt.Fprintf(s.out, "Error: %s\n", err)
+}
+
+// watch reruns a command on the interval, redrawing its output, until the
+// shell is interrupted
+func (s *shell) watch(cmd command, args []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	title := common.JoinStrings(append([]string{cmd.Name}, args...), " ")
+	for {
+		fmt.Fprint(s.out, clearScreen)
+		fmt.Fprintf(s.out, "Every %v: %s (Ctrl-C to stop)    %s\n\n", interval,
+			title, time.Now().Format(time.RFC1123))
+		s.runCommand(cmd, args)
+
+		select {
+		case <-s.interrupt:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drainInterrupts discards interrupts received while a command ran, so they
+// don't exit the shell at the next prompt
+func (s *shell) drainInterrupts() {
+	for {
+		select {
+		case <-s.interrupt:
+		default:
+			return
+		}
+	}
+}
+
+// splitShellLine splits a shell line into its arguments. Arguments are
+// separated by whitespace and can be quoted with single or double quotes
+func splitShellLine(line string) ([]string, error) {
+	var args []string
+	var arg []rune
+	var quote rune
+	inArg := false
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			arg = append(arg, r)
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, string(arg))
+				arg = arg[:0]
+				inArg = false
+			}
+		default:
+			arg = append(arg, r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, string(arg))
+	}
+	return args, nil
+}
+
+// expandHistory replaces a line of !! with the previous line and !<number>
+// with that line of the history
+func expandHistory(line string, history []string) (string, error) {
+	trimmed := common.TrimString(line, " \t")
+	if trimmed == "" || trimmed[0] != '!' {
+		return line, nil
+	}
+
+	if trimmed == "!!" {
+		if len(history) == 0 {
+			return "", errors.New("no previous command")
+		}
+		return history[len(history)-1], nil
+	}
+
+	n, err := strconv.Atoi(trimmed[1:])
+	if err != nil || n < 1 || n > len(history) {
+		return "", fmt.Errorf("%s: event not found", trimmed)
+	}
+	return history[n-1], nil
+}
+
+// parseShellCommand returns the command of a shell line and its arguments
+func parseShellCommand(args []string) (command, []string, error) {
+	if args[0] == "shell" {
+		return command{}, nil, errors.New("already running a shell")
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		return command{}, nil, fmt.Errorf("unknown command %q, type help for commands", args[0])
+	}
+	if len(args)-1 < cmd.MinArgs {
+		return command{}, nil, fmt.Errorf("usage: %s %s", cmd.Name, cmd.Usage)
+	}
+	return cmd, args[1:], nil
+}
+
+// parseWatch parses the watch arguments, a read only command with its
+// arguments followed by the interval to rerun it at. The interval is a
+// duration such as 5s or a number of seconds
+func parseWatch(args []string) (command, []string, time.Duration, error) {
+	if len(args) < 2 {
+		return command{}, nil, 0, errors.New("usage: watch <command> [arguments] <interval>")
+	}
+
+	last := args[len(args)-1]
+	interval, err := time.ParseDuration(last)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(last)
+		if convErr != nil {
+			return command{}, nil, 0, fmt.Errorf("invalid watch interval %q", last)
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+	if interval < minWatchInterval {
+		return command{}, nil, 0, fmt.Errorf("watch interval %v is below the minimum of %v",
+			interval, minWatchInterval)
+	}
+
+	cmd, cmdArgs, err := parseShellCommand(args[:len(args)-1])
+	if err != nil {
+		return command{}, nil, 0, err
+	}
+	if cmd.Mutating {
+		return command{}, nil, 0, fmt.Errorf("%s changes the daemon state and can't be watched", cmd.Name)
+	}
+	return cmd, cmdArgs, interval, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSplitShellLine(t *testing.T) {
+	tests := map[string][]string{
+		"":                                 nil,
+		"   ":                              nil,
+		"getinfo":                          {"getinfo"},
+		"  getticker\tBitstamp  BTCUSD  ":  {"getticker", "Bitstamp", "BTCUSD"},
+		`getaccountinfo Bitfinex "my sub"`: {"getaccountinfo", "Bitfinex", "my sub"},
+		`a 'b "c"' d`:                      {"a", `b "c"`, "d"},
+		`a ""`:                             {"a", ""},
+		`a b"c d"e`:                        {"a", "bc de"},
+	}
+	for line, expected := range tests {
+		args, err := splitShellLine(line)
+		if err != nil {
+			t.Errorf("Test failed - splitShellLine(%q) error: %s", line, err)
+			continue
+		}
+		if fmt.Sprintf("%q", args) != fmt.Sprintf("%q", expected) {
+			t.Errorf("Test failed - splitShellLine(%q) expected %q got %q", line,
+				expected, args)
+		}
+	}
+
+	_, err := splitShellLine(`getaccountinfo Bitfinex "my sub`)
+	if err == nil {
+		t.Error("Test failed - splitShellLine() accepted an unterminated quote")
+	}
+}
+
+func TestExpandHistory(t *testing.T) {
+	history := []string{"getinfo", "getticker Bitstamp BTCUSD"}
+	tests := map[string]string{
+		"getexchanges": "getexchanges",
+		"!!":           "getticker Bitstamp BTCUSD",
+		" !1 ":         "getinfo",
+		"!2":           "getticker Bitstamp BTCUSD",
+	}
+	for line, expected := range tests {
+		result, err := expandHistory(line, history)
+		if err != nil || result != expected {
+			t.Errorf("Test failed - expandHistory(%q) expected %q got %q, error: %v",
+				line, expected, result, err)
+		}
+	}
+
+	for _, line := range []string{"!0", "!3", "!x"} {
+		_, err := expandHistory(line, history)
+		if err == nil {
+			t.Errorf("Test failed - expandHistory(%q) accepted a missing event", line)
+		}
+	}
+	_, err := expandHistory("!!", nil)
+	if err == nil {
+		t.Error("Test failed - expandHistory() accepted !! without history")
+	}
+}
+
+func TestParseWatch(t *testing.T) {
+	cmd, args, interval, err := parseWatch([]string{"t", "Bitstamp", "BTCUSD", "5s"})
+	if err != nil {
+		t.Fatalf("Test failed - parseWatch() error: %s", err)
+	}
+	if cmd.Name != "getticker" || strings.Join(args, " ") != "Bitstamp BTCUSD" ||
+		interval != time.Second*5 {
+		t.Errorf("Test failed - unexpected watch %s %v %v", cmd.Name, args, interval)
+	}
+
+	_, _, interval, err = parseWatch([]string{"getinfo", "10"})
+	if err != nil || interval != time.Second*10 {
+		t.Errorf("Test failed - parseWatch() seconds interval %v error: %v", interval, err)
+	}
+
+	invalid := [][]string{
+		{"getinfo"},
+		{"getinfo", "soon"},
+		{"getinfo", "100ms"},
+		{"getticker", "Bitstamp", "5s"},
+		{"rawr", "5s"},
+		{"shutdown", "5s"},
+		{"enablewebsocket", "Bitstamp", "5s"},
+		{"shell", "5s"},
+	}
+	for x := range invalid {
+		_, _, _, err = parseWatch(invalid[x])
+		if err == nil {
+			t.Errorf("Test failed - parseWatch(%v) accepted invalid arguments", invalid[x])
+		}
+	}
+}
+
+func TestRunShell(t *testing.T) {
+	var connections int
+	var m sync.Mutex
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/shutdown" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"version":"test"}`)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			m.Lock()
+			connections++
+			m.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	// The shutdown confirmation is read from the shell input
+	input := strings.Join([]string{
+		"getinfo",
+		"!!",
+		"getticker Bitstamp",
+		"shutdown",
+		"y",
+		"history",
+		"exit",
+		"getinfo",
+	}, "\n")
+
+	var out bytes.Buffer
+	err := runShell(server.Listener.Addr().String(), strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatalf("Test failed - runShell() error: %s", err)
+	}
+
+	m.Lock()
+	if connections != 1 {
+		t.Errorf("Test failed - expected a single connection, got %d", connections)
+	}
+	m.Unlock()
+	if sharedClient != nil {
+		t.Error("Test failed - shared client not released on exit")
+	}
+
+	output := out.String()
+	for _, expected := range []string{
+		"usage: getticker",
+		"Authentication failed",
+		"    2  getinfo\n",
+		"    4  shutdown\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Test failed - shell output missing %q:\n%s", expected, output)
+		}
+	}
+	if strings.Count(output, shellPrompt) != 6 {
+		t.Errorf("Test failed - expected the shell to exit at the exit command:\n%s", output)
+	}
+}
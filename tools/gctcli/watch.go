@@ -0,0 +1,558 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/common"
+)
+
+// Webserver websocket broadcast events streamed by the watch commands
+const (
+	streamEventTicker    = "ticker_update"
+	streamEventOrderbook = "orderbook_update"
+)
+
+// defaultWatchDepth is the number of orderbook levels shown per side
+const defaultWatchDepth = 10
+
+var (
+	// streamReconnectDelay is the delay before reconnecting a dropped stream,
+	// doubling after every failed attempt up to streamMaxReconnectDelay
+	streamReconnectDelay    = time.Second
+	streamMaxReconnectDelay = time.Second * 30
+
+	// errStreamRejected is returned when the webserver rejects a stream
+	// subscription, such subscriptions aren't retried
+	errStreamRejected = errors.New("stream subscription rejected")
+)
+
+// streamSubscription is the webserver websocket subscription request
+type streamSubscription struct {
+	Events    []string `json:"events"`
+	Exchanges []string `json:"exchanges"`
+	Pairs     []string `json:"pairs"`
+}
+
+// streamMessage is a webserver websocket response or broadcast event
+type streamMessage struct {
+	Exchange  string          `json:"exchange"`
+	AssetType string          `json:"assetType"`
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data"`
+	Error     string          `json:"error"`
+}
+
+// tickerPrice is the ticker broadcast by the webserver
+type tickerPrice struct {
+	CurrencyPair  string    `json:"CurrencyPair"`
+	LastUpdated   time.Time `json:"LastUpdated"`
+	Last          float64   `json:"Last"`
+	Bid           float64   `json:"Bid"`
+	Ask           float64   `json:"Ask"`
+	Volume        float64   `json:"Volume"`
+	ChangePercent float64   `json:"ChangePercent"`
+}
+
+// orderbookLevel is an orderbook price level
+type orderbookLevel struct {
+	Amount float64 `json:"Amount"`
+	Price  float64 `json:"Price"`
+}
+
+// orderbookData is the orderbook broadcast by the webserver
+type orderbookData struct {
+	CurrencyPair string           `json:"CurrencyPair"`
+	Bids         []orderbookLevel `json:"bids"`
+	Asks         []orderbookLevel `json:"asks"`
+	LastUpdated  time.Time        `json:"last_updated"`
+}
+
+// watchRequest holds the parsed arguments of a watch command
+type watchRequest struct {
+	Exchange  string
+	Pairs     []string
+	AssetType string
+	Depth     int
+}
+
+// parseWatchStream parses the exchange and currency arguments of a watch
+// command followed by its optional flags. Orderbooks are watched for a single
+// currency and accept a -depth
+func parseWatchStream(name string, args []string, orderbook bool) (watchRequest, error) {
+	positional := args
+	var flags []string
+	for x := range args {
+		if len(args[x]) > 1 && args[x][0] == '-' {
+			positional, flags = args[:x], args[x:]
+			break
+		}
+	}
+
+	request := watchRequest{Depth: defaultWatchDepth}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&request.AssetType, "asset", "", "asset type, defaults to every asset type")
+	if orderbook {
+		fs.IntVar(&request.Depth, "depth", defaultWatchDepth, "number of levels shown per side")
+	}
+	err := fs.Parse(flags)
+	if err != nil {
+		return request, err
+	}
+	if fs.NArg() > 0 {
+		return request, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	switch {
+	case orderbook && len(positional) != 2:
+		return request, errors.New("expected <exchange> <currency>")
+	case len(positional) < 2:
+		return request, errors.New("expected <exchange> <currency> [currency...]")
+	case request.Depth <= 0:
+		return request, errors.New("depth must be greater than zero")
+	}
+	request.Exchange = positional[0]
+	request.Pairs = positional[1:]
+	return request, nil
+}
+
+// streamSymbol returns the pair without delimiters in upper case, matching
+// pairs regardless of their display format
+func streamSymbol(p string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, p)
+}
+
+// watchedTicker is a row of the ticker board
+type watchedTicker struct {
+	symbol   string
+	pair     string
+	exchange string
+	price    tickerPrice
+	previous float64
+	updated  bool
+}
+
+// tickerBoard holds the latest ticker of every watched pair in display order
+type tickerBoard struct {
+	exchange string
+	rows     []watchedTicker
+}
+
+// newTickerBoard returns a board with a row waiting for every pair
+func newTickerBoard(exchange string, pairs []string) *tickerBoard {
+	b := &tickerBoard{exchange: exchange}
+	for x := range pairs {
+		b.rows = append(b.rows, watchedTicker{
+			symbol:   streamSymbol(pairs[x]),
+			pair:     pairs[x],
+			exchange: exchange,
+		})
+	}
+	return b
+}
+
+// update stores the ticker and returns the index of its row
+func (b *tickerBoard) update(exchange string, price tickerPrice) int {
+	symbol := streamSymbol(price.CurrencyPair)
+	index := -1
+	for x := range b.rows {
+		if b.rows[x].symbol == symbol {
+			index = x
+			break
+		}
+	}
+	if index == -1 {
+		b.rows = append(b.rows, watchedTicker{symbol: symbol})
+		index = len(b.rows) - 1
+	}
+
+	row := &b.rows[index]
+	if row.updated {
+		row.previous = row.price.Last
+	}
+	row.exchange = exchange
+	row.pair = price.CurrencyPair
+	row.price = price
+	row.updated = true
+	return index
+}
+
+// line formats the row at the index
+func (b *tickerBoard) line(index int, color bool) string {
+	return formatTickerLine(b.rows[index], color)
+}
+
+// lines formats every row of the board
+func (b *tickerBoard) lines(color bool) []string {
+	result := make([]string, len(b.rows))
+	for x := range b.rows {
+		result[x] = b.line(x, color)
+	}
+	return result
+}
+
+// formatTickerLine formats a ticker on a single line, marking whether the last
+// price moved up or down since the previous update
+func formatTickerLine(t watchedTicker, color bool) string {
+	prefix := fmt.Sprintf("%-12s %-12s", t.exchange, t.pair)
+	if !t.updated {
+		return prefix + " waiting for ticker..."
+	}
+
+	var change float64
+	movement := " "
+	if t.previous != 0 {
+		change = t.price.Last - t.previous
+		switch {
+		case change > 0:
+			movement = "+"
+		case change < 0:
+			movement = "-"
+		}
+	}
+	last := fmt.Sprintf("%s %-14s", movement, formatNumber(t.price.Last))
+	if color {
+		last = colorize(last, change)
+	}
+
+	line := fmt.Sprintf("%s %s bid %-14s ask %-14s vol %-16s 24h %+.2f%%", prefix,
+		last, formatNumber(t.price.Bid), formatNumber(t.price.Ask),
+		formatNumber(t.price.Volume), t.price.ChangePercent)
+	if !t.price.LastUpdated.IsZero() {
+		line += " " + t.price.LastUpdated.UTC().Format("15:04:05")
+	}
+	return line
+}
+
+// formatOrderbookLadder formats the best depth levels of each side of the
+// orderbook as a ladder, asks above bids, with the spread between them and the
+// cumulative amount of every level
+func formatOrderbookLadder(exchange string, ob orderbookData, depth int, color bool) []string {
+	asks := append([]orderbookLevel(nil), ob.Asks...)
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	bids := append([]orderbookLevel(nil), ob.Bids...)
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	if len(asks) > depth {
+		asks = asks[:depth]
+	}
+	if len(bids) > depth {
+		bids = bids[:depth]
+	}
+
+	title := fmt.Sprintf("%s %s orderbook", exchange, ob.CurrencyPair)
+	if !ob.LastUpdated.IsZero() {
+		title += " " + ob.LastUpdated.UTC().Format("15:04:05")
+	}
+	lines := []string{title,
+		fmt.Sprintf("     %-16s %-16s %-16s", "PRICE", "AMOUNT", "TOTAL")}
+
+	level := func(side string, l orderbookLevel, total float64, change float64) string {
+		line := fmt.Sprintf("%s  %-16s %-16s %-16s", side, formatNumber(l.Price),
+			formatNumber(l.Amount), formatNumber(total))
+		if color {
+			line = colorize(line, change)
+		}
+		return line
+	}
+
+	// Asks are listed from the highest shown price down to the best ask, their
+	// totals accumulating towards the best ask
+	askTotals := make([]float64, len(asks))
+	var askTotal float64
+	for x := range asks {
+		askTotal += asks[x].Amount
+		askTotals[x] = askTotal
+	}
+	for x := len(asks) - 1; x >= 0; x-- {
+		lines = append(lines, level("ASK", asks[x], askTotals[x], -1))
+	}
+
+	spread := "spread n/a"
+	if len(asks) > 0 && len(bids) > 0 {
+		diff := asks[0].Price - bids[0].Price
+		mid := (asks[0].Price + bids[0].Price) / 2
+		spread = fmt.Sprintf("spread %s (%.4f%%)", formatNumber(diff), diff/mid*100)
+	}
+	lines = append(lines, "---- "+spread+" ----")
+
+	var bidTotal float64
+	for x := range bids {
+		bidTotal += bids[x].Amount
+		lines = append(lines, level("BID", bids[x], bidTotal, 1))
+	}
+	return append(lines, fmt.Sprintf("Totals: bids %s, asks %s",
+		formatNumber(bidTotal), formatNumber(askTotal)))
+}
+
+// websocketURL returns the webserver websocket URL for the host
+func websocketURL(host string) string {
+	if useTLS {
+		return "wss://" + host + "/ws"
+	}
+	return "ws://" + host + "/ws"
+}
+
+// streamRequest sends a request over the websocket and waits for its
+// response, skipping broadcasts received in the meantime
+func streamRequest(conn *websocket.Conn, event string, data interface{}) (streamMessage, error) {
+	var response streamMessage
+	err := conn.WriteJSON(map[string]interface{}{"event": event, "data": data})
+	if err != nil {
+		return response, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(requestTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return response, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(message))
+		for decoder.More() {
+			err = decoder.Decode(&response)
+			if err != nil {
+				return response, err
+			}
+			if common.StringToLower(response.Event) == event {
+				return response, nil
+			}
+		}
+	}
+}
+
+// dialStream connects to the webserver websocket, authenticates with the
+// admin credentials and subscribes to the events
+func dialStream(host string, sub streamSubscription) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: requestTimeout,
+	}
+	if useTLS {
+		tlsConfig, err := newTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialer.TLSClientConfig = tlsConfig
+	}
+
+	conn, _, err := dialer.Dial(websocketURL(host), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The websocket password is the SHA256 hash of the admin password
+	response, err := streamRequest(conn, "auth", map[string]string{
+		"username": username,
+		"password": common.HexEncodeToString(common.GetSHA256([]byte(password))),
+	})
+	if err == nil && response.Error != "" {
+		err = &statusError{Path: "/ws", StatusCode: http.StatusUnauthorized,
+			Body: []byte(response.Error)}
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	response, err = streamRequest(conn, "subscribe", sub)
+	if err == nil && response.Error != "" {
+		err = fmt.Errorf("%w: %s", errStreamRejected, response.Error)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// readStream passes every event received over the connection to handle until
+// the connection fails or an interrupt is received, which returns nil
+func readStream(conn *websocket.Conn, interrupt <-chan os.Signal, handle func(streamMessage)) error {
+	defer conn.Close()
+
+	messages := make(chan streamMessage)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				errs <- err
+				return
+			}
+			// The webserver batches queued events into a single message
+			decoder := json.NewDecoder(bytes.NewReader(message))
+			for decoder.More() {
+				var msg streamMessage
+				err = decoder.Decode(&msg)
+				if err != nil {
+					errs <- err
+					return
+				}
+				select {
+				case messages <- msg:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-interrupt:
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			return nil
+		case err := <-errs:
+			return err
+		case msg := <-messages:
+			handle(msg)
+		}
+	}
+}
+
+// streamEvents passes the subscribed events streamed by the webserver to
+// handle until interrupted. Dropped connections, such as when the daemon
+// restarts, are reconnected with an increasing delay and reported to status
+func streamEvents(host string, sub streamSubscription, interrupt <-chan os.Signal, status func(string), handle func(streamMessage)) error {
+	delay := streamReconnectDelay
+	for {
+		conn, err := dialStream(host, sub)
+		if err != nil {
+			var statusErr *statusError
+			if errors.As(err, &statusErr) || errors.Is(err, errStreamRejected) {
+				return err
+			}
+			status(fmt.Sprintf("Stream connection failed: %s, reconnecting in %v", err, delay))
+		} else {
+			delay = streamReconnectDelay
+			err = readStream(conn, interrupt, handle)
+			if err == nil {
+				return nil
+			}
+			status(fmt.Sprintf("Stream disconnected: %s, reconnecting in %v", err, delay))
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > streamMaxReconnectDelay {
+			delay = streamMaxReconnectDelay
+		}
+	}
+}
+
+// matchesStream returns whether a streamed message is the event for the
+// watched asset type
+func matchesStream(msg streamMessage, event string, request watchRequest) bool {
+	return msg.Event == event && (request.AssetType == "" ||
+		common.StringToUpper(msg.AssetType) == common.StringToUpper(request.AssetType))
+}
+
+// watchTicker streams the tickers of exchange currency pairs, redrawing them
+// in place on a terminal or printing every update otherwise, until interrupted
+func watchTicker(host string, args []string) error {
+	request, err := parseWatchStream("watchticker", args, false)
+	if err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	view := newLiveView(os.Stdout)
+	board := newTickerBoard(request.Exchange, request.Pairs)
+	view.Println(fmt.Sprintf("Watching %s tickers for %s (Ctrl-C to stop)",
+		request.Exchange, common.JoinStrings(request.Pairs, ", ")))
+	if view.tty {
+		view.Render(board.lines(true))
+	}
+
+	sub := streamSubscription{
+		Events:    []string{streamEventTicker},
+		Exchanges: []string{request.Exchange},
+		Pairs:     request.Pairs,
+	}
+	return streamEvents(host, sub, interrupt, view.Println, func(msg streamMessage) {
+		if !matchesStream(msg, streamEventTicker, request) {
+			return
+		}
+		var price tickerPrice
+		err := json.Unmarshal(msg.Data, &price)
+		if err != nil {
+			view.Println(fmt.Sprintf("Invalid ticker: %s", err))
+			return
+		}
+		index := board.update(msg.Exchange, price)
+		if view.tty {
+			view.Render(board.lines(true))
+			return
+		}
+		view.Render([]string{board.line(index, false)})
+	})
+}
+
+// watchOrderbook streams the orderbook of an exchange currency pair, redrawing
+// its ladder in place on a terminal or printing every update otherwise, until
+// interrupted
+func watchOrderbook(host string, args []string) error {
+	request, err := parseWatchStream("watchorderbook", args, true)
+	if err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	view := newLiveView(os.Stdout)
+	view.Println(fmt.Sprintf("Watching %s %s orderbook (Ctrl-C to stop)",
+		request.Exchange, request.Pairs[0]))
+
+	sub := streamSubscription{
+		Events:    []string{streamEventOrderbook},
+		Exchanges: []string{request.Exchange},
+		Pairs:     request.Pairs,
+	}
+	return streamEvents(host, sub, interrupt, view.Println, func(msg streamMessage) {
+		if !matchesStream(msg, streamEventOrderbook, request) {
+			return
+		}
+		var ob orderbookData
+		err := json.Unmarshal(msg.Data, &ob)
+		if err != nil {
+			view.Println(fmt.Sprintf("Invalid orderbook: %s", err))
+			return
+		}
+		lines := formatOrderbookLadder(msg.Exchange, ob, request.Depth, view.tty)
+		if !view.tty {
+			// Separates the appended ladders
+			lines = append(lines, "")
+		}
+		view.Render(lines)
+	})
+}
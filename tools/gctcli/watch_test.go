@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestParseWatchStream(t *testing.T) {
+	request, err := parseWatchStream("watchticker", []string{"Bitmex", "XBTUSD",
+		"ETHUSD", "-asset", "PERPETUAL"}, false)
+	if err != nil {
+		t.Fatalf("Test failed - parseWatchStream() error: %s", err)
+	}
+	if request.Exchange != "Bitmex" || len(request.Pairs) != 2 ||
+		request.AssetType != "PERPETUAL" {
+		t.Errorf("Test failed - unexpected request %+v", request)
+	}
+
+	_, err = parseWatchStream("watchticker", []string{"Bitmex", "XBTUSD", "-depth", "5"}, false)
+	if err == nil {
+		t.Error("Test failed - parseWatchStream() accepted a ticker depth")
+	}
+
+	request, err = parseWatchStream("watchorderbook", []string{"Bitmex", "XBTUSD", "-depth", "5"}, true)
+	if err != nil || request.Depth != 5 {
+		t.Errorf("Test failed - unexpected orderbook request %+v, error %v", request, err)
+	}
+
+	_, err = parseWatchStream("watchorderbook", []string{"Bitmex", "XBTUSD", "ETHUSD"}, true)
+	if err == nil {
+		t.Error("Test failed - parseWatchStream() accepted multiple orderbook pairs")
+	}
+
+	_, err = parseWatchStream("watchorderbook", []string{"Bitmex", "XBTUSD", "-depth", "0"}, true)
+	if err == nil {
+		t.Error("Test failed - parseWatchStream() accepted a zero depth")
+	}
+}
+
+func TestTickerBoard(t *testing.T) {
+	board := newTickerBoard("Bitmex", []string{"XBT-USD", "ETHUSD"})
+	if lines := board.lines(false); len(lines) != 2 ||
+		!strings.Contains(lines[1], "waiting for ticker") {
+		t.Errorf("Test failed - unexpected waiting rows %q", lines)
+	}
+
+	index := board.update("Bitmex", tickerPrice{CurrencyPair: "XBTUSD", Last: 6600.5})
+	if index != 0 || strings.Contains(board.line(index, false), "+ ") {
+		t.Errorf("Test failed - unexpected first update row %d %q", index,
+			board.line(index, false))
+	}
+
+	board.update("Bitmex", tickerPrice{CurrencyPair: "XBTUSD", Last: 6610,
+		LastUpdated: time.Date(2018, 1, 1, 12, 30, 0, 0, time.UTC)})
+	line := board.line(0, false)
+	if !strings.Contains(line, "+ 6610") || !strings.Contains(line, "12:30:00") ||
+		strings.Contains(line, "\033") {
+		t.Errorf("Test failed - unexpected plain ticker line %q", line)
+	}
+	if colored := board.line(0, true); !strings.Contains(colored, ansiGreen) {
+		t.Errorf("Test failed - expected a green ticker line, got %q", colored)
+	}
+
+	board.update("Bitmex", tickerPrice{CurrencyPair: "XBTUSD", Last: 6605})
+	if line = board.line(0, false); !strings.Contains(line, "- 6605") {
+		t.Errorf("Test failed - expected a down move, got %q", line)
+	}
+
+	if index = board.update("Bitmex", tickerPrice{CurrencyPair: "LTCUSD", Last: 1}); index != 2 {
+		t.Errorf("Test failed - expected an unwatched pair to be appended, got row %d", index)
+	}
+}
+
+func TestFormatOrderbookLadder(t *testing.T) {
+	ob := orderbookData{
+		CurrencyPair: "XBTUSD",
+		Asks:         []orderbookLevel{{Price: 102, Amount: 2}, {Price: 101, Amount: 1}, {Price: 103, Amount: 3}},
+		Bids:         []orderbookLevel{{Price: 99, Amount: 4}, {Price: 100, Amount: 5}},
+	}
+	lines := formatOrderbookLadder("Bitmex", ob, 2, false)
+	expected := []string{
+		"Bitmex XBTUSD orderbook",
+		"     PRICE            AMOUNT           TOTAL           ",
+		"ASK  102              2                3               ",
+		"ASK  101              1                1               ",
+		"---- spread 1 (0.9950%) ----",
+		"BID  100              5                5               ",
+		"BID  99               4                9               ",
+		"Totals: bids 9, asks 3",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Test failed - expected %d lines, got %q", len(expected), lines)
+	}
+	for x := range expected {
+		if lines[x] != expected[x] {
+			t.Errorf("Test failed - line %d expected %q, got %q", x, expected[x], lines[x])
+		}
+	}
+
+	lines = formatOrderbookLadder("Bitmex", orderbookData{CurrencyPair: "XBTUSD"}, 2, false)
+	if lines[2] != "---- spread n/a ----" {
+		t.Errorf("Test failed - unexpected empty orderbook %q", lines)
+	}
+}
+
+func TestLiveView(t *testing.T) {
+	var out bytes.Buffer
+	view := &liveView{out: &out}
+	view.Render([]string{"a", "b"})
+	view.Render([]string{"c"})
+	if out.String() != "a\nb\nc\n" {
+		t.Errorf("Test failed - unexpected append-only output %q", out.String())
+	}
+
+	out.Reset()
+	view = &liveView{out: &out, tty: true}
+	view.Render([]string{"a", "b"})
+	view.Render([]string{"c"})
+	if out.String() != "a\nb\n\033[2A\033[Jc\n" {
+		t.Errorf("Test failed - unexpected terminal output %q", out.String())
+	}
+
+	out.Reset()
+	view.Println("reconnecting")
+	view.Render([]string{"d"})
+	if out.String() != "reconnecting\nd\n" {
+		t.Errorf("Test failed - expected a new block after a message, got %q", out.String())
+	}
+}
+
+// streamServer serves the webserver websocket protocol, sending the
+// broadcasts of a connection in a single message
+func streamServer(t *testing.T, authError string, connections [][]string) *httptest.Server {
+	var upgrader websocket.Upgrader
+	var connection int
+	var mtx sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var request streamMessage
+		for _, event := range []string{"auth", "Subscribe"} {
+			err = conn.ReadJSON(&request)
+			if err != nil {
+				return
+			}
+			var errMsg string
+			if event == "auth" {
+				errMsg = authError
+			}
+			conn.WriteJSON(map[string]string{"event": event, "error": errMsg})
+			if errMsg != "" {
+				return
+			}
+		}
+
+		mtx.Lock()
+		broadcasts := connections[connection]
+		connection++
+		last := connection == len(connections)
+		mtx.Unlock()
+		conn.WriteMessage(websocket.TextMessage, []byte(strings.Join(broadcasts, "")))
+		if !last {
+			return
+		}
+		// The last connection stays open until the client closes it
+		conn.ReadMessage()
+	}))
+}
+
+func TestStreamEvents(t *testing.T) {
+	defer func(d time.Duration) { streamReconnectDelay = d }(streamReconnectDelay)
+	streamReconnectDelay = time.Millisecond
+
+	ticker := func(last float64) string {
+		return fmt.Sprintf(`{"exchange":"Bitmex","assetType":"PERPETUAL","Event":"ticker_update","Data":{"CurrencyPair":"XBTUSD","Last":%v}}`, last)
+	}
+	server := streamServer(t, "", [][]string{{ticker(1), ticker(2)}, {ticker(3)}})
+	defer server.Close()
+
+	interrupt := make(chan os.Signal, 1)
+	var received []float64
+	var statuses []string
+	err := streamEvents(server.Listener.Addr().String(), streamSubscription{}, interrupt,
+		func(status string) { statuses = append(statuses, status) },
+		func(msg streamMessage) {
+			var price tickerPrice
+			if err := json.Unmarshal(msg.Data, &price); err != nil {
+				t.Error(err)
+			}
+			received = append(received, price.Last)
+			if len(received) == 3 {
+				interrupt <- os.Interrupt
+			}
+		})
+	if err != nil {
+		t.Fatalf("Test failed - streamEvents() error: %s", err)
+	}
+	if len(received) != 3 || received[2] != 3 {
+		t.Errorf("Test failed - expected 3 tickers over a reconnect, got %v", received)
+	}
+	if len(statuses) != 1 || !strings.Contains(statuses[0], "Stream disconnected") {
+		t.Errorf("Test failed - expected a reconnect status, got %q", statuses)
+	}
+
+	authServer := streamServer(t, "invalid username/password", nil)
+	defer authServer.Close()
+	err = streamEvents(authServer.Listener.Addr().String(), streamSubscription{}, interrupt,
+		func(string) {}, func(streamMessage) {})
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Test failed - expected an authentication error, got %v", err)
+	}
+}
@@ -77,7 +77,7 @@ func main() {
 
 	displayCurrency = cfg.FiatDisplayCurrency
 	port := portfolio.Base{}
-	port.SeedPortfolio(cfg.Portfolio)
+	port.SeedPortfolio(&cfg.Portfolio)
 	result := port.GetPortfolioSummary()
 
 	log.Println("Fetched portfolio data.")
@@ -125,13 +125,13 @@ func main() {
 	log.Println("Saving config..")
 	origBotName := resultCfg.Name
 	resultCfg.Name = "TEST"
-	err = SendWebsocketEvent("SaveConfig", resultCfg, &wsResp)
+	err = SendWebsocketEvent("SaveConfig", &resultCfg, &wsResp)
 	if err != nil {
 		log.Fatal(err)
 	}
 	log.Println("Saved config!")
 	resultCfg.Name = origBotName
-	err = SendWebsocketEvent("SaveConfig", resultCfg, &wsResp)
+	err = SendWebsocketEvent("SaveConfig", &resultCfg, &wsResp)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
+)
+
+// defaultTradeStatsWindow is the window trade activity statistics are
+// computed over when none is requested
+const defaultTradeStatsWindow = time.Minute
+
+// GetRecentTrades returns up to limit of the most recent trades streamed by an
+// exchange for a currency pair and asset type, newest first. A limit below one
+// returns every stored trade
+func GetRecentTrades(exchName string, p pair.CurrencyPair, assetType string, limit int) ([]trades.Trade, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+	return trades.GetRecentTrades(exch.GetName(), p, assetType, limit)
+}
+
+// GetTradeStats returns the trade count, trade rate, volume and buy/sell
+// imbalance of the trades streamed by an exchange for a currency pair and
+// asset type over the window ending now
+func GetTradeStats(exchName string, p pair.CurrencyPair, assetType string, window time.Duration) (trades.Stats, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return trades.Stats{}, ErrExchangeNotFound
+	}
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+	return trades.GetStats(exch.GetName(), p, assetType, window, time.Now())
+}
+
+// parseTradeStatsWindow parses a trade stats window duration such as 30s or
+// 5m, an empty window returns the default window
+func parseTradeStatsWindow(window string) (time.Duration, error) {
+	if window == "" {
+		return defaultTradeStatsWindow, nil
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid trade stats window %q", window)
+	}
+	return d, nil
+}
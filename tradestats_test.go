@@ -0,0 +1,130 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-/gocryptotrader/exchanges/trades"
+)
+
+// setupTradesTest loads a test exchange listing BTC-USD with a buy and a sell trade stored
+// and returns a function restoring the exchanges and trades
+func setupTradesTest(t *testing.T) func() {
+	SetupTestHelpers(t)
+	exchanges := bot.exchanges
+	p := pair.NewCurrencyPairDelimiter("BTC-USD", "-")
+	exch := newTestExchange("TradesTest")
+	exch.enabledPairs = []pair.CurrencyPair{p}
+	exch.availablePairs = exch.enabledPairs
+	bot.exchanges = []exchange.IBotExchange{exch}
+	trades.SetBufferSize(0)
+
+	trades.Add("TradesTest", p, ticker.Spot, trades.Trade{
+		Timestamp: time.Now().Add(-time.Minute * 2), Price: 6400, Amount: 1, Side: "sell"})
+	trades.Add("TradesTest", p, ticker.Spot, trades.Trade{
+		Timestamp: time.Now().Add(-time.Second * 10), Price: 6500, Amount: 3, Side: "buy"})
+	return func() {
+		bot.exchanges = exchanges
+		trades.SetBufferSize(0)
+	}
+}
+
+func TestGetTradeStats(t *testing.T) {
+	defer setupTradesTest(t)()
+	p := pair.NewCurrencyPairFromString("BTCUSD")
+
+	recent, err := GetRecentTrades("tradestest", p, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || recent[0].Price != 6500 || recent[0].Side != trades.Buy {
+		t.Errorf("Test failed. Unexpected recent trades %+v", recent)
+	}
+
+	stats, err := GetTradeStats("tradestest", p, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Exchange != "TradesTest" || stats.AssetType != ticker.Spot ||
+		stats.Trades != 1 || stats.Volume != 3 || stats.Imbalance != 1 {
+		t.Errorf("Test failed. Unexpected trade stats %+v", stats)
+	}
+
+	_, err = GetTradeStats("Missing", p, "", time.Minute)
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrExchangeNotFound, err)
+	}
+	_, err = GetRecentTrades("TradesTest", pair.NewCurrencyPairFromString("LTCUSD"), "", 0)
+	if err != trades.ErrNoTrades {
+		t.Errorf("Test failed. Expected %v, got %v", trades.ErrNoTrades, err)
+	}
+
+	for window, expected := range map[string]time.Duration{
+		"": defaultTradeStatsWindow, "5m": time.Minute * 5, "30s": time.Second * 30,
+	} {
+		d, err := parseTradeStatsWindow(window)
+		if err != nil || d != expected {
+			t.Errorf("Test failed. Window %q expected %v, got %v %v", window, expected,
+				d, err)
+		}
+	}
+	for _, window := range []string{"0s", "-1m", "1x"} {
+		if _, err := parseTradeStatsWindow(window); err == nil {
+			t.Errorf("Test failed. Expected an error for window %q", window)
+		}
+	}
+}
+
+func TestRESTGetTradeStats(t *testing.T) {
+	defer setupTradesTest(t)()
+
+	for _, test := range []struct {
+		path   string
+		status int
+	}{
+		{"/exchanges/TradesTest/trades/recent/BTCUSD?limit=5", http.StatusOK},
+		{"/exchanges/TradesTest/trades/recent/BTCUSD?limit=x", http.StatusBadRequest},
+		{"/exchanges/TradesTest/trades/recent/LTCUSD", http.StatusNotFound},
+		{"/exchanges/Missing/trades/recent/BTCUSD", http.StatusNotFound},
+		{"/exchanges/TradesTest/trades/stats/BTCUSD?window=5m", http.StatusOK},
+		{"/exchanges/TradesTest/trades/stats/BTCUSD?window=0s", http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		NewRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if w.Code != test.status {
+			t.Errorf("Test failed. %s expected status %d, got %d", test.path,
+				test.status, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	NewRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/exchanges/TradesTest/trades/stats/BTCUSD?window=5m", nil))
+	var stats trades.Stats
+	err := common.JSONDecode(w.Body.Bytes(), &stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Window != "5m0s" || stats.Trades != 2 || stats.Volume != 4 ||
+		stats.BuyVolume != 3 || stats.SellVolume != 1 || stats.Imbalance != 0.5 {
+		t.Errorf("Test failed. Unexpected trade stats %+v", stats)
+	}
+
+	w = httptest.NewRecorder()
+	NewRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/exchanges/TradesTest/trades/recent/BTCUSD", nil))
+	var recent []trades.Trade
+	err = common.JSONDecode(w.Body.Bytes(), &recent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].Price != 6500 || recent[1].Side != trades.Sell {
+		t.Errorf("Test failed. Unexpected recent trades %+v", recent)
+	}
+}
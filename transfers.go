@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	log "github.com/thrasher-/gocryptotrader/logger"
+)
+
+// withdrawalJournalFile is the journal of withdrawals submitted by the bot in
+// the data directory
+const withdrawalJournalFile = "withdrawals.log"
+
+// autoWithdrawCrypto holds the withdrawal permissions which allow an exchange
+// to withdraw cryptocurrency through its API
+const autoWithdrawCrypto = exchange.AutoWithdrawCrypto |
+	exchange.AutoWithdrawCryptoWithAPIPermission |
+	exchange.AutoWithdrawCryptoWithSetup
+
+var (
+	// ErrTransferSameExchange is returned when the source and destination of
+	// a transfer are the same exchange
+	ErrTransferSameExchange = errors.New("source and destination exchanges must differ")
+	// ErrDepositAddressUnavailable is returned when the destination exchange
+	// doesn't supply a deposit address for the transferred currency
+	ErrDepositAddressUnavailable = errors.New("destination deposit address unavailable")
+	// ErrInsufficientBalance is returned when the source exchange balance
+	// doesn't cover the transferred amount
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrWithdrawalFailed is returned when the source exchange rejects a
+	// confirmed withdrawal
+	ErrWithdrawalFailed = errors.New("withdrawal failed")
+)
+
+// TransferRequest moves a cryptocurrency amount from the source exchange to
+// the deposit address of the destination exchange. The transfer is only
+// planned unless it is confirmed
+type TransferRequest struct {
+	Source      string  `json:"source"`
+	Destination string  `json:"destination"`
+	Currency    string  `json:"currency"`
+	Amount      float64 `json:"amount"`
+	Confirm     bool    `json:"confirm,omitempty"`
+}
+
+// TransferPlan holds the withdrawal a transfer makes. The fee is charged from
+// the withdrawn amount, NetAmount is the amount expected to be deposited
+type TransferPlan struct {
+	Source        string  `json:"source"`
+	Destination   string  `json:"destination"`
+	Currency      string  `json:"currency"`
+	Amount        float64 `json:"amount"`
+	Address       string  `json:"address"`
+	Fee           float64 `json:"fee"`
+	NetAmount     float64 `json:"netAmount"`
+	MinWithdrawal float64 `json:"minWithdrawal,omitempty"`
+	Available     float64 `json:"available"`
+	Executed      bool    `json:"executed"`
+	WithdrawalID  string  `json:"withdrawalId,omitempty"`
+}
+
+// WithdrawalRecord holds a withdrawal submitted by the bot and its outcome
+type WithdrawalRecord struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Exchange     string    `json:"exchange"`
+	Destination  string    `json:"destination,omitempty"`
+	Currency     string    `json:"currency"`
+	Amount       float64   `json:"amount"`
+	Fee          float64   `json:"fee"`
+	Address      string    `json:"address"`
+	WithdrawalID string    `json:"withdrawalId,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// withdrawalJournal appends each withdrawal submitted by the bot to the
+// journal file as a JSON line
+type withdrawalJournal struct {
+	mtx  sync.Mutex
+	path string
+}
+
+var withdrawals withdrawalJournal
+
+// record appends a withdrawal to the journal file
+func (j *withdrawalJournal) record(entry WithdrawalRecord) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	if j.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// TransferBetweenExchanges plans moving a cryptocurrency from one exchange to
+// another: the deposit address is fetched from the destination exchange, the
+// amount is checked against the source exchange balance and its withdrawal
+// minimum, and the withdrawal fee and net amount are worked out. A confirmed
+// transfer is withdrawn from the source exchange and journaled, an
+// unconfirmed transfer only returns the plan
+func TransferBetweenExchanges(req TransferRequest) (TransferPlan, error) {
+	source := GetExchangeByName(req.Source)
+	destination := GetExchangeByName(req.Destination)
+	if source == nil || destination == nil {
+		return TransferPlan{}, ErrExchangeNotFound
+	}
+	if source.GetName() == destination.GetName() {
+		return TransferPlan{}, ErrTransferSameExchange
+	}
+	c := common.StringToUpper(req.Currency)
+	if c == "" || currency.IsFiatCurrency(c) {
+		return TransferPlan{}, fmt.Errorf("invalid transfer currency %q, only cryptocurrencies can be transferred",
+			req.Currency)
+	}
+	if req.Amount <= 0 || math.IsNaN(req.Amount) || math.IsInf(req.Amount, 0) {
+		return TransferPlan{}, fmt.Errorf("invalid transfer amount %v", req.Amount)
+	}
+	for _, exch := range []exchange.IBotExchange{source, destination} {
+		if !isAuthAPISupported(exch) {
+			return TransferPlan{}, fmt.Errorf("%s: %w", exch.GetName(),
+				exchange.ErrAuthenticationNotConfigured)
+		}
+	}
+	if source.GetWithdrawPermissions()&autoWithdrawCrypto == 0 {
+		return TransferPlan{}, fmt.Errorf("%s doesn't support cryptocurrency withdrawals through its API: %w",
+			source.GetName(), common.ErrFunctionNotSupported)
+	}
+
+	plan := TransferPlan{
+		Source:      source.GetName(),
+		Destination: destination.GetName(),
+		Currency:    c,
+		Amount:      req.Amount,
+	}
+
+	address, err := destination.GetDepositAddress(pair.CurrencyItem(c), "", false)
+	if err != nil {
+		return TransferPlan{}, fmt.Errorf("%w: %s %s: %s", ErrDepositAddressUnavailable,
+			destination.GetName(), c, err)
+	}
+	if address == "" {
+		return TransferPlan{}, fmt.Errorf("%w: %s %s", ErrDepositAddressUnavailable,
+			destination.GetName(), c)
+	}
+	plan.Address = address
+
+	err = planTransferFee(source, destination, &plan)
+	if err != nil {
+		return TransferPlan{}, err
+	}
+
+	plan.Available, err = availableBalance(source, c)
+	if err != nil {
+		return TransferPlan{}, err
+	}
+	if plan.Available < plan.Amount {
+		return TransferPlan{}, fmt.Errorf("%w: %s %s, %v available of %v required",
+			ErrInsufficientBalance, source.GetName(), c, plan.Available, plan.Amount)
+	}
+
+	if !req.Confirm {
+		return plan, nil
+	}
+
+	entry := WithdrawalRecord{
+		Timestamp:   time.Now(),
+		Exchange:    plan.Source,
+		Destination: plan.Destination,
+		Currency:    c,
+		Amount:      plan.Amount,
+		Fee:         plan.Fee,
+		Address:     plan.Address,
+	}
+	id, err := source.WithdrawCryptocurrencyFunds(exchange.WithdrawRequest{
+		Description: fmt.Sprintf("Transfer to %s", plan.Destination),
+		Amount:      plan.Amount,
+		Currency:    pair.CurrencyItem(c),
+		Address:     plan.Address,
+		FeeAmount:   plan.Fee,
+	})
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	entry.WithdrawalID = id
+	if journalErr := withdrawals.record(entry); journalErr != nil {
+		log.Errorf("Unable to journal %s %s withdrawal to %s: %s", plan.Source, c,
+			plan.Destination, journalErr)
+	}
+	if err != nil {
+		return TransferPlan{}, fmt.Errorf("%w: %s: %s", ErrWithdrawalFailed,
+			plan.Source, err)
+	}
+
+	log.Infof("Withdrew %v %s from %s to %s address %s, withdrawal ID %s.", plan.Amount,
+		c, plan.Source, plan.Destination, plan.Address, id)
+	plan.Executed = true
+	plan.WithdrawalID = id
+	return plan, nil
+}
+
+// planTransferFee sets the withdrawal fee and net amount of a transfer from
+// the source exchange currency details, or its fee schedule if it has none,
+// and checks the withdrawal minimum and that neither exchange has suspended
+// the transfer
+func planTransferFee(source, destination exchange.IBotExchange, plan *TransferPlan) error {
+	details, err := source.GetCurrencyDetails()
+	d, ok := exchange.GetCurrencyDetail(details, plan.Currency)
+	if err == nil && ok {
+		if d.WithdrawalSuspended {
+			return fmt.Errorf("%s %s withdrawals are suspended", source.GetName(),
+				plan.Currency)
+		}
+		plan.Fee = d.WithdrawalFee
+		plan.MinWithdrawal = d.MinWithdrawal
+	} else {
+		plan.Fee, err = source.GetFeeByType(exchange.FeeBuilder{
+			FeeType:       exchange.CryptocurrencyWithdrawalFee,
+			FirstCurrency: plan.Currency,
+			Amount:        plan.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get %s %s withdrawal fee: %s",
+				source.GetName(), plan.Currency, err)
+		}
+	}
+
+	details, err = destination.GetCurrencyDetails()
+	if d, ok := exchange.GetCurrencyDetail(details, plan.Currency); err == nil && ok &&
+		d.DepositSuspended {
+		return fmt.Errorf("%s %s deposits are suspended", destination.GetName(),
+			plan.Currency)
+	}
+
+	if plan.Amount < plan.MinWithdrawal {
+		return fmt.Errorf("%s %s withdrawal amount %v is below the minimum of %v",
+			source.GetName(), plan.Currency, plan.Amount, plan.MinWithdrawal)
+	}
+	plan.NetAmount = plan.Amount - plan.Fee
+	if plan.NetAmount <= 0 {
+		return fmt.Errorf("%s %s withdrawal amount %v doesn't cover the fee of %v",
+			source.GetName(), plan.Currency, plan.Amount, plan.Fee)
+	}
+	return nil
+}
+
+// availableBalance returns the balance of a currency on an exchange which
+// isn't held by open orders
+func availableBalance(exch exchange.IBotExchange, c string) (float64, error) {
+	account, err := exch.GetAccountInfo()
+	if err != nil {
+		return 0, err
+	}
+	var available float64
+	for x := range account.Accounts {
+		for y := range account.Accounts[x].Currencies {
+			balance := account.Accounts[x].Currencies[y]
+			if common.StringToUpper(balance.CurrencyName) == c {
+				available += balance.TotalValue - balance.Hold
+			}
+		}
+	}
+	return available, nil
+}
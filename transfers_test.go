@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+)
+
+// transferAccount holds the deposit address, balance and currency details a
+// test exchange supplies and records the withdrawals made from it
+type transferAccount struct {
+	exch        *testExchange
+	address     string
+	addressErr  error
+	balance     float64
+	hold        float64
+	details     []exchange.CurrencyDetails
+	withdrawErr error
+	withdrawn   []exchange.WithdrawRequest
+}
+
+// newTransferAccount returns a transfer account on a test exchange
+func newTransferAccount(name string) *transferAccount {
+	a := &transferAccount{exch: newTestExchange(name)}
+	a.exch.getDepositAddress = func(c pair.CurrencyItem, accountID string, forceRefresh bool) (string, error) {
+		return a.address, a.addressErr
+	}
+	a.exch.getCurrencyDetails = func() ([]exchange.CurrencyDetails, error) {
+		if a.details == nil {
+			return nil, common.ErrFunctionNotSupported
+		}
+		return a.details, nil
+	}
+	a.exch.getFeeByType = func(feeBuilder exchange.FeeBuilder) (float64, error) {
+		return 0.001, nil
+	}
+	a.exch.getAccountInfo = func() (exchange.AccountInfo, error) {
+		return exchange.AccountInfo{Exchange: name, Accounts: []exchange.Account{{
+			Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: "btc", TotalValue: a.balance, Hold: a.hold},
+			},
+		}}}, nil
+	}
+	a.exch.withdrawCryptocurrencyFunds = func(req exchange.WithdrawRequest) (string, error) {
+		if a.withdrawErr != nil {
+			return "", a.withdrawErr
+		}
+		a.withdrawn = append(a.withdrawn, req)
+		return "w1", nil
+	}
+	return a
+}
+
+// setupTransferExchanges loads a source exchange holding 1 BTC and a
+// destination exchange with a BTC deposit address
+func setupTransferExchanges() (source, destination *transferAccount) {
+	source = newTransferAccount("Source")
+	source.exch.base.APIWithdrawPermissions = exchange.AutoWithdrawCrypto
+	source.balance, source.hold = 1.2, 0.2
+	source.details = []exchange.CurrencyDetails{
+		{Currency: "BTC", WithdrawalFee: 0.0005, MinWithdrawal: 0.01},
+	}
+	destination = newTransferAccount("Destination")
+	destination.address = "1BTCaddress"
+	bot.exchanges = []exchange.IBotExchange{source.exch, destination.exch}
+	return source, destination
+}
+
+func TestTransferBetweenExchanges(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange, path string) {
+		bot.exchanges = exchanges
+		withdrawals.path = path
+	}(bot.exchanges, withdrawals.path)
+	dir, err := ioutil.TempDir("", "gcttransfers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	withdrawals.path = filepath.Join(dir, withdrawalJournalFile)
+
+	source, destination := setupTransferExchanges()
+	req := TransferRequest{Source: "source", Destination: "destination",
+		Currency: "btc", Amount: 0.5}
+
+	// Plan only
+	plan, err := TransferBetweenExchanges(req)
+	if err != nil {
+		t.Fatalf("Test failed. TransferBetweenExchanges error: %s", err)
+	}
+	if plan.Executed || len(source.withdrawn) != 0 || plan.Address != "1BTCaddress" ||
+		plan.Fee != 0.0005 || plan.NetAmount != 0.4995 || plan.Available != 1 ||
+		plan.Currency != "BTC" || plan.Destination != "Destination" {
+		t.Errorf("Test failed. Unexpected plan %+v", plan)
+	}
+	if _, err = os.Stat(withdrawals.path); !os.IsNotExist(err) {
+		t.Error("Test failed. A planned transfer was journaled")
+	}
+
+	// The fee schedule is used without currency details
+	source.details = nil
+	plan, err = TransferBetweenExchanges(req)
+	if err != nil || plan.Fee != 0.001 || plan.MinWithdrawal != 0 {
+		t.Errorf("Test failed. Unexpected fee schedule plan %+v, error: %v", plan, err)
+	}
+
+	req.Confirm = true
+	plan, err = TransferBetweenExchanges(req)
+	if err != nil {
+		t.Fatalf("Test failed. TransferBetweenExchanges error: %s", err)
+	}
+	if !plan.Executed || plan.WithdrawalID != "w1" || len(source.withdrawn) != 1 ||
+		source.withdrawn[0].Address != "1BTCaddress" || source.withdrawn[0].Amount != 0.5 ||
+		source.withdrawn[0].Currency != "BTC" {
+		t.Errorf("Test failed. Unexpected executed plan %+v withdrawals %+v", plan,
+			source.withdrawn)
+	}
+
+	source.withdrawErr = errors.New("withdrawals disabled for API key")
+	_, err = TransferBetweenExchanges(req)
+	if !errors.Is(err, ErrWithdrawalFailed) {
+		t.Errorf("Test failed. Expected %v, got %v", ErrWithdrawalFailed, err)
+	}
+	journal, err := common.ReadFile(withdrawals.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(journal)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"destination":"Destination"`) ||
+		!strings.Contains(lines[0], `"withdrawalId":"w1"`) ||
+		!strings.Contains(lines[1], `"error":"withdrawals disabled for API key"`) {
+		t.Errorf("Test failed. Unexpected withdrawal journal %s", journal)
+	}
+
+	// Missing deposit address
+	source.withdrawErr, source.withdrawn = nil, nil
+	for _, addressErr := range []error{nil, common.ErrFunctionNotSupported} {
+		destination.address, destination.addressErr = "", addressErr
+		_, err = TransferBetweenExchanges(req)
+		if !errors.Is(err, ErrDepositAddressUnavailable) {
+			t.Errorf("Test failed. Expected %v, got %v", ErrDepositAddressUnavailable, err)
+		}
+	}
+	if len(source.withdrawn) != 0 {
+		t.Error("Test failed. Withdrew without a deposit address")
+	}
+	destination.address, destination.addressErr = "1BTCaddress", nil
+
+	// Insufficient balance, held funds aren't available
+	req.Amount = 1.1
+	_, err = TransferBetweenExchanges(req)
+	if !errors.Is(err, ErrInsufficientBalance) || len(source.withdrawn) != 0 {
+		t.Errorf("Test failed. Expected %v, got %v", ErrInsufficientBalance, err)
+	}
+}
+
+func TestTransferBetweenExchangesValidation(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+
+	source, destination := setupTransferExchanges()
+	_, err := TransferBetweenExchanges(TransferRequest{Source: "Source",
+		Destination: "Missing", Currency: "BTC", Amount: 1})
+	if err != ErrExchangeNotFound {
+		t.Errorf("Test failed. Expected %v, got %v", ErrExchangeNotFound, err)
+	}
+	_, err = TransferBetweenExchanges(TransferRequest{Source: "Source",
+		Destination: "source", Currency: "BTC", Amount: 1})
+	if err != ErrTransferSameExchange {
+		t.Errorf("Test failed. Expected %v, got %v", ErrTransferSameExchange, err)
+	}
+
+	for _, req := range []TransferRequest{
+		{Currency: "USD", Amount: 0.5},
+		{Currency: "", Amount: 0.5},
+		{Currency: "BTC", Amount: 0},
+		{Currency: "BTC", Amount: -1},
+		// Below the withdrawal minimum
+		{Currency: "BTC", Amount: 0.005},
+	} {
+		req.Source, req.Destination = "Source", "Destination"
+		_, err = TransferBetweenExchanges(req)
+		if err == nil {
+			t.Errorf("Test failed. Expected an error for %+v", req)
+		}
+	}
+
+	req := TransferRequest{Source: "Source", Destination: "Destination",
+		Currency: "BTC", Amount: 0.5}
+	source.details[0].WithdrawalSuspended = true
+	_, err = TransferBetweenExchanges(req)
+	if err == nil {
+		t.Error("Test failed. Expected a suspended withdrawals error")
+	}
+	source.details[0].WithdrawalSuspended = false
+	destination.details = []exchange.CurrencyDetails{{Currency: "BTC", DepositSuspended: true}}
+	_, err = TransferBetweenExchanges(req)
+	if err == nil {
+		t.Error("Test failed. Expected a suspended deposits error")
+	}
+	destination.details = nil
+
+	destination.exch.base.AuthenticatedAPISupport = false
+	_, err = TransferBetweenExchanges(req)
+	if !errors.Is(err, exchange.ErrAuthenticationNotConfigured) {
+		t.Errorf("Test failed. Expected %v, got %v", exchange.ErrAuthenticationNotConfigured, err)
+	}
+	destination.exch.base.AuthenticatedAPISupport = true
+	source.exch.base.APIWithdrawPermissions = exchange.WithdrawCryptoWithWebsiteApproval
+	_, err = TransferBetweenExchanges(req)
+	if !errors.Is(err, common.ErrFunctionNotSupported) {
+		t.Errorf("Test failed. Expected %v, got %v", common.ErrFunctionNotSupported, err)
+	}
+}
+
+func TestRESTTransferBetweenExchanges(t *testing.T) {
+	SetupTestHelpers(t)
+	defer func(exchanges []exchange.IBotExchange) {
+		bot.exchanges = exchanges
+	}(bot.exchanges)
+	source, _ := setupTransferExchanges()
+
+	router := NewRouter()
+	send := func(body string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(body))
+		if auth {
+			r.SetBasicAuth(bot.config.Webserver.AdminUsername,
+				bot.config.Webserver.AdminPassword)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	plan := `{"source":"Source","destination":"Destination","currency":"BTC","amount":0.5}`
+	w := send(plan, false)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Test failed. Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	for _, test := range []struct {
+		body string
+		code int
+	}{
+		{`{"amount":"lots"}`, http.StatusBadRequest},
+		{`{"source":"Blah","destination":"Destination","currency":"BTC","amount":0.5}`,
+			http.StatusNotFound},
+		{`{"source":"Source","destination":"Destination","currency":"BTC","amount":5}`,
+			http.StatusBadRequest},
+		{plan, http.StatusOK},
+	} {
+		w = send(test.body, true)
+		if w.Code != test.code {
+			t.Errorf("Test failed. %s expected status %d, got %d", test.body, test.code,
+				w.Code)
+		}
+	}
+
+	var result TransferPlan
+	err := common.JSONDecode(w.Body.Bytes(), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Executed || result.NetAmount != 0.4995 || len(source.withdrawn) != 0 {
+		t.Errorf("Test failed. Unexpected plan %+v", result)
+	}
+
+	source.withdrawErr = errors.New("invalid withdrawal address")
+	w = send(`{"source":"Source","destination":"Destination","currency":"BTC","amount":0.5,"confirm":true}`,
+		true)
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Test failed. Expected status %d, got %d", http.StatusBadGateway, w.Code)
+	}
+}
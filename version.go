@@ -16,6 +16,13 @@ const (
 	Issues          = "Issues: https://github.com/thrasher-/gocryptotrader/issues"
 )
 
+// Build information, set at link time via
+// -ldflags "-X main.BuildCommit=<commit> -X main.BuildTime=<time>"
+var (
+	BuildCommit string
+	BuildTime   string
+)
+
 // BuildVersion returns the version string
 func BuildVersion(short bool) string {
 	versionStr := fmt.Sprintf("GoCryptoTrader v%s.%s",
@@ -1,24 +1,71 @@
 package main
 
 import (
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-/gocryptotrader/common"
 	"github.com/thrasher-/gocryptotrader/config"
 	"github.com/thrasher-/gocryptotrader/currency"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
 	log "github.com/thrasher-/gocryptotrader/logger"
 )
 
 // Const vars for websocket
 const (
 	WebsocketResponseSuccess = "OK"
+	// websocketMaxDroppedMessages is the number of consecutive broadcasts
+	// dropped for a client with a full send queue before it is disconnected
+	websocketMaxDroppedMessages = 100
+	// websocketWriteTimeout is the time given to write a message to a client
+	// before its connection is closed
+	websocketWriteTimeout = time.Second * 10
+)
+
+// Websocket events broadcast to clients
+const (
+	WebsocketEventTickerUpdate        = "ticker_update"
+	WebsocketEventOrderbookUpdate     = "orderbook_update"
+	WebsocketEventBalanceUpdate       = "balance_update"
+	WebsocketEventPortfolioUpdate     = "portfolio_update"
+	WebsocketEventReconnectFailure    = "websocket_reconnect_failure"
+	WebsocketEventExchangeMaintenance = "exchange_maintenance"
+	WebsocketEventOrderFill           = "order_fill"
+	WebsocketEventConfigUpdate        = "config_update"
+)
+
+var websocketBroadcastEvents = []string{
+	WebsocketEventTickerUpdate,
+	WebsocketEventOrderbookUpdate,
+	WebsocketEventBalanceUpdate,
+	WebsocketEventPortfolioUpdate,
+	WebsocketEventReconnectFailure,
+	WebsocketEventExchangeMaintenance,
+	WebsocketEventOrderFill,
+	WebsocketEventConfigUpdate,
+}
+
+var (
+	errWebsocketConnectionLimitReached = errors.New("websocket client limit reached")
+	errWebsocketClientClosed           = errors.New("websocket client closed")
+	errWebsocketSendQueueFull          = errors.New("websocket client send queue full")
 )
 
 var (
 	wsHub        *WebsocketHub
 	wsHubStarted bool
+	// wsRPCDisabled is set when the bot is started with the websocket RPC
+	// handler disabled, websocket connections are then refused
+	wsRPCDisabled bool
 )
 
 type wsCommandHandler struct {
@@ -27,33 +74,159 @@ type wsCommandHandler struct {
 }
 
 var wsHandlers = map[string]wsCommandHandler{
-	"auth":             {authRequired: false, handler: wsAuth},
-	"getconfig":        {authRequired: true, handler: wsGetConfig},
-	"saveconfig":       {authRequired: true, handler: wsSaveConfig},
-	"getaccountinfo":   {authRequired: true, handler: wsGetAccountInfo},
-	"gettickers":       {authRequired: false, handler: wsGetTickers},
-	"getticker":        {authRequired: false, handler: wsGetTicker},
-	"getorderbooks":    {authRequired: false, handler: wsGetOrderbooks},
-	"getorderbook":     {authRequired: false, handler: wsGetOrderbook},
-	"getexchangerates": {authRequired: false, handler: wsGetExchangeRates},
-	"getportfolio":     {authRequired: true, handler: wsGetPortfolio},
-}
-
-// WebsocketClient stores information related to the websocket client
+	"auth":                {authRequired: false, handler: wsAuth},
+	"getconfig":           {authRequired: true, handler: wsGetConfig},
+	"saveconfig":          {authRequired: true, handler: wsSaveConfig},
+	"getconfigsection":    {authRequired: false, handler: wsGetConfigSection},
+	"updateconfigsection": {authRequired: true, handler: wsUpdateConfigSection},
+	"setexchangeendpoint": {authRequired: true, handler: wsSetExchangeEndpoint},
+	"getaccountinfo":      {authRequired: true, handler: wsGetAccountInfo},
+	"gettickers":          {authRequired: false, handler: wsGetTickers},
+	"getticker":           {authRequired: false, handler: wsGetTicker},
+	"getorderbooks":       {authRequired: false, handler: wsGetOrderbooks},
+	"getorderbook":        {authRequired: false, handler: wsGetOrderbook},
+	"getexchangerates":    {authRequired: false, handler: wsGetExchangeRates},
+	"getportfolio":        {authRequired: true, handler: wsGetPortfolio},
+	"getexposurereport":   {authRequired: true, handler: wsGetExposureReport},
+	"getexchangehealth":   {authRequired: false, handler: wsGetExchangeHealth},
+	"getstats":            {authRequired: false, handler: wsGetStats},
+	"getrecenttrades":     {authRequired: false, handler: wsGetRecentTrades},
+	"gettradestats":       {authRequired: false, handler: wsGetTradeStats},
+	"getinfo":             {authRequired: false, handler: wsGetInfo},
+	"getsettings":         {authRequired: false, handler: wsGetSettings},
+	"shutdown":            {authRequired: true, handler: wsShutdown},
+	"subscribe":           {authRequired: true, handler: wsSubscribe},
+	"unsubscribe":         {authRequired: true, handler: wsUnsubscribe},
+}
+
+// WebsocketClient stores information related to the websocket client. Send
+// queues the messages written to the client, broadcasts are dropped while it
+// is full
 type WebsocketClient struct {
-	Hub           *WebsocketHub
-	Conn          *websocket.Conn
-	Authenticated bool
-	authFailures  int
-	Send          chan []byte
+	Hub          *WebsocketHub
+	Conn         *websocket.Conn
+	Send         chan []byte
+	authFailures int
+
+	m             sync.Mutex
+	authenticated bool
+	closed        bool
+	dropped       int
+	// subscribed is set once the client subscribes, from then on it only
+	// receives broadcasts matching its subscriptions
+	subscribed    bool
+	subscriptions map[WebsocketSubscription]struct{}
 }
 
 // WebsocketHub stores the data for managing websocket clients
 type WebsocketHub struct {
-	Clients    map[*WebsocketClient]bool
-	Broadcast  chan []byte
-	Register   chan *WebsocketClient
-	Unregister chan *WebsocketClient
+	clients map[*WebsocketClient]bool
+	m       sync.Mutex
+}
+
+// WebsocketSubscription matches broadcasts by event, exchange and pair, an
+// empty field matches any. Events and exchanges are lower cased and pairs
+// upper cased without delimiters
+type WebsocketSubscription struct {
+	Event    string `json:"event,omitempty"`
+	Exchange string `json:"exchange,omitempty"`
+	Pair     string `json:"pair,omitempty"`
+}
+
+// matches returns whether a broadcast event matches the subscription
+func (s WebsocketSubscription) matches(evt *WebsocketEvent) bool {
+	if s.Event != "" && s.Event != common.StringToLower(evt.Event) {
+		return false
+	}
+	if s.Exchange != "" && s.Exchange != common.StringToLower(evt.Exchange) {
+		return false
+	}
+	if s.Pair == "" {
+		return true
+	}
+	p, ok := websocketEventPair(evt.Data)
+	if !ok {
+		return false
+	}
+	filter := TickerFilter{Pairs: []string{s.Pair}}
+	return filter.matchPair(p)
+}
+
+// websocketEventPair returns the currency pair of a broadcast event's data
+func websocketEventPair(data interface{}) (pair.CurrencyPair, bool) {
+	switch d := data.(type) {
+	case ticker.Price:
+		return d.Pair, true
+	case orderbook.Base:
+		return d.Pair, true
+	case OrderFill:
+		return d.Pair, true
+	}
+	return pair.CurrencyPair{}, false
+}
+
+// WebsocketSubscriptionRequest subscribes to or unsubscribes from the
+// broadcasts of every combination of its events, exchanges and pairs, an empty
+// list matches any. Unsubscribing without events, exchanges or pairs removes
+// every subscription
+type WebsocketSubscriptionRequest struct {
+	Events    []string `json:"events"`
+	Exchanges []string `json:"exchanges"`
+	Pairs     []string `json:"pairs"`
+}
+
+// subscriptions validates the request and returns its subscriptions
+func (r *WebsocketSubscriptionRequest) subscriptions() ([]WebsocketSubscription, error) {
+	events := []string{""}
+	if len(r.Events) > 0 {
+		events = nil
+		for x := range r.Events {
+			event := common.StringToLower(r.Events[x])
+			if !common.StringDataCompare(websocketBroadcastEvents, event) {
+				return nil, fmt.Errorf("unsupported event %q, supported events: %s",
+					r.Events[x], common.JoinStrings(websocketBroadcastEvents, ", "))
+			}
+			events = append(events, event)
+		}
+	}
+
+	exchanges := []string{""}
+	if len(r.Exchanges) > 0 {
+		exchanges = nil
+		for x := range r.Exchanges {
+			if GetExchangeByName(r.Exchanges[x]) == nil {
+				return nil, fmt.Errorf("%s: %s", ErrExchangeNotFound, r.Exchanges[x])
+			}
+			exchanges = append(exchanges, common.StringToLower(r.Exchanges[x]))
+		}
+	}
+
+	pairs := []string{""}
+	if len(r.Pairs) > 0 {
+		filter := TickerFilter{Pairs: r.Pairs}
+		err := filter.Validate()
+		if err != nil {
+			return nil, err
+		}
+		pairs = nil
+		for x := range r.Pairs {
+			pairs = append(pairs, pairSymbol(r.Pairs[x]))
+		}
+	}
+
+	var result []WebsocketSubscription
+	for x := range events {
+		for y := range exchanges {
+			for z := range pairs {
+				result = append(result, WebsocketSubscription{
+					Event:    events[x],
+					Exchange: exchanges[y],
+					Pair:     pairs[z],
+				})
+			}
+		}
+	}
+	return result, nil
 }
 
 // WebsocketEvent is the struct used for websocket events
@@ -74,9 +247,74 @@ type WebsocketEventResponse struct {
 // WebsocketOrderbookTickerRequest is a struct used for ticker and orderbook
 // requests
 type WebsocketOrderbookTickerRequest struct {
+	Exchange                 string `json:"exchangeName"`
+	Currency                 string `json:"currency"`
+	AssetType                string `json:"assetType"`
+	ConvertToDisplayCurrency bool   `json:"convertToDisplayCurrency"`
+	Refresh                  bool   `json:"refresh"`
+	PairDisplayFormat
+}
+
+// WebsocketStatsRequest is a struct used for exchange stats requests
+type WebsocketStatsRequest struct {
+	Currency  string `json:"currency"`
+	AssetType string `json:"assetType"`
+	ByVolume  bool   `json:"byVolume"`
+}
+
+// WebsocketConfigSectionRequest is a struct used for config section requests,
+// Section is a dot separated path such as exchanges.Bitfinex.httpTimeout and
+// Data the JSON payload merged into it by updates
+type WebsocketConfigSectionRequest struct {
+	Section string          `json:"section"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// WebsocketExchangeEndpointRequest is a struct used for overriding the
+// endpoints of an exchange, empty URLs are left unchanged
+type WebsocketExchangeEndpointRequest struct {
+	Exchange        string `json:"exchangeName"`
+	APIURL          string `json:"apiUrl"`
+	APIURLSecondary string `json:"apiUrlSecondary"`
+	WebsocketURL    string `json:"websocketUrl"`
+}
+
+// WebsocketTradesRequest is a struct used for recent trades and trade stats
+// requests, Window is a duration such as 30s or 5m
+type WebsocketTradesRequest struct {
 	Exchange  string `json:"exchangeName"`
 	Currency  string `json:"currency"`
 	AssetType string `json:"assetType"`
+	Limit     int    `json:"limit"`
+	Window    string `json:"window"`
+}
+
+// WebsocketTickersRequest is a struct used for all ticker requests
+type WebsocketTickersRequest struct {
+	ConvertToDisplayCurrency bool `json:"convertToDisplayCurrency"`
+	PairDisplayFormat
+	TickerFilter
+}
+
+// Validate checks the tickers request display format and filter
+func (r *WebsocketTickersRequest) Validate() error {
+	err := r.PairDisplayFormat.Validate()
+	if err != nil {
+		return err
+	}
+	return r.TickerFilter.Validate()
+}
+
+// WebsocketOrderbooksRequest is a struct used for all orderbooks requests
+type WebsocketOrderbooksRequest struct {
+	PairDisplayFormat
+}
+
+// WebsocketAccountInfoRequest is a struct used for account info requests,
+// optionally scoped to an exchange sub-account
+type WebsocketAccountInfoRequest struct {
+	Exchange string `json:"exchangeName"`
+	Account  string `json:"account"`
 }
 
 // WebsocketAuth is a struct used for
@@ -88,38 +326,199 @@ type WebsocketAuth struct {
 // NewWebsocketHub Creates a new websocket hub
 func NewWebsocketHub() *WebsocketHub {
 	return &WebsocketHub{
-		Broadcast:  make(chan []byte),
-		Register:   make(chan *WebsocketClient),
-		Unregister: make(chan *WebsocketClient),
-		Clients:    make(map[*WebsocketClient]bool),
+		clients: make(map[*WebsocketClient]bool),
 	}
 }
 
-func (h *WebsocketHub) run() {
-	for {
-		select {
-		case client := <-h.Register:
-			h.Clients[client] = true
-		case client := <-h.Unregister:
-			if _, ok := h.Clients[client]; ok {
-				log.Debugln("websocket: disconnected client")
-				delete(h.Clients, client)
-				close(client.Send)
-			}
-		case message := <-h.Broadcast:
-			for client := range h.Clients {
-				select {
-				case client.Send <- message:
-				default:
-					log.Debugln("websocket: disconnected client")
-					close(client.Send)
-					delete(h.Clients, client)
-				}
-			}
+// Len returns the number of connected clients
+func (h *WebsocketHub) Len() int {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return len(h.clients)
+}
+
+// register adds a client unless the connection limit is reached
+func (h *WebsocketHub) register(c *WebsocketClient, limit int) error {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if len(h.clients) >= limit {
+		return errWebsocketConnectionLimitReached
+	}
+	h.clients[c] = true
+	return nil
+}
+
+// unregister removes a client and closes its send queue
+func (h *WebsocketHub) unregister(c *WebsocketClient) {
+	h.m.Lock()
+	_, ok := h.clients[c]
+	delete(h.clients, c)
+	h.m.Unlock()
+	if ok {
+		log.Debugln("websocket: disconnected client")
+		c.close()
+	}
+}
+
+// broadcast queues a message to each authenticated client subscribed to the
+// event. A client whose send queue stays full is disconnected so a slow
+// client can't hold up the others
+func (h *WebsocketHub) broadcast(evt *WebsocketEvent, data []byte) {
+	h.m.Lock()
+	clients := make([]*WebsocketClient, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.m.Unlock()
+
+	for _, c := range clients {
+		if !c.wants(evt) {
+			continue
+		}
+		err := c.queue(data)
+		if err != errWebsocketSendQueueFull {
+			continue
+		}
+		if c.dropMessage() >= websocketMaxDroppedMessages {
+			log.Warnf("websocket: disconnecting slow client, %d consecutive messages dropped",
+				websocketMaxDroppedMessages)
+			c.disconnect()
+		}
+	}
+}
+
+// IsAuthenticated returns whether the client has authenticated
+func (c *WebsocketClient) IsAuthenticated() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.authenticated
+}
+
+// setAuthenticated marks the client as authenticated
+func (c *WebsocketClient) setAuthenticated() {
+	c.m.Lock()
+	c.authenticated = true
+	c.m.Unlock()
+}
+
+// wants returns whether the client receives a broadcast event, it must be
+// authenticated and, once it has subscribed, match one of its subscriptions
+func (c *WebsocketClient) wants(evt *WebsocketEvent) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if !c.authenticated {
+		return false
+	}
+	if !c.subscribed {
+		return true
+	}
+	for s := range c.subscriptions {
+		if s.matches(evt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscriptions returns the subscriptions of the client
+func (c *WebsocketClient) Subscriptions() []WebsocketSubscription {
+	c.m.Lock()
+	defer c.m.Unlock()
+	result := []WebsocketSubscription{}
+	for s := range c.subscriptions {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Event != result[j].Event {
+			return result[i].Event < result[j].Event
+		}
+		if result[i].Exchange != result[j].Exchange {
+			return result[i].Exchange < result[j].Exchange
 		}
+		return result[i].Pair < result[j].Pair
+	})
+	return result
+}
+
+// subscribe adds subscriptions to the client
+func (c *WebsocketClient) subscribe(subs []WebsocketSubscription) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.subscribed = true
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[WebsocketSubscription]struct{})
+	}
+	for x := range subs {
+		c.subscriptions[subs[x]] = struct{}{}
+	}
+}
+
+// unsubscribe removes subscriptions from the client, or all of them if none
+// are supplied
+func (c *WebsocketClient) unsubscribe(subs []WebsocketSubscription) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.subscribed = true
+	if subs == nil {
+		c.subscriptions = nil
+		return
+	}
+	for x := range subs {
+		delete(c.subscriptions, subs[x])
+	}
+}
+
+// queue adds a message to the send queue without blocking
+func (c *WebsocketClient) queue(data []byte) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return errWebsocketClientClosed
+	}
+	select {
+	case c.Send <- data:
+		c.dropped = 0
+		return nil
+	default:
+		return errWebsocketSendQueueFull
+	}
+}
+
+// dropMessage counts a message dropped for a full send queue and returns the
+// number of consecutive messages dropped
+func (c *WebsocketClient) dropMessage() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.dropped++
+	return c.dropped
+}
+
+// close closes the send queue, stopping the writer
+func (c *WebsocketClient) close() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
 	}
 }
 
+// isClosed returns whether the send queue of the client is closed
+func (c *WebsocketClient) isClosed() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.closed
+}
+
+// disconnect unregisters the client and closes its send queue, the writer
+// sends the queued messages before closing the connection
+func (c *WebsocketClient) disconnect() {
+	if c.Hub != nil {
+		c.Hub.unregister(c)
+	}
+	c.close()
+}
+
 // SendWebsocketMessage sends a websocket event to the client
 func (c *WebsocketClient) SendWebsocketMessage(evt interface{}) error {
 	data, err := common.JSONEncode(evt)
@@ -128,13 +527,12 @@ func (c *WebsocketClient) SendWebsocketMessage(evt interface{}) error {
 		return err
 	}
 
-	c.Send <- data
-	return nil
+	return c.queue(data)
 }
 
 func (c *WebsocketClient) read() {
 	defer func() {
-		c.Hub.Unregister <- c
+		c.Hub.unregister(c)
 		c.Conn.Close()
 	}()
 
@@ -175,7 +573,12 @@ func (c *WebsocketClient) read() {
 				continue
 			}
 
-			if result.authRequired && !c.Authenticated {
+			if IsStopping() {
+				c.SendWebsocketMessage(WebsocketEventResponse{Event: evt.Event, Error: "bot is shutting down"})
+				continue
+			}
+
+			if result.authRequired && !c.IsAuthenticated() {
 				log.Warnf("Websocket: request %s failed due to unauthenticated request on an authenticated API", evt.Event)
 				c.SendWebsocketMessage(WebsocketEventResponse{Event: evt.Event, Error: "unauthorised request on authenticated API"})
 				continue
@@ -197,6 +600,7 @@ func (c *WebsocketClient) write() {
 	for { // nolint: gosimple
 		select {
 		case message, ok := <-c.Send:
+			c.Conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
 			if !ok {
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				log.Debugln("websocket: hub closed the channel")
@@ -224,17 +628,16 @@ func (c *WebsocketClient) write() {
 	}
 }
 
-// StartWebsocketHandler starts the websocket hub and routine which
-// handles clients
+// StartWebsocketHandler starts the websocket hub which handles clients
 func StartWebsocketHandler() {
 	if !wsHubStarted {
 		wsHubStarted = true
 		wsHub = NewWebsocketHub()
-		go wsHub.run()
 	}
 }
 
-// BroadcastWebsocketMessage meow
+// BroadcastWebsocketMessage sends an event to every authenticated client
+// subscribed to it without waiting for slow clients
 func BroadcastWebsocketMessage(evt WebsocketEvent) error {
 	if !wsHubStarted {
 		return errors.New("websocket service not started")
@@ -245,19 +648,23 @@ func BroadcastWebsocketMessage(evt WebsocketEvent) error {
 		return err
 	}
 
-	wsHub.Broadcast <- data
+	wsHub.broadcast(&evt, data)
 	return nil
 }
 
 // WebsocketClientHandler upgrades the HTTP connection to a websocket
 // compatible one
 func WebsocketClientHandler(w http.ResponseWriter, r *http.Request) {
+	if wsRPCDisabled {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
 	if !wsHubStarted {
 		StartWebsocketHandler()
 	}
 
 	connectionLimit := bot.config.Webserver.WebsocketConnectionLimit
-	numClients := len(wsHub.Clients)
+	numClients := wsHub.Len()
 
 	if numClients >= connectionLimit {
 		log.Warnf("websocket: client rejected due to websocket client limit reached. Number of clients %d. Limit %d.",
@@ -283,10 +690,30 @@ func WebsocketClientHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := &WebsocketClient{Hub: wsHub, Conn: conn, Send: make(chan []byte, 1024)}
-	client.Hub.Register <- client
+	client := &WebsocketClient{Hub: wsHub, Conn: conn,
+		Send: make(chan []byte, bot.config.Webserver.WebsocketSendQueueSize)}
+	// Clients connecting concurrently can pass the check above
+	err = client.Hub.register(client, connectionLimit)
+	if err != nil {
+		log.Warnf("websocket: client rejected due to websocket client limit reached. Limit %d.",
+			connectionLimit)
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()))
+		conn.Close()
+		return
+	}
 	log.Debugf("websocket: client connected. Connected clients: %d. Limit %d.",
-		numClients+1, connectionLimit)
+		wsHub.Len(), connectionLimit)
+
+	if authTimeout := bot.config.Webserver.WebsocketAuthTimeout; authTimeout > 0 {
+		time.AfterFunc(authTimeout, func() {
+			if !client.IsAuthenticated() && !client.isClosed() {
+				log.Debugf("websocket: disconnecting client, not authenticated within %v",
+					authTimeout)
+				client.disconnect()
+			}
+		})
+	}
 
 	go client.read()
 	go client.write()
@@ -305,10 +732,13 @@ func wsAuth(client *WebsocketClient, data interface{}) error {
 		return err
 	}
 
+	// The webserver admin credentials are shared with the REST API, the
+	// password is sent as its SHA256 hash
 	hashPW := common.HexEncodeToString(common.GetSHA256([]byte(bot.config.Webserver.AdminPassword)))
 
-	if auth.Username == bot.config.Webserver.AdminUsername && auth.Password == hashPW {
-		client.Authenticated = true
+	if subtle.ConstantTimeCompare([]byte(auth.Username), []byte(bot.config.Webserver.AdminUsername)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(auth.Password), []byte(hashPW)) == 1 {
+		client.setAuthenticated()
 		wsResp.Data = WebsocketResponseSuccess
 		log.Debugf("websocket: client authenticated successfully")
 		return client.SendWebsocketMessage(wsResp)
@@ -320,7 +750,7 @@ func wsAuth(client *WebsocketClient, data interface{}) error {
 	if client.authFailures >= bot.config.Webserver.WebsocketMaxAuthFailures {
 		log.Debugf("websocket: disconnecting client, maximum auth failures threshold reached (failures: %d limit: %d)",
 			client.authFailures, bot.config.Webserver.WebsocketMaxAuthFailures)
-		wsHub.Unregister <- client
+		client.disconnect()
 		return nil
 	}
 
@@ -349,7 +779,7 @@ func wsSaveConfig(client *WebsocketClient, data interface{}) error {
 		return err
 	}
 
-	err = bot.config.UpdateConfig(bot.configFile, cfg)
+	err = bot.config.UpdateConfig(bot.settings.ConfigFile, &cfg)
 	if err != nil {
 		wsResp.Error = err.Error()
 		client.SendWebsocketMessage(wsResp)
@@ -361,12 +791,96 @@ func wsSaveConfig(client *WebsocketClient, data interface{}) error {
 	return client.SendWebsocketMessage(wsResp)
 }
 
+func wsGetConfigSection(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetConfigSection",
+	}
+	var sectionReq WebsocketConfigSectionRequest
+	err := common.JSONDecode(data.([]byte), &sectionReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result, err := GetConfigSection(sectionReq.Section)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsUpdateConfigSection(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "UpdateConfigSection",
+	}
+	var sectionReq WebsocketConfigSectionRequest
+	err := common.JSONDecode(data.([]byte), &sectionReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result, err := UpdateConfigSection(sectionReq.Section, sectionReq.Data)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsSetExchangeEndpoint(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "SetExchangeEndpoint",
+	}
+	var endpointReq WebsocketExchangeEndpointRequest
+	err := common.JSONDecode(data.([]byte), &endpointReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result, err := SetExchangeEndpoint(endpointReq.Exchange, endpointReq.APIURL,
+		endpointReq.APIURLSecondary, endpointReq.WebsocketURL)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
+	return client.SendWebsocketMessage(wsResp)
+}
+
 func wsGetAccountInfo(client *WebsocketClient, data interface{}) error {
-	accountInfo := GetAllEnabledExchangeAccountInfo()
 	wsResp := WebsocketEventResponse{
 		Event: "GetAccountInfo",
-		Data:  accountInfo,
 	}
+
+	var accountReq WebsocketAccountInfoRequest
+	if d, ok := data.([]byte); ok {
+		// Requests without a body fall through to all enabled exchanges
+		common.JSONDecode(d, &accountReq)
+	}
+
+	if accountReq.Exchange == "" {
+		wsResp.Data = GetAllEnabledExchangeAccountInfo()
+		return client.SendWebsocketMessage(wsResp)
+	}
+
+	result, err := GetExchangeAccountInfo(accountReq.Exchange, accountReq.Account)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
 	return client.SendWebsocketMessage(wsResp)
 }
 
@@ -374,7 +888,29 @@ func wsGetTickers(client *WebsocketClient, data interface{}) error {
 	wsResp := WebsocketEventResponse{
 		Event: "GetTickers",
 	}
-	wsResp.Data = GetAllActiveTickers()
+	var tickersReq WebsocketTickersRequest
+	err := common.JSONDecode(data.([]byte), &tickersReq)
+	if err == nil {
+		err = tickersReq.Validate()
+	}
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	tickers, err := getFilteredTickers(tickersReq.TickerFilter,
+		tickersReq.PairDisplayFormat)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	if tickersReq.ConvertToDisplayCurrency {
+		wsResp.Data = ConvertTickersToDisplayCurrency(tickers)
+	} else {
+		wsResp.Data = tickers
+	}
 	return client.SendWebsocketMessage(wsResp)
 }
 
@@ -384,21 +920,48 @@ func wsGetTicker(client *WebsocketClient, data interface{}) error {
 	}
 	var tickerReq WebsocketOrderbookTickerRequest
 	err := common.JSONDecode(data.([]byte), &tickerReq)
+	if err == nil {
+		err = tickerReq.Validate()
+	}
 	if err != nil {
 		wsResp.Error = err.Error()
 		client.SendWebsocketMessage(wsResp)
 		return err
 	}
 
-	result, err := GetSpecificTicker(tickerReq.Currency,
-		tickerReq.Exchange, tickerReq.AssetType)
+	if tickerReq.AssetType == "" {
+		tickerReq.AssetType = ticker.Spot
+	}
 
+	p, err := ParseExchangePair(tickerReq.Exchange, tickerReq.Currency)
 	if err != nil {
 		wsResp.Error = err.Error()
 		client.SendWebsocketMessage(wsResp)
 		return err
 	}
-	wsResp.Data = result
+
+	var result ticker.Price
+	if tickerReq.Refresh {
+		result, err = RefreshSpecificTicker(shutdownCtx, p, tickerReq.Exchange,
+			tickerReq.AssetType)
+	} else {
+		result, err = GetSpecificTicker(p, tickerReq.Exchange,
+			tickerReq.AssetType)
+	}
+
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result = formatTickerPair(result, tickerReq.PairDisplayFormat)
+	if tickerReq.ConvertToDisplayCurrency {
+		wsResp.Data = DisplayTickerResponse{
+			ConvertTickerToDisplayCurrency(result), !tickerReq.Refresh}
+	} else {
+		wsResp.Data = TickerResponse{result, !tickerReq.Refresh}
+	}
 	return client.SendWebsocketMessage(wsResp)
 }
 
@@ -406,7 +969,18 @@ func wsGetOrderbooks(client *WebsocketClient, data interface{}) error {
 	wsResp := WebsocketEventResponse{
 		Event: "GetOrderbooks",
 	}
-	wsResp.Data = GetAllActiveOrderbooks()
+	var orderbooksReq WebsocketOrderbooksRequest
+	if d, ok := data.([]byte); ok {
+		// Requests without a body use the configured pair display format
+		common.JSONDecode(d, &orderbooksReq)
+	}
+	err := orderbooksReq.Validate()
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = GetAllActiveOrderbooks(orderbooksReq.PairDisplayFormat)
 	return client.SendWebsocketMessage(wsResp)
 }
 
@@ -416,21 +990,43 @@ func wsGetOrderbook(client *WebsocketClient, data interface{}) error {
 	}
 	var orderbookReq WebsocketOrderbookTickerRequest
 	err := common.JSONDecode(data.([]byte), &orderbookReq)
+	if err == nil {
+		err = orderbookReq.Validate()
+	}
 	if err != nil {
 		wsResp.Error = err.Error()
 		client.SendWebsocketMessage(wsResp)
 		return err
 	}
 
-	result, err := GetSpecificOrderbook(orderbookReq.Currency,
-		orderbookReq.Exchange, orderbookReq.AssetType)
+	if orderbookReq.AssetType == "" {
+		orderbookReq.AssetType = ticker.Spot
+	}
 
+	p, err := ParseExchangePair(orderbookReq.Exchange, orderbookReq.Currency)
 	if err != nil {
 		wsResp.Error = err.Error()
 		client.SendWebsocketMessage(wsResp)
 		return err
 	}
-	wsResp.Data = result
+
+	var result orderbook.Base
+	if orderbookReq.Refresh {
+		result, err = RefreshSpecificOrderbook(shutdownCtx, p, orderbookReq.Exchange,
+			orderbookReq.AssetType)
+	} else {
+		result, err = GetSpecificOrderbook(p, orderbookReq.Exchange,
+			orderbookReq.AssetType)
+	}
+
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = OrderbookResponse{
+		formatOrderbookPair(result, orderbookReq.PairDisplayFormat),
+		!orderbookReq.Refresh}
 	return client.SendWebsocketMessage(wsResp)
 }
 
@@ -449,3 +1045,194 @@ func wsGetPortfolio(client *WebsocketClient, data interface{}) error {
 	wsResp.Data = bot.portfolio.GetPortfolioSummary()
 	return client.SendWebsocketMessage(wsResp)
 }
+
+func wsGetExposureReport(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetExposureReport",
+	}
+	wsResp.Data = GetExposureReport()
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsGetExchangeHealth(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetExchangeHealth",
+	}
+	wsResp.Data = GetAllExchangeHealth()
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsGetStats(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetStats",
+	}
+	var statsReq WebsocketStatsRequest
+	err := common.JSONDecode(data.([]byte), &statsReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	if statsReq.AssetType == "" {
+		statsReq.AssetType = ticker.Spot
+	}
+
+	if len(statsReq.Currency) < 3 {
+		err = errors.New("invalid currency pair supplied")
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result, err := GetExchangeStats(pair.NewCurrencyPairFromString(statsReq.Currency),
+		statsReq.AssetType, statsReq.ByVolume)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsGetRecentTrades(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetRecentTrades",
+	}
+	var tradesReq WebsocketTradesRequest
+	err := common.JSONDecode(data.([]byte), &tradesReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	p, err := ParseExchangePair(tradesReq.Exchange, tradesReq.Currency)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result, err := GetRecentTrades(tradesReq.Exchange, p, tradesReq.AssetType,
+		tradesReq.Limit)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsGetTradeStats(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetTradeStats",
+	}
+	var tradesReq WebsocketTradesRequest
+	err := common.JSONDecode(data.([]byte), &tradesReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	p, err := ParseExchangePair(tradesReq.Exchange, tradesReq.Currency)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	window, err := parseTradeStatsWindow(tradesReq.Window)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	result, err := GetTradeStats(tradesReq.Exchange, p, tradesReq.AssetType, window)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	wsResp.Data = result
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsGetInfo(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetInfo",
+	}
+	wsResp.Data = GetDaemonInfo()
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsGetSettings(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "GetSettings",
+	}
+	wsResp.Data = GetActiveSettings()
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsShutdown(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "Shutdown",
+		Data:  WebsocketResponseSuccess,
+	}
+	log.Debugln("websocket: shutdown requested.")
+	err := client.SendWebsocketMessage(wsResp)
+	RequestShutdown()
+	return err
+}
+
+func wsSubscribe(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "Subscribe",
+	}
+	var subReq WebsocketSubscriptionRequest
+	err := common.JSONDecode(data.([]byte), &subReq)
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+
+	subs, err := subReq.subscriptions()
+	if err != nil {
+		wsResp.Error = err.Error()
+		client.SendWebsocketMessage(wsResp)
+		return err
+	}
+	client.subscribe(subs)
+	wsResp.Data = client.Subscriptions()
+	return client.SendWebsocketMessage(wsResp)
+}
+
+func wsUnsubscribe(client *WebsocketClient, data interface{}) error {
+	wsResp := WebsocketEventResponse{
+		Event: "Unsubscribe",
+	}
+	var subReq WebsocketSubscriptionRequest
+	if d, ok := data.([]byte); ok {
+		// Requests without a body remove every subscription
+		common.JSONDecode(d, &subReq)
+	}
+
+	var subs []WebsocketSubscription
+	if len(subReq.Events) > 0 || len(subReq.Exchanges) > 0 || len(subReq.Pairs) > 0 {
+		var err error
+		subs, err = subReq.subscriptions()
+		if err != nil {
+			wsResp.Error = err.Error()
+			client.SendWebsocketMessage(wsResp)
+			return err
+		}
+	}
+	client.unsubscribe(subs)
+	wsResp.Data = client.Subscriptions()
+	return client.SendWebsocketMessage(wsResp)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thrasher-/gocryptotrader/common"
+	"github.com/thrasher-/gocryptotrader/config"
+	"github.com/thrasher-/gocryptotrader/currency/pair"
+	exchange "github.com/thrasher-/gocryptotrader/exchanges"
+	"github.com/thrasher-/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-/gocryptotrader/exchanges/ticker"
+)
+
+// setupTestWebsocketHub replaces the websocket hub until the returned func is
+// called
+func setupTestWebsocketHub() func() {
+	hub, started := wsHub, wsHubStarted
+	wsHub, wsHubStarted = NewWebsocketHub(), true
+	return func() {
+		wsHub, wsHubStarted = hub, started
+	}
+}
+
+// newTestWebsocketClient registers a client without a connection
+func newTestWebsocketClient(t *testing.T, queueSize int, authenticated bool) *WebsocketClient {
+	c := &WebsocketClient{Hub: wsHub, Send: make(chan []byte, queueSize)}
+	err := wsHub.register(c, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authenticated {
+		c.setAuthenticated()
+	}
+	return c
+}
+
+// drainWebsocketClient returns the messages queued for a client
+func drainWebsocketClient(c *WebsocketClient) []string {
+	var messages []string
+	for {
+		select {
+		case m, ok := <-c.Send:
+			if !ok {
+				return messages
+			}
+			messages = append(messages, string(m))
+		default:
+			return messages
+		}
+	}
+}
+
+func TestWebsocketSubscriptions(t *testing.T) {
+	SetupTestHelpers(t)
+	defer setupTestWebsocketHub()()
+	exchanges := bot.exchanges
+	bot.exchanges = []exchange.IBotExchange{newTestExchange("Bitstamp")}
+	defer func() { bot.exchanges = exchanges }()
+
+	unauthenticated := newTestWebsocketClient(t, 16, false)
+	all := newTestWebsocketClient(t, 16, true)
+	subscriber := newTestWebsocketClient(t, 16, true)
+
+	for _, request := range []string{
+		`{"events":["trade_update"]}`,
+		`{"exchanges":["Blah"]}`,
+		`{"pairs":["-"]}`,
+		`{"events":"ticker_update"}`,
+	} {
+		if err := wsSubscribe(subscriber, []byte(request)); err == nil {
+			t.Errorf("Test failed. %s expected a subscription error", request)
+		}
+	}
+	drainWebsocketClient(subscriber)
+
+	err := wsSubscribe(subscriber, []byte(
+		`{"events":["TICKER_UPDATE"],"exchanges":["bitstamp"],"pairs":["btc-usd","BTCUSD"]}`))
+	if err != nil {
+		t.Fatalf("Test failed. Subscribe error: %s", err)
+	}
+	err = wsSubscribe(subscriber, []byte(`{"events":["exchange_maintenance"]}`))
+	if err != nil {
+		t.Fatalf("Test failed. Subscribe error: %s", err)
+	}
+	expected := []WebsocketSubscription{
+		{Event: WebsocketEventExchangeMaintenance},
+		{Event: WebsocketEventTickerUpdate, Exchange: "bitstamp", Pair: "BTCUSD"},
+	}
+	if subs := subscriber.Subscriptions(); len(subs) != 2 || subs[0] != expected[0] ||
+		subs[1] != expected[1] {
+		t.Errorf("Test failed. Expected subscriptions %+v, got %+v", expected, subs)
+	}
+	drainWebsocketClient(subscriber)
+
+	btcusd := pair.NewCurrencyPairDelimiter("BTC_USD", "_")
+	events := []WebsocketEvent{
+		{Event: WebsocketEventTickerUpdate, Exchange: "Bitstamp",
+			Data: ticker.Price{Pair: btcusd, Last: 1}},
+		{Event: WebsocketEventTickerUpdate, Exchange: "Bitstamp",
+			Data: ticker.Price{Pair: pair.NewCurrencyPair("LTC", "USD"), Last: 2}},
+		{Event: WebsocketEventTickerUpdate, Exchange: "OKCoin",
+			Data: ticker.Price{Pair: btcusd, Last: 3}},
+		{Event: WebsocketEventOrderbookUpdate, Exchange: "Bitstamp",
+			Data: orderbook.Base{Pair: btcusd}},
+		{Event: WebsocketEventExchangeMaintenance, Exchange: "OKCoin",
+			Data: ExchangeMaintenanceChange{Exchange: "OKCoin", Maintenance: true}},
+	}
+	for x := range events {
+		err = BroadcastWebsocketMessage(events[x])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if messages := drainWebsocketClient(unauthenticated); len(messages) != 0 {
+		t.Errorf("Test failed. Expected no broadcasts to an unauthenticated client, got %v",
+			messages)
+	}
+	if messages := drainWebsocketClient(all); len(messages) != len(events) {
+		t.Errorf("Test failed. Expected every broadcast without subscriptions, got %v",
+			messages)
+	}
+	messages := drainWebsocketClient(subscriber)
+	if len(messages) != 2 || !strings.Contains(messages[0], `"Last":1`) ||
+		!strings.Contains(messages[1], WebsocketEventExchangeMaintenance) {
+		t.Errorf("Test failed. Unexpected subscribed broadcasts %v", messages)
+	}
+
+	err = wsUnsubscribe(subscriber, []byte(`{"events":["exchange_maintenance"]}`))
+	if err != nil || len(subscriber.Subscriptions()) != 1 {
+		t.Errorf("Test failed. Unexpected subscriptions %+v after unsubscribing, error %v",
+			subscriber.Subscriptions(), err)
+	}
+	err = wsUnsubscribe(subscriber, nil)
+	if err != nil || len(subscriber.Subscriptions()) != 0 {
+		t.Errorf("Test failed. Unexpected subscriptions %+v after unsubscribing all, error %v",
+			subscriber.Subscriptions(), err)
+	}
+	drainWebsocketClient(subscriber)
+	err = BroadcastWebsocketMessage(events[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messages := drainWebsocketClient(subscriber); len(messages) != 0 {
+		t.Errorf("Test failed. Expected no broadcasts without subscriptions, got %v",
+			messages)
+	}
+}
+
+func TestWebsocketSlowClient(t *testing.T) {
+	defer setupTestWebsocketHub()()
+	slow := newTestWebsocketClient(t, 1, true)
+	fast := newTestWebsocketClient(t, websocketMaxDroppedMessages*2, true)
+
+	done := make(chan struct{})
+	go func() {
+		for x := 0; x < websocketMaxDroppedMessages+1; x++ {
+			BroadcastWebsocketMessage(WebsocketEvent{Event: WebsocketEventTickerUpdate})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Test failed. A slow client blocked broadcasts")
+	}
+
+	if messages := drainWebsocketClient(fast); len(messages) != websocketMaxDroppedMessages+1 {
+		t.Errorf("Test failed. Expected every broadcast queued for the fast client, got %d",
+			len(messages))
+	}
+	if wsHub.Len() != 1 {
+		t.Errorf("Test failed. Expected the slow client to be disconnected, %d clients remain",
+			wsHub.Len())
+	}
+	if messages := drainWebsocketClient(slow); len(messages) != 1 {
+		t.Errorf("Test failed. Expected one queued message for the slow client, got %d",
+			len(messages))
+	}
+	if _, ok := <-slow.Send; ok {
+		t.Error("Test failed. Expected the slow client send queue to be closed")
+	}
+	if err := slow.SendWebsocketMessage(WebsocketEventResponse{}); err != errWebsocketClientClosed {
+		t.Errorf("Test failed. Expected a closed client error, got %v", err)
+	}
+}
+
+func TestWebsocketClientHandler(t *testing.T) {
+	SetupTestHelpers(t)
+	defer setupTestWebsocketHub()()
+	defer func(cfg config.WebserverConfig) {
+		bot.config.Webserver = cfg
+	}(bot.config.Webserver)
+	bot.config.Webserver.WebsocketConnectionLimit = 1
+	bot.config.Webserver.WebsocketMaxAuthFailures = 2
+	bot.config.Webserver.WebsocketAuthTimeout = time.Millisecond * 100
+	bot.config.Webserver.WebsocketSendQueueSize = 16
+
+	srv := httptest.NewServer(http.HandlerFunc(WebsocketClientHandler))
+	defer srv.Close()
+	dial := func() (*websocket.Conn, *http.Response, error) {
+		return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	}
+	send := func(conn *websocket.Conn, event string, data interface{}) {
+		err := conn.WriteJSON(WebsocketEvent{Event: event, Data: data})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// closed reads until the connection is closed, returning the messages read
+	closed := func(conn *websocket.Conn) (string, bool) {
+		var messages string
+		conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+		for {
+			_, m, err := conn.ReadMessage()
+			if err != nil {
+				netErr, ok := err.(interface{ Timeout() bool })
+				return messages, !ok || !netErr.Timeout()
+			}
+			messages += string(m)
+		}
+	}
+
+	conn, _, err := dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, resp, err := dial()
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Test failed. Expected the connection limit to reject a client, got %v", err)
+	}
+	// The client is disconnected once the auth timeout expires
+	if _, ok := closed(conn); !ok {
+		t.Error("Test failed. Expected an unauthenticated client to be disconnected")
+	}
+	conn.Close()
+
+	conn, _, err = dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < bot.config.Webserver.WebsocketMaxAuthFailures; x++ {
+		send(conn, "auth", WebsocketAuth{Username: "blah", Password: "blah"})
+	}
+	if messages, ok := closed(conn); !ok ||
+		!strings.Contains(messages, "invalid username/password") {
+		t.Errorf("Test failed. Expected a client to be disconnected after failing auth, got %s",
+			messages)
+	}
+	conn.Close()
+
+	conn, _, err = dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	send(conn, "auth", WebsocketAuth{Username: bot.config.Webserver.AdminUsername,
+		Password: common.HexEncodeToString(common.GetSHA256(
+			[]byte(bot.config.Webserver.AdminPassword)))})
+	send(conn, "subscribe", WebsocketSubscriptionRequest{
+		Events: []string{WebsocketEventExchangeMaintenance}})
+
+	var received string
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	for !strings.Contains(received, `"event":"Subscribe"`) {
+		_, m, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Test failed. Failed to subscribe, received %s: %s", received, err)
+		}
+		received += string(m)
+	}
+	if strings.Contains(received, `"error":"unauthorised`) {
+		t.Fatalf("Test failed. Unexpected unauthorised subscription %s", received)
+	}
+
+	// Authenticated clients outlive the auth timeout
+	time.Sleep(bot.config.Webserver.WebsocketAuthTimeout * 2)
+	BroadcastWebsocketMessage(WebsocketEvent{Event: WebsocketEventTickerUpdate})
+	BroadcastWebsocketMessage(WebsocketEvent{Event: WebsocketEventExchangeMaintenance,
+		Exchange: "Bitstamp"})
+	_, m, err := conn.ReadMessage()
+	if err != nil || !strings.Contains(string(m), WebsocketEventExchangeMaintenance) ||
+		strings.Contains(string(m), WebsocketEventTickerUpdate) {
+		t.Errorf("Test failed. Unexpected broadcast %s %v", m, err)
+	}
+}